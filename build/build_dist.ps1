@@ -1,5 +1,9 @@
 param (
-    [string]$ReleaseTag = $env:RELEASE_TAG
+    [string]$ReleaseTag = $env:RELEASE_TAG,
+    # Target architecture: amd64 or arm64. arm64 is cross-compiled on the
+    # amd64 runner (Wails on Windows needs no cgo).
+    [ValidateSet("amd64", "arm64")]
+    [string]$Arch = "amd64"
 )
 
 # Fail fast on any error, including the early setup steps below.
@@ -183,7 +187,7 @@ if ($env:REPO_NAME -and (Test-Path $env:REPO_NAME)) {
 $BuildTime = Get-Date -Format "yyyy-MM-dd"
 $LdFlags = "-s -w -X github.com/rescale/rescale-int/internal/version.Version=$($ReleaseTag) -X github.com/rescale/rescale-int/internal/version.BuildTime=$BuildTime"
 
-Write-Host "Build flags: GOFIPS140=certified"
+Write-Host "Build flags: GOFIPS140=certified, target windows/$Arch"
 Write-Host "LDFLAGS: $LdFlags"
 
 # Install frontend dependencies
@@ -206,7 +210,7 @@ Write-Host "Building rescale-int-gui.exe with Wails..."
 $WailsExe = "$env:GOPATH\bin\wails.exe"
 # Wails shells out to `go`; force our FIPS-certified toolchain by putting it
 # first on PATH and pinning GOROOT, so the runner's preinstalled Go can't win.
-$wailsBuildCmd = "set `"GOFIPS140=certified`"&& set `"GOROOT=$GoInstallDir`"&& set `"PATH=$GoInstallDir\bin;%PATH%`"&& `"$WailsExe`" build -tags fips -platform windows/amd64 -ldflags `"$LdFlags`""
+$wailsBuildCmd = "set `"GOFIPS140=certified`"&& set `"GOROOT=$GoInstallDir`"&& set `"PATH=$GoInstallDir\bin;%PATH%`"&& `"$WailsExe`" build -tags fips -platform windows/$Arch -ldflags `"$LdFlags`""
 Write-Host "Running: $wailsBuildCmd"
 $prevErrorAction = $ErrorActionPreference
 $ErrorActionPreference = "Continue"
@@ -226,7 +230,7 @@ Write-Host "GUI binary built: rescale-int-gui.exe"
 # v4.0.2: Build standalone CLI binary
 Write-Host "Building rescale-int.exe (standalone CLI)..."
 $GoExe = "C:\Go\bin\go.exe"
-$cliBuildCmd = "set `"GOFIPS140=certified`"&& `"$GoExe`" build -tags fips -ldflags `"$LdFlags`" -o `"$BinDir\rescale-int.exe`" .\cmd\rescale-int"
+$cliBuildCmd = "set `"GOFIPS140=certified`"&& set `"GOOS=windows`"&& set `"GOARCH=$Arch`"&& `"$GoExe`" build -tags fips -ldflags `"$LdFlags`" -o `"$BinDir\rescale-int.exe`" .\cmd\rescale-int"
 $prevErrorAction = $ErrorActionPreference
 $ErrorActionPreference = "Continue"
 cmd /c $cliBuildCmd 2>&1 | Out-Host
@@ -237,7 +241,7 @@ Write-Host "CLI binary built: rescale-int.exe"
 
 # Build tray companion (windowsgui subsystem) - this is a separate simple Go app
 Write-Host "Building rescale-int-tray.exe..."
-$trayCmd = "set `"GOFIPS140=certified`"&& set `"GOOS=windows`"&& set `"GOARCH=amd64`"&& `"$GoExe`" build -tags fips -ldflags `"$LdFlags -H=windowsgui`" -o `"$BinDir\rescale-int-tray.exe`" .\cmd\rescale-int-tray"
+$trayCmd = "set `"GOFIPS140=certified`"&& set `"GOOS=windows`"&& set `"GOARCH=$Arch`"&& `"$GoExe`" build -tags fips -ldflags `"$LdFlags -H=windowsgui`" -o `"$BinDir\rescale-int-tray.exe`" .\cmd\rescale-int-tray"
 $prevErrorAction = $ErrorActionPreference
 $ErrorActionPreference = "Continue"
 cmd /c $trayCmd 2>&1 | Out-Host
@@ -261,15 +265,17 @@ New-Item -ItemType Directory -Force -Path $WebView2Dir | Out-Null
 # IMPORTANT: Use WebView2.Runtime.X64 package (contains actual runtime files)
 # NOT Microsoft.Web.WebView2 (which is just the SDK with WebView2Loader.dll)
 # See: https://github.com/ProKn1fe/WebView2.Runtime
-$RuntimeNuGetUrl = "https://www.nuget.org/api/v2/package/WebView2.Runtime.X64"
+# The runtime must match the GUI architecture (WebView2.Runtime.ARM64 for arm64).
+$RuntimePackage = if ($Arch -eq "arm64") { "WebView2.Runtime.ARM64" } else { "WebView2.Runtime.X64" }
+$RuntimeNuGetUrl = "https://www.nuget.org/api/v2/package/$RuntimePackage"
 $RuntimePkg = Join-Path $BuildDir "webview2-runtime.zip"
 
-Write-Host "Downloading WebView2 Fixed Version Runtime (WebView2.Runtime.X64)..."
+Write-Host "Downloading WebView2 Fixed Version Runtime ($RuntimePackage)..."
 $hasWebView2 = $false
 
 try {
     (New-Object System.Net.WebClient).DownloadFile($RuntimeNuGetUrl, $RuntimePkg)
-    Write-Host "WebView2.Runtime.X64 package downloaded successfully"
+    Write-Host "$RuntimePackage package downloaded successfully"
     $pkgSize = (Get-Item $RuntimePkg).Length / 1MB
     Write-Host "Package size: $([math]::Round($pkgSize, 1)) MB"
 
@@ -328,7 +334,7 @@ try {
             $hasWebView2 = $false
         }
     } else {
-        Write-Host "ERROR: msedgewebview2.exe not found in WebView2.Runtime.X64 package"
+        Write-Host "ERROR: msedgewebview2.exe not found in $RuntimePackage package"
         Get-ChildItem -Path $RuntimeExtract -Recurse | Where-Object { $_.Name -like "*.exe" } | Select-Object FullName
         $hasWebView2 = $false
     }
@@ -1,6 +1,8 @@
 param (
     [string]$ReleaseTag = $env:RELEASE_TAG,
-    [string]$MsiName = $env:MSI_NAME
+    [string]$MsiName = $env:MSI_NAME,
+    [ValidateSet("amd64", "arm64")]
+    [string]$Arch = "amd64"
 )
 
 # Set up paths
@@ -40,7 +42,9 @@ $VersionNum = $ReleaseTag -replace '^v', ''
 $WxsFile = "$InstallerDir\rescale-interlink.wxs"
 # v4.0.8: Add -bindpath to tell WiX where to find License.rtf
 # v4.0.8: Add Util extension for WixShellExec (LaunchTray custom action)
-$wixBuildCmd = "wix build `"$WxsFile`" -d BuildDir=`"$BinDir`" -d SourceDir=`"$BinDir`" -d Version=`"$VersionNum`" -ext WixToolset.UI.wixext -ext WixToolset.Util.wixext -bindpath `"$InstallerDir`" -o `"$MsiPath`""
+# arm64 packages must declare their platform so Windows on ARM installs them natively
+$WixArch = if ($Arch -eq "arm64") { " -arch arm64" } else { "" }
+$wixBuildCmd = "wix build `"$WxsFile`"$WixArch -d BuildDir=`"$BinDir`" -d SourceDir=`"$BinDir`" -d Version=`"$VersionNum`" -ext WixToolset.UI.wixext -ext WixToolset.Util.wixext -bindpath `"$InstallerDir`" -o `"$MsiPath`""
 
 Write-Host "Running: $wixBuildCmd"
 $wixBuildResult = cmd /c "$wixBuildCmd 2>&1"
@@ -11,12 +11,13 @@ import (
 
 	"github.com/rescale/rescale-int/internal/cli"
 	"github.com/rescale/rescale-int/internal/cli/compat"
+	"github.com/rescale/rescale-int/internal/selfupdate"
 	"github.com/rescale/rescale-int/internal/version"
 )
 
 func init() {
 	// Shared FIPS 140-3 compliance check (common to GUI and CLI binaries)
-	intfips.Init("cli")
+	intfips.Init("cli", false)
 }
 
 func main() {
@@ -25,6 +26,9 @@ func main() {
 	cli.Version = version.Version
 	cli.BuildTime = version.BuildTime
 
+	// Remove the binary a previous self-update moved aside (Windows)
+	selfupdate.CleanupPrevious()
+
 	// Enable timing output
 	if slices.Contains(os.Args, "--timing") {
 		os.Setenv("RESCALE_TIMING", "1")
@@ -7,6 +7,7 @@ import {
   ArrowsRightLeftIcon,
   DocumentTextIcon,
   PlayIcon,
+  UserGroupIcon,
 } from '@heroicons/react/24/outline'
 import clsx from 'clsx'
 
@@ -18,9 +19,11 @@ import {
   TransfersTab,
   SingleJobTab,
   PURTab,
+  AdminTab,
 } from './components/tabs'
 import { ErrorBoundary } from './components/common'
 import ErrorReportModal from './components/ErrorReportModal'
+import { ToastStack, NotificationMuteMenu, NetworkUsageWidget } from './components/widgets'
 import * as App from '../wailsjs/go/wailsapp/App'
 import { wailsapp } from '../wailsjs/go/models'
 import { BrowserOpenURL } from '../wailsjs/runtime/runtime'
@@ -31,6 +34,7 @@ import { useTransferStore } from './stores/transferStore'
 import { useRunStore } from './stores/runStore'
 import { useErrorReportStore } from './stores/errorReportStore'
 import { useRateLimitStore } from './stores/rateLimitStore'
+import { useNotificationStore } from './stores/notificationStore'
 
 // Tab navigation context for switching tabs from other components.
 // activeTabName lets components like TransfersTab gate work (e.g. 500ms polling)
@@ -56,22 +60,30 @@ const tabs = [
   { name: 'Activity Logs', icon: DocumentTextIcon, component: ActivityTab },
 ]
 
+// Shown after the standard tabs when org admin mode is enabled in Setup.
+const adminTab = { name: 'Team Admin', icon: UserGroupIcon, component: AdminTab }
+
 function AppComponent() {
   const [appInfo, setAppInfo] = useState<wailsapp.AppInfoDTO | null>(null)
   const [selectedTabIndex, setSelectedTabIndex] = useState(0)
   const {
     workspaceName,
     workspaceId,
+    workspaces,
+    isSwitchingWorkspace,
     connectionStatus,
     config,
+    viewerMode,
     testConnection,
+    fetchWorkspaces,
+    switchWorkspace,
     setupEventListeners: setupConfigEventListeners,
     fetchConfig,
   } = useConfigStore()
   const { overallMessage, overallProgress } = useLogStore()
   const { stats: transferStats, setupEventListeners: setupTransferEventListeners } = useTransferStore()
   const { activeRun, setupEventListeners: setupRunEventListeners, recoverFromRestart } = useRunStore()
-  const { setupEventListeners: setupFileBrowserEventListeners } = useFileBrowserStore()
+  const { setupEventListeners: setupFileBrowserEventListeners, switchRemoteWorkspace } = useFileBrowserStore()
   const { setupEventListeners: setupErrorReportEventListeners } = useErrorReportStore()
 
   // App-level listener — persists across tab navigation
@@ -126,6 +138,13 @@ function AppComponent() {
     return cleanup
   }, [setupErrorReportEventListeners])
 
+  // App-level listener — toasts must fire whichever tab is open
+  const { setupEventListeners: setupNotificationEventListeners } = useNotificationStore()
+  useEffect(() => {
+    const cleanup = setupNotificationEventListeners()
+    return cleanup
+  }, [setupNotificationEventListeners])
+
   // Recover active run state after app restart — checks localStorage for
   // persisted run info and loads historical state from disk.
   useEffect(() => {
@@ -153,6 +172,16 @@ function AppComponent() {
     }
   }, [config?.apiKey, workspaceId, connectionStatus, testConnection])
 
+  // List the user's workspaces for the header selector once connected
+  useEffect(() => {
+    if (connectionStatus === 'connected') fetchWorkspaces()
+  }, [connectionStatus, fetchWorkspaces])
+
+  // The remote file browser keeps separate tabs and folders per workspace
+  useEffect(() => {
+    if (workspaceId) switchRemoteWorkspace(workspaceId)
+  }, [workspaceId, switchRemoteWorkspace])
+
   useEffect(() => {
     // Fetch app info from Go backend
     App.GetAppInfo().then(setAppInfo).catch(console.error)
@@ -175,15 +204,22 @@ function AppComponent() {
     return () => clearTimeout(timer)
   }, [])
 
+  const visibleTabs = config?.adminMode ? [...tabs, adminTab] : tabs
+
+  // Leave the Team Admin tab if admin mode is turned off while it is open
+  useEffect(() => {
+    if (selectedTabIndex >= visibleTabs.length) setSelectedTabIndex(0)
+  }, [selectedTabIndex, visibleTabs.length])
+
   // Tab navigation function
   const switchToTab = (tabName: string) => {
-    const index = tabs.findIndex(t => t.name === tabName)
+    const index = visibleTabs.findIndex(t => t.name === tabName)
     if (index !== -1) {
       setSelectedTabIndex(index)
     }
   }
 
-  const activeTabName = tabs[selectedTabIndex]?.name ?? ''
+  const activeTabName = visibleTabs[selectedTabIndex]?.name ?? ''
 
   // Transfers tab indicators:
   //  - "active": a pulsing dot while transfers are in progress or queued.
@@ -211,6 +247,16 @@ function AppComponent() {
     if (activeTabName === 'Transfers') setTransfersUnseen(null)
   }, [activeTabName])
 
+  // Run tab badges: running and failed job counts of the active run, shown on
+  // the tab that owns it (Single Job or PUR).
+  const runTabName = activeRun?.runType === 'single' ? 'Single Job' : 'PUR (Multiple Jobs)'
+  const runBadge = activeRun && activeRun.status === 'active'
+    ? {
+        running: Math.max(0, activeRun.totalJobs - activeRun.completedJobs - activeRun.failedJobs),
+        failed: activeRun.failedJobs,
+      }
+    : null
+
   return (
     <TabNavigationContext.Provider value={{ switchToTab, activeTabName }}>
       <ErrorReportModal />
@@ -231,19 +277,46 @@ function AppComponent() {
           </div>
           {/* Workspace info (center) */}
           <div className="flex-1 flex justify-center">
-            {connectionStatus === 'connected' && workspaceName && (
-              <span className="text-sm text-gray-600">
-                <span className="text-gray-500">Workspace: </span>
-                <span className="font-medium">{workspaceName}</span>
-                {workspaceId && (
-                  <span className="text-gray-400 ml-1">({workspaceId})</span>
-                )}
-              </span>
+            {connectionStatus === 'connected' && workspaces.length > 1 ? (
+              <label className="text-sm text-gray-600 flex items-center">
+                <span className="text-gray-500 mr-1">Workspace:</span>
+                <select
+                  value={workspaceId || ''}
+                  onChange={(e) => switchWorkspace(e.target.value)}
+                  disabled={isSwitchingWorkspace || activeRun?.status === 'active'}
+                  title={activeRun?.status === 'active' ? 'Workspace can be switched once the current run finishes' : undefined}
+                  className="font-medium border border-gray-300 rounded px-2 py-0.5 bg-white disabled:opacity-50"
+                >
+                  {workspaces.map((w) => (
+                    <option key={w.id} value={w.id}>
+                      {w.name} ({w.id})
+                    </option>
+                  ))}
+                </select>
+              </label>
+            ) : (
+              connectionStatus === 'connected' && workspaceName && (
+                <span className="text-sm text-gray-600">
+                  <span className="text-gray-500">Workspace: </span>
+                  <span className="font-medium">{workspaceName}</span>
+                  {workspaceId && (
+                    <span className="text-gray-400 ml-1">({workspaceId})</span>
+                  )}
+                </span>
+              )
             )}
           </div>
           {/* Version, FIPS status, and update notification (right) */}
           <div className="flex flex-col items-end text-sm text-gray-500">
             <div className="flex items-center space-x-4">
+              {viewerMode && (
+                <span
+                  className="px-2 py-0.5 bg-slate-200 text-slate-700 rounded text-xs font-medium"
+                  title="Runs and files can be viewed; uploads, downloads, deletes and job submissions are disabled"
+                >
+                  Viewer mode (read-only)
+                </span>
+              )}
               {appInfo && (
                 <>
                   <span>{appInfo.version}</span>
@@ -272,7 +345,7 @@ function AppComponent() {
         <Tab.Group as="div" className="flex-1 flex overflow-hidden" selectedIndex={selectedTabIndex} onChange={setSelectedTabIndex}>
         {/* Sidebar with tabs */}
         <Tab.List className="w-48 bg-white border-r border-gray-200 py-4 flex flex-col">
-          {tabs.map((tab) => (
+          {visibleTabs.map((tab) => (
             <Tab
               key={tab.name}
               title={(tab as any).title}
@@ -288,6 +361,24 @@ function AppComponent() {
             >
               <tab.icon className="w-5 h-5 mr-3" />
               {tab.name}
+              {tab.name === runTabName && runBadge && runBadge.running > 0 && (
+                <span
+                  className="ml-2 px-1.5 rounded-full bg-blue-100 text-blue-700 text-xs"
+                  title={`${runBadge.running} running`}
+                  aria-label={`${runBadge.running} running`}
+                >
+                  {runBadge.running}
+                </span>
+              )}
+              {tab.name === runTabName && runBadge && runBadge.failed > 0 && (
+                <span
+                  className="ml-1 px-1.5 rounded-full bg-red-100 text-red-700 text-xs"
+                  title={`${runBadge.failed} failed`}
+                  aria-label={`${runBadge.failed} failed`}
+                >
+                  {runBadge.failed}
+                </span>
+              )}
               {tab.name === 'Transfers' && transfersActive && (
                 <span
                   className="ml-2 h-2 w-2 rounded-full bg-blue-500 animate-pulse"
@@ -315,7 +406,7 @@ function AppComponent() {
             navigation during a folder upload. Without this, switching to
             Transfers mid-preflight would silently drop a pending merge dialog. */}
         <Tab.Panels className="flex-1 overflow-hidden">
-          {tabs.map((tab) => (
+          {visibleTabs.map((tab) => (
             <Tab.Panel key={tab.name} className="h-full" unmount={false}>
               <ErrorBoundary>
                 <tab.component />
@@ -356,8 +447,11 @@ function AppComponent() {
                 Pacing requests to stay within Rescale limits
               </span>
             )}
+            <NetworkUsageWidget />
+            <NotificationMuteMenu />
           </div>
         </footer>
+        <ToastStack onOpen={() => switchToTab(runTabName)} />
       </div>
     </TabNavigationContext.Provider>
   )
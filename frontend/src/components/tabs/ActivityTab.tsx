@@ -1,10 +1,12 @@
 import { useEffect, useRef, useMemo, useState } from 'react';
 import { useLogStore } from '../../stores';
+import { matchesCorrelation } from '../../stores/logStore';
 import { useRunStore } from '../../stores/runStore';
 import type { LogLevel } from '../../types';
 import type { JobRow } from '../../types/jobs';
 import { GetDaemonStatus, GetDaemonLogs, GetRunHistory, GetHistoricalJobRows, SaveLogExport } from '../../../wailsjs/go/wailsapp/App';
 import { JobsTable } from '../widgets';
+import { formatSize } from '../widgets/FileList';
 import { formatDurationMs } from '../../utils/formatDuration';
 import {
   MagnifyingGlassIcon,
@@ -64,6 +66,8 @@ interface LogEntry {
   stage: string;
   jobName: string;
   error?: string;
+  runId?: string;
+  transferId?: string;
   formattedText: string;
   lowerText: string;
 }
@@ -76,12 +80,14 @@ export function ActivityTab() {
     stats,
     levelFilter,
     searchTerm,
+    correlationFilter,
     autoScroll,
     // overallProgress/overallMessage hidden — was confusing users
     // overallProgress,
     // overallMessage,
     setLevelFilter,
     setSearchTerm,
+    setCorrelationFilter,
     setAutoScroll,
     clearLogs,
     getFilteredLogs,
@@ -117,6 +123,7 @@ export function ActivityTab() {
         if (logSev < minSev) return false;
       }
       if (searchTerm && !log.lowerText.includes(lowerSearch)) return false;
+      if (!matchesCorrelation(log, correlationFilter)) return false;
       return true;
     });
 
@@ -124,7 +131,7 @@ export function ActivityTab() {
     return [...guiFiltered, ...daemonFiltered].sort(
       (a, b) => a.timestamp.getTime() - b.timestamp.getTime()
     );
-  }, [logVersion, daemonLogs, levelFilter, searchTerm, getFilteredLogs]);
+  }, [logVersion, daemonLogs, levelFilter, searchTerm, correlationFilter, getFilteredLogs]);
 
   // Virtual scrolling for performance with large log counts
   const rowVirtualizer = useVirtualizer({
@@ -304,6 +311,27 @@ export function ActivityTab() {
           </div>
         </div>
 
+        {/* Correlation filter: one run or transfer */}
+        <div className="flex items-center gap-2">
+          <label className="text-sm font-medium text-gray-700">Run/Transfer:</label>
+          <input
+            type="text"
+            className="input py-1 w-40"
+            placeholder="ID"
+            title="Show only lines of this run or transfer. Click a run or transfer tag in the log to fill it in."
+            value={correlationFilter}
+            onChange={(e) => setCorrelationFilter(e.target.value)}
+          />
+          {correlationFilter && (
+            <button
+              onClick={() => setCorrelationFilter('')}
+              className="text-xs text-gray-500 hover:text-gray-700"
+            >
+              Clear
+            </button>
+          )}
+        </div>
+
         {/* Controls */}
         <div className="flex items-center gap-2">
           <label className="flex items-center gap-2 text-sm text-gray-700 cursor-pointer">
@@ -418,6 +446,11 @@ export function ActivityTab() {
                           </div>
                           <div className="flex items-center gap-3 text-xs text-gray-500">
                             <span>{formatDurationMs(run.durationMs)}</span>
+                            {(run.bytesSent || run.bytesReceived) ? (
+                              <span title="Network data sent and received during the run">
+                                ↑ {run.bytesSent ? formatSize(run.bytesSent) : '0 B'} ↓ {run.bytesReceived ? formatSize(run.bytesReceived) : '0 B'}
+                              </span>
+                            ) : null}
                             <span className="text-green-600">{run.completedJobs} ok</span>
                             {run.failedJobs > 0 && (
                               <span className="text-red-600">{run.failedJobs} failed</span>
@@ -557,6 +590,24 @@ export function ActivityTab() {
                     <span className="text-gray-500 mr-2">[{log.jobName}]</span>
                   )}
                   <span className="text-gray-800">{log.message}</span>
+                  {log.runId && (
+                    <button
+                      onClick={() => setCorrelationFilter(log.runId!)}
+                      className="ml-2 text-xs text-gray-400 hover:text-rescale-blue"
+                      title="Show only this run"
+                    >
+                      run={log.runId}
+                    </button>
+                  )}
+                  {log.transferId && (
+                    <button
+                      onClick={() => setCorrelationFilter(log.transferId!)}
+                      className="ml-2 text-xs text-gray-400 hover:text-rescale-blue"
+                      title="Show only this transfer"
+                    >
+                      transfer={log.transferId}
+                    </button>
+                  )}
                 </div>
               );
             })}
@@ -0,0 +1,282 @@
+// Team Admin tab (org admin mode): recent Interlink jobs across the
+// organization with bulk stop/archive, and per-member storage use.
+import { useEffect, useState } from 'react';
+import { ArrowPathIcon, XCircleIcon, CheckCircleIcon } from '@heroicons/react/24/outline';
+import clsx from 'clsx';
+import {
+  ListTeamJobs,
+  StopTeamJobs,
+  ArchiveTeamJobs,
+  GetTeamStorageUsage,
+} from '../../../wailsjs/go/wailsapp/App';
+import { wailsapp } from '../../../wailsjs/go/models';
+import { formatSize } from '../widgets/FileList';
+import { useConfigStore, VIEWER_MODE_TITLE } from '../../stores';
+
+const SINCE_OPTIONS = [1, 7, 30, 90];
+const STATUS_OPTIONS = ['', 'Pending', 'Queued', 'Executing', 'Completed', 'Stopped'];
+
+type BulkAction = 'stop' | 'archive';
+
+export function AdminTab() {
+  const viewerMode = useConfigStore((s) => s.viewerMode);
+  const [sinceDays, setSinceDays] = useState(7);
+  const [owner, setOwner] = useState('');
+  const [status, setStatus] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [jobs, setJobs] = useState<wailsapp.AdminJobsResultDTO | null>(null);
+  const [selected, setSelected] = useState<Set<string>>(new Set());
+  const [confirmAction, setConfirmAction] = useState<BulkAction | null>(null);
+  const [actionMessage, setActionMessage] = useState<{ ok: boolean; text: string } | null>(null);
+  const [storage, setStorage] = useState<wailsapp.TeamStorageResultDTO | null>(null);
+  const [storageLoading, setStorageLoading] = useState(false);
+
+  const loadJobs = async () => {
+    setLoading(true);
+    try {
+      setJobs(await ListTeamJobs({ sinceDays, owner: owner.trim(), status } as wailsapp.AdminJobFilterDTO));
+      setSelected(new Set());
+    } catch (err) {
+      setJobs({ jobs: [], adminRequired: false, error: String(err) } as wailsapp.AdminJobsResultDTO);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const loadStorage = async () => {
+    setStorageLoading(true);
+    try {
+      setStorage(await GetTeamStorageUsage());
+    } catch (err) {
+      setStorage({ members: [], adminRequired: false, error: String(err) } as wailsapp.TeamStorageResultDTO);
+    } finally {
+      setStorageLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadJobs();
+    loadStorage();
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, []);
+
+  const runAction = async (action: BulkAction) => {
+    setConfirmAction(null);
+    const ids = Array.from(selected);
+    const result = action === 'stop' ? await StopTeamJobs(ids) : await ArchiveTeamJobs(ids);
+    if (result.error) {
+      setActionMessage({ ok: false, text: result.error });
+      return;
+    }
+    const failed = result.results.filter((r) => r.error);
+    const verb = action === 'stop' ? 'Stopped' : 'Archived';
+    setActionMessage(
+      failed.length === 0
+        ? { ok: true, text: `${verb} ${result.results.length} job(s)` }
+        : { ok: false, text: `${verb} ${result.results.length - failed.length} of ${result.results.length}; failed: ${failed.map((r) => `${r.jobId} (${r.error})`).join(', ')}` }
+    );
+    loadJobs();
+  };
+
+  const toggle = (id: string) => {
+    const next = new Set(selected);
+    if (next.has(id)) next.delete(id);
+    else next.add(id);
+    setSelected(next);
+  };
+
+  const jobList = jobs?.jobs ?? [];
+  const allSelected = jobList.length > 0 && jobList.every((j) => selected.has(j.id));
+
+  return (
+    <div className="tab-panel flex flex-col h-full overflow-auto space-y-6">
+      <div className="card">
+        <h3 className="text-base font-semibold text-gray-900 mb-4">Team Interlink Jobs</h3>
+        <div className="flex flex-wrap items-end gap-4 mb-4">
+          <div>
+            <label className="label">Created in last</label>
+            <select className="input" value={sinceDays} onChange={(e) => setSinceDays(Number(e.target.value))}>
+              {SINCE_OPTIONS.map((d) => (
+                <option key={d} value={d}>{d === 1 ? '1 day' : `${d} days`}</option>
+              ))}
+            </select>
+          </div>
+          <div>
+            <label className="label">Owner</label>
+            <input
+              type="text"
+              className="input"
+              value={owner}
+              onChange={(e) => setOwner(e.target.value)}
+              placeholder="name@example.com"
+            />
+          </div>
+          <div>
+            <label className="label">Status</label>
+            <select className="input" value={status} onChange={(e) => setStatus(e.target.value)}>
+              {STATUS_OPTIONS.map((s) => (
+                <option key={s} value={s}>{s || 'Any'}</option>
+              ))}
+            </select>
+          </div>
+          <button onClick={loadJobs} disabled={loading} className="btn-secondary flex items-center">
+            <ArrowPathIcon className={clsx('w-4 h-4 mr-2', loading && 'animate-spin')} />
+            {loading ? 'Loading...' : 'Refresh'}
+          </button>
+        </div>
+
+        {jobs?.error && (
+          <div className="flex items-center gap-2 p-3 mb-4 rounded-md bg-red-50 text-red-700 text-sm">
+            <XCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>
+              {jobs.adminRequired
+                ? 'Your API key does not have organization admin permissions.'
+                : jobs.error}
+            </span>
+          </div>
+        )}
+
+        {actionMessage && (
+          <div className={clsx(
+            'flex items-center gap-2 p-3 mb-4 rounded-md text-sm',
+            actionMessage.ok ? 'bg-green-50 text-green-700' : 'bg-red-50 text-red-700'
+          )}>
+            {actionMessage.ok
+              ? <CheckCircleIcon className="w-5 h-5 flex-shrink-0" />
+              : <XCircleIcon className="w-5 h-5 flex-shrink-0" />}
+            <span>{actionMessage.text}</span>
+          </div>
+        )}
+
+        <div className="flex items-center gap-2 mb-2">
+          <button
+            onClick={() => setConfirmAction('stop')}
+            disabled={selected.size === 0 || viewerMode}
+            title={viewerMode ? VIEWER_MODE_TITLE : undefined}
+            className="btn-secondary"
+          >
+            Stop Selected
+          </button>
+          <button
+            onClick={() => setConfirmAction('archive')}
+            disabled={selected.size === 0 || viewerMode}
+            title={viewerMode ? VIEWER_MODE_TITLE : undefined}
+            className="btn-secondary"
+          >
+            Archive Selected
+          </button>
+          <span className="text-xs text-gray-500">{selected.size} of {jobList.length} selected</span>
+        </div>
+
+        <table className="w-full text-sm">
+          <thead>
+            <tr className="text-left text-gray-600 border-b border-gray-200">
+              <th className="py-2 w-8">
+                <input
+                  type="checkbox"
+                  checked={allSelected}
+                  onChange={() => setSelected(allSelected ? new Set() : new Set(jobList.map((j) => j.id)))}
+                  aria-label="Select all jobs"
+                />
+              </th>
+              <th className="py-2">Name</th>
+              <th className="py-2">Owner</th>
+              <th className="py-2">Status</th>
+              <th className="py-2">Created</th>
+            </tr>
+          </thead>
+          <tbody>
+            {jobList.map((j) => (
+              <tr key={j.id} className="border-b border-gray-100 last:border-0">
+                <td className="py-1.5">
+                  <input
+                    type="checkbox"
+                    checked={selected.has(j.id)}
+                    onChange={() => toggle(j.id)}
+                    aria-label={`Select ${j.name}`}
+                  />
+                </td>
+                <td className="py-1.5" title={j.id}>{j.name}</td>
+                <td className="py-1.5 text-gray-700">{j.owner}</td>
+                <td className="py-1.5">{j.status}</td>
+                <td className="py-1.5 text-gray-500">{j.createdAt ? new Date(j.createdAt).toLocaleString() : ''}</td>
+              </tr>
+            ))}
+            {jobs && !jobs.error && jobList.length === 0 && (
+              <tr>
+                <td colSpan={5} className="py-4 text-center text-gray-500">No Interlink jobs found</td>
+              </tr>
+            )}
+          </tbody>
+        </table>
+        <p className="mt-3 text-xs text-gray-500">
+          Jobs are identified by the tag set in Setup (default <code>interlink</code>, also matching
+          <code> interlink-*</code>). Team members need it in their Default Tags.
+        </p>
+      </div>
+
+      <div className="card">
+        <div className="flex items-center justify-between mb-4">
+          <h3 className="text-base font-semibold text-gray-900">Storage by Member</h3>
+          <button onClick={loadStorage} disabled={storageLoading} className="btn-secondary flex items-center">
+            <ArrowPathIcon className={clsx('w-4 h-4 mr-2', storageLoading && 'animate-spin')} />
+            Refresh
+          </button>
+        </div>
+        {storage?.error && (
+          <div className="flex items-center gap-2 p-3 mb-4 rounded-md bg-red-50 text-red-700 text-sm">
+            <XCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>
+              {storage.adminRequired
+                ? 'Your API key does not have organization admin permissions.'
+                : storage.error}
+            </span>
+          </div>
+        )}
+        {storage && !storage.error && (
+          <table className="w-full text-sm">
+            <thead>
+              <tr className="text-left text-gray-600 border-b border-gray-200">
+                <th className="py-2">Member</th>
+                <th className="py-2">Name</th>
+                <th className="py-2 text-right">Used</th>
+                <th className="py-2 text-right">Files</th>
+              </tr>
+            </thead>
+            <tbody>
+              {storage.members.map((m) => (
+                <tr key={m.email} className="border-b border-gray-100 last:border-0">
+                  <td className="py-1.5">{m.email}</td>
+                  <td className="py-1.5 text-gray-700">{m.fullName}</td>
+                  <td className="py-1.5 text-right font-mono">{formatSize(m.usedBytes)}</td>
+                  <td className="py-1.5 text-right">{m.fileCount}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        )}
+      </div>
+
+      {confirmAction && (
+        <div className="fixed inset-0 bg-black bg-opacity-50 flex items-center justify-center z-50">
+          <div className="bg-white rounded-lg shadow-xl p-6 max-w-md mx-4">
+            <h3 className="text-lg font-semibold text-gray-900 mb-4">
+              {confirmAction === 'stop' ? 'Stop' : 'Archive'} {selected.size} job(s)?
+            </h3>
+            <p className="text-gray-600 mb-6">
+              {confirmAction === 'stop'
+                ? 'Running jobs are stopped for their owners. This cannot be undone.'
+                : 'Archived jobs are hidden from job lists; their files are kept.'}
+            </p>
+            <div className="flex justify-end gap-3">
+              <button onClick={() => setConfirmAction(null)} className="btn-secondary">Cancel</button>
+              <button onClick={() => runAction(confirmAction)} className="btn-primary">
+                {confirmAction === 'stop' ? 'Stop Jobs' : 'Archive Jobs'}
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+}
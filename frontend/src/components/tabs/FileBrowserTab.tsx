@@ -5,9 +5,12 @@ import {
   ArrowUturnLeftIcon,
   TrashIcon,
   ExclamationTriangleIcon,
+  PlusIcon,
+  XMarkIcon,
 } from '@heroicons/react/24/outline'
 import { LocalBrowser, RemoteBrowser } from '../widgets'
-import { useFileBrowserStore, useTransferStore } from '../../stores'
+import { formatSize } from '../widgets/FileList'
+import { useFileBrowserStore, useTransferStore, useConfigStore, VIEWER_MODE_TITLE, remoteTabLabel, MAX_REMOTE_TABS, formatSpeed, formatETA } from '../../stores'
 import * as App from '../../../wailsjs/go/wailsapp/App'
 import { wailsapp } from '../../../wailsjs/go/models'
 import { useTabNavigation } from '../../App'
@@ -111,6 +114,32 @@ function ErrorDialog({ isOpen, title, message, onClose }: ErrorDialogProps) {
   )
 }
 
+// Queues transfers, first asking whether to skip or repeat any that are
+// already queued or running to the same destination (e.g. a double-clicked
+// upload). Returns how many were skipped.
+async function startTransfersAskingOnDuplicates(requests: wailsapp.TransferRequestDTO[]): Promise<number> {
+  const duplicates = (await App.FindDuplicateTransfers(requests)) || []
+  if (duplicates.length === 0) {
+    await App.StartTransfers(requests)
+    return 0
+  }
+
+  const names = duplicates.slice(0, 5).map((d) => `  • ${requests[d.index].name}`).join('\n')
+  const more = duplicates.length > 5 ? `\n  …and ${duplicates.length - 5} more` : ''
+  const transferAgain = confirm(
+    `${duplicates.length} file(s) are already queued or transferring to the same destination:\n${names}${more}\n\n` +
+    'OK to transfer them again, Cancel to skip them.'
+  )
+  const repeated = new Set(duplicates.map((d) => d.index))
+  const toStart = transferAgain
+    ? requests.map((r, i) => (repeated.has(i) ? { ...r, allowDuplicate: true } : r))
+    : requests.filter((_, i) => !repeated.has(i))
+  if (toStart.length > 0) {
+    await App.StartTransfers(toStart)
+  }
+  return transferAgain ? 0 : duplicates.length
+}
+
 export function FileBrowserTab() {
   const {
     local,
@@ -124,7 +153,13 @@ export function FileBrowserTab() {
     deleteRemoteItems,
     recoverTrashItems,
     purgeTrashItems,
+    remoteTabs,
+    activeRemoteTabId,
+    openRemoteTab,
+    switchRemoteTab,
+    closeRemoteTab,
   } = useFileBrowserStore()
+  const viewerMode = useConfigStore((s) => s.viewerMode)
 
   const isTrashMode = remote.mode === 'trash'
 
@@ -178,6 +213,26 @@ export function FileBrowserTab() {
     }
   } | null>(null)
 
+  const [duplicateConfirm, setDuplicateConfirm] = useState<{
+    duplicates: string[]  // File names that already exist in the destination
+    uploadData: {
+      files: wailsapp.FileItemDTO[]
+      folders: wailsapp.FileItemDTO[]
+      destFolderId: string
+      tags: string[]
+    }
+  } | null>(null)
+
+  const [largeUploadConfirm, setLargeUploadConfirm] = useState<{
+    summary: wailsapp.LargeUploadDTO  // Size, estimated duration and quota from CheckLargeUpload
+    uploadData: {
+      files: wailsapp.FileItemDTO[]
+      folders: wailsapp.FileItemDTO[]
+      destFolderId: string
+      tags: string[]
+    }
+  } | null>(null)
+
   const [uploadTagsInput, setUploadTagsInput] = useState('')
 
   // Status message
@@ -186,6 +241,8 @@ export function FileBrowserTab() {
   // Get selection counts
   const localSelectedCount = local.selection.selectedIds.size
   const remoteSelectedCount = remote.selection.selectedIds.size
+  // Download, delete, recover and purge need a selection and write access
+  const canModifyRemote = remoteSelectedCount > 0 && !viewerMode
 
   // Upload availability and reason
   // Jobs mode: Uploads disabled (job outputs are read-only)
@@ -193,6 +250,9 @@ export function FileBrowserTab() {
   // Library mode: Uploads allowed
   // Legacy mode: Uploads allowed (files upload to user's library and appear in Legacy view)
   const uploadState = useMemo(() => {
+    if (viewerMode) {
+      return { allowed: false, reason: VIEWER_MODE_TITLE, hasSelection: localSelectedCount > 0 }
+    }
     if (remote.mode === 'jobs') {
       return { allowed: false, reason: 'N/A in Jobs view', hasSelection: localSelectedCount > 0 }
     }
@@ -203,7 +263,7 @@ export function FileBrowserTab() {
       return { allowed: false, reason: 'Select files', hasSelection: false }
     }
     return { allowed: true, reason: '', hasSelection: true }
-  }, [remote.mode, localSelectedCount])
+  }, [viewerMode, remote.mode, localSelectedCount])
 
   // Handle resize
   const handleMouseDown = useCallback((e: React.MouseEvent) => {
@@ -263,7 +323,8 @@ export function FileBrowserTab() {
     files: wailsapp.FileItemDTO[],
     folders: wailsapp.FileItemDTO[],
     destFolderId: string,
-    tags: string[] = []
+    tags: string[] = [],
+    names?: wailsapp.UploadNameDTO[]  // Duplicate-name outcome per file, from PlanUploadNames
   ) => {
     setIsUploading(true)
     const totalItems = files.length + folders.length
@@ -311,31 +372,45 @@ export function FileBrowserTab() {
 
       // Upload individual files using transfer queue.
       // Batch grouping for 50+ files to collapse into single row in Transfers tab.
-      if (files.length > 0) {
-        const batchID = files.length >= 50 ? `fb_upload_${Date.now()}` : undefined
-        const batchLabel = files.length >= 50 ? `Upload: ${files.length} files` : undefined
-        const requests = files.map(item => ({
+      const toUpload = files
+        .map((item, i) => ({ item, plan: names?.[i] }))
+        .filter(({ plan }) => !plan?.skip)
+      let repeated = 0
+      if (toUpload.length > 0) {
+        const batchID = toUpload.length >= 50 ? `fb_upload_${Date.now()}` : undefined
+        const batchLabel = toUpload.length >= 50 ? `Upload: ${toUpload.length} files` : undefined
+        const requests = toUpload.map(({ item, plan }) => ({
           type: 'upload',
           source: item.id,
           dest: destFolderId,
-          name: item.name,
+          name: plan?.name ?? item.name,
           size: item.size ?? 0,
           sourceLabel: 'FileBrowser',
           tags: tags.length > 0 ? tags : undefined,
           batchID,
           batchLabel,
+          remoteName: plan && plan.name !== item.name ? plan.name : undefined,
+          replaceFileIDs: plan?.replaceFileIDs,
         }))
-        await App.StartTransfers(requests)
+        repeated = await startTransfersAskingOnDuplicates(requests)
       }
+      const skipped = files.length - toUpload.length
 
       clearLocalSelection()
 
       const statusParts = []
       if (folders.length > 0) statusParts.push(`${folders.length} folder(s)`)
-      if (files.length > 0) statusParts.push(`${files.length} file(s)`)
-      setStatus(`Upload started: ${statusParts.join(' and ')}.`)
-
-      if (files.length > 0 || folders.length > 0) {
+      if (toUpload.length > repeated) statusParts.push(`${toUpload.length - repeated} file(s)`)
+      const skippedParts = []
+      if (skipped > 0) skippedParts.push(`${skipped} existing`)
+      if (repeated > 0) skippedParts.push(`${repeated} already queued`)
+      setStatus(statusParts.length > 0
+        ? `Upload started: ${statusParts.join(' and ')}${skippedParts.length > 0 ? ` (${skippedParts.join(', ')} skipped)` : ''}.`
+        : repeated > 0
+          ? `Nothing to upload: ${skippedParts.join(', ')} skipped.`
+          : `Nothing to upload: ${skipped} file(s) already exist.`)
+
+      if (toUpload.length > 0 || folders.length > 0) {
         switchToTab('Transfers')
       }
 
@@ -348,28 +423,67 @@ export function FileBrowserTab() {
     }
   }, [clearLocalSelection, switchToTab, refreshRemote])
 
+  // Check the selected files' names against the destination (one folder
+  // listing) and ask how to handle duplicates before uploading.
+  const uploadCheckingDuplicates = useCallback(async (
+    files: wailsapp.FileItemDTO[],
+    folders: wailsapp.FileItemDTO[],
+    destFolderId: string,
+    tags: string[]
+  ) => {
+    if (files.length === 0) {
+      await proceedWithUpload(files, folders, destFolderId, tags)
+      return
+    }
+    setStatus('Checking for existing files…')
+    let plan: wailsapp.UploadNamesPlanDTO
+    try {
+      plan = await App.PlanUploadNames(files.map(f => f.name), destFolderId, 'allow')
+    } catch (err) {
+      plan = { files: [], error: err instanceof Error ? err.message : String(err) } as wailsapp.UploadNamesPlanDTO
+    }
+    if (plan.error) {
+      switchToTab('File Browser')
+      setErrorDialog({ title: 'Upload Error', message: `Failed to check for existing files: ${plan.error}` })
+      setStatus('')
+      return
+    }
+    const duplicates = files.filter((_, i) => plan.files[i]?.exists).map(f => f.name)
+    if (duplicates.length > 0) {
+      switchToTab('File Browser')
+      setDuplicateConfirm({ duplicates, uploadData: { files, folders, destFolderId, tags } })
+      setStatus('Waiting for duplicate-name choice…')
+      return
+    }
+    await proceedWithUpload(files, folders, destFolderId, tags)
+  }, [proceedWithUpload, switchToTab])
+
+  const resolveDuplicates = useCallback(async (policy: string) => {
+    if (!duplicateConfirm) return
+    const { files, folders, destFolderId, tags } = duplicateConfirm.uploadData
+    setDuplicateConfirm(null)
+    const plan = await App.PlanUploadNames(files.map(f => f.name), destFolderId, policy)
+    if (plan.error) {
+      setErrorDialog({ title: 'Upload Error', message: plan.error })
+      setStatus('')
+      return
+    }
+    await proceedWithUpload(files, folders, destFolderId, tags, plan.files)
+  }, [duplicateConfirm, proceedWithUpload])
+
   const parsedUploadTags = useMemo(() => {
     if (!uploadTagsInput.trim()) return []
     return uploadTagsInput.split(',').map(t => t.trim()).filter(Boolean)
   }, [uploadTagsInput])
 
-  // Confirm upload — checks for existing folders to offer merge before proceeding
-  const confirmUpload = useCallback(async () => {
-    if (!uploadConfirm) return
-
-    const tags = parsedUploadTags
-    setUploadConfirm(null)
-    setUploadTagsInput('')
-
-    // Separate files and folders
-    const files = uploadConfirm.items.filter(item => !item.isFolder)
-    const folders = uploadConfirm.items.filter(item => item.isFolder)
-
-    // Use frozen values from click time instead of live store state
-    const destFolderId = uploadConfirm.frozenMode === 'legacy'
-      ? (uploadConfirm.frozenMyLibraryId || uploadConfirm.frozenDestFolderId)
-      : uploadConfirm.frozenDestFolderId
-
+  // Start a confirmed upload — checks for existing folders to offer merge
+  // before proceeding
+  const startUpload = useCallback(async (
+    files: wailsapp.FileItemDTO[],
+    folders: wailsapp.FileItemDTO[],
+    destFolderId: string,
+    tags: string[]
+  ) => {
     // Check if any folders already exist before uploading.
     // Uses batch check with shared cache (single API paginate instead of N calls).
     //
@@ -448,15 +562,59 @@ export function FileBrowserTab() {
     }
 
     // No existing folders - proceed directly
-    await proceedWithUpload(files, folders, destFolderId, tags)
-  }, [uploadConfirm, parsedUploadTags, proceedWithUpload, switchToTab])
+    await uploadCheckingDuplicates(files, folders, destFolderId, tags)
+  }, [uploadCheckingDuplicates, switchToTab])
+
+  // Confirm upload — asks again for very large batches, then checks for
+  // existing folders to offer merge before proceeding
+  const confirmUpload = useCallback(async () => {
+    if (!uploadConfirm) return
+
+    const tags = parsedUploadTags
+    setUploadConfirm(null)
+    setUploadTagsInput('')
+
+    // Separate files and folders
+    const files = uploadConfirm.items.filter(item => !item.isFolder)
+    const folders = uploadConfirm.items.filter(item => item.isFolder)
+
+    // Use frozen values from click time instead of live store state
+    const destFolderId = uploadConfirm.frozenMode === 'legacy'
+      ? (uploadConfirm.frozenMyLibraryId || uploadConfirm.frozenDestFolderId)
+      : uploadConfirm.frozenDestFolderId
+
+    // Batches over the large-upload threshold show their size, estimated
+    // duration and remaining workspace storage before anything starts.
+    // Sizing errors are left for the upload itself to report.
+    setStatus('Measuring upload size…')
+    const large = await App.CheckLargeUpload(uploadConfirm.items.map(item => item.id))
+    if (large.needed) {
+      setLargeUploadConfirm({ summary: large, uploadData: { files, folders, destFolderId, tags } })
+      setStatus('Waiting for large-upload confirmation…')
+      return
+    }
+
+    await startUpload(files, folders, destFolderId, tags)
+  }, [uploadConfirm, parsedUploadTags, startUpload])
+
+  const confirmLargeUpload = useCallback(async () => {
+    if (!largeUploadConfirm) return
+    const { files, folders, destFolderId, tags } = largeUploadConfirm.uploadData
+    setLargeUploadConfirm(null)
+    await startUpload(files, folders, destFolderId, tags)
+  }, [largeUploadConfirm, startUpload])
+
+  const cancelLargeUpload = useCallback(() => {
+    setLargeUploadConfirm(null)
+    setStatus('Upload cancelled.')
+  }, [])
 
   const confirmMerge = useCallback(async () => {
     if (!mergeConfirm) return
     const { files, folders, destFolderId, tags } = mergeConfirm.uploadData
     setMergeConfirm(null)
-    await proceedWithUpload(files, folders, destFolderId, tags)
-  }, [mergeConfirm, proceedWithUpload])
+    await uploadCheckingDuplicates(files, folders, destFolderId, tags)
+  }, [mergeConfirm, uploadCheckingDuplicates])
 
   const cancelMerge = useCallback(() => {
     setMergeConfirm(null)
@@ -464,7 +622,7 @@ export function FileBrowserTab() {
   }, [])
 
   // Handle download button click
-  const handleDownload = useCallback(() => {
+  const handleDownload = useCallback(async () => {
     if (remoteSelectedCount === 0) return
 
     const selectedItems = getRemoteSelectedItems()
@@ -472,16 +630,34 @@ export function FileBrowserTab() {
 
     const folderCount = selectedItems.filter(item => item.isFolder).length
 
-    const destPath = local.currentPath || 'Home'
+    // A configured default download folder wins over the local browser's
+    // location. Organizing "by job" uses the job folder under My Jobs,
+    // otherwise the remote folder being browsed.
+    const label = remote.mode === 'jobs' && remote.breadcrumb.length > 1
+      ? remote.breadcrumb[1].name
+      : remote.breadcrumb[remote.breadcrumb.length - 1]?.name ?? ''
+    let targetPath = local.currentPath
+    try {
+      const configured = await App.ResolveDownloadDestination(label)
+      if (configured) {
+        targetPath = configured
+      }
+    } catch (err) {
+      setErrorDialog({
+        title: 'Download Folder Unavailable',
+        message: err instanceof Error ? err.message : String(err),
+      })
+      return
+    }
 
-    // Snapshot local path at click time to prevent stale references
+    // Snapshot the destination at click time to prevent stale references
     setDownloadConfirm({
       items: selectedItems,
-      destPath,
+      destPath: targetPath || 'Home',
       folderCount,
-      frozenLocalPath: local.currentPath,
+      frozenLocalPath: targetPath,
     })
-  }, [remoteSelectedCount, getRemoteSelectedItems, local.currentPath])
+  }, [remoteSelectedCount, getRemoteSelectedItems, local.currentPath, remote.mode, remote.breadcrumb])
 
   // Confirm download
   const confirmDownload = useCallback(async () => {
@@ -571,6 +747,7 @@ export function FileBrowserTab() {
       }
 
       // Download individual files using transfer queue
+      let repeated = 0
       if (files.length > 0) {
         const batchID = files.length >= 50 ? `fb_download_${Date.now()}` : undefined
         const batchLabel = files.length >= 50 ? `Download: ${files.length} files` : undefined
@@ -586,15 +763,18 @@ export function FileBrowserTab() {
           batchID,
           batchLabel,
         }))
-        await App.StartTransfers(requests)
+        repeated = await startTransfersAskingOnDuplicates(requests)
       }
 
       clearRemoteSelection()
 
       const statusParts = []
       if (folders.length > 0) statusParts.push(`${folders.length} folder(s)`)
-      if (files.length > 0) statusParts.push(`${files.length} file(s)`)
-      setStatus(`Download started: ${statusParts.join(' and ')}.`)
+      if (files.length > repeated) statusParts.push(`${files.length - repeated} file(s)`)
+      const skippedNote = repeated > 0 ? ` (${repeated} already queued skipped)` : ''
+      setStatus(statusParts.length > 0
+        ? `Download started: ${statusParts.join(' and ')}${skippedNote}.`
+        : `Nothing to download: ${repeated} file(s) are already queued.`)
 
       if (files.length > 0 || folders.length > 0) {
         switchToTab('Transfers')
@@ -792,10 +972,10 @@ export function FileBrowserTab() {
                 <>
                   <button
                     onClick={handleRecover}
-                    disabled={remoteSelectedCount === 0}
-                    title="Recover selected items to their original location"
+                    disabled={!canModifyRemote}
+                    title={viewerMode ? VIEWER_MODE_TITLE : 'Recover selected items to their original location'}
                     className={`flex items-center gap-1 px-3 py-1 text-sm rounded min-w-0 max-w-[10rem] ${
-                      remoteSelectedCount > 0
+                      canModifyRemote
                         ? 'bg-blue-500 text-white hover:bg-blue-600'
                         : 'bg-gray-300 dark:bg-gray-600 text-gray-500 dark:text-gray-400 cursor-not-allowed'
                     }`}
@@ -805,10 +985,10 @@ export function FileBrowserTab() {
                   </button>
                   <button
                     onClick={handlePurge}
-                    disabled={remoteSelectedCount === 0}
-                    title="Permanently delete selected items - cannot be undone"
+                    disabled={!canModifyRemote}
+                    title={viewerMode ? VIEWER_MODE_TITLE : 'Permanently delete selected items - cannot be undone'}
                     className={`flex items-center gap-1 px-3 py-1 text-sm rounded min-w-0 max-w-[12rem] ${
-                      remoteSelectedCount > 0
+                      canModifyRemote
                         ? 'bg-red-500 text-white hover:bg-red-600'
                         : 'bg-gray-300 dark:bg-gray-600 text-gray-500 dark:text-gray-400 cursor-not-allowed'
                     }`}
@@ -821,10 +1001,10 @@ export function FileBrowserTab() {
                 <>
                   <button
                     onClick={handleDownload}
-                    disabled={remoteSelectedCount === 0 || isDownloading}
-                    title="Download selected files to local"
+                    disabled={!canModifyRemote || isDownloading}
+                    title={viewerMode ? VIEWER_MODE_TITLE : 'Download selected files to local'}
                     className={`flex items-center gap-1 px-3 py-1 text-sm rounded min-w-0 max-w-[11rem] ${
-                      remoteSelectedCount > 0 && !isDownloading
+                      canModifyRemote && !isDownloading
                         ? 'bg-blue-500 text-white hover:bg-blue-600'
                         : 'bg-gray-300 dark:bg-gray-600 text-gray-500 dark:text-gray-400 cursor-not-allowed'
                     }`}
@@ -837,10 +1017,10 @@ export function FileBrowserTab() {
                   </button>
                   <button
                     onClick={handleDelete}
-                    disabled={remoteSelectedCount === 0 || isDeleting}
-                    title="Move selected items to Trash (recoverable)"
+                    disabled={!canModifyRemote || isDeleting}
+                    title={viewerMode ? VIEWER_MODE_TITLE : 'Move selected items to Trash (recoverable)'}
                     className={`flex items-center gap-1 px-3 py-1 text-sm rounded min-w-0 max-w-[8rem] ${
-                      remoteSelectedCount > 0 && !isDeleting
+                      canModifyRemote && !isDeleting
                         ? 'bg-red-500 text-white hover:bg-red-600'
                         : 'bg-gray-300 dark:bg-gray-600 text-gray-500 dark:text-gray-400 cursor-not-allowed'
                     }`}
@@ -853,9 +1033,48 @@ export function FileBrowserTab() {
             </div>
           </div>
 
+          {/* Remote browser tabs — uploads and downloads use the active tab */}
+          <div className="flex items-center gap-1 px-2 pt-1 bg-gray-50 dark:bg-gray-800 border-b border-gray-200 dark:border-gray-700 overflow-x-auto">
+            {remoteTabs.map(tab => {
+              const isActive = tab.id === activeRemoteTabId
+              const tabState = tab.state ?? remote
+              return (
+                <div
+                  key={tab.id}
+                  onClick={() => switchRemoteTab(tab.id)}
+                  title={tabState.breadcrumb.map(b => b.name).join(' > ') || remoteTabLabel(tabState)}
+                  className={`flex items-center gap-1 px-2 py-1 text-xs rounded-t cursor-pointer max-w-[10rem] min-w-0 ${
+                    isActive
+                      ? 'bg-white dark:bg-gray-900 border border-b-0 border-gray-200 dark:border-gray-700 font-medium'
+                      : 'text-gray-500 dark:text-gray-400 hover:bg-gray-100 dark:hover:bg-gray-700'
+                  }`}
+                >
+                  <span className="truncate">{remoteTabLabel(tabState)}</span>
+                  {remoteTabs.length > 1 && (
+                    <button
+                      onClick={(e) => { e.stopPropagation(); closeRemoteTab(tab.id) }}
+                      title="Close tab"
+                      className="flex-shrink-0 p-0.5 rounded hover:bg-gray-200 dark:hover:bg-gray-600"
+                    >
+                      <XMarkIcon className="w-3 h-3" />
+                    </button>
+                  )}
+                </div>
+              )
+            })}
+            <button
+              onClick={openRemoteTab}
+              disabled={remoteTabs.length >= MAX_REMOTE_TABS}
+              title={remoteTabs.length >= MAX_REMOTE_TABS ? `At most ${MAX_REMOTE_TABS} tabs` : 'Open a new tab'}
+              className="flex-shrink-0 p-1 rounded text-gray-500 hover:bg-gray-100 dark:hover:bg-gray-700 disabled:opacity-40 disabled:cursor-not-allowed"
+            >
+              <PlusIcon className="w-4 h-4" />
+            </button>
+          </div>
+
           {/* Remote browser */}
           <div className="flex-1 overflow-hidden">
-            <RemoteBrowser />
+            <RemoteBrowser key={activeRemoteTabId} />
           </div>
         </div>
       </div>
@@ -952,6 +1171,97 @@ export function FileBrowserTab() {
         onCancel={cancelMerge}
       />
 
+      <ConfirmDialog
+        isOpen={largeUploadConfirm !== null}
+        title="Large Upload"
+        message={
+          largeUploadConfirm
+            ? [
+                `Total size: ${formatSize(largeUploadConfirm.summary.totalBytes)} in ${largeUploadConfirm.summary.files} file(s)`,
+                largeUploadConfirm.summary.estimateSeconds > 0
+                  ? `Estimated duration: ${formatETA(largeUploadConfirm.summary.estimateSeconds * 1000)} at ${formatSpeed(largeUploadConfirm.summary.bytesPerSec)} (measured from recent uploads)`
+                  : 'Estimated duration: unknown (no recent uploads to measure)',
+                ...(largeUploadConfirm.summary.quotaKnown
+                  ? [`Workspace storage remaining: ${largeUploadConfirm.summary.quotaRemaining > 0 ? formatSize(largeUploadConfirm.summary.quotaRemaining) : 'none'}`]
+                  : []),
+                '',
+                `This is more than the ${formatSize(largeUploadConfirm.summary.thresholdBytes)} confirmation threshold (Setup tab). Start the upload?`,
+              ].join('\n')
+            : ''
+        }
+        confirmText="Upload"
+        isDanger={largeUploadConfirm?.summary.exceedsQuota}
+        warning={
+          largeUploadConfirm?.summary.exceedsQuota
+            ? 'This upload is larger than the storage remaining in your workspace and may fail part way.'
+            : undefined
+        }
+        onConfirm={confirmLargeUpload}
+        onCancel={cancelLargeUpload}
+      />
+
+      {/* File upload duplicate-name dialog */}
+      {duplicateConfirm && (
+        <div className="fixed inset-0 bg-black/50 flex items-center justify-center z-50">
+          <div className="bg-white dark:bg-gray-800 rounded-lg shadow-lg p-4 w-96 max-w-[90vw]">
+            <h3 className="text-lg font-medium mb-2">Files Already Exist</h3>
+            <p className="text-sm text-gray-600 dark:text-gray-400 mb-2">
+              {duplicateConfirm.duplicates.length === 1
+                ? `"${duplicateConfirm.duplicates[0]}" already exists in the destination folder.`
+                : `${duplicateConfirm.duplicates.length} files already exist in the destination folder:`}
+            </p>
+            {duplicateConfirm.duplicates.length > 1 && (
+              <ul className="text-sm text-gray-600 dark:text-gray-400 mb-3 ml-4 list-disc max-h-32 overflow-y-auto">
+                {duplicateConfirm.duplicates.map(name => (
+                  <li key={name}>{name}</li>
+                ))}
+              </ul>
+            )}
+            <p className="text-sm text-gray-600 dark:text-gray-400 mb-4">
+              How would you like to proceed?
+            </p>
+            <div className="flex flex-col gap-2">
+              <button
+                onClick={() => resolveDuplicates('skip')}
+                className="w-full px-4 py-2 text-sm text-white bg-blue-500 hover:bg-blue-600 rounded"
+              >
+                Skip — upload only new files
+              </button>
+              <button
+                onClick={() => resolveDuplicates('rename')}
+                className="w-full px-4 py-2 text-sm text-white bg-blue-500 hover:bg-blue-600 rounded"
+              >
+                Rename — upload as "name (1).ext"
+              </button>
+              <button
+                onClick={() => resolveDuplicates('version')}
+                className="w-full px-4 py-2 text-sm text-white bg-blue-500 hover:bg-blue-600 rounded"
+              >
+                Add version — upload as "name_v2.ext"
+              </button>
+              <button
+                onClick={() => resolveDuplicates('overwrite')}
+                className="w-full px-4 py-2 text-sm text-white bg-red-500 hover:bg-red-600 rounded"
+              >
+                Overwrite — delete existing after upload
+              </button>
+              <button
+                onClick={() => resolveDuplicates('allow')}
+                className="w-full px-4 py-2 text-sm text-gray-700 dark:text-gray-300 bg-gray-200 dark:bg-gray-700 hover:bg-gray-300 dark:hover:bg-gray-600 rounded"
+              >
+                Keep both — upload with the same name
+              </button>
+              <button
+                onClick={() => { setDuplicateConfirm(null); setStatus('Upload cancelled.') }}
+                className="w-full px-4 py-2 text-sm text-gray-600 dark:text-gray-400 hover:bg-gray-100 dark:hover:bg-gray-700 rounded"
+              >
+                Cancel
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
+
       {/* Folder download conflict dialog */}
       {folderConflict && (
         <div className="fixed inset-0 bg-black/50 flex items-center justify-center z-50">
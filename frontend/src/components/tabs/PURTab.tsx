@@ -16,11 +16,12 @@ import {
   EyeIcon,
 } from '@heroicons/react/24/outline'
 import clsx from 'clsx'
-import { useJobStore, useConfigStore, useRunStore } from '../../stores'
-import type { WorkflowState } from '../../types/jobs'
+import { useJobStore, useConfigStore, useRunStore, VIEWER_MODE_TITLE } from '../../stores'
+import type { JobRow, WorkflowState } from '../../types/jobs'
 import { wailsapp } from '../../../wailsjs/go/models'
-import { TemplateBuilder, JobsTable, StatsBar, PipelineStageSummary, PipelineLogPanel, ErrorSummary } from '../widgets'
+import { TemplateBuilder, JobsTable, ExportTableButton, StatsBar, PipelineStageSummary, PipelineLogPanel, ErrorSummary, JobDiffPanel, TarContentsPanel, LiveFilesPanel, BlackoutBanner } from '../widgets'
 import { formatDuration } from '../../utils/formatDuration'
+import { formatSize } from '../widgets/FileList'
 import * as App from '../../../wailsjs/go/wailsapp/App'
 import * as Runtime from '../../../wailsjs/runtime/runtime'
 
@@ -93,6 +94,13 @@ function WorkflowProgressBar({ currentState }: { currentState: WorkflowState })
 
 const COMPRESSION_OPTIONS = ['gzip', 'none'] as const
 
+// Network data moved by a finished run, for the run summary line
+function networkSummary(run: { bytesSent?: number; bytesReceived?: number }) {
+  if (!run.bytesSent && !run.bytesReceived) return null
+  const bytes = (n?: number) => (n ? formatSize(n) : '0 B')
+  return ` · Network: ${bytes(run.bytesSent)} sent, ${bytes(run.bytesReceived)} received`
+}
+
 function PipelineSettings({ config, updateConfig, saveConfig }: {
   config: wailsapp.ConfigDTO | null
   updateConfig: (updates: Partial<wailsapp.ConfigDTO>) => void
@@ -184,6 +192,107 @@ function PipelineSettings({ config, updateConfig, saveConfig }: {
               ))}
             </select>
           </div>
+          <div>
+            <label className="block text-xs text-gray-500 mb-1">Split Into Multiple Tars</label>
+            <select
+              className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              value={config?.tarSplitMode || 'none'}
+              onChange={(e) => {
+                updateConfig({ tarSplitMode: e.target.value })
+                saveConfig()
+              }}
+            >
+              <option value="none">Off (one tar per job)</option>
+              <option value="subdirs">One tar per subdirectory</option>
+              <option value="size">Fixed number of tars by size</option>
+            </select>
+          </div>
+          {config?.tarSplitMode === 'size' && (
+            <div>
+              <label className="block text-xs text-gray-500 mb-1">Tars per Job</label>
+              <input
+                type="number"
+                min={2}
+                max={64}
+                className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                value={config?.tarSplitParts || 4}
+                onChange={(e) => updateConfig({ tarSplitParts: parseInt(e.target.value) || 4 })}
+                onBlur={() => saveConfig()}
+              />
+            </div>
+          )}
+          <div>
+            <label className="block text-xs text-gray-500 mb-1">Locked Files (Windows)</label>
+            <select
+              className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              value={config?.tarLockedFiles || 'fail'}
+              onChange={(e) => {
+                updateConfig({ tarLockedFiles: e.target.value })
+                saveConfig()
+              }}
+              title="What to do when a solver or Excel holds a file open while the job is tarred"
+            >
+              <option value="fail">Fail the job</option>
+              <option value="skip">Skip and warn</option>
+              <option value="retry">Retry after a delay</option>
+              <option value="snapshot">Read from shadow copy (admin)</option>
+            </select>
+          </div>
+          <div>
+            <label className="block text-xs text-gray-500 mb-1">Text Line Endings / BOM</label>
+            <select
+              className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              value={config?.textNormalize || 'off'}
+              onChange={(e) => {
+                updateConfig({ textNormalize: e.target.value })
+                saveConfig()
+              }}
+              title="Check text input files for Windows CRLF line endings and UTF-8 BOMs, which break some solvers' input decks"
+            >
+              <option value="off">Archive as-is</option>
+              <option value="check">Check and warn</option>
+              <option value="fix">Convert to LF, remove BOM</option>
+            </select>
+          </div>
+          {config?.textNormalize && config.textNormalize !== 'off' && (
+            <div>
+              <label className="block text-xs text-gray-500 mb-1">Text File Extensions</label>
+              <input
+                type="text"
+                className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                value={config?.textNormalizeExtensions || ''}
+                placeholder=".inp;.in;.dat;.cfg;.sh;.py"
+                onChange={(e) => updateConfig({ textNormalizeExtensions: e.target.value })}
+                onBlur={() => saveConfig()}
+              />
+            </div>
+          )}
+          {config?.tarLockedFiles === 'retry' && (
+            <div className="grid grid-cols-2 gap-3">
+              <div>
+                <label className="block text-xs text-gray-500 mb-1">Retry After (seconds)</label>
+                <input
+                  type="number"
+                  min={1}
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  value={config?.tarLockRetrySeconds || 30}
+                  onChange={(e) => updateConfig({ tarLockRetrySeconds: parseInt(e.target.value) || 30 })}
+                  onBlur={() => saveConfig()}
+                />
+              </div>
+              <div>
+                <label className="block text-xs text-gray-500 mb-1">Retries</label>
+                <input
+                  type="number"
+                  min={0}
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  value={config?.tarLockRetries ?? 3}
+                  onChange={(e) => updateConfig({ tarLockRetries: parseInt(e.target.value) || 0 })}
+                  onBlur={() => saveConfig()}
+                />
+              </div>
+            </div>
+          )}
           <div className="flex items-center">
             <input
               type="checkbox"
@@ -201,7 +310,9 @@ function PipelineSettings({ config, updateConfig, saveConfig }: {
           </div>
         </div>
         <p className="mt-1 text-xs text-gray-400">
-          Patterns support wildcards (*). Use comma-separated list.
+          Patterns support wildcards (*). Use comma-separated list. Split tars are uploaded in parallel and
+          decompressed on the cluster into the original layout; splitting is skipped when flattening.
+          Skipped locked files are listed in the run's state file and log.
         </p>
       </div>
     </div>
@@ -239,6 +350,7 @@ export function PURTab() {
     saveJobToSGE,
     purRunOptions,
     setPURRunOptions,
+    setJobPriority,
   } = useJobStore()
 
   const {
@@ -250,18 +362,25 @@ export function PURTab() {
     clearActiveRun,
     setQueuedJob,
     cancelRun: cancelActiveRun,
+    setJobPlatformStatus,
+    blackoutUntil,
   } = useRunStore()
 
-  const { config, updateConfig, saveConfig } = useConfigStore()
+  const { config, viewerMode, updateConfig, saveConfig } = useConfigStore()
 
   // Template builder dialog state
   const [showTemplateBuilder, setShowTemplateBuilder] = useState(false)
   const [validationErrors, setValidationErrors] = useState<string[]>([])
   const [isValidating, setIsValidating] = useState(false)
   const [showLoadMenu, setShowLoadMenu] = useState(false)
+  const [showImportJob, setShowImportJob] = useState(false)
+  const [importJobRef, setImportJobRef] = useState('')
+  const [pendingImport, setPendingImport] = useState<wailsapp.JobImportResultDTO | null>(null)
   const [showSaveMenu, setShowSaveMenu] = useState(false)
   const [loadSaveError, setLoadSaveError] = useState<string | null>(null)
   const [monitorBannerCollapsed, setMonitorBannerCollapsed] = useState(false)
+  const [contentsJob, setContentsJob] = useState<wailsapp.JobSpecDTO | null>(null)
+  const [liveFilesJob, setLiveFilesJob] = useState<JobRow | null>(null)
 
   const effectiveView = useMemo(() => {
     if (purViewMode === 'monitor' || purViewMode === 'configure') return purViewMode
@@ -313,6 +432,7 @@ export function PURTab() {
   // Local state for CSV loading
   const [isLoadingCSV, setIsLoadingCSV] = useState(false)
   const [csvLoadError, setCsvLoadError] = useState<string | null>(null)
+  const [showJobDiff, setShowJobDiff] = useState(false)
 
   // Handle path selection - CSV loading
   const handleLoadCSV = useCallback(async () => {
@@ -363,6 +483,12 @@ export function PURTab() {
       projectId: loaded.projectId || '',
       orgCode: loaded.orgCode || '',
       automations: loaded.automations || [],
+      destinationFolder: loaded.destinationFolder || '',
+      continuationCommand: loaded.continuationCommand || '',
+      metadata: loaded.metadata || {},
+      envVars: loaded.envVars || {},
+      useRescaleLicense: loaded.useRescaleLicense || false,
+      clusterId: loaded.clusterId || '',
     })
   }, [setTemplate])
 
@@ -398,6 +524,21 @@ export function PURTab() {
     }
   }, [mapDTOToTemplate])
 
+  const handleImportFromRescale = useCallback(async () => {
+    try {
+      setLoadSaveError(null)
+      const result = await App.ImportJobFromRescale(importJobRef)
+      if (result.warnings && result.warnings.length > 0) {
+        // Let the user read what could not be imported before applying
+        setPendingImport(result)
+        return
+      }
+      mapDTOToTemplate(result.job)
+    } catch (error) {
+      setLoadSaveError(error instanceof Error ? error.message : String(error))
+    }
+  }, [importJobRef, mapDTOToTemplate])
+
   const handleLoadTemplateFromSGE = useCallback(async () => {
     setShowLoadMenu(false)
     try {
@@ -517,6 +658,41 @@ export function PURTab() {
     cancelRun()
   }, [cancelActiveRun, cancelRun])
 
+  // Rows index into scannedJobs; fall back to the row's own directory when
+  // they no longer match (e.g. jobs rescanned while a run is monitored)
+  const handleShowContents = useCallback((row: JobRow) => {
+    const spec = scannedJobs[row.index]
+    if (spec && spec.directory === row.directory) {
+      setContentsJob(spec as wailsapp.JobSpecDTO)
+    } else {
+      setContentsJob({ directory: row.directory, jobName: row.jobName } as wailsapp.JobSpecDTO)
+    }
+  }, [scannedJobs])
+
+  const contentsPanel = contentsJob && (
+    <TarContentsPanel job={contentsJob} onClose={() => setContentsJob(null)} />
+  )
+
+  const liveFilesPanel = liveFilesJob && (
+    <LiveFilesPanel
+      key={liveFilesJob.jobId}
+      jobId={liveFilesJob.jobId}
+      jobName={liveFilesJob.jobName}
+      onClose={() => setLiveFilesJob(null)}
+    />
+  )
+
+  const handleStopJob = useCallback(async (job: JobRow) => {
+    if (!confirm(`Stop job "${job.jobName}" (${job.jobId}) on Rescale?\n\nThis cannot be undone.`)) return
+    try {
+      const status = await App.StopJob(job.jobId)
+      setJobPlatformStatus(job.jobId, status || 'Stopping')
+    } catch (err) {
+      console.error('Failed to stop job:', err)
+      alert(`Failed to stop job: ${err}`)
+    }
+  }, [setJobPlatformStatus])
+
   const handleStartOver = useCallback(async () => {
     await clearActiveRun()
     reset()
@@ -603,6 +779,18 @@ export function PURTab() {
               <span className="text-sm text-gray-500">Scan directories for jobs</span>
             </button>
           </div>
+          <div className="mt-6">
+            {showJobDiff ? (
+              <JobDiffPanel onClose={() => setShowJobDiff(false)} />
+            ) : (
+              <button
+                onClick={() => setShowJobDiff(true)}
+                className="text-sm text-blue-600 hover:underline"
+              >
+                Compare two jobs files…
+              </button>
+            )}
+          </div>
         </div>
       )
     }
@@ -675,14 +863,65 @@ export function PURTab() {
                   </button>
                   <button
                     onClick={handleLoadTemplateFromSGE}
-                    className="w-full px-4 py-2 text-left hover:bg-gray-100 dark:hover:bg-gray-700 rounded-b-lg"
+                    className="w-full px-4 py-2 text-left hover:bg-gray-100 dark:hover:bg-gray-700"
                   >
                     SGE Script
                   </button>
+                  <button
+                    onClick={() => {
+                      setShowLoadMenu(false)
+                      setShowImportJob(true)
+                    }}
+                    className="w-full px-4 py-2 text-left hover:bg-gray-100 dark:hover:bg-gray-700 rounded-b-lg"
+                  >
+                    Rescale Job...
+                  </button>
                 </div>
               )}
             </div>
           </div>
+          {showImportJob && (
+            <div className="mt-4 w-full max-w-md">
+              <label className="label">Job ID or URL</label>
+              <div className="flex gap-2">
+                <input
+                  type="text"
+                  className="input flex-1"
+                  value={importJobRef}
+                  onChange={(e) => {
+                    setImportJobRef(e.target.value)
+                    setPendingImport(null)
+                  }}
+                  placeholder="https://platform.rescale.com/jobs/AbCdE/"
+                />
+                <button
+                  onClick={handleImportFromRescale}
+                  disabled={!importJobRef.trim()}
+                  className="px-4 py-2 bg-blue-500 text-white rounded hover:bg-blue-600 disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  Import
+                </button>
+              </div>
+              <p className="mt-1 text-xs text-gray-500">
+                Copies software, hardware, command, license variables and input file references from an existing job.
+              </p>
+              {pendingImport && (
+                <div className="mt-3 p-3 bg-amber-50 dark:bg-amber-900/20 border border-amber-200 dark:border-amber-800 rounded text-sm text-amber-700 dark:text-amber-400">
+                  <ul className="list-disc ml-4 space-y-1">
+                    {pendingImport.warnings.map((w) => (
+                      <li key={w}>{w}</li>
+                    ))}
+                  </ul>
+                  <button
+                    onClick={() => mapDTOToTemplate(pendingImport.job)}
+                    className="mt-2 px-3 py-1 bg-blue-500 text-white rounded hover:bg-blue-600"
+                  >
+                    Use These Settings
+                  </button>
+                </div>
+              )}
+            </div>
+          )}
           {loadSaveError && (
             <div className="mt-4 p-3 bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 rounded text-red-700 dark:text-red-400 text-sm max-w-md">
               {loadSaveError}
@@ -863,6 +1102,19 @@ export function PURTab() {
                 </div>
               </>
             )}
+            <div>
+              <label className="block text-sm font-medium mb-1">
+                Output Patterns (optional)
+              </label>
+              <input
+                type="text"
+                value={scanOptions.outputPatterns || ''}
+                onChange={(e) => setScanOptions({ outputPatterns: e.target.value })}
+                placeholder="*.out, *.log"
+                className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              />
+              <p className="mt-1 text-xs text-gray-500">Download matching output files into each job's directory when it completes</p>
+            </div>
           </div>
 
           {scanOptions.scanMode === 'files' && (
@@ -1135,7 +1387,8 @@ export function PURTab() {
             </div>
           )}
 
-          <JobsTable jobs={jobRows} />
+          {contentsPanel}
+          <JobsTable jobs={jobRows} onShowContents={handleShowContents} />
         </div>
       )
     }
@@ -1159,10 +1412,11 @@ export function PURTab() {
               {activeRun?.status === 'active' ? (
                 <button
                   onClick={handleQueueRun}
-                  disabled={!!queuedJob}
+                  disabled={!!queuedJob || viewerMode}
+                  title={viewerMode ? VIEWER_MODE_TITLE : undefined}
                   className={clsx(
                     'flex items-center gap-2 px-4 py-2 rounded',
-                    queuedJob
+                    queuedJob || viewerMode
                       ? 'bg-gray-400 text-white cursor-not-allowed'
                       : 'bg-yellow-500 text-white hover:bg-yellow-600'
                   )}
@@ -1173,7 +1427,12 @@ export function PURTab() {
               ) : (
                 <button
                   onClick={handleRun}
-                  className="flex items-center gap-2 px-4 py-2 bg-green-500 text-white rounded hover:bg-green-600"
+                  disabled={viewerMode}
+                  title={viewerMode ? VIEWER_MODE_TITLE : undefined}
+                  className={clsx(
+                    'flex items-center gap-2 px-4 py-2 text-white rounded',
+                    viewerMode ? 'bg-gray-400 cursor-not-allowed' : 'bg-green-500 hover:bg-green-600'
+                  )}
                 >
                   <PlayIcon className="w-5 h-5" />
                   Start Pipeline
@@ -1197,7 +1456,13 @@ export function PURTab() {
             </div>
           )}
 
-          <JobsTable jobs={jobRows} />
+          {contentsPanel}
+          <JobsTable
+            jobs={jobRows}
+            priorities={scannedJobs.map((j) => j.priority || 0)}
+            onPriorityChange={setJobPriority}
+            onShowContents={handleShowContents}
+          />
 
           <PipelineSettings config={config} updateConfig={updateConfig} saveConfig={saveConfig} />
         </div>
@@ -1236,6 +1501,7 @@ export function PURTab() {
                 {doneJobs} of {totalJobs} complete
                 {failedJobs > 0 && ` (${failedJobs} failed)`}
                 {' '}&middot; Elapsed: {elapsedStr}
+                {runData && networkSummary(runData)}
               </p>
             </div>
             <div className="flex items-center gap-2">
@@ -1269,6 +1535,8 @@ export function PURTab() {
             </div>
           </div>
 
+          {(!activeRun || activeRun.status === 'active') && <BlackoutBanner until={blackoutUntil} />}
+
           {/* Progress bar */}
           <div className="mb-4">
             <div className="flex justify-between text-sm mb-1">
@@ -1343,7 +1611,16 @@ export function PURTab() {
             <PipelineLogPanel logs={logs} maxHeight={300} />
           )}
 
-          <div className="flex justify-center mt-6">
+          <div className="flex justify-center gap-2 mt-6">
+            {runData?.runId && (
+              <button
+                onClick={() => { App.OpenRunReport(runData.runId).catch((err) => console.error('Failed to open run report:', err)) }}
+                className="flex items-center gap-2 px-4 py-2 border border-gray-300 dark:border-gray-600 rounded hover:bg-gray-100 dark:hover:bg-gray-700"
+              >
+                <DocumentArrowDownIcon className="w-5 h-5" />
+                Open Report
+              </button>
+            )}
             <button
               onClick={handleStartOver}
               className="flex items-center gap-2 px-4 py-2 bg-blue-500 text-white rounded hover:bg-blue-600"
@@ -1415,6 +1692,7 @@ export function PURTab() {
               {doneJobs} of {totalJobs} complete
               {activeRun.failedJobs > 0 && ` (${activeRun.failedJobs} failed)`}
               {' '}&middot; Elapsed: {elapsedStr}
+              {networkSummary(activeRun)}
             </p>
           </div>
           <div className="flex items-center gap-2">
@@ -1447,6 +1725,8 @@ export function PURTab() {
           </div>
         </div>
 
+        {activeRun.status === 'active' && <BlackoutBanner until={blackoutUntil} />}
+
         {/* Progress bar */}
         <div className="mb-4">
           <div className="flex justify-between text-sm mb-1">
@@ -1468,7 +1748,10 @@ export function PURTab() {
 
         <PipelineStageSummary stats={activeRun.pipelineStageStats} total={totalJobs} />
         <StatsBar jobs={activeRun.jobRows} />
-        <JobsTable jobs={activeRun.jobRows} />
+        {contentsPanel}
+        {liveFilesPanel}
+        <ExportTableButton jobs={activeRun.jobRows} />
+        <JobsTable jobs={activeRun.jobRows} onStopJob={handleStopJob} onShowContents={handleShowContents} onShowLiveFiles={setLiveFilesJob} />
         <PipelineLogPanel logs={activeRun.pipelineLogs} />
       </div>
     )
@@ -1502,7 +1785,9 @@ export function PURTab() {
         </div>
 
         <ErrorSummary jobs={activeRun.jobRows} />
-        <JobsTable jobs={activeRun.jobRows} />
+        {liveFilesPanel}
+        <ExportTableButton jobs={activeRun.jobRows} />
+        <JobsTable jobs={activeRun.jobRows} onStopJob={handleStopJob} onShowLiveFiles={setLiveFilesJob} />
 
         {activeRun.pipelineLogs.length > 0 && (
           <PipelineLogPanel logs={activeRun.pipelineLogs} maxHeight={300} />
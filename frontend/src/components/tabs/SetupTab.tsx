@@ -1,5 +1,5 @@
 import { useEffect, useState, useCallback, useRef } from 'react';
-import { useConfigStore } from '../../stores';
+import { useConfigStore, VIEWER_MODE_TITLE } from '../../stores';
 import {
   CheckCircleIcon,
   XCircleIcon,
@@ -41,14 +41,31 @@ import {
   ValidateAutoDownloadPreFlight,
   OpenLogsDirectory,
   InstallAndStartServiceElevated,
+  GetSendToMenu,
+  SetSendToMenu,
 } from '../../../wailsjs/go/wailsapp/App';
 import { wailsapp } from '../../../wailsjs/go/models';
+import { CacheUsagePanel, NetworkTestPanel, SelfUpdatePanel } from '../widgets';
 import {
   CodeNoAPIKey,
   CodeTransientTimeout,
 } from '../../lib/errors';
 
-const PROXY_MODES = ['no-proxy', 'system', 'ntlm', 'basic'] as const;
+const PROXY_MODES = ['no-proxy', 'system', 'ntlm', 'negotiate', 'basic'] as const;
+
+// Event types webhooks can be sent for (internal/notify DefaultEvents)
+const WEBHOOK_EVENTS = [
+  { value: 'state_change', label: 'Job state changes' },
+  { value: 'error', label: 'Errors' },
+  { value: 'complete', label: 'Run completed' },
+] as const;
+
+// Slack/Teams message types and their config fields (internal/notify ChatEvents)
+const CHAT_EVENTS = [
+  { value: 'run_started', label: 'Run started', template: 'chatTemplateRunStarted', placeholder: 'PUR run {{.RunID}} started on {{.Host}} with {{.TotalJobs}} job(s)' },
+  { value: 'run_completed', label: 'Run completed', template: 'chatTemplateRunCompleted', placeholder: 'PUR run {{.RunID}} on {{.Host}} finished in {{.Duration}}: {{.SuccessJobs}} of {{.TotalJobs}} job(s) succeeded' },
+  { value: 'job_failed', label: 'Job failed', template: 'chatTemplateJobFailed', placeholder: 'PUR job {{.JobName}} failed at {{.Stage}} in run {{.RunID}}: {{.Error}}' },
+] as const;
 
 // Check if URL is a FedRAMP platform (requires FIPS compliance).
 // NTLM proxy mode uses non-FIPS algorithms (MD4/MD5) and must be disabled for these platforms.
@@ -91,6 +108,7 @@ export function SetupTab() {
     isLoading,
     isSaving,
     appInfo,
+    viewerMode,
     fetchConfig,
     fetchAppInfo,
     updateConfig,
@@ -124,6 +142,8 @@ export function SetupTab() {
   const [fileLoggingEnabled, setFileLoggingEnabled] = useState(false);
   const [logFilePath, setLogFilePath] = useState('');
 
+  const [sendToMenu, setSendToMenuState] = useState<{ supported: boolean; installed: boolean; label: string } | null>(null);
+
   // pendingStartTime / pendingElapsed were replaced by the shared
   // service.Computer, which flips userState to 'error' after the 10s
   // transient-pending timeout. The frontend just renders whatever userState
@@ -301,6 +321,12 @@ export function SetupTab() {
     fetchFileLoggingSettings();
   }, []);
 
+  useEffect(() => {
+    GetSendToMenu()
+      .then(setSendToMenuState)
+      .catch((err) => console.error('Failed to fetch Send to menu status:', err));
+  }, []);
+
   // NTLM can be unavailable either by build policy or by selected platform policy.
   useEffect(() => {
     if (!config || config.proxyMode !== 'ntlm') {
@@ -401,6 +427,28 @@ export function SetupTab() {
     }
   };
 
+  const handleSelectDownloadDir = async () => {
+    try {
+      const path = await SelectDirectory('Select Default Download Folder');
+      if (path) {
+        updateConfig({ downloadDir: path });
+      }
+    } catch (err) {
+      console.error('Failed to select folder:', err);
+    }
+  };
+
+  const handleSelectStateDir = async () => {
+    try {
+      const path = await SelectDirectory('Select Run State Folder');
+      if (path) {
+        updateConfig({ stateDir: path });
+      }
+    } catch (err) {
+      console.error('Failed to select folder:', err);
+    }
+  };
+
   const handleTestAutoDownloadConnection = async () => {
     setAutoDownloadTestStatus('testing');
     setAutoDownloadTestResult(null);
@@ -744,6 +792,16 @@ export function SetupTab() {
     }
   };
 
+  const handleToggleSendToMenu = async (enabled: boolean) => {
+    try {
+      await SetSendToMenu(enabled);
+      setSendToMenuState(await GetSendToMenu());
+      setStatusMessage(enabled ? 'Send to Rescale context menu added' : 'Send to Rescale context menu removed');
+    } catch (err) {
+      setStatusMessage(`Failed to update context menu: ${err}`);
+    }
+  };
+
   const getConnectionStatusIcon = () => {
     switch (connectionStatus) {
       case 'testing':
@@ -788,6 +846,9 @@ export function SetupTab() {
 
   const proxyEnabled = config?.proxyMode !== 'no-proxy' && config?.proxyMode !== 'system';
   const basicAuthEnabled = config?.proxyMode === 'basic';
+  // NTLM and Negotiate fall back to the signed-in user (SSPI/Kerberos) when no credentials are set
+  const connectionAuth = config?.proxyMode === 'ntlm' || config?.proxyMode === 'negotiate';
+  const credentialsEnabled = basicAuthEnabled || connectionAuth;
   const isFRM = isFRMPlatform(config?.apiBaseUrl || '');
   const ntlmProxySupported = appInfo?.ntlmProxySupported ?? true;
   const ntlmUnavailable = !ntlmProxySupported || isFRM;
@@ -1003,6 +1064,575 @@ export function SetupTab() {
             <p className="text-xs text-gray-500">
               When enabled, all activity logs are also saved to a rotating log file for troubleshooting.
             </p>
+
+            <div className="flex items-center">
+              <input
+                type="checkbox"
+                id="secureDelete"
+                checked={config?.secureDelete || false}
+                onChange={(e) => updateConfig({ secureDelete: e.target.checked })}
+                className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+              />
+              <label htmlFor="secureDelete" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                Securely delete temporary files
+              </label>
+            </div>
+            <p className="text-xs text-gray-500">
+              Overwrites staging tars and .encrypted transfer files before deleting them. Effective on hard
+              disks; SSDs, copy-on-write filesystems (APFS, Btrfs, ZFS) and network shares may keep the old
+              data, so use full-disk encryption there. Run <code>rescale-int config test</code> for details.
+            </p>
+
+            <div>
+              <label htmlFor="otelEndpoint" className="label">Trace Collector (OTLP)</label>
+              <input
+                type="text"
+                id="otelEndpoint"
+                className="input font-mono"
+                value={config?.otelEndpoint || ''}
+                onChange={(e) => updateConfig({ otelEndpoint: e.target.value })}
+                placeholder="http://localhost:4318"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Exports OpenTelemetry traces of job runs, transfers and API calls to this OTLP/HTTP collector, to see
+                where time goes in a slow submission or upload. Leave empty to turn tracing off.
+              </p>
+            </div>
+
+            <div>
+              <label htmlFor="webhookUrls" className="label">Webhook URLs</label>
+              <input
+                type="text"
+                id="webhookUrls"
+                className="input font-mono"
+                value={config?.webhookUrls || ''}
+                onChange={(e) => updateConfig({ webhookUrls: e.target.value })}
+                placeholder="https://ci.example.com/hooks/interlink (separate several with ;)"
+              />
+              <div className="flex items-center gap-4 mt-2">
+                {WEBHOOK_EVENTS.map(({ value, label }) => {
+                  const selected = (config?.webhookEvents || '').split(';').map((s) => s.trim()).filter(Boolean)
+                  // No selection means every event
+                  const checked = selected.length === 0 || selected.includes(value)
+                  return (
+                    <label key={value} className="flex items-center text-sm text-gray-700 cursor-pointer">
+                      <input
+                        type="checkbox"
+                        checked={checked}
+                        onChange={(e) => {
+                          const current = selected.length === 0 ? WEBHOOK_EVENTS.map((ev) => ev.value) : selected
+                          const next = e.target.checked
+                            ? [...current, value]
+                            : current.filter((ev) => ev !== value)
+                          if (next.length === 0) return // Clear the URLs to turn webhooks off
+                          updateConfig({ webhookEvents: next.length === WEBHOOK_EVENTS.length ? '' : next.join(';') })
+                        }}
+                        disabled={!config?.webhookUrls}
+                        className="h-4 w-4 mr-2 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+                      />
+                      {label}
+                    </label>
+                  )
+                })}
+              </div>
+              <input
+                type="password"
+                id="webhookSecret"
+                className="input font-mono mt-2"
+                value={config?.webhookSecret || ''}
+                onChange={(e) => updateConfig({ webhookSecret: e.target.value })}
+                placeholder="Signing secret (optional)"
+                disabled={!config?.webhookUrls}
+                autoComplete="off"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                POSTs each PUR job state change, error and run completion as JSON (the same records as
+                <code> --events</code>) so CI systems can react without polling. With a secret, requests carry an
+                <code> X-Interlink-Signature</code> HMAC-SHA256 header.
+              </p>
+            </div>
+
+            <div>
+              <label htmlFor="slackWebhookUrl" className="label">Slack / Teams Notifications</label>
+              <input
+                type="password"
+                id="slackWebhookUrl"
+                className="input font-mono"
+                value={config?.slackWebhookUrl || ''}
+                onChange={(e) => updateConfig({ slackWebhookUrl: e.target.value })}
+                placeholder="Slack incoming webhook URL"
+                autoComplete="off"
+              />
+              <input
+                type="password"
+                id="teamsWebhookUrl"
+                className="input font-mono mt-2"
+                value={config?.teamsWebhookUrl || ''}
+                onChange={(e) => updateConfig({ teamsWebhookUrl: e.target.value })}
+                placeholder="Microsoft Teams incoming webhook URL"
+                autoComplete="off"
+              />
+              {(() => {
+                const chatEnabled = !!(config?.slackWebhookUrl || config?.teamsWebhookUrl)
+                const selected = (config?.chatEvents || '').split(';').map((s) => s.trim()).filter(Boolean)
+                return CHAT_EVENTS.map(({ value, label, template, placeholder }) => {
+                  // No selection means every message type
+                  const checked = selected.length === 0 || selected.includes(value)
+                  return (
+                    <div key={value} className="mt-2">
+                      <label className="flex items-center text-sm text-gray-700 cursor-pointer">
+                        <input
+                          type="checkbox"
+                          checked={checked}
+                          onChange={(e) => {
+                            const current = selected.length === 0 ? CHAT_EVENTS.map((ev) => ev.value) : selected
+                            const next = e.target.checked
+                              ? [...current, value]
+                              : current.filter((ev) => ev !== value)
+                            if (next.length === 0) return // Clear the URLs to turn chat messages off
+                            updateConfig({ chatEvents: next.length === CHAT_EVENTS.length ? '' : next.join(';') })
+                          }}
+                          disabled={!chatEnabled}
+                          className="h-4 w-4 mr-2 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+                        />
+                        {label}
+                      </label>
+                      <input
+                        type="text"
+                        className="input font-mono text-xs mt-1"
+                        value={config?.[template] || ''}
+                        onChange={(e) => updateConfig({ [template]: e.target.value })}
+                        placeholder={placeholder}
+                        disabled={!chatEnabled || !checked}
+                        aria-label={`${label} message template`}
+                      />
+                    </div>
+                  )
+                })
+              })()}
+              <p className="text-xs text-gray-500 mt-1">
+                Posts a message to Slack and/or Teams when a PUR run starts, finishes, or a job fails. Messages are Go
+                templates with fields such as <code>{'{{.RunID}}'}</code>, <code>{'{{.Host}}'}</code>,
+                <code> {'{{.JobName}}'}</code> and <code>{'{{.Error}}'}</code>; leave a template empty for the default text.
+              </p>
+            </div>
+
+            <div>
+              <label htmlFor="smtpHost" className="label">Run Summary Email</label>
+              <div className="flex gap-2">
+                <input
+                  type="text"
+                  id="smtpHost"
+                  className="input font-mono flex-1"
+                  value={config?.smtpHost || ''}
+                  onChange={(e) => updateConfig({ smtpHost: e.target.value })}
+                  placeholder="SMTP server, e.g. smtp.example.com"
+                />
+                <input
+                  type="number"
+                  className="input font-mono w-24"
+                  value={config?.smtpPort || ''}
+                  onChange={(e) => updateConfig({ smtpPort: parseInt(e.target.value) || 0 })}
+                  placeholder="587"
+                  min={0}
+                  max={65535}
+                  aria-label="SMTP port"
+                />
+              </div>
+              <div className="flex gap-2 mt-2">
+                <input
+                  type="text"
+                  className="input font-mono flex-1"
+                  value={config?.smtpUsername || ''}
+                  onChange={(e) => updateConfig({ smtpUsername: e.target.value })}
+                  placeholder="Username (optional)"
+                  disabled={!config?.smtpHost}
+                  autoComplete="off"
+                  aria-label="SMTP username"
+                />
+                <input
+                  type="password"
+                  className="input font-mono flex-1"
+                  value={config?.smtpPassword || ''}
+                  onChange={(e) => updateConfig({ smtpPassword: e.target.value })}
+                  placeholder="Password"
+                  disabled={!config?.smtpHost || !config?.smtpUsername}
+                  autoComplete="off"
+                  aria-label="SMTP password"
+                />
+              </div>
+              <input
+                type="text"
+                className="input font-mono mt-2"
+                value={config?.emailFrom || ''}
+                onChange={(e) => updateConfig({ emailFrom: e.target.value })}
+                placeholder="From address (default: username)"
+                disabled={!config?.smtpHost}
+                aria-label="Email sender"
+              />
+              <input
+                type="text"
+                className="input font-mono mt-2"
+                value={config?.emailTo || ''}
+                onChange={(e) => updateConfig({ emailTo: e.target.value })}
+                placeholder="Recipients, e.g. ops@example.com; lead@example.com"
+                disabled={!config?.smtpHost}
+                aria-label="Email recipients"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Emails a summary when a PUR run finishes: jobs submitted, failed jobs with their errors, duration and
+                the state file. Port 465 uses TLS from the start; other ports upgrade with STARTTLS when the server
+                offers it.
+              </p>
+            </div>
+          </div>
+        </div>
+
+        {/* Job Defaults Section */}
+        <div className="card">
+          <h3 className="text-base font-semibold text-gray-900 mb-4">Job Defaults</h3>
+          <div className="space-y-4">
+            <div>
+              <label className="label">Default Tags</label>
+              <input
+                type="text"
+                className="input"
+                value={config?.defaultTags || ''}
+                onChange={(e) => updateConfig({ defaultTags: e.target.value })}
+                placeholder="interlink-{version}; run-{run_id}"
+              />
+            </div>
+            <p className="text-xs text-gray-500">
+              Semicolon-separated tags added to every job Interlink creates, in addition to each job's own tags.
+              Use {'{version}'} for the Interlink version and {'{run_id}'} for the run ID.
+            </p>
+            <div>
+              <label className="label">Job Spec Safety Checks</label>
+              <select
+                className="input"
+                value={config?.jobValidationMode || 'permissive'}
+                onChange={(e) => updateConfig({ jobValidationMode: e.target.value })}
+              >
+                <option value="permissive">Permissive (warn about suspicious commands)</option>
+                <option value="strict">Strict (block suspicious commands)</option>
+              </select>
+            </div>
+            <p className="text-xs text-gray-500">
+              Commands, names and tar subpaths are checked before submission. Tar subpaths that leave the run
+              folder always block the job; unbalanced quotes, control characters, line breaks and command
+              substitution are warnings in permissive mode and errors in strict mode.
+            </p>
+            <div>
+              <label className="label">Duplicate Job Names</label>
+              <select
+                className="input"
+                value={config?.jobNamePolicy || 'warn'}
+                onChange={(e) => updateConfig({ jobNamePolicy: e.target.value })}
+              >
+                <option value="warn">Warn (run anyway)</option>
+                <option value="suffix">Suffix (rename duplicates with a timestamp)</option>
+                <option value="block">Block (refuse to run)</option>
+              </select>
+            </div>
+            <p className="text-xs text-gray-500">
+              Names repeated within a run, or already used by your jobs from the last 30 days, are handled
+              when a run starts. Suffix keeps the first copy and renames the rest.
+            </p>
+            <div>
+              <label className="label">Duplicate Runs</label>
+              <select
+                className="input"
+                value={config?.duplicateRunPolicy || 'warn'}
+                onChange={(e) => updateConfig({ duplicateRunPolicy: e.target.value })}
+              >
+                <option value="warn">Warn (run anyway)</option>
+                <option value="block">Block (refuse to run)</option>
+                <option value="off">Off</option>
+              </select>
+            </div>
+            <p className="text-xs text-gray-500">
+              A run whose jobs and input files match one started in the last 14 days is reported with the
+              earlier run's ID and status.
+            </p>
+            <div>
+              <label className="label">Job Metadata</label>
+              <select
+                className="input"
+                value={config?.jobMetadataTarget || 'description'}
+                onChange={(e) => updateConfig({ jobMetadataTarget: e.target.value })}
+              >
+                <option value="description">Job description</option>
+                <option value="custom_fields">Custom fields</option>
+                <option value="both">Description and custom fields</option>
+              </select>
+            </div>
+            <p className="text-xs text-gray-500">
+              Where per-job metadata (meta_* CSV columns or template metadata) is written when a job is created.
+              Custom fields must already be defined in your workspace; unknown fields are logged and skipped.
+            </p>
+            <div>
+              <label className="label">Send to Rescale Folder</label>
+              <input
+                type="text"
+                className="input"
+                value={config?.sendToFolder || ''}
+                onChange={(e) => updateConfig({ sendToFolder: e.target.value })}
+                placeholder="My Library"
+              />
+            </div>
+            {sendToMenu?.supported && (
+              <div className="flex items-center">
+                <input
+                  type="checkbox"
+                  id="sendToMenu"
+                  checked={sendToMenu.installed}
+                  onChange={(e) => handleToggleSendToMenu(e.target.checked)}
+                  className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+                />
+                <label htmlFor="sendToMenu" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                  Show &quot;{sendToMenu.label}&quot; in the file manager context menu
+                </label>
+              </div>
+            )}
+            <p className="text-xs text-gray-500">
+              Files sent from the context menu are uploaded into this folder path under My Library (created if
+              missing) while Interlink is running. On macOS, assign a keyboard shortcut to the Quick Action under
+              System Settings &gt; Keyboard &gt; Keyboard Shortcuts &gt; Services.
+            </p>
+            <div className="flex items-center">
+              <input
+                type="checkbox"
+                id="uploadReadbackVerify"
+                checked={config?.uploadReadbackVerify || false}
+                onChange={(e) => updateConfig({ uploadReadbackVerify: e.target.checked })}
+                className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+              />
+              <label htmlFor="uploadReadbackVerify" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                Verify uploads by reading back from storage
+              </label>
+            </div>
+            <p className="text-xs text-gray-500">
+              After each upload, reads back the last 1 MB block and three random blocks of the encrypted object and
+              checks them against hashes kept during upload. An upload that fails the check is reported as failed and
+              not registered. Costs a few extra reads per file.
+            </p>
+            <div>
+              <label htmlFor="cpuBudgetPercent" className="label">
+                Encryption CPU Budget: {config?.cpuBudgetPercent || 100}% of cores
+              </label>
+              <input
+                type="range"
+                id="cpuBudgetPercent"
+                min={10}
+                max={100}
+                step={10}
+                value={config?.cpuBudgetPercent || 100}
+                onChange={(e) => updateConfig({ cpuBudgetPercent: Number(e.target.value) })}
+                className="w-full cursor-pointer"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Limits how many files are encrypted at once during uploads. Lower it to keep the machine responsive
+                during large uploads; transfers may take longer.
+              </p>
+            </div>
+            <div className="flex items-center">
+              <input
+                type="checkbox"
+                id="cpuReduceOnBattery"
+                checked={config?.cpuReduceOnBattery ?? true}
+                onChange={(e) => updateConfig({ cpuReduceOnBattery: e.target.checked })}
+                className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+              />
+              <label htmlFor="cpuReduceOnBattery" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                Halve the budget when running on battery
+              </label>
+            </div>
+            <div>
+              <label htmlFor="blackoutWindows" className="label">Transfer Blackout Windows</label>
+              <input
+                type="text"
+                id="blackoutWindows"
+                className="input"
+                value={config?.blackoutWindows || ''}
+                onChange={(e) => updateConfig({ blackoutWindows: e.target.value })}
+                placeholder="01:00-03:00; 22:30-23:00"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Daily periods in this computer&apos;s local time (e.g. NAS backups) during which PUR runs start no new
+                tars or uploads. Uploads under way finish the parts already read, then wait; work resumes when the
+                window ends. Windows may run past midnight.
+              </p>
+            </div>
+            <div>
+              <label htmlFor="partRetries" className="label">Upload Part Retries</label>
+              <input
+                type="number"
+                id="partRetries"
+                className="input"
+                min={1}
+                max={50}
+                value={config?.partRetries ?? 10}
+                onChange={(e) => updateConfig({ partRetries: parseInt(e.target.value) || 1 })}
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Attempts per upload part before the file fails; other files in the batch keep going. Parts that storage
+                rejects outright (a permanent error) fail at once without using the budget.
+              </p>
+            </div>
+            <div>
+              <label htmlFor="largeUploadConfirmGb" className="label">Confirm Uploads Larger Than (GB)</label>
+              <input
+                type="number"
+                id="largeUploadConfirmGb"
+                className="input"
+                min={0}
+                value={config?.largeUploadConfirmGb ?? 500}
+                onChange={(e) => updateConfig({ largeUploadConfirmGb: Math.max(0, parseInt(e.target.value) || 0) })}
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Before an upload of more than this total size starts, show its size, estimated duration and the
+                remaining workspace storage, and ask to continue. 0 never asks.
+              </p>
+            </div>
+            <div className="flex items-center">
+              <input
+                type="checkbox"
+                id="adminMode"
+                checked={config?.adminMode || false}
+                onChange={(e) => updateConfig({ adminMode: e.target.checked })}
+                className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+              />
+              <label htmlFor="adminMode" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                Show Team Admin tab (organization admins)
+              </label>
+            </div>
+            {config?.adminMode && (
+              <div>
+                <label className="label">Interlink Job Tag</label>
+                <input
+                  type="text"
+                  className="input"
+                  value={config?.adminJobTag || ''}
+                  onChange={(e) => updateConfig({ adminJobTag: e.target.value })}
+                  placeholder="interlink"
+                />
+              </div>
+            )}
+            <p className="text-xs text-gray-500">
+              Lists team members' jobs carrying this tag (or a tag starting with it and a dash, like interlink-{'{version}'}),
+              with bulk stop/archive and per-member storage use. Requires an API key with organization admin permissions.
+            </p>
+            <div className="flex items-center">
+              <input
+                type="checkbox"
+                id="viewerMode"
+                checked={viewerMode || config?.viewerMode || false}
+                disabled={viewerMode}
+                onChange={(e) => updateConfig({ viewerMode: e.target.checked })}
+                className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+              />
+              <label htmlFor="viewerMode" className="ml-2 text-sm text-gray-700 cursor-pointer">
+                Viewer mode (read-only monitoring station)
+              </label>
+            </div>
+            <p className="text-xs text-gray-500">
+              Runs, jobs and files can be watched and browsed, but nothing is uploaded, downloaded, deleted or submitted.
+              Once saved it can only be turned off by editing viewer_mode in the config file.
+            </p>
+            <div>
+              <label className="label">Run State Folder</label>
+              <div className="flex gap-2">
+                <input
+                  type="text"
+                  className="input flex-1"
+                  value={config?.stateDir || ''}
+                  onChange={(e) => updateConfig({ stateDir: e.target.value })}
+                  placeholder="~/.rescale-int/states"
+                />
+                <button
+                  onClick={handleSelectStateDir}
+                  className="btn-secondary p-2"
+                  title="Browse for folder"
+                >
+                  <FolderOpenIcon className="w-5 h-5" />
+                </button>
+              </div>
+            </div>
+            <p className="text-xs text-gray-500">
+              Where run state is kept for resume and run history. Point it at a shared project drive so
+              teammates can monitor and resume each other's runs. Only one machine drives a run at a time;
+              on a read-only share, runs still work but their progress is not saved.
+            </p>
+            <div>
+              <label className="label">Default Download Folder</label>
+              <div className="flex gap-2">
+                <input
+                  type="text"
+                  className="input flex-1"
+                  value={config?.downloadDir || ''}
+                  onChange={(e) => updateConfig({ downloadDir: e.target.value })}
+                  placeholder="Local browser's current folder"
+                />
+                <button
+                  onClick={handleSelectDownloadDir}
+                  className="btn-secondary p-2"
+                  title="Browse for folder"
+                >
+                  <FolderOpenIcon className="w-5 h-5" />
+                </button>
+              </div>
+            </div>
+            <div>
+              <label className="label">Organize Downloads</label>
+              <select
+                className="input"
+                value={config?.downloadOrganize || 'none'}
+                onChange={(e) => updateConfig({ downloadOrganize: e.target.value })}
+                disabled={!config?.downloadDir}
+              >
+                <option value="none">Directly in the download folder</option>
+                <option value="job">One subfolder per job</option>
+                <option value="date">One subfolder per day</option>
+                <option value="date-job">Per day, then per job</option>
+              </select>
+            </div>
+            <p className="text-xs text-gray-500">
+              When set, File Browser downloads and <code>jobs download</code> without <code>-d</code> save here
+              instead of the local browser's current folder, grouped as chosen above.
+            </p>
+            <div>
+              <label htmlFor="postDownload" className="label">Post-Download Steps</label>
+              <input
+                type="text"
+                id="postDownload"
+                className="input font-mono"
+                value={config?.postDownload || ''}
+                onChange={(e) => updateConfig({ postDownload: e.target.value })}
+                placeholder="untar; checksum; rename:*.log={stem}-{date}{ext}; script:/path/to/script"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                Run on every file the Transfers tab downloads, in order: <code>untar</code> (<code>untar:delete</code>{' '}
+                removes the archive), <code>checksum</code> (writes <code>file.sha256</code>), <code>rename:glob=name</code>{' '}
+                and <code>script:command</code> (given the file path). Each step&apos;s status shows on the transfer; a
+                failing step fails it.
+              </p>
+            </div>
+            <div>
+              <label htmlFor="templateRepo" className="label">Team Template Repository</label>
+              <input
+                type="text"
+                id="templateRepo"
+                className="input font-mono"
+                value={config?.templateRepo || ''}
+                onChange={(e) => updateConfig({ templateRepo: e.target.value })}
+                placeholder="\\fileserver\cae\interlink-templates or https://git.example.com/cae/templates.git#v2"
+              />
+              <p className="text-xs text-gray-500 mt-1">
+                A folder or git URL holding your team&apos;s job templates (<code>templates/*.json</code>) and run recipes
+                (<code>recipes/*</code>). Browse and import them from the Template Builder&apos;s Team Repository section;
+                end a git URL with <code>#tag</code> to pin the whole repository to a version.
+              </p>
+            </div>
           </div>
         </div>
 
@@ -1068,10 +1698,10 @@ export function SetupTab() {
                 <input
                   type="text"
                   className="input"
-                  placeholder="Username (for Basic auth)"
+                  placeholder={connectionAuth ? 'DOMAIN\\user (blank = signed-in user)' : 'Username (for Basic auth)'}
                   value={config?.proxyUser || ''}
                   onChange={(e) => updateConfig({ proxyUser: e.target.value })}
-                  disabled={!basicAuthEnabled}
+                  disabled={!credentialsEnabled}
                 />
               </div>
               <div>
@@ -1079,13 +1709,19 @@ export function SetupTab() {
                 <input
                   type="password"
                   className="input"
-                  placeholder="Password (for Basic auth)"
+                  placeholder={connectionAuth ? 'Password (blank = signed-in user)' : 'Password (for Basic auth)'}
                   value={config?.proxyPassword || ''}
                   onChange={(e) => updateConfig({ proxyPassword: e.target.value })}
-                  disabled={!basicAuthEnabled}
+                  disabled={!credentialsEnabled}
                 />
               </div>
             </div>
+            {connectionAuth && (
+              <p className="text-xs text-gray-500">
+                Leave credentials blank to sign in as the current user: Windows logon (SSPI) for NTLM and
+                Negotiate, or the Kerberos ticket from <code>kinit</code> for Negotiate on macOS/Linux.
+              </p>
+            )}
             {proxyEnabled && (
               <div>
                 <label className="label">No Proxy (bypass list)</label>
@@ -1106,6 +1742,12 @@ export function SetupTab() {
           </div>
         </div>
 
+        <NetworkTestPanel />
+
+        <SelfUpdatePanel />
+
+        <CacheUsagePanel />
+
         <div className="card">
           <h3 className="text-base font-semibold text-gray-900 mb-4">Auto-Download</h3>
           <div className="space-y-4">
@@ -1656,12 +2298,12 @@ export function SetupTab() {
                       )}
                       <button
                         onClick={handleStartDaemon}
-                        disabled={isDaemonLoading || !daemonConfig?.enabled}
+                        disabled={isDaemonLoading || !daemonConfig?.enabled || viewerMode}
                         className={clsx(
                           "btn-primary text-sm",
-                          !daemonConfig?.enabled && "opacity-50 cursor-not-allowed"
+                          (!daemonConfig?.enabled || viewerMode) && "opacity-50 cursor-not-allowed"
                         )}
-                        title={!daemonConfig?.enabled ? 'Enable auto-download settings first' : 'Start auto-download service'}
+                        title={viewerMode ? VIEWER_MODE_TITLE : !daemonConfig?.enabled ? 'Enable auto-download settings first' : 'Start auto-download service'}
                       >
                         {isDaemonLoading ? 'Starting...' : 'Start Service'}
                       </button>
@@ -15,13 +15,14 @@ import {
   XMarkIcon,
   PlusIcon,
   FolderPlusIcon,
+  ForwardIcon,
 } from '@heroicons/react/24/outline'
 import clsx from 'clsx'
-import { useJobStore, useConfigStore } from '../../stores'
+import { useJobStore, useConfigStore, VIEWER_MODE_TITLE } from '../../stores'
 import type { JobSpec } from '../../stores'
 import { useSingleJobStore } from '../../stores/singleJobStore'
 import { useRunStore } from '../../stores/runStore'
-import { TemplateBuilder, RemoteFilePicker, JobsTable } from '../widgets'
+import { TemplateBuilder, RemoteFilePicker, JobsTable, ContinueJobPanel } from '../widgets'
 import * as App from '../../../wailsjs/go/wailsapp/App'
 import { wailsapp } from '../../../wailsjs/go/models'
 
@@ -53,12 +54,13 @@ export function SingleJobTab() {
     saveJobToSGE,
   } = useJobStore()
 
-  const { config, updateConfig, saveConfig } = useConfigStore()
+  const { config, viewerMode, updateConfig, saveConfig } = useConfigStore()
 
   // UX-only gate: prevents double-clicks from stacking native dialog calls.
   // The correctness layer is the Go-side dialogMu in config_bindings.go —
   // missing the gate is safe (user sees a clean "already open" error).
   const [dialogInFlight, setDialogInFlight] = useState(false)
+  const [showContinuePanel, setShowContinuePanel] = useState(false)
 
   const sjStore = useSingleJobStore()
   const {
@@ -160,6 +162,9 @@ export function SingleJobTab() {
           projectId: loadedJob.projectId,
           orgCode: loadedJob.orgCode || '',
           automations: loadedJob.automations || [],
+          envVars: loadedJob.envVars || {},
+          useRescaleLicense: loadedJob.useRescaleLicense || false,
+          clusterId: loadedJob.clusterId || '',
         })
         sjStore.setState('jobConfigured')
       }
@@ -361,6 +366,17 @@ export function SingleJobTab() {
     }
   }, [sjStore])
 
+  // Handle a continuation job: its restart files are already on Rescale, so
+  // it goes straight to submit with them as remote inputs
+  const handleContinueJob = useCallback((continued: JobSpec, restartFileIds: string[]) => {
+    sjStore.setJob({ ...continued, tags: continued.tags || [], automations: continued.automations || [] })
+    // setInputMode clears remote file IDs, so it must come first
+    sjStore.setInputMode('remoteFiles')
+    sjStore.setRemoteFileIds(restartFileIds)
+    sjStore.setState('inputsReady')
+    setShowContinuePanel(false)
+  }, [sjStore])
+
   // Handle job submission — delegates to store's submitJob() or queueJob()
   const handleSubmit = useCallback(async () => {
     if (!sjStore.isInputsValid()) return
@@ -500,6 +516,15 @@ export function SingleJobTab() {
                 </div>
               )}
             </div>
+
+            <button
+              onClick={() => setShowContinuePanel(true)}
+              title="Start a new job from the restart files of a finished job"
+              className="flex items-center gap-2 px-4 py-3 border border-gray-300 dark:border-gray-600 rounded-lg hover:bg-gray-100 dark:hover:bg-gray-700"
+            >
+              <ForwardIcon className="w-5 h-5" />
+              Continue From Job
+            </button>
           </div>
           {error && (
             <div className="mt-4 p-3 bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 rounded text-red-700 dark:text-red-400 text-sm max-w-md">
@@ -947,11 +972,15 @@ export function SingleJobTab() {
             </button>
             <button
               onClick={handleSubmit}
+              disabled={viewerMode}
+              title={viewerMode ? VIEWER_MODE_TITLE : undefined}
               className={clsx(
                 'flex items-center gap-2 px-6 py-2 text-white rounded',
-                isRunActive
-                  ? 'bg-yellow-500 hover:bg-yellow-600'
-                  : 'bg-green-500 hover:bg-green-600'
+                viewerMode
+                  ? 'bg-gray-400 cursor-not-allowed'
+                  : isRunActive
+                    ? 'bg-yellow-500 hover:bg-yellow-600'
+                    : 'bg-green-500 hover:bg-green-600'
               )}
             >
               <PlayIcon className="w-5 h-5" />
@@ -1091,6 +1120,12 @@ export function SingleJobTab() {
         onSelect={handleRemoteFilesSelected}
         title="Select Remote Files for Job Input"
       />
+
+      <ContinueJobPanel
+        isOpen={showContinuePanel}
+        onClose={() => setShowContinuePanel(false)}
+        onContinue={handleContinueJob}
+      />
     </div>
   )
 }
@@ -14,8 +14,9 @@ import {
   ChevronDownIcon,
 } from '@heroicons/react/24/outline'
 import clsx from 'clsx'
-import { useTransferStore, TransferTask, TransferBatch, Enumeration, extractDiskSpaceInfo, formatSpeed, formatETA } from '../../stores'
+import { useTransferStore, TransferTask, TransferBatch, Enumeration, FailedSummary, extractDiskSpaceInfo, formatSpeed, formatETA } from '../../stores'
 import { useTabNavigation } from '../../App'
+import type { wailsapp } from '../../../wailsjs/go/models'
 
 // Format file size (issue #18)
 function formatSize(bytes: number): string {
@@ -49,6 +50,8 @@ function getStatusInfo(state: string): { icon: typeof CheckCircleIcon; color: st
       return { icon: XMarkIcon, color: 'text-gray-500', label: 'Cancelled' }
     case 'paused':
       return { icon: ClockIcon, color: 'text-yellow-500', label: 'Paused' }
+    case 'retry_waiting':
+      return { icon: ClockIcon, color: 'text-amber-500', label: 'Retry pending' }
     default:
       return { icon: ClockIcon, color: 'text-gray-500', label: state }
   }
@@ -199,11 +202,12 @@ interface BatchRowProps {
   onLoadMore: () => void
   onCancelTask: (taskId: string) => void
   onRetryTask: (taskId: string) => void
+  onGiveUpTask: (taskId: string) => void
   onFilterChange: (filter: string) => void
 }
 
 const BatchRow = memo(function BatchRow({
-  batch, isExpanded, expandedTasks, statusFilter, onToggle, onCancel, onRetryFailed, onLoadMore, onCancelTask, onRetryTask, onFilterChange
+  batch, isExpanded, expandedTasks, statusFilter, onToggle, onCancel, onRetryFailed, onLoadMore, onCancelTask, onRetryTask, onGiveUpTask, onFilterChange
 }: BatchRowProps) {
   const isActive = batch.queued > 0 || batch.active > 0 || !batch.totalKnown
   const isAllComplete = batch.totalKnown && batch.total > 0 && batch.completed === batch.total
@@ -534,6 +538,7 @@ const BatchRow = memo(function BatchRow({
               task={task}
               onCancel={onCancelTask}
               onRetry={onRetryTask}
+              onGiveUp={onGiveUpTask}
               indent
             />
           ))}
@@ -565,9 +570,53 @@ const BatchRow = memo(function BatchRow({
   )
 })
 
+// Countdown to a scheduled automatic retry, re-rendered once per second
+function RetryCountdown({ nextRetryAt, attempt }: { nextRetryAt?: string; attempt?: number }) {
+  const [now, setNow] = useState(() => Date.now())
+  useEffect(() => {
+    const id = setInterval(() => setNow(Date.now()), 1000)
+    return () => clearInterval(id)
+  }, [])
+
+  const target = nextRetryAt ? Date.parse(nextRetryAt) : NaN
+  const remaining = Number.isFinite(target) ? Math.max(0, Math.ceil((target - now) / 1000)) : 0
+  const mins = Math.floor(remaining / 60)
+  const secs = remaining % 60
+  const label = remaining > 0
+    ? `Retry ${attempt ?? 1} in ${mins > 0 ? `${mins}m ` : ''}${String(secs).padStart(mins > 0 ? 2 : 1, '0')}s`
+    : 'Retrying...'
+  return <>{label}</>
+}
+
+// Post-download processing steps, one chip per step; the detail is in the tooltip
+const stepStateClasses: Record<string, string> = {
+  pending: 'bg-gray-100 text-gray-500 dark:bg-gray-800 dark:text-gray-400',
+  running: 'bg-blue-100 text-blue-700 dark:bg-blue-900/30 dark:text-blue-400',
+  done: 'bg-green-100 text-green-700 dark:bg-green-900/30 dark:text-green-400',
+  skipped: 'bg-gray-100 text-gray-400 line-through dark:bg-gray-800 dark:text-gray-500',
+  failed: 'bg-red-100 text-red-700 dark:bg-red-900/30 dark:text-red-400',
+}
+
+function PostProcessSteps({ steps }: { steps: wailsapp.TransferStepDTO[] }) {
+  return (
+    <div className="flex flex-wrap gap-1 mt-1">
+      {steps.map((step, i) => (
+        <span
+          key={i}
+          title={`${step.step}: ${step.state}${step.detail ? ` (${step.detail})` : ''}`}
+          className={clsx('text-[10px] font-medium px-1.5 py-0.5 rounded max-w-[12rem] truncate', stepStateClasses[step.state])}
+        >
+          {step.step.split(':')[0]}
+        </span>
+      ))}
+    </div>
+  )
+}
+
 function getShortErrorLabel(task: TransferTask): string {
   if (!task.error) return ''
   if (task.errorType === 'disk_space') return 'No disk space'
+  if (task.errorType === 'permanent') return 'Rejected by storage'
   return task.error
 }
 
@@ -597,18 +646,71 @@ function DiskSpaceBanner({ incident, onDismiss }: {
   )
 }
 
+// Compact list of failed files across all batches, with one retry for all of
+// them. A failed file never stops the rest of its batch.
+function FailureSummaryBanner({ summary, onRetryAll }: {
+  summary: FailedSummary
+  onRetryAll: () => void
+}) {
+  const [expanded, setExpanded] = useState(false)
+  const permanent = summary.tasks.filter(t => t.errorType === 'permanent').length
+
+  return (
+    <div className="px-4 py-3 bg-red-50 dark:bg-red-900/20 border-b border-red-200 dark:border-red-800">
+      <div className="flex items-center gap-3">
+        <ExclamationCircleIcon className="w-5 h-5 text-red-600 flex-shrink-0" />
+        <button
+          onClick={() => setExpanded(!expanded)}
+          className="flex-1 flex items-center gap-1 text-left text-sm text-red-800 dark:text-red-300"
+        >
+          {expanded ? <ChevronDownIcon className="w-4 h-4" /> : <ChevronRightIcon className="w-4 h-4" />}
+          <span className="font-medium">
+            {summary.total} transfer{summary.total !== 1 ? 's' : ''} failed;
+          </span>
+          <span>the rest continued.</span>
+          {permanent > 0 && (
+            <span className="text-red-600">({permanent} rejected by storage, likely to fail again)</span>
+          )}
+        </button>
+        <button
+          onClick={onRetryAll}
+          className="flex items-center gap-1 px-3 py-1 text-sm text-blue-600 border border-blue-300 dark:border-blue-700 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20 flex-shrink-0"
+        >
+          <ArrowPathIcon className="w-4 h-4" />
+          Retry failed ({summary.total})
+        </button>
+      </div>
+      {expanded && (
+        <ul className="mt-2 ml-8 space-y-0.5 text-xs text-red-800 dark:text-red-300">
+          {summary.tasks.map(task => (
+            <li key={task.id} className="flex gap-2 min-w-0">
+              <span className="font-medium flex-shrink-0 max-w-[40%] truncate" title={task.name}>{task.name}</span>
+              <span className="truncate" title={task.error}>{task.error}</span>
+            </li>
+          ))}
+          {summary.total > summary.tasks.length && (
+            <li className="text-red-600">and {summary.total - summary.tasks.length} more</li>
+          )}
+        </ul>
+      )}
+    </div>
+  )
+}
+
 interface TransferRowProps {
   task: TransferTask
   onCancel: (taskId: string) => void
   onRetry: (taskId: string) => void
+  onGiveUp: (taskId: string) => void
   indent?: boolean
 }
 
-const TransferRow = memo(function TransferRow({ task, onCancel, onRetry, indent }: TransferRowProps) {
+const TransferRow = memo(function TransferRow({ task, onCancel, onRetry, onGiveUp, indent }: TransferRowProps) {
   const statusInfo = getStatusInfo(task.state)
   const StatusIcon = statusInfo.icon
   const isActive = ['queued', 'initializing', 'active', 'paused'].includes(task.state)
   const canRetry = ['failed', 'cancelled'].includes(task.state)
+  const isRetryWaiting = task.state === 'retry_waiting'
 
   // Truncate name if too long
   const displayName = task.name.length > 30 ? task.name.slice(0, 27) + '...' : task.name
@@ -654,6 +756,7 @@ const TransferRow = memo(function TransferRow({ task, onCancel, onRetry, indent
               task.state === 'completed' ? 'bg-green-500' :
               task.state === 'failed' ? 'bg-red-500' :
               task.state === 'cancelled' ? 'bg-gray-400' :
+              isRetryWaiting ? 'bg-amber-400' :
               'bg-blue-500'
             )}
             style={{ width: `${task.displayProgress * 100}%` }}
@@ -664,13 +767,16 @@ const TransferRow = memo(function TransferRow({ task, onCancel, onRetry, indent
           {task.speedFormatted && <span>{task.speedFormatted}</span>}
           {task.etaFormatted && <span>ETA: {task.etaFormatted}</span>}
         </div>
+        {task.steps && task.steps.length > 0 && <PostProcessSteps steps={task.steps} />}
       </div>
 
       {/* Status */}
       <div className={clsx('flex items-center gap-1 flex-shrink-0 w-32 text-sm', statusInfo.color)}>
         <StatusIcon className={clsx('w-4 h-4 flex-shrink-0', task.state === 'active' && 'animate-spin')} />
         <span className="truncate" title={task.error || statusInfo.label}>
-          {task.error ? getShortErrorLabel(task) : statusInfo.label}
+          {isRetryWaiting
+            ? <RetryCountdown nextRetryAt={task.nextRetryAt} attempt={task.retryAttempts} />
+            : task.error ? getShortErrorLabel(task) : statusInfo.label}
         </span>
       </div>
 
@@ -694,6 +800,26 @@ const TransferRow = memo(function TransferRow({ task, onCancel, onRetry, indent
             Retry
           </button>
         )}
+        {isRetryWaiting && (
+          <div className="flex flex-col">
+            <button
+              onClick={() => onRetry(task.id)}
+              title="Retry now"
+              className="flex items-center gap-1 px-2 py-0.5 text-xs text-blue-600 hover:bg-blue-100 dark:hover:bg-blue-900/30 rounded"
+            >
+              <ArrowPathIcon className="w-4 h-4" />
+              Now
+            </button>
+            <button
+              onClick={() => onGiveUp(task.id)}
+              title="Stop retrying and mark as failed"
+              className="flex items-center gap-1 px-2 py-0.5 text-xs text-red-600 hover:bg-red-100 dark:hover:bg-red-900/30 rounded"
+            >
+              <XMarkIcon className="w-4 h-4" />
+              Give up
+            </button>
+          </div>
+        )}
       </div>
     </div>
   )
@@ -703,19 +829,23 @@ export function TransfersTab() {
   const {
     tasks,
     stats,
+    failedSummary,
     enumerations,
     batches,
     expandedBatches,
     batchTasks,
     batchStatusFilter,
     folderCheckStatus,
+    networkOffline,
     startPolling,
     stopPolling,
     cancelTransfer,
     cancelAllTransfers,
     cancelBatch,
     retryTransfer,
+    giveUpTransfer,
     retryFailedInBatch,
+    retryAllFailed,
     clearCompletedTransfers,
     toggleBatchExpanded,
     fetchBatchTasks,
@@ -789,6 +919,11 @@ export function TransfersTab() {
     retryTransfer(taskId)
   }, [retryTransfer])
 
+  // Handle give up on a pending automatic retry
+  const handleGiveUp = useCallback((taskId: string) => {
+    giveUpTransfer(taskId)
+  }, [giveUpTransfer])
+
   // Handle cancel all
   const handleCancelAll = useCallback(() => {
     if (stats.totalActive > 0) {
@@ -850,10 +985,25 @@ export function TransfersTab() {
         </div>
       </div>
 
+      {networkOffline && (
+        <div className="flex items-center gap-3 px-4 py-3 bg-amber-50 dark:bg-amber-900/20 border-b border-amber-200 dark:border-amber-800">
+          <ClockIcon className="w-5 h-5 text-amber-600 flex-shrink-0" />
+          <div className="flex-1 text-sm text-amber-800 dark:text-amber-300">
+            <span className="font-medium">Network connection lost.</span>
+            {' '}
+            <span>Transfers are paused and will resume automatically when the network is back.</span>
+          </div>
+        </div>
+      )}
+
       {diskSpaceIncident && !diskSpaceBannerDismissed && (
         <DiskSpaceBanner incident={diskSpaceIncident} onDismiss={() => setDiskSpaceBannerDismissed(true)} />
       )}
 
+      {failedSummary.total > 0 && (
+        <FailureSummaryBanner summary={failedSummary} onRetryAll={retryAllFailed} />
+      )}
+
       {/* Transfer list */}
       <div className="flex-1 overflow-auto">
         {isEmpty ? (
@@ -902,6 +1052,7 @@ export function TransfersTab() {
                 }}
                 onCancelTask={handleCancel}
                 onRetryTask={handleRetry}
+                onGiveUpTask={handleGiveUp}
                 onFilterChange={(filter) => setBatchStatusFilter(batch.batchID, filter)}
               />
             ))}
@@ -916,6 +1067,7 @@ export function TransfersTab() {
                 task={task}
                 onCancel={handleCancel}
                 onRetry={handleRetry}
+                onGiveUp={handleGiveUp}
               />
             ))}
           </div>
@@ -5,3 +5,4 @@ export { FileBrowserTab } from './FileBrowserTab';
 export { TransfersTab } from './TransfersTab';
 export { SingleJobTab } from './SingleJobTab';
 export { PURTab } from './PURTab';
+export { AdminTab } from './AdminTab';
@@ -0,0 +1,33 @@
+// Countdown shown while a blackout window (blackout_windows) pauses tar and upload work.
+import { useEffect, useState } from 'react'
+import { ClockIcon } from '@heroicons/react/24/outline'
+import { formatDuration } from '../../utils/formatDuration'
+
+interface BlackoutBannerProps {
+  until: string | null // RFC3339 end of the window
+}
+
+export function BlackoutBanner({ until }: BlackoutBannerProps) {
+  const end = until ? Date.parse(until) : NaN
+  const [now, setNow] = useState(() => Date.now())
+  useEffect(() => {
+    if (isNaN(end)) return
+    const id = setInterval(() => setNow(Date.now()), 1000)
+    return () => clearInterval(id)
+  }, [end])
+
+  if (isNaN(end) || end <= now) return null
+  const remaining = Math.ceil((end - now) / 1000)
+  const endTime = new Date(end).toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })
+
+  return (
+    <div className="flex items-center gap-3 mb-4 px-4 py-3 bg-amber-50 dark:bg-amber-900/20 border border-amber-200 dark:border-amber-800 rounded">
+      <ClockIcon className="w-5 h-5 text-amber-600 flex-shrink-0" />
+      <div className="flex-1 text-sm text-amber-800 dark:text-amber-300">
+        <span className="font-medium">Blackout window until {endTime}.</span>
+        {' '}
+        <span>New tar and upload work is paused and resumes in {formatDuration(remaining)}; uploads already under way finish their current parts.</span>
+      </div>
+    </div>
+  )
+}
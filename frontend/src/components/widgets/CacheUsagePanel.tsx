@@ -0,0 +1,141 @@
+// Settings panel for Interlink's on-disk caches: usage per category against
+// the configured budget, the budget itself, and a clear button. Least
+// recently used files are evicted after each run once a limit is set.
+import { useCallback, useEffect, useState } from 'react'
+import { ArrowPathIcon, TrashIcon, XCircleIcon } from '@heroicons/react/24/outline'
+import { useConfigStore } from '../../stores'
+import { ClearCache, GetCacheStats } from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+import { formatSize } from './FileList'
+
+const CATEGORY_LABELS: Record<string, string> = {
+  tars: 'Staged tars',
+  resume: 'Resume files',
+}
+
+const formatLimit = (bytes: number) => (bytes > 0 ? formatSize(bytes) : 'unlimited')
+
+export function CacheUsagePanel() {
+  const { config, updateConfig } = useConfigStore()
+  const [stats, setStats] = useState<wailsapp.CacheStatsDTO | null>(null)
+  const [busy, setBusy] = useState<'refresh' | 'clear' | null>(null)
+  const [message, setMessage] = useState('')
+
+  const refresh = useCallback(async () => {
+    setBusy('refresh')
+    try {
+      setStats(await GetCacheStats())
+    } catch (err) {
+      setStats({ categories: [], bytes: 0, limit: 0, error: String(err) } as wailsapp.CacheStatsDTO)
+    } finally {
+      setBusy(null)
+    }
+  }, [])
+
+  useEffect(() => {
+    refresh()
+  }, [refresh])
+
+  const handleClear = async () => {
+    setBusy('clear')
+    try {
+      const res = await ClearCache([])
+      setMessage(res.error
+        ? `Removed ${res.removed} file(s); some could not be removed: ${res.error}`
+        : `Removed ${res.removed} file(s), freed ${formatSize(res.freed)}`)
+      setStats(await GetCacheStats())
+    } catch (err) {
+      setMessage(`Failed to clear cache: ${err}`)
+    } finally {
+      setBusy(null)
+    }
+  }
+
+  return (
+    <div className="card">
+      <h3 className="text-base font-semibold text-gray-900 mb-4">Disk Cache</h3>
+      <div className="space-y-4">
+        <p className="text-xs text-gray-500">
+          Staged tars and transfer resume files can be recreated on demand. Set a budget to evict the least
+          recently used ones after each run; files in use by a running job or transfer are kept.
+          Cleared tars are rebuilt if their run is resumed.
+        </p>
+
+        <div className="grid grid-cols-2 gap-4">
+          <div>
+            <label className="label">Total Budget (MB)</label>
+            <input
+              type="number"
+              min={0}
+              className="input"
+              value={config?.cacheMaxMb || 0}
+              onChange={(e) => updateConfig({ cacheMaxMb: Math.max(0, parseInt(e.target.value, 10) || 0) })}
+            />
+          </div>
+          <div>
+            <label className="label">Per-Category Limits</label>
+            <input
+              type="text"
+              className="input"
+              value={config?.cacheLimits || ''}
+              onChange={(e) => updateConfig({ cacheLimits: e.target.value })}
+              placeholder="tars=20480,resume=512"
+            />
+          </div>
+        </div>
+        <p className="text-xs text-gray-500">0 means unlimited. Save settings to apply a new budget.</p>
+
+        {stats && stats.categories.length > 0 && (
+          <table className="w-full text-sm">
+            <thead>
+              <tr className="text-left text-xs text-gray-500">
+                <th className="font-medium pb-1">Category</th>
+                <th className="font-medium pb-1 text-right">Files</th>
+                <th className="font-medium pb-1 text-right">Size</th>
+                <th className="font-medium pb-1 text-right">In Use</th>
+                <th className="font-medium pb-1 text-right">Limit</th>
+              </tr>
+            </thead>
+            <tbody>
+              {stats.categories.map((c) => (
+                <tr key={c.category} className="text-gray-700">
+                  <td>{CATEGORY_LABELS[c.category] || c.category}</td>
+                  <td className="text-right">{c.entries}</td>
+                  <td className="text-right">{formatSize(c.bytes)}</td>
+                  <td className="text-right">{formatSize(c.inUseBytes)}</td>
+                  <td className="text-right">{formatLimit(c.limit)}</td>
+                </tr>
+              ))}
+              <tr className="font-medium text-gray-900 border-t border-gray-200">
+                <td className="pt-1">Total</td>
+                <td />
+                <td className="pt-1 text-right">{formatSize(stats.bytes)}</td>
+                <td />
+                <td className="pt-1 text-right">{formatLimit(stats.limit)}</td>
+              </tr>
+            </tbody>
+          </table>
+        )}
+
+        {stats?.error && (
+          <div className="flex items-center gap-2 p-3 rounded-md bg-red-50 text-red-700 text-sm">
+            <XCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>{stats.error}</span>
+          </div>
+        )}
+
+        <div className="flex items-center gap-4">
+          <button onClick={refresh} disabled={busy !== null} className="btn-secondary flex items-center">
+            <ArrowPathIcon className={`w-4 h-4 mr-2 ${busy === 'refresh' ? 'animate-spin' : ''}`} />
+            Refresh
+          </button>
+          <button onClick={handleClear} disabled={busy !== null} className="btn-secondary flex items-center">
+            <TrashIcon className="w-4 h-4 mr-2" />
+            {busy === 'clear' ? 'Clearing...' : 'Clear Cache'}
+          </button>
+          {message && <span className="text-sm text-gray-600">{message}</span>}
+        </div>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1,266 @@
+// Dialog for continuing a finished Rescale job from its restart files: load
+// the job, pick restart files from its outputs, and set the continuation
+// command (from a saved template or typed). The files are reused by ID, so
+// nothing is downloaded or uploaded.
+import { useCallback, useEffect, useMemo, useState } from 'react'
+import { ArrowPathIcon, XMarkIcon } from '@heroicons/react/24/outline'
+import * as App from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+import type { JobSpec } from '../../types/jobs'
+import { formatSize } from './FileList'
+
+interface ContinueJobPanelProps {
+  isOpen: boolean
+  onClose: () => void
+  onContinue: (job: JobSpec, restartFileIds: string[]) => void
+}
+
+// globToRegExp converts a "*"/"?" glob into an anchored RegExp.
+const globToRegExp = (glob: string) =>
+  new RegExp('^' + glob.replace(/[.+^${}()|[\]\\]/g, '\\$&').replace(/\*/g, '[^/]*').replace(/\?/g, '[^/]') + '$')
+
+// Patterns with "/" match the path relative to the job's working directory,
+// others the file name (same rules as 'jobs continue --restart-files').
+const matchesAny = (f: wailsapp.ContinuationFileDTO, patterns: string[]) =>
+  patterns.some((p) => globToRegExp(p).test(p.includes('/') ? f.relativePath : f.relativePath.split('/').pop() || f.name))
+
+export function ContinueJobPanel({ isOpen, onClose, onContinue }: ContinueJobPanelProps) {
+  const [jobRef, setJobRef] = useState('')
+  const [source, setSource] = useState<wailsapp.ContinuationSourceDTO | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [error, setError] = useState('')
+  const [selected, setSelected] = useState<Set<string>>(new Set())
+  const [patterns, setPatterns] = useState('')
+  const [templates, setTemplates] = useState<wailsapp.TemplateInfoDTO[]>([])
+  const [command, setCommand] = useState('')
+  const [name, setName] = useState('')
+  const [keepInputs, setKeepInputs] = useState(true)
+
+  useEffect(() => {
+    if (!isOpen) return
+    App.ListSavedTemplates()
+      .then((all) => setTemplates((all || []).filter((t) => t.job?.continuationCommand)))
+      .catch((err) => console.error('Failed to load saved templates:', err))
+  }, [isOpen])
+
+  const handleLoad = useCallback(async () => {
+    setLoading(true)
+    setError('')
+    setSource(null)
+    setSelected(new Set())
+    try {
+      const src = await App.GetContinuationSource(jobRef.trim())
+      setSource(src)
+      setName(src.suggestedName)
+      setCommand(src.job.continuationCommand || src.job.command)
+    } catch (err) {
+      setError(String(err))
+    } finally {
+      setLoading(false)
+    }
+  }, [jobRef])
+
+  const handleSelectMatching = useCallback(() => {
+    if (!source) return
+    const list = patterns.split(',').map((p) => p.trim()).filter(Boolean)
+    setSelected(new Set(source.files.filter((f) => matchesAny(f, list)).map((f) => f.id)))
+  }, [source, patterns])
+
+  const toggle = useCallback((id: string) => {
+    setSelected((prev) => {
+      const next = new Set(prev)
+      if (next.has(id)) next.delete(id)
+      else next.add(id)
+      return next
+    })
+  }, [])
+
+  const selectedSize = useMemo(
+    () => (source?.files || []).filter((f) => selected.has(f.id)).reduce((sum, f) => sum + f.size, 0),
+    [source, selected]
+  )
+
+  const handleContinue = useCallback(async () => {
+    if (!source) return
+    setError('')
+    try {
+      // Keep the output order so restart files stay in the job's own order
+      const ids = source.files.filter((f) => selected.has(f.id)).map((f) => f.id)
+      const spec = await App.BuildContinuationJob({
+        job: source.job,
+        restartFileIds: ids,
+        name,
+        command,
+        keepInputs,
+      } as wailsapp.ContinuationRequestDTO)
+      onContinue(spec as unknown as JobSpec, spec.inputFiles || ids)
+    } catch (err) {
+      setError(String(err))
+    }
+  }, [source, selected, name, command, keepInputs, onContinue])
+
+  if (!isOpen) return null
+
+  const inputCount = source?.job.extraInputFileIds ? source.job.extraInputFileIds.split(',').length : 0
+
+  return (
+    <div className="fixed inset-0 bg-black/50 flex items-center justify-center z-50">
+      <div className="bg-white dark:bg-gray-800 rounded-lg shadow-xl w-[800px] max-w-[90vw] max-h-[90vh] flex flex-col">
+        <div className="flex items-center justify-between px-6 py-4 border-b border-gray-200 dark:border-gray-700">
+          <h2 className="text-lg font-semibold">Continue From Job</h2>
+          <button onClick={onClose} className="p-1 rounded hover:bg-gray-200 dark:hover:bg-gray-700">
+            <XMarkIcon className="w-5 h-5" />
+          </button>
+        </div>
+
+        <div className="flex-1 overflow-y-auto px-6 py-4 space-y-4 text-sm">
+          <div>
+            <label className="block font-medium mb-1">Job ID or URL</label>
+            <div className="flex gap-2">
+              <input
+                type="text"
+                value={jobRef}
+                onChange={(e) => setJobRef(e.target.value)}
+                onKeyDown={(e) => e.key === 'Enter' && jobRef.trim() && handleLoad()}
+                placeholder="AbCdE or https://platform.rescale.com/jobs/AbCdE/"
+                className="flex-1 px-3 py-2 border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              />
+              <button
+                onClick={handleLoad}
+                disabled={loading || !jobRef.trim()}
+                className="flex items-center gap-2 px-4 py-2 bg-blue-500 text-white rounded hover:bg-blue-600 disabled:opacity-50"
+              >
+                <ArrowPathIcon className={loading ? 'w-4 h-4 animate-spin' : 'w-4 h-4'} />
+                Load
+              </button>
+            </div>
+          </div>
+
+          {source && (
+            <>
+              <div className="p-3 bg-gray-50 dark:bg-gray-900 rounded">
+                <div className="font-medium">{source.job.jobName} ({source.jobId})</div>
+                <div className="text-xs text-gray-500">
+                  {source.job.analysisCode} {source.job.analysisVersion} · {source.job.coreType} ·{' '}
+                  {source.status || 'unknown status'}
+                </div>
+                {source.warnings.map((w, i) => (
+                  <div key={i} className="text-xs text-yellow-600 mt-1">⚠ {w}</div>
+                ))}
+              </div>
+
+              {!source.continuable ? (
+                <p className="text-red-600">
+                  This job is {source.status || 'in an unknown state'}. Only finished jobs can be continued.
+                </p>
+              ) : (
+                <>
+                  <div>
+                    <label className="block font-medium mb-1">Restart Files</label>
+                    <div className="flex gap-2 mb-2">
+                      <input
+                        type="text"
+                        value={patterns}
+                        onChange={(e) => setPatterns(e.target.value)}
+                        placeholder="*.rst, restart/*"
+                        className="flex-1 px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                      />
+                      <button
+                        onClick={handleSelectMatching}
+                        className="px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded hover:bg-gray-100 dark:hover:bg-gray-700"
+                      >
+                        Select Matching
+                      </button>
+                    </div>
+                    <div className="max-h-56 overflow-y-auto border border-gray-200 dark:border-gray-700 rounded">
+                      {source.files.length === 0 && <div className="p-3 text-gray-500">The job has no output files.</div>}
+                      {source.files.map((f) => (
+                        <label
+                          key={f.id}
+                          className="flex items-center gap-2 px-3 py-1 hover:bg-gray-50 dark:hover:bg-gray-700 cursor-pointer"
+                        >
+                          <input type="checkbox" checked={selected.has(f.id)} onChange={() => toggle(f.id)} />
+                          <span className="flex-1 truncate font-mono text-xs" title={f.relativePath}>{f.relativePath}</span>
+                          <span className="text-xs text-gray-400">{formatSize(f.size)}</span>
+                        </label>
+                      ))}
+                    </div>
+                    <p className="mt-1 text-xs text-gray-500">
+                      {selected.size} selected ({formatSize(selectedSize)}). Files are reused from Rescale storage without
+                      downloading or re-uploading.
+                    </p>
+                  </div>
+
+                  <div>
+                    <div className="flex items-center justify-between mb-1">
+                      <label className="block font-medium">Continuation Command</label>
+                      {templates.length > 0 && (
+                        <select
+                          value=""
+                          onChange={(e) => {
+                            const t = templates.find((t) => t.name === e.target.value)
+                            if (t) setCommand(t.job.continuationCommand || '')
+                          }}
+                          className="px-2 py-1 text-xs border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800"
+                        >
+                          <option value="">From template...</option>
+                          {templates.map((t) => (
+                            <option key={t.name} value={t.name}>{t.name}</option>
+                          ))}
+                        </select>
+                      )}
+                    </div>
+                    <textarea
+                      value={command}
+                      onChange={(e) => setCommand(e.target.value)}
+                      rows={3}
+                      className="w-full px-3 py-2 font-mono border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500 resize-none"
+                    />
+                  </div>
+
+                  <div className="grid grid-cols-2 gap-4 items-end">
+                    <div>
+                      <label className="block font-medium mb-1">New Job Name</label>
+                      <input
+                        type="text"
+                        value={name}
+                        onChange={(e) => setName(e.target.value)}
+                        className="w-full px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                      />
+                    </div>
+                    <label className="flex items-center gap-2 pb-2 cursor-pointer">
+                      <input type="checkbox" checked={keepInputs} onChange={(e) => setKeepInputs(e.target.checked)} />
+                      Also use the original {inputCount} input file{inputCount !== 1 ? 's' : ''}
+                    </label>
+                  </div>
+                </>
+              )}
+            </>
+          )}
+
+          {error && (
+            <div className="p-3 bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 rounded text-red-700 dark:text-red-400">
+              {error}
+            </div>
+          )}
+        </div>
+
+        <div className="flex justify-end gap-2 px-6 py-4 border-t border-gray-200 dark:border-gray-700">
+          <button
+            onClick={onClose}
+            className="px-4 py-2 border border-gray-300 dark:border-gray-600 rounded hover:bg-gray-100 dark:hover:bg-gray-700"
+          >
+            Cancel
+          </button>
+          <button
+            onClick={handleContinue}
+            disabled={!source?.continuable || selected.size === 0}
+            className="px-4 py-2 bg-blue-500 text-white rounded hover:bg-blue-600 disabled:opacity-50"
+          >
+            Continue
+          </button>
+        </div>
+      </div>
+    </div>
+  )
+}
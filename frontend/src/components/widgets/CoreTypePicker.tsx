@@ -0,0 +1,241 @@
+import { useState, useMemo } from 'react'
+import { MagnifyingGlassIcon, StarIcon as StarOutlineIcon } from '@heroicons/react/24/outline'
+import { StarIcon as StarSolidIcon } from '@heroicons/react/24/solid'
+import clsx from 'clsx'
+import type { CoreType } from '../../stores'
+
+// Filter values; they match package hardware's classes.
+const MEMORY_OPTIONS = [2, 4, 8, 16]
+const INTERCONNECT_OPTIONS: [string, string][] = [
+  ['infiniband', 'InfiniBand'],
+  ['efa', 'EFA'],
+  ['ethernet', 'Ethernet'],
+]
+const ARCH_OPTIONS: [string, string][] = [
+  ['x86_64', 'x86_64'],
+  ['arm64', 'ARM64'],
+]
+const PRICE_OPTIONS: [string, string][] = [
+  ['low', 'Low'],
+  ['medium', 'Medium'],
+  ['high', 'High'],
+]
+
+const FILTER_SELECT_CLASS =
+  'px-2 py-1 text-xs border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500'
+
+interface CoreTypePickerProps {
+  coreTypes: CoreType[]
+  favorites: string[]
+  // Core types the selected analysis version runs on; empty = any
+  allowedCoreTypes: string[]
+  value: string
+  onChange: (code: string) => void
+  onToggleFavorite: (code: string) => void
+  placeholder?: string
+  disabled?: boolean
+}
+
+// isCompatible mirrors hardware.Compatible: an empty list allows everything.
+function isCompatible(code: string, allowed: string[]) {
+  return allowed.length === 0 || allowed.some((a) => a.toLowerCase() === code.toLowerCase())
+}
+
+// describe summarizes the reported hardware details of a core type.
+function describe(ct: CoreType) {
+  const parts: string[] = []
+  if (ct.memoryPerCoreGb) parts.push(`${Number(ct.memoryPerCoreGb.toPrecision(3))} GB/core`)
+  if (ct.architecture) parts.push(ct.architecture)
+  if (ct.interconnect) parts.push(ct.interconnect)
+  if (ct.price) parts.push(`$${ct.price.toFixed(3)}/core-h`)
+  return parts.join(' · ')
+}
+
+// Core type dropdown with hardware filters and starred favorites. Entries
+// the selected analysis version runs on come first, favorites first among
+// them, like `rescale-int hardware list`.
+export function CoreTypePicker({
+  coreTypes,
+  favorites,
+  allowedCoreTypes,
+  value,
+  onChange,
+  onToggleFavorite,
+  placeholder = 'Search coretypes...',
+  disabled = false,
+}: CoreTypePickerProps) {
+  const [isOpen, setIsOpen] = useState(false)
+  const [search, setSearch] = useState('')
+  const [minMemory, setMinMemory] = useState(0)
+  const [interconnect, setInterconnect] = useState('')
+  const [architecture, setArchitecture] = useState('')
+  const [priceBand, setPriceBand] = useState('')
+  const [favoritesOnly, setFavoritesOnly] = useState(false)
+
+  const hasPrices = useMemo(() => coreTypes.some((ct) => ct.priceBand), [coreTypes])
+
+  const options = useMemo(() => {
+    const searchLower = search.toLowerCase()
+    const filtered = coreTypes.filter(
+      (ct) =>
+        (!search || ct.code.toLowerCase().includes(searchLower) || ct.name.toLowerCase().includes(searchLower)) &&
+        (!minMemory || (ct.memoryPerCoreGb || 0) >= minMemory) &&
+        (!interconnect || ct.interconnectClass === interconnect) &&
+        (!architecture || ct.architecture === architecture) &&
+        (!priceBand || ct.priceBand === priceBand) &&
+        (!favoritesOnly || favorites.includes(ct.code))
+    )
+    const rank = (ct: CoreType) =>
+      (isCompatible(ct.code, allowedCoreTypes) ? 0 : 2) + (favorites.includes(ct.code) ? 0 : 1)
+    return filtered.sort(
+      (a, b) => rank(a) - rank(b) || a.displayOrder - b.displayOrder || a.code.localeCompare(b.code)
+    )
+  }, [coreTypes, favorites, allowedCoreTypes, search, minMemory, interconnect, architecture, priceBand, favoritesOnly])
+
+  const handleSelect = (code: string) => {
+    onChange(code)
+    setSearch('')
+    setIsOpen(false)
+  }
+
+  return (
+    <div>
+      <div className="relative">
+        <div className="relative">
+          <input
+            type="text"
+            value={isOpen ? search : value}
+            onChange={(e) => {
+              setSearch(e.target.value)
+              setIsOpen(true)
+            }}
+            onFocus={() => setIsOpen(true)}
+            onBlur={() => setTimeout(() => setIsOpen(false), 200)}
+            placeholder={placeholder}
+            disabled={disabled}
+            className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500 disabled:bg-gray-100 dark:disabled:bg-gray-700"
+          />
+          <MagnifyingGlassIcon className="absolute right-3 top-2.5 w-4 h-4 text-gray-400" />
+        </div>
+        {isOpen && options.length > 0 && (
+          <div className="absolute z-10 w-full mt-1 bg-white dark:bg-gray-800 border border-gray-300 dark:border-gray-600 rounded shadow-lg max-h-64 overflow-auto">
+            {options.slice(0, 50).map((ct) => {
+              const starred = favorites.includes(ct.code)
+              const compatible = isCompatible(ct.code, allowedCoreTypes)
+              return (
+                <div
+                  key={ct.code}
+                  className={clsx(
+                    'flex items-start hover:bg-gray-100 dark:hover:bg-gray-700',
+                    !compatible && 'opacity-60'
+                  )}
+                >
+                  <button
+                    type="button"
+                    // Keep focus in the input so the list stays open
+                    onMouseDown={(e) => e.preventDefault()}
+                    onClick={() => onToggleFavorite(ct.code)}
+                    title={starred ? 'Remove from favorites' : 'Add to favorites'}
+                    className="px-2 py-2 text-gray-400 hover:text-yellow-500"
+                  >
+                    {starred ? (
+                      <StarSolidIcon className="w-4 h-4 text-yellow-500" />
+                    ) : (
+                      <StarOutlineIcon className="w-4 h-4" />
+                    )}
+                  </button>
+                  <button type="button" onClick={() => handleSelect(ct.code)} className="flex-1 py-2 pr-3 text-sm text-left">
+                    <span className="font-medium">{ct.code}</span>
+                    {ct.name && ct.name !== ct.code && <span className="text-gray-500"> — {ct.name}</span>}
+                    {(describe(ct) || !compatible) && (
+                      <span className="block text-xs text-gray-500">
+                        {describe(ct)}
+                        {!compatible && (
+                          <span className="text-amber-600">
+                            {describe(ct) ? ' · ' : ''}not available for this version
+                          </span>
+                        )}
+                      </span>
+                    )}
+                  </button>
+                </div>
+              )
+            })}
+            {options.length > 50 && (
+              <div className="px-3 py-2 text-xs text-gray-500">Showing 50 of {options.length} results...</div>
+            )}
+          </div>
+        )}
+      </div>
+      <div className="flex flex-wrap items-center gap-2 mt-2">
+        <select
+          value={minMemory}
+          onChange={(e) => setMinMemory(Number(e.target.value))}
+          disabled={disabled}
+          title="Memory per core"
+          className={FILTER_SELECT_CLASS}
+        >
+          <option value={0}>Any memory</option>
+          {MEMORY_OPTIONS.map((gb) => (
+            <option key={gb} value={gb}>
+              ≥ {gb} GB/core
+            </option>
+          ))}
+        </select>
+        <select
+          value={interconnect}
+          onChange={(e) => setInterconnect(e.target.value)}
+          disabled={disabled}
+          title="Interconnect"
+          className={FILTER_SELECT_CLASS}
+        >
+          <option value="">Any interconnect</option>
+          {INTERCONNECT_OPTIONS.map(([v, label]) => (
+            <option key={v} value={v}>
+              {label}
+            </option>
+          ))}
+        </select>
+        <select
+          value={architecture}
+          onChange={(e) => setArchitecture(e.target.value)}
+          disabled={disabled}
+          title="Architecture"
+          className={FILTER_SELECT_CLASS}
+        >
+          <option value="">Any architecture</option>
+          {ARCH_OPTIONS.map(([v, label]) => (
+            <option key={v} value={v}>
+              {label}
+            </option>
+          ))}
+        </select>
+        {hasPrices && (
+          <select
+            value={priceBand}
+            onChange={(e) => setPriceBand(e.target.value)}
+            disabled={disabled}
+            title="Price band"
+            className={FILTER_SELECT_CLASS}
+          >
+            <option value="">Any price</option>
+            {PRICE_OPTIONS.map(([v, label]) => (
+              <option key={v} value={v}>
+                {label}
+              </option>
+            ))}
+          </select>
+        )}
+        <label className="flex items-center gap-1 text-xs text-gray-600 dark:text-gray-400">
+          <input
+            type="checkbox"
+            checked={favoritesOnly}
+            onChange={(e) => setFavoritesOnly(e.target.checked)}
+            disabled={disabled}
+          />
+          Favorites only
+        </label>
+      </div>
+    </div>
+  )
+}
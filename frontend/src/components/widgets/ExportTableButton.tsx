@@ -0,0 +1,67 @@
+// "Export table" action for the Jobs table: writes the rows as shown,
+// including live platform statuses, to CSV or Excel. The backend adds
+// stage durations and job links from the current run.
+import { useState } from 'react'
+import { ArrowDownTrayIcon } from '@heroicons/react/24/outline'
+import type { JobRow } from '../../types/jobs'
+import { ExportJobsTable } from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+
+const toExportRow = (j: JobRow) =>
+  ({
+    index: j.index,
+    directory: j.directory,
+    jobName: j.jobName,
+    tarStatus: j.tarStatus,
+    uploadStatus: j.uploadStatus,
+    createStatus: j.createStatus,
+    submitStatus: j.submitStatus,
+    status: j.status,
+    jobId: j.jobId,
+    error: j.error,
+    platformStatus: j.platformStatus,
+    subStatus: j.subStatus,
+    subStatusSince: j.subStatusSince,
+  }) as wailsapp.JobExportRowDTO
+
+export function ExportTableButton({ jobs }: { jobs: JobRow[] }) {
+  const [busy, setBusy] = useState(false)
+  const [message, setMessage] = useState('')
+
+  if (jobs.length === 0) return null
+
+  const handleExport = async (format: 'csv' | 'xlsx') => {
+    setBusy(true)
+    setMessage('')
+    try {
+      const path = await ExportJobsTable(jobs.map(toExportRow), format)
+      if (path) setMessage(`Saved to ${path}`)
+    } catch (err) {
+      setMessage(`Export failed: ${err}`)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  return (
+    <div className="flex items-center justify-end gap-2 mt-4 text-sm">
+      {message && <span className="text-xs text-gray-500 truncate">{message}</span>}
+      <ArrowDownTrayIcon className="w-4 h-4 text-gray-500" />
+      <span className="text-gray-600 dark:text-gray-400">Export table:</span>
+      <button
+        onClick={() => handleExport('csv')}
+        disabled={busy}
+        className="px-2 py-1 border border-gray-300 dark:border-gray-600 rounded hover:bg-gray-100 dark:hover:bg-gray-700 disabled:opacity-50"
+      >
+        CSV
+      </button>
+      <button
+        onClick={() => handleExport('xlsx')}
+        disabled={busy}
+        className="px-2 py-1 border border-gray-300 dark:border-gray-600 rounded hover:bg-gray-100 dark:hover:bg-gray-700 disabled:opacity-50"
+      >
+        Excel
+      </button>
+    </div>
+  )
+}
@@ -40,7 +40,7 @@ interface FileListProps {
 
 // Format file size for display.
 // Defensive: handle undefined/NaN values (issue #18).
-function formatSize(bytes: number): string {
+export function formatSize(bytes: number): string {
   if (typeof bytes !== 'number' || !Number.isFinite(bytes)) return '?'
   if (bytes < 0) return '?'
   if (bytes === 0) return '-'
@@ -51,7 +51,7 @@ function formatSize(bytes: number): string {
 }
 
 // Format date for display
-function formatDate(dateStr: string): string {
+export function formatDate(dateStr: string): string {
   if (!dateStr) return '-'
   try {
     const date = new Date(dateStr)
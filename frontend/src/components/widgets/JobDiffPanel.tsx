@@ -0,0 +1,119 @@
+// Compare two jobs files (e.g. study revisions) before submitting. Used by the PUR tab.
+import { useState } from 'react'
+import { ArrowsRightLeftIcon, ExclamationTriangleIcon, XMarkIcon } from '@heroicons/react/24/outline'
+import clsx from 'clsx'
+import * as App from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+
+function baseName(path: string) {
+  return path.split(/[\\/]/).pop() || path
+}
+
+export function JobDiffPanel({ onClose }: { onClose: () => void }) {
+  const [pathA, setPathA] = useState('')
+  const [pathB, setPathB] = useState('')
+  const [result, setResult] = useState<wailsapp.JobDiffDTO | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [riskyOnly, setRiskyOnly] = useState(false)
+
+  const pick = async (setPath: (p: string) => void) => {
+    const path = await App.SelectFile('Select Jobs File (CSV or JSON)')
+    if (path) {
+      setPath(path)
+      setResult(null)
+    }
+  }
+
+  const compare = async () => {
+    setError(null)
+    try {
+      setResult(await App.DiffJobFiles(pathA, pathB))
+    } catch (err) {
+      setResult(null)
+      setError(err instanceof Error ? err.message : String(err))
+    }
+  }
+
+  const changed = (result?.changed || []).filter((c) => !riskyOnly || c.risky)
+
+  return (
+    <div className="w-full max-w-3xl p-4 border border-gray-200 dark:border-gray-700 rounded-lg bg-white dark:bg-gray-800">
+      <div className="flex items-center justify-between mb-3">
+        <h4 className="font-medium flex items-center gap-2">
+          <ArrowsRightLeftIcon className="w-5 h-5 text-blue-500" />
+          Compare Jobs Files
+        </h4>
+        <button onClick={onClose} className="text-gray-400 hover:text-gray-600" title="Close">
+          <XMarkIcon className="w-5 h-5" />
+        </button>
+      </div>
+
+      <div className="flex items-center gap-2 text-sm">
+        <button onClick={() => pick(setPathA)} className="btn-secondary px-3 py-1 truncate max-w-[14rem]" title={pathA}>
+          {pathA ? baseName(pathA) : 'Original…'}
+        </button>
+        <span className="text-gray-400">→</span>
+        <button onClick={() => pick(setPathB)} className="btn-secondary px-3 py-1 truncate max-w-[14rem]" title={pathB}>
+          {pathB ? baseName(pathB) : 'Revised…'}
+        </button>
+        <button
+          onClick={compare}
+          disabled={!pathA || !pathB}
+          className="ml-auto px-3 py-1 bg-blue-500 text-white rounded hover:bg-blue-600 disabled:opacity-50 disabled:cursor-not-allowed"
+        >
+          Compare
+        </button>
+      </div>
+
+      {error && <p className="mt-3 text-sm text-red-500">{error}</p>}
+
+      {result && (
+        <div className="mt-4 text-sm">
+          <div className="flex items-center gap-4 mb-2 text-xs">
+            <span className="text-green-600">{result.added.length} added</span>
+            <span className="text-red-600">{result.removed.length} removed</span>
+            <span>{result.changed.length} changed</span>
+            {result.riskyCount > 0 && (
+              <span className="text-amber-600">{result.riskyCount} with risky changes</span>
+            )}
+            <span className="text-gray-500">{result.unchanged} unchanged</span>
+            <label className="ml-auto flex items-center gap-1 cursor-pointer">
+              <input type="checkbox" checked={riskyOnly} onChange={(e) => setRiskyOnly(e.target.checked)} />
+              Risky only
+            </label>
+          </div>
+
+          <div className="max-h-80 overflow-auto space-y-1 font-mono text-xs">
+            {!riskyOnly && result.added.map((k) => (
+              <div key={`+${k}`} className="text-green-600">+ {k}</div>
+            ))}
+            {!riskyOnly && result.removed.map((k) => (
+              <div key={`-${k}`} className="text-red-600">- {k}</div>
+            ))}
+            {changed.map((c) => (
+              <div key={`~${c.key}`}>
+                <div className={clsx(c.risky && 'text-amber-600')}>~ {c.key}</div>
+                {c.changes.map((fc) => (
+                  <div key={fc.field} className="ml-4 flex items-center gap-1">
+                    <span className="text-gray-500">{fc.field}:</span>
+                    <span className="line-through text-gray-400">{fc.old || '(empty)'}</span>
+                    <span>→ {fc.new || '(empty)'}</span>
+                    {fc.risk && (
+                      <span className="flex items-center gap-0.5 text-amber-600">
+                        <ExclamationTriangleIcon className="w-3 h-3" />
+                        {fc.risk}
+                      </span>
+                    )}
+                  </div>
+                ))}
+              </div>
+            ))}
+            {result.added.length + result.removed.length + changed.length === 0 && (
+              <p className="text-gray-500 font-sans">No differences.</p>
+            )}
+          </div>
+        </div>
+      )}
+    </div>
+  )
+}
@@ -1,9 +1,93 @@
 // Reusable jobs table for RunMonitorView, CompletedResultsView, ActivityTab.
+import { useEffect, useState } from 'react'
 import clsx from 'clsx'
 import type { JobRow } from '../../types/jobs'
+import { formatDuration } from '../../utils/formatDuration'
 import { StatusBadge } from './StatusBadge'
 
-export function JobsTable({ jobs }: { jobs: JobRow[] }) {
+interface JobsTableProps {
+  jobs: JobRow[]
+  // When provided, a Priority column is shown with an editable value per row
+  // (indexed by JobRow.index). Higher values are processed first.
+  priorities?: number[]
+  onPriorityChange?: (index: number, priority: number) => void
+  // When provided, a Stop action is shown for jobs that exist on the platform
+  onStopJob?: (job: JobRow) => void
+  // When provided, a Files action lists the job's input tar contents
+  onShowContents?: (job: JobRow) => void
+  // When provided, a Live action opens the work directory of executing jobs
+  onShowLiveFiles?: (job: JobRow) => void
+}
+
+// Platform statuses after which a job can no longer be stopped
+const TERMINAL_PLATFORM_STATUSES = ['Completed', 'Stopping', 'Stopped', 'Terminated', 'Failed']
+
+// Platform status while a job's cluster runs and its work directory exists
+const EXECUTING_STATUS = 'Executing'
+
+// Elapsed time in a queue/provisioning sub-status, e.g. "Provisioning cluster · 3m 12s"
+function subStatusText(job: JobRow, now: number): string {
+  if (!job.subStatus) return ''
+  const since = job.subStatusSince ? Date.parse(job.subStatusSince) : NaN
+  if (isNaN(since)) return job.subStatus
+  return `${job.subStatus} · ${formatDuration(Math.max(0, Math.floor((now - since) / 1000)))}`
+}
+
+// Stage statuses that count as done in an array's aggregate row
+const DONE_STATUSES = ['success', 'completed', 'skipped']
+
+// Display entry: a job row, or the collapsed row of an array's members
+type TableEntry =
+  | { kind: 'job'; job: JobRow }
+  | { kind: 'array'; name: string; members: JobRow[] }
+
+// Groups array members under one entry at the position of the first member
+function tableEntries(jobs: JobRow[]): TableEntry[] {
+  const entries: TableEntry[] = []
+  const arrays = new Map<string, JobRow[]>()
+  for (const job of jobs) {
+    if (!job.arrayName) {
+      entries.push({ kind: 'job', job })
+      continue
+    }
+    let members = arrays.get(job.arrayName)
+    if (!members) {
+      members = []
+      arrays.set(job.arrayName, members)
+      entries.push({ kind: 'array', name: job.arrayName, members })
+    }
+    members.push(job)
+  }
+  return entries
+}
+
+// "done/total" for one stage of an array's members
+function stageCount(members: JobRow[], status: (j: JobRow) => string): string {
+  return `${members.filter((j) => DONE_STATUSES.includes(status(j))).length}/${members.length}`
+}
+
+export function JobsTable({ jobs, priorities, onPriorityChange, onStopJob, onShowContents, onShowLiveFiles }: JobsTableProps) {
+  const showPriority = !!priorities && !!onPriorityChange
+
+  // Tick once a second while any job is waiting to execute so elapsed times advance
+  const [now, setNow] = useState(() => Date.now())
+  const waiting = jobs.some((j) => j.subStatus)
+  useEffect(() => {
+    if (!waiting) return
+    const id = setInterval(() => setNow(Date.now()), 1000)
+    return () => clearInterval(id)
+  }, [waiting])
+
+  // Arrays whose member rows are shown
+  const [expanded, setExpanded] = useState<Set<string>>(() => new Set())
+  const toggleArray = (name: string) =>
+    setExpanded((prev) => {
+      const next = new Set(prev)
+      if (next.has(name)) next.delete(name)
+      else next.add(name)
+      return next
+    })
+
   if (jobs.length === 0) {
     return (
       <div className="text-center text-gray-500 py-8">
@@ -12,6 +96,150 @@ export function JobsTable({ jobs }: { jobs: JobRow[] }) {
     )
   }
 
+  const renderRow = (job: JobRow, member = false) => (
+    <tr
+      key={job.index}
+      className={clsx(
+        'hover:bg-gray-50 dark:hover:bg-gray-800/50',
+        member && 'bg-gray-50/50 dark:bg-gray-800/20',
+        job.error && 'bg-red-50 dark:bg-red-900/10'
+      )}
+    >
+      <td className="px-4 py-2 text-gray-600">{job.index + 1}</td>
+      <td className="px-4 py-2 font-mono text-xs truncate max-w-48" title={job.directory}>
+        {onShowContents && (
+          <button
+            onClick={() => onShowContents(job)}
+            className="mr-2 px-1.5 py-0.5 font-sans text-xs text-blue-600 border border-blue-300 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20"
+            title="List the files in this job's input tar"
+          >
+            Files
+          </button>
+        )}
+        {job.directory}
+      </td>
+      <td className={clsx('py-2', member ? 'pl-10 pr-4' : 'px-4')}>
+        {job.jobName}
+        {job.warnings && job.warnings.length > 0 && (
+          <span
+            className="ml-2 px-1.5 py-0.5 text-xs rounded bg-yellow-100 text-yellow-800 dark:bg-yellow-900/30 dark:text-yellow-300"
+            title={job.warnings.join('\n')}
+          >
+            &#9888; {job.warnings.length}
+          </span>
+        )}
+      </td>
+      {showPriority && (
+        <td className="px-4 py-2 text-center">
+          <input
+            type="number"
+            step={1}
+            value={priorities?.[job.index] ?? 0}
+            onChange={(e) => onPriorityChange?.(job.index, parseInt(e.target.value, 10) || 0)}
+            className="w-16 px-1 py-0.5 text-center border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800"
+          />
+        </td>
+      )}
+      <td className="px-4 py-2 text-center">
+        {(job.tarStatus === 'running' || job.tarStatus === 'in_progress') && job.tarProgress !== undefined ? (
+          <span
+            className="px-2 py-0.5 text-xs rounded-full font-medium bg-blue-200 text-blue-700"
+            title={job.tarProgressDetail}
+          >
+            {job.tarProgress.toFixed(1)}%
+          </span>
+        ) : (
+          <StatusBadge status={job.tarStatus} />
+        )}
+      </td>
+      <td className="px-4 py-2 text-center">
+        {(job.uploadStatus === 'running' || job.uploadStatus === 'in_progress') && job.uploadProgress > 0 ? (
+          <span className="px-2 py-0.5 text-xs rounded-full font-medium bg-blue-200 text-blue-700">
+            {job.uploadProgress.toFixed(1)}%
+          </span>
+        ) : (
+          <StatusBadge status={job.uploadStatus} />
+        )}
+      </td>
+      <td className="px-4 py-2 text-center">
+        <StatusBadge status={job.createStatus} />
+      </td>
+      <td className="px-4 py-2 text-center">
+        <StatusBadge status={job.submitStatus} />
+      </td>
+      <td className="px-4 py-2 font-mono text-xs text-gray-500">
+        {job.jobId || '-'}
+        {onStopJob && job.jobId && !TERMINAL_PLATFORM_STATUSES.includes(job.platformStatus || '') && (
+          <button
+            onClick={() => onStopJob(job)}
+            className="ml-2 px-1.5 py-0.5 font-sans text-xs text-red-600 border border-red-300 rounded hover:bg-red-50 dark:hover:bg-red-900/20"
+            title="Stop this job on Rescale"
+          >
+            Stop
+          </button>
+        )}
+        {onShowLiveFiles && job.jobId && job.platformStatus === EXECUTING_STATUS && (
+          <button
+            onClick={() => onShowLiveFiles(job)}
+            className="ml-2 px-1.5 py-0.5 font-sans text-xs text-green-700 border border-green-300 rounded hover:bg-green-50 dark:hover:bg-green-900/20"
+            title="Browse this job's work directory while it runs"
+          >
+            Live
+          </button>
+        )}
+      </td>
+      <td className="px-4 py-2 text-xs text-gray-600 dark:text-gray-400" title={job.subStatusReason || ''}>
+        {job.platformStatus || '-'}
+        {job.subStatus && (
+          <div className="text-blue-600 dark:text-blue-400">{subStatusText(job, now)}</div>
+        )}
+      </td>
+      <td className="px-4 py-2 text-xs max-w-48 truncate" title={job.error || ''}>
+        {job.error ? (
+          <span className="text-red-600">{job.error}</span>
+        ) : '-'}
+      </td>
+    </tr>
+  )
+
+  const renderArray = (name: string, members: JobRow[]) => {
+    const open = expanded.has(name)
+    const failed = members.filter((j) => j.error || j.submitStatus === 'failed').length
+    const submitted = members.filter((j) => j.submitStatus === 'success' || j.submitStatus === 'completed').length
+    return (
+      <tr
+        key={`array:${name}`}
+        className={clsx(
+          'bg-gray-100 hover:bg-gray-200 dark:bg-gray-800 dark:hover:bg-gray-700 cursor-pointer',
+          failed > 0 && 'bg-red-50 dark:bg-red-900/10'
+        )}
+        onClick={() => toggleArray(name)}
+      >
+        <td className="px-4 py-2 text-gray-600">
+          {members[0].index + 1}&ndash;{members[members.length - 1].index + 1}
+        </td>
+        <td className="px-4 py-2 text-xs text-gray-500">-</td>
+        <td className="px-4 py-2 font-medium">
+          <span className="inline-block w-4">{open ? '\u25BE' : '\u25B8'}</span>
+          {name}
+          <span className="ml-2 text-xs text-gray-500">array, {members.length} jobs</span>
+        </td>
+        {showPriority && <td />}
+        <td className="px-4 py-2 text-center text-xs">{stageCount(members, (j) => j.tarStatus)}</td>
+        <td className="px-4 py-2 text-center text-xs">{stageCount(members, (j) => j.uploadStatus)}</td>
+        <td className="px-4 py-2 text-center text-xs">{stageCount(members, (j) => j.createStatus)}</td>
+        <td className="px-4 py-2 text-center text-xs">{stageCount(members, (j) => j.submitStatus)}</td>
+        <td className="px-4 py-2 text-xs text-gray-500">-</td>
+        <td className="px-4 py-2 text-xs text-gray-600 dark:text-gray-400">
+          {submitted}/{members.length} submitted
+        </td>
+        <td className="px-4 py-2 text-xs">
+          {failed > 0 ? <span className="text-red-600">{failed} failed</span> : '-'}
+        </td>
+      </tr>
+    )
+  }
+
   return (
     <div className="overflow-auto max-h-96">
       <table className="w-full text-sm">
@@ -26,6 +254,11 @@ export function JobsTable({ jobs }: { jobs: JobRow[] }) {
             <th className="px-4 py-2 text-left font-medium text-gray-700 dark:text-gray-300">
               Job Name
             </th>
+            {showPriority && (
+              <th className="px-4 py-2 text-center font-medium text-gray-700 dark:text-gray-300" title="Higher priority jobs are tarred, uploaded and submitted first">
+                Priority
+              </th>
+            )}
             <th className="px-4 py-2 text-center font-medium text-gray-700 dark:text-gray-300">
               Tar
             </th>
@@ -41,53 +274,23 @@ export function JobsTable({ jobs }: { jobs: JobRow[] }) {
             <th className="px-4 py-2 text-left font-medium text-gray-700 dark:text-gray-300">
               Job ID
             </th>
+            <th className="px-4 py-2 text-left font-medium text-gray-700 dark:text-gray-300">
+              Status
+            </th>
             <th className="px-4 py-2 text-left font-medium text-gray-700 dark:text-gray-300">
               Error
             </th>
           </tr>
         </thead>
         <tbody className="divide-y divide-gray-200 dark:divide-gray-700">
-          {jobs.map((job) => (
-            <tr
-              key={job.index}
-              className={clsx(
-                'hover:bg-gray-50 dark:hover:bg-gray-800/50',
-                job.error && 'bg-red-50 dark:bg-red-900/10'
-              )}
-            >
-              <td className="px-4 py-2 text-gray-600">{job.index + 1}</td>
-              <td className="px-4 py-2 font-mono text-xs truncate max-w-48" title={job.directory}>
-                {job.directory}
-              </td>
-              <td className="px-4 py-2">{job.jobName}</td>
-              <td className="px-4 py-2 text-center">
-                <StatusBadge status={job.tarStatus} />
-              </td>
-              <td className="px-4 py-2 text-center">
-                {(job.uploadStatus === 'running' || job.uploadStatus === 'in_progress') && job.uploadProgress > 0 ? (
-                  <span className="px-2 py-0.5 text-xs rounded-full font-medium bg-blue-200 text-blue-700">
-                    {job.uploadProgress.toFixed(1)}%
-                  </span>
-                ) : (
-                  <StatusBadge status={job.uploadStatus} />
-                )}
-              </td>
-              <td className="px-4 py-2 text-center">
-                <StatusBadge status={job.createStatus} />
-              </td>
-              <td className="px-4 py-2 text-center">
-                <StatusBadge status={job.submitStatus} />
-              </td>
-              <td className="px-4 py-2 font-mono text-xs text-gray-500">
-                {job.jobId || '-'}
-              </td>
-              <td className="px-4 py-2 text-xs max-w-48 truncate" title={job.error || ''}>
-                {job.error ? (
-                  <span className="text-red-600">{job.error}</span>
-                ) : '-'}
-              </td>
-            </tr>
-          ))}
+          {tableEntries(jobs).map((entry) =>
+            entry.kind === 'job'
+              ? renderRow(entry.job)
+              : [
+                  renderArray(entry.name, entry.members),
+                  ...(expanded.has(entry.name) ? entry.members.map((m) => renderRow(m, true)) : []),
+                ]
+          )}
         </tbody>
       </table>
     </div>
@@ -0,0 +1,216 @@
+// Browses a running job's work directory: download intermediate files and
+// upload small correction files mid-run. Used by the PUR tab's run monitor
+// and results tables, keyed by job ID so each job starts at its work directory.
+import { useCallback, useEffect, useState } from 'react'
+import {
+  ArrowPathIcon,
+  ArrowUpTrayIcon,
+  ExclamationTriangleIcon,
+  FolderIcon,
+  ServerStackIcon,
+  XMarkIcon,
+} from '@heroicons/react/24/outline'
+import * as App from '../../../wailsjs/go/wailsapp/App'
+import { useConfigStore } from '../../stores'
+import { formatSize } from './FileList'
+
+interface LiveFile {
+  path: string
+  name: string
+  isDirectory: boolean
+  size: number
+  dateModified: string
+}
+
+interface LiveFiles {
+  jobId: string
+  status: string
+  running: boolean
+  supported: boolean
+  path: string
+  entries: LiveFile[]
+  maxUploadSize: number
+}
+
+interface LiveFilesPanelProps {
+  jobId: string
+  jobName: string
+  onClose: () => void
+}
+
+function errorText(err: unknown): string {
+  return err instanceof Error ? err.message : String(err)
+}
+
+export function LiveFilesPanel({ jobId, jobName, onClose }: LiveFilesPanelProps) {
+  const viewerMode = useConfigStore((s) => s.viewerMode)
+  const [dir, setDir] = useState('')
+  const [result, setResult] = useState<LiveFiles | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [notice, setNotice] = useState<string | null>(null)
+  const [busy, setBusy] = useState(false)
+
+  const load = useCallback(async (path: string) => {
+    setLoading(true)
+    setError(null)
+    try {
+      setResult((await App.GetLiveFiles(jobId, path)) as unknown as LiveFiles)
+    } catch (err) {
+      setError(errorText(err))
+    } finally {
+      setLoading(false)
+    }
+  }, [jobId])
+
+  useEffect(() => {
+    setNotice(null)
+    load(dir)
+  }, [dir, load])
+
+  const handleDownload = async (file: LiveFile) => {
+    setBusy(true)
+    setNotice(null)
+    try {
+      const saved = await App.DownloadLiveFile(jobId, file.path)
+      if (saved) setNotice(`Saved ${file.name} to ${saved}`)
+    } catch (err) {
+      setError(`Failed to download ${file.name}: ${errorText(err)}`)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  const handleUpload = async () => {
+    const localPath = await App.SelectFile('Select File to Upload to the Running Job')
+    if (!localPath) return
+    const name = localPath.split(/[\\/]/).pop() || localPath
+    const exists = result?.entries.some((e) => !e.isDirectory && e.name === name)
+    if (exists && !confirm(`${name} already exists in ${dir || 'the work directory'}.\n\nReplace it in the running job?`)) return
+
+    setBusy(true)
+    setNotice(null)
+    try {
+      const uploaded = await App.UploadLiveFile(jobId, localPath, dir ? `${dir}/` : '', !!exists)
+      setNotice(`Uploaded ${name} to ${uploaded.path}`)
+      await load(dir)
+    } catch (err) {
+      setError(`Failed to upload ${name}: ${errorText(err)}`)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  const crumbs = dir ? dir.split('/') : []
+  const available = result?.running && result.supported
+
+  return (
+    <div className="mb-4 p-4 border border-gray-200 dark:border-gray-700 rounded-lg bg-white dark:bg-gray-800">
+      <div className="flex items-center justify-between mb-3">
+        <h4 className="font-medium flex items-center gap-2">
+          <ServerStackIcon className="w-5 h-5 text-green-500" />
+          Live files: {jobName}
+          <span className="text-xs font-normal text-gray-500 font-mono">{jobId}</span>
+          {result?.status && <span className="text-xs font-normal text-gray-500">· {result.status}</span>}
+        </h4>
+        <div className="flex items-center gap-2">
+          {available && !viewerMode && (
+            <button
+              onClick={handleUpload}
+              disabled={busy}
+              className="flex items-center gap-1 px-2 py-1 text-xs text-blue-600 border border-blue-300 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20 disabled:opacity-50"
+              title={`Upload a small file (up to ${formatSize(result?.maxUploadSize ?? 0)}) into this directory`}
+            >
+              <ArrowUpTrayIcon className="w-4 h-4" />
+              Upload
+            </button>
+          )}
+          <button
+            onClick={() => load(dir)}
+            disabled={loading}
+            className="text-gray-400 hover:text-gray-600 disabled:opacity-50"
+            title="Refresh"
+          >
+            <ArrowPathIcon className={`w-5 h-5 ${loading ? 'animate-spin' : ''}`} />
+          </button>
+          <button onClick={onClose} className="text-gray-400 hover:text-gray-600" title="Close">
+            <XMarkIcon className="w-5 h-5" />
+          </button>
+        </div>
+      </div>
+
+      {error && (
+        <div className="flex items-start gap-2 mb-2 text-sm text-red-600">
+          <ExclamationTriangleIcon className="w-5 h-5 flex-shrink-0" />
+          {error}
+        </div>
+      )}
+      {notice && <div className="mb-2 text-sm text-green-700 dark:text-green-400">{notice}</div>}
+      {!result && !error && <div className="text-sm text-gray-500">Connecting to the job's cluster…</div>}
+
+      {result && !result.running && (
+        <div className="text-sm text-gray-500">
+          The job has no running cluster{result.status ? ` (${result.status})` : ''}. Live files are available while it executes;
+          outputs of finished jobs are in the File Browser.
+        </div>
+      )}
+      {result && result.running && !result.supported && (
+        <div className="text-sm text-yellow-700 dark:text-yellow-400">
+          In-job file access is not available for this job's cluster.
+        </div>
+      )}
+
+      {result && available && (
+        <>
+          <div className="flex flex-wrap items-center gap-1 mb-2 text-xs font-mono">
+            <button onClick={() => setDir('')} className="text-blue-600 hover:underline">work</button>
+            {crumbs.map((c, i) => (
+              <span key={i} className="flex items-center gap-1">
+                <span className="text-gray-400">/</span>
+                <button onClick={() => setDir(crumbs.slice(0, i + 1).join('/'))} className="text-blue-600 hover:underline">
+                  {c}
+                </button>
+              </span>
+            ))}
+          </div>
+          {result.entries.length === 0 ? (
+            <div className="text-sm text-gray-500">This directory is empty.</div>
+          ) : (
+            <div className="overflow-auto max-h-64 text-xs">
+              {result.entries.map((e) => (
+                <div key={e.path} className="flex items-center justify-between gap-4 py-0.5 hover:bg-gray-50 dark:hover:bg-gray-700/50">
+                  {e.isDirectory ? (
+                    <button onClick={() => setDir(e.path)} className="flex items-center gap-1 font-mono text-blue-600 hover:underline truncate" title={e.path}>
+                      <FolderIcon className="w-4 h-4 flex-shrink-0" />
+                      {e.name}/
+                    </button>
+                  ) : (
+                    <span className="font-mono truncate" title={e.path}>{e.name}</span>
+                  )}
+                  <span className="flex items-center gap-3 flex-shrink-0 text-gray-500">
+                    {e.dateModified && <span>{new Date(e.dateModified).toLocaleTimeString()}</span>}
+                    {!e.isDirectory && (
+                      <>
+                        <span className="w-16 text-right">{formatSize(e.size)}</span>
+                        {!viewerMode && (
+                          <button
+                            onClick={() => handleDownload(e)}
+                            disabled={busy}
+                            className="px-1.5 py-0.5 text-blue-600 border border-blue-300 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20 disabled:opacity-50"
+                            title="Download the file's current contents"
+                          >
+                            Download
+                          </button>
+                        )}
+                      </>
+                    )}
+                  </span>
+                </div>
+              ))}
+            </div>
+          )}
+        </>
+      )}
+    </div>
+  )
+}
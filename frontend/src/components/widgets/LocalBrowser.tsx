@@ -6,14 +6,32 @@ import {
   EyeSlashIcon,
   EyeIcon,
   ExclamationTriangleIcon,
+  ComputerDesktopIcon,
+  ServerIcon,
+  FolderIcon,
+  PlusIcon,
+  XMarkIcon,
 } from '@heroicons/react/24/outline'
+import * as App from '../../../wailsjs/go/wailsapp/App'
 import { useFileBrowserStore } from '../../stores'
+import { findContainingRoot } from '../../stores/fileBrowserStore'
 import { FileList } from './FileList'
 
+const ROOT_ICONS: Record<string, typeof HomeIcon> = {
+  home: HomeIcon,
+  drive: ComputerDesktopIcon,
+  mount: ServerIcon,
+  custom: FolderIcon,
+}
+
 export function LocalBrowser() {
   const {
-    local: { currentPath, items, isLoading, error, warning, showHidden, history, selection },
+    local: { currentPath, items, isLoading, error, warning, showHidden, history, selection, roots },
     navigateLocalTo,
+    loadLocalRoots,
+    openLocalRoot,
+    addLocalRoot,
+    removeLocalRoot,
     goLocalBack,
     goLocalHome,
     refreshLocal,
@@ -31,6 +49,22 @@ export function LocalBrowser() {
     }
   }, [currentPath, goLocalHome])
 
+  // Load drive/mount shortcuts once
+  useEffect(() => {
+    loadLocalRoots()
+  }, [loadLocalRoots])
+
+  const activeRoot = findContainingRoot(roots, currentPath)
+
+  const handleAddRoot = useCallback(async () => {
+    try {
+      const dir = await App.SelectDirectory('Add Location')
+      if (dir) await addLocalRoot(dir)
+    } catch (err) {
+      console.error('Failed to add location:', err)
+    }
+  }, [addLocalRoot])
+
   // Sync path input with current path
   useEffect(() => {
     setPathInput(currentPath)
@@ -57,89 +91,132 @@ export function LocalBrowser() {
   }, [setLocalSelection])
 
   return (
-    <div className="flex flex-col h-full">
-      {/* Navigation bar */}
-      <div className="flex items-center gap-2 p-2 border-b border-gray-200 dark:border-gray-700 bg-gray-50 dark:bg-gray-800">
-        {/* Back button */}
+    <div className="flex h-full">
+      {/* Roots sidebar: home, drives, mounts and user-defined locations */}
+      <div className="w-36 flex-shrink-0 overflow-y-auto border-r border-gray-200 dark:border-gray-700 bg-gray-50 dark:bg-gray-800 py-1">
+        {roots.map((root) => {
+          const Icon = ROOT_ICONS[root.kind] || FolderIcon
+          const active = activeRoot?.path === root.path
+          return (
+            <div
+              key={root.path}
+              className={`group flex items-center gap-1.5 px-2 py-1 text-xs cursor-pointer ${
+                active ? 'bg-blue-100 dark:bg-blue-900/40 text-blue-700 dark:text-blue-300' : 'hover:bg-gray-200 dark:hover:bg-gray-700'
+              }`}
+              onClick={() => openLocalRoot(root)}
+              title={root.path}
+            >
+              <Icon className="w-4 h-4 flex-shrink-0" />
+              <span className="truncate flex-1">{root.name}</span>
+              {root.kind === 'custom' && (
+                <button
+                  onClick={(e) => {
+                    e.stopPropagation()
+                    removeLocalRoot(root.path).catch((err) => console.error('Failed to remove location:', err))
+                  }}
+                  className="hidden group-hover:block p-0.5 rounded hover:bg-gray-300 dark:hover:bg-gray-600"
+                  title="Remove location"
+                >
+                  <XMarkIcon className="w-3 h-3" />
+                </button>
+              )}
+            </div>
+          )
+        })}
         <button
-          onClick={goLocalBack}
-          disabled={history.length === 0}
-          className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 disabled:opacity-50 disabled:cursor-not-allowed"
-          title="Go back"
+          onClick={handleAddRoot}
+          className="flex items-center gap-1.5 w-full px-2 py-1 text-xs text-gray-500 hover:bg-gray-200 dark:hover:bg-gray-700"
+          title="Add a location to this list"
         >
-          <ArrowLeftIcon className="w-4 h-4" />
+          <PlusIcon className="w-4 h-4" />
+          <span>Add location</span>
         </button>
+      </div>
 
-        {/* Home button */}
-        <button
-          onClick={goLocalHome}
-          className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700"
-          title="Go to home directory"
-        >
-          <HomeIcon className="w-4 h-4" />
-        </button>
+      <div className="flex flex-col flex-1 min-w-0">
+        {/* Navigation bar */}
+        <div className="flex items-center gap-2 p-2 border-b border-gray-200 dark:border-gray-700 bg-gray-50 dark:bg-gray-800">
+          {/* Back button */}
+          <button
+            onClick={goLocalBack}
+            disabled={history.length === 0}
+            className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 disabled:opacity-50 disabled:cursor-not-allowed"
+            title="Go back"
+          >
+            <ArrowLeftIcon className="w-4 h-4" />
+          </button>
 
-        {/* Path input */}
-        <form onSubmit={handlePathSubmit} className="flex-1">
-          <input
-            ref={pathInputRef}
-            type="text"
-            value={pathInput}
-            onChange={(e) => setPathInput(e.target.value)}
-            onBlur={() => setPathInput(currentPath)}
-            className="w-full px-2 py-1 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-900 focus:outline-none focus:ring-1 focus:ring-blue-500"
-            placeholder="Enter path..."
-          />
-        </form>
+          {/* Home button */}
+          <button
+            onClick={goLocalHome}
+            className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700"
+            title="Go to home directory"
+          >
+            <HomeIcon className="w-4 h-4" />
+          </button>
 
-        {/* Hidden files toggle */}
-        <button
-          onClick={toggleShowHidden}
-          className={`p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 ${
-            showHidden ? 'text-blue-500' : ''
-          }`}
-          title={showHidden ? 'Hide hidden files' : 'Show hidden files'}
-        >
-          {showHidden ? (
-            <EyeIcon className="w-4 h-4" />
-          ) : (
-            <EyeSlashIcon className="w-4 h-4" />
-          )}
-        </button>
+          {/* Path input */}
+          <form onSubmit={handlePathSubmit} className="flex-1">
+            <input
+              ref={pathInputRef}
+              type="text"
+              value={pathInput}
+              onChange={(e) => setPathInput(e.target.value)}
+              onBlur={() => setPathInput(currentPath)}
+              className="w-full px-2 py-1 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-900 focus:outline-none focus:ring-1 focus:ring-blue-500"
+              placeholder="Enter path..."
+            />
+          </form>
 
-        {/* Refresh button */}
-        <button
-          onClick={refreshLocal}
-          disabled={isLoading}
-          className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 disabled:opacity-50"
-          title="Refresh"
-        >
-          <ArrowPathIcon className={`w-4 h-4 ${isLoading ? 'animate-spin' : ''}`} />
-        </button>
-      </div>
+          {/* Hidden files toggle */}
+          <button
+            onClick={toggleShowHidden}
+            className={`p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 ${
+              showHidden ? 'text-blue-500' : ''
+            }`}
+            title={showHidden ? 'Hide hidden files' : 'Show hidden files'}
+          >
+            {showHidden ? (
+              <EyeIcon className="w-4 h-4" />
+            ) : (
+              <EyeSlashIcon className="w-4 h-4" />
+            )}
+          </button>
+
+          {/* Refresh button */}
+          <button
+            onClick={refreshLocal}
+            disabled={isLoading}
+            className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 disabled:opacity-50"
+            title="Refresh"
+          >
+            <ArrowPathIcon className={`w-4 h-4 ${isLoading ? 'animate-spin' : ''}`} />
+          </button>
+        </div>
 
-      {/* Non-fatal warning banner (e.g. slow directory read). Fatal errors
-          are surfaced by FileList's error branch below. */}
-      {warning && (
-        <div className="flex items-start gap-2 px-3 py-2 bg-yellow-50 dark:bg-yellow-900/20 border-b border-yellow-200 dark:border-yellow-800 text-sm text-yellow-700 dark:text-yellow-400">
-          <ExclamationTriangleIcon className="w-4 h-4 flex-shrink-0 mt-0.5" />
-          <span>{warning}</span>
+        {/* Non-fatal warning banner (e.g. slow directory read). Fatal errors
+            are surfaced by FileList's error branch below. */}
+        {warning && (
+          <div className="flex items-start gap-2 px-3 py-2 bg-yellow-50 dark:bg-yellow-900/20 border-b border-yellow-200 dark:border-yellow-800 text-sm text-yellow-700 dark:text-yellow-400">
+            <ExclamationTriangleIcon className="w-4 h-4 flex-shrink-0 mt-0.5" />
+            <span>{warning}</span>
+          </div>
+        )}
+
+        {/* File list */}
+        <div className="flex-1 overflow-hidden">
+          <FileList
+            items={items}
+            selectedIds={selection.selectedIds}
+            lastSelectedId={selection.lastSelectedId}
+            onSelectionChange={handleSelectionChange}
+            onFolderOpen={handleFolderOpen}
+            isLoading={isLoading}
+            error={error}
+            emptyMessage="This folder is empty"
+            isLocal={true}
+          />
         </div>
-      )}
-
-      {/* File list */}
-      <div className="flex-1 overflow-hidden">
-        <FileList
-          items={items}
-          selectedIds={selection.selectedIds}
-          lastSelectedId={selection.lastSelectedId}
-          onSelectionChange={handleSelectionChange}
-          onFolderOpen={handleFolderOpen}
-          isLoading={isLoading}
-          error={error}
-          emptyMessage="This folder is empty"
-          isLocal={true}
-        />
       </div>
     </div>
   )
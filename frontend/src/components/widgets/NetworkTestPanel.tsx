@@ -0,0 +1,134 @@
+// Settings panel that runs the network test (API latency, DNS, proxy hops,
+// storage throughput). The last result is also attached to error reports.
+import { useEffect, useState } from 'react'
+import { ArrowPathIcon, XCircleIcon } from '@heroicons/react/24/outline'
+import clsx from 'clsx'
+import { RunNetworkTest, GetLastNetworkTest } from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+
+function formatMs(ms: number): string {
+  return ms >= 1000 ? `${(ms / 1000).toFixed(2)} s` : `${ms.toFixed(0)} ms`
+}
+
+function formatSize(bytes: number): string {
+  return bytes >= 1024 * 1024 ? `${(bytes / (1024 * 1024)).toFixed(0)} MB` : `${(bytes / 1024).toFixed(0)} KB`
+}
+
+function Row({ label, value, error }: { label: string; value: string; error?: string }) {
+  return (
+    <div className="flex justify-between gap-4 py-1 border-b border-gray-100 last:border-0">
+      <span className="text-gray-600">{label}</span>
+      {error ? (
+        <span className="text-red-600 text-right truncate" title={error}>{error}</span>
+      ) : (
+        <span className="text-gray-900 text-right font-mono">{value}</span>
+      )}
+    </div>
+  )
+}
+
+export function NetworkTestPanel() {
+  const [running, setRunning] = useState(false)
+  const [result, setResult] = useState<wailsapp.NetworkTestResultDTO | null>(null)
+
+  useEffect(() => {
+    GetLastNetworkTest().then((last) => {
+      if (last) setResult(last)
+    }).catch(() => {})
+  }, [])
+
+  const handleRun = async () => {
+    setRunning(true)
+    try {
+      setResult(await RunNetworkTest())
+    } catch (err) {
+      setResult({ error: String(err) } as wailsapp.NetworkTestResultDTO)
+    } finally {
+      setRunning(false)
+    }
+  }
+
+  const api = result?.api
+  const apiValue = api && api.samples > api.failed
+    ? `p50 ${formatMs(api.p50Ms)} · p90 ${formatMs(api.p90Ms)} · p99 ${formatMs(api.p99Ms)}`
+    : ''
+
+  return (
+    <div className="card">
+      <h3 className="text-base font-semibold text-gray-900 mb-4">Network Test</h3>
+      <div className="space-y-4">
+        <p className="text-xs text-gray-500">
+          Measures API latency, DNS resolution, proxy hops, and storage upload/download throughput
+          with a small and a large probe file. Probe files are deleted afterwards. The latest result
+          is included in error reports sent to support.
+        </p>
+
+        <div className="flex items-center gap-4">
+          <button onClick={handleRun} disabled={running} className="btn-primary flex items-center">
+            {running && <ArrowPathIcon className="w-4 h-4 mr-2 animate-spin" />}
+            {running ? 'Running...' : 'Run Network Test'}
+          </button>
+          {result?.startedAt && !running && (
+            <span className="text-xs text-gray-500">
+              Last run {new Date(result.startedAt).toLocaleString()} ({formatMs(result.durationMs)})
+            </span>
+          )}
+        </div>
+
+        {result?.error && (
+          <div className="flex items-center gap-2 p-3 rounded-md bg-red-50 text-red-700 text-sm">
+            <XCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>{result.error}</span>
+          </div>
+        )}
+
+        {result && !result.error && (
+          <div className="text-sm space-y-4">
+            <div>
+              <h4 className="font-medium text-gray-700 mb-1">API ({api?.host})</h4>
+              <Row
+                label={`Latency (${api?.samples ?? 0} requests${api?.failed ? `, ${api.failed} failed` : ''})`}
+                value={apiValue}
+                error={api && api.samples === api.failed ? api.error : undefined}
+              />
+              {api && api.samples > api.failed && (
+                <Row label="Min / max" value={`${formatMs(api.minMs)} / ${formatMs(api.maxMs)}`} />
+              )}
+            </div>
+
+            <div>
+              <h4 className="font-medium text-gray-700 mb-1">DNS</h4>
+              {result.dns.map((d) => (
+                <Row key={d.host} label={d.host} value={formatMs(d.durationMs)} error={d.error} />
+              ))}
+            </div>
+
+            <div>
+              <h4 className="font-medium text-gray-700 mb-1">Proxy</h4>
+              <Row label="Mode" value={result.proxy.url ? `${result.proxy.mode} (${result.proxy.url})` : result.proxy.mode} />
+              <Row label="Hops (Via headers)" value={String(result.proxy.hops)} />
+              {result.proxy.via.map((hop, i) => (
+                <Row key={i} label={`Hop ${i + 1}`} value={hop} />
+              ))}
+            </div>
+
+            <div>
+              <h4 className="font-medium text-gray-700 mb-1">
+                Storage{result.storageType ? ` (${result.storageType})` : ''}
+              </h4>
+              {result.storage.map((s) => (
+                <div key={s.label} className={clsx(s.error && 'text-red-600')}>
+                  <Row
+                    label={`${s.label === 'large' ? 'Large' : 'Small'} probe (${formatSize(s.bytes)})`}
+                    value={`PUT ${s.uploadMBps.toFixed(1)} MB/s · GET ${s.downloadMBps.toFixed(1)} MB/s`}
+                    error={s.error}
+                  />
+                </div>
+              ))}
+            </div>
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1,50 @@
+// Footer widget showing today's network data usage on this machine, with
+// this month's and this session's totals on hover, for sites that budget
+// their monthly egress. Totals include CLI runs ('history usage').
+import { useEffect, useState } from 'react'
+import { ArrowsUpDownIcon } from '@heroicons/react/24/outline'
+import { GetNetworkUsage } from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+import { formatSize } from './FileList'
+
+const POLL_INTERVAL_MS = 30_000
+
+const bytes = (n: number) => (n > 0 ? formatSize(n) : '0 B')
+
+export function NetworkUsageWidget() {
+  const [usage, setUsage] = useState<wailsapp.NetworkUsageDTO | null>(null)
+
+  useEffect(() => {
+    let cancelled = false
+    const refresh = async () => {
+      try {
+        const result = await GetNetworkUsage()
+        if (!cancelled) setUsage(result)
+      } catch {
+        // Keep the last totals; the next poll retries
+      }
+    }
+    refresh()
+    const timer = setInterval(refresh, POLL_INTERVAL_MS)
+    return () => {
+      cancelled = true
+      clearInterval(timer)
+    }
+  }, [])
+
+  if (!usage) return null
+
+  const title = [
+    `Today: ${bytes(usage.todaySent)} sent, ${bytes(usage.todayReceived)} received`,
+    `This month: ${bytes(usage.monthSent)} sent, ${bytes(usage.monthReceived)} received`,
+    `This session: ${bytes(usage.sessionSent)} sent, ${bytes(usage.sessionReceived)} received`,
+    usage.error ? `Usage log: ${usage.error}` : '',
+  ].filter(Boolean).join('\n')
+
+  return (
+    <span className="flex items-center gap-1 text-gray-500" title={title} data-testid="network-usage">
+      <ArrowsUpDownIcon className="w-3.5 h-3.5" />
+      Today ↑ {bytes(usage.todaySent)} ↓ {bytes(usage.todayReceived)}
+    </span>
+  )
+}
@@ -0,0 +1,84 @@
+// App-wide job notifications: a toast stack and the footer mute menu.
+import { useState } from 'react'
+import {
+  BellIcon,
+  BellSlashIcon,
+  CheckCircleIcon,
+  ExclamationCircleIcon,
+  InformationCircleIcon,
+  XMarkIcon,
+} from '@heroicons/react/24/outline'
+import clsx from 'clsx'
+import { useNotificationStore, type NotificationSeverity } from '../../stores/notificationStore'
+
+const severityStyle: Record<NotificationSeverity, { icon: typeof BellIcon; color: string; label: string }> = {
+  success: { icon: CheckCircleIcon, color: 'text-green-500', label: 'Completed jobs' },
+  error: { icon: ExclamationCircleIcon, color: 'text-red-500', label: 'Failures' },
+  info: { icon: InformationCircleIcon, color: 'text-blue-500', label: 'Submissions' },
+}
+
+export function ToastStack({ onOpen }: { onOpen?: () => void }) {
+  const { toasts, dismiss } = useNotificationStore()
+  if (toasts.length === 0) return null
+
+  return (
+    <div className="fixed bottom-12 right-4 z-50 flex flex-col gap-2 w-80">
+      {toasts.map((toast) => {
+        const { icon: Icon, color } = severityStyle[toast.severity]
+        return (
+          <div
+            key={toast.id}
+            role="status"
+            className="flex items-start gap-2 p-3 bg-white border border-gray-200 rounded-lg shadow-lg text-sm"
+          >
+            <Icon className={clsx('w-5 h-5 flex-shrink-0', color)} />
+            <button
+              onClick={() => {
+                onOpen?.()
+                dismiss(toast.id)
+              }}
+              className="flex-1 min-w-0 text-left"
+              title="Show job"
+            >
+              <div className="font-medium truncate">{toast.title}</div>
+              {toast.message && <div className="text-xs text-gray-500 truncate">{toast.message}</div>}
+            </button>
+            <button onClick={() => dismiss(toast.id)} className="text-gray-400 hover:text-gray-600" title="Dismiss">
+              <XMarkIcon className="w-4 h-4" />
+            </button>
+          </div>
+        )
+      })}
+    </div>
+  )
+}
+
+export function NotificationMuteMenu() {
+  const { mute, setMuted } = useNotificationStore()
+  const [open, setOpen] = useState(false)
+  const allMuted = Object.values(mute).every(Boolean)
+  const Icon = allMuted ? BellSlashIcon : BellIcon
+
+  return (
+    <div className="relative">
+      <button onClick={() => setOpen(!open)} className="flex items-center hover:text-gray-700" title="Notification settings">
+        <Icon className="w-4 h-4" />
+      </button>
+      {open && (
+        <div className="absolute bottom-6 right-0 z-50 w-48 p-2 bg-white border border-gray-200 rounded-lg shadow-lg">
+          <div className="px-1 pb-1 font-medium text-gray-700">Notify me about</div>
+          {(Object.keys(severityStyle) as NotificationSeverity[]).map((severity) => (
+            <label key={severity} className="flex items-center gap-2 px-1 py-0.5 cursor-pointer">
+              <input
+                type="checkbox"
+                checked={!mute[severity]}
+                onChange={(e) => setMuted(severity, !e.target.checked)}
+              />
+              {severityStyle[severity].label}
+            </label>
+          ))}
+        </div>
+      )}
+    </div>
+  )
+}
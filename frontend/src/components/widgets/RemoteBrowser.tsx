@@ -6,8 +6,10 @@ import {
   ChevronRightIcon,
   TrashIcon,
 } from '@heroicons/react/24/outline'
-import { useFileBrowserStore, BrowseMode } from '../../stores'
+import { useFileBrowserStore, useConfigStore, BrowseMode, isWindowedMode, WINDOW_PAGE_SIZE } from '../../stores'
 import { FileList } from './FileList'
+import type { SortField, SortDirection } from './FileList'
+import { WindowedFileList } from './WindowedFileList'
 
 export function RemoteBrowser() {
   const {
@@ -25,6 +27,10 @@ export function RemoteBrowser() {
       currentPage,
       itemsPerPage,
       knownTotalPages,
+      // Windowed listing state (library/jobs)
+      windowTotal,
+      windowPages,
+      windowSort,
     },
     initRemote,
     setRemoteMode,
@@ -38,7 +44,11 @@ export function RemoteBrowser() {
     setRemoteItemsPerPage,
     goToNextRemotePage,
     goToPreviousRemotePage,
+    loadRemoteWindow,
+    setRemoteSort,
+    jumpToRemoteName,
   } = useFileBrowserStore()
+  const viewerMode = useConfigStore((s) => s.viewerMode)
 
   const isTrash = mode === 'trash'
 
@@ -75,6 +85,10 @@ export function RemoteBrowser() {
     setRemoteSelection(ids, lastId)
   }, [setRemoteSelection])
 
+  const handleSortChange = useCallback((field: SortField, direction: SortDirection) => {
+    setRemoteSort(field, direction === 'desc')
+  }, [setRemoteSort])
+
   // Handle create folder
   const handleCreateFolder = useCallback(async () => {
     if (!newFolderName.trim()) return
@@ -133,8 +147,8 @@ export function RemoteBrowser() {
         {/* Spacer */}
         <div className="flex-1 min-w-0" />
 
-        {/* New folder button (only in My Library) */}
-        {canCreateFolder && !isTrash && (
+        {/* New folder button (only in My Library, not in viewer mode) */}
+        {canCreateFolder && !isTrash && !viewerMode && (
           <button
             onClick={() => setShowNewFolderDialog(true)}
             className="p-1.5 rounded hover:bg-gray-200 dark:hover:bg-gray-700 flex-shrink-0"
@@ -179,40 +193,53 @@ export function RemoteBrowser() {
         </div>
       )}
 
-      {/* File list */}
+      {/* File list: folder trees are virtualized over server-sorted pages;
+          Legacy and Trash keep cursor pagination */}
       <div className="flex-1 overflow-hidden">
-        <FileList
-          items={items}
-          selectedIds={selection.selectedIds}
-          lastSelectedId={selection.lastSelectedId}
-          onSelectionChange={handleSelectionChange}
-          onFolderOpen={handleFolderOpen}
-          isLoading={isLoading}
-          error={error}
-          emptyMessage={
-            mode === 'library'
-              ? 'Your library is empty'
-              : mode === 'jobs'
-              ? 'No job files found'
-              : mode === 'trash'
-              ? 'Trash is empty'
-              : 'No files found'
-          }
-          loadingMessage={
-            mode === 'legacy'
-              ? 'Loading legacy files (this may take a moment)...'
-              : 'Loading...'
-          }
-          // Server-side pagination
-          useServerPagination={true}
-          serverCurrentPage={currentPage}
-          serverKnownTotalPages={knownTotalPages}
-          serverHasMore={hasMore}
-          serverItemsPerPage={itemsPerPage}
-          onServerNextPage={goToNextRemotePage}
-          onServerPrevPage={goToPreviousRemotePage}
-          onServerItemsPerPageChange={setRemoteItemsPerPage}
-        />
+        {isWindowedMode(mode) ? (
+          <WindowedFileList
+            total={windowTotal}
+            pageSize={WINDOW_PAGE_SIZE}
+            pages={windowPages}
+            sortField={windowSort.field}
+            sortDirection={windowSort.desc ? 'desc' : 'asc'}
+            onSortChange={handleSortChange}
+            onRangeChange={loadRemoteWindow}
+            onJumpToName={jumpToRemoteName}
+            selectedIds={selection.selectedIds}
+            lastSelectedId={selection.lastSelectedId}
+            onSelectionChange={handleSelectionChange}
+            onFolderOpen={handleFolderOpen}
+            isLoading={isLoading}
+            error={error}
+            emptyMessage={mode === 'library' ? 'Your library is empty' : 'No job files found'}
+          />
+        ) : (
+          <FileList
+            items={items}
+            selectedIds={selection.selectedIds}
+            lastSelectedId={selection.lastSelectedId}
+            onSelectionChange={handleSelectionChange}
+            onFolderOpen={handleFolderOpen}
+            isLoading={isLoading}
+            error={error}
+            emptyMessage={mode === 'trash' ? 'Trash is empty' : 'No files found'}
+            loadingMessage={
+              mode === 'legacy'
+                ? 'Loading legacy files (this may take a moment)...'
+                : 'Loading...'
+            }
+            // Server-side pagination
+            useServerPagination={true}
+            serverCurrentPage={currentPage}
+            serverKnownTotalPages={knownTotalPages}
+            serverHasMore={hasMore}
+            serverItemsPerPage={itemsPerPage}
+            onServerNextPage={goToNextRemotePage}
+            onServerPrevPage={goToPreviousRemotePage}
+            onServerItemsPerPageChange={setRemoteItemsPerPage}
+          />
+        )}
       </div>
 
 
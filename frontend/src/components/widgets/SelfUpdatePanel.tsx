@@ -0,0 +1,123 @@
+// Settings panel for self-update from an internal distribution server.
+// Releases are only installed if their signature verifies against the
+// configured public key; the new binary takes effect on restart.
+import { useState } from 'react'
+import { ArrowPathIcon, CheckCircleIcon, XCircleIcon } from '@heroicons/react/24/outline'
+import { useConfigStore } from '../../stores'
+import { CheckSelfUpdate, InstallSelfUpdate, SelectFile } from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+
+export function SelfUpdatePanel() {
+  const { config, updateConfig } = useConfigStore()
+  const [busy, setBusy] = useState<'check' | 'install' | null>(null)
+  const [result, setResult] = useState<wailsapp.SelfUpdateDTO | null>(null)
+
+  const run = async (kind: 'check' | 'install') => {
+    setBusy(kind)
+    try {
+      setResult(kind === 'check' ? await CheckSelfUpdate() : await InstallSelfUpdate())
+    } catch (err) {
+      setResult({ error: String(err) } as wailsapp.SelfUpdateDTO)
+    } finally {
+      setBusy(null)
+    }
+  }
+
+  const handleBrowseKey = async () => {
+    try {
+      const path = await SelectFile('Select release public key (PEM)')
+      if (path) updateConfig({ updatePublicKey: path })
+    } catch {
+      // Dialog cancelled
+    }
+  }
+
+  return (
+    <div className="card">
+      <h3 className="text-base font-semibold text-gray-900 mb-4">Software Updates</h3>
+      <div className="space-y-4">
+        <p className="text-xs text-gray-500">
+          Install new releases from your organization's distribution server. A release is only
+          installed if its signature verifies against the public key below. Save settings before checking.
+        </p>
+
+        <div>
+          <label className="label">Update Manifest URL</label>
+          <input
+            type="text"
+            className="input"
+            value={config?.updateUrl || ''}
+            onChange={(e) => updateConfig({ updateUrl: e.target.value })}
+            placeholder="https://dist.example.com/rescale-int/latest.json"
+          />
+        </div>
+
+        <div>
+          <label className="label">Release Public Key</label>
+          <div className="flex gap-2">
+            <input
+              type="text"
+              className="input flex-1"
+              value={config?.updatePublicKey || ''}
+              onChange={(e) => updateConfig({ updatePublicKey: e.target.value })}
+              placeholder="/etc/rescale/release-key.pem"
+            />
+            <button onClick={handleBrowseKey} className="btn-secondary">Browse</button>
+          </div>
+        </div>
+
+        <div className="flex items-center">
+          <input
+            type="checkbox"
+            id="selfUpdateDisabled"
+            checked={config?.selfUpdateDisabled || false}
+            onChange={(e) => updateConfig({ selfUpdateDisabled: e.target.checked })}
+            className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+          />
+          <label htmlFor="selfUpdateDisabled" className="ml-2 text-sm text-gray-700 cursor-pointer">
+            Disable self-update
+          </label>
+        </div>
+
+        <div className="flex items-center gap-4">
+          <button onClick={() => run('check')} disabled={busy !== null} className="btn-secondary flex items-center">
+            {busy === 'check' && <ArrowPathIcon className="w-4 h-4 mr-2 animate-spin" />}
+            {busy === 'check' ? 'Checking...' : 'Check for Updates'}
+          </button>
+          {result?.hasUpdate && !result.installed && (
+            <button onClick={() => run('install')} disabled={busy !== null} className="btn-primary flex items-center">
+              {busy === 'install' && <ArrowPathIcon className="w-4 h-4 mr-2 animate-spin" />}
+              {busy === 'install' ? 'Installing...' : `Install ${result.latestVersion}`}
+            </button>
+          )}
+        </div>
+
+        {result?.error && (
+          <div className="flex items-center gap-2 p-3 rounded-md bg-red-50 text-red-700 text-sm">
+            <XCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>{result.error}</span>
+          </div>
+        )}
+
+        {result && !result.error && !result.enabled && (
+          <p className="text-sm text-gray-600">
+            Self-update is disabled or not configured. Set the manifest URL and public key, then save.
+          </p>
+        )}
+
+        {result && !result.error && result.enabled && (
+          <div className="flex items-center gap-2 p-3 rounded-md bg-green-50 text-green-700 text-sm">
+            <CheckCircleIcon className="w-5 h-5 flex-shrink-0" />
+            <span>
+              {result.installed
+                ? `Installed ${result.latestVersion} (signature verified). Restart Interlink to use it.`
+                : result.hasUpdate
+                  ? `Update available: ${result.currentVersion} → ${result.latestVersion}`
+                  : `${result.currentVersion} is up to date`}
+            </span>
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
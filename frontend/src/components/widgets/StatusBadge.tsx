@@ -5,6 +5,7 @@ const STYLES: Record<string, string> = {
   pending: 'bg-gray-200 text-gray-700',
   running: 'bg-blue-200 text-blue-700',
   in_progress: 'bg-blue-200 text-blue-700',
+  waiting: 'bg-yellow-200 text-yellow-800',
   success: 'bg-green-200 text-green-700',
   completed: 'bg-green-200 text-green-700',
   failed: 'bg-red-200 text-red-700',
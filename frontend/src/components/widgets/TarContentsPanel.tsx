@@ -0,0 +1,86 @@
+// Lists the files in a job's input tar so missing include files can be spotted
+// before submitting. Used by the PUR tab's review and run monitor tables.
+import { useEffect, useState } from 'react'
+import { ArchiveBoxIcon, ExclamationTriangleIcon, XMarkIcon } from '@heroicons/react/24/outline'
+import * as App from '../../../wailsjs/go/wailsapp/App'
+import { wailsapp } from '../../../wailsjs/go/models'
+import { formatSize } from './FileList'
+
+interface TarContentsPanelProps {
+  job: wailsapp.JobSpecDTO
+  onClose: () => void
+}
+
+export function TarContentsPanel({ job, onClose }: TarContentsPanelProps) {
+  const [result, setResult] = useState<wailsapp.TarContentsDTO | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [filter, setFilter] = useState('')
+
+  useEffect(() => {
+    let cancelled = false
+    setResult(null)
+    setError(null)
+    App.GetJobTarContents(job)
+      .then((r) => { if (!cancelled) setResult(r) })
+      .catch((err) => { if (!cancelled) setError(err instanceof Error ? err.message : String(err)) })
+    return () => { cancelled = true }
+  }, [job])
+
+  const needle = filter.trim().toLowerCase()
+  const entries = (result?.entries || []).filter((e) => !needle || e.name.toLowerCase().includes(needle))
+
+  return (
+    <div className="mb-4 p-4 border border-gray-200 dark:border-gray-700 rounded-lg bg-white dark:bg-gray-800">
+      <div className="flex items-center justify-between mb-3">
+        <h4 className="font-medium flex items-center gap-2">
+          <ArchiveBoxIcon className="w-5 h-5 text-blue-500" />
+          Tar contents: {job.jobName}
+          {result && (
+            <span className="text-xs font-normal text-gray-500">
+              {result.fromArchive ? 'archive' : 'preview'} · {result.entries?.length || 0} files · {formatSize(result.totalSize)}
+            </span>
+          )}
+        </h4>
+        <button onClick={onClose} className="text-gray-400 hover:text-gray-600" title="Close">
+          <XMarkIcon className="w-5 h-5" />
+        </button>
+      </div>
+
+      {error && (
+        <div className="flex items-start gap-2 text-sm text-red-600">
+          <ExclamationTriangleIcon className="w-5 h-5 flex-shrink-0" />
+          {error}
+        </div>
+      )}
+      {!result && !error && <div className="text-sm text-gray-500">Listing files…</div>}
+
+      {result && (
+        <>
+          {(result.entries?.length || 0) === 0 ? (
+            <div className="text-sm text-yellow-700 dark:text-yellow-400">
+              The tar would be empty: no files match the include/exclude patterns.
+            </div>
+          ) : (
+            <>
+              <input
+                type="text"
+                value={filter}
+                onChange={(e) => setFilter(e.target.value)}
+                placeholder="Filter files…"
+                className="w-full mb-2 px-2 py-1 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-900"
+              />
+              <div className="overflow-auto max-h-64 font-mono text-xs">
+                {entries.map((e) => (
+                  <div key={e.name} className="flex justify-between gap-4 py-0.5">
+                    <span className="truncate" title={e.name}>{e.name}</span>
+                    <span className="text-gray-500 flex-shrink-0">{formatSize(e.size)}</span>
+                  </div>
+                ))}
+              </div>
+            </>
+          )}
+        </>
+      )}
+    </div>
+  )
+}
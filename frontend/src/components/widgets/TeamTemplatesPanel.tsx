@@ -0,0 +1,194 @@
+// Lists the job templates and run recipes in the team template repository
+// (template_repo) with the status of each import: import, update and pin
+// them. Used by the Template Builder under Saved Templates.
+import { useCallback, useEffect, useState } from 'react'
+import {
+  ArrowDownTrayIcon,
+  ArrowPathIcon,
+  ExclamationTriangleIcon,
+  LockClosedIcon,
+  LockOpenIcon,
+} from '@heroicons/react/24/outline'
+import * as App from '../../../wailsjs/go/wailsapp/App'
+
+interface TemplateRepoItem {
+  kind: string
+  name: string
+  file: string
+  version: string
+  installedVersion: string
+  description: string
+  status: string
+  pinned: boolean
+  localPath: string
+}
+
+interface TemplateRepo {
+  configured: boolean
+  source: string
+  revision: string
+  warning: string
+  items: TemplateRepoItem[]
+  updates: number
+}
+
+interface TeamTemplatesPanelProps {
+  // Called after templates are imported or updated, to reload the library
+  onImported: () => void
+}
+
+const STATUS_TEXT: Record<string, string> = {
+  current: 'Imported',
+  'update-available': 'Update available',
+  pinned: 'Pinned (update available)',
+  modified: 'Edited locally',
+  removed: 'Removed upstream',
+}
+
+function errorText(err: unknown): string {
+  return err instanceof Error ? err.message : String(err)
+}
+
+export function TeamTemplatesPanel({ onImported }: TeamTemplatesPanelProps) {
+  const [repo, setRepo] = useState<TemplateRepo | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [busy, setBusy] = useState(false)
+
+  const load = useCallback(async () => {
+    setLoading(true)
+    setError(null)
+    try {
+      setRepo((await App.GetTemplateRepo()) as unknown as TemplateRepo)
+    } catch (err) {
+      setError(errorText(err))
+    } finally {
+      setLoading(false)
+    }
+  }, [])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const run = async (action: () => Promise<unknown>) => {
+    setBusy(true)
+    setError(null)
+    try {
+      await action()
+      onImported()
+      await load()
+    } catch (err) {
+      setError(errorText(err))
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  const handleImport = (item: TemplateRepoItem) => {
+    const force = item.status === 'modified'
+    if (force && !confirm(`${item.name} was edited locally.\n\nReplace it with the repository's version?`)) return
+    run(async () => {
+      try {
+        await App.ImportRepoTemplate(item.file, false, force)
+      } catch (err) {
+        // A local template or recipe of the same name that was not imported
+        if (force || !errorText(err).includes('would be overwritten')) throw err
+        if (!confirm(`${errorText(err)}\n\nReplace it with the repository's version?`)) return
+        await App.ImportRepoTemplate(item.file, false, true)
+      }
+    })
+  }
+
+  if (repo && !repo.configured) {
+    return (
+      <p className="text-sm text-gray-500 italic">
+        No team template repository configured. Set one under Job Defaults in the Setup tab.
+      </p>
+    )
+  }
+
+  return (
+    <div>
+      <div className="flex items-center justify-between mb-2 text-xs text-gray-500">
+        <span className="font-mono truncate" title={repo?.source}>
+          {repo?.source}
+          {repo?.revision && ` @ ${repo.revision.slice(0, 12)}`}
+        </span>
+        <span className="flex items-center gap-2 flex-shrink-0">
+          {repo && repo.updates > 0 && (
+            <button
+              onClick={() => run(() => App.UpdateRepoTemplates())}
+              disabled={busy}
+              className="px-2 py-0.5 text-blue-600 border border-blue-300 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20 disabled:opacity-50"
+              title="Update imports that changed upstream (pinned and edited ones are kept)"
+            >
+              Update {repo.updates}
+            </button>
+          )}
+          <button onClick={load} disabled={loading} className="text-gray-400 hover:text-gray-600 disabled:opacity-50" title="Refresh">
+            <ArrowPathIcon className={`w-4 h-4 ${loading ? 'animate-spin' : ''}`} />
+          </button>
+        </span>
+      </div>
+
+      {repo?.warning && (
+        <div className="flex items-start gap-2 mb-2 text-xs text-yellow-700 dark:text-yellow-400">
+          <ExclamationTriangleIcon className="w-4 h-4 flex-shrink-0" />
+          {repo.warning}
+        </div>
+      )}
+      {error && (
+        <div className="flex items-start gap-2 mb-2 text-sm text-red-600">
+          <ExclamationTriangleIcon className="w-5 h-5 flex-shrink-0" />
+          {error}
+        </div>
+      )}
+      {!repo && !error && <p className="text-sm text-gray-500">Loading the template repository…</p>}
+
+      {repo && repo.items.length === 0 && (
+        <p className="text-sm text-gray-500 italic">The repository has no templates or recipes</p>
+      )}
+      {repo && repo.items.length > 0 && (
+        <div className="grid gap-2 max-h-48 overflow-y-auto">
+          {repo.items.map((item) => (
+            <div key={item.file} className="flex items-center justify-between gap-2 p-2 bg-gray-50 dark:bg-gray-700/50 rounded">
+              <div className="flex-1 min-w-0">
+                <div className="font-medium text-sm truncate">
+                  {item.name}
+                  {item.kind === 'recipe' && <span className="ml-2 text-xs font-normal text-gray-500">recipe</span>}
+                </div>
+                <div className="text-xs text-gray-500 truncate" title={item.localPath || item.description}>
+                  {item.description}
+                  {STATUS_TEXT[item.status] && ` • ${STATUS_TEXT[item.status]}`}
+                  {item.installedVersion && ` (${item.installedVersion})`}
+                </div>
+              </div>
+              {item.installedVersion && item.status !== 'removed' && (
+                <button
+                  onClick={() => run(() => App.SetRepoTemplatePinned(item.file, !item.pinned))}
+                  disabled={busy}
+                  className={`p-1 ${item.pinned ? 'text-blue-600' : 'text-gray-400'} hover:text-blue-500 disabled:opacity-50`}
+                  title={item.pinned ? 'Pinned: updates are not applied. Click to unpin' : 'Pin to this version'}
+                >
+                  {item.pinned ? <LockClosedIcon className="w-4 h-4" /> : <LockOpenIcon className="w-4 h-4" />}
+                </button>
+              )}
+              {item.status !== 'current' && item.status !== 'removed' && (
+                <button
+                  onClick={() => handleImport(item)}
+                  disabled={busy}
+                  className="flex items-center gap-1 px-2 py-0.5 text-xs text-blue-600 border border-blue-300 rounded hover:bg-blue-50 dark:hover:bg-blue-900/20 disabled:opacity-50"
+                  title={item.kind === 'recipe' ? 'Save the recipe to ~/.config/rescale/recipes' : 'Add to Saved Templates'}
+                >
+                  <ArrowDownTrayIcon className="w-4 h-4" />
+                  {item.status === 'not-imported' ? 'Import' : 'Update'}
+                </button>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  )
+}
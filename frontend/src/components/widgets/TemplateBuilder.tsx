@@ -8,10 +8,13 @@ import {
   ChevronUpIcon,
   BookmarkIcon,
   FolderIcon,
+  UserGroupIcon,
 } from '@heroicons/react/24/outline'
 import clsx from 'clsx'
 import { useJobStore, JobSpec, DEFAULT_JOB_TEMPLATE, AnalysisCode } from '../../stores'
 import * as App from '../../../wailsjs/go/wailsapp/App'
+import { TeamTemplatesPanel } from './TeamTemplatesPanel'
+import { CoreTypePicker } from './CoreTypePicker'
 
 interface TemplateInfo {
   name: string
@@ -22,6 +25,19 @@ interface TemplateInfo {
   job?: JobSpec
 }
 
+interface RuntimePrediction {
+  samples: number
+  confidence: string
+  runtimeLowHours: number
+  runtimeTypicalHours: number
+  runtimeHighHours: number
+  suggestedWalltimeHours: number
+  coresLow: number
+  coresHigh: number
+  suggestedCores: number
+  coreType: string
+}
+
 interface TemplateBuilderProps {
   isOpen: boolean
   initialTemplate?: JobSpec
@@ -62,6 +78,25 @@ function parseCustomLicenseEntry(input: string): { key: string; value: string }
   return { key, value }
 }
 
+// Job metadata and environment variables are edited as "key=value" lines
+function formatMetadata(metadata?: Record<string, string>): string {
+  return Object.entries(metadata || {})
+    .map(([k, v]) => `${k}=${v}`)
+    .join('\n')
+}
+
+function parseMetadata(text: string): Record<string, string> {
+  const metadata: Record<string, string> = {}
+  for (const line of text.split('\n')) {
+    const eq = line.indexOf('=')
+    if (eq <= 0) continue
+    const key = line.slice(0, eq).trim()
+    const value = line.slice(eq + 1).trim()
+    if (key && value) metadata[key] = value
+  }
+  return metadata
+}
+
 // Searchable select component
 interface SearchableSelectProps {
   options: string[]
@@ -139,6 +174,7 @@ function SearchableSelect({
 export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: TemplateBuilderProps) {
   const {
     coreTypes,
+    coreTypeFavorites,
     analysisCodes,
     automations,
     isLoadingCoreTypes,
@@ -148,12 +184,16 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
     analysisCodesError,
     automationsError,
     fetchCoreTypes,
+    fetchCoreTypeFavorites,
+    toggleCoreTypeFavorite,
     fetchAnalysisCodes,
     fetchAutomations,
   } = useJobStore()
 
   // Form state
   const [template, setTemplate] = useState<JobSpec>(initialTemplate || DEFAULT_JOB_TEMPLATE)
+  const [metadataText, setMetadataText] = useState(() => formatMetadata(initialTemplate?.metadata))
+  const [envVarsText, setEnvVarsText] = useState(() => formatMetadata(initialTemplate?.envVars))
   const [selectedAnalysis, setSelectedAnalysis] = useState<AnalysisCode | null>(null)
   const [licenseType, setLicenseType] = useState('')
   const [licenseValue, setLicenseValue] = useState('')
@@ -188,8 +228,12 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
     setLicenseAutoSwitchHint(null)
   }, [licenseType])
 
+  // Walltime/cores suggestion from earlier jobs of the same template
+  const [prediction, setPrediction] = useState<RuntimePrediction | null>(null)
+
   const [savedTemplates, setSavedTemplates] = useState<TemplateInfo[]>([])
   const [showSavedTemplates, setShowSavedTemplates] = useState(false)
+  const [showTeamTemplates, setShowTeamTemplates] = useState(false)
   const [saveTemplateName, setSaveTemplateName] = useState('')
   const [showSaveDialog, setShowSaveDialog] = useState(false)
 
@@ -216,9 +260,18 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
     }
   }, [isOpen, coreTypes.length, coreTypesError, isLoadingCoreTypes, fetchCoreTypes])
 
+  // Favorites can be starred from the CLI too, so reload them on each open.
+  useEffect(() => {
+    if (isOpen) {
+      fetchCoreTypeFavorites()
+    }
+  }, [isOpen, fetchCoreTypeFavorites])
+
   const handleLoadSavedTemplate = useCallback((templateInfo: TemplateInfo) => {
     if (templateInfo.job) {
       setTemplate(templateInfo.job as JobSpec)
+      setMetadataText(formatMetadata(templateInfo.job.metadata))
+      setEnvVarsText(formatMetadata(templateInfo.job.envVars))
       setLicenseAutoSwitchHint(null)
       setLicenseLoadHint(null)
       if (templateInfo.job.licenseSettings) {
@@ -275,6 +328,8 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
   useEffect(() => {
     if (initialTemplate) {
       setTemplate(initialTemplate)
+      setMetadataText(formatMetadata(initialTemplate.metadata))
+      setEnvVarsText(formatMetadata(initialTemplate.envVars))
       setLicenseAutoSwitchHint(null)
       setLicenseLoadHint(null)
       // Parse license settings if present
@@ -306,9 +361,15 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
     return analysisCodes.map((a) => `${a.name} (${a.code})`)
   }, [analysisCodes])
 
-  const coreTypeOptions = useMemo(() => {
-    return coreTypes.map((ct) => ct.code)
-  }, [coreTypes])
+  // Core types the selected analysis version runs on (empty = any), listed
+  // first in the core type picker
+  const allowedCoreTypes = useMemo(() => {
+    const analysis = analysisCodes.find((a) => a.code === template.analysisCode)
+    const version = analysis?.versions.find(
+      (v) => v.versionCode === template.analysisVersion || v.version === template.analysisVersion
+    )
+    return version?.allowedCoreTypes || []
+  }, [analysisCodes, template.analysisCode, template.analysisVersion])
 
   // Build version display→code mapping for the dropdown
   const versionMap = useMemo(() => {
@@ -375,6 +436,41 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
     [coreTypes]
   )
 
+  // Earlier jobs match on software, tags and metadata keys, so only those
+  // refetch the prediction; walltime and cores are compared locally.
+  const predictionKey = useMemo(
+    () =>
+      [
+        template.analysisCode,
+        [...template.tags].sort().join(','),
+        Object.keys(template.metadata || {}).sort().join(','),
+      ].join('|'),
+    [template.analysisCode, template.tags, template.metadata]
+  )
+
+  useEffect(() => {
+    if (!isOpen || !template.analysisCode.trim()) {
+      setPrediction(null)
+      return
+    }
+    let cancelled = false
+    const timer = setTimeout(async () => {
+      try {
+        const result = await App.PredictJobRuntime(template as unknown as Parameters<typeof App.PredictJobRuntime>[0])
+        if (!cancelled) {
+          setPrediction(result.available ? (result as unknown as RuntimePrediction) : null)
+        }
+      } catch (err) {
+        console.error('Failed to predict runtime:', err)
+      }
+    }, 500)
+    return () => {
+      cancelled = true
+      clearTimeout(timer)
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [isOpen, predictionKey])
+
   // Update template field
   const updateField = useCallback(<K extends keyof JobSpec>(key: K, value: JobSpec[K]) => {
     setTemplate((t) => ({ ...t, [key]: value }))
@@ -538,6 +634,25 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
               )}
             </div>
           )}
+          <button
+            onClick={() => setShowTeamTemplates(!showTeamTemplates)}
+            className="w-full flex items-center justify-between px-6 py-3 text-sm font-medium text-gray-700 dark:text-gray-300 hover:bg-gray-50 dark:hover:bg-gray-700/50"
+          >
+            <span className="flex items-center gap-2">
+              <UserGroupIcon className="w-4 h-4" />
+              Team Repository
+            </span>
+            {showTeamTemplates ? (
+              <ChevronUpIcon className="w-4 h-4" />
+            ) : (
+              <ChevronDownIcon className="w-4 h-4" />
+            )}
+          </button>
+          {showTeamTemplates && (
+            <div className="px-6 pb-4">
+              <TeamTemplatesPanel onImported={loadSavedTemplates} />
+            </div>
+          )}
         </div>
 
         {/* Errors */}
@@ -630,6 +745,17 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                   className="w-full px-3 py-2 text-sm font-mono border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500 resize-none"
                 />
               </div>
+              <div className="col-span-2">
+                <label className="block text-sm font-medium mb-1">Continuation Command</label>
+                <textarea
+                  value={template.continuationCommand || ''}
+                  onChange={(e) => updateField('continuationCommand', e.target.value)}
+                  placeholder="./run.sh --restart"
+                  rows={2}
+                  className="w-full px-3 py-2 text-sm font-mono border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500 resize-none"
+                />
+                <p className="mt-1 text-xs text-gray-500">Used when continuing a finished job from its restart files</p>
+              </div>
             </div>
           </section>
 
@@ -656,10 +782,13 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                     First scan may take up to several minutes...
                   </p>
                 )}
-                <SearchableSelect
-                  options={coreTypeOptions}
+                <CoreTypePicker
+                  coreTypes={coreTypes}
+                  favorites={coreTypeFavorites}
+                  allowedCoreTypes={allowedCoreTypes}
                   value={template.coreType}
                   onChange={handleCoreTypeChange}
+                  onToggleFavorite={toggleCoreTypeFavorite}
                   placeholder={coreTypes.length === 0 ? 'Click "Scan Coretypes" to load' : 'Search coretypes...'}
                   disabled={isLoadingCoreTypes || coreTypes.length === 0}
                 />
@@ -701,6 +830,51 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                 />
               </div>
             </div>
+            {prediction && (
+              <div className="mt-3 p-3 text-xs rounded border border-blue-200 dark:border-blue-800 bg-blue-50 dark:bg-blue-900/20 text-gray-700 dark:text-gray-300 space-y-1">
+                <p>
+                  {prediction.samples} earlier job{prediction.samples === 1 ? '' : 's'} from this template ran{' '}
+                  <strong>{prediction.runtimeTypicalHours.toFixed(1)}h</strong> typically (
+                  {prediction.runtimeLowHours.toFixed(1)}–{prediction.runtimeHighHours.toFixed(1)}h,{' '}
+                  {prediction.confidence} confidence).
+                </p>
+                <p className="flex items-center gap-2">
+                  <span>Suggested walltime: {prediction.suggestedWalltimeHours}h</span>
+                  {template.walltimeHours !== prediction.suggestedWalltimeHours && (
+                    <button
+                      type="button"
+                      onClick={() => updateField('walltimeHours', prediction.suggestedWalltimeHours)}
+                      className="text-blue-600 hover:text-blue-800"
+                    >
+                      Apply
+                    </button>
+                  )}
+                </p>
+                {prediction.suggestedCores > 0 && (
+                  <p className="flex items-center gap-2">
+                    <span>
+                      Cores: typically {prediction.suggestedCores} ({prediction.coresLow}–{prediction.coresHigh}
+                      {prediction.coreType ? ` on ${prediction.coreType}` : ''})
+                    </span>
+                    {template.coresPerSlot * Math.max(1, template.slots) !== prediction.suggestedCores && (
+                      <button
+                        type="button"
+                        onClick={() => handleCoresChange(Math.round(prediction.suggestedCores / Math.max(1, template.slots)))}
+                        className="text-blue-600 hover:text-blue-800"
+                      >
+                        Apply
+                      </button>
+                    )}
+                  </p>
+                )}
+                {template.walltimeHours > 0 && template.walltimeHours < prediction.runtimeHighHours && (
+                  <p className="flex items-center gap-1 text-yellow-700 dark:text-yellow-400">
+                    <ExclamationTriangleIcon className="w-4 h-4" />
+                    Walltime {template.walltimeHours}h is below the 90th percentile runtime; jobs may be stopped before they finish.
+                  </p>
+                )}
+              </div>
+            )}
           </section>
 
           {/* Project & Tags */}
@@ -747,6 +921,31 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                   className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
                 />
               </div>
+              <div>
+                <label className="block text-sm font-medium mb-1">Destination Folder</label>
+                <input
+                  type="text"
+                  value={template.destinationFolder || ''}
+                  onChange={(e) => updateField('destinationFolder', e.target.value)}
+                  placeholder="ProjectA/Study1 (optional, under My Library)"
+                  title="The job's input tarballs and files are uploaded into this folder path; missing folders are created"
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                />
+              </div>
+              <div className="col-span-2">
+                <label className="block text-sm font-medium mb-1">Metadata</label>
+                <textarea
+                  value={metadataText}
+                  onChange={(e) => {
+                    setMetadataText(e.target.value)
+                    updateField('metadata', parseMetadata(e.target.value))
+                  }}
+                  rows={3}
+                  placeholder={'study=wing-sweep\nmach=0.8 (optional, one key=value per line)'}
+                  title="Written to the job description or custom fields at creation (job_metadata_target in Setup)"
+                  className="w-full px-3 py-2 text-sm font-mono border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                />
+              </div>
             </div>
           </section>
 
@@ -857,6 +1056,38 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                   </p>
                 )}
               </div>
+              <div className="col-span-2">
+                <label className="flex items-center gap-2 cursor-pointer">
+                  <input
+                    type="checkbox"
+                    checked={template.useRescaleLicense || false}
+                    onChange={(e) => updateField('useRescaleLicense', e.target.checked)}
+                    className="w-4 h-4 text-blue-500 border-gray-300 rounded focus:ring-blue-500"
+                  />
+                  <span className="text-sm">Use a Rescale-provided license (instead of your own license server)</span>
+                </label>
+              </div>
+            </div>
+          </section>
+
+          {/* Cluster Environment */}
+          <section>
+            <h3 className="text-sm font-semibold text-gray-700 dark:text-gray-300 mb-3 pb-1 border-b border-gray-200 dark:border-gray-700">
+              Cluster Environment
+            </h3>
+            <div>
+              <label className="block text-sm font-medium mb-1">Environment Variables</label>
+              <textarea
+                value={envVarsText}
+                onChange={(e) => {
+                  setEnvVarsText(e.target.value)
+                  updateField('envVars', parseMetadata(e.target.value))
+                }}
+                rows={3}
+                placeholder={'OMP_NUM_THREADS=4\nSOLVER_OPTS=-fast (optional, one NAME=value per line)'}
+                title="Set on the cluster alongside the license settings (env_* CSV columns)"
+                className="w-full px-3 py-2 text-sm font-mono border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+              />
             </div>
           </section>
 
@@ -880,16 +1111,29 @@ export function TemplateBuilder({ isOpen, initialTemplate, onClose, onSave }: Te
                   ))}
                 </select>
               </div>
-              <div className="flex items-end">
-                <label className="flex items-center gap-2 cursor-pointer">
-                  <input
-                    type="checkbox"
-                    checked={template.isLowPriority}
-                    onChange={(e) => updateField('isLowPriority', e.target.checked)}
-                    className="w-4 h-4 text-blue-500 border-gray-300 rounded focus:ring-blue-500"
-                  />
-                  <span className="text-sm">Use ODE (instead of default ODP - warning, ODE jobs may be interrupted)</span>
-                </label>
+              <div>
+                <label className="block text-sm font-medium mb-1">Scheduling</label>
+                <select
+                  value={template.isLowPriority ? 'economy' : 'pro'}
+                  onChange={(e) => updateField('isLowPriority', e.target.value === 'economy')}
+                  disabled={!!template.clusterId}
+                  title={template.clusterId ? 'Does not apply to jobs on a reserved cluster' : undefined}
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500 disabled:bg-gray-100 dark:disabled:bg-gray-700"
+                >
+                  <option value="pro">On-Demand Pro (default)</option>
+                  <option value="economy">On-Demand Economy (cheaper, jobs may be interrupted)</option>
+                </select>
+              </div>
+              <div className="col-span-2">
+                <label className="block text-sm font-medium mb-1">Cluster ID</label>
+                <input
+                  type="text"
+                  value={template.clusterId || ''}
+                  onChange={(e) => updateField('clusterId', e.target.value.trim())}
+                  placeholder="Reserved or persistent cluster ID (optional)"
+                  title="Run on this cluster instead of provisioning a new one"
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded bg-white dark:bg-gray-800 focus:outline-none focus:ring-2 focus:ring-blue-500"
+                />
               </div>
             </div>
           </section>
@@ -0,0 +1,330 @@
+import { useCallback, useEffect, useRef, useState } from 'react'
+import { useVirtualizer } from '@tanstack/react-virtual'
+import { FolderIcon, DocumentIcon, ArrowUpIcon, ArrowDownIcon, MagnifyingGlassIcon } from '@heroicons/react/24/outline'
+import clsx from 'clsx'
+import { wailsapp } from '../../../wailsjs/go/models'
+import { formatDate, formatSize } from './FileList'
+import type { SortField, SortDirection } from './FileList'
+
+const ROW_HEIGHT = 32
+
+interface WindowedFileListProps {
+  total: number                                   // Items in the folder; -1 while the first page loads
+  pageSize: number
+  pages: Map<number, wailsapp.FileItemDTO[]>      // Loaded pages by 0-based page number
+  sortField: SortField
+  sortDirection: SortDirection
+  onSortChange: (field: SortField, direction: SortDirection) => void
+  onRangeChange: (start: number, end: number) => void  // Visible rows changed; fetch what is missing
+  onJumpToName: (prefix: string) => Promise<number | null>
+  selectedIds: Set<string>
+  lastSelectedId?: string | null
+  onSelectionChange: (ids: Set<string>, lastId: string | null) => void
+  onFolderOpen: (item: wailsapp.FileItemDTO) => void
+  isLoading?: boolean
+  error?: string | null
+  emptyMessage?: string
+  loadingMessage?: string
+}
+
+// File list for remote folders of any size. Rows are virtualized over the
+// folder's full item count and filled in from server-sorted pages as they
+// scroll into view; sorting happens on the server, never in the browser.
+export function WindowedFileList({
+  total,
+  pageSize,
+  pages,
+  sortField,
+  sortDirection,
+  onSortChange,
+  onRangeChange,
+  onJumpToName,
+  selectedIds,
+  lastSelectedId,
+  onSelectionChange,
+  onFolderOpen,
+  isLoading = false,
+  error = null,
+  emptyMessage = 'No files or folders',
+  loadingMessage = 'Loading...',
+}: WindowedFileListProps) {
+  const parentRef = useRef<HTMLDivElement>(null)
+  const [jumpInput, setJumpInput] = useState('')
+  const [isJumping, setIsJumping] = useState(false)
+  const [highlightIndex, setHighlightIndex] = useState<number | null>(null)
+
+  // Refs avoid stale selection state in callbacks on rapid clicks (see FileList)
+  const selectedIdsRef = useRef(selectedIds)
+  const lastSelectedIdRef = useRef(lastSelectedId)
+  useEffect(() => {
+    selectedIdsRef.current = selectedIds
+  }, [selectedIds])
+  useEffect(() => {
+    lastSelectedIdRef.current = lastSelectedId
+  }, [lastSelectedId])
+
+  const getItem = useCallback((index: number): wailsapp.FileItemDTO | undefined => {
+    return pages.get(Math.floor(index / pageSize))?.[index % pageSize]
+  }, [pages, pageSize])
+
+  const rowVirtualizer = useVirtualizer({
+    count: Math.max(0, total),
+    getScrollElement: () => parentRef.current,
+    estimateSize: () => ROW_HEIGHT,
+    overscan: 10,
+  })
+
+  // Ask for the pages behind the visible rows whenever the viewport moves
+  const virtualItems = rowVirtualizer.getVirtualItems()
+  const firstVisible = virtualItems.length > 0 ? virtualItems[0].index : 0
+  const lastVisible = virtualItems.length > 0 ? virtualItems[virtualItems.length - 1].index : 0
+  useEffect(() => {
+    if (total > 0) {
+      onRangeChange(firstVisible, lastVisible)
+    }
+  }, [firstVisible, lastVisible, total, onRangeChange])
+
+  // Back to the top when the listing is replaced (navigation or re-sort)
+  useEffect(() => {
+    if (total < 0) {
+      parentRef.current?.scrollTo({ top: 0 })
+      setHighlightIndex(null)
+    }
+  }, [total])
+
+  const handleSort = useCallback((field: SortField) => {
+    if (sortField === field) {
+      onSortChange(field, sortDirection === 'asc' ? 'desc' : 'asc')
+    } else {
+      onSortChange(field, 'asc')
+    }
+  }, [sortField, sortDirection, onSortChange])
+
+  const handleRowClick = useCallback((e: React.MouseEvent, item: wailsapp.FileItemDTO, index: number) => {
+    const currentSelectedIds = selectedIdsRef.current
+    const currentLastSelectedId = lastSelectedIdRef.current
+    const newSelection = new Set(currentSelectedIds)
+    let lastId: string | null = item.id
+
+    if (e.shiftKey && currentLastSelectedId) {
+      // Range selection over loaded rows; rows on pages not yet fetched are skipped
+      let anchor = -1
+      for (const [page, items] of pages) {
+        const i = items.findIndex(it => it.id === currentLastSelectedId)
+        if (i >= 0) {
+          anchor = page * pageSize + i
+          break
+        }
+      }
+      if (anchor >= 0) {
+        for (let i = Math.min(anchor, index); i <= Math.max(anchor, index); i++) {
+          const rangeItem = getItem(i)
+          if (rangeItem) newSelection.add(rangeItem.id)
+        }
+      } else {
+        newSelection.add(item.id)
+      }
+    } else if (newSelection.has(item.id)) {
+      // Plain and Cmd/Ctrl click both toggle, matching FileList
+      newSelection.delete(item.id)
+      lastId = null
+    } else {
+      newSelection.add(item.id)
+    }
+
+    onSelectionChange(newSelection, lastId)
+  }, [pages, pageSize, getItem, onSelectionChange])
+
+  const handleCheckboxChange = useCallback((item: wailsapp.FileItemDTO, checked: boolean) => {
+    const newSelection = new Set(selectedIdsRef.current)
+    if (checked) {
+      newSelection.add(item.id)
+    } else {
+      newSelection.delete(item.id)
+    }
+    onSelectionChange(newSelection, checked ? item.id : null)
+  }, [onSelectionChange])
+
+  const handleJump = useCallback(async () => {
+    if (!jumpInput.trim()) return
+    setIsJumping(true)
+    try {
+      const index = await onJumpToName(jumpInput)
+      if (index !== null) {
+        rowVirtualizer.scrollToIndex(index, { align: 'start' })
+        setHighlightIndex(index)
+      }
+    } finally {
+      setIsJumping(false)
+    }
+  }, [jumpInput, onJumpToName, rowVirtualizer])
+
+  const SortIndicator = ({ field }: { field: SortField }) => {
+    if (sortField !== field) return null
+    return sortDirection === 'asc'
+      ? <ArrowUpIcon className="w-3 h-3 inline ml-1" />
+      : <ArrowDownIcon className="w-3 h-3 inline ml-1" />
+  }
+
+  if (isLoading && total < 0) {
+    return (
+      <div className="flex items-center justify-center h-full text-gray-500">
+        <div className="animate-pulse">{loadingMessage}</div>
+      </div>
+    )
+  }
+
+  if (error && total <= 0) {
+    return (
+      <div className="flex items-center justify-center h-full text-red-500 p-4 text-center">
+        {error}
+      </div>
+    )
+  }
+
+  if (total === 0) {
+    return (
+      <div className="flex items-center justify-center h-full text-gray-500">
+        {emptyMessage}
+      </div>
+    )
+  }
+
+  return (
+    <div className="flex flex-col h-full border border-gray-200 dark:border-gray-700 rounded">
+      {/* Header */}
+      <div className="flex items-center bg-gray-50 dark:bg-gray-800 border-b border-gray-200 dark:border-gray-700 text-sm font-medium text-gray-600 dark:text-gray-300 px-2 py-1 flex-shrink-0">
+        <span className="w-8 flex-shrink-0" />
+        <button
+          className="flex-1 text-left hover:text-gray-900 dark:hover:text-white cursor-pointer"
+          onClick={() => handleSort('name')}
+        >
+          Name <SortIndicator field="name" />
+        </button>
+        <button
+          className="w-24 text-right hover:text-gray-900 dark:hover:text-white cursor-pointer"
+          onClick={() => handleSort('size')}
+        >
+          Size <SortIndicator field="size" />
+        </button>
+        <button
+          className="w-48 text-right hover:text-gray-900 dark:hover:text-white cursor-pointer"
+          onClick={() => handleSort('modTime')}
+        >
+          Modified <SortIndicator field="modTime" />
+        </button>
+      </div>
+
+      {/* Virtual scrolling list over the whole folder */}
+      <div ref={parentRef} className="flex-1 overflow-auto">
+        <div
+          style={{
+            height: `${rowVirtualizer.getTotalSize()}px`,
+            width: '100%',
+            position: 'relative',
+          }}
+        >
+          {virtualItems.map((virtualRow) => {
+            const item = getItem(virtualRow.index)
+            const rowStyle = {
+              height: `${virtualRow.size}px`,
+              transform: `translateY(${virtualRow.start}px)`,
+            }
+
+            if (!item) {
+              return (
+                <div
+                  key={`pending-${virtualRow.index}`}
+                  className="absolute top-0 left-0 w-full flex items-center px-2 py-1 text-sm"
+                  style={rowStyle}
+                >
+                  <span className="w-8 flex-shrink-0" />
+                  <span className="h-3 w-1/3 rounded bg-gray-200 dark:bg-gray-700 animate-pulse" />
+                </div>
+              )
+            }
+
+            const isSelected = selectedIds.has(item.id)
+            return (
+              <div
+                key={item.id}
+                className={clsx(
+                  'absolute top-0 left-0 w-full flex items-center px-2 py-1 cursor-pointer text-sm',
+                  'hover:bg-blue-50 dark:hover:bg-blue-900/30',
+                  isSelected && 'bg-blue-100 dark:bg-blue-800/50',
+                  !isSelected && highlightIndex === virtualRow.index && 'bg-yellow-50 dark:bg-yellow-900/30'
+                )}
+                style={rowStyle}
+                onClick={(e) => handleRowClick(e, item, virtualRow.index)}
+                onDoubleClick={() => item.isFolder && onFolderOpen(item)}
+              >
+                <span className="w-8 flex-shrink-0 flex items-center justify-center">
+                  <input
+                    type="checkbox"
+                    checked={isSelected}
+                    onChange={(e) => {
+                      e.stopPropagation()
+                      handleCheckboxChange(item, e.target.checked)
+                    }}
+                    onClick={(e) => e.stopPropagation()}
+                    className="h-4 w-4 rounded border border-gray-300 text-rescale-blue focus:ring-rescale-blue focus:ring-2 bg-white cursor-pointer"
+                  />
+                </span>
+
+                <span className="w-5 h-5 mr-2 flex-shrink-0">
+                  {item.isFolder ? (
+                    <FolderIcon className="w-5 h-5 text-yellow-500" />
+                  ) : (
+                    <DocumentIcon className="w-5 h-5 text-gray-400" />
+                  )}
+                </span>
+
+                <span className="flex-1 truncate text-gray-900 dark:text-gray-100">
+                  {item.name}
+                </span>
+
+                <span className="w-24 text-right text-gray-500 dark:text-gray-400 flex-shrink-0">
+                  {item.isFolder ? '-' : formatSize(item.size ?? 0)}
+                </span>
+
+                <span className="w-48 text-right text-gray-500 dark:text-gray-400 flex-shrink-0 whitespace-nowrap">
+                  {formatDate(item.modTime ?? '')}
+                </span>
+              </div>
+            )
+          })}
+        </div>
+      </div>
+
+      {/* Footer with item count, jump-to-name and selection count */}
+      <div className="flex items-center justify-between gap-2 bg-gray-50 dark:bg-gray-800 border-t border-gray-200 dark:border-gray-700 px-2 py-1 text-xs text-gray-500 dark:text-gray-400 flex-shrink-0">
+        <span>
+          {Math.max(0, total).toLocaleString()} item{total !== 1 ? 's' : ''}
+        </span>
+
+        <div className="relative">
+          <MagnifyingGlassIcon className="absolute left-1.5 top-1/2 -translate-y-1/2 w-3 h-3 text-gray-400" />
+          <input
+            type="text"
+            value={jumpInput}
+            onChange={(e) => setJumpInput(e.target.value)}
+            onKeyDown={(e) => {
+              if (e.key === 'Enter') handleJump()
+            }}
+            disabled={isJumping}
+            placeholder="Jump to name..."
+            className="w-40 pl-5 pr-1 bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded"
+            title="Type the start of a name and press Enter (sorts by name)"
+          />
+        </div>
+
+        <div className="flex items-center gap-2">
+          {selectedIds.size > 0 && (
+            <span>{selectedIds.size} selected</span>
+          )}
+          {(isLoading || isJumping) && <span className="animate-pulse">Loading...</span>}
+        </div>
+      </div>
+    </div>
+  )
+}
@@ -4,12 +4,32 @@ export type { SortField, SortDirection } from './FileList'
 export { LocalBrowser } from './LocalBrowser'
 export { RemoteBrowser } from './RemoteBrowser'
 export { RemoteFilePicker } from './RemoteFilePicker'
+export { ContinueJobPanel } from './ContinueJobPanel'
+export { WindowedFileList } from './WindowedFileList'
 export { TemplateBuilder } from './TemplateBuilder'
+export { CoreTypePicker } from './CoreTypePicker'
+export { TeamTemplatesPanel } from './TeamTemplatesPanel'
 
 // Shared pipeline widgets
 export { StatusBadge } from './StatusBadge'
 export { StatsBar } from './StatsBar'
 export { JobsTable } from './JobsTable'
+export { ExportTableButton } from './ExportTableButton'
 export { PipelineStageSummary } from './PipelineStageSummary'
 export { PipelineLogPanel } from './PipelineLogPanel'
 export { ErrorSummary } from './ErrorSummary'
+export { JobDiffPanel } from './JobDiffPanel'
+export { TarContentsPanel } from './TarContentsPanel'
+export { LiveFilesPanel } from './LiveFilesPanel'
+export { BlackoutBanner } from './BlackoutBanner'
+
+// Notifications
+export { ToastStack, NotificationMuteMenu } from './NotificationToasts'
+
+// Footer
+export { NetworkUsageWidget } from './NetworkUsageWidget'
+
+// Settings widgets
+export { NetworkTestPanel } from './NetworkTestPanel'
+export { SelfUpdatePanel } from './SelfUpdatePanel'
+export { CacheUsagePanel } from './CacheUsagePanel'
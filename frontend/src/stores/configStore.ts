@@ -9,6 +9,9 @@ interface ConfigState {
   config: wailsapp.ConfigDTO | null;
   appInfo: wailsapp.AppInfoDTO | null;
   credentialSource: wailsapp.CredentialSourceDTO | null;
+  // Read-only viewer mode (viewer_mode or RESCALE_VIEWER_MODE): mutating
+  // controls are disabled; the backend refuses them regardless
+  viewerMode: boolean;
 
   // Connection state
   connectionStatus: 'unknown' | 'testing' | 'connected' | 'failed';
@@ -16,6 +19,8 @@ interface ConfigState {
   connectionFullName: string | null;
   workspaceId: string | null;
   workspaceName: string | null;
+  workspaces: wailsapp.WorkspaceDTO[];
+  isSwitchingWorkspace: boolean;
   connectionError: string | null;
   lastConnectionTest: Date | null;
 
@@ -33,6 +38,8 @@ interface ConfigState {
   clearSavedAPIKey: () => Promise<wailsapp.ClearSavedAPIKeyResultDTO>;
   loadConfigFromFile: (path: string) => Promise<void>;
   testConnection: () => Promise<void>;
+  fetchWorkspaces: () => Promise<void>;
+  switchWorkspace: (workspaceId: string) => Promise<void>;
   selectDirectory: (title: string) => Promise<string>;
   selectFile: (title: string) => Promise<string>;
 
@@ -41,16 +48,22 @@ interface ConfigState {
   _eventListenersSetup: boolean;
 }
 
+// Tooltip for controls disabled in viewer mode.
+export const VIEWER_MODE_TITLE = 'Disabled in viewer (read-only) mode';
+
 export const useConfigStore = create<ConfigState>((set, get) => ({
   // Initial state
   config: null,
   appInfo: null,
   credentialSource: null,
+  viewerMode: false,
   connectionStatus: 'unknown',
   connectionEmail: null,
   connectionFullName: null,
   workspaceId: null,
   workspaceName: null,
+  workspaces: [],
+  isSwitchingWorkspace: false,
   connectionError: null,
   lastConnectionTest: null,
   isLoading: false,
@@ -61,11 +74,12 @@ export const useConfigStore = create<ConfigState>((set, get) => ({
   fetchConfig: async () => {
     set({ isLoading: true, error: null });
     try {
-      const [config, credentialSource] = await Promise.all([
+      const [config, credentialSource, viewerMode] = await Promise.all([
         App.GetConfig(),
         App.GetCredentialSource(),
+        App.IsViewerMode(),
       ]);
-      set({ config, credentialSource, isLoading: false });
+      set({ config, credentialSource, viewerMode, isLoading: false });
     } catch (err) {
       set({
         error: err instanceof Error ? err.message : String(err),
@@ -131,7 +145,7 @@ export const useConfigStore = create<ConfigState>((set, get) => ({
       await App.UpdateConfig(config);
       await App.SaveConfig();
       await get().fetchCredentialSource();
-      set({ isSaving: false });
+      set({ viewerMode: await App.IsViewerMode(), isSaving: false });
     } catch (err) {
       set({
         error: err instanceof Error ? err.message : String(err),
@@ -227,6 +241,36 @@ export const useConfigStore = create<ConfigState>((set, get) => ({
     }
   },
 
+  fetchWorkspaces: async () => {
+    try {
+      const workspaces = await App.ListWorkspaces();
+      set({ workspaces: workspaces || [] });
+    } catch (err) {
+      // Single-workspace users may not be able to list; keep the name display
+      console.error('Failed to list workspaces:', err);
+      set({ workspaces: [] });
+    }
+  },
+
+  switchWorkspace: async (workspaceId: string) => {
+    set({ isSwitchingWorkspace: true, error: null });
+    try {
+      await App.SetWorkspace(workspaceId);
+      // Keep the edited config in step so the next UpdateConfig doesn't undo it
+      const { config } = get();
+      if (config) {
+        set({ config: new wailsapp.ConfigDTO({ ...config, workspace: workspaceId }) });
+      }
+      // Refreshes workspaceId/workspaceName, which the file browser follows
+      await get().testConnection();
+      await get().fetchWorkspaces();
+    } catch (err) {
+      set({ error: err instanceof Error ? err.message : String(err) });
+    } finally {
+      set({ isSwitchingWorkspace: false });
+    }
+  },
+
   selectDirectory: async (title: string) => {
     return App.SelectDirectory(title);
   },
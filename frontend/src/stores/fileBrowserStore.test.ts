@@ -67,6 +67,9 @@ function resetRemote() {
       pageCursors: [''],
       knownTotalPages: 1,
       pageCache: new Map(),
+      windowTotal: -1,
+      windowPages: new Map(),
+      windowSort: { field: 'name', desc: false },
     },
   })
 }
@@ -263,3 +266,198 @@ describe('remote trash browser', () => {
     expect(useFileBrowserStore.getState().remote.selection.selectedIds.size).toBe(0)
   })
 })
+
+describe('remote windowed listing', () => {
+  beforeEach(() => {
+    resetRemote()
+    vi.clearAllMocks()
+  })
+
+  function mockWindow(page: number, total: number, names: string[]): wailsapp.FolderWindowDTO {
+    return {
+      folderId: 'lib-folder-123',
+      page,
+      pageSize: 200,
+      total,
+      items: names.map(name => mockFileItem({ id: `id-${name}`, name })),
+      warning: '',
+    } as unknown as wailsapp.FolderWindowDTO
+  }
+
+  it('loads the first server-sorted page and records the folder total', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockResolvedValueOnce(mockWindow(0, 500000, ['a.dat', 'b.dat']))
+
+    await useFileBrowserStore.getState().loadRemoteFolder('lib-folder-123', 'My Library')
+
+    const s = useFileBrowserStore.getState().remote
+    expect(App.ListRemoteFolderWindow).toHaveBeenCalledWith('lib-folder-123', 'name', false, 0, 200)
+    expect(s.windowTotal).toBe(500000)
+    expect(s.windowPages.get(0)?.map(i => i.name)).toEqual(['a.dat', 'b.dat'])
+    expect(s.breadcrumb).toEqual([{ id: 'lib-folder-123', name: 'My Library' }])
+  })
+
+  it('fetches only the pages around the visible rows', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockResolvedValueOnce(mockWindow(0, 500000, ['a.dat']))
+    await useFileBrowserStore.getState().loadRemoteFolder('lib-folder-123', 'My Library')
+    vi.mocked(App.ListRemoteFolderWindow).mockClear()
+    vi.mocked(App.ListRemoteFolderWindow).mockImplementation((_id, _field, _desc, page) =>
+      Promise.resolve(mockWindow(page, 500000, [`row-${page}`])))
+
+    await useFileBrowserStore.getState().loadRemoteWindow(100000, 100030)
+
+    const pages = vi.mocked(App.ListRemoteFolderWindow).mock.calls.map(call => call[3])
+    expect(pages).toEqual([499, 500])
+    expect(useFileBrowserStore.getState().remote.windowPages.has(500)).toBe(true)
+  })
+
+  it('changing sort reloads from page 0 in the new order', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockResolvedValue(mockWindow(0, 3, ['c', 'b', 'a']))
+    useFileBrowserStore.setState((state) => ({
+      remote: { ...state.remote, currentFolderId: 'lib-folder-123' },
+    }))
+
+    await useFileBrowserStore.getState().setRemoteSort('size', true)
+
+    expect(App.ListRemoteFolderWindow).toHaveBeenCalledWith('lib-folder-123', 'size', true, 0, 200)
+    expect(useFileBrowserStore.getState().remote.windowSort).toEqual({ field: 'size', desc: true })
+  })
+
+  it('jump to name switches to name order and returns the server index', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockImplementation((_id, _field, _desc, page) =>
+      Promise.resolve(mockWindow(page, 1000, [`row-${page}`])))
+    vi.mocked(App.FindRemoteFolderItem).mockResolvedValueOnce(742)
+    useFileBrowserStore.setState((state) => ({
+      remote: {
+        ...state.remote,
+        currentFolderId: 'lib-folder-123',
+        windowSort: { field: 'modTime', desc: true },
+      },
+    }))
+
+    const index = await useFileBrowserStore.getState().jumpToRemoteName('run_07')
+
+    expect(index).toBe(742)
+    expect(App.FindRemoteFolderItem).toHaveBeenCalledWith('lib-folder-123', 'run_07', false, 200)
+    expect(useFileBrowserStore.getState().remote.windowSort).toEqual({ field: 'name', desc: false })
+    expect(useFileBrowserStore.getState().remote.windowPages.has(3)).toBe(true)
+  })
+})
+
+describe('remote browser tabs', () => {
+  beforeEach(() => {
+    resetRemote()
+    useFileBrowserStore.setState({ remoteTabs: [{ id: 'remote-0', state: null }], activeRemoteTabId: 'remote-0' })
+    vi.clearAllMocks()
+  })
+
+  function mockWindow(folderId: string, names: string[]): wailsapp.FolderWindowDTO {
+    return {
+      folderId,
+      page: 0,
+      pageSize: 200,
+      total: names.length,
+      items: names.map(name => mockFileItem({ id: `id-${name}`, name })),
+      warning: '',
+    } as unknown as wailsapp.FolderWindowDTO
+  }
+
+  it('keeps navigation and selection per tab', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockImplementation((folderId) =>
+      Promise.resolve(mockWindow(folderId, [`${folderId}-file`])))
+
+    // Tab 1: browse into a folder and select a file
+    await useFileBrowserStore.getState().loadRemoteFolder('lib-folder-123', 'My Library')
+    await useFileBrowserStore.getState().loadRemoteFolder('sim-1', 'sim-1')
+    useFileBrowserStore.getState().setRemoteSelection(new Set(['id-sim-1-file']))
+
+    // Tab 2 opens at My Library with nothing selected
+    useFileBrowserStore.getState().openRemoteTab()
+    await vi.waitFor(() => expect(useFileBrowserStore.getState().remote.isLoading).toBe(false))
+    let s = useFileBrowserStore.getState()
+    expect(s.remoteTabs).toHaveLength(2)
+    expect(s.remote.currentFolderId).toBe('lib-folder-123')
+    expect(s.remote.breadcrumb).toEqual([{ id: 'lib-folder-123', name: 'My Library' }])
+    expect(s.remote.selection.selectedIds.size).toBe(0)
+
+    // Back to tab 1: its folder and selection are restored
+    useFileBrowserStore.getState().switchRemoteTab('remote-0')
+    s = useFileBrowserStore.getState()
+    expect(s.remote.currentFolderId).toBe('sim-1')
+    expect(s.getRemoteSelectedItems().map(i => i.name)).toEqual(['sim-1-file'])
+  })
+
+  it('discards a load that finishes after its tab was parked', async () => {
+    let resolveSlow: (v: wailsapp.FolderWindowDTO) => void = () => {}
+    vi.mocked(App.ListRemoteFolderWindow).mockImplementationOnce(() =>
+      new Promise(resolve => { resolveSlow = resolve }))
+    const slow = useFileBrowserStore.getState().loadRemoteFolder('slow-folder', 'slow')
+
+    vi.mocked(App.ListRemoteFolderWindow).mockResolvedValue(mockWindow('lib-folder-123', ['lib-file']))
+    useFileBrowserStore.getState().openRemoteTab()
+    resolveSlow(mockWindow('slow-folder', ['slow-file']))
+    await slow
+
+    const s = useFileBrowserStore.getState()
+    expect(s.remote.currentFolderId).toBe('lib-folder-123')
+    expect(s.remote.items.map(i => i.name)).not.toContain('slow-file')
+  })
+
+  it('closing the active tab activates a neighbor; the last tab stays', () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockResolvedValue(mockWindow('lib-folder-123', []))
+    useFileBrowserStore.getState().openRemoteTab()
+    const second = useFileBrowserStore.getState().activeRemoteTabId
+
+    useFileBrowserStore.getState().closeRemoteTab(second)
+    let s = useFileBrowserStore.getState()
+    expect(s.remoteTabs.map(t => t.id)).toEqual(['remote-0'])
+    expect(s.activeRemoteTabId).toBe('remote-0')
+    expect(s.remoteTabs[0].state).toBeNull()
+
+    useFileBrowserStore.getState().closeRemoteTab('remote-0')
+    s = useFileBrowserStore.getState()
+    expect(s.remoteTabs).toHaveLength(1)
+  })
+})
+
+describe('remote browser workspaces', () => {
+  beforeEach(() => {
+    resetRemote()
+    useFileBrowserStore.setState({
+      remoteTabs: [{ id: 'remote-0', state: null }],
+      activeRemoteTabId: 'remote-0',
+      remoteWorkspaceId: null,
+      remoteWorkspaces: new Map(),
+    })
+    vi.clearAllMocks()
+  })
+
+  it('opens a new workspace at its own roots and restores the previous one', async () => {
+    vi.mocked(App.ListRemoteFolderWindow).mockImplementation((folderId) =>
+      Promise.resolve({
+        folderId, page: 0, pageSize: 200, total: 1,
+        items: [mockFileItem({ id: `id-${folderId}`, name: `${folderId}-file` })], warning: '',
+      } as unknown as wailsapp.FolderWindowDTO))
+
+    // The first workspace reported is the one already shown
+    useFileBrowserStore.getState().switchRemoteWorkspace('ws-a')
+    await useFileBrowserStore.getState().loadRemoteFolder('sim-1', 'sim-1')
+    let s = useFileBrowserStore.getState()
+    expect(s.remoteWorkspaceId).toBe('ws-a')
+    expect(s.remote.currentFolderId).toBe('sim-1')
+
+    // A workspace not browsed yet starts without root folder IDs
+    useFileBrowserStore.getState().switchRemoteWorkspace('ws-b')
+    s = useFileBrowserStore.getState()
+    expect(s.remote.myLibraryId).toBeNull()
+    expect(s.remote.items).toEqual([])
+    expect(s.remoteTabs).toHaveLength(1)
+
+    // Back to the first: its folder comes back and is reloaded
+    useFileBrowserStore.getState().switchRemoteWorkspace('ws-a')
+    await vi.waitFor(() => expect(useFileBrowserStore.getState().remote.isLoading).toBe(false))
+    s = useFileBrowserStore.getState()
+    expect(s.remote.currentFolderId).toBe('sim-1')
+    expect(s.remote.items.map(i => i.name)).toEqual(['sim-1-file'])
+    expect([...s.remoteWorkspaces.keys()]).toEqual(['ws-b'])
+  })
+})
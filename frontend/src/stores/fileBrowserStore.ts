@@ -10,6 +10,34 @@ export type BrowseMode = 'library' | 'jobs' | 'legacy' | 'trash'
 const PAGE_CACHE_TTL = 5 * 60 * 1000  // 5 minutes
 const MAX_CACHED_PAGES = 10           // Limit memory usage
 
+// Windowed listing for My Library / My Jobs folders. Pages are fetched by
+// number in server sort order, only around the visible rows, so folders with
+// 100k+ entries never load in full.
+export const WINDOW_PAGE_SIZE = 200
+const WINDOW_BUFFER_ROWS = 100         // Prefetch this many rows above/below the viewport
+const MAX_WINDOW_PAGES = 50            // Evict pages farthest from the viewport beyond this
+
+export type RemoteSortField = 'name' | 'size' | 'modTime'
+
+export interface RemoteSort {
+  field: RemoteSortField
+  desc: boolean
+}
+
+// Library and Jobs are folder trees and use the windowed listing;
+// Legacy and Trash keep cursor pagination.
+export function isWindowedMode(mode: BrowseMode): boolean {
+  return mode === 'library' || mode === 'jobs'
+}
+
+// Pages currently being fetched, keyed by `${navGeneration}:${page}`
+const windowRequests = new Set<string>()
+
+// Remote browser tabs. Tabs share the local pane and the signed-in account;
+// each keeps its own mode, folder, paging and selection.
+export const MAX_REMOTE_TABS = 8
+let nextRemoteTabId = 1
+
 // Cached page entry for fast back/forward navigation without re-fetching
 export interface CachedPage {
   items: wailsapp.FileItemDTO[]
@@ -45,6 +73,37 @@ interface LocalBrowserState {
   // backend calls.
   navGeneration: number
   selection: SelectionState
+  // Drive/mount/custom shortcuts shown in the sidebar
+  roots: wailsapp.LocalRootDTO[]
+  // Last visited path per root path, persisted to localStorage
+  rootLastPaths: Record<string, string>
+}
+
+// localStorage key for the last location visited under each local root
+const ROOT_LAST_PATHS_KEY = 'interlink.localRootLastPaths'
+
+function loadRootLastPaths(): Record<string, string> {
+  try {
+    const raw = localStorage.getItem(ROOT_LAST_PATHS_KEY)
+    return raw ? JSON.parse(raw) : {}
+  } catch {
+    return {}
+  }
+}
+
+// Returns the root whose path is the longest prefix of `path`, if any.
+export function findContainingRoot(
+  roots: wailsapp.LocalRootDTO[],
+  path: string
+): wailsapp.LocalRootDTO | undefined {
+  let best: wailsapp.LocalRootDTO | undefined
+  for (const root of roots) {
+    const prefix = /[\\/]$/.test(root.path) ? root.path : root.path + (path.includes('\\') ? '\\' : '/')
+    if (path === root.path || path.startsWith(prefix)) {
+      if (!best || root.path.length > best.path.length) best = root
+    }
+  }
+  return best
 }
 
 // Cancellation sentinel emitted by ListLocalDirectoryEx when a prior read
@@ -73,15 +132,54 @@ interface RemoteBrowserState {
   pageCursors: string[]          // Cursor for each page: [page0='', page1='...', page2='...']
   knownTotalPages: number        // Discovered page count (increments as user navigates forward)
   pageCache: Map<number, CachedPage>  // Cache by page number for fast back/forward
+  // Windowed listing (library/jobs modes)
+  windowTotal: number                            // Items in the folder; -1 until the first page arrives
+  windowPages: Map<number, wailsapp.FileItemDTO[]>  // Loaded pages by 0-based page number
+  windowSort: RemoteSort                         // Server-side sort order
+}
+
+// A remote browser tab. The active tab's state lives in `remote` so every
+// remote action operates on it; inactive tabs park their state here.
+export interface RemoteTab {
+  id: string
+  state: RemoteBrowserState | null  // null for the active tab
+}
+
+// The remote browser as left in another workspace, restored on switching back
+export interface RemoteWorkspaceView {
+  remote: RemoteBrowserState
+  remoteTabs: RemoteTab[]
+  activeRemoteTabId: string
+}
+
+// Tab title: the folder being browsed, or the view name before it loads
+export function remoteTabLabel(state: Pick<RemoteBrowserState, 'mode' | 'breadcrumb'>): string {
+  const last = state.breadcrumb[state.breadcrumb.length - 1]
+  if (last) return last.name
+  switch (state.mode) {
+    case 'jobs': return 'My Jobs'
+    case 'legacy': return 'Legacy Files'
+    case 'trash': return 'Trash'
+    default: return 'My Library'
+  }
 }
 
 interface FileBrowserStore {
   // Local browser state
   local: LocalBrowserState
 
-  // Remote browser state
+  // Remote browser state (the active tab)
   remote: RemoteBrowserState
 
+  // Remote browser tabs
+  remoteTabs: RemoteTab[]
+  activeRemoteTabId: string
+
+  // Workspace the remote browser shows (null until the first connection),
+  // and the views parked in other workspaces, by workspace ID
+  remoteWorkspaceId: string | null
+  remoteWorkspaces: Map<string, RemoteWorkspaceView>
+
   // Local browser actions
   loadLocalDirectory: (path?: string) => Promise<void>
   navigateLocalTo: (path: string) => void
@@ -89,6 +187,10 @@ interface FileBrowserStore {
   goLocalHome: () => void
   refreshLocal: () => void
   toggleShowHidden: () => void
+  loadLocalRoots: () => Promise<void>
+  openLocalRoot: (root: wailsapp.LocalRootDTO) => void
+  addLocalRoot: (path: string) => Promise<void>
+  removeLocalRoot: (path: string) => Promise<void>
   setLocalSelection: (ids: Set<string>, lastId?: string | null) => void
   clearLocalSelection: () => void
 
@@ -111,6 +213,13 @@ interface FileBrowserStore {
   setRemoteItemsPerPage: (size: number) => void       // Change page size, reload page 0
   goToNextRemotePage: () => Promise<void>             // Navigate to next page (replaces items)
   goToPreviousRemotePage: () => Promise<void>         // Navigate to previous page (from cache)
+  loadRemoteWindow: (start: number, end: number) => Promise<void>  // Fetch pages covering rows [start, end] ± buffer
+  setRemoteSort: (field: RemoteSortField, desc: boolean) => Promise<void>
+  jumpToRemoteName: (prefix: string) => Promise<number | null>     // Returns the row index to scroll to
+  openRemoteTab: () => void                           // New tab at My Library, made active
+  switchRemoteTab: (id: string) => void
+  closeRemoteTab: (id: string) => void                // The last tab cannot be closed
+  switchRemoteWorkspace: (workspaceId: string) => void // Park this workspace's tabs, restore or open the other's
 
   // Event listeners
   setupEventListeners: () => () => void
@@ -130,6 +239,8 @@ const initialLocalState: LocalBrowserState = {
   history: [],
   navGeneration: 0,
   selection: { selectedIds: new Set(), lastSelectedId: null },
+  roots: [],
+  rootLastPaths: loadRootLastPaths(),
 }
 
 const initialRemoteState: RemoteBrowserState = {
@@ -150,11 +261,18 @@ const initialRemoteState: RemoteBrowserState = {
   pageCursors: [''],           // First page has empty cursor
   knownTotalPages: 1,          // At least one page
   pageCache: new Map(),
+  windowTotal: -1,
+  windowPages: new Map(),
+  windowSort: { field: 'name', desc: false },
 }
 
 export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
   local: initialLocalState,
   remote: initialRemoteState,
+  remoteTabs: [{ id: 'remote-0', state: null }],
+  activeRemoteTabId: 'remote-0',
+  remoteWorkspaceId: null,
+  remoteWorkspaces: new Map(),
 
   // ===== LOCAL BROWSER ACTIONS =====
 
@@ -213,6 +331,15 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
           warning: warning && isSlowPath ? warning : null,
         },
       }))
+
+      // Remember where we are under the containing root
+      const { roots, rootLastPaths } = get().local
+      const root = findContainingRoot(roots, contents.folderPath)
+      if (root && rootLastPaths[root.path] !== contents.folderPath) {
+        const next = { ...rootLastPaths, [root.path]: contents.folderPath }
+        set(state => ({ local: { ...state.local, rootLastPaths: next } }))
+        try { localStorage.setItem(ROOT_LAST_PATHS_KEY, JSON.stringify(next)) } catch { /* ignore localStorage errors */ }
+      }
     } catch (error) {
       if (isStale()) return
       set(state => ({
@@ -276,6 +403,30 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
     get().loadLocalDirectory()
   },
 
+  loadLocalRoots: async () => {
+    try {
+      const roots = await App.GetLocalRoots()
+      set(state => ({ local: { ...state.local, roots: roots || [] } }))
+    } catch (error) {
+      console.error('Failed to load local roots:', error)
+    }
+  },
+
+  openLocalRoot: (root: wailsapp.LocalRootDTO) => {
+    const last = get().local.rootLastPaths[root.path]
+    get().navigateLocalTo(last || root.path)
+  },
+
+  addLocalRoot: async (path: string) => {
+    await App.AddLocalRoot(path)
+    await get().loadLocalRoots()
+  },
+
+  removeLocalRoot: async (path: string) => {
+    await App.RemoveLocalRoot(path)
+    await get().loadLocalRoots()
+  },
+
   setLocalSelection: (ids: Set<string>, lastId?: string | null) => {
     set(state => ({
       local: {
@@ -358,6 +509,8 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
         pageCursors: [''],
         knownTotalPages: 1,
         pageCache: new Map(),
+        windowTotal: -1,
+        windowPages: new Map(),
         navGeneration: state.remote.navGeneration + 1,
       }
     }))
@@ -375,115 +528,71 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
     }
   },
 
-  // If folderName is provided, this is a new navigation (resets pagination).
-  // Otherwise, reloads the current page. Items are REPLACED, not appended.
+  // If folderName is provided, this is a new navigation (updates the breadcrumb).
+  // Otherwise the current folder is reloaded. Either way the windowed listing
+  // restarts from page 0 in the current sort order; further pages are fetched
+  // by loadRemoteWindow as rows scroll into view.
   loadRemoteFolder: async (folderId?: string, folderName?: string) => {
     const state = get().remote
     const targetId = folderId ?? state.currentFolderId
     if (!targetId) return
 
     const isNewNavigation = folderName !== undefined
-    const currentPage = isNewNavigation ? 0 : state.currentPage
-    const itemsPerPage = state.itemsPerPage
-    const pageCursors = isNewNavigation ? [''] : state.pageCursors
-    const cursor = pageCursors[currentPage] ?? ''
 
-    // Use cached page for back/forward navigation to avoid redundant API calls
-    const cachedPage = state.pageCache.get(currentPage)
-    if (!isNewNavigation && cachedPage && (Date.now() - cachedPage.timestamp) < PAGE_CACHE_TTL) {
-      set(state => ({
-        remote: {
-          ...state.remote,
-          items: cachedPage.items,
-          hasMore: cachedPage.hasMore,
-          nextCursor: cachedPage.nextCursor,
-          isLoading: false,
-          error: null,
-        }
-      }))
-      return
-    }
+    // Stale response guard: a reload supersedes any in-flight page requests
+    const myGen = state.navGeneration + 1
 
-    // Stale response guard: capture generation before async call
-    const myGen = get().remote.navGeneration
+    let breadcrumb = state.breadcrumb
+    if (isNewNavigation) {
+      const existingIndex = breadcrumb.findIndex(b => b.id === targetId)
+      if (existingIndex >= 0) {
+        breadcrumb = breadcrumb.slice(0, existingIndex + 1)
+      } else {
+        breadcrumb = [...breadcrumb, { id: targetId, name: folderName! }]
+      }
+    }
 
     set(state => ({
-      remote: { ...state.remote, isLoading: true, error: null }
+      remote: {
+        ...state.remote,
+        currentFolderId: targetId,
+        breadcrumb,
+        isLoading: true,
+        error: null,
+        items: [],
+        // Keep the old total on refresh so the scroll height does not collapse
+        windowTotal: isNewNavigation ? -1 : state.remote.windowTotal,
+        windowPages: new Map(),
+        navGeneration: myGen,
+      }
     }))
 
+    const { windowSort } = get().remote
+    const requestKey = `${myGen}:0`
+    windowRequests.add(requestKey)
     try {
-      const contents = await App.ListRemoteFolderPage(targetId, cursor, itemsPerPage)
+      const page = await App.ListRemoteFolderWindow(targetId, windowSort.field, windowSort.desc, 0, WINDOW_PAGE_SIZE)
 
       // Stale response guard: discard if navigation changed during async call
       if (get().remote.navGeneration !== myGen) return
 
-      // Update breadcrumb only on new navigation
-      let breadcrumb = state.breadcrumb
-      if (isNewNavigation) {
-        const existingIndex = breadcrumb.findIndex(b => b.id === targetId)
-        if (existingIndex >= 0) {
-          breadcrumb = breadcrumb.slice(0, existingIndex + 1)
-        } else {
-          breadcrumb = [...breadcrumb, { id: targetId, name: folderName! }]
-        }
-      }
-
-      const newPageCursors = [...pageCursors]
-      if (contents.hasMore && contents.nextCursor) {
-        newPageCursors[currentPage + 1] = contents.nextCursor
-      }
-
-      const newCache = new Map(state.pageCache)
-      newCache.set(currentPage, {
-        items: contents.items,
-        hasMore: contents.hasMore,
-        nextCursor: contents.nextCursor ?? '',
-        timestamp: Date.now(),
-      })
-      // Limit cache size
-      if (newCache.size > MAX_CACHED_PAGES) {
-        const oldestKey = newCache.keys().next().value
-        if (oldestKey !== undefined) {
-          newCache.delete(oldestKey)
-        }
-      }
-
-      let knownTotalPages = isNewNavigation ? 1 : state.knownTotalPages
-      if (contents.hasMore && currentPage + 1 >= knownTotalPages) {
-        knownTotalPages = currentPage + 2  // We know at least one more page exists
-      } else if (!contents.hasMore) {
-        knownTotalPages = currentPage + 1  // This is the last page
+      if (page.warning) {
+        set(state => ({
+          remote: { ...state.remote, isLoading: false, error: page.warning!, windowTotal: 0 }
+        }))
+        return
       }
 
       set(state => ({
         remote: {
           ...state.remote,
-          currentFolderId: targetId,
-          items: contents.items,  // Replace (not append) — each page is a standalone view
+          items: page.items,
           isLoading: false,
           error: null,
-          hasMore: contents.hasMore,
-          nextCursor: contents.nextCursor ?? '',
-          breadcrumb,
-          currentPage,
-          pageCursors: newPageCursors,
-          knownTotalPages,
-          pageCache: isNewNavigation ? new Map() : newCache,  // Clear cache on new navigation
+          windowTotal: page.total,
+          windowPages: new Map([[0, page.items]]),
         }
       }))
-
-      if (isNewNavigation) {
-        const freshCache = new Map<number, CachedPage>()
-        freshCache.set(0, {
-          items: contents.items,
-          hasMore: contents.hasMore,
-          nextCursor: contents.nextCursor ?? '',
-          timestamp: Date.now(),
-        })
-        set(state => ({
-          remote: { ...state.remote, pageCache: freshCache }
-        }))
-      }
     } catch (error) {
       // Stale response guard: discard if navigation changed during async call
       if (get().remote.navGeneration !== myGen) return
@@ -494,9 +603,227 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
           error: error instanceof Error ? error.message : String(error),
         }
       }))
+    } finally {
+      windowRequests.delete(requestKey)
+    }
+  },
+
+  loadRemoteWindow: async (start: number, end: number) => {
+    const { mode, currentFolderId, windowTotal, windowPages, windowSort, navGeneration } = get().remote
+    if (!isWindowedMode(mode) || !currentFolderId || windowTotal <= 0) return
+
+    const lastPage = Math.ceil(windowTotal / WINDOW_PAGE_SIZE) - 1
+    const firstNeeded = Math.max(0, Math.floor((start - WINDOW_BUFFER_ROWS) / WINDOW_PAGE_SIZE))
+    const lastNeeded = Math.min(lastPage, Math.floor((end + WINDOW_BUFFER_ROWS) / WINDOW_PAGE_SIZE))
+
+    const missing: number[] = []
+    for (let page = firstNeeded; page <= lastNeeded; page++) {
+      const key = `${navGeneration}:${page}`
+      if (!windowPages.has(page) && !windowRequests.has(key)) {
+        windowRequests.add(key)
+        missing.push(page)
+      }
+    }
+    if (missing.length === 0) return
+
+    const center = Math.floor((start + end) / 2 / WINDOW_PAGE_SIZE)
+
+    await Promise.all(missing.map(async (page) => {
+      try {
+        const result = await App.ListRemoteFolderWindow(currentFolderId, windowSort.field, windowSort.desc, page, WINDOW_PAGE_SIZE)
+        if (get().remote.navGeneration !== navGeneration) return
+        if (result.warning) {
+          set(state => ({ remote: { ...state.remote, error: result.warning! } }))
+          return
+        }
+
+        set(state => {
+          const pages = new Map(state.remote.windowPages)
+          pages.set(page, result.items)
+
+          // Evict pages farthest from the viewport, but never ones holding
+          // selected items (getRemoteSelectedItems reads from loaded pages)
+          if (pages.size > MAX_WINDOW_PAGES) {
+            const selected = state.remote.selection.selectedIds
+            const evictable = Array.from(pages.keys())
+              .filter(p => !pages.get(p)!.some(item => selected.has(item.id)))
+              .sort((a, b) => Math.abs(b - center) - Math.abs(a - center))
+            for (const p of evictable.slice(0, pages.size - MAX_WINDOW_PAGES)) {
+              pages.delete(p)
+            }
+          }
+
+          return {
+            remote: {
+              ...state.remote,
+              windowPages: pages,
+              // The folder may have grown or shrunk since page 0 was fetched
+              windowTotal: result.total,
+            }
+          }
+        })
+      } catch (error) {
+        console.error(`Failed to load remote page ${page}:`, error)
+      } finally {
+        windowRequests.delete(`${navGeneration}:${page}`)
+      }
+    }))
+  },
+
+  setRemoteSort: async (field: RemoteSortField, desc: boolean) => {
+    const { windowSort } = get().remote
+    if (windowSort.field === field && windowSort.desc === desc) return
+    set(state => ({
+      remote: {
+        ...state.remote,
+        windowSort: { field, desc },
+        windowTotal: -1,
+      }
+    }))
+    await get().loadRemoteFolder()
+  },
+
+  jumpToRemoteName: async (prefix: string) => {
+    const trimmed = prefix.trim()
+    const { mode, currentFolderId } = get().remote
+    if (!trimmed || !isWindowedMode(mode) || !currentFolderId) return null
+
+    // Jumping by name needs name order; switch to ascending name order if needed
+    if (get().remote.windowSort.field !== 'name') {
+      await get().setRemoteSort('name', false)
+    }
+
+    const { windowSort, navGeneration } = get().remote
+    try {
+      const index = await App.FindRemoteFolderItem(currentFolderId, trimmed, windowSort.desc, WINDOW_PAGE_SIZE)
+      if (get().remote.navGeneration !== navGeneration) return null
+
+      const total = get().remote.windowTotal
+      const clamped = Math.max(0, Math.min(index, total - 1))
+      await get().loadRemoteWindow(clamped, clamped)
+      return clamped
+    } catch (error) {
+      set(state => ({
+        remote: {
+          ...state.remote,
+          error: error instanceof Error ? error.message : String(error),
+        }
+      }))
+      return null
+    }
+  },
+
+  // ===== REMOTE TABS =====
+
+  openRemoteTab: () => {
+    const { remote, remoteTabs, activeRemoteTabId } = get()
+    if (remoteTabs.length >= MAX_REMOTE_TABS) return
+
+    const id = `remote-${nextRemoteTabId++}`
+    set({
+      remoteTabs: [
+        ...remoteTabs.map(t => t.id === activeRemoteTabId ? { ...t, state: remote } : t),
+        { id, state: null },
+      ],
+      activeRemoteTabId: id,
+      remote: {
+        ...initialRemoteState,
+        myLibraryId: remote.myLibraryId,
+        myJobsId: remote.myJobsId,
+        itemsPerPage: remote.itemsPerPage,
+        // Stale response guard: the parked tab's in-flight responses are discarded
+        navGeneration: remote.navGeneration + 1,
+      },
+    })
+
+    if (remote.myLibraryId) {
+      get().loadRemoteFolder(remote.myLibraryId, 'My Library')
+    } else {
+      get().initRemote()
     }
   },
 
+  switchRemoteTab: (id: string) => {
+    const { remote, remoteTabs, activeRemoteTabId } = get()
+    if (id === activeRemoteTabId) return
+    const target = remoteTabs.find(t => t.id === id)
+    if (!target?.state) return
+
+    const parked = target.state
+    set({
+      remoteTabs: remoteTabs.map(t => {
+        if (t.id === activeRemoteTabId) return { ...t, state: remote }
+        if (t.id === id) return { ...t, state: null }
+        return t
+      }),
+      activeRemoteTabId: id,
+      // Generations only grow, so responses started in either tab before the
+      // switch can never match the live generation
+      remote: { ...parked, navGeneration: Math.max(remote.navGeneration, parked.navGeneration) + 1 },
+    })
+
+    // A load interrupted by parking the tab was discarded; fetch it again
+    if (parked.isLoading) {
+      get().refreshRemote()
+    }
+  },
+
+  closeRemoteTab: (id: string) => {
+    const { remoteTabs, activeRemoteTabId } = get()
+    if (remoteTabs.length <= 1) return
+    const index = remoteTabs.findIndex(t => t.id === id)
+    if (index < 0) return
+
+    if (id === activeRemoteTabId) {
+      const neighbor = remoteTabs[index + 1] ?? remoteTabs[index - 1]
+      get().switchRemoteTab(neighbor.id)
+    }
+    set(state => ({ remoteTabs: state.remoteTabs.filter(t => t.id !== id) }))
+  },
+
+  switchRemoteWorkspace: (workspaceId: string) => {
+    const { remote, remoteTabs, activeRemoteTabId, remoteWorkspaceId, remoteWorkspaces } = get()
+    if (workspaceId === remoteWorkspaceId) return
+    // The first workspace reported is the one the browser has been showing
+    if (remoteWorkspaceId === null) {
+      set({ remoteWorkspaceId: workspaceId })
+      return
+    }
+
+    const views = new Map(remoteWorkspaces)
+    views.set(remoteWorkspaceId, { remote, remoteTabs, activeRemoteTabId })
+    const view = views.get(workspaceId)
+    views.delete(workspaceId)
+    // Responses still in flight from the other workspace must not land here
+    const navGeneration = Math.max(remote.navGeneration, view?.remote.navGeneration ?? 0) + 1
+
+    if (view) {
+      set({
+        remoteWorkspaceId: workspaceId,
+        remoteWorkspaces: views,
+        remoteTabs: view.remoteTabs,
+        activeRemoteTabId: view.activeRemoteTabId,
+        remote: { ...view.remote, navGeneration },
+      })
+      // The folder may have changed while away
+      if (view.remote.myLibraryId) {
+        get().refreshRemote()
+      }
+      return
+    }
+
+    // Never browsed here: one tab at this workspace's My Library, which the
+    // remote browser loads once it sees no root folder IDs
+    const id = `remote-${nextRemoteTabId++}`
+    set({
+      remoteWorkspaceId: workspaceId,
+      remoteWorkspaces: views,
+      remoteTabs: [{ id, state: null }],
+      activeRemoteTabId: id,
+      remote: { ...initialRemoteState, itemsPerPage: remote.itemsPerPage, navGeneration },
+    })
+  },
+
   loadRemoteLegacy: async () => {
     const state = get().remote
     const currentPage = state.currentPage
@@ -902,11 +1229,13 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
   setupEventListeners: () => {
     const handleConfigChanged = () => {
       console.log('[FileBrowser] Config changed, invalidating remote cache')
+      // Parked tabs hold the old account's folders; start over with one tab
       set(state => ({
         remote: {
           ...initialRemoteState,
           navGeneration: state.remote.navGeneration + 1,
-        }
+        },
+        remoteTabs: [{ id: state.activeRemoteTabId, state: null }],
       }))
       get().initRemote()
     }
@@ -922,7 +1251,8 @@ export const useFileBrowserStore = create<FileBrowserStore>((set, get) => ({
   },
 
   getRemoteSelectedItems: () => {
-    const { items, selection } = get().remote
-    return items.filter(item => selection.selectedIds.has(item.id))
+    const { items, selection, mode, windowPages } = get().remote
+    const loaded = isWindowedMode(mode) ? Array.from(windowPages.values()).flat() : items
+    return loaded.filter(item => selection.selectedIds.has(item.id))
   },
 }))
@@ -1,10 +1,10 @@
 // Re-export all stores for convenient imports
-export { useConfigStore } from './configStore';
+export { useConfigStore, VIEWER_MODE_TITLE } from './configStore';
 export { useLogStore } from './logStore';
-export { useFileBrowserStore } from './fileBrowserStore';
-export type { BrowseMode, SelectionState, BreadcrumbEntry } from './fileBrowserStore';
+export { useFileBrowserStore, isWindowedMode, remoteTabLabel, WINDOW_PAGE_SIZE, MAX_REMOTE_TABS } from './fileBrowserStore';
+export type { BrowseMode, SelectionState, BreadcrumbEntry, RemoteSortField, RemoteTab } from './fileBrowserStore';
 export { useTransferStore, classifyError, extractDiskSpaceInfo, formatSpeed, formatETA } from './transferStore';
-export type { TransferTask, TransferBatch, TransferState, TransferStats, TransferErrorType, Enumeration } from './transferStore';
+export type { TransferTask, TransferBatch, TransferState, TransferStats, TransferErrorType, FailedSummary, Enumeration } from './transferStore';
 export { useJobStore, DEFAULT_JOB_TEMPLATE } from './jobStore';
 export type {
   WorkflowState,
@@ -27,5 +27,9 @@ export type {
 export { useRunStore } from './runStore';
 export { useSingleJobStore } from './singleJobStore';
 
+// Job notification toasts
+export { useNotificationStore, classifyStateChange } from './notificationStore';
+export type { Toast, NotificationSeverity, MuteSettings } from './notificationStore';
+
 // Error report store
 export { useErrorReportStore } from './errorReportStore';
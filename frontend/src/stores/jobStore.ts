@@ -22,6 +22,15 @@ export interface CoreType {
   displayOrder: number
   isActive: boolean
   cores: number[]
+  // Hardware details and filter classes; absent when the platform does not
+  // report them
+  memoryPerCoreGb?: number
+  processor?: string
+  interconnect?: string
+  interconnectClass?: string
+  architecture?: string
+  price?: number
+  priceBand?: string
 }
 
 // Analysis code from API
@@ -78,6 +87,9 @@ export interface ScanOptions {
   // Subdirectory within each Run_* to tar
   tarSubpath: string
 
+  // Comma-separated output file patterns downloaded into each job's directory on completion
+  outputPatterns: string
+
   // Vary command across runs (iterate numeric patterns)
   iteratePatterns: boolean
 }
@@ -140,6 +152,7 @@ interface JobStore {
 
   // API cache
   coreTypes: CoreType[]
+  coreTypeFavorites: string[]
   analysisCodes: AnalysisCode[]
   automations: Automation[]
   isLoadingCoreTypes: boolean
@@ -179,6 +192,7 @@ interface JobStore {
   // Actions - Validation
   validateJobs: () => Promise<string[]>
   updateJobRow: (index: number, updates: Partial<JobRow>) => void
+  setJobPriority: (index: number, priority: number) => void
 
   // Actions - Execution
   startBulkRun: () => Promise<string | null>
@@ -194,6 +208,8 @@ interface JobStore {
 
   // Actions - API Cache
   fetchCoreTypes: () => Promise<void>
+  fetchCoreTypeFavorites: () => Promise<void>
+  toggleCoreTypeFavorite: (code: string) => Promise<void>
   fetchAnalysisCodes: (search?: string) => Promise<void>
   fetchAutomations: () => Promise<void>
 
@@ -251,6 +267,7 @@ export const useJobStore = create<JobStore>((set, get) => ({
   },
 
   coreTypes: [],
+  coreTypeFavorites: [],
   analysisCodes: [],
   automations: [],
   isLoadingCoreTypes: false,
@@ -277,6 +294,7 @@ export const useJobStore = create<JobStore>((set, get) => ({
     primaryPattern: '*.inp',
     secondaryPatterns: [],
     tarSubpath: '',
+    outputPatterns: '',
     iteratePatterns: false,
   },
   isScanning: false,
@@ -425,6 +443,10 @@ export const useJobStore = create<JobStore>((set, get) => ({
           primaryPattern: scanOptions.primaryPattern,
           secondaryPatterns: secondaryPatternsDTO,
           tarSubpath: scanOptions.tarSubpath,
+          outputPatterns: scanOptions.outputPatterns
+            .split(',')
+            .map((p) => p.trim())
+            .filter(Boolean),
           iteratePatterns: scanOptions.iteratePatterns,
         } as wailsapp.ScanOptionsDTO,
         template as wailsapp.JobSpecDTO
@@ -501,6 +523,16 @@ export const useJobStore = create<JobStore>((set, get) => ({
     })
   },
 
+  setJobPriority: (index, priority) => {
+    set((state) => {
+      const scannedJobs = [...state.scannedJobs]
+      if (index >= 0 && index < scannedJobs.length) {
+        scannedJobs[index] = { ...scannedJobs[index], priority }
+      }
+      return { scannedJobs }
+    })
+  },
+
   // Execution Actions
   startBulkRun: async () => {
     const { scannedJobs, jobRows } = get()
@@ -607,6 +639,13 @@ export const useJobStore = create<JobStore>((set, get) => ({
         projectId: job.projectId,
         orgCode: job.orgCode || '',
         automations: job.automations || [],
+        priority: job.priority || 0,
+        destinationFolder: job.destinationFolder || '',
+        continuationCommand: job.continuationCommand || '',
+        metadata: job.metadata || {},
+        envVars: job.envVars || {},
+        useRescaleLicense: job.useRescaleLicense || false,
+        clusterId: job.clusterId || '',
       }))
 
       // Create job rows from the loaded jobs
@@ -668,6 +707,12 @@ export const useJobStore = create<JobStore>((set, get) => ({
         projectId: job.projectId,
         orgCode: job.orgCode || '',
         automations: job.automations || [],
+        destinationFolder: job.destinationFolder || '',
+        continuationCommand: job.continuationCommand || '',
+        metadata: job.metadata || {},
+        envVars: job.envVars || {},
+        useRescaleLicense: job.useRescaleLicense || false,
+        clusterId: job.clusterId || '',
       } as JobSpec
     } catch (error) {
       console.error('Failed to load job from JSON:', error)
@@ -702,6 +747,12 @@ export const useJobStore = create<JobStore>((set, get) => ({
         projectId: job.projectId,
         orgCode: job.orgCode || '',
         automations: job.automations || [],
+        destinationFolder: job.destinationFolder || '',
+        continuationCommand: job.continuationCommand || '',
+        metadata: job.metadata || {},
+        envVars: job.envVars || {},
+        useRescaleLicense: job.useRescaleLicense || false,
+        clusterId: job.clusterId || '',
       } as JobSpec
     } catch (error) {
       console.error('Failed to load job from SGE:', error)
@@ -730,6 +781,13 @@ export const useJobStore = create<JobStore>((set, get) => ({
         displayOrder: ct.displayOrder,
         isActive: ct.isActive,
         cores: ct.cores || [],
+        memoryPerCoreGb: ct.memoryPerCoreGb,
+        processor: ct.processor,
+        interconnect: ct.interconnect,
+        interconnectClass: ct.interconnectClass,
+        architecture: ct.architecture,
+        price: ct.price,
+        priceBand: ct.priceBand,
       }))
       set({ coreTypes: mapped, coreTypesError: null })
     } catch (error) {
@@ -741,6 +799,25 @@ export const useJobStore = create<JobStore>((set, get) => ({
     }
   },
 
+  fetchCoreTypeFavorites: async () => {
+    try {
+      const favorites = await App.GetCoreTypeFavorites()
+      set({ coreTypeFavorites: favorites || [] })
+    } catch (error) {
+      console.error('Failed to load core type favorites:', error)
+    }
+  },
+
+  toggleCoreTypeFavorite: async (code: string) => {
+    const starred = get().coreTypeFavorites.includes(code)
+    try {
+      const favorites = await App.SetCoreTypeFavorite(code, !starred)
+      set({ coreTypeFavorites: favorites || [] })
+    } catch (error) {
+      console.error('Failed to save core type favorite:', error)
+    }
+  },
+
   fetchAnalysisCodes: async (search = '') => {
     set({ isLoadingAnalysisCodes: true, analysisCodesError: null })
     try {
@@ -23,6 +23,8 @@ interface LogEntry {
   stage: string;
   jobName: string;
   error?: string;
+  runId?: string;
+  transferId?: string;
   // Cached for performance (matching Fyne optimization)
   formattedText: string;
   lowerText: string;
@@ -66,6 +68,7 @@ interface LogState {
   // Filters
   levelFilter: LogLevel | null;
   searchTerm: string;
+  correlationFilter: string; // Run or transfer ID; '' shows everything
   autoScroll: boolean;
 
   // Overall progress (from progress events)
@@ -76,6 +79,7 @@ interface LogState {
   addLog: (event: LogEventDTO) => void;
   setLevelFilter: (level: LogLevel | null) => void;
   setSearchTerm: (term: string) => void;
+  setCorrelationFilter: (id: string) => void;
   setAutoScroll: (enabled: boolean) => void;
   clearLogs: () => void;
   getFilteredLogs: () => LogEntry[];
@@ -103,9 +107,22 @@ function formatLogEntry(entry: LogEventDTO): string {
 
   parts.push(entry.message);
 
+  if (entry.runId) {
+    parts.push(`[run=${entry.runId}]`);
+  }
+
+  if (entry.transferId) {
+    parts.push(`[transfer=${entry.transferId}]`);
+  }
+
   return parts.join(' ');
 }
 
+// Check if a log entry belongs to the run or transfer id ('' matches all)
+export function matchesCorrelation(entry: { runId?: string; transferId?: string }, id: string): boolean {
+  return !id || entry.runId === id || entry.transferId === id;
+}
+
 // Check if a level is WARN or ERROR (routes to protected tier)
 function isWarnOrError(level: string): boolean {
   return level === 'WARN' || level === 'ERROR';
@@ -122,6 +139,7 @@ export const useLogStore = create<LogState>((set, get) => ({
   startTime: new Date(),
   levelFilter: 'INFO' as LogLevel,
   searchTerm: '',
+  correlationFilter: '',
   autoScroll: true,
   overallProgress: 0,
   overallMessage: 'Ready',
@@ -136,6 +154,8 @@ export const useLogStore = create<LogState>((set, get) => ({
       stage: event.stage,
       jobName: event.jobName,
       error: event.error,
+      runId: event.runId,
+      transferId: event.transferId,
       formattedText,
       lowerText: formattedText.toLowerCase(),
     };
@@ -197,6 +217,10 @@ export const useLogStore = create<LogState>((set, get) => ({
     set({ searchTerm: term });
   },
 
+  setCorrelationFilter: (id) => {
+    set({ correlationFilter: id.trim() });
+  },
+
   setAutoScroll: (enabled) => {
     set({ autoScroll: enabled });
   },
@@ -217,7 +241,7 @@ export const useLogStore = create<LogState>((set, get) => ({
   },
 
   getFilteredLogs: () => {
-    const { debugInfoLogs, warnErrorLogs, levelFilter, searchTerm } = get();
+    const { debugInfoLogs, warnErrorLogs, levelFilter, searchTerm, correlationFilter } = get();
     const lowerSearch = searchTerm.toLowerCase();
 
     // Optimization: when filter >= WARN, skip DEBUG/INFO tier entirely
@@ -238,6 +262,10 @@ export const useLogStore = create<LogState>((set, get) => ({
         return false;
       }
 
+      if (!matchesCorrelation(log, correlationFilter)) {
+        return false;
+      }
+
       return true;
     });
   },
@@ -0,0 +1,40 @@
+import { describe, it, expect } from 'vitest'
+import { classifyStateChange } from './notificationStore'
+import type { StateChangeEventDTO } from '../types/events'
+
+function event(overrides: Partial<StateChangeEventDTO> = {}): StateChangeEventDTO {
+  return {
+    timestamp: '',
+    jobName: 'Run_1',
+    oldStatus: '',
+    newStatus: '',
+    stage: '',
+    uploadProgress: 0,
+    ...overrides,
+  }
+}
+
+describe('classifyStateChange', () => {
+  it('reports platform completion as success', () => {
+    const toast = classifyStateChange(event({ stage: 'status', oldStatus: 'Executing', newStatus: 'Completed', jobId: 'AbCdE' }))
+    expect(toast?.severity).toBe('success')
+    expect(toast?.title).toBe('Run_1 completed')
+  })
+
+  it('reports a failed pipeline stage as error with its message', () => {
+    const toast = classifyStateChange(event({ stage: 'upload', newStatus: 'failed', errorMessage: 'disk full' }))
+    expect(toast?.severity).toBe('error')
+    expect(toast?.title).toBe('Run_1 failed at upload')
+    expect(toast?.message).toBe('disk full')
+  })
+
+  it('reports submission as info', () => {
+    expect(classifyStateChange(event({ stage: 'submit', newStatus: 'success' }))?.severity).toBe('info')
+  })
+
+  it('ignores intermediate updates and state replays', () => {
+    expect(classifyStateChange(event({ stage: 'upload', newStatus: 'in_progress', uploadProgress: 0.4 }))).toBeNull()
+    expect(classifyStateChange(event({ stage: 'status', oldStatus: 'Queued', newStatus: 'Executing' }))).toBeNull()
+    expect(classifyStateChange(event({ stage: '', newStatus: 'failed' }))).toBeNull()
+  })
+})
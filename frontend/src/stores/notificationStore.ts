@@ -0,0 +1,157 @@
+import { create } from 'zustand'
+import { EventsOn } from '../../wailsjs/runtime/runtime'
+import { EVENT_NAMES, type StateChangeEventDTO } from '../types/events'
+
+// In-app toasts for job state changes, so completions and failures are not
+// missed while the user works in another tab (e.g. the File Browser).
+
+export type NotificationSeverity = 'success' | 'error' | 'info'
+
+export interface Toast {
+  id: number
+  severity: NotificationSeverity
+  title: string
+  message: string
+  createdAt: number
+}
+
+export type MuteSettings = Record<NotificationSeverity, boolean>
+
+const MUTE_KEY = 'rescale-int-notification-mute'
+const MAX_TOASTS = 5
+// Errors stay until dismissed; other toasts clear themselves.
+const AUTO_DISMISS_MS = 8000
+
+const DEFAULT_MUTE: MuteSettings = { success: false, error: false, info: true }
+
+function loadMute(): MuteSettings {
+  try {
+    const raw = localStorage.getItem(MUTE_KEY)
+    if (raw) return { ...DEFAULT_MUTE, ...JSON.parse(raw) }
+  } catch { /* ignore localStorage errors */ }
+  return DEFAULT_MUTE
+}
+
+/**
+ * Map a state change to a toast, or null for intermediate updates.
+ * Terminal outcomes only: a pipeline stage failing, a job being submitted,
+ * and the platform reporting Completed/Failed/Stopped. Events without a
+ * stage are replays from LoadState and are ignored.
+ */
+export function classifyStateChange(
+  event: StateChangeEventDTO
+): Omit<Toast, 'id' | 'createdAt'> | null {
+  const name = event.jobName || event.jobId || 'Job'
+  const status = event.newStatus.toLowerCase()
+  if (!event.stage || event.newStatus === event.oldStatus) return null
+
+  if (event.stage === 'status') {
+    switch (event.newStatus) {
+      case 'Completed':
+        return { severity: 'success', title: `${name} completed`, message: event.jobId ? `Job ${event.jobId}` : '' }
+      case 'Failed':
+      case 'Stopped':
+      case 'Terminated':
+        return {
+          severity: 'error',
+          title: `${name} ${status}`,
+          message: event.errorMessage || (event.jobId ? `Job ${event.jobId}` : ''),
+        }
+    }
+    return null
+  }
+
+  if (status === 'failed') {
+    return { severity: 'error', title: `${name} failed at ${event.stage}`, message: event.errorMessage || '' }
+  }
+  if (event.stage === 'submit' && (status === 'completed' || status === 'success')) {
+    return { severity: 'info', title: `${name} submitted`, message: event.jobId ? `Job ${event.jobId}` : '' }
+  }
+  return null
+}
+
+interface NotificationState {
+  toasts: Toast[]
+  mute: MuteSettings
+  _eventListenersSetup: boolean
+
+  notify: (toast: Omit<Toast, 'id' | 'createdAt'>) => void
+  dismiss: (id: number) => void
+  clearAll: () => void
+  setMuted: (severity: NotificationSeverity, muted: boolean) => void
+  setupEventListeners: () => () => void
+}
+
+export const useNotificationStore = create<NotificationState>((set, get) => {
+  let nextId = 1
+  const timers = new Map<number, ReturnType<typeof setTimeout>>()
+
+  const dismiss = (id: number) => {
+    const timer = timers.get(id)
+    if (timer !== undefined) {
+      clearTimeout(timer)
+      timers.delete(id)
+    }
+    set((prev) => ({ toasts: prev.toasts.filter((t) => t.id !== id) }))
+  }
+
+  return {
+    toasts: [],
+    mute: loadMute(),
+    _eventListenersSetup: false,
+
+    notify: (toast) => {
+      if (get().mute[toast.severity]) return
+      const id = nextId++
+      set((prev) => {
+        const toasts = [...prev.toasts, { ...toast, id, createdAt: Date.now() }]
+        // Drop the oldest toasts past the cap (and their timers)
+        for (const old of toasts.slice(0, Math.max(0, toasts.length - MAX_TOASTS))) {
+          const timer = timers.get(old.id)
+          if (timer !== undefined) clearTimeout(timer)
+          timers.delete(old.id)
+        }
+        return { toasts: toasts.slice(-MAX_TOASTS) }
+      })
+      if (toast.severity !== 'error') {
+        timers.set(id, setTimeout(() => dismiss(id), AUTO_DISMISS_MS))
+      }
+    },
+
+    dismiss,
+
+    clearAll: () => {
+      timers.forEach((timer) => clearTimeout(timer))
+      timers.clear()
+      set({ toasts: [] })
+    },
+
+    setMuted: (severity, muted) => {
+      const mute = { ...get().mute, [severity]: muted }
+      set({ mute })
+      try {
+        localStorage.setItem(MUTE_KEY, JSON.stringify(mute))
+      } catch { /* ignore localStorage errors */ }
+    },
+
+    setupEventListeners: () => {
+      if (get()._eventListenersSetup) {
+        return () => {} // Already set up
+      }
+
+      // Use the unsub callback, never EventsOff (would drop other stores' listeners).
+      const unsubStateChange = EventsOn(EVENT_NAMES.STATE_CHANGE, (data: StateChangeEventDTO) => {
+        const toast = classifyStateChange(data)
+        if (toast) get().notify(toast)
+      })
+
+      set({ _eventListenersSetup: true })
+
+      return () => {
+        unsubStateChange()
+        get().clearAll()
+        set({ _eventListenersSetup: false })
+      }
+    },
+  }
+})
@@ -14,14 +14,15 @@ import type {
   PersistedActiveRun,
 } from '../types/run'
 import { computeStageStats } from '../utils/stageStats'
-import type { StateChangeEventDTO, LogEventDTO, CompleteEventDTO } from '../types/events'
+import { EVENT_NAMES } from '../types/events'
+import type { StateChangeEventDTO, LogEventDTO, CompleteEventDTO, ProgressEventDTO, BlackoutChangedEventDTO } from '../types/events'
 
 const ACTIVE_RUN_KEY = 'rescale-int-active-run'
 const MAX_COMPLETED_RUNS = 20
 const MAX_PIPELINE_LOGS = 200
 
 // Known pipeline stages for log filtering
-const PIPELINE_STAGES = new Set(['tar', 'upload', 'create', 'submit', 'pipeline'])
+const PIPELINE_STAGES = new Set(['tar', 'upload', 'create', 'submit', 'output', 'pipeline'])
 
 function sleep(ms: number): Promise<void> {
   return new Promise((resolve) => setTimeout(resolve, ms))
@@ -55,6 +56,8 @@ export function mergePolledJobRow(existing: JobRow | undefined, polled: JobRow):
     submitStatus: polled.submitStatus || existing.submitStatus,
     jobId: polled.jobId || existing.jobId,
     error: polled.error || existing.error,
+    warnings: polled.warnings ?? existing.warnings,
+    arrayName: polled.arrayName ?? existing.arrayName,
     createStatus: existing.createStatus || polled.createStatus,
     uploadProgress: polled.uploadProgress > 0
       ? polled.uploadProgress * 100
@@ -70,6 +73,7 @@ interface RunStore {
   queuedJob: QueuedJob | null
   queueStatus: string | null  // 'queued' | 'starting' | 'started' | 'failed:...' | null
   purViewMode: 'auto' | 'monitor' | 'configure'
+  blackoutUntil: string | null  // RFC3339 end of the current blackout window (tar/upload paused)
 
   // Actions
   registerRun: (runId: string, runType: RunType, totalJobs: number, initialJobRows: JobRow[]) => void
@@ -78,6 +82,7 @@ interface RunStore {
   setQueueStatus: (status: string | null) => void
   setPurViewMode: (mode: 'auto' | 'monitor' | 'configure') => void
   cancelRun: () => Promise<void>
+  setJobPlatformStatus: (jobId: string, status: string) => void
 
   // App-level event listeners (called from App.tsx, always active)
   setupEventListeners: () => () => void
@@ -100,6 +105,7 @@ export const useRunStore = create<RunStore>((set, get) => ({
   queuedJob: null,
   queueStatus: null,
   purViewMode: 'auto',
+  blackoutUntil: null,
 
   _pollInterval: null,
   _eventListenersSetup: false,
@@ -207,6 +213,19 @@ export const useRunStore = create<RunStore>((set, get) => ({
     }
   },
 
+  // Platform status updates (e.g. after Stop) apply to any displayed run,
+  // not just active ones, so stopping a job from the results view is reflected.
+  setJobPlatformStatus: (jobId, status) => {
+    set((prev) => {
+      if (!prev.activeRun) return prev
+      const idx = prev.activeRun.jobRows.findIndex((r) => r.jobId === jobId)
+      if (idx === -1) return prev
+      const jobRows = [...prev.activeRun.jobRows]
+      jobRows[idx] = { ...jobRows[idx], platformStatus: status, subStatus: undefined, subStatusReason: undefined, subStatusSince: undefined }
+      return { activeRun: { ...prev.activeRun, jobRows } }
+    })
+  },
+
   setupEventListeners: () => {
     if (get()._eventListenersSetup) {
       return () => {} // Already set up
@@ -231,10 +250,18 @@ export const useRunStore = create<RunStore>((set, get) => ({
         }
         else if (data.stage === 'create') row.createStatus = data.newStatus
         else if (data.stage === 'submit') row.submitStatus = data.newStatus
+        else if (data.stage === 'output') row.outputStatus = data.newStatus
+        else if (data.stage === 'status') {
+          row.platformStatus = data.newStatus
+          row.subStatus = data.subStatus
+          row.subStatusReason = data.subStatusReason
+          row.subStatusSince = data.subStatusSince
+        }
 
         if (data.jobId) row.jobId = data.jobId
         if (data.errorMessage) row.error = data.errorMessage
-        if (data.newStatus === 'failed') row.status = 'failed'
+        // A failed output download leaves the job itself as it was
+        if (data.newStatus === 'failed' && data.stage !== 'output') row.status = 'failed'
         else if (data.newStatus === 'completed' && data.stage === 'submit') row.status = 'completed'
 
         jobRows[idx] = row
@@ -261,6 +288,26 @@ export const useRunStore = create<RunStore>((set, get) => ({
       })
     })
 
+    // Tar progress: shown in the TarStatus column while the archive is written
+    const unsubProgress = EventsOn('interlink:progress', (data: ProgressEventDTO) => {
+      if (data.stage !== 'tar') return
+      const { activeRun } = get()
+      if (!activeRun || activeRun.status !== 'active') return
+
+      set((prev) => {
+        if (!prev.activeRun) return prev
+        const idx = prev.activeRun.jobRows.findIndex((r) => r.jobName === data.jobName)
+        if (idx === -1) return prev
+        const jobRows = [...prev.activeRun.jobRows]
+        jobRows[idx] = {
+          ...jobRows[idx],
+          tarProgress: data.progress * 100,
+          tarProgressDetail: data.message,
+        }
+        return { activeRun: { ...prev.activeRun, jobRows } }
+      })
+    })
+
     // C5: Use correct field names from LogEventDTO (jobName, stage — NOT detail, category)
     const unsubLog = EventsOn('interlink:log', (data: LogEventDTO) => {
       const { activeRun } = get()
@@ -317,6 +364,8 @@ export const useRunStore = create<RunStore>((set, get) => ({
         completedJobs: completedCount,
         failedJobs: failedCount,
         durationMs: Date.now() - activeRun.startTime,
+        bytesSent: data?.bytesSent,
+        bytesReceived: data?.bytesReceived,
         jobRows: [...activeRun.jobRows],
         finalStatus,
       }
@@ -328,6 +377,8 @@ export const useRunStore = create<RunStore>((set, get) => ({
           completedJobs: completedCount,
           failedJobs: failedCount,
           durationMs: Date.now() - prev.activeRun.startTime,
+          bytesSent: data?.bytesSent,
+          bytesReceived: data?.bytesReceived,
         } : null,
         completedRuns: [completedRun, ...prev.completedRuns].slice(0, MAX_COMPLETED_RUNS),
       }))
@@ -340,13 +391,24 @@ export const useRunStore = create<RunStore>((set, get) => ({
       }
     })
 
+    // Blackout windows: the backend only reports starts and ends, so fetch
+    // the current state once for a window already under way
+    const unsubBlackout = EventsOn(EVENT_NAMES.BLACKOUT_CHANGED, (data: BlackoutChangedEventDTO) => {
+      set({ blackoutUntil: data.active ? data.until : null })
+    })
+    App.GetBlackoutStatus()
+      .then((status) => set({ blackoutUntil: status.active ? status.until : null }))
+      .catch(() => { /* leave unset */ })
+
     set({ _eventListenersSetup: true })
 
     return () => {
       // C9: Only remove OUR listeners via unsub callbacks
       unsubStateChange()
+      unsubProgress()
       unsubLog()
       unsubComplete()
+      unsubBlackout()
       set({ _eventListenersSetup: false })
     }
   },
@@ -380,6 +442,8 @@ export const useRunStore = create<RunStore>((set, get) => ({
           jobId: r.jobId || '',
           progress: 0,
           error: r.error || '',
+          warnings: r.warnings,
+          arrayName: r.arrayName,
         }))
 
         const activeRun: ActiveRun = {
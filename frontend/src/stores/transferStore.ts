@@ -2,12 +2,12 @@ import { create } from 'zustand'
 import * as App from '../../wailsjs/go/wailsapp/App'
 import { wailsapp } from '../../wailsjs/go/models'
 import { EventsOn } from '../../wailsjs/runtime/runtime'
-import { ProgressEventDTO, TransferEventDTO, EnumerationEventDTO, BatchProgressEventDTO, EVENT_NAMES } from '../types/events'
+import { ProgressEventDTO, TransferEventDTO, EnumerationEventDTO, BatchProgressEventDTO, NetworkChangedEventDTO, EVENT_NAMES } from '../types/events'
 
 // Transfer task state
-export type TransferState = 'queued' | 'initializing' | 'active' | 'completed' | 'failed' | 'cancelled' | 'paused'
+export type TransferState = 'queued' | 'initializing' | 'active' | 'completed' | 'failed' | 'cancelled' | 'paused' | 'retry_waiting'
 
-export type TransferErrorType = 'disk_space' | 'generic'
+export type TransferErrorType = 'disk_space' | 'permanent' | 'generic'
 
 export function classifyError(error: string | undefined): TransferErrorType {
   if (!error) return 'generic'
@@ -21,6 +21,8 @@ export function classifyError(error: string | undefined): TransferErrorType {
     lower.includes('disk quota exceeded') ||
     lower.includes('enospc')
   ) return 'disk_space'
+  // An upload part storage rejected outright (cloud/transfer.PartError)
+  if (lower.includes('failed (permanent)')) return 'permanent'
   return 'generic'
 }
 
@@ -80,6 +82,12 @@ export interface TransferTask extends wailsapp.TransferTaskDTO {
   errorType?: TransferErrorType
 }
 
+// Failed transfers across all batches, for the failure summary
+export interface FailedSummary {
+  total: number
+  tasks: TransferTask[] // Oldest first, capped by the backend
+}
+
 // Transfer statistics
 export interface TransferStats extends wailsapp.TransferStatsDTO {
   totalActive: number
@@ -93,6 +101,7 @@ interface TransferStore {
   // State
   tasks: TransferTask[]
   stats: TransferStats
+  failedSummary: FailedSummary
   enumerations: Enumeration[]
   batches: TransferBatch[]
   expandedBatches: Set<string>
@@ -100,6 +109,7 @@ interface TransferStore {
   batchEpochs: Map<string, number> // Epoch counter per batch for stale-response protection
   batchStatusFilter: Map<string, string> // Per-batch status filter ("" = all, "active", "completed", "failed", "cancelled")
   folderCheckStatus: { folderName: string; message?: string } | null
+  networkOffline: boolean // Queue paused until the network is back
   isLoading: boolean
   error: string | null
   isPolling: boolean
@@ -108,6 +118,7 @@ interface TransferStore {
   // Actions
   fetchTasks: () => Promise<void>
   fetchStats: () => Promise<void>
+  fetchFailedSummary: () => Promise<void>
   fetchBatches: () => Promise<void>
   fetchDaemonSnapshot: () => Promise<void>
   fetchUngroupedTasks: () => Promise<void>
@@ -118,7 +129,9 @@ interface TransferStore {
   cancelAllTransfers: () => Promise<void>
   cancelBatch: (batchID: string) => Promise<void>
   retryTransfer: (taskId: string) => Promise<string | null>
+  giveUpTransfer: (taskId: string) => Promise<void>
   retryFailedInBatch: (batchID: string) => Promise<void>
+  retryAllFailed: () => Promise<void>
   clearCompletedTransfers: () => void
   toggleBatchExpanded: (batchID: string) => void
   setBatchStatusFilter: (batchID: string, filter: string) => void
@@ -126,6 +139,7 @@ interface TransferStore {
   handleTransferEvent: (event: TransferEventDTO) => void
   handleEnumerationEvent: (event: EnumerationEventDTO) => void
   handleBatchProgressEvent: (event: BatchProgressEventDTO) => void
+  handleNetworkChangedEvent: (event: NetworkChangedEventDTO) => void
   setFolderCheckStatus: (status: { folderName: string; message?: string } | null) => void
 
   // App-level event listeners (always active, unlike polling which is tab-specific)
@@ -194,6 +208,7 @@ const initialStats: TransferStats = {
 export const useTransferStore = create<TransferStore>((set, get) => ({
   tasks: [],
   stats: initialStats,
+  failedSummary: { total: 0, tasks: [] },
   enumerations: [],
   batches: [],
   expandedBatches: new Set<string>(),
@@ -201,6 +216,7 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
   batchEpochs: new Map<string, number>(), // Epoch counter per batch for stale-response protection
   batchStatusFilter: new Map<string, string>(),
   folderCheckStatus: null,
+  networkOffline: false,
   isLoading: false,
   error: null,
   isPolling: false,
@@ -437,6 +453,15 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
     }
   },
 
+  fetchFailedSummary: async () => {
+    try {
+      const summary = await App.GetFailedTransfers()
+      set({ failedSummary: { total: summary.total, tasks: (summary.tasks || []).map(enhanceTask) } })
+    } catch (error) {
+      console.error('Failed to fetch failed transfers:', error)
+    }
+  },
+
   startPolling: (intervalMs = 500) => {
     const state = get()
 
@@ -448,6 +473,10 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
       get().fetchUngroupedTasks()
       get().fetchStats()
       get().fetchDaemonSnapshot()
+      // Only walk the queue for failures while there are any to show
+      if (get().stats.failed > 0 || get().failedSummary.total > 0) {
+        get().fetchFailedSummary()
+      }
     }, intervalMs)
 
     // Subscribe to progress events for real-time updates (legacy PUR jobs)
@@ -463,6 +492,7 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
     get().fetchUngroupedTasks()
     get().fetchStats()
     get().fetchDaemonSnapshot()
+    get().fetchFailedSummary()
 
     set({
       isPolling: true,
@@ -526,7 +556,7 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
 
       // Cancel remaining ungrouped active tasks.
       const activeTasks = get().tasks.filter(
-        t => ['queued', 'initializing', 'active', 'paused'].includes(t.state)
+        t => ['queued', 'initializing', 'active', 'paused', 'retry_waiting'].includes(t.state)
       )
       for (const task of activeTasks) {
         if (task.sourceLabel === 'Daemon') {
@@ -585,6 +615,16 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
     }
   },
 
+  giveUpTransfer: async (taskId: string) => {
+    try {
+      await App.GiveUpTransfer(taskId)
+      get().fetchUngroupedTasks()
+      get().fetchBatches()
+    } catch (error) {
+      console.error('Failed to give up transfer:', error)
+    }
+  },
+
   retryFailedInBatch: async (batchID: string) => {
     try {
       const batch = get().batches.find(b => b.batchID === batchID)
@@ -607,6 +647,27 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
     }
   },
 
+  // Retries every failed local transfer, in batches or not. Daemon batches
+  // keep their own per-batch retry.
+  retryAllFailed: async () => {
+    try {
+      await App.RetryFailedTransfers()
+      // Invalidate expanded batch caches — failed rows are queued again
+      set(state => {
+        const newEpochs = new Map(state.batchEpochs)
+        for (const batchID of state.expandedBatches) {
+          newEpochs.set(batchID, (newEpochs.get(batchID) ?? 0) + 1)
+        }
+        return { batchTasks: new Map(), batchEpochs: newEpochs }
+      })
+      get().fetchBatches()
+      get().fetchUngroupedTasks()
+      get().fetchFailedSummary()
+    } catch (error) {
+      console.error('Failed to retry failed transfers:', error)
+    }
+  },
+
   clearCompletedTransfers: () => {
     App.ClearCompletedTransfers()
     // Invalidate all expanded batch caches — composition changed
@@ -643,6 +704,8 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
 
   setFolderCheckStatus: (status) => set({ folderCheckStatus: status }),
 
+  handleNetworkChangedEvent: (event) => set({ networkOffline: !event.online }),
+
   // Clears cached tasks, bumps epoch, and re-fetches page 0 with the new filter.
   setBatchStatusFilter: (batchID: string, filter: string) => {
     const newFilters = new Map(get().batchStatusFilter)
@@ -875,6 +938,10 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
       get().handleBatchProgressEvent(event)
     })
 
+    const unsubscribeNetwork = EventsOn(EVENT_NAMES.NETWORK_CHANGED, (event: NetworkChangedEventDTO) => {
+      get().handleNetworkChangedEvent(event)
+    })
+
     set({ _appEventListenersSetup: true })
 
     // Return cleanup function
@@ -882,6 +949,7 @@ export const useTransferStore = create<TransferStore>((set, get) => ({
       unsubscribeTransfer()
       unsubscribeEnumeration()
       unsubscribeBatchProgress()
+      unsubscribeNetwork()
       set({ _appEventListenersSetup: false })
     }
   },
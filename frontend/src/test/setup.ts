@@ -31,9 +31,53 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
     currentVersion: 'v4.8.2',
     checkedAt: new Date().toISOString(),
   })),
+  CheckSelfUpdate: vi.fn(() => Promise.resolve({
+    enabled: false,
+    hasUpdate: false,
+    currentVersion: 'v4.8.2',
+    installed: false,
+  })),
+  InstallSelfUpdate: vi.fn(() => Promise.resolve({
+    enabled: false,
+    hasUpdate: false,
+    currentVersion: 'v4.8.2',
+    installed: false,
+  })),
+  ListTeamJobs: vi.fn(() => Promise.resolve({ jobs: [], adminRequired: false })),
+  StopTeamJobs: vi.fn(() => Promise.resolve({ results: [] })),
+  ArchiveTeamJobs: vi.fn(() => Promise.resolve({ results: [] })),
+  GetTeamStorageUsage: vi.fn(() => Promise.resolve({ members: [], adminRequired: false })),
+  GetJobTarContents: vi.fn(() => Promise.resolve({ entries: [], totalSize: 0, fromArchive: false })),
+  GetLiveFiles: vi.fn(() => Promise.resolve({ jobId: '', status: '', running: false, supported: false, path: '', entries: [], maxUploadSize: 0 })),
+  DownloadLiveFile: vi.fn(() => Promise.resolve('')),
+  UploadLiveFile: vi.fn(() => Promise.resolve({ path: '', name: '', isDirectory: false, size: 0, dateModified: '' })),
+  PredictJobRuntime: vi.fn(() => Promise.resolve({ available: false, samples: 0 })),
+  GetSendToMenu: vi.fn(() => Promise.resolve({ supported: true, installed: false, label: 'Send to Rescale Interlink' })),
+  SetSendToMenu: vi.fn(() => Promise.resolve()),
+  GetCacheStats: vi.fn(() => Promise.resolve({ categories: [], bytes: 0, limit: 0 })),
+  GetNetworkUsage: vi.fn(() => Promise.resolve({
+    todaySent: 0, todayReceived: 0, monthSent: 0, monthReceived: 0, sessionSent: 0, sessionReceived: 0,
+  })),
+  ClearCache: vi.fn(() => Promise.resolve({ removed: 0, freed: 0 })),
+  ExportJobsTable: vi.fn(() => Promise.resolve('')),
+  GetContinuationSource: vi.fn(() => Promise.reject(new Error('not available in tests'))),
+  BuildContinuationJob: vi.fn(() => Promise.resolve({})),
+  ListWorkspaces: vi.fn(() => Promise.resolve([])),
+  SetWorkspace: vi.fn(() => Promise.resolve()),
+  GetBlackoutStatus: vi.fn(() => Promise.resolve({ active: false, until: '' })),
+  GetTemplateRepo: vi.fn(() => Promise.resolve({ configured: false, source: '', revision: '', warning: '', items: [], updates: 0 })),
+  ImportRepoTemplate: vi.fn(() => Promise.resolve()),
+  UpdateRepoTemplates: vi.fn(() => Promise.resolve(0)),
+  SetRepoTemplatePinned: vi.fn(() => Promise.resolve()),
+  PlanUploadNames: vi.fn((names: string[]) => Promise.resolve({ files: names.map(name => ({ name, exists: false, skip: false })) })),
+  CheckLargeUpload: vi.fn(() => Promise.resolve({ needed: false, files: 0, totalBytes: 0 })),
+  FindDuplicateTransfers: vi.fn(() => Promise.resolve([])),
+  IsViewerMode: vi.fn(() => Promise.resolve(false)),
   UpdateConfig: vi.fn(() => Promise.resolve()),
   SaveConfig: vi.fn(() => Promise.resolve()),
   TestConnection: vi.fn(() => Promise.resolve()),
+  RunNetworkTest: vi.fn(() => Promise.resolve({ dns: [], storage: [], proxy: { mode: 'no-proxy', hops: 0, via: [] } })),
+  GetLastNetworkTest: vi.fn(() => Promise.resolve(null)),
   SelectFile: vi.fn(() => Promise.resolve('')),
   SelectDirectory: vi.fn(() => Promise.resolve('')),
 
@@ -53,6 +97,9 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
     nextCursor: '',
   })),
   GetHomeDirectory: vi.fn(() => Promise.resolve('/home/user')),
+  GetLocalRoots: vi.fn(() => Promise.resolve([])),
+  AddLocalRoot: vi.fn(() => Promise.resolve()),
+  RemoveLocalRoot: vi.fn(() => Promise.resolve()),
   ListRemoteFolder: vi.fn(() => Promise.resolve({
     folderId: 'folder-123',
     folderPath: 'My Library',
@@ -67,6 +114,15 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
     hasMore: false,
     nextCursor: '',
   })),
+  ListRemoteFolderWindow: vi.fn(() => Promise.resolve({
+    folderId: 'folder-123',
+    page: 0,
+    pageSize: 200,
+    total: 0,
+    items: [],
+  })),
+  FindRemoteFolderItem: vi.fn(() => Promise.resolve(0)),
+  ResolveDownloadDestination: vi.fn(() => Promise.resolve('')),
   ListRemoteLegacy: vi.fn(() => Promise.resolve({
     folderId: '',
     folderPath: 'Legacy Files',
@@ -93,6 +149,9 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
   CancelTransfer: vi.fn(() => Promise.resolve()),
   CancelAllTransfers: vi.fn(() => Promise.resolve()),
   RetryTransfer: vi.fn(() => Promise.resolve('new-task-123')),
+  GiveUpTransfer: vi.fn(() => Promise.resolve()),
+  GetFailedTransfers: vi.fn(() => Promise.resolve({ total: 0, tasks: [] })),
+  RetryFailedTransfers: vi.fn(() => Promise.resolve(0)),
   GetTransferStats: vi.fn(() => Promise.resolve({
     queued: 0,
     initializing: 0,
@@ -110,6 +169,16 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
   ListSavedTemplates: vi.fn(() => Promise.resolve([])),
   SaveTemplate: vi.fn(() => Promise.resolve()),
   DeleteTemplate: vi.fn(() => Promise.resolve()),
+  ImportJobFromRescale: vi.fn(() => Promise.reject(new Error('not available in tests'))),
+  DiffJobFiles: vi.fn(() => Promise.resolve({
+    countA: 0,
+    countB: 0,
+    added: [],
+    removed: [],
+    changed: [],
+    unchanged: 0,
+    riskyCount: 0,
+  })),
 
   // Run bindings (runStore polling + reset)
   GetRunStatus: vi.fn(() => Promise.resolve({
@@ -125,6 +194,8 @@ vi.mock('../../wailsjs/go/wailsapp/App', () => ({
 
   // Job-spec metadata (reachable via jobStore when TemplateBuilder opens)
   GetCoreTypes: vi.fn(() => Promise.resolve([])),
+  GetCoreTypeFavorites: vi.fn(() => Promise.resolve([])),
+  SetCoreTypeFavorite: vi.fn(() => Promise.resolve([])),
   GetAnalysisCodes: vi.fn(() => Promise.resolve([])),
   GetAutomations: vi.fn(() => Promise.resolve([])),
 }))
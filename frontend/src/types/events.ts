@@ -10,6 +10,9 @@ export interface ProgressEventDTO {
   message: string;
   rateBytes: number;
   etaMs: number;
+  filesCurrent?: number; // Tar stage only
+  filesTotal?: number;
+  currentFile?: string;
 }
 
 export interface LogEventDTO {
@@ -19,6 +22,8 @@ export interface LogEventDTO {
   stage: string;
   jobName: string;
   error?: string;
+  runId?: string; // PUR run the line belongs to
+  transferId?: string; // Transfer the line belongs to
 }
 
 export interface StateChangeEventDTO {
@@ -30,6 +35,9 @@ export interface StateChangeEventDTO {
   jobId?: string;
   errorMessage?: string;
   uploadProgress: number;
+  subStatus?: string;       // Queue/provisioning sub-status (status stage only)
+  subStatusReason?: string;
+  subStatusSince?: string;  // RFC3339
 }
 
 export interface ErrorEventDTO {
@@ -45,6 +53,9 @@ export interface CompleteEventDTO {
   successJobs: number;
   failedJobs: number;
   durationMs: number;
+  reportPath?: string;
+  bytesSent?: number;      // Network bytes sent during the run
+  bytesReceived?: number;  // Network bytes received during the run
 }
 
 export interface TransferEventDTO {
@@ -56,6 +67,7 @@ export interface TransferEventDTO {
   progress: number; // 0.0 to 1.0
   speed: number;    // bytes/sec
   error?: string;
+  runId?: string;  // PUR run the transfer belongs to
 }
 
 export interface EnumerationEventDTO {
@@ -128,6 +140,19 @@ export interface BatchProgressEventDTO {
   skipped: number;
 }
 
+export interface NetworkChangedEventDTO {
+  timestamp: string;
+  oldRoute: string; // "interface/address" ("" = offline)
+  newRoute: string;
+  online: boolean;
+}
+
+export interface BlackoutChangedEventDTO {
+  timestamp: string;
+  active: boolean;
+  until: string; // RFC3339 end of the window; empty when not active
+}
+
 export interface ConnectionResultDTO {
   success: boolean;
   email?: string;
@@ -150,6 +175,8 @@ export const EVENT_NAMES = {
   SCAN_PROGRESS: 'interlink:scan_progress',
   BATCH_PROGRESS: 'interlink:batch_progress',
   CONFIG_CHANGED: 'interlink:config_changed',
+  NETWORK_CHANGED: 'interlink:network_changed',
+  BLACKOUT_CHANGED: 'interlink:blackout_changed',
   REPORTABLE_ERROR: 'interlink:reportable_error',
 } as const;
 
@@ -36,6 +36,17 @@ export interface JobSpec {
   projectId: string
   orgCode: string
   automations: string[]
+  priority?: number // Higher values are processed first within a run
+  destinationFolder?: string // Remote folder path under My Library for the job's uploads
+  metadata?: Record<string, string> // Written to the job description and/or custom fields
+  continuationCommand?: string // Command used when continuing a finished job from its restart files
+  outputPatterns?: string[] // Output files downloaded into the job's directory when it completes
+  dependsOn?: string[] // Jobs of the run that must complete before this one is submitted
+  array?: string // Index range (e.g. "1-100"); the spec runs as one job per index, {{index}} substituted
+  noTextNormalize?: boolean // Archive text inputs as they are, whatever text_normalize says
+  envVars?: Record<string, string> // Environment variables set on the cluster, besides licenseSettings
+  useRescaleLicense?: boolean // Run on a Rescale-provided license
+  clusterId?: string // Run on this reserved or persistent cluster (isLowPriority does not apply)
 }
 
 // Job row for the jobs table
@@ -52,6 +63,15 @@ export interface JobRow {
   jobId: string
   progress: number
   error: string
+  warnings?: string[] // Returned by the platform when the job was created (e.g. deprecated version)
+  outputStatus?: string // Download of outputs on completion: pending, in_progress, completed, failed or skipped
+  arrayName?: string // Array the job is a member of; members are shown collapsed under one row
+  platformStatus?: string // Live job status from Rescale (e.g. Executing, Stopping)
+  subStatus?: string // Queue/provisioning sub-status while waiting to execute (e.g. Provisioning cluster)
+  subStatusReason?: string
+  subStatusSince?: string // RFC3339; when the job entered subStatus
+  tarProgress?: number // 0-100 while the tar is being written
+  tarProgressDetail?: string // e.g. "43% - 1200/3400 files, 130.2 GB of 300.0 GB (Run_1/mesh.cas)"
 }
 
 // Run status
@@ -15,6 +15,8 @@ export interface ActiveRun {
   completedJobs: number
   failedJobs: number
   durationMs: number
+  bytesSent?: number         // Network usage, set on completion
+  bytesReceived?: number
   error?: string
   jobRows: JobRow[]
   pipelineStageStats: PipelineStageStats
@@ -31,6 +33,8 @@ export interface CompletedRun {
   completedJobs: number
   failedJobs: number
   durationMs: number
+  bytesSent?: number         // Network usage, from the complete event
+  bytesReceived?: number
   error?: string
   jobRows: JobRow[]          // Snapshot at completion
   finalStatus: 'completed' | 'failed' | 'cancelled' | 'interrupted'
@@ -425,6 +425,13 @@ export namespace wailsapp {
 	    displayOrder: number;
 	    isActive: boolean;
 	    cores: number[];
+	    memoryPerCoreGb?: number;
+	    processor?: string;
+	    interconnect?: string;
+	    interconnectClass?: string;
+	    architecture?: string;
+	    price?: number;
+	    priceBand?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new CoreTypeDTO(source);
@@ -437,6 +444,13 @@ export namespace wailsapp {
 	        this.displayOrder = source["displayOrder"];
 	        this.isActive = source["isActive"];
 	        this.cores = source["cores"];
+	        this.memoryPerCoreGb = source["memoryPerCoreGb"];
+	        this.processor = source["processor"];
+	        this.interconnect = source["interconnect"];
+	        this.interconnectClass = source["interconnectClass"];
+	        this.architecture = source["architecture"];
+	        this.price = source["price"];
+	        this.priceBand = source["priceBand"];
 	    }
 	}
 	export class CoreTypesResultDTO {
@@ -707,6 +721,22 @@ export namespace wailsapp {
 	        this.error = source["error"];
 	    }
 	}
+	export class DuplicateTransferDTO {
+	    index: number;
+	    taskId: string;
+	    state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateTransferDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.taskId = source["taskId"];
+	        this.state = source["state"];
+	    }
+	}
 	export class ElevatedServiceResultDTO {
 	    success: boolean;
 	    error?: string;
@@ -874,6 +904,8 @@ export namespace wailsapp {
 	    jobId: string;
 	    progress: number;
 	    error: string;
+	    warnings?: string[];
+	    arrayName?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new JobRowDTO(source);
@@ -893,6 +925,8 @@ export namespace wailsapp {
 	        this.jobId = source["jobId"];
 	        this.progress = source["progress"];
 	        this.error = source["error"];
+	        this.warnings = source["warnings"];
+	        this.arrayName = source["arrayName"];
 	    }
 	}
 	export class JobSpecDTO {
@@ -1005,6 +1039,30 @@ export namespace wailsapp {
 	        this.error = source["error"];
 	    }
 	}
+	export class NetworkUsageDTO {
+	    todaySent: number;
+	    todayReceived: number;
+	    monthSent: number;
+	    monthReceived: number;
+	    sessionSent: number;
+	    sessionReceived: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NetworkUsageDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.todaySent = source["todaySent"];
+	        this.todayReceived = source["todayReceived"];
+	        this.monthSent = source["monthSent"];
+	        this.monthReceived = source["monthReceived"];
+	        this.sessionSent = source["sessionSent"];
+	        this.sessionReceived = source["sessionReceived"];
+	        this.error = source["error"];
+	    }
+	}
 	export class PURRunOptionsDTO {
 	    extraInputFiles: string;
 	    decompressExtras: boolean;
@@ -1123,6 +1181,7 @@ export namespace wailsapp {
 	    primaryPattern: string;
 	    secondaryPatterns: SecondaryPatternDTO[];
 	    tarSubpath?: string;
+	    outputPatterns?: string[];
 	    iteratePatterns: boolean;
 	
 	    static createFrom(source: any = {}) {
@@ -1141,6 +1200,7 @@ export namespace wailsapp {
 	        this.primaryPattern = source["primaryPattern"];
 	        this.secondaryPatterns = this.convertValues(source["secondaryPatterns"], SecondaryPatternDTO);
 	        this.tarSubpath = source["tarSubpath"];
+	        this.outputPatterns = source["outputPatterns"];
 	        this.iteratePatterns = source["iteratePatterns"];
 	    }
 	
@@ -1365,6 +1425,8 @@ export namespace wailsapp {
 	    batchID?: string;
 	    batchLabel?: string;
 	    tags?: string[];
+	    postProcess?: string;
+	    allowDuplicate?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new TransferRequestDTO(source);
@@ -1381,6 +1443,8 @@ export namespace wailsapp {
 	        this.batchID = source["batchID"];
 	        this.batchLabel = source["batchLabel"];
 	        this.tags = source["tags"];
+	        this.postProcess = source["postProcess"];
+	        this.allowDuplicate = source["allowDuplicate"];
 	    }
 	}
 	export class TransferStatsDTO {
@@ -1409,6 +1473,22 @@ export namespace wailsapp {
 	        this.total = source["total"];
 	    }
 	}
+	export class TransferStepDTO {
+	    step: string;
+	    state: string;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TransferStepDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.step = source["step"];
+	        this.state = source["state"];
+	        this.detail = source["detail"];
+	    }
+	}
 	export class TransferTaskDTO {
 	    id: string;
 	    type: string;
@@ -1423,6 +1503,7 @@ export namespace wailsapp {
 	    progress: number;
 	    speed: number;
 	    error?: string;
+	    steps?: TransferStepDTO[];
 	    createdAt: string;
 	    startedAt?: string;
 	    completedAt?: string;
@@ -1446,10 +1527,29 @@ export namespace wailsapp {
 	        this.progress = source["progress"];
 	        this.speed = source["speed"];
 	        this.error = source["error"];
+	        this.steps = this.convertValues(source["steps"], TransferStepDTO);
 	        this.createdAt = source["createdAt"];
 	        this.startedAt = source["startedAt"];
 	        this.completedAt = source["completedAt"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
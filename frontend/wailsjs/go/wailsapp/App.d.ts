@@ -2,6 +2,12 @@
 // This file is automatically generated. DO NOT EDIT
 import {wailsapp} from '../models';
 
+export function AddLocalRoot(arg1:string):Promise<void>;
+
+export function ArchiveTeamJobs(arg1:Array<string>):Promise<wailsapp.AdminActionResultsDTO>;
+
+export function BuildContinuationJob(arg1:wailsapp.ContinuationRequestDTO):Promise<wailsapp.JobSpecDTO>;
+
 export function BuildErrorReport(arg1:string):Promise<string>;
 
 export function CancelAllTransfers():Promise<void>;
@@ -24,8 +30,14 @@ export function CheckFoldersExistForUpload(arg1:Array<string>,arg2:string):Promi
 
 export function CheckForUpdates():Promise<wailsapp.VersionCheckDTO>;
 
+export function CheckLargeUpload(arg1:Array<string>):Promise<wailsapp.LargeUploadDTO>;
+
 export function CheckLocalFolderExists(arg1:string,arg2:string):Promise<wailsapp.LocalFolderExistsCheckDTO>;
 
+export function CheckSelfUpdate():Promise<wailsapp.SelfUpdateDTO>;
+
+export function ClearCache(arg1:Array<string>):Promise<wailsapp.CacheClearResultDTO>;
+
 export function ClearCatalogCache():Promise<void>;
 
 export function ClearCompletedTransfers():Promise<void>;
@@ -38,6 +50,16 @@ export function DeleteRemoteItems(arg1:string,arg2:Array<wailsapp.FileItemDTO>):
 
 export function DeleteTemplate(arg1:string):Promise<void>;
 
+export function DiffJobFiles(arg1:string,arg2:string):Promise<wailsapp.JobDiffDTO>;
+
+export function DownloadLiveFile(arg1:string,arg2:string):Promise<string>;
+
+export function ExportJobsTable(arg1:Array<wailsapp.JobExportRowDTO>,arg2:string):Promise<string>;
+
+export function FindDuplicateTransfers(arg1:Array<wailsapp.TransferRequestDTO>):Promise<Array<wailsapp.DuplicateTransferDTO>>;
+
+export function FindRemoteFolderItem(arg1:string,arg2:string,arg3:boolean,arg4:number):Promise<number>;
+
 export function GetAnalysisCodes(arg1:string):Promise<wailsapp.AnalysisCodesResultDTO>;
 
 export function GetAppInfo():Promise<wailsapp.AppInfoDTO>;
@@ -46,8 +68,16 @@ export function GetAutomations():Promise<wailsapp.AutomationsResultDTO>;
 
 export function GetBatchTasks(arg1:string,arg2:number,arg3:number,arg4:string):Promise<Array<wailsapp.TransferTaskDTO>>;
 
+export function GetBlackoutStatus():Promise<wailsapp.BlackoutStatusDTO>;
+
+export function GetCacheStats():Promise<wailsapp.CacheStatsDTO>;
+
 export function GetConfig():Promise<wailsapp.ConfigDTO>;
 
+export function GetContinuationSource(arg1:string):Promise<wailsapp.ContinuationSourceDTO>;
+
+export function GetCoreTypeFavorites():Promise<Array<string>>;
+
 export function GetCoreTypes():Promise<wailsapp.CoreTypesResultDTO>;
 
 export function GetCredentialSource():Promise<wailsapp.CredentialSourceDTO>;
@@ -64,6 +94,8 @@ export function GetDefaultConfigPath():Promise<string>;
 
 export function GetDefaultDownloadFolder():Promise<string>;
 
+export function GetFailedTransfers():Promise<wailsapp.FailedTransfersDTO>;
+
 export function GetFileLoggingSettings():Promise<wailsapp.FileLoggingSettingsDTO>;
 
 export function GetHistoricalJobRows(arg1:string):Promise<Array<wailsapp.JobRowDTO>>;
@@ -72,10 +104,18 @@ export function GetHomeDirectory():Promise<string>;
 
 export function GetJobRows():Promise<Array<wailsapp.JobRowDTO>>;
 
+export function GetJobTarContents(arg1:wailsapp.JobSpecDTO):Promise<wailsapp.TarContentsDTO>;
+
 export function GetJobsStats():Promise<wailsapp.JobsStatsDTO>;
 
+export function GetLastNetworkTest():Promise<wailsapp.NetworkTestResultDTO>;
+
+export function GetLiveFiles(arg1:string,arg2:string):Promise<wailsapp.LiveFilesDTO>;
+
 export function GetLocalFilesInfo(arg1:Array<string>):Promise<Array<wailsapp.LocalFileInfoDTO>>;
 
+export function GetLocalRoots():Promise<Array<wailsapp.LocalRootDTO>>;
+
 export function GetLogFileLocation():Promise<string>;
 
 export function GetLogsDirectory():Promise<string>;
@@ -84,12 +124,22 @@ export function GetMyJobsFolderID():Promise<string>;
 
 export function GetMyLibraryFolderID():Promise<string>;
 
+export function GetNetworkUsage():Promise<wailsapp.NetworkUsageDTO>;
+
 export function GetRunHistory():Promise<Array<wailsapp.RunHistoryEntryDTO>>;
 
+export function GetRunReportPath(arg1:string):Promise<string>;
+
 export function GetRunStatus():Promise<wailsapp.RunStatusDTO>;
 
+export function GetSendToMenu():Promise<wailsapp.SendToMenuDTO>;
+
 export function GetServiceStatus():Promise<wailsapp.ServiceStatusDTO>;
 
+export function GetTeamStorageUsage():Promise<wailsapp.TeamStorageResultDTO>;
+
+export function GetTemplateRepo():Promise<wailsapp.TemplateRepoDTO>;
+
 export function GetTransferBatches():Promise<Array<wailsapp.TransferBatchDTO>>;
 
 export function GetTransferStats():Promise<wailsapp.TransferStatsDTO>;
@@ -98,8 +148,18 @@ export function GetTransferTasks():Promise<Array<wailsapp.TransferTaskDTO>>;
 
 export function GetUngroupedTransferTasks():Promise<Array<wailsapp.TransferTaskDTO>>;
 
+export function GiveUpTransfer(arg1:string):Promise<void>;
+
+export function ImportJobFromRescale(arg1:string):Promise<wailsapp.JobImportResultDTO>;
+
+export function ImportRepoTemplate(arg1:string,arg2:boolean,arg3:boolean):Promise<void>;
+
 export function InstallAndStartServiceElevated():Promise<wailsapp.ElevatedServiceResultDTO>;
 
+export function InstallSelfUpdate():Promise<wailsapp.SelfUpdateDTO>;
+
+export function IsViewerMode():Promise<boolean>;
+
 export function ListLocalDirectory(arg1:string):Promise<wailsapp.FolderContentsDTO>;
 
 export function ListLocalDirectoryEx(arg1:string,arg2:boolean):Promise<wailsapp.FolderContentsDTO>;
@@ -108,12 +168,18 @@ export function ListRemoteFolder(arg1:string):Promise<wailsapp.FolderContentsDTO
 
 export function ListRemoteFolderPage(arg1:string,arg2:string,arg3:number):Promise<wailsapp.FolderContentsDTO>;
 
+export function ListRemoteFolderWindow(arg1:string,arg2:string,arg3:boolean,arg4:number,arg5:number):Promise<wailsapp.FolderWindowDTO>;
+
 export function ListRemoteLegacy(arg1:string,arg2:number):Promise<wailsapp.FolderContentsDTO>;
 
 export function ListRemoteTrash(arg1:string,arg2:number):Promise<wailsapp.FolderContentsDTO>;
 
 export function ListSavedTemplates():Promise<Array<wailsapp.TemplateInfoDTO>>;
 
+export function ListTeamJobs(arg1:wailsapp.AdminJobFilterDTO):Promise<wailsapp.AdminJobsResultDTO>;
+
+export function ListWorkspaces():Promise<Array<wailsapp.WorkspaceDTO>>;
+
 export function LoadConfigFromPath(arg1:string):Promise<void>;
 
 export function LoadJobFromJSON(arg1:string):Promise<wailsapp.JobSpecDTO>;
@@ -128,8 +194,14 @@ export function LoadTemplate(arg1:string):Promise<wailsapp.JobSpecDTO>;
 
 export function OpenLogsDirectory():Promise<void>;
 
+export function OpenRunReport(arg1:string):Promise<void>;
+
 export function PauseDaemon():Promise<void>;
 
+export function PlanUploadNames(arg1:Array<string>,arg2:string,arg3:string):Promise<wailsapp.UploadNamesPlanDTO>;
+
+export function PredictJobRuntime(arg1:wailsapp.JobSpecDTO):Promise<wailsapp.RuntimePredictionDTO>;
+
 export function PreviewCommandPatterns(arg1:string,arg2:Array<string>):Promise<Array<wailsapp.CommandPreviewDTO>>;
 
 export function PurgeTrashItems(arg1:Array<wailsapp.FileItemDTO>):Promise<wailsapp.DeleteResultDTO>;
@@ -138,16 +210,24 @@ export function RecoverTrashItems(arg1:Array<wailsapp.FileItemDTO>):Promise<wail
 
 export function ReloadDaemonConfig():Promise<wailsapp.ReloadConfigResultDTO>;
 
+export function RemoveLocalRoot(arg1:string):Promise<void>;
+
 export function ResetRun():Promise<void>;
 
+export function ResolveDownloadDestination(arg1:string):Promise<string>;
+
 export function ResumeDaemon():Promise<void>;
 
 export function RetryFailedInBatch(arg1:string):Promise<void>;
 
 export function RetryFailedInDaemonBatch(arg1:string):Promise<void>;
 
+export function RetryFailedTransfers():Promise<number>;
+
 export function RetryTransfer(arg1:string):Promise<string>;
 
+export function RunNetworkTest():Promise<wailsapp.NetworkTestResultDTO>;
+
 export function SaveConfig():Promise<void>;
 
 export function SaveConfigAs(arg1:string):Promise<void>;
@@ -180,8 +260,16 @@ export function SelectFile(arg1:string):Promise<string>;
 
 export function SelectMultipleFiles(arg1:string):Promise<Array<string>>;
 
+export function SetCoreTypeFavorite(arg1:string,arg2:boolean):Promise<Array<string>>;
+
 export function SetFileLoggingEnabled(arg1:boolean):Promise<void>;
 
+export function SetRepoTemplatePinned(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetSendToMenu(arg1:boolean):Promise<void>;
+
+export function SetWorkspace(arg1:string):Promise<void>;
+
 export function StartBulkRun(arg1:Array<wailsapp.JobSpecDTO>):Promise<string>;
 
 export function StartBulkRunWithOptions(arg1:Array<wailsapp.JobSpecDTO>,arg2:wailsapp.PURRunOptionsDTO):Promise<string>;
@@ -200,8 +288,12 @@ export function StartTransfers(arg1:Array<wailsapp.TransferRequestDTO>):Promise<
 
 export function StopDaemon():Promise<void>;
 
+export function StopJob(arg1:string):Promise<string>;
+
 export function StopServiceElevated():Promise<wailsapp.ElevatedServiceResultDTO>;
 
+export function StopTeamJobs(arg1:Array<string>):Promise<wailsapp.AdminActionResultsDTO>;
+
 export function TestAutoDownloadConnection(arg1:string):Promise<void>;
 
 export function TestConnection():Promise<wailsapp.ConnectionResultDTO>;
@@ -212,6 +304,10 @@ export function TriggerProfileRescan():Promise<void>;
 
 export function UpdateConfig(arg1:wailsapp.ConfigDTO):Promise<void>;
 
+export function UpdateRepoTemplates():Promise<number>;
+
+export function UploadLiveFile(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<wailsapp.LiveFileDTO>;
+
 export function ValidateAutoDownloadPreFlight(arg1:string):Promise<wailsapp.PreFlightResultDTO>;
 
 export function ValidateAutoDownloadSetup():Promise<wailsapp.AutoDownloadValidationDTO>;
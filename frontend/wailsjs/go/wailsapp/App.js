@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddLocalRoot(arg1) {
+  return window['go']['wailsapp']['App']['AddLocalRoot'](arg1);
+}
+
+export function ArchiveTeamJobs(arg1) {
+  return window['go']['wailsapp']['App']['ArchiveTeamJobs'](arg1);
+}
+
+export function BuildContinuationJob(arg1) {
+  return window['go']['wailsapp']['App']['BuildContinuationJob'](arg1);
+}
+
 export function BuildErrorReport(arg1) {
   return window['go']['wailsapp']['App']['BuildErrorReport'](arg1);
 }
@@ -46,10 +58,22 @@ export function CheckForUpdates() {
   return window['go']['wailsapp']['App']['CheckForUpdates']();
 }
 
+export function CheckLargeUpload(arg1) {
+  return window['go']['wailsapp']['App']['CheckLargeUpload'](arg1);
+}
+
 export function CheckLocalFolderExists(arg1, arg2) {
   return window['go']['wailsapp']['App']['CheckLocalFolderExists'](arg1, arg2);
 }
 
+export function CheckSelfUpdate() {
+  return window['go']['wailsapp']['App']['CheckSelfUpdate']();
+}
+
+export function ClearCache(arg1) {
+  return window['go']['wailsapp']['App']['ClearCache'](arg1);
+}
+
 export function ClearCatalogCache() {
   return window['go']['wailsapp']['App']['ClearCatalogCache']();
 }
@@ -74,6 +98,26 @@ export function DeleteTemplate(arg1) {
   return window['go']['wailsapp']['App']['DeleteTemplate'](arg1);
 }
 
+export function DiffJobFiles(arg1, arg2) {
+  return window['go']['wailsapp']['App']['DiffJobFiles'](arg1, arg2);
+}
+
+export function DownloadLiveFile(arg1, arg2) {
+  return window['go']['wailsapp']['App']['DownloadLiveFile'](arg1, arg2);
+}
+
+export function ExportJobsTable(arg1, arg2) {
+  return window['go']['wailsapp']['App']['ExportJobsTable'](arg1, arg2);
+}
+
+export function FindDuplicateTransfers(arg1) {
+  return window['go']['wailsapp']['App']['FindDuplicateTransfers'](arg1);
+}
+
+export function FindRemoteFolderItem(arg1, arg2, arg3, arg4) {
+  return window['go']['wailsapp']['App']['FindRemoteFolderItem'](arg1, arg2, arg3, arg4);
+}
+
 export function GetAnalysisCodes(arg1) {
   return window['go']['wailsapp']['App']['GetAnalysisCodes'](arg1);
 }
@@ -90,10 +134,26 @@ export function GetBatchTasks(arg1, arg2, arg3, arg4) {
   return window['go']['wailsapp']['App']['GetBatchTasks'](arg1, arg2, arg3, arg4);
 }
 
+export function GetBlackoutStatus() {
+  return window['go']['wailsapp']['App']['GetBlackoutStatus']();
+}
+
+export function GetCacheStats() {
+  return window['go']['wailsapp']['App']['GetCacheStats']();
+}
+
 export function GetConfig() {
   return window['go']['wailsapp']['App']['GetConfig']();
 }
 
+export function GetContinuationSource(arg1) {
+  return window['go']['wailsapp']['App']['GetContinuationSource'](arg1);
+}
+
+export function GetCoreTypeFavorites() {
+  return window['go']['wailsapp']['App']['GetCoreTypeFavorites']();
+}
+
 export function GetCoreTypes() {
   return window['go']['wailsapp']['App']['GetCoreTypes']();
 }
@@ -126,6 +186,10 @@ export function GetDefaultDownloadFolder() {
   return window['go']['wailsapp']['App']['GetDefaultDownloadFolder']();
 }
 
+export function GetFailedTransfers() {
+  return window['go']['wailsapp']['App']['GetFailedTransfers']();
+}
+
 export function GetFileLoggingSettings() {
   return window['go']['wailsapp']['App']['GetFileLoggingSettings']();
 }
@@ -142,14 +206,30 @@ export function GetJobRows() {
   return window['go']['wailsapp']['App']['GetJobRows']();
 }
 
+export function GetJobTarContents(arg1) {
+  return window['go']['wailsapp']['App']['GetJobTarContents'](arg1);
+}
+
 export function GetJobsStats() {
   return window['go']['wailsapp']['App']['GetJobsStats']();
 }
 
+export function GetLastNetworkTest() {
+  return window['go']['wailsapp']['App']['GetLastNetworkTest']();
+}
+
+export function GetLiveFiles(arg1, arg2) {
+  return window['go']['wailsapp']['App']['GetLiveFiles'](arg1, arg2);
+}
+
 export function GetLocalFilesInfo(arg1) {
   return window['go']['wailsapp']['App']['GetLocalFilesInfo'](arg1);
 }
 
+export function GetLocalRoots() {
+  return window['go']['wailsapp']['App']['GetLocalRoots']();
+}
+
 export function GetLogFileLocation() {
   return window['go']['wailsapp']['App']['GetLogFileLocation']();
 }
@@ -166,18 +246,38 @@ export function GetMyLibraryFolderID() {
   return window['go']['wailsapp']['App']['GetMyLibraryFolderID']();
 }
 
+export function GetNetworkUsage() {
+  return window['go']['wailsapp']['App']['GetNetworkUsage']();
+}
+
 export function GetRunHistory() {
   return window['go']['wailsapp']['App']['GetRunHistory']();
 }
 
+export function GetRunReportPath(arg1) {
+  return window['go']['wailsapp']['App']['GetRunReportPath'](arg1);
+}
+
 export function GetRunStatus() {
   return window['go']['wailsapp']['App']['GetRunStatus']();
 }
 
+export function GetSendToMenu() {
+  return window['go']['wailsapp']['App']['GetSendToMenu']();
+}
+
 export function GetServiceStatus() {
   return window['go']['wailsapp']['App']['GetServiceStatus']();
 }
 
+export function GetTeamStorageUsage() {
+  return window['go']['wailsapp']['App']['GetTeamStorageUsage']();
+}
+
+export function GetTemplateRepo() {
+  return window['go']['wailsapp']['App']['GetTemplateRepo']();
+}
+
 export function GetTransferBatches() {
   return window['go']['wailsapp']['App']['GetTransferBatches']();
 }
@@ -194,10 +294,30 @@ export function GetUngroupedTransferTasks() {
   return window['go']['wailsapp']['App']['GetUngroupedTransferTasks']();
 }
 
+export function GiveUpTransfer(arg1) {
+  return window['go']['wailsapp']['App']['GiveUpTransfer'](arg1);
+}
+
+export function ImportJobFromRescale(arg1) {
+  return window['go']['wailsapp']['App']['ImportJobFromRescale'](arg1);
+}
+
+export function ImportRepoTemplate(arg1, arg2, arg3) {
+  return window['go']['wailsapp']['App']['ImportRepoTemplate'](arg1, arg2, arg3);
+}
+
 export function InstallAndStartServiceElevated() {
   return window['go']['wailsapp']['App']['InstallAndStartServiceElevated']();
 }
 
+export function InstallSelfUpdate() {
+  return window['go']['wailsapp']['App']['InstallSelfUpdate']();
+}
+
+export function IsViewerMode() {
+  return window['go']['wailsapp']['App']['IsViewerMode']();
+}
+
 export function ListLocalDirectory(arg1) {
   return window['go']['wailsapp']['App']['ListLocalDirectory'](arg1);
 }
@@ -214,6 +334,10 @@ export function ListRemoteFolderPage(arg1, arg2, arg3) {
   return window['go']['wailsapp']['App']['ListRemoteFolderPage'](arg1, arg2, arg3);
 }
 
+export function ListRemoteFolderWindow(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['wailsapp']['App']['ListRemoteFolderWindow'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function ListRemoteLegacy(arg1, arg2) {
   return window['go']['wailsapp']['App']['ListRemoteLegacy'](arg1, arg2);
 }
@@ -226,6 +350,14 @@ export function ListSavedTemplates() {
   return window['go']['wailsapp']['App']['ListSavedTemplates']();
 }
 
+export function ListTeamJobs(arg1) {
+  return window['go']['wailsapp']['App']['ListTeamJobs'](arg1);
+}
+
+export function ListWorkspaces() {
+  return window['go']['wailsapp']['App']['ListWorkspaces']();
+}
+
 export function LoadConfigFromPath(arg1) {
   return window['go']['wailsapp']['App']['LoadConfigFromPath'](arg1);
 }
@@ -254,10 +386,22 @@ export function OpenLogsDirectory() {
   return window['go']['wailsapp']['App']['OpenLogsDirectory']();
 }
 
+export function OpenRunReport(arg1) {
+  return window['go']['wailsapp']['App']['OpenRunReport'](arg1);
+}
+
 export function PauseDaemon() {
   return window['go']['wailsapp']['App']['PauseDaemon']();
 }
 
+export function PlanUploadNames(arg1, arg2, arg3) {
+  return window['go']['wailsapp']['App']['PlanUploadNames'](arg1, arg2, arg3);
+}
+
+export function PredictJobRuntime(arg1) {
+  return window['go']['wailsapp']['App']['PredictJobRuntime'](arg1);
+}
+
 export function PreviewCommandPatterns(arg1, arg2) {
   return window['go']['wailsapp']['App']['PreviewCommandPatterns'](arg1, arg2);
 }
@@ -274,10 +418,18 @@ export function ReloadDaemonConfig() {
   return window['go']['wailsapp']['App']['ReloadDaemonConfig']();
 }
 
+export function RemoveLocalRoot(arg1) {
+  return window['go']['wailsapp']['App']['RemoveLocalRoot'](arg1);
+}
+
 export function ResetRun() {
   return window['go']['wailsapp']['App']['ResetRun']();
 }
 
+export function ResolveDownloadDestination(arg1) {
+  return window['go']['wailsapp']['App']['ResolveDownloadDestination'](arg1);
+}
+
 export function ResumeDaemon() {
   return window['go']['wailsapp']['App']['ResumeDaemon']();
 }
@@ -290,10 +442,18 @@ export function RetryFailedInDaemonBatch(arg1) {
   return window['go']['wailsapp']['App']['RetryFailedInDaemonBatch'](arg1);
 }
 
+export function RetryFailedTransfers() {
+  return window['go']['wailsapp']['App']['RetryFailedTransfers']();
+}
+
 export function RetryTransfer(arg1) {
   return window['go']['wailsapp']['App']['RetryTransfer'](arg1);
 }
 
+export function RunNetworkTest() {
+  return window['go']['wailsapp']['App']['RunNetworkTest']();
+}
+
 export function SaveConfig() {
   return window['go']['wailsapp']['App']['SaveConfig']();
 }
@@ -358,10 +518,26 @@ export function SelectMultipleFiles(arg1) {
   return window['go']['wailsapp']['App']['SelectMultipleFiles'](arg1);
 }
 
+export function SetCoreTypeFavorite(arg1, arg2) {
+  return window['go']['wailsapp']['App']['SetCoreTypeFavorite'](arg1, arg2);
+}
+
 export function SetFileLoggingEnabled(arg1) {
   return window['go']['wailsapp']['App']['SetFileLoggingEnabled'](arg1);
 }
 
+export function SetRepoTemplatePinned(arg1, arg2) {
+  return window['go']['wailsapp']['App']['SetRepoTemplatePinned'](arg1, arg2);
+}
+
+export function SetSendToMenu(arg1) {
+  return window['go']['wailsapp']['App']['SetSendToMenu'](arg1);
+}
+
+export function SetWorkspace(arg1) {
+  return window['go']['wailsapp']['App']['SetWorkspace'](arg1);
+}
+
 export function StartBulkRun(arg1) {
   return window['go']['wailsapp']['App']['StartBulkRun'](arg1);
 }
@@ -398,10 +574,18 @@ export function StopDaemon() {
   return window['go']['wailsapp']['App']['StopDaemon']();
 }
 
+export function StopJob(arg1) {
+  return window['go']['wailsapp']['App']['StopJob'](arg1);
+}
+
 export function StopServiceElevated() {
   return window['go']['wailsapp']['App']['StopServiceElevated']();
 }
 
+export function StopTeamJobs(arg1) {
+  return window['go']['wailsapp']['App']['StopTeamJobs'](arg1);
+}
+
 export function TestAutoDownloadConnection(arg1) {
   return window['go']['wailsapp']['App']['TestAutoDownloadConnection'](arg1);
 }
@@ -422,6 +606,14 @@ export function UpdateConfig(arg1) {
   return window['go']['wailsapp']['App']['UpdateConfig'](arg1);
 }
 
+export function UpdateRepoTemplates() {
+  return window['go']['wailsapp']['App']['UpdateRepoTemplates']();
+}
+
+export function UploadLiveFile(arg1, arg2, arg3, arg4) {
+  return window['go']['wailsapp']['App']['UploadLiveFile'](arg1, arg2, arg3, arg4);
+}
+
 export function ValidateAutoDownloadPreFlight(arg1) {
   return window['go']['wailsapp']['App']['ValidateAutoDownloadPreFlight'](arg1);
 }
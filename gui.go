@@ -0,0 +1,49 @@
+//go:build !nogui
+
+package main
+
+import (
+	"embed"
+	"os"
+	"runtime"
+
+	"github.com/rescale/rescale-int/internal/wailsapp"
+)
+
+//go:embed all:frontend/dist
+var assets embed.FS
+
+// guiAvailable reports whether this build includes the GUI.
+const guiAvailable = true
+
+// fipsMode names the binary in FIPS compliance messages.
+const fipsMode = "wails"
+
+func init() {
+	// Linux-only environment mitigations. Must be set before GTK / WebKit
+	// initialization so the underlying libraries pick them up on first use.
+	if runtime.GOOS == "linux" {
+		if os.Getenv("GTK_IM_MODULE") == "" {
+			os.Setenv("GTK_IM_MODULE", "none")
+		}
+		// Disable GVFS remote-backend enumeration in the file chooser. Interlink
+		// does not use GVFS remote paths; on some VDIs (notably RHEL 9) a broken
+		// GVFS / D-Bus setup can crash the chooser during widget construction.
+		// Opt out with RESCALE_ENABLE_GVFS=1 if a user relies on GVFS.
+		if os.Getenv("RESCALE_ENABLE_GVFS") != "1" && os.Getenv("GIO_USE_VFS") == "" {
+			os.Setenv("GIO_USE_VFS", "local")
+		}
+		// Force WebKitGTK off the DMA-BUF rendering path, which is known-broken
+		// on RHEL 9 and contributes to GPU-related modal-dialog instability.
+		// Opt out with RESCALE_GPU_ACCEL=1 if a user wants accelerated rendering.
+		if os.Getenv("RESCALE_GPU_ACCEL") != "1" && os.Getenv("WEBKIT_DISABLE_DMABUF_RENDERER") == "" {
+			os.Setenv("WEBKIT_DISABLE_DMABUF_RENDERER", "1")
+		}
+	}
+}
+
+// runGUI launches the Wails GUI.
+func runGUI() error {
+	wailsapp.Assets = assets
+	return wailsapp.Run(os.Args)
+}
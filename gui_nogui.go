@@ -0,0 +1,20 @@
+//go:build nogui
+
+// CLI-only build of the unified binary (`-tags nogui`, see Makefile:
+// build-linux-*-cli). The Wails GUI and its WebKit/GTK dependencies are
+// compiled out, so the binary runs on hosts without a desktop stack.
+package main
+
+import "errors"
+
+// guiAvailable reports whether this build includes the GUI.
+const guiAvailable = false
+
+// fipsMode names the binary in FIPS compliance messages.
+const fipsMode = "cli"
+
+// runGUI fails: --gui was given to a build without the GUI.
+func runGUI() error {
+	return errors.New("--gui is not available in this CLI-only build (built with -tags nogui); " +
+		"use rescale-int-gui for the graphical interface")
+}
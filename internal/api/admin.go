@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	neturl "net/url"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// ListOrganizationJobs lists jobs submitted by all members of the
+// organization, newest first, stopping at the first page whose jobs are all
+// older than cutoff. Requires org admin permissions; a 403 is returned as
+// ErrAdminRequired.
+func (c *Client) ListOrganizationJobs(ctx context.Context, orgCode string, cutoff time.Time) ([]models.OrgJob, error) {
+	var allJobs []models.OrgJob
+	nextURL := fmt.Sprintf("/api/v2/organizations/%s/jobs/?ordering=-dateInserted", neturl.PathEscape(orgCode))
+	pageCount := 0
+
+	for nextURL != "" {
+		pageCount++
+		if pageCount > constants.MaxPaginationPages {
+			log.Printf("Warning: Pagination limit reached after %d pages (%d organization jobs fetched)", pageCount-1, len(allJobs))
+			break
+		}
+
+		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == nethttp.StatusForbidden {
+			resp.Body.Close()
+			return nil, fmt.Errorf("list organization jobs: %w", ErrAdminRequired)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			body := readResponseBody(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("list organization jobs failed: status %d: %s", resp.StatusCode, body)
+		}
+
+		var result struct {
+			Next    *string         `json:"next"`
+			Results []models.OrgJob `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode organization jobs response: %w", err)
+		}
+		resp.Body.Close()
+
+		oldJobsOnPage := 0
+		for _, job := range result.Results {
+			if createdAt, err := time.Parse(time.RFC3339, job.CreatedAt); err == nil && createdAt.Before(cutoff) {
+				oldJobsOnPage++
+				continue
+			}
+			allJobs = append(allJobs, job)
+		}
+		if oldJobsOnPage == len(result.Results) && len(result.Results) > 0 {
+			break
+		}
+
+		if result.Next != nil && *result.Next != "" {
+			nextURL = c.pagePath(*result.Next)
+		} else {
+			nextURL = ""
+		}
+	}
+
+	return allJobs, nil
+}
+
+// GetOrganizationStorageUsage returns per-member storage use for the
+// organization. Requires org admin permissions; a 403 is returned as
+// ErrAdminRequired.
+func (c *Client) GetOrganizationStorageUsage(ctx context.Context, orgCode string) ([]models.MemberStorageUsage, error) {
+	var all []models.MemberStorageUsage
+	nextURL := fmt.Sprintf("/api/v2/organizations/%s/storage-usage/", neturl.PathEscape(orgCode))
+	pageCount := 0
+
+	for nextURL != "" {
+		pageCount++
+		if pageCount > constants.MaxPaginationPages {
+			log.Printf("Warning: Pagination limit reached after %d pages (%d members fetched)", pageCount-1, len(all))
+			break
+		}
+
+		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == nethttp.StatusForbidden {
+			resp.Body.Close()
+			return nil, fmt.Errorf("get organization storage usage: %w", ErrAdminRequired)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			body := readResponseBody(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("get organization storage usage failed: status %d: %s", resp.StatusCode, body)
+		}
+
+		var result struct {
+			Next    *string                     `json:"next"`
+			Results []models.MemberStorageUsage `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode storage usage response: %w", err)
+		}
+		resp.Body.Close()
+
+		all = append(all, result.Results...)
+
+		if result.Next != nil && *result.Next != "" {
+			nextURL = c.pagePath(*result.Next)
+		} else {
+			nextURL = ""
+		}
+	}
+
+	return all, nil
+}
+
+// ArchiveJob archives a job: it is hidden from job lists but its files and
+// results are kept. Unlike DeleteJob this is reversible from the platform UI.
+func (c *Client) ArchiveJob(ctx context.Context, jobID string) error {
+	path := fmt.Sprintf("/api/v3/jobs/%s/archive/", jobID)
+
+	resp, err := c.doRequest(ctx, "POST", path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == nethttp.StatusForbidden {
+		return fmt.Errorf("archive job: %w", ErrAdminRequired)
+	}
+	if resp.StatusCode != nethttp.StatusOK && resp.StatusCode != nethttp.StatusAccepted &&
+		resp.StatusCode != nethttp.StatusNoContent {
+		body := readResponseBody(resp.Body)
+		return fmt.Errorf("archive job failed: status %d: %s", resp.StatusCode, body)
+	}
+
+	return nil
+}
@@ -19,8 +19,12 @@ import (
 	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/models"
 	"github.com/rescale/rescale-int/internal/ratelimit"
+	"github.com/rescale/rescale-int/internal/tracing"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // retryLogger implements the retryablehttp.LeveledLogger interface
@@ -107,6 +111,8 @@ type Client struct {
 	config     *config.Config
 	baseURL    string
 	apiKey     string
+	workspace  string                  // Workspace ID sent with every request ("" = the key's default)
+	headers    nethttp.Header          // Static API gateway headers (config api_headers)
 	store      *ratelimit.LimiterStore // Process-level singleton limiter store
 	metrics    *apiMetrics             // API usage tracking
 }
@@ -121,6 +127,14 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	if err := config.ValidatePlatformURL(cfg.APIBaseURL); err != nil {
 		return nil, fmt.Errorf("invalid platform URL %q: %w", cfg.APIBaseURL, err)
 	}
+	// API gateway settings; a bad value surfaces at the connection test
+	if _, err := config.NormalizeAPIBasePath(cfg.APIBasePath); err != nil {
+		return nil, err
+	}
+	headers, err := config.ParseAPIHeaders(cfg.APIHeaders)
+	if err != nil {
+		return nil, err
+	}
 
 	// Configure HTTP client with proxy support
 	httpClient, err := http.ConfigureHTTPClient(cfg)
@@ -137,7 +151,7 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	retryClient.Logger = &retryLogger{} // Enable error/warning logging
 
 	// Capture baseURL and apiKey for use in CheckRetry/Backoff closures
-	clientBaseURL := strings.TrimSuffix(cfg.APIBaseURL, "/")
+	clientBaseURL := cfg.APIRoot()
 	clientAPIKey := cfg.APIKey
 	store := ratelimit.GlobalStore()
 
@@ -237,6 +251,8 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		config:     cfg,
 		baseURL:    clientBaseURL,
 		apiKey:     clientAPIKey,
+		workspace:  strings.TrimSpace(cfg.Workspace),
+		headers:    headers,
 		store:      store,
 		metrics: &apiMetrics{
 			callsByPath:   make(map[string]int64),
@@ -247,12 +263,39 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	}, nil
 }
 
+// pagePath returns the path and query of a pagination "next" URL, for
+// doRequest. Behind an API gateway the platform may return next URLs with
+// or without the gateway's base path, or with its own host; all map to the
+// same path.
+func (c *Client) pagePath(next string) string {
+	u, err := neturl.Parse(next)
+	if err != nil || u.Host == "" {
+		return strings.TrimPrefix(next, c.baseURL)
+	}
+	path := u.Path
+	if root, err := neturl.Parse(c.baseURL); err == nil && root.Path != "" {
+		if rest, ok := strings.CutPrefix(path, root.Path); ok && strings.HasPrefix(rest, "/") {
+			path = rest
+		}
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
 // GetConfig returns the configuration used by this API client
 // This is needed by upload/download modules to configure their HTTP clients with proxy settings
 func (c *Client) GetConfig() *config.Config {
 	return c.config
 }
 
+// WithTimeout returns a context for a call of the given endpoint class,
+// bounded by the configured timeout for that class.
+func (c *Client) WithTimeout(ctx context.Context, class config.APIEndpointClass) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.config.APITimeout(class))
+}
+
 // CloseIdleConnections closes idle connections in the API HTTP client pool.
 // Called after sleep/wake or long idle periods to discard potentially stale connections.
 // Note: This only covers the API client pool. S3/Azure transport pools are separate.
@@ -277,16 +320,39 @@ func readResponseBody(body io.ReadCloser) string {
 // Scope resolution uses the unified registry (ratelimit.Registry) — the same
 // registry is used by the CheckRetry callback for 429 feedback, ensuring
 // consistent scope identification across the request lifecycle.
-func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*nethttp.Response, error) {
+func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (resp *nethttp.Response, err error) {
+	// Viewer mode allows reads only; every other method changes something
+	if method != nethttp.MethodGet && method != nethttp.MethodHead {
+		urlPath, _, _ := strings.Cut(path, "?")
+		if err := viewer.Check(method + " " + urlPath); err != nil {
+			return nil, err
+		}
+	}
+
 	// Resolve scope via the unified registry and get the shared limiter
 	registry := c.store.Registry()
 	scope := registry.ResolveScope(method, path)
 	limiter := c.store.GetLimiter(c.baseURL, c.apiKey, scope)
 
+	// One span per call, covering the rate limiter wait and all retries
+	urlPath, _, _ := strings.Cut(path, "?")
+	ctx, span := tracing.Start(ctx, "API "+method,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", urlPath),
+		attribute.String("rescale.rate_limit_scope", string(scope)))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		tracing.End(span, err)
+	}()
+
 	// Wait for rate limiter to allow request
+	waitStart := time.Now()
 	if err := limiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
 	}
+	span.SetAttributes(attribute.Int64("rescale.rate_limit_wait_ms", time.Since(waitStart).Milliseconds()))
 
 	// Track API call metrics per scope
 	c.metrics.Lock()
@@ -339,15 +405,21 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add headers
+	// Add headers (gateway headers first; none may be one set below)
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
 	req.Header.Set("Authorization", authScheme(c.apiKey)+" "+c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	if c.workspace != "" {
+		req.Header.Set(WorkspaceHeader, c.workspace)
+	}
 	// Note: Go's http.Transport automatically handles Accept-Encoding: gzip
 	// and transparently decompresses responses. Do NOT set this header manually
 	// as it disables automatic decompression (causing JSON decode errors).
 
-	resp, err := c.httpClient.Do(req)
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
 		// Check for specific error types by string matching
 		errStr := err.Error()
@@ -355,7 +427,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		// Don't log context canceled errors - they're expected during shutdown
 		// Only log in debug mode (RESCALE_DEBUG=1) to keep GUI console clean
 		if !strings.Contains(errStr, "context canceled") && os.Getenv("RESCALE_DEBUG") != "" {
-			log.Printf("❌ API call failed: %s %s - Error: %v", method, path, err)
+			logging.Printf(ctx, "❌ API call failed: %s %s - Error: %v", method, path, err)
 			if strings.Contains(errStr, "timeout") {
 				log.Printf("   └─ Timeout error (client timeout or network issue)")
 			}
@@ -377,7 +449,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		// Use the same registry for scope identification as doRequest routing
 		scopeDisplay := registry.ScopeDisplayString(scope)
 
-		log.Printf("⚠️  THROTTLED: %s %s - Rate limit exceeded on '%s' scope", method, path, scopeDisplay)
+		logging.Printf(ctx, "⚠️  THROTTLED: %s %s - Rate limit exceeded on '%s' scope", method, path, scopeDisplay)
 
 		// Drain the limiter for this scope to prevent further requests
 		limiter.Drain()
@@ -424,6 +496,23 @@ func (c *Client) GetUserProfile(ctx context.Context) (*models.UserProfile, error
 	return &profile, nil
 }
 
+// ProbeAPI issues a lightweight authenticated request (the current user's
+// profile) and returns the response headers without decoding the body.
+// Used by the network test to time round trips and read proxy Via headers.
+func (c *Client) ProbeAPI(ctx context.Context) (nethttp.Header, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v3/users/me/", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != nethttp.StatusOK {
+		return nil, fmt.Errorf("API probe failed: status %d", resp.StatusCode)
+	}
+	return resp.Header, nil
+}
+
 // GetStorageCredentials gets temporary credentials for storage
 // If fileInfo is provided, gets credentials for that file's specific storage (allows cross-storage downloads)
 // If fileInfo is nil, gets credentials for user's default storage
@@ -651,6 +740,13 @@ func (c *Client) CreateJob(ctx context.Context, jobReq models.JobRequest) (*mode
 		return nil, fmt.Errorf("failed to decode job response: %w", err)
 	}
 
+	// Every job Interlink creates carries the marker tag the org admin view
+	// lists by, whatever default_tags says. The platform ignores tags in the
+	// creation body. Non-fatal: the job exists.
+	if err := c.AddJobTag(ctx, job.ID, constants.DefaultAdminJobTag); err != nil {
+		logging.Printf(ctx, "[WARN] Failed to tag job %s as %q: %v", job.ID, constants.DefaultAdminJobTag, err)
+	}
+
 	return &job, nil
 }
 
@@ -711,58 +807,45 @@ func (c *Client) GetJob(ctx context.Context, jobID string) (*models.JobResponse,
 	return &job, nil
 }
 
+// ListJobs lists all jobs. Pages are checked for items that shift while
+// jobs are being submitted or deleted (see listChecked).
 func (c *Client) ListJobs(ctx context.Context) ([]models.JobResponse, error) {
-	var allJobs []models.JobResponse
-	nextURL := "/api/v3/jobs/"
-	pageCount := 0
-
-	for nextURL != "" {
-		// Pagination safety: prevent infinite loops from malformed API responses
-		pageCount++
-		if pageCount > constants.MaxPaginationPages {
-			log.Printf("Warning: Pagination limit reached after %d pages (%d jobs fetched)", pageCount-1, len(allJobs))
-			break
-		}
-		if pageCount == constants.PaginationWarningThreshold {
-			log.Printf("Warning: Approaching pagination limit (page %d of %d)", pageCount, constants.MaxPaginationPages)
-		}
-
-		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		if resp.StatusCode != nethttp.StatusOK {
-			body := readResponseBody(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("list jobs failed: status %d: %s", resp.StatusCode, body)
-		}
-
-		var result struct {
-			Count   int                  `json:"count"`
-			Next    *string              `json:"next"`
-			Results []models.JobResponse `json:"results"`
-		}
+	jobs, _, err := listChecked(ctx, "jobs", "/api/v3/jobs/", c.fetchJobsPage,
+		func(j models.JobResponse) string { return j.ID })
+	return jobs, err
+}
 
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode jobs response: %w", err)
-		}
+// fetchJobsPage fetches one page of the v3 job list.
+func (c *Client) fetchJobsPage(ctx context.Context, url string) (listPage[models.JobResponse], error) {
+	var page listPage[models.JobResponse]
 
-		// Close body immediately after reading - don't use defer in loop
-		resp.Body.Close()
+	resp, err := c.doRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return page, err
+	}
+	defer resp.Body.Close()
 
-		allJobs = append(allJobs, result.Results...)
+	if resp.StatusCode != nethttp.StatusOK {
+		body := readResponseBody(resp.Body)
+		return page, fmt.Errorf("list jobs failed: status %d: %s", resp.StatusCode, body)
+	}
 
-		if result.Next != nil && *result.Next != "" {
-			// Extract path from full URL
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
-		} else {
-			nextURL = ""
-		}
+	var result struct {
+		Count   int                  `json:"count"`
+		Next    *string              `json:"next"`
+		Results []models.JobResponse `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return page, fmt.Errorf("failed to decode jobs response: %w", err)
 	}
 
-	return allJobs, nil
+	page.Items = result.Results
+	page.Count = result.Count
+	if result.Next != nil && *result.Next != "" {
+		// Extract path from full URL
+		page.Next = c.pagePath(*result.Next)
+	}
+	return page, nil
 }
 
 // ListJobsWithCutoff lists jobs ordered by dateInserted (newest first) and stops
@@ -838,7 +921,7 @@ func (c *Client) ListJobsWithCutoff(ctx context.Context, cutoff time.Time) ([]mo
 		}
 
 		if result.Next != nil && *result.Next != "" {
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
+			nextURL = c.pagePath(*result.Next)
 		} else {
 			nextURL = ""
 		}
@@ -895,7 +978,7 @@ func (c *Client) GetCoreTypes(ctx context.Context, includeInactive bool) ([]mode
 
 		if result.Next != nil && *result.Next != "" {
 			// Extract path from full URL
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
+			nextURL = c.pagePath(*result.Next)
 		} else {
 			nextURL = ""
 		}
@@ -946,7 +1029,7 @@ func (c *Client) GetAnalyses(ctx context.Context) ([]models.Analysis, error) {
 
 		if result.Next != nil && *result.Next != "" {
 			// Extract path from full URL
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
+			nextURL = c.pagePath(*result.Next)
 		} else {
 			nextURL = ""
 		}
@@ -955,6 +1038,55 @@ func (c *Client) GetAnalyses(ctx context.Context) ([]models.Analysis, error) {
 	return allAnalyses, nil
 }
 
+// GetProjects retrieves the projects of an organization, used to validate
+// job ProjectIDs before submission. Handles pagination.
+func (c *Client) GetProjects(ctx context.Context, orgCode string) ([]models.Project, error) {
+	var allProjects []models.Project
+	nextURL := fmt.Sprintf("/api/v2/organizations/%s/projects/", neturl.PathEscape(orgCode))
+	pageCount := 0
+
+	for nextURL != "" {
+		pageCount++
+		if pageCount > constants.MaxPaginationPages {
+			log.Printf("Warning: Pagination limit reached after %d pages (%d projects fetched)", pageCount-1, len(allProjects))
+			break
+		}
+
+		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != nethttp.StatusOK {
+			body := readResponseBody(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("get projects failed: status %d: %s", resp.StatusCode, body)
+		}
+
+		var result struct {
+			Count   int              `json:"count"`
+			Next    *string          `json:"next"`
+			Results []models.Project `json:"results"`
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode projects response: %w", err)
+		}
+		resp.Body.Close()
+
+		allProjects = append(allProjects, result.Results...)
+
+		if result.Next != nil && *result.Next != "" {
+			nextURL = c.pagePath(*result.Next)
+		} else {
+			nextURL = ""
+		}
+	}
+
+	return allProjects, nil
+}
+
 func (c *Client) ListFiles(ctx context.Context, limit int) ([]interface{}, error) {
 	if limit <= 0 {
 		limit = 20
@@ -1090,6 +1222,7 @@ type FolderContents struct {
 	PrevURL  string // URL to fetch previous page (empty if on first page)
 	PageSize int    // Number of items per page (from API)
 	HasMore  bool   // True if there are more pages after this one
+	Count    int    // Total items in the folder across all pages (0 if not reported)
 }
 
 // FolderInfo represents basic folder information
@@ -1097,6 +1230,7 @@ type FolderInfo struct {
 	ID           string
 	Name         string
 	DateUploaded time.Time
+	Position     int // Index in the API page; restores server ordering across Folders/Files
 }
 
 // FileInfo represents file information from folder contents listing.
@@ -1105,6 +1239,7 @@ type FileInfo struct {
 	Name          string
 	DecryptedSize int64
 	DateUploaded  time.Time
+	Position      int // Index in the API page; restores server ordering across Folders/Files
 
 	// SymlinkID is the filesymlink id from trash listings, taken from item.id.
 	// It is the value the bulk recover/delete API expects in filesymlink_ids.
@@ -1293,6 +1428,21 @@ func (c *Client) ListFolderContentsPage(ctx context.Context, folderID, pageURL s
 	return c.fetchFolderContentsPage(ctx, url)
 }
 
+// ListFolderContentsWindow fetches one page of folder contents by page number
+// (1-based) in the given server-side ordering, e.g. "name", "-decryptedSize"
+// or "dateUploaded". Unlike cursor paging this allows random access, so a
+// browser can fetch only the part of a 100k-item folder that is on screen.
+// The returned Count is the folder's total item count.
+func (c *Client) ListFolderContentsWindow(ctx context.Context, folderID, ordering string, page, pageSize int) (*FolderContents, error) {
+	q := neturl.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(pageSize))
+	if ordering != "" {
+		q.Set("ordering", ordering)
+	}
+	return c.fetchFolderContentsPage(ctx, fmt.Sprintf("/api/v3/folders/%s/contents/?%s", folderID, q.Encode()))
+}
+
 // fetchFolderContentsPage issues a GET to the given URL and parses the
 // standard folder-contents response shape (results / next / previous).
 // Shared by folder listings and trash-bin listings.
@@ -1314,6 +1464,7 @@ func (c *Client) fetchFolderContentsPage(ctx context.Context, url string) (*Fold
 	}
 
 	var result struct {
+		Count    int                      `json:"count"`
 		Results  []map[string]interface{} `json:"results"`
 		Next     *string                  `json:"next"`
 		Previous *string                  `json:"previous"`
@@ -1324,8 +1475,9 @@ func (c *Client) fetchFolderContentsPage(ctx context.Context, url string) (*Fold
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 	resp.Body.Close()
+	contents.Count = result.Count
 
-	for _, entry := range result.Results {
+	for pos, entry := range result.Results {
 		itemType := getStringField(entry, "type", "folderContents")
 		itemData, ok := entry["item"].(map[string]interface{})
 		if !ok {
@@ -1336,8 +1488,9 @@ func (c *Client) fetchFolderContentsPage(ctx context.Context, url string) (*Fold
 			if id, ok := itemData["id"].(string); ok {
 				if name, ok := itemData["name"].(string); ok {
 					folder := FolderInfo{
-						ID:   id,
-						Name: name,
+						ID:       id,
+						Name:     name,
+						Position: pos,
 					}
 					if dateStr, ok := itemData["dateUploaded"].(string); ok {
 						if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
@@ -1383,6 +1536,7 @@ func (c *Client) fetchFolderContentsPage(ctx context.Context, url string) (*Fold
 				ID:            id,
 				Name:          name,
 				DecryptedSize: size,
+				Position:      pos,
 				SymlinkID:     symlinkID,
 			}
 			file.EncodedEncryptionKey, _ = itemData["encodedEncryptionKey"].(string)
@@ -1539,35 +1693,59 @@ func extractAPIPath(url string) string {
 	return url
 }
 
+// folderEntry is one item of a folder listing: a subfolder or a file.
+type folderEntry struct {
+	folder *FolderInfo
+	file   *FileInfo
+}
+
+func (e folderEntry) key() string {
+	if e.folder != nil {
+		return "folder:" + e.folder.ID
+	}
+	return "file:" + e.file.ID
+}
+
 // ListFolderContentsAll fetches ALL pages of folder contents (may be slow for large folders).
 // Use this when you need the complete list, not for interactive browsing.
+// Pages are checked for items that shift while the folder changes (see listChecked).
 func (c *Client) ListFolderContentsAll(ctx context.Context, folderID string) (*FolderContents, error) {
-	contents := &FolderContents{
-		Folders: make([]FolderInfo, 0),
-		Files:   make([]FileInfo, 0),
-	}
-
-	nextURL := fmt.Sprintf("/api/v3/folders/%s/contents/", folderID)
-	pageCount := 0
-
-	for nextURL != "" {
-		pageCount++
-		if pageCount > constants.MaxPaginationPages {
-			return nil, fmt.Errorf("pagination limit exceeded: %d pages (%d items), folder too large",
-				pageCount-1, len(contents.Folders)+len(contents.Files))
-		}
-
+	fetch := func(ctx context.Context, url string) (listPage[folderEntry], error) {
 		// Use page_size=1000 (API max) to reduce pagination calls
-		page, err := c.ListFolderContentsPage(ctx, folderID, nextURL, 1000)
+		page, err := c.ListFolderContentsPage(ctx, folderID, url, 1000)
 		if err != nil {
-			return nil, err
+			return listPage[folderEntry]{}, err
+		}
+		entries := make([]folderEntry, 0, len(page.Folders)+len(page.Files))
+		for i := range page.Folders {
+			entries = append(entries, folderEntry{folder: &page.Folders[i]})
+		}
+		for i := range page.Files {
+			entries = append(entries, folderEntry{file: &page.Files[i]})
 		}
+		return listPage[folderEntry]{Items: entries, Next: page.NextURL, Count: page.Count}, nil
+	}
 
-		contents.Folders = append(contents.Folders, page.Folders...)
-		contents.Files = append(contents.Files, page.Files...)
-		nextURL = page.NextURL
+	entries, report, err := listChecked(ctx, "folder items", fmt.Sprintf("/api/v3/folders/%s/contents/", folderID), fetch, folderEntry.key)
+	if err != nil {
+		return nil, err
+	}
+	if report.Truncated {
+		return nil, fmt.Errorf("pagination limit exceeded: %d pages (%d items), folder too large",
+			report.Pages, len(entries))
 	}
 
+	contents := &FolderContents{
+		Folders: make([]FolderInfo, 0),
+		Files:   make([]FileInfo, 0),
+	}
+	for _, e := range entries {
+		if e.folder != nil {
+			contents.Folders = append(contents.Folders, *e.folder)
+		} else {
+			contents.Files = append(contents.Files, *e.file)
+		}
+	}
 	return contents, nil
 }
 
@@ -1588,6 +1766,10 @@ func (c *Client) ListFolderContentsStreaming(
 ) error {
 	nextURL := fmt.Sprintf("/api/v3/folders/%s/contents/", folderID)
 	pageCount := 0
+	// Pages already handed to onPage cannot be re-listed, but items that
+	// shift onto a later page while the folder changes are dropped here.
+	seen := make(map[string]bool)
+	duplicates := 0
 
 	for nextURL != "" {
 		pageCount++
@@ -1603,13 +1785,35 @@ func (c *Client) ListFolderContentsStreaming(
 			return err
 		}
 
-		if err := onPage(page.Folders, page.Files); err != nil {
+		folders := page.Folders[:0]
+		for _, f := range page.Folders {
+			if k := "folder:" + f.ID; !seen[k] {
+				seen[k] = true
+				folders = append(folders, f)
+			} else {
+				duplicates++
+			}
+		}
+		files := page.Files[:0]
+		for _, f := range page.Files {
+			if k := "file:" + f.ID; !seen[k] {
+				seen[k] = true
+				files = append(files, f)
+			} else {
+				duplicates++
+			}
+		}
+
+		if err := onPage(folders, files); err != nil {
 			return err
 		}
 
 		nextURL = page.NextURL
 	}
 
+	if duplicates > 0 {
+		log.Printf("Warning: folder %s changed during pagination (%d duplicate items dropped)", folderID, duplicates)
+	}
 	return nil
 }
 
@@ -1797,56 +2001,45 @@ func (c *Client) GetJobStatuses(ctx context.Context, jobID string) ([]models.Job
 
 // ListJobFiles lists output files for a job (with pagination).
 // Uses the v2 endpoint which has a much higher rate limit (jobs-usage scope)
-// compared to the v3 user scope.
+// compared to the v3 user scope. Pages are checked for files that shift
+// while a running job is still writing outputs (see listChecked).
 func (c *Client) ListJobFiles(ctx context.Context, jobID string) ([]models.JobFile, error) {
-	var allFiles []models.JobFile
-	nextURL := fmt.Sprintf("/api/v2/jobs/%s/files/", jobID)
-	pageCount := 0
-
-	for nextURL != "" {
-		pageCount++
-		if pageCount > constants.MaxPaginationPages {
-			log.Printf("Warning: Pagination limit reached after %d pages (%d files fetched)", pageCount-1, len(allFiles))
-			break
-		}
-		if pageCount == constants.PaginationWarningThreshold {
-			log.Printf("Warning: Approaching pagination limit (page %d of %d)", pageCount, constants.MaxPaginationPages)
-		}
-
-		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		if resp.StatusCode != nethttp.StatusOK {
-			body := readResponseBody(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("list job files failed: status %d: %s", resp.StatusCode, body)
-		}
+	files, _, err := listChecked(ctx, "job files", fmt.Sprintf("/api/v2/jobs/%s/files/", jobID), c.fetchJobFilesPage,
+		func(f models.JobFile) string { return f.ID })
+	return files, err
+}
 
-		var result struct {
-			Count   int              `json:"count"`
-			Next    *string          `json:"next"`
-			Results []models.JobFile `json:"results"`
-		}
+// fetchJobFilesPage fetches one page of a job's output files.
+func (c *Client) fetchJobFilesPage(ctx context.Context, url string) (listPage[models.JobFile], error) {
+	var page listPage[models.JobFile]
 
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode job files response: %w", err)
-		}
-		resp.Body.Close()
+	resp, err := c.doRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return page, err
+	}
+	defer resp.Body.Close()
 
-		allFiles = append(allFiles, result.Results...)
+	if resp.StatusCode != nethttp.StatusOK {
+		body := readResponseBody(resp.Body)
+		return page, fmt.Errorf("list job files failed: status %d: %s", resp.StatusCode, body)
+	}
 
-		if result.Next != nil && *result.Next != "" {
-			// Extract path from full URL
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
-		} else {
-			nextURL = ""
-		}
+	var result struct {
+		Count   int              `json:"count"`
+		Next    *string          `json:"next"`
+		Results []models.JobFile `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return page, fmt.Errorf("failed to decode job files response: %w", err)
 	}
 
-	return allFiles, nil
+	page.Items = result.Results
+	page.Count = result.Count
+	if result.Next != nil && *result.Next != "" {
+		// Extract path from full URL
+		page.Next = c.pagePath(*result.Next)
+	}
+	return page, nil
 }
 
 // GetJobRuns lists runs for a job via the v2 API.
@@ -1888,7 +2081,7 @@ func (c *Client) GetJobRuns(ctx context.Context, jobID string) ([]models.JobRun,
 		allRuns = append(allRuns, result.Results...)
 
 		if result.Next != nil && *result.Next != "" {
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
+			nextURL = c.pagePath(*result.Next)
 		} else {
 			nextURL = ""
 		}
@@ -1936,7 +2129,7 @@ func (c *Client) GetRunFiles(ctx context.Context, jobID, runID string) ([]models
 		allFiles = append(allFiles, result.Results...)
 
 		if result.Next != nil && *result.Next != "" {
-			nextURL = strings.TrimPrefix(*result.Next, c.baseURL)
+			nextURL = c.pagePath(*result.Next)
 		} else {
 			nextURL = ""
 		}
@@ -2123,6 +2316,31 @@ func (c *Client) GetJobCustomFieldValue(ctx context.Context, jobID, fieldName st
 	return "", nil // Field not found
 }
 
+// SetJobCustomFields sets custom field values on a job, by field name. The
+// fields must already be defined for jobs in the user's workspace.
+// Body mirrors the GET format: {"fieldName": {"value": "..."}, ...}
+func (c *Client) SetJobCustomFields(ctx context.Context, jobID string, values map[string]string) error {
+	path := fmt.Sprintf("/api/v3/jobs/%s/custom-fields/", jobID)
+
+	body := make(map[string]map[string]string, len(values))
+	for name, value := range values {
+		body[name] = map[string]string{"value": value}
+	}
+
+	resp, err := c.doRequest(ctx, "PATCH", path, body)
+	if err != nil {
+		return fmt.Errorf("failed to set job custom fields: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK && resp.StatusCode != nethttp.StatusNoContent {
+		respBody := readResponseBody(resp.Body)
+		return fmt.Errorf("set job custom fields failed: status %d: %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
 // WorkspaceCustomFieldsResponse represents the API response for workspace custom fields.
 // Endpoint: GET /api/v2/organizations/{company_code}/workspaces/{workspace_id}/custom-fields/
 type WorkspaceCustomFieldsResponse struct {
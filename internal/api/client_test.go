@@ -11,7 +11,14 @@ import (
 	"testing"
 
 	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // TestNewClientRejectsEmptyBaseURL verifies that NewClient fails with a clear error
@@ -233,6 +240,38 @@ func TestListTrashBinPage_ParsesFilesymlinkID(t *testing.T) {
 	}
 }
 
+func TestListFolderContentsWindow_SendsPageAndOrdering(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/folders/fold-1/contents/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("page") != "3" || q.Get("page_size") != "200" || q.Get("ordering") != "-name" {
+			t.Errorf("query = %s, want page=3 page_size=200 ordering=-name", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": 120345,
+			"results": []map[string]interface{}{
+				{"type": "file", "item": map[string]interface{}{"id": "f1", "name": "zz.dat", "decryptedSize": 10}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	contents, err := newTestClient(t, server.URL).ListFolderContentsWindow(context.Background(), "fold-1", "-name", 3, 200)
+	if err != nil {
+		t.Fatalf("ListFolderContentsWindow() error = %v", err)
+	}
+	if contents.Count != 120345 {
+		t.Errorf("Count = %d, want 120345", contents.Count)
+	}
+	if len(contents.Files) != 1 || contents.Files[0].Name != "zz.dat" {
+		t.Errorf("Files = %+v", contents.Files)
+	}
+}
+
 func TestPostTrashBinAction_SendsMixedPayload(t *testing.T) {
 	var gotPayload map[string][]string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -754,3 +793,148 @@ func TestArchiveContents_EncodesEmptyListsNotNull(t *testing.T) {
 		t.Errorf("body should encode empty lists as [], not null: %s", raw)
 	}
 }
+
+func TestDoRequest_GatewayBasePathAndHeaders(t *testing.T) {
+	var server *httptest.Server
+	var pages []string
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Org-Token") != "abc=123" || r.Header.Get("Authorization") != "Token test-key" {
+			http.Error(w, "missing gateway headers", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/gw/rescale/api/v3/users/me/workspaces/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		pages = append(pages, r.URL.RawQuery)
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]string{{"id": "ws2"}},
+			})
+			return
+		}
+		// The platform behind the gateway doesn't know its prefix
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"next":    "https://platform.rescale.com/api/v3/users/me/workspaces/?page=2",
+			"results": []map[string]string{{"id": "ws1"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClientForTest(&config.Config{
+		APIBaseURL:  server.URL,
+		APIKey:      "test-key",
+		APIBasePath: "gw/rescale/",
+		APIHeaders:  "X-Org-Token: abc=123",
+	})
+	workspaces, err := client.ListWorkspaces(context.Background())
+	if err != nil {
+		t.Fatalf("ListWorkspaces through gateway: %v", err)
+	}
+	if len(workspaces) != 2 || len(pages) != 2 || pages[1] != "page=2" {
+		t.Errorf("got %d workspaces from pages %q, want 2 from [\"\" \"page=2\"]", len(workspaces), pages)
+	}
+}
+
+func TestNewClientRejectsInvalidGatewaySettings(t *testing.T) {
+	for _, cfg := range []*config.Config{
+		{APIBaseURL: "https://platform.rescale.com", APIKey: "k", APIHeaders: "Authorization: Bearer x"},
+		{APIBaseURL: "https://platform.rescale.com", APIKey: "k", APIHeaders: "no colon"},
+		{APIBaseURL: "https://platform.rescale.com", APIKey: "k", APIBasePath: "/a/../b"},
+	} {
+		if _, err := NewClient(cfg); err == nil {
+			t.Errorf("NewClient(%q, %q) succeeded, want error", cfg.APIBasePath, cfg.APIHeaders)
+		}
+	}
+}
+
+// TestDoRequest_Span verifies each API call is traced with its method, path
+// (without the query) and response status.
+func TestDoRequest_Span(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	resp, err := client.doRequest(context.Background(), "GET", "/api/v3/jobs/abc/?page=2", nil)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "API GET" {
+		t.Fatalf("ended spans = %v, want one \"API GET\" span", spans)
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["url.path"].AsString(); got != "/api/v3/jobs/abc/" {
+		t.Errorf("url.path = %q, want the path without the query", got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusNotFound {
+		t.Errorf("http.response.status_code = %d, want 404", got)
+	}
+}
+
+func TestDoRequest_ViewerModeAllowsOnlyReads(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"email":"viewer@example.com"}`))
+	}))
+	defer server.Close()
+
+	viewer.SetEnabled(true)
+	defer viewer.SetEnabled(false)
+	client := newTestClient(t, server.URL)
+
+	if _, err := client.GetUserProfile(context.Background()); err != nil {
+		t.Fatalf("GetUserProfile() error = %v, want reads allowed", err)
+	}
+	err := client.SubmitJob(context.Background(), "job1")
+	if !errors.Is(err, viewer.ErrReadOnly) {
+		t.Errorf("SubmitJob() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.DeleteFile(context.Background(), "file1"); !errors.Is(err, viewer.ErrReadOnly) {
+		t.Errorf("DeleteFile() error = %v, want ErrReadOnly", err)
+	}
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("server saw %v, want only the GET", methods)
+	}
+}
+
+func TestCreateJob_AddsInterlinkTag(t *testing.T) {
+	var tagged string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/jobs/":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"job1","name":"t"}`))
+		case "/api/v3/jobs/job1/tags/":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			tagged = body["name"]
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	if _, err := client.CreateJob(context.Background(), models.JobRequest{Name: "t"}); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if tagged != constants.DefaultAdminJobTag {
+		t.Errorf("tag added = %q, want %q", tagged, constants.DefaultAdminJobTag)
+	}
+}
@@ -53,3 +53,7 @@ func IsFileExistsError(err error) bool {
 
 	return false
 }
+
+// ErrAdminRequired indicates the API key lacks organization admin permissions
+// for an org-wide endpoint (HTTP 403).
+var ErrAdminRequired = errors.New("organization admin permissions required")
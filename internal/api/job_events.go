@@ -0,0 +1,67 @@
+// Package api provides the job event (long-poll) channel.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"time"
+)
+
+// ErrJobEventsUnsupported indicates the platform does not expose the job
+// event channel. Callers should fall back to polling job statuses.
+var ErrJobEventsUnsupported = errors.New("job event channel not available on this platform")
+
+// JobEvent is a single job status transition reported by the platform.
+type JobEvent struct {
+	JobID      string `json:"jobId"`
+	Status     string `json:"status"`
+	StatusDate string `json:"statusDate"`
+}
+
+// JobEventsPage is one long-poll response. Cursor is passed back on the next
+// call so no transition is delivered twice or skipped.
+type JobEventsPage struct {
+	Events []JobEvent `json:"results"`
+	Cursor string     `json:"cursor"`
+}
+
+// WaitJobEvents long-polls the platform's job event channel. The server holds
+// the request open for up to wait, returning as soon as any of the user's jobs
+// changes status; an empty page means nothing happened. An empty cursor starts
+// from "now". Returns ErrJobEventsUnsupported when the endpoint does not exist.
+func (c *Client) WaitJobEvents(ctx context.Context, cursor string, wait time.Duration) (*JobEventsPage, error) {
+	query := url.Values{}
+	query.Set("wait", fmt.Sprintf("%d", int(wait/time.Second)))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	path := "/api/v3/job-events/?" + query.Encode()
+
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case nethttp.StatusOK:
+	case nethttp.StatusNotFound, nethttp.StatusMethodNotAllowed, nethttp.StatusNotImplemented:
+		return nil, ErrJobEventsUnsupported
+	default:
+		body := readResponseBody(resp.Body)
+		return nil, fmt.Errorf("job events failed: status %d: %s", resp.StatusCode, body)
+	}
+
+	var page JobEventsPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode job events response: %w", err)
+	}
+	if page.Cursor == "" {
+		page.Cursor = cursor
+	}
+	return &page, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitJobEvents_SendsCursorAndParsesEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/job-events/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if got := r.URL.Query().Get("cursor"); got != "c1" {
+			t.Errorf("cursor = %q, want c1", got)
+		}
+		if got := r.URL.Query().Get("wait"); got != "25" {
+			t.Errorf("wait = %q, want 25", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"cursor": "c2",
+			"results": []map[string]string{
+				{"jobId": "abc", "status": "Executing", "statusDate": "2026-01-01T00:00:00Z"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	page, err := client.WaitJobEvents(context.Background(), "c1", 25*time.Second)
+	if err != nil {
+		t.Fatalf("WaitJobEvents() error = %v", err)
+	}
+	if page.Cursor != "c2" {
+		t.Errorf("Cursor = %q, want c2", page.Cursor)
+	}
+	if len(page.Events) != 1 || page.Events[0].JobID != "abc" || page.Events[0].Status != "Executing" {
+		t.Errorf("Events = %+v", page.Events)
+	}
+}
+
+func TestWaitJobEvents_UnsupportedEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	_, err := client.WaitJobEvents(context.Background(), "", 25*time.Second)
+	if !errors.Is(err, ErrJobEventsUnsupported) {
+		t.Fatalf("WaitJobEvents() error = %v, want ErrJobEventsUnsupported", err)
+	}
+}
@@ -0,0 +1,209 @@
+// Package api provides in-job access to a running job's work directory.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// ErrLiveFilesUnsupported indicates the platform does not offer in-job file
+// access for the job's cluster. Intermediate files can still be read from the
+// run's file listing (GetRunFiles).
+var ErrLiveFilesUnsupported = errors.New("live file access not available for this job")
+
+// LiveEntry is a file or directory in a running job's work directory.
+type LiveEntry struct {
+	Path         string `json:"path"` // Slash-separated, relative to the work directory
+	Name         string `json:"name"`
+	IsDirectory  bool   `json:"isDirectory"`
+	Size         int64  `json:"size,omitempty"`
+	DateModified string `json:"dateModified,omitempty"`
+}
+
+// CleanLivePath normalizes a path inside a job's work directory: slashes,
+// no leading "/" and no "..". The work directory itself is "".
+func CleanLivePath(p string) (string, error) {
+	p = strings.ReplaceAll(strings.TrimSpace(p), "\\", "/")
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("path %q leaves the job's work directory", p)
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/"), nil
+}
+
+// ActiveRunID returns the ID of the job's run that has started and not yet
+// completed, or "" while no run is active.
+func (c *Client) ActiveRunID(ctx context.Context, jobID string) (string, error) {
+	runs, err := c.GetJobRuns(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	for _, run := range runs {
+		if run.DateStarted != "" && run.DateCompleted == "" {
+			return run.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// livePath returns the directory-contents endpoint of a run, plus suffix.
+func livePath(jobID, runID, suffix string) string {
+	return fmt.Sprintf("/api/v2/jobs/%s/runs/%s/directory-contents/%s", jobID, runID, suffix)
+}
+
+// liveStatusError maps a live file response status to an error, or nil for
+// want. Returns ErrLiveFilesUnsupported when the cluster offers no in-job
+// access. A 404 means the endpoint is missing when listing, but the file is
+// missing otherwise.
+func liveStatusError(resp *nethttp.Response, op string, listing bool, want ...int) error {
+	for _, code := range want {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	switch resp.StatusCode {
+	case nethttp.StatusMethodNotAllowed, nethttp.StatusNotImplemented:
+		return ErrLiveFilesUnsupported
+	case nethttp.StatusNotFound:
+		if listing {
+			return ErrLiveFilesUnsupported
+		}
+	}
+	body := readResponseBody(resp.Body)
+	return fmt.Errorf("%s failed: status %d: %s", op, resp.StatusCode, body)
+}
+
+// ListLiveFiles lists a directory of a running job's work directory ("" for
+// the work directory itself). Returns ErrLiveFilesUnsupported when the
+// platform offers no in-job access for the job.
+func (c *Client) ListLiveFiles(ctx context.Context, jobID, runID, dir string) ([]LiveEntry, error) {
+	dir, err := CleanLivePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("path", dir)
+	nextURL := livePath(jobID, runID, "?"+query.Encode())
+
+	var entries []LiveEntry
+	pageCount := 0
+	for nextURL != "" {
+		pageCount++
+		if pageCount > constants.MaxPaginationPages {
+			break
+		}
+
+		resp, err := c.doRequest(ctx, "GET", nextURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := liveStatusError(resp, "list live files", true, nethttp.StatusOK); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var result struct {
+			Next    *string     `json:"next"`
+			Results []LiveEntry `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode live files response: %w", err)
+		}
+		resp.Body.Close()
+
+		entries = append(entries, result.Results...)
+
+		if result.Next != nil && *result.Next != "" {
+			nextURL = c.pagePath(*result.Next)
+		} else {
+			nextURL = ""
+		}
+	}
+
+	// Fill in names for listings that only return paths
+	for i := range entries {
+		if entries[i].Name == "" {
+			entries[i].Name = path.Base(entries[i].Path)
+		}
+	}
+	return entries, nil
+}
+
+// DownloadLiveFile copies the current contents of a file in a running job's
+// work directory to w and returns the number of bytes written. The file may
+// still be growing, so the copy is a snapshot.
+func (c *Client) DownloadLiveFile(ctx context.Context, jobID, runID, filePath string, w io.Writer) (int64, error) {
+	filePath, err := CleanLivePath(filePath)
+	if err != nil {
+		return 0, err
+	}
+	if filePath == "" {
+		return 0, fmt.Errorf("file path is required")
+	}
+	query := url.Values{}
+	query.Set("path", filePath)
+
+	resp, err := c.doRequest(ctx, "GET", livePath(jobID, runID, "download/?"+query.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if err := liveStatusError(resp, "download live file", false, nethttp.StatusOK); err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to read live file %s: %w", filePath, err)
+	}
+	return n, nil
+}
+
+// UploadLiveFile writes a small file into a running job's work directory,
+// replacing an existing file only when overwrite is set. Files larger than
+// constants.MaxLiveUploadSize are rejected before anything is sent.
+func (c *Client) UploadLiveFile(ctx context.Context, jobID, runID, filePath string, data []byte, overwrite bool) (*LiveEntry, error) {
+	filePath, err := CleanLivePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if filePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if len(data) > constants.MaxLiveUploadSize {
+		return nil, fmt.Errorf("%s is %d bytes; live uploads are limited to %d bytes", filePath, len(data), constants.MaxLiveUploadSize)
+	}
+
+	body := map[string]interface{}{
+		"path":      filePath,
+		"content":   data, // Encoded as base64
+		"overwrite": overwrite,
+	}
+	resp, err := c.doRequest(ctx, "POST", livePath(jobID, runID, "upload/"), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == nethttp.StatusConflict {
+		return nil, fmt.Errorf("%s already exists in the job's work directory (use overwrite to replace it)", filePath)
+	}
+	if err := liveStatusError(resp, "upload live file", false, nethttp.StatusOK, nethttp.StatusCreated); err != nil {
+		return nil, err
+	}
+
+	entry := LiveEntry{Path: filePath, Name: path.Base(filePath), Size: int64(len(data))}
+	// The response describes the written file; older platforms return no body
+	_ = json.NewDecoder(resp.Body).Decode(&entry)
+	return &entry, nil
+}
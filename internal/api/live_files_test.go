@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+func TestCleanLivePath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"/", "", false},
+		{".", "", false},
+		{"run1/solver.out", "run1/solver.out", false},
+		{"/run1//solver.out/", "run1/solver.out", false},
+		{`run1\solver.out`, "run1/solver.out", false},
+		{"run1/../../etc/passwd", "", true},
+		{"..", "", true},
+	}
+	for _, tt := range tests {
+		got, err := CleanLivePath(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CleanLivePath(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CleanLivePath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestListLiveFiles_SendsPathAndParsesEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/jobs/job1/runs/run1/directory-contents/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if got := r.URL.Query().Get("path"); got != "run1" {
+			t.Errorf("path = %q, want run1", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"path": "run1/solver.out", "size": 42},
+				{"path": "run1/restart", "name": "restart", "isDirectory": true},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	entries, err := client.ListLiveFiles(context.Background(), "job1", "run1", "/run1/")
+	if err != nil {
+		t.Fatalf("ListLiveFiles() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+	if entries[0].Name != "solver.out" || entries[0].Size != 42 || entries[0].IsDirectory {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if !entries[1].IsDirectory {
+		t.Errorf("entries[1] = %+v, want directory", entries[1])
+	}
+}
+
+func TestListLiveFiles_UnsupportedEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	_, err := client.ListLiveFiles(context.Background(), "job1", "run1", "")
+	if !errors.Is(err, ErrLiveFilesUnsupported) {
+		t.Fatalf("ListLiveFiles() error = %v, want ErrLiveFilesUnsupported", err)
+	}
+}
+
+func TestDownloadLiveFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/jobs/job1/runs/run1/directory-contents/download/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("path") != "solver.out" {
+			http.Error(w, `{"detail":"No such file"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("iteration 1\n"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	var buf bytes.Buffer
+	n, err := client.DownloadLiveFile(context.Background(), "job1", "run1", "solver.out", &buf)
+	if err != nil {
+		t.Fatalf("DownloadLiveFile() error = %v", err)
+	}
+	if n != 12 || buf.String() != "iteration 1\n" {
+		t.Errorf("DownloadLiveFile() = %d, %q", n, buf.String())
+	}
+
+	// A missing file is an error, not a missing feature
+	_, err = client.DownloadLiveFile(context.Background(), "job1", "run1", "missing.out", &buf)
+	if err == nil || errors.Is(err, ErrLiveFilesUnsupported) {
+		t.Errorf("DownloadLiveFile(missing) error = %v, want not-found error", err)
+	}
+}
+
+func TestUploadLiveFile(t *testing.T) {
+	var got struct {
+		Path      string `json:"path"`
+		Content   []byte `json:"content"`
+		Overwrite bool   `json:"overwrite"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v2/jobs/job1/runs/run1/directory-contents/upload/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	entry, err := client.UploadLiveFile(context.Background(), "job1", "run1", "run1/controlDict", []byte("endTime 200;"), true)
+	if err != nil {
+		t.Fatalf("UploadLiveFile() error = %v", err)
+	}
+	if got.Path != "run1/controlDict" || string(got.Content) != "endTime 200;" || !got.Overwrite {
+		t.Errorf("request = %+v", got)
+	}
+	if entry.Name != "controlDict" || entry.Size != 12 {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+func TestUploadLiveFile_RejectsLargeFiles(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:9")
+	data := []byte(strings.Repeat("x", constants.MaxLiveUploadSize+1))
+	_, err := client.UploadLiveFile(context.Background(), "job1", "run1", "big.dat", data, false)
+	if err == nil || !strings.Contains(err.Error(), "limited") {
+		t.Fatalf("UploadLiveFile() error = %v, want size limit error", err)
+	}
+}
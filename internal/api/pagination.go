@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"log"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// listPage is one page of a paginated v2/v3 listing.
+type listPage[T any] struct {
+	Items []T
+	Next  string // API path of the next page ("" on the last page)
+	Count int    // Total the API reports across all pages (0 if not reported)
+}
+
+// ListingReport describes pagination anomalies seen during a checked listing.
+// Listings that change while being paged (jobs submitted, files added or
+// deleted) shift items across page boundaries, so an item can be returned
+// twice or skipped entirely.
+type ListingReport struct {
+	Pages      int  // Pages fetched, including re-listed ones
+	Duplicates int  // Items returned more than once in the first pass
+	CountDrift bool // The reported total changed between pages
+	Relists    int  // Re-listing passes run to recover skipped items (0 or 1)
+	Missing    int  // Items still missing against the final reported total
+	Truncated  bool // Stopped at constants.MaxPaginationPages
+}
+
+// Anomalous reports whether the listing changed while it was being paged.
+func (r ListingReport) Anomalous() bool {
+	return r.Duplicates > 0 || r.CountDrift || r.Missing > 0
+}
+
+// listChecked pages through a listing starting at first and returns every
+// item once, keyed by key. The URL of each page is kept as a checkpoint; when
+// a page shows an anomaly (duplicate keys, a changed total) or the result has
+// fewer items than the API reports, the listing is re-read once from the page
+// before the first anomaly and merged, stopping as soon as the reported total
+// is reached.
+// Items deleted mid-listing may still be returned; callers already tolerate
+// stale entries (a later GET reports not found).
+func listChecked[T any](
+	ctx context.Context,
+	what, first string,
+	fetch func(ctx context.Context, url string) (listPage[T], error),
+	key func(T) string,
+) ([]T, ListingReport, error) {
+	var (
+		report       ListingReport
+		items        []T
+		seen         = make(map[string]bool)
+		checkpoints  []string // URL of each first-pass page
+		firstAnomaly = -1
+		count        = -1
+	)
+
+	walk := func(start string, firstPass bool) error {
+		for url, i := start, 0; url != ""; i++ {
+			// A re-read is done once everything reported is recovered
+			if !firstPass && i > 0 && len(items) >= count {
+				return nil
+			}
+			if report.Pages >= constants.MaxPaginationPages {
+				log.Printf("Warning: Pagination limit reached after %d pages (%d %s fetched)", report.Pages, len(items), what)
+				report.Truncated = true
+				return nil
+			}
+			if report.Pages+1 == constants.PaginationWarningThreshold {
+				log.Printf("Warning: Approaching pagination limit (page %d of %d)", report.Pages+1, constants.MaxPaginationPages)
+			}
+
+			page, err := fetch(ctx, url)
+			if err != nil {
+				return err
+			}
+			report.Pages++
+
+			anomaly := false
+			if page.Count > 0 {
+				if count >= 0 && page.Count != count {
+					report.CountDrift = true
+					anomaly = true
+				}
+				count = page.Count
+			}
+			for _, item := range page.Items {
+				k := key(item)
+				if seen[k] {
+					// Overlap is expected when re-reading pages
+					if firstPass {
+						report.Duplicates++
+						anomaly = true
+					}
+					continue
+				}
+				seen[k] = true
+				items = append(items, item)
+			}
+
+			if firstPass {
+				checkpoints = append(checkpoints, url)
+				if anomaly && firstAnomaly < 0 {
+					firstAnomaly = i
+				}
+			}
+			url = page.Next
+		}
+		return nil
+	}
+
+	if err := walk(first, true); err != nil {
+		return nil, report, err
+	}
+
+	// Items shift forward (duplicates) when entries are added ahead of the
+	// cursor and backward (skipped) when entries are removed, so re-read from
+	// the page before the first anomaly. A short result without a visible
+	// anomaly gives no hint where items were skipped: re-read from the start.
+	if !report.Truncated && (firstAnomaly >= 0 || count > 0 && len(items) < count) {
+		start := 0
+		if firstAnomaly > 0 {
+			start = firstAnomaly - 1
+		}
+		report.Relists++
+		if err := walk(checkpoints[start], false); err != nil {
+			return nil, report, err
+		}
+	}
+
+	if count > len(items) {
+		report.Missing = count - len(items)
+	}
+	if report.Anomalous() {
+		log.Printf("Warning: %s listing changed during pagination (%d duplicates dropped, count drift: %v, %d re-lists, %d still missing)",
+			what, report.Duplicates, report.CountDrift, report.Relists, report.Missing)
+	}
+	return items, report, nil
+}
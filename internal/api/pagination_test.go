@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeListing serves an offset-paged listing of IDs. mutate runs before each
+// page fetch so tests can change the listing mid-pagination.
+type fakeListing struct {
+	ids      []string
+	pageSize int
+	fetches  int
+	mutate   func(f *fakeListing)
+}
+
+func (f *fakeListing) fetch(_ context.Context, url string) (listPage[string], error) {
+	f.fetches++
+	if f.mutate != nil {
+		f.mutate(f)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(url, "/list/?offset="))
+	if err != nil {
+		return listPage[string]{}, fmt.Errorf("bad url %q", url)
+	}
+	end := min(offset+f.pageSize, len(f.ids))
+	page := listPage[string]{Count: len(f.ids)}
+	if offset < end {
+		page.Items = append(page.Items, f.ids[offset:end]...)
+	}
+	if end < len(f.ids) {
+		page.Next = fmt.Sprintf("/list/?offset=%d", end)
+	}
+	return page, nil
+}
+
+func ids(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("id%02d", i)
+	}
+	return out
+}
+
+func identity(s string) string { return s }
+
+func assertAllOnce(t *testing.T, got []string, want []string) {
+	t.Helper()
+	seen := make(map[string]int)
+	for _, id := range got {
+		seen[id]++
+	}
+	for _, id := range want {
+		if seen[id] != 1 {
+			t.Errorf("item %s returned %d times, want once", id, seen[id])
+		}
+	}
+}
+
+func TestListChecked_StableListing(t *testing.T) {
+	f := &fakeListing{ids: ids(10), pageSize: 3}
+	got, report, err := listChecked(context.Background(), "items", "/list/?offset=0", f.fetch, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAllOnce(t, got, ids(10))
+	if report.Anomalous() || report.Relists != 0 || f.fetches != 4 {
+		t.Errorf("stable listing: report %+v after %d fetches", report, f.fetches)
+	}
+}
+
+func TestListChecked_InsertionDuplicatesAreDropped(t *testing.T) {
+	f := &fakeListing{ids: ids(9), pageSize: 3}
+	f.mutate = func(f *fakeListing) {
+		// A new item lands at the head after the first page was read,
+		// pushing id02 onto the second page.
+		if f.fetches == 2 {
+			f.ids = append([]string{"new"}, f.ids...)
+		}
+	}
+	got, report, err := listChecked(context.Background(), "items", "/list/?offset=0", f.fetch, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAllOnce(t, got, append(ids(9), "new"))
+	if report.Duplicates == 0 || !report.CountDrift || report.Relists == 0 || report.Missing != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestListChecked_DeletionSkipIsRecovered(t *testing.T) {
+	f := &fakeListing{ids: ids(9), pageSize: 3}
+	f.mutate = func(f *fakeListing) {
+		// id01 is deleted after the first page, shifting id03 onto the first
+		// page so the second page starts at id04 and id03 is skipped.
+		if f.fetches == 2 {
+			f.ids = append([]string{"id00"}, f.ids[2:]...)
+		}
+	}
+	got, report, err := listChecked(context.Background(), "items", "/list/?offset=0", f.fetch, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAllOnce(t, got, f.ids)
+	if !report.CountDrift || report.Relists == 0 || report.Missing != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestListChecked_RelistIsBounded(t *testing.T) {
+	f := &fakeListing{ids: ids(30), pageSize: 3}
+	f.mutate = func(f *fakeListing) {
+		// Early deletion: the re-read should stop once id03 is recovered
+		// instead of paging through the whole listing again.
+		if f.fetches == 2 {
+			f.ids = append([]string{"id00"}, f.ids[2:]...)
+		}
+	}
+	got, report, err := listChecked(context.Background(), "items", "/list/?offset=0", f.fetch, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAllOnce(t, got, f.ids)
+	if report.Relists != 1 || f.fetches > 12 {
+		t.Errorf("report %+v after %d fetches, want one short re-read", report, f.fetches)
+	}
+
+	// A listing that keeps changing is re-read only once
+	churn := &fakeListing{ids: ids(9), pageSize: 3}
+	churn.mutate = func(f *fakeListing) {
+		f.ids = append([]string{fmt.Sprintf("new%02d", f.fetches)}, f.ids...)
+	}
+	_, report, err = listChecked(context.Background(), "items", "/list/?offset=0", churn.fetch, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Relists != 1 {
+		t.Errorf("churning listing: Relists = %d, want 1", report.Relists)
+	}
+}
+
+func TestListChecked_FetchError(t *testing.T) {
+	fetch := func(context.Context, string) (listPage[string], error) {
+		return listPage[string]{}, fmt.Errorf("boom")
+	}
+	if _, _, err := listChecked(context.Background(), "items", "/list/?offset=0", fetch, identity); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+)
+
+// ErrStorageQuotaUnsupported indicates the platform does not report storage
+// quotas. Callers should carry on without a quota figure.
+var ErrStorageQuotaUnsupported = errors.New("storage quota not available on this platform")
+
+// StorageQuota is the storage allowance of the workspace the client is
+// scoped to. A zero QuotaBytes means the workspace has no limit.
+type StorageQuota struct {
+	QuotaBytes int64 `json:"quotaBytes"`
+	UsedBytes  int64 `json:"usedBytes"`
+}
+
+// Remaining returns the bytes still available under the quota, never less
+// than zero. ok is false when the workspace has no limit.
+func (q *StorageQuota) Remaining() (bytes int64, ok bool) {
+	if q == nil || q.QuotaBytes <= 0 {
+		return 0, false
+	}
+	if q.UsedBytes >= q.QuotaBytes {
+		return 0, true
+	}
+	return q.QuotaBytes - q.UsedBytes, true
+}
+
+// GetStorageQuota fetches the storage quota and current usage of the
+// client's workspace. Returns ErrStorageQuotaUnsupported when the endpoint
+// does not exist.
+func (c *Client) GetStorageQuota(ctx context.Context) (*StorageQuota, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v3/users/me/storage-quota/", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case nethttp.StatusOK:
+	case nethttp.StatusNotFound, nethttp.StatusMethodNotAllowed, nethttp.StatusNotImplemented:
+		return nil, ErrStorageQuotaUnsupported
+	default:
+		body := readResponseBody(resp.Body)
+		return nil, fmt.Errorf("get storage quota failed: status %d: %s", resp.StatusCode, body)
+	}
+
+	var quota StorageQuota
+	if err := json.NewDecoder(resp.Body).Decode(&quota); err != nil {
+		return nil, fmt.Errorf("failed to decode storage quota response: %w", err)
+	}
+	return &quota, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetStorageQuota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/users/me/storage-quota/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]int64{"quotaBytes": 1000, "usedBytes": 400})
+	}))
+	defer server.Close()
+
+	quota, err := newTestClient(t, server.URL).GetStorageQuota(context.Background())
+	if err != nil {
+		t.Fatalf("GetStorageQuota() error = %v", err)
+	}
+	if left, ok := quota.Remaining(); !ok || left != 600 {
+		t.Errorf("Remaining() = %d, %t, want 600", left, ok)
+	}
+
+	over := &StorageQuota{QuotaBytes: 1000, UsedBytes: 1200}
+	if left, ok := over.Remaining(); !ok || left != 0 {
+		t.Errorf("over quota Remaining() = %d, %t, want 0", left, ok)
+	}
+	if _, ok := (&StorageQuota{UsedBytes: 5}).Remaining(); ok {
+		t.Error("unlimited quota reported a remaining figure")
+	}
+}
+
+func TestGetStorageQuota_UnsupportedEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(t, server.URL).GetStorageQuota(context.Background())
+	if !errors.Is(err, ErrStorageQuotaUnsupported) {
+		t.Fatalf("GetStorageQuota() error = %v, want ErrStorageQuotaUnsupported", err)
+	}
+}
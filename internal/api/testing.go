@@ -14,11 +14,14 @@ import (
 // (http://127.0.0.1:XXXXX) are not in the allowlist.
 // DO NOT use in production code.
 func NewClientForTest(cfg *config.Config) *Client {
+	headers, _ := config.ParseAPIHeaders(cfg.APIHeaders)
 	return &Client{
 		httpClient: &nethttp.Client{},
 		config:     cfg,
-		baseURL:    strings.TrimSuffix(cfg.APIBaseURL, "/"),
+		baseURL:    cfg.APIRoot(),
 		apiKey:     cfg.APIKey,
+		workspace:  strings.TrimSpace(cfg.Workspace),
+		headers:    headers,
 		store:      ratelimit.NewTestStore(),
 		metrics: &apiMetrics{
 			callsByPath:   make(map[string]int64),
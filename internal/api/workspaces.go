@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// WorkspaceHeader selects the workspace a request acts in. The platform
+// scopes folders, file registration, storage credentials and job creation
+// to it; without it, requests use the API key's default workspace. Set from
+// config.Config.Workspace.
+const WorkspaceHeader = "X-Rescale-Workspace"
+
+// Workspace returns the workspace ID this client's requests are scoped to,
+// or "" for the API key's default workspace.
+func (c *Client) Workspace() string {
+	return c.workspace
+}
+
+// ListWorkspaces lists the workspaces the API key's user belongs to.
+func (c *Client) ListWorkspaces(ctx context.Context) ([]models.WorkspaceInfo, error) {
+	workspaces, _, err := listChecked(ctx, "workspaces", "/api/v3/users/me/workspaces/", c.fetchWorkspacesPage,
+		func(w models.WorkspaceInfo) string { return w.ID })
+	return workspaces, err
+}
+
+// fetchWorkspacesPage fetches one page of the user's workspaces.
+func (c *Client) fetchWorkspacesPage(ctx context.Context, url string) (listPage[models.WorkspaceInfo], error) {
+	var page listPage[models.WorkspaceInfo]
+
+	resp, err := c.doRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return page, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		body := readResponseBody(resp.Body)
+		return page, fmt.Errorf("list workspaces failed: status %d: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Count   int                    `json:"count"`
+		Next    *string                `json:"next"`
+		Results []models.WorkspaceInfo `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return page, fmt.Errorf("failed to decode workspaces response: %w", err)
+	}
+
+	page.Items = result.Results
+	page.Count = result.Count
+	if result.Next != nil && *result.Next != "" {
+		page.Next = c.pagePath(*result.Next)
+	}
+	return page, nil
+}
+
+// FindWorkspace returns the workspace whose ID is ref, or else the one whose
+// name is ref (case-insensitive). A name shared by several workspaces is an
+// error, since it can't say which one is meant.
+func FindWorkspace(workspaces []models.WorkspaceInfo, ref string) (models.WorkspaceInfo, error) {
+	ref = strings.TrimSpace(ref)
+	for _, w := range workspaces {
+		if w.ID == ref {
+			return w, nil
+		}
+	}
+	var matches []models.WorkspaceInfo
+	for _, w := range workspaces {
+		if strings.EqualFold(w.Name, ref) {
+			matches = append(matches, w)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.WorkspaceInfo{}, fmt.Errorf("no workspace %q among your %d workspace(s)", ref, len(workspaces))
+	case 1:
+		return matches[0], nil
+	default:
+		return models.WorkspaceInfo{}, fmt.Errorf("%d workspaces are named %q; use the workspace ID", len(matches), ref)
+	}
+}
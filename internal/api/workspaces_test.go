@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestDoRequest_WorkspaceHeader(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(WorkspaceHeader))
+		json.NewEncoder(w).Encode(map[string]interface{}{"email": "a@example.com"})
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(t, server.URL).GetUserProfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scoped := NewClientForTest(&config.Config{APIBaseURL: server.URL, APIKey: "test-key", Workspace: " ws2 "})
+	if _, err := scoped.GetUserProfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[0] != "" || got[1] != "ws2" {
+		t.Errorf("workspace headers = %q, want none then \"ws2\"", got)
+	}
+	if scoped.Workspace() != "ws2" {
+		t.Errorf("Workspace() = %q, want ws2", scoped.Workspace())
+	}
+}
+
+func TestListWorkspaces_Pages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/users/me/workspaces/" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"count":   2,
+				"results": []map[string]string{{"id": "ws2", "name": "CFD"}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   2,
+			"next":    server.URL + "/api/v3/users/me/workspaces/?page=2",
+			"results": []map[string]string{{"id": "ws1", "name": "Default"}},
+		})
+	}))
+	defer server.Close()
+
+	workspaces, err := newTestClient(t, server.URL).ListWorkspaces(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 2 || workspaces[0].ID != "ws1" || workspaces[1].Name != "CFD" {
+		t.Errorf("workspaces = %+v", workspaces)
+	}
+}
+
+func TestFindWorkspace(t *testing.T) {
+	workspaces := []models.WorkspaceInfo{
+		{ID: "ws1", Name: "Default"},
+		{ID: "ws2", Name: "CFD"},
+		{ID: "ws3", Name: "Shared"},
+		{ID: "ws4", Name: "shared"},
+		{ID: "cfd", Name: "Other"},
+	}
+	tests := []struct {
+		ref     string
+		wantID  string
+		wantErr bool
+	}{
+		{"ws2", "ws2", false},
+		{"default", "ws1", false},
+		{"cfd", "cfd", false}, // IDs win over names
+		{"Shared", "", true},  // ambiguous name
+		{"nope", "", true},
+	}
+	for _, tt := range tests {
+		w, err := FindWorkspace(workspaces, tt.ref)
+		if (err != nil) != tt.wantErr || w.ID != tt.wantID {
+			t.Errorf("FindWorkspace(%q) = %q, %v; want %q (error: %v)", tt.ref, w.ID, err, tt.wantID, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package cache accounts for the disk space Interlink's caches use and keeps
+// it under a budget by evicting the least recently used entries.
+//
+// A cache is any category of files Interlink creates for its own benefit and
+// can recreate on demand: staged PUR tars and transfer resume sidecars (see
+// Sources). Entries that a running transfer or run is using are reported but
+// never evicted. The software and hardware catalogs
+// and remote folder listings are held in memory only and are not covered.
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache categories.
+const (
+	CategoryTars   = "tars"   // Staged PUR tarballs left by earlier runs
+	CategoryResume = "resume" // Upload/download resume sidecars
+)
+
+// Categories lists every category in display order.
+var Categories = []string{CategoryTars, CategoryResume}
+
+// Entry is one evictable file.
+type Entry struct {
+	Category string
+	Path     string
+	Size     int64
+	LastUsed time.Time
+	InUse    bool // Held by a live run or transfer; never evicted
+}
+
+// Source enumerates and removes the entries of one category.
+type Source interface {
+	Category() string
+	Entries() ([]Entry, error)
+	Remove(e Entry) error
+}
+
+// Limits is the disk budget. Zero means unlimited.
+type Limits struct {
+	Total       int64            // Bytes across all categories
+	PerCategory map[string]int64 // Bytes per category
+}
+
+// ParseLimits builds Limits from the cache_max_mb and cache_limits config
+// values. cache_limits is a comma-separated list of category=MB pairs, e.g.
+// "tars=20480,resume=512".
+func ParseLimits(maxMB int, spec string) (Limits, error) {
+	limits := Limits{Total: int64(maxMB) << 20, PerCategory: make(map[string]int64)}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !knownCategory(name) {
+			return Limits{}, fmt.Errorf("invalid cache limit %q: want <category>=<MB> with category one of %s",
+				pair, strings.Join(Categories, ", "))
+		}
+		mb, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || mb < 0 {
+			return Limits{}, fmt.Errorf("invalid cache limit %q: size must be a whole number of MB", pair)
+		}
+		limits.PerCategory[name] = int64(mb) << 20
+	}
+	return limits, nil
+}
+
+func knownCategory(name string) bool {
+	for _, c := range Categories {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryStats is the footprint of one category.
+type CategoryStats struct {
+	Category   string    `json:"category"`
+	Entries    int       `json:"entries"`
+	Bytes      int64     `json:"bytes"`
+	InUseBytes int64     `json:"inUseBytes"` // Part of Bytes that cannot be evicted right now
+	Limit      int64     `json:"limit"`      // 0 = no per-category limit
+	Oldest     time.Time `json:"oldest"`     // Least recently used entry (zero if none)
+}
+
+// Stats is the footprint of all categories.
+type Stats struct {
+	Categories []CategoryStats `json:"categories"`
+	Bytes      int64           `json:"bytes"`
+	Limit      int64           `json:"limit"` // 0 = unlimited
+}
+
+// Result reports what Enforce or Clear removed.
+type Result struct {
+	Removed int
+	Freed   int64
+	Errors  []error
+}
+
+// Manager applies Limits to a set of Sources.
+type Manager struct {
+	sources []Source
+	limits  Limits
+}
+
+// NewManager returns a manager for sources under limits.
+func NewManager(limits Limits, sources ...Source) *Manager {
+	return &Manager{sources: sources, limits: limits}
+}
+
+// collect lists each source's entries, keyed by category. A source that
+// fails to list is recorded in errs and treated as empty.
+func (m *Manager) collect() (map[string][]Entry, []error) {
+	all := make(map[string][]Entry)
+	var errs []error
+	for _, src := range m.sources {
+		entries, err := src.Entries()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Category(), err))
+		}
+		all[src.Category()] = append(all[src.Category()], entries...)
+	}
+	return all, errs
+}
+
+// Stats reports the current footprint. Listing errors are returned alongside
+// the stats of the categories that could be read.
+func (m *Manager) Stats() (Stats, error) {
+	all, errs := m.collect()
+	stats := Stats{Limit: m.limits.Total}
+	for _, src := range m.sources {
+		cs := CategoryStats{Category: src.Category(), Limit: m.limits.PerCategory[src.Category()]}
+		for _, e := range all[src.Category()] {
+			cs.Entries++
+			cs.Bytes += e.Size
+			if e.InUse {
+				cs.InUseBytes += e.Size
+			}
+			if cs.Oldest.IsZero() || e.LastUsed.Before(cs.Oldest) {
+				cs.Oldest = e.LastUsed
+			}
+		}
+		stats.Bytes += cs.Bytes
+		stats.Categories = append(stats.Categories, cs)
+	}
+	if len(errs) > 0 {
+		return stats, fmt.Errorf("failed to list caches: %v", errs)
+	}
+	return stats, nil
+}
+
+// Enforce evicts least recently used entries until every category is within
+// its own limit and the total is within the overall limit. In-use entries
+// count toward the totals but are skipped.
+func (m *Manager) Enforce() Result {
+	all, errs := m.collect()
+	res := Result{Errors: errs}
+
+	var remaining []Entry
+	var total int64
+	for _, src := range m.sources {
+		entries := all[src.Category()]
+		var size int64
+		for _, e := range entries {
+			size += e.Size
+		}
+		if limit := m.limits.PerCategory[src.Category()]; limit > 0 && size > limit {
+			entries = m.evict(&res, entries, size-limit)
+			size = 0
+			for _, e := range entries {
+				size += e.Size
+			}
+		}
+		remaining = append(remaining, entries...)
+		total += size
+	}
+	if m.limits.Total > 0 && total > m.limits.Total {
+		m.evict(&res, remaining, total-m.limits.Total)
+	}
+	return res
+}
+
+// evict removes entries oldest first until need bytes are freed, returning
+// the entries that are left.
+func (m *Manager) evict(res *Result, entries []Entry, need int64) []Entry {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].LastUsed.Before(entries[j].LastUsed) })
+	var kept []Entry
+	for _, e := range entries {
+		if need <= 0 || e.InUse {
+			kept = append(kept, e)
+			continue
+		}
+		if err := m.remove(e); err != nil {
+			res.Errors = append(res.Errors, err)
+			kept = append(kept, e)
+			continue
+		}
+		res.Removed++
+		res.Freed += e.Size
+		need -= e.Size
+	}
+	return kept
+}
+
+// Clear removes every entry that is not in use from the given categories,
+// or from all categories if none are given.
+func (m *Manager) Clear(categories ...string) Result {
+	for _, c := range categories {
+		if !knownCategory(c) {
+			return Result{Errors: []error{fmt.Errorf("unknown cache category %q (want %s)", c, strings.Join(Categories, ", "))}}
+		}
+	}
+	all, errs := m.collect()
+	res := Result{Errors: errs}
+	for _, src := range m.sources {
+		if len(categories) > 0 && !contains(categories, src.Category()) {
+			continue
+		}
+		for _, e := range all[src.Category()] {
+			if e.InUse {
+				continue
+			}
+			if err := m.remove(e); err != nil {
+				res.Errors = append(res.Errors, err)
+				continue
+			}
+			res.Removed++
+			res.Freed += e.Size
+		}
+	}
+	return res
+}
+
+func (m *Manager) remove(e Entry) error {
+	for _, src := range m.sources {
+		if src.Category() == e.Category {
+			if err := src.Remove(e); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", e.Path, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no source for cache category %q", e.Category)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
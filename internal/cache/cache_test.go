@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSource holds entries in memory and records removals.
+type fakeSource struct {
+	category string
+	entries  []Entry
+	removed  []string
+	failOn   string
+}
+
+func (s *fakeSource) Category() string { return s.category }
+
+func (s *fakeSource) Entries() ([]Entry, error) {
+	return append([]Entry(nil), s.entries...), nil
+}
+
+func (s *fakeSource) Remove(e Entry) error {
+	if e.Path == s.failOn {
+		return errors.New("permission denied")
+	}
+	s.removed = append(s.removed, e.Path)
+	for i, have := range s.entries {
+		if have.Path == e.Path {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+const mb = 1 << 20
+
+func entry(category, path string, sizeMB int64, age time.Duration) Entry {
+	return Entry{Category: category, Path: path, Size: sizeMB * mb, LastUsed: time.Now().Add(-age)}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(100, " tars=50, RESUME=5 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.Total != 100*mb || limits.PerCategory["tars"] != 50*mb || limits.PerCategory["resume"] != 5*mb {
+		t.Errorf("limits = %+v", limits)
+	}
+
+	for _, spec := range []string{"tars", "logs=5", "tars=-1", "tars=1.5"} {
+		if _, err := ParseLimits(0, spec); err == nil {
+			t.Errorf("ParseLimits(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestEnforceTotalEvictsLeastRecentlyUsed(t *testing.T) {
+	tars := &fakeSource{category: CategoryTars, entries: []Entry{
+		entry(CategoryTars, "new.tar", 40, time.Hour),
+		entry(CategoryTars, "old.tar", 40, 72*time.Hour),
+	}}
+	resume := &fakeSource{category: CategoryResume, entries: []Entry{
+		entry(CategoryResume, "a.resume", 30, 24*time.Hour),
+	}}
+
+	res := NewManager(Limits{Total: 80 * mb}, tars, resume).Enforce()
+	if len(res.Errors) > 0 {
+		t.Fatal(res.Errors)
+	}
+	if res.Removed != 1 || res.Freed != 40*mb {
+		t.Errorf("removed %d (%d bytes), want 1 (40 MB)", res.Removed, res.Freed)
+	}
+	if len(tars.removed) != 1 || tars.removed[0] != "old.tar" || len(resume.removed) != 0 {
+		t.Errorf("removed tars %v, resume %v; want only old.tar", tars.removed, resume.removed)
+	}
+}
+
+func TestEnforcePerCategoryThenTotal(t *testing.T) {
+	tars := &fakeSource{category: CategoryTars, entries: []Entry{
+		entry(CategoryTars, "t1.tar", 10, 1*time.Hour),
+		entry(CategoryTars, "t2.tar", 10, 2*time.Hour),
+		entry(CategoryTars, "t3.tar", 10, 3*time.Hour),
+	}}
+	resume := &fakeSource{category: CategoryResume, entries: []Entry{
+		entry(CategoryResume, "r1.resume", 10, 10*time.Hour),
+	}}
+
+	// tars capped at 20 MB drops t3; the 25 MB total then drops r1 (oldest left)
+	limits := Limits{Total: 25 * mb, PerCategory: map[string]int64{CategoryTars: 20 * mb}}
+	res := NewManager(limits, tars, resume).Enforce()
+	if res.Removed != 2 {
+		t.Fatalf("removed %d, want 2 (errors %v)", res.Removed, res.Errors)
+	}
+	if strings.Join(tars.removed, ",") != "t3.tar" || strings.Join(resume.removed, ",") != "r1.resume" {
+		t.Errorf("removed tars %v, resume %v; want t3.tar and r1.resume", tars.removed, resume.removed)
+	}
+}
+
+func TestEnforceSkipsInUse(t *testing.T) {
+	busy := entry(CategoryTars, "busy.tar", 50, 96*time.Hour)
+	busy.InUse = true
+	tars := &fakeSource{category: CategoryTars, entries: []Entry{
+		busy,
+		entry(CategoryTars, "idle.tar", 50, time.Hour),
+		entry(CategoryTars, "locked.tar", 10, 48*time.Hour),
+	}}
+	tars.failOn = "locked.tar"
+
+	res := NewManager(Limits{Total: 60 * mb}, tars).Enforce()
+	if len(tars.removed) != 1 || tars.removed[0] != "idle.tar" {
+		t.Errorf("removed %v, want only idle.tar", tars.removed)
+	}
+	if len(res.Errors) != 1 {
+		t.Errorf("errors = %v, want the failed removal of locked.tar", res.Errors)
+	}
+}
+
+func TestClearAndStats(t *testing.T) {
+	busy := entry(CategoryResume, "busy.resume", 1, time.Minute)
+	busy.InUse = true
+	tars := &fakeSource{category: CategoryTars, entries: []Entry{entry(CategoryTars, "a.tar", 5, time.Hour)}}
+	resume := &fakeSource{category: CategoryResume, entries: []Entry{busy, entry(CategoryResume, "old.resume", 1, 48*time.Hour)}}
+	mgr := NewManager(Limits{PerCategory: map[string]int64{CategoryTars: 8 * mb}}, tars, resume)
+
+	stats, err := mgr.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Bytes != 7*mb || len(stats.Categories) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if c := stats.Categories[1]; c.Entries != 2 || c.InUseBytes != 1*mb || time.Since(c.Oldest) < 47*time.Hour {
+		t.Errorf("resume stats = %+v", c)
+	}
+	if stats.Categories[0].Limit != 8*mb {
+		t.Errorf("tars limit = %d, want 8 MB", stats.Categories[0].Limit)
+	}
+
+	res := mgr.Clear(CategoryResume)
+	if res.Removed != 1 || len(tars.removed) != 0 {
+		t.Errorf("Clear(resume) removed %d, tars %v; want only old.resume", res.Removed, tars.removed)
+	}
+	res = mgr.Clear()
+	if res.Removed != 1 || strings.Join(tars.removed, ",") != "a.tar" {
+		t.Errorf("Clear() removed %d, tars %v", res.Removed, tars.removed)
+	}
+	if res := mgr.Clear("catalogs"); len(res.Errors) != 1 {
+		t.Errorf("Clear(catalogs) errors = %v, want unknown category", res.Errors)
+	}
+
+	left, _ := resume.Entries()
+	paths := []string{}
+	for _, e := range left {
+		paths = append(paths, e.Path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "busy.resume" {
+		t.Errorf("left %v, want busy.resume", paths)
+	}
+}
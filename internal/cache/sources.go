@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cloudstate "github.com/rescale/rescale-int/internal/cloud/state"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/pathutil"
+	"github.com/rescale/rescale-int/internal/pur/runhistory"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+)
+
+// Resume sidecar suffixes (see cloud/state).
+const (
+	uploadResumeSuffix   = ".upload.resume"
+	downloadResumeSuffix = ".download.resume"
+)
+
+// New returns a manager for cfg's caches under cfg's cache_max_mb and
+// cache_limits.
+func New(cfg *config.Config) (*Manager, error) {
+	limits, err := ParseLimits(cfg.CacheMaxMB, cfg.CacheLimits)
+	if err != nil {
+		return nil, err
+	}
+	return NewManager(limits, DefaultSources(cfg)...), nil
+}
+
+// Configured reports whether cfg sets any cache limit.
+func Configured(cfg *config.Config) bool {
+	return cfg != nil && (cfg.CacheMaxMB > 0 || strings.TrimSpace(cfg.CacheLimits) != "")
+}
+
+// DefaultSources returns the sources for cfg's caches, in Categories order.
+func DefaultSources(cfg *config.Config) []Source {
+	return []Source{
+		&tarSource{cfg: cfg},
+		&resumeSource{cfg: cfg},
+	}
+}
+
+// stateFiles returns the PUR state files Interlink knows about: those in the
+// state directory plus those recorded in the run history (runs started with
+// an explicit --state path).
+func stateFiles(cfg *config.Config) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	if matches, err := filepath.Glob(filepath.Join(config.StateDirectory(cfg), "*.state")); err == nil {
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	if recorded, err := runhistory.StateFiles(config.GetRunHistoryPath(cfg.APIBaseURL)); err == nil {
+		for _, f := range recorded {
+			add(f)
+		}
+	}
+	return files
+}
+
+// stagedTars maps each staged tar recorded in a state file to whether a live
+// run holds that state file.
+func stagedTars(cfg *config.Config) map[string]bool {
+	tars := make(map[string]bool)
+	for _, sf := range stateFiles(cfg) {
+		m := state.NewManager(sf)
+		if m.Load() != nil {
+			continue
+		}
+		locked := state.Locked(sf)
+		for _, js := range m.GetAllStates() {
+			for _, p := range js.TarPaths() {
+				if isStagedTar(p) {
+					tars[p] = tars[p] || locked
+				}
+			}
+		}
+	}
+	return tars
+}
+
+// isStagedTar reports whether path looks like a tar Interlink created, so a
+// corrupted or hand-edited state file cannot point eviction at user data.
+func isStagedTar(path string) bool {
+	lower := strings.ToLower(path)
+	return (strings.HasSuffix(lower, ".tar") || strings.HasSuffix(lower, ".tar.gz")) && pathutil.HasFNVSuffix(path)
+}
+
+func fileEntry(category, path string, inUse bool) (Entry, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return Entry{}, false
+	}
+	return Entry{Category: category, Path: path, Size: info.Size(), LastUsed: info.ModTime(), InUse: inUse}, true
+}
+
+// tarSource covers staged PUR tarballs. An evicted tar is rebuilt from its
+// input directory if its run is resumed.
+type tarSource struct{ cfg *config.Config }
+
+func (s *tarSource) Category() string { return CategoryTars }
+
+func (s *tarSource) Entries() ([]Entry, error) {
+	var entries []Entry
+	for path, inUse := range stagedTars(s.cfg) {
+		if e, ok := fileEntry(CategoryTars, path, inUse); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (s *tarSource) Remove(e Entry) error {
+	if err := securedelete.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// A resume sidecar is useless without its tar
+	if err := securedelete.Remove(e.Path + uploadResumeSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resumeSource covers upload resume sidecars next to staged tars, the
+// multipart upload states of CLI uploads and download resume sidecars under
+// the download directory. A sidecar written
+// within the transfer lock timeout belongs to a transfer that may still be
+// running.
+type resumeSource struct{ cfg *config.Config }
+
+func (s *resumeSource) Category() string { return CategoryResume }
+
+func (s *resumeSource) Entries() ([]Entry, error) {
+	seen := make(map[string]bool)
+	var entries []Entry
+	add := func(path string, inUse bool) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		if e, ok := fileEntry(CategoryResume, path, inUse); ok {
+			e.InUse = e.InUse || time.Since(e.LastUsed) < cloudstate.LockStaleTimeout
+			entries = append(entries, e)
+		}
+	}
+	for tar, inUse := range stagedTars(s.cfg) {
+		add(tar+uploadResumeSuffix, inUse)
+	}
+	// Multipart upload progress of CLI uploads
+	states, _ := filepath.Glob(filepath.Join(config.GetTransferStateDir(), "*.upload.json"))
+	for _, path := range states {
+		add(path, false)
+	}
+	if dir := s.cfg.DownloadDir; dir != "" {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Skip unreadable directories
+			}
+			if !d.IsDir() && (strings.HasSuffix(path, downloadResumeSuffix) || strings.HasSuffix(path, uploadResumeSuffix)) {
+				add(path, false)
+			}
+			return nil
+		})
+		if err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+func (s *resumeSource) Remove(e Entry) error {
+	// Sidecars hold transfer encryption keys
+	return securedelete.Remove(e.Path)
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func writeFile(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeState writes a one-job PUR state file whose TarPath is tarPaths.
+func writeState(t *testing.T, path, tarPaths string) {
+	t.Helper()
+	data := "Index,JobName,Directory,TarPath,TarStatus,FileID,UploadStatus,JobID,SubmitStatus,ExtraFileIDs,ErrorMessage,LastUpdated\n" +
+		"0,run1,/data/run1," + tarPaths + ",success,,pending,,pending,,,2026-01-01T00:00:00Z\n"
+	writeFile(t, path, 0, 0)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultSources(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("LOCALAPPDATA", filepath.Join(home, "AppData", "Local"))
+
+	data := filepath.Join(home, "data")
+	staged := filepath.Join(data, "run1_0a1b2c3d.tar.gz")
+	userTar := filepath.Join(data, "inputs.tar") // No FNV suffix: not ours
+	busyTar := filepath.Join(data, "run2_deadbeef.tar")
+	writeFile(t, staged, 1000, 48*time.Hour)
+	writeFile(t, staged+uploadResumeSuffix, 10, 48*time.Hour)
+	writeFile(t, userTar, 1000, 48*time.Hour)
+	writeFile(t, busyTar, 1000, 48*time.Hour)
+
+	cfg := &config.Config{StateDir: filepath.Join(home, "states"), DownloadDir: filepath.Join(home, "downloads")}
+	writeState(t, filepath.Join(cfg.StateDir, "run_1.state"), staged+models.PartSeparator+userTar)
+	writeState(t, filepath.Join(cfg.StateDir, "run_2.state"), busyTar)
+	writeFile(t, filepath.Join(cfg.StateDir, "run_2.state.lock"), 0, 0)
+	writeFile(t, filepath.Join(cfg.DownloadDir, "job", "out.dat.download.resume"), 20, 0)
+
+	mgr, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := mgr.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]CategoryStats{}
+	for _, c := range stats.Categories {
+		got[c.Category] = c
+	}
+	if c := got[CategoryTars]; c.Entries != 2 || c.Bytes != 2000 || c.InUseBytes != 1000 {
+		t.Errorf("tars = %+v, want the staged and the busy tar", c)
+	}
+	if c := got[CategoryResume]; c.Entries != 2 || c.Bytes != 30 || c.InUseBytes != 20 {
+		t.Errorf("resume = %+v, want both sidecars, the recent one in use", c)
+	}
+
+	res := mgr.Clear(CategoryTars)
+	if res.Removed != 1 || len(res.Errors) > 0 {
+		t.Fatalf("Clear(tars) = %+v, want the staged tar only", res)
+	}
+	for path, want := range map[string]bool{
+		staged:                      false,
+		staged + uploadResumeSuffix: false,
+		userTar:                     true,
+		busyTar:                     true,
+	} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", strings.TrimPrefix(path, home), err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/services"
+)
+
+// adminSelection holds the flags that pick team jobs for the admin commands.
+type adminSelection struct {
+	org    string
+	since  string
+	owner  string
+	status string
+	tag    string
+}
+
+func (s *adminSelection) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.org, "org", "", "Organization code (default: org_code from config, else the API key's organization)")
+	cmd.Flags().StringVar(&s.since, "since", fmt.Sprintf("%dd", constants.DefaultAdminSinceDays), "Jobs created since this age (e.g. 7d, 12h) or date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&s.owner, "owner", "", "Only jobs whose owner contains this text (e.g. an email)")
+	cmd.Flags().StringVar(&s.status, "status", "", "Only jobs with this status (e.g. Executing, Completed)")
+	cmd.Flags().StringVar(&s.tag, "tag", "", "Tag marking Interlink jobs (default: admin_job_tag from config, else \""+constants.DefaultAdminJobTag+"\")")
+}
+
+// list resolves the selection against the organization's jobs.
+func (s *adminSelection) list(ctx context.Context, svc *services.AdminService, orgDefault, tagDefault string) ([]services.AdminJob, error) {
+	cutoff, err := parseSince(s.since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter := services.AdminJobFilter{
+		OrgCode: firstNonEmpty(s.org, orgDefault),
+		Since:   cutoff,
+		Tag:     firstNonEmpty(s.tag, tagDefault),
+		Owner:   s.owner,
+		Status:  s.status,
+	}
+	return svc.ListInterlinkJobs(ctx, filter)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// newAdminCmd creates the 'admin' command group.
+func newAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Org admin: view and manage team members' Interlink jobs",
+		Long: `Commands for organization admins to list team members' jobs submitted
+via Interlink, stop or archive them in bulk, and summarize storage use.
+
+Requires an API key with organization admin permissions. Interlink jobs are
+identified by tag: a tag equal to admin_job_tag (default "interlink") or
+starting with "<tag>-", e.g. the suggested default tag "interlink-{version}".
+Every job Interlink creates is tagged "interlink" automatically; with a
+different admin_job_tag, team members' configs need default_tags to include it.`,
+	}
+
+	cmd.AddCommand(newAdminJobsCmd())
+	cmd.AddCommand(newAdminBulkCmd("stop", "Stop team members' running jobs", "Stopped",
+		func(svc *services.AdminService) func(context.Context, []string) ([]services.AdminActionResult, error) {
+			return svc.StopJobs
+		}))
+	cmd.AddCommand(newAdminBulkCmd("archive", "Archive team members' jobs (hidden from job lists, files kept)", "Archived",
+		func(svc *services.AdminService) func(context.Context, []string) ([]services.AdminActionResult, error) {
+			return svc.ArchiveJobs
+		}))
+	cmd.AddCommand(newAdminStorageCmd())
+
+	return cmd
+}
+
+func newAdminJobsCmd() *cobra.Command {
+	var sel adminSelection
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List recent Interlink jobs across the team",
+		Long: `List recent jobs submitted via Interlink by any member of the organization.
+
+Examples:
+  rescale-int admin jobs
+  rescale-int admin jobs --since 30d --owner alice@example.com
+  rescale-int admin jobs --status Executing --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			cfg := apiClient.GetConfig()
+
+			jobs, err := sel.list(GetContext(), services.NewAdminService(apiClient), cfg.OrgCode, cfg.AdminJobTag)
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(jobs)
+			}
+
+			if len(jobs) == 0 {
+				fmt.Println("No Interlink jobs found")
+				return nil
+			}
+			fmt.Printf("%-10s %-14s %-28s %-20s %s\n", "ID", "STATUS", "OWNER", "CREATED", "NAME")
+			for _, j := range jobs {
+				fmt.Printf("%-10s %-14s %-28s %-20s %s\n", j.ID, j.Status, j.Owner, j.CreatedAt, j.Name)
+			}
+			fmt.Printf("\n%d job(s)\n", len(jobs))
+			return nil
+		},
+	}
+
+	sel.addFlags(cmd)
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// newAdminBulkCmd creates a bulk job action command ('admin stop', 'admin archive').
+// Jobs are given with --job-id or selected with the listing filters.
+func newAdminBulkCmd(use, short, done string, action func(*services.AdminService) func(context.Context, []string) ([]services.AdminActionResult, error)) *cobra.Command {
+	var sel adminSelection
+	var jobIDs []string
+	var confirm bool
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long: fmt.Sprintf(`%s.
+
+Give jobs with --job-id, or select every Interlink job matching the listing
+filters (--since, --owner, --status). You are asked to confirm unless --confirm.
+
+Examples:
+  rescale-int admin %[2]s -j XxYyZz -j AaBbCc
+  rescale-int admin %[2]s --owner alice@example.com --status Executing`, short, use),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			cfg := apiClient.GetConfig()
+			svc := services.NewAdminService(apiClient)
+			ctx := GetContext()
+
+			if len(jobIDs) == 0 {
+				if sel.owner == "" && sel.status == "" {
+					return fmt.Errorf("give --job-id, or select jobs with --owner and/or --status")
+				}
+				jobs, err := sel.list(ctx, svc, cfg.OrgCode, cfg.AdminJobTag)
+				if err != nil {
+					return err
+				}
+				for _, j := range jobs {
+					jobIDs = append(jobIDs, j.ID)
+				}
+				if len(jobIDs) == 0 {
+					fmt.Println("No Interlink jobs match")
+					return nil
+				}
+			}
+
+			if !confirm {
+				fmt.Printf("About to %s %d job(s):\n", use, len(jobIDs))
+				for i, id := range jobIDs {
+					fmt.Printf("  %d. %s\n", i+1, id)
+				}
+				fmt.Print("\nAre you sure? (yes/no): ")
+				var response string
+				fmt.Scanln(&response)
+				if response != "yes" {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+
+			results, err := action(svc)(ctx, jobIDs)
+			if err != nil {
+				return err
+			}
+			var failures []string
+			for _, r := range results {
+				if r.Error != "" {
+					failures = append(failures, fmt.Sprintf("%s: %s", r.JobID, r.Error))
+				} else {
+					fmt.Printf("✓ %s job: %s\n", done, r.JobID)
+				}
+			}
+			if len(failures) > 0 {
+				fmt.Printf("\n❌ Failed to %s %d job(s):\n", use, len(failures))
+				for _, f := range failures {
+					fmt.Printf("  - %s\n", f)
+				}
+				return fmt.Errorf("%d of %d job(s) failed", len(failures), len(results))
+			}
+			return nil
+		},
+	}
+
+	sel.addFlags(cmd)
+	cmd.Flags().StringArrayVarP(&jobIDs, "job-id", "j", []string{}, "Job ID (can be specified multiple times)")
+	cmd.Flags().BoolVarP(&confirm, "confirm", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func newAdminStorageCmd() *cobra.Command {
+	var org string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Summarize storage use per team member",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			usage, err := services.NewAdminService(apiClient).StorageUsage(GetContext(), firstNonEmpty(org, apiClient.GetConfig().OrgCode))
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(usage)
+			}
+
+			var total int64
+			fmt.Printf("%-32s %-24s %12s %8s\n", "MEMBER", "NAME", "USED", "FILES")
+			for _, u := range usage {
+				total += u.UsedBytes
+				fmt.Printf("%-32s %-24s %12s %8d\n", u.Email, strings.TrimSpace(u.FullName), cloud.FormatBytes(u.UsedBytes), u.FileCount)
+			}
+			fmt.Printf("\n%d member(s), %s total\n", len(usage), cloud.FormatBytes(total))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&org, "org", "", "Organization code (default: org_code from config, else the API key's organization)")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
 )
 
 // newAutomationsCmd creates the 'automations' command group.
@@ -53,6 +55,8 @@ Examples:
 				return err
 			}
 
+			ctx, cancel := apiClient.WithTimeout(ctx, config.APICatalog)
+			defer cancel()
 			automations, err := apiClient.ListAutomations(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to list automations: %w", err)
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cache"
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// newCacheCmd creates the 'cache' command group.
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Show and clear Interlink's local disk caches",
+		Long: `Interlink keeps files on disk that it can recreate on demand:
+
+  tars    Staged PUR tarballs left next to the inputs of earlier runs
+  resume  Upload/download resume sidecars (.upload.resume, .download.resume)
+
+Set cache_max_mb (total) and cache_limits (per category, e.g.
+"tars=20480,resume=512") in the config to cap their footprint; least
+recently used entries are evicted after each run. Files held by a running
+PUR run or transfer are never removed.`,
+	}
+
+	cmd.AddCommand(newCacheStatsCmd())
+	cmd.AddCommand(newCacheClearCmd())
+
+	return cmd
+}
+
+// loadCacheManager returns a manager for the configured caches. No API key
+// is needed, since the caches are local.
+func loadCacheManager() (*cache.Manager, error) {
+	cfg, err := loadLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(cfg)
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show disk usage per cache category",
+		Long: `Show how much disk each cache category uses, against the configured limits.
+
+Examples:
+  rescale-int cache stats
+  rescale-int cache stats --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadCacheManager()
+			if err != nil {
+				return err
+			}
+			stats, statsErr := mgr.Stats()
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(stats); err != nil {
+					return err
+				}
+				return statsErr
+			}
+
+			fmt.Printf("%-8s %8s %12s %12s %12s  %s\n", "CATEGORY", "ENTRIES", "SIZE", "IN USE", "LIMIT", "OLDEST")
+			for _, c := range stats.Categories {
+				oldest := "-"
+				if !c.Oldest.IsZero() {
+					oldest = c.Oldest.Format("2006-01-02 15:04")
+				}
+				fmt.Printf("%-8s %8d %12s %12s %12s  %s\n", c.Category, c.Entries,
+					cloud.FormatBytes(c.Bytes), cloud.FormatBytes(c.InUseBytes), formatCacheLimit(c.Limit), oldest)
+			}
+			fmt.Printf("\nTotal %s of %s\n", cloud.FormatBytes(stats.Bytes), formatCacheLimit(stats.Limit))
+			return statsErr
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear [category...]",
+		Short: "Remove cached files",
+		Long: `Remove every cached file in the given categories (tars, resume), or
+in all categories if none are given. Files held by a running PUR run or
+transfer are skipped. Cleared tars are rebuilt if their run is resumed.
+
+Examples:
+  rescale-int cache clear
+  rescale-int cache clear tars resume`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := loadCacheManager()
+			if err != nil {
+				return err
+			}
+			res := mgr.Clear(args...)
+			for _, e := range res.Errors {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", e)
+			}
+			fmt.Printf("Removed %d file(s), freed %s\n", res.Removed, cloud.FormatBytes(res.Freed))
+			if len(res.Errors) > 0 {
+				return fmt.Errorf("%d cache entries could not be cleared", len(res.Errors))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func formatCacheLimit(limit int64) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return cloud.FormatBytes(limit)
+}
+
+// enforceCacheLimits evicts cached files over cfg's limits, if any are set.
+// Failures are logged; they never fail the command that triggered them.
+func enforceCacheLimits(cfg *config.Config) {
+	if !cache.Configured(cfg) {
+		return
+	}
+	mgr, err := cache.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	res := mgr.Enforce()
+	for _, e := range res.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: cache eviction: %v\n", e)
+	}
+	if res.Removed > 0 {
+		fmt.Printf("Cache limit: evicted %d file(s), freed %s\n", res.Removed, cloud.FormatBytes(res.Freed))
+	}
+}
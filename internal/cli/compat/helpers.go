@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
 )
 
 // compatMonitorJob polls job status until a terminal state is reached.
@@ -23,7 +24,7 @@ func compatMonitorJob(ctx context.Context, jobID string, client *api.Client, cc
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			reqCtx, cancel := client.WithTimeout(ctx, config.APIStatus)
 			statuses, err := client.GetJobStatuses(reqCtx, jobID)
 			cancel()
 
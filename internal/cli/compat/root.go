@@ -7,7 +7,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/version"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // NewCompatRootCmd creates the root Cobra command for compat mode.
@@ -38,6 +40,12 @@ Exit codes:
 			// Store compat context in command context for subcommands
 			SetCompatContext(cmd, cc)
 
+			// Compat commands take credentials from apiconfig, but a station
+			// set up as a read-only viewer stays one
+			if cfg, err := config.LoadConfigCSV(config.GetDefaultConfigPath()); err == nil {
+				viewer.SetEnabled(cfg.ViewerMode)
+			}
+
 			// Skip auth for commands that don't need it (e.g., check-for-update)
 			if cmd.Annotations != nil && cmd.Annotations["skipAuth"] == "true" {
 				return nil
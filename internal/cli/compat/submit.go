@@ -18,6 +18,7 @@ import (
 	"github.com/rescale/rescale-int/internal/pur/parser"
 	"github.com/rescale/rescale-int/internal/util/analysis"
 	"github.com/rescale/rescale-int/internal/util/glob"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
 )
 
 func newSubmitCmd() *cobra.Command {
@@ -220,7 +221,7 @@ func compatStageSubmitFiles(scriptFile string, inputFiles []string) (tmpDir stri
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
 	}
-	cleanup = func() { os.RemoveAll(tmpDir) }
+	cleanup = func() { securedelete.RemoveAll(tmpDir) }
 
 	// Copy script to run.sh
 	runShPath := filepath.Join(tmpDir, "run.sh")
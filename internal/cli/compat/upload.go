@@ -14,6 +14,7 @@ import (
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/cloud/credentials"
 	"github.com/rescale/rescale-int/internal/cloud/upload"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/models"
@@ -246,6 +247,7 @@ func compatUploadCoreValidated(ctx context.Context, filePaths []string, folderID
 			ProgressCallback: progressCB,
 			TransferHandle:   transferHandle,
 			PreEncrypt:       false,
+			ResumeDir:        config.GetTransferStateDir(),
 		})
 
 		if err != nil {
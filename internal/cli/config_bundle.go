@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// newConfigExportBundleCmd creates the 'config export-bundle' command.
+func newConfigExportBundleCmd() *cobra.Command {
+	var (
+		encryptSecrets bool
+		passphraseFile string
+		overwrite      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-bundle <file>",
+		Short: "Save the whole configuration to one file for another workstation",
+		Long: `Save settings (config.csv), daemon settings (daemon.conf) and the saved job
+templates to a single bundle file, to restore them on another workstation
+with 'config import-bundle'.
+
+The API key and the notification secrets (webhook secret, Slack/Teams webhook
+URLs, SMTP password) are only included when they are encrypted: with
+--encrypt-secrets a passphrase is prompted for (or read from
+--passphrase-file) and they are sealed with AES-256-GCM. Without it the
+bundle records which were left out, and they must be entered again on the
+new workstation.
+
+Examples:
+  # Settings and templates only
+  rescale-int config export-bundle interlink.bundle
+
+  # Include the API key and other secrets, encrypted with a passphrase
+  rescale-int config export-bundle interlink.bundle --encrypt-secrets`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := args[0]
+			if _, err := os.Stat(out); err == nil && !overwrite {
+				return fmt.Errorf("%s already exists (use --overwrite to replace it)", out)
+			}
+
+			var passphrase string
+			if encryptSecrets || passphraseFile != "" {
+				var err error
+				if passphrase, err = readBundlePassphrase(passphraseFile, true); err != nil {
+					return err
+				}
+			}
+
+			bundle, err := config.ExportBundle(config.DefaultBundlePaths(cfgFile), passphrase)
+			if err != nil {
+				return err
+			}
+			if err := config.WriteBundle(bundle, out); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Configuration bundle written to %s\n", out)
+			names := make([]string, 0, len(bundle.Files))
+			for name := range bundle.Files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("  %s\n", name)
+			}
+			if bundle.Secrets != nil {
+				fmt.Println("  secrets (encrypted)")
+			}
+			if len(bundle.SecretsOmitted) > 0 {
+				fmt.Println()
+				fmt.Printf("Secrets not included: %s. Use --encrypt-secrets to include them,\n", strings.Join(bundle.SecretsOmitted, ", "))
+				fmt.Println("or enter them again on the new workstation.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&encryptSecrets, "encrypt-secrets", false, "Include the API key and notification secrets, encrypted with a passphrase you are prompted for")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "Read the passphrase from this file instead of prompting (implies --encrypt-secrets)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the bundle file if it exists")
+
+	return cmd
+}
+
+// newConfigImportBundleCmd creates the 'config import-bundle' command.
+func newConfigImportBundleCmd() *cobra.Command {
+	var (
+		passphraseFile string
+		skipSecrets    bool
+		overwrite      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import-bundle <file>",
+		Short: "Restore the configuration from a bundle",
+		Long: `Restore settings, daemon settings, saved job templates and (when the bundle
+holds them encrypted) the API key and notification secrets from a file
+written by 'config export-bundle'.
+
+Files that already exist are kept unless --overwrite is given. If the bundle
+holds encrypted secrets, its passphrase is prompted for (or read from
+--passphrase-file); a wrong passphrase stops the import before anything is
+written. Use --skip-secrets to restore everything but the secrets.
+
+Examples:
+  rescale-int config import-bundle interlink.bundle
+  rescale-int config import-bundle interlink.bundle --overwrite`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bundle, err := config.ReadBundle(args[0])
+			if err != nil {
+				return err
+			}
+
+			var passphrase string
+			if bundle.Secrets != nil && !skipSecrets {
+				if passphrase, err = readBundlePassphrase(passphraseFile, false); err != nil {
+					return err
+				}
+			}
+
+			paths := config.DefaultBundlePaths(cfgFile)
+			result, err := config.ImportBundle(bundle, paths, passphrase, overwrite)
+			if err != nil {
+				return err
+			}
+
+			source := bundle.Hostname
+			if source == "" {
+				source = "unknown host"
+			}
+			fmt.Printf("Configuration bundle from %s (created %s)\n", source, bundle.CreatedAt.Local().Format("2006-01-02 15:04"))
+			for _, name := range result.Written {
+				fmt.Printf("  ✓ %s\n", name)
+			}
+			for _, name := range result.SecretsRestored {
+				target := config.GetSecretsPath(paths.ConfigFile)
+				if name == config.SecretAPIToken {
+					target = paths.TokenFile
+				}
+				fmt.Printf("  ✓ %s → %s\n", name, target)
+			}
+			for _, name := range result.Skipped {
+				fmt.Printf("  - %s (exists, kept)\n", name)
+			}
+			if len(result.Skipped) > 0 {
+				fmt.Println("Use --overwrite to replace existing files.")
+			}
+			if len(result.SecretsOmitted) > 0 || (bundle.Secrets != nil && skipSecrets) {
+				fmt.Println("Secrets were not restored; set the API key with 'rescale-int config init'")
+				fmt.Println("and re-enter any notification secrets.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "Read the passphrase from this file instead of prompting")
+	cmd.Flags().BoolVar(&skipSecrets, "skip-secrets", false, "Do not restore the API key or notification secrets")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace existing files")
+
+	return cmd
+}
+
+// readBundlePassphrase reads the passphrase from path, or prompts for it
+// (twice when confirm is set).
+func readBundlePassphrase(path string, confirm bool) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase := strings.TrimRight(string(data), "\r\n")
+		if passphrase == "" {
+			return "", fmt.Errorf("passphrase file %s is empty", path)
+		}
+		return passphrase, nil
+	}
+	if !IsTerminal() {
+		return "", fmt.Errorf("a passphrase is required: use --passphrase-file when not running in a terminal")
+	}
+
+	fmt.Print("Bundle passphrase: ")
+	first, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(first) == 0 {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	if confirm {
+		fmt.Print("Repeat passphrase: ")
+		second, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(first) != string(second) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return string(first), nil
+}
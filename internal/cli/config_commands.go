@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +16,10 @@ import (
 
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/util/tar"
 )
 
 // newConfigCmd creates the 'config' command group.
@@ -25,10 +30,12 @@ func newConfigCmd() *cobra.Command {
 		Long: `Configuration management commands for rescale-int.
 
 Commands:
-  init  - Interactive configuration setup
-  show  - Display current configuration
-  test  - Test API connection
-  path  - Show configuration file path`,
+  init          - Interactive configuration setup
+  show          - Display current configuration
+  test          - Test API connection
+  path          - Show configuration file path
+  export-bundle - Save the configuration to one file for another workstation
+  import-bundle - Restore the configuration from a bundle`,
 	}
 
 	// Add config subcommands
@@ -36,6 +43,8 @@ Commands:
 	configCmd.AddCommand(newConfigShowCmd())
 	configCmd.AddCommand(newConfigTestCmd())
 	configCmd.AddCommand(newConfigPathCmd())
+	configCmd.AddCommand(newConfigExportBundleCmd())
+	configCmd.AddCommand(newConfigImportBundleCmd())
 
 	return configCmd
 }
@@ -157,6 +166,7 @@ Use --force to overwrite existing configuration.`,
 				if config.NTLMProxySupported() {
 					proxyModes += ", ntlm"
 				}
+				proxyModes += ", negotiate"
 				fmt.Printf("Proxy modes: %s\n", proxyModes)
 				fmt.Print("Proxy mode [system]: ")
 				proxyModeInput, _ := reader.ReadString('\n')
@@ -189,17 +199,28 @@ Use --force to overwrite existing configuration.`,
 
 			// Create config
 			cfg := &config.Config{
-				APIKey:         apiKeyInput,
-				APIBaseURL:     apiURLInput,
-				TenantURL:      apiURLInput,
-				TarWorkers:     tarWorkers,
-				UploadWorkers:  uploadWorkers,
-				JobWorkers:     jobWorkers,
-				ProxyMode:      proxyMode,
-				ProxyHost:      proxyHost,
-				ProxyPort:      proxyPort,
-				TarCompression: "none",
-				MaxRetries:     1,
+				APIKey:               apiKeyInput,
+				APIBaseURL:           apiURLInput,
+				TenantURL:            apiURLInput,
+				TarWorkers:           tarWorkers,
+				UploadWorkers:        uploadWorkers,
+				JobWorkers:           jobWorkers,
+				ProxyMode:            proxyMode,
+				ProxyHost:            proxyHost,
+				ProxyPort:            proxyPort,
+				TarCompression:       "none",
+				TarSplitMode:         "none",
+				TarSplitParts:        constants.DefaultTarSplitParts,
+				CPUBudgetPercent:     constants.DefaultCPUBudgetPercent,
+				CPUReduceOnBattery:   true,
+				MaxRetries:           1,
+				PartRetries:          constants.MaxRetries,
+				LargeUploadConfirmGB: config.DefaultLargeUploadConfirmGB,
+				SettleSeconds:        config.DefaultSettleSeconds,
+				SettleTimeoutSeconds: config.DefaultSettleTimeoutSeconds,
+				TarLockedFiles:       "fail",
+				TarLockRetrySeconds:  constants.DefaultTarLockRetrySeconds,
+				TarLockRetries:       constants.DefaultTarLockRetries,
 			}
 
 			// Ensure config directory exists
@@ -290,6 +311,13 @@ Priority: flags > environment > config file > defaults`,
 			} else {
 				fmt.Println("  API Key:      <not set>")
 			}
+			if cfg.APIBasePath != "" {
+				fmt.Printf("  Base Path:    %s\n", cfg.APIBasePath)
+			}
+			if names := gatewayHeaderNames(cfg); names != "" {
+				// Values may be tokens; show names only
+				fmt.Printf("  Headers:      %s\n", names)
+			}
 			fmt.Println()
 
 			fmt.Println("Worker Settings:")
@@ -309,6 +337,42 @@ Priority: flags > environment > config file > defaults`,
 			fmt.Println("Advanced Settings:")
 			fmt.Printf("  Tar Compression: %s\n", cfg.TarCompression)
 			fmt.Printf("  Max Retries:     %d\n", cfg.MaxRetries)
+			fmt.Printf("  Part Retries:    %d\n", inthttp.PartRetries(cfg))
+			if cfg.LargeUploadConfirmGB > 0 {
+				fmt.Printf("  Confirm Uploads: over %d GB\n", cfg.LargeUploadConfirmGB)
+			} else {
+				fmt.Printf("  Confirm Uploads: never\n")
+			}
+			if cfg.SettleSeconds > 0 {
+				fmt.Printf("  Input Settle:    %ds (timeout %ds)\n", cfg.SettleSeconds, cfg.SettleTimeoutSeconds)
+			} else {
+				fmt.Printf("  Input Settle:    disabled\n")
+			}
+			switch tar.NormalizeSplitMode(cfg.TarSplitMode) {
+			case tar.SplitSubdirs:
+				fmt.Printf("  Tar Split:       one tar per subdirectory\n")
+			case tar.SplitSize:
+				fmt.Printf("  Tar Split:       %d tars by size\n", cfg.TarSplitParts)
+			}
+			switch tar.NormalizeLockedPolicy(cfg.TarLockedFiles) {
+			case tar.LockedSkip:
+				fmt.Printf("  Locked Files:    skip and record\n")
+			case tar.LockedRetry:
+				fmt.Printf("  Locked Files:    retry %d times, %ds apart\n", cfg.TarLockRetries, cfg.TarLockRetrySeconds)
+			case tar.LockedSnapshot:
+				fmt.Printf("  Locked Files:    read from shadow copy\n")
+			}
+			switch tar.NormalizeTextMode(cfg.TextNormalize) {
+			case tar.TextNormalizeCheck:
+				fmt.Printf("  Text Inputs:     check line endings and BOM\n")
+			case tar.TextNormalizeFix:
+				fmt.Printf("  Text Inputs:     convert to LF, remove BOM\n")
+			}
+			if cfg.CPUReduceOnBattery {
+				fmt.Printf("  CPU Budget:      %d%% of cores (halved on battery)\n", cfg.CPUBudgetPercent)
+			} else {
+				fmt.Printf("  CPU Budget:      %d%% of cores\n", cfg.CPUBudgetPercent)
+			}
 			if cfg.RunSubpath != "" {
 				fmt.Printf("  Run Subpath:     %s\n", cfg.RunSubpath)
 			}
@@ -342,7 +406,8 @@ func newConfigTestCmd() *cobra.Command {
 		Short: "Test API connection",
 		Long: `Test the API connection with current configuration.
 
-Use this to verify your API key and network connectivity.`,
+Use this to verify your API key and network connectivity. Also reports
+whether secure deletion of temp files is on and its storage limitations.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
@@ -369,7 +434,12 @@ Use this to verify your API key and network connectivity.`,
 				return fmt.Errorf("invalid configuration: %w", err)
 			}
 
-			fmt.Printf("API URL: %s\n", cfg.APIBaseURL)
+			printSecureDeleteStatus(cfg)
+
+			fmt.Printf("API URL: %s\n", cfg.APIRoot())
+			if names := gatewayHeaderNames(cfg); names != "" {
+				fmt.Printf("Gateway headers: %s\n", names)
+			}
 			fmt.Println("Testing connection...")
 			fmt.Println()
 
@@ -408,6 +478,43 @@ Use this to verify your API key and network connectivity.`,
 	return cmd
 }
 
+// printSecureDeleteStatus reports the secure_delete setting and, when it is
+// on, where overwriting cannot be relied on.
+func printSecureDeleteStatus(cfg *config.Config) {
+	if !cfg.SecureDelete {
+		fmt.Println("Secure delete: off (temp files are removed with a plain delete)")
+		fmt.Println()
+		return
+	}
+	fmt.Println("Secure delete: on (temp files are overwritten before deletion)")
+	fmt.Printf("  Temp directory: %s\n", os.TempDir())
+	fmt.Println("  ⚠ Limitations:")
+	for _, line := range wrapText(securedelete.Limitations, 72) {
+		fmt.Printf("    %s\n", line)
+	}
+	fmt.Println()
+}
+
+// wrapText splits s into lines of at most width characters on word boundaries.
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // newConfigPathCmd creates the 'config path' command.
 func newConfigPathCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -447,3 +554,18 @@ func newConfigPathCmd() *cobra.Command {
 
 	return cmd
 }
+
+// gatewayHeaderNames lists the names of the configured api_headers, for
+// display without their values.
+func gatewayHeaderNames(cfg *config.Config) string {
+	headers, err := config.ParseAPIHeaders(cfg.APIHeaders)
+	if err != nil {
+		return "<invalid>"
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -103,7 +103,7 @@ func TestConfigCmd(t *testing.T) {
 
 	// Check that subcommands exist
 	subcommands := cmd.Commands()
-	expectedSubs := []string{"init", "show", "test", "path"}
+	expectedSubs := []string{"init", "show", "test", "path", "export-bundle", "import-bundle"}
 
 	if len(subcommands) != len(expectedSubs) {
 		t.Errorf("Expected %d subcommands, got %d", len(expectedSubs), len(subcommands))
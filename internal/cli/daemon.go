@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/pathutil"
 	"github.com/rescale/rescale-int/internal/service"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // newDaemonCmd creates the 'daemon' command group.
@@ -131,6 +133,11 @@ Examples:
 			// I/O that reads credentials / state / logs.
 			config.RunStartupMigrations(nil, config.ScopeCurrentUser, nil)
 
+			// The daemon exists to download
+			if err := viewer.Check("auto-download daemon"); err != nil {
+				return err
+			}
+
 			// Early startup logging for debugging Windows subprocess launch issues.
 			// This writes to a file BEFORE the logger is fully initialized.
 			if runtime.GOOS == "windows" {
@@ -295,6 +302,12 @@ Examples:
 				LookbackDays:    daemonConf.Daemon.LookbackDays,
 			}
 
+			// Runaway job watchdog (daemon.conf [watchdog])
+			if err := daemonConf.ValidateWatchdog(); err != nil {
+				return fmt.Errorf("invalid daemon.conf: %w", err)
+			}
+			daemonCfg.Watchdog = daemon.WatchdogConfigFromDaemonConf(daemonConf)
+
 			// Load app config
 			cfg, err := loadConfig()
 			if err != nil {
@@ -899,6 +912,14 @@ Shows all settings from daemon.conf, or defaults if the file doesn't exist.`,
 			fmt.Printf("enabled = %t\n", cfg.Notifications.Enabled)
 			fmt.Printf("show_download_complete = %t\n", cfg.Notifications.ShowDownloadComplete)
 			fmt.Printf("show_download_failed = %t\n", cfg.Notifications.ShowDownloadFailed)
+			fmt.Println()
+
+			fmt.Println("[watchdog]")
+			fmt.Printf("enabled = %t\n", cfg.Watchdog.Enabled)
+			fmt.Printf("action = %s\n", cfg.Watchdog.Action)
+			fmt.Printf("max_hours = %g\n", cfg.Watchdog.MaxHours)
+			fmt.Printf("max_cost = %g\n", cfg.Watchdog.MaxCost)
+			fmt.Printf("protected = %s\n", cfg.Watchdog.Protected)
 
 			return nil
 		},
@@ -1005,6 +1026,13 @@ Available keys:
     show_download_complete   - true/false
     show_download_failed     - true/false
 
+  [watchdog]                 (only jobs submitted via Interlink are checked)
+    watchdog_enabled         - true/false
+    watchdog_action          - notify or terminate
+    watchdog_max_hours       - wall-clock cap in hours since the job started (0 = none)
+    watchdog_max_cost        - estimated cost cap (0 = none)
+    watchdog_protected       - comma-separated job IDs / name patterns never stopped
+
 Note (v4.3.0): Mode (Enabled/Conditional/Disabled) is now set per-job via the
 "Auto Download" custom field in your Rescale workspace, not in this config.
 
@@ -1012,7 +1040,8 @@ Examples:
   rescale-int daemon config set download_folder /path/to/downloads
   rescale-int daemon config set poll_interval_minutes 10
   rescale-int daemon config set auto_download_tag autoDownload
-  rescale-int daemon config set exclude "test,debug,scratch"`,
+  rescale-int daemon config set exclude "test,debug,scratch"
+  rescale-int daemon config set watchdog_max_hours 48`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
@@ -1092,6 +1121,28 @@ Examples:
 			case "show_download_failed":
 				cfg.Notifications.ShowDownloadFailed = value == "true" || value == "1" || value == "yes"
 
+			// [watchdog] section
+			case "watchdog_enabled":
+				cfg.Watchdog.Enabled = value == "true" || value == "1" || value == "yes"
+			case "watchdog_action":
+				v := strings.ToLower(value)
+				if v != config.WatchdogActionNotify && v != config.WatchdogActionTerminate {
+					return fmt.Errorf("watchdog_action must be notify or terminate")
+				}
+				cfg.Watchdog.Action = v
+			case "watchdog_max_hours", "watchdog_max_cost":
+				v, err := strconv.ParseFloat(value, 64)
+				if err != nil || v < 0 {
+					return fmt.Errorf("%s must be a number >= 0", key)
+				}
+				if key == "watchdog_max_hours" {
+					cfg.Watchdog.MaxHours = v
+				} else {
+					cfg.Watchdog.MaxCost = v
+				}
+			case "watchdog_protected":
+				cfg.Watchdog.Protected = value
+
 			default:
 				return fmt.Errorf("unknown configuration key: %s", key)
 			}
@@ -9,11 +9,15 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/cloud/credentials"
 	"github.com/rescale/rescale-int/internal/cloud/download"
 	"github.com/rescale/rescale-int/internal/cloud/state"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/models"
@@ -21,6 +25,7 @@ import (
 	"github.com/rescale/rescale-int/internal/transfer"
 	"github.com/rescale/rescale-int/internal/util/filter"
 	"github.com/rescale/rescale-int/internal/util/paths"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
 	"github.com/rescale/rescale-int/internal/validation"
 )
 
@@ -73,6 +78,7 @@ func executeFileDownload(
 	skipAll bool,
 	resumeAll bool,
 	skipChecksum bool,
+	verify bool,
 	apiClient *api.Client,
 	logger *logging.Logger,
 ) error {
@@ -80,6 +86,20 @@ func executeFileDownload(
 		return fmt.Errorf("at least one file ID is required")
 	}
 
+	// A repeated ID would be downloaded to the same path twice at once
+	seen := make(map[string]bool, len(fileIDs))
+	unique := make([]string, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) < len(fileIDs) {
+		fmt.Printf("⚠️  Skipping %d repeated file ID(s)\n", len(fileIDs)-len(unique))
+		fileIDs = unique
+	}
+
 	inthttp.WarmupProxyIfNeeded(ctx, apiClient.GetConfig())
 	credentials.GetManager(apiClient).WarmAll(ctx)
 
@@ -304,7 +324,7 @@ func executeFileDownload(
 						if encErr == nil {
 							fmt.Fprintf(downloadUI.Writer(), "Encrypted file has unexpected size (%d bytes, expected %d-%d bytes). Starting fresh download for %s...\n",
 								encryptedInfo.Size(), minEncryptedSize, maxEncryptedSize, item.name)
-							os.Remove(encryptedPath)
+							securedelete.Remove(encryptedPath)
 						}
 						os.Remove(outputPath)
 					}
@@ -338,6 +358,7 @@ func executeFileDownload(
 			},
 			TransferHandle: transferHandle,
 			SkipChecksum:   skipChecksum,
+			Verify:         verify,
 		})
 
 		if err != nil {
@@ -395,6 +416,28 @@ func executeFileDownload(
 	return nil
 }
 
+// defaultJobDownloadDir returns where `jobs download` saves a job's outputs
+// when no --output-dir is given: the configured download root, organized by
+// job name and/or date, or the working directory when no root is set.
+func defaultJobDownloadDir(ctx context.Context, apiClient *api.Client, jobID string) string {
+	cfg := apiClient.GetConfig()
+	if cfg == nil || cfg.DownloadDir == "" {
+		return "."
+	}
+
+	// The job name only matters when organizing by job; skip the lookup otherwise
+	jobName := ""
+	if cfg.DownloadOrganize == config.DownloadOrganizeJob || cfg.DownloadOrganize == config.DownloadOrganizeDateJob {
+		if job, err := apiClient.GetJob(ctx, jobID); err == nil {
+			jobName = job.Name
+		}
+		if jobName == "" {
+			jobName = jobID
+		}
+	}
+	return config.DownloadDirectory(cfg, jobName, time.Now())
+}
+
 // executeJobDownload - Common download logic for job output files.
 // Uses v2 ListJobFiles endpoint (jobs-usage scope) for efficient metadata
 // retrieval — no per-file GetFileInfo calls needed.
@@ -407,6 +450,7 @@ func executeJobDownload(
 	skipAll bool,
 	resumeAll bool,
 	skipChecksum bool,
+	verify bool,
 	filterPatterns []string,
 	excludePatterns []string,
 	searchTerms []string,
@@ -452,6 +496,25 @@ func executeJobDownload(
 		}
 	}
 
+	return downloadJobFiles(ctx, jobID, files, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, verify, apiClient, logger)
+}
+
+// downloadJobFiles downloads files, listed from job jobID, into outputDir
+// with their relative paths preserved.
+func downloadJobFiles(
+	ctx context.Context,
+	jobID string,
+	files []models.JobFile,
+	outputDir string,
+	maxConcurrent int,
+	overwriteAll bool,
+	skipAll bool,
+	resumeAll bool,
+	skipChecksum bool,
+	verify bool,
+	apiClient *api.Client,
+	logger *logging.Logger,
+) error {
 	if outputDir == "" {
 		outputDir = "."
 	}
@@ -514,10 +577,10 @@ func executeJobDownload(
 	// Zerolog outputs JSON which causes "invalid character '\x1b'" errors
 	// when mixed with ANSI escape codes from mpb progress bars.
 
-	defer downloadUI.Wait()
-
 	downloadedFiles := make([]string, 0, len(files))
 	skippedFiles := make([]string, 0)
+	redownloadedFiles := make([]string, 0)
+	verifyTargets := make([]download.VerifyTarget, 0, len(files))
 	var downloadMutex sync.Mutex
 
 	initialConflictMode := DownloadSkipOnce
@@ -583,6 +646,7 @@ func executeJobDownload(
 				fmt.Fprintf(downloadUI.Writer(), "⊘ Skipping existing file: %s\n", item.name)
 				downloadMutex.Lock()
 				skippedFiles = append(skippedFiles, outputPath)
+				verifyTargets = append(verifyTargets, download.VerifyTarget{LocalPath: outputPath, File: item.jobFile.ToCloudFile()})
 				downloadMutex.Unlock()
 				return nil
 			case DownloadAbort:
@@ -625,7 +689,7 @@ func executeJobDownload(
 						if encErr == nil {
 							fmt.Fprintf(downloadUI.Writer(), "Encrypted file has unexpected size (%d bytes, expected %d-%d bytes). Starting fresh download for %s...\n",
 								encryptedInfo.Size(), minEncryptedSize, maxEncryptedSize, item.name)
-							os.Remove(encryptedPath)
+							securedelete.Remove(encryptedPath)
 						}
 						os.Remove(outputPath)
 					}
@@ -645,7 +709,7 @@ func executeJobDownload(
 
 		cloudFile := item.jobFile.ToCloudFile()
 
-		err = downloadFileFn(ctx, download.DownloadParams{
+		err := downloadFileFn(ctx, download.DownloadParams{
 			FileInfo:  cloudFile,
 			LocalPath: outputPath,
 			APIClient: apiClient,
@@ -659,6 +723,15 @@ func executeJobDownload(
 			},
 			TransferHandle: transferHandle,
 			SkipChecksum:   skipChecksum,
+			OnIntegrityRetry: func(attempt int, err error) {
+				fmt.Fprintf(downloadUI.Writer(), "⚠ %s failed verification (%v), downloading again (retry %d of %d)\n",
+					item.name, err, attempt, constants.DownloadIntegrityRetries)
+				if attempt == 1 {
+					downloadMutex.Lock()
+					redownloadedFiles = append(redownloadedFiles, item.name)
+					downloadMutex.Unlock()
+				}
+			},
 		})
 
 		if err != nil {
@@ -691,29 +764,87 @@ func executeJobDownload(
 
 		downloadMutex.Lock()
 		downloadedFiles = append(downloadedFiles, outputPath)
+		verifyTargets = append(verifyTargets, download.VerifyTarget{LocalPath: outputPath, File: cloudFile})
 		downloadMutex.Unlock()
 		return nil
 	})
+	// Let the bars finish before the verification pass and summary print
+	downloadUI.Wait()
 
 	// Collect errors from batch result
 	dlErrors := batchResult.Errors
 
+	var verifyErr error
+	if verify && len(verifyTargets) > 0 && ctx.Err() == nil {
+		var repaired []string
+		repaired, verifyErr = verifyJobDownload(ctx, jobID, verifyTargets, maxConcurrent, apiClient)
+		redownloadedFiles = append(redownloadedFiles, repaired...)
+	}
+
 	// Print summary
+	fmt.Printf("\n✓ Successfully downloaded %d file(s)\n", len(downloadedFiles))
+	if len(skippedFiles) > 0 {
+		fmt.Printf("⊘ Skipped %d file(s)\n", len(skippedFiles))
+	}
+	if len(redownloadedFiles) > 0 {
+		fmt.Printf("⚠ Re-downloaded %d file(s) that failed verification: %s\n",
+			len(redownloadedFiles), strings.Join(redownloadedFiles, ", "))
+	}
 	if len(dlErrors) > 0 {
-		fmt.Printf("\n✓ Successfully downloaded %d file(s)\n", len(downloadedFiles))
-		if len(skippedFiles) > 0 {
-			fmt.Printf("⊘ Skipped %d file(s)\n", len(skippedFiles))
-		}
 		fmt.Printf("✗ Failed to download %d file(s)\n", len(dlErrors))
 		// Return first error but continue with others (per project objectives)
 		return dlErrors[0]
 	}
+	return verifyErr
+}
 
-	fmt.Printf("\n✓ Successfully downloaded %d file(s)\n", len(downloadedFiles))
-	if len(skippedFiles) > 0 {
-		fmt.Printf("⊘ Skipped %d file(s)\n", len(skippedFiles))
+// verifyJobDownload re-checks downloaded (and skipped existing) job outputs
+// against the platform's checksums, or sizes where there are none, hashing
+// up to maxConcurrent files at a time. Files that don't match are downloaded
+// again. It returns the names of the files it downloaded again, and an error
+// if any file still fails.
+func verifyJobDownload(ctx context.Context, jobID string, targets []download.VerifyTarget, maxConcurrent int, apiClient *api.Client) ([]string, error) {
+	fmt.Printf("\nVerifying %d file(s)...\n", len(targets))
+
+	var repaired []string
+	var failed []download.Verification
+	bySHA512, bySize := 0, 0
+	for _, v := range download.VerifyFiles(ctx, targets, maxConcurrent) {
+		if download.IsIntegrityError(v.Err) && ctx.Err() == nil {
+			fmt.Printf("⚠ %s failed verification (%v), downloading again\n", v.File.Name, v.Err)
+			_ = os.Remove(v.LocalPath)
+			err := downloadFileFn(ctx, download.DownloadParams{
+				FileInfo:  v.File,
+				LocalPath: v.LocalPath,
+				APIClient: apiClient,
+			})
+			if err == nil {
+				repaired = append(repaired, v.File.Name)
+				v = download.VerifyFile(v.VerifyTarget)
+			} else {
+				v.Err = err
+			}
+		}
+		if v.Err != nil {
+			failed = append(failed, v)
+			continue
+		}
+		if v.Method == download.VerifiedBySHA512 {
+			bySHA512++
+		} else {
+			bySize++
+		}
 	}
-	return nil
+
+	fmt.Printf("✓ Verified %d file(s): %d by SHA-512, %d by size only\n", bySHA512+bySize, bySHA512, bySize)
+	if len(failed) == 0 {
+		return repaired, nil
+	}
+	fmt.Printf("✗ %d file(s) failed verification:\n", len(failed))
+	for _, v := range failed {
+		fmt.Printf("    %s: %v\n", v.File.Name, v.Err)
+	}
+	return repaired, fmt.Errorf("%d file(s) of job %s failed verification", len(failed), jobID)
 }
 
 // sanitizeErrorString removes secrets (SAS tokens, access keys, session tokens)
@@ -741,6 +872,8 @@ func classifyDownloadStep(err error) string {
 		return "downloading from storage"
 	case strings.Contains(s, "checksum"):
 		return "verifying checksum"
+	case errors.Is(err, cloud.ErrSizeMismatch):
+		return "verifying file size"
 	case strings.Contains(s, "decrypt"):
 		return "decrypting file"
 	default:
@@ -755,11 +888,11 @@ func classifyDownloadStep(err error) string {
 func formatDownloadError(fileName, fileID, jobID, storageType string, err error) error {
 	step := classifyDownloadStep(err)
 
-	// Extract root cause
+	// Extract root cause, keeping the details of a checksum or size mismatch
 	rootCause := err
 	for {
 		unwrapped := errors.Unwrap(rootCause)
-		if unwrapped == nil {
+		if unwrapped == nil || unwrapped == cloud.ErrChecksumMismatch || unwrapped == cloud.ErrSizeMismatch {
 			break
 		}
 		rootCause = unwrapped
@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/notify"
+	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+// eventsSpec is the --events flag: where to stream machine-readable events.
+var eventsSpec string
+
+// cliEvents is the active event stream, nil unless --events was given or
+// webhooks are configured.
+var cliEvents *eventStream
+
+// eventStream forwards CLI events to an NDJSON sink and/or webhooks for
+// external orchestrators (Airflow, Jenkins, ...).
+type eventStream struct {
+	bus      *events.EventBus
+	sink     io.WriteCloser // nil without --events
+	notifier *notify.Notifier
+	done     chan struct{}
+}
+
+// nopCloser keeps stdout/stderr open when the stream is closed.
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// openEventSink opens the destination of an --events spec:
+//
+//	ndjson://stderr          standard error
+//	ndjson://stdout          standard output
+//	ndjson://unix:<path>     connect to a listening unix socket
+//	ndjson://<path>          append to a file
+func openEventSink(spec string) (io.WriteCloser, error) {
+	target, ok := strings.CutPrefix(spec, "ndjson://")
+	if !ok || target == "" {
+		return nil, fmt.Errorf("invalid --events %q: expected ndjson://stderr, ndjson://stdout, ndjson://unix:<socket> or ndjson://<file>", spec)
+	}
+	switch {
+	case target == "stderr":
+		return nopCloser{os.Stderr}, nil
+	case target == "stdout":
+		return nopCloser{os.Stdout}, nil
+	case strings.HasPrefix(target, "unix:"):
+		conn, err := net.DialTimeout("unix", strings.TrimPrefix(target, "unix:"), 5*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to event socket: %w", err)
+		}
+		return conn, nil
+	default:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event file: %w", err)
+		}
+		return f, nil
+	}
+}
+
+// commandNotifier returns a Notifier for the webhooks, Slack/Teams
+// integrations and run summary email in the config file, or nil when none are set. Like tracing, the config is read up front because
+// most commands load it later, if at all.
+func commandNotifier() *notify.Notifier {
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = config.GetDefaultConfigPath()
+	}
+	cfg, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		return nil
+	}
+	n, err := notify.FromConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: webhooks, chat and email notifications disabled: %v\n", err)
+		return nil
+	}
+	return n
+}
+
+// startEventStream streams every event published on the returned stream's
+// bus to spec (if not empty) and notifier (if not nil) until Close.
+func startEventStream(spec string, notifier *notify.Notifier) (*eventStream, error) {
+	var sink io.WriteCloser
+	if spec != "" {
+		var err error
+		if sink, err = openEventSink(spec); err != nil {
+			return nil, err
+		}
+	}
+	s := &eventStream{
+		bus:      events.NewEventBus(10000),
+		sink:     sink,
+		notifier: notifier,
+		done:     make(chan struct{}),
+	}
+	if notifier != nil {
+		notifier.Attach(s.bus)
+	}
+	if sink == nil {
+		close(s.done)
+		return s, nil
+	}
+	ch := s.bus.SubscribeAll()
+	go func() {
+		defer close(s.done)
+		if err := events.StreamNDJSON(ch, sink); err != nil {
+			log.Printf("Warning: event stream stopped: %v", err)
+			// Keep draining so publishers never notice
+			for range ch {
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Close flushes pending events, waits for webhook deliveries and closes the
+// sink.
+func (s *eventStream) Close() {
+	s.bus.Close()
+	<-s.done
+	if s.notifier != nil {
+		s.notifier.Close()
+	}
+	if s.sink != nil {
+		s.sink.Close()
+	}
+}
+
+// attachPipelineEvents publishes a pipeline's log, progress and state
+// changes on the CLI event stream. Logs still go to the console. No-op
+// without --events or webhooks.
+func attachPipelineEvents(pipe *pipeline.Pipeline) {
+	if cliEvents == nil {
+		return
+	}
+	bus := cliEvents.bus
+
+	pipe.SetLogCallback(func(level, message, stage, jobName string) {
+		// Setting a callback disables the pipeline's own console logging
+		log.Printf("[%s] [%s] %s%s", level, stage, message, logging.Correlation{RunID: pipe.RunID()}.Tag())
+
+		eventLevel := events.InfoLevel
+		switch level {
+		case "DEBUG":
+			eventLevel = events.DebugLevel
+		case "WARN":
+			eventLevel = events.WarnLevel
+		case "ERROR":
+			eventLevel = events.ErrorLevel
+		}
+		bus.Publish(&events.LogEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventLog, Time: time.Now(), RunID: pipe.RunID()},
+			Level:     eventLevel,
+			Message:   message,
+			Stage:     stage,
+			JobName:   jobName,
+		})
+	})
+
+	pipe.SetProgressCallback(func(completed, total int, stage, jobName string) {
+		progress := 0.0
+		if total > 0 {
+			progress = float64(completed) / float64(total)
+		}
+		bus.Publish(&events.ProgressEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventProgress, Time: time.Now(), RunID: pipe.RunID()},
+			JobName:   jobName,
+			Stage:     stage,
+			Progress:  progress,
+			Message:   fmt.Sprintf("%d/%d jobs complete", completed, total),
+		})
+	})
+
+	pipe.SetTarProgressCallback(func(jobName string, pr tar.Progress) {
+		bus.Publish(&events.ProgressEvent{
+			BaseEvent:    events.BaseEvent{EventType: events.EventProgress, Time: time.Now(), RunID: pipe.RunID()},
+			JobName:      jobName,
+			Stage:        "tar",
+			Progress:     pr.Fraction(),
+			BytesCurrent: pr.Bytes,
+			BytesTotal:   pr.TotalBytes,
+			Message:      pipeline.FormatTarProgress(pr),
+			Rate:         pr.Rate(),
+			ETA:          pr.ETA(),
+			FilesCurrent: pr.Files,
+			FilesTotal:   pr.TotalFiles,
+			CurrentFile:  pr.CurrentFile,
+		})
+	})
+
+	pipe.SetRunStartCallback(func(runID string, totalJobs int) {
+		bus.Publish(&events.RunStartedEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventRunStarted, Time: time.Now(), RunID: runID},
+			TotalJobs: totalJobs,
+		})
+	})
+
+	pipe.SetStateChangeCallback(func(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
+		bus.Publish(&events.StateChangeEvent{
+			BaseEvent:      events.BaseEvent{EventType: events.EventStateChange, Time: time.Now(), RunID: pipe.RunID()},
+			JobName:        jobName,
+			Stage:          stage,
+			NewStatus:      newStatus,
+			JobID:          jobID,
+			ErrorMessage:   errorMessage,
+			UploadProgress: uploadProgress,
+		})
+	})
+}
+
+// publishPipelineComplete emits the run's final tally on the CLI event
+// stream, counted the same way as the GUI engine. No-op without --events or
+// webhooks.
+func publishPipelineComplete(pipe *pipeline.Pipeline, start time.Time, reportPath string) {
+	if cliEvents == nil {
+		return
+	}
+	ev := &events.CompleteEvent{
+		BaseEvent:     events.BaseEvent{EventType: events.EventComplete, Time: time.Now(), RunID: pipe.RunID()},
+		Duration:      time.Since(start),
+		ReportPath:    reportPath,
+		StateFile:     pipe.StateManager().Path(),
+		BytesSent:     pipe.NetworkUsage().Sent,
+		BytesReceived: pipe.NetworkUsage().Received,
+	}
+	for _, job := range pipe.StateManager().GetAllStates() {
+		ev.TotalJobs++
+		switch {
+		case job.SubmitStatus == "success" || job.SubmitStatus == "completed" || job.SubmitStatus == "skipped":
+			ev.SuccessJobs++
+		case job.SubmitStatus == "failed" || job.TarStatus == "failed" || job.UploadStatus == "failed":
+			ev.FailedJobs++
+		}
+		if stage := state.FailedStage(job); stage != "" {
+			ev.Failures = append(ev.Failures, events.JobFailure{JobName: job.JobName, Stage: stage, Error: job.ErrorMessage})
+		}
+	}
+	cliEvents.bus.Publish(ev)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/rescale/rescale-int/internal/cloud/upload"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/util/filter"
 	"github.com/rescale/rescale-int/internal/util/tags"
@@ -28,6 +29,9 @@ func newFilesCmd() *cobra.Command {
 	filesCmd.AddCommand(newFilesListCmd())
 	filesCmd.AddCommand(newFilesDeleteCmd())
 	filesCmd.AddCommand(newFilesTagsCmd())
+	filesCmd.AddCommand(newFilesSyncCmd())
+	filesCmd.AddCommand(newFilesJournalCmd())
+	filesCmd.AddCommand(newFilesVerifyManifestCmd())
 
 	return filesCmd
 }
@@ -40,8 +44,12 @@ func newFilesUploadCmd() *cobra.Command {
 	var noCheckDuplicates bool
 	var skipDuplicates bool
 	var allowDuplicates bool
+	var onDuplicate string
+	var allowRepeats bool
 	var dryRun bool
+	var yes bool
 	var preEncrypt bool
+	var verify bool
 	var tagsFlag string
 
 	cmd := &cobra.Command{
@@ -56,13 +64,32 @@ Encryption modes:
   --pre-encrypt          Use legacy encryption (pre-encrypts entire file to temp file)
                          This mode is compatible with older Rescale clients (e.g., Python).
 
+Every upload records the SHA-256 of the plaintext with the file. With --verify,
+each file is hashed again once uploaded and fails with a checksum mismatch if it
+changed on disk during the upload.
+
 Duplicate handling modes:
   --check-duplicates     Check for existing files before uploading (prompts on conflict)
   --no-check-duplicates  Skip duplicate checking (fast, may create duplicates)
   --skip-duplicates      Check and automatically skip files that already exist
   --allow-duplicates     Check but upload anyway (creates duplicates with same name)
+  --on-duplicate POLICY  Check and apply POLICY to every duplicate:
+                           skip       don't upload the file
+                           overwrite  upload, then delete the existing file(s)
+                           rename     upload as "name (1).ext"
+                           version    upload as "name_v2.ext" (after the highest _vN)
+                           allow      same as --allow-duplicates
+
+The destination is listed once per upload, not once per file. When checking
+interactively, each duplicate can be skipped, overwritten, renamed or
+versioned, once or for all remaining files.
 
 If no duplicate flag is provided, you will be prompted interactively.
+
+A file named more than once (e.g. by a path and a pattern that also matches
+it) is uploaded once; interactively you are asked whether to upload the
+repeats too, and --allow-repeats uploads them without asking.
+
 Use --dry-run to preview what would happen without actually uploading.
 
 Examples:
@@ -75,6 +102,9 @@ Examples:
   # Upload with glob pattern, skip any duplicates
   rescale-int files upload *.dat --skip-duplicates
 
+  # Keep earlier uploads and add the new ones as model_v2.inp, model_v3.inp, ...
+  rescale-int files upload model.inp --on-duplicate version
+
   # Fast upload without duplicate checking
   rescale-int files upload *.dat --no-check-duplicates
 
@@ -82,7 +112,13 @@ Examples:
   rescale-int files upload *.zip --folder-id abc123
 
   # Use legacy pre-encryption for compatibility with older clients
-  rescale-int files upload large_file.tar.gz --pre-encrypt`,
+  rescale-int files upload large_file.tar.gz --pre-encrypt
+
+  # Fail any file that changes while it is being uploaded
+  rescale-int files upload run_output.h5 --verify
+
+  # Start without the confirmation shown for uploads over large_upload_confirm_gb
+  rescale-int files upload results/*.h5 --yes`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
@@ -107,13 +143,22 @@ Examples:
 			if allowDuplicates {
 				duplicateFlags++
 			}
+			if onDuplicate != "" {
+				duplicateFlags++
+			}
 			if duplicateFlags > 1 {
-				return fmt.Errorf("only one of --check-duplicates, --no-check-duplicates, --skip-duplicates, or --allow-duplicates can be specified")
+				return fmt.Errorf("only one of --check-duplicates, --no-check-duplicates, --skip-duplicates, --allow-duplicates or --on-duplicate can be specified")
 			}
 
 			// Determine duplicate handling mode
 			var duplicateMode UploadDuplicateMode
-			if noCheckDuplicates {
+			if onDuplicate != "" {
+				policy, err := upload.ParseDuplicatePolicy(onDuplicate)
+				if err != nil {
+					return err
+				}
+				duplicateMode = uploadDuplicateModeFor(policy)
+			} else if noCheckDuplicates {
 				duplicateMode = UploadDuplicateModeNoCheck
 			} else if skipDuplicates {
 				duplicateMode = UploadDuplicateModeSkipAll
@@ -136,6 +181,14 @@ Examples:
 				}
 			}
 
+			filePaths, err := expandUploadArgs(args)
+			if err != nil {
+				return err
+			}
+			if filePaths, err = resolveRepeatedUploads(filePaths, allowRepeats); err != nil {
+				return err
+			}
+
 			// Get API client
 			apiClient, err := getAPIClient()
 			if err != nil {
@@ -147,8 +200,14 @@ Examples:
 				uploadTags = tags.ParseCommaSeparated(tagsFlag)
 			}
 
+			if !dryRun {
+				if ok, err := confirmLargeUpload(GetContext(), apiClient, filePaths, yes); !ok || err != nil {
+					return err
+				}
+			}
+
 			// Use helper function with duplicate mode
-			return executeFileUploadWithDuplicateCheck(GetContext(), args, folderID, maxConcurrent, duplicateMode, dryRun, preEncrypt, uploadTags, apiClient, logger)
+			return executeFileUploadWithDuplicateCheck(GetContext(), filePaths, folderID, maxConcurrent, duplicateMode, dryRun, preEncrypt, verify, uploadTags, apiClient, logger)
 		},
 	}
 
@@ -159,9 +218,13 @@ Examples:
 	cmd.Flags().BoolVar(&noCheckDuplicates, "no-check-duplicates", false, "Skip duplicate checking (fast, may create duplicates)")
 	cmd.Flags().BoolVar(&skipDuplicates, "skip-duplicates", false, "Check and automatically skip files that already exist")
 	cmd.Flags().BoolVar(&allowDuplicates, "allow-duplicates", false, "Check but upload anyway (explicitly allows duplicates)")
+	cmd.Flags().StringVar(&onDuplicate, "on-duplicate", "", "Policy for files whose name already exists in the destination: skip, overwrite, rename, version or allow")
+	cmd.Flags().BoolVar(&allowRepeats, "allow-repeats", false, "Upload a file named more than once (e.g. by a path and a pattern) once per mention")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview what would be uploaded without actually uploading")
 	cmd.Flags().BoolVar(&preEncrypt, "pre-encrypt", false, "Use legacy pre-encryption (for compatibility with older Rescale clients)")
+	cmd.Flags().BoolVar(&verify, "verify", false, "Hash each file again after upload and fail it if it no longer matches the SHA-256 recorded during encryption")
 	cmd.Flags().StringVar(&tagsFlag, "tags", "", "Comma-separated tags to apply after upload (e.g., \"simulation,cfd,v2\")")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Start large uploads without asking for confirmation")
 
 	return cmd
 }
@@ -174,6 +237,7 @@ func newFilesDownloadCmd() *cobra.Command {
 	var skipAll bool
 	var resumeAll bool
 	var skipChecksum bool
+	var verify bool
 
 	cmd := &cobra.Command{
 		Use:   "download <file-id> [file-id...]",
@@ -188,7 +252,10 @@ Examples:
   rescale-int files download ABC123 DEF456 --outdir ./results
 
   # Download to current directory
-  rescale-int files download XxYyZz`,
+  rescale-int files download XxYyZz
+
+  # Re-read each file from disk and check it against the SHA-256 recorded at upload
+  rescale-int files download XxYyZz --verify`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
@@ -222,9 +289,12 @@ Examples:
 			if conflictFlags > 1 {
 				return fmt.Errorf("only one of --overwrite, --skip, or --resume can be specified")
 			}
+			if verify && skipChecksum {
+				return fmt.Errorf("--verify and --skip-checksum can't be used together")
+			}
 
 			// Use helper function
-			return executeFileDownload(GetContext(), args, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, apiClient, logger)
+			return executeFileDownload(GetContext(), args, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, verify, apiClient, logger)
 		},
 	}
 
@@ -235,6 +305,7 @@ Examples:
 	cmd.Flags().BoolVarP(&skipAll, "skip", "S", false, "Skip existing files without prompting")
 	cmd.Flags().BoolVarP(&resumeAll, "resume", "r", false, "Resume interrupted downloads without prompting")
 	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip checksum verification (not recommended, allows corrupted downloads)")
+	cmd.Flags().BoolVar(&verify, "verify", false, "Re-read each downloaded file and check it against the SHA-256 recorded at upload (or the SHA-512 or size if there is none)")
 
 	return cmd
 }
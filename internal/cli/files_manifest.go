@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud/manifest"
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// newFilesVerifyManifestCmd creates the 'files verify-manifest' command.
+func newFilesVerifyManifestCmd() *cobra.Command {
+	var (
+		hash       bool
+		reportPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify-manifest <local-dir | manifest-file>",
+		Short: "Check that everything in an uploaded directory exists on Rescale",
+		Long: `Check a directory uploaded with 'folders upload-dir' against the manifest the
+upload wrote: .rescale-manifest.json in the directory, or its manifest in the
+central store under the config directory.
+
+Every file listed must still exist on Rescale with the size and SHA-512
+registered at upload, and every local file must be listed and unchanged
+since (same size and modification time, or same content with --hash). Files
+deleted locally are reported but don't fail the check.
+
+The command exits non-zero if any file is not on Rescale as it is locally,
+or could not be checked.
+
+Examples:
+  # Check a directory against its manifest
+  rescale-int files verify-manifest ./my_project
+
+  # Compare file content rather than modification times, and save the report
+  rescale-int files verify-manifest ./my_project --hash --report verify.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := GetContext()
+
+			root, path, err := resolveManifest(args[0])
+			if err != nil {
+				return err
+			}
+			m, err := manifest.Load(path)
+			if err != nil {
+				return err
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			if platform := apiClient.GetConfig().APIBaseURL; m.Platform != "" && m.Platform != platform {
+				return fmt.Errorf("manifest %s is for %s, not %s", path, m.Platform, platform)
+			}
+
+			fmt.Printf("Verifying %s against %s (%d files, remote folder %s)...\n", root, path, len(m.Files), m.RemoteFolderID)
+			verifier := &manifest.Verifier{GetFileInfo: apiClient.GetFileInfo, Hash: hash}
+			report, err := verifier.Verify(ctx, m, root)
+			if err != nil {
+				return err
+			}
+			for _, res := range report.Results {
+				if res.Status != manifest.StatusOK {
+					fmt.Printf("  %-12s %s: %s\n", strings.ToUpper(res.Status), res.Path, res.Detail)
+				}
+			}
+
+			fmt.Printf("\nChecked %d: %d ok, %d not uploaded, %d changed locally, %d deleted locally, %d missing, %d mismatched, %d errors\n",
+				report.Checked, report.OK, report.NotUploaded, report.Changed, report.Deleted, report.Missing, report.Mismatched, report.Errors)
+
+			if reportPath != "" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(reportPath, data, 0600); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+				fmt.Printf("Report written to %s\n", reportPath)
+			}
+
+			if report.Failed() {
+				return fmt.Errorf("%d of %d files are not on Rescale as they are locally", report.Checked-report.OK-report.Deleted, report.Checked)
+			}
+			fmt.Println("✓ Everything in the directory exists on Rescale")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&hash, "hash", false, "Re-hash local files instead of trusting their size and modification time")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the JSON verification report to this file")
+
+	return cmd
+}
+
+// resolveManifest returns the directory to verify and its manifest for
+// arg, which is either the directory or a manifest file. A manifest file
+// inside a directory verifies that directory; one from the central store
+// verifies the directory it was written for.
+func resolveManifest(arg string) (root, path string, err error) {
+	info, err := os.Stat(arg)
+	if err != nil {
+		return "", "", err
+	}
+	if info.IsDir() {
+		path, err := manifest.Find(arg, config.GetManifestDir())
+		if err != nil {
+			return "", "", fmt.Errorf("%w (upload the directory with 'folders upload-dir' first)", err)
+		}
+		return arg, path, nil
+	}
+	if filepath.Base(arg) == manifest.FileName {
+		return filepath.Dir(arg), arg, nil
+	}
+	m, err := manifest.Load(arg)
+	if err != nil {
+		return "", "", err
+	}
+	return m.LocalRoot, arg, nil
+}
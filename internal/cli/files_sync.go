@@ -0,0 +1,528 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/credentials"
+	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/cloud/upload"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	encryption "github.com/rescale/rescale-int/internal/crypto"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/localfs/journal"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/progress"
+	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/transfer/dirsync"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+// syncOptions holds the 'files sync' flags.
+type syncOptions struct {
+	compare       dirsync.Options
+	dryRun        bool
+	includeHidden bool
+	yes           bool
+	maxConcurrent int
+}
+
+// newFilesSyncCmd creates the 'files sync' command.
+func newFilesSyncCmd() *cobra.Command {
+	var opts syncOptions
+	var uploadOnly bool
+	var downloadOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "sync <local-dir> <remote-folder-id>",
+		Short: "Synchronize a local directory with a Rescale folder",
+		Long: `Compare a local directory tree with a Rescale folder tree and transfer only
+the files that differ.
+
+Files are matched by their path below the two roots. A file is unchanged when
+its size matches and the local copy has not been modified since the remote one
+was uploaded; --checksum compares SHA-512 hashes instead (slower: every local
+file with a remote counterpart is read). Downloaded files take the remote upload
+time as their modification time, so the next sync sees them as unchanged.
+
+By default the sync runs both ways: new files are copied to the other side and
+for files that differ, the newer copy wins. --upload-only makes the Rescale
+folder match the local directory and --download-only the reverse; with either,
+--delete also removes files that exist only on the destination side, after
+asking for confirmation (skip it with --yes). Remote files are moved to the
+Trash, where they can be recovered. Empty directories are not synchronized.
+
+Examples:
+  # Preview what would be transferred
+  rescale-int files sync ./project abc123 --dry-run
+
+  # Two-way sync
+  rescale-int files sync ./project abc123
+
+  # Mirror a local directory to Rescale, removing remote files deleted locally
+  rescale-int files sync ./project abc123 --upload-only --delete
+
+  # Fetch new and changed results, comparing contents by hash
+  rescale-int files sync ./results abc123 --download-only --checksum`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			if opts.maxConcurrent < constants.MinMaxConcurrent || opts.maxConcurrent > constants.MaxMaxConcurrent {
+				return fmt.Errorf("--max-concurrent must be between %d and %d, got %d",
+					constants.MinMaxConcurrent, constants.MaxMaxConcurrent, opts.maxConcurrent)
+			}
+			switch {
+			case uploadOnly && downloadOnly:
+				return fmt.Errorf("only one of --upload-only or --download-only can be specified")
+			case uploadOnly:
+				opts.compare.Direction = dirsync.Up
+			case downloadOnly:
+				opts.compare.Direction = dirsync.Down
+			default:
+				opts.compare.Direction = dirsync.Both
+			}
+			if opts.compare.Delete && opts.compare.Direction == dirsync.Both {
+				return fmt.Errorf("--delete needs --upload-only or --download-only")
+			}
+			if opts.compare.Checksum {
+				opts.compare.Hash = encryption.CalculateSHA512
+			}
+
+			localDir := filepath.Clean(args[0])
+			info, err := os.Stat(localDir)
+			switch {
+			case os.IsNotExist(err) && opts.compare.Direction == dirsync.Down && !opts.dryRun:
+				if err := os.MkdirAll(localDir, 0755); err != nil {
+					return fmt.Errorf("failed to create local directory: %w", err)
+				}
+			case err != nil:
+				return fmt.Errorf("failed to access directory: %w", err)
+			case !info.IsDir():
+				return fmt.Errorf("path is not a directory: %s", localDir)
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			return executeFilesSync(GetContext(), localDir, args[1], opts, apiClient, logger)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "List the planned transfers and deletions without making them")
+	cmd.Flags().BoolVar(&opts.compare.Delete, "delete", false, "Delete files that exist only on the destination side (needs --upload-only or --download-only)")
+	cmd.Flags().BoolVar(&uploadOnly, "upload-only", false, "Only change the Rescale folder to match the local directory")
+	cmd.Flags().BoolVar(&downloadOnly, "download-only", false, "Only change the local directory to match the Rescale folder")
+	cmd.Flags().BoolVar(&opts.compare.Checksum, "checksum", false, "Compare SHA-512 hashes instead of size and modification time")
+	cmd.Flags().BoolVar(&opts.includeHidden, "include-hidden", false, "Include hidden local files (starting with .)")
+	cmd.Flags().IntVar(&opts.maxConcurrent, "max-concurrent", constants.DefaultMaxConcurrent,
+		fmt.Sprintf("Maximum concurrent file transfers (%d-%d)", constants.MinMaxConcurrent, constants.MaxMaxConcurrent))
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Start large uploads and deletions without asking for confirmation")
+
+	return cmd
+}
+
+// executeFilesSync scans both trees, prints the plan and, unless it is a dry
+// run, carries it out: uploads, then downloads, then deletions. A failed file
+// doesn't stop the others; the first error is returned at the end.
+func executeFilesSync(ctx context.Context, localDir, folderID string, opts syncOptions, apiClient *api.Client, logger *logging.Logger) error {
+	fmt.Printf("Scanning local directory %s...\n", localDir)
+	var local []dirsync.LocalFile
+	if _, err := os.Stat(localDir); err == nil {
+		var stats *journal.ScanStats
+		j := journal.NewStore(config.GetJournalDir())
+		if local, stats, err = dirsync.ScanLocal(ctx, localDir, opts.includeHidden, j); err != nil {
+			return fmt.Errorf("failed to scan local directory: %w", err)
+		}
+		if stats.Registered {
+			fmt.Printf("  %s\n", stats)
+		}
+	}
+	fmt.Printf("Scanning remote folder %s...\n", folderID)
+	remote, err := dirsync.ScanRemote(ctx, apiClient, folderID)
+	if err != nil {
+		return fmt.Errorf("failed to scan remote folder: %w", err)
+	}
+	if opts.compare.Checksum {
+		fmt.Println("Comparing checksums...")
+	}
+	plan, err := dirsync.Compare(local, remote, opts.compare)
+	if err != nil {
+		return err
+	}
+
+	printSyncPlan(os.Stdout, plan, len(local), len(remote), opts.dryRun)
+	if opts.dryRun || len(plan.Ops) == 0 {
+		return nil
+	}
+	if err := viewer.Check("sync files"); err != nil {
+		return err
+	}
+
+	var uploads, downloads, deletions []dirsync.Op
+	for _, op := range plan.Ops {
+		switch op.Action {
+		case dirsync.Upload:
+			uploads = append(uploads, op)
+		case dirsync.Download:
+			downloads = append(downloads, op)
+		default:
+			deletions = append(deletions, op)
+		}
+	}
+
+	if len(uploads) > 0 {
+		paths := make([]string, len(uploads))
+		for i, op := range uploads {
+			paths[i] = op.Local.Path
+		}
+		if ok, err := confirmLargeUpload(ctx, apiClient, paths, opts.yes); !ok || err != nil {
+			return err
+		}
+	}
+	if len(deletions) > 0 {
+		if ok, err := confirmSyncDeletions(deletions, opts.yes); !ok || err != nil {
+			return err
+		}
+	}
+
+	inthttp.WarmupProxyIfNeeded(ctx, apiClient.GetConfig())
+	credentials.GetManager(apiClient).WarmAll(ctx)
+
+	var errs []error
+	uploaded := 0
+	if len(uploads) > 0 {
+		var upErrs []error
+		uploaded, upErrs = syncUploads(ctx, uploads, folderID, opts.maxConcurrent, apiClient, logger)
+		errs = append(errs, upErrs...)
+	}
+	downloaded := 0
+	if len(downloads) > 0 {
+		var downErrs []error
+		downloaded, downErrs = syncDownloads(ctx, downloads, localDir, opts.maxConcurrent, apiClient, logger)
+		errs = append(errs, downErrs...)
+	}
+	deleted, delErrs := syncDeletions(ctx, deletions, apiClient)
+	errs = append(errs, delErrs...)
+
+	fmt.Printf("\n✓ Sync finished: %d uploaded, %d downloaded, %d deleted, %d unchanged\n",
+		uploaded, downloaded, deleted, plan.Unchanged)
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return fmt.Errorf("sync incomplete: %d file(s) failed (first error: %v)", len(errs), errs[0])
+	}
+}
+
+// confirmSyncDeletions lists how many files --delete is about to remove and
+// asks before going on. yes skips the question; without a terminal to ask on,
+// the sync is refused.
+func confirmSyncDeletions(ops []dirsync.Op, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	remote := 0
+	for _, op := range ops {
+		if op.Action == dirsync.DeleteRemote {
+			remote++
+		}
+	}
+	fmt.Println("⚠️  Deletions:")
+	if remote > 0 {
+		fmt.Printf("  %d remote file(s) will be moved to the Trash\n", remote)
+	}
+	if local := len(ops) - remote; local > 0 {
+		fmt.Printf("  %d local file(s) will be deleted\n", local)
+	}
+	if !IsTerminal() {
+		return false, fmt.Errorf("refusing to delete %d file(s) without confirmation: pass --yes", len(ops))
+	}
+	fmt.Print("Delete these files? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Println("Sync cancelled")
+		return false, nil
+	}
+	return true, nil
+}
+
+// syncDeletions removes local files and moves remote ones to the Trash. The
+// archive endpoint works per folder, so remote files are batched by the folder
+// holding them.
+func syncDeletions(ctx context.Context, ops []dirsync.Op, apiClient *api.Client) (int, []error) {
+	// Remote deletions are refused by the API client, local ones are not
+	if err := viewer.Check("delete files"); err != nil {
+		return 0, []error{err}
+	}
+	var errs []error
+	deleted := 0
+	var folders []string
+	byFolder := make(map[string][]dirsync.Op)
+	for _, op := range ops {
+		if op.Action == dirsync.DeleteRemote {
+			folderID := op.Remote.Task.FolderID
+			if _, ok := byFolder[folderID]; !ok {
+				folders = append(folders, folderID)
+			}
+			byFolder[folderID] = append(byFolder[folderID], op)
+			continue
+		}
+		if err := os.Remove(op.Local.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to delete %s: %v\n", op.RelPath, err)
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", op.RelPath, err))
+			continue
+		}
+		deleted++
+	}
+	for _, folderID := range folders {
+		batch := byFolder[folderID]
+		ids := make([]string, len(batch))
+		for i, op := range batch {
+			ids[i] = op.Remote.ID
+		}
+		if err := apiClient.ArchiveContents(ctx, folderID, ids, nil); err != nil {
+			for _, op := range batch {
+				fmt.Fprintf(os.Stderr, "✗ Failed to move %s to Trash: %v\n", op.RelPath, err)
+				errs = append(errs, fmt.Errorf("failed to move %s to Trash: %w", op.RelPath, err))
+			}
+			continue
+		}
+		deleted += len(batch)
+	}
+	return deleted, errs
+}
+
+// printSyncPlan summarizes plan; dry runs also list every op.
+func printSyncPlan(w io.Writer, plan *dirsync.Plan, localCount, remoteCount int, dryRun bool) {
+	fmt.Fprintf(w, "\nCompared %d local and %d remote file(s):\n", localCount, remoteCount)
+	up, upBytes := plan.Count(dirsync.Upload)
+	down, downBytes := plan.Count(dirsync.Download)
+	delLocal, _ := plan.Count(dirsync.DeleteLocal)
+	delRemote, _ := plan.Count(dirsync.DeleteRemote)
+	fmt.Fprintf(w, "  To upload:    %d (%s)\n", up, cloud.FormatBytes(upBytes))
+	fmt.Fprintf(w, "  To download:  %d (%s)\n", down, cloud.FormatBytes(downBytes))
+	if delLocal+delRemote > 0 {
+		fmt.Fprintf(w, "  To delete:    %d\n", delLocal+delRemote)
+	}
+	fmt.Fprintf(w, "  Unchanged:    %d\n", plan.Unchanged)
+
+	if len(plan.Ops) == 0 {
+		fmt.Fprintln(w, "\n✓ Already in sync")
+		return
+	}
+	if !dryRun {
+		fmt.Fprintln(w)
+		return
+	}
+	fmt.Fprintln(w, "\nDry run - planned changes:")
+	for _, op := range plan.Ops {
+		switch op.Action {
+		case dirsync.Upload:
+			fmt.Fprintf(w, "  ↑ %s (%s, %s)\n", op.RelPath, op.Reason, cloud.FormatBytes(op.Size()))
+		case dirsync.Download:
+			fmt.Fprintf(w, "  ↓ %s (%s, %s)\n", op.RelPath, op.Reason, cloud.FormatBytes(op.Size()))
+		case dirsync.DeleteLocal:
+			fmt.Fprintf(w, "  ✗ %s (delete local file)\n", op.RelPath)
+		case dirsync.DeleteRemote:
+			fmt.Fprintf(w, "  ✗ %s (delete remote file)\n", op.RelPath)
+		}
+	}
+}
+
+// syncUploads uploads ops into the folders matching their relative
+// directories under folderID, creating missing folders first. A file that
+// replaces remote copies deletes them once it has uploaded.
+func syncUploads(ctx context.Context, ops []dirsync.Op, folderID string, maxConcurrent int, apiClient *api.Client, logger *logging.Logger) (int, []error) {
+	// Folders are resolved one at a time so siblings share the cached listings
+	dirs := make(map[string]string)
+	for _, op := range ops {
+		dirs[path.Dir(op.RelPath)] = ""
+	}
+	names := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		names = append(names, dir)
+	}
+	sort.Strings(names)
+	cache := NewFolderCache()
+	for _, dir := range names {
+		if dir == "." {
+			dirs[dir] = folderID
+			continue
+		}
+		id, err := folder.EnsureFolderPath(ctx, apiClient, cache, folderID, dir)
+		if err != nil {
+			return 0, []error{fmt.Errorf("failed to create remote folder %s: %w", dir, err)}
+		}
+		dirs[dir] = id
+	}
+
+	uploadUI := progress.NewUploadUI(len(ops))
+	defer uploadUI.Wait()
+
+	resourceMgr := CreateResourceManager()
+	transferMgr := transfer.NewManager(resourceMgr)
+	items := make([]cliUploadItem, len(ops))
+	for i, op := range ops {
+		items[i] = cliUploadItem{idx: i, path: op.Local.Path, size: op.Local.Size}
+	}
+	cfg := transfer.BatchConfig{
+		MaxWorkers:  maxConcurrent,
+		ResourceMgr: resourceMgr,
+		Label:       "FILE-SYNC-UPLOAD",
+	}
+	numWorkers := transfer.ComputedWorkers(items, cfg)
+
+	var uploaded int
+	var mu sync.Mutex
+	result := transfer.RunBatch(ctx, items, cfg, func(ctx context.Context, item cliUploadItem) error {
+		op := ops[item.idx]
+		targetID := dirs[path.Dir(op.RelPath)]
+
+		transferHandle := transferMgr.AllocateTransfer(item.size, numWorkers)
+		defer transferHandle.Complete()
+
+		var fileBar *progress.FileBar
+		var barOnce sync.Once
+		cloudFile, err := upload.UploadFile(ctx, upload.UploadParams{
+			LocalPath: item.path,
+			FolderID:  targetID,
+			APIClient: apiClient,
+			ProgressCallback: func(fraction float64) {
+				barOnce.Do(func() {
+					fileBar = uploadUI.AddFileBar(item.path, targetID, item.size)
+				})
+				if fileBar != nil {
+					fileBar.UpdateProgress(fraction)
+				}
+			},
+			TransferHandle: transferHandle,
+			OutputWriter:   uploadUI.Writer(),
+			ResumeDir:      config.GetTransferStateDir(),
+		})
+		if fileBar == nil {
+			fileBar = uploadUI.AddFileBar(item.path, targetID, item.size)
+		}
+		if err != nil {
+			fileBar.Complete("", err)
+			return fmt.Errorf("failed to upload %s: %w", op.RelPath, err)
+		}
+
+		// The older remote copies go only once their replacement is in
+		if op.Remote != nil {
+			for _, oldID := range append([]string{op.Remote.ID}, op.Remote.OlderIDs...) {
+				if err := apiClient.DeleteFile(ctx, oldID); err != nil {
+					logger.Warn().Err(err).Str("file", op.RelPath).Str("replacedFileID", oldID).
+						Msg("Failed to delete the file being replaced (non-fatal)")
+					fmt.Fprintf(uploadUI.Writer(), "⚠️  Uploaded %s but could not delete the previous copy %s: %v\n", op.RelPath, oldID, err)
+				}
+			}
+		}
+		fileBar.Complete(cloudFile.ID, nil)
+
+		mu.Lock()
+		uploaded++
+		mu.Unlock()
+		return nil
+	})
+	return uploaded, result.Errors
+}
+
+// syncDownloads downloads ops below localDir. Each file is written under a
+// temporary name, stamped with its remote upload time and then moved over
+// the local copy, so a failed download leaves the old file in place.
+func syncDownloads(ctx context.Context, ops []dirsync.Op, localDir string, maxConcurrent int, apiClient *api.Client, logger *logging.Logger) (int, []error) {
+	downloadUI := progress.NewDownloadUI(len(ops))
+	defer downloadUI.Wait()
+
+	resourceMgr := CreateResourceManager()
+	transferMgr := transfer.NewManager(resourceMgr)
+	items := make([]cliDownloadItem, len(ops))
+	for i, op := range ops {
+		items[i] = cliDownloadItem{
+			idx:       i,
+			fileID:    op.Remote.ID,
+			name:      path.Base(op.RelPath),
+			size:      op.Remote.Size,
+			localPath: filepath.Join(localDir, filepath.FromSlash(op.RelPath)),
+			cloudFile: op.Remote.Task.CloudFile,
+		}
+	}
+	cfg := transfer.BatchConfig{
+		MaxWorkers:  maxConcurrent,
+		ResourceMgr: resourceMgr,
+		Label:       "FILE-SYNC-DOWNLOAD",
+	}
+	numWorkers := transfer.ComputedWorkers(items, cfg)
+
+	var downloaded int
+	var mu sync.Mutex
+	result := transfer.RunBatch(ctx, items, cfg, func(ctx context.Context, item cliDownloadItem) error {
+		op := ops[item.idx]
+		if !filepath.IsLocal(filepath.FromSlash(op.RelPath)) {
+			return fmt.Errorf("refusing to download %s outside %s", op.RelPath, localDir)
+		}
+		if err := os.MkdirAll(filepath.Dir(item.localPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", op.RelPath, err)
+		}
+		tmpPath := item.localPath + dirsync.TempSuffix
+		os.Remove(tmpPath)
+
+		transferHandle := transferMgr.AllocateTransfer(item.size, numWorkers)
+		var fileBar *progress.DownloadFileBar
+		var barOnce sync.Once
+		err := downloadFileFn(ctx, download.DownloadParams{
+			FileID:    item.fileID,
+			FileInfo:  item.cloudFile,
+			LocalPath: tmpPath,
+			APIClient: apiClient,
+			ProgressCallback: func(fraction float64) {
+				barOnce.Do(func() {
+					fileBar = downloadUI.AddFileBar(item.idx+1, item.fileID, item.name, item.localPath, item.size)
+				})
+				if fileBar != nil {
+					fileBar.UpdateProgress(fraction)
+				}
+			},
+			TransferHandle: transferHandle,
+		})
+		if err == nil && !op.Remote.Uploaded.IsZero() {
+			err = os.Chtimes(tmpPath, op.Remote.Uploaded, op.Remote.Uploaded)
+		}
+		if err == nil {
+			err = os.Rename(tmpPath, item.localPath)
+		}
+		if fileBar == nil {
+			fileBar = downloadUI.AddFileBar(item.idx+1, item.fileID, item.name, item.localPath, item.size)
+		}
+		fileBar.Complete(err)
+		if err != nil {
+			os.Remove(tmpPath)
+			logger.Debug().Str("error", sanitizeErrorString(err.Error())).Str("file_id", item.fileID).Msg("sync download failed")
+			return fmt.Errorf("failed to download %s: %w", op.RelPath, err)
+		}
+
+		mu.Lock()
+		downloaded++
+		mu.Unlock()
+		return nil
+	})
+	return downloaded, result.Errors
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/transfer/dirsync"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+func TestSyncDeletions_RefusedInViewerMode(t *testing.T) {
+	t.Setenv(viewer.EnvVar, "")
+	viewer.SetEnabled(true)
+	defer viewer.SetEnabled(false)
+
+	path := filepath.Join(t.TempDir(), "result.dat")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ops := []dirsync.Op{{
+		Action:  dirsync.DeleteLocal,
+		RelPath: "result.dat",
+		Local:   &dirsync.LocalFile{RelPath: "result.dat", Path: path},
+	}}
+
+	deleted, errs := syncDeletions(context.Background(), ops, nil)
+	if deleted != 0 || len(errs) != 1 || !errors.Is(errs[0], viewer.ErrReadOnly) {
+		t.Errorf("syncDeletions() = %d, %v; want 0 and ErrReadOnly", deleted, errs)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("local file removed in viewer mode: %v", err)
+	}
+}
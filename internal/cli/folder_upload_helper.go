@@ -9,13 +9,14 @@ import (
 
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/cloud/credentials"
-	"github.com/rescale/rescale-int/internal/cloud/state"
+	"github.com/rescale/rescale-int/internal/cloud/manifest"
 	"github.com/rescale/rescale-int/internal/cloud/upload"
 	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/diskspace"
 	"github.com/rescale/rescale-int/internal/localfs"
 	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/models"
 	"github.com/rescale/rescale-int/internal/progress"
 	"github.com/rescale/rescale-int/internal/resources"
 	"github.com/rescale/rescale-int/internal/transfer"
@@ -47,6 +48,7 @@ type UploadResult struct {
 	Errors          []UploadError
 	SymlinksSkipped []string
 	UploadedFileIDs []string
+	Manifest        []manifest.File // Files now on the platform, for the upload manifest
 }
 
 // UploadError tracks failed uploads
@@ -55,6 +57,26 @@ type UploadError struct {
 	Error    error
 }
 
+// uploadedManifestFile returns the upload manifest entry of the file at
+// fpath, uploaded as file.
+func uploadedManifestFile(root, fpath string, info os.FileInfo, file *models.CloudFile) manifest.File {
+	f := manifest.NewFile(root, fpath, info, file.ID)
+	f.SetChecksums(file.FileChecksums)
+	return f
+}
+
+// existingManifestFile returns the upload manifest entry of the file at
+// fpath, left in place because the remote folder already has it as fileID.
+func existingManifestFile(root, fpath, fileID string) (manifest.File, bool) {
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return manifest.File{}, false
+	}
+	f := manifest.NewFile(root, fpath, info, fileID)
+	f.Existing = true
+	return f, true
+}
+
 // FolderReadyEvent, FolderCache, NewFolderCache, BuildDirectoryTree, CheckFolderExists,
 // CreateFolderStructure, CreateFolderStructureStreaming, and related helpers live in
 // internal/transfer/folder/. Aliases in folder_upload_compat.go preserve the cli.* API surface.
@@ -190,8 +212,12 @@ func uploadDirectoryPipelined(
 					switch action {
 					case FileSkipOnce, FileSkipAll:
 						logger.Debug().Str("file", fileName).Msg("Ignoring existing file")
+						mf, ok := existingManifestFile(rootPath, fpath, existingFileID)
 						resultMutex.Lock()
 						result.FilesIgnored++
+						if ok {
+							result.Manifest = append(result.Manifest, mf)
+						}
 						resultMutex.Unlock()
 						return nil
 					case FileOverwriteOnce, FileOverwriteAll:
@@ -232,12 +258,13 @@ func uploadDirectoryPipelined(
 					},
 					OutputWriter:   uploadUI.Writer(),
 					TransferHandle: transferHandle,
+					ResumeDir:      config.GetTransferStateDir(),
 				})
 				transferHandle.Complete()
 
 				if uploadErr != nil {
 					fileBar.Complete("", uploadErr)
-					if state.UploadResumeStateExists(fpath) {
+					if uploadResumeStateExists(fpath) {
 						fmt.Fprintf(os.Stderr, "\n💡 Resume state saved for %s. To resume, re-run the upload command.\n", filepath.Base(fpath))
 					}
 					resultMutex.Lock()
@@ -253,6 +280,7 @@ func uploadDirectoryPipelined(
 				result.FilesUploaded++
 				result.TotalBytes += fileInfo.Size()
 				result.UploadedFileIDs = append(result.UploadedFileIDs, cloudFile.ID)
+				result.Manifest = append(result.Manifest, uploadedManifestFile(rootPath, fpath, fileInfo, cloudFile))
 				resultMutex.Unlock()
 				return nil
 			})
@@ -452,8 +480,12 @@ func uploadFiles(
 			case FileSkipOnce, FileSkipAll:
 				logger.Debug().Str("file", fileName).Msg("Ignoring existing file")
 				fmt.Fprintf(uploadUI.Writer(), "  ⏭  Ignoring existing file: %s\n", fileName)
+				mf, ok := existingManifestFile(rootPath, fpath, existingFileID)
 				resultMutex.Lock()
 				result.FilesIgnored++
+				if ok {
+					result.Manifest = append(result.Manifest, mf)
+				}
 				resultMutex.Unlock()
 				return nil
 			case FileOverwriteOnce, FileOverwriteAll:
@@ -509,6 +541,7 @@ func uploadFiles(
 			},
 			OutputWriter:   uploadUI.Writer(),
 			TransferHandle: seqHandle,
+			ResumeDir:      config.GetTransferStateDir(),
 		})
 		seqHandle.Complete()
 
@@ -590,8 +623,12 @@ func uploadFiles(
 				case FileSkipOnce, FileSkipAll:
 					logger.Debug().Str("file", fileName).Msg("Ignoring existing file")
 					fmt.Fprintf(uploadUI.Writer(), "  ⏭  Ignoring existing file: %s\n", fileName)
+					mf, ok := existingManifestFile(rootPath, fpath, existingFileID)
 					resultMutex.Lock()
 					result.FilesIgnored++
+					if ok {
+						result.Manifest = append(result.Manifest, mf)
+					}
 					resultMutex.Unlock()
 					return nil
 
@@ -614,6 +651,7 @@ func uploadFiles(
 							fileBar.UpdateProgress(prog)
 						},
 						OutputWriter: uploadUI.Writer(),
+						ResumeDir:    config.GetTransferStateDir(),
 					})
 
 					if retryErr != nil {
@@ -629,6 +667,7 @@ func uploadFiles(
 					resultMutex.Lock()
 					result.FilesUploaded++
 					result.TotalBytes += cloudFile.DecryptedSize
+					result.Manifest = append(result.Manifest, uploadedManifestFile(rootPath, fpath, fileInfo, cloudFile))
 					resultMutex.Unlock()
 					logger.Info().Str("file", fileName).Str("file_id", cloudFile.ID).Msg("Upload successful (after overwrite)")
 					return nil
@@ -674,6 +713,7 @@ func uploadFiles(
 		result.TotalBytes += fileInfo.Size()
 		if fileID != "" {
 			result.UploadedFileIDs = append(result.UploadedFileIDs, fileID)
+			result.Manifest = append(result.Manifest, uploadedManifestFile(rootPath, fpath, fileInfo, cloudFile))
 		}
 		resultMutex.Unlock()
 		return nil
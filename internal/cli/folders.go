@@ -2,6 +2,8 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,11 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/manifest"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/diskspace"
 	"github.com/rescale/rescale-int/internal/pathutil"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/progress"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
 	"github.com/rescale/rescale-int/internal/util/tags"
 )
 
@@ -190,6 +195,8 @@ func newFoldersUploadDirCmd() *cobra.Command {
 	var mergeFolderConflicts bool
 	var checkConflicts bool
 	var tagsFlag string
+	var yes bool
+	var manifestMode string
 
 	cmd := &cobra.Command{
 		Use:   "upload-dir <directory>",
@@ -202,6 +209,15 @@ Folder conflict handling modes (mutually exclusive):
 
 If no conflict flag is provided, you will be prompted interactively.
 
+Directories larger than the large_upload_confirm_gb setting (default 500 GB)
+show their size, estimated duration and remaining workspace storage and ask
+before uploading; --yes skips the question.
+
+After the upload, a manifest of the files now on Rescale (file IDs, sizes,
+checksums) is written to .rescale-manifest.json in the directory, or to the
+central store under the config directory with --manifest central. Check the
+directory against it later with 'rescale-int files verify-manifest'.
+
 Examples:
   # Upload directory to root (My Library) - will prompt for conflicts
   rescale-int folders upload-dir ./my_project
@@ -242,6 +258,12 @@ Examples:
 				maxConcurrent = constants.MaxMaxConcurrent
 			}
 
+			switch manifestMode {
+			case "folder", "central", "none":
+			default:
+				return fmt.Errorf("--manifest must be folder, central or none, got %q", manifestMode)
+			}
+
 			// Validate folder-concurrency
 			if folderConcurrency < 1 || folderConcurrency > 30 {
 				return fmt.Errorf("--folder-concurrency must be between 1 and 30, got %d", folderConcurrency)
@@ -314,6 +336,10 @@ Examples:
 				return fmt.Errorf("failed to resolve directory path: %w", err)
 			}
 
+			if ok, err := confirmLargeUpload(ctx, apiClient, []string{resolvedLocalPath}, yes); !ok || err != nil {
+				return err
+			}
+
 			// Initialize folder cache for API call optimization
 			cache := NewFolderCache()
 
@@ -497,6 +523,15 @@ Examples:
 				}
 			}
 
+			if manifestMode != "none" && len(result.Manifest) > 0 {
+				writeUploadManifest(manifestMode, manifest.Manifest{
+					Platform:       cfg.APIBaseURL,
+					LocalRoot:      resolvedLocalPath,
+					RemoteFolderID: rootFolderID,
+					IncludeHidden:  includeHidden,
+				}, result.Manifest)
+			}
+
 			// Save symlinks list to result
 			result.SymlinksSkipped = symlinks
 			result.FoldersCreated = foldersCreated
@@ -590,16 +625,44 @@ Examples:
 	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "DEPRECATED: Use --merge-folder-conflicts instead")
 	cmd.Flags().BoolVar(&checkConflicts, "check-conflicts", false, "Check for existing files before upload (slower but shows conflicts upfront)")
 	cmd.Flags().StringVar(&tagsFlag, "tags", "", "Comma-separated tags to apply after each file upload (e.g., \"simulation,cfd\")")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Start large uploads without asking for confirmation")
+	cmd.Flags().StringVar(&manifestMode, "manifest", "folder", "Where to write the upload manifest: folder (in the directory), central (config directory) or none")
 	cmd.Flags().MarkHidden("skip-existing") // Hide deprecated flag
 
 	return cmd
 }
 
+// writeUploadManifest records files in the upload manifest of m.LocalRoot,
+// in the directory itself (mode "folder") or the central store. A directory
+// that can't be written to gets a central manifest instead. Failures are
+// reported, not returned: the upload itself succeeded.
+func writeUploadManifest(mode string, m manifest.Manifest, files []manifest.File) {
+	central := manifest.CentralPath(config.GetManifestDir(), m.LocalRoot)
+	path := central
+	if mode == "folder" {
+		path = manifest.InFolderPath(m.LocalRoot)
+	}
+	err := manifest.Record(path, m, files)
+	if err != nil && path != central {
+		fmt.Printf("⚠️  Could not write the manifest into the directory (%v); using the central store\n", err)
+		path = central
+		err = manifest.Record(path, m, files)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write upload manifest: %v\n", err)
+		return
+	}
+	fmt.Printf("📝 Upload manifest: %s (verify with 'rescale-int files verify-manifest %s')\n", path, m.LocalRoot)
+}
+
 // newFoldersDeleteCmd creates the 'folders delete' command.
 func newFoldersDeleteCmd() *cobra.Command {
 	var folderID string
 	var confirm bool
 	var permanent bool
+	var workers int
+	var rate float64
+	var reportOut string
 
 	cmd := &cobra.Command{
 		Use:   "delete",
@@ -609,18 +672,34 @@ func newFoldersDeleteCmd() *cobra.Command {
 By default the folder is moved to Trash (recoverable). Use --permanent to
 delete it immediately and irreversibly.
 
+A permanent delete lists the folder tree first, then deletes its files and
+subfolders one request at a time (--workers in parallel, at most --rate per
+second), showing how many of the items are deleted so far. Press Ctrl+C to
+stop: items already deleted stay deleted, and the folder keeps the rest.
+Items that could not be deleted are listed at the end (and written to
+--report-out as JSON); their folders are kept.
+
 Example:
   # Move a folder to Trash (recoverable)
   rescale-int folders delete --folder-id XxYyZz
 
   # Permanently delete (cannot be undone)
-  rescale-int folders delete --folder-id XxYyZz --permanent`,
+  rescale-int folders delete --folder-id XxYyZz --permanent
+
+  # Permanently delete a large folder gently, keeping a failure report
+  rescale-int folders delete --folder-id XxYyZz --permanent --workers 2 --rate 1 --report-out delete-report.json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
 			if folderID == "" {
 				return fmt.Errorf("--folder-id is required")
 			}
+			if workers < 1 || workers > folder.MaxDeleteWorkers {
+				return fmt.Errorf("--workers must be between 1 and %d", folder.MaxDeleteWorkers)
+			}
+			if rate < 0 {
+				return fmt.Errorf("--rate cannot be negative")
+			}
 
 			// Confirmation prompt
 			if !confirm {
@@ -647,11 +726,7 @@ Example:
 
 			if permanent {
 				logger.Info().Str("folder_id", folderID).Msg("Permanently deleting folder")
-				if err := apiClient.DeleteFolder(ctx, folderID); err != nil {
-					return fmt.Errorf("failed to delete folder: %w", err)
-				}
-				fmt.Printf("✓ Folder permanently deleted\n")
-				return nil
+				return deleteFolderPermanently(ctx, apiClient, folderID, folder.DeleteOptions{Workers: workers, RatePerSecond: rate}, reportOut)
 			}
 
 			// Move to Trash: resolve the folder's parent (archive is folder-scoped).
@@ -672,12 +747,69 @@ Example:
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Folder ID to delete (required)")
 	cmd.Flags().BoolVar(&confirm, "confirm", false, "Skip confirmation prompt")
 	cmd.Flags().BoolVar(&permanent, "permanent", false, "Permanently delete instead of moving to Trash (irreversible)")
+	cmd.Flags().IntVar(&workers, "workers", folder.DefaultDeleteWorkers, "Parallel delete requests with --permanent")
+	cmd.Flags().Float64Var(&rate, "rate", 0, "Maximum deletes per second with --permanent (0 = only the API rate limits)")
+	cmd.Flags().StringVar(&reportOut, "report-out", "", "Write the result of a --permanent delete, with any failures, to this JSON file")
 
 	cmd.MarkFlagRequired("folder-id")
 
 	return cmd
 }
 
+// maxListedDeleteFailures caps how many failures a permanent delete prints.
+const maxListedDeleteFailures = 20
+
+// deleteFolderPermanently deletes a folder tree item by item with a progress
+// line, then reports what could not be deleted.
+func deleteFolderPermanently(ctx context.Context, apiClient *api.Client, folderID string, opts folder.DeleteOptions, reportOut string) error {
+	var lastPrint time.Time
+	opts.OnProgress = func(p folder.DeleteProgress) {
+		if time.Since(lastPrint) < 200*time.Millisecond {
+			return
+		}
+		lastPrint = time.Now()
+		if p.Listing {
+			fmt.Fprintf(os.Stderr, "\r  Listing: %d items found...", p.Total)
+		} else {
+			fmt.Fprintf(os.Stderr, "\r  Deleted %d of %d (%d failed)...", p.Deleted, p.Total, p.Failed)
+		}
+	}
+
+	result, err := folder.DeleteRecursive(ctx, apiClient, folderID, folderID, opts)
+	fmt.Fprintf(os.Stderr, "\r%80s\r", "") // Clear the progress line
+	if result == nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	if reportOut != "" {
+		if data, jerr := json.MarshalIndent(result, "", "  "); jerr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to encode delete report: %v\n", jerr)
+		} else if werr := os.WriteFile(reportOut, data, 0644); werr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write delete report: %v\n", werr)
+		} else {
+			fmt.Printf("Report written to %s\n", reportOut)
+		}
+	}
+
+	for i, f := range result.Failures {
+		if i == maxListedDeleteFailures {
+			fmt.Printf("  ... and %d more\n", len(result.Failures)-i)
+			break
+		}
+		fmt.Printf("  ✗ %s: %s\n", f.Path, f.Error)
+	}
+
+	switch {
+	case result.Cancelled:
+		fmt.Printf("Cancelled: %d of %d items deleted; the folder keeps the rest\n", result.Deleted, result.Total)
+		return err
+	case len(result.Failures) > 0:
+		return fmt.Errorf("%d of %d items deleted, %d could not be deleted", result.Deleted, result.Total, len(result.Failures))
+	}
+	fmt.Printf("✓ Folder permanently deleted (%d items)\n", result.Deleted)
+	return nil
+}
+
 // newFoldersDownloadDirCmd creates the 'folders download-dir' command.
 func newFoldersDownloadDirCmd() *cobra.Command {
 	var folderID string
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// guiPackages are import path fragments that pull in WebKit/GTK or other
+// desktop libraries, which cluster nodes running the CLI don't have.
+var guiPackages = []string{"wailsapp", "github.com/wailsapp/", "fyne.io/", "systray"}
+
+// TestCLIBuildsHaveNoGUIDependencies checks that the CLI binary, and the
+// unified binary built with -tags nogui, compile without any GUI package.
+func TestCLIBuildsHaveNoGUIDependencies(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not in PATH")
+	}
+
+	for _, build := range []struct {
+		name string
+		args []string
+	}{
+		{"cmd/rescale-int", []string{"list", "-deps", "github.com/rescale/rescale-int/cmd/rescale-int"}},
+		{"unified -tags nogui", []string{"list", "-deps", "-tags", "nogui", "github.com/rescale/rescale-int"}},
+	} {
+		out, err := exec.Command(goTool, build.args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: go %s: %v\n%s", build.name, strings.Join(build.args, " "), err, out)
+		}
+		for _, pkg := range strings.Fields(string(out)) {
+			for _, gui := range guiPackages {
+				if strings.Contains(pkg, gui) {
+					t.Errorf("%s depends on GUI package %s", build.name, pkg)
+				}
+			}
+		}
+	}
+}
@@ -4,11 +4,12 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/hardware"
 	"github.com/rescale/rescale-int/internal/models"
 )
 
@@ -22,16 +23,33 @@ func newHardwareCmd() *cobra.Command {
 
 	// Add hardware subcommands
 	hardwareCmd.AddCommand(newHardwareListCmd())
+	hardwareCmd.AddCommand(newHardwareStarCmd(true))
+	hardwareCmd.AddCommand(newHardwareStarCmd(false))
 
 	return hardwareCmd
 }
 
+// hardwareListEntry is a core type in `hardware list --json`, with the
+// classes the filters use.
+type hardwareListEntry struct {
+	models.CoreType
+	MemoryPerCoreGB   float64 `json:"memoryPerCoreGb,omitempty"`
+	Arch              string  `json:"arch,omitempty"`
+	InterconnectClass string  `json:"interconnectClass,omitempty"`
+	PriceBand         string  `json:"priceBand,omitempty"`
+	Favorite          bool    `json:"favorite,omitempty"`
+	Compatible        *bool   `json:"compatible,omitempty"` // With --analysis only
+}
+
 // newHardwareListCmd creates the 'hardware list' command.
 func newHardwareListCmd() *cobra.Command {
 	var (
-		search     string
-		outputJSON bool
-		showAll    bool
+		filter          hardware.Filter
+		outputJSON      bool
+		showAll         bool
+		favoritesOnly   bool
+		analysisCode    string
+		analysisVersion string
 	)
 
 	cmd := &cobra.Command{
@@ -42,6 +60,11 @@ func newHardwareListCmd() *cobra.Command {
 By default, only active hardware types are shown. Use --all to include
 inactive/deprecated types.
 
+Hardware can be filtered by memory per core, interconnect, architecture and
+price band (where the platform reports prices). Starred favorites (see
+'hardware star') are listed first and marked with *. With --analysis, the
+hardware the analysis version runs on is listed before the rest.
+
 Examples:
   # List active hardware types (default)
   rescale-int hardware list
@@ -52,9 +75,26 @@ Examples:
   # Search for specific hardware
   rescale-int hardware list --search emerald
 
+  # InfiniBand hardware with at least 8 GB per core
+  rescale-int hardware list --interconnect infiniband --min-memory 8
+
+  # Hardware for an analysis version, compatible types first
+  rescale-int hardware list --analysis openfoam --analysis-version 2306
+
   # Get JSON output
   rescale-int hardware list --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if analysisVersion != "" && analysisCode == "" {
+				return fmt.Errorf("--analysis-version requires --analysis")
+			}
+			if err := filter.Validate(); err != nil {
+				return err
+			}
+			favorites, err := hardware.LoadFavorites(config.GetHardwareFavoritesPath())
+			if err != nil {
+				return err
+			}
+
 			ctx := GetContext()
 
 			// Get API client
@@ -64,33 +104,54 @@ Examples:
 			}
 
 			// Fetch core types (by default active only, --all includes inactive)
+			ctx, cancel := apiClient.WithTimeout(ctx, config.APICatalog)
+			defer cancel()
 			coreTypes, err := apiClient.GetCoreTypes(ctx, showAll)
 			if err != nil {
 				return fmt.Errorf("failed to get core types: %w", err)
 			}
 
-			// Filter by search term if provided
-			if search != "" {
-				filtered := make([]models.CoreType, 0)
-				searchLower := strings.ToLower(search)
+			var allowed []string
+			if analysisCode != "" {
+				analyses, err := apiClient.GetAnalyses(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to get analyses: %w", err)
+				}
+				if allowed, err = allowedCoreTypes(analyses, analysisCode, analysisVersion); err != nil {
+					return err
+				}
+			}
+
+			coreTypes = filter.Apply(coreTypes)
+			if favoritesOnly {
+				starred := coreTypes[:0]
 				for _, ct := range coreTypes {
-					if strings.Contains(strings.ToLower(ct.Code), searchLower) ||
-						strings.Contains(strings.ToLower(ct.Name), searchLower) {
-						filtered = append(filtered, ct)
+					if favorites.Has(ct.Code) {
+						starred = append(starred, ct)
 					}
 				}
-				coreTypes = filtered
+				coreTypes = starred
 			}
-
-			// Sort by display order
-			sort.Slice(coreTypes, func(i, j int) bool {
-				return coreTypes[i].DisplayOrder < coreTypes[j].DisplayOrder
-			})
+			hardware.Sort(coreTypes, allowed, favorites)
 
 			// Output
 			if outputJSON {
-				// JSON output
-				data, err := json.MarshalIndent(coreTypes, "", "  ")
+				entries := make([]hardwareListEntry, len(coreTypes))
+				for i, ct := range coreTypes {
+					entries[i] = hardwareListEntry{
+						CoreType:          ct,
+						MemoryPerCoreGB:   hardware.MemoryPerCoreGB(ct),
+						Arch:              hardware.Architecture(ct),
+						InterconnectClass: hardware.Interconnect(ct),
+						PriceBand:         hardware.PriceBand(ct),
+						Favorite:          favorites.Has(ct.Code),
+					}
+					if analysisCode != "" {
+						ok := hardware.Compatible(ct.Code, allowed)
+						entries[i].Compatible = &ok
+					}
+				}
+				data, err := json.MarshalIndent(entries, "", "  ")
 				if err != nil {
 					return fmt.Errorf("failed to marshal JSON: %w", err)
 				}
@@ -104,17 +165,24 @@ Examples:
 
 				fmt.Printf("Found %d hardware type(s):\n\n", len(coreTypes))
 
-				// Find max width for alignment
-				maxCodeWidth := 0
+				// Find max widths for alignment
+				maxCodeWidth, maxNameWidth := 0, 0
 				for _, ct := range coreTypes {
-					if len(ct.Code) > maxCodeWidth {
-						maxCodeWidth = len(ct.Code)
-					}
+					maxCodeWidth = max(maxCodeWidth, len(ct.Code))
+					maxNameWidth = max(maxNameWidth, len(ct.Name))
 				}
 
 				// Print table
 				for _, ct := range coreTypes {
-					fmt.Printf("  %-*s  %s\n", maxCodeWidth, ct.Code, ct.Name)
+					star := " "
+					if favorites.Has(ct.Code) {
+						star = "*"
+					}
+					line := fmt.Sprintf("%s %-*s  %-*s  %s", star, maxCodeWidth, ct.Code, maxNameWidth, ct.Name, hardwareDetails(ct))
+					if !hardware.Compatible(ct.Code, allowed) {
+						line += "  (not available for this analysis version)"
+					}
+					fmt.Println(strings.TrimRight(line, " "))
 				}
 			}
 
@@ -122,9 +190,91 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&search, "search", "s", "", "Search for hardware by code or name")
+	cmd.Flags().StringVarP(&filter.Search, "search", "s", "", "Search for hardware by code or name")
 	cmd.Flags().BoolVarP(&outputJSON, "json", "J", false, "Output as JSON")
 	cmd.Flags().BoolVarP(&showAll, "all", "a", false, "Include inactive/deprecated hardware types")
+	cmd.Flags().Float64Var(&filter.MinMemoryPerCoreGB, "min-memory", 0, "Only hardware with at least this many GB of memory per core")
+	cmd.Flags().StringVar(&filter.Interconnect, "interconnect", "", "Only hardware with this interconnect: "+strings.Join(hardware.Interconnects, ", "))
+	cmd.Flags().StringVar(&filter.Architecture, "arch", "", "Only hardware with this architecture: "+strings.Join(hardware.Architectures, ", "))
+	cmd.Flags().StringVar(&filter.PriceBand, "price-band", "", "Only hardware in this price band: "+strings.Join(hardware.PriceBands, ", "))
+	cmd.Flags().BoolVar(&favoritesOnly, "favorites", false, "Only starred hardware")
+	cmd.Flags().StringVar(&analysisCode, "analysis", "", "List hardware the analysis runs on first")
+	cmd.Flags().StringVar(&analysisVersion, "analysis-version", "", "Analysis version (default: the hardware any version runs on)")
 
 	return cmd
 }
+
+// hardwareDetails summarizes the reported details of ct, e.g.
+// "8 GB/core  x86_64  infiniband  $0.120/core-h".
+func hardwareDetails(ct models.CoreType) string {
+	var parts []string
+	if gb := hardware.MemoryPerCoreGB(ct); gb > 0 {
+		parts = append(parts, fmt.Sprintf("%.3g GB/core", gb))
+	}
+	for _, s := range []string{hardware.Architecture(ct), hardware.Interconnect(ct)} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if ct.Price > 0 {
+		parts = append(parts, fmt.Sprintf("$%.3f/core-h", ct.Price))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// allowedCoreTypes returns the core types the analysis version runs on
+// (empty = any). Without a version, it is those any version runs on.
+func allowedCoreTypes(analyses []models.Analysis, code, version string) ([]string, error) {
+	for _, a := range analyses {
+		if !strings.EqualFold(a.Code, code) {
+			continue
+		}
+		var allowed []string
+		for _, v := range a.Versions {
+			if version == "" {
+				if len(v.AllowedCoreTypes) == 0 {
+					return nil, nil
+				}
+				allowed = append(allowed, v.AllowedCoreTypes...)
+			} else if v.Version == version || v.VersionCode == version {
+				return v.AllowedCoreTypes, nil
+			}
+		}
+		if version != "" {
+			return nil, fmt.Errorf("analysis %s has no version %q", a.Code, version)
+		}
+		return allowed, nil
+	}
+	return nil, fmt.Errorf("unknown analysis code %q", code)
+}
+
+// newHardwareStarCmd creates the 'hardware star' command, or 'hardware
+// unstar' when star is false.
+func newHardwareStarCmd(star bool) *cobra.Command {
+	use, short, verb := "star", "Star hardware types as favorites", "Starred"
+	if !star {
+		use, short, verb = "unstar", "Remove hardware types from favorites", "Unstarred"
+	}
+	return &cobra.Command{
+		Use:   use + " <code>...",
+		Short: short,
+		Long: short + `.
+
+Favorites are listed first by 'hardware list' and in the GUI core type
+picker. They are kept per user, in hardware-favorites.json in the
+configuration directory.
+
+Example:
+  rescale-int hardware ` + use + ` emerald carbon`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, code := range args {
+				if _, err := hardware.SetFavorite(config.GetHardwareFavoritesPath(), code, star); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("%s %s\n", verb, strings.Join(args, ", "))
+			return nil
+		},
+	}
+}
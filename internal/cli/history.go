@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/cloud/history"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/crypto"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// newHistoryCmd creates the 'history' command group.
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Upload history, integrity audits and network usage",
+		Long: `Every file uploaded by rescale-int is recorded in a local upload history
+(one per platform, under the config directory) with its size and SHA-512.
+'history verify' re-checks past uploads against the platform.
+
+Network data sent and received is logged too, per day and per PUR run;
+'history usage' shows the totals.`,
+	}
+
+	cmd.AddCommand(newHistoryVerifyCmd())
+	cmd.AddCommand(newHistoryUsageCmd())
+
+	return cmd
+}
+
+func newHistoryVerifyCmd() *cobra.Command {
+	var (
+		since       string
+		sample      int
+		all         bool
+		rehash      bool
+		rehashLimit int64
+		reportPath  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Re-check past uploads against the platform",
+		Long: `Re-check uploads recorded in the upload history and produce an audit
+report.
+
+For each upload the file's registered size and SHA-512 on the platform are
+compared with what was uploaded, and a few blocks of the stored (encrypted)
+object, hashed when it was uploaded, are read back and compared: a cheap
+check that the stored content is intact. Uploads recorded before block
+sampling are checked by metadata only and counted as such in the report.
+
+With --rehash the file is also downloaded, decrypted and re-hashed, which
+proves all of the stored content is intact; files larger than
+--rehash-max-size skip this step.
+
+A random sample of uploads is checked unless --all is given. The command
+exits non-zero if any upload is missing, mismatched or could not be checked.
+
+Examples:
+  # Check a sample of the last 30 days of uploads
+  rescale-int history verify --since 30d
+
+  # Check everything since a date, re-hashing content, and save the report
+  rescale-int history verify --since 2026-01-01 --all --rehash --report audit.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := GetContext()
+
+			cutoff, err := parseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			platform := apiClient.GetConfig().APIBaseURL
+
+			entries, err := history.Load(config.GetUploadHistoryPath(platform), cutoff)
+			if err != nil {
+				return err
+			}
+			report := &history.Report{
+				GeneratedAt: time.Now().UTC(),
+				Platform:    platform,
+				Since:       cutoff.UTC(),
+				Recorded:    len(entries),
+				Results:     []history.Result{},
+			}
+			if !all {
+				entries = history.Sample(entries, sample, rand.New(rand.NewSource(time.Now().UnixNano())))
+			}
+
+			verifier := &history.Verifier{
+				GetFileInfo:   apiClient.GetFileInfo,
+				RehashMaxSize: rehashLimit,
+				ReadStored: func(ctx context.Context, file *models.CloudFile, offset, length int64) ([]byte, error) {
+					return download.ReadStoredRange(ctx, apiClient, file, offset, length)
+				},
+			}
+			if rehash {
+				verifier.Rehash = func(ctx context.Context, file *models.CloudFile) (string, error) {
+					return rehashFile(ctx, apiClient, file)
+				}
+			}
+
+			fmt.Printf("Verifying %d of %d uploads since %s...\n", len(entries), report.Recorded, cutoff.Format("2006-01-02 15:04"))
+			for i, e := range entries {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				res := verifier.Verify(ctx, e)
+				report.Add(res)
+				if res.Status != history.StatusOK {
+					fmt.Printf("  [%d/%d] %-8s %s (%s): %s\n", i+1, len(entries), strings.ToUpper(res.Status), e.Name, e.FileID, res.Detail)
+				}
+			}
+
+			fmt.Printf("\nChecked %d: %d ok, %d mismatched, %d missing, %d errors\n",
+				report.Checked, report.OK, report.Mismatched, report.Missing, report.Errors)
+			if report.MetadataOnly > 0 {
+				fmt.Printf("%d ok by metadata only (recorded before block sampling; use --rehash to check their content)\n", report.MetadataOnly)
+			}
+
+			if reportPath != "" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(reportPath, data, 0600); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+				fmt.Printf("Report written to %s\n", reportPath)
+			}
+
+			if report.Failed() {
+				return fmt.Errorf("%d of %d uploads failed verification", report.Checked-report.OK, report.Checked)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "Check uploads since this age (e.g. 30d, 12h) or date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&sample, "sample", constants.HistoryVerifyDefaultSample, "Number of uploads to check at random")
+	cmd.Flags().BoolVar(&all, "all", false, "Check every upload in the window instead of a sample")
+	cmd.Flags().BoolVar(&rehash, "rehash", false, "Also download and re-hash file content")
+	cmd.Flags().Int64Var(&rehashLimit, "rehash-max-size", constants.HistoryRehashMaxSize, "Largest file (bytes) to re-hash; larger files get the sampled block check only")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the JSON audit report to this file")
+
+	return cmd
+}
+
+// parseSince converts an age like "30d", "2w" or "12h", or a YYYY-MM-DD
+// date, into a cutoff time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err == nil && count >= 0 {
+			days := count
+			if s[n-1] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use an age like 30d, 2w or 12h, or a date like 2026-01-31", s)
+}
+
+// rehashFile downloads file into a temporary directory and returns the
+// SHA-512 of its decrypted content.
+func rehashFile(ctx context.Context, apiClient *api.Client, file *models.CloudFile) (string, error) {
+	dir, err := os.MkdirTemp("", "rescale-int-verify-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	localPath := filepath.Join(dir, "content")
+	err = download.DownloadFile(ctx, download.DownloadParams{
+		FileInfo:     file,
+		LocalPath:    localPath,
+		APIClient:    apiClient,
+		SkipChecksum: true, // Compared against the upload history instead
+	})
+	if err != nil {
+		return "", err
+	}
+	return encryption.CalculateSHA512(localPath)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/netusage"
+)
+
+// usageRecordInterval is how often a running command adds its network
+// traffic to the usage log, so long watches and daemons show up in the
+// daily totals before they exit.
+const usageRecordInterval = time.Minute
+
+// startUsageRecorder records the command's network traffic in the usage log
+// until the returned function is called, which flushes the rest.
+func startUsageRecorder() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		netusage.NewRecorder(config.GetNetworkUsagePath()).Run(ctx, usageRecordInterval)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// usageSummary is the JSON output of 'history usage'.
+type usageSummary struct {
+	Today netusage.Usage   `json:"today"`
+	Month netusage.Usage   `json:"month"`
+	Days  []netusage.Day   `json:"days"`
+	Runs  []netusage.Entry `json:"runs"`
+}
+
+func newHistoryUsageCmd() *cobra.Command {
+	var (
+		since   string
+		runs    int
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show network data sent and received per day and per run",
+		Long: `Show the network data this machine has sent (uploads) and received
+(downloads) through rescale-int, today, this month and per day, plus the
+totals of recent PUR runs.
+
+Bytes are counted on the wire: file contents plus API calls, TLS and
+retries, across all platforms, from both the CLI and the GUI.`,
+		Example: `  # Usage over the last 30 days
+  rescale-int history usage
+
+  # Since the start of the billing period, as JSON
+  rescale-int history usage --since 2026-10-01 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			cutoff, err := parseSince(since, now)
+			if err != nil {
+				return err
+			}
+			loadFrom := netusage.StartOfMonth(now)
+			if cutoff.Before(loadFrom) {
+				loadFrom = cutoff
+			}
+			entries, err := netusage.Load(config.GetNetworkUsagePath(), loadFrom)
+			if err != nil {
+				return err
+			}
+
+			var inWindow []netusage.Entry
+			for _, e := range entries {
+				if !e.Time.Before(cutoff) {
+					inWindow = append(inWindow, e)
+				}
+			}
+			summary := usageSummary{
+				Today: netusage.Sum(entries, netusage.StartOfDay(now)),
+				Month: netusage.Sum(entries, netusage.StartOfMonth(now)),
+				Days:  netusage.Daily(inWindow),
+				Runs:  netusage.Runs(inWindow),
+			}
+			if runs >= 0 && len(summary.Runs) > runs {
+				summary.Runs = summary.Runs[:runs]
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(summary)
+			}
+
+			fmt.Printf("Today:      %s\n", summary.Today)
+			fmt.Printf("This month: %s\n", summary.Month)
+			if len(summary.Days) == 0 {
+				fmt.Printf("\nNo network usage recorded since %s\n", cutoff.Format("2006-01-02"))
+				return nil
+			}
+
+			fmt.Println()
+			fmt.Printf("%-10s %12s %12s\n", "DATE", "SENT", "RECEIVED")
+			var total netusage.Usage
+			for _, d := range summary.Days {
+				fmt.Printf("%-10s %12s %12s\n", d.Date, cloud.FormatBytes(d.Sent), cloud.FormatBytes(d.Received))
+				total = total.Add(d.Usage)
+			}
+			fmt.Printf("%-10s %12s %12s\n", "Total", cloud.FormatBytes(total.Sent), cloud.FormatBytes(total.Received))
+
+			if len(summary.Runs) > 0 {
+				fmt.Printf("\nRecent runs:\n%-16s %-24s %12s %12s\n", "FINISHED", "RUN", "SENT", "RECEIVED")
+				for _, r := range summary.Runs {
+					fmt.Printf("%-16s %-24s %12s %12s\n", r.Time.Local().Format("2006-01-02 15:04"), r.RunID,
+						cloud.FormatBytes(r.Sent), cloud.FormatBytes(r.Received))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "Show days since this age (e.g. 30d, 12h) or date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&runs, "runs", 10, "Number of recent runs to list")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+
+	return cmd
+}
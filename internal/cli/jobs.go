@@ -15,6 +15,7 @@ import (
 
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/logging"
@@ -43,9 +44,15 @@ func newJobsCmd() *cobra.Command {
 	jobsCmd.AddCommand(newJobsSubmitCmd())
 	jobsCmd.AddCommand(newJobsStopCmd())
 	jobsCmd.AddCommand(newJobsTailCmd())
+	jobsCmd.AddCommand(newJobsLiveFilesCmd())
 	jobsCmd.AddCommand(newJobsListFilesCmd())
 	jobsCmd.AddCommand(newJobsWatchCmd())
 	jobsCmd.AddCommand(newJobsDownloadCmd())
+	jobsCmd.AddCommand(newJobsDownloadOutputsCmd())
+	jobsCmd.AddCommand(newJobsDownloadListCmd())
+	jobsCmd.AddCommand(newJobsDiffCmd())
+	jobsCmd.AddCommand(newJobsImportCmd())
+	jobsCmd.AddCommand(newJobsContinueCmd())
 
 	return jobsCmd
 }
@@ -74,7 +81,8 @@ Example:
 				return err
 			}
 
-			ctx := GetContext()
+			ctx, cancel := apiClient.WithTimeout(GetContext(), config.APIListing)
+			defer cancel()
 
 			// List jobs
 			logger.Info().Msg("Fetching jobs")
@@ -147,7 +155,8 @@ Example:
 				return err
 			}
 
-			ctx := GetContext()
+			ctx, cancel := apiClient.WithTimeout(GetContext(), config.APIStatus)
+			defer cancel()
 
 			// Get job
 			logger.Info().Str("job_id", jobID).Msg("Fetching job details")
@@ -526,19 +535,27 @@ func newJobsStopCmd() *cobra.Command {
 	var confirm bool
 
 	cmd := &cobra.Command{
-		Use:   "stop",
+		Use:   "stop [job-id]",
 		Short: "Stop a running job",
 		Long: `Stop a running or queued job.
 
 WARNING: This operation cannot be undone!
 
 Example:
+  rescale-int jobs stop XxYyZz
   rescale-int jobs stop --job-id XxYyZz`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
+			if len(args) == 1 {
+				if jobID != "" && jobID != args[0] {
+					return fmt.Errorf("job ID given both as argument (%s) and --job-id (%s)", args[0], jobID)
+				}
+				jobID = args[0]
+			}
 			if jobID == "" {
-				return fmt.Errorf("--job-id is required")
+				return fmt.Errorf("job ID is required (argument or --job-id)")
 			}
 
 			// Confirmation prompt
@@ -559,7 +576,8 @@ Example:
 				return err
 			}
 
-			ctx := GetContext()
+			ctx, cancel := apiClient.WithTimeout(GetContext(), config.APIMutation)
+			defer cancel()
 
 			// Stop job
 			logger.Info().Str("job_id", jobID).Msg("Stopping job")
@@ -569,114 +587,17 @@ Example:
 
 			fmt.Printf("✓ Job stop request sent successfully\n")
 			fmt.Printf("  Job ID: %s\n", jobID)
+			if job, err := apiClient.GetJob(ctx, jobID); err == nil && job.JobStatus.Status != "" {
+				fmt.Printf("  Status: %s\n", job.JobStatus.Status)
+			}
 			fmt.Println("\nNote: It may take a few moments for the job to fully stop.")
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&jobID, "job-id", "j", "", "Job ID (required)")
+	cmd.Flags().StringVarP(&jobID, "job-id", "j", "", "Job ID (or pass as argument)")
 	cmd.Flags().BoolVarP(&confirm, "confirm", "y", false, "Skip confirmation prompt")
-	cmd.MarkFlagRequired("job-id")
-
-	return cmd
-}
-
-// newJobsTailCmd creates the 'jobs tail' command for real-time log monitoring.
-func newJobsTailCmd() *cobra.Command {
-	var jobID string
-	var pollInterval int
-
-	cmd := &cobra.Command{
-		Use:   "tail",
-		Short: "Tail job status logs in real-time",
-		Long: `Monitor job status changes in real-time.
-
-This command polls the job status at regular intervals and displays updates.
-Press Ctrl+C to stop monitoring.
-
-Example:
-  # Monitor job with 5-second polling
-  rescale-int jobs tail --job-id XxYyZz --interval 5`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			logger := GetLogger()
-
-			if jobID == "" {
-				return fmt.Errorf("--job-id is required")
-			}
-
-			// Get API client
-			apiClient, err := getAPIClient()
-			if err != nil {
-				return err
-			}
-
-			ctx := GetContext()
-
-			fmt.Printf("Monitoring job %s (polling every %d seconds, Ctrl+C to stop)...\n\n", jobID, pollInterval)
-
-			// Track last status to avoid duplicate prints
-			var lastStatus string
-			var lastDate string
-
-			ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
-			defer ticker.Stop()
-
-			// Poll immediately on start
-			statuses, err := apiClient.GetJobStatuses(ctx, jobID)
-			if err != nil {
-				return fmt.Errorf("failed to get job statuses: %w", err)
-			}
-
-			if len(statuses) > 0 {
-				latest := statuses[len(statuses)-1]
-				lastStatus = latest.Status
-				lastDate = latest.StatusDate
-				fmt.Printf("[%s] %s", latest.StatusDate, latest.Status)
-				if latest.StatusReason != "" {
-					fmt.Printf(" - %s", latest.StatusReason)
-				}
-				fmt.Println()
-			}
-
-			// Poll for updates
-			for range ticker.C {
-				statuses, err := apiClient.GetJobStatuses(ctx, jobID)
-				if err != nil {
-					logger.Error().Err(err).Msg("Failed to get job statuses")
-					continue
-				}
-
-				if len(statuses) > 0 {
-					latest := statuses[len(statuses)-1]
-
-					// Only print if status or date changed
-					if latest.Status != lastStatus || latest.StatusDate != lastDate {
-						lastStatus = latest.Status
-						lastDate = latest.StatusDate
-
-						fmt.Printf("[%s] %s", latest.StatusDate, latest.Status)
-						if latest.StatusReason != "" {
-							fmt.Printf(" - %s", latest.StatusReason)
-						}
-						fmt.Println()
-
-						// Stop monitoring if job reached terminal state
-						if latest.Status == "Completed" || latest.Status == "Failed" {
-							fmt.Printf("\nJob reached terminal state: %s\n", latest.Status)
-							return nil
-						}
-					}
-				}
-			}
-
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVarP(&jobID, "job-id", "j", "", "Job ID (required)")
-	cmd.Flags().IntVarP(&pollInterval, "interval", "i", 10, "Polling interval in seconds")
-	cmd.MarkFlagRequired("job-id")
 
 	return cmd
 }
@@ -759,6 +680,7 @@ func newJobsDownloadCmd() *cobra.Command {
 	var skipAll bool
 	var resumeAll bool
 	var skipChecksum bool
+	var verify bool
 	var filterPatterns string
 	var excludePatterns string
 	var searchTerms string
@@ -803,6 +725,9 @@ Examples:
   # Download all files, overwriting existing
   rescale-int jobs download --job-id XxYyZz --overwrite
 
+  # Re-check every file (including existing ones) against the job's checksums
+  rescale-int jobs download -j XxYyZz -d ./results --skip --verify
+
   # Download specific file by ID
   rescale-int jobs download --job-id XxYyZz --file-id AbCdEf --output /path/to/file.dat`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -837,9 +762,9 @@ Examples:
 					return fmt.Errorf("only one of --overwrite, --skip, or --resume can be specified")
 				}
 
-				// Determine output directory
+				// Determine output directory (config download_dir / download_organize)
 				if outputDir == "" {
-					outputDir = "."
+					outputDir = defaultJobDownloadDir(ctx, apiClient, jobID)
 				}
 
 				// Parse filter patterns
@@ -849,7 +774,7 @@ Examples:
 				pathFilterList := filter.ParsePatternList(pathFilterPatterns)
 
 				// Use helper function for symmetry with files download
-				return executeJobDownload(ctx, jobID, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, filterList, excludeList, searchList, pathFilterList, apiClient, logger)
+				return executeJobDownload(ctx, jobID, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, verify, filterList, excludeList, searchList, pathFilterList, apiClient, logger)
 			}
 
 			// MODE 2: Download specific file
@@ -956,7 +881,7 @@ Examples:
 	cmd.Flags().StringVarP(&jobID, "job-id", "j", "", "Job ID (required)")
 	cmd.Flags().StringVar(&jobID, "id", "", "Job ID (alias for --job-id)")
 	cmd.Flags().StringVar(&fileID, "file-id", "", "Specific file ID to download (optional, downloads all files if not specified)")
-	cmd.Flags().StringVarP(&outputDir, "outdir", "d", "", "Output directory for batch download (default: current directory)")
+	cmd.Flags().StringVarP(&outputDir, "outdir", "d", "", "Output directory for batch download (default: config download_dir, else current directory)")
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path for single file download")
 	cmd.Flags().IntVarP(&maxConcurrent, "max-concurrent", "m", constants.DefaultMaxConcurrent,
 		fmt.Sprintf("Maximum concurrent downloads (%d-%d)", constants.MinMaxConcurrent, constants.MaxMaxConcurrent))
@@ -964,6 +889,7 @@ Examples:
 	cmd.Flags().BoolVarP(&skipAll, "skip", "S", false, "Skip existing files without prompting")
 	cmd.Flags().BoolVarP(&resumeAll, "resume", "r", false, "Resume interrupted downloads without prompting")
 	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip checksum verification (not recommended, allows corrupted downloads)")
+	cmd.Flags().BoolVar(&verify, "verify", false, "After downloading, re-check every output file on disk against the job's checksums (or sizes) and download mismatches again")
 	cmd.Flags().StringVar(&filterPatterns, "filter", "", "Include only files matching these patterns (comma-separated glob patterns, e.g. \"*.dat,*.log\")")
 	cmd.Flags().StringVarP(&excludePatterns, "exclude", "x", "", "Exclude files matching these patterns (comma-separated glob patterns, e.g. \"debug*,temp*\")")
 	cmd.Flags().StringVarP(&searchTerms, "search", "s", "", "Include only files containing these terms in filename (comma-separated, case-insensitive)")
@@ -1016,12 +942,21 @@ func runCreateOnlyWorkflow(
 	fmt.Printf("✓ Job created (not submitted)\n")
 	fmt.Printf("  Job ID: %s\n", jobResp.ID)
 	fmt.Printf("  Name: %s\n", jobResp.Name)
+	printJobWarnings(jobResp.Warnings)
 	fmt.Printf("\nTo submit this job later, run:\n")
 	fmt.Printf("  rescale-int jobs submit --job-id %s\n", jobResp.ID)
 
 	return nil
 }
 
+// printJobWarnings prints the warnings the platform returned when creating
+// a job, such as deprecated software versions or license conflicts.
+func printJobWarnings(warnings models.JobWarnings) {
+	for _, w := range warnings {
+		fmt.Printf("  ⚠ Platform warning: %s\n", w)
+	}
+}
+
 // runSubmitWorkflow handles creating and submitting a job
 func runSubmitWorkflow(
 	ctx context.Context,
@@ -1063,6 +998,7 @@ func runSubmitWorkflow(
 
 	fmt.Printf("✓ Job created: %s\n", jobResp.ID)
 	fmt.Printf("  Name: %s\n", jobResp.Name)
+	printJobWarnings(jobResp.Warnings)
 
 	// Submit job automatically
 	logger.Info().Str("job_id", jobResp.ID).Msg("Submitting job")
@@ -1128,6 +1064,7 @@ func runEndToEndJobWorkflow(
 
 	fmt.Printf("✓ Job created: %s\n", jobResp.ID)
 	fmt.Printf("  Name: %s\n", jobResp.Name)
+	printJobWarnings(jobResp.Warnings)
 
 	logger.Info().Str("job_id", jobResp.ID).Msg("Submitting job")
 	if err := apiClient.SubmitJob(ctx, jobResp.ID); err != nil {
@@ -1193,7 +1130,7 @@ func monitorJobUntilComplete(ctx context.Context, jobID string, apiClient *api.C
 			// Add per-request timeout
 			// Note: GetJob() doesn't return jobStatus - use GetJobStatuses() instead
 			// See models/job.go comment: "The /jobs/{id}/ GET endpoint does NOT include jobStatus"
-			reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			reqCtx, cancel := apiClient.WithTimeout(ctx, config.APIStatus)
 			statuses, err := apiClient.GetJobStatuses(reqCtx, jobID)
 			cancel()
 
@@ -1259,5 +1196,5 @@ func downloadJobResults(ctx context.Context, jobID string, apiClient *api.Client
 	// This provides concurrent downloads, modern progress UI, and fixes zerolog warnings
 	// No filters for E2E workflow - download all files
 	// Use strict checksum verification (skipChecksum=false)
-	return executeJobDownload(ctx, jobID, outputDir, 5, false, false, false, false, nil, nil, nil, nil, apiClient, logger)
+	return executeJobDownload(ctx, jobID, outputDir, 5, false, false, false, false, false, nil, nil, nil, nil, apiClient, logger)
 }
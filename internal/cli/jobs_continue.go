@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/continuation"
+	"github.com/rescale/rescale-int/internal/pur/parser"
+	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+func newJobsContinueCmd() *cobra.Command {
+	var (
+		restartFiles string
+		templatePath string
+		command      string
+		name         string
+		noInputs     bool
+		stateFile    string
+		dryRun       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "continue <job-id-or-url>",
+		Short: "Start a new job from a finished job's restart files",
+		Long: `Continue a completed or stopped job from its restart (checkpoint) files.
+
+The new job copies the original job's software, hardware, licenses, project,
+tags and automations. Its inputs are the output files matching --restart-files
+plus, unless --no-inputs, the original job's inputs. Files are passed by ID:
+nothing is downloaded or re-uploaded.
+
+The command is, in order of precedence: --command, the continuationCommand of
+the job in --template (JSON or CSV), or the original job's command.
+
+Patterns are comma-separated globs matched against file names, or against
+paths relative to the job's working directory if they contain "/".
+
+Examples:
+  # Continue from all .rst files, with the template's continuation command
+  rescale-int jobs continue AbCdE --restart-files "*.rst" --template wing.json
+
+  # Preview the continuation job without creating it
+  rescale-int jobs continue AbCdE --restart-files "restart/*" \
+    --command "./run.sh --restart" --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := parser.ParseJobRef(args[0])
+			if err != nil {
+				return err
+			}
+			patterns := continuation.ParsePatterns(restartFiles)
+			if len(patterns) == 0 {
+				return fmt.Errorf("--restart-files is required")
+			}
+
+			if command == "" && templatePath != "" {
+				templates, err := config.LoadJobs(templatePath)
+				if err != nil {
+					return fmt.Errorf("failed to load template: %w", err)
+				}
+				command = templates[0].ContinuationCommand
+				if command == "" {
+					return fmt.Errorf("template %s has no continuationCommand", templatePath)
+				}
+			}
+
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			history, err := apiClient.GetJobStatuses(ctx, jobID)
+			if err != nil {
+				return fmt.Errorf("failed to get status of job %s: %w", jobID, err)
+			}
+			latest, _, _ := watch.LatestStatus(history)
+			if !continuation.Continuable(latest.Status) {
+				return fmt.Errorf("job %s is %s; only finished jobs can be continued", jobID, statusOrUnknown(latest.Status))
+			}
+
+			raw, err := apiClient.GetJobRaw(ctx, jobID)
+			if err != nil {
+				return fmt.Errorf("failed to get job %s: %w", jobID, err)
+			}
+			base, warnings, err := parser.RescaleJobToJobSpec(raw)
+			if err != nil {
+				return err
+			}
+			if tags, err := apiClient.GetJobTags(ctx, jobID); err == nil {
+				base.Tags = tags
+			} else {
+				warnings = append(warnings, fmt.Sprintf("could not fetch tags: %v", err))
+			}
+
+			outputs, err := apiClient.ListJobFiles(ctx, jobID)
+			if err != nil {
+				return fmt.Errorf("failed to list output files of job %s: %w", jobID, err)
+			}
+			restart, err := continuation.MatchFiles(outputs, patterns)
+			if err != nil {
+				return err
+			}
+			if len(restart) == 0 {
+				return fmt.Errorf("no output files of job %s match %s", jobID, strings.Join(patterns, ", "))
+			}
+
+			if command == "" {
+				warnings = append(warnings, "no continuation command given; the new job reruns the original command")
+			}
+			ids := make([]string, len(restart))
+			for i, f := range restart {
+				ids[i] = f.ID
+			}
+			spec, err := continuation.BuildSpec(base, ids, continuation.Options{
+				Name:       name,
+				Command:    command,
+				KeepInputs: !noInputs,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+			}
+			fmt.Printf("Continuing job %s (%s) as %s\n", jobID, base.JobName, spec.JobName)
+			fmt.Printf("  Command: %s\n", spec.Command)
+			fmt.Printf("  Restart files (%d):\n", len(restart))
+			for _, f := range restart {
+				fmt.Printf("    %s (%s)\n", continuation.RelativePath(f), cloud.FormatBytes(f.DecryptedSize))
+			}
+			if spec.ExtraInputFileIDs != "" {
+				fmt.Printf("  Original inputs: %s\n", spec.ExtraInputFileIDs)
+			}
+
+			if dryRun {
+				fmt.Println("\nDry run: no job created. Job spec:")
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(spec)
+			}
+			fmt.Println()
+
+			pipe, err := pipeline.NewPipeline(cfg, apiClient, []models.JobSpec{spec}, stateFile, false, nil, false, "", false)
+			if err != nil {
+				return fmt.Errorf("failed to create pipeline: %w", err)
+			}
+			attachPipelineEvents(pipe)
+			runStart := time.Now()
+			runErr := pipe.Run(ctx)
+			publishPipelineComplete(pipe, runStart, "")
+			if runErr != nil {
+				return fmt.Errorf("continuation job failed: %w", runErr)
+			}
+
+			for _, st := range pipe.StateManager().GetAllStates() {
+				if st.JobID == "" {
+					return fmt.Errorf("continuation job was not created: %s", st.ErrorMessage)
+				}
+				fmt.Printf("\n✓ Created continuation job %s (%s)\n", st.JobID, spec.JobName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&restartFiles, "restart-files", "", "Comma-separated patterns of output files to restart from (required)")
+	cmd.Flags().StringVarP(&templatePath, "template", "t", "", "Job template (JSON or CSV) whose continuationCommand to use")
+	cmd.Flags().StringVar(&command, "command", "", "Command of the new job (overrides --template)")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the new job (default <name>_cont<N>)")
+	cmd.Flags().BoolVar(&noInputs, "no-inputs", false, "Do not pass the original job's input files")
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file for the new job")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the continuation job without creating it")
+
+	return cmd
+}
+
+func statusOrUnknown(status string) string {
+	if status == "" {
+		return "in an unknown state"
+	}
+	return status
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/pur/jobdiff"
+)
+
+func newJobsDiffCmd() *cobra.Command {
+	var pathA string
+	var pathB string
+	var outputJSON bool
+	var riskyOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two jobs files field by field",
+		Long: `Compare two jobs files (CSV or JSON), e.g. two revisions of a study,
+before submitting. Jobs are matched by job name (directory if unnamed).
+
+Shows new and removed jobs and every changed field. Changes that often
+break a run are marked with ⚠:
+  - core type changed
+  - walltime reduced
+
+Examples:
+  rescale-int jobs diff --a jobs_v1.csv --b jobs_v2.csv
+
+  # Only jobs with risky changes
+  rescale-int jobs diff --a jobs_v1.csv --b jobs_v2.csv --risky-only
+
+  # Machine-readable output
+  rescale-int jobs diff --a jobs_v1.csv --b jobs_v2.json --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pathA == "" || pathB == "" {
+				return fmt.Errorf("--a and --b are required")
+			}
+
+			jobsA, err := config.LoadJobs(pathA)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", pathA, err)
+			}
+			jobsB, err := config.LoadJobs(pathB)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", pathB, err)
+			}
+
+			res := jobdiff.Compare(jobsA, jobsB)
+			if riskyOnly {
+				risky := res.Changed[:0]
+				for _, c := range res.Changed {
+					if c.Risky() {
+						risky = append(risky, c)
+					}
+				}
+				res.Changed = risky
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+
+			fmt.Printf("Comparing %s (%d jobs) → %s (%d jobs)\n\n", pathA, len(jobsA), pathB, len(jobsB))
+			if !riskyOnly {
+				for _, k := range res.Added {
+					fmt.Printf("+ %s (new)\n", k)
+				}
+				for _, k := range res.Removed {
+					fmt.Printf("- %s (removed)\n", k)
+				}
+			}
+			for _, c := range res.Changed {
+				fmt.Printf("~ %s\n", c.Key)
+				for _, fc := range c.Changes {
+					line := fmt.Sprintf("    %s: %q → %q", fc.Field, fc.Old, fc.New)
+					if fc.Risk != "" {
+						line += "  ⚠ " + fc.Risk
+					}
+					fmt.Println(line)
+				}
+			}
+
+			fmt.Printf("\nSummary: %d added, %d removed, %d changed (%d with risky changes), %d unchanged\n",
+				len(res.Added), len(res.Removed), len(res.Changed), res.RiskyCount(), res.Unchanged)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pathA, "a", "", "Original jobs file (CSV or JSON, required)")
+	cmd.Flags().StringVar(&pathB, "b", "", "Revised jobs file (CSV or JSON, required)")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output the comparison as JSON")
+	cmd.Flags().BoolVar(&riskyOnly, "risky-only", false, "Only show jobs with risky changes")
+
+	return cmd
+}
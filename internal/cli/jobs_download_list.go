@@ -0,0 +1,404 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// downloadListEntry is one line of a download list: a job (name or ID) and
+// the relative path of one of its output files.
+type downloadListEntry struct {
+	Line int
+	Job  string
+	Path string
+}
+
+// unresolvedEntry is a download list entry that matched no output file.
+type unresolvedEntry struct {
+	downloadListEntry
+	Reason string
+}
+
+// downloadListJob is a job of a download list with the output files its
+// entries resolved to, downloaded into Dir under --dest.
+type downloadListJob struct {
+	Key   string // Job column value
+	JobID string
+	Dir   string
+	Files []models.JobFile
+}
+
+// Header names accepted for the job and path columns of a download list.
+var (
+	downloadListJobColumns  = []string{"job", "job name", "job_name", "jobname", "job id", "job_id", "jobid"}
+	downloadListPathColumns = []string{"path", "relative path", "relative_path", "relativepath", "file", "output", "output path", "output_path"}
+)
+
+// newJobsDownloadListCmd creates the 'jobs download-list' command.
+func newJobsDownloadListCmd() *cobra.Command {
+	var (
+		dest             string
+		unresolvedReport string
+		dryRun           bool
+		maxConcurrent    int
+		overwriteAll     bool
+		skipAll          bool
+		resumeAll        bool
+		skipChecksum     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download-list <list.csv>",
+		Short: "Download specific output files listed by job and relative path",
+		Long: `Download the output files named in a list, such as one a post-processing
+team hands over for ParaView, into a layout that mirrors the jobs:
+<dest>/<job>/<relative path>.
+
+The list is a CSV file with the job (name or ID) in the first column and
+the file's path relative to the job's outputs in the second. A header row
+naming the columns (e.g. "job,path") is optional and may put them in any
+order; lines starting with # are ignored.
+
+Each job is looked up by ID, then by name (the newest job wins when several
+share a name), and each path is matched against the job's output files.
+Entries that cannot be resolved are reported (and written to
+--unresolved-report as CSV) and the command exits non-zero, after
+downloading everything that was resolved. Use --dry-run to only resolve.
+
+Examples:
+  # Download the listed files into ./post/<job>/<path>
+  rescale-int jobs download-list paraview-files.csv --dest ./post
+
+  # Check the list without downloading, saving what did not resolve
+  rescale-int jobs download-list paraview-files.csv --dry-run --unresolved-report missing.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			conflictFlags := 0
+			for _, set := range []bool{overwriteAll, skipAll, resumeAll} {
+				if set {
+					conflictFlags++
+				}
+			}
+			if conflictFlags > 1 {
+				return fmt.Errorf("only one of --overwrite, --skip, or --resume can be specified")
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open download list: %w", err)
+			}
+			entries, err := parseDownloadList(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("%s lists no files", args[0])
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			fmt.Printf("Resolving %d file(s)...\n", len(entries))
+			listCtx, cancel := apiClient.WithTimeout(ctx, config.APIListing)
+			allJobs, err := apiClient.ListJobs(listCtx)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("failed to list jobs: %w", err)
+			}
+			jobs, unresolved, warnings := resolveDownloadList(ctx, entries, allJobs,
+				func(ctx context.Context, jobID string) ([]models.JobFile, error) {
+					return listJobFilesFn(ctx, apiClient, jobID)
+				})
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for _, w := range warnings {
+				fmt.Printf("⚠️  %s\n", w)
+			}
+
+			resolved := len(entries) - len(unresolved)
+			fmt.Printf("Resolved %d of %d file(s) in %d job(s)\n", resolved, len(entries), len(jobs))
+			if len(unresolved) > 0 {
+				fmt.Printf("\n✗ %d unresolved line(s):\n", len(unresolved))
+				for _, u := range unresolved {
+					fmt.Printf("  line %d: %s, %s: %s\n", u.Line, u.Job, u.Path, u.Reason)
+				}
+				if unresolvedReport != "" {
+					if err := writeUnresolvedReport(unresolvedReport, unresolved); err != nil {
+						return err
+					}
+					fmt.Printf("Unresolved entries written to %s\n", unresolvedReport)
+				}
+			}
+
+			var failed []string
+			if !dryRun {
+				for i, job := range jobs {
+					outputDir := filepath.Join(dest, job.Dir)
+					fmt.Printf("\n[%d/%d] Job %s (%s) → %s\n", i+1, len(jobs), job.Key, job.JobID, outputDir)
+					err := downloadJobFiles(ctx, job.JobID, job.Files, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, false,
+						apiClient, logger)
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					if err != nil {
+						fmt.Printf("✗ Job %s: %v\n", job.Key, err)
+						failed = append(failed, job.Key)
+					}
+				}
+				fmt.Printf("\nDownloaded files of %d of %d job(s) to %s\n", len(jobs)-len(failed), len(jobs), dest)
+			}
+
+			switch {
+			case len(failed) > 0 && len(unresolved) > 0:
+				return fmt.Errorf("%d job(s) failed (%s) and %d line(s) unresolved", len(failed), strings.Join(failed, ", "), len(unresolved))
+			case len(failed) > 0:
+				return fmt.Errorf("%d job(s) failed: %s", len(failed), strings.Join(failed, ", "))
+			case len(unresolved) > 0:
+				return fmt.Errorf("%d line(s) unresolved", len(unresolved))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dest, "dest", "d", ".", "Directory to create the per-job subdirectories in")
+	cmd.Flags().StringVar(&unresolvedReport, "unresolved-report", "", "Write the entries that could not be resolved to this CSV file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Resolve the list and report, without downloading")
+	cmd.Flags().IntVarP(&maxConcurrent, "max-concurrent", "m", constants.DefaultMaxConcurrent,
+		fmt.Sprintf("Maximum concurrent downloads per job (1-%d)", constants.MaxMaxConcurrent))
+	cmd.Flags().BoolVarP(&overwriteAll, "overwrite", "w", false, "Overwrite existing files without prompting")
+	cmd.Flags().BoolVarP(&skipAll, "skip", "S", false, "Skip existing files without prompting")
+	cmd.Flags().BoolVarP(&resumeAll, "resume", "r", false, "Resume interrupted downloads without prompting")
+	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip checksum verification (not recommended, allows corrupted downloads)")
+
+	return cmd
+}
+
+// parseDownloadList reads a download list: CSV with the job and the
+// relative path in the first two columns, or in the columns a header row
+// names. Blank and # lines are skipped.
+func parseDownloadList(r io.Reader) ([]downloadListEntry, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	jobCol, pathCol := 0, 1
+	var entries []downloadListEntry
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if first {
+			if j, p := columnIndex(record, downloadListJobColumns), columnIndex(record, downloadListPathColumns); j >= 0 && p >= 0 {
+				jobCol, pathCol = j, p
+				continue
+			}
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) <= max(jobCol, pathCol) {
+			return nil, fmt.Errorf("line %d: expected a job and a relative path", line)
+		}
+		entries = append(entries, downloadListEntry{
+			Line: line,
+			Job:  strings.TrimSpace(record[jobCol]),
+			Path: strings.TrimSpace(record[pathCol]),
+		})
+	}
+}
+
+// columnIndex returns the index of the first field of record that is one of
+// names (case-insensitive), or -1.
+func columnIndex(record []string, names []string) int {
+	for i, field := range record {
+		field = strings.ToLower(strings.TrimSpace(field))
+		for _, name := range names {
+			if field == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// normalizeOutputPath turns a listed or platform relative path into the
+// slash-separated form they are compared in, or "" if it is not a relative
+// path inside the job's outputs.
+func normalizeOutputPath(p string) string {
+	p = path.Clean(strings.ReplaceAll(strings.TrimSpace(p), `\`, "/"))
+	p = strings.TrimPrefix(p, "./")
+	if p == "." || p == "" || path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return ""
+	}
+	return p
+}
+
+// resolveDownloadList resolves each entry to a job among jobs and to one of
+// that job's output files, listed with listFiles once per job. It returns
+// the jobs with their files in list order, the entries that did not
+// resolve, and warnings about ambiguous job names and repeated entries.
+func resolveDownloadList(
+	ctx context.Context,
+	entries []downloadListEntry,
+	jobs []models.JobResponse,
+	listFiles func(ctx context.Context, jobID string) ([]models.JobFile, error),
+) ([]downloadListJob, []unresolvedEntry, []string) {
+	byID := make(map[string]models.JobResponse, len(jobs))
+	byName := make(map[string][]models.JobResponse)
+	for _, j := range jobs {
+		byID[j.ID] = j
+		byName[j.Name] = append(byName[j.Name], j)
+	}
+
+	var (
+		out        []downloadListJob
+		unresolved []unresolvedEntry
+		warnings   []string
+		jobIndex   = make(map[string]int)                       // Key -> index in out, -1 = unresolved
+		jobFiles   = make(map[string]map[string]models.JobFile) // Key -> normalized path -> file
+		listErrs   = make(map[string]error)                     // Key -> error listing its files
+		seen       = make(map[string]int)                       // Key + path -> first line
+		usedDirs   = make(map[string]bool)
+	)
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		fail := func(reason string) {
+			unresolved = append(unresolved, unresolvedEntry{downloadListEntry: e, Reason: reason})
+		}
+		if e.Job == "" {
+			fail("no job")
+			continue
+		}
+		rel := normalizeOutputPath(e.Path)
+		if rel == "" {
+			fail("not a relative path")
+			continue
+		}
+
+		idx, known := jobIndex[e.Job]
+		if !known {
+			idx = -1
+			job, ok := byID[e.Job]
+			if !ok {
+				if matches := byName[e.Job]; len(matches) > 0 {
+					job = newestJob(matches)
+					ok = true
+					if len(matches) > 1 {
+						warnings = append(warnings, fmt.Sprintf("%d jobs are named %q; using the newest, %s", len(matches), e.Job, job.ID))
+					}
+				}
+			}
+			if ok {
+				files, err := listFiles(ctx, job.ID)
+				if err != nil {
+					listErrs[e.Job] = err
+				} else {
+					index := make(map[string]models.JobFile, len(files))
+					for _, f := range files {
+						p := f.RelativePath
+						if p == "" {
+							p = f.Name
+						}
+						if p = normalizeOutputPath(p); p != "" {
+							index[p] = f
+						}
+					}
+					jobFiles[e.Job] = index
+					dir := config.SafeDirName(e.Job)
+					if dir == "" || usedDirs[dir] {
+						dir = job.ID
+					}
+					usedDirs[dir] = true
+					idx = len(out)
+					out = append(out, downloadListJob{Key: e.Job, JobID: job.ID, Dir: dir})
+				}
+			}
+			jobIndex[e.Job] = idx
+		}
+		if idx < 0 {
+			if err := listErrs[e.Job]; err != nil {
+				fail(fmt.Sprintf("could not list job outputs: %v", err))
+			} else {
+				fail("job not found")
+			}
+			continue
+		}
+
+		key := e.Job + "\x00" + rel
+		if line, dup := seen[key]; dup {
+			warnings = append(warnings, fmt.Sprintf("line %d repeats line %d", e.Line, line))
+			continue
+		}
+		seen[key] = e.Line
+		f, ok := jobFiles[e.Job][rel]
+		if !ok {
+			fail("no such output file")
+			continue
+		}
+		out[idx].Files = append(out[idx].Files, f)
+	}
+	return out, unresolved, warnings
+}
+
+// newestJob returns the most recently created of jobs.
+func newestJob(jobs []models.JobResponse) models.JobResponse {
+	newest := jobs[0]
+	newestAt, _ := time.Parse(time.RFC3339, newest.CreatedAt)
+	for _, j := range jobs[1:] {
+		if at, err := time.Parse(time.RFC3339, j.CreatedAt); err == nil && at.After(newestAt) {
+			newest, newestAt = j, at
+		}
+	}
+	return newest
+}
+
+// writeUnresolvedReport writes the unresolved entries to path as CSV.
+func writeUnresolvedReport(path string, unresolved []unresolvedEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write unresolved report: %w", err)
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"line", "job", "path", "reason"})
+	for _, u := range unresolved {
+		w.Write([]string{fmt.Sprint(u.Line), u.Job, u.Path, u.Reason})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write unresolved report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write unresolved report: %w", err)
+	}
+	return nil
+}
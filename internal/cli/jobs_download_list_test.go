@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestParseDownloadList(t *testing.T) {
+	entries, err := parseDownloadList(strings.NewReader("# from the ParaView team\nRelative Path,Job Name\nVTK/wing_0.vtu, wing_a\n\n\"case 2/out.csv\",wing_b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []downloadListEntry{
+		{Line: 3, Job: "wing_a", Path: "VTK/wing_0.vtu"},
+		{Line: 5, Job: "wing_b", Path: "case 2/out.csv"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("parseDownloadList() = %+v, want %+v", entries, want)
+	}
+
+	// Without a header the job comes first
+	entries, err = parseDownloadList(strings.NewReader("AbCd,results/p.csv\n"))
+	if err != nil || len(entries) != 1 || entries[0].Job != "AbCd" || entries[0].Path != "results/p.csv" {
+		t.Errorf("parseDownloadList(no header) = %+v, %v", entries, err)
+	}
+	if _, err := parseDownloadList(strings.NewReader("job,path\nwing_a\n")); err == nil {
+		t.Error("parseDownloadList accepted a line without a path")
+	}
+}
+
+func TestResolveDownloadList(t *testing.T) {
+	jobs := []models.JobResponse{
+		{ID: "old", Name: "wing_a", CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "new", Name: "wing_a", CreatedAt: "2026-02-01T00:00:00Z"},
+		{ID: "EfGh", Name: "wing_b"},
+		{ID: "broken", Name: "wing_c"},
+	}
+	listed := map[string]int{}
+	listFiles := func(ctx context.Context, jobID string) ([]models.JobFile, error) {
+		listed[jobID]++
+		switch jobID {
+		case "new":
+			return []models.JobFile{
+				{ID: "f1", Name: "wing_0.vtu", RelativePath: "VTK/wing_0.vtu"},
+				{ID: "f2", Name: "p.csv", RelativePath: "p.csv"},
+			}, nil
+		case "EfGh":
+			return []models.JobFile{{ID: "f3", Name: "out.csv"}}, nil
+		}
+		return nil, errors.New("forbidden")
+	}
+
+	entries := []downloadListEntry{
+		{Line: 1, Job: "wing_a", Path: `VTK\wing_0.vtu`},
+		{Line: 2, Job: "EfGh", Path: "./out.csv"},
+		{Line: 3, Job: "wing_a", Path: "VTK/missing.vtu"},
+		{Line: 4, Job: "wing_z", Path: "p.csv"},
+		{Line: 5, Job: "wing_a", Path: "../etc/passwd"},
+		{Line: 6, Job: "wing_c", Path: "p.csv"},
+		{Line: 7, Job: "wing_a", Path: "VTK/wing_0.vtu"},
+		{Line: 8, Job: "wing_a", Path: "p.csv"},
+	}
+	got, unresolved, warnings := resolveDownloadList(context.Background(), entries, jobs, listFiles)
+
+	if len(got) != 2 || got[0].JobID != "new" || got[0].Dir != "wing_a" || got[1].JobID != "EfGh" {
+		t.Fatalf("jobs = %+v", got)
+	}
+	var ids []string
+	for _, f := range got[0].Files {
+		ids = append(ids, f.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"f1", "f2"}) || len(got[1].Files) != 1 || got[1].Files[0].ID != "f3" {
+		t.Errorf("files = %v and %+v", ids, got[1].Files)
+	}
+	if listed["new"] != 1 {
+		t.Errorf("wing_a outputs listed %d times, want once", listed["new"])
+	}
+
+	reasons := map[int]string{}
+	for _, u := range unresolved {
+		reasons[u.Line] = u.Reason
+	}
+	want := map[int]string{
+		3: "no such output file",
+		4: "job not found",
+		5: "not a relative path",
+		6: "could not list job outputs: forbidden",
+	}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("unresolved = %v, want %v", reasons, want)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "using the newest, new") || warnings[1] != "line 7 repeats line 1" {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/util/filter"
+	"github.com/rescale/rescale-int/internal/validation"
+)
+
+// newJobsDownloadOutputsCmd creates the 'jobs download-outputs' command.
+func newJobsDownloadOutputsCmd() *cobra.Command {
+	var (
+		dest            string
+		includePatterns string
+		excludePatterns string
+		maxConcurrent   int
+		overwriteAll    bool
+		skipAll         bool
+		resumeAll       bool
+		skipChecksum    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download-outputs <job-id>...",
+		Short: "Download the output files of several jobs",
+		Long: `Download the output files of one or more jobs, each into its own
+subdirectory of --dest named after the job ID.
+
+Each job's files are listed, filtered by file name with --include and
+--exclude (comma-separated glob patterns), and downloaded concurrently with
+their relative paths preserved. Jobs are processed one after another; a job
+that fails does not stop the others, and the command exits non-zero if any
+job failed.
+
+Examples:
+  # Solver output of three jobs into ./results/<job-id>/
+  rescale-int jobs download-outputs AbCdEf GhIjKl MnOpQr --include "*.out" --dest ./results
+
+  # Everything except restart files, skipping files already downloaded
+  rescale-int jobs download-outputs AbCdEf GhIjKl --exclude "*.rst" --skip`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			conflictFlags := 0
+			for _, set := range []bool{overwriteAll, skipAll, resumeAll} {
+				if set {
+					conflictFlags++
+				}
+			}
+			if conflictFlags > 1 {
+				return fmt.Errorf("only one of --overwrite, --skip, or --resume can be specified")
+			}
+
+			jobIDs, err := uniqueJobIDs(args)
+			if err != nil {
+				return err
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			includeList := filter.ParsePatternList(includePatterns)
+			excludeList := filter.ParsePatternList(excludePatterns)
+
+			var failed []string
+			for i, jobID := range jobIDs {
+				outputDir := filepath.Join(dest, jobID)
+				fmt.Printf("\n[%d/%d] Job %s → %s\n", i+1, len(jobIDs), jobID, outputDir)
+				err := executeJobDownload(ctx, jobID, outputDir, maxConcurrent, overwriteAll, skipAll, resumeAll, skipChecksum, false,
+					includeList, excludeList, nil, nil, apiClient, logger)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if err != nil {
+					fmt.Printf("✗ Job %s: %v\n", jobID, err)
+					failed = append(failed, jobID)
+				}
+			}
+
+			fmt.Printf("\nDownloaded outputs of %d of %d jobs to %s\n", len(jobIDs)-len(failed), len(jobIDs), dest)
+			if len(failed) > 0 {
+				return fmt.Errorf("%d job(s) failed: %s", len(failed), strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dest, "dest", "d", ".", "Directory to create the per-job subdirectories in")
+	cmd.Flags().StringVar(&includePatterns, "include", "", "Include only files matching these patterns (comma-separated glob patterns, e.g. \"*.out,*.log\")")
+	cmd.Flags().StringVarP(&excludePatterns, "exclude", "x", "", "Exclude files matching these patterns (comma-separated glob patterns, e.g. \"*.rst\")")
+	cmd.Flags().IntVarP(&maxConcurrent, "max-concurrent", "m", constants.DefaultMaxConcurrent,
+		fmt.Sprintf("Maximum concurrent downloads per job (1-%d)", constants.MaxMaxConcurrent))
+	cmd.Flags().BoolVarP(&overwriteAll, "overwrite", "w", false, "Overwrite existing files without prompting")
+	cmd.Flags().BoolVarP(&skipAll, "skip", "S", false, "Skip existing files without prompting")
+	cmd.Flags().BoolVarP(&resumeAll, "resume", "r", false, "Resume interrupted downloads without prompting")
+	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip checksum verification (not recommended, allows corrupted downloads)")
+
+	return cmd
+}
+
+// uniqueJobIDs returns the job IDs in order with duplicates removed. Each
+// becomes a directory name, so it must be a plain file name.
+func uniqueJobIDs(args []string) ([]string, error) {
+	seen := make(map[string]bool, len(args))
+	var ids []string
+	for _, arg := range args {
+		id := strings.TrimSpace(arg)
+		if id == "" || seen[id] {
+			continue
+		}
+		if err := validation.ValidateFilename(id); err != nil {
+			return nil, fmt.Errorf("invalid job ID %q: %w", id, err)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one job ID is required")
+	}
+	return ids, nil
+}
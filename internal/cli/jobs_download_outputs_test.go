@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUniqueJobIDs(t *testing.T) {
+	ids, err := uniqueJobIDs([]string{"AbCd", " EfGh ", "AbCd", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"AbCd", "EfGh"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("uniqueJobIDs() = %v, want %v", ids, want)
+	}
+
+	for _, args := range [][]string{{""}, {"../x"}, {"a/b"}, {".."}} {
+		if _, err := uniqueJobIDs(args); err == nil {
+			t.Errorf("uniqueJobIDs(%q) succeeded, want an error", args)
+		}
+	}
+}
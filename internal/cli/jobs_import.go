@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/parser"
+)
+
+func newJobsImportCmd() *cobra.Command {
+	var outputPath string
+	var overwrite bool
+
+	cmd := &cobra.Command{
+		Use:   "import <job-id-or-url>",
+		Short: "Turn an existing Rescale job into a job spec or PUR template",
+		Long: `Fetch an existing job's definition and write a job spec that reproduces it:
+software and version, core type and counts, walltime, command, license
+environment variables, project, automations, tags and input file references.
+
+Input files are referenced by file ID (ExtraInputFileIDs), so the spec can be
+submitted as-is with 'pur submit-existing' or used as the base job settings of
+a PUR template. Only the first analysis of multi-analysis jobs is imported.
+
+The output format follows the --output extension: .json (PUR template),
+.csv (jobs CSV) or .sh (SGE script). Without --output, JSON is printed.
+
+Examples:
+  rescale-int jobs import AbCdE
+  rescale-int jobs import https://platform.rescale.com/jobs/AbCdE/ -o template.json
+  rescale-int jobs import AbCdE -o jobs.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := parser.ParseJobRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			switch ext := strings.ToLower(filepath.Ext(outputPath)); ext {
+			case "", ".json", ".csv", ".sh":
+			default:
+				return fmt.Errorf("unsupported output format %q (use .json, .csv or .sh)", ext)
+			}
+			if outputPath != "" && !overwrite {
+				if _, err := os.Stat(outputPath); err == nil {
+					return fmt.Errorf("output file already exists: %s (use --overwrite)", outputPath)
+				}
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			raw, err := apiClient.GetJobRaw(ctx, jobID)
+			if err != nil {
+				return fmt.Errorf("failed to get job %s: %w", jobID, err)
+			}
+			spec, warnings, err := parser.RescaleJobToJobSpec(raw)
+			if err != nil {
+				return err
+			}
+			// Tags are a separate endpoint; a failure only loses the tags
+			if tags, err := apiClient.GetJobTags(ctx, jobID); err == nil {
+				spec.Tags = tags
+			} else {
+				warnings = append(warnings, fmt.Sprintf("could not fetch tags: %v", err))
+			}
+
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+			}
+
+			if outputPath == "" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(spec)
+			}
+			if err := writeImportedJob(outputPath, spec); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Imported job %s (%s) to %s\n", jobID, spec.JobName, outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file: .json, .csv or .sh (default: print JSON)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing output file")
+
+	return cmd
+}
+
+// writeImportedJob saves spec in the format given by the path's extension.
+func writeImportedJob(path string, spec models.JobSpec) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return config.SaveJobJSON(path, spec)
+	case ".csv":
+		return config.SaveJobsCSV(path, []models.JobSpec{spec})
+	case ".sh":
+		md := parser.JobSpecToSGEMetadata(spec)
+		env, err := config.JobEnvironment(spec)
+		if err != nil {
+			return err
+		}
+		if env != nil {
+			md.EnvVariables = env
+		}
+		if spec.ExtraInputFileIDs != "" {
+			fmt.Fprintf(os.Stderr, "⚠ SGE scripts cannot reference input file IDs; %s are not included\n", spec.ExtraInputFileIDs)
+		}
+		script := md.ToSGEScript()
+		if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+			return fmt.Errorf("failed to write SGE script: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (use .json, .csv or .sh)", filepath.Ext(path))
+	}
+}
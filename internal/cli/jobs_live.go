@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+// newJobsLiveFilesCmd creates the 'jobs live-files' command group, which
+// works in the work directory of a running job.
+func newJobsLiveFilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "live-files",
+		Short: "Browse, download and upload files in a running job's work directory",
+		Long: `Work with the files of a running job while it runs, where the platform
+offers in-job access to the job's cluster.
+
+List the work directory, download intermediate files (a snapshot of a file
+that may still be growing), or upload a small correction file, e.g. an edited
+control file the solver re-reads. Uploads are limited to ` + cloud.FormatBytes(constants.MaxLiveUploadSize) + `.
+
+The job must have a running cluster; queued and finished jobs have no work
+directory to access. Use 'jobs download' for the outputs of finished jobs.`,
+	}
+
+	cmd.AddCommand(newLiveFilesListCmd())
+	cmd.AddCommand(newLiveFilesGetCmd())
+	cmd.AddCommand(newLiveFilesPutCmd())
+
+	return cmd
+}
+
+// newLiveFilesListCmd creates the 'jobs live-files ls' command.
+func newLiveFilesListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls <job-id> [directory]",
+		Short: "List a running job's work directory",
+		Long: `List a directory of a running job's work directory, the work directory
+itself unless a directory relative to it is given.
+
+Examples:
+  rescale-int jobs live-files ls XxYyZz
+  rescale-int jobs live-files ls XxYyZz run1/postProcessing`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := ""
+			if len(args) == 2 {
+				dir = args[1]
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			runID, err := activeRunID(ctx, apiClient, args[0])
+			if err != nil {
+				return err
+			}
+			entries, err := apiClient.ListLiveFiles(ctx, args[0], runID, dir)
+			if err != nil {
+				return liveFilesError(err)
+			}
+			if len(entries) == 0 {
+				fmt.Println("Directory is empty")
+				return nil
+			}
+
+			sortLiveEntries(entries)
+			for _, e := range entries {
+				name := e.Path
+				if e.IsDirectory {
+					fmt.Printf("%10s  %-20s %s/\n", "-", e.DateModified, name)
+					continue
+				}
+				fmt.Printf("%10s  %-20s %s\n", cloud.FormatBytes(e.Size), e.DateModified, name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// newLiveFilesGetCmd creates the 'jobs live-files get' command.
+func newLiveFilesGetCmd() *cobra.Command {
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "get <job-id> <path>",
+		Short: "Download a file from a running job's work directory",
+		Long: `Download the current contents of a file in a running job's work directory.
+The file may still be written to by the job, so the copy is a snapshot.
+
+Examples:
+  # Save into the current directory under the file's name
+  rescale-int jobs live-files get XxYyZz run1/residuals.dat
+
+  # Save under another name, or "-" for stdout
+  rescale-int jobs live-files get XxYyZz solver.log -o - | tail -50`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, remotePath := args[0], args[1]
+			if outPath == "" {
+				outPath = path.Base(filepath.ToSlash(remotePath))
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			runID, err := activeRunID(ctx, apiClient, jobID)
+			if err != nil {
+				return err
+			}
+
+			if outPath == "-" {
+				_, err := apiClient.DownloadLiveFile(ctx, jobID, runID, remotePath, os.Stdout)
+				return liveFilesError(err)
+			}
+
+			// Printing is viewing; saving a copy is a download
+			if err := viewer.Check("save live file"); err != nil {
+				return err
+			}
+
+			// Write to a temporary file so a failed download leaves no partial copy
+			tmp := outPath + ".part"
+			f, err := os.Create(tmp)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", tmp, err)
+			}
+			n, err := apiClient.DownloadLiveFile(ctx, jobID, runID, remotePath, f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(tmp)
+				return liveFilesError(err)
+			}
+			if err := os.Rename(tmp, outPath); err != nil {
+				os.Remove(tmp)
+				return fmt.Errorf("failed to save %s: %w", outPath, err)
+			}
+			fmt.Printf("✓ Downloaded %s (%s) to %s\n", remotePath, cloud.FormatBytes(n), outPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outPath, "output", "o", "", "Local path to save to, or - for stdout (default: the file's name)")
+
+	return cmd
+}
+
+// newLiveFilesPutCmd creates the 'jobs live-files put' command.
+func newLiveFilesPutCmd() *cobra.Command {
+	var remotePath string
+	var overwrite bool
+
+	cmd := &cobra.Command{
+		Use:   "put <job-id> <local-file>",
+		Short: "Upload a small file into a running job's work directory",
+		Long: `Upload a small file (up to ` + cloud.FormatBytes(constants.MaxLiveUploadSize) + `) into a running job's work directory,
+e.g. a corrected control file the solver picks up on its next iteration.
+
+An existing file is only replaced with --overwrite.
+
+Examples:
+  # Upload to the work directory under the file's name
+  rescale-int jobs live-files put XxYyZz stop.flag
+
+  # Replace a file in a subdirectory
+  rescale-int jobs live-files put XxYyZz ./controlDict --to run1/system/controlDict --overwrite`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, localPath := args[0], args[1]
+			if remotePath == "" {
+				remotePath = filepath.Base(localPath)
+			}
+
+			info, err := os.Stat(localPath)
+			if err != nil {
+				return fmt.Errorf("cannot read %s: %w", localPath, err)
+			}
+			if info.IsDir() {
+				return fmt.Errorf("%s is a directory; only single files can be uploaded", localPath)
+			}
+			if info.Size() > constants.MaxLiveUploadSize {
+				return fmt.Errorf("%s is %s; live uploads are limited to %s", localPath,
+					cloud.FormatBytes(info.Size()), cloud.FormatBytes(constants.MaxLiveUploadSize))
+			}
+			data, err := os.ReadFile(localPath)
+			if err != nil {
+				return fmt.Errorf("cannot read %s: %w", localPath, err)
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			runID, err := activeRunID(ctx, apiClient, jobID)
+			if err != nil {
+				return err
+			}
+			entry, err := apiClient.UploadLiveFile(ctx, jobID, runID, remotePath, data, overwrite)
+			if err != nil {
+				return liveFilesError(err)
+			}
+			fmt.Printf("✓ Uploaded %s (%s) to %s\n", localPath, cloud.FormatBytes(entry.Size), entry.Path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&remotePath, "to", "", "Path in the work directory to write (default: the local file's name)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the file if it already exists")
+
+	return cmd
+}
+
+// activeRunID returns the job's running cluster run, or an error saying the
+// job has none.
+func activeRunID(ctx context.Context, apiClient *api.Client, jobID string) (string, error) {
+	runID, err := apiClient.ActiveRunID(ctx, jobID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get runs of job %s: %w", jobID, err)
+	}
+	if runID == "" {
+		status, _ := latestJobStatus(ctx, apiClient, jobID)
+		if status == "" {
+			status = "unknown"
+		}
+		return "", fmt.Errorf("job %s has no running cluster (status: %s)", jobID, status)
+	}
+	return runID, nil
+}
+
+// liveFilesError explains ErrLiveFilesUnsupported and passes other errors
+// through.
+func liveFilesError(err error) error {
+	if errors.Is(err, api.ErrLiveFilesUnsupported) {
+		return fmt.Errorf("%w: the platform does not offer in-job file access for this job's cluster; 'jobs tail' can still follow its output files", err)
+	}
+	return err
+}
+
+// sortLiveEntries orders directories first, then by path.
+func sortLiveEntries(entries []api.LiveEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDirectory != entries[j].IsDirectory {
+			return entries[i].IsDirectory
+		}
+		return entries[i].Path < entries[j].Path
+	})
+}
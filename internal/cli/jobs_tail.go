@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// defaultTailFile is the output file `jobs tail` streams without --file.
+const defaultTailFile = "process_output.log"
+
+// newJobsTailCmd creates the 'jobs tail' command, which streams a file of a
+// running job to stdout as it grows.
+func newJobsTailCmd() *cobra.Command {
+	var (
+		jobID        string
+		fileName     string
+		pollInterval int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tail [job-id]",
+		Short: "Stream a running job's output file",
+		Long: `Stream a file of a running job to stdout as it grows, ` + defaultTailFile + `
+unless --file names another, until the job finishes.
+
+The command polls the job's live file listing. Whenever the file has grown,
+the bytes appended since the last poll are written to stdout. Status changes
+go to stderr, so the output can be piped or redirected. While the job is
+queued or starting and the file does not exist yet, the command waits for
+it. When the job finishes, whatever was appended after the last poll is read
+from the job's output files. Press Ctrl+C to stop.
+
+Each poll downloads the current copy of the file, so use a longer --interval
+for large files.
+
+Examples:
+  # Follow the solver log of a running job
+  rescale-int jobs tail XxYyZz
+
+  # Follow another file, by name or path relative to the run directory
+  rescale-int jobs tail XxYyZz --file run1/solver.out --interval 5
+
+  # Keep a local copy while watching
+  rescale-int jobs tail XxYyZz | tee solver.log`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			if len(args) == 1 {
+				if jobID != "" && jobID != args[0] {
+					return fmt.Errorf("job ID given both as argument (%s) and --job-id (%s)", args[0], jobID)
+				}
+				jobID = args[0]
+			}
+			if jobID == "" {
+				return fmt.Errorf("job ID is required (argument or --job-id)")
+			}
+			if pollInterval < 1 {
+				return fmt.Errorf("--interval must be at least 1 second")
+			}
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			tmpDir, err := os.MkdirTemp("", "rescale-tail-")
+			if err != nil {
+				return fmt.Errorf("failed to create temp directory: %w", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			tailer := &fileTailer{
+				name: fileName,
+				out:  os.Stdout,
+				tmp:  filepath.Join(tmpDir, "tail"),
+				fetch: func(ctx context.Context, fileID, localPath string) error {
+					return downloadFileFn(ctx, download.DownloadParams{
+						FileID:       fileID,
+						LocalPath:    localPath,
+						APIClient:    apiClient,
+						OutputWriter: io.Discard,
+						SkipChecksum: true, // A live file changes while it is read
+					})
+				},
+			}
+
+			fmt.Fprintf(os.Stderr, "Tailing %s of job %s (polling every %ds, Ctrl+C to stop)...\n", fileName, jobID, pollInterval)
+
+			ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
+			defer ticker.Stop()
+
+			var lastStatus string
+			waiting := false
+			for {
+				status, err := latestJobStatus(ctx, apiClient, jobID)
+				if err != nil {
+					logger.Warn().Err(err).Msg("Failed to get job status")
+				} else if status != lastStatus {
+					fmt.Fprintf(os.Stderr, "[%s] %s\n", time.Now().Format("15:04:05"), status)
+					lastStatus = status
+				}
+
+				if watch.TerminalStatuses[status] {
+					// The run is gone; its files are now job output files
+					files, err := listJobFilesFn(ctx, apiClient, jobID)
+					if err != nil {
+						return fmt.Errorf("job finished (%s) but its files could not be listed: %w", status, err)
+					}
+					candidates := make([]tailCandidate, len(files))
+					for i, f := range files {
+						candidates[i] = tailCandidate{ID: f.ID, Name: f.Name, RelativePath: f.RelativePath, Size: f.DecryptedSize}
+					}
+					found, err := tailer.poll(ctx, candidates)
+					if err != nil {
+						return err
+					}
+					if !found && tailer.offset == 0 {
+						fmt.Fprintf(os.Stderr, "Job finished (%s) without writing %s\n", status, fileName)
+					} else {
+						fmt.Fprintf(os.Stderr, "Job finished: %s\n", status)
+					}
+					return nil
+				}
+
+				if candidates, err := liveRunFiles(ctx, apiClient, jobID); err != nil {
+					logger.Warn().Err(err).Msg("Failed to list live files")
+				} else if found, err := tailer.poll(ctx, candidates); err != nil {
+					logger.Warn().Err(err).Msg("Failed to read live file")
+				} else if !found && !waiting && status != "" {
+					fmt.Fprintf(os.Stderr, "Waiting for %s to appear...\n", fileName)
+					waiting = true
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&jobID, "job-id", "j", "", "Job ID (or pass as argument)")
+	cmd.Flags().StringVarP(&fileName, "file", "f", defaultTailFile, "File to stream: a name, or a path relative to the run directory")
+	cmd.Flags().IntVarP(&pollInterval, "interval", "i", 10, "Polling interval in seconds")
+
+	return cmd
+}
+
+// latestJobStatus returns the most recent status of a job.
+func latestJobStatus(ctx context.Context, apiClient *api.Client, jobID string) (string, error) {
+	ctx, cancel := apiClient.WithTimeout(ctx, config.APIStatus)
+	defer cancel()
+	statuses, err := apiClient.GetJobStatuses(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	if latest, _, ok := watch.LatestStatus(statuses); ok {
+		return latest.Status, nil
+	}
+	if len(statuses) > 0 {
+		return statuses[len(statuses)-1].Status, nil
+	}
+	return "", nil
+}
+
+// liveRunFiles lists the files of the job's active run, or none while no
+// run has started.
+func liveRunFiles(ctx context.Context, apiClient *api.Client, jobID string) ([]tailCandidate, error) {
+	runID, err := apiClient.ActiveRunID(ctx, jobID)
+	if err != nil || runID == "" {
+		return nil, err
+	}
+	files, err := apiClient.GetRunFiles(ctx, jobID, runID)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]tailCandidate, len(files))
+	for i, f := range files {
+		candidates[i] = tailCandidate{ID: f.ID, Name: f.Name, RelativePath: f.RelativePath, Size: f.Size}
+	}
+	return candidates, nil
+}
+
+// tailCandidate is a file of a job that may be the one being tailed.
+type tailCandidate struct {
+	ID           string
+	Name         string
+	RelativePath string
+	Size         int64 // 0 when the listing doesn't say
+}
+
+// fileTailer writes what is appended to one job file to out, one poll at a
+// time.
+type fileTailer struct {
+	name  string // File name, or slash-separated path relative to the run directory
+	out   io.Writer
+	tmp   string // Local path each copy of the file is fetched to
+	fetch func(ctx context.Context, fileID, localPath string) error
+
+	offset   int64 // Bytes written to out so far
+	lastSize int64 // Size listed at the last fetch
+}
+
+// matches reports whether c is the file being tailed.
+func (t *fileTailer) matches(c tailCandidate) bool {
+	if strings.Contains(t.name, "/") {
+		return filepath.ToSlash(c.RelativePath) == t.name
+	}
+	return c.Name == t.name
+}
+
+// poll fetches the file from candidates if it has grown and writes the new
+// bytes. It reports whether the file was listed.
+func (t *fileTailer) poll(ctx context.Context, candidates []tailCandidate) (bool, error) {
+	var file *tailCandidate
+	for i := range candidates {
+		if t.matches(candidates[i]) {
+			file = &candidates[i]
+			break
+		}
+	}
+	if file == nil {
+		return false, nil
+	}
+	if file.Size > 0 && file.Size == t.lastSize {
+		return true, nil
+	}
+
+	if err := t.fetch(ctx, file.ID, t.tmp); err != nil {
+		return true, err
+	}
+	defer os.Remove(t.tmp)
+	f, err := os.Open(t.tmp)
+	if err != nil {
+		return true, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return true, err
+	}
+	t.lastSize = file.Size
+
+	if info.Size() < t.offset {
+		fmt.Fprintf(os.Stderr, "%s was truncated; streaming it from the start\n", t.name)
+		t.offset = 0
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return true, err
+	}
+	n, err := io.Copy(t.out, f)
+	t.offset += n
+	return true, err
+}
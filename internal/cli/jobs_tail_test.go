@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTailer_StreamsAppendedBytes(t *testing.T) {
+	var content string
+	fetches := 0
+	var out bytes.Buffer
+	tailer := &fileTailer{
+		name: "process_output.log",
+		out:  &out,
+		tmp:  filepath.Join(t.TempDir(), "tail"),
+		fetch: func(ctx context.Context, fileID, localPath string) error {
+			fetches++
+			return os.WriteFile(localPath, []byte(content), 0600)
+		},
+	}
+	poll := func(size int64, candidates ...tailCandidate) bool {
+		t.Helper()
+		found, err := tailer.poll(context.Background(), append(candidates, tailCandidate{ID: "f1", Name: "process_output.log", Size: size}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+
+	if found, _ := tailer.poll(context.Background(), []tailCandidate{{ID: "x", Name: "other.log"}}); found {
+		t.Fatal("poll() found a file with another name")
+	}
+
+	content = "step 1\n"
+	poll(7, tailCandidate{ID: "x", Name: "other.log"})
+	content = "step 1\nstep 2\n"
+	poll(14)
+	poll(14) // Unchanged size: not fetched again
+	if out.String() != "step 1\nstep 2\n" {
+		t.Errorf("output = %q", out.String())
+	}
+	if fetches != 2 {
+		t.Errorf("fetched %d times, want 2", fetches)
+	}
+
+	// A file rewritten from scratch is streamed from its start
+	content = "restart\n"
+	poll(8)
+	if out.String() != "step 1\nstep 2\nrestart\n" {
+		t.Errorf("output after truncation = %q", out.String())
+	}
+}
+
+func TestFileTailer_MatchesRelativePath(t *testing.T) {
+	tailer := &fileTailer{name: "run2/solver.out"}
+	if tailer.matches(tailCandidate{Name: "solver.out", RelativePath: "run1/solver.out"}) {
+		t.Error("matched a file in another directory")
+	}
+	if !tailer.matches(tailCandidate{Name: "solver.out", RelativePath: "run2/solver.out"}) {
+		t.Error("did not match the file by its relative path")
+	}
+}
@@ -159,7 +159,7 @@ func runSingleJobWatch(
 
 	downloadFn := func(ctx context.Context, jID string) error {
 		return executeJobDownload(ctx, jID, outdir, maxConcurrent,
-			false, true, false, false, // overwrite=false, skip=true, resume=false, skipChecksum=false
+			false, true, false, false, false, // overwrite=false, skip=true, resume=false, skipChecksum=false, verify=false
 			filterList, excludeList, searchList, nil,
 			apiClient, logger)
 	}
@@ -232,7 +232,7 @@ func runNewerThanWatch(
 		jobOutdir := filepath.Join(outdir, fmt.Sprintf("job_%s", jID))
 		return func(ctx context.Context, _ string) error {
 			return executeJobDownload(ctx, jID, jobOutdir, maxConcurrent,
-				false, true, false, false, // skip-existing
+				false, true, false, false, false, // skip-existing
 				nil, nil, nil, nil,
 				apiClient, logger)
 		}
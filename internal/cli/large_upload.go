@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/upload"
+)
+
+// confirmLargeUpload asks before uploading paths whose total size exceeds
+// the large_upload_confirm_gb setting, showing the size, the estimated
+// duration at the throughput measured from earlier uploads, and the
+// workspace's remaining storage. It returns false if the user declines.
+// yes skips the check; without a terminal the upload is refused unless yes
+// is set.
+func confirmLargeUpload(ctx context.Context, apiClient *api.Client, paths []string, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	total, files, err := upload.LocalSize(paths)
+	if err != nil {
+		// The upload itself reports unreadable paths
+		return true, nil
+	}
+	l := upload.CheckLargeUpload(ctx, apiClient, total, files)
+	if l == nil {
+		return true, nil
+	}
+
+	fmt.Println("⚠️  Large upload:")
+	for _, line := range l.Lines() {
+		fmt.Printf("  %s\n", line)
+	}
+	if !IsTerminal() {
+		return false, fmt.Errorf("refusing to upload more than %s without confirmation: pass --yes",
+			cloud.FormatBytes(l.ThresholdBytes))
+	}
+	fmt.Print("Start upload? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Println("Upload cancelled")
+		return false, nil
+	}
+	return true, nil
+}
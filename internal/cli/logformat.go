@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+// logFormat is the --log-format flag; empty uses log_format from the config
+// file.
+var logFormat string
+
+// applyLogFormat switches log output to --log-format, or else log_format,
+// before anything is logged. The config is read here because most commands
+// load it later, if at all.
+func applyLogFormat() error {
+	if logFormat != "" {
+		format, err := logging.ParseFormat(logFormat)
+		if err != nil {
+			return fmt.Errorf("--log-format: %w", err)
+		}
+		logging.SetFormat(format)
+		return nil
+	}
+
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = config.GetDefaultConfigPath()
+	}
+	cfg, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		return nil
+	}
+	format, err := logging.ParseFormat(cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: log_format ignored: %v\n", err)
+		return nil
+	}
+	logging.SetFormat(format)
+	return nil
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+// logsFollowInterval is how often 'logs tail --follow' checks for new lines.
+const logsFollowInterval = 500 * time.Millisecond
+
+// logLevelRank orders run log levels for --level.
+var logLevelRank = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+// newLogsCmd creates the 'logs' command group.
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Read the logs of PUR runs",
+		Long: `Every PUR run (CLI, GUI or REST API) writes its own log under the config
+directory, named after its run ID, so lines from concurrent runs don't mix.
+Each line carries the run ID and, for uploads, the ID of the transfer it
+belongs to. The same IDs appear on the --events stream and in the GUI
+Activity Logs, where they can be used as a filter.`,
+	}
+
+	cmd.AddCommand(newLogsTailCmd())
+	cmd.AddCommand(newLogsListCmd())
+
+	return cmd
+}
+
+func newLogsTailCmd() *cobra.Command {
+	var (
+		runID      string
+		transferID string
+		lines      int
+		follow     bool
+		level      string
+		asJSON     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Show the last lines of a run's log",
+		Long: `Print the last lines of a PUR run's log, optionally only those of one
+transfer, and with --follow keep printing new lines as they are written
+(Ctrl+C to stop). Without --run the most recently written run log is used.
+
+Examples:
+  # Last 50 lines of the most recent run
+  rescale-int logs tail
+
+  # Follow a run started from study.state
+  rescale-int logs tail --run study -f
+
+  # Only the lines of one upload, including debug detail
+  rescale-int logs tail --run study --transfer tr-1a2b3c4d --level debug`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			level = strings.ToUpper(level)
+			if _, ok := logLevelRank[level]; !ok {
+				return fmt.Errorf("invalid --level %q: expected debug, info, warn or error", level)
+			}
+			if lines < 0 {
+				return fmt.Errorf("--lines must not be negative")
+			}
+
+			path, err := resolveRunLog(runID)
+			if err != nil {
+				return err
+			}
+			keep := func(rec logging.RunLogRecord) bool {
+				return logLevelRank[rec.Level] >= logLevelRank[level] &&
+					(transferID == "" || rec.TransferID == transferID)
+			}
+			show := func(rec logging.RunLogRecord) {
+				if asJSON {
+					line, _ := json.Marshal(rec)
+					fmt.Println(string(line))
+				} else {
+					fmt.Println(formatRunLogRecord(rec))
+				}
+			}
+
+			records, err := logging.ReadRunLog(path)
+			if err != nil {
+				return fmt.Errorf("failed to read run log: %w", err)
+			}
+			var shown []logging.RunLogRecord
+			for _, rec := range records {
+				if keep(rec) {
+					shown = append(shown, rec)
+				}
+			}
+			if len(shown) > lines {
+				shown = shown[len(shown)-lines:]
+			}
+			for _, rec := range shown {
+				show(rec)
+			}
+			if !follow {
+				return nil
+			}
+			return followRunLog(path, keep, show)
+		},
+	}
+
+	cmd.Flags().StringVar(&runID, "run", "", "Run ID (state file name without extension; default: most recent run)")
+	cmd.Flags().StringVar(&transferID, "transfer", "", "Only show lines of this transfer ID")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 50, "Number of lines to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new lines as they are written")
+	cmd.Flags().StringVar(&level, "level", "info", "Minimum level to show: debug, info, warn or error")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw JSON records")
+
+	return cmd
+}
+
+func newLogsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List run logs, most recent first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logs, err := listRunLogs()
+			if err != nil {
+				return err
+			}
+			if len(logs) == 0 {
+				fmt.Println("No run logs yet")
+				return nil
+			}
+			fmt.Printf("%-40s  %-19s  %s\n", "RUN ID", "LAST WRITTEN", "SIZE")
+			for _, l := range logs {
+				fmt.Printf("%-40s  %-19s  %s\n", l.runID, l.modTime.Format("2006-01-02 15:04:05"), cloud.FormatBytes(l.size))
+			}
+			return nil
+		},
+	}
+}
+
+// runLogFile is one file in config.GetRunLogDir.
+type runLogFile struct {
+	runID   string
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// listRunLogs returns the run logs, most recently written first.
+func listRunLogs() ([]runLogFile, error) {
+	dir := config.GetRunLogDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run logs: %w", err)
+	}
+	var logs []runLogFile
+	for _, e := range entries {
+		runID, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, runLogFile{runID, filepath.Join(dir, e.Name()), info.ModTime(), info.Size()})
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].modTime.After(logs[j].modTime) })
+	return logs, nil
+}
+
+// resolveRunLog returns the log path of runID, or of the most recently
+// written run when runID is empty.
+func resolveRunLog(runID string) (string, error) {
+	if runID != "" {
+		if strings.ContainsAny(runID, `/\`) || runID == "." || runID == ".." {
+			return "", fmt.Errorf("invalid run ID %q", runID)
+		}
+		path := config.GetRunLogPath(runID)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("no log for run %q (see 'rescale-int logs list')", runID)
+		}
+		return path, nil
+	}
+	logs, err := listRunLogs()
+	if err != nil {
+		return "", err
+	}
+	if len(logs) == 0 {
+		return "", fmt.Errorf("no run logs yet: runs write their log to %s", config.GetRunLogDir())
+	}
+	return logs[0].path, nil
+}
+
+// formatRunLogRecord renders rec as one console line.
+func formatRunLogRecord(rec logging.RunLogRecord) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s", rec.Time.Local().Format("2006-01-02 15:04:05"), rec.Level)
+	if rec.Stage != "" {
+		fmt.Fprintf(&b, " [%s]", rec.Stage)
+	}
+	if rec.JobName != "" {
+		fmt.Fprintf(&b, " [%s]", rec.JobName)
+	}
+	b.WriteString(" " + rec.Message)
+	if rec.TransferID != "" {
+		fmt.Fprintf(&b, " (transfer %s)", rec.TransferID)
+	}
+	return b.String()
+}
+
+// followRunLog prints records appended to path after its current end until
+// the command is interrupted.
+func followRunLog(path string, keep func(logging.RunLogRecord) bool, show func(logging.RunLogRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open run log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to read run log: %w", err)
+	}
+
+	ctx := GetContext()
+	reader := bufio.NewReader(f)
+	var partial string
+	ticker := time.NewTicker(logsFollowInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			chunk, err := reader.ReadString('\n')
+			partial += chunk
+			if err != nil {
+				break // Incomplete line: wait for the rest
+			}
+			var rec logging.RunLogRecord
+			if json.Unmarshal([]byte(partial), &rec) == nil && keep(rec) {
+				show(rec)
+			}
+			partial = ""
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+func TestFormatRunLogRecord(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	got := formatRunLogRecord(logging.RunLogRecord{
+		Time: ts, Level: "WARN", RunID: "study", TransferID: "tr-1", Stage: "upload", JobName: "Run_1", Message: "retrying",
+	})
+	want := "2026-03-01 12:00:00 WARN  [upload] [Run_1] retrying (transfer tr-1)"
+	if got != want {
+		t.Errorf("formatRunLogRecord = %q, want %q", got, want)
+	}
+}
+
+func TestResolveRunLogRejectsPaths(t *testing.T) {
+	for _, id := range []string{"../config", `a\b`, ".."} {
+		if _, err := resolveRunLog(id); err == nil {
+			t.Errorf("resolveRunLog(%q) accepted a path", id)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"syscall"
 
 	"golang.org/x/term"
+
+	"github.com/rescale/rescale-int/internal/cloud/upload"
 )
 
 // =============================================================================
@@ -272,8 +274,27 @@ const (
 	UploadDuplicateModeCheck                                // Check and prompt for each duplicate
 	UploadDuplicateModeSkipAll                              // Check and skip all duplicates
 	UploadDuplicateModeUploadAll                            // Check and upload all anyway
+	UploadDuplicateModeOverwrite                            // Check and replace existing files
+	UploadDuplicateModeRename                               // Check and upload duplicates as "name (1).ext"
+	UploadDuplicateModeVersion                              // Check and upload duplicates as "name_v2.ext"
 )
 
+// uploadDuplicateModeFor returns the mode that applies policy to every
+// duplicate without prompting (--on-duplicate).
+func uploadDuplicateModeFor(policy upload.DuplicatePolicy) UploadDuplicateMode {
+	switch policy {
+	case upload.DuplicateSkip:
+		return UploadDuplicateModeSkipAll
+	case upload.DuplicateOverwrite:
+		return UploadDuplicateModeOverwrite
+	case upload.DuplicateRename:
+		return UploadDuplicateModeRename
+	case upload.DuplicateVersion:
+		return UploadDuplicateModeVersion
+	}
+	return UploadDuplicateModeUploadAll
+}
+
 // promptUploadDuplicateMode asks user to select duplicate handling mode for file uploads
 // Returns the selected mode or error. Used when no --check-duplicates flag is provided.
 func promptUploadDuplicateMode() (UploadDuplicateMode, error) {
@@ -308,6 +329,44 @@ func promptUploadDuplicateMode() (UploadDuplicateMode, error) {
 	}
 }
 
+// promptRepeatedUploads asks whether files named more than once should be
+// uploaded again. Returns true to upload every repeat, false to upload each
+// file once.
+func promptRepeatedUploads(paths []string) (bool, error) {
+	fmt.Printf("\n⚠️  %d file(s) are named more than once:\n", len(paths))
+	for i, p := range paths {
+		if i == 5 {
+			fmt.Printf("  ...and %d more\n", len(paths)-i)
+			break
+		}
+		fmt.Printf("  %s\n", p)
+	}
+	fmt.Println("")
+	fmt.Println("What would you like to do?")
+	fmt.Println("  1. Upload each file once (skip the repeats)")
+	fmt.Println("  2. Upload them again (creates separate copies in the destination)")
+	fmt.Println("  3. Abort")
+	fmt.Print("\nChoose [1-3]: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.TrimSpace(input) {
+	case "1":
+		return false, nil
+	case "2":
+		return true, nil
+	case "3":
+		return false, fmt.Errorf("upload aborted by user")
+	default:
+		fmt.Println("Invalid choice, please try again.")
+		return promptRepeatedUploads(paths)
+	}
+}
+
 // UploadConflictAction represents user choice for individual file upload conflicts (duplicate exists)
 // Note: Uses Overwrite (consistent naming) - UploadAnyway* aliases retained for backward compatibility
 type UploadConflictAction int
@@ -318,8 +377,44 @@ const (
 	UploadOverwriteOnce
 	UploadOverwriteAll
 	UploadAbort
+	UploadRenameOnce
+	UploadRenameAll
+	UploadVersionOnce
+	UploadVersionAll
+	UploadKeepBothAll // --allow-duplicates: upload and keep both files
 )
 
+// duplicatePolicy maps a conflict action to the upload duplicate-name policy
+// it applies.
+func (a UploadConflictAction) duplicatePolicy() upload.DuplicatePolicy {
+	switch a {
+	case UploadSkipOnce, UploadSkipAll:
+		return upload.DuplicateSkip
+	case UploadOverwriteOnce, UploadOverwriteAll:
+		return upload.DuplicateOverwrite
+	case UploadRenameOnce, UploadRenameAll:
+		return upload.DuplicateRename
+	case UploadVersionOnce, UploadVersionAll:
+		return upload.DuplicateVersion
+	}
+	return upload.DuplicateAllow
+}
+
+// uploadConflictActionFor returns the "for all" action for a policy.
+func uploadConflictActionFor(policy upload.DuplicatePolicy) UploadConflictAction {
+	switch policy {
+	case upload.DuplicateSkip:
+		return UploadSkipAll
+	case upload.DuplicateOverwrite:
+		return UploadOverwriteAll
+	case upload.DuplicateRename:
+		return UploadRenameAll
+	case upload.DuplicateVersion:
+		return UploadVersionAll
+	}
+	return UploadKeepBothAll
+}
+
 // promptUploadConflict asks user what to do when a file already exists in the destination
 func promptUploadConflict(fileName string, existingChecksum string) (UploadConflictAction, error) {
 	fmt.Printf("\n⚠️  File '%s' already exists in destination", fileName)
@@ -330,10 +425,14 @@ func promptUploadConflict(fileName string, existingChecksum string) (UploadConfl
 	fmt.Println("What would you like to do?")
 	fmt.Println("  1. Skip (once) - Don't upload this file")
 	fmt.Println("  2. Skip (for all) - Skip all duplicates")
-	fmt.Println("  3. Overwrite (once) - Replace existing file")
+	fmt.Println("  3. Overwrite (once) - Upload, then delete the existing file")
 	fmt.Println("  4. Overwrite (for all) - Replace all duplicates")
 	fmt.Println("  5. Abort - Stop upload")
-	fmt.Print("Choose [1-5]: ")
+	fmt.Println("  6. Rename (once) - Upload as \"name (1).ext\"")
+	fmt.Println("  7. Rename (for all) - Rename all duplicates")
+	fmt.Println("  8. Version (once) - Upload as \"name_v2.ext\"")
+	fmt.Println("  9. Version (for all) - Add a version suffix to all duplicates")
+	fmt.Print("Choose [1-9]: ")
 
 	reader := bufio.NewReader(os.Stdin)
 	input, err := reader.ReadString('\n')
@@ -353,6 +452,14 @@ func promptUploadConflict(fileName string, existingChecksum string) (UploadConfl
 		return UploadOverwriteAll, nil
 	case "5":
 		return UploadAbort, nil
+	case "6":
+		return UploadRenameOnce, nil
+	case "7":
+		return UploadRenameAll, nil
+	case "8":
+		return UploadVersionOnce, nil
+	case "9":
+		return UploadVersionAll, nil
 	default:
 		fmt.Println("Invalid choice, please try again.")
 		return promptUploadConflict(fileName, existingChecksum)
@@ -2,25 +2,40 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/models"
 	"github.com/rescale/rescale-int/internal/pur/filescan"
+	"github.com/rescale/rescale-int/internal/pur/jobarray"
 	"github.com/rescale/rescale-int/internal/pur/pattern"
 	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/report"
+	"github.com/rescale/rescale-int/internal/pur/repro"
+	"github.com/rescale/rescale-int/internal/pur/runhistory"
+	"github.com/rescale/rescale-int/internal/pur/runtimes"
 	"github.com/rescale/rescale-int/internal/pur/state"
 	"github.com/rescale/rescale-int/internal/pur/validation"
+	"github.com/rescale/rescale-int/internal/resources"
 	"github.com/rescale/rescale-int/internal/util/multipart"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // newPURCmd creates the 'pur' command group.
@@ -33,11 +48,14 @@ func newPURCmd() *cobra.Command {
 
 	// Add PUR subcommands
 	purCmd.AddCommand(newMakeDirsCSVCmd())
+	purCmd.AddCommand(newPURZipCmd())
 	purCmd.AddCommand(newScanFilesCmd())
+	purCmd.AddCommand(newSweepCmd())
 	purCmd.AddCommand(newPlanCmd())
 	purCmd.AddCommand(newRunCmd())
 	purCmd.AddCommand(newResumeCmd())
 	purCmd.AddCommand(newSubmitExistingCmd())
+	purCmd.AddCommand(newDownloadOutputsCmd())
 
 	return purCmd
 }
@@ -55,6 +73,8 @@ func newMakeDirsCSVCmd() *cobra.Command {
 	var validationPattern string
 	var startIndex int
 	var partDirs []string
+	var zipPath string
+	var extractTo string
 
 	cmd := &cobra.Command{
 		Use:   "make-dirs-csv",
@@ -72,15 +92,28 @@ template command across jobs (e.g., data_1.txt becomes data_2.txt for Run_2).
 
 Use --command-pattern-test to preview detected patterns without generating CSV.
 
+Use --zip to take the runs from folders inside a ZIP archive instead: the jobs
+read their inputs straight from the archive (Directory is the ZIP, TarSubpath
+the run folder), so it never has to be extracted. Add --extract-to to extract
+only the matching runs there and point the jobs at the extracted copies.
+
 Examples:
   rescale-int pur make-dirs-csv --template template.csv --output jobs.csv --pattern "Run_*"
   rescale-int pur make-dirs-csv --template template.csv --output jobs.csv --pattern "Run_*" --iterate-command-patterns
   rescale-int pur make-dirs-csv --template template.csv --pattern "Run_*" --command-pattern-test
   rescale-int pur make-dirs-csv --template template.csv --output jobs.csv --pattern "Run_*" \
-    --part-dirs /data/DOE_1 /data/DOE_2 /data/DOE_3 --validation-pattern "*.avg.fnc"`,
+    --part-dirs /data/DOE_1 /data/DOE_2 /data/DOE_3 --validation-pattern "*.avg.fnc"
+  rescale-int pur make-dirs-csv --template template.csv --output jobs.csv --pattern "Run_*" --zip study.zip`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
+			if zipPath != "" && len(partDirs) > 0 {
+				return fmt.Errorf("--zip cannot be combined with --part-dirs")
+			}
+			if extractTo != "" && zipPath == "" {
+				return fmt.Errorf("--extract-to requires --zip")
+			}
+
 			if templatePath == "" {
 				return fmt.Errorf("--template is required")
 			}
@@ -157,7 +190,17 @@ Examples:
 				RunSubpath:        runSubpath,
 			}
 
-			if len(partDirs) > 0 {
+			var results []multipart.ScanResult
+			if zipPath != "" {
+				// ZIP mode: run folders inside the archive
+				results, err = multipart.ScanZip(zipPath, scanOpts)
+				if err == nil && extractTo != "" {
+					results, err = extractZipRuns(results, extractTo)
+				}
+				if err != nil {
+					return fmt.Errorf("ZIP scan failed: %w", err)
+				}
+			} else if len(partDirs) > 0 {
 				// Multi-part mode
 				scanOpts.PartDirs = partDirs
 				logger.Info().Int("partDirs", len(partDirs)).Msg("Multi-part mode enabled")
@@ -176,9 +219,11 @@ Examples:
 				scanOpts.SingleDir = baseDir
 			}
 
-			results, err := multipart.ScanDirectories(scanOpts)
-			if err != nil {
-				return fmt.Errorf("directory scan failed: %w", err)
+			if zipPath == "" {
+				results, err = multipart.ScanDirectories(scanOpts)
+				if err != nil {
+					return fmt.Errorf("directory scan failed: %w", err)
+				}
 			}
 
 			// Convert ScanResults to JobSpecs
@@ -188,6 +233,9 @@ Examples:
 				job := tmpl
 				job.JobName = r.JobName
 				job.Directory = r.Directory
+				if r.ZipFolder != "" {
+					job.TarSubpath = path.Join(r.ZipFolder, filepath.ToSlash(tmpl.TarSubpath))
+				}
 
 				// Iterate command patterns if requested
 				if iteratePatterns {
@@ -195,7 +243,7 @@ Examples:
 				}
 
 				jobs = append(jobs, job)
-				logger.Info().Str("dir", filepath.Base(r.Directory)).Str("job", r.JobName).Msg("Added job")
+				logger.Info().Str("dir", filepath.Base(job.Directory)).Str("subpath", job.TarSubpath).Str("job", r.JobName).Msg("Added job")
 			}
 
 			// Save jobs CSV
@@ -224,6 +272,8 @@ Examples:
 	cmd.Flags().StringVar(&validationPattern, "validation-pattern", "", "File pattern to validate directories")
 	cmd.Flags().IntVar(&startIndex, "start-index", 1, "Starting index for job numbering")
 	cmd.Flags().StringSliceVar(&partDirs, "part-dirs", nil, "Project directories for multi-part mode (e.g., DOE_1 DOE_2 DOE_3)")
+	cmd.Flags().StringVar(&zipPath, "zip", "", "Take runs from folders inside this ZIP archive (read in place, no extraction)")
+	cmd.Flags().StringVar(&extractTo, "extract-to", "", "With --zip, extract the matching runs to this directory instead")
 
 	cmd.MarkFlagRequired("template")
 	cmd.MarkFlagRequired("pattern")
@@ -419,20 +469,47 @@ Examples:
 // newPlanCmd creates the 'plan' command.
 func newPlanCmd() *cobra.Command {
 	var jobsCSV string
+	var jobsJSON string
 	var validateCoretype bool
+	var strictValidation bool
+	var namePolicy string
+	var showContents bool
+	var stateFile string
+	var multiPart bool
 
 	cmd := &cobra.Command{
 		Use:   "plan",
 		Short: "Plan and validate job pipeline",
 		Long: `Analyze and validate the job pipeline without executing it.
 
+Commands, names and tar subpaths are screened for path traversal and
+command injection. Traversal and NUL bytes always fail; other suspicious
+constructs (unbalanced quotes, control characters, line breaks, command
+substitution) are warnings unless --strict-validation is set or the config
+has job_validation_mode=strict.
+
+Duplicate job names within the CSV (and, with --validate-coretype, among
+your jobs from the last 30 days) are reported according to --name-policy
+or job_name_policy: warn (default), suffix (renamed when run) or block.
+
+With --validate-coretype, each job's walltime and cores are also compared
+with how long earlier jobs from the same template (same software, tags and
+metadata keys) actually ran. A walltime below their 90th percentile runtime
+is a warning with a suggested walltime.
+
+--show-contents lists the files each job's input tar will contain, using the
+include/exclude/flatten settings from the config, so missing include files
+show up before any compute is spent. With --state, jobs whose tar stage has
+already succeeded list their actual archives instead.
+
 Example:
-  rescale-int pur plan --jobs-csv jobs.csv --validate-coretype`,
+  rescale-int pur plan --jobs-csv jobs.csv --validate-coretype
+  rescale-int pur plan --jobs-csv jobs.csv --show-contents`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
-			if jobsCSV == "" {
-				return fmt.Errorf("--jobs-csv is required")
+			if jobsCSV == "" && jobsJSON == "" {
+				return fmt.Errorf("--jobs-csv or --jobs-json is required")
 			}
 
 			logger.Info().Str("jobs", jobsCSV).Msg("Planning job pipeline")
@@ -444,51 +521,90 @@ Example:
 			}
 
 			// Load jobs
-			jobs, err := config.LoadJobsCSV(jobsCSV)
+			jobs, err := loadJobsInput(jobsCSV, jobsJSON, os.Stdin)
 			if err != nil {
-				return fmt.Errorf("failed to load jobs CSV: %w", err)
+				return fmt.Errorf("failed to load jobs: %w", err)
 			}
 
 			logger.Info().Int("count", len(jobs)).Msg("Loaded jobs")
 
-			// Validate core types if requested
+			validationMode := cfg.JobValidationMode
+			if strictValidation {
+				validationMode = validation.SafetyStrict
+			}
+			if namePolicy == "" {
+				namePolicy = cfg.JobNamePolicy
+			}
+			var recentNames map[string][]string
+			var predictor *runtimes.Predictor
+			var catalog *validation.Catalog
+
+			// Platform checks if requested: core types, analyses and projects
+			// are fetched once and shared by every job
 			if validateCoretype {
 				apiClient, err := api.NewClient(cfg)
 				if err != nil {
 					return fmt.Errorf("failed to create API client: %w", err)
 				}
-
-				validator := validation.NewCoreTypeValidator(apiClient)
 				ctx := GetContext()
 
-				// Fetch available core types
-				if err := validator.FetchCoreTypes(ctx); err != nil {
-					return fmt.Errorf("failed to fetch core types: %w", err)
+				var warnings []string
+				catalog, warnings = validation.LoadCatalog(ctx, apiClient, cfg.OrgCode)
+				for _, w := range warnings {
+					logger.Warn().Msg(w)
 				}
 
-				// Validate each job's core type
-				for i, job := range jobs {
-					if err := validator.Validate(job.CoreType); err != nil {
-						return fmt.Errorf("job %s (index %d): %w", job.JobName, i+1, err)
-					}
-					logger.Info().
-						Int("index", i+1).
-						Str("name", job.JobName).
-						Str("coretype", job.CoreType).
-						Msg("Core type validated")
+				// Names already used by recent jobs
+				recentNames, err = validation.RecentJobNames(ctx, apiClient, constants.JobNameRecentWindow)
+				if err != nil {
+					logger.Warn().Err(err).Msg("Could not check recent job names")
+				}
+
+				predictor = runtimes.NewPredictor(config.GetJobHistoryPath(cfg.APIBaseURL), apiClient)
+			}
+
+			// Duplicate names are per-row errors only under the block policy
+			nameProblems := make(map[int][]string)
+			for _, c := range validation.FindNameCollisions(jobs, recentNames) {
+				msg := c.String()
+				if validation.NormalizeNamePolicy(namePolicy) == validation.NamePolicySuffix {
+					msg += ", later copies renamed when run"
+				}
+				for _, row := range c.Rows {
+					nameProblems[row] = append(nameProblems[row], msg)
 				}
 			}
+			blockNames := validation.NormalizeNamePolicy(namePolicy) == validation.NamePolicyBlock
+			_, dependencyProblems := validation.ResolveDependencies(jobs)
+
+			var stateMgr *state.Manager
+			if stateFile != "" {
+				stateMgr = state.NewManager(stateFile)
+				if err := stateMgr.Load(); err != nil {
+					return fmt.Errorf("failed to load state: %w", err)
+				}
+			}
+
+			// Same parallel validation as the GUI plan; results in CSV order
+			results := validation.ValidateJobs(jobs, validationMode, catalog, checkPlanDirectory, nil)
 
 			hasErrors := false
 			for i, job := range jobs {
-				errs := validation.ValidateJobSpec(job)
+				errs, warnings := results[i].Errors, results[i].Warnings
+				errs = append(errs, dependencyProblems[i]...)
+				if blockNames {
+					errs = append(errs, nameProblems[i+1]...)
+				} else {
+					warnings = append(warnings, nameProblems[i+1]...)
+				}
 
-				// Also check directory exists (warning, not fatal)
-				if _, err := os.Stat(job.Directory); os.IsNotExist(err) {
-					logger.Warn().
-						Str("job", job.JobName).
-						Str("dir", job.Directory).
-						Msg("Directory does not exist")
+				// Walltime and cores against earlier jobs of the same template
+				if predictor != nil {
+					prediction, err := predictor.Predict(GetContext(), job)
+					if err != nil {
+						logger.Warn().Err(err).Msg("Could not read job history")
+					}
+					warnings = append(warnings, runtimes.Warnings(job, prediction)...)
 				}
 
 				if len(errs) > 0 {
@@ -500,6 +616,22 @@ Example:
 				} else {
 					fmt.Printf("[%d/%d] ✓ %s\n", i+1, len(jobs), job.JobName)
 				}
+				for _, w := range warnings {
+					fmt.Printf("        ⚠ %s\n", w)
+				}
+				var st *models.JobState
+				if stateMgr != nil {
+					st = stateMgr.GetState(i + 1)
+				}
+				if st != nil {
+					// Warnings the platform returned when the job was created
+					for _, w := range st.WarningList() {
+						fmt.Printf("        ⚠ platform: %s\n", w)
+					}
+				}
+				if showContents {
+					printJobContents(cfg, job, st, multiPart)
+				}
 			}
 
 			if hasErrors {
@@ -512,43 +644,129 @@ Example:
 		},
 	}
 
-	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file (required)")
+	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file, or - to read CSV or JSON from stdin")
+	cmd.Flags().StringVar(&jobsJSON, "jobs-json", "", "Inline JSON job list (array or single job) instead of --jobs-csv")
 	cmd.Flags().BoolVar(&validateCoretype, "validate-coretype", false, "Validate core type with Rescale API")
+	cmd.Flags().BoolVar(&strictValidation, "strict-validation", false, "Treat suspicious commands and names as errors (overrides job_validation_mode)")
+	cmd.Flags().StringVar(&namePolicy, "name-policy", "", "Duplicate job names: warn, suffix or block (overrides job_name_policy)")
+	cmd.Flags().BoolVar(&showContents, "show-contents", false, "List the files in each job's input tar")
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file of a run: shows warnings the platform returned for created jobs, and jobs already tarred list their actual archives (with --show-contents)")
+	cmd.Flags().BoolVar(&multiPart, "multipart", false, "Show entry names as archived in multi-part mode (with --show-contents)")
 
-	cmd.MarkFlagRequired("jobs-csv")
+	cmd.MarkFlagsOneRequired("jobs-csv", "jobs-json")
+	cmd.MarkFlagsMutuallyExclusive("jobs-csv", "jobs-json")
 
 	return cmd
 }
 
+// checkPlanDirectory warns when a job's directory does not exist. Not an
+// error for pur plan: the directory may be created before the run.
+func checkPlanDirectory(job models.JobSpec) (errs, warnings []string) {
+	if _, err := os.Stat(job.Directory); os.IsNotExist(err) {
+		return nil, []string{fmt.Sprintf("directory does not exist: %s", job.Directory)}
+	}
+	return nil, nil
+}
+
+// printJobContents prints the files in a job's input tar beneath its plan
+// line: the existing archive when the tar stage has run, otherwise a preview.
+func printJobContents(cfg *config.Config, job models.JobSpec, st *models.JobState, multiPart bool) {
+	entries, fromArchive, err := pipeline.JobContents(cfg, job, st, multiPart)
+	if err != nil {
+		fmt.Printf("        ⚠ cannot list tar contents: %v\n", err)
+		return
+	}
+
+	source := "preview"
+	if fromArchive {
+		source = "archive"
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	fmt.Printf("        Tar contents (%s, %d files, %s):\n", source, len(entries), cloud.FormatBytes(total))
+	if len(entries) == 0 {
+		fmt.Println("          (empty: no files match the include/exclude patterns)")
+	}
+	for _, e := range entries {
+		fmt.Printf("          %10s  %s\n", cloud.FormatBytes(e.Size), e.Name)
+	}
+}
+
 // newRunCmd creates the 'run' command.
 func newRunCmd() *cobra.Command {
 	var jobsCSV string
+	var jobsJSON string
 	var stateFile string
 	var multiPart bool
 	var includePatterns []string
 	var excludePatterns []string
 	var flattenTar bool
 	var tarCompression string
+	var tarSplit string
+	var tarSplitParts int
+	var textNormalize string
 	var tarWorkers int
 	var uploadWorkers int
 	var jobWorkers int
 	var rmTarOnSuccess bool
+	var failOnWarning bool
 	var extraInputFiles string
 	var decompressExtras bool
 	var dryRun bool
+	var reportOut string
+	var settleSeconds int
+	var settleTimeout int
+	var namePolicy string
+	var force bool
+	var retentionFile string
 
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the job pipeline",
 		Long: `Execute the complete job pipeline: tar → upload → submit.
 
+Before a new run starts, job names are checked for duplicates within the CSV
+and among your jobs from the last 30 days. --name-policy (or job_name_policy)
+decides what happens: warn (default), suffix (append the state file name, or
+a timestamp without --state) or block. Resumed runs are not checked.
+
+A new run is also fingerprinted (job fields plus the name, size and
+modification time of every input file) and compared with the runs started
+from this machine in the last 14 days. A match is reported with the earlier
+run's ID and status; with duplicate_run_policy=block the run is refused
+unless --force is given.
+
+--tar-split (or tar_split_mode) archives each run directory as several tars:
+one per top-level subdirectory ("subdirs") or --tar-split-parts tars of
+similar size ("size"). The parts are uploaded in parallel, all attached to
+the job and decompressed on the cluster into the original layout.
+
+--text-normalize (or text_normalize) checks text inputs with the extensions in
+text_normalize_extensions for Windows CRLF line endings and UTF-8 BOMs:
+"check" reports them, "fix" also archives them with LF endings and no BOM.
+The files on disk are not changed; a NoTextNormalize column opts a job out.
+
+--jobs-csv - reads the job list from stdin as CSV or JSON (detected from the
+content), and --jobs-json takes a small JSON list inline, so generators can
+pipe straight into a run without a temp file.
+
+A recipe's retention policy, <jobs file>.retention.json next to the jobs CSV
+or --retention, is applied when the run ends: local tars deleted, state and
+run logs archived, uploaded inputs deleted after some days, outputs
+downloaded as jobs finish. See 'rescale-int runs retention'.
+
 Example:
-  rescale-int pur run --jobs-csv jobs.csv --state state.csv`,
+  rescale-int pur run --jobs-csv jobs.csv --state state.csv
+  rescale-int pur run --jobs-csv jobs.csv --tar-split size --tar-split-parts 8
+  generate-jobs | rescale-int pur run --jobs-csv - --state state.csv
+  rescale-int pur run --jobs-json '[{"Directory":"./Run_1","JobName":"Run_1",...}]'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := GetLogger()
 
-			if jobsCSV == "" {
-				return fmt.Errorf("--jobs-csv is required")
+			if jobsCSV == "" && jobsJSON == "" {
+				return fmt.Errorf("--jobs-csv or --jobs-json is required")
 			}
 
 			logger.Info().
@@ -575,6 +793,21 @@ Example:
 			if cmd.Flags().Changed("tar-compression") {
 				cfg.TarCompression = tarCompression
 			}
+			if cmd.Flags().Changed("tar-split") {
+				cfg.TarSplitMode = tarSplit
+			}
+			if cmd.Flags().Changed("tar-split-parts") && tarSplitParts >= 2 && tarSplitParts <= constants.MaxTarSplitParts {
+				cfg.TarSplitParts = tarSplitParts
+			}
+			if cmd.Flags().Changed("text-normalize") {
+				cfg.TextNormalize = textNormalize
+			}
+			if cmd.Flags().Changed("settle-seconds") && settleSeconds >= 0 {
+				cfg.SettleSeconds = settleSeconds
+			}
+			if cmd.Flags().Changed("settle-timeout") && settleTimeout >= 0 {
+				cfg.SettleTimeoutSeconds = settleTimeout
+			}
 			if cmd.Flags().Changed("tar-workers") && tarWorkers > 0 {
 				cfg.TarWorkers = tarWorkers
 			}
@@ -586,28 +819,62 @@ Example:
 			}
 
 			// Load jobs
-			jobs, err := config.LoadJobsCSV(jobsCSV)
+			jobs, err := loadJobsInput(jobsCSV, jobsJSON, os.Stdin)
 			if err != nil {
-				return fmt.Errorf("failed to load jobs CSV: %w", err)
+				return fmt.Errorf("failed to load jobs: %w", err)
 			}
 
 			logger.Info().Int("count", len(jobs)).Msg("Loaded jobs")
 
+			retentionPolicy, err := loadRetentionPolicy(retentionFile, jobsCSV)
+			if err != nil {
+				return err
+			}
+
 			if dryRun {
+				// Preview the archives, uploads and job requests. Analysis
+				// versions are looked up; nothing else touches the API.
+				previewClient, err := api.NewClient(cfg)
+				if err != nil {
+					previewClient = nil
+				}
+				pipe, err := pipeline.NewPipeline(cfg, previewClient, jobs, stateFile, multiPart, state.NewManager(stateFile), false, extraInputFiles, decompressExtras)
+				if err != nil {
+					return fmt.Errorf("failed to create pipeline: %w", err)
+				}
+				preview := pipe.Preview(GetContext())
+
 				fmt.Printf("\n=== DRY RUN: %d jobs loaded ===\n\n", len(jobs))
-				fmt.Printf("%-5s %-30s %-20s %-10s %-8s %s\n", "#", "Job Name", "Directory", "CoreType", "Hours", "Command (preview)")
-				fmt.Println(strings.Repeat("-", 110))
+				fmt.Printf("%-5s %-30s %-20s %-10s %-8s %-10s %s\n", "#", "Job Name", "Directory", "CoreType", "Hours", "Size", "Command (preview)")
+				fmt.Println(strings.Repeat("-", 121))
 				for i, job := range jobs {
 					cmdPreview := job.Command
 					if len(cmdPreview) > 40 {
 						cmdPreview = cmdPreview[:37] + "..."
 					}
 					dirPreview := filepath.Base(job.Directory)
-					fmt.Printf("%-5d %-30s %-20s %-10s %-8.1f %s\n",
-						i+1, job.JobName, dirPreview, job.CoreType, job.WalltimeHours, cmdPreview)
+					fmt.Printf("%-5d %-30s %-20s %-10s %-8.1f %-10s %s\n",
+						i+1, job.JobName, dirPreview, job.CoreType, job.WalltimeHours, cloud.FormatBytes(preview.Jobs[i].Bytes), cmdPreview)
+				}
+				for _, job := range preview.Jobs {
+					if job.Error != "" {
+						fmt.Printf("✗ %s: %s\n", job.JobName, job.Error)
+					}
+				}
+				fmt.Printf("\nTotal: %d jobs, %d files, %s to archive and upload\n", len(jobs), preview.TotalFiles, cloud.FormatBytes(preview.TotalBytes))
+
+				previewPath := reportOut
+				if previewPath == "" {
+					previewPath = pipeline.PreviewPath(stateFile)
+					if stateFile == "" {
+						previewPath = pipeline.PreviewPath("pur_run.state")
+					}
+				}
+				if err := preview.WriteJSON(previewPath); err != nil {
+					return err
 				}
-				fmt.Printf("\nTotal: %d jobs\n", len(jobs))
-				fmt.Println("\n(dry-run mode: no jobs were created or submitted)")
+				fmt.Printf("Preview with the job creation requests written to %s\n", previewPath)
+				fmt.Println("\n(dry-run mode: nothing was archived, uploaded, created or submitted)")
 				return nil
 			}
 
@@ -617,6 +884,26 @@ Example:
 				return fmt.Errorf("failed to create API client: %w", err)
 			}
 
+			// Resolve duplicate job names for a new run. An existing state
+			// file means a resume: its jobs are keyed by name and may already
+			// be on the platform.
+			var fingerprint string
+			_, statErr := os.Stat(stateFile)
+			resumed := statErr == nil
+			if os.IsNotExist(statErr) {
+				// Checked before names are changed by the name policy
+				fingerprint, err = checkDuplicateRun(cfg, jobs, force)
+				if err != nil {
+					return err
+				}
+				if namePolicy == "" {
+					namePolicy = cfg.JobNamePolicy
+				}
+				if err := applyNamePolicy(GetContext(), apiClient, jobs, namePolicy, stateFile); err != nil {
+					return err
+				}
+			}
+
 			// Create pipeline
 			pipe, err := pipeline.NewPipeline(cfg, apiClient, jobs, stateFile, multiPart, nil, false, extraInputFiles, decompressExtras)
 			if err != nil {
@@ -627,11 +914,28 @@ Example:
 			if rmTarOnSuccess {
 				pipe.SetRmTarOnSuccess(true)
 			}
+			pipe.SetFailOnWarning(failOnWarning)
+			attachPipelineEvents(pipe)
+			recordRun(cfg, fingerprint, stateFile, len(jobs))
+			writeReproBundle(cfg, jobs, repro.Options{
+				StateFile:        stateFile,
+				JobsSource:       jobsSource(jobsCSV, jobsJSON),
+				Resumed:          resumed,
+				MultiPart:        multiPart,
+				ExtraInputFiles:  extraInputFiles,
+				DecompressExtras: decompressExtras,
+			})
 
 			// Run pipeline
 			ctx := GetContext()
-			if err := pipe.Run(ctx); err != nil {
-				return fmt.Errorf("pipeline failed: %w", err)
+			runStart := time.Now()
+			runErr := pipe.Run(ctx)
+			reportPath := writePURReport(pipe, cfg, stateFile, reportOut, runStart)
+			publishPipelineComplete(pipe, runStart, reportPath)
+			applyRetention(ctx, cfg, apiClient, retentionPolicy, pipe, stateFile, reportPath)
+			enforceCacheLimits(cfg)
+			if runErr != nil {
+				return fmt.Errorf("pipeline failed: %w", runErr)
 			}
 
 			logger.Info().Msg("Pipeline completed successfully")
@@ -640,48 +944,172 @@ Example:
 		},
 	}
 
-	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file (required)")
+	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file, or - to read CSV or JSON from stdin")
+	cmd.Flags().StringVar(&jobsJSON, "jobs-json", "", "Inline JSON job list (array or single job) instead of --jobs-csv")
 	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file for resume capability")
 	cmd.Flags().BoolVar(&multiPart, "multipart", false, "Enable multi-part mode")
 	cmd.Flags().StringArrayVar(&includePatterns, "include-pattern", nil, "Only tar files matching glob pattern (can repeat)")
 	cmd.Flags().StringArrayVar(&excludePatterns, "exclude-pattern", nil, "Exclude files matching glob from tar (can repeat)")
 	cmd.Flags().BoolVar(&flattenTar, "flatten-tar", false, "Remove subdirectory structure in tarball")
 	cmd.Flags().StringVar(&tarCompression, "tar-compression", "", "Tar compression: 'none' or 'gzip' (default from config)")
+	cmd.Flags().StringVar(&tarSplit, "tar-split", "", "Split each run directory into several tars uploaded in parallel: 'none', 'subdirs' or 'size' (default from config)")
+	cmd.Flags().IntVar(&tarSplitParts, "tar-split-parts", 0, "Number of tars per job with --tar-split size (default from config)")
+	cmd.Flags().StringVar(&textNormalize, "text-normalize", "", "Line endings and BOMs of text inputs: 'off', 'check' or 'fix' (default from config)")
+	cmd.Flags().IntVar(&settleSeconds, "settle-seconds", 0, "Wait until input files are unchanged for this many seconds before tarring; 0 disables (default from config)")
+	cmd.Flags().IntVar(&settleTimeout, "settle-timeout", 0, "Fail a job if its input files are still changing after this many seconds (default from config)")
 	cmd.Flags().IntVar(&tarWorkers, "tar-workers", 0, "Number of parallel tar workers (default from config)")
 	cmd.Flags().IntVar(&uploadWorkers, "upload-workers", 0, "Number of parallel upload workers (default from config)")
 	cmd.Flags().IntVar(&jobWorkers, "job-workers", 0, "Number of parallel job creation workers (default from config)")
 	cmd.Flags().BoolVar(&rmTarOnSuccess, "rm-tar-on-success", false, "Delete local tar file after successful upload")
+	cmd.Flags().BoolVar(&failOnWarning, "fail-on-warning", false, "Leave jobs the platform created with warnings unsubmitted and mark them failed")
 	cmd.Flags().StringVar(&extraInputFiles, "extra-input-files", "", "Comma-separated local paths and/or id:<fileId> references to share across all jobs")
 	cmd.Flags().BoolVar(&decompressExtras, "decompress-extras", false, "Decompress extra input files on cluster")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show plan without executing")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview archives, uploads and job requests without executing")
+	cmd.Flags().StringVar(&reportOut, "report-out", "", "Write the HTML/JSON run report to this path (default: next to the state file)")
+	cmd.Flags().StringVar(&namePolicy, "name-policy", "", "Duplicate job names: warn, suffix or block (overrides job_name_policy)")
+	cmd.Flags().BoolVar(&force, "force", false, "Run even if duplicate_run_policy=block finds a recent run of the same jobs and inputs")
+	cmd.Flags().StringVar(&retentionFile, "retention", "", "Retention policy JSON applied after the run (default: <jobs file>.retention.json when present)")
+
+	cmd.MarkFlagsOneRequired("jobs-csv", "jobs-json")
+	cmd.MarkFlagsMutuallyExclusive("jobs-csv", "jobs-json")
 
-	cmd.MarkFlagRequired("jobs-csv")
+	cmd.AddCommand(newRetryFailedCmd())
 
 	return cmd
 }
 
+// loadJobsInput loads the jobs given by --jobs-csv or --jobs-json. A
+// --jobs-csv of "-" reads CSV or JSON from stdin, so job generators can pipe
+// straight into a run without a temp file. Array specs are expanded into
+// their members.
+func loadJobsInput(jobsCSV, jobsJSON string, stdin io.Reader) ([]models.JobSpec, error) {
+	var jobs []models.JobSpec
+	var err error
+	switch {
+	case jobsJSON != "":
+		jobs, err = config.ParseJobsJSON([]byte(jobsJSON))
+	case jobsCSV == "-":
+		jobs, err = config.ReadJobs(stdin)
+	default:
+		jobs, err = config.LoadJobsCSV(jobsCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jobarray.Expand(jobs)
+}
+
+// applyNamePolicy checks jobs for duplicate names within the run and, under
+// suffix and block, among the user's recent jobs, then warns, renames or
+// fails per policy (see validation.ApplyNamePolicy). Renames use the state
+// file's base name as the suffix. A failed recent-jobs lookup only loses
+// that half of the check.
+func applyNamePolicy(ctx context.Context, apiClient *api.Client, jobs []models.JobSpec, policy, stateFile string) error {
+	var recent map[string][]string
+	if validation.NormalizeNamePolicy(policy) != validation.NamePolicyWarn {
+		var err error
+		recent, err = validation.RecentJobNames(ctx, apiClient, constants.JobNameRecentWindow)
+		if err != nil {
+			GetLogger().Warn().Err(err).Msg("Could not check recent job names")
+		}
+	}
+	var runID string
+	if stateFile != "" {
+		runID = strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+	}
+	messages, err := validation.ApplyNamePolicy(jobs, validation.FindNameCollisions(jobs, recent), policy,
+		validation.NameSuffix(runID, time.Now()))
+	if err != nil {
+		return err
+	}
+	for _, m := range messages {
+		fmt.Printf("⚠ %s\n", m)
+	}
+	return nil
+}
+
+// checkDuplicateRun applies duplicate_run_policy to a new run: a recent run
+// of the same jobs and inputs is reported, or refused under block unless
+// force is set. Returns the run fingerprint for recordRun, or "" if it could
+// not be computed. A history that can't be read only skips the check.
+func checkDuplicateRun(cfg *config.Config, jobs []models.JobSpec, force bool) (string, error) {
+	fingerprint, err := runhistory.Fingerprint(jobs)
+	if err != nil {
+		GetLogger().Warn().Err(err).Msg("Could not fingerprint run; duplicate run check skipped")
+		return "", nil
+	}
+	warning, err := runhistory.Check(config.GetRunHistoryPath(cfg.APIBaseURL), fingerprint,
+		cfg.DuplicateRunPolicy, force, time.Now(), constants.DuplicateRunWindow)
+	if errors.Is(err, runhistory.ErrDuplicateRun) {
+		return "", fmt.Errorf("%w; use --force to run anyway", err)
+	}
+	if err != nil {
+		GetLogger().Warn().Err(err).Msg("Could not read run history; duplicate run check skipped")
+	} else if warning != "" {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	return fingerprint, nil
+}
+
+// recordRun adds a started run to the local run history. The run ID is the
+// state file's base name, or a timestamp without --state.
+func recordRun(cfg *config.Config, fingerprint, stateFile string, jobs int) {
+	if fingerprint == "" {
+		return
+	}
+	runID := "run_" + time.Now().Format("20060102-150405")
+	if stateFile != "" {
+		runID = strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+		if abs, err := filepath.Abs(stateFile); err == nil {
+			stateFile = abs
+		}
+	}
+	err := runhistory.Append(config.GetRunHistoryPath(cfg.APIBaseURL), runhistory.Entry{
+		Time:        time.Now(),
+		Fingerprint: fingerprint,
+		RunID:       runID,
+		StateFile:   stateFile,
+		Jobs:        jobs,
+	})
+	if err != nil {
+		GetLogger().Warn().Err(err).Msg("Failed to record run history")
+	}
+}
+
 // newResumeCmd creates the 'resume' command.
 func newResumeCmd() *cobra.Command {
 	var jobsCSV string
+	var jobsJSON string
 	var stateFile string
 	var multiPart bool
 	var includePatterns []string
 	var excludePatterns []string
 	var flattenTar bool
 	var tarCompression string
+	var tarSplit string
+	var tarSplitParts int
+	var textNormalize string
 	var tarWorkers int
 	var uploadWorkers int
 	var jobWorkers int
 	var rmTarOnSuccess bool
+	var failOnWarning bool
 	var extraInputFiles string
 	var decompressExtras bool
 	var dryRun bool
+	var reportOut string
+	var settleSeconds int
+	var settleTimeout int
+	var retentionFile string
 
 	cmd := &cobra.Command{
 		Use:   "resume",
 		Short: "Resume a previously interrupted pipeline",
 		Long: `Resume pipeline execution from saved state.
 
+The recipe's retention policy is applied when the resumed run ends, as for
+pur run.
+
 Example:
   rescale-int pur resume --jobs-csv jobs.csv --state state.csv`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -690,8 +1118,8 @@ Example:
 			if stateFile == "" {
 				return fmt.Errorf("--state is required")
 			}
-			if jobsCSV == "" {
-				return fmt.Errorf("--jobs-csv is required")
+			if jobsCSV == "" && jobsJSON == "" {
+				return fmt.Errorf("--jobs-csv or --jobs-json is required")
 			}
 
 			logger.Info().
@@ -723,6 +1151,21 @@ Example:
 			if cmd.Flags().Changed("tar-compression") {
 				cfg.TarCompression = tarCompression
 			}
+			if cmd.Flags().Changed("tar-split") {
+				cfg.TarSplitMode = tarSplit
+			}
+			if cmd.Flags().Changed("tar-split-parts") && tarSplitParts >= 2 && tarSplitParts <= constants.MaxTarSplitParts {
+				cfg.TarSplitParts = tarSplitParts
+			}
+			if cmd.Flags().Changed("text-normalize") {
+				cfg.TextNormalize = textNormalize
+			}
+			if cmd.Flags().Changed("settle-seconds") && settleSeconds >= 0 {
+				cfg.SettleSeconds = settleSeconds
+			}
+			if cmd.Flags().Changed("settle-timeout") && settleTimeout >= 0 {
+				cfg.SettleTimeoutSeconds = settleTimeout
+			}
 			if cmd.Flags().Changed("tar-workers") && tarWorkers > 0 {
 				cfg.TarWorkers = tarWorkers
 			}
@@ -734,13 +1177,18 @@ Example:
 			}
 
 			// Load jobs
-			jobs, err := config.LoadJobsCSV(jobsCSV)
+			jobs, err := loadJobsInput(jobsCSV, jobsJSON, os.Stdin)
 			if err != nil {
-				return fmt.Errorf("failed to load jobs CSV: %w", err)
+				return fmt.Errorf("failed to load jobs: %w", err)
 			}
 
 			logger.Info().Int("count", len(jobs)).Msg("Loaded jobs")
 
+			retentionPolicy, err := loadRetentionPolicy(retentionFile, jobsCSV)
+			if err != nil {
+				return err
+			}
+
 			if dryRun {
 				// Load state file to analyze what's remaining
 				stateMgr := state.NewManager(stateFile)
@@ -797,11 +1245,27 @@ Example:
 			if rmTarOnSuccess {
 				pipe.SetRmTarOnSuccess(true)
 			}
+			pipe.SetFailOnWarning(failOnWarning)
+			attachPipelineEvents(pipe)
+			writeReproBundle(cfg, jobs, repro.Options{
+				StateFile:        stateFile,
+				JobsSource:       jobsSource(jobsCSV, jobsJSON),
+				Resumed:          true,
+				MultiPart:        multiPart,
+				ExtraInputFiles:  extraInputFiles,
+				DecompressExtras: decompressExtras,
+			})
 
 			// Run pipeline (will resume from state)
 			ctx := GetContext()
-			if err := pipe.Run(ctx); err != nil {
-				return fmt.Errorf("pipeline failed: %w", err)
+			runStart := time.Now()
+			runErr := pipe.Run(ctx)
+			reportPath := writePURReport(pipe, cfg, stateFile, reportOut, runStart)
+			publishPipelineComplete(pipe, runStart, reportPath)
+			applyRetention(ctx, cfg, apiClient, retentionPolicy, pipe, stateFile, reportPath)
+			enforceCacheLimits(cfg)
+			if runErr != nil {
+				return fmt.Errorf("pipeline failed: %w", runErr)
 			}
 
 			logger.Info().Msg("Pipeline resumed and completed")
@@ -810,22 +1274,32 @@ Example:
 		},
 	}
 
-	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file (required)")
+	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file, or - to read CSV or JSON from stdin")
+	cmd.Flags().StringVar(&jobsJSON, "jobs-json", "", "Inline JSON job list (array or single job) instead of --jobs-csv")
 	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file (required)")
 	cmd.Flags().BoolVar(&multiPart, "multipart", false, "Enable multi-part mode")
 	cmd.Flags().StringArrayVar(&includePatterns, "include-pattern", nil, "Only tar files matching glob pattern (can repeat)")
 	cmd.Flags().StringArrayVar(&excludePatterns, "exclude-pattern", nil, "Exclude files matching glob from tar (can repeat)")
 	cmd.Flags().BoolVar(&flattenTar, "flatten-tar", false, "Remove subdirectory structure in tarball")
 	cmd.Flags().StringVar(&tarCompression, "tar-compression", "", "Tar compression: 'none' or 'gzip' (default from config)")
+	cmd.Flags().StringVar(&tarSplit, "tar-split", "", "Split each run directory into several tars uploaded in parallel: 'none', 'subdirs' or 'size' (default from config)")
+	cmd.Flags().IntVar(&tarSplitParts, "tar-split-parts", 0, "Number of tars per job with --tar-split size (default from config)")
+	cmd.Flags().StringVar(&textNormalize, "text-normalize", "", "Line endings and BOMs of text inputs: 'off', 'check' or 'fix' (default from config)")
+	cmd.Flags().IntVar(&settleSeconds, "settle-seconds", 0, "Wait until input files are unchanged for this many seconds before tarring; 0 disables (default from config)")
+	cmd.Flags().IntVar(&settleTimeout, "settle-timeout", 0, "Fail a job if its input files are still changing after this many seconds (default from config)")
 	cmd.Flags().IntVar(&tarWorkers, "tar-workers", 0, "Number of parallel tar workers (default from config)")
 	cmd.Flags().IntVar(&uploadWorkers, "upload-workers", 0, "Number of parallel upload workers (default from config)")
 	cmd.Flags().IntVar(&jobWorkers, "job-workers", 0, "Number of parallel job creation workers (default from config)")
 	cmd.Flags().BoolVar(&rmTarOnSuccess, "rm-tar-on-success", false, "Delete local tar file after successful upload")
+	cmd.Flags().BoolVar(&failOnWarning, "fail-on-warning", false, "Leave jobs the platform created with warnings unsubmitted and mark them failed")
 	cmd.Flags().StringVar(&extraInputFiles, "extra-input-files", "", "Comma-separated local paths and/or id:<fileId> references to share across all jobs")
 	cmd.Flags().BoolVar(&decompressExtras, "decompress-extras", false, "Decompress extra input files on cluster")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be resumed without executing")
+	cmd.Flags().StringVar(&reportOut, "report-out", "", "Write the HTML/JSON run report to this path (default: next to the state file)")
+	cmd.Flags().StringVar(&retentionFile, "retention", "", "Retention policy JSON applied after the run (default: <jobs file>.retention.json when present)")
 
-	cmd.MarkFlagRequired("jobs-csv")
+	cmd.MarkFlagsOneRequired("jobs-csv", "jobs-json")
+	cmd.MarkFlagsMutuallyExclusive("jobs-csv", "jobs-json")
 	cmd.MarkFlagRequired("state")
 
 	return cmd
@@ -934,11 +1408,16 @@ Example:
 				return fmt.Errorf("failed to create pipeline: %w", err)
 			}
 
+			attachPipelineEvents(pipe)
+
 			// Note: The existing pipeline.Run() will handle the submit-existing logic
 			// It checks if jobs have ExtraInputFileIDs and skips tar/upload accordingly
 			ctx := GetContext()
-			if err := pipe.Run(ctx); err != nil {
-				return fmt.Errorf("submit-existing failed: %w", err)
+			runStart := time.Now()
+			runErr := pipe.Run(ctx)
+			publishPipelineComplete(pipe, runStart, "")
+			if runErr != nil {
+				return fmt.Errorf("submit-existing failed: %w", runErr)
 			}
 
 			logger.Info().Msg("Submit-existing completed")
@@ -954,6 +1433,23 @@ Example:
 	return cmd
 }
 
+// loadLocalConfig loads the configuration file for commands that work on
+// local files only, so an API key is not required.
+func loadLocalConfig() (*config.Config, error) {
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = config.GetDefaultConfigPath()
+	}
+	cfg, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.MergeWithFlagsAndTokenFile(apiKey, tokenFile, apiBaseURL, "", "", 0)
+	securedelete.SetEnabled(cfg.SecureDelete)
+	viewer.SetEnabled(cfg.ViewerMode)
+	return cfg, nil
+}
+
 // loadConfig loads the configuration file.
 func loadConfig() (*config.Config, error) {
 	configPath := cfgFile
@@ -975,6 +1471,13 @@ func loadConfig() (*config.Config, error) {
 	// Priority: flags > token-file > environment > defaults
 	cfg.MergeWithFlagsAndTokenFile(apiKey, tokenFile, apiBaseURL, "", "", 0)
 
+	securedelete.SetEnabled(cfg.SecureDelete)
+	viewer.SetEnabled(cfg.ViewerMode)
+	resources.SetCPUBudget(cfg.CPUBudgetPercent, cfg.CPUReduceOnBattery)
+	if err := resources.SetBlackoutWindows(cfg.BlackoutWindows); err != nil {
+		return nil, fmt.Errorf("invalid blackout_windows: %w", err)
+	}
+
 	// Validate required fields
 	if cfg.APIKey == "" {
 		return nil, fmt.Errorf("API key is required (use --api-key flag, --token-file flag, or RESCALE_API_KEY env var)")
@@ -993,5 +1496,77 @@ func loadConfig() (*config.Config, error) {
 		}
 	}
 
+	if err := applyWorkspaceFlag(cfg); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// jobsSource describes where a run's jobs came from, for the reproducibility
+// bundle.
+func jobsSource(jobsCSV, jobsJSON string) string {
+	switch {
+	case jobsJSON != "":
+		return "inline JSON"
+	case jobsCSV == "-":
+		return "stdin"
+	}
+	return jobsCSV
+}
+
+// writeReproBundle records the run's reproducibility bundle next to the state
+// file (see 'runs show --repro'). Failures are logged, never fatal.
+func writeReproBundle(cfg *config.Config, jobs []models.JobSpec, opts repro.Options) {
+	if opts.StateFile == "" {
+		return
+	}
+	path := repro.DefaultPath(opts.StateFile)
+	if err := repro.New(cfg, jobs, opts).WriteJSON(path); err != nil {
+		GetLogger().Warn().Err(err).Msg("Failed to write reproducibility bundle")
+		return
+	}
+	GetLogger().Debug().Str("path", path).Msg("Wrote reproducibility bundle")
+}
+
+// writePURReport prints the run's network usage and writes the HTML and JSON
+// run summary after a pipeline run. Reports go to reportOut when set,
+// otherwise next to the state file. With neither, no report is written.
+// Failures are logged, never fatal.
+// Returns the HTML report path, or "" if none was written.
+func writePURReport(pipe *pipeline.Pipeline, cfg *config.Config, stateFile, reportOut string, start time.Time) string {
+	if usage := pipe.NetworkUsage(); usage.Total() > 0 {
+		fmt.Printf("Network: %s\n", usage)
+	}
+
+	var htmlPath, jsonPath string
+	switch {
+	case reportOut != "":
+		htmlPath, jsonPath = report.PathsFor(reportOut)
+	case stateFile != "":
+		htmlPath, jsonPath = report.DefaultPaths(stateFile)
+	default:
+		return ""
+	}
+
+	runID := strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+	if runID == "" {
+		runID = fmt.Sprintf("pur_%d", start.Unix())
+	}
+
+	rep := report.Build(pipe.StateManager().GetAllStates(), report.Options{
+		RunID:          runID,
+		StateFile:      stateFile,
+		PlatformURL:    cfg.APIBaseURL,
+		StartTime:      start,
+		EndTime:        time.Now(),
+		StageDurations: pipe.StageDurations(),
+		NetworkUsage:   pipe.NetworkUsage(),
+	})
+	if err := rep.WriteFiles(htmlPath, jsonPath); err != nil {
+		GetLogger().Warn().Err(err).Msg("Failed to write run report")
+		return ""
+	}
+	fmt.Printf("Run report: %s\n", htmlPath)
+	return htmlPath
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/util/filter"
+)
+
+// downloadManifestName is the resumable manifest written into --dest.
+const downloadManifestName = ".rescale-int-downloads.json"
+
+// jobCurrentStatusFn returns a job's current platform status. Test seam.
+var jobCurrentStatusFn = func(ctx context.Context, apiClient *api.Client, jobID string) (string, error) {
+	statuses, err := apiClient.GetJobStatuses(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	// Statuses are returned newest-first; first entry is current status
+	if len(statuses) == 0 {
+		return "", nil
+	}
+	return statuses[0].Status, nil
+}
+
+// downloadManifest records which jobs of a run have been fully downloaded,
+// so an interrupted `pur download-outputs` can be re-run and skip them.
+type downloadManifest struct {
+	StateFile string                           `json:"stateFile"`
+	Jobs      map[string]downloadManifestEntry `json:"jobs"`
+}
+
+type downloadManifestEntry struct {
+	JobName     string    `json:"jobName"`
+	Dir         string    `json:"dir"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// loadDownloadManifest reads the manifest in dest, returning an empty one if
+// none exists yet.
+func loadDownloadManifest(dest string) (*downloadManifest, error) {
+	m := &downloadManifest{Jobs: make(map[string]downloadManifestEntry)}
+	data, err := os.ReadFile(filepath.Join(dest, downloadManifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse download manifest: %w", err)
+	}
+	if m.Jobs == nil {
+		m.Jobs = make(map[string]downloadManifestEntry)
+	}
+	return m, nil
+}
+
+// save writes the manifest atomically (temp file + rename).
+func (m *downloadManifest) save(dest string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dest, downloadManifestName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write download manifest: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// runOutputDirs maps each submitted job to a destination directory relative
+// to --dest that mirrors the run layout: job directories are made relative
+// to their common parent, so /data/doe/run_1 and /data/doe/sub/run_2 become
+// run_1 and sub/run_2. Jobs without a usable directory fall back to their
+// job name. Clashing paths get the job ID appended.
+func runOutputDirs(states []*models.JobState) map[string]string {
+	var parents []string
+	for _, st := range states {
+		if st.JobID != "" && st.Directory != "" {
+			parents = append(parents, filepath.Dir(filepath.Clean(st.Directory)))
+		}
+	}
+	root := commonDir(parents)
+
+	dirs := make(map[string]string)
+	used := make(map[string]bool)
+	for _, st := range states {
+		if st.JobID == "" {
+			continue
+		}
+		rel := ""
+		if st.Directory != "" && root != "" {
+			if r, err := filepath.Rel(root, filepath.Clean(st.Directory)); err == nil && r != "." && !strings.HasPrefix(r, "..") {
+				rel = r
+			}
+		}
+		if rel == "" {
+			rel = config.SafeDirName(st.JobName)
+		}
+		if rel == "" {
+			rel = st.JobID
+		}
+		if used[rel] {
+			rel = rel + "_" + st.JobID
+		}
+		used[rel] = true
+		dirs[st.JobID] = rel
+	}
+	return dirs
+}
+
+// commonDir returns the deepest directory containing every path in dirs.
+func commonDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	root := dirs[0]
+	for _, d := range dirs[1:] {
+		for root != d && !strings.HasPrefix(d, root+string(filepath.Separator)) {
+			parent := filepath.Dir(root)
+			if parent == root {
+				break
+			}
+			root = parent
+		}
+	}
+	return root
+}
+
+// newDownloadOutputsCmd creates the 'pur download-outputs' command.
+func newDownloadOutputsCmd() *cobra.Command {
+	var stateFile string
+	var dest string
+	var maxConcurrent int
+	var overwriteAll bool
+	var skipChecksum bool
+	var filterPatterns string
+	var excludePatterns string
+	var searchTerms string
+	var pathFilterPatterns string
+
+	cmd := &cobra.Command{
+		Use:   "download-outputs",
+		Short: "Download outputs of every completed job in a run",
+		Long: `Download the output files of every completed job recorded in a PUR state file.
+
+Each job's outputs go into its own directory under --dest, mirroring the
+run's original directory layout (job directories relative to their common
+parent). Jobs without a recorded directory use the job name.
+
+Progress is recorded in ` + downloadManifestName + ` inside --dest. Re-running
+the command skips jobs already downloaded and skips existing files of a
+partially downloaded job, so an interrupted download can simply be repeated.
+Jobs that are not yet Completed are reported and skipped.
+
+Examples:
+  rescale-int pur download-outputs --state run.state --dest ./results
+
+  # Only result files, 8 files at a time
+  rescale-int pur download-outputs --state run.state --dest ./results --filter "*.csv,*.dat" -m 8`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			if stateFile == "" {
+				return fmt.Errorf("--state is required")
+			}
+			if _, err := os.Stat(stateFile); os.IsNotExist(err) {
+				return fmt.Errorf("state file does not exist: %s", stateFile)
+			}
+
+			stateMgr := state.NewManager(stateFile)
+			if err := stateMgr.Load(); err != nil {
+				return fmt.Errorf("failed to load state: %w", err)
+			}
+			states := stateMgr.GetAllStates()
+			outDirs := runOutputDirs(states)
+			if len(outDirs) == 0 {
+				fmt.Println("No submitted jobs in state file")
+				return nil
+			}
+
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return fmt.Errorf("failed to create destination directory: %w", err)
+			}
+			manifest, err := loadDownloadManifest(dest)
+			if err != nil {
+				return err
+			}
+			manifest.StateFile = stateFile
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+			ctx := GetContext()
+
+			filterList := filter.ParsePatternList(filterPatterns)
+			excludeList := filter.ParsePatternList(excludePatterns)
+			searchList := filter.ParsePatternList(searchTerms)
+			pathFilterList := filter.ParsePatternList(pathFilterPatterns)
+
+			var downloaded, alreadyDone, notCompleted, failed int
+			for _, st := range states {
+				rel, ok := outDirs[st.JobID]
+				if !ok {
+					continue
+				}
+				if _, done := manifest.Jobs[st.JobID]; done {
+					alreadyDone++
+					continue
+				}
+
+				status, err := jobCurrentStatusFn(ctx, apiClient, st.JobID)
+				if err != nil {
+					logger.Warn().Err(err).Str("job_id", st.JobID).Msg("Failed to get job status")
+					fmt.Printf("✗ %s (%s): failed to get status: %v\n", st.JobName, st.JobID, err)
+					failed++
+					continue
+				}
+				if status != "Completed" {
+					fmt.Printf("⊘ %s (%s): status %q, skipping\n", st.JobName, st.JobID, status)
+					notCompleted++
+					continue
+				}
+
+				fmt.Printf("\n=== %s (%s) → %s ===\n", st.JobName, st.JobID, rel)
+				outputDir := filepath.Join(dest, rel)
+				// Skip existing files so a partially downloaded job resumes
+				// where it stopped, unless --overwrite was given.
+				err = executeJobDownload(ctx, st.JobID, outputDir, maxConcurrent, overwriteAll, !overwriteAll, false, skipChecksum, false,
+					filterList, excludeList, searchList, pathFilterList, apiClient, logger)
+				if err != nil {
+					fmt.Printf("✗ %s (%s): %v\n", st.JobName, st.JobID, err)
+					failed++
+					if ctx.Err() != nil {
+						break
+					}
+					continue
+				}
+
+				manifest.Jobs[st.JobID] = downloadManifestEntry{JobName: st.JobName, Dir: rel, CompletedAt: time.Now()}
+				if err := manifest.save(dest); err != nil {
+					return err
+				}
+				downloaded++
+			}
+
+			fmt.Printf("\nDownloaded: %d, already downloaded: %d, not completed: %d, failed: %d\n",
+				downloaded, alreadyDone, notCompleted, failed)
+			if failed > 0 {
+				return fmt.Errorf("%d job(s) failed to download; re-run to retry", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file of the run (required)")
+	cmd.Flags().StringVarP(&dest, "dest", "d", ".", "Destination directory")
+	cmd.Flags().IntVarP(&maxConcurrent, "max-concurrent", "m", constants.DefaultMaxConcurrent,
+		fmt.Sprintf("Maximum concurrent file downloads per job (%d-%d)", constants.MinMaxConcurrent, constants.MaxMaxConcurrent))
+	cmd.Flags().BoolVarP(&overwriteAll, "overwrite", "w", false, "Overwrite existing files instead of skipping them")
+	cmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip checksum verification (not recommended, allows corrupted downloads)")
+	cmd.Flags().StringVar(&filterPatterns, "filter", "", "Include only files matching these patterns (comma-separated glob patterns, e.g. \"*.dat,*.log\")")
+	cmd.Flags().StringVarP(&excludePatterns, "exclude", "x", "", "Exclude files matching these patterns (comma-separated glob patterns)")
+	cmd.Flags().StringVar(&searchTerms, "search", "", "Include only files containing these terms in filename (comma-separated, case-insensitive)")
+	cmd.Flags().StringVar(&pathFilterPatterns, "path-filter", "", "Include only files matching these path patterns (supports ** for recursive matching)")
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestRunOutputDirs_MirrorsLayout(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "data", "doe")
+	states := []*models.JobState{
+		{JobName: "Run_1", Directory: filepath.Join(root, "Run_1"), JobID: "aaa"},
+		{JobName: "Run_2", Directory: filepath.Join(root, "sub", "Run_2"), JobID: "bbb"},
+		{JobName: "pending", Directory: filepath.Join(root, "Run_3")},
+		{JobName: "no/dir", JobID: "ccc"},
+		{JobName: "Run_1", JobID: "ddd"},
+	}
+
+	got := runOutputDirs(states)
+	want := map[string]string{
+		"aaa": "Run_1",
+		"bbb": filepath.Join("sub", "Run_2"),
+		"ccc": "no_dir",
+		"ddd": "Run_1_ddd",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("runOutputDirs() = %v, want %v", got, want)
+	}
+	for id, dir := range want {
+		if got[id] != dir {
+			t.Errorf("dir for %s = %q, want %q", id, got[id], dir)
+		}
+	}
+}
+
+func TestRunOutputDirs_SingleJob(t *testing.T) {
+	states := []*models.JobState{
+		{JobName: "Run_1", Directory: filepath.Join("runs", "Run_1"), JobID: "aaa"},
+	}
+	if got := runOutputDirs(states)["aaa"]; got != "Run_1" {
+		t.Errorf("dir = %q, want Run_1", got)
+	}
+}
+
+func TestDownloadManifest_RoundTrip(t *testing.T) {
+	dest := t.TempDir()
+
+	m, err := loadDownloadManifest(dest)
+	if err != nil {
+		t.Fatalf("loadDownloadManifest() on empty dir: %v", err)
+	}
+	if len(m.Jobs) != 0 {
+		t.Fatalf("new manifest has %d jobs", len(m.Jobs))
+	}
+
+	m.StateFile = "run.state"
+	m.Jobs["aaa"] = downloadManifestEntry{JobName: "Run_1", Dir: "Run_1", CompletedAt: time.Now()}
+	if err := m.save(dest); err != nil {
+		t.Fatalf("save() error: %v", err)
+	}
+
+	loaded, err := loadDownloadManifest(dest)
+	if err != nil {
+		t.Fatalf("loadDownloadManifest() error: %v", err)
+	}
+	if loaded.StateFile != "run.state" || loaded.Jobs["aaa"].Dir != "Run_1" {
+		t.Errorf("loaded manifest = %+v", loaded)
+	}
+}
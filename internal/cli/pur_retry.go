@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/repro"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+// newRetryFailedCmd creates the 'pur run retry-failed' command.
+func newRetryFailedCmd() *cobra.Command {
+	var jobsCSV string
+	var jobsJSON string
+	var stateFile string
+	var multiPart bool
+	var tarWorkers int
+	var uploadWorkers int
+	var jobWorkers int
+	var rmTarOnSuccess bool
+	var failOnWarning bool
+	var extraInputFiles string
+	var decompressExtras bool
+	var dryRun bool
+	var reportOut string
+
+	cmd := &cobra.Command{
+		Use:   "retry-failed",
+		Short: "Re-run only the failed jobs of a run",
+		Long: `Re-run only the jobs of a run whose tar, upload or submit failed.
+
+Each failed job's row in the state file is set back to pending from the stage
+that failed, keeping what it finished before (its tar, uploaded files or the
+created job), and only those jobs go through the pipeline again. Jobs that
+succeeded keep their rows and are not touched, and jobs the run never reached
+are left for 'pur resume'. Use the jobs CSV the run was started with.
+
+Example:
+  rescale-int pur run retry-failed --jobs-csv jobs.csv --state state.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			if _, err := os.Stat(stateFile); os.IsNotExist(err) {
+				return fmt.Errorf("state file does not exist: %s", stateFile)
+			}
+
+			jobs, err := loadJobsInput(jobsCSV, jobsJSON, os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to load jobs: %w", err)
+			}
+
+			stateMgr := state.NewManager(stateFile)
+			if err := stateMgr.Load(); err != nil {
+				return fmt.Errorf("failed to load state: %w", err)
+			}
+
+			if dryRun {
+				fmt.Printf("\n=== DRY RUN: Failed Jobs ===\n\n")
+				failed := 0
+				for _, st := range stateMgr.GetAllStates() {
+					stage := failedStage(st.TarStatus, st.UploadStatus, st.SubmitStatus)
+					if stage == "" {
+						continue
+					}
+					failed++
+					fmt.Printf("%-30s %-7s %s\n", st.JobName, stage, st.ErrorMessage)
+				}
+				fmt.Printf("\nJobs to retry: %d of %d\n", failed, len(jobs))
+				fmt.Println("\n(dry-run mode: no work was performed)")
+				return nil
+			}
+
+			cfg, err := loadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			if cmd.Flags().Changed("tar-workers") && tarWorkers > 0 {
+				cfg.TarWorkers = tarWorkers
+			}
+			if cmd.Flags().Changed("upload-workers") && uploadWorkers > 0 {
+				cfg.UploadWorkers = uploadWorkers
+			}
+			if cmd.Flags().Changed("job-workers") && jobWorkers > 0 {
+				cfg.JobWorkers = jobWorkers
+			}
+
+			// Hold the run's lock across the reset so a live run isn't
+			// overwritten; the pipeline reuses it
+			if err := stateMgr.AcquireLock(); err != nil {
+				return fmt.Errorf("cannot retry run: %w", err)
+			}
+			defer stateMgr.ReleaseLock()
+
+			failed, err := stateMgr.ResetFailed()
+			if err != nil {
+				return fmt.Errorf("failed to reset failed jobs: %w", err)
+			}
+			if len(failed) == 0 {
+				fmt.Println("No failed jobs to retry")
+				return nil
+			}
+
+			logger.Info().
+				Str("state", stateFile).
+				Int("count", len(failed)).
+				Msg("Retrying failed jobs")
+
+			apiClient, err := api.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create API client: %w", err)
+			}
+
+			pipe, err := pipeline.NewPipeline(cfg, apiClient, jobs, stateFile, multiPart, stateMgr, false, extraInputFiles, decompressExtras)
+			if err != nil {
+				return fmt.Errorf("failed to create pipeline: %w", err)
+			}
+			pipe.SetJobFilter(failed)
+			if rmTarOnSuccess {
+				pipe.SetRmTarOnSuccess(true)
+			}
+			pipe.SetFailOnWarning(failOnWarning)
+			attachPipelineEvents(pipe)
+			writeReproBundle(cfg, jobs, repro.Options{
+				StateFile:        stateFile,
+				JobsSource:       jobsSource(jobsCSV, jobsJSON),
+				Resumed:          true,
+				MultiPart:        multiPart,
+				ExtraInputFiles:  extraInputFiles,
+				DecompressExtras: decompressExtras,
+			})
+
+			ctx := GetContext()
+			runStart := time.Now()
+			runErr := pipe.Run(ctx)
+			reportPath := writePURReport(pipe, cfg, stateFile, reportOut, runStart)
+			publishPipelineComplete(pipe, runStart, reportPath)
+			enforceCacheLimits(cfg)
+			if runErr != nil {
+				return fmt.Errorf("pipeline failed: %w", runErr)
+			}
+
+			logger.Info().Msg("Failed jobs retried")
+			fmt.Printf("\n✓ Retried %d failed jobs\n", len(failed))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&jobsCSV, "jobs-csv", "j", "", "Jobs CSV file the run was started with, or - to read CSV or JSON from stdin")
+	cmd.Flags().StringVar(&jobsJSON, "jobs-json", "", "Inline JSON job list (array or single job) instead of --jobs-csv")
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file of the run (required)")
+	cmd.Flags().BoolVar(&multiPart, "multipart", false, "Enable multi-part mode")
+	cmd.Flags().IntVar(&tarWorkers, "tar-workers", 0, "Number of parallel tar workers (default from config)")
+	cmd.Flags().IntVar(&uploadWorkers, "upload-workers", 0, "Number of parallel upload workers (default from config)")
+	cmd.Flags().IntVar(&jobWorkers, "job-workers", 0, "Number of parallel job creation workers (default from config)")
+	cmd.Flags().BoolVar(&rmTarOnSuccess, "rm-tar-on-success", false, "Delete local tar file after successful upload")
+	cmd.Flags().BoolVar(&failOnWarning, "fail-on-warning", false, "Leave jobs the platform created with warnings unsubmitted and mark them failed")
+	cmd.Flags().StringVar(&extraInputFiles, "extra-input-files", "", "Comma-separated local paths and/or id:<fileId> references to share across all jobs")
+	cmd.Flags().BoolVar(&decompressExtras, "decompress-extras", false, "Decompress extra input files on cluster")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the failed jobs without retrying them")
+	cmd.Flags().StringVar(&reportOut, "report-out", "", "Write the HTML/JSON run report to this path (default: next to the state file)")
+
+	cmd.MarkFlagsOneRequired("jobs-csv", "jobs-json")
+	cmd.MarkFlagsMutuallyExclusive("jobs-csv", "jobs-json")
+	cmd.MarkFlagRequired("state")
+
+	return cmd
+}
+
+// failedStage returns the first stage ("tar", "upload" or "submit") whose
+// status is failed, or "" if none is.
+func failedStage(tarStatus, uploadStatus, submitStatus string) string {
+	switch {
+	case tarStatus == "failed":
+		return "tar"
+	case uploadStatus == "failed":
+		return "upload"
+	case submitStatus == "failed":
+		return "submit"
+	}
+	return ""
+}
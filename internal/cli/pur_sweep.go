@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/pur/sweep"
+)
+
+// newSweepCmd creates the 'pur sweep' command.
+func newSweepCmd() *cobra.Command {
+	var templatePath string
+	var paramsPath string
+	var outputPath string
+	var overwrite bool
+	var startIndex int
+
+	cmd := &cobra.Command{
+		Use:   "sweep",
+		Short: "Generate jobs CSV from a parameter table",
+		Long: `Generate a jobs CSV file with one job per row of a parameter table.
+
+Use this for a parameter study where every job reads the same input directory
+and only the command differs, instead of preparing one directory per job. The
+first row of the template CSV is expanded once per row of --params, a CSV file
+whose header names the variables or a JSON array of objects. Every {{name}}
+in the template's JobName and Command is replaced by the row's value, and
+{{index}} by the job number (counted from --start-index). A JobName without
+placeholders is numbered like make-dirs-csv: "wing_1" becomes wing_1, wing_2, ...
+
+Examples:
+  rescale-int pur sweep --template template.csv --params params.csv --output jobs.csv
+
+  # template.csv: JobName "wing_M{{mach}}_A{{aoa}}", Command "./run.sh --mach {{mach}} --aoa {{aoa}}"
+  # params.csv:
+  #   mach,aoa
+  #   0.3,2
+  #   0.5,4`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := GetLogger()
+
+			// Load template
+			templateJobs, err := config.LoadJobsCSV(templatePath)
+			if err != nil {
+				return fmt.Errorf("failed to load template: %w", err)
+			}
+			if len(templateJobs) == 0 {
+				return fmt.Errorf("template CSV is empty")
+			}
+
+			table, err := sweep.LoadTable(paramsPath)
+			if err != nil {
+				return err
+			}
+
+			// Check if output exists
+			if !overwrite {
+				if _, err := os.Stat(outputPath); err == nil {
+					return fmt.Errorf("output file %s already exists (use --overwrite to replace)", outputPath)
+				}
+			}
+
+			logger.Info().
+				Str("template", templatePath).
+				Str("params", paramsPath).
+				Str("output", outputPath).
+				Int("rows", len(table.Rows)).
+				Str("variables", strings.Join(table.Names, ",")).
+				Msg("Generating jobs CSV from parameter table")
+
+			jobs, err := sweep.Expand(templateJobs[0], table, startIndex)
+			if err != nil {
+				return fmt.Errorf("parameter sweep failed: %w", err)
+			}
+
+			if err := config.SaveJobsCSV(outputPath, jobs); err != nil {
+				return fmt.Errorf("failed to save jobs CSV: %w", err)
+			}
+
+			logger.Info().
+				Int("count", len(jobs)).
+				Str("output", outputPath).
+				Msg("Jobs CSV generated successfully")
+
+			fmt.Printf("Generated %d jobs in %s\n", len(jobs), outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&templatePath, "template", "t", "", "Template CSV file (required)")
+	cmd.Flags().StringVar(&paramsPath, "params", "", "Parameter table, CSV with a header row or JSON array of objects (required)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output jobs CSV file (required)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing output file")
+	cmd.Flags().IntVar(&startIndex, "start-index", 1, "Starting index for job numbering")
+
+	cmd.MarkFlagRequired("template")
+	cmd.MarkFlagRequired("params")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
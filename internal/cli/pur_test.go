@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadJobsInput_Stdin(t *testing.T) {
+	stdin := strings.NewReader(`[{"JobName": "Piped1"}, {"JobName": "Piped2"}]`)
+
+	jobs, err := loadJobsInput("-", "", stdin)
+	if err != nil {
+		t.Fatalf("loadJobsInput() error = %v", err)
+	}
+	if len(jobs) != 2 || jobs[1].JobName != "Piped2" {
+		t.Errorf("loadJobsInput() = %+v, want Piped1 and Piped2", jobs)
+	}
+}
+
+func TestLoadJobsInput_InlineJSON(t *testing.T) {
+	// Stdin is not read when the job list is inline
+	jobs, err := loadJobsInput("", `{"JobName": "Inline", "AnalysisCode": "user_included"}`, strings.NewReader("not jobs"))
+	if err != nil {
+		t.Fatalf("loadJobsInput() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].JobName != "Inline" {
+		t.Errorf("loadJobsInput() = %+v, want one job named Inline", jobs)
+	}
+
+	if _, err := loadJobsInput("", `[{"JobName": `, nil); err == nil {
+		t.Error("Expected error for malformed inline JSON, got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/util/multipart"
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+// newPURZipCmd creates the 'pur zip' command group.
+func newPURZipCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "zip",
+		Short: "Use a study delivered as a ZIP archive as run source",
+		Long: `Work with studies delivered as one large ZIP archive without extracting it
+first.
+
+A jobs CSV row whose Directory is a .zip file reads its inputs from inside the
+archive: TarSubpath names the run folder in it, and the tar stage streams that
+folder straight into the job's tar.gz. 'pur make-dirs-csv --zip' writes such
+rows, one per run folder matching --pattern, so a study can be submitted from
+the ZIP with no extra disk space beyond the tars themselves.
+
+Use 'pur zip list' to see which run folders a pattern selects, and
+'pur zip extract' (or make-dirs-csv --extract-to) to extract only those runs
+when they need to be edited before submission.`,
+	}
+
+	cmd.AddCommand(newPURZipListCmd())
+	cmd.AddCommand(newPURZipExtractCmd())
+
+	return cmd
+}
+
+// zipScanFlags are the run selection flags shared by the 'pur zip' commands.
+type zipScanFlags struct {
+	pattern           string
+	runSubpath        string
+	validationPattern string
+}
+
+func (f *zipScanFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.pattern, "pattern", "p", "*", "Run folder pattern, e.g., 'Run_*'")
+	cmd.Flags().StringVar(&f.runSubpath, "run-subpath", "", "Folder inside the ZIP to look for runs in (e.g., 'study')")
+	cmd.Flags().StringVar(&f.validationPattern, "validation-pattern", "", "Only runs containing a file matching this pattern")
+}
+
+func (f *zipScanFlags) scan(zipPath string) ([]multipart.ScanResult, error) {
+	return multipart.ScanZip(zipPath, multipart.ScanOpts{
+		Pattern:           f.pattern,
+		RunSubpath:        f.runSubpath,
+		ValidationPattern: f.validationPattern,
+		BaseJobName:       "Run",
+		StartIndex:        1,
+	})
+}
+
+func newPURZipListCmd() *cobra.Command {
+	var flags zipScanFlags
+
+	cmd := &cobra.Command{
+		Use:   "list <study.zip>",
+		Short: "List the run folders in a ZIP archive",
+		Long: `List the run folders in a ZIP archive that match --pattern, with the number
+of files and the uncompressed size of each.
+
+Examples:
+  rescale-int pur zip list study.zip --pattern "Run_*"
+  rescale-int pur zip list study.zip --run-subpath study/cases --pattern "Run_*"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := flags.scan(args[0])
+			if err != nil {
+				return err
+			}
+
+			var totalFiles int
+			var totalSize int64
+			fmt.Printf("%-50s  %8s  %10s\n", "RUN FOLDER", "FILES", "SIZE")
+			for _, r := range results {
+				entries, err := tar.PreviewZipContents(r.Directory, r.ZipFolder, nil, nil, false)
+				if err != nil {
+					return err
+				}
+				var size int64
+				for _, e := range entries {
+					size += e.Size
+				}
+				totalFiles += len(entries)
+				totalSize += size
+				fmt.Printf("%-50s  %8d  %10s\n", r.ZipFolder, len(entries), cloud.FormatBytes(size))
+			}
+			fmt.Printf("\n%d run folders, %d files, %s uncompressed\n", len(results), totalFiles, cloud.FormatBytes(totalSize))
+			return nil
+		},
+	}
+	flags.register(cmd)
+
+	return cmd
+}
+
+func newPURZipExtractCmd() *cobra.Command {
+	var flags zipScanFlags
+
+	cmd := &cobra.Command{
+		Use:   "extract <study.zip> <dest-dir>",
+		Short: "Extract only the run folders matching a pattern",
+		Long: `Extract the run folders in a ZIP archive that match --pattern into dest-dir,
+one directory per run, leaving the rest of the archive packed.
+
+Example:
+  rescale-int pur zip extract study.zip ./runs --pattern "Run_1*"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := flags.scan(args[0])
+			if err != nil {
+				return err
+			}
+			extracted, err := extractZipRuns(results, args[1])
+			if err != nil {
+				return err
+			}
+			for _, r := range extracted {
+				fmt.Println(r.Directory)
+			}
+			fmt.Printf("Extracted %d run folders to %s\n", len(extracted), args[1])
+			return nil
+		},
+	}
+	flags.register(cmd)
+
+	return cmd
+}
+
+// extractZipRuns extracts the run folders found by multipart.ScanZip into
+// destDir and returns the results pointing at the extracted directories.
+// A run directory that already exists in destDir is an error, so nothing is
+// silently overwritten.
+func extractZipRuns(results []multipart.ScanResult, destDir string) ([]multipart.ScanResult, error) {
+	absDest, err := filepath.Abs(destDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", destDir, err)
+	}
+	seen := make(map[string]string)
+	for _, r := range results {
+		target := filepath.Join(absDest, filepath.Base(r.ZipFolder))
+		if other, ok := seen[target]; ok {
+			return nil, fmt.Errorf("run folders %s and %s would both extract to %s", other, r.ZipFolder, target)
+		}
+		seen[target] = r.ZipFolder
+		if _, err := os.Stat(target); err == nil {
+			return nil, fmt.Errorf("%s already exists", target)
+		}
+	}
+
+	extracted := make([]multipart.ScanResult, len(results))
+	for i, r := range results {
+		dir, err := tar.ExtractZip(r.Directory, r.ZipFolder, absDest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", r.ZipFolder, err)
+		}
+		GetLogger().Info().Str("folder", r.ZipFolder).Str("dir", dir).Msg("Extracted run folder")
+		r.Directory = dir
+		r.ZipFolder = ""
+		extracted[i] = r
+	}
+	return extracted, nil
+}
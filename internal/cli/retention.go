@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/report"
+	"github.com/rescale/rescale-int/internal/pur/repro"
+	"github.com/rescale/rescale-int/internal/pur/retention"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// loadRetentionPolicy reads the --retention policy, or the recipe's own
+// <jobs>.retention.json when the flag is not given and the file exists.
+// Returns nil when there is no policy.
+func loadRetentionPolicy(flagPath, jobsCSV string) (*retention.Policy, error) {
+	path := flagPath
+	if path == "" {
+		if jobsCSV == "" || jobsCSV == "-" {
+			return nil, nil
+		}
+		path = retention.PolicyPath(jobsCSV)
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+	p, err := retention.LoadPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	if p.Empty() {
+		return nil, nil
+	}
+	GetLogger().Info().Str("policy", path).Msg("Retention actions will run after the pipeline")
+	return p, nil
+}
+
+// applyRetention queues the policy's actions for the finished run and
+// carries out the ones due now. Failures are reported, never fatal: the
+// run itself is done.
+func applyRetention(ctx context.Context, cfg *config.Config, apiClient *api.Client, policy *retention.Policy,
+	pipe *pipeline.Pipeline, stateFile, reportPath string) {
+	if policy == nil || ctx.Err() != nil {
+		return
+	}
+
+	runID := "run_" + time.Now().Format("20060102-150405")
+	var files []string
+	if stateFile != "" {
+		runID = strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+		if abs, err := filepath.Abs(stateFile); err == nil {
+			stateFile = abs
+		}
+		htmlPath, jsonPath := report.DefaultPaths(stateFile)
+		if reportPath != "" {
+			htmlPath, jsonPath = report.PathsFor(reportPath)
+		}
+		files = []string{htmlPath, jsonPath, repro.DefaultPath(stateFile), pipeline.PreviewPath(stateFile)}
+	}
+	states := pipe.StateManager().GetAllStates()
+	tasks := retention.Plan(policy, retention.Run{
+		RunID:     runID,
+		StateFile: stateFile,
+		Files:     files,
+		States:    states,
+		OutputDir: runOutputDirs(states),
+		Finished:  time.Now(),
+	})
+	if len(tasks) == 0 {
+		return
+	}
+
+	w := retentionWorker(cfg, apiClient)
+	if err := w.Schedule(tasks); err != nil {
+		fmt.Printf("⚠ Retention actions not scheduled: %v\n", err)
+		return
+	}
+	sum, err := w.RunDue(ctx)
+	if err != nil {
+		fmt.Printf("⚠ Retention actions: %v\n", err)
+	}
+	fmt.Printf("Retention: %s\n", formatRetentionSummary(sum))
+	if pending := len(tasks) - sum.Done - sum.Skipped - sum.Abandoned; pending > 0 {
+		fmt.Printf("  %d action(s) left for the retention worker: rescale-int runs retention run --watch\n", pending)
+	}
+}
+
+// retentionWorker returns a worker for the platform in cfg. Without an API
+// client, tasks that need the platform stay queued.
+func retentionWorker(cfg *config.Config, apiClient *api.Client) *retention.Worker {
+	w := &retention.Worker{
+		Queue:    retention.NewQueue(config.GetRetentionQueuePath(cfg.APIBaseURL)),
+		AuditLog: config.GetRetentionAuditPath(cfg.APIBaseURL),
+	}
+	if apiClient != nil {
+		w.Platform = apiRetentionPlatform{client: apiClient}
+	}
+	return w
+}
+
+func formatRetentionSummary(s retention.Summary) string {
+	return fmt.Sprintf("%d done, %d waiting for jobs, %d to retry, %d skipped, %d abandoned",
+		s.Done, s.Waiting, s.Retried, s.Skipped, s.Abandoned)
+}
+
+// apiRetentionPlatform carries out retention tasks with the API client.
+type apiRetentionPlatform struct {
+	client *api.Client
+}
+
+func (p apiRetentionPlatform) DeleteFile(ctx context.Context, fileID string) error {
+	ctx, cancel := p.client.WithTimeout(ctx, config.APIMutation)
+	defer cancel()
+	return p.client.DeleteFile(ctx, fileID)
+}
+
+// DownloadOutputs downloads the outputs of Completed jobs like `pur
+// download-outputs`, skipping files already there. Jobs that ended any
+// other way have no outputs to wait for and are dropped.
+func (p apiRetentionPlatform) DownloadOutputs(ctx context.Context, jobs []retention.Job, dest string) ([]retention.Job, error) {
+	logger := GetLogger()
+	var pending []retention.Job
+	var firstErr error
+	for _, job := range jobs {
+		status, err := jobCurrentStatusFn(ctx, p.client, job.ID)
+		if err != nil {
+			pending = append(pending, job)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("job %s: %w", job.ID, err)
+			}
+			continue
+		}
+		if !watch.TerminalStatuses[status] {
+			pending = append(pending, job)
+			continue
+		}
+		if status != "Completed" {
+			logger.Info().Str("job_id", job.ID).Str("status", status).Msg("Retention: job did not complete, no outputs to download")
+			continue
+		}
+		err = executeJobDownload(ctx, job.ID, filepath.Join(dest, job.Dir), constants.DefaultMaxConcurrent, false, true, false, false, false,
+			nil, nil, nil, nil, p.client, logger)
+		if err != nil {
+			pending = append(pending, job)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("job %s: %w", job.ID, err)
+			}
+		}
+	}
+	return pending, firstErr
+}
+
+// newRunsRetentionCmd creates the 'runs retention' command group.
+func newRunsRetentionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Show and carry out end-of-run retention actions",
+		Long: `A run recipe can ask for retention actions after the run: delete the local
+tars, archive the state file and run logs, delete the uploaded inputs from
+the platform after some days, and download the outputs as the jobs finish.
+They are read from <jobs file>.retention.json next to the jobs CSV, or from
+pur run --retention, for example:
+
+  {
+    "deleteLocalTars": true,
+    "archiveDir": "/archive/pur",
+    "deleteRemoteInputsAfterDays": 30,
+    "downloadOutputsTo": "/results/wing"
+  }
+
+Actions due when the run ends are carried out right away. The rest wait in
+a queue for the retention worker (runs retention run), which can run from
+cron or with --watch. Every action is recorded in an audit log (runs
+retention log).`,
+	}
+
+	cmd.AddCommand(newRunsRetentionListCmd())
+	cmd.AddCommand(newRunsRetentionRunCmd())
+	cmd.AddCommand(newRunsRetentionLogCmd())
+	cmd.AddCommand(newRunsRetentionCancelCmd())
+
+	return cmd
+}
+
+func newRunsRetentionListCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List queued retention actions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLocalConfig()
+			if err != nil {
+				return err
+			}
+			tasks, err := retention.NewQueue(config.GetRetentionQueuePath(cfg.APIBaseURL)).Tasks()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if outputJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if tasks == nil {
+					tasks = []retention.Task{}
+				}
+				return enc.Encode(tasks)
+			}
+			if len(tasks) == 0 {
+				fmt.Fprintln(out, "No retention actions queued")
+				return nil
+			}
+			fmt.Fprintf(out, "%-16s %-20s %-22s %-17s %s\n", "ID", "Run", "Action", "Due", "Details")
+			for _, t := range tasks {
+				detail := retention.Describe(t)
+				if t.LastError != "" {
+					detail += fmt.Sprintf(" (attempt %d failed: %s)", t.Attempts, t.LastError)
+				}
+				fmt.Fprintf(out, "%-16s %-20s %-22s %-17s %s\n", t.ID, t.RunID, t.Action, t.DueAt.Local().Format("2006-01-02 15:04"), detail)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newRunsRetentionRunCmd() *cobra.Command {
+	var watchQueue bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Carry out the retention actions that are due",
+		Long: `Carry out the queued retention actions that are due: delete uploaded inputs
+whose day has come and download the outputs of jobs that have finished.
+Failed actions are retried with backoff and abandoned after 5 attempts.
+
+Run it from cron, or keep it running with --watch.
+
+Examples:
+  rescale-int runs retention run
+  rescale-int runs retention run --watch --interval 10m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			apiClient, err := api.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create API client: %w", err)
+			}
+			w := retentionWorker(cfg, apiClient)
+			ctx := GetContext()
+
+			for {
+				sum, err := w.RunDue(ctx)
+				if err != nil && ctx.Err() == nil {
+					return err
+				}
+				fmt.Printf("%s Retention: %s\n", time.Now().Format("15:04:05"), formatRetentionSummary(sum))
+				if !watchQueue {
+					return nil
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&watchQueue, "watch", false, "Keep running, checking the queue every --interval")
+	cmd.Flags().DurationVar(&interval, "interval", retention.DownloadPollInterval, "How often --watch checks the queue")
+
+	return cmd
+}
+
+func newRunsRetentionLogCmd() *cobra.Command {
+	var runID string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the retention audit log",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLocalConfig()
+			if err != nil {
+				return err
+			}
+			entries, err := retention.ReadAudit(config.GetRetentionAuditPath(cfg.APIBaseURL), runID)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if outputJSON {
+				enc := json.NewEncoder(out)
+				for _, e := range entries {
+					if err := enc.Encode(e); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(out, "No retention actions recorded")
+				return nil
+			}
+			for _, e := range entries {
+				line := fmt.Sprintf("%s  %-20s %-22s %-10s %s", e.Time.Local().Format("2006-01-02 15:04:05"), e.RunID, e.Action, e.Result, e.Detail)
+				if e.Error != "" {
+					line += ": " + e.Error
+				}
+				fmt.Fprintln(out, strings.TrimRight(line, " "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&runID, "run", "", "Only show actions of this run")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON Lines")
+
+	return cmd
+}
+
+func newRunsRetentionCancelCmd() *cobra.Command {
+	var runID string
+
+	cmd := &cobra.Command{
+		Use:   "cancel [task-id...]",
+		Short: "Remove queued retention actions",
+		Long: `Remove queued retention actions by ID (see runs retention list), or all
+actions of a run with --run.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runID == "" && len(args) == 0 {
+				return fmt.Errorf("give task IDs or --run")
+			}
+			cfg, err := loadLocalConfig()
+			if err != nil {
+				return err
+			}
+			removed, err := retentionWorker(cfg, nil).Cancel(runID, args...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d retention action(s)\n", len(removed))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&runID, "run", "", "Remove every queued action of this run")
+
+	return cmd
+}
@@ -8,11 +8,14 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
 
+	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/netwatch"
 	"github.com/rescale/rescale-int/internal/ratelimit"
 	"github.com/rescale/rescale-int/internal/ratelimit/coordinator"
 	"github.com/rescale/rescale-int/internal/reporting"
@@ -57,6 +60,36 @@ func FIPSStatus() string {
 	return "[FIPS: disabled]"
 }
 
+// networkWatchCommands are the long-running transfer and run commands that
+// follow network changes, by command path below the root command.
+var networkWatchCommands = map[string]bool{
+	"upload":                true,
+	"download":              true,
+	"files upload":          true,
+	"files download":        true,
+	"files sync":            true,
+	"folders upload-dir":    true,
+	"folders download-dir":  true,
+	"jobs submit":           true,
+	"jobs download":         true,
+	"jobs download-outputs": true,
+	"jobs live-files get":   true,
+	"jobs live-files put":   true,
+	"pur run":               true,
+	"pur run retry-failed":  true,
+	"pur resume":            true,
+	"pur sweep":             true,
+	"pur download-outputs":  true,
+	"daemon run":            true,
+	"send-to":               true,
+}
+
+// watchesNetwork reports whether cmd is one of networkWatchCommands.
+func watchesNetwork(cmd *cobra.Command) bool {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return networkWatchCommands[path]
+}
+
 // NewRootCmd creates the root command for CLI mode.
 func NewRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -74,7 +107,12 @@ GUI Mode (--gui flag):
 
 Security:
   FIPS 140-3 compliant cryptography for FedRAMP Moderate.`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Log format (--log-format, log_format) before the first line
+			if err := applyLogFormat(); err != nil {
+				return err
+			}
+
 			// Initialize logger
 			logger = logging.NewDefaultCLILogger()
 			if verbose || debug {
@@ -88,6 +126,38 @@ Security:
 			ratelimit.SetGlobalNotifyFunc(func(level, message string) {
 				log.Printf("%s", message)
 			})
+
+			// Re-dial connections when the default route changes (Wi-Fi → VPN)
+			// so in-flight parts retry over the new route instead of hanging.
+			if watchesNetwork(cmd) {
+				go netwatch.New(0, func(c netwatch.Change) {
+					if !c.Online() {
+						log.Printf("Network connection lost; transfers will retry when it is back")
+						return
+					}
+					log.Printf("Network changed (%s); reconnected %d connection(s)", c.New, inthttp.ResetAllConnections())
+				}).Run(GetContext())
+			}
+
+			// Read-only viewer mode (viewer_mode)
+			applyViewerMode()
+
+			// OpenTelemetry traces of the command (otel_endpoint)
+			startCommandTrace(cmd)
+
+			// Machine-readable event stream and webhooks for external
+			// orchestrators
+			if cliEvents == nil {
+				notifier := commandNotifier()
+				if eventsSpec != "" || notifier != nil {
+					stream, err := startEventStream(eventsSpec, notifier)
+					if err != nil {
+						return err
+					}
+					cliEvents = stream
+				}
+			}
+			return nil
 		},
 	}
 
@@ -96,8 +166,11 @@ Security:
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "Rescale API key (overrides all other sources)")
 	rootCmd.PersistentFlags().StringVar(&tokenFile, "token-file", "", "Path to file containing API key")
 	rootCmd.PersistentFlags().StringVar(&apiBaseURL, "api-url", "", "Rescale API base URL (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&workspaceFlag, "workspace", "", "Workspace ID or name to browse, upload and submit in (overrides config)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output (shows debug messages)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug output (same as --verbose)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log output format: text or json (newline-delimited JSON records; overrides log_format)")
+	rootCmd.PersistentFlags().StringVar(&eventsSpec, "events", "", "Stream events as NDJSON: ndjson://stderr, ndjson://stdout, ndjson://unix:<socket> or ndjson://<file>")
 
 	// Thread control flags for multi-threaded transfers
 	rootCmd.PersistentFlags().IntVar(&maxThreads, "max-threads", 0, "Maximum threads for transfers (0 = auto-detect, range: 1-32)")
@@ -278,9 +351,20 @@ func Execute() error {
 		}
 	}()
 
+	// Record network usage for the daily totals ('history usage')
+	stopUsageRecorder := startUsageRecorder()
+
 	rootCmd := NewRootCmd()
 	AddCommands(rootCmd)
 	executedCmd, err := rootCmd.ExecuteC()
+	endCommandTrace(err)
+	stopUsageRecorder()
+
+	// Flush the event stream before anything else is printed
+	if cliEvents != nil {
+		cliEvents.Close()
+		cliEvents = nil
+	}
 
 	// Classify and auto-save report for blocking failures.
 	// ExecuteC returns the actual subcommand that ran, so we get a meaningful operation
@@ -313,6 +397,16 @@ func AddCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newDaemonCmd())
 	rootCmd.AddCommand(newServiceCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newTemplatesCmd())
+	rootCmd.AddCommand(newWorkspacesCmd())
+	rootCmd.AddCommand(newRunsCmd())
+	rootCmd.AddCommand(newLogsCmd())
+	rootCmd.AddCommand(newAdminCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newSendToCmd())
+	rootCmd.AddCommand(newSelfUpdateCmd())
 	rootCmd.AddCommand(newCoordinatorCmd()) // internal: cross-process rate limit coordinator
 
 	// Add shortcuts for convenience
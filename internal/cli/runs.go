@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/pur/report"
+	"github.com/rescale/rescale-int/internal/pur/repro"
+	"github.com/rescale/rescale-int/internal/pur/runhistory"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// runsStatusWorkers bounds concurrent job status requests during an export.
+const runsStatusWorkers = 8
+
+// newRunsCmd creates the 'runs' command group.
+func newRunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Work with PUR runs",
+		Long: `Commands for PUR runs started from this machine (CLI, GUI or REST API),
+identified by their state file.`,
+	}
+
+	cmd.AddCommand(newRunsExportCmd())
+	cmd.AddCommand(newRunsShowCmd())
+	cmd.AddCommand(newRunsRetentionCmd())
+
+	return cmd
+}
+
+func newRunsExportCmd() *cobra.Command {
+	var (
+		stateFile string
+		runID     string
+		format    string
+		output    string
+		noLive    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a run's jobs table to CSV or Excel",
+		Long: `Write one row per job of a PUR run with its directory, name, tar/upload/
+create/submit statuses, job ID, live platform status (and queue sub-status
+while waiting to execute), per-stage durations in seconds, error and portal
+link. This is the same table the GUI Jobs view exports.
+
+The run is the one recorded in --state, or --run <id> in the state folder;
+without either, the most recent run is used. Platform statuses are fetched
+from Rescale unless --no-live is given. Stage durations come from the run
+report written when the run finished.
+
+Examples:
+  # Most recent run, as CSV in the current directory
+  rescale-int runs export
+
+  # A specific run, as an Excel workbook
+  rescale-int runs export --state study.state --format xlsx -o study.xlsx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLocalConfig()
+			if err != nil {
+				return err
+			}
+			if stateFile, err = resolveRunStateFile(cfg, stateFile, runID); err != nil {
+				return err
+			}
+
+			fmtName, err := report.ExportFormat(format, output)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+			if output == "" {
+				output = name + "-jobs." + fmtName
+			}
+
+			if _, err := os.Stat(stateFile); err != nil {
+				return fmt.Errorf("state file not found: %s", stateFile)
+			}
+			mgr := state.NewManager(stateFile)
+			if err := mgr.Load(); err != nil {
+				return err
+			}
+			opts := report.Options{RunID: name, StateFile: stateFile, PlatformURL: cfg.APIBaseURL}
+			// A missing report just means the run has not finished yet.
+			_, jsonPath := report.DefaultPaths(stateFile)
+			if prev, err := report.ReadJSON(jsonPath); err == nil {
+				opts.StageDurations = make(map[int]map[string]time.Duration)
+				for _, j := range prev.Jobs {
+					opts.StageDurations[j.Index] = j.StageDurations
+				}
+			}
+			rows := report.Build(mgr.GetAllStates(), opts).TableRows()
+
+			if !noLive {
+				apiClient, err := getAPIClient()
+				if err != nil {
+					return fmt.Errorf("%w (use --no-live to export without platform statuses)", err)
+				}
+				fillPlatformStatuses(GetContext(), apiClient, rows)
+			}
+
+			if err := report.WriteTableFile(output, fmtName, rows); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d job(s) from %s to %s\n", len(rows), stateFile, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file of the run to export")
+	cmd.Flags().StringVar(&runID, "run", "", "Run ID in the state folder (e.g. run_1712345678)")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: csv or xlsx (default from the --output extension, else csv)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default <run>-jobs.<format> in the current directory)")
+	cmd.Flags().BoolVar(&noLive, "no-live", false, "Do not fetch live job statuses from Rescale")
+
+	return cmd
+}
+
+func newRunsShowCmd() *cobra.Command {
+	var (
+		stateFile  string
+		showRepro  bool
+		outputJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show [run-id]",
+		Short: "Show a run's outcome or reproducibility bundle",
+		Long: `Show where a PUR run's artifacts are and how its jobs ended.
+
+With --repro, show the reproducibility bundle recorded when the run started:
+the Interlink version and commit, FIPS status, effective configuration
+(secrets redacted), job templates, scan options and the software versions and
+core types the jobs use. The bundle is <run>.repro.json next to the state
+file; attach it (or the --json output) to support tickets.
+
+The run is [run-id] in the state folder or --state; without either, the most
+recent run is used.
+
+Examples:
+  rescale-int runs show run_1712345678
+  rescale-int runs show --repro run_1712345678
+  rescale-int runs show --repro --json --state study.state > repro.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var runID string
+			if len(args) == 1 {
+				runID = args[0]
+			}
+			cfg, err := loadLocalConfig()
+			if err != nil {
+				return err
+			}
+			if stateFile, err = resolveRunStateFile(cfg, stateFile, runID); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			if showRepro {
+				path := repro.DefaultPath(stateFile)
+				if _, err := os.Stat(path); err != nil {
+					return fmt.Errorf("no reproducibility bundle for this run (%s); runs started before this version don't have one", path)
+				}
+				if outputJSON {
+					data, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("failed to read reproducibility bundle: %w", err)
+					}
+					_, err = out.Write(data)
+					return err
+				}
+				b, err := repro.ReadJSON(path)
+				if err != nil {
+					return err
+				}
+				b.Print(out)
+				fmt.Fprintf(out, "\nBundle: %s\n", path)
+				return nil
+			}
+
+			if _, err := os.Stat(stateFile); err != nil {
+				return fmt.Errorf("state file not found: %s", stateFile)
+			}
+			mgr := state.NewManager(stateFile)
+			if err := mgr.Load(); err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+			rep := report.Build(mgr.GetAllStates(), report.Options{RunID: name, StateFile: stateFile, PlatformURL: cfg.APIBaseURL})
+			if outputJSON {
+				return json.NewEncoder(out).Encode(rep)
+			}
+
+			t := rep.Totals
+			fmt.Fprintf(out, "Run:         %s\n", name)
+			fmt.Fprintf(out, "State file:  %s\n", stateFile)
+			fmt.Fprintf(out, "Jobs:        %d (%d succeeded, %d failed, %d incomplete)\n", t.Jobs, t.Succeeded, t.Failed, t.Incomplete)
+			htmlPath, _ := report.DefaultPaths(stateFile)
+			for _, artifact := range []struct{ label, path string }{
+				{"Report:", htmlPath},
+				{"Repro:", repro.DefaultPath(stateFile)},
+			} {
+				if _, err := os.Stat(artifact.path); err == nil {
+					fmt.Fprintf(out, "%-12s %s\n", artifact.label, artifact.path)
+				}
+			}
+			for _, f := range rep.Failures() {
+				fmt.Fprintf(out, "  ✗ %s: %s\n", f.JobName, f.Error)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&stateFile, "state", "s", "", "State file of the run to show")
+	cmd.Flags().BoolVar(&showRepro, "repro", false, "Show the reproducibility bundle recorded when the run started")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// resolveRunStateFile returns the state file named by --state or a run ID in
+// the state folder, or the most recent run when neither is given.
+func resolveRunStateFile(cfg *config.Config, stateFile, runID string) (string, error) {
+	switch {
+	case stateFile != "" && runID != "":
+		return "", fmt.Errorf("--state and a run ID are mutually exclusive")
+	case runID != "":
+		return filepath.Join(config.StateDirectory(cfg), runID+".state"), nil
+	case stateFile != "":
+		return stateFile, nil
+	}
+	return latestStateFile(cfg)
+}
+
+// latestStateFile returns the most recently modified state file in the state
+// folder or recorded in the run history.
+func latestStateFile(cfg *config.Config) (string, error) {
+	candidates, _ := filepath.Glob(filepath.Join(config.StateDirectory(cfg), "*.state"))
+	if recorded, err := runhistory.StateFiles(config.GetRunHistoryPath(cfg.APIBaseURL)); err == nil {
+		candidates = append(candidates, recorded...)
+	}
+	var latest string
+	var latestMod time.Time
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err != nil {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestMod) {
+			latest, latestMod = c, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no runs found in %s; use --state to name a state file", config.StateDirectory(cfg))
+	}
+	return latest, nil
+}
+
+// fillPlatformStatuses sets each created job's current platform status and,
+// while it waits to execute, its queue/provisioning sub-status. Jobs whose
+// status cannot be read are left blank with a warning.
+func fillPlatformStatuses(ctx context.Context, apiClient *api.Client, rows []report.TableRow) {
+	sem := make(chan struct{}, runsStatusWorkers)
+	var wg sync.WaitGroup
+	for i := range rows {
+		if rows[i].JobID == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(row *report.TableRow) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			history, err := apiClient.GetJobStatuses(ctx, row.JobID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to get status of job %s: %v\n", row.JobID, err)
+				return
+			}
+			if latest, _, ok := watch.LatestStatus(history); ok {
+				row.PlatformStatus = latest.Status
+			}
+			if sub, ok := watch.PreExecutionSubStatus(history); ok {
+				row.SubStatus = sub.Label
+				row.SubStatusSince = sub.Since
+			}
+		}(&rows[i])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/selfupdate"
+)
+
+func newSelfUpdateCmd() *cobra.Command {
+	var checkOnly bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update rescale-int from the configured distribution server",
+		Long: `Download the latest release from your organization's distribution
+server, verify its signature and replace this binary.
+
+Requires update_url (HTTPS URL of the release manifest) and
+update_public_key (PEM file with the ECDSA P-256 release signing key) in
+the config. The binary is only installed if the manifest's detached
+signature verifies, the binary matches the signed checksum and the signed
+version is newer than this one; the swap is atomic and takes effect the
+next time rescale-int starts. Set self_update_disabled=true (or RESCALE_DISABLE_UPDATE_CHECK=1)
+to turn self-update off on managed installs.
+
+Examples:
+  rescale-int self-update --check
+  rescale-int self-update --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := cfgFile
+			if configPath == "" {
+				configPath = config.GetDefaultConfigPath()
+			}
+			cfg, err := config.LoadConfigCSV(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			u, err := selfupdate.NewFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(GetContext(), constants.SelfUpdateTimeout)
+			defer cancel()
+
+			rel, err := u.Check(ctx)
+			if err != nil {
+				return err
+			}
+			if !rel.Newer {
+				fmt.Printf("rescale-int %s is up to date (latest: %s)\n", rel.Current, rel.Version)
+				return nil
+			}
+			fmt.Printf("Update available: %s → %s\n", rel.Current, rel.Version)
+			if checkOnly {
+				return nil
+			}
+
+			if !yes {
+				if !IsTerminal() {
+					return fmt.Errorf("refusing to update without confirmation: pass --yes")
+				}
+				fmt.Printf("Replace %s? [y/N]: ", u.Executable)
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					fmt.Println("Update cancelled")
+					return nil
+				}
+			}
+
+			fmt.Printf("Downloading and verifying %s...\n", rel.Key)
+			if err := u.Apply(ctx, rel); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Updated to %s (signature verified). Restart rescale-int to use it.\n", rel.Version)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&checkOnly, "check", false, "Only report whether an update is available")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Install without prompting")
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/sendto"
+)
+
+// newSendToCmd creates the "send-to" command, which the OS context-menu
+// entry runs to queue files for upload by the running GUI or serve.
+func newSendToCmd() *cobra.Command {
+	var install, uninstall, status bool
+
+	cmd := &cobra.Command{
+		Use:   "send-to [files...]",
+		Short: "Queue files for upload from the OS context menu",
+		Long: `Queue files for upload by a running Interlink GUI or 'rescale-int serve'.
+
+This is what the "Send to Rescale Interlink" context-menu entry runs. Files
+are uploaded into send_to_folder (a folder path under My Library, set in
+config.csv or the GUI Setup tab; empty means My Library itself). If neither
+the GUI nor serve is running, the GUI is started to pick the files up.
+
+--install adds the context-menu entry for the current user:
+  Windows  Explorer right-click menu
+  macOS    Finder Quick Action (assign a keyboard shortcut under System
+           Settings > Keyboard > Keyboard Shortcuts > Services)
+  Linux    GNOME Files Scripts menu and KDE Dolphin service menu`,
+		Example: `  rescale-int send-to --install
+  rescale-int send-to ~/results/case1.csv ~/results/case2.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate executable: %w", err)
+			}
+
+			switch {
+			case install:
+				if err := sendto.Install(exe); err != nil {
+					return err
+				}
+				fmt.Printf("Added %q to the file context menu\n", sendto.MenuLabel)
+				return nil
+			case uninstall:
+				if err := sendto.Uninstall(); err != nil {
+					return err
+				}
+				fmt.Printf("Removed %q from the file context menu\n", sendto.MenuLabel)
+				return nil
+			case status:
+				if !sendto.Supported() {
+					fmt.Println("Context menu: not supported on this platform")
+				} else if sendto.Installed() {
+					fmt.Println("Context menu: installed")
+				} else {
+					fmt.Println("Context menu: not installed")
+				}
+				return nil
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("no files given")
+			}
+			files := make([]string, len(args))
+			for i, a := range args {
+				// File managers may pass paths relative to the selected folder
+				if files[i], err = filepath.Abs(a); err != nil {
+					return err
+				}
+			}
+
+			spool := sendto.Spool{Dir: config.SendToSpoolDirectory()}
+			if err := spool.Enqueue(files); err != nil {
+				return err
+			}
+			if spool.ConsumerAlive() {
+				fmt.Printf("Queued %d file(s) for upload\n", len(files))
+				return nil
+			}
+			if err := sendto.LaunchGUI(exe); err != nil {
+				fmt.Printf("Queued %d file(s); they will upload when Interlink is started (%v)\n", len(files), err)
+				return nil
+			}
+			fmt.Printf("Queued %d file(s); starting Interlink to upload them\n", len(files))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&install, "install", false, "Add the context-menu entry for the current user")
+	cmd.Flags().BoolVar(&uninstall, "uninstall", false, "Remove the context-menu entry")
+	cmd.Flags().BoolVar(&status, "status", false, "Show whether the context-menu entry is installed")
+	cmd.MarkFlagsMutuallyExclusive("install", "uninstall", "status")
+
+	return cmd
+}
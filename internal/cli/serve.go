@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/core"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/sendto"
+	"github.com/rescale/rescale-int/internal/server"
+)
+
+// newServeCmd creates the "serve" command, which runs the local REST API.
+func newServeCmd() *cobra.Command {
+	var listen string
+	var tokenFile string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local REST API for scripts and other tools",
+		Long: `Run an authenticated REST API on a loopback address so tools on this machine
+(Python, MATLAB, shell scripts) can start uploads, downloads and PUR runs and
+follow their progress.
+
+A new random token is written to --token-file on every start (readable by
+the current user only). Send it with each request:
+
+  Authorization: Bearer <token>
+
+Endpoints:
+  GET    /v1/status               Version, run and transfer summary
+  POST   /v1/uploads              {"paths": [...], "folderId": "", "tags": [...]}
+  POST   /v1/downloads            {"fileIds": [...], "destDir": "/abs/dir"}
+  GET    /v1/transfers            Transfer tasks (?batch_id= to filter)
+  DELETE /v1/transfers/{id}       Cancel a transfer
+  POST   /v1/runs                 {"jobsFile": "/abs/jobs.csv"}
+  GET    /v1/runs/current         Current (or last) run progress
+  DELETE /v1/runs/current         Cancel the current run
+  GET    /v1/events               Server-Sent Events stream (NDJSON records)
+
+While running, serve also uploads files queued with "Send to Rescale
+Interlink" (see send-to). Only loopback listen addresses are accepted. Requests with an Origin header
+(browsers) are refused.`,
+		Example: `  rescale-int serve
+  rescale-int serve --listen 127.0.0.1:9000
+  curl -H "Authorization: Bearer $(cat ~/.config/rescale/serve-token)" \
+    http://127.0.0.1:8642/v1/status`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.ValidateListenAddr(listen); err != nil {
+				return err
+			}
+
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			engine, err := core.NewEngine(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create engine: %w", err)
+			}
+
+			token, err := server.GenerateToken()
+			if err != nil {
+				return err
+			}
+			if err := server.WriteTokenFile(tokenFile, token); err != nil {
+				return err
+			}
+			defer os.Remove(tokenFile)
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			logger := logging.NewLogger("serve", nil)
+			fmt.Printf("Listening on http://%s\n", listen)
+			fmt.Printf("Token written to %s\n", tokenFile)
+
+			// Also upload files sent from the OS context menu
+			go sendto.NewProcessor(engine, func() string { return cfg.SendToFolder }, logger).Run(ctx)
+
+			srv := server.New(engine, token, logger)
+			if err := srv.Serve(ctx, listen); err != nil {
+				return err
+			}
+			engine.Stop()
+			fmt.Println("Server stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", constants.DefaultServeListen, "Loopback address to listen on")
+	cmd.Flags().StringVar(&tokenFile, "token-file", config.ServeTokenPath(), "File the API token is written to")
+
+	return cmd
+}
@@ -4,6 +4,7 @@ package cli
 import (
 	"fmt"
 
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +23,7 @@ func newUploadShortcut() *cobra.Command {
 	var folderID string
 	var maxConcurrent int
 	var preEncrypt bool
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:   "upload <file> [file...]",
@@ -55,6 +57,10 @@ Examples:
 				return err
 			}
 
+			if ok, err := confirmLargeUpload(GetContext(), apiClient, args, yes); !ok || err != nil {
+				return err
+			}
+
 			// Use shared helper function
 			return executeFileUpload(GetContext(), args, folderID, maxConcurrent, preEncrypt, apiClient, logger)
 		},
@@ -64,6 +70,7 @@ Examples:
 	cmd.Flags().IntVarP(&maxConcurrent, "max-concurrent", "m", constants.DefaultMaxConcurrent,
 		fmt.Sprintf("Maximum concurrent file uploads (%d-%d)", constants.MinMaxConcurrent, constants.MaxMaxConcurrent))
 	cmd.Flags().BoolVar(&preEncrypt, "pre-encrypt", false, "Use legacy pre-encryption (for compatibility with older Rescale clients)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Start large uploads without asking for confirmation")
 
 	return cmd
 }
@@ -102,7 +109,7 @@ Examples:
 			}
 
 			// Use shared helper function (no conflict flags for shortcut)
-			return executeFileDownload(GetContext(), args, outputDir, maxConcurrent, false, false, false, false, apiClient, logger)
+			return executeFileDownload(GetContext(), args, outputDir, maxConcurrent, false, false, false, false, false, apiClient, logger)
 		},
 	}
 
@@ -139,7 +146,8 @@ Examples:
 				return err
 			}
 
-			ctx := GetContext()
+			ctx, cancel := apiClient.WithTimeout(GetContext(), config.APIListing)
+			defer cancel()
 
 			// List jobs
 			logger.Info().Msg("Fetching jobs")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/models"
 )
 
@@ -66,6 +67,8 @@ Examples:
 			}
 
 			// Fetch analyses
+			ctx, cancel := apiClient.WithTimeout(ctx, config.APICatalog)
+			defer cancel()
 			analyses, err := apiClient.GetAnalyses(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to get analyses: %w", err)
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/templaterepo"
+)
+
+// newTemplatesCmd creates the 'templates' command group.
+func newTemplatesCmd() *cobra.Command {
+	var repoFlag string
+
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Import job templates and run recipes from the team template repository",
+		Long: `Browse and import the job templates and run recipes your team shares in a
+template repository, set with template_repo in the config: a folder (e.g. on
+a network share) or a git URL. A git URL may end in #<tag or branch> to pin
+the repository to that version.
+
+The repository holds job templates in templates/*.json (the GUI template
+library format) and run recipes in recipes/*.csv or *.json (jobs files and
+templates for 'pur make-dirs-csv'). Templates are imported into the GUI
+template library (~/.config/rescale/templates), recipes into
+~/.config/rescale/recipes.
+
+Imports remember the version they came from, so 'templates check' reports
+which changed upstream and 'templates update' brings them up to date. A
+pinned import keeps its version until it is unpinned, and a local file you
+edited is never overwritten without --force.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&repoFlag, "repo", "", "Template repository to use instead of template_repo")
+
+	cmd.AddCommand(newTemplatesListCmd(&repoFlag))
+	cmd.AddCommand(newTemplatesImportCmd(&repoFlag))
+	cmd.AddCommand(newTemplatesCheckCmd(&repoFlag))
+	cmd.AddCommand(newTemplatesUpdateCmd(&repoFlag))
+	cmd.AddCommand(newTemplatesPinCmd(true))
+	cmd.AddCommand(newTemplatesPinCmd(false))
+
+	return cmd
+}
+
+// openTemplateRepo opens the repository named by --repo or template_repo
+// and lists its items. No API key is needed.
+func openTemplateRepo(repoFlag string) (*templaterepo.Repo, []templaterepo.Item, error) {
+	setting := repoFlag
+	if setting == "" {
+		cfg, err := loadLocalConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		setting = cfg.TemplateRepo
+	}
+	repo, err := templaterepo.Open(GetContext(), setting, config.GetTemplateRepoCacheDir())
+	if err != nil {
+		return nil, nil, err
+	}
+	if repo.FetchErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not update the template repository, using revision %.12s: %v\n", repo.Revision, repo.FetchErr)
+	}
+	items, err := repo.Items()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, items, nil
+}
+
+// templateStatusText describes a check status for the table output.
+func templateStatusText(status string) string {
+	switch status {
+	case templaterepo.StatusNotImported:
+		return "-"
+	case templaterepo.StatusUpdate:
+		return "update available"
+	case templaterepo.StatusPinned:
+		return "pinned (update available)"
+	case templaterepo.StatusModified:
+		return "modified locally"
+	case templaterepo.StatusRemoved:
+		return "removed upstream"
+	}
+	return status
+}
+
+// printTemplateChecks prints checks as a table or as JSON.
+func printTemplateChecks(checks []templaterepo.Check, outputJSON bool) error {
+	if outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+	fmt.Printf("%-8s %-28s %-14s %-26s %s\n", "KIND", "NAME", "VERSION", "STATUS", "DESCRIPTION")
+	for _, c := range checks {
+		version := c.Item.Version
+		if c.Import != nil && c.Status != templaterepo.StatusCurrent {
+			version = c.Import.Version // What is installed
+		}
+		if version == "" {
+			version = "-"
+		}
+		fmt.Printf("%-8s %-28s %-14s %-26s %s\n", c.Item.Kind, c.Item.Name, version, templateStatusText(c.Status), c.Item.Description)
+	}
+	return nil
+}
+
+func newTemplatesListCmd(repoFlag *string) *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the repository's templates and recipes",
+		Long: `List the templates and recipes in the template repository, with the status
+of any you have imported.
+
+Examples:
+  rescale-int templates list
+  rescale-int templates list --repo '\\fileserver\cae\interlink-templates'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, items, err := openTemplateRepo(*repoFlag)
+			if err != nil {
+				return err
+			}
+			checks, err := templaterepo.DefaultLibrary().Check(items)
+			if err != nil {
+				return err
+			}
+			if !outputJSON {
+				fmt.Printf("Repository: %s", repo.Source)
+				if repo.Revision != "" {
+					fmt.Printf(" (revision %.12s)", repo.Revision)
+				}
+				fmt.Print("\n\n")
+			}
+			return printTemplateChecks(checks, outputJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newTemplatesImportCmd(repoFlag *string) *cobra.Command {
+	var pin, force bool
+
+	cmd := &cobra.Command{
+		Use:   "import <name>...",
+		Short: "Import templates or recipes from the repository",
+		Long: `Import templates or recipes by name. Use the file path (e.g.
+recipes/wing.csv) or kind/name (e.g. recipe/wing) when a template and a
+recipe share a name.
+
+A local template or recipe of the same name that was not imported from the
+repository, or was edited since, is only replaced with --force.
+
+Examples:
+  rescale-int templates import openfoam-wing
+  rescale-int templates import openfoam-wing recipe/wing-sweep --pin`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, items, err := openTemplateRepo(*repoFlag)
+			if err != nil {
+				return err
+			}
+			lib := templaterepo.DefaultLibrary()
+			for _, name := range args {
+				item, err := templaterepo.Find(items, name)
+				if err != nil {
+					return err
+				}
+				imp, err := lib.Import(repo, item, pin, force)
+				if err != nil {
+					return err
+				}
+				pinned := ""
+				if imp.Pinned {
+					pinned = ", pinned"
+				}
+				fmt.Printf("✓ Imported %s %s (version %s%s) to %s\n", item.Kind, item.Name, imp.Version, pinned, lib.LocalPath(item.Kind, item.File))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&pin, "pin", false, "Pin the imports to this version")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace local files that were not imported or were edited")
+
+	return cmd
+}
+
+func newTemplatesCheckCmd(repoFlag *string) *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check imported templates and recipes for upstream changes",
+		Long: `Compare the templates and recipes you imported with the repository and
+report which have a newer version, were edited locally, or were removed
+upstream.
+
+Examples:
+  rescale-int templates check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, items, err := openTemplateRepo(*repoFlag)
+			if err != nil {
+				return err
+			}
+			checks, err := templaterepo.DefaultLibrary().Check(items)
+			if err != nil {
+				return err
+			}
+			var imported []templaterepo.Check
+			updates := 0
+			for _, c := range checks {
+				if c.Import == nil {
+					continue
+				}
+				imported = append(imported, c)
+				if c.Status == templaterepo.StatusUpdate {
+					updates++
+				}
+			}
+			if outputJSON {
+				return printTemplateChecks(imported, true)
+			}
+			if len(imported) == 0 {
+				fmt.Println("No templates or recipes imported from the repository")
+				return nil
+			}
+			if err := printTemplateChecks(imported, false); err != nil {
+				return err
+			}
+			if updates > 0 {
+				fmt.Printf("\n%d update(s) available; run 'rescale-int templates update'\n", updates)
+			} else {
+				fmt.Println("\nAll unpinned imports are up to date")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newTemplatesUpdateCmd(repoFlag *string) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "update [name...]",
+		Short: "Update imported templates and recipes to the repository's version",
+		Long: `Update imported templates and recipes that changed upstream, all of them or
+the named ones. Pinned imports are skipped; unpin them first. Imports you
+edited locally are skipped unless --force is given.
+
+Examples:
+  rescale-int templates update
+  rescale-int templates update openfoam-wing`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, items, err := openTemplateRepo(*repoFlag)
+			if err != nil {
+				return err
+			}
+			lib := templaterepo.DefaultLibrary()
+			checks, err := lib.Check(items)
+			if err != nil {
+				return err
+			}
+
+			wanted := make(map[string]bool)
+			for _, name := range args {
+				item, err := templaterepo.Find(items, name)
+				if err != nil {
+					return err
+				}
+				wanted[item.File] = true
+			}
+
+			updated := 0
+			for _, c := range checks {
+				if c.Import == nil || (len(wanted) > 0 && !wanted[c.Item.File]) {
+					continue
+				}
+				switch {
+				case c.Status == templaterepo.StatusUpdate, c.Status == templaterepo.StatusModified && force:
+				case c.Status == templaterepo.StatusPinned && len(wanted) > 0:
+					fmt.Printf("Skipped %s: pinned to version %s (run 'templates unpin %s' first)\n", c.Item.Name, c.Import.Version, c.Item.Name)
+					continue
+				case c.Status == templaterepo.StatusModified:
+					fmt.Printf("Skipped %s: edited locally (use --force to replace it)\n", c.Item.Name)
+					continue
+				default:
+					continue
+				}
+				imp, err := lib.Import(repo, c.Item, false, true)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Updated %s %s to version %s\n", c.Item.Kind, c.Item.Name, imp.Version)
+				updated++
+			}
+			if updated == 0 {
+				fmt.Println("Nothing to update")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Also replace imports that were edited locally")
+
+	return cmd
+}
+
+// newTemplatesPinCmd creates the 'templates pin' or 'templates unpin'
+// command, which work without reaching the repository.
+func newTemplatesPinCmd(pin bool) *cobra.Command {
+	use, short := "pin", "Keep imported templates or recipes at their current version"
+	if !pin {
+		use, short = "unpin", "Let 'templates update' update pinned imports again"
+	}
+
+	cmd := &cobra.Command{
+		Use:   use + " <name>...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lib := templaterepo.DefaultLibrary()
+			imports, err := lib.Imports()
+			if err != nil {
+				return err
+			}
+			items := templaterepo.ImportedItems(imports)
+			for _, name := range args {
+				item, err := templaterepo.Find(items, name)
+				if errors.Is(err, templaterepo.ErrNotFound) {
+					return fmt.Errorf("%s has not been imported from the template repository", name)
+				}
+				if err != nil {
+					return err
+				}
+				if err := lib.SetPinned(item.File, pin); err != nil {
+					return err
+				}
+				fmt.Printf("✓ %s %s %sned at version %s\n", strings.ToUpper(item.Kind[:1])+item.Kind[1:], item.Name, use, item.Version)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/tracing"
+)
+
+// commandSpan is the span of the running command and tracingShutdown
+// flushes the exporter behind it; both nil unless otel_endpoint or
+// OTEL_EXPORTER_OTLP_* is set.
+var (
+	commandSpan     trace.Span
+	tracingShutdown tracing.ShutdownFunc
+)
+
+// startCommandTrace exports traces when tracing is configured, with the
+// command as the root span: its API calls, pipeline stages and transfers
+// are children. The config is read here because most commands load it
+// later, if at all.
+func startCommandTrace(cmd *cobra.Command) {
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = config.GetDefaultConfigPath()
+	}
+	endpoint := ""
+	if cfg, err := config.LoadConfigCSV(configPath); err == nil {
+		endpoint = cfg.OTelEndpoint
+	}
+	if !tracing.Enabled(endpoint) {
+		return
+	}
+
+	shutdown, err := tracing.Init(GetContext(), endpoint, "cli")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tracing disabled: %v\n", err)
+		return
+	}
+	tracingShutdown = shutdown
+	rootContext, commandSpan = tracing.Start(GetContext(), cmd.CommandPath())
+}
+
+// endCommandTrace ends the command's span with its outcome and flushes the
+// exporter, waiting a few seconds at most for an unreachable collector.
+func endCommandTrace(err error) {
+	if commandSpan == nil {
+		return
+	}
+	tracing.End(commandSpan, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = tracingShutdown(ctx)
+	commandSpan, tracingShutdown = nil, nil
+}
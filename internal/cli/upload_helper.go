@@ -2,16 +2,21 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/cloud/credentials"
 	"github.com/rescale/rescale-int/internal/cloud/state"
+	cloudtransfer "github.com/rescale/rescale-int/internal/cloud/transfer"
 	"github.com/rescale/rescale-int/internal/cloud/upload"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/logging"
@@ -37,6 +42,53 @@ func expandGlobPatterns(patterns []string) ([]string, error) {
 	return glob.ExpandPatterns(patterns)
 }
 
+// expandUploadArgs expands each upload argument's glob patterns, keeping
+// files named by more than one argument so resolveRepeatedUploads can report
+// them.
+func expandUploadArgs(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		expanded, err := expandGlobPatterns([]string{arg})
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, expanded...)
+	}
+	return paths, nil
+}
+
+// resolveRepeatedUploads handles files named more than once, e.g. by a path
+// and a pattern that also matches it. They are uploaded once, unless
+// allowRepeats is set or the user chooses to upload them again when asked.
+func resolveRepeatedUploads(paths []string, allowRepeats bool) ([]string, error) {
+	seen := make(map[string]bool, len(paths))
+	var unique, repeats []string
+	for _, p := range paths {
+		if seen[p] {
+			repeats = append(repeats, p)
+			continue
+		}
+		seen[p] = true
+		unique = append(unique, p)
+	}
+	if len(repeats) == 0 || allowRepeats {
+		return paths, nil
+	}
+
+	if !IsTerminal() {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping %d repeated file(s); use --allow-repeats to upload them again\n", len(repeats))
+		return unique, nil
+	}
+	again, err := promptRepeatedUploads(repeats)
+	if err != nil {
+		return nil, err
+	}
+	if again {
+		return paths, nil
+	}
+	return unique, nil
+}
+
 // executeFileUpload - Common upload logic for both files upload and upload shortcut
 // Now uses the unified UploadFilesWithIDs for concurrent uploads
 func executeFileUpload(
@@ -53,25 +105,21 @@ func executeFileUpload(
 	return err
 }
 
-// executeFileUploadWithDuplicateCheck handles file uploads with optional duplicate detection
+// executeFileUploadWithDuplicateCheck handles file uploads with optional duplicate detection.
+// filePaths are expanded (see expandUploadArgs).
 func executeFileUploadWithDuplicateCheck(
 	ctx context.Context,
-	filePatterns []string,
+	filePaths []string,
 	folderID string,
 	maxConcurrent int,
 	duplicateMode UploadDuplicateMode,
 	dryRun bool,
 	preEncrypt bool,
+	verify bool,
 	uploadTags []string,
 	apiClient *api.Client,
 	logger *logging.Logger,
 ) error {
-	// Expand glob patterns first
-	filePaths, err := expandGlobPatterns(filePatterns)
-	if err != nil {
-		return err
-	}
-
 	// Validate all files exist before starting upload
 	for _, filePath := range filePaths {
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
@@ -88,7 +136,7 @@ func executeFileUploadWithDuplicateCheck(
 
 	// If not checking duplicates, use the fast path
 	if duplicateMode == UploadDuplicateModeNoCheck {
-		_, err := UploadFilesWithIDs(ctx, filePaths, folderID, maxConcurrent, preEncrypt, uploadTags, apiClient, logger, false)
+		_, err := uploadFilesTo(ctx, filePaths, nil, "", folderID, maxConcurrent, preEncrypt, verify, uploadTags, apiClient, logger, false)
 		return err
 	}
 
@@ -102,24 +150,19 @@ func executeFileUploadWithDuplicateCheck(
 		destFolderID = folders.MyLibrary
 	}
 
-	// Get existing files in destination folder
+	// One listing of the destination covers every file in the batch
 	fmt.Println("📡 Checking for existing files in destination...")
-	folderContents, err := apiClient.ListFolderContentsAll(ctx, destFolderID)
+	existing, err := upload.ListFolderNames(ctx, apiClient, destFolderID)
 	if err != nil {
-		return fmt.Errorf("failed to list destination folder: %w", err)
-	}
-
-	// Build set of existing file names
-	existingFiles := make(map[string]bool)
-	for _, file := range folderContents.Files {
-		existingFiles[file.Name] = true
+		return err
 	}
 
-	fmt.Printf("✓ Found %d existing file(s) in destination\n\n", len(existingFiles))
+	fmt.Printf("✓ Found %d existing file name(s) in destination\n\n", len(existing))
 
-	// Filter files based on duplicate mode
+	// Resolve each file against the folder with the duplicate mode
 	var filesToUpload []string
-	var filesSkipped int
+	var targets []uploadTarget
+	var filesSkipped, filesRenamed, filesReplaced int
 	var conflictMode UploadConflictAction
 
 	// Set initial conflict mode based on duplicate mode
@@ -127,7 +170,13 @@ func executeFileUploadWithDuplicateCheck(
 	case UploadDuplicateModeSkipAll:
 		conflictMode = UploadSkipAll
 	case UploadDuplicateModeUploadAll:
+		conflictMode = UploadKeepBothAll
+	case UploadDuplicateModeOverwrite:
 		conflictMode = UploadOverwriteAll
+	case UploadDuplicateModeRename:
+		conflictMode = UploadRenameAll
+	case UploadDuplicateModeVersion:
+		conflictMode = UploadVersionAll
 	default:
 		conflictMode = UploadSkipOnce // Will prompt
 	}
@@ -135,41 +184,46 @@ func executeFileUploadWithDuplicateCheck(
 	for _, filePath := range filePaths {
 		fileName := filepath.Base(filePath)
 
-		if !existingFiles[fileName] {
-			// No duplicate, upload it
-			filesToUpload = append(filesToUpload, filePath)
-			continue
-		}
-
 		// File exists - handle based on mode
 		action := conflictMode
-		if action == UploadSkipOnce || action == UploadOverwriteOnce {
+		if existing.Taken(fileName) && !isUploadConflictAll(action) {
 			// Need to prompt for this file
 			action, err = promptUploadConflict(fileName, "")
 			if err != nil {
 				return err
 			}
+			if action == UploadAbort {
+				return fmt.Errorf("upload aborted by user")
+			}
 
 			// Update mode if user chose "for all"
-			if action == UploadSkipAll || action == UploadOverwriteAll {
+			if isUploadConflictAll(action) {
 				conflictMode = action
 			}
 		}
 
-		switch action {
-		case UploadSkipOnce, UploadSkipAll:
+		target := existing.Resolve(fileName, action.duplicatePolicy())
+		switch {
+		case target.Skip:
 			fmt.Printf("⊘ Skipping duplicate: %s\n", fileName)
 			filesSkipped++
-		case UploadOverwriteOnce, UploadOverwriteAll:
+			continue
+		case target.Name != fileName:
+			fmt.Printf("✎ Uploading %s as %s\n", fileName, target.Name)
+			filesRenamed++
+		case len(target.ReplaceIDs) > 0:
+			fmt.Printf("⟳ Replacing existing: %s\n", fileName)
+			filesReplaced++
+		case target.Exists:
 			fmt.Printf("⊕ Uploading duplicate: %s\n", fileName)
-			filesToUpload = append(filesToUpload, filePath)
-		case UploadAbort:
-			return fmt.Errorf("upload aborted by user")
 		}
+		filesToUpload = append(filesToUpload, filePath)
+		targets = append(targets, uploadTarget{name: target.Name, replaceIDs: target.ReplaceIDs})
 	}
 
-	if filesSkipped > 0 {
-		fmt.Printf("\n📊 Pre-upload summary: %d file(s) to upload, %d skipped as duplicates\n\n", len(filesToUpload), filesSkipped)
+	if filesSkipped+filesRenamed+filesReplaced > 0 {
+		fmt.Printf("\n📊 Pre-upload summary: %d file(s) to upload (%d renamed, %d replacing existing), %d skipped as duplicates\n\n",
+			len(filesToUpload), filesRenamed, filesReplaced, filesSkipped)
 	}
 
 	if len(filesToUpload) == 0 {
@@ -193,6 +247,12 @@ func executeFileUploadWithDuplicateCheck(
 
 		fmt.Printf("\n📄 Files:\n")
 		fmt.Printf("  Would upload:     %d\n", len(filesToUpload))
+		if filesRenamed > 0 {
+			fmt.Printf("  Would rename:     %d (duplicates)\n", filesRenamed)
+		}
+		if filesReplaced > 0 {
+			fmt.Printf("  Would replace:    %d (existing files deleted after upload)\n", filesReplaced)
+		}
 		if filesSkipped > 0 {
 			fmt.Printf("  Would skip:       %d (duplicates)\n", filesSkipped)
 		}
@@ -207,6 +267,12 @@ func executeFileUploadWithDuplicateCheck(
 			fmt.Println("SKIP-DUPLICATES (skip existing files)")
 		case UploadDuplicateModeUploadAll:
 			fmt.Println("ALLOW-DUPLICATES (upload even if exists)")
+		case UploadDuplicateModeOverwrite:
+			fmt.Println("OVERWRITE (replace existing files)")
+		case UploadDuplicateModeRename:
+			fmt.Println("RENAME (upload duplicates as \"name (1).ext\")")
+		case UploadDuplicateModeVersion:
+			fmt.Println("VERSION (upload duplicates as \"name_v2.ext\")")
 		default:
 			fmt.Println("CHECK (prompt for each duplicate)")
 		}
@@ -218,10 +284,36 @@ func executeFileUploadWithDuplicateCheck(
 	}
 
 	// Upload the filtered files
-	_, err = UploadFilesWithIDs(ctx, filesToUpload, folderID, maxConcurrent, preEncrypt, uploadTags, apiClient, logger, false)
+	_, err = uploadFilesTo(ctx, filesToUpload, targets, conflictMode.onDuplicateFlag(), folderID, maxConcurrent, preEncrypt, verify, uploadTags, apiClient, logger, false)
 	return err
 }
 
+// isUploadConflictAll reports whether an action applies to every remaining
+// duplicate without prompting.
+func isUploadConflictAll(a UploadConflictAction) bool {
+	switch a {
+	case UploadSkipAll, UploadOverwriteAll, UploadRenameAll, UploadVersionAll, UploadKeepBothAll:
+		return true
+	}
+	return false
+}
+
+// onDuplicateFlag returns the --on-duplicate value that repeats an "all"
+// action, or "" when duplicates were decided one by one.
+func (a UploadConflictAction) onDuplicateFlag() string {
+	if !isUploadConflictAll(a) {
+		return ""
+	}
+	return string(a.duplicatePolicy())
+}
+
+// uploadTarget is where the duplicate-name policy sends one file: the name it
+// is registered under ("" = its base name) and the existing files it replaces.
+type uploadTarget struct {
+	name       string
+	replaceIDs []string
+}
+
 // UploadFilesWithIDs uploads files concurrently and returns their file IDs.
 // This is a shared helper for both 'files upload' and 'jobs submit --files'.
 // Returns file IDs in the same order as input files.
@@ -238,14 +330,35 @@ func UploadFilesWithIDs(
 	logger *logging.Logger,
 	silent bool, // If true, skip summary output (for use in job submission)
 ) ([]string, error) {
-	inthttp.WarmupProxyIfNeeded(ctx, apiClient.GetConfig())
-	credentials.GetManager(apiClient).WarmAll(ctx)
-
 	// Expand glob patterns
 	filePaths, err := expandGlobPatterns(filePatterns)
 	if err != nil {
 		return nil, err
 	}
+	return uploadFilesTo(ctx, filePaths, nil, "", folderID, maxConcurrent, preEncrypt, false, uploadTags, apiClient, logger, silent)
+}
+
+// uploadFilesTo uploads filePaths concurrently. targets, if non-nil, holds each
+// file's duplicate-name outcome: the name to register it under and existing
+// files to delete once it has uploaded. onDuplicate is repeated in the retry
+// command of the failure summary. With verify, each file is hashed again
+// after upload and fails with a *cloud.IntegrityError if it changed.
+func uploadFilesTo(
+	ctx context.Context,
+	filePaths []string,
+	targets []uploadTarget,
+	onDuplicate string,
+	folderID string,
+	maxConcurrent int,
+	preEncrypt bool,
+	verify bool,
+	uploadTags []string,
+	apiClient *api.Client,
+	logger *logging.Logger,
+	silent bool,
+) ([]string, error) {
+	inthttp.WarmupProxyIfNeeded(ctx, apiClient.GetConfig())
+	credentials.GetManager(apiClient).WarmAll(ctx)
 
 	if !silent {
 		logger.Info().
@@ -293,7 +406,9 @@ func UploadFilesWithIDs(
 	// when mixed with ANSI escape codes from mpb progress bars.
 	// The mpb library handles rendering progress bars above stderr output automatically.
 
-	defer uploadUI.Wait()
+	// Waited for explicitly before the failure summary so bars don't redraw over it
+	waitUI := sync.OnceFunc(uploadUI.Wait)
+	defer waitUI()
 
 	// Pre-allocate results slice to maintain order
 	uploadedFileIDs := make([]string, len(filePaths))
@@ -319,6 +434,10 @@ func UploadFilesWithIDs(
 	}
 	numWorkers := transfer.ComputedWorkers(items, cfg)
 
+	// A failed file doesn't stop the others; failures are summarized at the end
+	var failures []uploadFailure
+	var failuresMu sync.Mutex
+
 	// Upload each file concurrently via BatchExecutor
 	batchResult := transfer.RunBatch(ctx, items, cfg, func(ctx context.Context, item cliUploadItem) error {
 		fPath := item.path
@@ -334,10 +453,16 @@ func UploadFilesWithIDs(
 		var fileBar *progress.FileBar
 		var barOnce sync.Once
 
+		var target uploadTarget
+		if targets != nil {
+			target = targets[item.idx]
+		}
+
 		cloudFile, err := upload.UploadFile(ctx, upload.UploadParams{
-			LocalPath: fPath,
-			FolderID:  folderID,
-			APIClient: apiClient,
+			LocalPath:  fPath,
+			FolderID:   folderID,
+			APIClient:  apiClient,
+			RemoteName: target.name,
 			ProgressCallback: func(fraction float64) {
 				barOnce.Do(func() {
 					fileBar = uploadUI.AddFileBar(fPath, folderID, fileInfo.Size())
@@ -346,9 +471,11 @@ func UploadFilesWithIDs(
 					fileBar.UpdateProgress(fraction)
 				}
 			},
-			TransferHandle: transferHandle,
-			OutputWriter:   uploadUI.Writer(),
-			PreEncrypt:     preEncrypt,
+			TransferHandle:  transferHandle,
+			OutputWriter:    uploadUI.Writer(),
+			PreEncrypt:      preEncrypt,
+			VerifyIntegrity: verify,
+			ResumeDir:       config.GetTransferStateDir(),
 		})
 
 		if err != nil {
@@ -357,7 +484,11 @@ func UploadFilesWithIDs(
 			}
 			fileBar.Complete("", err)
 
-			if state.UploadResumeStateExists(fPath) {
+			failuresMu.Lock()
+			failures = append(failures, uploadFailure{idx: item.idx, path: fPath, err: err})
+			failuresMu.Unlock()
+
+			if uploadResumeStateExists(fPath) {
 				fmt.Fprintf(os.Stderr, "\n💡 Resume state saved. To resume this upload, run the same command again:\n")
 				fmt.Fprintf(os.Stderr, "   rescale-int files upload %s\n\n", fPath)
 			}
@@ -374,6 +505,17 @@ func UploadFilesWithIDs(
 			}
 		}
 
+		// Overwrite: the existing files go only once their replacement is in
+		for _, oldID := range target.replaceIDs {
+			if err := apiClient.DeleteFile(ctx, oldID); err != nil {
+				logger.Warn().Err(err).
+					Str("file", fPath).
+					Str("replacedFileID", oldID).
+					Msg("Failed to delete the file being replaced (non-fatal)")
+				fmt.Fprintf(uploadUI.Writer(), "⚠️  Uploaded %s but could not delete the existing file %s: %v\n", filepath.Base(fPath), oldID, err)
+			}
+		}
+
 		if fileBar == nil {
 			fileBar = uploadUI.AddFileBar(fPath, folderID, fileInfo.Size())
 		}
@@ -385,6 +527,10 @@ func UploadFilesWithIDs(
 
 	// Return first error but report count of all failures
 	if len(batchResult.Errors) > 0 {
+		waitUI()
+		if !silent && len(failures) > 0 {
+			printUploadFailureSummary(os.Stderr, failures, len(filePaths), folderID, uploadTags, preEncrypt, verify, onDuplicate)
+		}
 		if len(batchResult.Errors) == 1 {
 			return nil, batchResult.Errors[0]
 		}
@@ -404,3 +550,77 @@ func UploadFilesWithIDs(
 
 	return uploadedFileIDs, nil
 }
+
+// uploadFailure is one file that failed in a batch upload.
+type uploadFailure struct {
+	idx  int
+	path string
+	err  error
+}
+
+// printUploadFailureSummary lists each failed file with a one-line reason,
+// then a command that retries just those files.
+func printUploadFailureSummary(w io.Writer, failures []uploadFailure, total int, folderID string, uploadTags []string, preEncrypt, verify bool, onDuplicate string) {
+	sort.Slice(failures, func(i, j int) bool { return failures[i].idx < failures[j].idx })
+
+	fmt.Fprintf(w, "\n✗ %d of %d file(s) failed to upload:\n", len(failures), total)
+	for _, f := range failures {
+		fmt.Fprintf(w, "    %s: %s\n", filepath.Base(f.path), uploadFailureReason(f.err))
+	}
+
+	args := []string{"rescale-int", "files", "upload"}
+	if folderID != "" {
+		args = append(args, "--folder-id", folderID)
+	}
+	if len(uploadTags) > 0 {
+		args = append(args, "--tags", quoteCommandArg(strings.Join(uploadTags, ",")))
+	}
+	if preEncrypt {
+		args = append(args, "--pre-encrypt")
+	}
+	if verify {
+		args = append(args, "--verify")
+	}
+	if onDuplicate != "" {
+		args = append(args, "--on-duplicate", onDuplicate)
+	}
+	for _, f := range failures {
+		args = append(args, quoteCommandArg(f.path))
+	}
+	fmt.Fprintf(w, "\nRetry just the failed file(s):\n  %s\n\n", strings.Join(args, " "))
+}
+
+// uploadResumeStateExists reports whether a failed upload of path left
+// state behind that the same command will resume from.
+func uploadResumeStateExists(path string) bool {
+	return state.UploadResumeStateExists(path) || state.MultipartStateExists(config.GetTransferStateDir(), path)
+}
+
+// uploadFailureReason shortens an upload error to one line for the failure
+// summary, keeping the part classification ("part 3 of 12 failed
+// (permanent): ...") at the front.
+func uploadFailureReason(err error) string {
+	var partErr *cloudtransfer.PartError
+	if errors.As(err, &partErr) {
+		err = partErr
+	}
+	reason := sanitizeErrorString(err.Error())
+	if i := strings.IndexByte(reason, '\n'); i >= 0 {
+		reason = reason[:i]
+	}
+	if r := []rune(reason); len(r) > uploadFailureReasonMax {
+		reason = string(r[:uploadFailureReasonMax-1]) + "…"
+	}
+	return reason
+}
+
+const uploadFailureReasonMax = 160
+
+// quoteCommandArg double-quotes s for a copy-paste command line when it
+// contains spaces or shell metacharacters.
+func quoteCommandArg(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t'\"$&;|<>()*?`!") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
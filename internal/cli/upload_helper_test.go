@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	cloudtransfer "github.com/rescale/rescale-int/internal/cloud/transfer"
+)
+
+func TestPrintUploadFailureSummary(t *testing.T) {
+	rejected := cloudtransfer.NewPartError(2, 12, errors.New("InvalidPart: 400 Bad Request"))
+	failures := []uploadFailure{
+		{idx: 4, path: "/data/run two/big.dat", err: fmt.Errorf("failed to upload /data/run two/big.dat: %w", errors.New("disk read error\ndetails"))},
+		{idx: 1, path: "/data/model.tar", err: fmt.Errorf("failed to upload /data/model.tar: %w", rejected)},
+	}
+
+	var out bytes.Buffer
+	printUploadFailureSummary(&out, failures, 10, "abc123", []string{"cfd", "v2"}, false, false, "rename")
+	got := out.String()
+
+	for _, want := range []string{
+		"✗ 2 of 10 file(s) failed to upload:",
+		"    model.tar: part 3 of 12 failed (permanent): InvalidPart: 400 Bad Request\n",
+		"    big.dat: failed to upload /data/run two/big.dat: disk read error\n",
+		`rescale-int files upload --folder-id abc123 --tags cfd,v2 --on-duplicate rename /data/model.tar "/data/run two/big.dat"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "model.tar:") > strings.Index(got, "big.dat:") {
+		t.Errorf("failures not in input order:\n%s", got)
+	}
+}
+
+func TestResolveRepeatedUploads(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.dat", "b.dat"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b := filepath.Join(dir, "a.dat"), filepath.Join(dir, "b.dat")
+
+	// a.dat is named by its path and by the pattern
+	paths, err := expandUploadArgs([]string{a, filepath.Join(dir, "*.dat")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{a, a, b}; !slices.Equal(paths, want) {
+		t.Fatalf("expandUploadArgs() = %v, want %v", paths, want)
+	}
+
+	// Tests have no terminal, so repeats are skipped without asking
+	once, err := resolveRepeatedUploads(paths, false)
+	if err != nil || !slices.Equal(once, []string{a, b}) {
+		t.Errorf("resolveRepeatedUploads() = %v, %v, want each file once", once, err)
+	}
+	again, err := resolveRepeatedUploads(paths, true)
+	if err != nil || !slices.Equal(again, paths) {
+		t.Errorf("resolveRepeatedUploads(allowRepeats) = %v, %v, want %v", again, err, paths)
+	}
+}
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+// applyViewerMode turns on read-only viewer mode when viewer_mode is set,
+// before the command does anything. The config is read here because
+// commands that only browse may never load it.
+func applyViewerMode() {
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = config.GetDefaultConfigPath()
+	}
+	on := false
+	if cfg, err := config.LoadConfigCSV(configPath); err == nil {
+		on = cfg.ViewerMode
+	}
+	viewer.SetEnabled(on)
+}
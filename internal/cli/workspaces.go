@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// workspaceFlag is the global --workspace flag: a workspace ID or name that
+// overrides the config's workspace for one command.
+var workspaceFlag string
+
+// resolvedWorkspaces memoizes --workspace resolution, since some commands
+// load the config more than once.
+var resolvedWorkspaces = map[string]string{}
+
+// applyWorkspaceFlag scopes cfg to the --workspace flag, if given. A name is
+// looked up among the user's workspaces; if they can't be listed, the value
+// is used as an ID as is.
+func applyWorkspaceFlag(cfg *config.Config) error {
+	ref := strings.TrimSpace(workspaceFlag)
+	if ref == "" {
+		return nil
+	}
+	if id, ok := resolvedWorkspaces[ref]; ok {
+		cfg.Workspace = id
+		return nil
+	}
+
+	// List with the key's default workspace, not the configured one
+	lookup := *cfg
+	lookup.Workspace = ""
+	client, err := api.NewClient(&lookup)
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+	workspaces, err := client.ListWorkspaces(GetContext())
+	if err != nil {
+		GetLogger().Warn().Err(err).Str("workspace", ref).Msg("Could not list workspaces; using --workspace as an ID")
+		cfg.Workspace = ref
+		return nil
+	}
+	w, err := api.FindWorkspace(workspaces, ref)
+	if err != nil {
+		return fmt.Errorf("--workspace: %w", err)
+	}
+	resolvedWorkspaces[ref] = w.ID
+	cfg.Workspace = w.ID
+	return nil
+}
+
+// newWorkspacesCmd creates the 'workspaces' command group.
+func newWorkspacesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspaces",
+		Short: "List and switch Rescale workspaces",
+		Long: `Users in several Rescale workspaces can scope file browsing, uploads and
+job submission to one of them, with the same API key.
+
+'workspaces use' saves the choice as "workspace" in the config; the global
+--workspace flag (ID or name) overrides it for a single command.`,
+	}
+
+	cmd.AddCommand(newWorkspacesListCmd())
+	cmd.AddCommand(newWorkspacesUseCmd())
+
+	return cmd
+}
+
+func newWorkspacesListCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your workspaces",
+		Long: `List the workspaces you belong to. The active one is marked with *.
+
+Examples:
+  rescale-int workspaces list
+  rescale-int workspaces list --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := GetContext()
+
+			apiClient, err := getAPIClient()
+			if err != nil {
+				return err
+			}
+
+			workspaces, err := apiClient.ListWorkspaces(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list workspaces: %w", err)
+			}
+
+			active := apiClient.Workspace()
+			if active == "" {
+				// The API key's default workspace
+				if profile, err := apiClient.GetUserProfile(ctx); err == nil {
+					active = profile.Workspace.ID
+				}
+			}
+
+			if outputJSON {
+				type workspaceJSON struct {
+					models.WorkspaceInfo
+					Active bool `json:"active"`
+				}
+				out := make([]workspaceJSON, len(workspaces))
+				for i, w := range workspaces {
+					out[i] = workspaceJSON{WorkspaceInfo: w, Active: w.ID == active}
+				}
+				data, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(workspaces) == 0 {
+				fmt.Println("No workspaces found")
+				return nil
+			}
+
+			fmt.Printf("%-2s %-12s %s\n", "", "ID", "NAME")
+			fmt.Println(strings.Repeat("-", 50))
+			for _, w := range workspaces {
+				mark := ""
+				if w.ID == active {
+					mark = "*"
+				}
+				fmt.Printf("%-2s %-12s %s\n", mark, w.ID, w.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&outputJSON, "json", "J", false, "Output as JSON")
+
+	return cmd
+}
+
+func newWorkspacesUseCmd() *cobra.Command {
+	var useDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "use [workspace-id-or-name]",
+		Short: "Save the workspace to work in",
+		Long: `Save the workspace that file browsing, uploads and job submission use, as
+"workspace" in the config. The API key is not changed.
+
+Examples:
+  rescale-int workspaces use CFD
+  rescale-int workspaces use AbCdE
+  rescale-int workspaces use --default   # back to the API key's default workspace`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if useDefault == (len(args) == 1) {
+				return fmt.Errorf("give a workspace ID or name, or --default")
+			}
+
+			configPath := cfgFile
+			if configPath == "" {
+				configPath = config.GetDefaultConfigPath()
+			}
+			// Edit the file as saved, without flags or environment merged in
+			saved, err := config.LoadConfigCSV(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load config %s (run 'rescale-int config init' first): %w", configPath, err)
+			}
+
+			var chosen models.WorkspaceInfo
+			if !useDefault {
+				apiClient, err := getAPIClient()
+				if err != nil {
+					return err
+				}
+				workspaces, err := apiClient.ListWorkspaces(GetContext())
+				if err != nil {
+					return fmt.Errorf("failed to list workspaces: %w", err)
+				}
+				if chosen, err = api.FindWorkspace(workspaces, args[0]); err != nil {
+					return err
+				}
+			}
+
+			saved.Workspace = chosen.ID
+			if err := config.SaveConfigCSV(saved, configPath); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+
+			if useDefault {
+				fmt.Println("✓ Using the API key's default workspace")
+			} else {
+				fmt.Printf("✓ Using workspace %s (%s)\n", chosen.Name, chosen.ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&useDefault, "default", false, "Go back to the API key's default workspace")
+
+	return cmd
+}
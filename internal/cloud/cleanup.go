@@ -0,0 +1,31 @@
+package cloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// Cleanup runs fn to release remote state after a transfer failed, e.g.
+// aborting a multipart upload. fn gets a context that outlives ctx's
+// cancellation (bounded by CancelCleanupTimeout): handing it the cancelled
+// transfer context would fail the request before it is sent and leave the
+// upload orphaned.
+//
+// When ctx was cancelled, fn runs in the background so the cleanup round
+// trip does not delay the cancel. Otherwise it runs before Cleanup returns.
+func Cleanup(ctx context.Context, operation string, fn func(context.Context) error) {
+	run := func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), constants.CancelCleanupTimeout)
+		defer cancel()
+		if err := fn(cleanupCtx); err != nil {
+			log.Printf("Warning: %s failed: %v", operation, err)
+		}
+	}
+	if ctx.Err() != nil {
+		go run()
+		return
+	}
+	run()
+}
@@ -4,9 +4,11 @@ package download
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"strings"
@@ -18,8 +20,14 @@ import (
 	"github.com/rescale/rescale-int/internal/cloud/providers"
 	"github.com/rescale/rescale-int/internal/cloud/state"
 	cloudtransfer "github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/tracing"
 	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DownloadParams consolidates all parameters for download operations.
@@ -53,6 +61,16 @@ type DownloadParams struct {
 	// false (default) = strict mode - fail on checksum mismatch
 	// true = skip mode - warn but don't fail on checksum mismatch
 	SkipChecksum bool
+
+	// Optional: Re-read the finished file from disk and compare it against the
+	// plaintext SHA-256 recorded at upload (the SHA-512 or size for files
+	// uploaded without one). A mismatch is a *cloud.IntegrityError and is
+	// downloaded again like any other integrity failure.
+	Verify bool
+
+	// Optional: Called before a file that failed verification is
+	// downloaded again; err wraps cloud.ErrChecksumMismatch or cloud.ErrSizeMismatch
+	OnIntegrityRetry func(attempt int, err error)
 }
 
 // DownloadFile is THE ONLY canonical entry point for downloading files from Rescale cloud storage.
@@ -62,10 +80,45 @@ type DownloadParams struct {
 //   - Automatically detects encryption format (legacy v0 or streaming v1)
 //   - Uses concurrent chunk downloads if TransferHandle has threads > 1
 //   - Supports resume from partial downloads
-//   - Verifies SHA-512 checksum after download (unless SkipChecksum=true)
+//   - Verifies SHA-512 checksum after download (unless SkipChecksum=true), or
+//     the size when the platform published no checksum
+//   - With Verify, re-reads the file and checks its plaintext SHA-256
+//   - Downloads a file that fails verification again, up to
+//     constants.DownloadIntegrityRetries times
 //
 // Returns nil on success, or an error on failure.
-func DownloadFile(ctx context.Context, params DownloadParams) error {
+func DownloadFile(ctx context.Context, params DownloadParams) (err error) {
+	fileID := params.FileID
+	if fileID == "" && params.FileInfo != nil {
+		fileID = params.FileInfo.ID
+	}
+	if err := viewer.Check("download " + fileID); err != nil {
+		return err
+	}
+	ctx, span := tracing.Start(ctx, "download.file", attribute.String("rescale.file_id", fileID))
+	defer func() { tracing.End(span, err) }()
+
+	for attempt := 1; ; attempt++ {
+		err = downloadFile(ctx, params)
+		if !IsIntegrityError(err) || attempt > constants.DownloadIntegrityRetries || ctx.Err() != nil {
+			return err
+		}
+		span.AddEvent("integrity retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		if params.OnIntegrityRetry != nil {
+			params.OnIntegrityRetry(attempt, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %s failed verification, downloading again (retry %d of %d)\n",
+				params.LocalPath, attempt, constants.DownloadIntegrityRetries)
+		}
+		// Start from scratch: resuming would keep the corrupted bytes
+		_ = os.Remove(params.LocalPath)
+		_ = securedelete.Remove(params.LocalPath + ".encrypted")
+		state.DeleteDownloadState(params.LocalPath)
+	}
+}
+
+// downloadFile makes one attempt at DownloadFile.
+func downloadFile(ctx context.Context, params DownloadParams) error {
 	overallTimer := cloud.StartTimer(params.OutputWriter, "Download total")
 
 	// Validate required parameters
@@ -80,12 +133,14 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 	}
 
 	initTimer := cloud.StartTimer(params.OutputWriter, "Download initialization")
+	initCtx, initSpan := tracing.Start(ctx, "download.init")
+	defer initSpan.End() // For the error returns below
 
 	// Get file metadata (if not already provided)
 	fileInfo := params.FileInfo
 	if fileInfo == nil {
 		var err error
-		fileInfo, err = params.APIClient.GetFileInfo(ctx, params.FileID)
+		fileInfo, err = params.APIClient.GetFileInfo(initCtx, params.FileID)
 		if err != nil {
 			return fmt.Errorf("failed to get file info: %w", err)
 		}
@@ -103,7 +158,7 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 	if fileInfo.Storage != nil && fileInfo.Storage.StorageType != "" {
 		storageInfo = getStorageInfo(fileInfo, nil)
 	} else {
-		profile, err := credManager.GetUserProfile(ctx)
+		profile, err := credManager.GetUserProfile(initCtx)
 		if err != nil {
 			return fmt.Errorf("failed to get user profile: %w", err)
 		}
@@ -112,12 +167,17 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 
 	// Create provider using factory (S3 or Azure based on storage type)
 	factory := providers.NewFactory()
-	provider, err := factory.NewTransferFromStorageInfo(ctx, storageInfo, params.APIClient)
+	provider, err := factory.NewTransferFromStorageInfo(initCtx, storageInfo, params.APIClient)
 	if err != nil {
 		return fmt.Errorf("failed to create provider: %w", err)
 	}
 
 	initTimer.StopWithMessage("backend=%s", storageInfo.StorageType)
+	initSpan.SetAttributes(
+		attribute.String("file.name", fileInfo.Name),
+		attribute.Int64("file.size", fileInfo.DecryptedSize),
+		attribute.String("rescale.storage_type", storageInfo.StorageType))
+	initSpan.End()
 
 	// Determine the remote path for download
 	remotePath := fileInfo.Path
@@ -164,6 +224,14 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 	if fi.Size() == 0 && fileInfo.DecryptedSize > 0 {
 		return fmt.Errorf("download failed: file is empty (0 bytes) - possible write error or filesystem issue")
 	}
+	if expectedHash := getExpectedSHA512(fileInfo.FileChecksums); expectedHash == "" && fileInfo.DecryptedSize > 0 && fi.Size() != fileInfo.DecryptedSize {
+		// No checksum to go by: the size is the only check there is
+		err := fmt.Errorf("%w: expected %d bytes, got %d", cloud.ErrSizeMismatch, fileInfo.DecryptedSize, fi.Size())
+		if !params.SkipChecksum {
+			return fmt.Errorf("verification failed for %s: %w", params.LocalPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: Verification failed for %s: %v\n", params.LocalPath, err)
+	}
 
 	checksumTimer := cloud.StartTimer(params.OutputWriter, "Checksum verification")
 
@@ -175,9 +243,9 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 	if computedHash != "" && expectedHash != "" {
 		// Compare using hash computed during download - no file re-read needed
 		if !strings.EqualFold(computedHash, expectedHash) {
-			checksumErr = fmt.Errorf("checksum mismatch: expected SHA-512=%s, got %s", expectedHash, computedHash)
+			checksumErr = &cloud.IntegrityError{Path: params.LocalPath, Algorithm: "SHA-512", Expected: expectedHash, Actual: computedHash}
 		}
-	} else if expectedHash != "" {
+	} else if expectedHash != "" || getExpectedSHA256(fileInfo.FileChecksums) != "" {
 		// Fallback: No computed hash available, use traditional file re-read verification
 		checksumErr = verifyChecksum(params.LocalPath, fileInfo.FileChecksums)
 	}
@@ -196,13 +264,21 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 
 	checksumTimer.StopWithThroughput(fileInfo.DecryptedSize)
 
+	if params.Verify {
+		verifyTimer := cloud.StartTimer(params.OutputWriter, "Integrity verification")
+		if err := verifyIntegrity(params.LocalPath, fileInfo); err != nil {
+			return fmt.Errorf("verification failed for %s: %w", params.LocalPath, err)
+		}
+		verifyTimer.StopWithThroughput(fileInfo.DecryptedSize)
+	}
+
 	overallTimer.StopWithThroughput(fileInfo.DecryptedSize)
 
 	// Safety net: clean up any leftover .encrypted temp file from legacy path.
 	// Only on success -- failed downloads may need the .encrypted file for resume.
 	// This covers the case where the downloader's own defer ran but failed
 	// (e.g., Windows file locking released after a delay).
-	_ = os.Remove(params.LocalPath + ".encrypted")
+	_ = securedelete.Remove(params.LocalPath + ".encrypted")
 
 	// Clean up resume state file on successful download.
 	// This prevents stale resume state from accumulating and ensures
@@ -212,6 +288,37 @@ func DownloadFile(ctx context.Context, params DownloadParams) error {
 	return nil
 }
 
+// ReadStoredRange returns length bytes at offset of fileInfo's stored
+// object, still encrypted. `history verify` compares these with the block
+// hashes sampled at upload, a content check without a full download.
+func ReadStoredRange(ctx context.Context, apiClient *api.Client, fileInfo *models.CloudFile, offset, length int64) ([]byte, error) {
+	var storageInfo *models.StorageInfo
+	if fileInfo.Storage != nil && fileInfo.Storage.StorageType != "" {
+		storageInfo = getStorageInfo(fileInfo, nil)
+	} else {
+		profile, err := credentials.GetManager(apiClient).GetUserProfile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user profile: %w", err)
+		}
+		storageInfo = getStorageInfo(fileInfo, profile)
+	}
+
+	provider, err := providers.NewFactory().NewTransferFromStorageInfo(ctx, storageInfo, apiClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider: %w", err)
+	}
+	reader, ok := provider.(cloudtransfer.StreamingPartDownloader)
+	if !ok {
+		return nil, fmt.Errorf("%s storage does not support ranged reads", storageInfo.StorageType)
+	}
+
+	remotePath := fileInfo.Path
+	if fileInfo.PathParts != nil && fileInfo.PathParts.Path != "" {
+		remotePath = fileInfo.PathParts.Path
+	}
+	return reader.DownloadEncryptedRange(ctx, remotePath, offset, length, nil)
+}
+
 // getExpectedSHA512 extracts the expected SHA-512 hash from checksums.
 // Returns empty string if no SHA-512 checksum is available.
 func getExpectedSHA512(checksums []models.FileChecksum) string {
@@ -224,6 +331,18 @@ func getExpectedSHA512(checksums []models.FileChecksum) string {
 	return ""
 }
 
+// getExpectedSHA256 extracts the plaintext SHA-256 recorded at upload.
+// Returns empty string for files uploaded without one.
+func getExpectedSHA256(checksums []models.FileChecksum) string {
+	for _, cs := range checksums {
+		switch cs.HashFunction {
+		case "sha256", "SHA-256", "SHA256":
+			return cs.FileHash
+		}
+	}
+	return ""
+}
+
 // getStorageInfo determines the correct storage configuration for a file
 // Uses fileInfo.Storage if available (for job outputs or files in different storage)
 // Falls back to profile.DefaultStorage if fileInfo.Storage is nil (backwards compatibility)
@@ -249,7 +368,8 @@ func getStorageInfo(fileInfo *models.CloudFile, profile *models.UserProfile) *mo
 	return &profile.DefaultStorage
 }
 
-// verifyChecksum verifies the SHA-512 checksum of a downloaded file
+// verifyChecksum verifies the SHA-512 checksum of a downloaded file, or the
+// SHA-256 one when that's all there is
 // Returns an error if the checksum verification fails
 // Note: This is called AFTER decryption, so it verifies the decrypted file
 func verifyChecksum(localPath string, checksums []models.FileChecksum) error {
@@ -271,11 +391,16 @@ checksumLoop:
 		}
 	}
 
+	newHash := sha512.New
+	if expectedHash == "" {
+		expectedHash, hashAlgorithm, newHash = getExpectedSHA256(checksums), "SHA-256", sha256.New
+	}
+
 	if expectedHash == "" {
-		// No SHA-512 checksum found, check for other algorithms
+		// No SHA-512 or SHA-256 checksum found, check for other algorithms
 		for _, cs := range checksums {
 			if cs.HashFunction != "" && cs.FileHash != "" {
-				return fmt.Errorf("file has checksum with algorithm %s, but SHA-512 verification is not implemented for this algorithm", cs.HashFunction)
+				return fmt.Errorf("file has checksum with algorithm %s, but verification is not implemented for this algorithm", cs.HashFunction)
 			}
 		}
 		return nil // No recognized checksum algorithm
@@ -289,7 +414,7 @@ checksumLoop:
 	var lastActualHash string
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		actualHash, err := computeFileChecksum(localPath)
+		actualHash, err := computeFileHash(localPath, newHash)
 		if err != nil {
 			if attempt < maxRetries {
 				time.Sleep(100 * time.Millisecond)
@@ -312,18 +437,18 @@ checksumLoop:
 	}
 
 	// All retries failed
-	return fmt.Errorf("checksum mismatch: expected %s=%s, got %s (after %d attempts)", hashAlgorithm, expectedHash, lastActualHash, maxRetries)
+	return &cloud.IntegrityError{Path: localPath, Algorithm: hashAlgorithm, Expected: expectedHash, Actual: lastActualHash}
 }
 
-// computeFileChecksum opens a file and computes its SHA-512 hash.
-func computeFileChecksum(localPath string) (string, error) {
+// computeFileHash opens a file and hashes it with newHash.
+func computeFileHash(localPath string, newHash func() hash.Hash) (string, error) {
 	file, err := os.Open(localPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	hash := sha512.New()
+	hash := newHash()
 	if _, err := io.Copy(hash, file); err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
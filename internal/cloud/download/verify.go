@@ -0,0 +1,123 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Verification methods, strongest first.
+const (
+	VerifiedBySHA512 = "sha512"
+	VerifiedBySHA256 = "sha256"
+	VerifiedBySize   = "size"
+)
+
+// IsIntegrityError reports whether err is a downloaded file not matching the
+// platform's checksum or size, including a *cloud.IntegrityError.
+func IsIntegrityError(err error) bool {
+	return errors.Is(err, cloud.ErrChecksumMismatch) || errors.Is(err, cloud.ErrSizeMismatch)
+}
+
+// VerifyTarget is a local file to check against the file it was downloaded from.
+type VerifyTarget struct {
+	LocalPath string
+	File      *models.CloudFile
+}
+
+// Verification is the outcome of checking one VerifyTarget.
+type Verification struct {
+	VerifyTarget
+	Method string // VerifiedBySHA512, VerifiedBySHA256 or VerifiedBySize
+	Err    error  // nil if the file matches; wraps cloud.ErrChecksumMismatch or cloud.ErrSizeMismatch on a mismatch
+}
+
+// VerifyFile checks a local file against the SHA-512 checksum the platform
+// published for it, the plaintext SHA-256 recorded at upload when there is
+// no SHA-512, or only its size when there is neither.
+func VerifyFile(target VerifyTarget) Verification {
+	v := Verification{VerifyTarget: target, Method: VerifiedBySize}
+	info, err := os.Stat(target.LocalPath)
+	if err != nil {
+		v.Err = err
+		return v
+	}
+	if info.Size() != target.File.DecryptedSize {
+		v.Err = fmt.Errorf("%w: expected %d bytes, got %d", cloud.ErrSizeMismatch, target.File.DecryptedSize, info.Size())
+		return v
+	}
+
+	method, algorithm, newHash := VerifiedBySHA512, "SHA-512", sha512.New
+	expected := getExpectedSHA512(target.File.FileChecksums)
+	if expected == "" {
+		method, algorithm, newHash = VerifiedBySHA256, "SHA-256", sha256.New
+		expected = getExpectedSHA256(target.File.FileChecksums)
+	}
+	if expected == "" {
+		return v
+	}
+	v.Method = method
+	actual, err := computeFileHash(target.LocalPath, newHash)
+	switch {
+	case err != nil:
+		v.Err = err
+	case !strings.EqualFold(actual, expected):
+		v.Err = &cloud.IntegrityError{Path: target.LocalPath, Algorithm: algorithm, Expected: expected, Actual: actual}
+	}
+	return v
+}
+
+// verifyIntegrity re-reads a downloaded file and compares it against the
+// plaintext SHA-256 recorded at upload, falling back to VerifyFile for files
+// uploaded without one.
+func verifyIntegrity(localPath string, file *models.CloudFile) error {
+	expected := getExpectedSHA256(file.FileChecksums)
+	if expected == "" {
+		return VerifyFile(VerifyTarget{LocalPath: localPath, File: file}).Err
+	}
+	actual, err := computeFileHash(localPath, sha256.New)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, expected) {
+		return &cloud.IntegrityError{Path: localPath, Algorithm: "SHA-256", Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// VerifyFiles checks targets with up to workers files hashed at a time and
+// returns the outcomes in the order of targets. Targets not reached before
+// ctx is cancelled fail with its error.
+func VerifyFiles(ctx context.Context, targets []VerifyTarget, workers int) []Verification {
+	results := make([]Verification, len(targets))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		if ctx.Err() != nil {
+			results[i] = Verification{VerifyTarget: target, Err: ctx.Err()}
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = Verification{VerifyTarget: target, Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, target VerifyTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = VerifyFile(target)
+		}(i, target)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,158 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// writeTarget writes content to a temp file and returns a target expecting
+// size bytes, with a SHA-512 of sum if it isn't empty.
+func writeTarget(t *testing.T, content string, size int64, sum string) VerifyTarget {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.dat")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file := &models.CloudFile{Name: "out.dat", DecryptedSize: size}
+	if sum != "" {
+		file.FileChecksums = []models.FileChecksum{{HashFunction: "sha512", FileHash: sum}}
+	}
+	return VerifyTarget{LocalPath: path, File: file}
+}
+
+func sha512Hex(s string) string {
+	sum := sha512.Sum512([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		size       int64
+		sum        string
+		wantMethod string
+		wantErr    error
+	}{
+		{"size only", "hello", 5, "", VerifiedBySize, nil},
+		{"size mismatch", "hell", 5, "", VerifiedBySize, cloud.ErrSizeMismatch},
+		{"sha512", "hello", 5, sha512Hex("hello"), VerifiedBySHA512, nil},
+		{"sha512 mismatch", "hellO", 5, sha512Hex("hello"), VerifiedBySHA512, cloud.ErrChecksumMismatch},
+		{"size checked before hashing", "hi", 5, sha512Hex("hello"), VerifiedBySize, cloud.ErrSizeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := VerifyFile(writeTarget(t, tt.content, tt.size, tt.sum))
+			if v.Method != tt.wantMethod {
+				t.Errorf("Method = %q, want %q", v.Method, tt.wantMethod)
+			}
+			if tt.wantErr == nil && v.Err != nil {
+				t.Errorf("Err = %v, want nil", v.Err)
+			}
+			if tt.wantErr != nil && (!errors.Is(v.Err, tt.wantErr) || !IsIntegrityError(v.Err)) {
+				t.Errorf("Err = %v, want %v", v.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyFile_Missing(t *testing.T) {
+	target := VerifyTarget{LocalPath: filepath.Join(t.TempDir(), "gone"), File: &models.CloudFile{DecryptedSize: 1}}
+	v := VerifyFile(target)
+	if !errors.Is(v.Err, os.ErrNotExist) || IsIntegrityError(v.Err) {
+		t.Errorf("Err = %v, want a not-exist error", v.Err)
+	}
+}
+
+func TestVerifyFiles_KeepsOrder(t *testing.T) {
+	var targets []VerifyTarget
+	for i := 0; i < 10; i++ {
+		size := int64(5)
+		if i%3 == 0 {
+			size = 6
+		}
+		targets = append(targets, writeTarget(t, "hello", size, ""))
+	}
+
+	results := VerifyFiles(context.Background(), targets, 3)
+	if len(results) != len(targets) {
+		t.Fatalf("got %d results, want %d", len(results), len(targets))
+	}
+	for i, v := range results {
+		if v.LocalPath != targets[i].LocalPath {
+			t.Errorf("result %d is for %s, want %s", i, v.LocalPath, targets[i].LocalPath)
+		}
+		if failed := v.Err != nil; failed != (i%3 == 0) {
+			t.Errorf("result %d: Err = %v", i, v.Err)
+		}
+	}
+}
+
+func TestVerifyFiles_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := VerifyFiles(ctx, []VerifyTarget{writeTarget(t, "hello", 5, ""), writeTarget(t, "hello", 5, "")}, 1)
+	for i, v := range results {
+		if !errors.Is(v.Err, context.Canceled) {
+			t.Errorf("result %d: Err = %v, want context.Canceled", i, v.Err)
+		}
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyFile_SHA256Only(t *testing.T) {
+	target := writeTarget(t, "hellO", 5, "")
+	target.File.FileChecksums = []models.FileChecksum{{HashFunction: "sha256", FileHash: sha256Hex("hello")}}
+	v := VerifyFile(target)
+	if v.Method != VerifiedBySHA256 {
+		t.Errorf("Method = %q, want %q", v.Method, VerifiedBySHA256)
+	}
+	var integrityErr *cloud.IntegrityError
+	if !errors.As(v.Err, &integrityErr) || integrityErr.Algorithm != "SHA-256" || !IsIntegrityError(v.Err) {
+		t.Errorf("Err = %v, want a SHA-256 IntegrityError", v.Err)
+	}
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		sums     []models.FileChecksum
+		wantAlgo string // "" if the file should verify
+	}{
+		{"sha256", "hello", []models.FileChecksum{{HashFunction: "sha512", FileHash: sha512Hex("hello")}, {HashFunction: "sha256", FileHash: sha256Hex("hello")}}, ""},
+		{"sha256 mismatch", "hellO", []models.FileChecksum{{HashFunction: "sha256", FileHash: sha256Hex("hello")}}, "SHA-256"},
+		{"sha256 preferred", "hellO", []models.FileChecksum{{HashFunction: "sha512", FileHash: sha512Hex("hellO")}, {HashFunction: "sha256", FileHash: sha256Hex("hello")}}, "SHA-256"},
+		{"sha512 fallback", "hellO", []models.FileChecksum{{HashFunction: "sha512", FileHash: sha512Hex("hello")}}, "SHA-512"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := writeTarget(t, tt.content, int64(len(tt.content)), "")
+			target.File.FileChecksums = tt.sums
+			err := verifyIntegrity(target.LocalPath, target.File)
+			if tt.wantAlgo == "" {
+				if err != nil {
+					t.Errorf("verifyIntegrity = %v, want nil", err)
+				}
+				return
+			}
+			var integrityErr *cloud.IntegrityError
+			if !errors.As(err, &integrityErr) || integrityErr.Algorithm != tt.wantAlgo || integrityErr.Path != target.LocalPath {
+				t.Errorf("verifyIntegrity = %v, want a %s IntegrityError", err, tt.wantAlgo)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package history keeps a local log of completed uploads and re-checks them
+// against the platform for integrity audits (`rescale-int history verify`).
+//
+// Each platform has its own append-only JSON Lines file (see
+// config.GetUploadHistoryPath) with one Entry per registered file, written
+// by upload.UploadFile once registration succeeds.
+package history
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+)
+
+// Entry records one completed upload.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	FileID      string    `json:"fileId"`
+	Name        string    `json:"name"`
+	LocalPath   string    `json:"localPath"`
+	Size        int64     `json:"size"`
+	SHA512      string    `json:"sha512"` // Plaintext hash registered with the file
+	StorageType string    `json:"storageType,omitempty"`
+	TransferMs  int64     `json:"transferMs,omitempty"` // Time spent moving bytes to storage
+	Blocks      []Block   `json:"blocks,omitempty"`     // Sampled ciphertext blocks for content checks
+}
+
+// Block is a hashed range of the stored (encrypted) object, sampled at
+// upload so `history verify` can check stored content with a few ranged
+// reads instead of a full download.
+type Block struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// Append adds e to the log at path, creating the file and its directory if
+// needed.
+func Append(path string, e Entry) error {
+	return jsonl.Append(path, "upload history", e)
+}
+
+// Load returns the entries at path recorded at or after since, oldest first.
+// A missing file yields no entries; malformed lines (e.g. an interrupted
+// write) are skipped.
+func Load(path string, since time.Time) ([]Entry, error) {
+	var entries []Entry
+	err := jsonl.Read(path, "upload history", func(e Entry) {
+		if e.FileID != "" && !e.Time.Before(since) {
+			entries = append(entries, e)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// Sample returns n entries chosen uniformly at random, oldest first. If n
+// is not less than len(entries), all entries are returned.
+func Sample(entries []Entry, n int, rng *rand.Rand) []Entry {
+	if n >= len(entries) {
+		return entries
+	}
+	picked := rng.Perm(len(entries))[:n]
+	sort.Ints(picked)
+	out := make([]Entry, 0, n)
+	for _, i := range picked {
+		out = append(out, entries[i])
+	}
+	return out
+}
+
+// Throughput estimation considers only recent uploads large enough for the
+// transfer, rather than per-file setup, to dominate their duration.
+const (
+	throughputSamples = 50
+	throughputMinSize = 16 * 1024 * 1024
+)
+
+// Throughput returns the measured upload rate in bytes per second over the
+// most recent large entries that recorded a transfer time. Each entry's time
+// is counted in full even when uploads ran side by side, so the rate is a
+// conservative per-stream figure. ok is false when there are no samples.
+func Throughput(entries []Entry) (bytesPerSec float64, ok bool) {
+	var bytes, ms int64
+	n := 0
+	for i := len(entries) - 1; i >= 0 && n < throughputSamples; i-- {
+		e := entries[i]
+		if e.TransferMs <= 0 || e.Size < throughputMinSize {
+			continue
+		}
+		bytes += e.Size
+		ms += e.TransferMs
+		n++
+	}
+	if ms == 0 {
+		return 0, false
+	}
+	return float64(bytes) / (float64(ms) / 1000), true
+}
@@ -0,0 +1,188 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "platform.jsonl")
+	now := time.Now().UTC()
+
+	for i, age := range []time.Duration{40 * 24 * time.Hour, 2 * time.Hour, time.Hour} {
+		e := Entry{Time: now.Add(-age), FileID: string(rune('a' + i)), Name: "f", Size: 10, SHA512: "abc"}
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Interrupted write leaves a partial line
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	f.WriteString(`{"time":"2026-`)
+	f.Close()
+
+	entries, err := Load(path, now.Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].FileID != "b" || entries[1].FileID != "c" {
+		t.Errorf("entries = %+v, want b and c", entries)
+	}
+
+	missing, err := Load(filepath.Join(t.TempDir(), "none.jsonl"), time.Time{})
+	if err != nil || len(missing) != 0 {
+		t.Errorf("missing file: %v, %v", missing, err)
+	}
+}
+
+func TestSample(t *testing.T) {
+	var entries []Entry
+	for i := 0; i < 10; i++ {
+		entries = append(entries, Entry{Time: time.Unix(int64(i), 0), FileID: string(rune('a' + i))})
+	}
+	got := Sample(entries, 4, rand.New(rand.NewSource(1)))
+	if len(got) != 4 {
+		t.Fatalf("len = %d, want 4", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if !got[i-1].Time.Before(got[i].Time) {
+			t.Errorf("sample not in order: %+v", got)
+		}
+	}
+	if all := Sample(entries, 20, rand.New(rand.NewSource(1))); len(all) != 10 {
+		t.Errorf("oversized sample len = %d, want 10", len(all))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	entry := Entry{FileID: "f1", Name: "case.tar.gz", Size: 100, SHA512: "aa11"}
+	remote := func(size int64, hash string) *models.CloudFile {
+		return &models.CloudFile{ID: "f1", IsUploaded: true, DecryptedSize: size,
+			FileChecksums: []models.FileChecksum{{HashFunction: "sha512", FileHash: hash}}}
+	}
+
+	tests := []struct {
+		name       string
+		file       *models.CloudFile
+		err        error
+		rehash     string
+		wantStatus string
+		wantMethod string
+	}{
+		{"metadata ok", remote(100, "AA11"), nil, "", StatusOK, MethodMetadata},
+		{"size changed", remote(99, "aa11"), nil, "", StatusMismatch, MethodMetadata},
+		{"checksum changed", remote(100, "bb22"), nil, "", StatusMismatch, MethodMetadata},
+		{"deleted", nil, errors.New("get file info failed: status 404: not found"), "", StatusMissing, MethodMetadata},
+		{"api error", nil, errors.New("connection reset"), "", StatusError, MethodMetadata},
+		{"rehash ok", remote(100, "aa11"), nil, "aa11", StatusOK, MethodRehash},
+		{"rehash corrupt", remote(100, "aa11"), nil, "ff00", StatusMismatch, MethodRehash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Verifier{
+				GetFileInfo: func(ctx context.Context, id string) (*models.CloudFile, error) { return tt.file, tt.err },
+			}
+			if tt.rehash != "" {
+				v.Rehash = func(ctx context.Context, f *models.CloudFile) (string, error) { return tt.rehash, nil }
+			}
+			res := v.Verify(context.Background(), entry)
+			if res.Status != tt.wantStatus || res.Method != tt.wantMethod {
+				t.Errorf("got %s/%s (%s), want %s/%s", res.Status, res.Method, res.Detail, tt.wantStatus, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	stored := []byte("stored ciphertext")
+	sum := sha256.Sum256(stored[7:17])
+	entry := Entry{FileID: "f1", Size: 100, SHA512: "aa",
+		Blocks: []Block{{Offset: 7, Length: 10, SHA256: hex.EncodeToString(sum[:])}}}
+	v := &Verifier{
+		GetFileInfo: func(ctx context.Context, id string) (*models.CloudFile, error) {
+			return &models.CloudFile{IsUploaded: true, DecryptedSize: 100,
+				FileChecksums: []models.FileChecksum{{HashFunction: "sha512", FileHash: "aa"}}}, nil
+		},
+		ReadStored: func(ctx context.Context, f *models.CloudFile, offset, length int64) ([]byte, error) {
+			return stored[offset : offset+length], nil
+		},
+	}
+
+	report := &Report{}
+	res := v.Verify(context.Background(), entry)
+	report.Add(res)
+	if res.Status != StatusOK || res.Method != MethodBlocks {
+		t.Errorf("intact object: got %s/%s (%s), want ok/blocks", res.Status, res.Method, res.Detail)
+	}
+
+	stored[9] ^= 0xff
+	if res := v.Verify(context.Background(), entry); res.Status != StatusMismatch || res.Method != MethodBlocks {
+		t.Errorf("corrupt object: got %s/%s (%s), want mismatch/blocks", res.Status, res.Method, res.Detail)
+	}
+
+	// Entries recorded without blocks are labelled metadata-only
+	entry.Blocks = nil
+	res = v.Verify(context.Background(), entry)
+	report.Add(res)
+	if res.Status != StatusOK || res.Method != MethodMetadata || !strings.Contains(res.Detail, "metadata only") {
+		t.Errorf("no blocks: got %s/%s (%s), want ok/metadata labelled metadata only", res.Status, res.Method, res.Detail)
+	}
+	if report.MetadataOnly != 1 {
+		t.Errorf("report.MetadataOnly = %d, want 1", report.MetadataOnly)
+	}
+}
+
+func TestVerifyRehashLimit(t *testing.T) {
+	called := false
+	v := &Verifier{
+		GetFileInfo: func(ctx context.Context, id string) (*models.CloudFile, error) {
+			return &models.CloudFile{IsUploaded: true, DecryptedSize: 100,
+				FileChecksums: []models.FileChecksum{{HashFunction: "sha512", FileHash: "aa"}}}, nil
+		},
+		Rehash:        func(ctx context.Context, f *models.CloudFile) (string, error) { called = true; return "aa", nil },
+		RehashMaxSize: 50,
+	}
+	res := v.Verify(context.Background(), Entry{FileID: "f", Size: 100, SHA512: "aa"})
+	if called || res.Status != StatusOK || res.Method != MethodMetadata {
+		t.Errorf("oversized file: rehash called=%v, result %+v", called, res)
+	}
+}
+
+func TestReportFailed(t *testing.T) {
+	r := &Report{}
+	r.Add(Result{Status: StatusOK})
+	if r.Failed() {
+		t.Error("all-ok report failed")
+	}
+	r.Add(Result{Status: StatusError})
+	if !r.Failed() || r.Errors != 1 || r.Checked != 2 {
+		t.Errorf("report = %+v", r)
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	if _, ok := Throughput(nil); ok {
+		t.Error("Throughput(nil) reported a rate")
+	}
+
+	const mb = 1024 * 1024
+	entries := []Entry{
+		{FileID: "a", Size: 100 * mb, TransferMs: 1000}, // 100 MB/s
+		{FileID: "b", Size: 1 * mb, TransferMs: 5000},   // Too small to count
+		{FileID: "c", Size: 300 * mb, TransferMs: 5000}, // 60 MB/s
+		{FileID: "d", Size: 500 * mb},                   // Recorded before transfer times
+	}
+	rate, ok := Throughput(entries)
+	if want := 400.0 * mb / 6; !ok || rate != want {
+		t.Errorf("Throughput = %g, %t, want %g", rate, ok, want)
+	}
+}
@@ -0,0 +1,191 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Verification outcomes for one upload.
+const (
+	StatusOK       = "ok"
+	StatusMismatch = "mismatch" // Remote size or checksum differs from what was uploaded
+	StatusMissing  = "missing"  // File no longer exists on the platform
+	StatusError    = "error"    // Could not be checked (network, permissions)
+)
+
+// Verification methods, weakest first.
+const (
+	MethodMetadata = "metadata" // Registered size and SHA-512 compared; stored content not read
+	MethodBlocks   = "blocks"   // Metadata, plus sampled blocks of the stored object read back
+	MethodRehash   = "rehash"   // Downloaded, decrypted and re-hashed
+)
+
+// Result is the audit outcome for one upload.
+type Result struct {
+	Entry
+	Status string `json:"status"`
+	Method string `json:"method"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the audit report written by `history verify --report`.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Platform    string    `json:"platform"`
+	Since       time.Time `json:"since"`
+	Recorded    int       `json:"recorded"` // Uploads in the history window
+	Checked     int       `json:"checked"`
+	OK          int       `json:"ok"`
+	Mismatched  int       `json:"mismatched"`
+	Missing     int       `json:"missing"`
+	Errors      int       `json:"errors"`
+	// Checks that only compared metadata, which cannot tell whether the
+	// stored content is intact (uploads recorded before block sampling)
+	MetadataOnly int      `json:"metadataOnly"`
+	Results      []Result `json:"results"`
+}
+
+// Add records res and updates the counts.
+func (r *Report) Add(res Result) {
+	r.Results = append(r.Results, res)
+	r.Checked++
+	if res.Method == MethodMetadata && res.Status == StatusOK {
+		r.MetadataOnly++
+	}
+	switch res.Status {
+	case StatusOK:
+		r.OK++
+	case StatusMismatch:
+		r.Mismatched++
+	case StatusMissing:
+		r.Missing++
+	default:
+		r.Errors++
+	}
+}
+
+// Failed reports whether any upload did not verify. Errors count as
+// failures: an audit that could not check a file has not passed it.
+func (r *Report) Failed() bool {
+	return r.Mismatched+r.Missing+r.Errors > 0
+}
+
+// Verifier re-checks recorded uploads against the platform.
+type Verifier struct {
+	// GetFileInfo fetches the registered metadata for a file ID.
+	GetFileInfo func(ctx context.Context, fileID string) (*models.CloudFile, error)
+
+	// ReadStored reads length bytes at offset of the file's stored
+	// (encrypted) object. Used to compare the blocks sampled at upload;
+	// nil checks metadata only unless Rehash is set.
+	ReadStored func(ctx context.Context, file *models.CloudFile, offset, length int64) ([]byte, error)
+
+	// Rehash downloads the file and returns the SHA-512 of its decrypted
+	// content. Nil checks metadata only.
+	Rehash func(ctx context.Context, file *models.CloudFile) (string, error)
+
+	// RehashMaxSize limits Rehash to files up to this size; larger files are
+	// checked by metadata only. Zero means no limit.
+	RehashMaxSize int64
+}
+
+// Verify checks one recorded upload.
+func (v *Verifier) Verify(ctx context.Context, e Entry) Result {
+	res := Result{Entry: e, Method: MethodMetadata}
+
+	file, err := v.GetFileInfo(ctx, e.FileID)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			res.Status = StatusMissing
+			res.Detail = "file not found on platform"
+		} else {
+			res.Status = StatusError
+			res.Detail = err.Error()
+		}
+		return res
+	}
+
+	if !file.IsUploaded {
+		return mismatch(res, "file is no longer marked uploaded")
+	}
+	if file.DecryptedSize != 0 && file.DecryptedSize != e.Size {
+		return mismatch(res, fmt.Sprintf("size: uploaded %d bytes, registered %d", e.Size, file.DecryptedSize))
+	}
+	remote := registeredSHA512(file.FileChecksums)
+	if remote == "" {
+		return mismatch(res, "no SHA-512 checksum registered")
+	}
+	if !strings.EqualFold(remote, e.SHA512) {
+		return mismatch(res, fmt.Sprintf("checksum: uploaded %s, registered %s", abbrev(e.SHA512), abbrev(remote)))
+	}
+
+	if v.ReadStored != nil && len(e.Blocks) > 0 {
+		res.Method = MethodBlocks
+		for _, b := range e.Blocks {
+			data, err := v.ReadStored(ctx, file, b.Offset, b.Length)
+			if err != nil {
+				res.Status = StatusError
+				res.Detail = fmt.Sprintf("block read failed: %v", err)
+				return res
+			}
+			sum := sha256.Sum256(data)
+			if int64(len(data)) != b.Length || !strings.EqualFold(hex.EncodeToString(sum[:]), b.SHA256) {
+				return mismatch(res, fmt.Sprintf("stored bytes %d-%d differ from what was uploaded", b.Offset, b.Offset+b.Length-1))
+			}
+		}
+	}
+
+	if v.Rehash != nil && (v.RehashMaxSize == 0 || e.Size <= v.RehashMaxSize) {
+		res.Method = MethodRehash
+		got, err := v.Rehash(ctx, file)
+		if err != nil {
+			res.Status = StatusError
+			res.Detail = fmt.Sprintf("re-hash failed: %v", err)
+			return res
+		}
+		if !strings.EqualFold(got, e.SHA512) {
+			return mismatch(res, fmt.Sprintf("stored content hashes to %s, uploaded %s", abbrev(got), abbrev(e.SHA512)))
+		}
+	} else if v.Rehash != nil && res.Method == MethodMetadata {
+		res.Detail = "larger than re-hash limit; metadata only"
+	} else if v.Rehash != nil {
+		res.Detail = "larger than re-hash limit; sampled blocks only"
+	}
+	if res.Method == MethodMetadata && res.Detail == "" {
+		res.Detail = "metadata only: no blocks sampled at upload"
+	}
+
+	res.Status = StatusOK
+	return res
+}
+
+func mismatch(res Result, detail string) Result {
+	res.Status = StatusMismatch
+	res.Detail = detail
+	return res
+}
+
+// registeredSHA512 returns the SHA-512 checksum registered with a file.
+func registeredSHA512(checksums []models.FileChecksum) string {
+	for _, cs := range checksums {
+		switch cs.HashFunction {
+		case "sha512", "SHA-512", "SHA512":
+			return cs.FileHash
+		}
+	}
+	return ""
+}
+
+// abbrev shortens a hex digest for display.
+func abbrev(hash string) string {
+	if len(hash) > 16 {
+		return hash[:16] + "…"
+	}
+	return hash
+}
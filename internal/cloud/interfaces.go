@@ -6,6 +6,8 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/rescale/rescale-int/internal/api"
@@ -13,6 +15,34 @@ import (
 	"github.com/rescale/rescale-int/internal/transfer"
 )
 
+// Errors for a downloaded file that does not match the platform's metadata.
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrSizeMismatch     = errors.New("size mismatch")
+)
+
+// IntegrityError is a file whose content hash, checked after a transfer,
+// doesn't match the one recorded for it: on upload, the source changed
+// while it was being encrypted; on download, the file written to disk
+// isn't the one uploaded. It wraps ErrChecksumMismatch.
+type IntegrityError struct {
+	Path      string
+	Upload    bool   // Found after an upload rather than a download
+	Algorithm string // e.g. "SHA-256"
+	Expected  string
+	Actual    string
+}
+
+func (e *IntegrityError) Error() string {
+	msg := fmt.Sprintf("%v: expected %s=%s, got %s", ErrChecksumMismatch, e.Algorithm, e.Expected, e.Actual)
+	if e.Upload {
+		msg += " (the file changed while it was uploaded)"
+	}
+	return msg
+}
+
+func (e *IntegrityError) Unwrap() error { return ErrChecksumMismatch }
+
 // ProgressCallback is called during transfers to report progress (0.0 to 1.0)
 type ProgressCallback func(progress float64)
 
@@ -98,6 +128,10 @@ type UploadResult struct {
 
 	// PartSize is the part size used for streaming format (v1)
 	PartSize int64
+
+	// PlaintextSHA256 is the hex SHA-256 of the file content as it was
+	// encrypted, when the upload computed it (streaming uploads)
+	PlaintextSHA256 string
 }
 
 // CloudTransfer is the unified interface for cloud storage operations.
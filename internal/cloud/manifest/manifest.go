@@ -0,0 +1,161 @@
+// Package manifest records what a folder upload sent to the platform, so a
+// later `files verify-manifest` can check that everything in the directory
+// still exists remotely.
+//
+// A manifest lists each uploaded file by its path relative to the uploaded
+// directory with its remote file ID, size, modification time and checksums.
+// It is written into the directory itself (FileName) or, when that is not
+// wanted, into a central store keyed by the directory's absolute path.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileName is the manifest written into an uploaded directory.
+const FileName = ".rescale-manifest.json"
+
+// version is bumped when the manifest layout changes incompatibly.
+const version = 1
+
+// Manifest describes the upload of one local directory to one remote folder.
+type Manifest struct {
+	Version        int       `json:"version"`
+	Platform       string    `json:"platform"`
+	LocalRoot      string    `json:"localRoot"` // Absolute path of the directory when it was uploaded
+	RemoteFolderID string    `json:"remoteFolderId"`
+	IncludeHidden  bool      `json:"includeHidden,omitempty"`
+	Updated        time.Time `json:"updated"`
+	Files          []File    `json:"files"`
+}
+
+// File is one file of the directory and its remote copy.
+type File struct {
+	Path     string    `json:"path"` // Slash-separated, relative to the directory
+	FileID   string    `json:"fileId"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	SHA256   string    `json:"sha256,omitempty"`
+	SHA512   string    `json:"sha512,omitempty"`
+	Uploaded time.Time `json:"uploaded"`
+
+	// Existing is set for files left in place because the remote folder
+	// already had them; they have no checksums.
+	Existing bool `json:"existing,omitempty"`
+}
+
+// NewFile returns the entry for the local file at path below root.
+func NewFile(root, path string, info os.FileInfo, fileID string) File {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	return File{
+		Path:     filepath.ToSlash(rel),
+		FileID:   fileID,
+		Size:     info.Size(),
+		ModTime:  info.ModTime().UTC(),
+		Uploaded: time.Now().UTC(),
+	}
+}
+
+// InFolderPath returns the manifest path inside the directory root.
+func InFolderPath(root string) string {
+	return filepath.Join(root, FileName)
+}
+
+// CentralPath returns the manifest path for root in the central store dir.
+func CentralPath(dir, root string) string {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(root)))
+	return filepath.Join(dir, filepath.Base(root)+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// Find returns the manifest of root: the one inside it if there is one,
+// otherwise the one in the central store dir. The error wraps
+// os.ErrNotExist when there is neither.
+func Find(root, dir string) (string, error) {
+	for _, path := range []string{InFolderPath(root), CentralPath(dir, root)} {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no upload manifest for %s: %w", root, os.ErrNotExist)
+}
+
+// Load reads the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid upload manifest %s: %w", path, err)
+	}
+	if m.Version != version {
+		return nil, fmt.Errorf("upload manifest %s has unsupported version %d", path, m.Version)
+	}
+	return &m, nil
+}
+
+// Record writes files into the manifest at path. Entries of an earlier
+// upload of the same directory to the same remote folder are kept unless
+// files replaces them, so a merge that skips existing files still lists
+// them with their checksums.
+func Record(path string, m Manifest, files []File) error {
+	byPath := map[string]File{}
+	// A manifest that can't be read, or is for another remote folder, is
+	// replaced by this upload's.
+	if old, err := Load(path); err == nil && old.Platform == m.Platform && old.RemoteFolderID == m.RemoteFolderID {
+		for _, f := range old.Files {
+			byPath[f.Path] = f
+		}
+	}
+	for _, f := range files {
+		if f.Path == FileName {
+			continue // An earlier manifest uploaded with hidden files
+		}
+		if prev, ok := byPath[f.Path]; ok && f.Existing && !prev.Existing &&
+			prev.FileID == f.FileID && prev.Size == f.Size && prev.ModTime.Equal(f.ModTime) {
+			continue
+		}
+		byPath[f.Path] = f
+	}
+
+	m.Version = version
+	m.Updated = time.Now().UTC()
+	m.Files = make([]File, 0, len(byPath))
+	for _, f := range byPath {
+		m.Files = append(m.Files, f)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	// Replace the manifest in one step, so an interrupted write never
+	// leaves a truncated one behind.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write upload manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write upload manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func writeFile(t *testing.T, path, content string) os.FileInfo {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestRecord_MergesSameFolder(t *testing.T) {
+	root := t.TempDir()
+	path := InFolderPath(root)
+	a := NewFile(root, filepath.Join(root, "a.txt"), writeFile(t, filepath.Join(root, "a.txt"), "a"), "fa")
+	a.SHA512 = "abc"
+	base := Manifest{Platform: "https://platform.rescale.com", LocalRoot: root, RemoteFolderID: "folder1"}
+	if err := Record(path, base, []File{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A merge that left a.txt in place keeps its checksummed entry
+	existing := a
+	existing.SHA512, existing.Existing = "", true
+	b := NewFile(root, filepath.Join(root, "sub", "b.txt"), writeFile(t, filepath.Join(root, "sub", "b.txt"), "b"), "fb")
+	if err := Record(path, base, []File{existing, b}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 2 || m.Files[0].Path != "a.txt" || m.Files[1].Path != "sub/b.txt" {
+		t.Fatalf("Files = %+v", m.Files)
+	}
+	if m.Files[0].SHA512 != "abc" || m.Files[0].Existing {
+		t.Errorf("merged entry lost its checksum: %+v", m.Files[0])
+	}
+
+	// An upload to another remote folder starts a new manifest
+	base.RemoteFolderID = "folder2"
+	if err := Record(path, base, []File{b}); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := Load(path); len(m.Files) != 1 || m.RemoteFolderID != "folder2" {
+		t.Errorf("manifest for another folder = %+v", m)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root, store := t.TempDir(), t.TempDir()
+	if _, err := Find(root, store); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Find() with no manifest = %v, want ErrNotExist", err)
+	}
+	central := CentralPath(store, root)
+	if err := Record(central, Manifest{RemoteFolderID: "f"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Find(root, store); err != nil || got != central {
+		t.Errorf("Find() = %q, %v, want %q", got, err, central)
+	}
+	if err := Record(InFolderPath(root), Manifest{RemoteFolderID: "f"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Find(root, store); got != InFolderPath(root) {
+		t.Errorf("Find() = %q, want the manifest in the folder", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+	var files []File
+	for _, name := range []string{"ok.txt", "changed.txt", "deleted.txt", "missing.txt", "resized.txt"} {
+		path := filepath.Join(root, name)
+		f := NewFile(root, path, writeFile(t, path, name), "id-"+name)
+		f.SetChecksums([]models.FileChecksum{{HashFunction: "sha512", FileHash: "h-" + name}})
+		files = append(files, f)
+	}
+	m := &Manifest{RemoteFolderID: "folder", Files: files}
+	if err := Record(InFolderPath(root), *m, files); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(root, "changed.txt"), later, later)
+	os.Remove(filepath.Join(root, "deleted.txt"))
+	writeFile(t, filepath.Join(root, "new.txt"), "new")
+
+	v := &Verifier{GetFileInfo: func(ctx context.Context, id string) (*models.CloudFile, error) {
+		switch id {
+		case "id-missing.txt":
+			return nil, errors.New("API request failed with status 404: not found")
+		case "id-resized.txt":
+			return &models.CloudFile{ID: id, IsUploaded: true, DecryptedSize: 1}, nil
+		}
+		return &models.CloudFile{ID: id, IsUploaded: true, FileChecksums: []models.FileChecksum{{HashFunction: "sha512", FileHash: "H-" + id[3:]}}}, nil
+	}}
+	report, err := v.Verify(context.Background(), m, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"changed.txt": StatusChanged,
+		"deleted.txt": StatusDeleted,
+		"missing.txt": StatusMissing,
+		"new.txt":     StatusNotUploaded,
+		"ok.txt":      StatusOK,
+		"resized.txt": StatusMismatch,
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("Results = %+v", report.Results)
+	}
+	for _, res := range report.Results {
+		if want[res.Path] != res.Status {
+			t.Errorf("%s: status %q (%s), want %q", res.Path, res.Status, res.Detail, want[res.Path])
+		}
+	}
+	if !report.Failed() || report.OK != 1 || report.Deleted != 1 {
+		t.Errorf("report counts = %+v", report)
+	}
+}
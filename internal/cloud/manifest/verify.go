@@ -0,0 +1,251 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/localfs"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Verification outcomes for one file.
+const (
+	StatusOK          = "ok"
+	StatusNotUploaded = "not-uploaded" // Local file the manifest doesn't list
+	StatusChanged     = "changed"      // Local file differs from the one uploaded
+	StatusDeleted     = "deleted"      // Listed file no longer exists locally; its remote copy is fine
+	StatusMissing     = "missing"      // Remote file no longer exists on the platform
+	StatusMismatch    = "mismatch"     // Remote size or checksum differs from what was uploaded
+	StatusError       = "error"        // Could not be checked (network, permissions)
+)
+
+// Result is the outcome for one file.
+type Result struct {
+	Path   string `json:"path"`
+	FileID string `json:"fileId,omitempty"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the outcome of verifying a manifest, written by
+// `files verify-manifest --report`.
+type Report struct {
+	GeneratedAt    time.Time `json:"generatedAt"`
+	Root           string    `json:"root"`
+	RemoteFolderID string    `json:"remoteFolderId"`
+	Checked        int       `json:"checked"`
+	OK             int       `json:"ok"`
+	NotUploaded    int       `json:"notUploaded"`
+	Changed        int       `json:"changed"`
+	Deleted        int       `json:"deleted"`
+	Missing        int       `json:"missing"`
+	Mismatched     int       `json:"mismatched"`
+	Errors         int       `json:"errors"`
+	Results        []Result  `json:"results"`
+}
+
+// Add records res and updates the counts.
+func (r *Report) Add(res Result) {
+	r.Results = append(r.Results, res)
+	r.Checked++
+	switch res.Status {
+	case StatusOK:
+		r.OK++
+	case StatusNotUploaded:
+		r.NotUploaded++
+	case StatusChanged:
+		r.Changed++
+	case StatusDeleted:
+		r.Deleted++
+	case StatusMissing:
+		r.Missing++
+	case StatusMismatch:
+		r.Mismatched++
+	default:
+		r.Errors++
+	}
+}
+
+// Failed reports whether anything in the directory is not on the platform
+// as it is locally. Files deleted locally don't count: their remote copies
+// are intact.
+func (r *Report) Failed() bool {
+	return r.NotUploaded+r.Changed+r.Missing+r.Mismatched+r.Errors > 0
+}
+
+// Verifier checks a directory against its manifest and the platform.
+type Verifier struct {
+	// GetFileInfo fetches the registered metadata for a file ID.
+	GetFileInfo func(ctx context.Context, fileID string) (*models.CloudFile, error)
+
+	// Hash re-hashes local files instead of trusting an unchanged size and
+	// modification time.
+	Hash bool
+
+	// Workers is the number of files checked at a time (default 8).
+	Workers int
+}
+
+// Verify checks every file of the manifest and every file under root,
+// walked as the upload walked it. Results are sorted by path.
+func (v *Verifier) Verify(ctx context.Context, m *Manifest, root string) (*Report, error) {
+	walked, err := localfs.WalkCollect(root, localfs.WalkOptions{
+		IncludeHidden:  m.IncludeHidden,
+		SkipHiddenDirs: true,
+		FollowSymlinks: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	local := make(map[string]localfs.FileEntry, len(walked.Files))
+	for _, f := range walked.Files {
+		rel, err := filepath.Rel(root, f.Path)
+		if err != nil || rel == FileName {
+			continue
+		}
+		local[filepath.ToSlash(rel)] = f
+	}
+
+	report := &Report{
+		GeneratedAt:    time.Now().UTC(),
+		Root:           root,
+		RemoteFolderID: m.RemoteFolderID,
+		Results:        []Result{},
+	}
+	listed := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		listed[f.Path] = true
+	}
+	for rel := range local {
+		if !listed[rel] {
+			report.Add(Result{Path: rel, Status: StatusNotUploaded, Detail: "not in the manifest"})
+		}
+	}
+
+	workers := v.Workers
+	if workers <= 0 {
+		workers = 8
+	}
+	results := make([]Result, len(m.Files))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, f := range m.Files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			entry, ok := local[f.Path]
+			results[i] = v.check(ctx, f, entry, ok)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		report.Add(res)
+	}
+
+	sort.SliceStable(report.Results, func(i, j int) bool { return report.Results[i].Path < report.Results[j].Path })
+	return report, nil
+}
+
+// check verifies one manifest entry: its remote copy first, then the local
+// file it was uploaded from.
+func (v *Verifier) check(ctx context.Context, f File, local localfs.FileEntry, exists bool) Result {
+	res := Result{Path: f.Path, FileID: f.FileID}
+
+	remote, err := v.GetFileInfo(ctx, f.FileID)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			res.Status, res.Detail = StatusMissing, "file not found on platform"
+		} else {
+			res.Status, res.Detail = StatusError, err.Error()
+		}
+		return res
+	}
+	if !remote.IsUploaded {
+		res.Status, res.Detail = StatusMismatch, "file is no longer marked uploaded"
+		return res
+	}
+	if remote.DecryptedSize != 0 && remote.DecryptedSize != f.Size {
+		res.Status, res.Detail = StatusMismatch, fmt.Sprintf("size: uploaded %d bytes, registered %d", f.Size, remote.DecryptedSize)
+		return res
+	}
+	if registered := checksum(remote.FileChecksums, "sha512"); f.SHA512 != "" && registered != "" && !strings.EqualFold(registered, f.SHA512) {
+		res.Status, res.Detail = StatusMismatch, "registered SHA-512 differs from the one uploaded"
+		return res
+	}
+
+	if !exists {
+		res.Status, res.Detail = StatusDeleted, "deleted locally"
+		return res
+	}
+	if local.Size != f.Size {
+		res.Status, res.Detail = StatusChanged, fmt.Sprintf("size: uploaded %d bytes, now %d", f.Size, local.Size)
+		return res
+	}
+	if v.Hash && (f.SHA512 != "" || f.SHA256 != "") {
+		expected, newHash := f.SHA512, sha512.New
+		if expected == "" {
+			expected, newHash = f.SHA256, sha256.New
+		}
+		actual, err := hashFile(local.Path, newHash)
+		if err != nil {
+			res.Status, res.Detail = StatusError, err.Error()
+			return res
+		}
+		if !strings.EqualFold(actual, expected) {
+			res.Status, res.Detail = StatusChanged, "content differs from the one uploaded"
+			return res
+		}
+	} else if !local.ModTime.Equal(f.ModTime) {
+		res.Status, res.Detail = StatusChanged, "modified since the upload"
+		return res
+	}
+
+	res.Status = StatusOK
+	return res
+}
+
+// SetChecksums fills in the SHA-256 and SHA-512 registered with the file.
+func (f *File) SetChecksums(checksums []models.FileChecksum) {
+	f.SHA256 = checksum(checksums, "sha256")
+	f.SHA512 = checksum(checksums, "sha512")
+}
+
+// checksum returns the checksum of the named hash function ("sha256" or
+// "sha512") in checksums, in any of the spellings the platform uses.
+func checksum(checksums []models.FileChecksum, name string) string {
+	for _, cs := range checksums {
+		if strings.EqualFold(strings.ReplaceAll(cs.HashFunction, "-", ""), name) {
+			return cs.FileHash
+		}
+	}
+	return ""
+}
+
+func hashFile(path string, newHash func() hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,72 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestDownloadRangeOnce_CancelAbortsBody(t *testing.T) {
+	// Send the first bytes of a large range and then stall until the client
+	// goes away, simulating a slow in-flight block.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "16777216")
+		w.Header().Set("Content-Range", "bytes 0-16777215/16777216")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(make([]byte, 1024))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client, err := azblob.NewClientWithNoCredential(srv.URL+"/account", &azblob.ClientOptions{
+		ClientOptions: policy.ClientOptions{Retry: policy.RetryOptions{MaxRetries: -1}},
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithNoCredential: %v", err)
+	}
+	c := &AzureClient{
+		client:      client,
+		storageInfo: &models.StorageInfo{ConnectionSettings: models.ConnectionSettings{Container: "container"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := c.DownloadRangeOnce(ctx, "blob", 0, 16777216)
+	if err != nil {
+		t.Fatalf("DownloadRangeOnce: %v", err)
+	}
+	defer resp.Body.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, resp.Body)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("body read error = %v, want context.Canceled", err)
+		}
+		if elapsed := time.Since(start); elapsed > constants.CancelIdleTarget {
+			t.Errorf("body read took %v to abort, want < %v", elapsed, constants.CancelIdleTarget)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("body read did not abort after cancel")
+	}
+}
@@ -259,8 +259,18 @@ func (c *AzureClient) EnsureFreshCredentials(ctx context.Context) error {
 // RetryWithBackoff executes a function with exponential backoff retry logic.
 // Uses the shared retry package for consistent retry behavior across all operations.
 func (c *AzureClient) RetryWithBackoff(ctx context.Context, operation string, fn func() error) error {
+	return c.retryWithBudget(ctx, operation, constants.MaxRetries, fn)
+}
+
+// RetryPartWithBackoff is RetryWithBackoff for uploading one part, limited to
+// the part_retries budget. Errors classified as fatal still fail at once.
+func (c *AzureClient) RetryPartWithBackoff(ctx context.Context, operation string, fn func() error) error {
+	return c.retryWithBudget(ctx, operation, http.PartRetries(c.apiClient.GetConfig()), fn)
+}
+
+func (c *AzureClient) retryWithBudget(ctx context.Context, operation string, maxRetries int, fn func() error) error {
 	retryConfig := http.Config{
-		MaxRetries:   constants.MaxRetries,
+		MaxRetries:   maxRetries,
 		InitialDelay: constants.RetryInitialDelay,
 		MaxDelay:     constants.RetryMaxDelay,
 		CredentialRefresh: func(ctx context.Context) error {
@@ -270,7 +280,7 @@ func (c *AzureClient) RetryWithBackoff(ctx context.Context, operation string, fn
 			// Log retry attempts for debugging
 			if os.Getenv("DEBUG_RETRY") == "true" {
 				log.Printf("[RETRY] %s: attempt %d/%d, error type: %s, error: %v",
-					operation, attempt, constants.MaxRetries, http.ErrorTypeName(errorType), err)
+					operation, attempt, maxRetries, http.ErrorTypeName(errorType), err)
 			}
 		},
 	}
@@ -212,7 +212,7 @@ func (p *Provider) uploadEncryptedBlockBlob(ctx context.Context, azureClient *Az
 		copy(blockData, buffer[:n])
 
 		// Stage block using AzureClient
-		err = azureClient.RetryWithBackoff(ctx, fmt.Sprintf("StageBlock %d", blockNum), func() error {
+		err = azureClient.RetryPartWithBackoff(ctx, fmt.Sprintf("StageBlock %d", blockNum), func() error {
 			client := azureClient.Client()
 			blockBlobClient := client.ServiceClient().NewContainerClient(azureClient.Container()).NewBlockBlobClient(blobPath)
 			_, err := blockBlobClient.StageBlock(ctx, blockID, &readSeekCloser{Reader: bytes.NewReader(blockData)}, nil)
@@ -397,7 +397,7 @@ func (p *Provider) uploadEncryptedBlockBlobConcurrent(ctx context.Context, azure
 				// Create context with timeout for this specific block
 				blockCtx, cancel := context.WithTimeout(opCtx, constants.PartOperationTimeout)
 
-				stageErr := azureClient.RetryWithBackoff(blockCtx, fmt.Sprintf("StageBlock %d/%d", job.blockIndex+1, totalBlocks), func() error {
+				stageErr := azureClient.RetryPartWithBackoff(blockCtx, fmt.Sprintf("StageBlock %d/%d", job.blockIndex+1, totalBlocks), func() error {
 					client := azureClient.Client()
 					blockBlobClient := client.ServiceClient().NewContainerClient(azureClient.Container()).NewBlockBlobClient(blobPath)
 					_, err := blockBlobClient.StageBlock(blockCtx, currentBlockID, &readSeekCloser{Reader: bytes.NewReader(blockDataToUpload)}, nil)
@@ -407,7 +407,7 @@ func (p *Provider) uploadEncryptedBlockBlobConcurrent(ctx context.Context, azure
 				cancel()
 
 				if stageErr != nil {
-					setError(fmt.Errorf("failed to stage block %d/%d: %w", job.blockIndex+1, totalBlocks, stageErr))
+					setError(transfer.NewPartError(job.blockIndex, totalBlocks, stageErr))
 					return
 				}
 
@@ -466,11 +466,16 @@ func (p *Provider) uploadEncryptedBlockBlobConcurrent(ctx context.Context, azure
 			// Generate block ID
 			blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%06d", blockIndex)))
 
-			// Queue this block for upload
-			jobChan <- blockJob{
+			// Queue this block for upload. Workers stop receiving once
+			// opCtx is cancelled, so a blocking send would leak this goroutine.
+			select {
+			case jobChan <- blockJob{
 				blockIndex: blockIndex,
 				blockID:    blockID,
 				data:       blockData,
+			}:
+			case <-opCtx.Done():
+				return
 			}
 
 			blockIndex++
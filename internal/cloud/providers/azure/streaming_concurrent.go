@@ -17,6 +17,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
@@ -151,7 +152,7 @@ func (p *Provider) UploadStreamingPart(ctx context.Context, uploadState *transfe
 	}
 
 	// Stage the block using AzureClient
-	err = providerData.azureClient.RetryWithBackoff(partCtx, fmt.Sprintf("StageBlock %d", partIndex), func() error {
+	err = providerData.azureClient.RetryPartWithBackoff(partCtx, fmt.Sprintf("StageBlock %d", partIndex), func() error {
 		client := providerData.azureClient.Client()
 		blockBlobClient := client.ServiceClient().NewContainerClient(providerData.container).NewBlockBlobClient(providerData.blobPath)
 		reader := &readSeekCloser{Reader: bytes.NewReader(ciphertext)}
@@ -217,7 +218,7 @@ func (p *Provider) UploadCiphertext(ctx context.Context, uploadState *transfer.S
 	}
 
 	// Stage the block using AzureClient
-	err := providerData.azureClient.RetryWithBackoff(partCtx, fmt.Sprintf("StageBlock %d", partIndex), func() error {
+	err := providerData.azureClient.RetryPartWithBackoff(partCtx, fmt.Sprintf("StageBlock %d", partIndex), func() error {
 		client := providerData.azureClient.Client()
 		blockBlobClient := client.ServiceClient().NewContainerClient(providerData.container).NewBlockBlobClient(providerData.blobPath)
 
@@ -425,45 +426,24 @@ func (p *Provider) DetectFormat(ctx context.Context, remotePath string) (int, st
 		return 0, "", 0, nil, fmt.Errorf("failed to get blob properties: %w", err)
 	}
 
-	// Check for streaming format metadata
-	// Note: Azure may title-case metadata keys, so check multiple variants
-	metadata := props.Metadata
+	return formatFromMetadata(props.Metadata)
+}
 
+// formatFromMetadata interprets blob metadata for DetectFormat.
+// Returns: formatVersion (0=legacy, 1=HKDF streaming, 2=CBC streaming), fileId (base64), partSize, iv, error
+func formatFromMetadata(metadata map[string]*string) (int, string, int64, []byte, error) {
 	if metadata == nil {
 		return 0, "", 0, nil, nil // No metadata, legacy format
 	}
 
-	// Check formatVersion (try various case combinations)
-	var formatVersionStr string
-	for _, key := range []string{"formatversion", "formatVersion", "FormatVersion", "Formatversion"} {
-		if v, ok := metadata[key]; ok && v != nil {
-			formatVersionStr = *v
-			break
-		}
-	}
-
-	if formatVersionStr == "1" {
+	if metadataValue(metadata, "formatversion") == "1" {
 		// HKDF streaming format (backward compatibility for files uploaded before v3.2.0)
-		// Get fileId
-		var fileId string
-		for _, key := range []string{"fileid", "fileId", "FileId", "Fileid"} {
-			if v, ok := metadata[key]; ok && v != nil {
-				fileId = *v
-				break
-			}
-		}
+		fileId := metadataValue(metadata, "fileid")
 		if fileId == "" {
 			return 0, "", 0, nil, fmt.Errorf("streaming format missing fileId in metadata")
 		}
 
-		// Get partSize
-		var partSizeStr string
-		for _, key := range []string{"partsize", "partSize", "PartSize", "Partsize"} {
-			if v, ok := metadata[key]; ok && v != nil {
-				partSizeStr = *v
-				break
-			}
-		}
+		partSizeStr := metadataValue(metadata, "partsize")
 		if partSizeStr == "" {
 			return 0, "", 0, nil, fmt.Errorf("streaming format missing partSize in metadata")
 		}
@@ -478,35 +458,26 @@ func (p *Provider) DetectFormat(ctx context.Context, remotePath string) (int, st
 
 	// Check for CBC streaming format (v3.2.4+) and get IV from metadata
 	var iv []byte
-	for _, key := range []string{"iv", "IV", "Iv"} {
-		if v, ok := metadata[key]; ok && v != nil && *v != "" {
-			iv, err = encryption.DecodeBase64(*v)
-			if err != nil {
-				// IV decode failed - might be provided via FileInfo instead
-				iv = nil
-			}
-			break
+	if ivStr := metadataValue(metadata, "iv"); ivStr != "" {
+		decoded, err := encryption.DecodeBase64(ivStr)
+		if err == nil {
+			iv = decoded
 		}
-	}
-
-	// Check for streamingformat: cbc
-	for _, key := range []string{"streamingformat", "streamingFormat", "StreamingFormat", "Streamingformat"} {
-		if v, ok := metadata[key]; ok && v != nil && *v == "cbc" {
-			// CBC streaming format - uploaded by rescale-int v3.2.4+
-			// Can use streaming download (no temp file) with sequential part decryption
-			// Read partSize from metadata. Return 0 if not present so downloader
-			// can calculate the correct size from file size (backward compatibility).
-			var partSize int64 = 0 // 0 means "calculate from file size"
-			for _, psKey := range []string{"partsize", "partSize", "PartSize", "Partsize"} {
-				if ps, psOk := metadata[psKey]; psOk && ps != nil && *ps != "" {
-					if parsed, parseErr := strconv.ParseInt(*ps, 10, 64); parseErr == nil && parsed > 0 {
-						partSize = parsed
-					}
-					break
-				}
+		// IV decode failed - might be provided via FileInfo instead
+	}
+
+	if metadataValue(metadata, "streamingformat") == "cbc" {
+		// CBC streaming format - uploaded by rescale-int v3.2.4+
+		// Can use streaming download (no temp file) with parallel part fetches,
+		// exactly like S3. Read partSize from metadata. Return 0 if not present
+		// so downloader can calculate the correct size from file size (backward compatibility).
+		var partSize int64 = 0 // 0 means "calculate from file size"
+		if ps := metadataValue(metadata, "partsize"); ps != "" {
+			if parsed, parseErr := strconv.ParseInt(ps, 10, 64); parseErr == nil && parsed > 0 {
+				partSize = parsed
 			}
-			return 2, "", partSize, iv, nil
 		}
+		return 2, "", partSize, iv, nil
 	}
 
 	// Legacy format - file uploaded by Rescale platform or older rescale-int
@@ -514,6 +485,19 @@ func (p *Provider) DetectFormat(ctx context.Context, remotePath string) (int, st
 	return 0, "", 0, iv, nil
 }
 
+// metadataValue returns the blob metadata value for key, or "" if it is not
+// set. Keys match case-insensitively: they are written lowercase, but Azure
+// returns them title-cased ("Streamingformat") through the SDK's canonical
+// HTTP header names.
+func metadataValue(metadata map[string]*string, key string) string {
+	for k, v := range metadata {
+		if v != nil && strings.EqualFold(k, key) {
+			return *v
+		}
+	}
+	return ""
+}
+
 // DownloadStreaming downloads and decrypts a file using HKDF streaming format (v1).
 // This is for backward compatibility with files uploaded before v3.2.0.
 // Format metadata (fileId, partSize) is read from Azure blob metadata.
@@ -544,13 +528,7 @@ func (p *Provider) DownloadStreaming(ctx context.Context, remotePath, localPath
 	metadata := props.Metadata
 
 	// Get fileId from metadata
-	var fileIdStr string
-	for _, key := range []string{"fileid", "fileId", "FileId", "Fileid"} {
-		if v, ok := metadata[key]; ok && v != nil {
-			fileIdStr = *v
-			break
-		}
-	}
+	fileIdStr := metadataValue(metadata, "fileid")
 	if fileIdStr == "" {
 		return fmt.Errorf("streaming format missing fileId in metadata")
 	}
@@ -560,13 +538,7 @@ func (p *Provider) DownloadStreaming(ctx context.Context, remotePath, localPath
 	}
 
 	// Get partSize from metadata
-	var partSizeStr string
-	for _, key := range []string{"partsize", "partSize", "PartSize", "Partsize"} {
-		if v, ok := metadata[key]; ok && v != nil {
-			partSizeStr = *v
-			break
-		}
-	}
+	partSizeStr := metadataValue(metadata, "partsize")
 	if partSizeStr == "" {
 		return fmt.Errorf("streaming format missing partSize in metadata")
 	}
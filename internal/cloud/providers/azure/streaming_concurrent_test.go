@@ -0,0 +1,48 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+
+	"github.com/rescale/rescale-int/internal/crypto" // package name is 'encryption'
+)
+
+// TestFormatFromMetadata verifies that CBC streaming uploads are detected as
+// format version 2 (parallel-fetch downloads, as on S3) whatever case Azure
+// returns the metadata keys in.
+func TestFormatFromMetadata(t *testing.T) {
+	iv := []byte("0123456789abcdef")
+	ivStr := encryption.EncodeBase64(iv)
+
+	tests := []struct {
+		name         string
+		metadata     map[string]*string
+		wantVersion  int
+		wantPartSize int64
+		wantIV       bool
+		wantErr      bool
+	}{
+		{"no metadata", nil, 0, 0, false, false},
+		{"cbc as written", map[string]*string{"iv": to.Ptr(ivStr), "streamingformat": to.Ptr("cbc"), "partsize": to.Ptr("33554432")}, 2, 33554432, true, false},
+		{"cbc title-cased", map[string]*string{"Iv": to.Ptr(ivStr), "Streamingformat": to.Ptr("cbc"), "Partsize": to.Ptr("33554432")}, 2, 33554432, true, false},
+		{"cbc without part size", map[string]*string{"IV": to.Ptr(ivStr), "StreamingFormat": to.Ptr("cbc")}, 2, 0, true, false},
+		{"legacy", map[string]*string{"Iv": to.Ptr(ivStr)}, 0, 0, true, false},
+		{"hkdf", map[string]*string{"Formatversion": to.Ptr("1"), "Fileid": to.Ptr("ZmlsZQ=="), "Partsize": to.Ptr("1024")}, 1, 1024, false, false},
+		{"hkdf missing fileId", map[string]*string{"formatversion": to.Ptr("1"), "partsize": to.Ptr("1024")}, 0, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, _, partSize, gotIV, err := formatFromMetadata(tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.wantVersion || partSize != tt.wantPartSize {
+				t.Errorf("version, partSize = %d, %d, want %d, %d", version, partSize, tt.wantVersion, tt.wantPartSize)
+			}
+			if (gotIV != nil) != tt.wantIV || (tt.wantIV && string(gotIV) != string(iv)) {
+				t.Errorf("iv = %x, want present=%v", gotIV, tt.wantIV)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+// Package conformance is the test suite every storage provider must pass
+// before it is registered with the providers package.
+//
+// A provider's test file calls Run with a Harness that creates providers
+// pointed at an empty, disposable storage location:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Harness{
+//			NewProvider:   func(t *testing.T) providers.Provider { return newTestProvider(t) },
+//			MultipartSize: 40 * 1024 * 1024,
+//		})
+//	}
+//
+// The suite drives the provider through the same interfaces the upload and
+// download orchestrators use and checks upload/download round trips, ranged
+// reads, object metadata, abort and resume, and credential refresh.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/providers"
+	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/crypto" // package name is 'encryption'
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Harness describes the provider under test.
+type Harness struct {
+	// NewProvider returns a provider backed by an empty storage location.
+	// Called once per subtest. Required.
+	NewProvider func(t *testing.T) providers.Provider
+
+	// MultipartSize is a plaintext size that spans at least two parts.
+	// Zero skips the multipart round-trip and resume cases.
+	MultipartSize int64
+
+	// ExpireCredentials invalidates the provider's current credentials.
+	// When set, the suite checks that the next operation refreshes them
+	// transparently instead of failing.
+	ExpireCredentials func(p providers.Provider)
+}
+
+// Run executes the conformance suite as subtests of t.
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+	if h.NewProvider == nil {
+		t.Fatal("conformance: Harness.NewProvider is required")
+	}
+
+	t.Run("StorageType", func(t *testing.T) { testStorageType(t, h) })
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, h) })
+	t.Run("Metadata", func(t *testing.T) { testMetadata(t, h) })
+	t.Run("Range", func(t *testing.T) { testRange(t, h) })
+	t.Run("MissingObject", func(t *testing.T) { testMissingObject(t, h) })
+	t.Run("Abort", func(t *testing.T) { testAbort(t, h) })
+	t.Run("Resume", func(t *testing.T) { testResume(t, h) })
+	t.Run("CredentialRefresh", func(t *testing.T) { testCredentialRefresh(t, h) })
+	t.Run("Cancellation", func(t *testing.T) { testCancellation(t, h) })
+}
+
+// uploaded is the outcome of uploadBytes.
+type uploaded struct {
+	upload     *transfer.StreamingUpload
+	result     *cloud.UploadResult
+	ciphertext []byte // concatenated ciphertext of all parts, in order
+}
+
+// randomBytes returns n random bytes.
+func randomBytes(t *testing.T, n int64) []byte {
+	t.Helper()
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+	return data
+}
+
+// writeTemp writes data to a file in a per-test temp directory.
+func writeTemp(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
+
+// initUpload starts a streaming upload of data.
+func initUpload(t *testing.T, ctx context.Context, p providers.Provider, data []byte) *transfer.StreamingUpload {
+	t.Helper()
+	localPath := writeTemp(t, "conformance.dat", data)
+	upload, err := p.InitStreamingUpload(ctx, transfer.StreamingUploadInitParams{
+		LocalPath: localPath,
+		FileSize:  int64(len(data)),
+	})
+	if err != nil {
+		t.Fatalf("InitStreamingUpload: %v", err)
+	}
+	if upload.StoragePath == "" {
+		t.Fatal("InitStreamingUpload returned empty StoragePath")
+	}
+	if upload.PartSize <= 0 || upload.PartSize%16 != 0 {
+		t.Fatalf("PartSize = %d, want a positive multiple of 16", upload.PartSize)
+	}
+	if len(upload.MasterKey) != 32 {
+		t.Fatalf("MasterKey length = %d, want 32", len(upload.MasterKey))
+	}
+	if len(upload.InitialIV) != 16 {
+		t.Fatalf("InitialIV length = %d, want 16", len(upload.InitialIV))
+	}
+	if upload.TotalParts != transfer.CalculateTotalParts(int64(len(data)), upload.PartSize) {
+		t.Fatalf("TotalParts = %d, want %d", upload.TotalParts,
+			transfer.CalculateTotalParts(int64(len(data)), upload.PartSize))
+	}
+	return upload
+}
+
+// plaintextPart returns the plaintext for partIndex of data.
+func plaintextPart(upload *transfer.StreamingUpload, data []byte, partIndex int64) []byte {
+	start := partIndex * upload.PartSize
+	end := start + upload.PartSize
+	if end > int64(len(data)) {
+		end = int64(len(data))
+	}
+	return data[start:end]
+}
+
+// uploadParts encrypts and uploads parts [from, upload.TotalParts) and returns
+// their results and ciphertext.
+func uploadParts(t *testing.T, ctx context.Context, p providers.Provider, upload *transfer.StreamingUpload, data []byte, from int64) ([]*transfer.PartResult, []byte) {
+	t.Helper()
+	var parts []*transfer.PartResult
+	var ciphertext []byte
+	for i := from; i < upload.TotalParts; i++ {
+		encrypted, err := p.EncryptStreamingPart(ctx, upload, i, plaintextPart(upload, data, i))
+		if err != nil {
+			t.Fatalf("EncryptStreamingPart(%d): %v", i, err)
+		}
+		part, err := p.UploadCiphertext(ctx, upload, i, encrypted)
+		if err != nil {
+			t.Fatalf("UploadCiphertext(%d): %v", i, err)
+		}
+		if part.PartIndex != i {
+			t.Fatalf("PartResult.PartIndex = %d, want %d", part.PartIndex, i)
+		}
+		parts = append(parts, part)
+		ciphertext = append(ciphertext, encrypted...)
+	}
+	return parts, ciphertext
+}
+
+// uploadBytes uploads data end to end through the streaming interface.
+func uploadBytes(t *testing.T, ctx context.Context, p providers.Provider, data []byte) *uploaded {
+	t.Helper()
+	upload := initUpload(t, ctx, p, data)
+	parts, ciphertext := uploadParts(t, ctx, p, upload, data, 0)
+	result, err := p.CompleteStreamingUpload(ctx, upload, parts)
+	if err != nil {
+		t.Fatalf("CompleteStreamingUpload: %v", err)
+	}
+	checkResult(t, upload, result)
+	return &uploaded{upload: upload, result: result, ciphertext: ciphertext}
+}
+
+// checkResult verifies the UploadResult matches the upload it completed.
+func checkResult(t *testing.T, upload *transfer.StreamingUpload, result *cloud.UploadResult) {
+	t.Helper()
+	if result.StoragePath != upload.StoragePath {
+		t.Errorf("UploadResult.StoragePath = %q, want %q", result.StoragePath, upload.StoragePath)
+	}
+	if !bytes.Equal(result.EncryptionKey, upload.MasterKey) {
+		t.Error("UploadResult.EncryptionKey does not match the upload key")
+	}
+	if !bytes.Equal(result.IV, upload.InitialIV) {
+		t.Error("UploadResult.IV does not match the upload initial IV")
+	}
+}
+
+// downloadBytes downloads an uploaded object through transfer.Downloader.
+func downloadBytes(t *testing.T, ctx context.Context, p providers.Provider, up *uploaded) []byte {
+	t.Helper()
+	localPath := filepath.Join(t.TempDir(), "downloaded.dat")
+	_, err := transfer.NewDownloader(p).Download(ctx, cloud.DownloadParams{
+		RemotePath: up.result.StoragePath,
+		LocalPath:  localPath,
+		FileInfo: &models.CloudFile{
+			Path:                 up.result.StoragePath,
+			EncodedEncryptionKey: encryption.EncodeBase64(up.result.EncryptionKey),
+			IV:                   encryption.EncodeBase64(up.result.IV),
+			DecryptedSize:        up.upload.TotalSize,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatalf("read downloaded file: %v", err)
+	}
+	return data
+}
+
+func testStorageType(t *testing.T, h Harness) {
+	p := h.NewProvider(t)
+	if p.StorageType() == "" {
+		t.Error("StorageType() returned empty string")
+	}
+}
+
+func testRoundTrip(t *testing.T, h Harness) {
+	sizes := map[string]int64{
+		"Empty":     0,
+		"OneByte":   1,
+		"BlockSize": 16,
+		"Small":     4096 + 7,
+	}
+	if h.MultipartSize > 0 {
+		sizes["Multipart"] = h.MultipartSize
+	}
+
+	for name, size := range sizes {
+		size := size
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			p := h.NewProvider(t)
+			data := randomBytes(t, size)
+
+			up := uploadBytes(t, ctx, p, data)
+			if name == "Multipart" && up.upload.TotalParts < 2 {
+				t.Fatalf("MultipartSize %d produced %d part(s), want at least 2", size, up.upload.TotalParts)
+			}
+
+			got := downloadBytes(t, ctx, p, up)
+			if !bytes.Equal(got, data) {
+				t.Fatalf("round trip mismatch: got %d bytes, want %d", len(got), len(data))
+			}
+		})
+	}
+}
+
+func testMetadata(t *testing.T, h Harness) {
+	ctx := context.Background()
+	p := h.NewProvider(t)
+	up := uploadBytes(t, ctx, p, randomBytes(t, 1000))
+
+	version, fileID, partSize, iv, err := p.DetectFormat(ctx, up.result.StoragePath)
+	if err != nil {
+		t.Fatalf("DetectFormat: %v", err)
+	}
+	if version != 2 {
+		t.Errorf("format version = %d, want 2 (CBC streaming)", version)
+	}
+	if fileID != "" {
+		t.Errorf("fileID = %q, want empty for CBC streaming", fileID)
+	}
+	if partSize != up.upload.PartSize {
+		t.Errorf("partSize = %d, want %d", partSize, up.upload.PartSize)
+	}
+	if !bytes.Equal(iv, up.upload.InitialIV) {
+		t.Error("metadata IV does not match the upload initial IV")
+	}
+
+	size, err := p.GetEncryptedSize(ctx, up.result.StoragePath)
+	if err != nil {
+		t.Fatalf("GetEncryptedSize: %v", err)
+	}
+	if size != int64(len(up.ciphertext)) {
+		t.Errorf("GetEncryptedSize = %d, want %d", size, len(up.ciphertext))
+	}
+}
+
+func testRange(t *testing.T, h Harness) {
+	ctx := context.Background()
+	p := h.NewProvider(t)
+	up := uploadBytes(t, ctx, p, randomBytes(t, 5000))
+	size := int64(len(up.ciphertext))
+
+	ranges := []struct {
+		name           string
+		offset, length int64
+	}{
+		{"Start", 0, 16},
+		{"Middle", 32, 100},
+		{"LastBlock", size - 16, 16},
+		{"Whole", 0, size},
+	}
+	for _, r := range ranges {
+		t.Run(r.name, func(t *testing.T) {
+			var reported int64
+			got, err := p.DownloadEncryptedRange(ctx, up.result.StoragePath, r.offset, r.length, func(n int64) {
+				reported += n
+			})
+			if err != nil {
+				t.Fatalf("DownloadEncryptedRange(%d, %d): %v", r.offset, r.length, err)
+			}
+			want := up.ciphertext[r.offset : r.offset+r.length]
+			if !bytes.Equal(got, want) {
+				t.Fatalf("DownloadEncryptedRange(%d, %d) returned wrong bytes", r.offset, r.length)
+			}
+			if reported != r.length {
+				t.Errorf("progress reported %d bytes, want %d", reported, r.length)
+			}
+		})
+	}
+
+	t.Run("NilProgress", func(t *testing.T) {
+		if _, err := p.DownloadEncryptedRange(ctx, up.result.StoragePath, 0, 16, nil); err != nil {
+			t.Fatalf("DownloadEncryptedRange with nil progress: %v", err)
+		}
+	})
+}
+
+func testMissingObject(t *testing.T, h Harness) {
+	ctx := context.Background()
+	p := h.NewProvider(t)
+	missing := "conformance/does-not-exist"
+
+	if _, _, _, _, err := p.DetectFormat(ctx, missing); err == nil {
+		t.Error("DetectFormat on missing object returned nil error")
+	}
+	if _, err := p.GetEncryptedSize(ctx, missing); err == nil {
+		t.Error("GetEncryptedSize on missing object returned nil error")
+	}
+	if _, err := p.DownloadEncryptedRange(ctx, missing, 0, 16, nil); err == nil {
+		t.Error("DownloadEncryptedRange on missing object returned nil error")
+	}
+}
+
+func testAbort(t *testing.T, h Harness) {
+	ctx := context.Background()
+	p := h.NewProvider(t)
+	data := randomBytes(t, 100)
+	upload := initUpload(t, ctx, p, data)
+
+	exists, err := p.ValidateStreamingUploadExists(ctx, upload.UploadID, upload.StoragePath)
+	if err != nil {
+		t.Fatalf("ValidateStreamingUploadExists: %v", err)
+	}
+	if !exists {
+		t.Error("ValidateStreamingUploadExists = false for an in-progress upload")
+	}
+
+	if err := p.AbortStreamingUpload(ctx, upload); err != nil {
+		t.Fatalf("AbortStreamingUpload: %v", err)
+	}
+	if _, err := p.GetEncryptedSize(ctx, upload.StoragePath); err == nil {
+		t.Error("aborted upload is readable")
+	}
+}
+
+func testResume(t *testing.T, h Harness) {
+	if h.MultipartSize <= 0 {
+		t.Skip("Harness.MultipartSize not set")
+	}
+	ctx := context.Background()
+	p := h.NewProvider(t)
+	data := randomBytes(t, h.MultipartSize)
+
+	upload := initUpload(t, ctx, p, data)
+	if upload.TotalParts < 2 {
+		t.Fatalf("MultipartSize %d produced %d part(s), want at least 2", h.MultipartSize, upload.TotalParts)
+	}
+
+	// Upload the first part, then simulate a restart from saved resume state.
+	first, err := p.EncryptStreamingPart(ctx, upload, 0, plaintextPart(upload, data, 0))
+	if err != nil {
+		t.Fatalf("EncryptStreamingPart(0): %v", err)
+	}
+	firstPart, err := p.UploadCiphertext(ctx, upload, 0, first)
+	if err != nil {
+		t.Fatalf("UploadCiphertext(0): %v", err)
+	}
+
+	exists, err := p.ValidateStreamingUploadExists(ctx, upload.UploadID, upload.StoragePath)
+	if err != nil {
+		t.Fatalf("ValidateStreamingUploadExists: %v", err)
+	}
+	if !exists {
+		t.Fatal("ValidateStreamingUploadExists = false for a resumable upload")
+	}
+
+	resumed, err := p.InitStreamingUploadFromState(ctx, transfer.StreamingUploadResumeParams{
+		LocalPath:    upload.LocalPath,
+		FileSize:     upload.TotalSize,
+		StoragePath:  upload.StoragePath,
+		UploadID:     upload.UploadID,
+		MasterKey:    upload.MasterKey,
+		InitialIV:    upload.InitialIV,
+		CurrentIV:    first[len(first)-16:],
+		PartSize:     upload.PartSize,
+		RandomSuffix: upload.RandomSuffix,
+	})
+	if err != nil {
+		t.Fatalf("InitStreamingUploadFromState: %v", err)
+	}
+
+	rest, restCiphertext := uploadParts(t, ctx, p, resumed, data, 1)
+	result, err := p.CompleteStreamingUpload(ctx, resumed, append([]*transfer.PartResult{firstPart}, rest...))
+	if err != nil {
+		t.Fatalf("CompleteStreamingUpload: %v", err)
+	}
+	checkResult(t, upload, result)
+
+	up := &uploaded{upload: resumed, result: result, ciphertext: append(first, restCiphertext...)}
+	if got := downloadBytes(t, ctx, p, up); !bytes.Equal(got, data) {
+		t.Fatalf("resumed round trip mismatch: got %d bytes, want %d", len(got), len(data))
+	}
+}
+
+func testCredentialRefresh(t *testing.T, h Harness) {
+	ctx := context.Background()
+	p := h.NewProvider(t)
+
+	if err := p.RefreshCredentials(ctx); err != nil {
+		t.Fatalf("RefreshCredentials: %v", err)
+	}
+
+	if h.ExpireCredentials == nil {
+		t.Skip("Harness.ExpireCredentials not set")
+	}
+
+	data := randomBytes(t, 2000)
+	up := uploadBytes(t, ctx, p, data)
+
+	h.ExpireCredentials(p)
+	if _, err := p.GetEncryptedSize(ctx, up.result.StoragePath); err != nil {
+		t.Fatalf("GetEncryptedSize after credential expiry: %v", err)
+	}
+
+	h.ExpireCredentials(p)
+	if got := downloadBytes(t, ctx, p, up); !bytes.Equal(got, data) {
+		t.Fatal("download after credential expiry returned wrong data")
+	}
+
+	h.ExpireCredentials(p)
+	uploadBytes(t, ctx, p, data)
+}
+
+func testCancellation(t *testing.T, h Harness) {
+	p := h.NewProvider(t)
+	up := uploadBytes(t, context.Background(), p, randomBytes(t, 100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := p.DownloadEncryptedRange(ctx, up.result.StoragePath, 0, 16, nil); err == nil {
+		t.Error("DownloadEncryptedRange with cancelled context returned nil error")
+	}
+}
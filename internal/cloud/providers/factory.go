@@ -13,7 +13,17 @@ import (
 	"github.com/rescale/rescale-int/internal/models"
 )
 
+func init() {
+	Register("S3Storage", func(storageInfo *models.StorageInfo, apiClient *api.Client) (Provider, error) {
+		return s3.NewProvider(storageInfo, apiClient)
+	})
+	Register("AzureStorage", func(storageInfo *models.StorageInfo, apiClient *api.Client) (Provider, error) {
+		return azure.NewProvider(storageInfo, apiClient)
+	})
+}
+
 // Factory implements CloudTransferFactory and creates providers based on storage type.
+// Storage types are resolved through the provider registry (see Register).
 type Factory struct{}
 
 // NewFactory creates a new provider factory.
@@ -22,21 +32,18 @@ func NewFactory() *Factory {
 }
 
 // NewTransfer creates a CloudTransfer for the specified storage type.
-// storageType should be "S3Storage" or "AzureStorage".
+// storageType must have been registered; "S3Storage" and "AzureStorage" are built in.
 func (f *Factory) NewTransfer(
 	ctx context.Context,
 	storageType string,
 	storageInfo *models.StorageInfo,
 	apiClient *api.Client,
 ) (cloud.CloudTransfer, error) {
-	switch storageType {
-	case "S3Storage":
-		return s3.NewProvider(storageInfo, apiClient)
-	case "AzureStorage":
-		return azure.NewProvider(storageInfo, apiClient)
-	default:
+	ctor, ok := lookup(storageType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported storage type: %s", storageType)
 	}
+	return ctor(storageInfo, apiClient)
 }
 
 // NewTransferFromStorageInfo creates a CloudTransfer from StorageInfo.
@@ -54,3 +61,5 @@ func (f *Factory) NewTransferFromStorageInfo(
 
 // Compile-time interface verification
 var _ cloud.CloudTransferFactory = (*Factory)(nil)
+var _ Provider = (*s3.Provider)(nil)
+var _ Provider = (*azure.Provider)(nil)
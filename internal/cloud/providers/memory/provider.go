@@ -0,0 +1,423 @@
+// Package memory provides an in-memory implementation of providers.Provider.
+// It is the reference implementation for the provider SDK: it stores objects
+// and multipart uploads in maps, uses the same CBC streaming format and metadata
+// as the S3 and Azure providers, and simulates credential expiry so the
+// conformance suite can exercise refresh behavior without a real backend.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/providers"
+	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/crypto" // package name is 'encryption'
+)
+
+// StorageType is the storage type reported by the in-memory provider.
+const StorageType = "MemoryStorage"
+
+// DefaultPartSize is the plaintext part size used when NewProvider is given zero.
+const DefaultPartSize int64 = 64 * 1024
+
+// object is a completed upload.
+type object struct {
+	data     []byte
+	metadata map[string]string
+}
+
+// pendingUpload is a multipart upload that has not been completed or aborted.
+type pendingUpload struct {
+	storagePath string
+	metadata    map[string]string
+	parts       map[int64][]byte
+}
+
+// memoryProviderData carries the per-upload encryption state.
+type memoryProviderData struct {
+	encryptState *transfer.StreamingEncryptionState
+}
+
+// Provider implements providers.Provider with in-process storage.
+// Safe for concurrent use.
+type Provider struct {
+	partSize int64
+
+	mu      sync.Mutex
+	objects map[string]*object
+	uploads map[string]*pendingUpload
+
+	credentialsValid bool
+	refreshCount     int
+}
+
+// NewProvider creates an empty in-memory provider.
+// partSize is the plaintext part size; it is rounded up to a multiple of the
+// AES block size so non-final parts encrypt without padding.
+func NewProvider(partSize int64) *Provider {
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	if rem := partSize % 16; rem != 0 {
+		partSize += 16 - rem
+	}
+	return &Provider{
+		partSize:         partSize,
+		objects:          make(map[string]*object),
+		uploads:          make(map[string]*pendingUpload),
+		credentialsValid: true,
+	}
+}
+
+// ExpireCredentials invalidates the current credentials. The next operation
+// refreshes them, as a real provider does when its STS or SAS token expires.
+func (p *Provider) ExpireCredentials() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.credentialsValid = false
+}
+
+// RefreshCount returns how many times credentials have been refreshed.
+func (p *Provider) RefreshCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refreshCount
+}
+
+// ensureFreshCredentials refreshes expired credentials. Callers must hold p.mu.
+func (p *Provider) ensureFreshCredentials() {
+	if !p.credentialsValid {
+		p.credentialsValid = true
+		p.refreshCount++
+	}
+}
+
+// begin checks ctx, locks the provider and refreshes credentials if needed.
+// On success the caller must unlock p.mu.
+func (p *Provider) begin(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.ensureFreshCredentials()
+	return nil
+}
+
+// =============================================================================
+// CloudTransfer Interface Implementation
+// =============================================================================
+
+// Upload is not supported directly; uploads go through the upload orchestrator.
+func (p *Provider) Upload(ctx context.Context, params cloud.UploadParams) (*cloud.UploadResult, error) {
+	return nil, fmt.Errorf("streaming uploads should go through transfer.Uploader, not provider.Upload directly")
+}
+
+// Download is not supported directly; downloads go through transfer.Downloader.
+func (p *Provider) Download(ctx context.Context, params cloud.DownloadParams) error {
+	return fmt.Errorf("downloads should go through transfer.Downloader orchestrator, not provider.Download directly")
+}
+
+// RefreshCredentials forces a credential refresh.
+func (p *Provider) RefreshCredentials(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.credentialsValid = true
+	p.refreshCount++
+	return nil
+}
+
+// StorageType returns "MemoryStorage".
+func (p *Provider) StorageType() string {
+	return StorageType
+}
+
+// =============================================================================
+// StreamingConcurrentUploader Interface Implementation
+// =============================================================================
+
+// InitStreamingUpload starts a multipart upload with CBC streaming encryption.
+func (p *Provider) InitStreamingUpload(ctx context.Context, params transfer.StreamingUploadInitParams) (*transfer.StreamingUpload, error) {
+	randomSuffix, err := encryption.GenerateSecureRandomString(22)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate random suffix: %w", err)
+	}
+	uploadID, err := encryption.GenerateSecureRandomString(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload ID: %w", err)
+	}
+
+	encryptState, err := transfer.NewStreamingEncryptionState(p.partSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encryption state: %w", err)
+	}
+
+	storagePath := fmt.Sprintf("memory/%s-%s", filepath.Base(params.LocalPath), randomSuffix)
+
+	if err := p.begin(ctx); err != nil {
+		return nil, err
+	}
+	p.uploads[uploadID] = &pendingUpload{
+		storagePath: storagePath,
+		metadata: map[string]string{
+			"iv":              encryption.EncodeBase64(encryptState.GetInitialIV()),
+			"streamingformat": "cbc",
+			"partsize":        strconv.FormatInt(p.partSize, 10),
+		},
+		parts: make(map[int64][]byte),
+	}
+	p.mu.Unlock()
+
+	return &transfer.StreamingUpload{
+		UploadID:     uploadID,
+		StoragePath:  storagePath,
+		MasterKey:    encryptState.GetKey(),
+		InitialIV:    encryptState.GetInitialIV(),
+		PartSize:     p.partSize,
+		LocalPath:    params.LocalPath,
+		TotalSize:    params.FileSize,
+		TotalParts:   transfer.CalculateTotalParts(params.FileSize, p.partSize),
+		RandomSuffix: randomSuffix,
+		ProviderData: &memoryProviderData{encryptState: encryptState},
+	}, nil
+}
+
+// InitStreamingUploadFromState resumes a multipart upload from saved CBC state.
+func (p *Provider) InitStreamingUploadFromState(ctx context.Context, params transfer.StreamingUploadResumeParams) (*transfer.StreamingUpload, error) {
+	if params.InitialIV == nil || params.CurrentIV == nil {
+		return nil, fmt.Errorf("cannot resume upload without CBC initial and current IV")
+	}
+
+	if err := p.begin(ctx); err != nil {
+		return nil, err
+	}
+	pending, ok := p.uploads[params.UploadID]
+	p.mu.Unlock()
+	if !ok || pending.storagePath != params.StoragePath {
+		return nil, fmt.Errorf("no such upload: %s", params.UploadID)
+	}
+
+	encryptState, err := transfer.NewStreamingEncryptionStateFromKey(
+		params.MasterKey, params.InitialIV, params.CurrentIV, params.PartSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encryption state from resume: %w", err)
+	}
+
+	return &transfer.StreamingUpload{
+		UploadID:     params.UploadID,
+		StoragePath:  params.StoragePath,
+		MasterKey:    params.MasterKey,
+		InitialIV:    params.InitialIV,
+		PartSize:     params.PartSize,
+		LocalPath:    params.LocalPath,
+		TotalSize:    params.FileSize,
+		TotalParts:   transfer.CalculateTotalParts(params.FileSize, params.PartSize),
+		RandomSuffix: params.RandomSuffix,
+		ProviderData: &memoryProviderData{encryptState: encryptState},
+	}, nil
+}
+
+// ValidateStreamingUploadExists reports whether the upload is still pending.
+func (p *Provider) ValidateStreamingUploadExists(ctx context.Context, uploadID, storagePath string) (bool, error) {
+	if err := p.begin(ctx); err != nil {
+		return false, err
+	}
+	defer p.mu.Unlock()
+
+	pending, ok := p.uploads[uploadID]
+	return ok && pending.storagePath == storagePath, nil
+}
+
+// UploadStreamingPart encrypts and uploads a single part.
+func (p *Provider) UploadStreamingPart(ctx context.Context, uploadState *transfer.StreamingUpload, partIndex int64, plaintext []byte) (*transfer.PartResult, error) {
+	ciphertext, err := p.EncryptStreamingPart(ctx, uploadState, partIndex, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return p.UploadCiphertext(ctx, uploadState, partIndex, ciphertext)
+}
+
+// EncryptStreamingPart encrypts plaintext with CBC chaining.
+// Must be called sequentially.
+func (p *Provider) EncryptStreamingPart(ctx context.Context, uploadState *transfer.StreamingUpload, partIndex int64, plaintext []byte) ([]byte, error) {
+	providerData, ok := uploadState.ProviderData.(*memoryProviderData)
+	if !ok {
+		return nil, fmt.Errorf("invalid provider data for memory streaming upload")
+	}
+
+	isFinal := partIndex == uploadState.TotalParts-1
+	ciphertext, err := providerData.encryptState.EncryptPart(plaintext, isFinal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt part %d: %w", partIndex, err)
+	}
+	return ciphertext, nil
+}
+
+// UploadCiphertext stores an encrypted part. Safe to call concurrently.
+func (p *Provider) UploadCiphertext(ctx context.Context, uploadState *transfer.StreamingUpload, partIndex int64, ciphertext []byte) (*transfer.PartResult, error) {
+	if err := p.begin(ctx); err != nil {
+		return nil, err
+	}
+	pending, ok := p.uploads[uploadState.UploadID]
+	if ok {
+		pending.parts[partIndex] = append([]byte(nil), ciphertext...)
+	}
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("failed to upload part %d: no such upload: %s", partIndex+1, uploadState.UploadID)
+	}
+
+	if uploadState.ByteProgressCallback != nil {
+		uploadState.ByteProgressCallback(int64(len(ciphertext)))
+	}
+
+	return &transfer.PartResult{
+		PartIndex:  partIndex,
+		PartNumber: int32(partIndex + 1),
+		ETag:       fmt.Sprintf("part-%d", partIndex+1),
+		Size:       int64(len(ciphertext)),
+	}, nil
+}
+
+// CompleteStreamingUpload assembles the parts into an object.
+func (p *Provider) CompleteStreamingUpload(ctx context.Context, uploadState *transfer.StreamingUpload, parts []*transfer.PartResult) (*cloud.UploadResult, error) {
+	if err := p.begin(ctx); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+
+	pending, ok := p.uploads[uploadState.UploadID]
+	if !ok {
+		return nil, fmt.Errorf("failed to complete upload: no such upload: %s", uploadState.UploadID)
+	}
+
+	sorted := append([]*transfer.PartResult(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartIndex < sorted[j].PartIndex })
+
+	var data []byte
+	for _, part := range sorted {
+		ciphertext, ok := pending.parts[part.PartIndex]
+		if !ok {
+			return nil, fmt.Errorf("failed to complete upload: part %d was not uploaded", part.PartNumber)
+		}
+		data = append(data, ciphertext...)
+	}
+
+	p.objects[pending.storagePath] = &object{data: data, metadata: pending.metadata}
+	delete(p.uploads, uploadState.UploadID)
+
+	return &cloud.UploadResult{
+		StoragePath:   uploadState.StoragePath,
+		EncryptionKey: uploadState.MasterKey,
+		IV:            uploadState.InitialIV,
+		FormatVersion: 0,
+		PartSize:      uploadState.PartSize,
+	}, nil
+}
+
+// AbortStreamingUpload discards a pending upload and its parts.
+func (p *Provider) AbortStreamingUpload(ctx context.Context, uploadState *transfer.StreamingUpload) error {
+	if err := p.begin(ctx); err != nil {
+		return err
+	}
+	defer p.mu.Unlock()
+
+	if _, ok := p.uploads[uploadState.UploadID]; !ok {
+		return fmt.Errorf("failed to abort upload: no such upload: %s", uploadState.UploadID)
+	}
+	delete(p.uploads, uploadState.UploadID)
+	return nil
+}
+
+// =============================================================================
+// StreamingPartDownloader Interface Implementation
+// =============================================================================
+
+// getObject returns the stored object for remotePath.
+func (p *Provider) getObject(ctx context.Context, remotePath string) (*object, error) {
+	if err := p.begin(ctx); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+
+	obj, ok := p.objects[remotePath]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %s", remotePath)
+	}
+	return obj, nil
+}
+
+// DetectFormat reads the encryption format from object metadata.
+// Objects written by this provider are always CBC streaming (version 2).
+func (p *Provider) DetectFormat(ctx context.Context, remotePath string) (int, string, int64, []byte, error) {
+	obj, err := p.getObject(ctx, remotePath)
+	if err != nil {
+		return 0, "", 0, nil, fmt.Errorf("failed to get object metadata: %w", err)
+	}
+
+	var iv []byte
+	if ivStr := obj.metadata["iv"]; ivStr != "" {
+		if decoded, decodeErr := encryption.DecodeBase64(ivStr); decodeErr == nil {
+			iv = decoded
+		}
+	}
+
+	if obj.metadata["streamingformat"] == "cbc" {
+		var partSize int64
+		if parsed, parseErr := strconv.ParseInt(obj.metadata["partsize"], 10, 64); parseErr == nil && parsed > 0 {
+			partSize = parsed
+		}
+		return 2, "", partSize, iv, nil
+	}
+
+	return 0, "", 0, iv, nil
+}
+
+// DownloadStreaming is not supported: the in-memory provider never writes
+// the legacy HKDF (v1) format.
+func (p *Provider) DownloadStreaming(ctx context.Context, remotePath, localPath string, masterKey []byte, progressCallback cloud.ProgressCallback) error {
+	return fmt.Errorf("HKDF streaming format (v1) is not supported by the memory provider")
+}
+
+// GetEncryptedSize returns the stored ciphertext size.
+func (p *Provider) GetEncryptedSize(ctx context.Context, remotePath string) (int64, error) {
+	obj, err := p.getObject(ctx, remotePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get object metadata: %w", err)
+	}
+	return int64(len(obj.data)), nil
+}
+
+// DownloadEncryptedRange returns bytes [offset, offset+length) of the ciphertext.
+// Like an HTTP range request, a range that runs past the end is truncated.
+func (p *Provider) DownloadEncryptedRange(ctx context.Context, remotePath string, offset, length int64, progressCallback func(int64)) ([]byte, error) {
+	obj, err := p.getObject(ctx, remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download range [%d-%d]: %w", offset, offset+length-1, err)
+	}
+
+	size := int64(len(obj.data))
+	if offset < 0 || length <= 0 || offset >= size {
+		return nil, fmt.Errorf("failed to download range [%d-%d]: range not satisfiable for size %d", offset, offset+length-1, size)
+	}
+	end := offset + length
+	if end > size {
+		end = size
+	}
+
+	data := append([]byte(nil), obj.data[offset:end]...)
+	if progressCallback != nil {
+		progressCallback(int64(len(data)))
+	}
+	return data, nil
+}
+
+// Compile-time interface verification
+var _ providers.Provider = (*Provider)(nil)
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/cloud/providers"
+	"github.com/rescale/rescale-int/internal/cloud/providers/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, conformance.Harness{
+		NewProvider: func(t *testing.T) providers.Provider {
+			return NewProvider(1024)
+		},
+		MultipartSize: 3*1024 + 100,
+		ExpireCredentials: func(p providers.Provider) {
+			p.(*Provider).ExpireCredentials()
+		},
+	})
+}
+
+func TestExpiredCredentialsAreRefreshed(t *testing.T) {
+	p := NewProvider(0)
+	if p.partSize != DefaultPartSize {
+		t.Fatalf("partSize = %d, want %d", p.partSize, DefaultPartSize)
+	}
+
+	p.ExpireCredentials()
+	if _, err := p.GetEncryptedSize(t.Context(), "missing"); err == nil {
+		t.Fatal("expected error for missing object")
+	}
+	if got := p.RefreshCount(); got != 1 {
+		t.Errorf("RefreshCount = %d, want 1", got)
+	}
+}
+
+func TestNewProviderRoundsPartSize(t *testing.T) {
+	if got := NewProvider(100).partSize; got != 112 {
+		t.Errorf("partSize = %d, want 112", got)
+	}
+}
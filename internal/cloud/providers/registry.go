@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Provider is the interface a storage backend implements to plug into the
+// upload and download orchestrators. It is the contract covered by the
+// conformance suite in the conformance package.
+//
+// Required behavior:
+//   - Uploads go through the streaming multipart methods. Parts are encrypted
+//     sequentially (CBC chaining) and may be uploaded concurrently.
+//   - Completed objects carry "iv", "streamingformat" = "cbc" and "partsize"
+//     metadata so DetectFormat reports format version 2.
+//   - DownloadEncryptedRange returns exactly the requested [offset, offset+length)
+//     bytes of the stored ciphertext.
+//   - Credentials are refreshed transparently before they expire; RefreshCredentials
+//     forces a refresh for long-running operations.
+//
+// Optional interfaces: cloud.FileInfoSetter (cross-storage downloads),
+// transfer.PreEncryptUploader (--pre-encrypt uploads) and transfer.LegacyDownloader
+// (files uploaded by the platform or older clients).
+type Provider interface {
+	transfer.StreamingConcurrentUploader
+	transfer.StreamingPartDownloader
+}
+
+// Constructor creates a Provider for the given storage.
+// apiClient is used to fetch credentials and may be nil for providers that
+// do not need it.
+type Constructor func(storageInfo *models.StorageInfo, apiClient *api.Client) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Constructor)
+)
+
+// Register makes a provider available for the given storage type
+// (the StorageInfo.StorageType value reported by the platform).
+// It panics if storageType is empty, ctor is nil, or the type is already registered,
+// mirroring database/sql.Register.
+func Register(storageType string, ctor Constructor) {
+	if storageType == "" {
+		panic("providers: Register with empty storage type")
+	}
+	if ctor == nil {
+		panic("providers: Register constructor is nil for " + storageType)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[storageType]; exists {
+		panic(fmt.Sprintf("providers: Register called twice for %s", storageType))
+	}
+	registry[storageType] = ctor
+}
+
+// Registered returns the registered storage types in sorted order.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for storageType := range registry {
+		types = append(types, storageType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// lookup returns the constructor registered for storageType.
+func lookup(storageType string) (Constructor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	ctor, ok := registry[storageType]
+	return ctor, ok
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestBuiltInProvidersRegistered(t *testing.T) {
+	registered := map[string]bool{}
+	for _, storageType := range Registered() {
+		registered[storageType] = true
+	}
+	for _, want := range []string{"S3Storage", "AzureStorage"} {
+		if !registered[want] {
+			t.Errorf("%s is not registered", want)
+		}
+	}
+}
+
+func TestFactoryUsesRegisteredConstructor(t *testing.T) {
+	errStub := errors.New("stub constructor called")
+	Register("RegistryTestStorage", func(storageInfo *models.StorageInfo, apiClient *api.Client) (Provider, error) {
+		return nil, errStub
+	})
+
+	_, err := NewFactory().NewTransfer(context.Background(), "RegistryTestStorage", &models.StorageInfo{}, nil)
+	if !errors.Is(err, errStub) {
+		t.Errorf("NewTransfer error = %v, want %v", err, errStub)
+	}
+}
+
+func TestFactoryUnknownStorageType(t *testing.T) {
+	if _, err := NewFactory().NewTransfer(context.Background(), "NoSuchStorage", &models.StorageInfo{}, nil); err == nil {
+		t.Error("expected error for unregistered storage type")
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	ctor := func(*models.StorageInfo, *api.Client) (Provider, error) { return nil, nil }
+	cases := map[string]func(){
+		"empty type": func() { Register("", ctor) },
+		"nil ctor":   func() { Register("RegistryNilStorage", nil) },
+		"duplicate":  func() { Register("S3Storage", ctor) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register did not panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
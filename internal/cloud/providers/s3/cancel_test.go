@@ -0,0 +1,78 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// stallingServer sends the first bytes of a large response body and then
+// stalls until the client goes away, simulating a slow in-flight part.
+func stallingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "16777216")
+		w.Header().Set("Content-Range", "bytes 0-16777215/16777216")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(make([]byte, 1024))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetObjectRangeOnce_CancelAbortsBody(t *testing.T) {
+	srv := stallingServer(t)
+	c := &S3Client{
+		client: s3.NewFromConfig(aws.Config{
+			Region:      "us-east-1",
+			Credentials: aws.AnonymousCredentials{},
+		}, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(srv.URL)
+			o.UsePathStyle = true
+			o.Retryer = aws.NopRetryer{}
+		}),
+		storageInfo: &models.StorageInfo{ConnectionSettings: models.ConnectionSettings{Container: "bucket"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, err := c.GetObjectRangeOnce(ctx, "key", 0, 16777215)
+	if err != nil {
+		t.Fatalf("GetObjectRangeOnce: %v", err)
+	}
+	defer out.Body.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, out.Body)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("body read error = %v, want context.Canceled", err)
+		}
+		if elapsed := time.Since(start); elapsed > constants.CancelIdleTarget {
+			t.Errorf("body read took %v to abort, want < %v", elapsed, constants.CancelIdleTarget)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("body read did not abort after cancel")
+	}
+}
@@ -462,10 +462,14 @@ func (p *Provider) downloadChunkedConcurrent(
 				currentChunkSize = totalSize - offset
 			}
 
-			jobChan <- chunkJob{
+			select {
+			case jobChan <- chunkJob{
 				chunkIndex: chunkIndex,
 				offset:     offset,
 				size:       currentChunkSize,
+			}:
+			case <-opCtx.Done():
+				return
 			}
 		}
 	}()
@@ -208,7 +208,7 @@ func (p *Provider) uploadEncryptedMultipart(ctx context.Context, s3Client *S3Cli
 		copy(partData, buffer[:n])
 
 		var uploadResp *s3.UploadPartOutput
-		err = s3Client.RetryWithBackoff(ctx, fmt.Sprintf("UploadPart %d", partNum), func() error {
+		err = s3Client.RetryPartWithBackoff(ctx, fmt.Sprintf("UploadPart %d", partNum), func() error {
 			var err error
 			uploadResp, err = s3Client.Client().UploadPart(ctx, &s3.UploadPartInput{
 				Bucket:        aws.String(s3Client.Bucket()),
@@ -484,7 +484,7 @@ func (p *Provider) uploadEncryptedMultipartConcurrent(ctx context.Context, s3Cli
 				// Add HTTP tracing if DEBUG_HTTP is enabled
 				partCtx = TraceContext(partCtx, fmt.Sprintf("UploadPart %d/%d (worker %d)", job.partNumber, totalParts, workerID))
 
-				uploadErr := s3Client.RetryWithBackoff(partCtx, fmt.Sprintf("UploadPart %d/%d", job.partNumber, totalParts), func() error {
+				uploadErr := s3Client.RetryPartWithBackoff(partCtx, fmt.Sprintf("UploadPart %d/%d", job.partNumber, totalParts), func() error {
 					var err error
 					uploadResp, err = s3Client.Client().UploadPart(partCtx, &s3.UploadPartInput{
 						Bucket:        aws.String(s3Client.Bucket()),
@@ -500,7 +500,7 @@ func (p *Provider) uploadEncryptedMultipartConcurrent(ctx context.Context, s3Cli
 				cancel()
 
 				if uploadErr != nil {
-					setError(fmt.Errorf("failed to upload part %d/%d: %w", job.partNumber, totalParts, uploadErr))
+					setError(transfer.NewPartError(int64(job.partNumber-1), int64(totalParts), uploadErr))
 					return
 				}
 
@@ -556,10 +556,15 @@ func (p *Provider) uploadEncryptedMultipartConcurrent(ctx context.Context, s3Cli
 
 			buffers.PutChunkBuffer(bufferPtr)
 
-			// Queue this part for upload
-			jobChan <- partJob{
+			// Queue this part for upload. Workers stop receiving once
+			// opCtx is cancelled, so a blocking send would leak this goroutine.
+			select {
+			case jobChan <- partJob{
 				partNumber: partNumber,
 				data:       partData,
+			}:
+			case <-opCtx.Done():
+				return
 			}
 
 			partNumber++
@@ -161,7 +161,7 @@ func (p *Provider) UploadStreamingPart(ctx context.Context, uploadState *transfe
 
 	// Upload the part using S3Client
 	var uploadResp *s3.UploadPartOutput
-	err = providerData.s3Client.RetryWithBackoff(partCtx, fmt.Sprintf("UploadPart %d", partNumber), func() error {
+	err = providerData.s3Client.RetryPartWithBackoff(partCtx, fmt.Sprintf("UploadPart %d", partNumber), func() error {
 		var err error
 		uploadResp, err = providerData.s3Client.Client().UploadPart(partCtx, &s3.UploadPartInput{
 			Bucket:        aws.String(providerData.bucket),
@@ -233,7 +233,7 @@ func (p *Provider) UploadCiphertext(ctx context.Context, uploadState *transfer.S
 	// Reader created inside closure so each retry attempt gets a fresh reader.
 	// Uses uploadProgressReader (io.ReadSeeker) so AWS SDK can rewind on transient errors.
 	var uploadResp *s3.UploadPartOutput
-	err := providerData.s3Client.RetryWithBackoff(partCtx, fmt.Sprintf("UploadPart %d", partNumber), func() error {
+	err := providerData.s3Client.RetryPartWithBackoff(partCtx, fmt.Sprintf("UploadPart %d", partNumber), func() error {
 		// Create fresh reader per attempt (enables retry after partial read)
 		var bodyReader io.ReadSeeker = bytes.NewReader(ciphertext)
 		if uploadState.ByteProgressCallback != nil {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/rescale/rescale-int/internal/util/securedelete"
 )
 
 // ByteRange represents a completed byte range in the output file.
@@ -326,7 +328,7 @@ func CleanupExpiredDownloadResume(state *DownloadResumeState, localPath string,
 			if verbose {
 				fmt.Printf("Cleaning up expired download temp file: %s\n", state.EncryptedPath)
 			}
-			os.Remove(state.EncryptedPath)
+			securedelete.Remove(state.EncryptedPath)
 		}
 	}
 
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MultipartUploadState is the persisted progress of a streaming multipart
+// upload, kept in a per-user directory (not next to the source file, which
+// may be read-only) so an upload interrupted by a killed or cancelled
+// process resumes from its completed parts. One state exists per source
+// file; see MultipartStatePath.
+type MultipartUploadState struct {
+	LocalPath    string    `json:"local_path"`
+	FileSize     int64     `json:"file_size"`
+	ModTime      time.Time `json:"mod_time"` // Source modification time; a changed file is uploaded afresh
+	StorageType  string    `json:"storage_type"`
+	StoragePath  string    `json:"storage_path"`
+	UploadID     string    `json:"upload_id"`  // S3 upload ID (empty for Azure)
+	MasterKey    string    `json:"master_key"` // Base64-encoded
+	InitialIV    string    `json:"initial_iv"` // Base64-encoded
+	PartSize     int64     `json:"part_size"`
+	RandomSuffix string    `json:"random_suffix"`
+
+	Parts []MultipartPart `json:"parts"` // Completed parts, in completion order
+
+	ProcessID  int       `json:"process_id"` // Process currently uploading
+	CreatedAt  time.Time `json:"created_at"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// MultipartPart is one uploaded part of a MultipartUploadState.
+type MultipartPart struct {
+	Index      int64  `json:"index"` // 0-based
+	PartNumber int32  `json:"part_number"`
+	ETag       string `json:"etag"` // S3 ETag or Azure block ID
+	Size       int64  `json:"size"` // Plaintext bytes
+	CipherSize int64  `json:"cipher_size"`
+	EndIV      string `json:"end_iv"` // Base64 last ciphertext block: the CBC IV of the next part
+}
+
+// MultipartStatePath returns where the upload state of localPath is kept in
+// dir: a file named after a hash of the absolute path.
+func MultipartStatePath(dir, localPath string) string {
+	if abs, err := filepath.Abs(localPath); err == nil {
+		localPath = abs
+	}
+	sum := sha256.Sum256([]byte(localPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:12])+".upload.json")
+}
+
+// LoadMultipartState reads the state at path. Returns nil without error if
+// there is none.
+func LoadMultipartState(path string) (*MultipartUploadState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload state: %w", err)
+	}
+	var s MultipartUploadState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse upload state: %w", err)
+	}
+	return &s, nil
+}
+
+// SaveMultipartState writes s to path atomically (temp file + rename),
+// readable only by the user since it holds the encryption key.
+func SaveMultipartState(path string, s *MultipartUploadState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create upload state directory: %w", err)
+	}
+	s.LastUpdate = time.Now()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write upload state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write upload state: %w", err)
+	}
+	return nil
+}
+
+// DeleteMultipartState removes the state at path, if any.
+func DeleteMultipartState(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete upload state: %w", err)
+	}
+	return nil
+}
+
+// MultipartStateExists reports whether localPath has upload state in dir.
+func MultipartStateExists(dir, localPath string) bool {
+	_, err := os.Stat(MultipartStatePath(dir, localPath))
+	return err == nil
+}
+
+// Check returns why s cannot be resumed for an upload of localPath to
+// storageType, or nil. It does not ask the provider whether the upload
+// still exists.
+func (s *MultipartUploadState) Check(localPath, storageType string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+	abs, _ := filepath.Abs(localPath)
+	switch {
+	case s.LocalPath != abs:
+		return fmt.Errorf("local path mismatch")
+	case s.StorageType != storageType:
+		return fmt.Errorf("storage changed (was %s, now %s)", s.StorageType, storageType)
+	case info.Size() != s.FileSize || !info.ModTime().Equal(s.ModTime):
+		return fmt.Errorf("source file changed since the upload started")
+	case time.Since(s.CreatedAt) > MaxResumeAge:
+		return fmt.Errorf("resume state expired")
+	case s.PartSize <= 0 || s.MasterKey == "" || s.InitialIV == "":
+		return fmt.Errorf("resume state incomplete")
+	}
+	return nil
+}
+
+// InUseByOtherProcess reports whether another running process is uploading
+// the file, in which case its state must be left alone.
+func (s *MultipartUploadState) InUseByOtherProcess() bool {
+	return s.ProcessID != os.Getpid() && time.Since(s.LastUpdate) < LockStaleTimeout && isProcessRunning(s.ProcessID)
+}
+
+// CompletedPrefix returns the parts 0..n-1 that were all uploaded, in order.
+// Encryption is chained, so an upload can only resume after an unbroken run
+// of parts; parts completed out of order beyond a gap are uploaded again.
+func (s *MultipartUploadState) CompletedPrefix() []MultipartPart {
+	byIndex := make(map[int64]MultipartPart, len(s.Parts))
+	for _, p := range s.Parts {
+		byIndex[p.Index] = p
+	}
+	var prefix []MultipartPart
+	for i := int64(0); ; i++ {
+		p, ok := byIndex[i]
+		if !ok {
+			return prefix
+		}
+		prefix = append(prefix, p)
+	}
+}
@@ -261,3 +261,71 @@ func TestDownloadState_RoundTrip(t *testing.T) {
 		t.Errorf("FormatVersion: expected %d, got %d", original.FormatVersion, loaded.FormatVersion)
 	}
 }
+
+// TestMultipartState_RoundTrip tests save/load, permissions and the checks
+// that decide whether a saved multipart upload can be resumed.
+func TestMultipartState_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	localPath := filepath.Join(tmpDir, "testfile.bin")
+	if err := os.WriteFile(localPath, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	info, _ := os.Stat(localPath)
+
+	stateDir := filepath.Join(tmpDir, "transfers")
+	path := MultipartStatePath(stateDir, localPath)
+	original := &MultipartUploadState{
+		LocalPath:   localPath,
+		FileSize:    info.Size(),
+		ModTime:     info.ModTime(),
+		StorageType: "S3Storage",
+		StoragePath: "bucket/key",
+		UploadID:    "test-upload-id",
+		MasterKey:   "master-key",
+		InitialIV:   "iv",
+		PartSize:    4,
+		// Part 1 is missing: only part 0 can be resumed after
+		Parts:     []MultipartPart{{Index: 2, ETag: "c"}, {Index: 0, ETag: "a"}},
+		ProcessID: os.Getpid(),
+		CreatedAt: time.Now(),
+	}
+	if err := SaveMultipartState(path, original); err != nil {
+		t.Fatalf("SaveMultipartState failed: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("state file mode = %v, %v; want 0600", info, err)
+	}
+	if !MultipartStateExists(stateDir, localPath) {
+		t.Error("MultipartStateExists = false after save")
+	}
+
+	loaded, err := LoadMultipartState(path)
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadMultipartState = %v, %v", loaded, err)
+	}
+	if loaded.UploadID != original.UploadID || len(loaded.Parts) != 2 {
+		t.Errorf("loaded state %+v does not match saved", loaded)
+	}
+	if prefix := loaded.CompletedPrefix(); len(prefix) != 1 || prefix[0].ETag != "a" {
+		t.Errorf("CompletedPrefix = %+v, want only part 0", prefix)
+	}
+	if err := loaded.Check(localPath, "S3Storage"); err != nil {
+		t.Errorf("Check on unchanged file: %v", err)
+	}
+	if err := loaded.Check(localPath, "AzureStorage"); err == nil {
+		t.Error("Check accepted a different storage type")
+	}
+	if err := os.WriteFile(localPath, []byte("changed content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Check(localPath, "S3Storage"); err == nil {
+		t.Error("Check accepted a changed source file")
+	}
+
+	if err := DeleteMultipartState(path); err != nil {
+		t.Errorf("DeleteMultipartState: %v", err)
+	}
+	if loaded, err := LoadMultipartState(path); loaded != nil || err != nil {
+		t.Errorf("LoadMultipartState after delete = %v, %v; want nil, nil", loaded, err)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/rescale/rescale-int/internal/models"
 	"github.com/rescale/rescale-int/internal/resources"
 	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
 )
 
 // Downloader orchestrates file downloads using a CloudTransfer provider.
@@ -330,7 +331,7 @@ func (d *Downloader) downloadLegacy(ctx context.Context, prep *DownloadPrep) err
 	defer func() {
 		var lastErr error
 		for i := 0; i < 3; i++ {
-			if lastErr = os.Remove(encryptedPath); lastErr == nil || os.IsNotExist(lastErr) {
+			if lastErr = securedelete.Remove(encryptedPath); lastErr == nil || os.IsNotExist(lastErr) {
 				return
 			}
 			time.Sleep(100 * time.Millisecond)
@@ -380,7 +381,7 @@ func (d *Downloader) downloadLegacy(ctx context.Context, prep *DownloadPrep) err
 	// Use DecryptFileWithHash to compute hash during decryption, avoiding a race
 	// condition where post-download verification re-reads the file and may get
 	// stale cache data.
-	computedHash, err := encryption.DecryptFileWithHash(encryptedPath, localPath, prep.EncryptionKey, prep.IV)
+	computedHash, err := encryption.DecryptFileWithHashContext(ctx, encryptedPath, localPath, prep.EncryptionKey, prep.IV)
 	if err != nil {
 		// Check for disk full during decryption
 		if storage.IsDiskFullError(err) {
@@ -842,7 +843,7 @@ func (d *Downloader) downloadCBCStreaming(ctx context.Context, prep *DownloadPre
 	// where post-download verification could read stale data from filesystem cache.
 	if expectedHash != "" {
 		if !strings.EqualFold(actualHash, expectedHash) {
-			return fmt.Errorf("checksum mismatch (computed during write): expected SHA-512=%s, got %s", expectedHash, actualHash)
+			return fmt.Errorf("%w (computed during write): expected SHA-512=%s, got %s", cloud.ErrChecksumMismatch, expectedHash, actualHash)
 		}
 	}
 
@@ -1013,16 +1014,16 @@ func (d *Downloader) downloadStreamingConcurrent(
 
 	// Set up concurrent download
 	type partJob struct {
-		partIndex         int64
-		encryptedStart    int64
-		encryptedEnd      int64
-		plaintextOffset   int64
+		partIndex       int64
+		encryptedStart  int64
+		encryptedEnd    int64
+		plaintextOffset int64
 	}
 
 	type partResult struct {
-		partIndex       int64
-		plaintextSize   int64
-		err             error
+		partIndex     int64
+		plaintextSize int64
+		err           error
 	}
 
 	// Create channels
@@ -1062,7 +1063,7 @@ func (d *Downloader) downloadStreamingConcurrent(
 			select {
 			case <-progressTicker.C:
 				if prep.Params.ProgressCallback != nil && decryptedSize > 0 {
-						currentBytes := atomic.LoadInt64(&decryptedBytes)
+					currentBytes := atomic.LoadInt64(&decryptedBytes)
 					prep.Params.ProgressCallback(float64(currentBytes) / float64(decryptedSize))
 				}
 			case <-progressDone:
@@ -1158,11 +1159,15 @@ func (d *Downloader) downloadStreamingConcurrent(
 				encEnd = encryptedSize
 			}
 
-			jobChan <- partJob{
+			select {
+			case jobChan <- partJob{
 				partIndex:       partIdx,
 				encryptedStart:  encStart,
 				encryptedEnd:    encEnd,
 				plaintextOffset: plaintextOffset,
+			}:
+			case <-opCtx.Done():
+				return
 			}
 
 			// Calculate plaintext offset for next part
@@ -1300,8 +1305,8 @@ type StreamingDownloadInitParams struct {
 // StreamingDownload represents an in-progress streaming download.
 type StreamingDownload struct {
 	// Download identifiers
-	RemotePath  string // Path in cloud storage
-	LocalPath   string // Local destination path
+	RemotePath string // Path in cloud storage
+	LocalPath  string // Local destination path
 
 	// Decryption state
 	MasterKey []byte // Master encryption key
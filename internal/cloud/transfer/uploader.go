@@ -5,9 +5,12 @@ package transfer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/rescale/rescale-int/internal/cloud"
+	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/transfer"
 )
 
@@ -115,6 +118,42 @@ type PartResult struct {
 	Size       int64  // Size of plaintext data uploaded
 }
 
+// PartError reports an upload part that failed for good: either storage
+// rejected it (permanent; retrying the file will not help) or it used up its
+// retry budget on transient errors (the file is worth retrying later).
+// The classification survives wrapping, see inthttp.ClassifyError.
+type PartError struct {
+	PartIndex  int64 // 0-based part index
+	TotalParts int64
+	Type       inthttp.ErrorType
+	Err        error
+}
+
+// NewPartError classifies err from uploading part partIndex of totalParts.
+// Cancellation is returned unwrapped so callers still see context.Canceled.
+func NewPartError(partIndex, totalParts int64, err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &PartError{PartIndex: partIndex, TotalParts: totalParts, Type: inthttp.ClassifyError(err), Err: err}
+}
+
+// Permanent reports whether storage rejected the part outright.
+func (e *PartError) Permanent() bool { return e.Type == inthttp.ErrorTypeFatal }
+
+// ErrorType implements the classification hook used by inthttp.ClassifyError.
+func (e *PartError) ErrorType() inthttp.ErrorType { return e.Type }
+
+func (e *PartError) Error() string {
+	kind := "transient"
+	if e.Permanent() {
+		kind = "permanent"
+	}
+	return fmt.Sprintf("part %d of %d failed (%s): %v", e.PartIndex+1, e.TotalParts, kind, e.Err)
+}
+
+func (e *PartError) Unwrap() error { return e.Err }
+
 // PreEncryptUploader extends CloudTransfer with pre-encrypted upload support.
 // Providers that support pre-encrypted uploads implement this interface.
 // This interface allows the transfer orchestrator to handle encryption
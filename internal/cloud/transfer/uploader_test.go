@@ -0,0 +1,44 @@
+// Package transfer provides unified upload and download orchestration.
+// This file contains tests for upload part errors.
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	inthttp "github.com/rescale/rescale-int/internal/http"
+)
+
+func TestNewPartError(t *testing.T) {
+	rejected := NewPartError(2, 8, errors.New("InvalidPart: 400 Bad Request"))
+	var partErr *PartError
+	if !errors.As(rejected, &partErr) || !partErr.Permanent() {
+		t.Fatalf("rejected part: %v, want permanent PartError", rejected)
+	}
+	if !strings.HasPrefix(rejected.Error(), "part 3 of 8 failed (permanent):") {
+		t.Errorf("Error() = %q", rejected.Error())
+	}
+
+	exhausted := NewPartError(499, 900, errors.New("operation failed after 10 attempts: connection reset by peer"))
+	if !errors.As(exhausted, &partErr) || partErr.Permanent() {
+		t.Fatalf("exhausted part: %v, want transient PartError", exhausted)
+	}
+
+	// The classification survives wrapping, even though "part 500" reads
+	// like an HTTP 500 to string matching.
+	wrapped := fmt.Errorf("upload of model.tar failed: %w", exhausted)
+	if got := inthttp.ClassifyError(wrapped); got != inthttp.ErrorTypeNetwork {
+		t.Errorf("ClassifyError(wrapped) = %s, want network", inthttp.ErrorTypeName(got))
+	}
+	if got := inthttp.ClassifyError(fmt.Errorf("x: %w", rejected)); got != inthttp.ErrorTypeFatal {
+		t.Errorf("ClassifyError(rejected) = %s, want fatal", inthttp.ErrorTypeName(got))
+	}
+
+	canceled := fmt.Errorf("%w: use of closed network connection", context.Canceled)
+	if err := NewPartError(0, 1, canceled); err != canceled {
+		t.Errorf("NewPartError wrapped a cancellation: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/api"
+)
+
+// DuplicatePolicy decides what happens when a file is uploaded into a folder
+// that already holds a file of the same name. Rescale accepts both, leaving
+// two files that can only be told apart by ID.
+type DuplicatePolicy string
+
+const (
+	DuplicateAllow     DuplicatePolicy = "allow"     // Upload anyway and keep both files
+	DuplicateSkip      DuplicatePolicy = "skip"      // Don't upload the file
+	DuplicateOverwrite DuplicatePolicy = "overwrite" // Upload, then delete the existing file(s)
+	DuplicateRename    DuplicatePolicy = "rename"    // Upload as "name (1).ext"
+	DuplicateVersion   DuplicatePolicy = "version"   // Upload as "name_v2.ext"
+)
+
+// DuplicatePolicies lists the policies in the order they are offered.
+var DuplicatePolicies = []DuplicatePolicy{DuplicateSkip, DuplicateOverwrite, DuplicateRename, DuplicateVersion, DuplicateAllow}
+
+// ParseDuplicatePolicy parses a policy name as accepted by --on-duplicate.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	p := DuplicatePolicy(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range DuplicatePolicies {
+		if p == known {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid duplicate policy %q (expected skip, overwrite, rename, version or allow)", s)
+}
+
+// DuplicateTarget is the outcome of applying a policy to one file.
+type DuplicateTarget struct {
+	Name       string   // Name to register the file under
+	Exists     bool     // A file of the original name was already in the folder
+	Skip       bool     // Don't upload
+	ReplaceIDs []string // Existing files to delete once the upload succeeds
+}
+
+// FolderNames indexes a remote folder's files by name. Names claimed by
+// earlier files of the same batch are included with no IDs, so two local files
+// with the same base name don't collide with each other either.
+type FolderNames map[string][]string
+
+// ListFolderNames lists a folder once and indexes its files by name. Callers
+// uploading many files into one folder share the result instead of checking
+// each file.
+func ListFolderNames(ctx context.Context, apiClient *api.Client, folderID string) (FolderNames, error) {
+	contents, err := apiClient.ListFolderContentsAll(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list destination folder: %w", err)
+	}
+	names := make(FolderNames, len(contents.Files))
+	for _, f := range contents.Files {
+		names[f.Name] = append(names[f.Name], f.ID)
+	}
+	return names, nil
+}
+
+// Taken reports whether name is used in the folder or by an earlier file of
+// the batch.
+func (n FolderNames) Taken(name string) bool {
+	_, ok := n[name]
+	return ok
+}
+
+// Resolve applies policy to a file named name and claims the resulting name.
+func (n FolderNames) Resolve(name string, policy DuplicatePolicy) DuplicateTarget {
+	t := DuplicateTarget{Name: name, Exists: n.Taken(name)}
+	if t.Exists {
+		switch policy {
+		case DuplicateSkip:
+			t.Skip = true
+			return t
+		case DuplicateOverwrite:
+			t.ReplaceIDs = n[name]
+		case DuplicateRename:
+			t.Name = n.renamed(name)
+		case DuplicateVersion:
+			t.Name = n.versioned(name)
+		}
+	}
+	if _, ok := n[t.Name]; !ok {
+		n[t.Name] = nil
+	}
+	return t
+}
+
+// splitExt splits name into stem and extension, keeping compound archive
+// extensions together ("run.tar.gz" -> "run", ".tar.gz").
+func splitExt(name string) (string, string) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if inner := filepath.Ext(stem); inner == ".tar" {
+		return strings.TrimSuffix(stem, inner), inner + ext
+	}
+	if stem == "" { // Dotfile such as ".env"
+		return name, ""
+	}
+	return stem, ext
+}
+
+// renamed returns the first free "stem (N)ext" name.
+func (n FolderNames) renamed(name string) string {
+	stem, ext := splitExt(name)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", stem, i, ext)
+		if !n.Taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+var versionSuffix = regexp.MustCompile(`_v(\d+)$`)
+
+// versioned returns "stem_vN.ext" with N one past the highest version of the
+// name in the folder; an unversioned file counts as version 1.
+func (n FolderNames) versioned(name string) string {
+	stem, ext := splitExt(name)
+	if m := versionSuffix.FindStringSubmatch(stem); m != nil {
+		stem = strings.TrimSuffix(stem, m[0])
+	}
+	next := 2
+	for existing := range n {
+		s, e := splitExt(existing)
+		if e != ext {
+			continue
+		}
+		m := versionSuffix.FindStringSubmatch(s)
+		if m == nil || strings.TrimSuffix(s, m[0]) != stem {
+			continue
+		}
+		if v, err := strconv.Atoi(m[1]); err == nil && v >= next {
+			next = v + 1
+		}
+	}
+	for {
+		candidate := fmt.Sprintf("%s_v%d%s", stem, next, ext)
+		if !n.Taken(candidate) {
+			return candidate
+		}
+		next++
+	}
+}
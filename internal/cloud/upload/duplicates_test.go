@@ -0,0 +1,66 @@
+package upload
+
+import "testing"
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	if p, err := ParseDuplicatePolicy(" Rename "); err != nil || p != DuplicateRename {
+		t.Errorf("ParseDuplicatePolicy(Rename) = %q, %v", p, err)
+	}
+	if _, err := ParseDuplicatePolicy("replace"); err == nil {
+		t.Error("ParseDuplicatePolicy accepted an unknown policy")
+	}
+}
+
+func TestFolderNamesResolve(t *testing.T) {
+	folder := func() FolderNames {
+		return FolderNames{
+			"data.csv":       {"f1", "f2"},
+			"data (1).csv":   {"f3"},
+			"run.tar.gz":     {"f4"},
+			"model_v3.inp":   {"f5"},
+			"model.inp":      {"f6"},
+			"model_v2.inp":   {"f7"},
+			"model_v9.other": {"f8"},
+		}
+	}
+
+	tests := []struct {
+		policy   DuplicatePolicy
+		name     string
+		wantName string
+		skip     bool
+		replace  int
+	}{
+		{DuplicateSkip, "data.csv", "data.csv", true, 0},
+		{DuplicateSkip, "new.csv", "new.csv", false, 0},
+		{DuplicateAllow, "data.csv", "data.csv", false, 0},
+		{DuplicateOverwrite, "data.csv", "data.csv", false, 2},
+		{DuplicateRename, "data.csv", "data (2).csv", false, 0},
+		{DuplicateRename, "run.tar.gz", "run (1).tar.gz", false, 0},
+		{DuplicateVersion, "model.inp", "model_v4.inp", false, 0},
+		{DuplicateVersion, "model_v2.inp", "model_v4.inp", false, 0},
+		{DuplicateVersion, "run.tar.gz", "run_v2.tar.gz", false, 0},
+	}
+	for _, tt := range tests {
+		got := folder().Resolve(tt.name, tt.policy)
+		if got.Name != tt.wantName || got.Skip != tt.skip || len(got.ReplaceIDs) != tt.replace {
+			t.Errorf("Resolve(%q, %s) = %+v, want name %q skip %t replacing %d", tt.name, tt.policy, got, tt.wantName, tt.skip, tt.replace)
+		}
+	}
+}
+
+func TestFolderNamesResolve_WithinBatch(t *testing.T) {
+	names := FolderNames{}
+	first := names.Resolve("out.log", DuplicateRename)
+	second := names.Resolve("out.log", DuplicateRename)
+	if first.Name != "out.log" || first.Exists {
+		t.Errorf("first = %+v", first)
+	}
+	if second.Name != "out (1).log" || !second.Exists {
+		t.Errorf("second = %+v, want renamed past the first file of the batch", second)
+	}
+	// Names claimed by the batch have nothing on the platform to delete yet
+	if third := names.Resolve("out.log", DuplicateOverwrite); len(third.ReplaceIDs) != 0 {
+		t.Errorf("third = %+v", third)
+	}
+}
@@ -2,12 +2,20 @@
 package upload
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/rescale/rescale-int/internal/cloud/history"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/crypto"
+	"github.com/rescale/rescale-int/internal/models"
 )
 
 // GenerateEncryptionParams generates new encryption key, IV, and random suffix.
@@ -62,3 +70,56 @@ func CreateEncryptedTempFile(localPath string) (string, error) {
 
 	return encryptedPath, nil
 }
+
+// hashFile returns the hex SHA-512 of the file at path and, if withSHA256,
+// its SHA-256, reading it once.
+func hashFile(path string, withSHA256 bool) (sha512Hex, sha256Hex string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	h512 := sha512.New()
+	var w io.Writer = h512
+	h256 := sha256.New()
+	if withSHA256 {
+		w = io.MultiWriter(h512, h256)
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return "", "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	sha512Hex = hex.EncodeToString(h512.Sum(nil))
+	if withSHA256 {
+		sha256Hex = hex.EncodeToString(h256.Sum(nil))
+	}
+	return sha512Hex, sha256Hex, nil
+}
+
+// recordHistory appends a registered upload to the platform's upload history
+// for later integrity audits. Failures are logged, not returned: the upload
+// itself succeeded.
+func recordHistory(params UploadParams, file *models.CloudFile, size int64, sha512 string, storageType string, transfer time.Duration, blocks []history.Block) {
+	cfg := params.APIClient.GetConfig()
+	if cfg == nil {
+		return
+	}
+	localPath := params.LocalPath
+	if abs, err := filepath.Abs(localPath); err == nil {
+		localPath = abs
+	}
+	err := history.Append(config.GetUploadHistoryPath(cfg.APIBaseURL), history.Entry{
+		Time:        time.Now().UTC(),
+		FileID:      file.ID,
+		Name:        file.Name,
+		LocalPath:   localPath,
+		Size:        size,
+		SHA512:      sha512,
+		StorageType: storageType,
+		TransferMs:  transfer.Milliseconds(),
+		Blocks:      blocks,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to record upload history for %s: %v", filepath.Base(params.LocalPath), err)
+	}
+}
@@ -0,0 +1,35 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	data := []byte("integrity check")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	want512, want256 := sha512.Sum512(data), sha256.Sum256(data)
+
+	got512, got256, err := hashFile(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got512 != hex.EncodeToString(want512[:]) || got256 != hex.EncodeToString(want256[:]) {
+		t.Errorf("hashFile = %s, %s", got512, got256)
+	}
+
+	got512, got256, err = hashFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got512 != hex.EncodeToString(want512[:]) || got256 != "" {
+		t.Errorf("hashFile without SHA-256 = %s, %q", got512, got256)
+	}
+}
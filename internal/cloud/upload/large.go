@@ -0,0 +1,149 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/history"
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// quotaTimeout bounds the quota lookup so a slow platform doesn't hold up the
+// confirmation; the quota line is then left out.
+const quotaTimeout = 15 * time.Second
+
+// historyWindow is how far back upload history is read to measure throughput.
+const historyWindow = 30 * 24 * time.Hour
+
+// LargeUpload describes an upload batch over the configured confirmation
+// threshold, for the CLI prompt and the GUI dialog.
+type LargeUpload struct {
+	Files          int
+	TotalBytes     int64
+	ThresholdBytes int64
+	BytesPerSec    float64       // Measured from upload history; 0 when unknown
+	Estimate       time.Duration // TotalBytes at BytesPerSec; 0 when unknown
+	QuotaRemaining int64         // Valid when QuotaKnown
+	QuotaKnown     bool          // False for unlimited workspaces or platforms without quotas
+}
+
+// ExceedsQuota reports whether the batch is larger than the workspace's
+// remaining storage.
+func (l *LargeUpload) ExceedsQuota() bool {
+	return l.QuotaKnown && l.TotalBytes > l.QuotaRemaining
+}
+
+// Lines returns the summary shown before asking for confirmation.
+func (l *LargeUpload) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Total size:         %s in %d file(s) (confirmation threshold %s)",
+			cloud.FormatBytes(l.TotalBytes), l.Files, cloud.FormatBytes(l.ThresholdBytes)),
+	}
+	if l.Estimate > 0 {
+		lines = append(lines, fmt.Sprintf("Estimated duration: %s at %s (measured from recent uploads)",
+			FormatEstimate(l.Estimate), cloud.FormatSpeed(l.BytesPerSec)))
+	} else {
+		lines = append(lines, "Estimated duration: unknown (no recent uploads to measure)")
+	}
+	if l.QuotaKnown {
+		line := fmt.Sprintf("Workspace storage:  %s remaining", cloud.FormatBytes(l.QuotaRemaining))
+		if l.ExceedsQuota() {
+			line += " - this upload does not fit"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// LargeUploadThreshold returns the total size above which uploads need
+// confirmation, or 0 when the check is turned off.
+func LargeUploadThreshold(cfg *config.Config) int64 {
+	if cfg == nil || cfg.LargeUploadConfirmGB <= 0 {
+		return 0
+	}
+	return int64(cfg.LargeUploadConfirmGB) << 30
+}
+
+// CheckLargeUpload returns a summary of a batch of totalBytes in files files
+// when it exceeds the configured threshold, or nil when no confirmation is
+// needed. The duration estimate comes from the platform's upload history and
+// the quota from the API; either is left out when unavailable.
+func CheckLargeUpload(ctx context.Context, apiClient *api.Client, totalBytes int64, files int) *LargeUpload {
+	cfg := apiClient.GetConfig()
+	threshold := LargeUploadThreshold(cfg)
+	if threshold == 0 || totalBytes <= threshold {
+		return nil
+	}
+	l := &LargeUpload{Files: files, TotalBytes: totalBytes, ThresholdBytes: threshold}
+
+	entries, err := history.Load(config.GetUploadHistoryPath(cfg.APIBaseURL), time.Now().Add(-historyWindow))
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if rate, ok := history.Throughput(entries); ok {
+		l.BytesPerSec = rate
+		l.Estimate = time.Duration(float64(totalBytes) / rate * float64(time.Second))
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, quotaTimeout)
+	defer cancel()
+	quota, err := apiClient.GetStorageQuota(qctx)
+	switch {
+	case err == nil:
+		l.QuotaRemaining, l.QuotaKnown = quota.Remaining()
+	case !errors.Is(err, api.ErrStorageQuotaUnsupported):
+		log.Printf("Warning: failed to get storage quota: %v", err)
+	}
+	return l
+}
+
+// LocalSize returns the total size and number of regular files under paths,
+// walking directories. Symlinks are not followed.
+func LocalSize(paths []string) (int64, int, error) {
+	var total int64
+	files := 0
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+			files++
+			return nil
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to size %s: %w", root, err)
+		}
+	}
+	return total, files, nil
+}
+
+// FormatEstimate renders a duration estimate to the nearest minute, e.g.
+// "3h 20m" or "under a minute".
+func FormatEstimate(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
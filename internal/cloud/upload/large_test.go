@@ -0,0 +1,84 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/history"
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+func TestCheckLargeUpload(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]int64{"quotaBytes": 3 << 30, "usedBytes": 1 << 30})
+	}))
+	defer server.Close()
+	cfg := &config.Config{APIBaseURL: server.URL, APIKey: "k", ProxyMode: "no-proxy", LargeUploadConfirmGB: 1}
+	client := api.NewClientForTest(cfg)
+
+	if l := CheckLargeUpload(context.Background(), client, 1<<30, 1); l != nil {
+		t.Errorf("upload at the threshold needs confirmation: %+v", l)
+	}
+
+	// 100 MiB/s measured from earlier uploads
+	err := history.Append(config.GetUploadHistoryPath(cfg.APIBaseURL), history.Entry{
+		Time: time.Now(), FileID: "f1", Size: 1000 << 20, TransferMs: 10000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := CheckLargeUpload(context.Background(), client, 3<<30, 4)
+	if l == nil {
+		t.Fatal("upload over the threshold needs no confirmation")
+	}
+	if l.Estimate != 30720*time.Millisecond || l.QuotaRemaining != 2<<30 || !l.ExceedsQuota() {
+		t.Errorf("CheckLargeUpload = %+v", l)
+	}
+	if lines := strings.Join(l.Lines(), "\n"); !strings.Contains(lines, "does not fit") {
+		t.Errorf("Lines() = %s", lines)
+	}
+
+	cfg.LargeUploadConfirmGB = 0
+	if l := CheckLargeUpload(context.Background(), client, 1<<40, 1); l != nil {
+		t.Error("disabled check still asked for confirmation")
+	}
+}
+
+func TestLocalSize(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 5), 0644)
+	single := filepath.Join(t.TempDir(), "c")
+	os.WriteFile(single, make([]byte, 7), 0644)
+
+	total, files, err := LocalSize([]string{dir, single})
+	if err != nil || total != 22 || files != 3 {
+		t.Errorf("LocalSize = %d, %d, %v, want 22, 3", total, files, err)
+	}
+	if _, _, err := LocalSize([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("LocalSize of a missing path succeeded")
+	}
+}
+
+func TestFormatEstimate(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		20 * time.Second:           "under a minute",
+		45 * time.Minute:           "45m",
+		200 * time.Minute:          "3h 20m",
+		75*time.Hour + time.Minute: "3d 3h",
+	} {
+		if got := FormatEstimate(d); got != want {
+			t.Errorf("FormatEstimate(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
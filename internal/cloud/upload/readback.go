@@ -0,0 +1,251 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/history"
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// rangeReader is the part of transfer.StreamingPartDownloader that read-back
+// verification needs.
+type rangeReader interface {
+	GetEncryptedSize(ctx context.Context, remotePath string) (int64, error)
+	DownloadEncryptedRange(ctx context.Context, remotePath string, offset, length int64, progressCallback func(int64)) ([]byte, error)
+}
+
+// readbackLedger retains SHA-256 hashes of fixed-size ciphertext blocks as
+// parts are encrypted, so ranges of the stored object can be checked after
+// completion without keeping the ciphertext itself (32 bytes per block).
+// Parts may be added in any order and concurrently.
+type readbackLedger struct {
+	mu        sync.Mutex
+	blockSize int64
+	parts     map[int64]ledgerPart
+}
+
+type ledgerPart struct {
+	size   int64
+	blocks [][sha256.Size]byte
+}
+
+// ledgerBlock is one hashed block at its offset in the stored object.
+type ledgerBlock struct {
+	offset int64
+	length int64
+	hash   [sha256.Size]byte
+}
+
+func newReadbackLedger(blockSize int64) *readbackLedger {
+	return &readbackLedger{blockSize: blockSize, parts: make(map[int64]ledgerPart)}
+}
+
+// addPart records the block hashes of one part's ciphertext.
+func (l *readbackLedger) addPart(partIndex int64, ciphertext []byte) {
+	part := ledgerPart{size: int64(len(ciphertext))}
+	for off := int64(0); off < part.size; off += l.blockSize {
+		end := min(off+l.blockSize, part.size)
+		part.blocks = append(part.blocks, sha256.Sum256(ciphertext[off:end]))
+	}
+
+	l.mu.Lock()
+	l.parts[partIndex] = part
+	l.mu.Unlock()
+}
+
+// addUnhashedPart records the size of a part whose ciphertext is not known,
+// e.g. uploaded before a resume. Its blocks are never sampled.
+func (l *readbackLedger) addUnhashedPart(partIndex, size int64) {
+	l.mu.Lock()
+	l.parts[partIndex] = ledgerPart{size: size}
+	l.mu.Unlock()
+}
+
+// addFile records the block hashes of an encrypted file, one part per block.
+func (l *readbackLedger) addFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open encrypted file: %w", err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, l.blockSize)
+	for i := int64(0); ; i++ {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			l.addPart(i, buf[:n])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read encrypted file: %w", err)
+		}
+	}
+}
+
+// addFileSample records the hashes of the final block of an encrypted file
+// and up to n-1 others chosen at random, reading only those blocks. The
+// rest of the file is recorded as unhashed parts.
+func (l *readbackLedger) addFileSample(path string, n int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open encrypted file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat encrypted file: %w", err)
+	}
+
+	size := info.Size()
+	count := int((size + l.blockSize - 1) / l.blockSize)
+	buf := make([]byte, l.blockSize)
+	var part, next int64 // next part index; offset of the first unrecorded byte
+	for _, i := range pickBlocks(count, n) {
+		offset := int64(i) * l.blockSize
+		if offset > next {
+			l.addUnhashedPart(part, offset-next)
+			part++
+		}
+		length := min(l.blockSize, size-offset)
+		if _, err := f.ReadAt(buf[:length], offset); err != nil {
+			return fmt.Errorf("failed to read encrypted file: %w", err)
+		}
+		l.addPart(part, buf[:length])
+		part++
+		next = offset + length
+	}
+	return nil
+}
+
+// sample returns the final hashed block and up to n-1 others chosen at
+// random, in offset order, for the upload history. Nil l yields none.
+func (l *readbackLedger) sample(n int) []history.Block {
+	if l == nil {
+		return nil
+	}
+	blocks, _ := l.layout()
+	var out []history.Block
+	for _, i := range pickBlocks(len(blocks), n) {
+		b := blocks[i]
+		out = append(out, history.Block{Offset: b.offset, Length: b.length, SHA256: hex.EncodeToString(b.hash[:])})
+	}
+	return out
+}
+
+// pickBlocks returns the index of the last of count blocks and up to n-1
+// other indexes chosen at random, in ascending order.
+func pickBlocks(count, n int) []int {
+	if count == 0 || n <= 0 {
+		return nil
+	}
+	picked := map[int]bool{count - 1: true}
+	for len(picked) < min(n, count) {
+		picked[rand.IntN(count)] = true
+	}
+	indexes := make([]int, 0, len(picked))
+	for i := range picked {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// layout returns every block with its object offset, in order, and the
+// total ciphertext size.
+func (l *readbackLedger) layout() ([]ledgerBlock, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	indexes := make([]int64, 0, len(l.parts))
+	for idx := range l.parts {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	var blocks []ledgerBlock
+	var offset int64
+	for _, idx := range indexes {
+		part := l.parts[idx]
+		for i, h := range part.blocks {
+			start := int64(i) * l.blockSize
+			blocks = append(blocks, ledgerBlock{
+				offset: offset + start,
+				length: min(l.blockSize, part.size-start),
+				hash:   h,
+			})
+		}
+		offset += part.size
+	}
+	return blocks, offset
+}
+
+// verify checks the stored object at remotePath against the ledger: its
+// size, its final block, and up to samples other blocks chosen at random.
+// It returns the number of blocks read back.
+func (l *readbackLedger) verify(ctx context.Context, reader rangeReader, remotePath string, samples int) (int, error) {
+	blocks, total := l.layout()
+
+	size, err := reader.GetEncryptedSize(ctx, remotePath)
+	if err != nil {
+		return 0, fmt.Errorf("read-back verification: %w", err)
+	}
+	if size != total {
+		return 0, fmt.Errorf("read-back verification: stored object is %d bytes, uploaded %d", size, total)
+	}
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+
+	last := len(blocks) - 1
+	check := []int{last}
+	for _, i := range rand.Perm(last) {
+		if len(check) > samples {
+			break
+		}
+		check = append(check, i)
+	}
+
+	for _, i := range check {
+		b := blocks[i]
+		data, err := reader.DownloadEncryptedRange(ctx, remotePath, b.offset, b.length, nil)
+		if err != nil {
+			return 0, fmt.Errorf("read-back verification: %w", err)
+		}
+		if int64(len(data)) != b.length || sha256.Sum256(data) != b.hash {
+			return 0, fmt.Errorf("read-back verification: bytes %d-%d of the stored object do not match what was uploaded",
+				b.offset, b.offset+b.length-1)
+		}
+	}
+	return len(check), nil
+}
+
+// readbackVerify checks a completed upload against ledger when
+// params.VerifyReadback is set. The provider must support ranged reads.
+func readbackVerify(ctx context.Context, provider cloud.CloudTransfer, params UploadParams, ledger *readbackLedger, remotePath string) error {
+	if !params.VerifyReadback || ledger == nil {
+		return nil
+	}
+	reader, ok := provider.(rangeReader)
+	if !ok {
+		return fmt.Errorf("read-back verification: provider does not support ranged reads")
+	}
+
+	timer := cloud.StartTimer(params.OutputWriter, "Read-back verification")
+	checked, err := ledger.verify(ctx, reader, remotePath, constants.ReadbackSampleBlocks)
+	if err != nil {
+		return err
+	}
+	timer.StopWithMessage("blocks=%d", checked)
+	return nil
+}
@@ -0,0 +1,158 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/cloud/providers/memory"
+)
+
+// bytesReader serves ranges of a fixed ciphertext.
+type bytesReader struct {
+	data  []byte
+	reads int
+}
+
+func (b *bytesReader) GetEncryptedSize(_ context.Context, _ string) (int64, error) {
+	return int64(len(b.data)), nil
+}
+
+func (b *bytesReader) DownloadEncryptedRange(_ context.Context, _ string, offset, length int64, _ func(int64)) ([]byte, error) {
+	b.reads++
+	return append([]byte(nil), b.data[offset:min(offset+length, int64(len(b.data)))]...), nil
+}
+
+func TestReadbackLedgerVerify(t *testing.T) {
+	parts := [][]byte{[]byte("0123456789"), []byte("abcdefghij"), []byte("XYZ")}
+
+	ledger := newReadbackLedger(4)
+	// Parts complete out of order
+	ledger.addPart(2, parts[2])
+	ledger.addPart(0, parts[0])
+	ledger.addPart(1, parts[1])
+
+	stored := []byte("0123456789abcdefghijXYZ")
+	reader := &bytesReader{data: stored}
+
+	// 3 blocks per 10-byte part plus one for the final part; check them all
+	checked, err := ledger.verify(context.Background(), reader, "obj", 10)
+	if err != nil {
+		t.Fatalf("verify failed on matching object: %v", err)
+	}
+	if checked != 7 || reader.reads != 7 {
+		t.Errorf("checked %d blocks with %d reads, want 7", checked, reader.reads)
+	}
+
+	// Only the final block plus one sample
+	reader.reads = 0
+	if checked, err := ledger.verify(context.Background(), reader, "obj", 1); err != nil || checked != 2 {
+		t.Errorf("verify(samples=1) = %d, %v; want 2, nil", checked, err)
+	}
+
+	corrupt := append([]byte(nil), stored...)
+	corrupt[13] ^= 0xff // part 1 starts at 10; its first block is [10,14)
+	_, err = ledger.verify(context.Background(), &bytesReader{data: corrupt}, "obj", 10)
+	if err == nil || !strings.Contains(err.Error(), "bytes 10-13") {
+		t.Errorf("expected mismatch at bytes 10-13, got %v", err)
+	}
+
+	_, err = ledger.verify(context.Background(), &bytesReader{data: stored[:20]}, "obj", 10)
+	if err == nil || !strings.Contains(err.Error(), "stored object is 20 bytes, uploaded 23") {
+		t.Errorf("expected size mismatch, got %v", err)
+	}
+}
+
+func TestReadbackLedgerAddFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.encrypted")
+	data := []byte("0123456789abcdefghijXYZ")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := newReadbackLedger(8)
+	if err := ledger.addFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ledger.verify(context.Background(), &bytesReader{data: data}, "obj", 10); err != nil {
+		t.Errorf("verify failed: %v", err)
+	}
+}
+
+// tamperingProvider returns a flipped byte from every ranged read, as if the
+// stored object differed from what was uploaded.
+type tamperingProvider struct {
+	*memory.Provider
+}
+
+func (p tamperingProvider) DownloadEncryptedRange(ctx context.Context, remotePath string, offset, length int64, progressCallback func(int64)) ([]byte, error) {
+	data, err := p.Provider.DownloadEncryptedRange(ctx, remotePath, offset, length, progressCallback)
+	if err == nil && len(data) > 0 {
+		data[0] ^= 0xff
+	}
+	return data, err
+}
+
+func TestUploadStreamingVerifyReadback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "critical.dat")
+	data := make([]byte, 200*1024) // 4 parts of 64KB
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	params := UploadParams{LocalPath: path, VerifyReadback: true}
+
+	if _, err := uploadStreaming(context.Background(), memory.NewProvider(0), params, int64(len(data))); err != nil {
+		t.Fatalf("uploadStreaming with read-back failed: %v", err)
+	}
+
+	_, err := uploadStreaming(context.Background(), tamperingProvider{memory.NewProvider(0)}, params, int64(len(data)))
+	if err == nil || !strings.Contains(err.Error(), "read-back verification") {
+		t.Errorf("expected read-back verification failure, got %v", err)
+	}
+}
+
+func TestReadbackLedgerSample(t *testing.T) {
+	stored := []byte("0123456789abcdefghijXYZ") // 6 blocks of 4 bytes
+	path := filepath.Join(t.TempDir(), "enc")
+	if err := os.WriteFile(path, stored, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	full := newReadbackLedger(4)
+	full.addPart(0, stored)
+	sampled := newReadbackLedger(4)
+	if err := sampled.addFileSample(path, 3); err != nil {
+		t.Fatalf("addFileSample: %v", err)
+	}
+
+	for name, ledger := range map[string]*readbackLedger{"full": full, "file sample": sampled} {
+		blocks := ledger.sample(3)
+		if len(blocks) != 3 {
+			t.Fatalf("%s: sample(3) = %d blocks, want 3", name, len(blocks))
+		}
+		if last := blocks[len(blocks)-1]; last.Offset != 20 || last.Length != 3 {
+			t.Errorf("%s: last block = %+v, want the final 3 bytes", name, last)
+		}
+		for _, b := range blocks {
+			sum := sha256.Sum256(stored[b.Offset : b.Offset+b.Length])
+			if b.SHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("%s: block at %d has the wrong hash", name, b.Offset)
+			}
+		}
+	}
+
+	// The sampled ledger spans the whole file, so read-back sees its size
+	if _, err := sampled.verify(context.Background(), &bytesReader{data: stored}, "obj", 10); err != nil {
+		t.Errorf("verify of sampled ledger: %v", err)
+	}
+	if got := (*readbackLedger)(nil).sample(3); got != nil {
+		t.Errorf("nil ledger sample = %v, want nil", got)
+	}
+}
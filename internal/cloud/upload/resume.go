@@ -0,0 +1,220 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/cloud/state"
+	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/crypto"
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+// cbcBlockSize is the AES block size: the last ciphertext block of a part
+// is the IV the next part is chained from.
+const cbcBlockSize = 16
+
+var errCorruptResumeState = errors.New("resume state is corrupt")
+
+// streamingResume persists the progress of one streaming upload in
+// UploadParams.ResumeDir (see state.MultipartUploadState), so a later upload
+// of the same file can pick up after the parts already uploaded. A nil
+// *streamingResume persists nothing.
+type streamingResume struct {
+	path string
+
+	mu    sync.Mutex
+	state *state.MultipartUploadState
+}
+
+// newStreamingResume returns the resume store for localPath in dir, or nil
+// when dir is empty.
+func newStreamingResume(dir, localPath string) *streamingResume {
+	if dir == "" {
+		return nil
+	}
+	return &streamingResume{path: state.MultipartStatePath(dir, localPath)}
+}
+
+// resume returns the upload to continue and the parts of it already
+// uploaded (an unbroken run from part 0), or a nil upload when there is
+// nothing usable to resume. State that can't be used is deleted and its
+// upload aborted. While another running process is uploading the same file,
+// nil is returned and r stops persisting, leaving that upload alone.
+func (r *streamingResume) resume(ctx context.Context, uploader transfer.StreamingConcurrentUploader, storageType string, params UploadParams, fileSize int64) (*transfer.StreamingUpload, []state.MultipartPart) {
+	if r == nil {
+		return nil, nil
+	}
+	fileName := filepath.Base(params.LocalPath)
+	saved, err := state.LoadMultipartState(r.path)
+	if err != nil {
+		logging.Printf(ctx, "[WARN] %s: ignoring unreadable upload resume state: %v", fileName, err)
+		state.DeleteMultipartState(r.path)
+		return nil, nil
+	}
+	if saved == nil {
+		return nil, nil
+	}
+	if saved.InUseByOtherProcess() {
+		logging.Printf(ctx, "[WARN] %s: another process (PID %d) is uploading this file, not resuming", fileName, saved.ProcessID)
+		r.path = ""
+		return nil, nil
+	}
+
+	key, keyErr := encryption.DecodeBase64(saved.MasterKey)
+	initialIV, ivErr := encryption.DecodeBase64(saved.InitialIV)
+	prefix := saved.CompletedPrefix()
+	currentIV := initialIV
+	var endErr error
+	if len(prefix) > 0 {
+		currentIV, endErr = encryption.DecodeBase64(prefix[len(prefix)-1].EndIV)
+	}
+
+	reason := saved.Check(params.LocalPath, storageType)
+	if reason == nil && (keyErr != nil || ivErr != nil || endErr != nil) {
+		reason = errCorruptResumeState
+	}
+	if reason == nil {
+		exists, err := uploader.ValidateStreamingUploadExists(ctx, saved.UploadID, saved.StoragePath)
+		if err != nil {
+			logging.Printf(ctx, "[WARN] %s: cannot check the upload to resume, starting over: %v", fileName, err)
+			return nil, nil
+		}
+		if !exists {
+			logging.Printf(ctx, "[INFO] %s: interrupted upload no longer exists in storage, starting over", fileName)
+			state.DeleteMultipartState(r.path)
+			return nil, nil
+		}
+	}
+
+	resumeParams := transfer.StreamingUploadResumeParams{
+		LocalPath:    params.LocalPath,
+		FileSize:     fileSize,
+		StoragePath:  saved.StoragePath,
+		UploadID:     saved.UploadID,
+		MasterKey:    key,
+		InitialIV:    initialIV,
+		CurrentIV:    currentIV,
+		PartSize:     saved.PartSize,
+		RandomSuffix: saved.RandomSuffix,
+		OutputWriter: params.OutputWriter,
+	}
+	if reason != nil || len(prefix) == 0 {
+		if reason != nil {
+			logging.Printf(ctx, "[INFO] %s: not resuming interrupted upload: %v", fileName, reason)
+		}
+		r.discard(ctx, uploader, resumeParams)
+		return nil, nil
+	}
+
+	upload, err := uploader.InitStreamingUploadFromState(ctx, resumeParams)
+	if err != nil {
+		logging.Printf(ctx, "[WARN] %s: cannot resume interrupted upload, starting over: %v", fileName, err)
+		r.discard(ctx, uploader, resumeParams)
+		return nil, nil
+	}
+
+	var done int64
+	for _, p := range prefix {
+		done += p.Size
+	}
+	saved.Parts = prefix
+	saved.ProcessID = os.Getpid()
+	r.state = saved
+	r.save(ctx)
+	logging.Printf(ctx, "[INFO] %s: resuming interrupted upload after %d of %d parts (%d bytes already uploaded)",
+		fileName, len(prefix), upload.TotalParts, done)
+	return upload, prefix
+}
+
+// partResult converts a saved part back to the result of its upload.
+func partResult(p state.MultipartPart) *transfer.PartResult {
+	return &transfer.PartResult{PartIndex: p.Index, PartNumber: p.PartNumber, ETag: p.ETag, Size: p.Size}
+}
+
+// discard aborts the saved upload, if it can still be reached, and deletes
+// its state.
+func (r *streamingResume) discard(ctx context.Context, uploader transfer.StreamingConcurrentUploader, params transfer.StreamingUploadResumeParams) {
+	if params.InitialIV != nil && params.CurrentIV != nil {
+		if upload, err := uploader.InitStreamingUploadFromState(ctx, params); err == nil {
+			abortStreaming(ctx, uploader, upload)
+		}
+	}
+	state.DeleteMultipartState(r.path)
+}
+
+// begin starts persisting a newly initialized upload.
+func (r *streamingResume) begin(ctx context.Context, upload *transfer.StreamingUpload, storageType string) {
+	if r == nil || r.path == "" {
+		return
+	}
+	info, err := os.Stat(upload.LocalPath)
+	if err != nil {
+		return
+	}
+	abs, _ := filepath.Abs(upload.LocalPath)
+	r.mu.Lock()
+	r.state = &state.MultipartUploadState{
+		LocalPath:    abs,
+		FileSize:     info.Size(),
+		ModTime:      info.ModTime(),
+		StorageType:  storageType,
+		StoragePath:  upload.StoragePath,
+		UploadID:     upload.UploadID,
+		MasterKey:    encryption.EncodeBase64(upload.MasterKey),
+		InitialIV:    encryption.EncodeBase64(upload.InitialIV),
+		PartSize:     upload.PartSize,
+		RandomSuffix: upload.RandomSuffix,
+		ProcessID:    os.Getpid(),
+		CreatedAt:    time.Now(),
+	}
+	r.mu.Unlock()
+	r.save(ctx)
+}
+
+// partDone records an uploaded part, with the size and last block of its
+// ciphertext.
+func (r *streamingResume) partDone(ctx context.Context, part *transfer.PartResult, cipherSize int64, endIV []byte) {
+	if !r.keeps() || len(endIV) != cbcBlockSize {
+		return
+	}
+	r.mu.Lock()
+	r.state.Parts = append(r.state.Parts, state.MultipartPart{
+		Index:      part.PartIndex,
+		PartNumber: part.PartNumber,
+		ETag:       part.ETag,
+		Size:       part.Size,
+		CipherSize: cipherSize,
+		EndIV:      encryption.EncodeBase64(endIV),
+	})
+	r.mu.Unlock()
+	r.save(ctx)
+}
+
+// save writes the state. A failure only costs the ability to resume, so it
+// is logged, not returned.
+func (r *streamingResume) save(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := state.SaveMultipartState(r.path, r.state); err != nil {
+		logging.Printf(ctx, "[WARN] %s: %v", filepath.Base(r.state.LocalPath), err)
+	}
+}
+
+// keeps reports whether an interrupted upload is left in storage for a
+// later resume instead of being aborted.
+func (r *streamingResume) keeps() bool {
+	return r != nil && r.path != "" && r.state != nil
+}
+
+// finish deletes the state of a completed or abandoned upload.
+func (r *streamingResume) finish() {
+	if r == nil || r.path == "" {
+		return
+	}
+	state.DeleteMultipartState(r.path)
+}
@@ -0,0 +1,176 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/cloud/providers/memory"
+	"github.com/rescale/rescale-int/internal/cloud/state"
+	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/crypto" // package name is 'encryption'
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// droppingProvider fails the upload of every part from failFrom on with a
+// transient error, once the parts before it have been uploaded, as if the
+// connection dropped mid-upload.
+type droppingProvider struct {
+	*memory.Provider
+	failFrom int64
+
+	mu       sync.Mutex
+	uploaded int64
+	before   chan struct{}
+}
+
+func (p *droppingProvider) UploadCiphertext(ctx context.Context, upload *transfer.StreamingUpload, partIndex int64, ciphertext []byte) (*transfer.PartResult, error) {
+	if partIndex >= p.failFrom {
+		<-p.before
+		return nil, errors.New("connection reset by peer")
+	}
+	result, err := p.Provider.UploadCiphertext(ctx, upload, partIndex, ciphertext)
+	if err == nil {
+		p.mu.Lock()
+		if p.uploaded++; p.uploaded == p.failFrom {
+			close(p.before)
+		}
+		p.mu.Unlock()
+	}
+	return result, err
+}
+
+func TestUploadStreamingResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "large.dat")
+	data := make([]byte, 200*1024) // 4 parts of 64KB
+	for i := range data {
+		data[i] = byte(i * 13)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	resumeDir := t.TempDir()
+	statePath := state.MultipartStatePath(resumeDir, path)
+	params := UploadParams{LocalPath: path, ResumeDir: resumeDir, VerifyReadback: true}
+	store := memory.NewProvider(0)
+
+	dropping := &droppingProvider{Provider: store, failFrom: 2, before: make(chan struct{})}
+	if _, err := uploadStreaming(context.Background(), dropping, params, int64(len(data))); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+	saved, err := state.LoadMultipartState(statePath)
+	if err != nil || saved == nil {
+		t.Fatalf("resume state not kept: %v", err)
+	}
+	if n := len(saved.CompletedPrefix()); n != 2 {
+		t.Fatalf("resume state has %d completed parts, want 2", n)
+	}
+
+	// The retry uploads only the remaining parts into the same upload
+	counting := &countingProvider{Provider: store}
+	result, err := uploadStreaming(context.Background(), counting, params, int64(len(data)))
+	if err != nil {
+		t.Fatalf("resumed upload failed: %v", err)
+	}
+	if result.StoragePath != saved.StoragePath {
+		t.Errorf("resumed upload stored at %s, want %s", result.StoragePath, saved.StoragePath)
+	}
+	if got := counting.parts(); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("resumed upload sent parts %v, want [2 3]", got)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("resume state not deleted after success: %v", err)
+	}
+
+	got := downloadResult(t, store, result, int64(len(data)))
+	if string(got) != string(data) {
+		t.Error("resumed upload does not decrypt to the source file")
+	}
+	// The parts sent before the interruption are part of the recorded hash
+	if want := sha256.Sum256(data); result.PlaintextSHA256 != hex.EncodeToString(want[:]) {
+		t.Errorf("PlaintextSHA256 = %s, want the SHA-256 of the whole file", result.PlaintextSHA256)
+	}
+}
+
+func TestUploadStreamingResumeChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "large.dat")
+	data := make([]byte, 200*1024)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	resumeDir := t.TempDir()
+	params := UploadParams{LocalPath: path, ResumeDir: resumeDir}
+	store := memory.NewProvider(0)
+
+	dropping := &droppingProvider{Provider: store, failFrom: 2, before: make(chan struct{})}
+	if _, err := uploadStreaming(context.Background(), dropping, params, int64(len(data))); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+
+	// A changed source starts over and abandons the interrupted upload
+	data[0] = 1
+	if err := os.WriteFile(path, append(data, 'x'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	counting := &countingProvider{Provider: store}
+	if _, err := uploadStreaming(context.Background(), counting, params, int64(len(data)+1)); err != nil {
+		t.Fatalf("upload of changed file failed: %v", err)
+	}
+	if got := counting.parts(); len(got) != 4 {
+		t.Errorf("changed file uploaded parts %v, want all 4", got)
+	}
+}
+
+// countingProvider records which parts are uploaded.
+type countingProvider struct {
+	*memory.Provider
+
+	mu      sync.Mutex
+	indexes []int64
+}
+
+func (p *countingProvider) UploadCiphertext(ctx context.Context, upload *transfer.StreamingUpload, partIndex int64, ciphertext []byte) (*transfer.PartResult, error) {
+	p.mu.Lock()
+	p.indexes = append(p.indexes, partIndex)
+	p.mu.Unlock()
+	return p.Provider.UploadCiphertext(ctx, upload, partIndex, ciphertext)
+}
+
+func (p *countingProvider) parts() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sorted := append([]int64(nil), p.indexes...)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// downloadResult downloads and decrypts an uploaded file.
+func downloadResult(t *testing.T, p *memory.Provider, result *cloud.UploadResult, size int64) []byte {
+	t.Helper()
+	localPath := filepath.Join(t.TempDir(), "downloaded.dat")
+	_, err := transfer.NewDownloader(p).Download(context.Background(), cloud.DownloadParams{
+		RemotePath: result.StoragePath,
+		LocalPath:  localPath,
+		FileInfo: &models.CloudFile{
+			Path:                 result.StoragePath,
+			EncodedEncryptionKey: encryption.EncodeBase64(result.EncryptionKey),
+			IV:                   encryption.EncodeBase64(result.IV),
+			DecryptedSize:        size,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
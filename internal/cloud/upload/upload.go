@@ -2,10 +2,13 @@
 package upload
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,8 +24,15 @@ import (
 	"github.com/rescale/rescale-int/internal/cloud/transfer"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/crypto"
+	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/tracing"
 	internaltransfer "github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // UploadParams consolidates all parameters for upload operations.
@@ -51,6 +61,40 @@ type UploadParams struct {
 	// false (default) = streaming encryption (no temp file, saves disk space)
 	// true = pre-encryption (creates temp file, compatible with legacy clients)
 	PreEncrypt bool
+
+	// Optional: Leave the upload out of the upload history used by
+	// `history verify` (e.g. network test probes that are deleted afterwards)
+	SkipHistory bool
+
+	// Optional: After the upload completes, read back the final ciphertext
+	// block and a few random ones and compare them with hashes kept while
+	// encrypting. Also enabled by the upload_readback_verify config setting.
+	VerifyReadback bool
+
+	// Optional: Name to register the file under on Rescale (default: the base
+	// name of LocalPath). Set when a duplicate-name policy renames the upload.
+	RemoteName string
+
+	// Optional: Hold off reading the next part while a blackout window is
+	// active (see resources.WaitForBlackout). Parts already read finish
+	// uploading. Used by PUR runs; interactive uploads are not held.
+	PauseInBlackout bool
+
+	// Optional: Hash the file again after the upload and fail with a
+	// cloud.IntegrityError, without registering it, if it doesn't match the
+	// SHA-256 computed while it was encrypted (the file changed mid-upload).
+	VerifyIntegrity bool
+
+	// Optional: Directory to persist streaming upload progress in (upload ID,
+	// encryption state and completed parts, see state.MultipartUploadState).
+	// An upload of the same unchanged file then resumes after the parts
+	// already uploaded instead of from byte zero, and an interrupted upload
+	// is left in storage for that instead of being aborted. Used by the CLI.
+	ResumeDir string
+
+	// ledger collects ciphertext block hashes for the upload history (set
+	// by uploadFile unless SkipHistory) and for read-back verification
+	ledger *readbackLedger
 }
 
 // UploadFile is THE ONLY canonical entry point for uploading files to Rescale cloud storage.
@@ -68,6 +112,19 @@ type UploadParams struct {
 //
 // Returns the registered CloudFile on success, or an error on failure.
 func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, error) {
+	if err := viewer.Check("upload " + filepath.Base(params.LocalPath)); err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.Start(ctx, "upload.file", attribute.String("file.name", filepath.Base(params.LocalPath)))
+	cloudFile, err := uploadFile(ctx, params)
+	tracing.End(span, err)
+	return cloudFile, err
+}
+
+// uploadFile does the work of UploadFile.
+func uploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, error) {
+	// Callers tracking the upload in the transfer queue set its task ID
+	ctx = logging.EnsureTransferID(ctx)
 	overallTimer := cloud.StartTimer(params.OutputWriter, "Upload total")
 
 	// Validate required parameters
@@ -88,6 +145,11 @@ func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, er
 	}
 
 	cloud.TimingLog(params.OutputWriter, "File: %s (%s)", filepath.Base(params.LocalPath), cloud.FormatBytes(fileInfo.Size()))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("file.size", fileInfo.Size()))
+
+	if cfg := params.APIClient.GetConfig(); cfg != nil && cfg.UploadReadbackVerify {
+		params.VerifyReadback = true
+	}
 
 	// Hash calculation is deferred until after upload completes — see hashTimer below.
 
@@ -95,48 +157,58 @@ func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, er
 	fileName := filepath.Base(params.LocalPath)
 
 	initTimer := cloud.StartTimer(params.OutputWriter, "Upload initialization")
+	initCtx, initSpan := tracing.Start(ctx, "upload.init")
+	defer initSpan.End() // For the error returns below
 
 	// Get the global credential manager (caches user profile, credentials, and folders)
 	credManager := credentials.GetManager(params.APIClient)
 
 	// Get user profile to determine storage type (cached for 5 minutes)
 	t1 := time.Now()
-	profile, err := credManager.GetUserProfile(ctx)
+	profile, err := credManager.GetUserProfile(initCtx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
-	log.Printf("[DEBUG] %s: GetUserProfile took %v", fileName, time.Since(t1))
+	logging.Printf(ctx, "[DEBUG] %s: GetUserProfile took %v", fileName, time.Since(t1))
 
 	// Skip GetRootFolders() when caller provides FolderID (batch uploads always do).
 	// GetRootFolders is only needed to resolve MyLibrary as default target.
 	var targetFolder string
 	if params.FolderID != "" {
 		targetFolder = params.FolderID
-		log.Printf("[DEBUG] %s: GetRootFolders skipped (FolderID provided)", fileName)
+		logging.Printf(ctx, "[DEBUG] %s: GetRootFolders skipped (FolderID provided)", fileName)
 	} else {
 		t2 := time.Now()
-		folders, err := credManager.GetRootFolders(ctx)
+		folders, err := credManager.GetRootFolders(initCtx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get root folders: %w", err)
 		}
 		targetFolder = folders.MyLibrary
-		log.Printf("[DEBUG] %s: GetRootFolders took %v", fileName, time.Since(t2))
+		logging.Printf(ctx, "[DEBUG] %s: GetRootFolders took %v", fileName, time.Since(t2))
 	}
 
 	// Create provider using factory
 	t3 := time.Now()
 	factory := providers.NewFactory()
-	provider, err := factory.NewTransferFromStorageInfo(ctx, &profile.DefaultStorage, params.APIClient)
+	provider, err := factory.NewTransferFromStorageInfo(initCtx, &profile.DefaultStorage, params.APIClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider: %w", err)
 	}
-	log.Printf("[DEBUG] %s: CreateProvider took %v", fileName, time.Since(t3))
-	log.Printf("[DEBUG] %s: Total init took %v", fileName, time.Since(debugStart))
+	logging.Printf(ctx, "[DEBUG] %s: CreateProvider took %v", fileName, time.Since(t3))
+	logging.Printf(ctx, "[DEBUG] %s: Total init took %v", fileName, time.Since(debugStart))
 
 	initTimer.StopWithMessage("backend=%s", profile.DefaultStorage.StorageType)
+	initSpan.SetAttributes(attribute.String("rescale.storage_type", profile.DefaultStorage.StorageType))
+	initSpan.End()
 
 	var result *cloud.UploadResult
 
+	// Block hashes sampled into the upload history let `history verify`
+	// check stored content with a few ranged reads
+	if !params.SkipHistory {
+		params.ledger = newReadbackLedger(constants.ReadbackBlockSize)
+	}
+
 	uploadTimer := cloud.StartTimer(params.OutputWriter, "Upload transfer")
 
 	// Upload based on encryption mode
@@ -154,19 +226,31 @@ func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, er
 		return nil, fmt.Errorf("%s upload failed: %w", profile.DefaultStorage.StorageType, err)
 	}
 
-	uploadTimer.StopWithThroughput(fileInfo.Size())
+	transferTime := uploadTimer.StopWithThroughput(fileInfo.Size())
 
 	// Hash AFTER upload completes: the file is now in disk cache, so hashing is fast
 	// (avoids disk I/O contention that caused long "Preparing" delays for large files).
+	// The SHA-256 of what was encrypted comes with the result; the file is
+	// only hashed with it as well to verify it, or when the upload had none.
 	hashTimer := cloud.StartTimer(params.OutputWriter, "Hash calculation")
-	fileHash, err := encryption.CalculateSHA512(params.LocalPath)
+	plainSHA256 := result.PlaintextSHA256
+	fileHash, fileSHA256, err := hashFile(params.LocalPath, params.VerifyIntegrity || plainSHA256 == "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate file hash: %w", err)
 	}
 	hashTimer.StopWithThroughput(fileInfo.Size())
+	if plainSHA256 == "" {
+		plainSHA256 = fileSHA256
+	} else if params.VerifyIntegrity && plainSHA256 != fileSHA256 {
+		// Not registered: the stored object isn't the file as it is now
+		return nil, &cloud.IntegrityError{Path: params.LocalPath, Upload: true, Algorithm: "SHA-256", Expected: plainSHA256, Actual: fileSHA256}
+	}
 
 	// Build file registration request
 	filename := filepath.Base(params.LocalPath)
+	if params.RemoteName != "" {
+		filename = params.RemoteName
+	}
 	fileReq := &models.CloudFileRequest{
 		TypeID:               1, // INPUT_FILE
 		Name:                 filename,
@@ -188,6 +272,10 @@ func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, er
 				HashFunction: "sha512",
 				FileHash:     fileHash,
 			},
+			{
+				HashFunction: "sha256",
+				FileHash:     plainSHA256,
+			},
 		},
 	}
 
@@ -214,6 +302,15 @@ func UploadFile(ctx context.Context, params UploadParams) (*models.CloudFile, er
 	}
 
 	regTimer.StopWithMessage("file_id=%s", cloudFile.ID)
+	if len(cloudFile.FileChecksums) == 0 {
+		// Callers such as upload manifests rely on the checksums registered
+		cloudFile.FileChecksums = fileReq.FileChecksums
+	}
+
+	if !params.SkipHistory {
+		recordHistory(params, cloudFile, fileInfo.Size(), fileHash, profile.DefaultStorage.StorageType, transferTime,
+			params.ledger.sample(constants.HistoryBlockSamples))
+	}
 
 	overallTimer.StopWithThroughput(fileInfo.Size())
 
@@ -355,10 +452,12 @@ type encryptedPart struct {
 
 // uploadResult holds the result of an upload worker (used for parallel uploads).
 type uploadResult struct {
-	partIndex int64
-	result    *transfer.PartResult
-	plainSize int64
-	err       error
+	partIndex  int64
+	result     *transfer.PartResult
+	plainSize  int64
+	cipherSize int64
+	endIV      []byte // Last ciphertext block, for the resume state
+	err        error
 }
 
 // uploadStreaming uses the StreamingConcurrentUploader interface for streaming uploads.
@@ -382,16 +481,24 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 		OutputWriter: params.OutputWriter,
 	}
 
-	log.Printf("[DEBUG] %s: Starting InitStreamingUpload", fileName)
-	t1 := time.Now()
-	uploadState, err := streamingUploader.InitStreamingUpload(ctx, initParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize streaming upload: %w", err)
+	// Pick up an interrupted upload of the same file where it stopped
+	resume := newStreamingResume(params.ResumeDir, params.LocalPath)
+	uploadState, priorParts := resume.resume(ctx, streamingUploader, provider.StorageType(), params, fileSize)
+	if uploadState == nil {
+		logging.Printf(ctx, "[DEBUG] %s: Starting InitStreamingUpload", fileName)
+		t1 := time.Now()
+		var err error
+		uploadState, err = streamingUploader.InitStreamingUpload(ctx, initParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize streaming upload: %w", err)
+		}
+		logging.Printf(ctx, "[DEBUG] %s: InitStreamingUpload took %v", fileName, time.Since(t1))
+		resume.begin(ctx, uploadState, provider.StorageType())
 	}
-	log.Printf("[DEBUG] %s: InitStreamingUpload took %v", fileName, time.Since(t1))
+	startPart := int64(len(priorParts))
 
 	streamInitTimer.StopWithMessage("parts=%d part_size=%s", uploadState.TotalParts, cloud.FormatBytes(int64(uploadState.PartSize)))
-	log.Printf("[DEBUG] %s: Streaming init complete, starting transfer at %v since start", fileName, time.Since(streamStart))
+	logging.Printf(ctx, "[DEBUG] %s: Streaming init complete, starting transfer at %v since start", fileName, time.Since(streamStart))
 
 	// Progress interpolator provides smooth updates every 500ms, ensuring responsive
 	// feedback even when individual parts take seconds to upload.
@@ -420,6 +527,15 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	}
 	defer file.Close()
 
+	// SHA-256 of the plaintext as it is encrypted, recorded with the file.
+	// The parts uploaded before a resume are read once more to hash them.
+	plainHash := sha256.New()
+	if startPart > 0 {
+		if _, err := io.CopyN(plainHash, file, startPart*uploadState.PartSize); err != nil {
+			return nil, fmt.Errorf("failed to read the parts uploaded before resuming: %w", err)
+		}
+	}
+
 	concurrency := 4
 	if params.TransferHandle != nil && params.TransferHandle.GetThreads() > 1 {
 		concurrency = params.TransferHandle.GetThreads()
@@ -441,12 +557,25 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	var firstErr error
 	var errOnce sync.Once
 
+	// Ciphertext block hashes for the upload history and read-back
+	// verification, recorded by the upload workers so hashing stays off the
+	// sequential encryption path
+	ledger := params.ledger
+	if ledger == nil && params.VerifyReadback {
+		ledger = newReadbackLedger(constants.ReadbackBlockSize)
+	}
+	if ledger != nil {
+		for _, p := range priorParts {
+			ledger.addUnhashedPart(p.Index, p.CipherSize)
+		}
+	}
+
 	// Encryption goroutine: reads file, encrypts parts, sends to channel
 	// Must be sequential due to CBC chaining constraint
 	go func() {
 		defer close(encryptedChan)
 		buffer := make([]byte, uploadState.PartSize)
-		var partIndex int64 = 0
+		partIndex := startPart
 		encryptFirstLogged := false
 
 		for {
@@ -457,6 +586,14 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 			default:
 			}
 
+			if params.PauseInBlackout {
+				if err := resources.WaitForBlackout(uploadCtx, func(until time.Time) {
+					logging.Printf(ctx, "[INFO] %s: paused for blackout window until %s (part %d)", fileName, until.Format("15:04"), partIndex)
+				}); err != nil {
+					return
+				}
+			}
+
 			n, readErr := file.Read(buffer)
 
 			// Handle empty file: first read returns (0, io.EOF)
@@ -483,9 +620,17 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 				// Make copy of plaintext (buffer will be reused)
 				plaintext := make([]byte, n)
 				copy(plaintext, buffer[:n])
+				plainHash.Write(plaintext)
 
-				// Encrypt this part (sequential, CBC constraint)
+				// Encrypt this part (sequential, CBC constraint). Holding an
+				// encryption slot per part keeps the process within its CPU
+				// budget while letting concurrent files take turns.
+				release, encErr := resources.AcquireEncryption(uploadCtx)
+				if encErr != nil {
+					return
+				}
 				ciphertext, encErr := streamingUploader.EncryptStreamingPart(uploadCtx, uploadState, partIndex, plaintext)
+				release()
 				if encErr != nil {
 					errOnce.Do(func() { firstErr = encErr })
 					cancelUpload()
@@ -493,7 +638,7 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 				}
 
 				if !encryptFirstLogged {
-					log.Printf("[DEBUG] %s: First encrypted part ready at %v since stream start (part 0, %d bytes)",
+					logging.Printf(ctx, "[DEBUG] %s: First encrypted part ready at %v since stream start (part 0, %d bytes)",
 						fileName, time.Since(streamStart), len(ciphertext))
 					encryptFirstLogged = true
 				}
@@ -538,7 +683,7 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 
 			// Log first upload start
 			if atomic.CompareAndSwapInt32(&firstUploadStartLogged, 0, 1) {
-				log.Printf("[DEBUG] %s: First upload STARTING at %v since stream start (part %d)",
+				logging.Printf(ctx, "[DEBUG] %s: First upload STARTING at %v since stream start (part %d)",
 					fileName, time.Since(streamStart), enc.partIndex)
 			}
 
@@ -546,23 +691,30 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 			partResult, uploadErr := streamingUploader.UploadCiphertext(uploadCtx, uploadState, enc.partIndex, enc.ciphertext)
 
 			if uploadErr != nil {
+				uploadErr = transfer.NewPartError(enc.partIndex, uploadState.TotalParts, uploadErr)
 				errOnce.Do(func() { firstErr = uploadErr })
 				cancelUpload()
 				resultChan <- uploadResult{partIndex: enc.partIndex, err: uploadErr}
 				return
 			}
 
+			if ledger != nil {
+				ledger.addPart(enc.partIndex, enc.ciphertext)
+			}
+
 			// Log first upload complete
 			if atomic.CompareAndSwapInt32(&firstUploadDoneLogged, 0, 1) {
-				log.Printf("[DEBUG] %s: First upload COMPLETE at %v since stream start (part %d)",
+				logging.Printf(ctx, "[DEBUG] %s: First upload COMPLETE at %v since stream start (part %d)",
 					fileName, time.Since(streamStart), enc.partIndex)
 			}
 
 			// Send success result
 			resultChan <- uploadResult{
-				partIndex: enc.partIndex,
-				result:    partResult,
-				plainSize: enc.plainSize,
+				partIndex:  enc.partIndex,
+				result:     partResult,
+				plainSize:  enc.plainSize,
+				cipherSize: int64(len(enc.ciphertext)),
+				endIV:      bytes.Clone(enc.ciphertext[max(0, len(enc.ciphertext)-cbcBlockSize):]),
 			}
 		}
 	}
@@ -627,6 +779,13 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	// Collect results - parts may arrive out of order due to parallel uploads
 	partsMap := make(map[int64]*transfer.PartResult)
 	completedCount := 0
+	for _, p := range priorParts {
+		partsMap[p.Index] = partResult(p)
+		completedCount++
+		if progressInterp != nil {
+			progressInterp.ConfirmBytes(p.Size)
+		}
+	}
 
 	firstProgressLogged := false
 	for res := range resultChan {
@@ -639,10 +798,11 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 		res.result.Size = res.plainSize
 		partsMap[res.partIndex] = res.result
 		completedCount++
+		resume.partDone(ctx, res.result, res.cipherSize, res.endIV)
 
 		if progressInterp != nil {
 			if !firstProgressLogged {
-				log.Printf("[DEBUG] %s: FIRST part complete at %v since stream start (part %d/%d)",
+				logging.Printf(ctx, "[DEBUG] %s: FIRST part complete at %v since stream start (part %d/%d)",
 					fileName, time.Since(streamStart), completedCount, uploadState.TotalParts)
 				firstProgressLogged = true
 			}
@@ -650,9 +810,16 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 		}
 	}
 
-	// Check for errors
+	// Check for errors. With a resume state, uploads that may succeed on a
+	// later attempt keep their parts; storage rejecting a part is final.
 	if firstErr != nil {
-		_ = streamingUploader.AbortStreamingUpload(ctx, uploadState)
+		var partErr *transfer.PartError
+		if resume.keeps() && (!errors.As(firstErr, &partErr) || !partErr.Permanent()) {
+			logging.Printf(ctx, "[INFO] %s: upload interrupted after %d of %d parts, kept for resume", fileName, completedCount, uploadState.TotalParts)
+			return nil, firstErr
+		}
+		abortStreaming(ctx, streamingUploader, uploadState)
+		resume.finish()
 		return nil, firstErr
 	}
 
@@ -660,7 +827,11 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	// This can happen if the user cancels the upload or a timeout occurs.
 	select {
 	case <-ctx.Done():
-		_ = streamingUploader.AbortStreamingUpload(ctx, uploadState)
+		if resume.keeps() {
+			logging.Printf(ctx, "[INFO] %s: upload cancelled after %d of %d parts, kept for resume", fileName, completedCount, uploadState.TotalParts)
+		} else {
+			abortStreaming(ctx, streamingUploader, uploadState)
+		}
 		return nil, fmt.Errorf("upload cancelled: %w", ctx.Err())
 	default:
 	}
@@ -671,7 +842,9 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	// we must abort to avoid creating corrupted files in cloud storage.
 	expectedParts := int(uploadState.TotalParts)
 	if len(partsMap) != expectedParts {
-		_ = streamingUploader.AbortStreamingUpload(ctx, uploadState)
+		if !resume.keeps() {
+			abortStreaming(ctx, streamingUploader, uploadState)
+		}
 		return nil, fmt.Errorf("upload incomplete: received %d of %d parts (upload was interrupted or cancelled)",
 			len(partsMap), expectedParts)
 	}
@@ -687,14 +860,33 @@ func uploadStreaming(ctx context.Context, provider cloud.CloudTransfer, params U
 	// Complete upload
 	result, err := streamingUploader.CompleteStreamingUpload(ctx, uploadState, parts)
 	if err != nil {
+		// The parts may have expired (Azure drops uncommitted blocks after a
+		// week); don't resume into the same failure
+		resume.finish()
 		return nil, fmt.Errorf("failed to complete streaming upload: %w", err)
 	}
 
 	completeTimer.StopWithMessage("parts=%d", len(parts))
+	resume.finish()
+	// The encryption goroutine has exited: every part it sent was collected
+	result.PlaintextSHA256 = hex.EncodeToString(plainHash.Sum(nil))
+
+	if err := readbackVerify(ctx, provider, params, ledger, result.StoragePath); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
+// abortStreaming aborts a streaming upload that will not be completed, so
+// the provider does not keep its parts. Uses cloud.Cleanup because after a
+// cancel ctx can no longer carry the abort request.
+func abortStreaming(ctx context.Context, uploader transfer.StreamingConcurrentUploader, uploadState *transfer.StreamingUpload) {
+	cloud.Cleanup(ctx, "abort streaming upload", func(ctx context.Context) error {
+		return uploader.AbortStreamingUpload(ctx, uploadState)
+	})
+}
+
 // uploadPreEncrypt uses the PreEncryptUploader interface for pre-encrypted uploads.
 func uploadPreEncrypt(ctx context.Context, provider cloud.CloudTransfer, params UploadParams, fileSize int64) (*cloud.UploadResult, error) {
 	// Cast to PreEncryptUploader
@@ -714,7 +906,7 @@ func uploadPreEncrypt(ctx context.Context, provider cloud.CloudTransfer, params
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
-	defer os.Remove(encryptedPath)
+	defer securedelete.Remove(encryptedPath)
 
 	encryptTimer := cloud.StartTimer(params.OutputWriter, "Pre-encryption")
 
@@ -722,12 +914,39 @@ func uploadPreEncrypt(ctx context.Context, provider cloud.CloudTransfer, params
 	if params.OutputWriter != nil {
 		fmt.Fprintf(params.OutputWriter, "Encrypting file (%s)...\n", filepath.Base(params.LocalPath))
 	}
-	if err := encryption.EncryptFile(params.LocalPath, encryptedPath, encryptionKey, iv); err != nil {
+	if params.PauseInBlackout {
+		if err := resources.WaitForBlackout(ctx, nil); err != nil {
+			return nil, fmt.Errorf("upload cancelled: %w", err)
+		}
+	}
+	release, err := resources.AcquireEncryption(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("upload cancelled: %w", err)
+	}
+	err = encryption.EncryptFileContext(ctx, params.LocalPath, encryptedPath, encryptionKey, iv)
+	release()
+	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt file: %w", err)
 	}
 
 	encryptTimer.StopWithThroughput(fileSize)
 
+	// Read-back verification hashes every block; the upload history only
+	// needs the few it samples, read from the encrypted file
+	ledger := params.ledger
+	if params.VerifyReadback {
+		if ledger == nil {
+			ledger = newReadbackLedger(constants.ReadbackBlockSize)
+		}
+		if err := ledger.addFile(encryptedPath); err != nil {
+			return nil, err
+		}
+	} else if ledger != nil {
+		if err := ledger.addFileSample(encryptedPath, constants.HistoryBlockSamples); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build upload params (providers stat the encrypted file themselves)
 	uploadParams := transfer.EncryptedFileUploadParams{
 		LocalPath:        params.LocalPath,
@@ -751,6 +970,10 @@ func uploadPreEncrypt(ctx context.Context, provider cloud.CloudTransfer, params
 
 	uploadTimer.StopWithThroughput(fileSize)
 
+	if err := readbackVerify(ctx, provider, params, ledger, result.StoragePath); err != nil {
+		return nil, err
+	}
+
 	// Clean up resume state
 	state.DeleteUploadState(params.LocalPath)
 
@@ -2,6 +2,7 @@ package upload
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -12,23 +13,26 @@ import (
 
 	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/cloud/transfer"
+	"github.com/rescale/rescale-int/internal/constants"
 )
 
 // fakeStreamingUploader implements transfer.StreamingConcurrentUploader
 // for testing the upload pipeline without real cloud storage.
 type fakeStreamingUploader struct {
 	// Track what the pipeline does
-	mu                sync.Mutex
-	initCalled        bool
-	encryptCalls      []fakeEncryptCall
-	uploadCalls       []fakeUploadCall
-	completeCalled    bool
-	completeParts     []*transfer.PartResult
-	abortCalled       bool
+	mu             sync.Mutex
+	initCalled     bool
+	encryptCalls   []fakeEncryptCall
+	uploadCalls    []fakeUploadCall
+	completeCalled bool
+	completeParts  []*transfer.PartResult
+	abortCalled    bool
+	abortCtxErr    error
 
 	// Configure behavior
-	partSize          int64
-	encryptFn         func(partIndex int64, plaintext []byte) ([]byte, error)
+	partSize     int64
+	encryptFn    func(partIndex int64, plaintext []byte) ([]byte, error)
+	blockUploads bool // UploadCiphertext blocks until ctx is cancelled
 }
 
 type fakeEncryptCall struct {
@@ -122,7 +126,13 @@ func (f *fakeStreamingUploader) EncryptStreamingPart(_ context.Context, _ *trans
 	return padded, nil
 }
 
-func (f *fakeStreamingUploader) UploadCiphertext(_ context.Context, _ *transfer.StreamingUpload, partIndex int64, ciphertext []byte) (*transfer.PartResult, error) {
+func (f *fakeStreamingUploader) UploadCiphertext(ctx context.Context, _ *transfer.StreamingUpload, partIndex int64, ciphertext []byte) (*transfer.PartResult, error) {
+	if f.blockUploads {
+		// Simulate an in-flight part that only returns once its request is aborted
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -156,10 +166,11 @@ func (f *fakeStreamingUploader) CompleteStreamingUpload(_ context.Context, uploa
 	}, nil
 }
 
-func (f *fakeStreamingUploader) AbortStreamingUpload(_ context.Context, _ *transfer.StreamingUpload) error {
+func (f *fakeStreamingUploader) AbortStreamingUpload(ctx context.Context, _ *transfer.StreamingUpload) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.abortCalled = true
+	f.abortCtxErr = ctx.Err()
 	return nil
 }
 
@@ -422,6 +433,69 @@ func TestUploadStreamingEncryptError(t *testing.T) {
 	}
 }
 
+// TestUploadStreamingCancelMidPart verifies that cancelling while parts are
+// in flight returns within the cancel-to-idle target, and that the multipart
+// abort still runs with a live context so the cloud side is cleaned up.
+func TestUploadStreamingCancelMidPart(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "cancel.dat")
+	if err := os.WriteFile(testFile, make([]byte, 64*1024), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	fake := &fakeStreamingUploader{
+		partSize:     16 * 1024,
+		blockUploads: true,
+	}
+
+	params := UploadParams{
+		LocalPath: testFile,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := uploadStreaming(ctx, fake, params, 64*1024)
+		errCh <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > constants.CancelIdleTarget {
+			t.Errorf("upload took %v to return after cancel, want < %v", elapsed, constants.CancelIdleTarget)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload did not return after cancel")
+	}
+
+	// The abort runs in the background once the caller's context is gone
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fake.mu.Lock()
+		called, ctxErr := fake.abortCalled, fake.abortCtxErr
+		fake.mu.Unlock()
+		if called {
+			if ctxErr != nil {
+				t.Errorf("AbortStreamingUpload called with cancelled context: %v", ctxErr)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected AbortStreamingUpload to be called after cancel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // TestProgressInterpolatorEmptyFile verifies that the progress interpolator
 // handles 0-byte files correctly (no NaN from 0/0 division).
 func TestProgressInterpolatorEmptyFile(t *testing.T) {
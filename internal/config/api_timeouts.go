@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// APIEndpointClass groups API calls that share a request timeout.
+type APIEndpointClass string
+
+const (
+	// APICatalog is a scan of a whole catalog (core types, software,
+	// automations); large platforms need many pages.
+	APICatalog APIEndpointClass = "catalog"
+	// APIListing lists jobs, files or folders.
+	APIListing APIEndpointClass = "listing"
+	// APIMutation creates, submits, stops or deletes something.
+	APIMutation APIEndpointClass = "mutation"
+	// APIStatus fetches a job's status or a single record.
+	APIStatus APIEndpointClass = "status"
+)
+
+// APIEndpointClasses lists the classes in the order they are documented.
+var APIEndpointClasses = []APIEndpointClass{APICatalog, APIListing, APIMutation, APIStatus}
+
+// APITimeout returns the request timeout for class: the api_timeout_<class>
+// setting, or the class default when it is 0 or c is nil.
+func (c *Config) APITimeout(class APIEndpointClass) time.Duration {
+	var seconds int
+	if c != nil {
+		seconds = *c.apiTimeoutField(class)
+	}
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	switch class {
+	case APICatalog:
+		return constants.DefaultCatalogAPITimeout
+	case APIListing:
+		return constants.DefaultListingAPITimeout
+	case APIMutation:
+		return constants.DefaultMutationAPITimeout
+	default:
+		return constants.DefaultStatusAPITimeout
+	}
+}
+
+// apiTimeoutField returns the setting that holds class's timeout.
+func (c *Config) apiTimeoutField(class APIEndpointClass) *int {
+	switch class {
+	case APICatalog:
+		return &c.APITimeoutCatalog
+	case APIListing:
+		return &c.APITimeoutListing
+	case APIMutation:
+		return &c.APITimeoutMutation
+	default:
+		return &c.APITimeoutStatus
+	}
+}
+
+// validateAPITimeouts rejects negative api_timeout_* settings.
+func (c *Config) validateAPITimeouts() error {
+	for _, class := range APIEndpointClasses {
+		if *c.apiTimeoutField(class) < 0 {
+			return fmt.Errorf("api_timeout_%s must be 0 (default) or a number of seconds", class)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+func TestAPITimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.csv")
+	if err := os.WriteFile(path, []byte("key,value\napi_timeout_catalog,900\napi_timeout_status,5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[APIEndpointClass]time.Duration{
+		APICatalog:  15 * time.Minute,
+		APIListing:  constants.DefaultListingAPITimeout,
+		APIMutation: constants.DefaultMutationAPITimeout,
+		APIStatus:   5 * time.Second,
+	}
+	for class, d := range want {
+		if got := cfg.APITimeout(class); got != d {
+			t.Errorf("APITimeout(%s) = %v, want %v", class, got, d)
+		}
+	}
+
+	var nilCfg *Config
+	if got := nilCfg.APITimeout(APICatalog); got != constants.DefaultCatalogAPITimeout {
+		t.Errorf("nil config APITimeout(catalog) = %v", got)
+	}
+
+	cfg.APITimeoutListing = -1
+	if err := cfg.validateAPITimeouts(); err == nil {
+		t.Error("validateAPITimeouts() accepted a negative timeout")
+	}
+}
@@ -0,0 +1,400 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BundleVersion is the format version ExportBundle writes.
+const BundleVersion = 1
+
+// Names of the entries of a configuration bundle.
+const (
+	bundleConfigFile   = "config.csv"
+	bundleDaemonConfig = "daemon.conf"
+	bundleTemplateDir  = "templates"
+
+	// SecretAPIToken is the bundle secret holding the API key from the
+	// token file.
+	SecretAPIToken = "api_token"
+)
+
+// Key derivation for sealed bundle secrets.
+const (
+	bundleKDF        = "pbkdf2-sha256"
+	bundleIterations = 600000
+)
+
+// ErrBundlePassphrase is returned when a bundle's secrets cannot be opened
+// with the passphrase given.
+var ErrBundlePassphrase = errors.New("wrong passphrase, or the bundle's secrets are corrupted")
+
+// Bundle is a snapshot of a user's configuration for moving it to another
+// workstation: settings, daemon settings and saved job templates, plus the
+// API key and notification secrets (see SecretsFileName) sealed with a
+// passphrase. Without a passphrase secrets are left out and only their names
+// are recorded.
+type Bundle struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Hostname  string    `json:"hostname,omitempty"`
+
+	// Files maps a slash-separated name (config.csv, daemon.conf,
+	// templates/<name>.json) to its content.
+	Files map[string][]byte `json:"files"`
+
+	Secrets        *SealedSecrets `json:"secrets,omitempty"`
+	SecretsOmitted []string       `json:"secretsOmitted,omitempty"`
+}
+
+// SealedSecrets holds the bundle's secrets encrypted with AES-256-GCM under
+// a key derived from the passphrase.
+type SealedSecrets struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// BundlePaths locates the configuration a bundle is exported from or
+// imported to.
+type BundlePaths struct {
+	ConfigFile   string // config.csv
+	TokenFile    string // API key
+	DaemonConfig string // daemon.conf
+	TemplateDir  string // Saved job templates (*.json)
+}
+
+// DefaultBundlePaths returns the standard locations, with configFile in
+// place of the default config.csv when set.
+func DefaultBundlePaths(configFile string) BundlePaths {
+	if configFile == "" {
+		configFile = GetDefaultConfigPath()
+	}
+	daemonConfig, _ := DefaultDaemonConfigPath()
+	return BundlePaths{
+		ConfigFile:   configFile,
+		TokenFile:    GetDefaultTokenPath(),
+		DaemonConfig: daemonConfig,
+		TemplateDir:  TemplateDirectory(),
+	}
+}
+
+// ExportBundle collects the configuration at paths. The API key and the
+// notification secrets are sealed with passphrase; with an empty passphrase
+// they are left out. Missing files are skipped.
+func ExportBundle(paths BundlePaths, passphrase string) (*Bundle, error) {
+	b := &Bundle{
+		Version:   BundleVersion,
+		CreatedAt: time.Now().UTC(),
+		Files:     make(map[string][]byte),
+	}
+	b.Hostname, _ = os.Hostname()
+
+	for name, p := range map[string]string{bundleConfigFile: paths.ConfigFile, bundleDaemonConfig: paths.DaemonConfig} {
+		if p == "" {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		b.Files[name] = data
+	}
+
+	if paths.TemplateDir != "" {
+		entries, err := os.ReadDir(paths.TemplateDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read templates: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(paths.TemplateDir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+			}
+			b.Files[bundleTemplateDir+"/"+entry.Name()] = data
+		}
+	}
+
+	secrets := make(map[string]string)
+	if paths.TokenFile != "" {
+		if _, err := os.Stat(paths.TokenFile); err == nil {
+			token, err := ReadTokenFile(paths.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+			if token != "" {
+				secrets[SecretAPIToken] = token
+			}
+		}
+	}
+	if paths.ConfigFile != "" {
+		stored := &Config{}
+		if err := loadSecrets(stored, paths.ConfigFile); err != nil {
+			return nil, err
+		}
+		for _, record := range stored.secretRecords() {
+			if record[1] != "" {
+				secrets[record[0]] = record[1]
+			}
+		}
+	}
+	if len(secrets) > 0 {
+		if passphrase == "" {
+			for name := range secrets {
+				b.SecretsOmitted = append(b.SecretsOmitted, name)
+			}
+			sort.Strings(b.SecretsOmitted)
+		} else {
+			sealed, err := sealSecrets(secrets, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			b.Secrets = sealed
+		}
+	}
+	return b, nil
+}
+
+// WriteBundle writes b to path as JSON, readable by the owner only.
+func WriteBundle(b *Bundle, path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// ReadBundle reads and checks a bundle written by WriteBundle.
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("not a configuration bundle: %w", err)
+	}
+	if b.Version < 1 || b.Version > BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (this build reads version %d)", b.Version, BundleVersion)
+	}
+	for name := range b.Files {
+		if !validBundleEntry(name) {
+			return nil, fmt.Errorf("bundle contains an unexpected entry %q", name)
+		}
+	}
+	return &b, nil
+}
+
+// BundleImportResult reports what ImportBundle did.
+type BundleImportResult struct {
+	Written         []string // Bundle entries written
+	Skipped         []string // Entries left alone because the file exists
+	SecretsRestored []string
+	SecretsOmitted  []string // Secrets the bundle was exported without
+}
+
+// ImportBundle writes the bundle's files to paths. Existing files are only
+// replaced with overwrite. Sealed secrets are opened with passphrase and
+// restored; with an empty passphrase they are not. Nothing is written when
+// config.csv does not parse or the passphrase is wrong.
+func ImportBundle(b *Bundle, paths BundlePaths, passphrase string, overwrite bool) (*BundleImportResult, error) {
+	if data, ok := b.Files[bundleConfigFile]; ok {
+		if err := checkBundledConfig(data); err != nil {
+			return nil, err
+		}
+	}
+	var secrets map[string]string
+	if b.Secrets != nil && passphrase != "" {
+		var err error
+		if secrets, err = openSecrets(b.Secrets, passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BundleImportResult{SecretsOmitted: b.SecretsOmitted}
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := bundleTarget(paths, name)
+		if target == "" {
+			continue
+		}
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(target, b.Files[name], 0600); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		result.Written = append(result.Written, name)
+	}
+
+	if token, ok := secrets[SecretAPIToken]; ok && paths.TokenFile != "" {
+		if _, err := os.Stat(paths.TokenFile); err == nil && !overwrite {
+			result.Skipped = append(result.Skipped, SecretAPIToken)
+		} else {
+			if err := WriteTokenFile(paths.TokenFile, token); err != nil {
+				return result, err
+			}
+			result.SecretsRestored = append(result.SecretsRestored, SecretAPIToken)
+		}
+	}
+
+	// Notification secrets go to the secrets file next to config.csv
+	if paths.ConfigFile != "" {
+		restored := &Config{}
+		var names []string
+		for _, record := range restored.secretRecords() {
+			if value, ok := secrets[record[0]]; ok {
+				restored.setSecret(record[0], value)
+				names = append(names, record[0])
+			}
+		}
+		if len(names) > 0 {
+			if _, err := os.Stat(GetSecretsPath(paths.ConfigFile)); err == nil && !overwrite {
+				result.Skipped = append(result.Skipped, names...)
+			} else {
+				if err := saveSecrets(restored, paths.ConfigFile); err != nil {
+					return result, err
+				}
+				result.SecretsRestored = append(result.SecretsRestored, names...)
+			}
+		}
+	}
+	return result, nil
+}
+
+// validBundleEntry reports whether name is an entry a bundle may hold, so a
+// crafted bundle cannot write outside the configuration directories.
+func validBundleEntry(name string) bool {
+	switch name {
+	case bundleConfigFile, bundleDaemonConfig:
+		return true
+	}
+	dir, file := path.Split(name)
+	return dir == bundleTemplateDir+"/" && strings.HasSuffix(file, ".json") &&
+		file != ".json" && !strings.ContainsAny(file, `\:`) && !strings.HasPrefix(file, ".")
+}
+
+// bundleTarget returns where the entry name is written, or "" when paths
+// has no location for it.
+func bundleTarget(paths BundlePaths, name string) string {
+	switch name {
+	case bundleConfigFile:
+		return paths.ConfigFile
+	case bundleDaemonConfig:
+		return paths.DaemonConfig
+	}
+	if paths.TemplateDir == "" {
+		return ""
+	}
+	return filepath.Join(paths.TemplateDir, path.Base(name))
+}
+
+// checkBundledConfig parses a bundled config.csv.
+func checkBundledConfig(data []byte) error {
+	f, err := os.CreateTemp("", "bundle-config-*.csv")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := LoadConfigCSV(f.Name()); err != nil {
+		return fmt.Errorf("bundled config.csv is invalid: %w", err)
+	}
+	return nil
+}
+
+// sealSecrets encrypts secrets under a key derived from passphrase.
+func sealSecrets(secrets map[string]string, passphrase string) (*SealedSecrets, error) {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	s := &SealedSecrets{KDF: bundleKDF, Iterations: bundleIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := bundleCipher(s, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Data = gcm.Seal(nil, s.Nonce, plain, nil)
+	return s, nil
+}
+
+// openSecrets decrypts sealed secrets with passphrase.
+func openSecrets(s *SealedSecrets, passphrase string) (map[string]string, error) {
+	if s.KDF != bundleKDF || s.Iterations < 1 {
+		return nil, fmt.Errorf("unsupported key derivation %q", s.KDF)
+	}
+	gcm, err := bundleCipher(s, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return nil, ErrBundlePassphrase
+	}
+	plain, err := gcm.Open(nil, s.Nonce, s.Data, nil)
+	if err != nil {
+		return nil, ErrBundlePassphrase
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, ErrBundlePassphrase
+	}
+	return secrets, nil
+}
+
+// bundleCipher derives the AES-256-GCM cipher for s from passphrase.
+func bundleCipher(s *SealedSecrets, passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, s.Salt, s.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
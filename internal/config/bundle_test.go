@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testBundlePaths(dir string) BundlePaths {
+	return BundlePaths{
+		ConfigFile:   filepath.Join(dir, "config.csv"),
+		TokenFile:    filepath.Join(dir, "token"),
+		DaemonConfig: filepath.Join(dir, "daemon.conf"),
+		TemplateDir:  filepath.Join(dir, "templates"),
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	src := testBundlePaths(t.TempDir())
+	cfg := &Config{
+		TarWorkers:    7,
+		UploadWorkers: 4,
+		JobWorkers:    4,
+		ProxyMode:     "no-proxy",
+		APIBaseURL:    "https://platform.rescale.com",
+		MaxRetries:    1,
+		SMTPPassword:  "mail-pass",
+	}
+	if err := SaveConfigCSV(cfg, src.ConfigFile); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(src.DaemonConfig, []byte("[daemon]\nenabled = true\n"), 0600)
+	os.MkdirAll(src.TemplateDir, 0700)
+	os.WriteFile(filepath.Join(src.TemplateDir, "cfd.json"), []byte(`{"jobName":"cfd"}`), 0600)
+	os.WriteFile(filepath.Join(src.TemplateDir, "notes.txt"), []byte("not a template"), 0600)
+	if err := WriteTokenFile(src.TokenFile, "secret-key"); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := ExportBundle(src, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "interlink.bundle")
+	if err := WriteBundle(bundle, file); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err = ReadBundle(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.Files) != 3 || bundle.Secrets == nil {
+		t.Fatalf("bundle has files %v and secrets %v, want 3 files and sealed secrets", bundle.Files, bundle.Secrets)
+	}
+
+	dst := testBundlePaths(t.TempDir())
+	if _, err := ImportBundle(bundle, dst, "wrong", false); !errors.Is(err, ErrBundlePassphrase) {
+		t.Fatalf("ImportBundle() with a wrong passphrase = %v, want ErrBundlePassphrase", err)
+	}
+	if _, err := os.Stat(dst.ConfigFile); err == nil {
+		t.Fatal("files were written despite the wrong passphrase")
+	}
+
+	result, err := ImportBundle(bundle, dst, "correct horse", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Written) != 3 || len(result.SecretsRestored) != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	restored, err := LoadConfigCSV(dst.ConfigFile)
+	if err != nil || restored.TarWorkers != 7 || restored.SMTPPassword != "mail-pass" {
+		t.Errorf("restored config: %v, %v", restored, err)
+	}
+	if token, _ := ReadTokenFile(dst.TokenFile); token != "secret-key" {
+		t.Errorf("restored token = %q", token)
+	}
+
+	// Existing files are kept unless overwrite is set
+	os.WriteFile(filepath.Join(dst.TemplateDir, "cfd.json"), []byte("{}"), 0600)
+	result, err = ImportBundle(bundle, dst, "correct horse", false)
+	if err != nil || len(result.Written) != 0 || len(result.Skipped) != 5 {
+		t.Errorf("second import = %+v, %v, want everything skipped", result, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst.TemplateDir, "cfd.json")); string(data) != "{}" {
+		t.Error("existing template was overwritten")
+	}
+}
+
+func TestExportBundle_WithoutPassphraseOmitsSecrets(t *testing.T) {
+	src := testBundlePaths(t.TempDir())
+	WriteTokenFile(src.TokenFile, "secret-key")
+
+	bundle, err := ExportBundle(src, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Secrets != nil || len(bundle.SecretsOmitted) != 1 || bundle.SecretsOmitted[0] != SecretAPIToken {
+		t.Errorf("secrets = %v, omitted = %v", bundle.Secrets, bundle.SecretsOmitted)
+	}
+}
+
+func TestReadBundle_RejectsUnexpectedEntries(t *testing.T) {
+	for _, name := range []string{"token", "../config.csv", "templates/../../x.json", "templates/a/b.json", "templates/.json"} {
+		bundle := &Bundle{Version: BundleVersion, Files: map[string][]byte{name: nil}}
+		file := filepath.Join(t.TempDir(), "b.bundle")
+		WriteBundle(bundle, file)
+		if _, err := ReadBundle(file); err == nil {
+			t.Errorf("ReadBundle() accepted entry %q", name)
+		}
+	}
+}
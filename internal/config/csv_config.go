@@ -10,6 +10,10 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/util/tags"
 )
 
 // Config represents the application configuration for Rescale Interlink.
@@ -20,7 +24,7 @@ type Config struct {
 	JobWorkers    int
 
 	// Proxy settings
-	ProxyMode     string // "no-proxy", "ntlm" when supported by build, "basic", "system"
+	ProxyMode     string // "no-proxy", "ntlm" when supported by build, "negotiate", "basic", "system"
 	ProxyHost     string
 	ProxyPort     int
 	ProxyUser     string
@@ -35,6 +39,20 @@ type Config struct {
 	// Rescale tenant URL (for v2/v3 API calls)
 	TenantURL string
 
+	// API gateway deployments: a path prefix the gateway serves the API
+	// under (e.g. "/rescale"), and static headers sent with every API call,
+	// as semicolon-separated "Name: value" pairs. See ParseAPIHeaders.
+	// APIHeaders may hold credentials, so Records leaves it out.
+	APIBasePath string
+	APIHeaders  string
+
+	// Request timeouts, in seconds, per API endpoint class (see
+	// APIEndpointClass); 0 uses the class default. Use APITimeout to read.
+	APITimeoutCatalog  int
+	APITimeoutListing  int
+	APITimeoutMutation int
+	APITimeoutStatus   int
+
 	// Tarball options
 	ExcludePatterns   []string // Patterns to exclude from tarballs (e.g., *.log, *.tmp)
 	IncludePatterns   []string // Include-only patterns (mutually exclusive with exclude)
@@ -45,8 +63,47 @@ type Config struct {
 	// Tar compression
 	TarCompression string // "none" or "gzip" (normalized from legacy "gz")
 
+	// Split each run directory into several tars that are uploaded in
+	// parallel and all attached to the job: "none" (default), "subdirs" (one
+	// tar per top-level subdirectory) or "size" (TarSplitParts tars of
+	// similar size). See tar.PlanSplit.
+	TarSplitMode  string
+	TarSplitParts int
+
+	// Input quiescence: before tarring, wait until no file going into the
+	// archive has changed for SettleSeconds (0, the default, disables the
+	// check). A job whose inputs are still changing after SettleTimeoutSeconds
+	// fails instead of archiving a partially written file.
+	SettleSeconds        int
+	SettleTimeoutSeconds int
+
+	// Files another process holds locked while tarring (Windows): "fail"
+	// (default), "skip" (leave out and record in the state file), "retry"
+	// (TarLockRetries more attempts, TarLockRetrySeconds apart) or
+	// "snapshot" (read from a Volume Shadow Copy; skip without admin rights).
+	TarLockedFiles      string
+	TarLockRetrySeconds int
+	TarLockRetries      int
+
+	// Line endings and byte order marks of text inputs while tarring:
+	// "off" (default), "check" (report files with CRLF line endings or a
+	// UTF-8 BOM) or "fix" (also archive them with LF endings and no BOM;
+	// the files on disk are not changed). Only files whose extension is in
+	// TextNormalizeExtensions (default
+	// constants.DefaultTextNormalizeExtensions) are checked. Jobs opt out
+	// with NoTextNormalize.
+	TextNormalize           string
+	TextNormalizeExtensions []string
+
 	// Retry settings
 	MaxRetries int // Maximum upload retry attempts (default: 1)
+	// PartRetries is the attempt budget for each upload part before the file
+	// fails; errors the storage provider reports as permanent fail at once.
+	PartRetries int
+
+	// LargeUploadConfirmGB is the total upload size, in GB, above which the
+	// CLI and GUI ask for confirmation before starting. 0 disables the check.
+	LargeUploadConfirmGB int
 
 	// Upload conflict detection mode
 	// CheckConflictsBeforeUpload controls how file upload conflicts are detected.
@@ -69,27 +126,202 @@ type Config struct {
 	SortField     string // "name", "size", "modified" (default: "name")
 	SortAscending bool   // true = ascending, false = descending (default: true)
 
+	// User-defined local browser roots, shown alongside drives and mounts
+	LocalRoots []string
+
 	// Detailed logging toggle for timing/metrics in Activity tab
 	DetailedLogging bool
 
+	// Process log format: "text" (default) or "json" for newline-delimited
+	// JSON records. --log-format overrides it. See package logging.
+	LogFormat string
+
 	// Organization code for org-scoped project assignment
 	OrgCode string
+
+	// Workspace ID that file browsing, uploads and job submission are scoped
+	// to, for users in several workspaces. Empty = the API key's default
+	// workspace.
+	Workspace string
+
+	// Workspace-level tags added to every job at creation, on top of each
+	// job's own tags. Supports {version} (Interlink version) and {run_id}
+	// placeholders, e.g. "interlink-{version}".
+	DefaultTags []string
+
+	// Root directory for PUR run state files (empty = ~/.rescale-int/states).
+	// Point it at a shared project drive so teammates can monitor and resume
+	// each other's runs; concurrent writers are serialized with lock files.
+	StateDir string
+
+	// Default root for GUI and `jobs download` downloads (empty = the local
+	// browser's current folder / the working directory).
+	DownloadDir string
+
+	// How downloads are grouped under the download root: "none", "job"
+	// (one subfolder per job), "date" (YYYY-MM-DD) or "date-job".
+	DownloadOrganize string
+
+	// Overwrite Interlink-created temp files (staging tars, .encrypted
+	// copies) before deleting them. See securedelete.Limitations.
+	SecureDelete bool
+
+	// After each upload completes, read back the final ciphertext block and
+	// a few random ones from storage and check them against hashes kept
+	// during encryption. Costs extra GETs; meant for critical datasets.
+	UploadReadbackVerify bool
+
+	// Share of logical CPUs (10-100%) encryption may use, and whether to
+	// halve it while on battery. Keeps laptops usable during large uploads.
+	CPUBudgetPercent   int
+	CPUReduceOnBattery bool
+
+	// How job specs are screened for traversal and command injection before
+	// submission: "permissive" (default; suspicious constructs are warnings)
+	// or "strict" (every finding blocks the job).
+	JobValidationMode string
+
+	// What a run does about job names it would duplicate (within the run or
+	// among the user's recent jobs): "warn" (default), "suffix" (rename with
+	// the run ID or a timestamp) or "block".
+	JobNamePolicy string
+
+	// What a new run does when the same jobs and inputs were run recently
+	// (see pur/runhistory): "warn" (default), "block" or "off".
+	DuplicateRunPolicy string
+
+	// Where per-job metadata (meta_* jobs CSV columns) is written when a job
+	// is created: "description" (default), "custom_fields" or "both".
+	JobMetadataTarget string
+
+	// Remote folder path under My Library that files sent from the OS
+	// context menu ("Send to Rescale Interlink") are uploaded into. Empty
+	// uploads to My Library itself.
+	SendToFolder string
+
+	// Self-update source: the HTTPS URL of the release manifest on an
+	// internal distribution server, and the PEM file holding the ECDSA P-256
+	// public key its binaries are signed with. Both must be set for
+	// `self-update` to work. See package selfupdate.
+	UpdateURL       string
+	UpdatePublicKey string
+
+	// Disable self-update entirely (enterprise-managed installs).
+	SelfUpdateDisabled bool
+
+	// Show the Team Admin tab in the GUI (org admins). AdminJobTag is the tag
+	// that marks team members' jobs as submitted via Interlink; empty means
+	// constants.DefaultAdminJobTag.
+	AdminMode   bool
+	AdminJobTag string
+
+	// Read-only mode for shared monitoring stations: runs and files can be
+	// viewed, but nothing is uploaded, downloaded, deleted or submitted.
+	// RESCALE_VIEWER_MODE=true forces it on. See package viewer.
+	ViewerMode bool
+
+	// Disk budget for Interlink's caches (staged tars, resume sidecars, the
+	// chunk index), in MB; 0 means unlimited. CacheLimits caps single
+	// categories, e.g. "tars=20480,chunks=512". Least recently used entries
+	// are evicted first. See package cache.
+	CacheMaxMB  int
+	CacheLimits string
+
+	// Daily local-time windows during which tar and upload work pauses, e.g.
+	// "01:00-03:00;22:30-23:00" for NAS backups. In-flight upload parts
+	// finish; work resumes when the window ends. See resources.ParseBlackoutWindows.
+	BlackoutWindows string
+
+	// Steps run on every file the Transfers queue downloads, unless the
+	// request sets its own, e.g. "untar;checksum;script:/opt/bin/index.sh".
+	// See package postprocess.
+	PostDownload string
+
+	// Shared repository of job templates and run recipes curated by the
+	// team: a folder (e.g. on a network share) or a git URL, optionally with
+	// "#<tag or branch>" to pin it. See package templaterepo.
+	TemplateRepo string
+
+	// OTLP/HTTP collector that traces of pipeline runs, transfers and API
+	// calls are exported to, e.g. "http://localhost:4318". Empty = tracing
+	// off unless the OTEL_EXPORTER_OTLP_* environment sets one. See package
+	// tracing.
+	OTelEndpoint string
+
+	// Webhook URLs that PUR run events are POSTed to as JSON, and which
+	// events: "state_change", "complete" and/or "error" (empty = all three).
+	// See package notify.
+	WebhookURLs   []string
+	WebhookEvents []string
+
+	// Key for the X-Interlink-Signature HMAC on webhook requests; empty
+	// sends them unsigned. Persisted in the secrets file (see
+	// SecretsFileName), not config.csv.
+	WebhookSecret string
+
+	// Slack and Microsoft Teams incoming-webhook URLs for chat messages
+	// about runs. The URLs embed the posting credential, so they are
+	// persisted like WebhookSecret.
+	SlackWebhookURL string
+	TeamsWebhookURL string
+
+	// Which chat messages are sent: "run_started", "run_completed" and/or
+	// "job_failed" (empty = all three), and optional text/template
+	// overrides of their default text. See package notify.
+	ChatEvents               []string
+	ChatTemplateRunStarted   string
+	ChatTemplateRunCompleted string
+	ChatTemplateJobFailed    string
+
+	// SMTP server that a summary email is sent through when a PUR run
+	// completes, to the EmailTo recipients. Port 0 means 587 (STARTTLS);
+	// 465 uses implicit TLS. Empty host or recipients = no email. The
+	// password is persisted like WebhookSecret. See package notify.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string // Empty = SMTPUsername
+	EmailTo      []string
 }
 
+// Defaults for the pre-tar input quiescence check, off unless settle_seconds
+// is set.
+const (
+	DefaultSettleSeconds        = 0
+	DefaultSettleTimeoutSeconds = 600
+)
+
+// DefaultLargeUploadConfirmGB is the default size threshold for the
+// large-upload confirmation.
+const DefaultLargeUploadConfirmGB = 500
+
 // LoadConfigCSV loads configuration from a CSV file
 // CSV format: key,value pairs
 func LoadConfigCSV(path string) (*Config, error) {
+	workers := resources.DefaultPipelineWorkers()
 	cfg := &Config{
-		TarWorkers:        4,
-		UploadWorkers:     4,
-		JobWorkers:        4,
-		ProxyMode:         "no-proxy",
-		APIBaseURL:        "https://platform.rescale.com",
-		ValidationPattern: "", // validation is opt-in, disabled by default
-		TarCompression:    "none",
-		MaxRetries:        1,
-		SortField:         "name",
-		SortAscending:     true,
+		TarWorkers:           workers,
+		UploadWorkers:        workers,
+		JobWorkers:           workers,
+		ProxyMode:            "no-proxy",
+		APIBaseURL:           "https://platform.rescale.com",
+		ValidationPattern:    "", // validation is opt-in, disabled by default
+		TarCompression:       "none",
+		TarSplitMode:         "none",
+		TarSplitParts:        constants.DefaultTarSplitParts,
+		CPUBudgetPercent:     constants.DefaultCPUBudgetPercent,
+		CPUReduceOnBattery:   true,
+		SettleSeconds:        DefaultSettleSeconds,
+		SettleTimeoutSeconds: DefaultSettleTimeoutSeconds,
+		TarLockedFiles:       "fail",
+		TarLockRetrySeconds:  constants.DefaultTarLockRetrySeconds,
+		TarLockRetries:       constants.DefaultTarLockRetries,
+		MaxRetries:           1,
+		PartRetries:          constants.MaxRetries,
+		LargeUploadConfirmGB: DefaultLargeUploadConfirmGB,
+		SortField:            "name",
+		SortAscending:        true,
 	}
 
 	if path == "" {
@@ -98,7 +330,11 @@ func LoadConfigCSV(path string) (*Config, error) {
 
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return cfg, nil // Return defaults if config doesn't exist
+		// Return defaults if config doesn't exist
+		if err := loadSecrets(cfg, path); err != nil {
+			return nil, err
+		}
+		return cfg, nil
 	}
 
 	file, err := os.Open(path)
@@ -180,6 +416,10 @@ func LoadConfigCSV(path string) (*Config, error) {
 			if value != "" {
 				cfg.APIBaseURL = value
 			}
+		case "api_base_path":
+			cfg.APIBasePath = value
+		case "api_headers":
+			cfg.APIHeaders = value
 		case "exclude_pattern":
 			// Parse semicolon-separated patterns
 			if value != "" {
@@ -204,18 +444,176 @@ func LoadConfigCSV(path string) (*Config, error) {
 			cfg.ValidationPattern = value
 		case "tar_compression":
 			cfg.TarCompression = value
+		case "tar_split_mode":
+			cfg.TarSplitMode = value
+		case "tar_split_parts":
+			if v, err := strconv.Atoi(value); err == nil && v >= 2 && v <= constants.MaxTarSplitParts {
+				cfg.TarSplitParts = v
+			}
+		case "settle_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.SettleSeconds = v
+			}
+		case "settle_timeout_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.SettleTimeoutSeconds = v
+			}
+		case "tar_locked_files":
+			cfg.TarLockedFiles = value
+		case "tar_lock_retry_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v >= 1 {
+				cfg.TarLockRetrySeconds = v
+			}
+		case "tar_lock_retries":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.TarLockRetries = v
+			}
+		case "text_normalize":
+			cfg.TextNormalize = value
+		case "text_normalize_extensions":
+			// Parse semicolon-separated extensions
+			for _, ext := range strings.Split(value, ";") {
+				if ext = strings.TrimSpace(ext); ext != "" {
+					cfg.TextNormalizeExtensions = append(cfg.TextNormalizeExtensions, ext)
+				}
+			}
 		case "max_retries":
 			if v, err := strconv.Atoi(value); err == nil {
 				cfg.MaxRetries = v
 			}
+		case "part_retries":
+			if v, err := strconv.Atoi(value); err == nil && v >= 1 {
+				cfg.PartRetries = v
+			}
+		case "large_upload_confirm_gb":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.LargeUploadConfirmGB = v
+			}
 		case "sort_field":
 			cfg.SortField = value
 		case "sort_ascending":
 			cfg.SortAscending = strings.ToLower(value) == "true" || value == "1"
 		case "detailed_logging":
 			cfg.DetailedLogging = strings.ToLower(value) == "true" || value == "1"
+		case "log_format":
+			cfg.LogFormat = strings.ToLower(value)
 		case "org_code":
 			cfg.OrgCode = value
+		case "workspace":
+			cfg.Workspace = value
+		case "local_roots":
+			// Parse semicolon-separated paths
+			if value != "" {
+				for _, root := range strings.Split(value, ";") {
+					if root = strings.TrimSpace(root); root != "" {
+						cfg.LocalRoots = append(cfg.LocalRoots, root)
+					}
+				}
+			}
+		case "state_dir":
+			cfg.StateDir = value
+		case "download_dir":
+			cfg.DownloadDir = value
+		case "download_organize":
+			cfg.DownloadOrganize = value
+		case "secure_delete":
+			cfg.SecureDelete = strings.ToLower(value) == "true" || value == "1"
+		case "upload_readback_verify":
+			cfg.UploadReadbackVerify = strings.ToLower(value) == "true" || value == "1"
+		case "cpu_budget_percent":
+			if v, err := strconv.Atoi(value); err == nil && v >= constants.MinCPUBudgetPercent && v <= 100 {
+				cfg.CPUBudgetPercent = v
+			}
+		case "cpu_reduce_on_battery":
+			cfg.CPUReduceOnBattery = strings.ToLower(value) == "true" || value == "1"
+		case "job_validation_mode":
+			cfg.JobValidationMode = value
+		case "job_name_policy":
+			cfg.JobNamePolicy = value
+		case "duplicate_run_policy":
+			cfg.DuplicateRunPolicy = value
+		case "job_metadata_target":
+			cfg.JobMetadataTarget = value
+		case "send_to_folder":
+			cfg.SendToFolder = value
+		case "update_url":
+			cfg.UpdateURL = value
+		case "update_public_key":
+			cfg.UpdatePublicKey = value
+		case "self_update_disabled":
+			cfg.SelfUpdateDisabled = strings.ToLower(value) == "true" || value == "1"
+		case "admin_mode":
+			cfg.AdminMode = strings.ToLower(value) == "true" || value == "1"
+		case "viewer_mode":
+			cfg.ViewerMode = strings.ToLower(value) == "true" || value == "1"
+		case "cache_max_mb":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.CacheMaxMB = v
+			}
+		case "cache_limits":
+			cfg.CacheLimits = value
+		case "blackout_windows":
+			cfg.BlackoutWindows = value
+		case "post_download":
+			cfg.PostDownload = value
+		case "template_repo":
+			cfg.TemplateRepo = value
+		case "otel_endpoint":
+			cfg.OTelEndpoint = value
+		case "webhook_urls":
+			// Parse semicolon-separated URLs
+			for _, u := range strings.Split(value, ";") {
+				if u = strings.TrimSpace(u); u != "" {
+					cfg.WebhookURLs = append(cfg.WebhookURLs, u)
+				}
+			}
+		case "webhook_events":
+			for _, e := range strings.Split(value, ";") {
+				if e = strings.TrimSpace(e); e != "" {
+					cfg.WebhookEvents = append(cfg.WebhookEvents, e)
+				}
+			}
+		case "webhook_secret", "slack_webhook_url", "teams_webhook_url", "smtp_password":
+			// Written by older versions; the secrets file overrides them
+			// below and the next save moves them there
+			cfg.setSecret(key, value)
+		case "chat_events":
+			for _, e := range strings.Split(value, ";") {
+				if e = strings.TrimSpace(e); e != "" {
+					cfg.ChatEvents = append(cfg.ChatEvents, e)
+				}
+			}
+		case "chat_template_run_started":
+			cfg.ChatTemplateRunStarted = value
+		case "chat_template_run_completed":
+			cfg.ChatTemplateRunCompleted = value
+		case "chat_template_job_failed":
+			cfg.ChatTemplateJobFailed = value
+		case "smtp_host":
+			cfg.SMTPHost = value
+		case "smtp_port":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.SMTPPort = v
+			}
+		case "smtp_username":
+			cfg.SMTPUsername = value
+		case "email_from":
+			cfg.EmailFrom = value
+		case "email_to":
+			// Parse semicolon-separated addresses
+			for _, a := range strings.Split(value, ";") {
+				if a = strings.TrimSpace(a); a != "" {
+					cfg.EmailTo = append(cfg.EmailTo, a)
+				}
+			}
+		case "api_timeout_catalog", "api_timeout_listing", "api_timeout_mutation", "api_timeout_status":
+			if v, err := strconv.Atoi(value); err == nil {
+				*cfg.apiTimeoutField(APIEndpointClass(strings.TrimPrefix(key, "api_timeout_"))) = v
+			}
+		case "admin_job_tag":
+			cfg.AdminJobTag = value
+		case "default_tags":
+			cfg.DefaultTags = tags.ParseSemicolonSeparated(value)
 		}
 	}
 
@@ -233,6 +631,10 @@ func LoadConfigCSV(path string) (*Config, error) {
 		return nil, fmt.Errorf("include_pattern and exclude_pattern are mutually exclusive")
 	}
 
+	if err := loadSecrets(cfg, path); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -241,6 +643,14 @@ func LoadConfigCSV(path string) (*Config, error) {
 //
 //	Persisted to disk (strict permissions):
 //	  - API key → token file (owner-only ACL via WriteTokenFile).
+//	  - Webhook signing key (webhook_secret), Slack/Teams webhook URLs
+//	    (slack_webhook_url, teams_webhook_url) and SMTP password
+//	    (smtp_password) → secrets file next to config.csv (see
+//	    SecretsFileName), owner-only via restrictToOwner.
+//	  - API gateway headers (api_headers, e.g. an org token) →
+//	    config.csv, which restrictToOwner makes owner-only while they are
+//	    set: mode 0600 on Unix, the same explicit ACL as the token file
+//	    on Windows.
 //	Never persisted, prompted per-session:
 //	  - Proxy password.
 //
@@ -263,11 +673,20 @@ func SaveConfigCSV(cfg *Config, path string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	if err := saveSecrets(cfg, path); err != nil {
+		return err
+	}
+
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
 	defer file.Close()
+	if cfg.APIHeaders != "" {
+		if err := restrictToOwner(path); err != nil {
+			return fmt.Errorf("failed to restrict config file permissions: %w", err)
+		}
+	}
 
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
@@ -277,14 +696,34 @@ func SaveConfigCSV(cfg *Config, path string) error {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
-	// Write all config fields
+	records := cfg.Records()
+	// Not in Records: gateway headers may carry an access token. The
+	// notification secrets go to the secrets file instead (saveSecrets).
+	records = append(records, []string{"api_headers", cfg.APIHeaders})
+
+	// Write ALL values unconditionally. A previous filter skipped "0", "false",
+	// and "" values, which silently reverted settings like sort_ascending=false,
+	// max_retries=0, and cleared proxy settings to defaults on reload.
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Records returns the key/value pairs SaveConfigCSV writes, in file order.
+// Secrets (api_key, proxy_password) are never included, so the result is safe
+// to log or attach to a support ticket.
+func (c *Config) Records() [][]string {
 	// SECURITY: api_key and proxy_password are intentionally NOT saved to config files
 	// API keys should be provided via RESCALE_API_KEY env var or --token-file flag
 	// Proxy passwords should be entered at runtime via secure prompt
 
 	// Symmetric sync: tenant_url is a legacy alias — keep in sync with api_base_url
-	apiBaseURL := cfg.APIBaseURL
-	tenantURL := cfg.TenantURL
+	apiBaseURL := c.APIBaseURL
+	tenantURL := c.TenantURL
 	if tenantURL == "" && apiBaseURL != "" {
 		tenantURL = apiBaseURL
 	}
@@ -293,42 +732,91 @@ func SaveConfigCSV(cfg *Config, path string) error {
 	}
 
 	records := [][]string{
-		{"tar_workers", strconv.Itoa(cfg.TarWorkers)},
-		{"upload_workers", strconv.Itoa(cfg.UploadWorkers)},
-		{"job_workers", strconv.Itoa(cfg.JobWorkers)},
-		{"proxy_mode", cfg.ProxyMode},
-		{"proxy_host", cfg.ProxyHost},
-		{"proxy_port", strconv.Itoa(cfg.ProxyPort)},
-		{"proxy_user", cfg.ProxyUser},
+		{"tar_workers", strconv.Itoa(c.TarWorkers)},
+		{"upload_workers", strconv.Itoa(c.UploadWorkers)},
+		{"job_workers", strconv.Itoa(c.JobWorkers)},
+		{"proxy_mode", c.ProxyMode},
+		{"proxy_host", c.ProxyHost},
+		{"proxy_port", strconv.Itoa(c.ProxyPort)},
+		{"proxy_user", c.ProxyUser},
 		// proxy_password intentionally omitted for security
-		{"no_proxy", cfg.NoProxy},
-		{"proxy_warmup", strconv.FormatBool(cfg.ProxyWarmup)},
+		{"no_proxy", c.NoProxy},
+		{"proxy_warmup", strconv.FormatBool(c.ProxyWarmup)},
 		// api_key intentionally omitted for security
 		{"api_base_url", apiBaseURL},
 		{"tenant_url", tenantURL},
-		{"exclude_pattern", strings.Join(cfg.ExcludePatterns, ";")},
-		{"include_pattern", strings.Join(cfg.IncludePatterns, ";")},
-		{"flatten_tar", strconv.FormatBool(cfg.FlattenTar)},
-		{"run_subpath", cfg.RunSubpath},
-		{"validation_pattern", cfg.ValidationPattern},
-		{"tar_compression", cfg.TarCompression},
-		{"max_retries", strconv.Itoa(cfg.MaxRetries)},
-		{"sort_field", cfg.SortField},
-		{"sort_ascending", strconv.FormatBool(cfg.SortAscending)},
-		{"detailed_logging", strconv.FormatBool(cfg.DetailedLogging)},
-		{"org_code", cfg.OrgCode},
-	}
-
-	// Write ALL values unconditionally. A previous filter skipped "0", "false",
-	// and "" values, which silently reverted settings like sort_ascending=false,
-	// max_retries=0, and cleared proxy settings to defaults on reload.
-	for _, record := range records {
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write record: %w", err)
-		}
-	}
-
-	return nil
+		{"api_base_path", c.APIBasePath},
+		// api_headers omitted: may hold credentials (SaveConfigCSV writes it)
+		{"exclude_pattern", strings.Join(c.ExcludePatterns, ";")},
+		{"include_pattern", strings.Join(c.IncludePatterns, ";")},
+		{"flatten_tar", strconv.FormatBool(c.FlattenTar)},
+		{"run_subpath", c.RunSubpath},
+		{"validation_pattern", c.ValidationPattern},
+		{"tar_compression", c.TarCompression},
+		{"tar_split_mode", c.TarSplitMode},
+		{"tar_split_parts", strconv.Itoa(c.TarSplitParts)},
+		{"settle_seconds", strconv.Itoa(c.SettleSeconds)},
+		{"settle_timeout_seconds", strconv.Itoa(c.SettleTimeoutSeconds)},
+		{"tar_locked_files", c.TarLockedFiles},
+		{"tar_lock_retry_seconds", strconv.Itoa(c.TarLockRetrySeconds)},
+		{"tar_lock_retries", strconv.Itoa(c.TarLockRetries)},
+		{"text_normalize", c.TextNormalize},
+		{"text_normalize_extensions", strings.Join(c.TextNormalizeExtensions, ";")},
+		{"max_retries", strconv.Itoa(c.MaxRetries)},
+		{"part_retries", strconv.Itoa(c.PartRetries)},
+		{"large_upload_confirm_gb", strconv.Itoa(c.LargeUploadConfirmGB)},
+		{"sort_field", c.SortField},
+		{"sort_ascending", strconv.FormatBool(c.SortAscending)},
+		{"local_roots", strings.Join(c.LocalRoots, ";")},
+		{"detailed_logging", strconv.FormatBool(c.DetailedLogging)},
+		{"log_format", c.LogFormat},
+		{"org_code", c.OrgCode},
+		{"workspace", c.Workspace},
+		{"default_tags", tags.JoinSemicolonSeparated(c.DefaultTags)},
+		{"state_dir", c.StateDir},
+		{"download_dir", c.DownloadDir},
+		{"download_organize", c.DownloadOrganize},
+		{"secure_delete", strconv.FormatBool(c.SecureDelete)},
+		{"upload_readback_verify", strconv.FormatBool(c.UploadReadbackVerify)},
+		{"cpu_budget_percent", strconv.Itoa(c.CPUBudgetPercent)},
+		{"cpu_reduce_on_battery", strconv.FormatBool(c.CPUReduceOnBattery)},
+		{"job_validation_mode", c.JobValidationMode},
+		{"job_name_policy", c.JobNamePolicy},
+		{"duplicate_run_policy", c.DuplicateRunPolicy},
+		{"job_metadata_target", c.JobMetadataTarget},
+		{"send_to_folder", c.SendToFolder},
+		{"update_url", c.UpdateURL},
+		{"update_public_key", c.UpdatePublicKey},
+		{"self_update_disabled", strconv.FormatBool(c.SelfUpdateDisabled)},
+		{"admin_mode", strconv.FormatBool(c.AdminMode)},
+		{"admin_job_tag", c.AdminJobTag},
+		{"viewer_mode", strconv.FormatBool(c.ViewerMode)},
+		{"cache_max_mb", strconv.Itoa(c.CacheMaxMB)},
+		{"cache_limits", c.CacheLimits},
+		{"blackout_windows", c.BlackoutWindows},
+		{"post_download", c.PostDownload},
+		{"template_repo", c.TemplateRepo},
+		{"otel_endpoint", c.OTelEndpoint},
+		{"webhook_urls", strings.Join(c.WebhookURLs, ";")},
+		{"webhook_events", strings.Join(c.WebhookEvents, ";")},
+		// webhook_secret omitted: SaveConfigCSV writes it to the secrets file
+		// slack_webhook_url, teams_webhook_url omitted: likewise
+		{"chat_events", strings.Join(c.ChatEvents, ";")},
+		{"chat_template_run_started", c.ChatTemplateRunStarted},
+		{"chat_template_run_completed", c.ChatTemplateRunCompleted},
+		{"chat_template_job_failed", c.ChatTemplateJobFailed},
+		{"smtp_host", c.SMTPHost},
+		{"smtp_port", strconv.Itoa(c.SMTPPort)},
+		{"smtp_username", c.SMTPUsername},
+		// smtp_password omitted: SaveConfigCSV writes it to the secrets file
+		{"email_from", c.EmailFrom},
+		{"email_to", strings.Join(c.EmailTo, ";")},
+		{"api_timeout_catalog", strconv.Itoa(c.APITimeoutCatalog)},
+		{"api_timeout_listing", strconv.Itoa(c.APITimeoutListing)},
+		{"api_timeout_mutation", strconv.Itoa(c.APITimeoutMutation)},
+		{"api_timeout_status", strconv.Itoa(c.APITimeoutStatus)},
+	}
+	return records
 }
 
 // MergeWithFlags merges config with command-line flags and environment variables
@@ -468,6 +956,12 @@ func (c *Config) Validate() error {
 	if err := ValidatePlatformURL(c.APIBaseURL); err != nil {
 		return fmt.Errorf("invalid platform URL %q: %w", c.APIBaseURL, err)
 	}
+	if err := c.validateGateway(); err != nil {
+		return err
+	}
+	if err := c.validateAPITimeouts(); err != nil {
+		return err
+	}
 	if c.TarWorkers < 1 {
 		return fmt.Errorf("tar_workers must be at least 1")
 	}
@@ -626,6 +1120,96 @@ func GetConfigPathForProfile(userProfilePath string) string {
 	return filepath.Join(userProfilePath, ".config", ConfigDir, "config.csv")
 }
 
+// GetUploadHistoryPath returns the upload history log for the platform at
+// apiBaseURL (one log per platform host, under the config directory).
+func GetUploadHistoryPath(apiBaseURL string) string {
+	return filepath.Join(getConfigDir(), "history", platformFileName(apiBaseURL)+".jsonl")
+}
+
+// GetRunHistoryPath returns the PUR run history log for the platform at
+// apiBaseURL (one log per platform host, next to the upload history).
+func GetRunHistoryPath(apiBaseURL string) string {
+	return filepath.Join(getConfigDir(), "history", platformFileName(apiBaseURL)+".runs.jsonl")
+}
+
+// GetRetentionQueuePath returns the pending end-of-run retention actions for
+// the platform at apiBaseURL (next to the run history).
+func GetRetentionQueuePath(apiBaseURL string) string {
+	return filepath.Join(getConfigDir(), "history", platformFileName(apiBaseURL)+".retention.json")
+}
+
+// GetRetentionAuditPath returns the audit log of retention actions carried
+// out for the platform at apiBaseURL (next to the run history).
+func GetRetentionAuditPath(apiBaseURL string) string {
+	return filepath.Join(getConfigDir(), "history", platformFileName(apiBaseURL)+".retention-audit.jsonl")
+}
+
+// GetJobHistoryPath returns the log of jobs submitted by PUR runs on the
+// platform at apiBaseURL, used for runtime predictions (next to the run
+// history).
+func GetJobHistoryPath(apiBaseURL string) string {
+	return filepath.Join(getConfigDir(), "history", platformFileName(apiBaseURL)+".jobs.jsonl")
+}
+
+// GetNetworkUsagePath returns the log of network bytes sent and received by
+// this machine, across platforms (next to the upload histories).
+func GetNetworkUsagePath() string {
+	return filepath.Join(getConfigDir(), "history", "network.jsonl")
+}
+
+// GetTransferStateDir returns the directory holding the progress of
+// interrupted multipart uploads, so they resume instead of restarting.
+func GetTransferStateDir() string {
+	return filepath.Join(getConfigDir(), "transfers")
+}
+
+// GetJournalDir returns the directory holding the change journal: the
+// registered roots and their scan snapshots.
+func GetJournalDir() string {
+	return filepath.Join(getConfigDir(), "journal")
+}
+
+// GetManifestDir returns the central store for folder upload manifests
+// kept outside the uploaded folders.
+func GetManifestDir() string {
+	return filepath.Join(getConfigDir(), "manifests")
+}
+
+// GetTemplateRepoCacheDir returns where clones of git template repositories
+// are kept.
+func GetTemplateRepoCacheDir() string {
+	return filepath.Join(getConfigDir(), "template-repo")
+}
+
+// GetHardwareFavoritesPath returns the user's starred core types, shared by
+// `hardware list` and the GUI core type picker.
+func GetHardwareFavoritesPath() string {
+	return filepath.Join(getConfigDir(), "hardware-favorites.json")
+}
+
+// GetRunLogDir returns the directory holding one log file per PUR run.
+func GetRunLogDir() string {
+	return filepath.Join(getConfigDir(), "logs", "runs")
+}
+
+// GetRunLogPath returns the log file of the PUR run runID.
+func GetRunLogPath(runID string) string {
+	return filepath.Join(GetRunLogDir(), runID+".jsonl")
+}
+
+// platformFileName turns the host of apiBaseURL into a safe file name.
+func platformFileName(apiBaseURL string) string {
+	host := apiBaseURL
+	if u, err := url.Parse(apiBaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(host)
+	if host == "" {
+		host = "default"
+	}
+	return host
+}
+
 // GetDefaultTokenPath returns the default token file path
 // - Windows: %APPDATA%\Rescale\Interlink\token (standard Windows location)
 // - Unix: ~/.config/rescale/token (XDG standard)
@@ -700,7 +1284,7 @@ func WriteTokenFile(path, token string) error {
 	}
 
 	// Write token with secure permissions (0600 = owner read/write only).
-	// On Windows, 0600 is honored via a coarser ACL — applyTokenFileACL
+	// On Windows, 0600 is honored via a coarser ACL — restrictToOwner
 	// below tightens it to the explicit (owner, Administrators, SYSTEM)
 	// model required by spec §11.2.
 	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
@@ -709,19 +1293,32 @@ func WriteTokenFile(path, token string) error {
 
 	// os.WriteFile only applies the 0600 mode when creating the file; if the
 	// token file already existed with looser permissions (e.g. 0644), the
-	// overwrite preserves that mode. Explicitly chmod to repair it. On Windows
-	// the meaningful protection is the ACL applied below, so a chmod error
-	// there is non-fatal.
-	if err := os.Chmod(path, 0600); err != nil && runtime.GOOS != "windows" {
+	// overwrite preserves that mode. restrictToOwner repairs it.
+	if err := restrictToOwner(path); err != nil {
 		return fmt.Errorf("failed to set token file permissions: %w", err)
 	}
 
+	return nil
+}
+
+// restrictToOwner makes the file at path readable by its owner only: mode
+// 0600 on Unix, and on Windows an explicit (owner, Administrators, SYSTEM)
+// ACL via applyTokenFileACL, since Go's chmod there only toggles the
+// read-only attribute. Files holding persisted secrets (see SaveConfigCSV)
+// go through it.
+func restrictToOwner(path string) error {
+	// On Windows the meaningful protection is the ACL applied below, so a
+	// chmod error there is non-fatal.
+	if err := os.Chmod(path, 0600); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+
 	// Best-effort explicit-ACL tightening on Windows. A failure here does
 	// NOT block the write — the file has already been written with Go's
 	// default permissions, which is no worse than pre-Plan-4 behavior.
 	sid, sidErr := currentUserSID()
 	if sidErr != nil {
-		log.Printf("[WARN] could not capture current user SID for token ACL: %v", sidErr)
+		log.Printf("[WARN] could not capture current user SID for ACL on %s: %v", path, sidErr)
 		return nil
 	}
 	if sid == "" {
@@ -729,7 +1326,7 @@ func WriteTokenFile(path, token string) error {
 		return nil
 	}
 	if aclErr := applyTokenFileACL(path, sid); aclErr != nil {
-		log.Printf("[WARN] could not apply explicit ACL to token file %s: %v", path, aclErr)
+		log.Printf("[WARN] could not apply explicit ACL to %s: %v", path, aclErr)
 	}
 
 	return nil
@@ -2,9 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfigCSV(t *testing.T) {
@@ -616,3 +618,122 @@ func TestWriteTokenFile_RepairsLoosePermissions(t *testing.T) {
 		t.Errorf("token file perms = %o, want 0600", perm)
 	}
 }
+
+// TestSettleSettingsRoundTrip verifies the input quiescence settings persist,
+// including an explicit 0 that disables the check.
+func TestSettleSettingsRoundTrip(t *testing.T) {
+	csvPath := t.TempDir() + "/config.csv"
+
+	original := &Config{
+		ProxyMode:            "no-proxy",
+		APIBaseURL:           "https://platform.rescale.com",
+		SettleSeconds:        0,
+		SettleTimeoutSeconds: 120,
+	}
+	if err := SaveConfigCSV(original, csvPath); err != nil {
+		t.Fatalf("SaveConfigCSV() error = %v", err)
+	}
+
+	loaded, err := LoadConfigCSV(csvPath)
+	if err != nil {
+		t.Fatalf("LoadConfigCSV() error = %v", err)
+	}
+	if loaded.SettleSeconds != 0 || loaded.SettleTimeoutSeconds != 120 {
+		t.Errorf("settle settings = %d/%d, want 0/120", loaded.SettleSeconds, loaded.SettleTimeoutSeconds)
+	}
+
+	defaults, _ := LoadConfigCSV("")
+	if defaults.SettleSeconds != DefaultSettleSeconds || defaults.SettleTimeoutSeconds != DefaultSettleTimeoutSeconds {
+		t.Errorf("default settle settings = %d/%d", defaults.SettleSeconds, defaults.SettleTimeoutSeconds)
+	}
+}
+
+// TestLargeUploadConfirmRoundTrip verifies the large-upload threshold
+// persists, including an explicit 0 that turns the confirmation off.
+func TestLargeUploadConfirmRoundTrip(t *testing.T) {
+	csvPath := t.TempDir() + "/config.csv"
+
+	if err := SaveConfigCSV(&Config{ProxyMode: "no-proxy", LargeUploadConfirmGB: 0}, csvPath); err != nil {
+		t.Fatalf("SaveConfigCSV() error = %v", err)
+	}
+	loaded, err := LoadConfigCSV(csvPath)
+	if err != nil {
+		t.Fatalf("LoadConfigCSV() error = %v", err)
+	}
+	if loaded.LargeUploadConfirmGB != 0 {
+		t.Errorf("LargeUploadConfirmGB = %d, want 0", loaded.LargeUploadConfirmGB)
+	}
+
+	defaults, _ := LoadConfigCSV("")
+	if defaults.LargeUploadConfirmGB != DefaultLargeUploadConfirmGB {
+		t.Errorf("default LargeUploadConfirmGB = %d", defaults.LargeUploadConfirmGB)
+	}
+}
+
+func TestStateDirRoundTrip(t *testing.T) {
+	csvPath := t.TempDir() + "/config.csv"
+	shared := filepath.Join(t.TempDir(), "team", "states")
+
+	if err := SaveConfigCSV(&Config{ProxyMode: "no-proxy", StateDir: shared}, csvPath); err != nil {
+		t.Fatalf("SaveConfigCSV() error = %v", err)
+	}
+	loaded, err := LoadConfigCSV(csvPath)
+	if err != nil {
+		t.Fatalf("LoadConfigCSV() error = %v", err)
+	}
+	if loaded.StateDir != shared {
+		t.Errorf("StateDir = %q, want %q", loaded.StateDir, shared)
+	}
+	if got := StateDirectory(loaded); got != shared {
+		t.Errorf("StateDirectory() = %q, want %q", got, shared)
+	}
+
+	home, _ := os.UserHomeDir()
+	if got, want := StateDirectory(&Config{}), filepath.Join(home, ".rescale-int", "states"); got != want {
+		t.Errorf("default StateDirectory() = %q, want %q", got, want)
+	}
+	if got, want := StateDirectory(&Config{StateDir: "~/runs"}), filepath.Join(home, "runs"); got != want {
+		t.Errorf("StateDirectory(~/runs) = %q, want %q", got, want)
+	}
+}
+
+func TestDownloadDirectory(t *testing.T) {
+	csvPath := t.TempDir() + "/config.csv"
+	root := filepath.Join(t.TempDir(), "results")
+
+	if err := SaveConfigCSV(&Config{ProxyMode: "no-proxy", DownloadDir: root, DownloadOrganize: DownloadOrganizeDateJob}, csvPath); err != nil {
+		t.Fatalf("SaveConfigCSV() error = %v", err)
+	}
+	loaded, err := LoadConfigCSV(csvPath)
+	if err != nil {
+		t.Fatalf("LoadConfigCSV() error = %v", err)
+	}
+	if loaded.DownloadDir != root || loaded.DownloadOrganize != DownloadOrganizeDateJob {
+		t.Fatalf("download settings = %q/%q", loaded.DownloadDir, loaded.DownloadOrganize)
+	}
+
+	when := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		mode  string
+		label string
+		want  string
+	}{
+		{DownloadOrganizeNone, "wing_run", root},
+		{"", "wing_run", root},
+		{DownloadOrganizeJob, "wing_run", filepath.Join(root, "wing_run")},
+		{DownloadOrganizeJob, "a/b: c?", filepath.Join(root, "a_b_ c_")},
+		{DownloadOrganizeJob, "", root},
+		{DownloadOrganizeDate, "wing_run", filepath.Join(root, "2026-03-14")},
+		{DownloadOrganizeDateJob, "wing_run", filepath.Join(root, "2026-03-14", "wing_run")},
+	}
+	for _, tt := range tests {
+		cfg := &Config{DownloadDir: root, DownloadOrganize: tt.mode}
+		if got := DownloadDirectory(cfg, tt.label, when); got != tt.want {
+			t.Errorf("DownloadDirectory(%q, %q) = %q, want %q", tt.mode, tt.label, got, tt.want)
+		}
+	}
+
+	if got := DownloadDirectory(&Config{DownloadOrganize: DownloadOrganizeJob}, "wing_run", when); got != "" {
+		t.Errorf("DownloadDirectory() without root = %q, want empty", got)
+	}
+}
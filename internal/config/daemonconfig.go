@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"gopkg.in/ini.v1"
@@ -64,6 +65,13 @@ const (
 //	show_download_complete = true
 //	show_download_failed = true
 //
+//	[watchdog]
+//	enabled = false
+//	action = notify          # notify | terminate
+//	max_hours = 0            # wall-clock cap since the job started executing (0 = off)
+//	max_cost = 0             # estimated cost cap (0 = off)
+//	protected = jobId1,nightly-*
+//
 // Note: Mode (Enabled/Conditional/Disabled) is now set per-job via the
 // "Auto Download" custom field in the Rescale workspace, not in this config.
 type DaemonConfig struct {
@@ -78,6 +86,9 @@ type DaemonConfig struct {
 
 	// Notification settings
 	Notifications NotificationConfig
+
+	// Runaway job watchdog
+	Watchdog WatchdogConfig
 }
 
 // DaemonCoreConfig contains core daemon settings.
@@ -131,12 +142,44 @@ type EligibilityConfig struct {
 	AutoDownloadTag string `ini:"auto_download_tag"`
 }
 
+// Watchdog actions
+const (
+	WatchdogActionNotify    = "notify"
+	WatchdogActionTerminate = "terminate"
+)
+
+// WatchdogConfig contains the job watchdog settings. On every poll the
+// watchdog checks the user's running jobs that were submitted via Interlink
+// (tagged "interlink") against the caps and reports or stops the ones that
+// exceed them.
+type WatchdogConfig struct {
+	// Enabled turns the watchdog on. Default: false
+	Enabled bool `ini:"enabled"`
+
+	// Action is "notify" (log a warning) or "terminate" (stop the job).
+	// Default: notify
+	Action string `ini:"action"`
+
+	// MaxHours caps the time since the job started executing. 0 = no cap.
+	MaxHours float64 `ini:"max_hours"`
+
+	// MaxCost caps the estimated cost: core-hours so far times the core
+	// type's listed price. 0 = no cap.
+	MaxCost float64 `ini:"max_cost"`
+
+	// Protected is a comma-separated list of job IDs or job name patterns
+	// (* wildcards) the watchdog never stops.
+	Protected string `ini:"protected"`
+}
+
 // DaemonConfig validation errors
 var (
 	ErrDaemonMissingDownloadFolder = errors.New("download_folder is required when daemon is enabled")
 	ErrDaemonInvalidPollInterval   = errors.New("poll_interval_minutes must be between 1 and 1440")
 	ErrDaemonInvalidMaxConcurrent  = errors.New("max_concurrent must be between 1 and 10")
 	ErrDaemonInvalidLookbackDays   = errors.New("lookback_days must be between 1 and 365")
+	ErrWatchdogInvalidAction       = errors.New("watchdog action must be notify or terminate")
+	ErrWatchdogInvalidCaps         = errors.New("watchdog needs max_hours or max_cost above 0 (neither may be negative)")
 )
 
 // DefaultDaemonConfigPath returns the default path for the daemon.conf file.
@@ -224,6 +267,9 @@ func NewDaemonConfig() *DaemonConfig {
 			ShowDownloadComplete: true,
 			ShowDownloadFailed:   true,
 		},
+		Watchdog: WatchdogConfig{
+			Action: WatchdogActionNotify,
+		},
 	}
 }
 
@@ -284,6 +330,14 @@ func LoadDaemonConfig(path string) (*DaemonConfig, error) {
 	cfg.Notifications.ShowDownloadComplete = notifySection.Key("show_download_complete").MustBool(true)
 	cfg.Notifications.ShowDownloadFailed = notifySection.Key("show_download_failed").MustBool(true)
 
+	// Parse [watchdog] section
+	watchdogSection := iniFile.Section("watchdog")
+	cfg.Watchdog.Enabled = watchdogSection.Key("enabled").MustBool(false)
+	cfg.Watchdog.Action = strings.ToLower(strings.TrimSpace(watchdogSection.Key("action").MustString(WatchdogActionNotify)))
+	cfg.Watchdog.MaxHours = watchdogSection.Key("max_hours").MustFloat64(0)
+	cfg.Watchdog.MaxCost = watchdogSection.Key("max_cost").MustFloat64(0)
+	cfg.Watchdog.Protected = watchdogSection.Key("protected").String()
+
 	return cfg, nil
 }
 
@@ -346,6 +400,17 @@ func SaveDaemonConfig(cfg *DaemonConfig, path string) error {
 	notifySection.Key("show_download_complete").SetValue(fmt.Sprintf("%t", cfg.Notifications.ShowDownloadComplete))
 	notifySection.Key("show_download_failed").SetValue(fmt.Sprintf("%t", cfg.Notifications.ShowDownloadFailed))
 
+	// Write [watchdog] section
+	watchdogSection, err := iniFile.NewSection("watchdog")
+	if err != nil {
+		return fmt.Errorf("failed to create watchdog section: %w", err)
+	}
+	watchdogSection.Key("enabled").SetValue(fmt.Sprintf("%t", cfg.Watchdog.Enabled))
+	watchdogSection.Key("action").SetValue(cfg.Watchdog.Action)
+	watchdogSection.Key("max_hours").SetValue(strconv.FormatFloat(cfg.Watchdog.MaxHours, 'f', -1, 64))
+	watchdogSection.Key("max_cost").SetValue(strconv.FormatFloat(cfg.Watchdog.MaxCost, 'f', -1, 64))
+	watchdogSection.Key("protected").SetValue(cfg.Watchdog.Protected)
+
 	// Save to file with restricted permissions (user read/write only)
 	// Use temporary file + rename for atomicity
 	tmpPath := path + ".tmp"
@@ -389,6 +454,21 @@ func (cfg *DaemonConfig) Validate() error {
 		}
 	}
 
+	return cfg.ValidateWatchdog()
+}
+
+// ValidateWatchdog checks the [watchdog] section only. Returns nil when the
+// watchdog is disabled.
+func (cfg *DaemonConfig) ValidateWatchdog() error {
+	if !cfg.Watchdog.Enabled {
+		return nil
+	}
+	if cfg.Watchdog.Action != WatchdogActionNotify && cfg.Watchdog.Action != WatchdogActionTerminate {
+		return ErrWatchdogInvalidAction
+	}
+	if cfg.Watchdog.MaxHours < 0 || cfg.Watchdog.MaxCost < 0 || (cfg.Watchdog.MaxHours == 0 && cfg.Watchdog.MaxCost == 0) {
+		return ErrWatchdogInvalidCaps
+	}
 	return nil
 }
 
@@ -413,6 +493,18 @@ func (cfg *DaemonConfig) GetExcludePatterns() []string {
 	return result
 }
 
+// GetProtectedJobs returns the watchdog's protected job IDs and name
+// patterns as a slice.
+func (cfg *DaemonConfig) GetProtectedJobs() []string {
+	var result []string
+	for _, p := range strings.Split(cfg.Watchdog.Protected, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 // SetExcludePatterns sets the exclude patterns from a slice.
 func (cfg *DaemonConfig) SetExcludePatterns(patterns []string) {
 	cfg.Filters.Exclude = strings.Join(patterns, ",")
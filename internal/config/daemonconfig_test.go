@@ -212,6 +212,40 @@ func TestDaemonConfigValidate(t *testing.T) {
 			},
 			wantErr: ErrDaemonInvalidLookbackDays,
 		},
+		{
+			name: "watchdog valid",
+			modify: func(cfg *DaemonConfig) {
+				cfg.Watchdog.Enabled = true
+				cfg.Watchdog.Action = WatchdogActionTerminate
+				cfg.Watchdog.MaxHours = 48
+			},
+			wantErr: nil,
+		},
+		{
+			name: "watchdog unknown action",
+			modify: func(cfg *DaemonConfig) {
+				cfg.Watchdog.Enabled = true
+				cfg.Watchdog.Action = "kill"
+				cfg.Watchdog.MaxHours = 48
+			},
+			wantErr: ErrWatchdogInvalidAction,
+		},
+		{
+			name: "watchdog without caps",
+			modify: func(cfg *DaemonConfig) {
+				cfg.Watchdog.Enabled = true
+			},
+			wantErr: ErrWatchdogInvalidCaps,
+		},
+		{
+			name: "watchdog negative cap",
+			modify: func(cfg *DaemonConfig) {
+				cfg.Watchdog.Enabled = true
+				cfg.Watchdog.MaxHours = 48
+				cfg.Watchdog.MaxCost = -1
+			},
+			wantErr: ErrWatchdogInvalidCaps,
+		},
 	}
 
 	for _, tt := range tests {
@@ -300,3 +334,34 @@ func TestDaemonConfigIsEnabled(t *testing.T) {
 		t.Error("Expected IsEnabled=true when config is valid and enabled")
 	}
 }
+
+func TestDaemonConfigWatchdogLoadSave(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "daemon.conf")
+
+	cfg := NewDaemonConfig()
+	if cfg.Watchdog.Enabled || cfg.Watchdog.Action != WatchdogActionNotify {
+		t.Fatalf("unexpected watchdog defaults: %+v", cfg.Watchdog)
+	}
+	cfg.Watchdog = WatchdogConfig{
+		Enabled:   true,
+		Action:    WatchdogActionTerminate,
+		MaxHours:  36.5,
+		MaxCost:   250,
+		Protected: "WkXyz, nightly-*",
+	}
+	if err := SaveDaemonConfig(cfg, configPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	loaded, err := LoadDaemonConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if loaded.Watchdog != cfg.Watchdog {
+		t.Errorf("watchdog = %+v, want %+v", loaded.Watchdog, cfg.Watchdog)
+	}
+	protected := loaded.GetProtectedJobs()
+	if len(protected) != 2 || protected[0] != "WkXyz" || protected[1] != "nightly-*" {
+		t.Errorf("GetProtectedJobs() = %v", protected)
+	}
+}
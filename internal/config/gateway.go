@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"strings"
+)
+
+// reservedAPIHeaders are set by the API client itself and can't be
+// overridden with api_headers.
+var reservedAPIHeaders = []string{
+	"Authorization", "Content-Type", "Accept", "Accept-Encoding",
+	"Content-Length", "Host", "Cookie", "X-Rescale-Workspace",
+}
+
+// NormalizeAPIBasePath checks the path prefix an API gateway serves the
+// platform API under and returns it as "/prefix" (no trailing slash), or ""
+// when p is empty or "/".
+func NormalizeAPIBasePath(p string) (string, error) {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(p, "?#%\\ ") || strings.Contains(p, "://") {
+		return "", fmt.Errorf("api_base_path %q must be a plain URL path such as /rescale", p)
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("api_base_path %q contains an empty, '.' or '..' segment", p)
+		}
+	}
+	return "/" + p, nil
+}
+
+// ParseAPIHeaders parses api_headers, static headers sent with every API
+// request: semicolon-separated "Name: value" pairs, e.g.
+// "X-Org-Token: abc123;X-Env: prod". Headers the client sets itself are
+// rejected.
+func ParseAPIHeaders(spec string) (nethttp.Header, error) {
+	headers := make(nethttp.Header)
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("api_headers entry %q must be \"Name: value\"", item)
+		}
+		if !validHeaderName(name) {
+			return nil, fmt.Errorf("api_headers: invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("api_headers: value of %s contains a control character", name)
+		}
+		for _, reserved := range reservedAPIHeaders {
+			if strings.EqualFold(name, reserved) {
+				return nil, fmt.Errorf("api_headers: %s is set by Interlink and can't be overridden", reserved)
+			}
+		}
+		headers.Add(name, value)
+	}
+	return headers, nil
+}
+
+// validHeaderName reports whether name is an RFC 7230 token.
+func validHeaderName(name string) bool {
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// APIRoot returns the URL API paths ("/api/v3/...") are appended to: the
+// platform URL plus the gateway base path, if any. The base path is assumed
+// valid (see Validate).
+func (c *Config) APIRoot() string {
+	root := strings.TrimSuffix(c.APIBaseURL, "/")
+	if basePath, err := NormalizeAPIBasePath(c.APIBasePath); err == nil {
+		root += basePath
+	}
+	return root
+}
+
+// validateGateway checks the API gateway settings.
+func (c *Config) validateGateway() error {
+	if _, err := NormalizeAPIBasePath(c.APIBasePath); err != nil {
+		return err
+	}
+	if _, err := ParseAPIHeaders(c.APIHeaders); err != nil {
+		return err
+	}
+	return nil
+}
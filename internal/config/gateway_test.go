@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestNormalizeAPIBasePath(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"/", "", false},
+		{"rescale", "/rescale", false},
+		{"/gw/rescale/", "/gw/rescale", false},
+		{"/a//b", "", true},
+		{"/a/../b", "", true},
+		{"/a?x=1", "", true},
+		{"https://gw/rescale", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeAPIBasePath(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeAPIBasePath(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseAPIHeaders(t *testing.T) {
+	headers, err := ParseAPIHeaders(" X-Org-Token: abc:def= ; x-env:prod;")
+	if err != nil {
+		t.Fatalf("ParseAPIHeaders: %v", err)
+	}
+	if headers.Get("X-Org-Token") != "abc:def=" || headers.Get("X-Env") != "prod" || len(headers) != 2 {
+		t.Errorf("ParseAPIHeaders = %v", headers)
+	}
+
+	for _, spec := range []string{"X-Token", "Bad Name: x", "authorization: Bearer x", "X-Rescale-Workspace: ws"} {
+		if _, err := ParseAPIHeaders(spec); err == nil {
+			t.Errorf("ParseAPIHeaders(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestGatewaySettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.csv")
+	cfg := &Config{APIBaseURL: DefaultPlatformURL}
+	cfg.APIBasePath = "/rescale"
+	cfg.APIHeaders = "X-Org-Token: secret"
+	if err := SaveConfigCSV(cfg, path); err != nil {
+		t.Fatalf("SaveConfigCSV: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0600) {
+		t.Errorf("config with api_headers has mode %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+	loaded, err := LoadConfigCSV(path)
+	if err != nil {
+		t.Fatalf("LoadConfigCSV: %v", err)
+	}
+	if loaded.APIBasePath != "/rescale" || loaded.APIHeaders != "X-Org-Token: secret" {
+		t.Errorf("loaded gateway settings %q, %q", loaded.APIBasePath, loaded.APIHeaders)
+	}
+	if loaded.APIRoot() != "https://platform.rescale.com/rescale" {
+		t.Errorf("APIRoot() = %q", loaded.APIRoot())
+	}
+	for _, rec := range loaded.Records() {
+		if rec[0] == "api_headers" {
+			t.Error("Records() includes api_headers")
+		}
+	}
+}
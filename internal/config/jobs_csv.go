@@ -4,11 +4,14 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/filter"
 	"github.com/rescale/rescale-int/internal/util/sanitize"
 )
 
@@ -20,7 +23,12 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	return ReadJobsCSV(file)
+}
+
+// ReadJobsCSV reads job specifications in CSV format from r.
+func ReadJobsCSV(r io.Reader) ([]models.JobSpec, error) {
+	reader := csv.NewReader(r)
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read jobs CSV: %w", err)
@@ -33,8 +41,21 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 	// Parse header
 	header := records[0]
 	headerMap := make(map[string]int)
+	metaCols := make(map[string]int) // metadata key (case kept) -> column
+	envCols := make(map[string]int)  // environment variable name -> column
 	for i, col := range header {
-		headerMap[strings.ToLower(strings.TrimSpace(col))] = i
+		col = strings.TrimSpace(col)
+		headerMap[strings.ToLower(col)] = i
+		if len(col) > len(models.MetadataPrefix) && strings.EqualFold(col[:len(models.MetadataPrefix)], models.MetadataPrefix) {
+			metaCols[col[len(models.MetadataPrefix):]] = i
+		}
+		if len(col) > len(models.EnvPrefix) && strings.EqualFold(col[:len(models.EnvPrefix)], models.EnvPrefix) {
+			name := col[len(models.EnvPrefix):]
+			if !IsValidEnvName(name) {
+				return nil, fmt.Errorf("column %q: %q is not a valid environment variable name", col, name)
+			}
+			envCols[name] = i
+		}
 	}
 
 	// Required columns
@@ -97,6 +118,14 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 			}
 		}
 
+		if pr := getCol("priority"); pr != "" {
+			if v, err := strconv.Atoi(pr); err == nil {
+				job.Priority = v
+			} else {
+				return nil, fmt.Errorf("row %d: invalid Priority: %s", i+1, pr)
+			}
+		}
+
 		// Optional fields
 		job.AnalysisVersion = getCol("analysisversion")
 		job.ExtraInputFileIDs = getCol("extrainputfileids")
@@ -104,8 +133,45 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 		job.ProjectID = sanitize.SanitizeField(getCol("projectid"))
 		job.OrgCode = sanitize.SanitizeField(getCol("orgcode"))
 		job.TarSubpath = getCol("tarsubpath")
+		job.DestinationFolder = getCol("destinationfolder")
+		job.ContinuationCommand = getCol("continuationcommand")
+		job.OutputPatterns = filter.ParsePatternList(getCol("outputpatterns"))
+		job.ClusterID = sanitize.SanitizeField(getCol("clusterid"))
+
+		// Metadata columns; empty cells leave the key unset
+		for key, idx := range metaCols {
+			if idx >= len(record) {
+				continue
+			}
+			if v := sanitize.SanitizeField(strings.TrimSpace(record[idx])); v != "" {
+				if job.Metadata == nil {
+					job.Metadata = make(map[string]string)
+				}
+				job.Metadata[key] = v
+			}
+		}
+
+		// Environment variable columns; empty cells leave the variable unset
+		for name, idx := range envCols {
+			if idx >= len(record) {
+				continue
+			}
+			if v := sanitize.SanitizeField(record[idx]); v != "" {
+				if job.EnvVars == nil {
+					job.EnvVars = make(map[string]string)
+				}
+				job.EnvVars[name] = v
+			}
+		}
 
 		// Parse tags (comma-separated)
+		job.Array = getCol("array")
+		for _, name := range strings.Split(getCol("dependson"), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				job.DependsOn = append(job.DependsOn, name)
+			}
+		}
+
 		if tagsStr := getCol("tags"); tagsStr != "" {
 			tagParts := strings.Split(tagsStr, ",")
 			for _, tag := range tagParts {
@@ -121,10 +187,28 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 			job.NoDecompress = true
 		}
 
+		if nt := strings.ToLower(getCol("notextnormalize")); nt == "true" || nt == "yes" || nt == "1" {
+			job.NoTextNormalize = true
+		}
+
 		if lp := strings.ToLower(getCol("islowpriority")); lp == "true" || lp == "yes" || lp == "1" {
 			job.IsLowPriority = true
 		}
 
+		// Scheduling ("pro" or "economy") is the friendlier spelling of
+		// IsLowPriority and wins when both are set
+		if sched := getCol("scheduling"); sched != "" {
+			lowPriority, err := models.ParseScheduling(sched)
+			if err != nil {
+				return nil, fmt.Errorf("row %d (%s): %w", i+1, job.JobName, err)
+			}
+			job.IsLowPriority = lowPriority
+		}
+
+		if rl := strings.ToLower(getCol("userescalelicense")); rl == "true" || rl == "yes" || rl == "1" {
+			job.UseRescaleLicense = true
+		}
+
 		// Submit mode (default to "yes")
 		submitMode := strings.ToLower(getCol("submit"))
 		if submitMode == "" {
@@ -138,6 +222,9 @@ func LoadJobsCSV(path string) ([]models.JobSpec, error) {
 				return nil, fmt.Errorf("row %d (%s): invalid LicenseSettings: %w", i+1, job.JobName, err)
 			}
 		}
+		if _, err := JobEnvironment(job); err != nil {
+			return nil, fmt.Errorf("row %d (%s): %w", i+1, job.JobName, err)
+		}
 
 		jobs = append(jobs, job)
 	}
@@ -188,6 +275,47 @@ func ParseLicenseJSON(licenseJSON string) (map[string]string, error) {
 	return result, nil
 }
 
+// IsValidEnvName reports whether name can be an environment variable on the
+// cluster: letters, digits and underscores, not starting with a digit.
+func IsValidEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// JobEnvironment returns the environment variables the job sets on the
+// cluster: its license settings plus EnvVars. A name may be set in both only
+// with the same value. Returns nil when the job sets none.
+func JobEnvironment(job models.JobSpec) (map[string]string, error) {
+	var env map[string]string
+	if strings.TrimSpace(job.LicenseSettings) != "" {
+		var err error
+		if env, err = ParseLicenseJSON(job.LicenseSettings); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range job.EnvKeys() {
+		if !IsValidEnvName(name) {
+			return nil, fmt.Errorf("%q is not a valid environment variable name", name)
+		}
+		value := job.EnvVars[name]
+		if licenseValue, ok := env[name]; ok && licenseValue != value {
+			return nil, fmt.Errorf("environment variable %s is set in both LicenseSettings (%q) and EnvVars (%q)", name, licenseValue, value)
+		}
+		if env == nil {
+			env = make(map[string]string, len(job.EnvVars))
+		}
+		env[name] = value
+	}
+	return env, nil
+}
+
 // SaveJobsCSV writes job specifications to a CSV file
 func SaveJobsCSV(path string, jobs []models.JobSpec) error {
 	file, err := os.Create(path)
@@ -199,12 +327,41 @@ func SaveJobsCSV(path string, jobs []models.JobSpec) error {
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
+	// One column per metadata key and environment variable used by any job
+	var keys, envNames []string
+	seen := make(map[string]bool)
+	seenEnv := make(map[string]bool)
+	for _, job := range jobs {
+		for _, k := range job.MetadataKeys() {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+		for _, name := range job.EnvKeys() {
+			if !seenEnv[name] {
+				seenEnv[name] = true
+				envNames = append(envNames, name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	sort.Strings(envNames)
+
 	// Write header
 	header := []string{
 		"Directory", "JobName", "AnalysisCode", "AnalysisVersion", "Command",
 		"CoreType", "CoresPerSlot", "WalltimeHours", "Slots", "LicenseSettings",
 		"ExtraInputFileIDs", "OnDemandLicenseSeller", "ProjectID", "OrgCode", "Tags",
-		"NoDecompress", "IsLowPriority", "Submit", "TarSubpath",
+		"NoDecompress", "IsLowPriority", "Submit", "TarSubpath", "Priority",
+		"DestinationFolder", "ContinuationCommand", "OutputPatterns", "DependsOn", "Array",
+		"NoTextNormalize", "UseRescaleLicense", "ClusterID",
+	}
+	for _, k := range keys {
+		header = append(header, models.MetadataPrefix+k)
+	}
+	for _, name := range envNames {
+		header = append(header, models.EnvPrefix+name)
 	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
@@ -232,6 +389,21 @@ func SaveJobsCSV(path string, jobs []models.JobSpec) error {
 			strconv.FormatBool(job.IsLowPriority),
 			job.SubmitMode,
 			job.TarSubpath,
+			strconv.Itoa(job.Priority),
+			job.DestinationFolder,
+			job.ContinuationCommand,
+			strings.Join(job.OutputPatterns, ","),
+			strings.Join(job.DependsOn, ","),
+			job.Array,
+			strconv.FormatBool(job.NoTextNormalize),
+			strconv.FormatBool(job.UseRescaleLicense),
+			job.ClusterID,
+		}
+		for _, k := range keys {
+			row = append(row, job.Metadata[k])
+		}
+		for _, name := range envNames {
+			row = append(row, job.EnvVars[name])
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write job row: %w", err)
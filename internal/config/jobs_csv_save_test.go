@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/rescale/rescale-int/internal/models"
@@ -148,6 +149,13 @@ func TestSaveLoadRoundTrip(t *testing.T) {
 		IsLowPriority:         true,
 		SubmitMode:            "create_and_submit",
 		TarSubpath:            "output/results",
+		Priority:              5,
+		DestinationFolder:     "ProjectA/Study 1",
+		ContinuationCommand:   "./run.sh --restart",
+		Metadata:              map[string]string{"Mach": "0.8", "study": "wing-sweep"},
+		EnvVars:               map[string]string{"OMP_NUM_THREADS": "16", "SOLVER_OPTS": "-fast, -v"},
+		UseRescaleLicense:     true,
+		ClusterID:             "cluster123",
 	}
 
 	tmpDir := t.TempDir()
@@ -228,4 +236,25 @@ func TestSaveLoadRoundTrip(t *testing.T) {
 	if reloaded.TarSubpath != originalJob.TarSubpath {
 		t.Errorf("TarSubpath = %s, want %s", reloaded.TarSubpath, originalJob.TarSubpath)
 	}
+	if reloaded.Priority != originalJob.Priority {
+		t.Errorf("Priority = %d, want %d", reloaded.Priority, originalJob.Priority)
+	}
+	if reloaded.DestinationFolder != originalJob.DestinationFolder {
+		t.Errorf("DestinationFolder = %s, want %s", reloaded.DestinationFolder, originalJob.DestinationFolder)
+	}
+	if reloaded.ContinuationCommand != originalJob.ContinuationCommand {
+		t.Errorf("ContinuationCommand = %s, want %s", reloaded.ContinuationCommand, originalJob.ContinuationCommand)
+	}
+	if !reflect.DeepEqual(reloaded.Metadata, originalJob.Metadata) {
+		t.Errorf("Metadata = %v, want %v", reloaded.Metadata, originalJob.Metadata)
+	}
+	if !reflect.DeepEqual(reloaded.EnvVars, originalJob.EnvVars) {
+		t.Errorf("EnvVars = %v, want %v", reloaded.EnvVars, originalJob.EnvVars)
+	}
+	if reloaded.UseRescaleLicense != originalJob.UseRescaleLicense {
+		t.Errorf("UseRescaleLicense = %v, want %v", reloaded.UseRescaleLicense, originalJob.UseRescaleLicense)
+	}
+	if reloaded.ClusterID != originalJob.ClusterID {
+		t.Errorf("ClusterID = %s, want %s", reloaded.ClusterID, originalJob.ClusterID)
+	}
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/rescale/rescale-int/internal/models"
@@ -122,5 +124,36 @@ func TestParseLicenseJSON(t *testing.T) {
 	}
 }
 
+func TestReadJobsCSV_EnvironmentAndScheduling(t *testing.T) {
+	const header = "Directory,JobName,AnalysisCode,Command,CoreType,CoresPerSlot,WalltimeHours,Slots,LicenseSettings,IsLowPriority,Scheduling,env_OMP_NUM_THREADS\n"
+	csv := header +
+		`./a,a,user_included,run,emerald,1,1,1,"{""LM_LICENSE_FILE"":""27000@lic""}",false,economy,4` + "\n" +
+		`./b,b,user_included,run,emerald,1,1,1,,true,On-Demand-Pro,` + "\n"
+	jobs, err := ReadJobsCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadJobsCSV() error = %v", err)
+	}
+	if !jobs[0].IsLowPriority || jobs[1].IsLowPriority {
+		t.Errorf("IsLowPriority = %v, %v; want Scheduling to win (true, false)", jobs[0].IsLowPriority, jobs[1].IsLowPriority)
+	}
+	if !reflect.DeepEqual(jobs[0].EnvVars, map[string]string{"OMP_NUM_THREADS": "4"}) || jobs[1].EnvVars != nil {
+		t.Errorf("EnvVars = %v, %v; want OMP_NUM_THREADS=4 and unset", jobs[0].EnvVars, jobs[1].EnvVars)
+	}
+	env, err := JobEnvironment(jobs[0])
+	if err != nil || !reflect.DeepEqual(env, map[string]string{"LM_LICENSE_FILE": "27000@lic", "OMP_NUM_THREADS": "4"}) {
+		t.Errorf("JobEnvironment() = %v, %v; want license and env variables merged", env, err)
+	}
+
+	for name, bad := range map[string]string{
+		"bad scheduling": header + "./a,a,user_included,run,emerald,1,1,1,,false,spot,\n",
+		"bad env name":   strings.Replace(header, "env_OMP_NUM_THREADS", "env_1X", 1) + "./a,a,user_included,run,emerald,1,1,1,,false,,1\n",
+		"env conflict":   strings.Replace(header, "OMP_NUM_THREADS", "LM_LICENSE_FILE", 1) + `./a,a,user_included,run,emerald,1,1,1,"{""LM_LICENSE_FILE"":""27000@lic""}",false,,1@other` + "\n",
+	} {
+		if _, err := ReadJobsCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: ReadJobsCSV() succeeded, want error", name)
+		}
+	}
+}
+
 // parseJobRow is not exported, so we can't test it directly.
 // We test it indirectly through LoadJobsCSV
@@ -6,8 +6,10 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -22,6 +24,12 @@ func LoadJobsJSON(path string) ([]models.JobSpec, error) {
 		return nil, fmt.Errorf("failed to read jobs JSON file: %w", err)
 	}
 
+	return ParseJobsJSON(data)
+}
+
+// ParseJobsJSON parses job specifications from JSON: an array of JobSpec or
+// a single JobSpec.
+func ParseJobsJSON(data []byte) ([]models.JobSpec, error) {
 	// Try parsing as array first
 	var jobs []models.JobSpec
 	if err := json.Unmarshal(data, &jobs); err == nil {
@@ -88,3 +96,25 @@ func DetectJobFileFormat(path string) string {
 	return "unknown"
 }
 
+// LoadJobs loads job specifications from a CSV or JSON file, chosen by
+// extension (anything other than .json is read as CSV).
+func LoadJobs(path string) ([]models.JobSpec, error) {
+	if DetectJobFileFormat(path) == "json" {
+		return LoadJobsJSON(path)
+	}
+	return LoadJobsCSV(path)
+}
+
+// ReadJobs reads job specifications from r, which is typically stdin for a
+// jobs file given as "-". Content starting with '[' or '{' is parsed as JSON,
+// anything else as CSV.
+func ReadJobs(r io.Reader) ([]models.JobSpec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return ParseJobsJSON(trimmed)
+	}
+	return ReadJobsCSV(bytes.NewReader(data))
+}
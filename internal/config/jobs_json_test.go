@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rescale/rescale-int/internal/models"
@@ -153,3 +154,44 @@ func TestLoadJobsJSON_EmptyFile(t *testing.T) {
 		t.Error("Expected error for empty array, got nil")
 	}
 }
+
+func TestReadJobs_DetectsFormat(t *testing.T) {
+	csvData := "directory,jobname,analysiscode,command,coretype,coresperslot,walltimehours,slots,licensesettings\n" +
+		"./Run_1,TestJob1,user_included,./run.sh,emerald,1,1,1,\n" +
+		"./Run_2,TestJob2,user_included,./run.sh,emerald,1,1,1,\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		wantLen int
+		want    string // first job name
+	}{
+		{"csv", csvData, 2, "TestJob1"},
+		{"json array", `  [{"JobName": "Piped1"}, {"JobName": "Piped2"}]`, 2, "Piped1"},
+		{"json object", "\n{\"JobName\": \"Single\", \"AnalysisCode\": \"user_included\"}\n", 1, "Single"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := ReadJobs(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadJobs() error = %v", err)
+			}
+			if len(jobs) != tt.wantLen {
+				t.Fatalf("ReadJobs() got %d jobs, want %d", len(jobs), tt.wantLen)
+			}
+			if jobs[0].JobName != tt.want {
+				t.Errorf("Job 0 name = %q, want %q", jobs[0].JobName, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJobs_Empty(t *testing.T) {
+	if _, err := ReadJobs(strings.NewReader("")); err == nil {
+		t.Error("Expected error for empty input, got nil")
+	}
+	if _, err := ReadJobs(strings.NewReader("[]")); err == nil {
+		t.Error("Expected error for empty JSON array, got nil")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // LogDirectory returns the unified log directory for all Interlink logs.
@@ -90,7 +93,149 @@ func ReportDirectory() string {
 	return filepath.Join(configDir, "rescale", "reports")
 }
 
+// ServeTokenPath returns the file `rescale-int serve` writes its API token
+// to, next to config.csv.
+func ServeTokenPath() string {
+	return filepath.Join(getConfigDir(), "serve-token")
+}
+
+// SendToSpoolDirectory returns where "Send to Rescale Interlink" requests
+// wait for a running GUI or `serve` to upload them, next to config.csv.
+func SendToSpoolDirectory() string {
+	return filepath.Join(getConfigDir(), "send-to")
+}
+
+// TemplateDirectory returns where the GUI's template library saves job
+// templates: ~/.config/rescale/templates on every platform.
+func TemplateDirectory() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "rescale", "templates")
+}
+
+// RecipeDirectory returns where run recipes imported from the team template
+// repository are saved, next to the template library.
+func RecipeDirectory() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "rescale", "recipes")
+}
+
+// TemplateImportsPath returns the record of templates and recipes imported
+// from the team template repository.
+func TemplateImportsPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "rescale", "template-imports.json")
+}
+
 // EnsureReportDirectory creates the report directory if it doesn't exist.
 func EnsureReportDirectory() error {
 	return os.MkdirAll(ReportDirectory(), 0700)
 }
+
+// StateDirectory returns the directory for PUR run state files.
+//
+// cfg.StateDir wins when set (a leading ~ expands to the home directory), so
+// teams can keep run state on a shared project drive. Otherwise:
+//   - All platforms: ~/.rescale-int/states
+func StateDirectory(cfg *Config) string {
+	if cfg != nil && cfg.StateDir != "" {
+		return filepath.Clean(expandHome(cfg.StateDir))
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".rescale-int", "states")
+}
+
+// Download organization modes for Config.DownloadOrganize.
+const (
+	DownloadOrganizeNone    = "none"
+	DownloadOrganizeJob     = "job"
+	DownloadOrganizeDate    = "date"
+	DownloadOrganizeDateJob = "date-job"
+)
+
+// DownloadDirectory returns the default download destination for a job (or
+// remote folder) named label, or "" when no download root is configured.
+//
+// cfg.DownloadDir is the root (a leading ~ expands to the home directory);
+// cfg.DownloadOrganize appends a subfolder per job name, per date (taken
+// from when), or both, e.g. <root>/2026-03-14/<label>.
+func DownloadDirectory(cfg *Config, label string, when time.Time) string {
+	if cfg == nil || cfg.DownloadDir == "" {
+		return ""
+	}
+	return OrganizeDownloadDir(expandHome(cfg.DownloadDir), cfg.DownloadOrganize, label, when)
+}
+
+// OrganizeDownloadDir applies an organization mode beneath root. Unknown
+// modes and an empty label fall back to no job subfolder.
+func OrganizeDownloadDir(root, mode, label string, when time.Time) string {
+	dir := filepath.Clean(root)
+	date := when.Format("2006-01-02")
+	name := SafeDirName(label)
+
+	switch mode {
+	case DownloadOrganizeJob:
+		if name != "" {
+			dir = filepath.Join(dir, name)
+		}
+	case DownloadOrganizeDate:
+		dir = filepath.Join(dir, date)
+	case DownloadOrganizeDateJob:
+		dir = filepath.Join(dir, date)
+		if name != "" {
+			dir = filepath.Join(dir, name)
+		}
+	}
+	return dir
+}
+
+// expandHome expands a leading ~ to the user's home directory.
+func expandHome(dir string) string {
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`) {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, dir[1:])
+		}
+	}
+	return dir
+}
+
+// maxDirNameBytes caps SafeDirName results, well under the 255-byte name
+// limit of common file systems.
+const maxDirNameBytes = 100
+
+// SafeDirName makes a job name usable as a single directory name on every
+// platform: path separators, characters Windows reserves and control
+// characters become '_', surrounding spaces and dots are trimmed (so "." and
+// ".." become ""), and long names are cut to maxDirNameBytes on a rune
+// boundary. Returns "" when nothing usable is left.
+func SafeDirName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > maxDirNameBytes {
+		cut := maxDirNameBytes
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+	return strings.Trim(strings.TrimSpace(name), ". ")
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSafeDirName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"run_1", "run_1"},
+		{"a/b\\c:d*e?f\"g<h>i|j", "a_b_c_d_e_f_g_h_i_j"},
+		{"tab\there", "tab_here"},
+		{"  spaced  ", "spaced"},
+		{".", ""},
+		{"..", ""},
+		{"name.", "name"},
+	}
+	for _, tt := range tests {
+		if got := SafeDirName(tt.name); got != tt.want {
+			t.Errorf("SafeDirName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Long names are cut on a rune boundary
+	long := strings.Repeat("é", 80) // 160 bytes
+	got := SafeDirName(long)
+	if len(got) > maxDirNameBytes || !utf8.ValidString(got) {
+		t.Errorf("SafeDirName(long) = %q (%d bytes), want valid UTF-8 of at most %d bytes", got, len(got), maxDirNameBytes)
+	}
+}
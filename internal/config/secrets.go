@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretsFileName is the file next to config.csv that holds the persisted
+// notification secrets: the webhook signing key, the Slack/Teams webhook
+// URLs (they embed the posting credential) and the SMTP password. It is
+// written like the token file, owner-only on every OS (see restrictToOwner),
+// so these values never land in config.csv.
+const SecretsFileName = "secrets.csv"
+
+// GetSecretsPath returns the secrets file that belongs to the config file
+// at configPath.
+func GetSecretsPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), SecretsFileName)
+}
+
+// secretRecords returns the secrets SaveConfigCSV writes to the secrets
+// file, in file order.
+func (c *Config) secretRecords() [][]string {
+	return [][]string{
+		{"webhook_secret", c.WebhookSecret},
+		{"slack_webhook_url", c.SlackWebhookURL},
+		{"teams_webhook_url", c.TeamsWebhookURL},
+		{"smtp_password", c.SMTPPassword},
+	}
+}
+
+// setSecret sets the secret named key. Unknown keys are ignored.
+func (c *Config) setSecret(key, value string) {
+	switch key {
+	case "webhook_secret":
+		c.WebhookSecret = value
+	case "slack_webhook_url":
+		c.SlackWebhookURL = value
+	case "teams_webhook_url":
+		c.TeamsWebhookURL = value
+	case "smtp_password":
+		c.SMTPPassword = value
+	}
+}
+
+// loadSecrets overlays the secrets file of configPath onto cfg. A missing
+// file leaves cfg as is, so values an older version wrote to config.csv
+// still apply until the next save moves them.
+func loadSecrets(cfg *Config, configPath string) error {
+	data, err := os.ReadFile(GetSecretsPath(configPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	for _, record := range records {
+		if len(record) >= 2 {
+			cfg.setSecret(strings.TrimSpace(strings.ToLower(record[0])), strings.TrimSpace(record[1]))
+		}
+	}
+	return nil
+}
+
+// saveSecrets writes cfg's non-empty secrets to the secrets file of
+// configPath, owner-only, or removes the file when none is set.
+func saveSecrets(cfg *Config, configPath string) error {
+	path := GetSecretsPath(configPath)
+
+	var set [][]string
+	for _, record := range cfg.secretRecords() {
+		if record[1] != "" {
+			set = append(set, record)
+		}
+	}
+	if len(set) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove secrets file: %w", err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(append([][]string{{"key", "value"}}, set...)); err != nil {
+		return fmt.Errorf("failed to encode secrets: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	// os.WriteFile keeps the mode of an existing file; restrictToOwner
+	// repairs it and applies the Windows ACL
+	if err := restrictToOwner(path); err != nil {
+		return fmt.Errorf("failed to restrict secrets file permissions: %w", err)
+	}
+	return nil
+}
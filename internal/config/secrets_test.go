@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSecretsKeptOutOfConfigCSV(t *testing.T) {
+	path := t.TempDir() + "/config.csv"
+	cfg := &Config{ProxyMode: "no-proxy", WebhookSecret: "sign-key", SlackWebhookURL: "https://hooks.slack.com/services/T/B/X"}
+	if err := SaveConfigCSV(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "sign-key") || strings.Contains(string(data), "hooks.slack.com") {
+		t.Errorf("config.csv holds a secret:\n%s", data)
+	}
+	info, err := os.Stat(GetSecretsPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := LoadConfigCSV(path)
+	if err != nil || loaded.WebhookSecret != "sign-key" || loaded.SlackWebhookURL != cfg.SlackWebhookURL {
+		t.Fatalf("LoadConfigCSV() = %+v, %v", loaded, err)
+	}
+
+	// Clearing every secret removes the file
+	loaded.WebhookSecret, loaded.SlackWebhookURL = "", ""
+	if err := SaveConfigCSV(loaded, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(GetSecretsPath(path)); !os.IsNotExist(err) {
+		t.Errorf("secrets file still exists: %v", err)
+	}
+}
+
+// TestSecretsMigratedFromConfigCSV tests that a secret an older version
+// wrote to config.csv still loads and moves to the secrets file on save.
+func TestSecretsMigratedFromConfigCSV(t *testing.T) {
+	path := t.TempDir() + "/config.csv"
+	if err := os.WriteFile(path, []byte("key,value\nsmtp_password,old-pass\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigCSV(path)
+	if err != nil || cfg.SMTPPassword != "old-pass" {
+		t.Fatalf("LoadConfigCSV() = %+v, %v", cfg, err)
+	}
+	if err := SaveConfigCSV(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "old-pass") {
+		t.Error("config.csv still holds smtp_password after save")
+	}
+	if cfg, err = LoadConfigCSV(path); err != nil || cfg.SMTPPassword != "old-pass" {
+		t.Errorf("after save: SMTPPassword = %q, %v", cfg.SMTPPassword, err)
+	}
+}
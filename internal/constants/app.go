@@ -83,6 +83,61 @@ const (
 	RetryMaxDelay = 15 * time.Second
 )
 
+// Automatic transfer re-queue configuration.
+// Applies after a whole upload has failed (per-request retries exhausted) with
+// a transient error; the task waits in the Transfers tab and is re-queued.
+const (
+	// TransferAutoRetryMaxAttempts - automatic re-queues before a task is left failed
+	TransferAutoRetryMaxAttempts = 5
+
+	// TransferAutoRetryInitialDelay - wait before the first automatic re-queue (30s)
+	// Doubles on each subsequent attempt.
+	TransferAutoRetryInitialDelay = 30 * time.Second
+
+	// TransferAutoRetryMaxDelay - cap on the wait between automatic re-queues (10 minutes)
+	TransferAutoRetryMaxDelay = 10 * time.Minute
+)
+
+// Network change detection (Wi-Fi → VPN, dock/undock)
+const (
+	// NetworkProbeInterval - how often the default route is checked for changes
+	NetworkProbeInterval = 3 * time.Second
+
+	// NetworkOfflineRetryDelay - automatic re-queue delay for transfers that
+	// failed while the network was down; the queue holds them until it is back
+	NetworkOfflineRetryDelay = 5 * time.Second
+)
+
+// Network test (Settings > Run Network Test)
+const (
+	// NetworkTestLatencySamples - API round trips timed for latency percentiles
+	NetworkTestLatencySamples = 10
+
+	// NetworkTestSmallProbeSize - small storage probe (256 KB), dominated by per-request overhead
+	NetworkTestSmallProbeSize = 256 * 1024
+
+	// NetworkTestLargeProbeSize - large storage probe (16 MB), dominated by bandwidth
+	NetworkTestLargeProbeSize = 16 * 1024 * 1024
+
+	// NetworkTestTimeout - overall limit for one network test run (3 minutes)
+	NetworkTestTimeout = 3 * time.Minute
+)
+
+// Shared run state locking
+const (
+	// StateLockHeartbeat - how often a run refreshes its state lock file (30 seconds)
+	StateLockHeartbeat = 30 * time.Second
+
+	// StateLockStaleAfter - a lock not refreshed for this long belongs to a dead
+	// process and may be taken over (3 minutes, several missed heartbeats)
+	StateLockStaleAfter = 3 * time.Minute
+
+	// StateSaveInterval - routine state changes are batched for this long
+	// before the state file is rewritten (2 seconds). Critical transitions
+	// (a created job, an uploaded file, a submit outcome) are written at once.
+	StateSaveInterval = 2 * time.Second
+)
+
 // Transfer operation timeouts
 const (
 	// PartOperationTimeout - timeout for individual part uploads/downloads (10 minutes)
@@ -90,6 +145,14 @@ const (
 
 	// ProgressUpdateInterval - how often progress updates are checked/emitted (500ms)
 	ProgressUpdateInterval = 500 * time.Millisecond
+
+	// CancelIdleTarget - a cancelled transfer should stop all I/O and release
+	// its slot within this long (2 seconds); slower cancels are logged
+	CancelIdleTarget = 2 * time.Second
+
+	// CancelCleanupTimeout - bound for best-effort cleanup after a failed or
+	// cancelled transfer, e.g. aborting a multipart upload (30 seconds)
+	CancelCleanupTimeout = 30 * time.Second
 )
 
 // Disk space safety margin
@@ -120,6 +183,109 @@ const (
 	MaxQueueSize = 1000
 )
 
+// Pipeline Worker Defaults (tar, upload and job workers)
+const (
+	// DefaultPipelineWorkers - default workers per pipeline stage
+	DefaultPipelineWorkers = 4
+
+	// MinARMPipelineWorkers / MaxARMPipelineWorkers - bounds for the arm64
+	// default, which scales with core count (see resources.DefaultPipelineWorkers)
+	MinARMPipelineWorkers = 2
+	MaxARMPipelineWorkers = 8
+)
+
+// Split Input Tars (tar_split_mode)
+const (
+	// DefaultTarSplitParts - number of tars a run directory is split into
+	// in "size" mode when tar_split_parts is not set
+	DefaultTarSplitParts = 4
+
+	// MaxTarSplitParts - upper bound on tar_split_parts
+	MaxTarSplitParts = 64
+
+	// TarSplitUploadConcurrency - parts of one job uploaded at the same time
+	TarSplitUploadConcurrency = 4
+)
+
+// Locked Files During Tar (tar_locked_files)
+const (
+	// DefaultTarLockRetrySeconds - wait between attempts to read a locked
+	// file under the "retry" policy
+	DefaultTarLockRetrySeconds = 30
+
+	// DefaultTarLockRetries - attempts after the first before a locked file
+	// fails the job under the "retry" policy
+	DefaultTarLockRetries = 3
+
+	// TarLockProbeBytes - bytes read from each file before its tar header is
+	// written, so byte-range locks are found while the file can still be
+	// skipped
+	TarLockProbeBytes = 64 * 1024
+)
+
+// Text Input Normalization (text_normalize)
+const (
+	// DefaultTextNormalizeExtensions - extensions of the files checked when
+	// text_normalize_extensions is not set: solver input decks and scripts
+	DefaultTextNormalizeExtensions = ".inp;.in;.dat;.cfg;.sh;.py"
+
+	// MaxTextNormalizeBytes - larger files are archived as they are; text
+	// files are read into memory to be checked
+	MaxTextNormalizeBytes = 64 * 1024 * 1024
+)
+
+// Tar Progress
+const (
+	// TarProgressInterval - minimum time between tar progress events for a job
+	TarProgressInterval = 500 * time.Millisecond
+
+	// TarProgressLogInterval - minimum time between tar progress log lines
+	// for a job, so a large archive shows it is still moving
+	TarProgressLogInterval = 15 * time.Second
+)
+
+// Encryption CPU Budget (cpu_budget_percent)
+const (
+	// DefaultCPUBudgetPercent - share of logical CPUs encryption may use
+	DefaultCPUBudgetPercent = 100
+
+	// MinCPUBudgetPercent - lower bound on cpu_budget_percent; at least one
+	// encryption slot is always available
+	MinCPUBudgetPercent = 10
+
+	// OnBatteryCPUBudgetFactor - the budget is multiplied by this (percent)
+	// while on battery, when cpu_reduce_on_battery is set
+	OnBatteryCPUBudgetFactor = 50
+
+	// PowerSourceCheckInterval - how long the battery state is cached
+	PowerSourceCheckInterval = 30 * time.Second
+)
+
+// Transfer Blackout Windows (blackout_windows)
+const (
+	// BlackoutCheckInterval - how often work paused for a blackout window
+	// re-checks the schedule, so edited windows and clock changes apply
+	BlackoutCheckInterval = 30 * time.Second
+)
+
+// Upload Read-Back Verification (upload_readback_verify)
+const (
+	// ReadbackBlockSize - granularity of the ciphertext hashes retained
+	// during upload; each read-back fetches one block
+	ReadbackBlockSize = 1 * 1024 * 1024
+
+	// ReadbackSampleBlocks - random blocks read back in addition to the
+	// final block
+	ReadbackSampleBlocks = 3
+)
+
+// Download Verification
+const (
+	// DownloadIntegrityRetries - times a downloaded file whose checksum or
+	// size does not match the platform's is downloaded again before failing
+	DownloadIntegrityRetries = 2
+)
+
 // UI Updates
 const (
 	// TableRefreshMinInterval - minimum time between table refreshes (100ms)
@@ -163,6 +329,20 @@ const (
 
 	// MaxConsecutiveWatchErrors - abort watch after this many consecutive status check failures
 	MaxConsecutiveWatchErrors = 5
+
+	// JobEventWait - how long the platform may hold a job event long-poll open
+	JobEventWait = 25 * time.Second
+
+	// JobEventResyncInterval - full status sweep while on the event channel,
+	// to catch any transition the channel missed
+	JobEventResyncInterval = 10 * time.Minute
+
+	// MaxConsecutiveJobEventErrors - fall back to polling after this many failed long-polls
+	MaxConsecutiveJobEventErrors = 3
+
+	// WatchdogLookback - the daemon's job watchdog only considers jobs
+	// created within this window
+	WatchdogLookback = 30 * 24 * time.Hour
 )
 
 // CLI Concurrency Limits
@@ -304,10 +484,27 @@ const (
 	// Set high because 30s caused "context deadline exceeded" on page 5+.
 	PaginatedAPITimeout = 5 * time.Minute
 
+	// Default timeouts per API endpoint class, overridable with the
+	// api_timeout_* config keys (see config.APIEndpointClass).
+	// DefaultCatalogAPITimeout covers whole-catalog scans (core types,
+	// software, automations); DefaultListingAPITimeout job, file and folder
+	// listings; DefaultMutationAPITimeout creating, submitting, stopping and
+	// deleting; DefaultStatusAPITimeout job status and single-record lookups,
+	// which should fail fast.
+	DefaultCatalogAPITimeout  = PaginatedAPITimeout
+	DefaultListingAPITimeout  = 2 * time.Minute
+	DefaultMutationAPITimeout = 1 * time.Minute
+	DefaultStatusAPITimeout   = 15 * time.Second
+
 	// ValidationCacheTTL - time-to-live for cached validation results (5 minutes)
 	ValidationCacheTTL = 5 * time.Minute
 )
 
+// PlanValidationWorkers - jobs validated in parallel by Plan. Per-job work is
+// local (directory stat, field checks, catalog lookups); API data is fetched
+// once per plan, so this does not multiply API traffic.
+const PlanValidationWorkers = 16
+
 // HTTP Client Timeouts
 const (
 	// HTTPIdleConnTimeout - how long to keep idle connections open (90 seconds)
@@ -351,6 +548,32 @@ const (
 	PaginationWarningThreshold = 900
 )
 
+// Self-Update
+const (
+	// SelfUpdateMaxManifestSize - cap on manifest and signature downloads (1 MB)
+	SelfUpdateMaxManifestSize = 1 << 20
+
+	// SelfUpdateMaxBinarySize - cap on release binary downloads (512 MB)
+	SelfUpdateMaxBinarySize = 512 << 20
+
+	// SelfUpdateTimeout - time allowed to check for and download an update
+	SelfUpdateTimeout = 10 * time.Minute
+)
+
+// Job Name Uniqueness
+const (
+	// JobNameRecentWindow - how far back the user's existing jobs are checked
+	// for names that a new run would duplicate
+	JobNameRecentWindow = 30 * 24 * time.Hour
+)
+
+// Duplicate Run Protection
+const (
+	// DuplicateRunWindow - how far back the local run history is checked for
+	// a run with the same fingerprint as a new one
+	DuplicateRunWindow = 14 * 24 * time.Hour
+)
+
 // Local File Browser
 const (
 	// DirectoryReadTimeout - timeout for reading a local directory (30 seconds)
@@ -383,3 +606,79 @@ const (
 	// Used in: pipelined upload work items, folder-ready events, daemon log buffer.
 	WorkChannelBuffer = 100
 )
+
+// Upload History / Integrity Audit
+const (
+	// HistoryVerifyDefaultSample - uploads re-checked by `history verify`
+	// unless --all or --sample is given.
+	HistoryVerifyDefaultSample = 100
+
+	// HistoryRehashMaxSize - files larger than this are checked by metadata
+	// only under --rehash, since re-hashing downloads the whole file.
+	HistoryRehashMaxSize = 1 << 30
+
+	// HistoryBlockSamples - ciphertext blocks (ReadbackBlockSize each, the
+	// final one included) hashed into each upload history entry and read
+	// back by `history verify`
+	HistoryBlockSamples = 4
+)
+
+// Org Admin Mode
+const (
+	// DefaultAdminJobTag - tag that marks a job as submitted via Interlink in
+	// the admin view. Jobs tagged "<tag>-<anything>" (e.g. the suggested
+	// "interlink-{version}" default tag) also match.
+	DefaultAdminJobTag = "interlink"
+
+	// DefaultAdminSinceDays - window of recent jobs the admin view lists.
+	DefaultAdminSinceDays = 7
+
+	// AdminTagFetchWorkers - concurrent per-job tag lookups when listing
+	// organization jobs. The org job list does not include tags.
+	AdminTagFetchWorkers = 4
+
+	// AdminRequestTimeout - limit for one admin view request from the GUI
+	// (an org job listing plus one tags request per job).
+	AdminRequestTimeout = 2 * time.Minute
+)
+
+// Local REST Server (rescale-int serve)
+const (
+	// DefaultServeListen - address `serve` listens on. Only loopback
+	// addresses are accepted.
+	DefaultServeListen = "127.0.0.1:8642"
+
+	// ServeMaxRequestBody - limit for a JSON request body.
+	ServeMaxRequestBody = 1 << 20
+
+	// ServeEventKeepalive - interval between SSE comment lines, so clients
+	// and proxies do not time out an idle event stream.
+	ServeEventKeepalive = 15 * time.Second
+
+	// ServeShutdownTimeout - time to finish in-flight requests on shutdown.
+	ServeShutdownTimeout = 5 * time.Second
+)
+
+// Send to Rescale Interlink (shell context menu)
+const (
+	// SendToPollInterval - how often a running GUI or `serve` checks the
+	// send-to spool for new requests.
+	SendToPollInterval = 2 * time.Second
+
+	// SendToConsumerStale - a consumer heartbeat older than this means no
+	// GUI or `serve` is picking up requests, so `send-to` launches the GUI.
+	SendToConsumerStale = 10 * time.Second
+
+	// SendToClaimTimeout - a claimed request still in the spool after this
+	// long was abandoned (consumer crashed or upload could not start) and
+	// is claimed again.
+	SendToClaimTimeout = time.Minute
+)
+
+// Live job files (in-job access to a running job's work directory)
+const (
+	// MaxLiveUploadSize - largest file that can be uploaded into a running
+	// job's work directory (10 MB). Live uploads are meant for small
+	// correction files; inputs belong in the job's input files.
+	MaxLiveUploadSize = 10 * 1024 * 1024
+)
@@ -2,7 +2,7 @@ package core
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -15,20 +15,36 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cache"
+	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/events"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/localfs"
+	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/netwatch"
 	"github.com/rescale/rescale-int/internal/pathutil"
+	"github.com/rescale/rescale-int/internal/pur/jobarray"
 	"github.com/rescale/rescale-int/internal/pur/pattern"
 	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/report"
+	"github.com/rescale/rescale-int/internal/pur/repro"
+	"github.com/rescale/rescale-int/internal/pur/runhistory"
+	"github.com/rescale/rescale-int/internal/pur/runtimes"
 	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/pur/sweep"
 	"github.com/rescale/rescale-int/internal/pur/validation"
 	"github.com/rescale/rescale-int/internal/ratelimit"
 	"github.com/rescale/rescale-int/internal/reporting"
+	"github.com/rescale/rescale-int/internal/resources"
 	"github.com/rescale/rescale-int/internal/services"
+	"github.com/rescale/rescale-int/internal/transfer"
 	"github.com/rescale/rescale-int/internal/util/multipart"
+	"github.com/rescale/rescale-int/internal/util/tar"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"github.com/rescale/rescale-int/internal/watch"
 )
 
 // RunOptions groups PUR-specific pipeline options to avoid parameter creep.
@@ -36,6 +52,10 @@ type RunOptions struct {
 	ExtraInputFiles  string
 	DecompressExtras bool
 	RmTarOnSuccess   bool
+
+	// DryRun previews the run instead (see Engine.PreviewRun): nothing is
+	// archived, uploaded, created or written to the state file.
+	DryRun bool
 }
 
 // syncUploaderAdapter wraps TransferService to implement pipeline.SyncUploader.
@@ -55,6 +75,7 @@ func (a *syncUploaderAdapter) UploadFileSync(ctx context.Context, params pipelin
 		Tags:        params.Tags,
 	}, services.UploadFileSyncParams{
 		ExtraProgressCallback: params.ExtraProgressCallback,
+		PauseInBlackout:       params.PauseInBlackout,
 	})
 }
 
@@ -77,6 +98,9 @@ type Engine struct {
 	cancel    context.CancelFunc
 	mu        sync.RWMutex
 
+	// Reproducibility bundle of the most recent run, for error reports
+	lastReproPath string
+
 	runCtx   *RunContext
 	runCtxMu sync.RWMutex
 
@@ -84,8 +108,7 @@ type Engine struct {
 	fileService     *services.FileService
 
 	// Job monitoring
-	monitorTicker *time.Ticker
-	monitorStop   chan struct{}
+	monitorCancel context.CancelFunc
 	monitorWg     sync.WaitGroup
 
 	// Event publishing control (to prevent deadlocks)
@@ -113,7 +136,9 @@ func NewEngine(cfg *config.Config) (*Engine, error) {
 	// Create event bus
 	eventBus := events.NewEventBus(10000) // Increased buffer for rapid events
 
-	transferService := services.NewTransferService(apiClient, eventBus, services.TransferServiceConfig{})
+	transferService := services.NewTransferService(apiClient, eventBus, services.TransferServiceConfig{
+		PostDownload: cfg.PostDownload,
+	})
 	fileService := services.NewFileService(apiClient, eventBus)
 
 	return &Engine{
@@ -122,7 +147,6 @@ func NewEngine(cfg *config.Config) (*Engine, error) {
 		apiClient:       apiClient,
 		transferService: transferService,
 		fileService:     fileService,
-		monitorStop:     make(chan struct{}),
 		publishEvents:   true, // Enable by default
 	}, nil
 }
@@ -152,6 +176,7 @@ func (e *Engine) UpdateConfig(cfg *config.Config) error {
 	e.apiClient = apiClient
 	if e.transferService != nil {
 		e.transferService.SetAPIClient(apiClient)
+		e.transferService.SetPostDownload(cfg.PostDownload)
 	}
 	if e.fileService != nil {
 		e.fileService.SetAPIClient(apiClient)
@@ -290,12 +315,14 @@ type ScanOptions struct {
 	MultiPartMode     bool     // Enable multi-part mode (scan multiple project directories)
 	PartDirs          []string // Project directories for multi-part mode
 	TarSubpath        string   // Subdirectory within each Run_* to tar (optional)
+	OutputPatterns    []string // Output files to download into each run directory on completion (overrides the template)
+	SweepTable        string   // Parameter table (CSV or JSON) for sweep mode: one job per row instead of per directory
 }
 
-// Scan generates a jobs CSV from directory scan
+// Scan generates a jobs CSV from directory scan. Jobs are built by
+// ScanToSpecs, so every template field (tags, project, org, priority, ...)
+// is carried into the written CSV.
 func (e *Engine) Scan(opts ScanOptions) error {
-	e.publishLog(events.InfoLevel, "Starting directory scan...", "scan", "")
-
 	// Load template CSV
 	jobs, err := config.LoadJobsCSV(opts.TemplateCSV)
 	if err != nil {
@@ -307,227 +334,17 @@ func (e *Engine) Scan(opts ScanOptions) error {
 		return fmt.Errorf("template CSV is empty")
 	}
 
-	template := jobs[0]
-	e.publishLog(events.InfoLevel, fmt.Sprintf("Using template: %s", template.JobName), "scan", "")
-
-	validationPattern := opts.ValidationPattern
-
-	// Structure for directory entries
-	type dirEntry struct {
-		path        string
-		projectName string // For multi-part mode
-	}
-	var dirEntries []dirEntry
-
-	// Multi-part mode or normal mode
-	if opts.MultiPartMode {
-		// Multi-part mode: scan multiple project directories
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Multi-part mode enabled with %d project directories", len(opts.PartDirs)), "scan", "")
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Subdirectory pattern: '%s'", opts.Pattern), "scan", "")
-		if opts.RunSubpath != "" {
-			e.publishLog(events.InfoLevel, fmt.Sprintf("Run subpath: '%s'", opts.RunSubpath), "scan", "")
-		}
-		if validationPattern != "" {
-			e.publishLog(events.InfoLevel, fmt.Sprintf("Validation pattern: '%s'", validationPattern), "scan", "")
-		}
-
-		// Collect all run directories from all projects
-		allRuns, err := multipart.CollectAllRunDirectories(opts.PartDirs, opts.RunSubpath, opts.Pattern)
-		if err != nil {
-			e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to collect run directories: %v", err), "scan", "")
-			return err
-		}
-
-		if len(allRuns) == 0 {
-			return fmt.Errorf("no run directories found in any project (pattern='%s')", opts.Pattern)
-		}
-
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Found %d total run directories across all projects", len(allRuns)), "scan", "")
-
-		// Validate each run directory
-		validRuns := 0
-		for _, run := range allRuns {
-			if validationPattern == "" || multipart.ValidateRunDirectory(run.RunPath, validationPattern) {
-				dirEntries = append(dirEntries, dirEntry{
-					path:        run.RunPath,
-					projectName: run.ProjectName,
-				})
-				validRuns++
-				e.publishLog(events.InfoLevel, fmt.Sprintf("✓ Valid: %s from %s", run.RunName, run.ProjectName), "scan", "")
-			} else {
-				e.publishLog(events.DebugLevel, fmt.Sprintf("✗ Skipped: %s from %s (no %s file)", run.RunName, run.ProjectName, validationPattern), "scan", "")
-			}
-		}
-
-		if len(dirEntries) == 0 {
-			return fmt.Errorf("no valid run directories found (validation: %s)", validationPattern)
-		}
-
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Total valid runs after validation: %d/%d", validRuns, len(allRuns)), "scan", "")
-	} else {
-		// Normal mode - scan current directory
+	// Normal mode scans the current directory
+	if !opts.MultiPartMode {
 		cwd, _ := os.Getwd()
-
-		// Navigate through run_subpath if specified
-		scanRoot := cwd
-		if opts.RunSubpath != "" {
-			scanRoot = filepath.Join(cwd, opts.RunSubpath)
-			if _, err := os.Stat(scanRoot); os.IsNotExist(err) {
-				return fmt.Errorf("subpath '%s' not found under %s", opts.RunSubpath, cwd)
-			}
-			e.publishLog(events.InfoLevel, fmt.Sprintf("Scanning run directories under subpath: %s", opts.RunSubpath), "scan", "")
-		}
-
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Scanning directory: %s (pattern: %s)", scanRoot, opts.Pattern), "scan", "")
-
-		var dirs []string
-		if opts.Recursive {
-			// Recursive mode - walk directory tree.
-			// Uses WalkDir instead of Walk to avoid per-entry os.Stat syscalls.
-			err := filepath.WalkDir(scanRoot, func(path string, d fs.DirEntry, err error) error {
-				if err != nil {
-					return err
-				}
-				if d.IsDir() {
-					// Skip hidden directories unless specified
-					if !opts.IncludeHidden && localfs.IsHidden(path) && path != scanRoot {
-						return filepath.SkipDir
-					}
-					// Check if this directory matches the pattern
-					matched, matchErr := filepath.Match(opts.Pattern, filepath.Base(path))
-					if matchErr == nil && matched && path != scanRoot {
-						dirs = append(dirs, path)
-						// SkipDir: intentionally stop descending into matched directories.
-						// Run directories (e.g., Run_*) are expected to be siblings, not nested.
-						// This avoids walking the full contents of each matched directory,
-						// which is the primary source of slow recursive scans.
-						return filepath.SkipDir
-					}
-				}
-				return nil
-			})
-			if err != nil {
-				e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to walk directory: %v", err), "scan", "")
-				return fmt.Errorf("failed to walk directory tree: %w", err)
-			}
-		} else {
-			// Non-recursive mode - single level glob
-			matches, err := filepath.Glob(filepath.Join(scanRoot, opts.Pattern))
-			if err != nil {
-				e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to glob: %v", err), "scan", "")
-				return err
-			}
-			// Filter to directories only
-			for _, match := range matches {
-				info, err := os.Stat(match)
-				if err == nil && info.IsDir() {
-					if !opts.IncludeHidden && localfs.IsHidden(match) {
-						continue
-					}
-					dirs = append(dirs, match)
-				}
-			}
-		}
-
-		e.publishLog(events.InfoLevel, fmt.Sprintf("Found %d directories matching pattern", len(dirs)), "scan", "")
-
-		// Validate directories if validation pattern specified
-		var validDirs []string
-		if validationPattern != "" {
-			e.publishLog(events.InfoLevel, fmt.Sprintf("Validating directories (pattern: %s)", validationPattern), "scan", "")
-			for _, dir := range dirs {
-				if multipart.ValidateRunDirectory(dir, validationPattern) {
-					validDirs = append(validDirs, dir)
-				} else {
-					e.publishLog(events.DebugLevel, fmt.Sprintf("Skipped %s (no matching file)", filepath.Base(dir)), "scan", "")
-				}
-			}
-			if len(validDirs) == 0 {
-				return fmt.Errorf("no directories found matching validation pattern '%s'", validationPattern)
-			}
-			e.publishLog(events.InfoLevel, fmt.Sprintf("Valid directories after validation: %d/%d", len(validDirs), len(dirs)), "scan", "")
-		} else {
-			validDirs = dirs
-		}
-
-		// Sort directories
-		sort.Strings(validDirs)
-
-		// Convert to dirEntries (no project name in normal mode)
-		for _, dir := range validDirs {
-			dirEntries = append(dirEntries, dirEntry{
-				path:        dir,
-				projectName: "",
-			})
-		}
+		opts.PartDirs = []string{cwd}
 	}
 
-	// Extract template job index for pattern iteration
-	templateIdx := pattern.ExtractIndexFromJobName(template.JobName)
-	baseJobName := strings.TrimSuffix(template.JobName, "_1")
-
-	// Sort dirEntries by path
-	sort.Slice(dirEntries, func(i, j int) bool {
-		return dirEntries[i].path < dirEntries[j].path
-	})
-
-	// Generate CSV rows
-	var rows [][]string
-	for i, entry := range dirEntries {
-		dirNum := i + opts.StartIndex
-
-		// Extract directory number if using default start index
-		if opts.StartIndex == 1 {
-			baseName := filepath.Base(entry.path)
-			if match := regexp.MustCompile(`\d+`).FindString(baseName); match != "" {
-				if num, err := strconv.Atoi(match); err == nil {
-					dirNum = num
-				}
-			}
-		}
-
-		// Create job name with project suffix for multi-part mode
-		var jobName string
-		if opts.MultiPartMode && entry.projectName != "" {
-			// Multi-part mode: append project name to make unique
-			jobName = fmt.Sprintf("%s_%d_%s", baseJobName, dirNum, entry.projectName)
-		} else {
-			// Normal mode: just use base name and number
-			jobName = fmt.Sprintf("%s_%d", baseJobName, dirNum)
-		}
-
-		// Iterate command patterns if requested
-		command := template.Command
-		if opts.IteratePatterns {
-			command = pattern.IterateCommandPatterns(command, templateIdx, dirNum)
-		}
-
-		// Normalize directory path to absolute
-		dirPath := entry.path
-		if absPath, err := pathutil.ResolveAbsolutePath(entry.path); err == nil {
-			dirPath = absPath
-		}
-
-		// Build row
-		row := []string{
-			dirPath,
-			jobName,
-			template.AnalysisCode,
-			template.AnalysisVersion,
-			command,
-			template.CoreType,
-			strconv.Itoa(template.CoresPerSlot),
-			fmt.Sprintf("%.1f", template.WalltimeHours),
-			strconv.Itoa(template.Slots),
-			template.LicenseSettings,
-			template.ExtraInputFileIDs,
-			template.SubmitMode,
-		}
-		rows = append(rows, row)
+	specs, err := e.ScanToSpecs(jobs[0], opts)
+	if err != nil {
+		return err
 	}
 
-	e.publishLog(events.InfoLevel, fmt.Sprintf("Generated %d job entries", len(rows)), "scan", "")
-
 	// Check if output file exists
 	if !opts.Overwrite {
 		if _, err := os.Stat(opts.OutputCSV); err == nil {
@@ -535,28 +352,9 @@ func (e *Engine) Scan(opts ScanOptions) error {
 		}
 	}
 
-	// Write CSV
-	file, err := os.Create(opts.OutputCSV)
-	if err != nil {
-		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to create output: %v", err), "scan", "")
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write header
-	header := []string{"Directory", "JobName", "AnalysisCode", "AnalysisVersion", "Command", "CoreType", "CoresPerSlot", "WalltimeHours", "Slots", "LicenseSettings", "ExtraInputFileIDs", "SubmitMode"}
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
-	}
-
-	// Write rows
-	for _, row := range rows {
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row: %w", err)
-		}
+	if err := config.SaveJobsCSV(opts.OutputCSV, specs); err != nil {
+		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to write output: %v", err), "scan", "")
+		return err
 	}
 
 	e.publishLog(events.InfoLevel, fmt.Sprintf("Jobs CSV written to %s", opts.OutputCSV), "scan", "")
@@ -566,6 +364,10 @@ func (e *Engine) Scan(opts ScanOptions) error {
 // ScanToSpecs generates job specs from directory scan without writing CSV.
 // This is the CSV-less alternative to Scan() for GUI workflows.
 func (e *Engine) ScanToSpecs(template models.JobSpec, opts ScanOptions) ([]models.JobSpec, error) {
+	if opts.SweepTable != "" {
+		return e.sweepToSpecs(template, opts)
+	}
+
 	e.publishLog(events.InfoLevel, "Starting in-memory directory scan...", "scan", "")
 	e.publishLog(events.InfoLevel, fmt.Sprintf("Using template: %s", template.JobName), "scan", "")
 
@@ -749,8 +551,14 @@ func (e *Engine) ScanToSpecs(template models.JobSpec, opts ScanOptions) ([]model
 			}
 		}
 
-		// Create job from template
+		// Create job from template. Slices are copied so per-job edits never
+		// alias the template or sibling jobs.
 		job := template
+		job.Tags = append([]string(nil), template.Tags...)
+		job.Automations = append([]string(nil), template.Automations...)
+		job.InputFiles = append([]string(nil), template.InputFiles...)
+		job.OutputPatterns = append([]string(nil), template.OutputPatterns...)
+		job.DependsOn = append([]string(nil), template.DependsOn...)
 
 		// Normalize directory path to absolute
 		if absPath, err := pathutil.ResolveAbsolutePath(entry.path); err == nil {
@@ -774,6 +582,9 @@ func (e *Engine) ScanToSpecs(template models.JobSpec, opts ScanOptions) ([]model
 		if opts.TarSubpath != "" {
 			job.TarSubpath = opts.TarSubpath
 		}
+		if len(opts.OutputPatterns) > 0 {
+			job.OutputPatterns = append([]string(nil), opts.OutputPatterns...)
+		}
 
 		jobs = append(jobs, job)
 	}
@@ -782,12 +593,56 @@ func (e *Engine) ScanToSpecs(template models.JobSpec, opts ScanOptions) ([]model
 	return jobs, nil
 }
 
-// Plan validates a jobs CSV file
+// sweepToSpecs is the sweep mode of ScanToSpecs: it expands the template
+// into one job per row of opts.SweepTable, substituting the row's variables
+// into the job name and command. All jobs keep the template's directory.
+func (e *Engine) sweepToSpecs(template models.JobSpec, opts ScanOptions) ([]models.JobSpec, error) {
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Expanding template %s over parameter table %s", template.JobName, opts.SweepTable), "scan", "")
+
+	table, err := sweep.LoadTable(opts.SweepTable)
+	if err != nil {
+		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to load parameter table: %v", err), "scan", "")
+		return nil, err
+	}
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Parameter table has %d rows (variables: %s)", len(table.Rows), strings.Join(table.Names, ", ")), "scan", "")
+
+	if template.Directory != "" {
+		if absPath, err := pathutil.ResolveAbsolutePath(template.Directory); err == nil {
+			template.Directory = absPath
+		}
+	}
+	if opts.TarSubpath != "" {
+		template.TarSubpath = opts.TarSubpath
+	}
+	if len(opts.OutputPatterns) > 0 {
+		template.OutputPatterns = opts.OutputPatterns
+	}
+
+	startIndex := opts.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	jobs, err := sweep.Expand(template, table, startIndex)
+	if err != nil {
+		e.publishLog(events.ErrorLevel, fmt.Sprintf("Parameter sweep failed: %v", err), "scan", "")
+		return nil, fmt.Errorf("parameter sweep failed: %w", err)
+	}
+
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Generated %d jobs in memory", len(jobs)), "scan", "")
+	return jobs, nil
+}
+
+// Plan validates a jobs CSV file. Jobs are validated in parallel; with
+// validateCoreType the platform checks (core types, analysis versions,
+// projects) run against a catalog fetched once up front.
 func (e *Engine) Plan(jobsCSVPath string, validateCoreType bool) (*PlanResult, error) {
 	e.publishLog(events.InfoLevel, "Starting plan validation...", "plan", "")
 
-	// Load jobs
+	// Load jobs; array specs are validated as their members
 	jobs, err := config.LoadJobsCSV(jobsCSVPath)
+	if err == nil {
+		jobs, err = jobarray.Expand(jobs)
+	}
 	if err != nil {
 		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to load jobs: %v", err), "plan", "")
 		return nil, err
@@ -803,52 +658,124 @@ func (e *Engine) Plan(jobsCSVPath string, validateCoreType bool) (*PlanResult, e
 		Errors:      []string{},
 	}
 
-	// Optionally validate core types (only if API key is configured)
-	var validator *validation.CoreTypeValidator
+	// Optionally run the API-backed checks (only if API key is configured)
+	var catalog *validation.Catalog
+	var predictor *runtimes.Predictor
 	if validateCoreType {
 		// Check if API key is configured before attempting API calls
 		e.mu.RLock()
-		hasAPIKey := e.config.APIKey != ""
+		hasAPIKey := e.config != nil && e.config.APIKey != ""
+		orgCode := ""
+		if e.config != nil {
+			orgCode = e.config.OrgCode
+		}
+		apiClient := e.apiClient
 		e.mu.RUnlock()
 
-		if !hasAPIKey {
+		if !hasAPIKey || apiClient == nil {
 			e.publishLog(events.WarnLevel, "API key not configured - skipping core type validation", "plan", "")
 			e.publishLog(events.InfoLevel, "Configure API key in Setup tab for full validation", "plan", "")
 		} else {
-			e.publishLog(events.InfoLevel, "Fetching available core types...", "plan", "")
-			validator = validation.NewCoreTypeValidator(e.apiClient)
+			e.publishLog(events.InfoLevel, "Fetching core types, analyses and projects...", "plan", "")
 
 			// Use context with timeout to prevent hanging on network issues
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			if err := validator.FetchCoreTypes(ctx); err != nil {
-				e.publishLog(events.WarnLevel, fmt.Sprintf("Could not fetch core types: %v", err), "plan", "")
-				e.publishLog(events.InfoLevel, "Continuing validation without core type check", "plan", "")
-				validator = nil
+			var warnings []string
+			catalog, warnings = validation.LoadCatalog(ctx, apiClient, orgCode)
+			for _, w := range warnings {
+				e.publishLog(events.WarnLevel, w, "plan", "")
+			}
+			e.publishLog(events.InfoLevel, "Loaded platform catalog for validation", "plan", "")
+
+			apiBaseURL := ""
+			if cfg := e.GetConfig(); cfg != nil {
+				apiBaseURL = cfg.APIBaseURL
+			}
+			predictor = runtimes.NewPredictor(config.GetJobHistoryPath(apiBaseURL), apiClient)
+		}
+	}
+
+	// Validate jobs in parallel. Results come back in CSV order; progress
+	// is published as each job finishes.
+	mode := validation.SafetyPermissive
+	if cfg := e.GetConfig(); cfg != nil {
+		mode = cfg.JobValidationMode
+	}
+	results := validation.ValidateJobs(jobs, mode, catalog, checkJobDirectory,
+		func(i int, res validation.JobResult, done int) {
+			job := jobs[i]
+			for _, w := range res.Warnings {
+				e.publishLog(events.WarnLevel, fmt.Sprintf("Job %s: %s", job.JobName, w), "plan", job.JobName)
+			}
+			if len(res.Errors) > 0 {
+				for _, err := range res.Errors {
+					e.publishLog(events.WarnLevel, fmt.Sprintf("Job %d (%s): %s", i+1, job.JobName, err), "plan", job.JobName)
+				}
 			} else {
-				e.publishLog(events.InfoLevel, "Loaded core types for validation", "plan", "")
+				e.publishLog(events.DebugLevel, fmt.Sprintf("Job %s validated", job.JobName), "plan", job.JobName)
+			}
+			e.publishProgress("plan", "plan", float64(done)/float64(len(jobs)),
+				fmt.Sprintf("Validated %d of %d jobs", done, len(jobs)))
+		})
+	jobErrors := make([][]string, len(jobs))
+	for i, res := range results {
+		jobErrors[i] = res.Errors
+	}
+
+	// Duplicate job names: errors under job_name_policy=block, else warnings
+	namePolicy := validation.NamePolicyWarn
+	if cfg := e.GetConfig(); cfg != nil {
+		namePolicy = validation.NormalizeNamePolicy(cfg.JobNamePolicy)
+	}
+	nameCtx, nameCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	for _, c := range e.jobNameCollisions(nameCtx, jobs, catalog != nil) {
+		switch namePolicy {
+		case validation.NamePolicyBlock:
+			for _, row := range c.Rows {
+				jobErrors[row-1] = append(jobErrors[row-1], c.String())
 			}
+		case validation.NamePolicySuffix:
+			e.publishLog(events.WarnLevel, c.String()+" - will be renamed with a timestamp suffix when run", "plan", c.Name)
+		default:
+			e.publishLog(events.WarnLevel, c.String(), "plan", c.Name)
 		}
 	}
+	nameCancel()
 
-	for i, job := range jobs {
-		errors := e.validateJob(&job, validator)
-		if len(errors) > 0 {
-			result.InvalidJobs++
-			for _, err := range errors {
-				errMsg := fmt.Sprintf("Job %d (%s): %s", i+1, job.JobName, err)
-				result.Errors = append(result.Errors, errMsg)
-				e.publishLog(events.WarnLevel, errMsg, "plan", job.JobName)
+	// DependsOn references must name one other job of the run, without cycles
+	_, dependencyProblems := validation.ResolveDependencies(jobs)
+	for i, problems := range dependencyProblems {
+		jobErrors[i] = append(jobErrors[i], problems...)
+	}
+
+	// Walltime and cores against earlier jobs of the same template. Warnings
+	// only: the platform accepts any walltime.
+	if predictor != nil {
+		predictCtx, predictCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		for i, job := range jobs {
+			prediction, err := predictor.Predict(predictCtx, job)
+			if err != nil {
+				e.publishLog(events.WarnLevel, fmt.Sprintf("Could not read job history: %v", err), "plan", "")
+				break
+			}
+			for _, w := range runtimes.Warnings(job, prediction) {
+				e.publishLog(events.WarnLevel, fmt.Sprintf("Job %d (%s): %s", i+1, job.JobName, w), "plan", job.JobName)
 			}
-		} else {
-			result.ValidJobs++
-			e.publishLog(events.DebugLevel, fmt.Sprintf("Job %s validated", job.JobName), "plan", job.JobName)
 		}
+		predictCancel()
+	}
 
-		// Publish progress
-		e.publishProgress("plan", "plan", float64(i+1)/float64(len(jobs)),
-			fmt.Sprintf("Validating job %d of %d", i+1, len(jobs)))
+	for i, errs := range jobErrors {
+		if len(errs) == 0 {
+			result.ValidJobs++
+			continue
+		}
+		result.InvalidJobs++
+		for _, err := range errs {
+			result.Errors = append(result.Errors, fmt.Sprintf("Job %d (%s): %s", i+1, jobs[i].JobName, err))
+		}
 	}
 
 	if result.InvalidJobs > 0 {
@@ -866,7 +793,29 @@ func (e *Engine) Plan(jobsCSVPath string, validateCoreType bool) (*PlanResult, e
 
 // Run executes the full pipeline
 func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string) error {
-	// Check if API key is configured before starting pipeline
+	return e.RunWithOptions(ctx, jobsCSVPath, stateFile, RunOptions{})
+}
+
+// RunWithOptions is Run with PUR pipeline options. With opts.DryRun the jobs
+// are previewed and no API key is needed.
+func (e *Engine) RunWithOptions(ctx context.Context, jobsCSVPath string, stateFile string, opts RunOptions) error {
+	if opts.DryRun {
+		jobs, err := config.LoadJobsCSV(jobsCSVPath)
+		if err != nil {
+			return err
+		}
+		_, err = e.PreviewRun(ctx, jobs, stateFile, opts)
+		return err
+	}
+	if err := e.requireRunAPIKey(); err != nil {
+		return err
+	}
+	return e.runJobsCSV(ctx, jobsCSVPath, stateFile, nil, opts)
+}
+
+// requireRunAPIKey checks that an API key is configured before a pipeline
+// run starts.
+func (e *Engine) requireRunAPIKey() error {
 	e.mu.RLock()
 	hasAPIKey := e.config.APIKey != ""
 	e.mu.RUnlock()
@@ -874,7 +823,12 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 	if !hasAPIKey {
 		return fmt.Errorf("API key not configured - please enter your API key in the Setup tab and click 'Apply Changes' before running jobs")
 	}
+	return nil
+}
 
+// runJobsCSV runs the pipeline for the jobs in jobsCSVPath. When only is
+// non-nil, just the jobs with those state indices are run.
+func (e *Engine) runJobsCSV(ctx context.Context, jobsCSVPath string, stateFile string, only []int, opts RunOptions) error {
 	e.mu.Lock()
 
 	// Create cancellable context
@@ -899,16 +853,28 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 	}
 
 	// Create pipeline with shared state manager
-	pip, err := pipeline.NewPipeline(e.config, e.apiClient, jobs, stateFile, false, e.state, false, "", false)
+	pip, err := pipeline.NewPipeline(e.config, e.apiClient, jobs, stateFile, false, e.state, false, opts.ExtraInputFiles, opts.DecompressExtras)
 	if err != nil {
 		e.mu.Unlock()
 		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to create pipeline: %v", err), "run", "")
 		return err
 	}
+	e.pipeline = pip
+	if only != nil {
+		pip.SetJobFilter(only)
+	}
+	e.writeReproBundle(jobs, repro.Options{
+		StateFile:        stateFile,
+		JobsSource:       jobsCSVPath,
+		Resumed:          only != nil,
+		ExtraInputFiles:  opts.ExtraInputFiles,
+		DecompressExtras: opts.DecompressExtras,
+	})
 
 	if e.transferService != nil {
 		pip.SetSyncUploader(&syncUploaderAdapter{ts: e.transferService})
 	}
+	pip.SetRmTarOnSuccess(opts.RmTarOnSuccess)
 
 	// Set up callbacks to publish to event bus
 	pip.SetLogCallback(func(level, message, stage, jobName string) {
@@ -925,7 +891,7 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 		default:
 			eventLevel = events.InfoLevel
 		}
-		e.publishLog(eventLevel, message, stage, jobName)
+		e.publishRunLog(pip.RunID(), eventLevel, message, stage, jobName)
 	})
 
 	pip.SetProgressCallback(func(completed, total int, stage, jobName string) {
@@ -937,6 +903,7 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventProgress,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			Progress: progress,
 			Stage:    stage,
@@ -945,6 +912,12 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 		})
 	})
 
+	pip.SetTarProgressCallback(func(jobName string, pr tar.Progress) {
+		e.publishTarProgress(pip.RunID(), jobName, pr)
+	})
+
+	pip.SetRunStartCallback(e.publishRunStarted)
+
 	pip.SetStateChangeCallback(func(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
 		e.publishLog(events.DebugLevel, fmt.Sprintf("[DEBUG] StateChangeCallback: job=%s, stage=%s, status=%s, progress=%.2f",
 			jobName, stage, newStatus, uploadProgress), "engine", "")
@@ -957,6 +930,7 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventStateChange,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			JobName:        jobName,
 			Stage:          stage,
@@ -980,16 +954,25 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 	// Get final stats
 	stats := e.getJobStats()
 
+	// Write the run summary artifact next to the state file
+	reportPath := e.writeRunReport(pip, stateFile, startTime)
+
 	// Emit completion event
 	e.eventBus.Publish(&events.CompleteEvent{
 		BaseEvent: events.BaseEvent{
 			EventType: events.EventComplete,
 			Time:      time.Now(),
+			RunID:     pip.RunID(),
 		},
-		TotalJobs:   stats.Total,
-		SuccessJobs: stats.Completed,
-		FailedJobs:  stats.Failed,
-		Duration:    duration,
+		TotalJobs:     stats.Total,
+		SuccessJobs:   stats.Completed,
+		FailedJobs:    stats.Failed,
+		Duration:      duration,
+		ReportPath:    reportPath,
+		StateFile:     stateFile,
+		BytesSent:     pip.NetworkUsage().Sent,
+		BytesReceived: pip.NetworkUsage().Received,
+		Failures:      stats.Failures,
 	})
 
 	// Only report if all jobs failed, not cancelled, and there were jobs to run.
@@ -1002,27 +985,144 @@ func (e *Engine) Run(ctx context.Context, jobsCSVPath string, stateFile string)
 	}
 
 	if err != nil {
-		if err == context.Canceled {
-			e.publishLog(events.InfoLevel, "Pipeline stopped by user", "run", "")
-		} else {
-			e.publishLog(events.ErrorLevel, fmt.Sprintf("Pipeline error: %v", err), "run", "")
-		}
-		return err
+		if err == context.Canceled {
+			e.publishLog(events.InfoLevel, "Pipeline stopped by user", "run", "")
+		} else {
+			e.publishLog(events.ErrorLevel, fmt.Sprintf("Pipeline error: %v", err), "run", "")
+		}
+		return err
+	}
+
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Pipeline completed successfully in %s", duration.Round(time.Second)), "run", "")
+	return nil
+}
+
+// Resume resumes a pipeline from state file
+// Note: Resume is essentially the same as Run but uses existing state
+// The jobs CSV must still be provided as the source of truth for job specs
+func (e *Engine) Resume(ctx context.Context, jobsCSVPath string, stateFile string) error {
+	e.publishLog(events.InfoLevel, "Resuming from state file...", "resume", "")
+
+	// Resume is just Run with an existing state file
+	// The pipeline will automatically skip completed jobs
+	return e.Run(ctx, jobsCSVPath, stateFile)
+}
+
+// PreviewRun performs a dry run of jobs (see pipeline.Pipeline.Preview) and
+// writes the preview to pipeline.PreviewPath(stateFile): the archives each
+// job would get, how they would be uploaded and the exact job creation
+// request. No mutating API endpoint is called and the state file is not
+// read or written. Analysis versions are resolved if an API key is set.
+func (e *Engine) PreviewRun(ctx context.Context, jobs []models.JobSpec, stateFile string, opts RunOptions) (*pipeline.Preview, error) {
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no jobs provided")
+	}
+
+	e.mu.RLock()
+	cfg, apiClient := e.config, e.apiClient
+	if cfg.APIKey == "" {
+		apiClient = nil // Versions can't be resolved; don't wait on the API
+	}
+	e.mu.RUnlock()
+
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Dry run: previewing %d jobs...", len(jobs)), "run", "")
+
+	// A manager that is never loaded or saved keeps the state file untouched
+	pip, err := pipeline.NewPipeline(cfg, apiClient, jobs, stateFile, false, state.NewManager(stateFile), false, opts.ExtraInputFiles, opts.DecompressExtras)
+	if err != nil {
+		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to create pipeline: %v", err), "run", "")
+		return nil, err
+	}
+	pip.SetLogCallback(func(level, message, stage, jobName string) {
+		eventLevel := events.InfoLevel
+		switch level {
+		case "DEBUG":
+			eventLevel = events.DebugLevel
+		case "WARN":
+			eventLevel = events.WarnLevel
+		case "ERROR":
+			eventLevel = events.ErrorLevel
+		}
+		e.publishRunLog(pip.RunID(), eventLevel, message, stage, jobName)
+	})
+
+	startTime := time.Now()
+	preview := pip.Preview(ctx)
+	for _, job := range preview.Jobs {
+		if job.Error != "" {
+			e.publishLog(events.WarnLevel, fmt.Sprintf("Dry run: %s would fail: %s", job.JobName, job.Error), "run", job.JobName)
+		}
+	}
+
+	previewPath := ""
+	if stateFile != "" {
+		previewPath = pipeline.PreviewPath(stateFile)
+		if err := preview.WriteJSON(previewPath); err != nil {
+			e.publishLog(events.WarnLevel, fmt.Sprintf("Failed to write dry-run preview: %v", err), "run", "")
+			previewPath = ""
+		}
+	}
+
+	msg := fmt.Sprintf("Dry run complete: %d jobs, %d files, %s to archive and upload",
+		len(preview.Jobs), preview.TotalFiles, cloud.FormatBytes(preview.TotalBytes))
+	if previewPath != "" {
+		msg += "; preview written to " + previewPath
+	}
+	e.publishLog(events.InfoLevel, msg, "run", "")
+
+	e.eventBus.Publish(&events.CompleteEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventComplete,
+			Time:      time.Now(),
+			RunID:     pip.RunID(),
+		},
+		TotalJobs:   len(preview.Jobs),
+		SuccessJobs: len(preview.Jobs) - preview.Errors,
+		FailedJobs:  preview.Errors,
+		Duration:    time.Since(startTime),
+	})
+	return preview, ctx.Err()
+}
+
+// RetryFailed re-runs only the jobs of a run whose tar, upload or submit
+// failed. Their rows in the state file are reset to pending from the stage
+// that failed (see state.Manager.ResetFailed); the rows of jobs that
+// succeeded are kept and those jobs are not run again.
+// The jobs CSV must be the one the run was started with.
+func (e *Engine) RetryFailed(ctx context.Context, jobsCSVPath string, stateFile string) error {
+	if err := e.requireRunAPIKey(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(stateFile); err != nil {
+		return fmt.Errorf("state file does not exist: %s", stateFile)
+	}
+
+	st := state.NewManager(stateFile)
+	if err := st.Load(); err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	// Hold the run's lock across the reset so a live run isn't overwritten;
+	// the pipeline reuses it
+	if err := st.AcquireLock(); err != nil {
+		return fmt.Errorf("cannot retry run: %w", err)
+	}
+	defer st.ReleaseLock()
+
+	failed, err := st.ResetFailed()
+	if err != nil {
+		return fmt.Errorf("failed to reset failed jobs: %w", err)
+	}
+	if len(failed) == 0 {
+		e.publishLog(events.InfoLevel, "No failed jobs to retry", "retry", "")
+		return nil
 	}
 
-	e.publishLog(events.InfoLevel, fmt.Sprintf("Pipeline completed successfully in %s", duration.Round(time.Second)), "run", "")
-	return nil
-}
-
-// Resume resumes a pipeline from state file
-// Note: Resume is essentially the same as Run but uses existing state
-// The jobs CSV must still be provided as the source of truth for job specs
-func (e *Engine) Resume(ctx context.Context, jobsCSVPath string, stateFile string) error {
-	e.publishLog(events.InfoLevel, "Resuming from state file...", "resume", "")
+	e.mu.Lock()
+	e.state = st
+	e.mu.Unlock()
 
-	// Resume is just Run with an existing state file
-	// The pipeline will automatically skip completed jobs
-	return e.Run(ctx, jobsCSVPath, stateFile)
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Retrying %d failed jobs...", len(failed)), "retry", "")
+	return e.runJobsCSV(ctx, jobsCSVPath, stateFile, failed, RunOptions{})
 }
 
 // RunFromSpecs executes the pipeline from an in-memory job list.
@@ -1062,6 +1162,8 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to create pipeline: %v", err), "run", "")
 		return err
 	}
+	e.pipeline = pip
+	e.writeReproBundle(jobs, repro.Options{StateFile: stateFile})
 
 	if e.transferService != nil {
 		pip.SetSyncUploader(&syncUploaderAdapter{ts: e.transferService})
@@ -1082,7 +1184,7 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 		default:
 			eventLevel = events.InfoLevel
 		}
-		e.publishLog(eventLevel, message, stage, jobName)
+		e.publishRunLog(pip.RunID(), eventLevel, message, stage, jobName)
 	})
 
 	pip.SetProgressCallback(func(completed, total int, stage, jobName string) {
@@ -1094,6 +1196,7 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventProgress,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			Progress: progress,
 			Stage:    stage,
@@ -1102,6 +1205,12 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 		})
 	})
 
+	pip.SetTarProgressCallback(func(jobName string, pr tar.Progress) {
+		e.publishTarProgress(pip.RunID(), jobName, pr)
+	})
+
+	pip.SetRunStartCallback(e.publishRunStarted)
+
 	pip.SetStateChangeCallback(func(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
 		e.publishLog(events.DebugLevel, fmt.Sprintf("[DEBUG] StateChangeCallback: job=%s, stage=%s, status=%s, progress=%.2f",
 			jobName, stage, newStatus, uploadProgress), "engine", "")
@@ -1114,6 +1223,7 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventStateChange,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			JobName:        jobName,
 			Stage:          stage,
@@ -1137,16 +1247,25 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 	// Get final stats
 	stats := e.getJobStats()
 
+	// Write the run summary artifact next to the state file
+	reportPath := e.writeRunReport(pip, stateFile, startTime)
+
 	// Emit completion event
 	e.eventBus.Publish(&events.CompleteEvent{
 		BaseEvent: events.BaseEvent{
 			EventType: events.EventComplete,
 			Time:      time.Now(),
+			RunID:     pip.RunID(),
 		},
-		TotalJobs:   stats.Total,
-		SuccessJobs: stats.Completed,
-		FailedJobs:  stats.Failed,
-		Duration:    duration,
+		TotalJobs:     stats.Total,
+		SuccessJobs:   stats.Completed,
+		FailedJobs:    stats.Failed,
+		Duration:      duration,
+		ReportPath:    reportPath,
+		StateFile:     stateFile,
+		BytesSent:     pip.NetworkUsage().Sent,
+		BytesReceived: pip.NetworkUsage().Received,
+		Failures:      stats.Failures,
 	})
 
 	if err != nil {
@@ -1165,6 +1284,11 @@ func (e *Engine) RunFromSpecs(ctx context.Context, jobs []models.JobSpec, stateF
 // RunFromSpecsWithOptions executes the pipeline from an in-memory job list with
 // additional PUR options (extra input files, decompress flag, tar cleanup).
 func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobSpec, stateFile string, opts RunOptions) error {
+	if opts.DryRun {
+		_, err := e.PreviewRun(ctx, jobs, stateFile, opts)
+		return err
+	}
+
 	// Check if API key is configured before starting pipeline
 	e.mu.RLock()
 	hasAPIKey := e.config.APIKey != ""
@@ -1198,6 +1322,12 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to create pipeline: %v", err), "run", "")
 		return err
 	}
+	e.pipeline = pip
+	e.writeReproBundle(jobs, repro.Options{
+		StateFile:        stateFile,
+		ExtraInputFiles:  opts.ExtraInputFiles,
+		DecompressExtras: opts.DecompressExtras,
+	})
 
 	if e.transferService != nil {
 		pip.SetSyncUploader(&syncUploaderAdapter{ts: e.transferService})
@@ -1219,7 +1349,7 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 		default:
 			eventLevel = events.InfoLevel
 		}
-		e.publishLog(eventLevel, message, stage, jobName)
+		e.publishRunLog(pip.RunID(), eventLevel, message, stage, jobName)
 	})
 
 	pip.SetProgressCallback(func(completed, total int, stage, jobName string) {
@@ -1231,6 +1361,7 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventProgress,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			Progress: progress,
 			Stage:    stage,
@@ -1239,6 +1370,12 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 		})
 	})
 
+	pip.SetTarProgressCallback(func(jobName string, pr tar.Progress) {
+		e.publishTarProgress(pip.RunID(), jobName, pr)
+	})
+
+	pip.SetRunStartCallback(e.publishRunStarted)
+
 	pip.SetStateChangeCallback(func(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
 		e.publishLog(events.DebugLevel, fmt.Sprintf("[DEBUG] StateChangeCallback: job=%s, stage=%s, status=%s, progress=%.2f",
 			jobName, stage, newStatus, uploadProgress), "engine", "")
@@ -1251,6 +1388,7 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 			BaseEvent: events.BaseEvent{
 				EventType: events.EventStateChange,
 				Time:      time.Now(),
+				RunID:     pip.RunID(),
 			},
 			JobName:        jobName,
 			Stage:          stage,
@@ -1274,16 +1412,25 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 	// Get final stats
 	stats := e.getJobStats()
 
+	// Write the run summary artifact next to the state file
+	reportPath := e.writeRunReport(pip, stateFile, startTime)
+
 	// Emit completion event
 	e.eventBus.Publish(&events.CompleteEvent{
 		BaseEvent: events.BaseEvent{
 			EventType: events.EventComplete,
 			Time:      time.Now(),
+			RunID:     pip.RunID(),
 		},
-		TotalJobs:   stats.Total,
-		SuccessJobs: stats.Completed,
-		FailedJobs:  stats.Failed,
-		Duration:    duration,
+		TotalJobs:     stats.Total,
+		SuccessJobs:   stats.Completed,
+		FailedJobs:    stats.Failed,
+		Duration:      duration,
+		ReportPath:    reportPath,
+		StateFile:     stateFile,
+		BytesSent:     pip.NetworkUsage().Sent,
+		BytesReceived: pip.NetworkUsage().Received,
+		Failures:      stats.Failures,
 	})
 
 	// Only report if all jobs failed, not cancelled, and there were jobs to run.
@@ -1308,6 +1455,77 @@ func (e *Engine) RunFromSpecsWithOptions(ctx context.Context, jobs []models.JobS
 	return nil
 }
 
+// StageDurations returns per-job, per-stage elapsed times of the current or
+// most recent run (job index -> stage -> duration), or nil before any run.
+func (e *Engine) StageDurations() map[int]map[string]time.Duration {
+	e.mu.RLock()
+	pip := e.pipeline
+	e.mu.RUnlock()
+	if pip == nil {
+		return nil
+	}
+	return pip.StageDurations()
+}
+
+// writeReproBundle records the run's reproducibility bundle next to
+// stateFile. Called with e.mu held. Failures are logged, never fatal.
+func (e *Engine) writeReproBundle(jobs []models.JobSpec, opts repro.Options) {
+	if opts.StateFile == "" {
+		return
+	}
+	path := repro.DefaultPath(opts.StateFile)
+	if err := repro.New(e.config, jobs, opts).WriteJSON(path); err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Failed to write reproducibility bundle: %v", err), "run", "")
+		return
+	}
+	e.lastReproPath = path
+}
+
+// LastReproBundle returns the reproducibility bundle of the most recent run
+// started by this engine, or nil if there is none.
+func (e *Engine) LastReproBundle() *repro.Bundle {
+	e.mu.RLock()
+	path := e.lastReproPath
+	e.mu.RUnlock()
+	if path == "" {
+		return nil
+	}
+	b, err := repro.ReadJSON(path)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// writeRunReport writes the HTML/JSON run report next to stateFile and
+// returns the HTML path, or "" if no report could be written.
+func (e *Engine) writeRunReport(pip *pipeline.Pipeline, stateFile string, start time.Time) string {
+	if stateFile == "" {
+		return ""
+	}
+
+	e.mu.RLock()
+	platformURL := e.config.APIBaseURL
+	e.mu.RUnlock()
+
+	htmlPath, jsonPath := report.DefaultPaths(stateFile)
+	rep := report.Build(pip.StateManager().GetAllStates(), report.Options{
+		RunID:          strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile)),
+		StateFile:      stateFile,
+		PlatformURL:    platformURL,
+		StartTime:      start,
+		EndTime:        time.Now(),
+		StageDurations: pip.StageDurations(),
+		NetworkUsage:   pip.NetworkUsage(),
+	})
+	if err := rep.WriteFiles(htmlPath, jsonPath); err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Failed to write run report: %v", err), "run", "")
+		return ""
+	}
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Run report written to %s", htmlPath), "run", "")
+	return htmlPath
+}
+
 // Stop cancels any running operations
 func (e *Engine) Stop() {
 	e.mu.RLock()
@@ -1332,57 +1550,227 @@ func (e *Engine) GetJobStatus(jobID string) (string, error) {
 	return job.JobStatus.Status, nil
 }
 
-// StartJobMonitoring starts monitoring job statuses on Rescale
+// StopJob asks the platform to stop a running or queued job, then publishes
+// the job's refreshed status so monitors and the GUI table update immediately
+// instead of waiting for the next poll.
+func (e *Engine) StopJob(ctx context.Context, jobID string) (string, error) {
+	if jobID == "" {
+		return "", fmt.Errorf("job ID is required")
+	}
+
+	e.mu.RLock()
+	client := e.apiClient
+	st := e.state
+	e.mu.RUnlock()
+
+	if client == nil {
+		return "", fmt.Errorf("API client not configured")
+	}
+
+	// Resolve the pipeline job (if this job belongs to the active run)
+	var jobState *models.JobState
+	jobName := ""
+	if st != nil {
+		for _, js := range st.GetAllStates() {
+			if js.JobID == jobID {
+				jobState = js
+				jobName = js.JobName
+				break
+			}
+		}
+	}
+
+	if err := client.StopJob(ctx, jobID); err != nil {
+		e.publishLog(events.ErrorLevel, fmt.Sprintf("Failed to stop job %s: %v", jobID, err), "monitor", jobName)
+		return "", err
+	}
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Stop requested for job %s", jobID), "monitor", jobName)
+
+	// The platform transitions asynchronously; report whatever it says now,
+	// falling back to "Stopping" when the status read fails.
+	status := "Stopping"
+	if job, err := client.GetJob(ctx, jobID); err == nil && job.JobStatus.Status != "" {
+		status = job.JobStatus.Status
+	}
+
+	// Record the stop in the run's state file; outputs of a stopped job
+	// are not downloaded, as for any job that ends without completing
+	if jobState != nil {
+		jobState.ErrorMessage = fmt.Sprintf("stopped by user (%s)", status)
+		if jobState.OutputStatus == "pending" {
+			jobState.OutputStatus = "skipped"
+		}
+		st.UpdateState(jobState)
+		if err := st.Save(); err != nil {
+			e.publishLog(events.WarnLevel, fmt.Sprintf("Failed to record stop of job %s in state file: %v", jobID, err), "monitor", jobName)
+		}
+	}
+
+	e.eventBus.Publish(&events.StateChangeEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventStateChange,
+			Time:      time.Now(),
+		},
+		JobName:   jobName,
+		Stage:     "status",
+		NewStatus: status,
+		JobID:     jobID,
+	})
+	return status, nil
+}
+
+// StartJobMonitoring starts monitoring the run's submitted jobs on Rescale and
+// publishes status changes to the EventBus. It uses the platform's job event
+// channel when available, so changes arrive within seconds at one request per
+// wait window; otherwise it polls each non-terminal job every interval.
 func (e *Engine) StartJobMonitoring(interval time.Duration) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.monitorTicker != nil {
+	if e.monitorCancel != nil {
 		return // Already monitoring
 	}
 
-	e.monitorTicker = time.NewTicker(interval)
-	tickerC := e.monitorTicker.C   // Capture channel to avoid race with StopJobMonitoring
-	stopC := e.monitorStop         // Capture stop channel as well
+	ctx, cancel := context.WithCancel(context.Background())
+	e.monitorCancel = cancel
+
+	monitor := watch.NewMonitor(
+		watch.MonitorConfig{PollInterval: interval},
+		e.monitoredJobIDs,
+		e.monitorStatus,
+		e.monitorEvents,
+		&watch.Callbacks{
+			OnStatusChange: e.publishJobStatus,
+			OnError: func(jobID string, err error) {
+				e.publishLog(events.WarnLevel,
+					fmt.Sprintf("Failed to get status for job %s: %v", jobID, err),
+					"monitor", e.jobNameForID(jobID))
+			},
+			OnStreamError: func(err error) {
+				e.publishLog(events.WarnLevel,
+					fmt.Sprintf("Job event stream error (retrying): %v", err),
+					"monitor", "")
+			},
+			OnFallback: func(reason error) {
+				e.publishLog(events.InfoLevel,
+					fmt.Sprintf("Job event channel unavailable (%v); polling every %v", reason, interval),
+					"monitor", "")
+			},
+		},
+	)
 
 	e.monitorWg.Add(1)
 	go func() {
 		defer e.monitorWg.Done()
+		_ = monitor.Run(ctx)
+	}()
+
+	e.publishLog(events.InfoLevel, fmt.Sprintf("Started job monitoring (poll fallback interval: %v)", interval), "", "")
+}
+
+// WatchNetwork follows the default network route until ctx is cancelled.
+// When the route disappears the transfer queue is paused; when a route
+// appears or changes (Wi-Fi → VPN), connections bound to the old route are
+// closed so in-flight parts retry over the new one, and the queue resumes.
+func (e *Engine) WatchNetwork(ctx context.Context) {
+	w := netwatch.New(constants.NetworkProbeInterval, e.handleNetworkChange)
+	go w.Run(ctx)
+}
+
+// handleNetworkChange applies a route change and publishes it.
+func (e *Engine) handleNetworkChange(c netwatch.Change) {
+	var queue *transfer.Queue
+	if e.transferService != nil {
+		queue = e.transferService.GetQueue()
+	}
+
+	if !c.Online() {
+		if queue != nil {
+			queue.PauseForNetwork()
+		}
+		e.publishLog(events.WarnLevel, "Network connection lost - transfers paused until it is back", "network", "")
+	} else {
+		closed := inthttp.ResetAllConnections()
+		e.mu.RLock()
+		apiClient := e.apiClient
+		e.mu.RUnlock()
+		if apiClient != nil {
+			apiClient.CloseIdleConnections()
+		}
+		resumed := 0
+		if queue != nil {
+			resumed = queue.ResumeForNetwork()
+		}
+		e.publishLog(events.InfoLevel,
+			fmt.Sprintf("Network changed (%s) - reconnected %d connection(s), resumed %d transfer(s)", c.New, closed, resumed),
+			"network", "")
+	}
+
+	e.eventBus.Publish(&events.NetworkChangedEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventNetworkChanged,
+			Time:      time.Now(),
+		},
+		OldRoute: c.Old,
+		NewRoute: c.New,
+		Online:   c.Online(),
+	})
+}
+
+// WatchBlackout publishes a BlackoutChangedEvent whenever a blackout window
+// (blackout_windows) starts or ends, until ctx is cancelled. The pipeline and
+// uploads pause themselves; this only reports it.
+func (e *Engine) WatchBlackout(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(constants.BlackoutCheckInterval)
+		defer ticker.Stop()
+
+		var last time.Time
 		for {
+			if until := resources.BlackoutUntil(time.Now()); !until.Equal(last) {
+				last = until
+				if until.IsZero() {
+					e.publishLog(events.InfoLevel, "Blackout window over - tar and upload work resumes", "blackout", "")
+				} else {
+					e.publishLog(events.InfoLevel,
+						fmt.Sprintf("Blackout window until %s - new tar and upload work paused", until.Format("15:04")),
+						"blackout", "")
+				}
+				e.eventBus.Publish(&events.BlackoutChangedEvent{
+					BaseEvent: events.BaseEvent{
+						EventType: events.EventBlackoutChanged,
+						Time:      time.Now(),
+					},
+					Active: !until.IsZero(),
+					Until:  until,
+				})
+			}
+
 			select {
-			case <-tickerC:
-				e.checkJobStatuses()
-			case <-stopC:
+			case <-ticker.C:
+			case <-ctx.Done():
 				return
 			}
 		}
 	}()
-
-	e.publishLog(events.InfoLevel, fmt.Sprintf("Started job monitoring (interval: %v)", interval), "", "")
 }
 
 // StopJobMonitoring stops job status monitoring.
 // Waits for the goroutine to exit before returning to prevent race conditions.
 func (e *Engine) StopJobMonitoring() {
 	e.mu.Lock()
-	if e.monitorTicker == nil {
-		e.mu.Unlock()
+	cancel := e.monitorCancel
+	e.monitorCancel = nil
+	e.mu.Unlock()
+
+	if cancel == nil {
 		return
 	}
+	cancel()
 
-	e.monitorTicker.Stop()
-	e.monitorTicker = nil
-	close(e.monitorStop)
-	e.mu.Unlock()
-
-	// Wait for goroutine to actually exit before creating new channel.
-	// This prevents race conditions when start is called immediately after stop.
+	// Wait for the monitor to exit so a following Start begins from a clean slate
 	e.monitorWg.Wait()
 
-	e.mu.Lock()
-	e.monitorStop = make(chan struct{})
-	e.mu.Unlock()
-
 	e.publishLog(events.InfoLevel, "Stopped job monitoring", "", "")
 }
 
@@ -1500,6 +1888,10 @@ func (e *Engine) LoadState(stateFile string) ([]*models.JobState, error) {
 // Also initializes the state manager here (before the goroutine) so that
 // GetState()/GetRunStats() is never nil while a run is active.
 func (e *Engine) StartRun(runID, stateFile string, totalJobs int) error {
+	if err := viewer.Check("run jobs"); err != nil {
+		return err
+	}
+
 	e.runCtxMu.Lock()
 	defer e.runCtxMu.Unlock()
 
@@ -1556,6 +1948,30 @@ func (e *Engine) EndRun() {
 	if e.runCtx != nil {
 		e.publishLog(events.InfoLevel, fmt.Sprintf("Ended run %s", e.runCtx.RunID), "run", "")
 		e.runCtx = nil
+		go e.EnforceCacheLimits()
+	}
+}
+
+// EnforceCacheLimits evicts least recently used cached files (staged tars,
+// resume sidecars) until they fit the configured cache_max_mb and
+// cache_limits. It does nothing when no limit is set.
+func (e *Engine) EnforceCacheLimits() {
+	cfg := e.GetConfig()
+	if !cache.Configured(cfg) {
+		return
+	}
+	mgr, err := cache.New(cfg)
+	if err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Cache limits ignored: %v", err), "cache", "")
+		return
+	}
+	res := mgr.Enforce()
+	for _, err := range res.Errors {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Cache eviction: %v", err), "cache", "")
+	}
+	if res.Removed > 0 {
+		e.publishLog(events.InfoLevel, fmt.Sprintf("Cache limit: evicted %d file(s), freed %s",
+			res.Removed, cloud.FormatBytes(res.Freed)), "cache", "")
 	}
 }
 
@@ -1619,10 +2035,51 @@ func (e *Engine) GetRunStats() (total, completed, failed, pending int) {
 
 // Private helper methods
 
+// publishTarProgress publishes a job's archive progress as a tar-stage
+// progress event.
+func (e *Engine) publishTarProgress(runID, jobName string, pr tar.Progress) {
+	e.eventBus.Publish(&events.ProgressEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventProgress,
+			Time:      time.Now(),
+			RunID:     runID,
+		},
+		JobName:      jobName,
+		Stage:        "tar",
+		Progress:     pr.Fraction(),
+		BytesCurrent: pr.Bytes,
+		BytesTotal:   pr.TotalBytes,
+		Message:      pipeline.FormatTarProgress(pr),
+		Rate:         pr.Rate(),
+		ETA:          pr.ETA(),
+		FilesCurrent: pr.Files,
+		FilesTotal:   pr.TotalFiles,
+		CurrentFile:  pr.CurrentFile,
+	})
+}
+
+// publishRunStarted announces the start of a pipeline run, for webhooks
+// and chat notifications.
+func (e *Engine) publishRunStarted(runID string, totalJobs int) {
+	e.eventBus.Publish(&events.RunStartedEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventRunStarted,
+			Time:      time.Now(),
+			RunID:     runID,
+		},
+		TotalJobs: totalJobs,
+	})
+}
+
 func (e *Engine) publishLog(level events.LogLevel, message, stage, jobName string) {
+	e.publishRunLog("", level, message, stage, jobName)
+}
+
+// publishRunLog is publishLog for messages of the pipeline run runID.
+func (e *Engine) publishRunLog(runID string, level events.LogLevel, message, stage, jobName string) {
 	// Write to stdout directly (not log.Printf) to avoid double-publish
 	// when TeeWriter is active on stdlib log.
-	fmt.Printf("[%s] %s\n", level.String(), message)
+	logging.PrintLine(level.String(), message, stage, jobName, logging.Correlation{RunID: runID})
 
 	// Only publish events if enabled (to prevent deadlocks)
 	e.eventMu.RLock()
@@ -1630,7 +2087,17 @@ func (e *Engine) publishLog(level events.LogLevel, message, stage, jobName strin
 	e.eventMu.RUnlock()
 
 	if enabled {
-		e.eventBus.PublishLog(level, message, stage, jobName, nil)
+		e.eventBus.Publish(&events.LogEvent{
+			BaseEvent: events.BaseEvent{
+				EventType: events.EventLog,
+				Time:      time.Now(),
+				RunID:     runID,
+			},
+			Level:   level,
+			Message: message,
+			Stage:   stage,
+			JobName: jobName,
+		})
 	}
 }
 
@@ -1645,87 +2112,235 @@ func (e *Engine) publishProgress(jobName, stage string, progress float64, messag
 	}
 }
 
-func (e *Engine) validateJob(job *models.JobSpec, validator *validation.CoreTypeValidator) []string {
-	var errors []string
-
-	// Basic validation
-	if job.JobName == "" {
-		errors = append(errors, "job name is required")
-	}
+// checkJobDirectory is the engine's extra plan check: it tars the run
+// directory, so the directory must exist.
+func checkJobDirectory(job models.JobSpec) (errs, warnings []string) {
 	if job.Directory == "" {
-		errors = append(errors, "directory is required")
-	} else {
-		// Check directory exists
-		if _, err := os.Stat(job.Directory); os.IsNotExist(err) {
-			errors = append(errors, fmt.Sprintf("directory does not exist: %s", job.Directory))
-		}
+		return []string{"Directory is required"}, nil
 	}
-	if job.Command == "" {
-		errors = append(errors, "command is required")
+	if _, err := os.Stat(job.Directory); os.IsNotExist(err) {
+		return []string{fmt.Sprintf("Directory does not exist: %s", job.Directory)}, nil
 	}
-	if job.CoresPerSlot <= 0 {
-		errors = append(errors, "cores per slot must be positive")
+	return nil, nil
+}
+
+// jobNameCollisions finds job names repeated within jobs and, with
+// checkRecent and an API client, names already used by the user's recent
+// jobs. A failed lookup is logged and only within-run duplicates are returned.
+func (e *Engine) jobNameCollisions(ctx context.Context, jobs []models.JobSpec, checkRecent bool) []validation.NameCollision {
+	var existing map[string][]string
+	if apiClient := e.API(); checkRecent && apiClient != nil {
+		names, err := validation.RecentJobNames(ctx, apiClient, constants.JobNameRecentWindow)
+		if err != nil {
+			e.publishLog(events.WarnLevel, fmt.Sprintf("Could not check recent job names: %v", err), "plan", "")
+		} else {
+			existing = names
+		}
 	}
-	if job.Slots <= 0 {
-		errors = append(errors, "slots must be positive")
+	return validation.FindNameCollisions(jobs, existing)
+}
+
+// ApplyJobNamePolicy applies the configured job_name_policy to jobs about to
+// run: duplicates are logged (warn), renamed in place with runID as the
+// suffix (suffix) or rejected with an error (block). Recent job names are
+// only fetched for suffix and block; under warn a run checks its own rows
+// and leaves the recent-jobs warning to Plan. Call before the run's state
+// is initialized, since job names key the state rows.
+func (e *Engine) ApplyJobNamePolicy(ctx context.Context, jobs []models.JobSpec, runID string) error {
+	policy := validation.NamePolicyWarn
+	if cfg := e.GetConfig(); cfg != nil {
+		policy = validation.NormalizeNamePolicy(cfg.JobNamePolicy)
+	}
+	collisions := e.jobNameCollisions(ctx, jobs, policy != validation.NamePolicyWarn)
+	messages, err := validation.ApplyNamePolicy(jobs, collisions, policy, validation.NameSuffix(runID, time.Now()))
+	if err != nil {
+		e.publishLog(events.ErrorLevel, err.Error(), "run", "")
+		return err
 	}
-	if job.WalltimeHours <= 0 {
-		errors = append(errors, "walltime hours must be positive")
+	for _, m := range messages {
+		e.publishLog(events.WarnLevel, m, "run", "")
 	}
+	return nil
+}
 
-	// Validate core type if we have a validator
-	if validator != nil && job.CoreType != "" {
-		if err := validator.Validate(job.CoreType); err != nil {
-			errors = append(errors, fmt.Sprintf("invalid core type: %s", err))
-		}
+// CheckDuplicateRun applies the configured duplicate_run_policy to jobs
+// about to run: a recent run with the same jobs and inputs is logged (warn)
+// or rejected with an error (block). Call before ApplyJobNamePolicy, which
+// may rename jobs. Returns the run fingerprint for RecordRun, or "" if it
+// could not be computed.
+func (e *Engine) CheckDuplicateRun(jobs []models.JobSpec) (string, error) {
+	cfg := e.GetConfig()
+	if cfg == nil {
+		return "", nil
+	}
+	fingerprint, err := runhistory.Fingerprint(jobs)
+	if err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Duplicate run check skipped: %v", err), "run", "")
+		return "", nil
 	}
+	warning, err := runhistory.Check(config.GetRunHistoryPath(cfg.APIBaseURL), fingerprint,
+		cfg.DuplicateRunPolicy, false, time.Now(), constants.DuplicateRunWindow)
+	if errors.Is(err, runhistory.ErrDuplicateRun) {
+		e.publishLog(events.ErrorLevel, err.Error(), "run", "")
+		return "", err
+	}
+	if err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Duplicate run check skipped: %v", err), "run", "")
+	} else if warning != "" {
+		e.publishLog(events.WarnLevel, warning, "run", "")
+	}
+	return fingerprint, nil
+}
 
-	return errors
+// RecordRun adds a started run to the local run history so later runs of
+// the same jobs and inputs are caught by CheckDuplicateRun.
+func (e *Engine) RecordRun(fingerprint, runID, stateFile string, jobs int) {
+	cfg := e.GetConfig()
+	if cfg == nil || fingerprint == "" {
+		return
+	}
+	err := runhistory.Append(config.GetRunHistoryPath(cfg.APIBaseURL), runhistory.Entry{
+		Time:        time.Now(),
+		Fingerprint: fingerprint,
+		RunID:       runID,
+		StateFile:   stateFile,
+		Jobs:        jobs,
+	})
+	if err != nil {
+		e.publishLog(events.WarnLevel, fmt.Sprintf("Failed to record run history: %v", err), "run", "")
+	}
 }
 
 func (e *Engine) stopMonitoring() {
 	// Already handled by context cancellation
 }
 
-func (e *Engine) checkJobStatuses() {
+// monitoredJobIDs returns the platform IDs of the run's submitted jobs.
+func (e *Engine) monitoredJobIDs() []string {
 	e.mu.RLock()
 	st := e.state
 	e.mu.RUnlock()
 
 	if st == nil {
-		return
+		return nil
 	}
 
-	jobs := st.GetAllStates()
-	for _, job := range jobs {
+	var ids []string
+	for _, job := range st.GetAllStates() {
 		if job.JobID != "" && job.SubmitStatus == "success" {
-			// Check status on Rescale
-			status, err := e.GetJobStatus(job.JobID)
-			if err != nil {
-				e.publishLog(events.WarnLevel,
-					fmt.Sprintf("Failed to get status for job %s: %v", job.JobName, err),
-					"monitor", job.JobName)
-				continue
-			}
+			ids = append(ids, job.JobID)
+		}
+	}
+	return ids
+}
 
-			// Emit status update event (this will update the UI table)
-			e.eventBus.Publish(&events.StateChangeEvent{
-				BaseEvent: events.BaseEvent{
-					EventType: events.EventStateChange,
-					Time:      time.Now(),
-				},
-				JobName:      job.JobName,
-				Stage:        "status",
-				NewStatus:    status,
-				JobID:        job.JobID,
-				ErrorMessage: "",
-			})
+// jobNameForID resolves the pipeline job name for a platform job ID.
+func (e *Engine) jobNameForID(jobID string) string {
+	e.mu.RLock()
+	st := e.state
+	e.mu.RUnlock()
+
+	if st == nil || jobID == "" {
+		return ""
+	}
+	for _, job := range st.GetAllStates() {
+		if job.JobID == jobID {
+			return job.JobName
+		}
+	}
+	return ""
+}
+
+func (e *Engine) monitorStatus(ctx context.Context, jobID string) (string, error) {
+	e.mu.RLock()
+	client := e.apiClient
+	e.mu.RUnlock()
 
-			e.publishLog(events.DebugLevel,
-				fmt.Sprintf("Job %s status: %s", job.JobName, status),
-				"monitor", job.JobName)
+	if client == nil {
+		return "", fmt.Errorf("API client not configured")
+	}
+
+	job, err := client.GetJob(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	return job.JobStatus.Status, nil
+}
+
+// monitorEvents adapts the API client's long-poll job event channel to the
+// watch package's EventStream.
+func (e *Engine) monitorEvents(ctx context.Context, cursor string) ([]watch.StatusEvent, string, error) {
+	e.mu.RLock()
+	client := e.apiClient
+	e.mu.RUnlock()
+
+	if client == nil {
+		return nil, cursor, fmt.Errorf("API client not configured")
+	}
+
+	page, err := client.WaitJobEvents(ctx, cursor, constants.JobEventWait)
+	if errors.Is(err, api.ErrJobEventsUnsupported) {
+		return nil, cursor, watch.ErrStreamUnsupported
+	}
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	out := make([]watch.StatusEvent, 0, len(page.Events))
+	for _, ev := range page.Events {
+		out = append(out, watch.StatusEvent{JobID: ev.JobID, Status: ev.Status})
+	}
+	return out, page.Cursor, nil
+}
+
+// publishJobStatus emits a status update event (this will update the UI table).
+// While the job waits to execute, the event also carries its queue/provisioning
+// sub-status and when it entered it.
+func (e *Engine) publishJobStatus(jobID, oldStatus, newStatus string) {
+	jobName := e.jobNameForID(jobID)
+	ev := &events.StateChangeEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: events.EventStateChange,
+			Time:      time.Now(),
+		},
+		JobName:   jobName,
+		Stage:     "status",
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		JobID:     jobID,
+	}
+	if label := watch.SubStatusLabel(newStatus); label != "" {
+		sub := e.jobSubStatus(jobID, label)
+		ev.SubStatus = sub.Label
+		ev.SubStatusReason = sub.Reason
+		ev.SubStatusSince = sub.Since
+	}
+	e.eventBus.Publish(ev)
+
+	e.publishLog(events.DebugLevel,
+		fmt.Sprintf("Job %s status: %s", jobName, newStatus),
+		"monitor", jobName)
+}
+
+// jobSubStatus reads the job's status history for when it entered the
+// sub-status label. If the history can't be read or lags behind the status
+// that was just reported, the sub-status is timed from now.
+func (e *Engine) jobSubStatus(jobID, label string) watch.SubStatus {
+	e.mu.RLock()
+	client := e.apiClient
+	e.mu.RUnlock()
+
+	if client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.APIContextTimeout)
+		history, err := client.GetJobStatuses(ctx, jobID)
+		cancel()
+		if err == nil {
+			if sub, ok := watch.PreExecutionSubStatus(history); ok && sub.Label == label {
+				return sub
+			}
 		}
 	}
+	return watch.SubStatus{Label: label, Since: time.Now()}
 }
 
 type jobStats struct {
@@ -1733,6 +2348,9 @@ type jobStats struct {
 	Completed int
 	Failed    int
 	Pending   int
+
+	// Failed jobs with the stage and error they failed with
+	Failures []events.JobFailure
 }
 
 // getJobStats returns pipeline job statistics using the same SubmitStatus-based logic as GetRunStats().
@@ -1764,6 +2382,9 @@ func (e *Engine) getJobStats() jobStats {
 				stats.Pending++
 			}
 		}
+		if stage := state.FailedStage(job); stage != "" {
+			stats.Failures = append(stats.Failures, events.JobFailure{JobName: job.JobName, Stage: stage, Error: job.ErrorMessage})
+		}
 	}
 
 	return stats
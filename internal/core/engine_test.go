@@ -2,14 +2,20 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/events"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
 )
 
 func TestNewEngine(t *testing.T) {
@@ -144,6 +150,40 @@ func TestEngine_Plan_ValidFile(t *testing.T) {
 	}
 }
 
+func TestEngine_Plan_ParallelKeepsOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	jobsCSV := filepath.Join(tmpDir, "jobs.csv")
+
+	// Every other job points at a missing directory
+	var b strings.Builder
+	b.WriteString("Directory,JobName,AnalysisCode,AnalysisVersion,Command,CoreType,CoresPerSlot,WalltimeHours,Slots,LicenseSettings\n")
+	for i := 0; i < 200; i++ {
+		dir := tmpDir
+		if i%2 == 1 {
+			dir = filepath.Join(tmpDir, "missing")
+		}
+		fmt.Fprintf(&b, "%s,job-%03d,user_included,1.0,./run.sh,emerald,4,1.0,1,\"{\"\"k\"\":\"\"v\"\"}\"\n", dir, i)
+	}
+	if err := os.WriteFile(jobsCSV, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to create test CSV: %v", err)
+	}
+
+	engine, _ := NewEngine(nil)
+	result, err := engine.Plan(jobsCSV, false)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if result.ValidJobs != 100 || result.InvalidJobs != 100 {
+		t.Fatalf("valid/invalid = %d/%d, want 100/100", result.ValidJobs, result.InvalidJobs)
+	}
+	for i, msg := range result.Errors {
+		want := fmt.Sprintf("Job %d (job-%03d)", 2*i+2, 2*i+1)
+		if !strings.HasPrefix(msg, want) {
+			t.Fatalf("Errors[%d] = %q, want prefix %q", i, msg, want)
+		}
+	}
+}
+
 func TestEngine_Stop(t *testing.T) {
 	engine, _ := NewEngine(nil)
 
@@ -166,6 +206,55 @@ func TestEngine_Stop(t *testing.T) {
 	}
 }
 
+func TestEngine_StopJob_RequiresJobIDAndClient(t *testing.T) {
+	engine, _ := NewEngine(nil)
+
+	if _, err := engine.StopJob(context.Background(), ""); err == nil {
+		t.Error("StopJob(\"\") should fail")
+	}
+
+	// No API key configured -> no client
+	engine.apiClient = nil
+	if _, err := engine.StopJob(context.Background(), "abc"); err == nil {
+		t.Error("StopJob without API client should fail")
+	}
+}
+
+func TestEngine_StopJob_RecordsStopInState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/jobs/job-1/stop/":
+			w.WriteHeader(http.StatusOK)
+		case "/api/v3/jobs/job-1/":
+			w.Write([]byte(`{"id":"job-1","jobStatus":{"status":"Stopping"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	engine, _ := NewEngine(nil)
+	engine.apiClient = api.NewClientForTest(&config.Config{APIBaseURL: server.URL, APIKey: "test-key", ProxyMode: "no-proxy"})
+	stateFile := filepath.Join(t.TempDir(), "run_state.csv")
+	engine.state = state.NewManager(stateFile)
+	js := engine.state.InitializeState(1, "job_1", "/data/job_1")
+	js.JobID, js.SubmitStatus, js.OutputStatus = "job-1", "success", "pending"
+
+	if _, err := engine.StopJob(context.Background(), "job-1"); err != nil {
+		t.Fatalf("StopJob() error = %v", err)
+	}
+
+	saved := state.NewManager(stateFile)
+	if err := saved.Load(); err != nil {
+		t.Fatal(err)
+	}
+	got := saved.GetState(1)
+	if got == nil || !strings.Contains(got.ErrorMessage, "stopped") || got.OutputStatus != "skipped" {
+		t.Errorf("saved state = %+v, want the stop recorded and outputs skipped", got)
+	}
+}
+
 func TestEngine_SaveConfig(t *testing.T) {
 	cfg, _ := config.LoadConfigCSV("")
 	cfg.TarWorkers = 8
@@ -269,6 +358,52 @@ func TestEngine_Scan(t *testing.T) {
 	}
 }
 
+func TestEngine_Scan_PreservesTemplateMetadata(t *testing.T) {
+	cfg, _ := config.LoadConfigCSV("")
+	engine, _ := NewEngine(cfg)
+
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "Run_1"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "Run_2"), 0755)
+
+	templatePath := filepath.Join(tmpDir, "template.csv")
+	templateContent := `Directory,JobName,AnalysisCode,Command,CoreType,CoresPerSlot,WalltimeHours,Slots,LicenseSettings,Tags,ProjectID,OrgCode,Submit
+/tmp/test,test_job_1,user_included,./run.sh,emerald,4,1.0,1,"{""test"":""value""}","cfd,nightly",proj123,acme,create_only`
+	os.WriteFile(templatePath, []byte(templateContent), 0644)
+
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	outputPath := filepath.Join(tmpDir, "jobs.csv")
+	err := engine.Scan(ScanOptions{
+		TemplateCSV: templatePath,
+		OutputCSV:   outputPath,
+		Pattern:     "Run_*",
+		StartIndex:  1,
+		Overwrite:   true,
+	})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	jobs, err := config.LoadJobsCSV(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load generated CSV: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(jobs))
+	}
+	for _, job := range jobs {
+		if strings.Join(job.Tags, ",") != "cfd,nightly" || job.ProjectID != "proj123" || job.OrgCode != "acme" {
+			t.Errorf("%s lost template metadata: tags=%v project=%q org=%q", job.JobName, job.Tags, job.ProjectID, job.OrgCode)
+		}
+		if job.SubmitMode != "create_only" {
+			t.Errorf("%s SubmitMode = %q, want create_only", job.JobName, job.SubmitMode)
+		}
+	}
+}
+
 func TestEngine_ScanToSpecs_AbsolutePaths(t *testing.T) {
 	cfg, _ := config.LoadConfigCSV("")
 	cfg.ValidationPattern = ""
@@ -312,6 +447,90 @@ func TestEngine_ScanToSpecs_AbsolutePaths(t *testing.T) {
 	}
 }
 
+func TestEngine_RetryFailed_NothingToRetry(t *testing.T) {
+	cfg, _ := config.LoadConfigCSV("")
+	cfg.APIKey = "test-key"
+	engine, _ := NewEngine(cfg)
+
+	stateFile := filepath.Join(t.TempDir(), "run_state.csv")
+	if err := engine.RetryFailed(context.Background(), "jobs.csv", stateFile); err == nil {
+		t.Error("expected an error for a missing state file")
+	}
+
+	st := state.NewManager(stateFile)
+	done := st.InitializeState(1, "job_1", "/data/job_1")
+	done.TarStatus, done.UploadStatus, done.JobID, done.SubmitStatus = "success", "success", "job-1", "success"
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// No failed jobs: nothing is run, so the missing jobs CSV is never read
+	if err := engine.RetryFailed(context.Background(), "missing_jobs.csv", stateFile); err != nil {
+		t.Errorf("RetryFailed() error = %v", err)
+	}
+}
+
+func TestEngine_RunFromSpecsWithOptions_DryRun(t *testing.T) {
+	cfg, _ := config.LoadConfigCSV("")
+	engine, _ := NewEngine(cfg)
+
+	tmpDir := t.TempDir()
+	runDir := filepath.Join(tmpDir, "Run_1")
+	os.MkdirAll(runDir, 0755)
+	os.WriteFile(filepath.Join(runDir, "input.txt"), []byte("data"), 0644)
+	jobs := []models.JobSpec{{
+		JobName: "Run_1", Directory: runDir, AnalysisCode: "user_included", AnalysisVersion: "0",
+		Command: "./run.sh", CoreType: "emerald", CoresPerSlot: 1, Slots: 1, WalltimeHours: 1,
+	}}
+	stateFile := filepath.Join(tmpDir, "run.csv")
+
+	// No API key is needed for a dry run
+	if err := engine.RunFromSpecsWithOptions(context.Background(), jobs, stateFile, RunOptions{DryRun: true}); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "run.preview.json"))
+	if err != nil {
+		t.Fatalf("preview not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"command": "./run.sh"`) {
+		t.Errorf("preview lacks the job request:\n%s", data)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file written by a dry run (stat error %v)", err)
+	}
+}
+
+func TestEngine_ScanToSpecs_Sweep(t *testing.T) {
+	cfg, _ := config.LoadConfigCSV("")
+	engine, _ := NewEngine(cfg)
+
+	tmpDir := t.TempDir()
+	tablePath := filepath.Join(tmpDir, "params.csv")
+	os.WriteFile(tablePath, []byte("mach,aoa\n0.3,2\n0.5,4\n0.7,6\n"), 0644)
+
+	template := models.JobSpec{
+		JobName:   "wing_1",
+		Command:   "./run.sh --mach {{mach}} --aoa {{aoa}}",
+		Directory: tmpDir,
+	}
+
+	jobs, err := engine.ScanToSpecs(template, ScanOptions{SweepTable: tablePath, StartIndex: 1, OutputPatterns: []string{"*.dat"}})
+	if err != nil {
+		t.Fatalf("ScanToSpecs failed: %v", err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("Expected 3 jobs, got %d", len(jobs))
+	}
+	if jobs[2].JobName != "wing_3" || jobs[2].Command != "./run.sh --mach 0.7 --aoa 6" {
+		t.Errorf("job 3 = %q: %q", jobs[2].JobName, jobs[2].Command)
+	}
+	for _, job := range jobs {
+		if job.Directory != tmpDir || len(job.OutputPatterns) != 1 {
+			t.Errorf("%s: directory %q, output patterns %v", job.JobName, job.Directory, job.OutputPatterns)
+		}
+	}
+}
+
 func TestEngine_RecursiveScan_SkipDir(t *testing.T) {
 	// Verify that nested directories matching the pattern are NOT discovered
 	// when using recursive scan (SkipDir behavior).
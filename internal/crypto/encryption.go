@@ -1,6 +1,7 @@
 package encryption
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -100,6 +101,13 @@ func pkcs7Unpad(data []byte) ([]byte, error) {
 
 // EncryptFile encrypts a file using AES-256-CBC with PKCS7 padding
 func EncryptFile(inputPath, outputPath string, key, iv []byte) error {
+	return EncryptFileContext(context.Background(), inputPath, outputPath, key, iv)
+}
+
+// EncryptFileContext is EncryptFile, stopping with ctx's error between
+// chunks once ctx is cancelled. Multi-GB files take minutes to encrypt, so
+// a cancelled upload must not wait for the whole file.
+func EncryptFileContext(ctx context.Context, inputPath, outputPath string, key, iv []byte) error {
 	if len(key) != KeySize {
 		return fmt.Errorf("key must be %d bytes", KeySize)
 	}
@@ -132,6 +140,9 @@ func EncryptFile(inputPath, outputPath string, key, iv []byte) error {
 	var lastChunk []byte
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		n, err := inputFile.Read(buffer)
 		if err == io.EOF {
 			break
@@ -295,6 +306,12 @@ func DecryptFile(inputPath, outputPath string, key, iv []byte) error {
 // Computes hash during decryption to avoid re-reading file for verification.
 // This eliminates the race condition where post-download verification could read stale cache data.
 func DecryptFileWithHash(inputPath, outputPath string, key, iv []byte) (string, error) {
+	return DecryptFileWithHashContext(context.Background(), inputPath, outputPath, key, iv)
+}
+
+// DecryptFileWithHashContext is DecryptFileWithHash, stopping with ctx's
+// error between chunks once ctx is cancelled.
+func DecryptFileWithHashContext(ctx context.Context, inputPath, outputPath string, key, iv []byte) (string, error) {
 	if len(key) != KeySize {
 		return "", fmt.Errorf("key must be %d bytes", KeySize)
 	}
@@ -342,6 +359,9 @@ func DecryptFileWithHash(inputPath, outputPath string, key, iv []byte) (string,
 	var totalRead int64
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
 		n, err := inputFile.Read(buffer)
 		if err == io.EOF {
 			break
@@ -2,7 +2,9 @@ package encryption
 
 import (
 	"bytes"
+	"context"
 	"crypto/aes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -406,3 +408,35 @@ func TestEncryptFileInvalidIVSize(t *testing.T) {
 		t.Error("Expected error with wrong IV size, got nil")
 	}
 }
+
+// TestEncryptDecryptFileContextCancelled tests that whole-file encryption and
+// decryption stop when their context is cancelled
+func TestEncryptDecryptFileContextCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "input.dat")
+	encryptedFile := filepath.Join(tmpDir, "input.dat.enc")
+	decryptedFile := filepath.Join(tmpDir, "output.dat")
+
+	if err := os.WriteFile(inputFile, bytes.Repeat([]byte("x"), 64*1024), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	key, _ := GenerateKey()
+	iv, _ := GenerateIV()
+	if err := EncryptFile(inputFile, encryptedFile, key, iv); err != nil {
+		t.Fatalf("EncryptFile failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := EncryptFileContext(ctx, inputFile, filepath.Join(tmpDir, "cancelled.enc"), key, iv)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("EncryptFileContext error = %v, want context.Canceled", err)
+	}
+
+	_, err = DecryptFileWithHashContext(ctx, encryptedFile, decryptedFile, key, iv)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DecryptFileWithHashContext error = %v, want context.Canceled", err)
+	}
+}
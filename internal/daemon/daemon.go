@@ -3,6 +3,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 	"github.com/rescale/rescale-int/internal/events"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/ipc"
+	"github.com/rescale/rescale-int/internal/localfs/journal"
 	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/reporting"
 	"github.com/rescale/rescale-int/internal/services"
@@ -51,6 +53,9 @@ type Config struct {
 
 	// When set, jobs must pass eligibility checks to be downloaded
 	Eligibility *EligibilityConfig
+
+	// When set, running jobs are checked against wall-clock and cost caps
+	Watchdog *WatchdogConfig
 }
 
 // DefaultConfig returns a daemon configuration with sensible defaults.
@@ -71,6 +76,7 @@ type Daemon struct {
 	apiClient *api.Client
 	state     *State
 	monitor   *Monitor
+	watchdog  *Watchdog // nil when the watchdog is disabled
 	logger    *logging.Logger
 
 	// Shutdown coordination
@@ -121,12 +127,23 @@ func New(appCfg *config.Config, daemonCfg *Config, logger *logging.Logger) (*Dae
 		monitor = NewMonitor(apiClient, state, daemonCfg.Filter, logger)
 	}
 
+	// Create job watchdog if configured
+	var watchdog *Watchdog
+	if daemonCfg.Watchdog != nil {
+		wdCfg := *daemonCfg.Watchdog
+		if wdCfg.AuditFile == "" {
+			wdCfg.AuditFile = WatchdogAuditPath(daemonCfg.StateFile)
+		}
+		watchdog = NewWatchdog(apiClient, wdCfg, logger)
+	}
+
 	// Daemon-scoped EventBus + TransferService. EventBus drives the shared
 	// transfer.Queue; IPC serializes from that queue on demand. No external
 	// subscribers — the bus exists so the shared transfer path works.
 	eventBus := events.NewEventBus(0) // default buffer
 	ts := services.NewTransferService(apiClient, eventBus, services.TransferServiceConfig{
 		MaxConcurrent: daemonCfg.MaxConcurrent,
+		PostDownload:  appCfg.PostDownload,
 	})
 
 	return &Daemon{
@@ -135,6 +152,7 @@ func New(appCfg *config.Config, daemonCfg *Config, logger *logging.Logger) (*Dae
 		apiClient: apiClient,
 		state:     state,
 		monitor:   monitor,
+		watchdog:  watchdog,
 		logger:    logger,
 		stopChan:  make(chan struct{}),
 		ts:        ts,
@@ -251,9 +269,29 @@ func (d *Daemon) Start(ctx context.Context) error {
 	d.wg.Add(1)
 	go d.pollLoop(d.lifecycleCtx)
 
+	// Record changes under directories registered with the change journal
+	// ('files journal add'), so their scans can be incremental
+	d.wg.Add(1)
+	go d.recordChanges(d.lifecycleCtx)
+
 	return nil
 }
 
+// recordChanges runs the change journal recorder until ctx is done. Where
+// the platform has none (or lacks the privileges for one) the registered
+// directories are simply walked in full.
+func (d *Daemon) recordChanges(ctx context.Context) {
+	defer d.wg.Done()
+
+	err := journal.NewStore(config.GetJournalDir()).Record(ctx)
+	switch {
+	case errors.Is(err, journal.ErrUnsupported):
+		d.logger.Debug().Err(err).Msg("Change journal recorder not started")
+	case err != nil:
+		d.logger.Warn().Err(err).Msg("Change journal recorder stopped")
+	}
+}
+
 // Stop signals the daemon to stop and waits for cleanup.
 func (d *Daemon) Stop() {
 	d.mu.Lock()
@@ -351,6 +389,14 @@ func (d *Daemon) poll(ctx context.Context) {
 		}
 	}
 
+	// Watchdog pass: report or stop running jobs over their caps. Failures
+	// are logged only; they never block the download scan.
+	if d.watchdog != nil {
+		if err := d.watchdog.Check(scanCtx); err != nil {
+			d.logger.Warn().Msgf("Watchdog check failed: %v", err)
+		}
+	}
+
 	// Build the still-pending set AFTER the retry pass. Jobs in this set
 	// will be skipped by FindCompletedJobs with ReasonPendingTagApply.
 	var pendingSet map[string]struct{}
@@ -0,0 +1,325 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/parser"
+	"github.com/rescale/rescale-int/internal/services"
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// WatchdogConfig defines the caps the job watchdog enforces. A zero cap is
+// not checked.
+type WatchdogConfig struct {
+	// Terminate stops jobs over a cap; otherwise they are only reported.
+	Terminate bool
+
+	// MaxRuntime caps the time since the job started executing.
+	MaxRuntime time.Duration
+
+	// MaxCost caps the estimated cost: core-hours so far times the core
+	// type's listed price per core-hour.
+	MaxCost float64
+
+	// Protected lists job IDs and job name patterns (* wildcards) that are
+	// never stopped. Matching jobs over a cap are still audited.
+	Protected []string
+
+	// AuditFile receives one JSON line per watchdog action. Empty = next to
+	// the daemon state file.
+	AuditFile string
+}
+
+// WatchdogConfigFromDaemonConf builds the watchdog settings from the
+// [watchdog] section of daemon.conf. Returns nil when the watchdog is off.
+func WatchdogConfigFromDaemonConf(conf *config.DaemonConfig) *WatchdogConfig {
+	if !conf.Watchdog.Enabled {
+		return nil
+	}
+	return &WatchdogConfig{
+		Terminate:  conf.Watchdog.Action == config.WatchdogActionTerminate,
+		MaxRuntime: time.Duration(conf.Watchdog.MaxHours * float64(time.Hour)),
+		MaxCost:    conf.Watchdog.MaxCost,
+		Protected:  conf.GetProtectedJobs(),
+	}
+}
+
+// Watchdog audit actions
+const (
+	WatchdogNotified   = "notified"
+	WatchdogTerminated = "terminated"
+	WatchdogStopFailed = "stop_failed"
+	WatchdogProtected  = "protected"
+)
+
+// WatchdogAuditEntry is one line of the watchdog audit log.
+type WatchdogAuditEntry struct {
+	Time          time.Time `json:"time"`
+	JobID         string    `json:"job_id"`
+	JobName       string    `json:"job_name"`
+	Owner         string    `json:"owner,omitempty"`
+	Action        string    `json:"action"`
+	Reason        string    `json:"reason"`
+	RuntimeHours  float64   `json:"runtime_hours"`
+	EstimatedCost float64   `json:"estimated_cost,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// watchdogAPI is the subset of api.Client the watchdog uses.
+type watchdogAPI interface {
+	ListJobsWithCutoff(ctx context.Context, cutoff time.Time) ([]models.JobResponse, error)
+	GetJobStatuses(ctx context.Context, jobID string) ([]models.JobStatusEntry, error)
+	GetJobRaw(ctx context.Context, jobID string) (json.RawMessage, error)
+	GetCoreTypesRaw(ctx context.Context, includeInactive bool) ([]json.RawMessage, error)
+	GetJobTags(ctx context.Context, jobID string) ([]string, error)
+	StopJob(ctx context.Context, jobID string) error
+}
+
+// Watchdog checks running jobs against wall-clock and cost caps on each
+// daemon poll, and reports or stops the ones that exceed them. Only jobs
+// carrying the Interlink marker tag (constants.DefaultAdminJobTag) are
+// candidates; jobs submitted from the web UI or other tools are left alone.
+type Watchdog struct {
+	api    watchdogAPI
+	cfg    WatchdogConfig
+	logger *logging.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	handled map[string]bool // Jobs already acted on (or protected) this session
+}
+
+// NewWatchdog creates a watchdog.
+func NewWatchdog(api watchdogAPI, cfg WatchdogConfig, logger *logging.Logger) *Watchdog {
+	return &Watchdog{
+		api:     api,
+		cfg:     cfg,
+		logger:  logger,
+		now:     time.Now,
+		handled: make(map[string]bool),
+	}
+}
+
+// Check runs one pass over the user's non-terminal Interlink jobs. Per-job
+// failures are logged; the returned error is only for the job listing itself.
+func (w *Watchdog) Check(ctx context.Context) error {
+	jobs, err := w.api.ListJobsWithCutoff(ctx, w.now().Add(-constants.WatchdogLookback))
+	if err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	var prices map[string]float64 // Loaded on first need
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if watch.TerminalStatuses[job.JobStatus.Status] || w.isHandled(job.ID) {
+			continue
+		}
+
+		started, ok := w.executionStart(ctx, job.ID)
+		if !ok {
+			continue // Not running yet
+		}
+		runtime := w.now().Sub(started)
+
+		var reasons []string
+		if w.cfg.MaxRuntime > 0 && runtime > w.cfg.MaxRuntime {
+			reasons = append(reasons, fmt.Sprintf("running %.1fh exceeds the %.1fh cap",
+				runtime.Hours(), w.cfg.MaxRuntime.Hours()))
+		}
+		var cost float64
+		if w.cfg.MaxCost > 0 {
+			if prices == nil {
+				prices = w.loadPrices(ctx)
+			}
+			if c, ok := w.estimateCost(ctx, job.ID, runtime, prices); ok {
+				cost = c
+				if cost > w.cfg.MaxCost {
+					reasons = append(reasons, fmt.Sprintf("estimated cost %.2f exceeds the %.2f cap", cost, w.cfg.MaxCost))
+				}
+			}
+		}
+		if len(reasons) == 0 || !w.isInterlinkJob(ctx, job.ID) {
+			continue
+		}
+
+		entry := WatchdogAuditEntry{
+			JobID:         job.ID,
+			JobName:       job.Name,
+			Owner:         job.Owner,
+			Reason:        strings.Join(reasons, "; "),
+			RuntimeHours:  runtime.Hours(),
+			EstimatedCost: cost,
+		}
+		w.act(ctx, entry)
+	}
+	return nil
+}
+
+// act reports, stops or (for protected jobs) only records a job over a cap.
+func (w *Watchdog) act(ctx context.Context, entry WatchdogAuditEntry) {
+	switch {
+	case w.isProtected(entry.JobID, entry.JobName):
+		entry.Action = WatchdogProtected
+		w.logger.Info().Msgf("WATCHDOG: %s [%s] is protected - %s", entry.JobName, entry.JobID, entry.Reason)
+		w.markHandled(entry.JobID)
+
+	case w.cfg.Terminate:
+		if err := w.api.StopJob(ctx, entry.JobID); err != nil {
+			// Not marked handled: retried on the next poll
+			entry.Action = WatchdogStopFailed
+			entry.Error = err.Error()
+			w.logger.Error().Msgf("WATCHDOG: failed to stop %s [%s] - %s: %v", entry.JobName, entry.JobID, entry.Reason, err)
+		} else {
+			entry.Action = WatchdogTerminated
+			w.logger.Warn().Msgf("WATCHDOG: stopped %s [%s] - %s", entry.JobName, entry.JobID, entry.Reason)
+			w.markHandled(entry.JobID)
+		}
+
+	default:
+		entry.Action = WatchdogNotified
+		w.logger.Warn().Msgf("WATCHDOG: %s [%s] - %s (action=notify, job left running)", entry.JobName, entry.JobID, entry.Reason)
+		w.markHandled(entry.JobID)
+	}
+
+	if err := w.audit(entry); err != nil {
+		w.logger.Error().Err(err).Msg("Failed to write watchdog audit log")
+	}
+}
+
+// isInterlinkJob reports whether the job carries the Interlink marker tag.
+// Jobs without it are marked handled so their tags aren't fetched again;
+// a failed lookup is retried on the next poll.
+func (w *Watchdog) isInterlinkJob(ctx context.Context, jobID string) bool {
+	tags, err := w.api.GetJobTags(ctx, jobID)
+	if err != nil {
+		w.logger.Debug().Err(err).Str("job_id", jobID).Msg("Watchdog: failed to get job tags")
+		return false
+	}
+	if !services.IsInterlinkJob(tags, constants.DefaultAdminJobTag) {
+		w.logger.Debug().Str("job_id", jobID).Msg("Watchdog: job not submitted via Interlink; skipped")
+		w.markHandled(jobID)
+		return false
+	}
+	return true
+}
+
+// executionStart returns when the job first entered Executing.
+func (w *Watchdog) executionStart(ctx context.Context, jobID string) (time.Time, bool) {
+	statuses, err := w.api.GetJobStatuses(ctx, jobID)
+	if err != nil {
+		w.logger.Debug().Err(err).Str("job_id", jobID).Msg("Watchdog: failed to get job statuses")
+		return time.Time{}, false
+	}
+
+	var start time.Time
+	for _, s := range statuses {
+		if s.Status != "Executing" {
+			continue
+		}
+		t, err := watch.ParseStatusDate(s.StatusDate)
+		if err != nil {
+			continue
+		}
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+	}
+	return start, !start.IsZero()
+}
+
+// estimateCost returns core-hours so far times the core type's price.
+// ok is false when the hardware or its price is unknown.
+func (w *Watchdog) estimateCost(ctx context.Context, jobID string, runtime time.Duration, prices map[string]float64) (float64, bool) {
+	raw, err := w.api.GetJobRaw(ctx, jobID)
+	if err != nil {
+		w.logger.Debug().Err(err).Str("job_id", jobID).Msg("Watchdog: failed to get job for cost estimate")
+		return 0, false
+	}
+	spec, _, err := parser.RescaleJobToJobSpec(raw)
+	if err != nil {
+		return 0, false
+	}
+	price, ok := prices[spec.CoreType]
+	if !ok {
+		w.logger.Debug().Str("job_id", jobID).Str("core_type", spec.CoreType).Msg("Watchdog: no price for core type; cost cap not checked")
+		return 0, false
+	}
+	cores := spec.CoresPerSlot * spec.Slots
+	return float64(cores) * runtime.Hours() * price, true
+}
+
+// loadPrices returns the listed price per core-hour by core type code.
+func (w *Watchdog) loadPrices(ctx context.Context) map[string]float64 {
+	prices := make(map[string]float64)
+	raws, err := w.api.GetCoreTypesRaw(ctx, true)
+	if err != nil {
+		w.logger.Warn().Err(err).Msg("Watchdog: failed to load core type prices; cost cap not checked")
+		return prices
+	}
+	for _, raw := range raws {
+		var ct struct {
+			Code  string          `json:"code"`
+			Price json.RawMessage `json:"price"`
+		}
+		if json.Unmarshal(raw, &ct) != nil || ct.Code == "" {
+			continue
+		}
+		// The v2 endpoint has returned the price both as a number and a string
+		if p, err := strconv.ParseFloat(strings.Trim(string(ct.Price), `"`), 64); err == nil && p > 0 {
+			prices[ct.Code] = p
+		}
+	}
+	return prices
+}
+
+// isProtected reports whether a job is on the protected list, by ID or by
+// name pattern.
+func (w *Watchdog) isProtected(jobID, jobName string) bool {
+	for _, p := range w.cfg.Protected {
+		if p == jobID {
+			return true
+		}
+		if ok, _ := path.Match(p, jobName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watchdog) isHandled(jobID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.handled[jobID]
+}
+
+func (w *Watchdog) markHandled(jobID string) {
+	w.mu.Lock()
+	w.handled[jobID] = true
+	w.mu.Unlock()
+}
+
+// audit appends entry to the audit log.
+func (w *Watchdog) audit(entry WatchdogAuditEntry) error {
+	entry.Time = w.now().UTC()
+	return jsonl.Append(w.cfg.AuditFile, "watchdog audit log", entry)
+}
+
+// WatchdogAuditPath returns the audit log path used next to stateFile.
+func WatchdogAuditPath(stateFile string) string {
+	return filepath.Join(filepath.Dir(stateFile), "watchdog-audit.jsonl")
+}
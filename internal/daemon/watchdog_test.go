@@ -0,0 +1,237 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// fakeWatchdogAPI serves a fixed set of jobs. Each job started executing
+// started[id] ago and runs on coresPerSlot "emerald" cores. Jobs without a
+// tags entry carry the Interlink marker tag.
+type fakeWatchdogAPI struct {
+	now     time.Time
+	jobs    []models.JobResponse
+	started map[string]time.Duration
+	cores   map[string]int
+	tags    map[string][]string
+	price   string // raw JSON price of the "emerald" core type
+	stopErr error
+	stopped []string
+}
+
+func (f *fakeWatchdogAPI) ListJobsWithCutoff(_ context.Context, _ time.Time) ([]models.JobResponse, error) {
+	return f.jobs, nil
+}
+
+func (f *fakeWatchdogAPI) GetJobStatuses(_ context.Context, jobID string) ([]models.JobStatusEntry, error) {
+	entries := []models.JobStatusEntry{{Status: "Queued", StatusDate: f.now.Add(-100 * time.Hour).Format(time.RFC3339)}}
+	if d, ok := f.started[jobID]; ok {
+		entries = append(entries, models.JobStatusEntry{Status: "Executing", StatusDate: f.now.Add(-d).Format(time.RFC3339)})
+	}
+	return entries, nil
+}
+
+func (f *fakeWatchdogAPI) GetJobRaw(_ context.Context, jobID string) (json.RawMessage, error) {
+	return json.RawMessage(fmt.Sprintf(`{"id":%q,"jobanalyses":[{"hardware":{"coreType":{"code":"emerald"},"coresPerSlot":%d,"slots":1}}]}`,
+		jobID, f.cores[jobID])), nil
+}
+
+func (f *fakeWatchdogAPI) GetCoreTypesRaw(_ context.Context, _ bool) ([]json.RawMessage, error) {
+	return []json.RawMessage{json.RawMessage(`{"code":"emerald","price":` + f.price + `}`)}, nil
+}
+
+func (f *fakeWatchdogAPI) GetJobTags(_ context.Context, jobID string) ([]string, error) {
+	if tags, ok := f.tags[jobID]; ok {
+		return tags, nil
+	}
+	return []string{constants.DefaultAdminJobTag}, nil
+}
+
+func (f *fakeWatchdogAPI) StopJob(_ context.Context, jobID string) error {
+	if f.stopErr != nil {
+		return f.stopErr
+	}
+	f.stopped = append(f.stopped, jobID)
+	return nil
+}
+
+func newTestWatchdog(t *testing.T, api *fakeWatchdogAPI, cfg WatchdogConfig) *Watchdog {
+	t.Helper()
+	cfg.AuditFile = filepath.Join(t.TempDir(), "watchdog-audit.jsonl")
+	w := NewWatchdog(api, cfg, logging.NewLogger("watchdog-test", nil))
+	w.now = func() time.Time { return api.now }
+	return w
+}
+
+func readAudit(t *testing.T, path string) []WatchdogAuditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []WatchdogAuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e WatchdogAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("bad audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func testJobs() *fakeWatchdogAPI {
+	return &fakeWatchdogAPI{
+		now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		jobs: []models.JobResponse{
+			{ID: "long", Name: "cfd-long", JobStatus: models.JobStatusContent{Status: "Executing"}},
+			{ID: "short", Name: "cfd-short", JobStatus: models.JobStatusContent{Status: "Executing"}},
+			{ID: "queued", Name: "cfd-queued", JobStatus: models.JobStatusContent{Status: "Queued"}},
+			{ID: "done", Name: "cfd-done", JobStatus: models.JobStatusContent{Status: "Completed"}},
+		},
+		started: map[string]time.Duration{"long": 30 * time.Hour, "short": time.Hour, "done": 50 * time.Hour},
+		cores:   map[string]int{"long": 2, "short": 64},
+		price:   `0.5`,
+	}
+}
+
+func TestWatchdogRuntimeCapNotify(t *testing.T) {
+	api := testJobs()
+	w := newTestWatchdog(t, api, WatchdogConfig{MaxRuntime: 24 * time.Hour})
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.stopped) != 0 {
+		t.Errorf("notify mode stopped jobs: %v", api.stopped)
+	}
+	entries := readAudit(t, w.cfg.AuditFile)
+	if len(entries) != 1 || entries[0].JobID != "long" || entries[0].Action != WatchdogNotified {
+		t.Fatalf("audit = %+v, want one notified entry for job long", entries)
+	}
+	if entries[0].RuntimeHours != 30 {
+		t.Errorf("runtime_hours = %v, want 30", entries[0].RuntimeHours)
+	}
+
+	// Already reported: no second entry on the next poll
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(readAudit(t, w.cfg.AuditFile)); n != 1 {
+		t.Errorf("audit has %d entries after second check, want 1", n)
+	}
+}
+
+func TestWatchdogCostCapTerminate(t *testing.T) {
+	// short: 64 cores x 1h x 0.5 = 32; long: 2 cores x 30h x 0.5 = 30
+	for _, price := range []string{`0.5`, `"0.5"`} {
+		api := testJobs()
+		api.price = price
+		w := newTestWatchdog(t, api, WatchdogConfig{Terminate: true, MaxCost: 31})
+
+		if err := w.Check(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(api.stopped) != 1 || api.stopped[0] != "short" {
+			t.Errorf("price %s: stopped = %v, want [short]", price, api.stopped)
+		}
+		entries := readAudit(t, w.cfg.AuditFile)
+		if len(entries) != 1 || entries[0].Action != WatchdogTerminated || entries[0].EstimatedCost != 32 {
+			t.Errorf("price %s: audit = %+v, want one terminated entry with cost 32", price, entries)
+		}
+	}
+}
+
+func TestWatchdogUnknownPriceSkipsCostCap(t *testing.T) {
+	api := testJobs()
+	api.price = `null`
+	w := newTestWatchdog(t, api, WatchdogConfig{Terminate: true, MaxCost: 1})
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.stopped) != 0 {
+		t.Errorf("stopped %v without a known price", api.stopped)
+	}
+}
+
+func TestWatchdogProtected(t *testing.T) {
+	api := testJobs()
+	w := newTestWatchdog(t, api, WatchdogConfig{
+		Terminate:  true,
+		MaxRuntime: 30 * time.Minute,
+		Protected:  []string{"long", "*-short"},
+	})
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.stopped) != 0 {
+		t.Errorf("protected jobs were stopped: %v", api.stopped)
+	}
+	entries := readAudit(t, w.cfg.AuditFile)
+	if len(entries) != 2 {
+		t.Fatalf("audit has %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		if e.Action != WatchdogProtected {
+			t.Errorf("job %s action = %s, want %s", e.JobID, e.Action, WatchdogProtected)
+		}
+	}
+}
+
+func TestWatchdogSkipsNonInterlinkJobs(t *testing.T) {
+	api := testJobs()
+	api.tags = map[string][]string{"long": {"web-ui"}, "short": {"interlink-nightly"}}
+	w := newTestWatchdog(t, api, WatchdogConfig{Terminate: true, MaxRuntime: 30 * time.Minute})
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.stopped) != 1 || api.stopped[0] != "short" {
+		t.Errorf("stopped = %v, want only the Interlink job short", api.stopped)
+	}
+	entries := readAudit(t, w.cfg.AuditFile)
+	if len(entries) != 1 || entries[0].JobID != "short" {
+		t.Errorf("audit = %+v, want one entry for job short", entries)
+	}
+}
+
+func TestWatchdogStopFailureRetries(t *testing.T) {
+	api := testJobs()
+	api.stopErr = errors.New("503 service unavailable")
+	w := newTestWatchdog(t, api, WatchdogConfig{Terminate: true, MaxRuntime: 24 * time.Hour})
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	api.stopErr = nil
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api.stopped) != 1 || api.stopped[0] != "long" {
+		t.Errorf("stopped = %v, want [long] on retry", api.stopped)
+	}
+	entries := readAudit(t, w.cfg.AuditFile)
+	if len(entries) != 2 || entries[0].Action != WatchdogStopFailed || entries[0].Error == "" || entries[1].Action != WatchdogTerminated {
+		t.Errorf("audit = %+v, want stop_failed then terminated", entries)
+	}
+}
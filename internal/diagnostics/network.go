@@ -0,0 +1,363 @@
+// Package diagnostics provides connectivity checks used by the GUI network
+// test and attached to support reports.
+package diagnostics
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/cloud/upload"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// NetworkTestResult is the outcome of one network test run.
+// Individual sections carry their own Error so a failure in one probe
+// does not hide the results of the others.
+type NetworkTestResult struct {
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+
+	API     APILatencyResult     `json:"api"`
+	DNS     []DNSResult          `json:"dns"`
+	Proxy   ProxyResult          `json:"proxy"`
+	Storage []StorageProbeResult `json:"storage"`
+
+	StorageType string `json:"storageType,omitempty"`
+}
+
+// APILatencyResult summarizes round-trip times to the platform API.
+type APILatencyResult struct {
+	Host    string  `json:"host"`
+	Samples int     `json:"samples"`
+	Failed  int     `json:"failed"`
+	MinMs   float64 `json:"minMs"`
+	P50Ms   float64 `json:"p50Ms"`
+	P90Ms   float64 `json:"p90Ms"`
+	P99Ms   float64 `json:"p99Ms"`
+	MaxMs   float64 `json:"maxMs"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// DNSResult is the resolution time for one host.
+type DNSResult struct {
+	Host       string   `json:"host"`
+	DurationMs float64  `json:"durationMs"`
+	Addresses  []string `json:"addresses,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ProxyResult describes the proxy path to the API.
+// Hops counts the intermediaries that added a Via header to the API response.
+type ProxyResult struct {
+	Mode string   `json:"mode"`
+	URL  string   `json:"url,omitempty"` // Configured proxy, credentials removed
+	Hops int      `json:"hops"`
+	Via  []string `json:"via,omitempty"`
+}
+
+// StorageProbeResult is the PUT/GET throughput for one probe size.
+// Times are end to end (encryption, transfer and file registration).
+type StorageProbeResult struct {
+	Label        string  `json:"label"` // "small" or "large"
+	Bytes        int64   `json:"bytes"`
+	UploadMs     float64 `json:"uploadMs"`
+	DownloadMs   float64 `json:"downloadMs"`
+	UploadMBps   float64 `json:"uploadMBps"`
+	DownloadMBps float64 `json:"downloadMBps"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// NetworkTester runs the network test against one configuration.
+// The function fields default to the real implementations and are replaced in tests.
+type NetworkTester struct {
+	cfg *config.Config
+
+	latencySamples int
+	probeSizes     map[string]int64
+
+	probeAPI     func(ctx context.Context) (nethttp.Header, error)
+	lookupHost   func(ctx context.Context, host string) ([]string, error)
+	getProfile   func(ctx context.Context) (*models.UserProfile, error)
+	uploadFile   func(ctx context.Context, localPath string) (*models.CloudFile, error)
+	downloadFile func(ctx context.Context, fileID, localPath string) error
+	deleteFile   func(ctx context.Context, fileID string) error
+}
+
+// NewNetworkTester creates a tester using the given config and API client.
+func NewNetworkTester(cfg *config.Config, apiClient *api.Client) *NetworkTester {
+	return &NetworkTester{
+		cfg:            cfg,
+		latencySamples: constants.NetworkTestLatencySamples,
+		probeSizes: map[string]int64{
+			"small": constants.NetworkTestSmallProbeSize,
+			"large": constants.NetworkTestLargeProbeSize,
+		},
+		probeAPI:   apiClient.ProbeAPI,
+		lookupHost: net.DefaultResolver.LookupHost,
+		getProfile: apiClient.GetUserProfile,
+		uploadFile: func(ctx context.Context, localPath string) (*models.CloudFile, error) {
+			return upload.UploadFile(ctx, upload.UploadParams{
+				LocalPath:   localPath,
+				APIClient:   apiClient,
+				SkipHistory: true, // Probe files are deleted after the test
+			})
+		},
+		downloadFile: func(ctx context.Context, fileID, localPath string) error {
+			return download.DownloadFile(ctx, download.DownloadParams{
+				FileID:    fileID,
+				LocalPath: localPath,
+				APIClient: apiClient,
+			})
+		},
+		deleteFile: apiClient.DeleteFile,
+	}
+}
+
+// Run executes all probes and returns the combined result.
+// It never returns nil; failures are recorded per section.
+func (t *NetworkTester) Run(ctx context.Context) *NetworkTestResult {
+	result := &NetworkTestResult{StartedAt: time.Now()}
+	defer func() {
+		result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+	}()
+
+	apiHost := hostOf(t.cfg.APIBaseURL)
+	hosts := []string{apiHost}
+
+	var storageInfo *models.StorageInfo
+	if profile, err := t.getProfile(ctx); err == nil {
+		storageInfo = &profile.DefaultStorage
+		result.StorageType = storageInfo.StorageType
+		if host := StorageHost(storageInfo); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	for _, host := range hosts {
+		result.DNS = append(result.DNS, t.resolve(ctx, host))
+	}
+
+	result.Proxy = ProxyResult{
+		Mode: t.cfg.ProxyMode,
+		URL:  configuredProxy(t.cfg),
+	}
+	if result.Proxy.Mode == "" {
+		result.Proxy.Mode = "no-proxy"
+	}
+
+	result.API, result.Proxy.Via = t.measureAPI(ctx, apiHost)
+	result.Proxy.Hops = len(result.Proxy.Via)
+
+	for _, label := range []string{"small", "large"} {
+		size, ok := t.probeSizes[label]
+		if !ok {
+			continue
+		}
+		result.Storage = append(result.Storage, t.probeStorage(ctx, label, size))
+	}
+
+	return result
+}
+
+// resolve times a DNS lookup for host.
+func (t *NetworkTester) resolve(ctx context.Context, host string) DNSResult {
+	res := DNSResult{Host: host}
+	start := time.Now()
+	addrs, err := t.lookupHost(ctx, host)
+	res.DurationMs = msSince(start)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Addresses = addrs
+	return res
+}
+
+// measureAPI times latencySamples API round trips and collects Via headers.
+func (t *NetworkTester) measureAPI(ctx context.Context, host string) (APILatencyResult, []string) {
+	res := APILatencyResult{Host: host}
+	var durations []float64
+	var via []string
+
+	for i := 0; i < t.latencySamples; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		start := time.Now()
+		headers, err := t.probeAPI(ctx)
+		elapsed := msSince(start)
+		res.Samples++
+		if err != nil {
+			res.Failed++
+			res.Error = err.Error()
+			continue
+		}
+		durations = append(durations, elapsed)
+		if via == nil {
+			via = viaHops(headers)
+		}
+	}
+
+	if len(durations) > 0 {
+		sort.Float64s(durations)
+		res.MinMs = durations[0]
+		res.MaxMs = durations[len(durations)-1]
+		res.P50Ms = Percentile(durations, 50)
+		res.P90Ms = Percentile(durations, 90)
+		res.P99Ms = Percentile(durations, 99)
+	}
+	return res, via
+}
+
+// probeStorage uploads and downloads a random file of size bytes, then deletes it.
+func (t *NetworkTester) probeStorage(ctx context.Context, label string, size int64) StorageProbeResult {
+	res := StorageProbeResult{Label: label, Bytes: size}
+
+	dir, err := os.MkdirTemp("", "rescale-nettest-")
+	if err != nil {
+		res.Error = fmt.Sprintf("create temp dir: %v", err)
+		return res
+	}
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		res.Error = fmt.Sprintf("generate probe data: %v", err)
+		return res
+	}
+	localPath := filepath.Join(dir, fmt.Sprintf("interlink-network-test-%s.bin", label))
+	if err := os.WriteFile(localPath, data, 0600); err != nil {
+		res.Error = fmt.Sprintf("write probe file: %v", err)
+		return res
+	}
+
+	start := time.Now()
+	file, err := t.uploadFile(ctx, localPath)
+	res.UploadMs = msSince(start)
+	if err != nil {
+		res.Error = fmt.Sprintf("upload: %v", err)
+		return res
+	}
+	res.UploadMBps = mbps(size, res.UploadMs)
+
+	// Always remove the probe from the user's library, even if the download fails.
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = t.deleteFile(cleanupCtx, file.ID)
+	}()
+
+	start = time.Now()
+	err = t.downloadFile(ctx, file.ID, filepath.Join(dir, "download.bin"))
+	res.DownloadMs = msSince(start)
+	if err != nil {
+		res.Error = fmt.Sprintf("download: %v", err)
+		return res
+	}
+	res.DownloadMBps = mbps(size, res.DownloadMs)
+
+	return res
+}
+
+// Percentile returns the p-th percentile (nearest rank) of sorted values.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted)) + 0.999999)
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// StorageHost returns the hostname used for storage traffic, or "" if unknown.
+func StorageHost(info *models.StorageInfo) string {
+	if info == nil {
+		return ""
+	}
+	switch info.StorageType {
+	case "S3Storage":
+		if info.ConnectionSettings.Region == "" {
+			return "s3.amazonaws.com"
+		}
+		return fmt.Sprintf("s3.%s.amazonaws.com", info.ConnectionSettings.Region)
+	case "AzureStorage":
+		account := info.ConnectionSettings.AccountName
+		if account == "" {
+			account = info.ConnectionSettings.StorageAccount
+		}
+		if account == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s.blob.core.windows.net", account)
+	default:
+		return ""
+	}
+}
+
+// viaHops splits the Via response headers into one entry per intermediary.
+func viaHops(headers nethttp.Header) []string {
+	var hops []string
+	for _, value := range headers.Values("Via") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// configuredProxy returns the proxy URL from config without credentials.
+func configuredProxy(cfg *config.Config) string {
+	switch strings.ToLower(cfg.ProxyMode) {
+	case "", "no-proxy", "system":
+		return ""
+	}
+	proxyURL := inthttp.BuildProxyURL(cfg)
+	if proxyURL == nil {
+		return ""
+	}
+	redacted := *proxyURL
+	redacted.User = nil
+	return redacted.String()
+}
+
+// hostOf returns the hostname of rawURL, or rawURL itself if it does not parse.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return rawURL
+	}
+	return u.Hostname()
+}
+
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// mbps converts bytes over milliseconds into megabytes per second.
+func mbps(bytes int64, ms float64) float64 {
+	if ms <= 0 {
+		return 0
+	}
+	return float64(bytes) / (1024 * 1024) / (ms / 1000)
+}
@@ -0,0 +1,142 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"os"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// newFakeTester returns a tester whose network calls are all stubbed.
+func newFakeTester(cfg *config.Config) *NetworkTester {
+	return &NetworkTester{
+		cfg:            cfg,
+		latencySamples: 4,
+		probeSizes:     map[string]int64{"small": 1024, "large": 4096},
+		probeAPI: func(ctx context.Context) (nethttp.Header, error) {
+			h := nethttp.Header{}
+			h.Add("Via", "1.1 proxy-a, 1.1 proxy-b")
+			return h, nil
+		},
+		lookupHost: func(ctx context.Context, host string) ([]string, error) {
+			return []string{"192.0.2.1"}, nil
+		},
+		getProfile: func(ctx context.Context) (*models.UserProfile, error) {
+			return &models.UserProfile{DefaultStorage: models.StorageInfo{
+				StorageType:        "S3Storage",
+				ConnectionSettings: models.ConnectionSettings{Region: "us-west-2"},
+			}}, nil
+		},
+		uploadFile: func(ctx context.Context, localPath string) (*models.CloudFile, error) {
+			return &models.CloudFile{ID: "probe-" + localPath}, nil
+		},
+		downloadFile: func(ctx context.Context, fileID, localPath string) error {
+			return os.WriteFile(localPath, []byte("x"), 0600)
+		},
+		deleteFile: func(ctx context.Context, fileID string) error { return nil },
+	}
+}
+
+func TestNetworkTesterRun(t *testing.T) {
+	cfg := &config.Config{
+		APIBaseURL:    "https://platform.rescale.com",
+		ProxyMode:     "basic",
+		ProxyHost:     "proxy.corp",
+		ProxyPort:     3128,
+		ProxyUser:     "alice",
+		ProxyPassword: "secret",
+	}
+	tester := newFakeTester(cfg)
+
+	var deleted []string
+	tester.deleteFile = func(ctx context.Context, fileID string) error {
+		deleted = append(deleted, fileID)
+		return nil
+	}
+
+	result := tester.Run(context.Background())
+
+	if result.API.Host != "platform.rescale.com" || result.API.Samples != 4 || result.API.Failed != 0 {
+		t.Errorf("API = %+v", result.API)
+	}
+	if len(result.DNS) != 2 || result.DNS[1].Host != "s3.us-west-2.amazonaws.com" {
+		t.Errorf("DNS = %+v, want API and storage hosts", result.DNS)
+	}
+	if result.Proxy.Hops != 2 {
+		t.Errorf("Proxy.Hops = %d, want 2", result.Proxy.Hops)
+	}
+	if result.Proxy.URL != "http://proxy.corp:3128" {
+		t.Errorf("Proxy.URL = %q, want credentials removed", result.Proxy.URL)
+	}
+	if len(result.Storage) != 2 || result.Storage[0].Label != "small" || result.Storage[1].Label != "large" {
+		t.Fatalf("Storage = %+v", result.Storage)
+	}
+	for _, probe := range result.Storage {
+		if probe.Error != "" {
+			t.Errorf("%s probe error: %s", probe.Label, probe.Error)
+		}
+	}
+	if len(deleted) != 2 {
+		t.Errorf("deleted %d probe files, want 2", len(deleted))
+	}
+}
+
+func TestNetworkTesterRecordsFailures(t *testing.T) {
+	tester := newFakeTester(&config.Config{APIBaseURL: "https://platform.rescale.com"})
+	tester.probeAPI = func(ctx context.Context) (nethttp.Header, error) {
+		return nil, errors.New("connection refused")
+	}
+	deletedAfterFailedDownload := false
+	tester.downloadFile = func(ctx context.Context, fileID, localPath string) error {
+		return errors.New("403 forbidden")
+	}
+	tester.deleteFile = func(ctx context.Context, fileID string) error {
+		deletedAfterFailedDownload = true
+		return nil
+	}
+
+	result := tester.Run(context.Background())
+
+	if result.API.Failed != 4 || result.API.Error == "" {
+		t.Errorf("API = %+v, want all samples failed", result.API)
+	}
+	if result.Proxy.Mode != "no-proxy" || result.Proxy.Hops != 0 {
+		t.Errorf("Proxy = %+v", result.Proxy)
+	}
+	if result.Storage[0].Error != "download: 403 forbidden" {
+		t.Errorf("Storage[0].Error = %q, want download error", result.Storage[0].Error)
+	}
+	if !deletedAfterFailedDownload {
+		t.Error("probe file not deleted after failed download")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	cases := map[float64]float64{0: 1, 50: 5, 90: 9, 99: 10, 100: 10}
+	for p, want := range cases {
+		if got := Percentile(values, p); got != want {
+			t.Errorf("Percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestStorageHost(t *testing.T) {
+	azure := &models.StorageInfo{
+		StorageType:        "AzureStorage",
+		ConnectionSettings: models.ConnectionSettings{AccountName: "acct"},
+	}
+	if got := StorageHost(azure); got != "acct.blob.core.windows.net" {
+		t.Errorf("StorageHost(azure) = %q", got)
+	}
+	if got := StorageHost(&models.StorageInfo{StorageType: "Other"}); got != "" {
+		t.Errorf("StorageHost(other) = %q, want empty", got)
+	}
+}
@@ -17,19 +17,27 @@ const (
 	EventStateChange EventType = "state_change"
 	EventError       EventType = "error"
 	EventComplete    EventType = "complete"
+	EventRunStarted  EventType = "run_started"
 
 	// Transfer queue events
-	EventTransferQueued       EventType = "transfer_queued"       // Task added to queue
-	EventTransferInitializing EventType = "transfer_initializing" // Acquired slot, initializing
-	EventTransferStarted      EventType = "transfer_started"      // Actual transfer began (bytes moving)
-	EventTransferProgress     EventType = "transfer_progress"     // Progress update
-	EventTransferCompleted    EventType = "transfer_completed"    // Successfully completed
-	EventTransferFailed       EventType = "transfer_failed"       // Failed with error
-	EventTransferCancelled    EventType = "transfer_cancelled"    // Cancelled by user
+	EventTransferQueued         EventType = "transfer_queued"          // Task added to queue
+	EventTransferInitializing   EventType = "transfer_initializing"    // Acquired slot, initializing
+	EventTransferStarted        EventType = "transfer_started"         // Actual transfer began (bytes moving)
+	EventTransferProgress       EventType = "transfer_progress"        // Progress update
+	EventTransferCompleted      EventType = "transfer_completed"       // Successfully completed
+	EventTransferFailed         EventType = "transfer_failed"          // Failed with error
+	EventTransferCancelled      EventType = "transfer_cancelled"       // Cancelled by user
+	EventTransferRetryScheduled EventType = "transfer_retry_scheduled" // Transient failure, automatic retry pending
 
 	// Configuration change events
 	EventConfigChanged EventType = "config_changed" // API key or config changed, caches should be invalidated
 
+	// Network events
+	EventNetworkChanged EventType = "network_changed" // Default route changed or went offline/online
+
+	// Blackout window events (blackout_windows)
+	EventBlackoutChanged EventType = "blackout_changed" // A blackout window started or ended
+
 	// Enumeration events for folder download/upload progress
 	EventEnumerationStarted   EventType = "enumeration_started"   // Folder scan began
 	EventEnumerationProgress  EventType = "enumeration_progress"  // Folder scan progress
@@ -79,11 +87,19 @@ type Event interface {
 type BaseEvent struct {
 	EventType EventType
 	Time      time.Time
+
+	// Correlation IDs of the PUR run and the transfer the event belongs to;
+	// empty when it belongs to neither
+	RunID      string
+	TransferID string
 }
 
 func (e BaseEvent) Type() EventType      { return e.EventType }
 func (e BaseEvent) Timestamp() time.Time { return e.Time }
 
+// CorrelationIDs returns the run and transfer IDs the event is tagged with.
+func (e BaseEvent) CorrelationIDs() (runID, transferID string) { return e.RunID, e.TransferID }
+
 // ProgressEvent represents progress updates
 type ProgressEvent struct {
 	BaseEvent
@@ -95,6 +111,12 @@ type ProgressEvent struct {
 	Message      string
 	Rate         float64 // bytes/sec
 	ETA          time.Duration
+
+	// Files archived so far, of FilesTotal, and the one being archived; only
+	// used for the tar stage
+	FilesCurrent int
+	FilesTotal   int
+	CurrentFile  string
 }
 
 // LogEvent represents log messages
@@ -117,6 +139,12 @@ type StateChangeEvent struct {
 	JobID          string
 	ErrorMessage   string
 	UploadProgress float64 // 0.0 to 1.0, only used for upload stage
+
+	// Queue/provisioning sub-status, only used for the status stage while a
+	// submitted job waits to execute
+	SubStatus       string
+	SubStatusReason string
+	SubStatusSince  time.Time
 }
 
 // ErrorEvent represents error conditions
@@ -128,6 +156,13 @@ type ErrorEvent struct {
 	Retryable bool
 }
 
+// RunStartedEvent marks the start of a pipeline run, before any job is
+// worked on
+type RunStartedEvent struct {
+	BaseEvent
+	TotalJobs int
+}
+
 // CompleteEvent represents pipeline completion
 type CompleteEvent struct {
 	BaseEvent
@@ -135,6 +170,23 @@ type CompleteEvent struct {
 	SuccessJobs int
 	FailedJobs  int
 	Duration    time.Duration
+	ReportPath  string // HTML run report, empty if none was written
+	StateFile   string // PUR state file, empty outside PUR runs
+
+	// Network bytes sent and received during the run
+	BytesSent     int64
+	BytesReceived int64
+
+	// Jobs that failed, in job order
+	Failures []JobFailure
+}
+
+// JobFailure is a job that failed in a run: the stage it failed at (tar,
+// upload or submit) and why.
+type JobFailure struct {
+	JobName string `json:"jobName"`
+	Stage   string `json:"stage"`
+	Error   string `json:"error,omitempty"`
 }
 
 // TransferEvent represents transfer queue events.
@@ -158,6 +210,23 @@ type ConfigChangedEvent struct {
 	Email  string // User email after successful auth (empty if auth failed)
 }
 
+// NetworkChangedEvent reports a default route change. Transfers are paused
+// while Online is false and resume, over fresh connections, when it is back.
+type NetworkChangedEvent struct {
+	BaseEvent
+	OldRoute string // "interface/address" before the change ("" = offline)
+	NewRoute string // "interface/address" after the change ("" = offline)
+	Online   bool
+}
+
+// BlackoutChangedEvent reports that a blackout window started or ended. Tar
+// and upload work is paused while Active is true, until Until.
+type BlackoutChangedEvent struct {
+	BaseEvent
+	Active bool
+	Until  time.Time // End of the window (zero when not Active)
+}
+
 // Enumeration phase constants.
 const (
 	EnumPhaseScanning        = "scanning"
@@ -208,13 +277,13 @@ type SanitizedTimelineEntry struct {
 // Published by Reporter.Report() or ClassifyAndPublish() for GUI error reporting.
 type ReportableErrorEvent struct {
 	BaseEvent
-	ErrorID      string                  `json:"errorID"`
-	Category     string                  `json:"category"`     // "transfer", "job_create", "pur_pipeline", "auth"
-	Severity     string                  `json:"severity"`     // "critical", "error"
-	Operation    string                  `json:"operation"`    // "folder_upload", "file_download", etc.
-	Backend      string                  `json:"backend"`      // "s3", "azure", ""
-	ErrorMessage string                  `json:"errorMessage"` // Redacted
-	ErrorClass   string                  `json:"errorClass"`   // "network", "auth", "disk_space", "client_error", "server_error", "internal", "timeout"
+	ErrorID      string                   `json:"errorID"`
+	Category     string                   `json:"category"`     // "transfer", "job_create", "pur_pipeline", "auth"
+	Severity     string                   `json:"severity"`     // "critical", "error"
+	Operation    string                   `json:"operation"`    // "folder_upload", "file_download", etc.
+	Backend      string                   `json:"backend"`      // "s3", "azure", ""
+	ErrorMessage string                   `json:"errorMessage"` // Redacted
+	ErrorClass   string                   `json:"errorClass"`   // "network", "auth", "disk_space", "client_error", "server_error", "internal", "timeout"
 	Timeline     []SanitizedTimelineEntry `json:"timeline"`
 }
 
@@ -226,8 +295,8 @@ type BatchProgressEvent struct {
 	Label           string  `json:"label"`
 	Direction       string  `json:"direction"` // "upload" or "download"
 	Total           int     `json:"total"`
-	Active          int     `json:"active"`    // Currently transferring
-	Queued          int     `json:"queued"`    // Waiting for semaphore slot
+	Active          int     `json:"active"` // Currently transferring
+	Queued          int     `json:"queued"` // Waiting for semaphore slot
 	Completed       int     `json:"completed"`
 	Failed          int     `json:"failed"`
 	Progress        float64 `json:"progress"`        // 0.0-1.0
@@ -0,0 +1,205 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// NDJSONSchemaVersion is the "v" field of every NDJSON record. Bump it only
+// for incompatible changes; adding fields or event types is compatible.
+const NDJSONSchemaVersion = 1
+
+// ndjsonRecord is one line of the NDJSON event stream. Field names are part
+// of the public contract for external orchestrators and must not change.
+type ndjsonRecord struct {
+	V          int       `json:"v"`
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+	RunID      string    `json:"runId,omitempty"`
+	TransferID string    `json:"transferId,omitempty"`
+	Data       any       `json:"data"`
+}
+
+// correlated is implemented by every event embedding BaseEvent.
+type correlated interface {
+	CorrelationIDs() (runID, transferID string)
+}
+
+type progressData struct {
+	JobName      string  `json:"jobName,omitempty"`
+	Stage        string  `json:"stage,omitempty"`
+	Progress     float64 `json:"progress"`
+	BytesCurrent int64   `json:"bytesCurrent,omitempty"`
+	BytesTotal   int64   `json:"bytesTotal,omitempty"`
+	Message      string  `json:"message,omitempty"`
+	Rate         float64 `json:"rateBytes,omitempty"`
+	ETAMs        int64   `json:"etaMs,omitempty"`
+	FilesCurrent int     `json:"filesCurrent,omitempty"`
+	FilesTotal   int     `json:"filesTotal,omitempty"`
+	CurrentFile  string  `json:"currentFile,omitempty"`
+}
+
+type logData struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Stage   string `json:"stage,omitempty"`
+	JobName string `json:"jobName,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type stateChangeData struct {
+	JobName         string  `json:"jobName"`
+	Stage           string  `json:"stage"`
+	OldStatus       string  `json:"oldStatus,omitempty"`
+	NewStatus       string  `json:"newStatus"`
+	JobID           string  `json:"jobId,omitempty"`
+	ErrorMessage    string  `json:"errorMessage,omitempty"`
+	UploadProgress  float64 `json:"uploadProgress,omitempty"`
+	SubStatus       string  `json:"subStatus,omitempty"`
+	SubStatusReason string  `json:"subStatusReason,omitempty"`
+	SubStatusSince  string  `json:"subStatusSince,omitempty"`
+}
+
+type errorData struct {
+	JobName   string `json:"jobName,omitempty"`
+	Stage     string `json:"stage,omitempty"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+type completeData struct {
+	TotalJobs     int          `json:"totalJobs"`
+	SuccessJobs   int          `json:"successJobs"`
+	FailedJobs    int          `json:"failedJobs"`
+	DurationMs    int64        `json:"durationMs"`
+	ReportPath    string       `json:"reportPath,omitempty"`
+	BytesSent     int64        `json:"bytesSent"`
+	BytesReceived int64        `json:"bytesReceived"`
+	StateFile     string       `json:"stateFile,omitempty"`
+	Failures      []JobFailure `json:"failures,omitempty"`
+}
+
+type runStartedData struct {
+	TotalJobs int `json:"totalJobs"`
+}
+
+type transferData struct {
+	TaskID   string  `json:"taskId"`
+	TaskType string  `json:"taskType"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Speed    float64 `json:"speed,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+type networkChangedData struct {
+	OldRoute string `json:"oldRoute"`
+	NewRoute string `json:"newRoute"`
+	Online   bool   `json:"online"`
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// timeString formats t as RFC 3339, or "" for the zero time.
+func timeString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ndjsonData returns the "data" payload for an event. Events that already
+// carry JSON tags are encoded as they are, minus the embedded BaseEvent
+// (type, time and correlation IDs are on the record itself).
+func ndjsonData(e Event) any {
+	switch ev := e.(type) {
+	case *ProgressEvent:
+		return progressData{ev.JobName, ev.Stage, ev.Progress, ev.BytesCurrent, ev.BytesTotal, ev.Message, ev.Rate, ev.ETA.Milliseconds(), ev.FilesCurrent, ev.FilesTotal, ev.CurrentFile}
+	case *LogEvent:
+		return logData{ev.Level.String(), ev.Message, ev.Stage, ev.JobName, errString(ev.Error)}
+	case *StateChangeEvent:
+		return stateChangeData{ev.JobName, ev.Stage, ev.OldStatus, ev.NewStatus, ev.JobID, ev.ErrorMessage, ev.UploadProgress, ev.SubStatus, ev.SubStatusReason, timeString(ev.SubStatusSince)}
+	case *ErrorEvent:
+		return errorData{ev.JobName, ev.Stage, errString(ev.Error), ev.Retryable}
+	case *RunStartedEvent:
+		return runStartedData{ev.TotalJobs}
+	case *CompleteEvent:
+		return completeData{ev.TotalJobs, ev.SuccessJobs, ev.FailedJobs, ev.Duration.Milliseconds(), ev.ReportPath, ev.BytesSent, ev.BytesReceived, ev.StateFile, ev.Failures}
+	case *TransferEvent:
+		return transferData{ev.TaskID, ev.TaskType, ev.Name, ev.Size, ev.Progress, ev.Speed, errString(ev.Error)}
+	case *NetworkChangedEvent:
+		return networkChangedData{ev.OldRoute, ev.NewRoute, ev.Online}
+	case *ConfigChangedEvent:
+		// Email is identity information and stays out of the stream
+		return struct {
+			Source string `json:"source"`
+		}{ev.Source}
+	case *EnumerationEvent, *ScanProgressEvent, *BatchProgressEvent, *ReportableErrorEvent:
+		return withoutBase{ev}
+	default:
+		return struct{}{}
+	}
+}
+
+// withoutBase encodes an event that has JSON tags without the untagged
+// fields promoted from BaseEvent.
+type withoutBase struct {
+	any
+}
+
+func (w withoutBase) MarshalJSON() ([]byte, error) {
+	raw, err := json.Marshal(w.any)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "EventType")
+	delete(fields, "Time")
+	delete(fields, "RunID")
+	delete(fields, "TransferID")
+	return json.Marshal(fields)
+}
+
+// MarshalNDJSON encodes an event as one NDJSON line (without the newline).
+func MarshalNDJSON(e Event) ([]byte, error) {
+	rec := ndjsonRecord{
+		V:    NDJSONSchemaVersion,
+		Type: e.Type(),
+		Time: e.Timestamp().UTC(),
+		Data: ndjsonData(e),
+	}
+	if c, ok := e.(correlated); ok {
+		rec.RunID, rec.TransferID = c.CorrelationIDs()
+	}
+	return json.Marshal(rec)
+}
+
+// StreamNDJSON writes every event received on ch to w, one JSON object per
+// line, flushing after each so readers see events as they happen. It returns
+// when ch is closed or a write fails.
+func StreamNDJSON(ch <-chan Event, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for e := range ch {
+		line, err := MarshalNDJSON(e)
+		if err != nil {
+			continue
+		}
+		bw.Write(line)
+		bw.WriteByte('\n')
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalNDJSON_StateChange(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	line, err := MarshalNDJSON(&StateChangeEvent{
+		BaseEvent: BaseEvent{EventType: EventStateChange, Time: ts},
+		JobName:   "Run_1",
+		Stage:     "submit",
+		NewStatus: "success",
+		JobID:     "AbCdE",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"v":1,"type":"state_change","time":"2026-01-02T03:04:05Z","data":{"jobName":"Run_1","stage":"submit","newStatus":"success","jobId":"AbCdE"}}`
+	if string(line) != want {
+		t.Errorf("got  %s\nwant %s", line, want)
+	}
+}
+
+func TestMarshalNDJSON_CorrelationIDs(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	line, err := MarshalNDJSON(&TransferEvent{
+		BaseEvent: BaseEvent{EventType: EventTransferStarted, Time: ts, RunID: "pur_1", TransferID: "task_7"},
+		TaskID:    "task_7",
+		TaskType:  "upload",
+		Name:      "a.tar",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"v":1,"type":"transfer_started","time":"2026-01-02T03:04:05Z","runId":"pur_1","transferId":"task_7","data":{"taskId":"task_7","taskType":"upload","name":"a.tar","size":0,"progress":0}}`
+	if string(line) != want {
+		t.Errorf("got  %s\nwant %s", line, want)
+	}
+
+	line, err = MarshalNDJSON(&BatchProgressEvent{
+		BaseEvent: BaseEvent{EventType: EventBatchProgress, Time: ts, RunID: "pur_1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(line), `"RunID"`) || !strings.Contains(string(line), `"runId":"pur_1"`) {
+		t.Errorf("correlation IDs should be on the record only: %s", line)
+	}
+}
+
+func TestMarshalNDJSON_ErrorsAndTaggedEvents(t *testing.T) {
+	line, err := MarshalNDJSON(&LogEvent{
+		BaseEvent: BaseEvent{EventType: EventLog, Time: time.Now()},
+		Level:     ErrorLevel,
+		Message:   "upload failed",
+		Error:     errors.New("connection reset"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(line), `"level":"ERROR"`) || !strings.Contains(string(line), `"error":"connection reset"`) {
+		t.Errorf("log record missing level or error: %s", line)
+	}
+
+	line, err = MarshalNDJSON(&BatchProgressEvent{
+		BaseEvent: BaseEvent{EventType: EventBatchProgress, Time: time.Now()},
+		BatchID:   "pur_1",
+		Total:     3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Data["batchID"] != "pur_1" {
+		t.Errorf("batch data = %v", rec.Data)
+	}
+	if _, ok := rec.Data["EventType"]; ok {
+		t.Errorf("BaseEvent fields leaked into data: %v", rec.Data)
+	}
+}
+
+func TestStreamNDJSON_OneLinePerEvent(t *testing.T) {
+	bus := NewEventBus(10)
+	ch := bus.SubscribeAll()
+	bus.PublishLog(InfoLevel, "one", "tar", "Run_1", nil)
+	bus.PublishStateChange("Run_1", "", "failed", "tar", "", "boom")
+	bus.Close()
+
+	var buf bytes.Buffer
+	if err := StreamNDJSON(ch, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	for _, l := range lines {
+		if !json.Valid([]byte(l)) {
+			t.Errorf("invalid JSON line: %s", l)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package fips
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// InstallerURL is where the FIPS 140-3 compliant installers are published.
+const InstallerURL = "https://github.com/rescale-labs/Rescale_Interlink/releases/latest"
+
+// Cause is why FIPS 140-3 mode is not active.
+type Cause int
+
+const (
+	// CauseBuild means the binary was built without the Go FIPS module
+	// (GOFIPS140 unset or off).
+	CauseBuild Cause = iota
+	// CauseDisabled means the binary has the module, but GODEBUG=fips140=off
+	// in the environment turned it off.
+	CauseDisabled
+)
+
+// Diagnosis explains a failed FIPS check and how to recover from it.
+type Diagnosis struct {
+	Cause Cause
+
+	// OSEnforced reports that the operating system itself runs in FIPS mode
+	// (Linux fips_enabled, the Windows FIPS algorithm policy), so only a
+	// compliant build should run on this machine.
+	OSEnforced bool
+
+	// Companion is a FIPS build of Interlink installed next to this binary,
+	// or "" if there is none.
+	Companion string
+}
+
+// Diagnose works out why FIPS mode is not active in this process.
+func Diagnose() Diagnosis {
+	d := Diagnosis{Cause: CauseBuild, OSEnforced: osEnforcesFIPS()}
+	if info, ok := debug.ReadBuildInfo(); ok && builtWithFIPS(info.Settings) {
+		d.Cause = CauseDisabled
+	}
+	if exe, err := os.Executable(); err == nil {
+		d.Companion = findCompanion(exe)
+	}
+	return d
+}
+
+// builtWithFIPS reports whether build settings record the Go FIPS module.
+func builtWithFIPS(settings []debug.BuildSetting) bool {
+	for _, s := range settings {
+		if s.Key == "GOFIPS140" {
+			return s.Value != "" && s.Value != "off"
+		}
+	}
+	return false
+}
+
+// findCompanion returns another Interlink binary (rescale-int*) in the
+// directory of exe that was built with the FIPS module, or "".
+func findCompanion(exe string) string {
+	self, err := os.Stat(exe)
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Dir(exe)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "rescale-int") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if fi, err := os.Stat(path); err != nil || os.SameFile(fi, self) {
+			continue
+		}
+		if info, err := buildinfo.ReadFile(path); err == nil && builtWithFIPS(info.Settings) {
+			return path
+		}
+	}
+	return ""
+}
+
+// Guidance returns the recovery steps for d as plain text, one per line.
+// buildType is "wails" or "cli", as passed to Init.
+func (d Diagnosis) Guidance(buildType string) string {
+	var b strings.Builder
+	if d.Cause == CauseDisabled {
+		b.WriteString("This binary includes the FIPS 140-3 module, but GODEBUG=fips140=off\n")
+		b.WriteString("in the environment turned it off. Remove fips140=off from GODEBUG\n")
+		b.WriteString("and start Interlink again.\n")
+		return b.String()
+	}
+
+	b.WriteString("This binary was NOT built with FIPS support enabled.\n")
+	if d.OSEnforced {
+		b.WriteString("This system enforces FIPS mode, so only the compliant build can be used here.\n")
+	}
+	if d.Companion != "" {
+		fmt.Fprintf(&b, "\nA FIPS 140-3 compliant build is installed next to it. Run it instead:\n  %s\n", d.Companion)
+	} else {
+		fmt.Fprintf(&b, "\nInstall the FIPS 140-3 compliant build from:\n  %s\n", InstallerURL)
+	}
+
+	rebuildHint := "GOFIPS140=certified go build -tags fips ./cmd/rescale-int"
+	if buildType == "wails" {
+		rebuildHint = "GOFIPS140=certified wails build -tags fips"
+	}
+	b.WriteString("\nWhen building from source, rebuild using: make build\n")
+	fmt.Fprintf(&b, "Or manually: %s\n", rebuildHint)
+	if !d.OSEnforced {
+		b.WriteString("\nFor development ONLY, set RESCALE_ALLOW_NON_FIPS=true to bypass.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package fips
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestBuiltWithFIPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []debug.BuildSetting
+		want     bool
+	}{
+		{"not recorded", []debug.BuildSetting{{Key: "GOOS", Value: "linux"}}, false},
+		{"certified", []debug.BuildSetting{{Key: "GOFIPS140", Value: "v1.0.0"}}, true},
+		{"off", []debug.BuildSetting{{Key: "GOFIPS140", Value: "off"}}, false},
+	}
+	for _, tt := range tests {
+		if got := builtWithFIPS(tt.settings); got != tt.want {
+			t.Errorf("%s: builtWithFIPS = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGuidance(t *testing.T) {
+	tests := []struct {
+		name      string
+		diagnosis Diagnosis
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "build",
+			diagnosis: Diagnosis{Cause: CauseBuild},
+			want:      []string{"NOT built with FIPS", InstallerURL, "wails build -tags fips", "RESCALE_ALLOW_NON_FIPS"},
+		},
+		{
+			name:      "companion",
+			diagnosis: Diagnosis{Cause: CauseBuild, Companion: "/opt/rescale/rescale-int"},
+			want:      []string{"Run it instead", "/opt/rescale/rescale-int"},
+			notWant:   []string{InstallerURL},
+		},
+		{
+			name:      "os enforced",
+			diagnosis: Diagnosis{Cause: CauseBuild, OSEnforced: true},
+			want:      []string{"This system enforces FIPS mode", InstallerURL},
+			notWant:   []string{"RESCALE_ALLOW_NON_FIPS"},
+		},
+		{
+			name:      "disabled by GODEBUG",
+			diagnosis: Diagnosis{Cause: CauseDisabled},
+			want:      []string{"GODEBUG=fips140=off"},
+			notWant:   []string{InstallerURL, "make build"},
+		},
+	}
+	for _, tt := range tests {
+		got := tt.diagnosis.Guidance("wails")
+		for _, s := range tt.want {
+			if !strings.Contains(got, s) {
+				t.Errorf("%s: guidance missing %q:\n%s", tt.name, s, got)
+			}
+		}
+		for _, s := range tt.notWant {
+			if strings.Contains(got, s) {
+				t.Errorf("%s: guidance should not contain %q:\n%s", tt.name, s, got)
+			}
+		}
+	}
+}
+
+func TestFindCompanion_IgnoresNonFIPSFiles(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && builtWithFIPS(info.Settings) {
+		t.Skip("the test binary itself has the FIPS module")
+	}
+	testBinary, err := os.ReadFile(self)
+	if err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "rescale-int-gui")
+	for name, data := range map[string][]byte{
+		"rescale-int-gui": testBinary,      // this binary itself
+		"rescale-int":     testBinary,      // a Go binary without the FIPS module
+		"rescale-int.txt": []byte("notes"), // not a binary
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := findCompanion(exe); got != "" {
+		t.Errorf("findCompanion = %q, want none", got)
+	}
+}
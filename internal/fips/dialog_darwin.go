@@ -0,0 +1,32 @@
+package fips
+
+import (
+	"os/exec"
+	"strings"
+)
+
+const openInstallerButton = "Open Download Page"
+
+// showDialog shows message in an AppleScript dialog. With offerInstaller it
+// offers to open the installer download page and reports whether the user
+// chose to.
+func showDialog(title, message string, offerInstaller bool) bool {
+	buttons := `buttons {"Quit"} default button "Quit"`
+	if offerInstaller {
+		buttons = `buttons {"Quit", "` + openInstallerButton + `"} default button "` + openInstallerButton + `"`
+	}
+	script := "display dialog " + appleScriptString(message) + " with title " + appleScriptString(title) +
+		" " + buttons + " with icon stop"
+	out, err := exec.Command("osascript", "-e", script).Output()
+	return err == nil && offerInstaller && strings.Contains(string(out), openInstallerButton)
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// openURL opens url in the default browser.
+func openURL(url string) error {
+	return exec.Command("open", url).Start()
+}
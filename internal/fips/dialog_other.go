@@ -0,0 +1,33 @@
+//go:build !windows && !darwin
+
+package fips
+
+import "os/exec"
+
+// showDialog shows message with zenity or kdialog, whichever is installed.
+// With offerInstaller it asks whether to open the installer download page
+// and reports the answer. Without either tool the message is only on stderr.
+func showDialog(title, message string, offerInstaller bool) bool {
+	if path, err := exec.LookPath("zenity"); err == nil {
+		if !offerInstaller {
+			_ = exec.Command(path, "--error", "--title", title, "--text", message, "--no-markup").Run()
+			return false
+		}
+		return exec.Command(path, "--question", "--title", title, "--text", message, "--no-markup",
+			"--ok-label", "Open Download Page", "--cancel-label", "Quit").Run() == nil
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		if !offerInstaller {
+			_ = exec.Command(path, "--title", title, "--error", message).Run()
+			return false
+		}
+		return exec.Command(path, "--title", title, "--yesno", message,
+			"--yes-label", "Open Download Page", "--no-label", "Quit").Run() == nil
+	}
+	return false
+}
+
+// openURL opens url in the default browser.
+func openURL(url string) error {
+	return exec.Command("xdg-open", url).Start()
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package fips
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	mbOK        = 0x00000000
+	mbYesNo     = 0x00000004
+	mbIconError = 0x00000010
+	idYes       = 6
+)
+
+var procMessageBoxW = windows.NewLazySystemDLL("user32.dll").NewProc("MessageBoxW")
+
+// showDialog shows message in a message box. With offerInstaller it asks
+// whether to open the installer download page and reports the answer.
+func showDialog(title, message string, offerInstaller bool) bool {
+	style := uintptr(mbOK | mbIconError)
+	if offerInstaller {
+		message += "\nOpen the download page for the compliant installer?"
+		style = mbYesNo | mbIconError
+	}
+	text, err := windows.UTF16PtrFromString(message)
+	if err != nil {
+		return false
+	}
+	caption, err := windows.UTF16PtrFromString(title)
+	if err != nil {
+		return false
+	}
+	ret, _, _ := procMessageBoxW.Call(0, uintptr(unsafe.Pointer(text)), uintptr(unsafe.Pointer(caption)), style)
+	return offerInstaller && ret == idYes
+}
+
+// openURL opens url in the default browser.
+func openURL(url string) error {
+	verb, err := windows.UTF16PtrFromString("open")
+	if err != nil {
+		return err
+	}
+	file, err := windows.UTF16PtrFromString(url)
+	if err != nil {
+		return err
+	}
+	return windows.ShellExecute(0, verb, file, nil, nil, windows.SW_SHOWNORMAL)
+}
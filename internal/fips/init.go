@@ -18,18 +18,28 @@ var Enabled bool
 //
 // buildType should be "wails" for the GUI binary or "cli" for the standalone
 // CLI binary; it controls the rebuild hint shown when FIPS is not active.
+// gui is true when the process is about to start the GUI.
 //
 // If FIPS is not enabled and RESCALE_ALLOW_NON_FIPS is not set to "true",
-// Init prints an error to stderr and calls os.Exit(2).
-func Init(buildType string) {
+// Init prints a diagnosis with recovery steps to stderr (see Diagnose),
+// shows it in a dialog as well when gui is set, and calls os.Exit(2).
+func Init(buildType string, gui bool) {
 	Enabled = fips140.Enabled()
 	if Enabled {
 		return
 	}
 
 	// FIPS is NOT active - this is a compliance issue
+	diagnosis := Diagnose()
 	log.Printf("[CRITICAL] FIPS 140-3 mode is NOT active")
-	log.Printf("[CRITICAL] This binary was NOT built with GOFIPS140=certified")
+	if diagnosis.Cause == CauseDisabled {
+		log.Printf("[CRITICAL] This binary was built with FIPS support, but GODEBUG=fips140=off disabled it")
+	} else {
+		log.Printf("[CRITICAL] This binary was NOT built with GOFIPS140=certified")
+	}
+	if diagnosis.OSEnforced {
+		log.Printf("[CRITICAL] The operating system enforces FIPS mode")
+	}
 	log.Printf("[CRITICAL] FedRAMP compliance REQUIRES FIPS 140-3 mode")
 
 	// Check if non-FIPS mode is explicitly allowed (for development only)
@@ -38,19 +48,21 @@ func Init(buildType string) {
 		return
 	}
 
-	rebuildHint := "GOFIPS140=certified go build -tags fips ./cmd/rescale-int"
-	if buildType == "wails" {
-		rebuildHint = "GOFIPS140=certified wails build -tags fips"
-	}
-
+	guidance := diagnosis.Guidance(buildType)
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "ERROR: FIPS 140-3 compliance is REQUIRED.\n")
 	fmt.Fprintf(os.Stderr, "\n")
-	fmt.Fprintf(os.Stderr, "This binary was NOT built with FIPS support enabled.\n")
-	fmt.Fprintf(os.Stderr, "Please rebuild using: make build\n")
-	fmt.Fprintf(os.Stderr, "Or manually: %s\n", rebuildHint)
-	fmt.Fprintf(os.Stderr, "\n")
-	fmt.Fprintf(os.Stderr, "For development ONLY, set RESCALE_ALLOW_NON_FIPS=true to bypass.\n")
-	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "%s\n", guidance)
+
+	if gui {
+		message := "Rescale Interlink requires FIPS 140-3 validated cryptography for FedRAMP compliance and cannot start.\n\n" + guidance
+		// The download page only helps when this build is the problem
+		offerInstaller := diagnosis.Cause == CauseBuild
+		if showDialog("Rescale Interlink - FIPS 140-3 required", message, offerInstaller) {
+			if err := openURL(InstallerURL); err != nil {
+				log.Printf("[WARN] Failed to open %s: %v", InstallerURL, err)
+			}
+		}
+	}
 	os.Exit(2) // Exit with code 2 to indicate compliance failure
 }
@@ -0,0 +1,13 @@
+package fips
+
+import (
+	"os"
+	"strings"
+)
+
+// osEnforcesFIPS reports whether the kernel runs in FIPS mode (fips=1 on the
+// kernel command line, as set by fips-mode-setup on RHEL).
+func osEnforcesFIPS() bool {
+	data, err := os.ReadFile("/proc/sys/crypto/fips_enabled")
+	return err == nil && strings.TrimSpace(string(data)) == "1"
+}
@@ -0,0 +1,6 @@
+//go:build !windows && !linux
+
+package fips
+
+// osEnforcesFIPS reports false: there is no system-wide FIPS switch to check.
+func osEnforcesFIPS() bool { return false }
@@ -0,0 +1,18 @@
+//go:build windows
+
+package fips
+
+import "golang.org/x/sys/windows/registry"
+
+// osEnforcesFIPS reports whether the "System cryptography: Use FIPS compliant
+// algorithms" security policy is enabled.
+func osEnforcesFIPS() bool {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\Lsa\FipsAlgorithmPolicy`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	enabled, _, err := key.GetIntegerValue("Enabled")
+	return err == nil && enabled == 1
+}
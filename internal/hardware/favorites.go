@@ -0,0 +1,79 @@
+package hardware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Favorites is the set of core type codes a user has starred.
+type Favorites []string
+
+// Has reports whether code is a favorite.
+func (f Favorites) Has(code string) bool {
+	return slices.Contains(f, code)
+}
+
+// favoritesFile is the JSON layout of the favorites file.
+type favoritesFile struct {
+	CoreTypes Favorites `json:"coreTypes"`
+}
+
+// LoadFavorites reads the favorites file at path (see
+// config.GetHardwareFavoritesPath). A missing file has no favorites.
+func LoadFavorites(path string) (Favorites, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hardware favorites: %w", err)
+	}
+	var f favoritesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid hardware favorites %s: %w", path, err)
+	}
+	return f.CoreTypes, nil
+}
+
+// SetFavorite stars (on) or unstars code in the favorites file at path and
+// returns the favorites after the change.
+func SetFavorite(path, code string, on bool) (Favorites, error) {
+	if code == "" {
+		return nil, fmt.Errorf("core type code is required")
+	}
+	favs, err := LoadFavorites(path)
+	if err != nil {
+		return nil, err
+	}
+	if on == favs.Has(code) {
+		return favs, nil
+	}
+	if on {
+		favs = append(favs, code)
+		sort.Strings(favs)
+	} else {
+		favs = slices.DeleteFunc(favs, func(c string) bool { return c == code })
+	}
+
+	data, err := json.MarshalIndent(favoritesFile{CoreTypes: favs}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to save hardware favorites: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save hardware favorites: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to save hardware favorites: %w", err)
+	}
+	return favs, nil
+}
@@ -0,0 +1,217 @@
+// Package hardware classifies Rescale core types by the hardware details the
+// platform reports, for the filters, favorites and compatibility order of the
+// core type picker (`rescale-int hardware list`, the GUI template builder).
+//
+// Memory per core, interconnect and architecture come from a core type's
+// memory, io, architecture and processorInfo fields; a class that cannot be
+// told from them is empty and matches no filter on it. Price bands need the
+// on-demand price per core-hour, which not every platform reports.
+package hardware
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Architectures.
+const (
+	ArchX86 = "x86_64"
+	ArchARM = "arm64"
+)
+
+// Interconnects.
+const (
+	InterconnectInfiniBand = "infiniband"
+	InterconnectEFA        = "efa"
+	InterconnectEthernet   = "ethernet"
+)
+
+// Price bands.
+const (
+	PriceLow    = "low"
+	PriceMedium = "medium"
+	PriceHigh   = "high"
+)
+
+// Price band limits, in USD per core-hour: below lowPriceLimit is low, from
+// highPriceLimit up is high.
+const (
+	lowPriceLimit  = 0.05
+	highPriceLimit = 0.15
+)
+
+// Architectures, Interconnects and PriceBands list the filter values, in
+// display order.
+var (
+	Architectures = []string{ArchX86, ArchARM}
+	Interconnects = []string{InterconnectInfiniBand, InterconnectEFA, InterconnectEthernet}
+	PriceBands    = []string{PriceLow, PriceMedium, PriceHigh}
+)
+
+// MemoryPerCoreGB returns the memory per core in GB, or 0 when not reported.
+func MemoryPerCoreGB(ct models.CoreType) float64 {
+	return ct.Memory / 1024
+}
+
+// Architecture returns ArchX86 or ArchARM: the reported architecture, or
+// else the one the processor name implies. Empty when neither tells.
+func Architecture(ct models.CoreType) string {
+	switch a := strings.ToLower(ct.Architecture); a {
+	case "":
+	case "x86_64", "x86-64", "x86", "amd64":
+		return ArchX86
+	case "arm64", "aarch64", "arm":
+		return ArchARM
+	default:
+		return a
+	}
+	p := strings.ToLower(ct.Processor)
+	switch {
+	case containsAny(p, "graviton", "ampere", "altra", "neoverse", "arm"):
+		return ArchARM
+	case containsAny(p, "intel", "xeon", "amd", "epyc", "opteron"):
+		return ArchX86
+	}
+	return ""
+}
+
+// Interconnect returns InterconnectInfiniBand, InterconnectEFA or
+// InterconnectEthernet as the io field describes it. A bare speed such as
+// "10 Gbps" is Ethernet. Empty when not reported.
+func Interconnect(ct models.CoreType) string {
+	io := strings.ToLower(ct.Interconnect)
+	switch {
+	case io == "":
+		return ""
+	case containsAny(io, "infiniband", "hdr", "edr", "ndr", "fdr") || slices.Contains(strings.Fields(io), "ib"):
+		return InterconnectInfiniBand
+	case strings.Contains(io, "efa") || strings.Contains(io, "elastic fabric"):
+		return InterconnectEFA
+	case containsAny(io, "ethernet", "gb", "gigabit"):
+		return InterconnectEthernet
+	}
+	return ""
+}
+
+// PriceBand returns PriceLow, PriceMedium or PriceHigh for the price per
+// core-hour. Empty when the price is not reported.
+func PriceBand(ct models.CoreType) string {
+	switch {
+	case ct.Price <= 0:
+		return ""
+	case ct.Price < lowPriceLimit:
+		return PriceLow
+	case ct.Price < highPriceLimit:
+		return PriceMedium
+	}
+	return PriceHigh
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter selects core types. Zero fields match everything.
+type Filter struct {
+	Search             string  // Code or name contains, case-insensitive
+	MinMemoryPerCoreGB float64 // At least this much memory per core
+	Interconnect       string  // One of Interconnects
+	Architecture       string  // One of Architectures
+	PriceBand          string  // One of PriceBands
+}
+
+// Validate checks the filter's values.
+func (f Filter) Validate() error {
+	if f.MinMemoryPerCoreGB < 0 {
+		return fmt.Errorf("minimum memory per core cannot be negative")
+	}
+	for _, c := range []struct {
+		name, value string
+		valid       []string
+	}{
+		{"interconnect", f.Interconnect, Interconnects},
+		{"architecture", f.Architecture, Architectures},
+		{"price band", f.PriceBand, PriceBands},
+	} {
+		if c.value != "" && !slices.Contains(c.valid, strings.ToLower(c.value)) {
+			return fmt.Errorf("unknown %s %q (expected %s)", c.name, c.value, strings.Join(c.valid, ", "))
+		}
+	}
+	return nil
+}
+
+// Match reports whether ct passes the filter.
+func (f Filter) Match(ct models.CoreType) bool {
+	if f.Search != "" {
+		s := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(ct.Code), s) && !strings.Contains(strings.ToLower(ct.Name), s) {
+			return false
+		}
+	}
+	if f.MinMemoryPerCoreGB > 0 && MemoryPerCoreGB(ct) < f.MinMemoryPerCoreGB {
+		return false
+	}
+	if f.Interconnect != "" && Interconnect(ct) != strings.ToLower(f.Interconnect) {
+		return false
+	}
+	if f.Architecture != "" && Architecture(ct) != strings.ToLower(f.Architecture) {
+		return false
+	}
+	if f.PriceBand != "" && PriceBand(ct) != strings.ToLower(f.PriceBand) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the core types that pass the filter.
+func (f Filter) Apply(coreTypes []models.CoreType) []models.CoreType {
+	out := make([]models.CoreType, 0, len(coreTypes))
+	for _, ct := range coreTypes {
+		if f.Match(ct) {
+			out = append(out, ct)
+		}
+	}
+	return out
+}
+
+// Compatible reports whether an analysis version whose allowed core types
+// are allowed can run on code. An empty list allows every core type.
+func Compatible(code string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort orders core types for picking: those compatible with the analysis
+// version's allowed core types first, favorites first among each, then by
+// display order and code.
+func Sort(coreTypes []models.CoreType, allowed []string, favorites Favorites) {
+	sort.SliceStable(coreTypes, func(i, j int) bool {
+		a, b := coreTypes[i], coreTypes[j]
+		if ca, cb := Compatible(a.Code, allowed), Compatible(b.Code, allowed); ca != cb {
+			return ca
+		}
+		if fa, fb := favorites.Has(a.Code), favorites.Has(b.Code); fa != fb {
+			return fa
+		}
+		if a.DisplayOrder != b.DisplayOrder {
+			return a.DisplayOrder < b.DisplayOrder
+		}
+		return a.Code < b.Code
+	})
+}
@@ -0,0 +1,91 @@
+package hardware
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+var testCoreTypes = []models.CoreType{
+	{Code: "emerald", Name: "Emerald", DisplayOrder: 1, Memory: 4096, Processor: "Intel Xeon E5-2670", Interconnect: "10 Gbps", Price: 0.04},
+	{Code: "carbon", Name: "Carbon", DisplayOrder: 2, Memory: 8192, Processor: "Intel Xeon Platinum 8375C", Interconnect: "200 Gbps HDR InfiniBand", Price: 0.12},
+	{Code: "granite", Name: "Granite", DisplayOrder: 3, Memory: 2048, Processor: "AWS Graviton3", Interconnect: "25 Gbps EFA"},
+	{Code: "onyx", Name: "Onyx", DisplayOrder: 4, Memory: 16384, Architecture: "x86_64", Price: 0.30},
+}
+
+func TestClassify(t *testing.T) {
+	for _, tc := range []struct {
+		code, arch, interconnect, band string
+		memGB                          float64
+	}{
+		{"emerald", ArchX86, InterconnectEthernet, PriceLow, 4},
+		{"carbon", ArchX86, InterconnectInfiniBand, PriceMedium, 8},
+		{"granite", ArchARM, InterconnectEFA, "", 2},
+		{"onyx", ArchX86, "", PriceHigh, 16},
+	} {
+		i := slices.IndexFunc(testCoreTypes, func(ct models.CoreType) bool { return ct.Code == tc.code })
+		ct := testCoreTypes[i]
+		if got := Architecture(ct); got != tc.arch {
+			t.Errorf("Architecture(%s) = %q, want %q", tc.code, got, tc.arch)
+		}
+		if got := Interconnect(ct); got != tc.interconnect {
+			t.Errorf("Interconnect(%s) = %q, want %q", tc.code, got, tc.interconnect)
+		}
+		if got := PriceBand(ct); got != tc.band {
+			t.Errorf("PriceBand(%s) = %q, want %q", tc.code, got, tc.band)
+		}
+		if got := MemoryPerCoreGB(ct); got != tc.memGB {
+			t.Errorf("MemoryPerCoreGB(%s) = %v, want %v", tc.code, got, tc.memGB)
+		}
+	}
+}
+
+func TestFilterAndSort(t *testing.T) {
+	codes := func(cts []models.CoreType) []string {
+		var out []string
+		for _, ct := range cts {
+			out = append(out, ct.Code)
+		}
+		return out
+	}
+
+	if got := codes(Filter{MinMemoryPerCoreGB: 8, Architecture: "X86_64"}.Apply(testCoreTypes)); !slices.Equal(got, []string{"carbon", "onyx"}) {
+		t.Errorf("memory and architecture filter = %v", got)
+	}
+	if got := codes(Filter{Interconnect: InterconnectInfiniBand}.Apply(testCoreTypes)); !slices.Equal(got, []string{"carbon"}) {
+		t.Errorf("interconnect filter = %v", got)
+	}
+	if got := codes(Filter{PriceBand: PriceLow, Search: "EMER"}.Apply(testCoreTypes)); !slices.Equal(got, []string{"emerald"}) {
+		t.Errorf("price and search filter = %v", got)
+	}
+	if err := (Filter{Interconnect: "token-ring"}).Validate(); err == nil {
+		t.Error("Validate accepted an unknown interconnect")
+	}
+
+	cts := slices.Clone(testCoreTypes)
+	Sort(cts, []string{"Granite", "onyx", "carbon"}, Favorites{"onyx", "emerald"})
+	if got := codes(cts); !slices.Equal(got, []string{"onyx", "carbon", "granite", "emerald"}) {
+		t.Errorf("Sort = %v, want compatible first, then favorites, then display order", got)
+	}
+}
+
+func TestSetFavorite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "favorites.json")
+	if favs, err := LoadFavorites(path); err != nil || favs != nil {
+		t.Fatalf("LoadFavorites(missing) = %v, %v", favs, err)
+	}
+	for _, code := range []string{"onyx", "emerald", "onyx"} {
+		if _, err := SetFavorite(path, code, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := SetFavorite(path, "onyx", false); err != nil {
+		t.Fatal(err)
+	}
+	favs, err := LoadFavorites(path)
+	if err != nil || !slices.Equal(favs, Favorites{"emerald"}) {
+		t.Errorf("LoadFavorites() = %v, %v, want [emerald]", favs, err)
+	}
+}
@@ -73,9 +73,10 @@ func CreateOptimizedClient(cfg *config.Config) (*nethttp.Client, error) {
 	// Get the transport from the base client
 	tr, ok := baseClient.Transport.(*nethttp.Transport)
 	if !ok {
-		// If transport is not *nethttp.Transport (e.g., wrapped by NTLM negotiator),
-		// we can't apply optimizations, so return the base client as-is
-		// This happens with NTLM proxy mode which uses ntlmssp.Negotiator wrapper
+		// If transport is not *nethttp.Transport (e.g., a wrapping RoundTripper),
+		// we can't apply optimizations, so return the base client as-is.
+		// NTLM/Negotiate proxy modes keep a plain *nethttp.Transport (auth
+		// happens in its DialContext), so they are optimized like the rest.
 		// Clear the 300s timeout to allow long transfers.
 		// Per-operation timeouts should be used via context instead.
 		baseClient.Timeout = 0
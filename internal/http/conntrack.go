@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// Live connection registry. Every connection dialed by ConfigureHTTPClient
+// transports (API, S3, Azure) is tracked until it is closed, so a network
+// change can tear down connections bound to the old interface. Idle pool
+// cleanup alone is not enough: after a Wi-Fi → VPN switch, in-flight reads on
+// the old route hang until a timeout instead of failing fast.
+var (
+	connMu    sync.Mutex
+	liveConns = make(map[*trackedConn]struct{})
+)
+
+// Bytes sent and received over tracked connections since the process
+// started, TLS and HTTP overhead included: what the network carries, for
+// sites with egress budgets (see package netusage).
+var bytesSent, bytesReceived atomic.Int64
+
+// trackedConn removes itself from the registry when closed and counts the
+// bytes it carries.
+type trackedConn struct {
+	net.Conn
+	once sync.Once
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	bytesReceived.Add(int64(n))
+	return n, err
+}
+
+func (c *trackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	bytesSent.Add(int64(n))
+	return n, err
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		connMu.Lock()
+		delete(liveConns, c)
+		connMu.Unlock()
+	})
+	return c.Conn.Close()
+}
+
+// DialFunc matches net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// trackDial wraps a dial function so the connections it returns are
+// registered for ResetAllConnections.
+func trackDial(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tc := &trackedConn{Conn: conn}
+		connMu.Lock()
+		liveConns[tc] = struct{}{}
+		connMu.Unlock()
+		return tc, nil
+	}
+}
+
+// ResetAllConnections closes every tracked connection, idle or in use.
+// In-flight requests fail with a network error, which the retry layer
+// (ExecuteWithRetry, SDK retryers) handles by re-dialing over the current
+// route and retrying the part. Called when the default route changes.
+// Returns the number of connections closed.
+func ResetAllConnections() int {
+	connMu.Lock()
+	conns := make([]*trackedConn, 0, len(liveConns))
+	for c := range liveConns {
+		conns = append(conns, c)
+	}
+	connMu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+	CloseAllIdleConnections()
+	return len(conns)
+}
+
+// NetworkBytes returns the bytes sent and received over all tracked
+// connections (API, S3, Azure) since the process started.
+func NetworkBytes() (sent, received int64) {
+	return bytesSent.Load(), bytesReceived.Load()
+}
+
+// LiveConnectionCount returns the number of tracked open connections.
+func LiveConnectionCount() int {
+	connMu.Lock()
+	defer connMu.Unlock()
+	return len(liveConns)
+}
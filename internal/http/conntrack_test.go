@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+func TestResetAllConnections_AbortsInFlightRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusOK)
+		w.(nethttp.Flusher).Flush()
+		<-release // hang like a read on a dead route
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, err := ConfigureHTTPClient(&config.Config{ProxyMode: "no-proxy"})
+	if err != nil {
+		t.Fatalf("ConfigureHTTPClient: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		req, _ := nethttp.NewRequestWithContext(context.Background(), "GET", srv.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		done <- err
+	}()
+
+	// Wait until the connection is tracked, then simulate a route change
+	deadline := time.Now().Add(5 * time.Second)
+	for LiveConnectionCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("connection was never tracked")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := ResetAllConnections(); n == 0 {
+		t.Error("ResetAllConnections() closed no connections")
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("in-flight read succeeded after reset")
+		}
+		if ClassifyError(err) != ErrorTypeNetwork {
+			t.Errorf("ClassifyError(%v) = %s, want network (retryable)", err, ErrorTypeName(ClassifyError(err)))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request did not fail after ResetAllConnections")
+	}
+	if LiveConnectionCount() != 0 {
+		t.Errorf("LiveConnectionCount() = %d after reset, want 0", LiveConnectionCount())
+	}
+}
+
+func TestNetworkBytes_CountsTrackedConnections(t *testing.T) {
+	body := strings.Repeat("x", 64*1024)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		io.Copy(io.Discard, r.Body)
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	client, err := ConfigureHTTPClient(&config.Config{ProxyMode: "no-proxy"})
+	if err != nil {
+		t.Fatalf("ConfigureHTTPClient: %v", err)
+	}
+
+	sent0, received0 := NetworkBytes()
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	sent, received := NetworkBytes()
+	if sent-sent0 < int64(len(body)) {
+		t.Errorf("sent %d bytes, want at least %d", sent-sent0, len(body))
+	}
+	if received-received0 < int64(len(body)) {
+		t.Errorf("received %d bytes, want at least %d", received-received0, len(body))
+	}
+}
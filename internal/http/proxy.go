@@ -24,10 +24,10 @@ func ConfigureHTTPClient(cfg *config.Config) (*nethttp.Client, error) {
 	}
 
 	transport := &nethttp.Transport{
-		DialContext: (&net.Dialer{
+		DialContext: trackDial((&net.Dialer{
 			Timeout:   constants.HTTPDialTimeout,
 			KeepAlive: constants.HTTPDialKeepAlive,
-		}).DialContext,
+		}).DialContext),
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		},
@@ -49,12 +49,12 @@ func ConfigureHTTPClient(cfg *config.Config) (*nethttp.Client, error) {
 		// Use system proxy settings from environment
 		transport.Proxy = nethttp.ProxyFromEnvironment
 
-	case "ntlm":
-		// NTLM authentication
+	case "ntlm", "negotiate":
+		// NTLM or Negotiate (Kerberos) authentication on the CONNECT tunnel.
 		// Fall back to no-proxy if host is missing (incomplete saved config).
 		// This allows GUI to start so user can reconfigure proxy settings.
 		if cfg.ProxyHost == "" {
-			fmt.Printf("[WARN] Proxy mode is NTLM but host is missing - falling back to no-proxy mode\n")
+			fmt.Printf("[WARN] Proxy mode is %s but host is missing - falling back to no-proxy mode\n", cfg.ProxyMode)
 			transport.Proxy = nil
 			return &nethttp.Client{
 				Transport: transport,
@@ -62,22 +62,32 @@ func ConfigureHTTPClient(cfg *config.Config) (*nethttp.Client, error) {
 			}, nil
 		}
 
-		proxyURL := BuildProxyURL(cfg)
-		transport.Proxy = proxyFuncWithBypass(proxyURL, cfg.NoProxy)
-
-		ntlmRoundTripper, err := ntlmTransport(transport)
-		if err != nil {
-			return nil, err
+		// An unusable authenticator (e.g. password not entered yet) still
+		// yields a client, so the GUI can start; each proxied connection
+		// then fails with the reason.
+		auth, authErr := newProxyAuth(cfg)
+		if authErr != nil {
+			fmt.Printf("[WARN] %v - proxy auth disabled until the proxy settings are fixed\n", authErr)
+			auth = &proxyAuth{scheme: cfg.ProxyMode, newSession: func(string) (authSession, error) {
+				return nil, authErr
+			}}
 		}
 
+		dialer := newTunnelDialer(BuildProxyURL(cfg), cfg.NoProxy, auth, (&net.Dialer{
+			Timeout:   constants.HTTPDialTimeout,
+			KeepAlive: constants.HTTPDialKeepAlive,
+		}).DialContext)
+		transport.Proxy = nil
+		transport.DialContext = trackDial(dialer.DialContext)
+
 		client := &nethttp.Client{
-			Transport: ntlmRoundTripper,
+			Transport: transport,
 			Timeout:   300 * time.Second,
 		}
 
-		// Only perform warmup if credentials are complete and warmup is requested.
+		// Only perform warmup if the authenticator is usable and warmup is requested.
 		// If password is missing, skip warmup - let the caller prompt for password.
-		if cfg.ProxyWarmup && cfg.ProxyUser != "" && cfg.ProxyPassword != "" {
+		if cfg.ProxyWarmup && authErr == nil {
 			if err := warmupProxy(client, cfg); err != nil {
 				return nil, fmt.Errorf("proxy warmup failed: %w", err)
 			}
@@ -168,8 +178,8 @@ func BuildProxyURL(cfg *config.Config) *url.URL {
 // warmupProxy performs a warmup request to establish proxy connection.
 func warmupProxy(client *nethttp.Client, cfg *config.Config) error {
 	// Use a lightweight endpoint for warmup
-	warmupURL := cfg.APIBaseURL
-	if warmupURL == "" {
+	warmupURL := cfg.APIRoot()
+	if cfg.APIBaseURL == "" {
 		warmupURL = "https://platform.rescale.com"
 	}
 
@@ -227,8 +237,8 @@ func WarmupProxyConnection(ctx context.Context, cfg *config.Config) error {
 		Timeout:   timeout,
 	}
 
-	warmupURL := cfg.APIBaseURL
-	if warmupURL == "" {
+	warmupURL := cfg.APIRoot()
+	if cfg.APIBaseURL == "" {
 		warmupURL = "https://platform.rescale.com"
 	}
 
@@ -301,8 +311,8 @@ func proxyFuncWithBypass(proxyURL *url.URL, noProxy string) func(*nethttp.Reques
 // but one has not been provided. Used by CLI to determine if interactive prompt is needed.
 func NeedsProxyPassword(cfg *config.Config) bool {
 	mode := strings.ToLower(cfg.ProxyMode)
-	// Only basic and ntlm modes require credentials
-	if mode != "basic" && mode != "ntlm" {
+	// Only basic, ntlm and negotiate modes take credentials
+	if mode != "basic" && mode != "ntlm" && mode != "negotiate" {
 		return false
 	}
 	// If user is set but password is not, we need to prompt
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Connection-oriented proxy authentication (NTLM and Negotiate/Kerberos).
+//
+// These schemes authenticate a TCP connection rather than a request, so the
+// handshake has to run on the CONNECT that opens the tunnel, on the same
+// connection, before TLS starts. net/http can only send one fixed
+// Proxy-Authorization header on CONNECT, so proxied connections are dialed
+// here instead: the transport has no Proxy func and its DialContext returns
+// an already-authenticated tunnel. Every target, including plain http URLs,
+// is reached through CONNECT.
+
+// maxProxyAuthLegs bounds the CONNECT exchanges in one handshake. NTLM needs
+// two and Kerberos one; SPNEGO may add a leg.
+const maxProxyAuthLegs = 4
+
+// errSystemAuthUnavailable is returned when the platform security provider
+// (SSPI on Windows, GSSAPI elsewhere) cannot be used.
+var errSystemAuthUnavailable = errors.New("no system security provider (SSPI/GSSAPI) is available")
+
+// authSession is one security context. A new session is started for every
+// proxy connection.
+type authSession interface {
+	// Step returns the token for the next Proxy-Authorization header, given
+	// the proxy's last challenge token (nil on the first leg).
+	Step(challenge []byte) ([]byte, error)
+	Close()
+}
+
+// proxyAuth produces sessions for one auth scheme.
+type proxyAuth struct {
+	scheme     string // "NTLM" or "Negotiate"
+	newSession func(proxyHost string) (authSession, error)
+}
+
+// newProxyAuth selects the authenticator for an "ntlm" or "negotiate" proxy
+// mode:
+//   - ntlm with proxy_user and password: NTLMv2 with those credentials.
+//   - ntlm without proxy_user: the logged-on Windows user via SSPI.
+//   - negotiate: Kerberos via SSPI (Windows) or GSSAPI with the current
+//     ticket cache (Linux/macOS). With proxy_user and password, SSPI uses
+//     those credentials, and where no provider is available NTLM tokens are
+//     sent under the Negotiate scheme.
+func newProxyAuth(cfg *config.Config) (*proxyAuth, error) {
+	user, password := cfg.ProxyUser, cfg.ProxyPassword
+	haveCreds := user != "" && password != ""
+
+	switch strings.ToLower(cfg.ProxyMode) {
+	case "ntlm":
+		if haveCreds {
+			return &proxyAuth{scheme: "NTLM", newSession: func(string) (authSession, error) {
+				return newNTLMSession(user, password)
+			}}, nil
+		}
+		if user != "" {
+			return nil, fmt.Errorf("NTLM proxy user %q has no password", user)
+		}
+		if err := systemAuthAvailable("NTLM"); err != nil {
+			return nil, fmt.Errorf("NTLM proxy mode needs proxy_user and a password on this platform: %w", err)
+		}
+		return &proxyAuth{scheme: "NTLM", newSession: func(host string) (authSession, error) {
+			return newSystemSession("NTLM", host, "", "")
+		}}, nil
+
+	case "negotiate":
+		sysErr := systemAuthAvailable("Negotiate")
+		if sysErr == nil {
+			if !haveCreds {
+				user, password = "", ""
+			}
+			return &proxyAuth{scheme: "Negotiate", newSession: func(host string) (authSession, error) {
+				return newSystemSession("Negotiate", host, user, password)
+			}}, nil
+		}
+		if haveCreds && config.NTLMProxySupported() {
+			return &proxyAuth{scheme: "Negotiate", newSession: func(string) (authSession, error) {
+				return newNTLMSession(user, password)
+			}}, nil
+		}
+		return nil, fmt.Errorf("Negotiate proxy mode is unavailable: %w", sysErr)
+
+	default:
+		return nil, fmt.Errorf("proxy mode %q does not use connection authentication", cfg.ProxyMode)
+	}
+}
+
+// tunnelDialer dials targets through an authenticating proxy. Targets that
+// match the no_proxy list are dialed directly.
+type tunnelDialer struct {
+	proxyAddr string
+	auth      *proxyAuth
+	bypass    func(*url.URL) (*url.URL, error)
+	dial      DialFunc
+}
+
+func newTunnelDialer(proxyURL *url.URL, noProxy string, auth *proxyAuth, dial DialFunc) *tunnelDialer {
+	d := &tunnelDialer{proxyAddr: proxyURL.Host, auth: auth, dial: dial}
+	if noProxy != "" {
+		p := "http://" + proxyURL.Host
+		d.bypass = (&httpproxy.Config{HTTPProxy: p, HTTPSProxy: p, NoProxy: noProxy}).ProxyFunc()
+	}
+	return d
+}
+
+// DialContext returns a connection to addr, tunnelled through the proxy
+// unless addr is bypassed.
+func (d *tunnelDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.bypass != nil {
+		if p, err := d.bypass(&url.URL{Scheme: "https", Host: addr}); err == nil && p == nil {
+			return d.dial(ctx, network, addr)
+		}
+	}
+
+	proxyHost, _, err := net.SplitHostPort(d.proxyAddr)
+	if err != nil {
+		proxyHost = d.proxyAddr
+	}
+	session, err := d.auth.newSession(proxyHost)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s authentication: %w", d.auth.scheme, err)
+	}
+	defer session.Close()
+
+	conn, err := d.dial(ctx, network, d.proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", d.proxyAddr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if err := d.connect(conn, addr, session); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// connect runs the CONNECT handshake on conn, authenticating every leg with
+// session.
+// The first request carries the initial token, which saves the anonymous
+// round trip and keeps NTLM on one connection from the start.
+func (d *tunnelDialer) connect(conn net.Conn, addr string, session authSession) error {
+	br := bufio.NewReader(conn)
+	var challenge []byte
+	for leg := 0; leg < maxProxyAuthLegs; leg++ {
+		token, err := session.Step(challenge)
+		if err != nil {
+			return fmt.Errorf("proxy %s authentication: %w", d.auth.scheme, err)
+		}
+
+		req := &nethttp.Request{
+			Method: nethttp.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: nethttp.Header{},
+		}
+		req.Header.Set("Proxy-Connection", "Keep-Alive")
+		if len(token) > 0 {
+			req.Header.Set("Proxy-Authorization", d.auth.scheme+" "+base64.StdEncoding.EncodeToString(token))
+		}
+		if err := req.Write(conn); err != nil {
+			return fmt.Errorf("proxy CONNECT failed: %w", err)
+		}
+		resp, err := nethttp.ReadResponse(br, req)
+		if err != nil {
+			return fmt.Errorf("proxy CONNECT failed: %w", err)
+		}
+
+		switch resp.StatusCode {
+		case nethttp.StatusOK:
+			resp.Body.Close()
+			return nil
+		case nethttp.StatusProxyAuthRequired:
+			challenge = proxyChallenge(resp.Header, d.auth.scheme)
+			if len(challenge) == 0 || resp.Close {
+				resp.Body.Close()
+				return fmt.Errorf("proxy authentication failed (HTTP 407, %s); proxy offers: %s",
+					d.auth.scheme, offeredSchemes(resp.Header))
+			}
+			// Drain so the next leg reads its own response.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		default:
+			resp.Body.Close()
+			return fmt.Errorf("proxy CONNECT to %s failed: %s", addr, resp.Status)
+		}
+	}
+	return fmt.Errorf("proxy %s authentication did not complete after %d attempts", d.auth.scheme, maxProxyAuthLegs)
+}
+
+// proxyChallenge returns the decoded token of the scheme's Proxy-Authenticate
+// header, or nil if the proxy sent the scheme without a token or not at all.
+func proxyChallenge(h nethttp.Header, scheme string) []byte {
+	for _, v := range h.Values("Proxy-Authenticate") {
+		name, param, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(name, scheme) {
+			continue
+		}
+		token, err := base64.StdEncoding.DecodeString(strings.TrimSpace(param))
+		if err != nil {
+			return nil
+		}
+		return token
+	}
+	return nil
+}
+
+// offeredSchemes lists the schemes in the Proxy-Authenticate headers.
+func offeredSchemes(h nethttp.Header) string {
+	var schemes []string
+	for _, v := range h.Values("Proxy-Authenticate") {
+		name, _, _ := strings.Cut(strings.TrimSpace(v), " ")
+		schemes = append(schemes, name)
+	}
+	if len(schemes) == 0 {
+		return "none"
+	}
+	return strings.Join(schemes, ", ")
+}
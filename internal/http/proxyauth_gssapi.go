@@ -0,0 +1,236 @@
+//go:build (linux || darwin) && cgo
+
+package http
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+// Minimal GSSAPI (RFC 2744) declarations. The library is loaded at run time,
+// so neither its headers nor the library itself are needed to build.
+typedef uint32_t OM_uint32;
+
+// Apple's GSS headers pack these structs to 2 bytes.
+#if defined(__APPLE__)
+#pragma pack(push, 2)
+#endif
+typedef struct { OM_uint32 length; void *elements; } gss_OID_desc;
+typedef struct { size_t length; void *value; } gss_buffer_desc;
+#if defined(__APPLE__)
+#pragma pack(pop)
+#endif
+
+typedef OM_uint32 (*import_name_fn)(OM_uint32 *, gss_buffer_desc *, gss_OID_desc *, void **);
+typedef OM_uint32 (*release_name_fn)(OM_uint32 *, void **);
+typedef OM_uint32 (*init_sec_context_fn)(OM_uint32 *, void *, void **, void *, gss_OID_desc *,
+	OM_uint32, OM_uint32, void *, gss_buffer_desc *, gss_OID_desc **, gss_buffer_desc *,
+	OM_uint32 *, OM_uint32 *);
+typedef OM_uint32 (*delete_sec_context_fn)(OM_uint32 *, void **, gss_buffer_desc *);
+typedef OM_uint32 (*release_buffer_fn)(OM_uint32 *, gss_buffer_desc *);
+typedef OM_uint32 (*display_status_fn)(OM_uint32 *, OM_uint32, int, gss_OID_desc *, OM_uint32 *, gss_buffer_desc *);
+
+static struct {
+	import_name_fn import_name;
+	release_name_fn release_name;
+	init_sec_context_fn init_sec_context;
+	delete_sec_context_fn delete_sec_context;
+	release_buffer_fn release_buffer;
+	display_status_fn display_status;
+} gss;
+
+// 1.2.840.113554.1.2.1.4 (GSS_C_NT_HOSTBASED_SERVICE)
+static gss_OID_desc nt_hostbased_service = {10, "\x2a\x86\x48\x86\xf7\x12\x01\x02\x01\x04"};
+// 1.3.6.1.5.5.2 (SPNEGO)
+static gss_OID_desc mech_spnego = {6, "\x2b\x06\x01\x05\x05\x02"};
+
+static int gss_load(const char *path) {
+	void *h = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (!h) return 0;
+	gss.import_name = (import_name_fn)dlsym(h, "gss_import_name");
+	gss.release_name = (release_name_fn)dlsym(h, "gss_release_name");
+	gss.init_sec_context = (init_sec_context_fn)dlsym(h, "gss_init_sec_context");
+	gss.delete_sec_context = (delete_sec_context_fn)dlsym(h, "gss_delete_sec_context");
+	gss.release_buffer = (release_buffer_fn)dlsym(h, "gss_release_buffer");
+	gss.display_status = (display_status_fn)dlsym(h, "gss_display_status");
+	if (!gss.import_name || !gss.release_name || !gss.init_sec_context ||
+	    !gss.delete_sec_context || !gss.release_buffer || !gss.display_status) {
+		dlclose(h);
+		return 0;
+	}
+	return 1;
+}
+
+static OM_uint32 gss_import_service(OM_uint32 *minor, char *service, size_t len, void **name) {
+	gss_buffer_desc buf = {len, service};
+	return gss.import_name(minor, &buf, &nt_hostbased_service, name);
+}
+
+static OM_uint32 gss_init(OM_uint32 *minor, void **ctx, void *name, void *in, size_t in_len, gss_buffer_desc *out) {
+	gss_buffer_desc input = {in_len, in};
+	return gss.init_sec_context(minor, NULL, ctx, name, &mech_spnego, 0, 0, NULL,
+		in_len ? &input : NULL, NULL, out, NULL, NULL);
+}
+
+static void gss_release(gss_buffer_desc *buf) {
+	OM_uint32 minor;
+	gss.release_buffer(&minor, buf);
+}
+
+static void gss_free_name(void **name) {
+	OM_uint32 minor;
+	gss.release_name(&minor, name);
+}
+
+static void gss_delete(void **ctx) {
+	OM_uint32 minor;
+	gss.delete_sec_context(&minor, ctx, NULL);
+}
+
+static OM_uint32 gss_status_text(OM_uint32 code, int type, OM_uint32 *msg_ctx, gss_buffer_desc *out) {
+	OM_uint32 minor;
+	return gss.display_status(&minor, code, type, NULL, msg_ctx, out);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// GSSAPI (Kerberos via SPNEGO) proxy authentication using the current
+// credential cache, e.g. from kinit or a domain login.
+
+// gssLibraries are tried in order; the first that loads is used.
+var gssLibraries = map[string][]string{
+	"linux":  {"libgssapi_krb5.so.2", "libgssapi.so.3"},
+	"darwin": {"/System/Library/Frameworks/GSS.framework/GSS", "/usr/lib/libgssapi_krb5.dylib"},
+}
+
+var (
+	gssOnce   sync.Once
+	gssLoaded bool
+)
+
+const (
+	gssSComplete     = 0
+	gssCGSSCode      = 1
+	gssCMechCode     = 2
+	gssCallingErrors = 0xffff0000
+)
+
+func loadGSSAPI() bool {
+	gssOnce.Do(func() {
+		for _, lib := range gssLibraries[runtime.GOOS] {
+			path := C.CString(lib)
+			ok := C.gss_load(path) == 1
+			C.free(unsafe.Pointer(path))
+			if ok {
+				gssLoaded = true
+				return
+			}
+		}
+	})
+	return gssLoaded
+}
+
+func systemAuthAvailable(pkg string) error {
+	if pkg != "Negotiate" {
+		return fmt.Errorf("%w: only Negotiate is supported through GSSAPI", errSystemAuthUnavailable)
+	}
+	if !loadGSSAPI() {
+		return fmt.Errorf("%w: GSSAPI library not found (install the Kerberos client libraries)", errSystemAuthUnavailable)
+	}
+	return nil
+}
+
+// gssSession is one GSSAPI security context.
+type gssSession struct {
+	name unsafe.Pointer
+	ctx  unsafe.Pointer
+	done bool
+}
+
+// newSystemSession imports the proxy's HTTP service name. GSSAPI uses the
+// credential cache, so user and password are ignored.
+func newSystemSession(pkg, proxyHost, _, _ string) (authSession, error) {
+	if err := systemAuthAvailable(pkg); err != nil {
+		return nil, err
+	}
+	service := "HTTP@" + proxyHost
+	cs := C.CString(service)
+	defer C.free(unsafe.Pointer(cs))
+
+	s := &gssSession{}
+	var minor C.OM_uint32
+	major := C.gss_import_service(&minor, cs, C.size_t(len(service)), &s.name)
+	if major != gssSComplete {
+		return nil, fmt.Errorf("gss_import_name(%s): %s", service, gssStatus(major, minor))
+	}
+	return s, nil
+}
+
+func (s *gssSession) Step(challenge []byte) ([]byte, error) {
+	if s.done {
+		return nil, fmt.Errorf("Kerberos ticket rejected by proxy")
+	}
+
+	var in unsafe.Pointer
+	if len(challenge) > 0 {
+		in = C.CBytes(challenge)
+		defer C.free(in)
+	}
+	var out C.gss_buffer_desc
+	var minor C.OM_uint32
+	major := C.gss_init(&minor, &s.ctx, s.name, in, C.size_t(len(challenge)), &out)
+	defer C.gss_release(&out)
+	if major&gssCallingErrors != 0 {
+		return nil, fmt.Errorf("gss_init_sec_context: %s", gssStatus(major, minor))
+	}
+	s.done = major == gssSComplete
+	return C.GoBytes(out.value, C.int(out.length)), nil
+}
+
+func (s *gssSession) Close() {
+	if s.ctx != nil {
+		C.gss_delete(&s.ctx)
+	}
+	if s.name != nil {
+		C.gss_free_name(&s.name)
+	}
+}
+
+// gssStatus renders major and minor status codes as text, e.g. "No
+// Kerberos credentials available".
+func gssStatus(major, minor C.OM_uint32) string {
+	var msgs []string
+	for _, st := range []struct {
+		code C.OM_uint32
+		kind C.int
+	}{{major, gssCGSSCode}, {minor, gssCMechCode}} {
+		if st.code == 0 {
+			continue
+		}
+		var msgCtx C.OM_uint32
+		for {
+			var buf C.gss_buffer_desc
+			if C.gss_status_text(st.code, st.kind, &msgCtx, &buf) != gssSComplete {
+				break
+			}
+			msgs = append(msgs, C.GoStringN((*C.char)(buf.value), C.int(buf.length)))
+			C.gss_release(&buf)
+			if msgCtx == 0 {
+				break
+			}
+		}
+	}
+	if len(msgs) == 0 {
+		return fmt.Sprintf("major 0x%x, minor 0x%x", uint32(major), uint32(minor))
+	}
+	return strings.Join(msgs, ": ")
+}
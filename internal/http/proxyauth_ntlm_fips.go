@@ -0,0 +1,9 @@
+//go:build fips || fips3
+
+package http
+
+import "fmt"
+
+func newNTLMSession(_, _ string) (authSession, error) {
+	return nil, fmt.Errorf("NTLM proxy authentication is disabled in FIPS builds because it requires non-FIPS MD4/MD5 algorithms")
+}
@@ -0,0 +1,37 @@
+//go:build !fips && !fips3
+
+package http
+
+import (
+	"errors"
+	"os"
+
+	ntlmssp "github.com/Azure/go-ntlmssp"
+)
+
+// ntlmSession is an NTLMv2 handshake with explicit credentials. The user
+// may be "DOMAIN\user" or "user@domain".
+type ntlmSession struct {
+	user, password string
+	legs           int
+}
+
+func newNTLMSession(user, password string) (authSession, error) {
+	return &ntlmSession{user: user, password: password}, nil
+}
+
+func (s *ntlmSession) Step(challenge []byte) ([]byte, error) {
+	s.legs++
+	switch s.legs {
+	case 1:
+		return ntlmssp.NewNegotiateMessage("", "")
+	case 2:
+		workstation, _ := os.Hostname()
+		return ntlmssp.NewAuthenticateMessage(challenge, s.user, s.password,
+			&ntlmssp.AuthenticateMessageOptions{WorkstationName: workstation})
+	default:
+		return nil, errors.New("credentials rejected by proxy")
+	}
+}
+
+func (s *ntlmSession) Close() {}
@@ -0,0 +1,94 @@
+//go:build !fips && !fips3
+
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// ntlmChallenge builds a minimal NTLM CHALLENGE (type 2) message.
+func ntlmChallenge() []byte {
+	var b bytes.Buffer
+	b.WriteString("NTLMSSP\x00")
+	binary.Write(&b, binary.LittleEndian, uint32(2))
+	b.Write(make([]byte, 8))                                 // TargetName
+	binary.Write(&b, binary.LittleEndian, uint32(0x1|0x200)) // UNICODE | NTLM
+	b.WriteString("12345678")                                // ServerChallenge
+	b.Write(make([]byte, 8+8))                               // Reserved, TargetInfo
+	return b.Bytes()
+}
+
+// ntlmType decodes an "NTLM <base64>" header and returns the message type.
+func ntlmType(header string) uint32 {
+	token, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "NTLM "))
+	if err != nil || len(token) < 12 || !bytes.HasPrefix(token, []byte("NTLMSSP\x00")) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(token[8:12])
+}
+
+func TestConfigureHTTPClientNTLMProxy(t *testing.T) {
+	target := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer target.Close()
+
+	proxyAddr, _ := startAuthProxy(t, func(leg int, auth string) (int, []string) {
+		switch {
+		case leg == 0 && ntlmType(auth) == 1:
+			return 407, []string{"NTLM " + base64.StdEncoding.EncodeToString(ntlmChallenge())}
+		case leg == 1 && ntlmType(auth) == 3:
+			return 200, nil
+		}
+		return 407, []string{"NTLM"}
+	})
+	host, port, _ := net.SplitHostPort(proxyAddr)
+	portNum, _ := strconv.Atoi(port)
+
+	client, err := ConfigureHTTPClient(&config.Config{
+		ProxyMode:     "ntlm",
+		ProxyHost:     host,
+		ProxyPort:     portNum,
+		ProxyUser:     `CORP\alice`,
+		ProxyPassword: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(target.URL)
+	if err != nil {
+		t.Fatalf("GET through NTLM proxy: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", body)
+	}
+}
+
+func TestConfigureHTTPClientNTLMMissingPassword(t *testing.T) {
+	client, err := ConfigureHTTPClient(&config.Config{
+		ProxyMode: "ntlm",
+		ProxyHost: "127.0.0.1",
+		ProxyPort: 1,
+		ProxyUser: `CORP\alice`,
+	})
+	if err != nil {
+		t.Fatalf("client should still be created without a password: %v", err)
+	}
+	_, err = client.Get("https://platform.rescale.com/")
+	if err == nil || !strings.Contains(err.Error(), "has no password") {
+		t.Fatalf("err = %v, want missing password", err)
+	}
+}
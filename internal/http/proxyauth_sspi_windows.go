@@ -0,0 +1,216 @@
+//go:build windows
+
+package http
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// SSPI (secur32.dll) proxy authentication. With no explicit credentials the
+// logged-on user's are used, so domain-joined machines get single sign-on.
+
+var (
+	secur32                        = windows.NewLazySystemDLL("secur32.dll")
+	procQuerySecurityPackageInfoW  = secur32.NewProc("QuerySecurityPackageInfoW")
+	procAcquireCredentialsHandleW  = secur32.NewProc("AcquireCredentialsHandleW")
+	procInitializeSecurityContextW = secur32.NewProc("InitializeSecurityContextW")
+	procCompleteAuthToken          = secur32.NewProc("CompleteAuthToken")
+	procDeleteSecurityContext      = secur32.NewProc("DeleteSecurityContext")
+	procFreeCredentialsHandle      = secur32.NewProc("FreeCredentialsHandle")
+	procFreeContextBuffer          = secur32.NewProc("FreeContextBuffer")
+)
+
+const (
+	secpkgCredOutbound          = 2
+	securityNativeDrep          = 0x10
+	secbufferVersion            = 0
+	secbufferToken              = 2
+	secWinntAuthIdentityUnicode = 2
+
+	iscReqAllocateMemory = 0x100
+	iscReqConnection     = 0x800
+
+	secEOK                  = 0
+	secIContinueNeeded      = 0x00090312
+	secICompleteNeeded      = 0x00090313
+	secICompleteAndContinue = 0x00090314
+)
+
+// secHandle mirrors CredHandle / CtxtHandle.
+type secHandle struct {
+	lower, upper uintptr
+}
+
+// secBuffer mirrors SecBuffer.
+type secBuffer struct {
+	size       uint32
+	bufferType uint32
+	buffer     *byte
+}
+
+// secBufferDesc mirrors SecBufferDesc.
+type secBufferDesc struct {
+	version uint32
+	count   uint32
+	buffers *secBuffer
+}
+
+// secWinntAuthIdentity mirrors SEC_WINNT_AUTH_IDENTITY_W.
+type secWinntAuthIdentity struct {
+	user           *uint16
+	userLength     uint32
+	domain         *uint16
+	domainLength   uint32
+	password       *uint16
+	passwordLength uint32
+	flags          uint32
+}
+
+func systemAuthAvailable(pkg string) error {
+	if err := procQuerySecurityPackageInfoW.Find(); err != nil {
+		return fmt.Errorf("%w: %v", errSystemAuthUnavailable, err)
+	}
+	name, err := windows.UTF16PtrFromString(pkg)
+	if err != nil {
+		return err
+	}
+	var info uintptr
+	if ret, _, _ := procQuerySecurityPackageInfoW.Call(uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(&info))); ret != secEOK {
+		return fmt.Errorf("%w: SSPI package %s not found (0x%x)", errSystemAuthUnavailable, pkg, uint32(ret))
+	}
+	procFreeContextBuffer.Call(info)
+	return nil
+}
+
+// sspiSession is one SSPI client security context.
+type sspiSession struct {
+	cred    secHandle
+	ctx     secHandle
+	haveCtx bool
+	target  *uint16
+	done    bool
+}
+
+// newSystemSession acquires outbound credentials for pkg ("NTLM" or
+// "Negotiate"). user may be "DOMAIN\user" or "user@domain"; empty uses the
+// logged-on user.
+func newSystemSession(pkg, proxyHost, user, password string) (authSession, error) {
+	pkgName, err := windows.UTF16PtrFromString(pkg)
+	if err != nil {
+		return nil, err
+	}
+	target, err := windows.UTF16PtrFromString("HTTP/" + proxyHost)
+	if err != nil {
+		return nil, err
+	}
+
+	var id *secWinntAuthIdentity
+	if user != "" {
+		domain := ""
+		if d, u, ok := strings.Cut(user, `\`); ok {
+			domain, user = d, u
+		}
+		id = &secWinntAuthIdentity{flags: secWinntAuthIdentityUnicode}
+		id.user, id.userLength = utf16Field(user)
+		id.domain, id.domainLength = utf16Field(domain)
+		id.password, id.passwordLength = utf16Field(password)
+	}
+
+	s := &sspiSession{target: target}
+	var expiry int64
+	ret, _, _ := procAcquireCredentialsHandleW.Call(
+		0,
+		uintptr(unsafe.Pointer(pkgName)),
+		secpkgCredOutbound,
+		0,
+		uintptr(unsafe.Pointer(id)),
+		0, 0,
+		uintptr(unsafe.Pointer(&s.cred)),
+		uintptr(unsafe.Pointer(&expiry)),
+	)
+	if ret != secEOK {
+		return nil, fmt.Errorf("AcquireCredentialsHandle(%s) failed: 0x%x", pkg, uint32(ret))
+	}
+	return s, nil
+}
+
+// utf16Field returns a UTF-16 pointer and its length in characters.
+func utf16Field(s string) (*uint16, uint32) {
+	if s == "" {
+		return nil, 0
+	}
+	u, err := windows.UTF16FromString(s)
+	if err != nil {
+		return nil, 0
+	}
+	return &u[0], uint32(len(u) - 1)
+}
+
+func (s *sspiSession) Step(challenge []byte) ([]byte, error) {
+	if s.done {
+		return nil, fmt.Errorf("credentials rejected by proxy")
+	}
+
+	out := secBuffer{bufferType: secbufferToken}
+	outDesc := secBufferDesc{version: secbufferVersion, count: 1, buffers: &out}
+
+	var inDesc *secBufferDesc
+	if len(challenge) > 0 {
+		in := secBuffer{size: uint32(len(challenge)), bufferType: secbufferToken, buffer: &challenge[0]}
+		inDesc = &secBufferDesc{version: secbufferVersion, count: 1, buffers: &in}
+	}
+	var ctxIn *secHandle
+	if s.haveCtx {
+		ctxIn = &s.ctx
+	}
+
+	var attrs uint32
+	var expiry int64
+	ret, _, _ := procInitializeSecurityContextW.Call(
+		uintptr(unsafe.Pointer(&s.cred)),
+		uintptr(unsafe.Pointer(ctxIn)),
+		uintptr(unsafe.Pointer(s.target)),
+		iscReqAllocateMemory|iscReqConnection,
+		0,
+		securityNativeDrep,
+		uintptr(unsafe.Pointer(inDesc)),
+		0,
+		uintptr(unsafe.Pointer(&s.ctx)),
+		uintptr(unsafe.Pointer(&outDesc)),
+		uintptr(unsafe.Pointer(&attrs)),
+		uintptr(unsafe.Pointer(&expiry)),
+	)
+	status := uint32(ret)
+	switch status {
+	case secEOK, secIContinueNeeded, secICompleteNeeded, secICompleteAndContinue:
+	default:
+		return nil, fmt.Errorf("InitializeSecurityContext failed: 0x%x", status)
+	}
+	s.haveCtx = true
+
+	if status == secICompleteNeeded || status == secICompleteAndContinue {
+		if ret, _, _ := procCompleteAuthToken.Call(uintptr(unsafe.Pointer(&s.ctx)), uintptr(unsafe.Pointer(&outDesc))); ret != secEOK {
+			return nil, fmt.Errorf("CompleteAuthToken failed: 0x%x", uint32(ret))
+		}
+	}
+	s.done = status == secEOK || status == secICompleteNeeded
+
+	var token []byte
+	if out.buffer != nil {
+		token = append([]byte(nil), unsafe.Slice(out.buffer, out.size)...)
+		procFreeContextBuffer.Call(uintptr(unsafe.Pointer(out.buffer)))
+	}
+	return token, nil
+}
+
+func (s *sspiSession) Close() {
+	if s.haveCtx {
+		procDeleteSecurityContext.Call(uintptr(unsafe.Pointer(&s.ctx)))
+		s.haveCtx = false
+	}
+	procFreeCredentialsHandle.Call(uintptr(unsafe.Pointer(&s.cred)))
+}
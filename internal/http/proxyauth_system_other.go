@@ -0,0 +1,13 @@
+//go:build !windows && !((linux || darwin) && cgo)
+
+package http
+
+import "fmt"
+
+func systemAuthAvailable(_ string) error {
+	return fmt.Errorf("%w in this build", errSystemAuthUnavailable)
+}
+
+func newSystemSession(_, _, _, _ string) (authSession, error) {
+	return nil, fmt.Errorf("%w in this build", errSystemAuthUnavailable)
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startAuthProxy runs a CONNECT proxy that asks respond about each leg's
+// Proxy-Authorization header. A 200 opens the tunnel; anything else is sent
+// as a 407 with the returned Proxy-Authenticate values. It returns the proxy
+// address and a counter of accepted connections.
+func startAuthProxy(t *testing.T, respond func(leg int, auth string) (int, []string)) (string, *int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var conns int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&conns, 1)
+			go func() {
+				defer conn.Close()
+				br := bufio.NewReader(conn)
+				for leg := 0; ; leg++ {
+					req, err := nethttp.ReadRequest(br)
+					if err != nil {
+						return
+					}
+					status, challenges := respond(leg, req.Header.Get("Proxy-Authorization"))
+					if status != nethttp.StatusOK {
+						fmt.Fprintf(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n")
+						for _, c := range challenges {
+							fmt.Fprintf(conn, "Proxy-Authenticate: %s\r\n", c)
+						}
+						fmt.Fprintf(conn, "Content-Length: 0\r\n\r\n")
+						continue
+					}
+					target, err := net.Dial("tcp", req.Host)
+					if err != nil {
+						fmt.Fprintf(conn, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\n\r\n")
+						return
+					}
+					defer target.Close()
+					fmt.Fprintf(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+					go io.Copy(target, br)
+					io.Copy(conn, target)
+					return
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), &conns
+}
+
+// scriptedSession returns "tok1", then "tok2:<challenge>".
+type scriptedSession struct{ legs int }
+
+func (s *scriptedSession) Step(challenge []byte) ([]byte, error) {
+	s.legs++
+	if s.legs == 1 {
+		return []byte("tok1"), nil
+	}
+	return []byte("tok2:" + string(challenge)), nil
+}
+
+func (s *scriptedSession) Close() {}
+
+func b64(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+func TestTunnelDialerMultiLegHandshake(t *testing.T) {
+	target := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer target.Close()
+
+	proxyAddr, conns := startAuthProxy(t, func(leg int, auth string) (int, []string) {
+		switch {
+		case leg == 0 && auth == "Test "+b64("tok1"):
+			return 407, []string{"Basic realm=\"corp\"", "Test " + b64("chal")}
+		case leg == 1 && auth == "Test "+b64("tok2:chal"):
+			return 200, nil
+		}
+		return 407, nil
+	})
+
+	auth := &proxyAuth{scheme: "Test", newSession: func(string) (authSession, error) {
+		return &scriptedSession{}, nil
+	}}
+	dialer := newTunnelDialer(&url.URL{Host: proxyAddr}, "", auth, (&net.Dialer{}).DialContext)
+	client := &nethttp.Client{Transport: &nethttp.Transport{DialContext: dialer.DialContext}, Timeout: 5 * time.Second}
+
+	resp, err := client.Get(target.URL)
+	if err != nil {
+		t.Fatalf("GET through proxy: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", body)
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("proxy connections = %d, want 1 (handshake must stay on one connection)", n)
+	}
+}
+
+func TestTunnelDialerAuthRejected(t *testing.T) {
+	proxyAddr, _ := startAuthProxy(t, func(int, string) (int, []string) {
+		return 407, []string{"Negotiate", "Basic realm=\"corp\""}
+	})
+	auth := &proxyAuth{scheme: "NTLM", newSession: func(string) (authSession, error) {
+		return &scriptedSession{}, nil
+	}}
+	dialer := newTunnelDialer(&url.URL{Host: proxyAddr}, "", auth, (&net.Dialer{}).DialContext)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := dialer.DialContext(ctx, "tcp", "platform.rescale.com:443")
+	if err == nil || !strings.Contains(err.Error(), "proxy offers: Negotiate, Basic") {
+		t.Fatalf("err = %v, want 407 listing the offered schemes", err)
+	}
+}
+
+func TestTunnelDialerBypass(t *testing.T) {
+	var dialed []string
+	dial := func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		c, s := net.Pipe()
+		s.Close()
+		return c, nil
+	}
+	auth := &proxyAuth{scheme: "NTLM", newSession: func(string) (authSession, error) {
+		t.Fatal("bypassed host must not authenticate")
+		return nil, nil
+	}}
+	dialer := newTunnelDialer(&url.URL{Host: "proxy.corp:8080"}, "*.internal, 10.0.0.0/8", auth, dial)
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "files.internal:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if len(dialed) != 1 || dialed[0] != "files.internal:443" {
+		t.Errorf("dialed %v, want a direct dial to files.internal:443", dialed)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"net"
 	"strings"
 	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 )
 
 // ErrorType represents different classes of errors for retry strategy
@@ -53,6 +56,12 @@ func ClassifyError(err error) ErrorType {
 	if errors.Is(err, context.Canceled) {
 		return ErrorTypeFatal
 	}
+	// Errors that carry their own classification (such as an upload part that
+	// already used up its retry budget) keep it however they are wrapped.
+	var classified interface{ ErrorType() ErrorType }
+	if errors.As(err, &classified) {
+		return classified.ErrorType()
+	}
 	// Timeouts ARE retryable
 	if errors.Is(err, context.DeadlineExceeded) {
 		return ErrorTypeNetwork
@@ -113,7 +122,8 @@ func ClassifyError(err error) ErrorType {
 		strings.Contains(errStr, "no such host") ||
 		strings.Contains(errStr, "temporary failure in name resolution") ||
 		strings.Contains(errStr, "server misbehaving") ||
-		strings.Contains(errStr, "nodename nor servname provided") {
+		strings.Contains(errStr, "nodename nor servname provided") ||
+		IsConnectionLost(err) {
 		return ErrorTypeNetwork
 	}
 
@@ -148,6 +158,28 @@ func ClassifyError(err error) ErrorType {
 	return ErrorTypeFatal
 }
 
+// IsConnectionLost reports errors that mean the connection (or the route
+// under it) went away mid-request, as happens when the machine switches
+// networks (Wi-Fi → VPN, dock/undock). Messages cover Linux, macOS and
+// Windows socket errors.
+func IsConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "connection reset") ||
+		strings.Contains(errStr, "broken pipe") ||
+		strings.Contains(errStr, "use of closed network connection") ||
+		strings.Contains(errStr, "network is unreachable") ||
+		strings.Contains(errStr, "network is down") ||
+		strings.Contains(errStr, "no route to host") ||
+		strings.Contains(errStr, "host is down") ||
+		strings.Contains(errStr, "can't assign requested address") ||
+		strings.Contains(errStr, "cannot assign requested address") ||
+		strings.Contains(errStr, "connection was aborted") ||
+		strings.Contains(errStr, "forcibly closed")
+}
+
 // CalculateBackoff returns exponential backoff duration with full jitter
 // Full jitter prevents thundering herd problem when many clients retry simultaneously
 //
@@ -176,7 +208,8 @@ func CalculateBackoff(attempt int, initialDelay, maxDelay time.Duration) time.Du
 //   - Credential errors: Refresh credentials and retry immediately
 //   - Network/Retryable errors: Exponential backoff with full jitter
 //   - Fatal errors: Return immediately without retry
-//   - Context cancellation: Return immediately
+//   - Context cancellation: Return immediately, including when the operation
+//     fails because ctx was cancelled while it was in flight
 //
 // The function will make up to config.MaxRetries attempts. If all attempts fail,
 // it returns an error wrapping the last failure.
@@ -208,6 +241,17 @@ func ExecuteWithRetry(ctx context.Context, config Config, operation func() error
 
 		lastErr = err
 
+		// Failed because ctx was cancelled mid-request. The error often reads
+		// like a network failure ("use of closed network connection"), and
+		// classifying it as one would close idle connections that other
+		// transfers share, so return before classifying.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if errors.Is(err, ctxErr) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ctxErr, err)
+		}
+
 		// Classify the error to determine retry strategy
 		errType := ClassifyError(err)
 
@@ -236,6 +280,11 @@ func ExecuteWithRetry(ctx context.Context, config Config, operation func() error
 			return fmt.Errorf("credential error after %d attempts: %w", config.MaxRetries, err)
 
 		case ErrorTypeNetwork, ErrorTypeRetryable:
+			// A lost connection usually means the route changed; drop pooled
+			// connections from the same route so the retry re-dials.
+			if IsConnectionLost(err) {
+				CloseAllIdleConnections()
+			}
 			// Network or server errors - use exponential backoff
 			if attempt < config.MaxRetries-1 {
 				backoff := CalculateBackoff(attempt, config.InitialDelay, config.MaxDelay)
@@ -260,6 +309,15 @@ func ExecuteWithRetry(ctx context.Context, config Config, operation func() error
 	return fmt.Errorf("operation failed after %d attempts: %w", config.MaxRetries, lastErr)
 }
 
+// PartRetries returns the attempt budget for a single upload part:
+// cfg.PartRetries (part_retries), or constants.MaxRetries when unset.
+func PartRetries(cfg *config.Config) int {
+	if cfg == nil || cfg.PartRetries <= 0 {
+		return constants.MaxRetries
+	}
+	return cfg.PartRetries
+}
+
 // ErrorTypeName returns a human-readable name for an ErrorType
 func ErrorTypeName(errType ErrorType) string {
 	switch errType {
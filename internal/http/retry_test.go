@@ -2,10 +2,15 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 )
 
 // TestExecuteWithRetry_Success verifies basic success case returns nil on first attempt.
@@ -92,6 +97,37 @@ func TestExecuteWithRetry_ContextCancelledDuringSleep(t *testing.T) {
 	}
 }
 
+// TestExecuteWithRetry_CancelledMidOperation verifies that an operation
+// failing because ctx was cancelled in flight is not retried or treated as
+// a lost connection.
+func TestExecuteWithRetry_CancelledMidOperation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	retried := false
+	cfg := Config{
+		MaxRetries:   5,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		OnRetry:      func(int, error, ErrorType) { retried = true },
+	}
+
+	calls := 0
+	err := ExecuteWithRetry(ctx, cfg, func() error {
+		calls++
+		cancel()
+		return fmt.Errorf("write tcp: use of closed network connection")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "use of closed network connection") {
+		t.Errorf("expected operation error to be kept, got %v", err)
+	}
+	if calls != 1 || retried {
+		t.Errorf("expected 1 call and no retry, got %d calls (retried=%v)", calls, retried)
+	}
+}
+
 // TestClassifyError verifies all error classification paths including DNS errors.
 func TestClassifyError(t *testing.T) {
 	tests := []struct {
@@ -119,6 +155,13 @@ func TestClassifyError(t *testing.T) {
 		{"context deadline", context.DeadlineExceeded, ErrorTypeNetwork},
 		{"net.Error timeout", &net.OpError{Err: &timeoutErr{}}, ErrorTypeNetwork},
 
+		// Network change (route lost or switched mid-transfer)
+		{"network unreachable", fmt.Errorf("dial tcp 52.1.2.3:443: connect: network is unreachable"), ErrorTypeNetwork},
+		{"no route to host", fmt.Errorf("read tcp: no route to host"), ErrorTypeNetwork},
+		{"cannot assign address", fmt.Errorf("dial tcp 10.0.0.5:0->52.1.2.3:443: bind: can't assign requested address"), ErrorTypeNetwork},
+		{"windows aborted", fmt.Errorf("wsarecv: An established connection was aborted by the software in your host machine."), ErrorTypeNetwork},
+		{"windows forcibly closed", fmt.Errorf("wsarecv: An existing connection was forcibly closed by the remote host."), ErrorTypeNetwork},
+
 		// Credential errors
 		{"403 forbidden", fmt.Errorf("403 forbidden"), ErrorTypeCredential},
 		{"expired token", fmt.Errorf("token expired"), ErrorTypeCredential},
@@ -244,3 +287,15 @@ func TestExecuteWithRetry_InsufficientDeadline(t *testing.T) {
 		t.Errorf("expected at least 1 call, got %d", calls)
 	}
 }
+
+func TestPartRetries(t *testing.T) {
+	if got := PartRetries(nil); got != constants.MaxRetries {
+		t.Errorf("PartRetries(nil) = %d, want %d", got, constants.MaxRetries)
+	}
+	if got := PartRetries(&config.Config{}); got != constants.MaxRetries {
+		t.Errorf("PartRetries(unset) = %d, want %d", got, constants.MaxRetries)
+	}
+	if got := PartRetries(&config.Config{PartRetries: 3}); got != 3 {
+		t.Errorf("PartRetries(3) = %d", got)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package journal
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// followDirLinks reports whether localfs.WalkCollect follows symlinked
+// directories on this platform.
+const followDirLinks = true
+
+// dirID returns the inode of the directory at path.
+func dirID(_ string, info fs.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+	return 0
+}
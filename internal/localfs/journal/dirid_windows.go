@@ -0,0 +1,40 @@
+//go:build windows
+
+package journal
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/windows"
+)
+
+// followDirLinks reports whether localfs.WalkCollect follows symlinked
+// directories on this platform.
+const followDirLinks = false
+
+// dirID returns the file reference number of the directory at path, which
+// is what USN records name parent directories by.
+func dirID(path string, _ fs.FileInfo) uint64 {
+	h, err := openDir(path)
+	if err != nil {
+		return 0
+	}
+	defer windows.CloseHandle(h)
+	var info windows.ByHandleFileInformation
+	if err := windows.GetFileInformationByHandle(h, &info); err != nil {
+		return 0
+	}
+	return uint64(info.FileIndexHigh)<<32 | uint64(info.FileIndexLow)
+}
+
+// openDir opens a directory handle for queries; it needs no access rights
+// beyond listing.
+func openDir(path string) (windows.Handle, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	return windows.CreateFile(p, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+}
@@ -0,0 +1,99 @@
+//go:build linux
+
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	recorderSupported = true
+	recorderName      = "fanotify"
+)
+
+// watcher reports writes on the mounts holding the recorded roots.
+// fanotify in notification mode needs CAP_SYS_ADMIN. It does not report
+// creations, deletions or renames; those change the modification time of
+// the parent directory, which every scan compares anyway.
+type watcher struct {
+	fd     int
+	f      *os.File
+	events chan watchEvent
+	done   chan struct{}
+}
+
+func newWatcher() (*watcher, error) {
+	fd, err := unix.FanotifyInit(unix.FAN_CLASS_NOTIF|unix.FAN_CLOEXEC|unix.FAN_NONBLOCK,
+		unix.O_RDONLY|unix.O_LARGEFILE|unix.O_CLOEXEC)
+	if errors.Is(err, unix.EPERM) {
+		return nil, fmt.Errorf("%w: fanotify needs CAP_SYS_ADMIN (run the daemon as root)", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: fanotify: %v", ErrUnsupported, err)
+	}
+	// The descriptor is non-blocking, so reads go through the runtime
+	// poller and Close interrupts them.
+	w := &watcher{fd: fd, f: os.NewFile(uintptr(fd), "fanotify"), events: make(chan watchEvent, 1024), done: make(chan struct{})}
+	go w.read()
+	return w, nil
+}
+
+// Add watches the mount holding root.
+func (w *watcher) Add(root string) error {
+	return unix.FanotifyMark(w.fd, unix.FAN_MARK_ADD|unix.FAN_MARK_MOUNT,
+		unix.FAN_MODIFY|unix.FAN_CLOSE_WRITE, unix.AT_FDCWD, root)
+}
+
+func (w *watcher) Events() <-chan watchEvent { return w.events }
+
+func (w *watcher) Close() error {
+	close(w.done)
+	return w.f.Close()
+}
+
+// send delivers ev unless the watcher was closed.
+func (w *watcher) send(ev watchEvent) bool {
+	select {
+	case w.events <- ev:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *watcher) read() {
+	defer close(w.events)
+	const metaLen = int(unsafe.Sizeof(unix.FanotifyEventMetadata{}))
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := w.f.Read(buf)
+		if err != nil {
+			return
+		}
+		for off := 0; off+metaLen <= n; {
+			meta := (*unix.FanotifyEventMetadata)(unsafe.Pointer(&buf[off]))
+			if int(meta.Event_len) < metaLen {
+				break
+			}
+			off += int(meta.Event_len)
+
+			if meta.Mask&unix.FAN_Q_OVERFLOW != 0 && !w.send(watchEvent{overflow: true}) {
+				return
+			}
+			if meta.Fd < 0 {
+				continue
+			}
+			path, err := os.Readlink("/proc/self/fd/" + strconv.Itoa(int(meta.Fd)))
+			unix.Close(int(meta.Fd))
+			if err == nil && !w.send(watchEvent{path: path}) {
+				return
+			}
+		}
+	}
+}
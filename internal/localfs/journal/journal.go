@@ -0,0 +1,235 @@
+// Package journal speeds up repeated scans of large local trees.
+//
+// A root registered with a Store keeps a snapshot of its directories and
+// files. The next Scan asks the platform change journal which directories
+// changed since the snapshot and re-reads only those, instead of walking the
+// whole tree:
+//
+//   - Windows: the NTFS/ReFS USN journal, read without admin rights
+//     (Windows 10 1709 and later).
+//   - Linux: a fanotify recorder run by the daemon (`rescale-int daemon`),
+//     which needs CAP_SYS_ADMIN.
+//   - Other platforms (including macOS): no journal; every scan is a full
+//     walk.
+//
+// A scan falls back to a full walk whenever the journal can't vouch for the
+// whole interval since the snapshot: the journal was reset or wrapped, the
+// recorder was not running, the scan options changed, or the tree contains
+// symlinked directories (whose targets the journal doesn't cover). The
+// result is therefore always the same as localfs.WalkCollect.
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported means no change journal is available for a root on this
+// system, so its scans are full walks.
+var ErrUnsupported = errors.New("change journal not available")
+
+// Store keeps the registered roots and their snapshots in one directory
+// (config.GetJournalDir).
+type Store struct {
+	dir string
+	mu  sync.Mutex
+
+	// openSource is replaced in tests.
+	openSource func(s *Store, root string) (source, error)
+}
+
+// NewStore returns the store kept in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, openSource: openPlatformSource}
+}
+
+// Root is a registered root.
+type Root struct {
+	Path       string    `json:"path"`
+	Registered time.Time `json:"registered"`
+}
+
+// Changes is what a journal reports for the interval since a cursor: the
+// directories whose entries changed, by ID (Windows file reference number)
+// or by absolute path (Linux recorder).
+type Changes struct {
+	DirIDs map[uint64]bool
+	Dirs   map[string]bool
+}
+
+func (c *Changes) has(id uint64, path string) bool {
+	if c == nil {
+		return false
+	}
+	return c.DirIDs[id] || c.Dirs[path]
+}
+
+// source is a change journal for one root.
+type source interface {
+	// Name identifies the journal in snapshots and `files journal list`.
+	Name() string
+	// Cursor returns the current position of the journal.
+	Cursor() (string, error)
+	// ChangesSince returns the changes after cursor. ok is false when the
+	// journal can't cover the whole interval, so a full walk is needed.
+	ChangesSince(cursor string) (changes *Changes, ok bool, err error)
+	Close() error
+}
+
+// Roots returns the registered roots.
+func (s *Store) Roots() ([]Root, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadRoots()
+}
+
+// Register adds root to the journal. It is scanned on the next Scan.
+func (s *Store) Register(root string) error {
+	root, err := cleanRoot(root)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("failed to access directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path is not a directory: %s", root)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	roots, err := s.loadRoots()
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(roots, func(r Root) bool { return r.Path == root }) {
+		return nil
+	}
+	return s.saveRoots(append(roots, Root{Path: root, Registered: time.Now()}))
+}
+
+// Unregister removes root and its snapshot. It returns false if root was
+// not registered.
+func (s *Store) Unregister(root string) (bool, error) {
+	root, err := cleanRoot(root)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	roots, err := s.loadRoots()
+	if err != nil {
+		return false, err
+	}
+	kept := slices.DeleteFunc(slices.Clone(roots), func(r Root) bool { return r.Path == root })
+	if len(kept) == len(roots) {
+		return false, nil
+	}
+	if err := s.saveRoots(kept); err != nil {
+		return false, err
+	}
+	for _, path := range []string{s.snapshotPath(root), s.recorderLogPath(root), s.recorderStatusPath(root)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return true, fmt.Errorf("failed to remove journal data: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// registered returns whether root (already cleaned) is registered.
+func (s *Store) registered(root string) (bool, error) {
+	roots, err := s.Roots()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(roots, func(r Root) bool { return r.Path == root }), nil
+}
+
+func (s *Store) loadRoots() ([]Root, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "roots.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal roots: %w", err)
+	}
+	var roots []Root
+	if err := json.Unmarshal(data, &roots); err != nil {
+		return nil, fmt.Errorf("failed to parse journal roots: %w", err)
+	}
+	return roots, nil
+}
+
+func (s *Store) saveRoots(roots []Root) error {
+	data, err := json.MarshalIndent(roots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal roots: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, "roots.json"), data)
+}
+
+// rootKey names the files of root in the store.
+func rootKey(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Store) snapshotPath(root string) string {
+	return filepath.Join(s.dir, rootKey(root)+".snapshot.json.gz")
+}
+
+func (s *Store) recorderLogPath(root string) string {
+	return filepath.Join(s.dir, rootKey(root)+".changes")
+}
+
+func (s *Store) recorderStatusPath(root string) string {
+	return filepath.Join(s.dir, rootKey(root)+".recorder.json")
+}
+
+// cleanRoot makes root absolute and clean, so each directory has one key.
+func cleanRoot(root string) (string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	return filepath.Clean(abs), nil
+}
+
+// within reports whether path is root or below it.
+func within(root, path string) bool {
+	if path == root {
+		return true
+	}
+	if !strings.HasSuffix(root, string(filepath.Separator)) {
+		root += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, root)
+}
+
+// writeFileAtomic replaces path with data, so a crash never leaves a
+// truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
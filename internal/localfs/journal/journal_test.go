@@ -0,0 +1,248 @@
+package journal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/localfs"
+)
+
+// fakeSource reports the directories a test marks as changed.
+type fakeSource struct {
+	cursor  int
+	covered bool
+	dirs    map[string]bool
+}
+
+func (f *fakeSource) Name() string            { return "fake" }
+func (f *fakeSource) Close() error            { return nil }
+func (f *fakeSource) Cursor() (string, error) { return string(rune('a' + f.cursor)), nil }
+func (f *fakeSource) ChangesSince(string) (*Changes, bool, error) {
+	return &Changes{Dirs: f.dirs}, f.covered, nil
+}
+
+func newTestStore(t *testing.T, src *fakeSource) *Store {
+	t.Helper()
+	s := NewStore(t.TempDir())
+	s.openSource = func(*Store, string) (source, error) { return src, nil }
+	return s
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var testOpts = localfs.WalkOptions{SkipHiddenDirs: true, FollowSymlinks: true}
+
+// assertSameAsWalk checks files against a full WalkCollect of root.
+func assertSameAsWalk(t *testing.T, root string, files []localfs.FileEntry) {
+	t.Helper()
+	want, err := localfs.WalkCollect(root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(want.Files) {
+		t.Fatalf("got %d files, want %d: %v", len(files), len(want.Files), files)
+	}
+	for i, f := range files {
+		w := want.Files[i]
+		if f.Path != w.Path || f.Size != w.Size || !f.ModTime.Equal(w.ModTime) {
+			t.Errorf("file %d = %s (%d bytes, %v), want %s (%d bytes, %v)",
+				i, f.Path, f.Size, f.ModTime, w.Path, w.Size, w.ModTime)
+		}
+	}
+}
+
+func TestScan_Incremental(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "a", "b.txt"), "b")
+	writeFile(t, filepath.Join(root, "c", "d", "e.txt"), "e")
+	writeFile(t, filepath.Join(root, ".hidden", "h.txt"), "h")
+
+	src := &fakeSource{covered: true}
+	s := newTestStore(t, src)
+	if err := s.Register(root); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	files, stats, err := s.Scan(ctx, root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Incremental || stats.Reason != "no snapshot yet" {
+		t.Errorf("first scan stats = %+v, want a full walk", stats)
+	}
+	assertSameAsWalk(t, root, files)
+
+	// Nothing changed: no directory is read.
+	files, stats, err = s.Scan(ctx, root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Incremental || stats.DirsRead != 0 {
+		t.Errorf("unchanged scan stats = %+v, want incremental with no reads", stats)
+	}
+	assertSameAsWalk(t, root, files)
+
+	// Rewrite a file in place, keeping the directory's modification time:
+	// only the journal reveals it.
+	dir := filepath.Join(root, "c", "d")
+	info, _ := os.Stat(dir)
+	writeFile(t, filepath.Join(dir, "e.txt"), "changed")
+	os.Chtimes(dir, info.ModTime(), info.ModTime())
+	src.dirs = map[string]bool{dir: true}
+
+	// Add and remove entries elsewhere.
+	writeFile(t, filepath.Join(root, "c", "new", "n.txt"), "n")
+	if err := os.RemoveAll(filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, stats, err = s.Scan(ctx, root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Incremental {
+		t.Errorf("stats = %+v, want incremental", stats)
+	}
+	// The root, c (new subdirectory), c/new and c/d.
+	if stats.DirsRead != 4 {
+		t.Errorf("DirsRead = %d, want 4", stats.DirsRead)
+	}
+	assertSameAsWalk(t, root, files)
+}
+
+func TestScan_FallsBackToFullWalk(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "x", "y.txt"), "y")
+
+	src := &fakeSource{covered: true}
+	s := newTestStore(t, src)
+	if err := s.Register(root); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, _, err := s.Scan(ctx, root, testOpts); err != nil {
+		t.Fatal(err)
+	}
+
+	src.covered = false
+	files, stats, err := s.Scan(ctx, root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Incremental {
+		t.Errorf("stats = %+v, want a full walk when the journal has a gap", stats)
+	}
+	assertSameAsWalk(t, root, files)
+
+	src.covered = true
+	opts := testOpts
+	opts.IncludeHidden = true
+	if _, stats, err = s.Scan(ctx, root, opts); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Reason != "scan options changed" {
+		t.Errorf("Reason = %q, want scan options changed", stats.Reason)
+	}
+}
+
+func TestScan_Unregistered(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "f.txt"), "f")
+	s := newTestStore(t, &fakeSource{covered: true})
+
+	files, stats, err := s.Scan(context.Background(), root, testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Incremental || stats.Reason != "not registered" {
+		t.Errorf("stats = %+v", stats)
+	}
+	assertSameAsWalk(t, root, files)
+	if _, err := os.Stat(s.snapshotPath(root)); !os.IsNotExist(err) {
+		t.Error("an unregistered root should not get a snapshot")
+	}
+}
+
+func TestScan_SymlinkedDirectory(t *testing.T) {
+	if !followDirLinks {
+		t.Skip("symlinked directories are not followed on this platform")
+	}
+	root := t.TempDir()
+	target := t.TempDir()
+	writeFile(t, filepath.Join(target, "t.txt"), "t")
+	writeFile(t, filepath.Join(root, "r.txt"), "r")
+	if err := os.Symlink(target, filepath.Join(root, "link")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	s := newTestStore(t, &fakeSource{covered: true})
+	if err := s.Register(root); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		files, stats, err := s.Scan(context.Background(), root, testOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Incremental || stats.Reason != errSymlinkDir.Error() {
+			t.Errorf("scan %d stats = %+v, want a full walk", i, stats)
+		}
+		assertSameAsWalk(t, root, files)
+	}
+}
+
+func TestRegisterUnregister(t *testing.T) {
+	root := t.TempDir()
+	s := newTestStore(t, &fakeSource{covered: true})
+
+	if err := s.Register(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register(root + string(filepath.Separator)); err != nil {
+		t.Fatal(err)
+	}
+	roots, err := s.Roots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 1 || roots[0].Path != root {
+		t.Fatalf("Roots() = %v, want just %s", roots, root)
+	}
+	if _, _, err := s.Scan(context.Background(), root, testOpts); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Journal != "fake" || statuses[0].Scanned.IsZero() {
+		t.Errorf("Status() = %+v", statuses)
+	}
+
+	removed, err := s.Unregister(root)
+	if err != nil || !removed {
+		t.Fatalf("Unregister() = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(s.snapshotPath(root)); !os.IsNotExist(err) {
+		t.Error("snapshot was not removed")
+	}
+	if removed, _ := s.Unregister(root); removed {
+		t.Error("second Unregister() reported a removal")
+	}
+	if err := s.Register(filepath.Join(root, "missing")); err == nil {
+		t.Error("registering a missing directory should fail")
+	}
+}
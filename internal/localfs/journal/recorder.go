@@ -0,0 +1,304 @@
+//go:build !windows
+
+package journal
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// flushInterval is how often the recorder appends to the change logs
+	// and refreshes its status.
+	flushInterval = time.Second
+	// staleAfter is how old a status may be before the recorder counts as
+	// stopped.
+	staleAfter = 5 * flushInterval
+	// maxLogSize starts a new session once a change log grows past it,
+	// which makes the next scan of that root a full walk.
+	maxLogSize = 16 << 20
+	// reloadInterval is how often the recorder picks up newly registered
+	// roots.
+	reloadInterval = 30 * time.Second
+)
+
+// watchEvent is a file the kernel reported as written, or an overflow of
+// its event queue (events were lost).
+type watchEvent struct {
+	path     string
+	overflow bool
+}
+
+// recorderStatus is written next to a root's change log after every flush.
+// A session lasts while the recorder runs without losing events; the log
+// holds one quoted directory path per line.
+type recorderStatus struct {
+	Session string    `json:"session"`
+	PID     int       `json:"pid"`
+	Offset  int64     `json:"offset"` // Size of the log after the flush
+	Flushed time.Time `json:"flushed"`
+}
+
+func (s *Store) readRecorderStatus(root string) (*recorderStatus, error) {
+	data, err := os.ReadFile(s.recorderStatusPath(root))
+	if err != nil {
+		return nil, err
+	}
+	var st recorderStatus
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// recorderSource reads the change log the recorder keeps for a root.
+type recorderSource struct {
+	s    *Store
+	root string
+}
+
+func openPlatformSource(s *Store, root string) (source, error) {
+	if !recorderSupported {
+		return nil, fmt.Errorf("%w on %s", ErrUnsupported, runtime.GOOS)
+	}
+	return &recorderSource{s: s, root: root}, nil
+}
+
+func (r *recorderSource) Name() string { return recorderName }
+
+func (r *recorderSource) Close() error { return nil }
+
+func (r *recorderSource) Cursor() (string, error) {
+	st, err := r.s.readRecorderStatus(r.root)
+	if err != nil || time.Since(st.Flushed) > staleAfter {
+		return "", fmt.Errorf("%w: change recorder is not running (start `rescale-int daemon`)", ErrUnsupported)
+	}
+	return st.Session + ":" + strconv.FormatInt(st.Offset, 10), nil
+}
+
+func (r *recorderSource) ChangesSince(cursor string) (*Changes, bool, error) {
+	session, offsetText, found := strings.Cut(cursor, ":")
+	offset, err := strconv.ParseInt(offsetText, 10, 64)
+	if !found || err != nil {
+		return nil, false, nil
+	}
+
+	// Wait for a flush that happened after this call, so writes made just
+	// before the scan are in the log.
+	asked := time.Now()
+	var st *recorderStatus
+	for {
+		st, err = r.s.readRecorderStatus(r.root)
+		if err == nil && st.Flushed.After(asked) {
+			break
+		}
+		if time.Since(asked) > 3*flushInterval {
+			return nil, false, nil
+		}
+		time.Sleep(flushInterval / 10)
+	}
+	if st.Session != session || offset > st.Offset {
+		return nil, false, nil
+	}
+
+	f, err := os.Open(r.s.recorderLogPath(r.root))
+	if err != nil {
+		return nil, false, nil
+	}
+	defer f.Close()
+	changes := &Changes{Dirs: map[string]bool{}}
+	scanner := bufio.NewScanner(io.NewSectionReader(f, offset, st.Offset-offset))
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		dir, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			return nil, false, nil
+		}
+		changes.Dirs[dir] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return changes, true, nil
+}
+
+// Record runs the change recorder for the registered roots until ctx is
+// done. It returns an error wrapping ErrUnsupported when the platform has
+// no recorder or the process lacks the privileges for one.
+func (s *Store) Record(ctx context.Context) error {
+	w, err := newWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	r := &recorder{s: s, w: w, logs: map[string]*rootLog{}}
+	defer r.closeAll()
+	if err := r.reload(); err != nil {
+		return err
+	}
+
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	reload := time.NewTicker(reloadInterval)
+	defer reload.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.Events():
+			if !ok {
+				return fmt.Errorf("change recorder stopped")
+			}
+			if ev.overflow {
+				// Events were lost: scans after this must walk in full.
+				for _, l := range r.logs {
+					if err := l.start(); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			dir := filepath.Dir(ev.path)
+			if within(s.dir, dir) {
+				// The recorder's own writes are not changes.
+				continue
+			}
+			for root, l := range r.logs {
+				if within(root, dir) {
+					l.pending[dir] = true
+				}
+			}
+		case <-flush.C:
+			for _, l := range r.logs {
+				if err := l.flush(); err != nil {
+					return err
+				}
+			}
+		case <-reload.C:
+			if err := r.reload(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type recorder struct {
+	s    *Store
+	w    *watcher
+	logs map[string]*rootLog
+}
+
+// reload starts recording newly registered roots and stops recording
+// removed ones.
+func (r *recorder) reload() error {
+	roots, err := r.s.Roots()
+	if err != nil {
+		return err
+	}
+	current := map[string]bool{}
+	for _, root := range roots {
+		current[root.Path] = true
+		if _, ok := r.logs[root.Path]; ok {
+			continue
+		}
+		if err := r.w.Add(root.Path); err != nil {
+			// The root may be gone or on a file system without fanotify;
+			// its scans stay full walks.
+			continue
+		}
+		l := &rootLog{s: r.s, root: root.Path}
+		if err := l.start(); err != nil {
+			return err
+		}
+		r.logs[root.Path] = l
+	}
+	for root, l := range r.logs {
+		if !current[root] {
+			l.close()
+			delete(r.logs, root)
+		}
+	}
+	return nil
+}
+
+func (r *recorder) closeAll() {
+	for _, l := range r.logs {
+		l.close()
+	}
+}
+
+// rootLog is the change log of one root.
+type rootLog struct {
+	s       *Store
+	root    string
+	session string
+	f       *os.File
+	offset  int64
+	pending map[string]bool
+}
+
+// start begins a new session with an empty log.
+func (l *rootLog) start() error {
+	l.close()
+	if err := os.MkdirAll(l.s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	f, err := os.Create(l.s.recorderLogPath(l.root))
+	if err != nil {
+		return fmt.Errorf("failed to create change log: %w", err)
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	l.session, l.f, l.offset, l.pending = hex.EncodeToString(id), f, 0, map[string]bool{}
+	return l.writeStatus()
+}
+
+// flush appends the pending directories and refreshes the status, which
+// also tells scanners the recorder is alive.
+func (l *rootLog) flush() error {
+	if len(l.pending) > 0 {
+		dirs := make([]string, 0, len(l.pending))
+		for dir := range l.pending {
+			dirs = append(dirs, strconv.Quote(dir))
+		}
+		sort.Strings(dirs)
+		n, err := l.f.WriteString(strings.Join(dirs, "\n") + "\n")
+		l.offset += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write change log: %w", err)
+		}
+		l.pending = map[string]bool{}
+		if l.offset > maxLogSize {
+			return l.start()
+		}
+	}
+	return l.writeStatus()
+}
+
+func (l *rootLog) writeStatus() error {
+	data, err := json.Marshal(recorderStatus{Session: l.session, PID: os.Getpid(), Offset: l.offset, Flushed: time.Now()})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(l.s.recorderStatusPath(l.root), data)
+}
+
+func (l *rootLog) close() {
+	if l.f != nil {
+		l.f.Close()
+		l.f = nil
+	}
+}
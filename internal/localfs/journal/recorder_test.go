@@ -0,0 +1,58 @@
+//go:build !windows
+
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorderSource(t *testing.T) {
+	if !recorderSupported {
+		t.Skip("no change recorder on this platform")
+	}
+	s := NewStore(t.TempDir())
+	root := t.TempDir()
+	src := &recorderSource{s: s, root: root}
+	if _, err := src.Cursor(); err == nil {
+		t.Fatal("Cursor() without a recorder should fail")
+	}
+
+	l := &rootLog{s: s, root: root}
+	if err := l.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer l.close()
+	cursor, err := src.Cursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "sub\ndir")
+	l.pending[dir] = true
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.flush()
+	}()
+	changes, ok, err := src.ChangesSince(cursor)
+	if err != nil || !ok {
+		t.Fatalf("ChangesSince() = %v, %v", ok, err)
+	}
+	if !changes.Dirs[dir] || len(changes.Dirs) != 1 {
+		t.Errorf("Dirs = %v, want just %q", changes.Dirs, dir)
+	}
+
+	// A new session (the recorder restarted or lost events) breaks the
+	// chain.
+	if err := l.start(); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.flush()
+	}()
+	if _, ok, _ := src.ChangesSince(cursor); ok {
+		t.Error("ChangesSince() across sessions should not be covered")
+	}
+}
@@ -0,0 +1,339 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/localfs"
+)
+
+// errSymlinkDir stops a walk that meets a symlinked directory it would have
+// to follow.
+var errSymlinkDir = errors.New("tree contains symlinked directories")
+
+// ScanStats describes how a Scan went.
+type ScanStats struct {
+	Registered  bool   // The root is registered with the journal
+	Incremental bool   // Only changed directories were read
+	Journal     string // Journal consulted, if any
+	Reason      string // Why the tree was walked in full, when not Incremental
+	DirsRead    int    // Directories listed by this scan
+	Dirs        int    // Directories in the tree
+}
+
+// Scan returns the files under dir as localfs.WalkCollect(dir,
+// opts).Files would, in the same order. For a registered root only the
+// directories the change journal reports (or whose modification time moved)
+// are read again; the snapshot is then updated for the next scan.
+func (s *Store) Scan(ctx context.Context, dir string, opts localfs.WalkOptions) ([]localfs.FileEntry, *ScanStats, error) {
+	root, err := cleanRoot(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	ok, err := s.registered(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		result, err := localfs.WalkCollect(dir, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Files, &ScanStats{Reason: "not registered", Dirs: len(result.Directories) + 1}, nil
+	}
+
+	started := time.Now()
+	stats := &ScanStats{Registered: true}
+	src, srcErr := s.openSource(s, root)
+	var cursor string
+	if srcErr == nil {
+		defer src.Close()
+		stats.Journal = src.Name()
+		cursor, srcErr = src.Cursor()
+	}
+
+	// The cursor is taken before reading anything, so changes made while
+	// this scan runs are reported again by the next one.
+	snap := s.loadSnapshot(root)
+	var changes *Changes
+	switch {
+	case snap == nil:
+		stats.Reason = "no snapshot yet"
+	case snap.Options != opts:
+		stats.Reason = "scan options changed"
+	case snap.Symlinks:
+		stats.Reason = errSymlinkDir.Error()
+	case srcErr != nil:
+		stats.Reason = srcErr.Error()
+	case snap.Journal != src.Name():
+		stats.Reason = "journal changed"
+	default:
+		var covered bool
+		changes, covered, err = src.ChangesSince(snap.Cursor)
+		switch {
+		case err != nil:
+			stats.Reason = err.Error()
+		case !covered:
+			stats.Reason = "journal does not cover the time since the last scan"
+		}
+	}
+
+	w := &walker{ctx: ctx, root: root, opts: opts}
+	if stats.Reason == "" {
+		stats.Incremental = true
+		err = w.refresh(snap, changes)
+	} else {
+		snap = &snapshot{Version: snapshotVersion, Root: root, Options: opts, Dirs: map[string]*dirState{}}
+		err = w.walk(snap, "")
+	}
+	stats.DirsRead = w.dirsRead
+
+	if errors.Is(err, errSymlinkDir) {
+		// Fall back to the regular walker, which follows symlinks with
+		// cycle detection, and remember not to try the journal again.
+		result, walkErr := localfs.WalkCollect(dir, opts)
+		if walkErr != nil {
+			return nil, nil, walkErr
+		}
+		stats.Incremental, stats.Reason = false, errSymlinkDir.Error()
+		stats.Dirs = len(result.Directories) + 1
+		snap = &snapshot{Version: snapshotVersion, Root: root, Options: opts, Scanned: started, Symlinks: true}
+		if err := s.saveSnapshot(snap); err != nil {
+			return nil, nil, err
+		}
+		return result.Files, stats, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snap.Scanned = started
+	snap.Journal, snap.Cursor = "", ""
+	if srcErr == nil {
+		snap.Journal, snap.Cursor = src.Name(), cursor
+	}
+	if err := s.saveSnapshot(snap); err != nil {
+		return nil, nil, err
+	}
+	stats.Dirs = len(snap.Dirs)
+	// Paths start with dir as given, like those of WalkCollect.
+	return snap.files(dir), stats, nil
+}
+
+// files lists the files of s below root in the order filepath.WalkDir
+// visits them.
+func (s *snapshot) files(root string) []localfs.FileEntry {
+	files := make([]localfs.FileEntry, 0, s.fileCount())
+	var visit func(rel string)
+	visit = func(rel string) {
+		d := s.Dirs[rel]
+		if d == nil {
+			return
+		}
+		names := make([]string, 0, len(d.Files)+len(d.Subdirs))
+		for name := range d.Files {
+			names = append(names, name)
+		}
+		names = append(names, d.Subdirs...)
+		sort.Strings(names)
+		for _, name := range names {
+			if f, ok := d.Files[name]; ok {
+				files = append(files, localfs.FileEntry{
+					Path:    filepath.Join(root, filepath.FromSlash(rel), name),
+					Name:    name,
+					Size:    f.Size,
+					ModTime: time.Unix(0, f.ModTime),
+					Mode:    fs.FileMode(f.Mode),
+				})
+			} else {
+				visit(path.Join(rel, name))
+			}
+		}
+	}
+	visit("")
+	return files
+}
+
+// walker reads directories into a snapshot with the filtering rules of
+// localfs.WalkCollect.
+type walker struct {
+	ctx      context.Context
+	root     string
+	opts     localfs.WalkOptions
+	dirsRead int
+}
+
+func (w *walker) abs(rel string) string {
+	return filepath.Join(w.root, filepath.FromSlash(rel))
+}
+
+// walk reads the directory rel and everything below it into snap.
+func (w *walker) walk(snap *snapshot, rel string) error {
+	abs := w.abs(rel)
+	info, err := os.Lstat(abs)
+	if err != nil || !info.IsDir() {
+		// WalkCollect skips what it can't read; a missing root is empty.
+		if rel == "" {
+			snap.Dirs[rel] = &dirState{}
+		}
+		return nil
+	}
+	d, err := w.list(rel, info)
+	if err != nil {
+		return err
+	}
+	snap.Dirs[rel] = d
+	for _, name := range d.Subdirs {
+		if err := w.walk(snap, path.Join(rel, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refresh brings snap up to date, reading only the directories that
+// changed.
+func (w *walker) refresh(snap *snapshot, changes *Changes) error {
+	rels := make([]string, 0, len(snap.Dirs))
+	for rel := range snap.Dirs {
+		rels = append(rels, rel)
+	}
+	// Parents sort before their children, so a removed or re-walked
+	// subtree is settled before its directories come up.
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		old, ok := snap.Dirs[rel]
+		if !ok {
+			continue
+		}
+		abs := w.abs(rel)
+		info, err := os.Lstat(abs)
+		if err != nil || !info.IsDir() {
+			removeSubtree(snap, rel)
+			if rel == "" {
+				snap.Dirs[rel] = &dirState{}
+			}
+			continue
+		}
+		if info.ModTime().UnixNano() == old.ModTime && !changes.has(old.ID, abs) {
+			continue
+		}
+
+		d, err := w.list(rel, info)
+		if err != nil {
+			return err
+		}
+		snap.Dirs[rel] = d
+		for _, name := range d.Subdirs {
+			child := path.Join(rel, name)
+			prev, known := snap.Dirs[child]
+			if known {
+				// A directory replaced by another of the same name has a
+				// new ID; its entries are unknown, so read it all again.
+				childInfo, err := os.Lstat(w.abs(child))
+				if err != nil || dirID(w.abs(child), childInfo) == prev.ID {
+					continue
+				}
+				removeSubtree(snap, child)
+			}
+			if err := w.walk(snap, child); err != nil {
+				return err
+			}
+		}
+		for _, name := range old.Subdirs {
+			if !slices.Contains(d.Subdirs, name) {
+				removeSubtree(snap, path.Join(rel, name))
+			}
+		}
+	}
+	return nil
+}
+
+// removeSubtree drops rel and every directory below it.
+func removeSubtree(snap *snapshot, rel string) {
+	prefix := rel + "/"
+	for key := range snap.Dirs {
+		if key == rel || rel == "" || strings.HasPrefix(key, prefix) {
+			delete(snap.Dirs, key)
+		}
+	}
+}
+
+// list reads one directory: its regular (and other non-directory) files
+// and the subdirectories the walk descends into.
+func (w *walker) list(rel string, info fs.FileInfo) (*dirState, error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, err
+	}
+	abs := w.abs(rel)
+	d := &dirState{ID: dirID(abs, info), ModTime: info.ModTime().UnixNano(), Files: map[string]fileState{}}
+	w.dirsRead++
+
+	entries, err := os.ReadDir(abs)
+	if err != nil && len(entries) == 0 {
+		return d, nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !w.opts.IncludeHidden && localfs.IsHiddenName(name) {
+			if entry.IsDir() && !w.opts.SkipHiddenDirs {
+				// WalkCollect descends without listing the directory
+				// itself; its non-hidden files are still included.
+				d.Subdirs = append(d.Subdirs, name)
+			}
+			continue
+		}
+		if entry.IsDir() {
+			d.Subdirs = append(d.Subdirs, name)
+			continue
+		}
+
+		full := filepath.Join(abs, name)
+		fi, err := os.Lstat(full)
+		if err != nil {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if !w.opts.FollowSymlinks {
+				continue
+			}
+			target, err := os.Stat(full)
+			if err != nil {
+				continue // Broken symlink
+			}
+			if target.IsDir() {
+				if !followDirLinks {
+					continue // Not followed by WalkCollect on this platform
+				}
+				return nil, errSymlinkDir
+			}
+			fi = target
+		} else if !fi.Mode().IsRegular() {
+			// Junctions and other reparse points that resolve to a
+			// directory are not files.
+			if target, err := os.Stat(full); err == nil && target.IsDir() {
+				continue
+			}
+		}
+		d.Files[name] = fileState{Size: fi.Size(), ModTime: fi.ModTime().UnixNano(), Mode: uint32(fi.Mode())}
+	}
+	return d, nil
+}
+
+// String summarises stats for CLI output.
+func (st *ScanStats) String() string {
+	if st.Incremental {
+		return fmt.Sprintf("%s journal: read %d of %d directories", st.Journal, st.DirsRead, st.Dirs)
+	}
+	return fmt.Sprintf("full scan of %d directories (%s)", st.Dirs, st.Reason)
+}
@@ -0,0 +1,146 @@
+package journal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/localfs"
+)
+
+// snapshotVersion is bumped when the snapshot layout changes; older
+// snapshots are discarded.
+const snapshotVersion = 1
+
+// snapshot is the state of a root at its last scan.
+type snapshot struct {
+	Version int                 `json:"version"`
+	Root    string              `json:"root"`
+	Options localfs.WalkOptions `json:"options"`
+	Journal string              `json:"journal,omitempty"`
+	Cursor  string              `json:"cursor,omitempty"`
+	Scanned time.Time           `json:"scanned"`
+
+	// Symlinks is set when the tree holds followed symlinked directories,
+	// which are always walked in full; Dirs is then empty.
+	Symlinks bool `json:"symlinks,omitempty"`
+
+	// Dirs holds every directory the walk descends into, keyed by the
+	// slash-separated path relative to the root ("" is the root).
+	Dirs map[string]*dirState `json:"dirs"`
+}
+
+// dirState is one directory. Short JSON keys keep snapshots of large trees
+// small.
+type dirState struct {
+	ID      uint64               `json:"i,omitempty"`
+	ModTime int64                `json:"m"`
+	Subdirs []string             `json:"d,omitempty"`
+	Files   map[string]fileState `json:"f,omitempty"`
+}
+
+type fileState struct {
+	Size    int64  `json:"s"`
+	ModTime int64  `json:"m"`
+	Mode    uint32 `json:"p"`
+}
+
+// fileCount returns the number of files in s.
+func (s *snapshot) fileCount() int {
+	n := 0
+	for _, d := range s.Dirs {
+		n += len(d.Files)
+	}
+	return n
+}
+
+// loadSnapshot returns the snapshot of root, or nil if there is none or it
+// can't be used.
+func (s *Store) loadSnapshot(root string) *snapshot {
+	f, err := os.Open(s.snapshotPath(root))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil
+	}
+	var snap snapshot
+	if err := json.NewDecoder(zr).Decode(&snap); err != nil {
+		return nil
+	}
+	if snap.Version != snapshotVersion || snap.Root != root {
+		return nil
+	}
+	return &snap
+}
+
+func (s *Store) saveSnapshot(snap *snapshot) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		return fmt.Errorf("failed to encode journal snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to encode journal snapshot: %w", err)
+	}
+	return writeFileAtomic(s.snapshotPath(snap.Root), buf.Bytes())
+}
+
+// Status describes a registered root for `files journal list`.
+type Status struct {
+	Root
+	Journal  string    // Journal in use, or "" if scans are full walks
+	Reason   string    // Why there is no journal, when Journal is ""
+	Scanned  time.Time // Last scan; zero if the root was never scanned
+	Dirs     int
+	Files    int
+	Symlinks bool // Tree holds symlinked directories, so it is always walked
+}
+
+// Journal returns the name of the change journal usable for root now, or
+// an error saying why there is none.
+func (s *Store) Journal(root string) (string, error) {
+	root, err := cleanRoot(root)
+	if err != nil {
+		return "", err
+	}
+	src, err := s.openSource(s, root)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	if _, err := src.Cursor(); err != nil {
+		return "", err
+	}
+	return src.Name(), nil
+}
+
+// Status returns the state of every registered root.
+func (s *Store) Status() ([]Status, error) {
+	roots, err := s.Roots()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(roots))
+	for _, root := range roots {
+		st := Status{Root: root}
+		if name, err := s.Journal(root.Path); err != nil {
+			st.Reason = err.Error()
+		} else {
+			st.Journal = name
+		}
+		if snap := s.loadSnapshot(root.Path); snap != nil {
+			st.Scanned = snap.Scanned
+			st.Dirs = len(snap.Dirs)
+			st.Files = snap.fileCount()
+			st.Symlinks = snap.Symlinks
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,166 @@
+//go:build windows
+
+package journal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	fsctlQueryUSNJournal            = 0x000900f4
+	fsctlReadUnprivilegedUSNJournal = 0x000903ab
+)
+
+// usnJournalData is USN_JOURNAL_DATA_V0.
+type usnJournalData struct {
+	UsnJournalID    uint64
+	FirstUsn        int64
+	NextUsn         int64
+	LowestValidUsn  int64
+	MaxUsn          int64
+	MaximumSize     uint64
+	AllocationDelta uint64
+}
+
+// readUSNJournalData is READ_USN_JOURNAL_DATA_V0.
+type readUSNJournalData struct {
+	StartUsn          int64
+	ReasonMask        uint32
+	ReturnOnlyOnClose uint32
+	Timeout           uint64
+	BytesToWaitFor    uint64
+	UsnJournalID      uint64
+}
+
+// usnSource reads the USN journal of the volume holding a root. The
+// unprivileged read control code works on a handle to any directory of the
+// volume, so no admin rights are needed.
+type usnSource struct {
+	h windows.Handle
+}
+
+func openPlatformSource(_ *Store, root string) (source, error) {
+	h, err := openDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	src := &usnSource{h: h}
+	if _, err := src.query(); err != nil {
+		windows.CloseHandle(h)
+		return nil, err
+	}
+	return src, nil
+}
+
+func (u *usnSource) Name() string { return "usn" }
+
+func (u *usnSource) Close() error { return windows.CloseHandle(u.h) }
+
+func (u *usnSource) query() (*usnJournalData, error) {
+	var data usnJournalData
+	var n uint32
+	err := windows.DeviceIoControl(u.h, fsctlQueryUSNJournal, nil, 0,
+		(*byte)(unsafe.Pointer(&data)), uint32(unsafe.Sizeof(data)), &n, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: USN journal: %v", ErrUnsupported, err)
+	}
+	return &data, nil
+}
+
+func (u *usnSource) Cursor() (string, error) {
+	data, err := u.query()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x:%d", data.UsnJournalID, data.NextUsn), nil
+}
+
+func (u *usnSource) ChangesSince(cursor string) (*Changes, bool, error) {
+	var journalID uint64
+	var usn int64
+	if _, err := fmt.Sscanf(cursor, "%x:%d", &journalID, &usn); err != nil {
+		return nil, false, nil
+	}
+	data, err := u.query()
+	if err != nil {
+		return nil, false, err
+	}
+	if journalID != data.UsnJournalID || usn < data.FirstUsn || usn < data.LowestValidUsn {
+		// The journal was recreated or has wrapped past the cursor.
+		return nil, false, nil
+	}
+
+	changes := &Changes{DirIDs: map[uint64]bool{}}
+	buf := make([]byte, 64<<10)
+	for usn < data.NextUsn {
+		in := readUSNJournalData{StartUsn: usn, ReasonMask: 0xffffffff, UsnJournalID: data.UsnJournalID}
+		var n uint32
+		err := windows.DeviceIoControl(u.h, fsctlReadUnprivilegedUSNJournal,
+			(*byte)(unsafe.Pointer(&in)), uint32(unsafe.Sizeof(in)), &buf[0], uint32(len(buf)), &n, nil)
+		if errors.Is(err, windows.ERROR_JOURNAL_ENTRY_DELETED) || errors.Is(err, windows.ERROR_JOURNAL_DELETE_IN_PROGRESS) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read USN journal: %w", err)
+		}
+		if n < 8 {
+			break
+		}
+		addUSNRecords(changes, buf[8:n])
+		next := int64(binary.LittleEndian.Uint64(buf))
+		if next <= usn {
+			break
+		}
+		usn = next
+	}
+	return changes, true, nil
+}
+
+// addUSNRecords marks the parent directory of every record, and the
+// directory itself when the record is about one. Version 3 records (ReFS)
+// carry 128-bit IDs, of which the low 64 bits match the file index that
+// dirID reads.
+func addUSNRecords(changes *Changes, records []byte) {
+	le := binary.LittleEndian
+	for len(records) >= 8 {
+		length := int(le.Uint32(records))
+		if length < 8 || length > len(records) {
+			return
+		}
+		rec := records[:length]
+		records = records[length:]
+
+		var id, parent uint64
+		var attrs uint32
+		switch le.Uint16(rec[4:]) {
+		case 2:
+			if len(rec) < 60 {
+				continue
+			}
+			id, parent, attrs = le.Uint64(rec[8:]), le.Uint64(rec[16:]), le.Uint32(rec[52:])
+		case 3:
+			if len(rec) < 76 {
+				continue
+			}
+			id, parent, attrs = le.Uint64(rec[8:]), le.Uint64(rec[24:]), le.Uint32(rec[68:])
+		default:
+			continue
+		}
+		changes.DirIDs[parent] = true
+		if attrs&windows.FILE_ATTRIBUTE_DIRECTORY != 0 {
+			changes.DirIDs[id] = true
+		}
+	}
+}
+
+// Record does nothing on Windows: the file system keeps the USN journal
+// itself.
+func (s *Store) Record(ctx context.Context) error {
+	return nil
+}
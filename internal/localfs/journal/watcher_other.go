@@ -0,0 +1,27 @@
+//go:build !windows && !linux
+
+package journal
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// No change journal is wired up here yet (macOS FSEvents would need cgo),
+// so registered roots are walked in full.
+const (
+	recorderSupported = false
+	recorderName      = ""
+)
+
+type watcher struct{}
+
+func newWatcher() (*watcher, error) {
+	return nil, fmt.Errorf("%w on %s", ErrUnsupported, runtime.GOOS)
+}
+
+func (w *watcher) Add(string) error { return nil }
+
+func (w *watcher) Events() <-chan watchEvent { return nil }
+
+func (w *watcher) Close() error { return nil }
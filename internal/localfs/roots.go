@@ -0,0 +1,83 @@
+package localfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Root kinds reported by ListRoots.
+const (
+	RootKindHome   = "home"
+	RootKindDrive  = "drive"
+	RootKindMount  = "mount"
+	RootKindCustom = "custom"
+)
+
+// Root is a top-level location offered as a shortcut in the local browser.
+type Root struct {
+	Name string // Display label
+	Path string // Absolute path to navigate to
+	Kind string // One of the RootKind* constants
+}
+
+// ListRoots enumerates browsable roots: the user's home directory, platform
+// drives and mounts (Windows drive letters, /Volumes, /mnt, /media), and any
+// user-defined roots. Paths that do not exist or are not directories are
+// skipped, and duplicates are collapsed with the first occurrence winning.
+func ListRoots(custom []string) []Root {
+	var roots []Root
+	seen := make(map[string]bool)
+	add := func(r Root) {
+		clean := filepath.Clean(r.Path)
+		if seen[clean] || !isDir(clean) {
+			return
+		}
+		seen[clean] = true
+		r.Path = clean
+		roots = append(roots, r)
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		add(Root{Name: "Home", Path: home, Kind: RootKindHome})
+	}
+	for _, r := range platformRoots() {
+		add(r)
+	}
+	for _, p := range custom {
+		if p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		add(Root{Name: filepath.Base(abs), Path: abs, Kind: RootKindCustom})
+	}
+	return roots
+}
+
+// mountEntries lists the subdirectories of a mount parent such as /Volumes
+// or /mnt as mount roots.
+func mountEntries(parent string) []Root {
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return nil
+	}
+	var roots []Root
+	for _, e := range entries {
+		if IsHiddenName(e.Name()) {
+			continue
+		}
+		roots = append(roots, Root{
+			Name: e.Name(),
+			Path: filepath.Join(parent, e.Name()),
+			Kind: RootKindMount,
+		})
+	}
+	return roots
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
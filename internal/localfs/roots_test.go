@@ -0,0 +1,49 @@
+package localfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListRoots_IncludesHomeAndCustom(t *testing.T) {
+	custom := t.TempDir()
+	missing := filepath.Join(custom, "does-not-exist")
+
+	roots := ListRoots([]string{custom, custom, missing, ""})
+
+	home, _ := os.UserHomeDir()
+	var sawHome bool
+	customCount := 0
+	for _, r := range roots {
+		if r.Kind == RootKindHome && r.Path == filepath.Clean(home) {
+			sawHome = true
+		}
+		if r.Path == filepath.Clean(custom) {
+			customCount++
+			if r.Kind != RootKindCustom || r.Name != filepath.Base(custom) {
+				t.Errorf("custom root = %+v", r)
+			}
+		}
+		if r.Path == missing {
+			t.Error("non-existent custom root should be skipped")
+		}
+	}
+	if home != "" && !sawHome {
+		t.Errorf("home directory missing from roots: %+v", roots)
+	}
+	if customCount != 1 {
+		t.Errorf("custom root listed %d times, want 1", customCount)
+	}
+}
+
+func TestMountEntries_SkipsHidden(t *testing.T) {
+	parent := t.TempDir()
+	os.Mkdir(filepath.Join(parent, "data"), 0755)
+	os.Mkdir(filepath.Join(parent, ".hidden"), 0755)
+
+	roots := mountEntries(parent)
+	if len(roots) != 1 || roots[0].Name != "data" || roots[0].Kind != RootKindMount {
+		t.Errorf("mountEntries() = %+v, want only data", roots)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package localfs
+
+import (
+	"os/user"
+	"path/filepath"
+)
+
+// platformRoots returns the filesystem root plus mounted volumes under the
+// conventional macOS (/Volumes) and Linux (/mnt, /media, /run/media) parents.
+func platformRoots() []Root {
+	roots := []Root{{Name: "/", Path: "/", Kind: RootKindDrive}}
+
+	parents := []string{"/Volumes", "/mnt", "/media"}
+	userMedia := ""
+	if u, err := user.Current(); err == nil {
+		// udisks mounts removable media per user
+		userMedia = filepath.Join("/media", u.Username)
+		parents = append(parents, userMedia, filepath.Join("/run/media", u.Username))
+	}
+	for _, parent := range parents {
+		for _, r := range mountEntries(parent) {
+			// /media/<user> is a parent of volumes, not a volume itself
+			if r.Path == userMedia {
+				continue
+			}
+			roots = append(roots, r)
+		}
+	}
+	return roots
+}
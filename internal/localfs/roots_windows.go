@@ -0,0 +1,17 @@
+//go:build windows
+
+package localfs
+
+// platformRoots returns every drive letter that currently resolves to a
+// mounted volume (local disks, mapped network drives, removable media).
+func platformRoots() []Root {
+	var roots []Root
+	for c := 'A'; c <= 'Z'; c++ {
+		drive := string(c) + `:\`
+		if !isDir(drive) {
+			continue
+		}
+		roots = append(roots, Root{Name: string(c) + ":", Path: drive, Kind: RootKindDrive})
+	}
+	return roots
+}
@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Correlation identifies the PUR run and the transfer a log line or event
+// belongs to, so output from concurrent runs and transfers can be told
+// apart. Either ID may be empty.
+type Correlation struct {
+	RunID      string
+	TransferID string
+}
+
+type correlationKey struct{}
+
+type runLogKey struct{}
+
+// WithRunID returns a copy of ctx carrying runID. Log lines written with
+// Printf and events published for work under ctx are tagged with it.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	c := CorrelationFrom(ctx)
+	c.RunID = runID
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// WithTransferID returns a copy of ctx carrying transferID, keeping any
+// run ID already set.
+func WithTransferID(ctx context.Context, transferID string) context.Context {
+	c := CorrelationFrom(ctx)
+	c.TransferID = transferID
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// EnsureTransferID returns ctx unchanged when it already carries a transfer
+// ID, or a copy with a new one.
+func EnsureTransferID(ctx context.Context) context.Context {
+	if CorrelationFrom(ctx).TransferID != "" {
+		return ctx
+	}
+	return WithTransferID(ctx, NewTransferID())
+}
+
+// CorrelationFrom returns the IDs carried by ctx.
+func CorrelationFrom(ctx context.Context) Correlation {
+	if ctx == nil {
+		return Correlation{}
+	}
+	c, _ := ctx.Value(correlationKey{}).(Correlation)
+	return c
+}
+
+// NewTransferID returns a short random ID for a transfer that isn't tracked
+// in the transfer queue (which has its own task IDs), e.g. "tr-1a2b3c4d".
+func NewTransferID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return "tr-" + hex.EncodeToString(b)
+}
+
+// Matches reports whether id is the run or the transfer ID.
+func (c Correlation) Matches(id string) bool {
+	return id != "" && (id == c.RunID || id == c.TransferID)
+}
+
+// Tag is appended to log lines: " [run=<id> transfer=<id>]", leaving out
+// empty IDs, or "" when there are none. ParseTag reverses it.
+func (c Correlation) Tag() string {
+	var parts []string
+	if c.RunID != "" {
+		parts = append(parts, "run="+c.RunID)
+	}
+	if c.TransferID != "" {
+		parts = append(parts, "transfer="+c.TransferID)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, " ") + "]"
+}
+
+// ParseTag splits a trailing Tag off line, returning the line without it and
+// the IDs it held. Lines without a tag are returned unchanged.
+func ParseTag(line string) (string, Correlation) {
+	if !strings.HasSuffix(line, "]") {
+		return line, Correlation{}
+	}
+	start := strings.LastIndex(line, " [")
+	if start < 0 {
+		return line, Correlation{}
+	}
+	var c Correlation
+	for _, field := range strings.Fields(line[start+2 : len(line)-1]) {
+		key, value, ok := strings.Cut(field, "=")
+		switch {
+		case ok && key == "run" && value != "":
+			c.RunID = value
+		case ok && key == "transfer" && value != "":
+			c.TransferID = value
+		default:
+			return line, Correlation{}
+		}
+	}
+	if c == (Correlation{}) {
+		return line, Correlation{}
+	}
+	return line[:start], c
+}
+
+// WithRunLog returns a copy of ctx that also writes Printf output to rl.
+func WithRunLog(ctx context.Context, rl *RunLog) context.Context {
+	return context.WithValue(ctx, runLogKey{}, rl)
+}
+
+// RunLogFrom returns the run log carried by ctx, or nil.
+func RunLogFrom(ctx context.Context) *RunLog {
+	if ctx == nil {
+		return nil
+	}
+	rl, _ := ctx.Value(runLogKey{}).(*RunLog)
+	return rl
+}
+
+// Printf writes a stdlib log line tagged with the IDs carried by ctx and,
+// when ctx carries a run log, records it there too. A leading "[LEVEL]"
+// prefix sets the run log level (INFO otherwise).
+func Printf(ctx context.Context, format string, args ...interface{}) {
+	c := CorrelationFrom(ctx)
+	message := fmt.Sprintf(format, args...)
+	log.Print(message + c.Tag())
+
+	if rl := RunLogFrom(ctx); rl != nil {
+		level, text := splitLevel(message)
+		rl.Log(RunLogRecord{Level: level, TransferID: c.TransferID, Message: text})
+	}
+}
+
+// splitLevel separates a leading "[DEBUG]", "[INFO]", "[WARN]" or "[ERROR]"
+// from message.
+func splitLevel(message string) (string, string) {
+	for _, level := range []string{"DEBUG", "INFO", "WARN", "ERROR"} {
+		if rest, ok := strings.CutPrefix(message, "["+level+"] "); ok {
+			return level, rest
+		}
+	}
+	return "INFO", message
+}
+
+// Ctx returns a child logger whose lines carry the run_id and transfer_id
+// fields from ctx, or l itself when ctx carries neither.
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	c := CorrelationFrom(ctx)
+	child := l
+	if c.RunID != "" {
+		child = child.WithStr("run_id", c.RunID)
+	}
+	if c.TransferID != "" {
+		child = child.WithStr("transfer_id", c.TransferID)
+	}
+	return child
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestCorrelationContext(t *testing.T) {
+	ctx := WithTransferID(WithRunID(context.Background(), "pur_1"), "tr-1")
+	if c := CorrelationFrom(ctx); c != (Correlation{RunID: "pur_1", TransferID: "tr-1"}) {
+		t.Errorf("CorrelationFrom = %+v", c)
+	}
+	if got := EnsureTransferID(ctx); CorrelationFrom(got).TransferID != "tr-1" {
+		t.Error("EnsureTransferID replaced an existing transfer ID")
+	}
+	if c := CorrelationFrom(EnsureTransferID(context.Background())); c.TransferID == "" || c.RunID != "" {
+		t.Errorf("EnsureTransferID = %+v, want a new transfer ID only", c)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantLine string
+		want     Correlation
+	}{
+		{"uploaded [run=pur_1 transfer=tr-1]", "uploaded", Correlation{"pur_1", "tr-1"}},
+		{"uploaded [transfer=tr-1]", "uploaded", Correlation{TransferID: "tr-1"}},
+		{"[INFO] part [3/4]", "[INFO] part [3/4]", Correlation{}},
+		{"plain line", "plain line", Correlation{}},
+	}
+	for _, tt := range tests {
+		line, c := ParseTag(tt.line)
+		if line != tt.wantLine || c != tt.want {
+			t.Errorf("ParseTag(%q) = %q, %+v, want %q, %+v", tt.line, line, c, tt.wantLine, tt.want)
+		}
+		if tt.want != (Correlation{}) {
+			if line, c := ParseTag(tt.wantLine + tt.want.Tag()); line != tt.wantLine || c != tt.want {
+				t.Errorf("ParseTag(Tag()) round trip = %q, %+v", line, c)
+			}
+		}
+	}
+}
+
+func TestRunLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "pur_1.jsonl")
+	rl, err := OpenRunLog(path, "pur_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.Log(RunLogRecord{Level: "INFO", Stage: "tar", JobName: "Run_1", Message: "tarred"})
+	ctx := WithRunLog(WithTransferID(WithRunID(context.Background(), "pur_1"), "tr-1"), rl)
+	Printf(ctx, "[WARN] retrying part %d", 3)
+	if err := rl.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadRunLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if r := records[0]; r.RunID != "pur_1" || r.Stage != "tar" || r.Message != "tarred" || r.Time.IsZero() {
+		t.Errorf("record 0 = %+v", r)
+	}
+	if r := records[1]; r.Level != "WARN" || r.TransferID != "tr-1" || r.Message != "retrying part 3" {
+		t.Errorf("record 1 = %+v", r)
+	}
+
+	var nilLog *RunLog
+	nilLog.Log(RunLogRecord{Message: "dropped"})
+	if err := nilLog.Close(); err != nil {
+		t.Errorf("nil RunLog Close = %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Log output formats, as named by --log-format and log_format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// jsonTimeFormat is the time field of JSON log records: RFC 3339 with
+// milliseconds, so lines logged in the same second keep their order when
+// ingested.
+const jsonTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var jsonOutput atomic.Bool
+
+// ParseFormat checks a --log-format or log_format value. Empty means text.
+func ParseFormat(s string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q (expected text or json)", s)
+	}
+}
+
+// SetFormat switches the process's log output to format. With FormatJSON,
+// every line written by a Logger, the global zerolog logger, the stdlib log
+// package and PrintLine is one JSON object with the fields time, level,
+// message and, when known, stage, job_name, run_id and transfer_id, for
+// ingestion by Splunk, ELK and the like. Loggers created before the call
+// keep their format, so call it before creating any.
+func SetFormat(format string) {
+	on := format == FormatJSON
+	jsonOutput.Store(on)
+	if !on {
+		return
+	}
+	zerolog.TimeFieldFormat = jsonTimeFormat
+	zlog.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(StdlibWriter(os.Stderr))
+}
+
+// JSONOutput reports whether log output is JSON.
+func JSONOutput() bool {
+	return jsonOutput.Load()
+}
+
+// formatWriter returns what a Logger writes to w through: the console
+// writer for text, w itself for JSON (zerolog's native output).
+func formatWriter(w io.Writer) io.Writer {
+	if JSONOutput() {
+		return w
+	}
+	return zerolog.ConsoleWriter{
+		Out:        w,
+		TimeFormat: "15:04:05",
+	}
+}
+
+// jsonRecord is one line of JSON log output. The field names are zerolog's,
+// so Logger and stdlib lines look alike.
+type jsonRecord struct {
+	Time       string `json:"time"`
+	Level      string `json:"level"`
+	Message    string `json:"message"`
+	Stage      string `json:"stage,omitempty"`
+	JobName    string `json:"job_name,omitempty"`
+	RunID      string `json:"run_id,omitempty"`
+	TransferID string `json:"transfer_id,omitempty"`
+}
+
+func (r jsonRecord) line() []byte {
+	r.Time = time.Now().Format(jsonTimeFormat)
+	r.Level = strings.ToLower(r.Level)
+	b, _ := json.Marshal(r) // Only strings; cannot fail
+	return append(b, '\n')
+}
+
+// PrintLine writes a log line to stdout: "[LEVEL] message [run=<id>]" for
+// text, or a JSON record carrying stage and jobName too. level is DEBUG,
+// INFO, WARN or ERROR.
+func PrintLine(level, message, stage, jobName string, c Correlation) {
+	if !JSONOutput() {
+		fmt.Printf("[%s] %s%s\n", level, message, c.Tag())
+		return
+	}
+	os.Stdout.Write(jsonRecord{
+		Level:      level,
+		Message:    message,
+		Stage:      stage,
+		JobName:    jobName,
+		RunID:      c.RunID,
+		TransferID: c.TransferID,
+	}.line())
+}
+
+// StdlibWriter returns a writer for the stdlib log package that writes to
+// w: unchanged for text, or with each line turned into a JSON record for
+// JSON.
+func StdlibWriter(w io.Writer) io.Writer {
+	if !JSONOutput() {
+		return w
+	}
+	return &jsonLineWriter{out: w}
+}
+
+// jsonLineWriter turns stdlib log lines into JSON records. The level comes
+// from a leading "[DEBUG]", "[INFO]", "[WARN]", "[ERROR]", "[CRITICAL]" or
+// "Warning:", the stage from a known prefix such as "[BATCH]", and the run
+// and transfer IDs from a trailing correlation tag.
+type jsonLineWriter struct {
+	out io.Writer
+}
+
+func (w *jsonLineWriter) Write(p []byte) (int, error) {
+	var out []byte
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		out = append(out, stdlibRecord(line).line()...)
+	}
+	if _, err := w.out.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func stdlibRecord(line string) jsonRecord {
+	line, c := ParseTag(stripStdlibTimestamp(line))
+	level, message := splitLevel(line)
+	var stage string
+	switch {
+	case message != line:
+		// Explicit level
+	case strings.HasPrefix(line, "[CRITICAL] "):
+		level, message = "ERROR", strings.TrimPrefix(line, "[CRITICAL] ")
+	case strings.HasPrefix(line, "Warning: "):
+		level = "WARN"
+	case strings.HasPrefix(line, "["):
+		if tag, _, ok := strings.Cut(line[1:], "]"); ok {
+			if info, known := knownPrefixes[tag]; known {
+				level, stage = info.level.String(), info.stage
+			}
+		}
+	}
+	return jsonRecord{Level: level, Message: message, Stage: stage, RunID: c.RunID, TransferID: c.TransferID}
+}
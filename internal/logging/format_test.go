@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &jsonLineWriter{out: &buf}
+	w.Write([]byte("2026/03/10 14:05:23 [WARN] disk nearly full [run=wing transfer=tr-1]\n"))
+	w.Write([]byte("[BATCH] 3 files queued\n"))
+	w.Write([]byte("Warning: webhook not delivered\nplain line\n"))
+
+	want := []jsonRecord{
+		{Level: "warn", Message: "disk nearly full", RunID: "wing", TransferID: "tr-1"},
+		{Level: "debug", Message: "[BATCH] 3 files queued", Stage: "BATCH"},
+		{Level: "warn", Message: "Warning: webhook not delivered"},
+		{Level: "info", Message: "plain line"},
+	}
+	sc := bufio.NewScanner(&buf)
+	for i := 0; sc.Scan(); i++ {
+		var got jsonRecord
+		if err := json.Unmarshal(sc.Bytes(), &got); err != nil {
+			t.Fatalf("line %d is not JSON: %s", i, sc.Bytes())
+		}
+		if _, err := time.Parse(time.RFC3339, got.Time); err != nil {
+			t.Errorf("line %d time = %q: %v", i, got.Time, err)
+		}
+		got.Time = ""
+		if i >= len(want) {
+			t.Fatalf("extra line %s", sc.Bytes())
+		}
+		if got != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestLoggerJSONOutput(t *testing.T) {
+	jsonOutput.Store(true)
+	defer jsonOutput.Store(false)
+
+	var buf bytes.Buffer
+	l := NewDefaultCLILogger()
+	l.SetOutput(&buf)
+	l.WithStr("run_id", "wing").Info().Str("stage", "upload").Msg("uploaded")
+
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Logger output is not JSON: %s", buf.Bytes())
+	}
+	if got["level"] != "info" || got["message"] != "uploaded" || got["run_id"] != "wing" || got["stage"] != "upload" || got["time"] == "" {
+		t.Errorf("Logger output = %v", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatText, "text": FormatText, "JSON": FormatJSON} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("logfmt"); err == nil {
+		t.Error("ParseFormat(logfmt) succeeded, want error")
+	}
+}
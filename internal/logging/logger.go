@@ -19,22 +19,17 @@ type Logger struct {
 	output   io.Writer // current output writer
 }
 
-// NewLogger creates a new logger for the specified mode.
+// NewLogger creates a new logger for the specified mode, in the format
+// set by SetFormat.
 func NewLogger(mode string, eventBus *events.EventBus) *Logger {
 	var output io.Writer
 
 	if mode == "cli" {
 		// CLI mode: Use stdout for logs (stderr reserved for progress bars)
-		output = zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: "15:04:05",
-		}
+		output = formatWriter(os.Stdout)
 	} else {
 		// GUI mode: Write to stderr for debugging
-		output = zerolog.ConsoleWriter{
-			Out:        os.Stderr,
-			TimeFormat: "15:04:05",
-		}
+		output = formatWriter(os.Stderr)
 		// In GUI mode, we could also send to event bus if needed
 	}
 
@@ -118,10 +113,7 @@ func (l *Logger) WithStr(key, value string) *Logger {
 // This is useful for redirecting logs through progress bars.
 func (l *Logger) SetOutput(w io.Writer) {
 	l.output = w
-	l.zlog = zerolog.New(zerolog.ConsoleWriter{
-		Out:        w,
-		TimeFormat: "15:04:05",
-	}).With().Timestamp().Logger()
+	l.zlog = zerolog.New(formatWriter(w)).With().Timestamp().Logger()
 }
 
 // Output returns the current output writer.
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RunLogRecord is one line of a run log.
+type RunLogRecord struct {
+	Time       time.Time `json:"time"`
+	Level      string    `json:"level"` // DEBUG, INFO, WARN or ERROR
+	RunID      string    `json:"runId"`
+	TransferID string    `json:"transferId,omitempty"`
+	Stage      string    `json:"stage,omitempty"`
+	JobName    string    `json:"jobName,omitempty"`
+	Message    string    `json:"message"`
+}
+
+// RunLog is the append-only JSON Lines log of one PUR run, kept apart from
+// the process log so concurrent runs don't interleave (`logs tail --run`).
+// A nil *RunLog discards everything.
+type RunLog struct {
+	mu    sync.Mutex
+	f     *os.File
+	runID string
+}
+
+// OpenRunLog opens (or continues, for resumed runs) the log at path.
+func OpenRunLog(path, runID string) (*RunLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create run log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log: %w", err)
+	}
+	return &RunLog{f: f, runID: runID}, nil
+}
+
+// Log appends rec, filling in the time and run ID. Write errors are dropped:
+// the run log is a diagnostic aid and must not fail the run.
+func (r *RunLog) Log(rec RunLogRecord) {
+	if r == nil {
+		return
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	rec.RunID = r.runID
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f != nil {
+		r.f.Write(append(line, '\n'))
+	}
+}
+
+// Close closes the log file.
+func (r *RunLog) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// ReadRunLog returns the records of the run log at path, skipping lines
+// that don't parse (e.g. one cut short by a crash).
+func ReadRunLog(path string) ([]RunLogRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []RunLogRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec RunLogRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
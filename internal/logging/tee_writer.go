@@ -86,7 +86,19 @@ func (tw *TeeWriter) publishLines(text string) {
 			}
 		}
 
-		tw.eventBus.PublishLog(level, line, stage, "", nil)
+		// Correlation IDs become event fields rather than message text
+		line, corr := ParseTag(line)
+		tw.eventBus.Publish(&events.LogEvent{
+			BaseEvent: events.BaseEvent{
+				EventType:  events.EventLog,
+				Time:       time.Now(),
+				RunID:      corr.RunID,
+				TransferID: corr.TransferID,
+			},
+			Level:   level,
+			Message: line,
+			Stage:   stage,
+		})
 	}
 }
 
@@ -221,6 +221,26 @@ func TestTeeWriter_StdlibTimestampStripped(t *testing.T) {
 	eb.Close()
 }
 
+func TestTeeWriter_CorrelationTag(t *testing.T) {
+	var buf bytes.Buffer
+	eb, collector := collectingEventBus(t)
+	tw := NewTeeWriter(&buf, eb)
+
+	tw.Write([]byte("2026/03/10 14:05:23 [BATCH] part 3 done [run=pur_1 transfer=tr-1]\n"))
+
+	time.Sleep(50 * time.Millisecond)
+
+	if got := collector.count(); got != 1 {
+		t.Fatalf("got %d events, want 1", got)
+	}
+	ev := collector.get(0)
+	if ev.Message != "[BATCH] part 3 done" || ev.RunID != "pur_1" || ev.TransferID != "tr-1" || ev.Stage != "BATCH" {
+		t.Errorf("event = %+v, want tag moved into RunID/TransferID", ev)
+	}
+
+	eb.Close()
+}
+
 func TestTeeWriter_NilEventBus(t *testing.T) {
 	var buf bytes.Buffer
 	tw := NewTeeWriter(&buf, nil)
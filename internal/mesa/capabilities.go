@@ -0,0 +1,72 @@
+package mesa
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Capabilities describes the rendering stack available to the GUI on the
+// running platform. The bundled Mesa DLLs are x86-64 only, and each OS uses a
+// different WebView, so what --mesa-doctor can check and recommend depends
+// on the OS and architecture.
+type Capabilities struct {
+	OS      string
+	Arch    string
+	NumCPU  int
+	WebView string // Web runtime the GUI renders in
+
+	// MesaSupported reports whether a Mesa build exists for this platform
+	// (Windows x86-64 only); MesaEmbedded whether this binary includes it.
+	MesaSupported bool
+	MesaEmbedded  bool
+
+	// SoftwareFallback names what renders the GUI when no usable GPU is present.
+	SoftwareFallback string
+}
+
+// DetectCapabilities reports the rendering capabilities of the running platform.
+func DetectCapabilities() Capabilities {
+	return capabilitiesFor(runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), HasEmbeddedDLLs())
+}
+
+func capabilitiesFor(goos, goarch string, numCPU int, embedded bool) Capabilities {
+	c := Capabilities{
+		OS:           goos,
+		Arch:         goarch,
+		NumCPU:       numCPU,
+		MesaEmbedded: embedded,
+	}
+	switch goos {
+	case "windows":
+		c.WebView = "WebView2"
+		c.MesaSupported = goarch == "amd64"
+		switch {
+		case embedded:
+			c.SoftwareFallback = "Mesa llvmpipe (embedded)"
+		case c.MesaSupported:
+			c.SoftwareFallback = "none (use the -mesa build on VMs and RDP sessions)"
+		default:
+			// No arm64 Mesa build; WebView2 falls back to Direct3D WARP
+			c.SoftwareFallback = "WebView2 WARP (Direct3D software rasterizer)"
+		}
+	case "darwin":
+		c.WebView = "WKWebView"
+		c.SoftwareFallback = "not needed (Metal is always available)"
+	default:
+		c.WebView = "WebKitGTK"
+		c.SoftwareFallback = "system Mesa (LIBGL_ALWAYS_SOFTWARE=1)"
+	}
+	return c
+}
+
+// printCapabilities prints the platform capability summary shared by the
+// Windows and non-Windows diagnostics.
+func printCapabilities(c Capabilities) {
+	fmt.Println("[PLATFORM CAPABILITIES]")
+	fmt.Printf("  Platform: %s/%s (%d logical CPUs)\n", c.OS, c.Arch, c.NumCPU)
+	fmt.Printf("  WebView: %s\n", c.WebView)
+	fmt.Printf("  Mesa build available for platform: %v\n", c.MesaSupported)
+	fmt.Printf("  Mesa embedded in this binary: %v\n", c.MesaEmbedded)
+	fmt.Printf("  Software rendering fallback: %s\n", c.SoftwareFallback)
+	fmt.Println()
+}
@@ -4,8 +4,11 @@ package mesa
 
 import "fmt"
 
-// Doctor runs Mesa diagnostics. On non-Windows platforms, Mesa is not used.
+// Doctor runs Mesa diagnostics. On non-Windows platforms, Mesa is not used;
+// only the platform capability summary is printed.
 func Doctor() {
 	fmt.Println("Mesa diagnostics are only available on Windows.")
 	fmt.Println("On this platform, Mesa software rendering is not needed.")
+	fmt.Println()
+	printCapabilities(DetectCapabilities())
 }
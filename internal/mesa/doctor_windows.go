@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 	"unsafe"
@@ -48,6 +49,7 @@ func Doctor() {
 	fmt.Println("=== SECTION 1: BUILD AND ENVIRONMENT ===")
 	fmt.Println()
 	printBuildInfo()
+	printCapabilities(DetectCapabilities())
 	printEnvironment()
 
 	// Section 2: System Configuration
@@ -241,6 +243,8 @@ func printBuildInfo() {
 	fmt.Printf("  Mesa embedded: %v\n", mesaEmbedded)
 	if mesaEmbedded {
 		fmt.Println("  Build variant: -tags mesa (Mesa DLLs embedded)")
+	} else if runtime.GOARCH != "amd64" {
+		fmt.Printf("  Build variant: %s (no Mesa build for this architecture)\n", runtime.GOARCH)
 	} else {
 		fmt.Println("  Build variant: no mesa tag (requires hardware GPU)")
 	}
@@ -325,7 +329,7 @@ func printSystemInfo() {
 	}
 
 	// Architecture
-	fmt.Printf("  Process architecture: amd64 (64-bit)\n")
+	fmt.Printf("  Process architecture: %s (64-bit)\n", runtime.GOARCH)
 
 	// Check SafeDllSearchMode (affects DLL search order)
 	safeKey, err := registry.OpenKey(
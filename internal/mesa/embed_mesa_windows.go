@@ -1,4 +1,4 @@
-//go:build windows && mesa
+//go:build windows && mesa && amd64
 
 package mesa
 
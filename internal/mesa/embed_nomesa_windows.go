@@ -1,4 +1,4 @@
-//go:build windows && !mesa
+//go:build windows && !(mesa && amd64)
 
 package mesa
 
@@ -6,6 +6,8 @@ package mesa
 // This produces a smaller binary (~25MB smaller) but requires hardware GPU/OpenGL support.
 //
 // Users on systems without GPU (VMs, RDP, etc.) should use the "-mesa" build variant.
+// The bundled DLLs are x86-64 only, so arm64 builds never embed them even with
+// -tags mesa; WebView2 falls back to WARP there.
 var embeddedDLLs = map[string][]byte{}
 
 // mesaEmbedded indicates whether Mesa DLLs are embedded in this build
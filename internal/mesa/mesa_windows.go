@@ -14,8 +14,8 @@ import (
 )
 
 // embeddedDLLs is defined in either:
-// - embed_mesa_windows.go (when built with -tags mesa) - contains actual DLL data
-// - embed_nomesa_windows.go (default) - empty map for smaller binary
+// - embed_mesa_windows.go (when built with -tags mesa for amd64) - contains actual DLL data
+// - embed_nomesa_windows.go (default, and all arm64 builds) - empty map for smaller binary
 //
 // mesaEmbedded is also defined there, indicating which build variant this is.
 
@@ -1,7 +1,13 @@
 // Package models defines data structures for the PUR application.
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // JobSpec represents a complete job specification from CSV
 type JobSpec struct {
@@ -32,9 +38,126 @@ type JobSpec struct {
 	// Optional subdirectory within each Run_* to tar instead of the full directory.
 	// When set, only the contents of Directory/TarSubpath are archived.
 	TarSubpath string `json:"tarSubpath,omitempty"`
+
+	// Processing priority within a run. Higher values are tarred, uploaded and
+	// submitted first; jobs with equal priority keep their CSV order. Default 0.
+	Priority int `json:"priority,omitempty"`
+
+	// Remote folder path under My Library (e.g. "ProjectA/Study1") that the
+	// job's inputs are uploaded into. Missing folders are created. Empty = My Library.
+	DestinationFolder string `json:"destinationFolder,omitempty"`
+
+	// Free-form key/value metadata (jobs CSV columns prefixed MetadataPrefix),
+	// written to the job description and/or custom fields at creation
+	// (job_metadata_target) so portal reports can filter by study parameters.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Command for a job continued from this one's restart files (see
+	// 'jobs continue'). Empty = the continued job reruns Command.
+	ContinuationCommand string `json:"continuationCommand,omitempty"`
+
+	// Glob patterns (matched against file names) of output files the PUR
+	// pipeline downloads into Directory once the job completes. Empty =
+	// outputs are not downloaded by the run.
+	OutputPatterns []string `json:"outputPatterns,omitempty"`
+
+	// Names of other jobs of the same run that must reach Completed on
+	// Rescale before this job is submitted. The job is created as usual
+	// and held until then; it fails if one of them fails or ends otherwise.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Array makes the spec stand for one job per index (e.g. "1-100" or
+	// "1-5,8"), with {{index}} replaced in the name, command and paths; see
+	// package jobarray. Members have Array cleared and ArrayName set to the
+	// spec's JobName and ArrayIndex to their index.
+	Array      string `json:"array,omitempty"`
+	ArrayName  string `json:"arrayName,omitempty"`
+	ArrayIndex int    `json:"arrayIndex,omitempty"`
+
+	// NoTextNormalize archives the job's text inputs as they are, whatever
+	// text_normalize says (e.g. for a deck that needs its CRLF endings).
+	NoTextNormalize bool `json:"noTextNormalize,omitempty"`
+
+	// Environment variables set on the cluster for the job (jobs CSV
+	// columns prefixed EnvPrefix). License variables go in LicenseSettings;
+	// both are sent to the platform together, so a name may not be set in
+	// both.
+	EnvVars map[string]string `json:"envVars,omitempty"`
+
+	// UseRescaleLicense runs the software on a license supplied by Rescale
+	// instead of the one named in LicenseSettings.
+	UseRescaleLicense bool `json:"useRescaleLicense,omitempty"`
+
+	// ClusterID runs the job on an existing (reserved or persistent) cluster
+	// instead of new on-demand hardware. IsLowPriority then does not apply.
+	ClusterID string `json:"clusterId,omitempty"`
+}
+
+// MetadataPrefix marks jobs CSV columns that hold JobSpec.Metadata: a
+// "meta_Mach" column sets the "Mach" key.
+const MetadataPrefix = "meta_"
+
+// MetadataKeys returns the keys of the job's metadata in sorted order.
+func (j JobSpec) MetadataKeys() []string {
+	keys := make([]string, 0, len(j.Metadata))
+	for k := range j.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvPrefix marks jobs CSV columns that hold JobSpec.EnvVars: an
+// "env_OMP_NUM_THREADS" column sets OMP_NUM_THREADS on the cluster.
+const EnvPrefix = "env_"
+
+// EnvKeys returns the names of the job's environment variables in sorted
+// order.
+func (j JobSpec) EnvKeys() []string {
+	keys := make([]string, 0, len(j.EnvVars))
+	for k := range j.EnvVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Scheduling tiers for on-demand hardware, the jobs CSV Scheduling column.
+// Economy is JobSpec.IsLowPriority.
+const (
+	// SchedulingPro starts the job as soon as hardware is available. This is
+	// the default.
+	SchedulingPro = "pro"
+
+	// SchedulingEconomy runs the job at a lower price when capacity allows,
+	// so it may wait to start.
+	SchedulingEconomy = "economy"
+)
+
+// ParseScheduling maps a Scheduling value ("pro" or "economy", also
+// "on-demand-pro" and "on-demand-economy", any case) to IsLowPriority.
+func ParseScheduling(s string) (lowPriority bool, err error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "on-demand-") {
+	case SchedulingPro:
+		return false, nil
+	case SchedulingEconomy:
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid scheduling %q: use %q or %q", s, SchedulingPro, SchedulingEconomy)
+}
+
+// Scheduling returns the job's scheduling tier.
+func (j JobSpec) Scheduling() string {
+	if j.IsLowPriority {
+		return SchedulingEconomy
+	}
+	return SchedulingPro
 }
 
-// JobState represents the state of a job in the pipeline
+// JobState represents the state of a job in the pipeline.
+// When the run directory is split into several tars (tar_split_mode),
+// TarPath and FileID hold one entry per part joined by JoinParts; a
+// part that is not uploaded yet has an empty FileID entry.
 type JobState struct {
 	Index          int
 	JobName        string
@@ -45,15 +168,103 @@ type JobState struct {
 	UploadStatus   string  // "pending", "success", "failed"
 	UploadProgress float64 // 0.0-100.0 upload percentage (transient, not persisted)
 	JobID          string
-	SubmitStatus   string // "pending", "success", "failed", "skipped"
+	SubmitStatus   string // "pending", "waiting" (for DependsOn), "success", "failed", "skipped"
 	ExtraFileIDs   string
 	ErrorMessage   string
 	LastUpdated    time.Time
+
+	// Files left out of the tar because another process held them locked
+	// (tar_locked_files=skip or snapshot), joined by JoinParts
+	SkippedFiles string
+
+	// Warnings the platform returned when the job was created (deprecated
+	// versions, license conflicts), joined by WarningSeparator
+	Warnings string
+
+	// Download of the job's outputs (JobSpec.OutputPatterns) after it
+	// completes: "" (not requested), "pending", "success", "failed" or
+	// "skipped" (the job did not complete)
+	OutputStatus string
+
+	// JobSpec.ArrayName of an array member; "" for other jobs
+	ArrayName string
+
+	// Text inputs found with CRLF line endings or a BOM while tarring
+	// (text_normalize), each with what was found, joined by JoinParts
+	NormalizedFiles string
+}
+
+// WarningSeparator joins the messages in JobState.Warnings.
+const WarningSeparator = "\n"
+
+// WarningList returns the platform warnings recorded for the job.
+func (s *JobState) WarningList() []string {
+	if s.Warnings == "" {
+		return nil
+	}
+	return strings.Split(s.Warnings, WarningSeparator)
+}
+
+// PartSeparator joins per-part values in JobState.TarPath, FileID,
+// SkippedFiles and NormalizedFiles. Use JoinParts to build these fields:
+// it escapes separators inside values, which file names may contain.
+const PartSeparator = "|"
+
+// partEscaper percent-encodes the separator and the escape character itself.
+// Only these two sequences are decoded, so values written before escaping
+// (such as Windows paths) read back unchanged.
+var (
+	partEscaper   = strings.NewReplacer("%", "%25", PartSeparator, "%7C")
+	partUnescaper = strings.NewReplacer("%25", "%", "%7C", PartSeparator)
+)
+
+// JoinParts joins values with PartSeparator, escaping any separator within
+// them. SplitParts reverses it.
+func JoinParts(values []string) string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = partEscaper.Replace(v)
+	}
+	return strings.Join(escaped, PartSeparator)
+}
+
+// SplitParts returns the values JoinParts joined; none for "".
+func SplitParts(v string) []string {
+	if v == "" {
+		return nil
+	}
+	values := strings.Split(v, PartSeparator)
+	for i, value := range values {
+		values[i] = partUnescaper.Replace(value)
+	}
+	return values
+}
+
+// TarPaths returns the job's tar paths: one for a single tar, one per part
+// for a split run directory, none before tarring.
+func (s *JobState) TarPaths() []string {
+	return SplitParts(s.TarPath)
+}
+
+// FileIDs returns the uploaded file IDs in TarPaths order.
+func (s *JobState) FileIDs() []string {
+	return SplitParts(s.FileID)
+}
+
+// SkippedFileList returns the files left out of the tar because they were locked.
+func (s *JobState) SkippedFileList() []string {
+	return SplitParts(s.SkippedFiles)
+}
+
+// NormalizedFileList returns the text inputs found with CRLF line endings or a BOM.
+func (s *JobState) NormalizedFileList() []string {
+	return SplitParts(s.NormalizedFiles)
 }
 
 // JobRequest represents a Rescale API v3 job creation request
 type JobRequest struct {
 	Name           string                 `json:"name"`
+	Description    string                 `json:"description,omitempty"`
 	JobAnalyses    []JobAnalysisRequest   `json:"jobanalyses"`
 	IsLowPriority  bool                   `json:"isLowPriority"`
 	Tags           []string               `json:"tags,omitempty"`
@@ -106,6 +317,61 @@ type JobResponse struct {
 	JobStatus JobStatusContent `json:"jobStatus"`
 	CreatedAt string           `json:"dateInserted"`
 	Owner     string           `json:"owner"`
+
+	// Warnings are returned by job creation for problems that don't stop
+	// the job, such as a deprecated analysis version
+	Warnings JobWarnings `json:"warnings,omitempty"`
+}
+
+// JobWarnings are the warning messages of a job response. The platform
+// sends them as strings or as objects with a message (and optional code);
+// both decode to "code: message" strings.
+type JobWarnings []string
+
+// UnmarshalJSON accepts a list of strings or objects, or a single one.
+func (w *JobWarnings) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		raw = []json.RawMessage{data}
+	}
+	var out JobWarnings
+	for _, r := range raw {
+		var text string
+		if err := json.Unmarshal(r, &text); err == nil {
+			if text = oneLine(text); text != "" {
+				out = append(out, text)
+			}
+			continue
+		}
+		var obj struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Detail  string `json:"detail"`
+		}
+		if err := json.Unmarshal(r, &obj); err != nil {
+			continue // null or an unknown shape
+		}
+		msg := oneLine(obj.Message)
+		if msg == "" {
+			msg = oneLine(obj.Detail)
+		}
+		if msg == "" {
+			msg = obj.Code
+		} else if obj.Code != "" {
+			msg = obj.Code + ": " + msg
+		}
+		if msg != "" {
+			out = append(out, msg)
+		}
+	}
+	*w = out
+	return nil
+}
+
+// oneLine collapses whitespace, so a message never contains
+// WarningSeparator.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 // JobStatusContent represents job status
@@ -129,6 +395,13 @@ type CoreType struct {
 	DisplayOrder int    `json:"displayOrder"`
 	IsActive     bool   `json:"isActive"`
 	Cores        []int  `json:"cores"` // Valid core counts for this hardware type
+
+	// Hardware details, as far as the platform reports them
+	Memory       float64 `json:"memory,omitempty"`        // MB of memory per core
+	Processor    string  `json:"processorInfo,omitempty"` // e.g. "Intel Xeon Platinum 8375C"
+	Interconnect string  `json:"io,omitempty"`            // e.g. "100 Gbps InfiniBand"
+	Architecture string  `json:"architecture,omitempty"`  // e.g. "x86_64", "arm64"
+	Price        float64 `json:"price,omitempty"`         // On-demand USD per core-hour; 0 = not reported
 }
 
 // Analysis represents software analysis/application available on Rescale.
@@ -151,6 +424,12 @@ type Analysis struct {
 	Thumbnail    string `json:"thumbnail,omitempty"`
 }
 
+// Project represents an organization project jobs can be assigned to
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // JobStatusEntry represents a job status update entry
 type JobStatusEntry struct {
 	Status       string `json:"status"`
@@ -263,3 +542,22 @@ func (r *JobRequest) NormalizeAutomations() {
 		}
 	}
 }
+
+// OrgJob is a job in the organization-wide job list (org admins only).
+// Tags are filled in separately from the per-job tags endpoint.
+type OrgJob struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Owner     string           `json:"owner"`
+	JobStatus JobStatusContent `json:"jobStatus"`
+	CreatedAt string           `json:"dateInserted"`
+	Tags      []string         `json:"-"`
+}
+
+// MemberStorageUsage is one organization member's storage use (org admins only).
+type MemberStorageUsage struct {
+	Email     string `json:"email"`
+	FullName  string `json:"fullName"`
+	UsedBytes int64  `json:"storageUsed"`
+	FileCount int    `json:"fileCount"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -132,3 +133,46 @@ func TestJobAutomationRequest_NestedAutomationFormat(t *testing.T) {
 		t.Errorf("expected nested automation format, got: %s", s)
 	}
 }
+
+func TestJobResponse_Warnings(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"absent", `{"id":"abc"}`, nil},
+		{"strings", `{"id":"abc","warnings":["Version 2021 is deprecated","  "]}`, []string{"Version 2021 is deprecated"}},
+		{"objects", `{"id":"abc","warnings":[{"code":"license_conflict","message":"License server\nbusy"},{"detail":"Core type retiring"}]}`,
+			[]string{"license_conflict: License server busy", "Core type retiring"}},
+		{"single", `{"id":"abc","warnings":"Walltime capped"}`, []string{"Walltime capped"}},
+		{"null", `{"id":"abc","warnings":null}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp JobResponse
+			if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(resp.Warnings, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Warnings = %q, want %q", resp.Warnings, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinParts_RoundTrip(t *testing.T) {
+	values := []string{`C:\runs\a|b.tar`, "50%.tar", "", "%7C literal"}
+	joined := JoinParts(values)
+	if got := SplitParts(joined); !reflect.DeepEqual(got, values) {
+		t.Errorf("SplitParts(JoinParts(%q)) = %q", values, got)
+	}
+
+	// Values written before escaping read back unchanged
+	legacy := `C:\runs\a.tar|C:\runs\b.tar`
+	if got := SplitParts(legacy); !reflect.DeepEqual(got, []string{`C:\runs\a.tar`, `C:\runs\b.tar`}) {
+		t.Errorf("SplitParts(%q) = %q", legacy, got)
+	}
+	if got := SplitParts(""); got != nil {
+		t.Errorf("SplitParts(\"\") = %q, want nil", got)
+	}
+}
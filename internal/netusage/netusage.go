@@ -0,0 +1,228 @@
+// Package netusage tracks the network data this machine sends and receives
+// through Interlink, per run and per day, for sites with monthly egress
+// budgets (`rescale-int history usage`, the GUI footer usage widget).
+//
+// Bytes are counted on the wire by the shared HTTP transport (see
+// http.NetworkBytes): file contents plus API calls, TLS and retried
+// requests, to S3, Azure and the Rescale API alike.
+//
+// The log is an append-only JSON Lines file (see config.GetNetworkUsagePath)
+// shared by the CLI and the GUI. A Recorder appends KindInterval entries with
+// the bytes moved since its last flush; daily and monthly totals are sums of
+// those. Each PUR run also appends one KindRun entry with its own totals,
+// which overlap the intervals and are not summed into days.
+package netusage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/cloud"
+	"github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+)
+
+// Entry kinds.
+const (
+	// KindInterval records the bytes a process moved since its last flush.
+	KindInterval = "interval"
+
+	// KindRun records the bytes moved during one PUR run.
+	KindRun = "run"
+)
+
+// Usage is a count of bytes sent (uploads, egress) and received (downloads).
+type Usage struct {
+	Sent     int64 `json:"sent"`
+	Received int64 `json:"received"`
+}
+
+// Add returns u plus o.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{Sent: u.Sent + o.Sent, Received: u.Received + o.Received}
+}
+
+// Sub returns u minus o.
+func (u Usage) Sub(o Usage) Usage {
+	return Usage{Sent: u.Sent - o.Sent, Received: u.Received - o.Received}
+}
+
+// Total returns the bytes sent and received together.
+func (u Usage) Total() int64 {
+	return u.Sent + u.Received
+}
+
+// String formats u for log lines and terminal output, e.g.
+// "1.2 GB sent, 35.0 MB received".
+func (u Usage) String() string {
+	return fmt.Sprintf("%s sent, %s received", cloud.FormatBytes(u.Sent), cloud.FormatBytes(u.Received))
+}
+
+// Current returns the bytes this process has sent and received since it
+// started.
+func Current() Usage {
+	sent, received := http.NetworkBytes()
+	return Usage{Sent: sent, Received: received}
+}
+
+// Entry is one line of the usage log.
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Kind  string    `json:"kind"`
+	RunID string    `json:"runId,omitempty"` // KindRun only
+	Usage
+}
+
+// Append adds e to the log at path, creating the file and its directory if
+// needed.
+func Append(path string, e Entry) error {
+	return jsonl.Append(path, "network usage log", e)
+}
+
+// RecordRun appends the usage of a finished run to the log at path. Runs
+// that moved nothing are not recorded.
+func RecordRun(path, runID string, u Usage) error {
+	if u.Total() == 0 {
+		return nil
+	}
+	return Append(path, Entry{Time: time.Now(), Kind: KindRun, RunID: runID, Usage: u})
+}
+
+// Load returns the entries at path recorded at or after since, oldest first.
+// A missing file yields no entries; malformed lines (e.g. an interrupted
+// write) are skipped.
+func Load(path string, since time.Time) ([]Entry, error) {
+	var entries []Entry
+	err := jsonl.Read(path, "network usage log", func(e Entry) {
+		if e.Kind != "" && !e.Time.Before(since) {
+			entries = append(entries, e)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// Day is the usage of one local calendar day.
+type Day struct {
+	Date string `json:"date"` // YYYY-MM-DD, local time
+	Usage
+}
+
+// Daily sums the interval entries per local day, oldest first. Days without
+// traffic are omitted.
+func Daily(entries []Entry) []Day {
+	var days []Day
+	index := make(map[string]int)
+	for _, e := range entries {
+		if e.Kind != KindInterval {
+			continue
+		}
+		date := e.Time.Local().Format("2006-01-02")
+		i, ok := index[date]
+		if !ok {
+			i = len(days)
+			index[date] = i
+			days = append(days, Day{Date: date})
+		}
+		days[i].Usage = days[i].Usage.Add(e.Usage)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
+
+// Sum totals the interval entries recorded at or after since.
+func Sum(entries []Entry, since time.Time) Usage {
+	var total Usage
+	for _, e := range entries {
+		if e.Kind == KindInterval && !e.Time.Before(since) {
+			total = total.Add(e.Usage)
+		}
+	}
+	return total
+}
+
+// Runs returns the run entries, newest first.
+func Runs(entries []Entry) []Entry {
+	var runs []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Kind == KindRun {
+			runs = append(runs, entries[i])
+		}
+	}
+	return runs
+}
+
+// StartOfDay returns local midnight on t's day.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// StartOfMonth returns local midnight on the first of t's month.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Local().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+}
+
+// Recorder appends this process's traffic to the usage log as interval
+// entries.
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	recorded Usage // Current() at the last successful flush
+}
+
+// NewRecorder returns a Recorder for the log at path. Its first flush
+// records everything the process has moved since it started.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Flush appends the bytes moved since the last flush, if any.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := Current()
+	delta := now.Sub(r.recorded)
+	if delta.Total() <= 0 {
+		return nil
+	}
+	if err := Append(r.path, Entry{Time: time.Now(), Kind: KindInterval, Usage: delta}); err != nil {
+		return err
+	}
+	r.recorded = now
+	return nil
+}
+
+// Run flushes every interval until ctx is done, then flushes once more.
+// Flush errors are logged once rather than on every tick.
+func (r *Recorder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var warned bool
+	flush := func() {
+		if err := r.Flush(); err != nil && !warned {
+			log.Printf("Warning: network usage not recorded: %v", err)
+			warned = true
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
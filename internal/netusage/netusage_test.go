@@ -0,0 +1,80 @@
+package netusage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDailyAndSum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.jsonl")
+	day1 := time.Date(2026, 3, 30, 10, 0, 0, 0, time.Local)
+	day2 := time.Date(2026, 4, 1, 9, 0, 0, 0, time.Local)
+
+	for _, e := range []Entry{
+		{Time: day1, Kind: KindInterval, Usage: Usage{Sent: 100, Received: 10}},
+		{Time: day1.Add(time.Hour), Kind: KindInterval, Usage: Usage{Sent: 50}},
+		{Time: day1.Add(2 * time.Hour), Kind: KindRun, RunID: "run1", Usage: Usage{Sent: 150}},
+		{Time: day2, Kind: KindInterval, Usage: Usage{Received: 7}},
+	} {
+		if err := Append(path, e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	// An interrupted write is skipped
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	f.WriteString(`{"time":"2026-04`)
+	f.Close()
+
+	entries, err := Load(path, time.Time{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Load returned %d entries, want 4", len(entries))
+	}
+
+	days := Daily(entries)
+	want := []Day{
+		{Date: "2026-03-30", Usage: Usage{Sent: 150, Received: 10}},
+		{Date: "2026-04-01", Usage: Usage{Received: 7}},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("Daily = %+v, want %+v", days, want)
+	}
+	for i := range want {
+		if days[i] != want[i] {
+			t.Errorf("Daily[%d] = %+v, want %+v", i, days[i], want[i])
+		}
+	}
+
+	if got := Sum(entries, StartOfMonth(day2)); got != (Usage{Received: 7}) {
+		t.Errorf("Sum(April) = %+v, want 7 received", got)
+	}
+	if runs := Runs(entries); len(runs) != 1 || runs[0].RunID != "run1" || runs[0].Sent != 150 {
+		t.Errorf("Runs = %+v, want run1 with 150 sent", runs)
+	}
+}
+
+func TestRecorderFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.jsonl")
+	r := NewRecorder(path)
+	r.recorded = Current().Sub(Usage{Sent: 40, Received: 2})
+
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	// Nothing moved since, so the second flush appends nothing
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	entries, err := Load(path, time.Time{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Kind != KindInterval || entries[0].Usage != (Usage{Sent: 40, Received: 2}) {
+		t.Errorf("entries = %+v, want one interval of 40 sent, 2 received", entries)
+	}
+}
@@ -0,0 +1,125 @@
+// Package netwatch detects changes of the machine's default network route,
+// such as switching from Wi-Fi to a VPN, docking, or losing connectivity.
+// Transfers use it to drop connections bound to the old route and to pause
+// the transfer queue while no route exists.
+package netwatch
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// Probe targets for finding the default route. Connecting a UDP socket sends
+// no packets; it only asks the OS which local address it would route from.
+// Documentation-range addresses (RFC 5737 / RFC 3849) are never contacted.
+const (
+	probeTargetV4 = "198.51.100.1:9"
+	probeTargetV6 = "[2001:db8::1]:9"
+)
+
+// Change describes a default route change. Old and New are route
+// fingerprints ("interface/address"); an empty fingerprint means offline.
+type Change struct {
+	Old string
+	New string
+}
+
+// Online reports whether a route exists after the change.
+func (c Change) Online() bool {
+	return c.New != ""
+}
+
+// Probe returns the current route fingerprint, or "" when offline.
+type Probe func() string
+
+// DefaultRoute returns "interface/address" for the local address the OS
+// would use to reach the internet, or "" if there is no default route.
+func DefaultRoute() string {
+	for _, target := range []string{probeTargetV4, probeTargetV6} {
+		network := "udp4"
+		if target == probeTargetV6 {
+			network = "udp6"
+		}
+		conn, err := net.Dial(network, target)
+		if err != nil {
+			continue
+		}
+		addr, ok := conn.LocalAddr().(*net.UDPAddr)
+		conn.Close()
+		if !ok || addr.IP.IsUnspecified() {
+			continue
+		}
+		return interfaceName(addr.IP) + "/" + addr.IP.String()
+	}
+	return ""
+}
+
+// interfaceName returns the name of the interface holding ip, or "?".
+func interfaceName(ip net.IP) string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "?"
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return iface.Name
+			}
+		}
+	}
+	return "?"
+}
+
+// Watcher polls the route fingerprint and reports changes.
+type Watcher struct {
+	interval time.Duration
+	probe    Probe
+	onChange func(Change)
+}
+
+// New creates a watcher using DefaultRoute. interval <= 0 uses
+// constants.NetworkProbeInterval. onChange runs on the watcher goroutine.
+func New(interval time.Duration, onChange func(Change)) *Watcher {
+	return NewWithProbe(interval, DefaultRoute, onChange)
+}
+
+// NewWithProbe creates a watcher with a custom probe (used by tests).
+func NewWithProbe(interval time.Duration, probe Probe, onChange func(Change)) *Watcher {
+	if interval <= 0 {
+		interval = constants.NetworkProbeInterval
+	}
+	return &Watcher{interval: interval, probe: probe, onChange: onChange}
+}
+
+// Run polls until ctx is cancelled. The first probe sets the baseline and is
+// not reported.
+func (w *Watcher) Run(ctx context.Context) {
+	current := w.probe()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			next := w.probe()
+			if next == current {
+				continue
+			}
+			change := Change{Old: current, New: next}
+			current = next
+			if w.onChange != nil {
+				w.onChange(change)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package netwatch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcher_ReportsChanges(t *testing.T) {
+	routes := []string{"en0/192.168.1.5", "en0/192.168.1.5", "", "utun3/10.8.0.2", "utun3/10.8.0.2"}
+	var mu sync.Mutex
+	i := 0
+	probe := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		r := routes[len(routes)-1]
+		if i < len(routes) {
+			r = routes[i]
+			i++
+		}
+		return r
+	}
+
+	changes := make(chan Change, 10)
+	w := NewWithProbe(time.Millisecond, probe, func(c Change) { changes <- c })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	want := []Change{
+		{Old: "en0/192.168.1.5", New: ""},
+		{Old: "", New: "utun3/10.8.0.2"},
+	}
+	for _, wc := range want {
+		select {
+		case c := <-changes:
+			if c != wc {
+				t.Fatalf("change = %+v, want %+v", c, wc)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %+v", wc)
+		}
+	}
+	if (Change{New: ""}).Online() || !(Change{New: "x"}).Online() {
+		t.Error("Online() wrong")
+	}
+
+	select {
+	case c := <-changes:
+		t.Errorf("unexpected change %+v", c)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDefaultRoute_DoesNotPanic(t *testing.T) {
+	// Result depends on the test machine; only check the format when online.
+	if r := DefaultRoute(); r != "" {
+		t.Logf("default route: %s", r)
+	}
+}
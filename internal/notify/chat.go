@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+)
+
+// Chat message types, as named in chat_events.
+const (
+	ChatRunStarted   = "run_started"
+	ChatRunCompleted = "run_completed"
+	ChatJobFailed    = "job_failed"
+)
+
+// ChatEvents are the chat message types, all sent when chat_events is empty.
+var ChatEvents = []string{ChatRunStarted, ChatRunCompleted, ChatJobFailed}
+
+// DefaultChatTemplates is the text of each chat message type when its
+// chat_template_* setting is empty. Templates use text/template syntax with
+// a ChatTemplateData.
+var DefaultChatTemplates = map[string]string{
+	ChatRunStarted: "PUR run {{.RunID}} started on {{.Host}} with {{.TotalJobs}} job(s)",
+	ChatRunCompleted: "PUR run {{.RunID}} on {{.Host}} finished in {{.Duration}}: " +
+		"{{.SuccessJobs}} of {{.TotalJobs}} job(s) succeeded{{if .FailedJobs}}, {{.FailedJobs}} failed{{end}}" +
+		"{{if .ReportPath}}. Report: {{.ReportPath}}{{end}}",
+	ChatJobFailed: "PUR job {{.JobName}} failed at {{.Stage}} in run {{.RunID}} on {{.Host}}" +
+		"{{if .Error}}: {{.Error}}{{end}}",
+}
+
+// ChatTemplateData is what a chat template is executed with. Fields that do
+// not apply to a message type are zero.
+type ChatTemplateData struct {
+	Event string // run_started, run_completed or job_failed
+	RunID string
+	Host  string // Machine the run is on
+
+	// Run counts; TotalJobs is also set for run_started
+	TotalJobs   int
+	SuccessJobs int
+	FailedJobs  int
+	Duration    string // e.g. "1h2m3s"
+	ReportPath  string // HTML run report, empty if none was written
+
+	// The failed job (job_failed only)
+	JobName string
+	JobID   string
+	Stage   string
+	Error   string
+}
+
+// ParseChatEvents parses a chat_events list (semicolon or comma separated).
+// An empty list yields ChatEvents.
+func ParseChatEvents(list []string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, item := range list {
+		for _, name := range strings.FieldsFunc(item, func(r rune) bool { return r == ';' || r == ',' }) {
+			n := strings.ToLower(strings.TrimSpace(name))
+			if n == "" || seen[n] {
+				continue
+			}
+			if _, ok := DefaultChatTemplates[n]; !ok {
+				return nil, fmt.Errorf("chat_events: unknown event %q (expected run_started, run_completed or job_failed)", name)
+			}
+			seen[n] = true
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return ChatEvents, nil
+	}
+	return names, nil
+}
+
+// ParseChatTemplate parses the template for a chat message type, or its
+// default when text is empty, and checks that it executes against sample
+// data so a typo in a field name is reported when the setting is saved
+// rather than when a run finishes.
+func ParseChatTemplate(event, text string) (*template.Template, error) {
+	def, ok := DefaultChatTemplates[event]
+	if !ok {
+		return nil, fmt.Errorf("unknown chat event %q", event)
+	}
+	if strings.TrimSpace(text) == "" {
+		text = def
+	}
+	tmpl, err := template.New(event).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("chat_template_%s: %w", event, err)
+	}
+	sample := ChatTemplateData{
+		Event: event, RunID: "run", Host: "host",
+		TotalJobs: 2, SuccessJobs: 1, FailedJobs: 1, Duration: "1m0s", ReportPath: "report.html",
+		JobName: "job", JobID: "abc123", Stage: "submit", Error: "error",
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+		return nil, fmt.Errorf("chat_template_%s: %w", event, err)
+	}
+	return tmpl, nil
+}
+
+// chat renders events as Slack and Teams messages.
+type chat struct {
+	slackURL  string
+	teamsURL  string
+	templates map[string]*template.Template // Only the enabled message types
+	host      string
+}
+
+// newChat returns the chat settings in cfg, or nil when neither Slack nor
+// Teams is configured.
+func newChat(cfg *config.Config) (*chat, error) {
+	if cfg.SlackWebhookURL == "" && cfg.TeamsWebhookURL == "" {
+		return nil, nil
+	}
+	for _, u := range []string{cfg.SlackWebhookURL, cfg.TeamsWebhookURL} {
+		if u == "" {
+			continue
+		}
+		if err := ValidateURL(u); err != nil {
+			return nil, err
+		}
+	}
+	names, err := ParseChatEvents(cfg.ChatEvents)
+	if err != nil {
+		return nil, err
+	}
+	texts := map[string]string{
+		ChatRunStarted:   cfg.ChatTemplateRunStarted,
+		ChatRunCompleted: cfg.ChatTemplateRunCompleted,
+		ChatJobFailed:    cfg.ChatTemplateJobFailed,
+	}
+	c := &chat{
+		slackURL:  cfg.SlackWebhookURL,
+		teamsURL:  cfg.TeamsWebhookURL,
+		templates: make(map[string]*template.Template),
+	}
+	for _, name := range names {
+		if c.templates[name], err = ParseChatTemplate(name, texts[name]); err != nil {
+			return nil, err
+		}
+	}
+	c.host, _ = os.Hostname()
+	return c, nil
+}
+
+// wants reports whether ev becomes a chat message.
+func (c *chat) wants(ev events.Event) bool {
+	_, ok := c.data(ev)
+	return ok
+}
+
+// data maps ev to an enabled chat message type and its template data.
+func (c *chat) data(ev events.Event) (ChatTemplateData, bool) {
+	d := ChatTemplateData{Host: c.host}
+	switch e := ev.(type) {
+	case *events.RunStartedEvent:
+		d.Event, d.RunID, d.TotalJobs = ChatRunStarted, e.RunID, e.TotalJobs
+	case *events.CompleteEvent:
+		d.Event, d.RunID = ChatRunCompleted, e.RunID
+		d.TotalJobs, d.SuccessJobs, d.FailedJobs = e.TotalJobs, e.SuccessJobs, e.FailedJobs
+		d.Duration = e.Duration.Round(time.Second).String()
+		d.ReportPath = e.ReportPath
+	case *events.StateChangeEvent:
+		if e.NewStatus != "failed" {
+			return d, false
+		}
+		d.Event, d.RunID = ChatJobFailed, e.RunID
+		d.JobName, d.JobID, d.Stage, d.Error = e.JobName, e.JobID, e.Stage, e.ErrorMessage
+	default:
+		return d, false
+	}
+	_, ok := c.templates[d.Event]
+	return d, ok
+}
+
+// messages returns the request body for each chat webhook for ev, keyed by
+// URL, or nil when ev is not a chat message.
+func (c *chat) messages(ev events.Event) (map[string][]byte, error) {
+	d, ok := c.data(ev)
+	if !ok {
+		return nil, nil
+	}
+	var text bytes.Buffer
+	if err := c.templates[d.Event].Execute(&text, d); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte)
+	if c.slackURL != "" {
+		body, err := json.Marshal(map[string]string{"text": text.String()})
+		if err != nil {
+			return nil, err
+		}
+		out[c.slackURL] = body
+	}
+	if c.teamsURL != "" {
+		body, err := json.Marshal(teamsMessage(text.String()))
+		if err != nil {
+			return nil, err
+		}
+		out[c.teamsURL] = body
+	}
+	return out, nil
+}
+
+// teamsMessage wraps text in an Adaptive Card, the payload both Teams
+// incoming webhooks and Workflows (Power Automate) webhooks accept.
+func teamsMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]interface{}{{
+					"type": "TextBlock",
+					"text": text,
+					"wrap": true,
+				}},
+			},
+		}},
+	}
+}
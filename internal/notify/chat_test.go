@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+)
+
+func TestNotifier_SendsChatMessages(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string][][]byte)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("X-Interlink-Event") != "" {
+			t.Errorf("chat request to %s carries X-Interlink headers", r.URL.Path)
+		}
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], body)
+	}))
+	defer server.Close()
+
+	n, err := FromConfig(&config.Config{
+		SlackWebhookURL:       server.URL + "/slack",
+		TeamsWebhookURL:       server.URL + "/teams",
+		ChatEvents:            []string{"run_started", "job_failed"},
+		ChatTemplateJobFailed: "{{.JobName}} failed: {{.Error}}",
+	})
+	if err != nil || n == nil {
+		t.Fatalf("FromConfig() = %v, %v; want a notifier", n, err)
+	}
+	n.chat.host = "station1"
+	bus := events.NewEventBus(100)
+	n.Attach(bus)
+
+	now := time.Now()
+	bus.Publish(&events.RunStartedEvent{
+		BaseEvent: events.BaseEvent{EventType: events.EventRunStarted, Time: now, RunID: "run1"},
+		TotalJobs: 3,
+	})
+	bus.Publish(&events.StateChangeEvent{
+		BaseEvent:    events.BaseEvent{EventType: events.EventStateChange, Time: now, RunID: "run1"},
+		JobName:      "job1",
+		Stage:        "submit",
+		NewStatus:    "failed",
+		ErrorMessage: "quota exceeded",
+	})
+	bus.Publish(&events.CompleteEvent{ // run_completed not selected
+		BaseEvent: events.BaseEvent{EventType: events.EventComplete, Time: now, RunID: "run1"},
+		TotalJobs: 3,
+	})
+	n.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	slack := bodies["/slack"]
+	if len(slack) != 2 {
+		t.Fatalf("got %d Slack messages, want 2", len(slack))
+	}
+	wantText := []string{"PUR run run1 started on station1 with 3 job(s)", "job1 failed: quota exceeded"}
+	for i, body := range slack {
+		var msg struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil || msg.Text != wantText[i] {
+			t.Errorf("Slack message %d = %s, want text %q", i, body, wantText[i])
+		}
+	}
+
+	teams := bodies["/teams"]
+	if len(teams) != 2 {
+		t.Fatalf("got %d Teams messages, want 2", len(teams))
+	}
+	var card struct {
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(teams[1], &card); err != nil || len(card.Attachments) != 1 || len(card.Attachments[0].Content.Body) != 1 {
+		t.Fatalf("Teams message = %s, want one Adaptive Card", teams[1])
+	}
+	if got := card.Attachments[0].Content.Body[0].Text; got != wantText[1] {
+		t.Errorf("Teams card text = %q, want %q", got, wantText[1])
+	}
+}
+
+func TestParseChatTemplate(t *testing.T) {
+	for _, event := range ChatEvents {
+		if _, err := ParseChatTemplate(event, ""); err != nil {
+			t.Errorf("default %s template: %v", event, err)
+		}
+	}
+	if _, err := ParseChatTemplate(ChatJobFailed, "{{.JobNmae}} failed"); err == nil {
+		t.Error("ParseChatTemplate() with an unknown field succeeded, want error")
+	}
+	if _, err := ParseChatEvents([]string{"run_started;complete"}); err == nil {
+		t.Error("ParseChatEvents(complete) succeeded, want error")
+	}
+}
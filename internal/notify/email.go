@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+)
+
+const (
+	// DefaultSMTPPort is the submission port, used when smtp_port is 0.
+	DefaultSMTPPort = 587
+
+	// smtpTLSPort is the port on which the connection is TLS from the start
+	// (SMTPS) rather than upgraded with STARTTLS.
+	smtpTLSPort = 465
+
+	// smtpTimeout bounds one delivery attempt, from dialing to QUIT.
+	smtpTimeout = 30 * time.Second
+)
+
+// ParseEmailAddresses parses an email_to list (semicolon or comma
+// separated) into bare addresses.
+func ParseEmailAddresses(list []string) ([]string, error) {
+	var out []string
+	for _, item := range list {
+		for _, raw := range strings.FieldsFunc(item, func(r rune) bool { return r == ';' || r == ',' }) {
+			if raw = strings.TrimSpace(raw); raw == "" {
+				continue
+			}
+			addr, err := mail.ParseAddress(raw)
+			if err != nil {
+				return nil, fmt.Errorf("email_to: invalid address %q", raw)
+			}
+			out = append(out, addr.Address)
+		}
+	}
+	return out, nil
+}
+
+// ValidateEmail checks the SMTP and email settings in cfg without
+// connecting to the server.
+func ValidateEmail(cfg *config.Config) error {
+	_, err := newEmail(cfg)
+	return err
+}
+
+// email sends a summary of each completed PUR run through an SMTP server.
+type email struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     *mail.Address
+	to       []string
+	hostname string // Machine the run is on
+}
+
+// newEmail returns the email settings in cfg, or nil when no SMTP server or
+// recipients are configured.
+func newEmail(cfg *config.Config) (*email, error) {
+	host := strings.TrimSpace(cfg.SMTPHost)
+	if host == "" || len(cfg.EmailTo) == 0 {
+		return nil, nil
+	}
+	to, err := ParseEmailAddresses(cfg.EmailTo)
+	if err != nil {
+		return nil, err
+	}
+	if len(to) == 0 {
+		return nil, nil
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	if port < 0 || port > 65535 {
+		return nil, fmt.Errorf("smtp_port: %d is not a valid port", port)
+	}
+	sender := cfg.EmailFrom
+	if sender == "" {
+		sender = cfg.SMTPUsername
+	}
+	from, err := mail.ParseAddress(sender)
+	if err != nil {
+		return nil, fmt.Errorf("email_from: %q is not an email address (set email_from when smtp_username is not one)", sender)
+	}
+	e := &email{
+		host:     host,
+		port:     port,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     from,
+		to:       to,
+	}
+	e.hostname, _ = os.Hostname()
+	return e, nil
+}
+
+// wants reports whether ev is the end of a PUR run. Dry runs and single
+// GUI jobs have no state file and are not summarized.
+func (e *email) wants(ev events.Event) bool {
+	c, ok := ev.(*events.CompleteEvent)
+	return ok && c.StateFile != ""
+}
+
+// subject and body render the summary of a completed run.
+func (e *email) subject(c *events.CompleteEvent) string {
+	s := fmt.Sprintf("PUR run %s on %s: %d of %d job(s) succeeded", c.RunID, e.hostname, c.SuccessJobs, c.TotalJobs)
+	if c.FailedJobs > 0 {
+		s += fmt.Sprintf(", %d failed", c.FailedJobs)
+	}
+	return s
+}
+
+func (e *email) body(c *events.CompleteEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PUR run %s on %s has finished.\n\n", c.RunID, e.hostname)
+	fmt.Fprintf(&b, "Jobs:       %d\n", c.TotalJobs)
+	fmt.Fprintf(&b, "Submitted:  %d\n", c.SuccessJobs)
+	fmt.Fprintf(&b, "Failed:     %d\n", c.FailedJobs)
+	fmt.Fprintf(&b, "Duration:   %s\n", c.Duration.Round(time.Second))
+	fmt.Fprintf(&b, "State file: %s\n", c.StateFile)
+	if c.ReportPath != "" {
+		fmt.Fprintf(&b, "Report:     %s\n", c.ReportPath)
+	}
+	if len(c.Failures) > 0 {
+		b.WriteString("\nFailed jobs:\n")
+		for _, f := range c.Failures {
+			fmt.Fprintf(&b, "  %s (%s)", f.JobName, f.Stage)
+			if f.Error != "" {
+				fmt.Fprintf(&b, ": %s", strings.Join(strings.Fields(f.Error), " "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// message returns the RFC 5322 message for a completed run: plain UTF-8
+// text, quoted-printable encoded.
+func (e *email) message(c *events.CompleteEvent, now time.Time) ([]byte, error) {
+	var msg bytes.Buffer
+	header := func(key, value string) {
+		msg.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", e.from.String())
+	header("To", strings.Join(e.to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", e.subject(c)))
+	header("Date", now.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	msg.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&msg)
+	if _, err := qp.Write([]byte(e.body(c))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// send makes one delivery attempt and reports whether a failure is worth
+// retrying: network errors and 4xx replies are, 5xx replies are not.
+// STARTTLS is used whenever the server offers it; credentials are only
+// sent over TLS (or to localhost), as smtp.PlainAuth enforces.
+func (e *email) send(ctx context.Context, msg []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(e.host, strconv.Itoa(e.port))
+	tlsConfig := &tls.Config{ServerName: e.host, MinVersion: tls.VersionTLS12}
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	if e.port == smtpTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return true, err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		conn.Close()
+		return smtpRetryable(err), err
+	}
+	defer c.Close()
+
+	if e.port != smtpTLSPort {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return false, fmt.Errorf("STARTTLS: %w", err)
+			}
+		}
+	}
+	if e.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.username, e.password, e.host)); err != nil {
+			return false, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	if err := c.Mail(e.from.Address); err != nil {
+		return smtpRetryable(err), err
+	}
+	for _, rcpt := range e.to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return smtpRetryable(err), fmt.Errorf("recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpRetryable(err), err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return true, err
+	}
+	if err := w.Close(); err != nil {
+		return smtpRetryable(err), err
+	}
+	// The message is accepted; a failed QUIT does not unsend it
+	_ = c.Quit()
+	return false, nil
+}
+
+// smtpRetryable reports whether err is worth retrying: anything but a
+// permanent (5xx) SMTP reply.
+func smtpRetryable(err error) bool {
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		return reply.Code < 500
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+)
+
+// fakeSMTPServer accepts plain SMTP on localhost and sends each message's
+// DATA to the returned channel.
+func fakeSMTPServer(t *testing.T) (port int, messages <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	out := make(chan []byte, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				tp := textproto.NewConn(conn)
+				tp.PrintfLine("220 fake ESMTP")
+				for {
+					line, err := tp.ReadLine()
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(strings.Fields(line + " x")[0]) {
+					case "DATA":
+						tp.PrintfLine("354 go ahead")
+						data, err := tp.ReadDotBytes()
+						if err != nil {
+							return
+						}
+						out <- data
+						tp.PrintfLine("250 queued")
+					case "QUIT":
+						tp.PrintfLine("221 bye")
+						return
+					default: // EHLO, MAIL, RCPT
+						tp.PrintfLine("250 ok")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, out
+}
+
+func TestNotifier_EmailsRunSummary(t *testing.T) {
+	port, messages := fakeSMTPServer(t)
+	n, err := FromConfig(&config.Config{
+		SMTPHost:  "127.0.0.1",
+		SMTPPort:  port,
+		EmailFrom: "Interlink <interlink@example.com>",
+		EmailTo:   []string{"ops@example.com; lead@example.com"},
+	})
+	if err != nil || n == nil {
+		t.Fatalf("FromConfig() = %v, %v; want a notifier", n, err)
+	}
+	n.email.hostname = "station1"
+	bus := events.NewEventBus(100)
+	n.Attach(bus)
+
+	now := time.Now()
+	bus.Publish(&events.CompleteEvent{ // Dry run: no state file, no email
+		BaseEvent: events.BaseEvent{EventType: events.EventComplete, Time: now, RunID: "wing"},
+		TotalJobs: 3,
+	})
+	bus.Publish(&events.CompleteEvent{
+		BaseEvent:   events.BaseEvent{EventType: events.EventComplete, Time: now, RunID: "wing"},
+		TotalJobs:   3,
+		SuccessJobs: 2,
+		FailedJobs:  1,
+		Duration:    90 * time.Minute,
+		StateFile:   "/runs/wing.csv",
+		Failures:    []events.JobFailure{{JobName: "Run_3", Stage: "submit", Error: "quota\nexceeded"}},
+	})
+	n.Close()
+
+	var data []byte
+	select {
+	case data = <-messages:
+	default:
+		t.Fatal("no email sent")
+	}
+	if len(messages) != 0 {
+		t.Errorf("got %d extra email(s), want one per PUR run", len(messages))
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if want := "PUR run wing on station1: 2 of 3 job(s) succeeded, 1 failed"; subject != want {
+		t.Errorf("Subject = %q, want %q", subject, want)
+	}
+	if to := msg.Header.Get("To"); to != "ops@example.com, lead@example.com" {
+		t.Errorf("To = %q", to)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Submitted:  2", "Duration:   1h30m0s", "State file: /runs/wing.csv", "Run_3 (submit): quota exceeded"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestNewEmail(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		cfg     config.Config
+		wantNil bool
+		wantErr bool
+	}{
+		{"no host", config.Config{EmailTo: []string{"a@example.com"}}, true, false},
+		{"no recipients", config.Config{SMTPHost: "smtp.example.com"}, true, false},
+		{"bad recipient", config.Config{SMTPHost: "smtp.example.com", EmailTo: []string{"ops"}, EmailFrom: "a@example.com"}, false, true},
+		{"sender from username", config.Config{SMTPHost: "smtp.example.com", SMTPUsername: "a@example.com", EmailTo: []string{"b@example.com"}}, false, false},
+		{"username is not an address", config.Config{SMTPHost: "smtp.example.com", SMTPUsername: "apikey", EmailTo: []string{"b@example.com"}}, false, true},
+	} {
+		e, err := newEmail(&tc.cfg)
+		if (err != nil) != tc.wantErr || (err == nil && (e == nil) != tc.wantNil) {
+			t.Errorf("%s: newEmail() = %v, %v", tc.name, e, err)
+		}
+	}
+	e, _ := newEmail(&config.Config{SMTPHost: "smtp.example.com", EmailFrom: "a@example.com", EmailTo: []string{"b@example.com"}})
+	if e.port != DefaultSMTPPort {
+		t.Errorf("port = %d, want %d", e.port, DefaultSMTPPort)
+	}
+}
@@ -0,0 +1,455 @@
+// Package notify tells external systems about PUR runs as they happen. A
+// Notifier subscribes to an events.EventBus and POSTs job state changes,
+// errors and run completion to the webhook URLs in webhook_urls, so CI
+// pipelines, dashboards and chat bridges can react without polling.
+//
+// Each request body is one record of the NDJSON event stream (see
+// events.MarshalNDJSON), so a receiver parses webhooks and `--events` output
+// alike. Every request carries
+//
+//	X-Interlink-Event:     <event type, e.g. complete>
+//	X-Interlink-Delivery:  <random ID, the same across retries>
+//	X-Interlink-Timestamp: <unix seconds>
+//
+// and, with webhook_secret set,
+//
+//	X-Interlink-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// keyed with the secret. A failed delivery is retried with backoff; one that
+// still fails is logged and dropped, never failing the run.
+//
+// The same Notifier also posts plain-text chat messages to Slack and
+// Microsoft Teams incoming webhooks when a run starts, a run completes or a
+// job fails (see chat.go). Those carry no X-Interlink headers.
+//
+// With an SMTP server and email_to recipients configured, it also emails a
+// summary of each completed PUR run: jobs submitted and failed (with their
+// errors), duration and state file (see email.go).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/events"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+const (
+	// maxAttempts is how many times a delivery is tried before it is dropped.
+	maxAttempts = 3
+
+	// requestTimeout bounds one delivery attempt.
+	requestTimeout = 10 * time.Second
+
+	// closeTimeout bounds how long Close waits for queued deliveries, so a
+	// dead endpoint cannot hold up the end of a command or GUI shutdown.
+	closeTimeout = 15 * time.Second
+
+	// queueSize is how many events may wait for delivery before the bus
+	// starts dropping them for this subscriber.
+	queueSize = 1000
+)
+
+// DefaultEvents are the event types sent when webhook_events is empty.
+var DefaultEvents = []events.EventType{events.EventStateChange, events.EventComplete, events.EventError}
+
+// ParseEvents parses a webhook_events list (semicolon or comma separated).
+// An empty list yields DefaultEvents.
+func ParseEvents(list []string) ([]events.EventType, error) {
+	var types []events.EventType
+	seen := make(map[events.EventType]bool)
+	for _, item := range list {
+		for _, name := range strings.FieldsFunc(item, func(r rune) bool { return r == ';' || r == ',' }) {
+			t := events.EventType(strings.ToLower(strings.TrimSpace(name)))
+			if t == "" || seen[t] {
+				continue
+			}
+			if !isDefaultEvent(t) {
+				return nil, fmt.Errorf("webhook_events: unknown event %q (expected state_change, complete or error)", name)
+			}
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return DefaultEvents, nil
+	}
+	return types, nil
+}
+
+func isDefaultEvent(t events.EventType) bool {
+	for _, d := range DefaultEvents {
+		if t == d {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateURL checks that raw is an absolute http:// or https:// URL.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL %q must be an http:// or https:// URL", raw)
+	}
+	return nil
+}
+
+// Notifier delivers events from a bus to webhooks. Create one with
+// FromConfig, start it with Attach and stop it with Close.
+type Notifier struct {
+	urls       []string
+	secret     []byte
+	types      map[events.EventType]bool
+	client     *nethttp.Client
+	retryDelay time.Duration // Before the second attempt; doubles after
+	chat       *chat         // Slack/Teams messages; nil when not configured
+	email      *email        // Run summary emails; nil when not configured
+
+	ctx    context.Context // Cancelled to abandon deliveries on Close
+	cancel context.CancelFunc
+
+	bus       *events.EventBus
+	sub       <-chan events.Event
+	queue     chan events.Event
+	stop      chan struct{}
+	forwarded chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Last status delivered per run, job and stage (worker only), so repeated
+	// progress updates of the same state are not re-sent
+	lastStatus map[string]string
+}
+
+// FromConfig returns a Notifier for the webhooks, chat integrations and
+// summary email in cfg, or nil when none are configured. Webhook and chat
+// requests go through the configured proxy; email goes directly to the SMTP
+// server.
+func FromConfig(cfg *config.Config) (*Notifier, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	ch, err := newChat(cfg)
+	if err != nil {
+		return nil, err
+	}
+	em, err := newEmail(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.WebhookURLs) == 0 && ch == nil && em == nil {
+		return nil, nil
+	}
+	for _, u := range cfg.WebhookURLs {
+		if err := ValidateURL(u); err != nil {
+			return nil, err
+		}
+	}
+	types, err := ParseEvents(cfg.WebhookEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same proxy settings as API traffic, without the proxy warmup request
+	clientCfg := *cfg
+	clientCfg.ProxyWarmup = false
+	client, err := inthttp.ConfigureHTTPClient(&clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	client.Timeout = requestTimeout
+
+	n := newNotifier(cfg.WebhookURLs, cfg.WebhookSecret, types, client)
+	n.chat = ch
+	n.email = em
+	return n, nil
+}
+
+func newNotifier(urls []string, secret string, types []events.EventType, client *nethttp.Client) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		urls:       urls,
+		types:      make(map[events.EventType]bool),
+		client:     client,
+		retryDelay: time.Second,
+		ctx:        ctx,
+		cancel:     cancel,
+		queue:      make(chan events.Event, queueSize),
+		stop:       make(chan struct{}),
+		forwarded:  make(chan struct{}),
+		done:       make(chan struct{}),
+		lastStatus: make(map[string]string),
+	}
+	for _, t := range types {
+		n.types[t] = true
+	}
+	if secret != "" {
+		n.secret = []byte(secret)
+	}
+	return n
+}
+
+// Attach subscribes to bus and starts delivering its events. Call it once.
+func (n *Notifier) Attach(bus *events.EventBus) {
+	n.bus = bus
+	// One subscription to everything keeps events in the order they were
+	// published, so a run's completion never overtakes its last state change
+	n.sub = bus.SubscribeAll()
+	go n.forward()
+	go n.run()
+}
+
+// forward moves the subscribed event types to the delivery queue, so slow
+// webhooks do not back up the bus. On Close it passes on what was already
+// published, then returns.
+func (n *Notifier) forward() {
+	defer close(n.forwarded)
+	enqueue := func(ev events.Event) {
+		if n.types[ev.Type()] || (n.chat != nil && n.chat.wants(ev)) || (n.email != nil && n.email.wants(ev)) {
+			n.queue <- ev
+		}
+	}
+	for {
+		select {
+		case ev, ok := <-n.sub:
+			if !ok {
+				return
+			}
+			enqueue(ev)
+		case <-n.stop:
+			for {
+				select {
+				case ev, ok := <-n.sub:
+					if !ok {
+						return
+					}
+					enqueue(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close stops the subscription and waits for queued deliveries, abandoning
+// them after closeTimeout. Safe to call more than once.
+func (n *Notifier) Close() {
+	n.closeOnce.Do(func() {
+		if n.bus == nil {
+			n.cancel()
+			return
+		}
+		n.bus.UnsubscribeAll(n.sub)
+		close(n.stop)
+		<-n.forwarded
+		close(n.queue)
+
+		select {
+		case <-n.done:
+		case <-time.After(closeTimeout):
+			log.Printf("Warning: webhooks: gave up on %d undelivered event(s)", len(n.queue))
+			n.cancel()
+			<-n.done
+		}
+		n.cancel()
+	})
+}
+
+// run delivers queued events to every webhook, chat integration and email
+// recipient, in order.
+func (n *Notifier) run() {
+	defer close(n.done)
+	for ev := range n.queue {
+		if n.ctx.Err() != nil || !n.changed(ev) {
+			continue
+		}
+		if n.types[ev.Type()] {
+			n.sendWebhooks(ev)
+		}
+		if n.chat != nil {
+			n.sendChat(ev)
+		}
+		if n.email != nil && n.email.wants(ev) {
+			n.sendEmail(ev.(*events.CompleteEvent))
+		}
+	}
+}
+
+func (n *Notifier) sendWebhooks(ev events.Event) {
+	body, err := events.MarshalNDJSON(ev)
+	if err != nil {
+		return
+	}
+	for _, u := range n.urls {
+		if err := n.deliver(u, ev.Type(), body); err != nil {
+			log.Printf("Warning: webhook to %s not delivered: %v", redactURL(u), err)
+		}
+	}
+}
+
+func (n *Notifier) sendChat(ev events.Event) {
+	messages, err := n.chat.messages(ev)
+	if err != nil {
+		log.Printf("Warning: chat message not sent: %v", err)
+		return
+	}
+	for u, body := range messages {
+		if err := n.deliver(u, "", body); err != nil {
+			log.Printf("Warning: chat message to %s not delivered: %v", redactURL(u), err)
+		}
+	}
+}
+
+// sendEmail emails the summary of a completed run, retrying transient
+// failures with backoff like deliver.
+func (n *Notifier) sendEmail(ev *events.CompleteEvent) {
+	msg, err := n.email.message(ev, time.Now())
+	if err != nil {
+		log.Printf("Warning: run summary email not sent: %v", err)
+		return
+	}
+	delay := n.retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := n.email.send(n.ctx, msg)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == maxAttempts {
+			log.Printf("Warning: run summary email via %s not delivered: %v", n.email.host, err)
+			return
+		}
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+// changed reports whether ev should be sent: everything but a state change
+// that repeats the last status sent for its job and stage (e.g. upload
+// progress updates).
+func (n *Notifier) changed(ev events.Event) bool {
+	sc, ok := ev.(*events.StateChangeEvent)
+	if !ok {
+		return true
+	}
+	key := sc.RunID + "\x00" + sc.JobName + "\x00" + sc.Stage
+	status := sc.NewStatus + "\x00" + sc.SubStatus
+	if n.lastStatus[key] == status {
+		return false
+	}
+	n.lastStatus[key] = status
+	return true
+}
+
+// deliver POSTs body to target, retrying network errors, timeouts, 429 and
+// 5xx responses with backoff.
+func (n *Notifier) deliver(target string, eventType events.EventType, body []byte) error {
+	deliveryID := newDeliveryID()
+	delay := n.retryDelay
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(delay):
+				delay *= 2
+			case <-n.ctx.Done():
+				return n.ctx.Err()
+			}
+		}
+
+		retry, err := n.post(target, eventType, deliveryID, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying. Chat messages (eventType "") are sent without X-Interlink
+// headers.
+func (n *Notifier) post(target string, eventType events.EventType, deliveryID string, body []byte) (retry bool, err error) {
+	req, err := nethttp.NewRequestWithContext(n.ctx, nethttp.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rescale-interlink/"+version.Version)
+	if eventType != "" {
+		req.Header.Set("X-Interlink-Event", string(eventType))
+		req.Header.Set("X-Interlink-Delivery", deliveryID)
+		req.Header.Set("X-Interlink-Timestamp", timestamp)
+		if n.secret != nil {
+			req.Header.Set("X-Interlink-Signature", "sha256="+sign(n.secret, timestamp, body))
+		}
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return n.ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == nethttp.StatusTooManyRequests || resp.StatusCode == nethttp.StatusRequestTimeout || resp.StatusCode >= 500:
+		return true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+}
+
+// sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+func sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// redactURL keeps a webhook URL's scheme and host for log lines; paths and
+// queries often carry tokens.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/events"
+)
+
+type received struct {
+	event     string
+	delivery  string
+	signature string
+	timestamp string
+	body      []byte
+}
+
+func TestNotifier_DeliversSignedEventsWithRetry(t *testing.T) {
+	var mu sync.Mutex
+	var got []received
+	failures := 1 // First request fails, so the first event is retried
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, received{
+			event:     r.Header.Get("X-Interlink-Event"),
+			delivery:  r.Header.Get("X-Interlink-Delivery"),
+			signature: r.Header.Get("X-Interlink-Signature"),
+			timestamp: r.Header.Get("X-Interlink-Timestamp"),
+			body:      body,
+		})
+		if failures > 0 {
+			failures--
+			w.WriteHeader(nethttp.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	n := newNotifier([]string{server.URL}, "s3cret", DefaultEvents, server.Client())
+	n.retryDelay = time.Millisecond
+	bus := events.NewEventBus(100)
+	n.Attach(bus)
+
+	now := time.Now()
+	stateChange := func(status string) *events.StateChangeEvent {
+		return &events.StateChangeEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventStateChange, Time: now, RunID: "run1"},
+			JobName:   "job1",
+			Stage:     "upload",
+			NewStatus: status,
+		}
+	}
+	bus.Publish(stateChange("in_progress"))
+	bus.Publish(stateChange("in_progress")) // Progress update, not re-sent
+	bus.PublishLog(events.InfoLevel, "not subscribed", "tar", "job1", nil)
+	bus.Publish(stateChange("success"))
+	bus.Publish(&events.CompleteEvent{
+		BaseEvent:   events.BaseEvent{EventType: events.EventComplete, Time: now, RunID: "run1"},
+		TotalJobs:   1,
+		SuccessJobs: 1,
+	})
+	n.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 4 {
+		t.Fatalf("got %d requests, want 4 (one retry plus three events)", len(got))
+	}
+	if got[0].delivery != got[1].delivery {
+		t.Error("retry used a new delivery ID")
+	}
+	wantEvents := []string{"state_change", "state_change", "state_change", "complete"}
+	for i, r := range got {
+		if r.event != wantEvents[i] {
+			t.Errorf("request %d: X-Interlink-Event = %q, want %q", i, r.event, wantEvents[i])
+		}
+		if want := "sha256=" + sign([]byte("s3cret"), r.timestamp, r.body); r.signature != want {
+			t.Errorf("request %d: signature = %q, want %q", i, r.signature, want)
+		}
+	}
+
+	var record struct {
+		Type  string `json:"type"`
+		RunID string `json:"runId"`
+		Data  struct {
+			NewStatus string `json:"newStatus"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(got[2].body, &record); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if record.Type != "state_change" || record.RunID != "run1" || record.Data.NewStatus != "success" {
+		t.Errorf("body = %s, want the success state change of run1", got[2].body)
+	}
+}
+
+func TestNotifier_ClientErrorsAreNotRetried(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		w.WriteHeader(nethttp.StatusNotFound)
+	}))
+	defer server.Close()
+
+	n := newNotifier([]string{server.URL}, "", DefaultEvents, server.Client())
+	n.retryDelay = time.Millisecond
+	if err := n.deliver(server.URL, events.EventComplete, []byte(`{}`)); err == nil {
+		t.Error("deliver() to a 404 succeeded, want error")
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	types, err := ParseEvents(nil)
+	if err != nil || len(types) != len(DefaultEvents) {
+		t.Errorf("ParseEvents(nil) = %v, %v; want the defaults", types, err)
+	}
+	types, err = ParseEvents([]string{"Complete, error", "complete"})
+	if err != nil || len(types) != 2 || types[0] != events.EventComplete || types[1] != events.EventError {
+		t.Errorf("ParseEvents() = %v, %v; want [complete error]", types, err)
+	}
+	if _, err := ParseEvents([]string{"progress"}); err == nil {
+		t.Error("ParseEvents(progress) succeeded, want error")
+	}
+}
@@ -0,0 +1,8 @@
+package platform
+
+// OnBattery reports whether the machine is running on battery power. It
+// returns false when the power source cannot be determined (desktops,
+// servers, unsupported platforms).
+func OnBattery() bool {
+	return onBattery()
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package platform
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func onBattery() bool {
+	// First line is "Now drawing from 'AC Power'" or "... 'Battery Power'"
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), "'Battery Power'")
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const powerSupplyDir = "/sys/class/power_supply"
+
+func onBattery() bool {
+	return onBatteryFromSysfs(powerSupplyDir)
+}
+
+// onBatteryFromSysfs reads the power_supply class: on battery means no
+// online mains/USB adapter and at least one discharging battery.
+func onBatteryFromSysfs(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	discharging := false
+	for _, e := range entries {
+		supply := filepath.Join(dir, e.Name())
+		switch readSysfs(supply, "type") {
+		case "Mains", "USB", "USB_C", "USB_PD":
+			if readSysfs(supply, "online") == "1" {
+				return false
+			}
+		case "Battery":
+			if readSysfs(supply, "status") == "Discharging" {
+				discharging = true
+			}
+		}
+	}
+	return discharging
+}
+
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSupply(t *testing.T, dir, name string, attrs map[string]string) {
+	t.Helper()
+	supply := filepath.Join(dir, name)
+	if err := os.MkdirAll(supply, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range attrs {
+		if err := os.WriteFile(filepath.Join(supply, k), []byte(v+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOnBatteryFromSysfs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mains   string // "" = no adapter
+		battery string // "" = no battery
+		want    bool
+	}{
+		{"desktop without supplies", "", "", false},
+		{"laptop unplugged", "0", "Discharging", true},
+		{"laptop plugged in, charging", "1", "Charging", false},
+		{"adapter online while battery reports discharging", "1", "Discharging", false},
+		{"battery without adapter entry", "", "Discharging", true},
+		{"battery full", "0", "Full", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.mains != "" {
+				writeSupply(t, dir, "AC", map[string]string{"type": "Mains", "online": tt.mains})
+			}
+			if tt.battery != "" {
+				writeSupply(t, dir, "BAT0", map[string]string{"type": "Battery", "status": tt.battery})
+			}
+			if got := onBatteryFromSysfs(dir); got != tt.want {
+				t.Errorf("onBatteryFromSysfs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if onBatteryFromSysfs(filepath.Join(t.TempDir(), "missing")) {
+		t.Error("missing power_supply directory should not report battery")
+	}
+}
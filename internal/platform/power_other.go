@@ -0,0 +1,7 @@
+//go:build !darwin && !windows && !linux
+
+package platform
+
+func onBattery() bool {
+	return false
+}
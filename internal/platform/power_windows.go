@@ -0,0 +1,30 @@
+//go:build windows
+
+package platform
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// systemPowerStatus mirrors SYSTEM_POWER_STATUS.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+var procGetSystemPowerStatus = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+func onBattery() bool {
+	var status systemPowerStatus
+	ret, _, _ := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return false
+	}
+	return status.ACLineStatus == 0 // 0 = offline, 1 = online, 255 = unknown
+}
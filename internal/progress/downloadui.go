@@ -20,6 +20,7 @@ type DownloadUI struct {
 	isTerminal bool
 	totalFiles int
 	completed  int32
+	summary    *transferSummary // aggregate line (TTY) or periodic text summary
 }
 
 // DownloadFileBar represents a single file download progress bar
@@ -56,11 +57,20 @@ func NewDownloadUI(totalFiles int) *DownloadUI {
 		p = mpb.New(mpb.WithOutput(io.Discard))
 	}
 
-	return &DownloadUI{
+	u := &DownloadUI{
 		progress:   p,
 		isTerminal: isTerminal,
 		totalFiles: totalFiles,
 	}
+
+	// One aggregate line below the per-file bars on a TTY; periodic plain-text
+	// summaries otherwise so concurrent transfers don't produce unreadable logs.
+	if isTerminal {
+		u.summary = newTransferSummary("Downloaded", func() int { return u.totalFiles }, p, nil, 0)
+	} else {
+		u.summary = newTransferSummary("Downloaded", func() int { return u.totalFiles }, nil, os.Stdout, SummaryInterval)
+	}
+	return u
 }
 
 // AddFileBar creates a new progress bar for a file download
@@ -78,6 +88,7 @@ func (u *DownloadUI) AddFileBar(index int, fileID, remoteName, localPath string,
 		startTime:  time.Now(),
 		lastUpdate: time.Now(),
 	}
+	u.summary.fileStarted(size)
 
 	if u.isTerminal {
 		fb.bar = u.progress.New(size,
@@ -138,16 +149,19 @@ func (u *DownloadUI) AddFileBar(index int, fileID, remoteName, localPath string,
 // Uses EWMA timing for accurate speed and ETA calculations
 // Throttles updates to reduce visual noise and improve performance
 func (f *DownloadFileBar) UpdateProgress(fraction float64) {
-	if f.bar == nil {
-		return
-	}
-
 	now := time.Now()
 	elapsed := now.Sub(f.lastUpdate)
 
 	currentBytes := int64(fraction * float64(f.size))
 	bytesDelta := currentBytes - f.lastBytes
 
+	if f.bar == nil {
+		// Non-TTY: no bar to drive, but keep the aggregate summary current
+		f.ui.summary.addBytes(bytesDelta)
+		f.lastBytes = currentBytes
+		return
+	}
+
 	// THROTTLE: Update every 300ms minimum to ensure smooth ticker-driven updates
 	// The key insight: ticker calls us even when no bytes have changed (bytesDelta == 0)
 	// We MUST always call EwmaIncrBy to let MPB track time passage for speed/ETA
@@ -157,6 +171,7 @@ func (f *DownloadFileBar) UpdateProgress(fraction float64) {
 		// Always update MPB with elapsed time, even if no bytes transferred
 		// This keeps EWMA speed calculation accurate
 		f.bar.EwmaIncrBy(int(bytesDelta), elapsed)
+		f.ui.summary.addBytes(bytesDelta)
 		f.lastBytes = currentBytes
 		f.lastUpdate = now
 	}
@@ -225,11 +240,13 @@ func (f *DownloadFileBar) Complete(err error) {
 		}
 	}
 
+	f.ui.summary.fileFinished(f.size-f.lastBytes, err)
 	atomic.AddInt32(&f.ui.completed, 1)
 }
 
 // Wait blocks until all progress bars complete
 func (u *DownloadUI) Wait() {
+	u.summary.stop()
 	if u.progress != nil {
 		u.progress.Wait()
 	}
@@ -238,6 +255,7 @@ func (u *DownloadUI) Wait() {
 // WaitWithTimeout blocks until all progress bars complete or timeout expires.
 // Returns true if Wait completed normally, false if timeout occurred.
 func (u *DownloadUI) WaitWithTimeout(timeout time.Duration) bool {
+	u.summary.stop()
 	if u.progress == nil {
 		return true
 	}
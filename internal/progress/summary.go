@@ -0,0 +1,168 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vbauerster/mpb/v8"
+	"github.com/vbauerster/mpb/v8/decor"
+)
+
+// SummaryInterval is how often a plain-text aggregate summary is printed
+// when output is not attached to a terminal.
+const SummaryInterval = 30 * time.Second
+
+// transferSummary aggregates byte and file counts across all concurrent
+// transfers in a UI. In terminal mode it renders a single aggregate line
+// below the per-file bars; otherwise it prints periodic plain-text summaries
+// so that logs stay readable when many files move at once.
+type transferSummary struct {
+	verb string // "Uploaded" / "Downloaded"
+
+	totalFiles func() int
+	active     int32
+	completed  int32
+	failed     int32
+	knownBytes int64 // sum of sizes of files started so far
+	doneBytes  int64
+
+	startTime time.Time
+	bar       *mpb.Bar
+
+	out      io.Writer // non-terminal output for periodic summaries
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// newTransferSummary creates the aggregate tracker. When p is non-nil an
+// aggregate bar is added to the container; otherwise a background ticker
+// writes a summary line to w every interval.
+func newTransferSummary(verb string, totalFiles func() int, p *mpb.Progress, w io.Writer, interval time.Duration) *transferSummary {
+	s := &transferSummary{
+		verb:       verb,
+		totalFiles: totalFiles,
+		startTime:  time.Now(),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+
+	if p != nil {
+		// Dynamic-total bar with no filler: it only renders the decorator line and
+		// is kept at the bottom of the stack with the lowest priority.
+		s.bar = p.New(0, mpb.NopStyle(),
+			mpb.BarPriority(math.MaxInt32),
+			mpb.PrependDecorators(
+				decor.Any(func(decor.Statistics) string { return s.Line() }),
+			),
+		)
+		close(s.doneCh)
+		return s
+	}
+
+	if w == nil || interval <= 0 {
+		close(s.doneCh)
+		return s
+	}
+	s.out = w
+
+	go func() {
+		defer close(s.doneCh)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Only report while something is actually in flight
+				if atomic.LoadInt32(&s.active) > 0 {
+					fmt.Fprintf(w, "%s\n", s.Line())
+				}
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// fileStarted registers a new file of the given size.
+func (s *transferSummary) fileStarted(size int64) {
+	atomic.AddInt32(&s.active, 1)
+	atomic.AddInt64(&s.knownBytes, size)
+}
+
+// addBytes records transferred bytes (may be negative after a retry rewinds progress).
+func (s *transferSummary) addBytes(n int64) {
+	if n != 0 {
+		atomic.AddInt64(&s.doneBytes, n)
+	}
+}
+
+// fileFinished records completion of a file. remaining is the number of bytes
+// not yet reported via addBytes, credited only on success.
+func (s *transferSummary) fileFinished(remaining int64, err error) {
+	atomic.AddInt32(&s.active, -1)
+	if err != nil {
+		atomic.AddInt32(&s.failed, 1)
+		return
+	}
+	atomic.AddInt32(&s.completed, 1)
+	s.addBytes(remaining)
+}
+
+// Line renders the aggregate summary: files done, bytes, rate and ETA.
+func (s *transferSummary) Line() string {
+	completed := atomic.LoadInt32(&s.completed)
+	failed := atomic.LoadInt32(&s.failed)
+	active := atomic.LoadInt32(&s.active)
+	known := atomic.LoadInt64(&s.knownBytes)
+	done := atomic.LoadInt64(&s.doneBytes)
+
+	total := 0
+	if s.totalFiles != nil {
+		total = s.totalFiles()
+	}
+	if total < int(completed+failed+active) {
+		total = int(completed + failed + active)
+	}
+
+	elapsed := time.Since(s.startTime)
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(done) / elapsed.Seconds()
+	}
+
+	eta := "--"
+	if rate > 0 && known > done {
+		eta = time.Duration(float64(known-done) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+
+	line := fmt.Sprintf("%s %d/%d files, %.1f / %.1f MiB, %.1f MiB/s, ETA %s, %d active",
+		s.verb, completed, total,
+		float64(done)/(1024*1024), float64(known)/(1024*1024),
+		rate/(1024*1024), eta, active)
+	if failed > 0 {
+		line += fmt.Sprintf(", %d failed", failed)
+	}
+	return line
+}
+
+// stop finalizes the aggregate bar or stops the periodic ticker, printing a
+// final summary line in non-terminal mode. Safe to call more than once.
+func (s *transferSummary) stop() {
+	s.stopOnce.Do(func() {
+		if s.bar != nil {
+			// Mark the dynamic-total bar complete so the container can shut down
+			s.bar.SetTotal(-1, true)
+		}
+		close(s.stopCh)
+		<-s.doneCh
+		if s.out != nil && atomic.LoadInt32(&s.completed)+atomic.LoadInt32(&s.failed) > 1 {
+			fmt.Fprintf(s.out, "%s\n", s.Line())
+		}
+	})
+}
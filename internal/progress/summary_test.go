@@ -0,0 +1,78 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes from the ticker goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTransferSummary_Line(t *testing.T) {
+	s := newTransferSummary("Uploaded", func() int { return 3 }, nil, nil, 0)
+	s.fileStarted(1024 * 1024)
+	s.fileStarted(2 * 1024 * 1024)
+	s.addBytes(512 * 1024)
+	s.fileFinished(512*1024, nil)
+	s.fileFinished(0, errors.New("boom"))
+
+	line := s.Line()
+	for _, want := range []string{"Uploaded 1/3 files", "1.0 / 3.0 MiB", "0 active", "1 failed"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Line() = %q, missing %q", line, want)
+		}
+	}
+	s.stop()
+	s.stop() // idempotent
+}
+
+func TestTransferSummary_TotalNeverBelowSeen(t *testing.T) {
+	// Streaming uploads start with an unknown (zero) total
+	s := newTransferSummary("Uploaded", func() int { return 0 }, nil, nil, 0)
+	s.fileStarted(10)
+	s.fileStarted(10)
+	if line := s.Line(); !strings.Contains(line, "0/2 files") {
+		t.Errorf("Line() = %q, want total to include active files", line)
+	}
+}
+
+func TestTransferSummary_PeriodicTextOutput(t *testing.T) {
+	var out syncBuffer
+	s := newTransferSummary("Downloaded", func() int { return 2 }, nil, &out, 10*time.Millisecond)
+	s.fileStarted(100)
+	s.fileStarted(100)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "Downloaded 0/2 files") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "Downloaded 0/2 files") {
+		t.Fatalf("expected periodic summary, got %q", out.String())
+	}
+
+	s.fileFinished(100, nil)
+	s.fileFinished(100, nil)
+	s.stop()
+	if !strings.Contains(out.String(), "Downloaded 2/2 files") {
+		t.Errorf("expected final summary on stop, got %q", out.String())
+	}
+}
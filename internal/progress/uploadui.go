@@ -25,6 +25,7 @@ type UploadUI struct {
 	totalFiles int32 // atomic — streaming uploads increment as files are discovered
 	started    int32 // Atomic counter for file index (1, 2, 3, ...)
 	completed  int32
+	summary    *transferSummary // aggregate line (TTY) or periodic text summary
 }
 
 // FileBar represents a single file upload progress bar
@@ -61,11 +62,20 @@ func NewUploadUI(totalFiles int) *UploadUI {
 		p = mpb.New(mpb.WithOutput(io.Discard))
 	}
 
-	return &UploadUI{
+	u := &UploadUI{
 		progress:   p,
 		isTerminal: isTerminal,
 		totalFiles: int32(totalFiles),
 	}
+
+	// One aggregate line below the per-file bars on a TTY; periodic plain-text
+	// summaries otherwise so concurrent transfers don't produce unreadable logs.
+	if isTerminal {
+		u.summary = newTransferSummary("Uploaded", func() int { return int(atomic.LoadInt32(&u.totalFiles)) }, p, nil, 0)
+	} else {
+		u.summary = newTransferSummary("Uploaded", func() int { return int(atomic.LoadInt32(&u.totalFiles)) }, nil, os.Stdout, SummaryInterval)
+	}
+	return u
 }
 
 // IncrementTotal atomically increments the total file count.
@@ -107,6 +117,7 @@ func (u *UploadUI) AddFileBar(localPath, folderID string, size int64) *FileBar {
 		startTime:  time.Now(),
 		lastUpdate: time.Now(),
 	}
+	u.summary.fileStarted(size)
 
 	if u.isTerminal {
 		fb.bar = u.progress.New(size,
@@ -173,16 +184,19 @@ func (f *FileBar) UpdateProgress(fraction float64) {
 		return
 	}
 
-	if f.bar == nil {
-		return
-	}
-
 	now := time.Now()
 	elapsed := now.Sub(f.lastUpdate)
 
 	currentBytes := int64(fraction * float64(f.size))
 	bytesDelta := currentBytes - f.lastBytes
 
+	if f.bar == nil {
+		// Non-TTY: no bar to drive, but keep the aggregate summary current
+		f.ui.summary.addBytes(bytesDelta)
+		f.lastBytes = currentBytes
+		return
+	}
+
 	// THROTTLE: Update every 300ms minimum to ensure smooth ticker-driven updates
 	// The key insight: ticker calls us even when no bytes have changed (bytesDelta == 0)
 	// We MUST always call EwmaIncrBy to let MPB track time passage for speed/ETA
@@ -192,6 +206,7 @@ func (f *FileBar) UpdateProgress(fraction float64) {
 		// Always update MPB with elapsed time, even if no bytes transferred
 		// This keeps EWMA speed calculation accurate
 		f.bar.EwmaIncrBy(int(bytesDelta), elapsed)
+		f.ui.summary.addBytes(bytesDelta)
 		f.lastBytes = currentBytes
 		f.lastUpdate = now
 	}
@@ -260,11 +275,13 @@ func (f *FileBar) Complete(fileID string, err error) {
 		}
 	}
 
+	f.ui.summary.fileFinished(f.size-f.lastBytes, err)
 	atomic.AddInt32(&f.ui.completed, 1)
 }
 
 // Wait blocks until all progress bars complete
 func (u *UploadUI) Wait() {
+	u.summary.stop()
 	if u.progress != nil {
 		u.progress.Wait()
 	}
@@ -273,6 +290,7 @@ func (u *UploadUI) Wait() {
 // WaitWithTimeout blocks until all progress bars complete or timeout expires.
 // Returns true if Wait completed normally, false if timeout occurred.
 func (u *UploadUI) WaitWithTimeout(timeout time.Duration) bool {
+	u.summary.stop()
 	if u.progress == nil {
 		return true
 	}
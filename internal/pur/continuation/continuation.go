@@ -0,0 +1,128 @@
+// Package continuation builds jobs that continue a finished Rescale job from
+// its restart (checkpoint) files. The restart files are passed to the new job
+// by file ID, so nothing is downloaded or re-uploaded.
+package continuation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// Continuable reports whether a job in status can be continued: it must have
+// finished, whether it completed, failed or was stopped.
+func Continuable(status string) bool {
+	return watch.TerminalStatuses[status]
+}
+
+// ParsePatterns splits a comma-separated list of restart file patterns.
+func ParsePatterns(s string) []string {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// MatchFiles returns the files matching any of patterns, in their original
+// order. A pattern containing "/" is matched against the file's path relative
+// to the job's working directory; otherwise against its base name.
+func MatchFiles(files []models.JobFile, patterns []string) ([]models.JobFile, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid restart file pattern %q: %w", p, err)
+		}
+	}
+	var matched []models.JobFile
+	for _, f := range files {
+		rel := RelativePath(f)
+		for _, p := range patterns {
+			target := path.Base(rel)
+			if strings.Contains(p, "/") {
+				target = rel
+			}
+			if ok, _ := path.Match(p, target); ok {
+				matched = append(matched, f)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// RelativePath returns f's path relative to the job's working directory,
+// falling back to its name.
+func RelativePath(f models.JobFile) string {
+	if f.RelativePath != "" {
+		return strings.TrimPrefix(f.RelativePath, "/")
+	}
+	return f.Name
+}
+
+var contSuffix = regexp.MustCompile(`_cont(\d+)$`)
+
+// NextName returns the name of the job continuing one named name:
+// "run1" -> "run1_cont1", "run1_cont1" -> "run1_cont2".
+func NextName(name string) string {
+	if m := contSuffix.FindStringSubmatchIndex(name); m != nil {
+		n, _ := strconv.Atoi(name[m[2]:m[3]])
+		return name[:m[0]] + "_cont" + strconv.Itoa(n+1)
+	}
+	return name + "_cont1"
+}
+
+// Options controls how a continuation job differs from the job it continues.
+type Options struct {
+	Name       string // New job name; empty = NextName of the original
+	Command    string // Continuation command; empty = the original command
+	KeepInputs bool   // Also pass the original job's input files
+}
+
+// BuildSpec returns the spec of a job continuing base (as imported with
+// parser.RescaleJobToJobSpec) from restartFileIDs. The restart files become
+// the job's pre-uploaded InputFiles; the original inputs stay in
+// ExtraInputFileIDs when opts.KeepInputs is set.
+func BuildSpec(base models.JobSpec, restartFileIDs []string, opts Options) (models.JobSpec, error) {
+	if len(restartFileIDs) == 0 {
+		return models.JobSpec{}, fmt.Errorf("no restart files selected")
+	}
+
+	spec := base
+	spec.Directory = ""
+	spec.TarSubpath = ""
+	spec.InputFiles = append([]string(nil), restartFileIDs...)
+	spec.JobName = opts.Name
+	if spec.JobName == "" {
+		spec.JobName = NextName(base.JobName)
+	}
+	if opts.Command != "" {
+		spec.Command = opts.Command
+	}
+	if spec.Command == "" {
+		return models.JobSpec{}, fmt.Errorf("continuation job %s has no command", spec.JobName)
+	}
+
+	// Restart files already passed as InputFiles are not repeated
+	var extra []string
+	if opts.KeepInputs {
+		restart := make(map[string]bool, len(restartFileIDs))
+		for _, id := range restartFileIDs {
+			restart[id] = true
+		}
+		for _, id := range strings.Split(base.ExtraInputFileIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" && !restart[id] {
+				extra = append(extra, id)
+			}
+		}
+	}
+	spec.ExtraInputFileIDs = strings.Join(extra, ",")
+
+	return spec, nil
+}
@@ -0,0 +1,94 @@
+package continuation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestMatchFiles(t *testing.T) {
+	files := []models.JobFile{
+		{ID: "f1", Name: "case.rst", RelativePath: "case.rst"},
+		{ID: "f2", Name: "case.log", RelativePath: "case.log"},
+		{ID: "f3", Name: "state.chk", RelativePath: "restart/state.chk"},
+		{ID: "f4", Name: "old.chk", RelativePath: "archive/old.chk"},
+		{ID: "f5", Name: "nopath.rst"},
+	}
+	tests := []struct {
+		patterns string
+		want     []string
+	}{
+		{"*.rst", []string{"f1", "f5"}},
+		{"*.chk", []string{"f3", "f4"}},
+		{"restart/*", []string{"f3"}},
+		{"*.rst, restart/*.chk", []string{"f1", "f3", "f5"}},
+		{"*.dat", nil},
+	}
+	for _, tt := range tests {
+		got, err := MatchFiles(files, ParsePatterns(tt.patterns))
+		if err != nil {
+			t.Fatalf("MatchFiles(%q): %v", tt.patterns, err)
+		}
+		var ids []string
+		for _, f := range got {
+			ids = append(ids, f.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("MatchFiles(%q) = %v, want %v", tt.patterns, ids, tt.want)
+		}
+	}
+
+	if _, err := MatchFiles(files, []string{"[bad"}); err == nil {
+		t.Error("MatchFiles accepted a malformed pattern")
+	}
+}
+
+func TestNextName(t *testing.T) {
+	for name, want := range map[string]string{
+		"run1":         "run1_cont1",
+		"run1_cont1":   "run1_cont2",
+		"run1_cont9":   "run1_cont10",
+		"run1_contour": "run1_contour_cont1",
+	} {
+		if got := NextName(name); got != want {
+			t.Errorf("NextName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuildSpec(t *testing.T) {
+	base := models.JobSpec{
+		JobName:           "wing",
+		Directory:         "/data/wing",
+		Command:           "./run.sh",
+		ExtraInputFileIDs: "in1, in2,rst1",
+		SubmitMode:        "yes",
+	}
+
+	spec, err := BuildSpec(base, []string{"rst1", "rst2"}, Options{Command: "./run.sh --restart", KeepInputs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.JobName != "wing_cont1" || spec.Command != "./run.sh --restart" || spec.Directory != "" {
+		t.Errorf("spec = %+v", spec)
+	}
+	if !reflect.DeepEqual(spec.InputFiles, []string{"rst1", "rst2"}) {
+		t.Errorf("InputFiles = %v", spec.InputFiles)
+	}
+	if spec.ExtraInputFileIDs != "in1,in2" {
+		t.Errorf("ExtraInputFileIDs = %q, want the original inputs without restart files", spec.ExtraInputFileIDs)
+	}
+
+	spec, err = BuildSpec(base, []string{"rst1"}, Options{Name: "wing_restart"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.JobName != "wing_restart" || spec.Command != "./run.sh" || spec.ExtraInputFileIDs != "" {
+		t.Errorf("spec = %+v, want original command and no original inputs", spec)
+	}
+
+	if _, err := BuildSpec(base, nil, Options{}); err == nil {
+		t.Error("BuildSpec accepted no restart files")
+	}
+}
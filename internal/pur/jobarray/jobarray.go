@@ -0,0 +1,142 @@
+// Package jobarray expands array job specs: a JobSpec with Array set (e.g.
+// "1-100") stands for one job per index, with IndexPlaceholder replaced by
+// the index in its name, command and paths.
+package jobarray
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// IndexPlaceholder is replaced by a member's index.
+const IndexPlaceholder = "{{index}}"
+
+// MaxMembers caps the number of jobs one array expands into.
+const MaxMembers = 10000
+
+// ParseRange parses an array range: comma-separated indexes and ranges
+// "first-last", a range optionally followed by ":step", e.g. "1-100",
+// "0-90:10" or "1-5,8,10-12". Indexes are returned in ascending order and
+// must not repeat.
+func ParseRange(spec string) ([]int, error) {
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		step := 1
+		if r, s, ok := strings.Cut(part, ":"); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			part, step = r, n
+		}
+		first, last := part, part
+		if f, l, ok := strings.Cut(part, "-"); ok {
+			first, last = f, l
+		} else if step != 1 {
+			return nil, fmt.Errorf("a step needs a range: %q", part)
+		}
+		lo, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || lo < 0 {
+			return nil, fmt.Errorf("invalid index %q", first)
+		}
+		hi, err := strconv.Atoi(strings.TrimSpace(last))
+		if err != nil || hi < lo {
+			return nil, fmt.Errorf("invalid range %q", part)
+		}
+
+		for i := lo; i <= hi; i += step {
+			if seen[i] {
+				return nil, fmt.Errorf("index %d is listed twice", i)
+			}
+			if len(indexes) == MaxMembers {
+				return nil, fmt.Errorf("more than %d indexes", MaxMembers)
+			}
+			seen[i] = true
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no indexes in %q", spec)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// Expand replaces every array spec in jobs with its members, in index
+// order and at the array's position. A member is a copy of the spec with
+// IndexPlaceholder replaced in its name, command, paths, file IDs, output
+// patterns, DependsOn and metadata values; ArrayName (the spec's JobName as
+// written) and ArrayIndex record where it came from. A name without the
+// placeholder gets "_<index>" appended. Specs without Array are returned
+// unchanged, so expanding twice is harmless.
+func Expand(jobs []models.JobSpec) ([]models.JobSpec, error) {
+	expanded := make([]models.JobSpec, 0, len(jobs))
+	for i, job := range jobs {
+		if strings.TrimSpace(job.Array) == "" {
+			expanded = append(expanded, job)
+			continue
+		}
+		indexes, err := ParseRange(job.Array)
+		if err != nil {
+			return nil, fmt.Errorf("row %d (%s): invalid Array: %w", i+1, job.JobName, err)
+		}
+		for _, index := range indexes {
+			expanded = append(expanded, member(job, index))
+		}
+	}
+	return expanded, nil
+}
+
+// member builds the array member of job for index.
+func member(job models.JobSpec, index int) models.JobSpec {
+	n := strconv.Itoa(index)
+	sub := func(s string) string { return strings.ReplaceAll(s, IndexPlaceholder, n) }
+	subAll := func(list []string) []string {
+		if list == nil {
+			return nil
+		}
+		out := make([]string, len(list))
+		for i, s := range list {
+			out[i] = sub(s)
+		}
+		return out
+	}
+
+	m := job
+	m.Array = ""
+	m.ArrayName = job.JobName
+	m.ArrayIndex = index
+	if strings.Contains(job.JobName, IndexPlaceholder) {
+		m.JobName = sub(job.JobName)
+	} else {
+		m.JobName = job.JobName + "_" + n
+	}
+	m.Command = sub(job.Command)
+	m.ContinuationCommand = sub(job.ContinuationCommand)
+	m.Directory = sub(job.Directory)
+	m.TarSubpath = sub(job.TarSubpath)
+	m.DestinationFolder = sub(job.DestinationFolder)
+	m.ExtraInputFileIDs = sub(job.ExtraInputFileIDs)
+	m.InputFiles = subAll(job.InputFiles)
+	m.OutputPatterns = subAll(job.OutputPatterns)
+	m.DependsOn = subAll(job.DependsOn)
+	m.Tags = append([]string(nil), job.Tags...)
+	m.Automations = append([]string(nil), job.Automations...)
+	if job.Metadata != nil {
+		m.Metadata = make(map[string]string, len(job.Metadata))
+		for k, v := range job.Metadata {
+			m.Metadata[k] = sub(v)
+		}
+	}
+	return m
+}
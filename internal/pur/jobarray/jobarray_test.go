@@ -0,0 +1,86 @@
+package jobarray
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []int
+	}{
+		{"1-5", []int{1, 2, 3, 4, 5}},
+		{"0-20:10", []int{0, 10, 20}},
+		{"8, 1-3 ,10", []int{1, 2, 3, 8, 10}},
+		{"7", []int{7}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRange(tt.spec)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRange(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "5-1", "a-3", "-2", "1-3,2", "4:2", "1-5:0", "0-20000"} {
+		if _, err := ParseRange(spec); err == nil {
+			t.Errorf("ParseRange(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	jobs := []models.JobSpec{
+		{JobName: "mesh"},
+		{
+			JobName:        "case_{{index}}",
+			Array:          "1-3",
+			Directory:      "/data/case_{{index}}",
+			Command:        "run.sh --case {{index}}",
+			Tags:           []string{"sweep"},
+			OutputPatterns: []string{"case{{index}}.out"},
+			DependsOn:      []string{"mesh"},
+			Metadata:       map[string]string{"case": "{{index}}"},
+		},
+		{JobName: "post", Array: "1-2", Command: "post.sh"},
+	}
+
+	got, err := Expand(jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, j := range got {
+		names = append(names, j.JobName)
+	}
+	want := []string{"mesh", "case_1", "case_2", "case_3", "post_1", "post_2"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	m := got[2]
+	if m.Directory != "/data/case_2" || m.Command != "run.sh --case 2" || m.OutputPatterns[0] != "case2.out" ||
+		m.Metadata["case"] != "2" || m.DependsOn[0] != "mesh" {
+		t.Errorf("member 2 = %+v", m)
+	}
+	if m.Array != "" || m.ArrayName != "case_{{index}}" || m.ArrayIndex != 2 {
+		t.Errorf("member 2 array fields = %q %q %d", m.Array, m.ArrayName, m.ArrayIndex)
+	}
+	if got[4].ArrayName != "post" || got[4].Command != "post.sh" {
+		t.Errorf("post_1 = %+v", got[4])
+	}
+	if jobs[1].Metadata["case"] != "{{index}}" {
+		t.Error("expanding modified the array spec")
+	}
+
+	again, err := Expand(got)
+	if err != nil || !reflect.DeepEqual(again, got) {
+		t.Errorf("expanding the members again changed them: %v", err)
+	}
+
+	if _, err := Expand([]models.JobSpec{{JobName: "bad", Array: "3-1"}}); err == nil {
+		t.Error("Expand() accepted an invalid range")
+	}
+}
@@ -0,0 +1,187 @@
+// Package jobdiff compares two sets of job specifications (e.g. two revisions
+// of a jobs CSV) so a study revision can be reviewed before submitting it.
+package jobdiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// FieldChange is one differing field of a job present in both sets.
+// Risk is non-empty for changes worth a second look before submitting.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Risk  string `json:"risk,omitempty"`
+}
+
+// JobChange lists the field differences of one job.
+type JobChange struct {
+	Key     string        `json:"key"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// Risky reports whether any field change is flagged.
+func (c JobChange) Risky() bool {
+	for _, fc := range c.Changes {
+		if fc.Risk != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is the outcome of Compare. Jobs are listed in the order they
+// appear in the second set (Removed: in the first set).
+type Result struct {
+	Added     []string    `json:"added"`
+	Removed   []string    `json:"removed"`
+	Changed   []JobChange `json:"changed"`
+	Unchanged int         `json:"unchanged"`
+}
+
+// RiskyCount returns the number of changed jobs with at least one risky change.
+func (r Result) RiskyCount() int {
+	n := 0
+	for _, c := range r.Changed {
+		if c.Risky() {
+			n++
+		}
+	}
+	return n
+}
+
+// field reads one comparable value of a job spec. Columns follow the jobs
+// CSV order so output matches what users see in their files.
+type field struct {
+	name string
+	get  func(models.JobSpec) string
+	risk func(old, new models.JobSpec) string
+}
+
+var fields = []field{
+	{name: "Directory", get: func(j models.JobSpec) string { return j.Directory }},
+	{name: "JobName", get: func(j models.JobSpec) string { return j.JobName }},
+	{name: "AnalysisCode", get: func(j models.JobSpec) string { return j.AnalysisCode }},
+	{name: "AnalysisVersion", get: func(j models.JobSpec) string { return j.AnalysisVersion }},
+	{name: "Command", get: func(j models.JobSpec) string { return j.Command }},
+	{name: "CoreType", get: func(j models.JobSpec) string { return j.CoreType },
+		risk: func(o, n models.JobSpec) string {
+			if !strings.EqualFold(o.CoreType, n.CoreType) {
+				return "core type changed"
+			}
+			return ""
+		}},
+	{name: "CoresPerSlot", get: func(j models.JobSpec) string { return strconv.Itoa(j.CoresPerSlot) }},
+	{name: "WalltimeHours", get: func(j models.JobSpec) string { return strconv.FormatFloat(j.WalltimeHours, 'f', -1, 64) },
+		risk: func(o, n models.JobSpec) string {
+			if n.WalltimeHours < o.WalltimeHours {
+				return "walltime reduced"
+			}
+			return ""
+		}},
+	{name: "Slots", get: func(j models.JobSpec) string { return strconv.Itoa(j.Slots) }},
+	{name: "LicenseSettings", get: func(j models.JobSpec) string { return j.LicenseSettings }},
+	{name: "ExtraInputFileIDs", get: func(j models.JobSpec) string { return j.ExtraInputFileIDs }},
+	{name: "OnDemandLicenseSeller", get: func(j models.JobSpec) string { return j.OnDemandLicenseSeller }},
+	{name: "ProjectID", get: func(j models.JobSpec) string { return j.ProjectID }},
+	{name: "OrgCode", get: func(j models.JobSpec) string { return j.OrgCode }},
+	{name: "Tags", get: func(j models.JobSpec) string { return strings.Join(j.Tags, ",") }},
+	{name: "NoDecompress", get: func(j models.JobSpec) string { return strconv.FormatBool(j.NoDecompress) }},
+	{name: "IsLowPriority", get: func(j models.JobSpec) string { return strconv.FormatBool(j.IsLowPriority) }},
+	{name: "Submit", get: func(j models.JobSpec) string { return j.SubmitMode }},
+	{name: "TarSubpath", get: func(j models.JobSpec) string { return j.TarSubpath }},
+	{name: "Priority", get: func(j models.JobSpec) string { return strconv.Itoa(j.Priority) }},
+	{name: "DestinationFolder", get: func(j models.JobSpec) string { return j.DestinationFolder }},
+	{name: "Metadata", get: func(j models.JobSpec) string {
+		pairs := make([]string, 0, len(j.Metadata))
+		for _, k := range j.MetadataKeys() {
+			pairs = append(pairs, k+"="+j.Metadata[k])
+		}
+		return strings.Join(pairs, ", ")
+	}},
+	{name: "EnvVars", get: func(j models.JobSpec) string {
+		pairs := make([]string, 0, len(j.EnvVars))
+		for _, k := range j.EnvKeys() {
+			pairs = append(pairs, k+"="+j.EnvVars[k])
+		}
+		return strings.Join(pairs, ", ")
+	}},
+	{name: "UseRescaleLicense", get: func(j models.JobSpec) string { return strconv.FormatBool(j.UseRescaleLicense) }},
+	{name: "ClusterID", get: func(j models.JobSpec) string { return j.ClusterID }},
+	{name: "Automations", get: func(j models.JobSpec) string { return strings.Join(j.Automations, ",") }},
+}
+
+// jobKeys returns a matching key per job: the job name, else the directory,
+// else the row number. Repeated keys get "#2", "#3", ... in order.
+func jobKeys(jobs []models.JobSpec) []string {
+	keys := make([]string, len(jobs))
+	seen := make(map[string]int)
+	for i, j := range jobs {
+		key := j.JobName
+		if key == "" {
+			key = j.Directory
+		}
+		if key == "" {
+			key = fmt.Sprintf("row %d", i+1)
+		}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// Compare matches jobs in a and b by name (falling back to directory) and
+// reports added, removed and changed jobs.
+func Compare(a, b []models.JobSpec) Result {
+	keysA, keysB := jobKeys(a), jobKeys(b)
+	byKeyA := make(map[string]models.JobSpec, len(a))
+	for i, k := range keysA {
+		byKeyA[k] = a[i]
+	}
+	inB := make(map[string]bool, len(b))
+
+	var res Result
+	for i, k := range keysB {
+		inB[k] = true
+		oldJob, ok := byKeyA[k]
+		if !ok {
+			res.Added = append(res.Added, k)
+			continue
+		}
+		if changes := diffJob(oldJob, b[i]); len(changes) > 0 {
+			res.Changed = append(res.Changed, JobChange{Key: k, Changes: changes})
+		} else {
+			res.Unchanged++
+		}
+	}
+	for _, k := range keysA {
+		if !inB[k] {
+			res.Removed = append(res.Removed, k)
+		}
+	}
+	return res
+}
+
+func diffJob(o, n models.JobSpec) []FieldChange {
+	var changes []FieldChange
+	for _, f := range fields {
+		ov, nv := f.get(o), f.get(n)
+		if ov == nv {
+			continue
+		}
+		fc := FieldChange{Field: f.name, Old: ov, New: nv}
+		if f.risk != nil {
+			fc.Risk = f.risk(o, n)
+		}
+		changes = append(changes, fc)
+	}
+	return changes
+}
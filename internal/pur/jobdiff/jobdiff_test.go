@@ -0,0 +1,72 @@
+package jobdiff
+
+import (
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestCompare(t *testing.T) {
+	base := models.JobSpec{Directory: "Run_1", JobName: "Run_1", CoreType: "emerald", WalltimeHours: 24, Command: "./run.sh"}
+	a := []models.JobSpec{
+		base,
+		{Directory: "Run_2", JobName: "Run_2", CoreType: "emerald", WalltimeHours: 24},
+		{Directory: "Run_3", JobName: "Run_3", CoreType: "emerald", WalltimeHours: 24},
+	}
+
+	run1 := base
+	run1.Command = "./run.sh -v"
+	run2 := a[1]
+	run2.CoreType = "onyx"
+	run2.WalltimeHours = 12
+	b := []models.JobSpec{
+		run1,
+		run2,
+		{Directory: "Run_4", JobName: "Run_4"},
+	}
+
+	res := Compare(a, b)
+
+	if len(res.Added) != 1 || res.Added[0] != "Run_4" {
+		t.Errorf("Added = %v, want [Run_4]", res.Added)
+	}
+	if len(res.Removed) != 1 || res.Removed[0] != "Run_3" {
+		t.Errorf("Removed = %v, want [Run_3]", res.Removed)
+	}
+	if len(res.Changed) != 2 {
+		t.Fatalf("Changed = %+v, want 2 jobs", res.Changed)
+	}
+	if c := res.Changed[0]; c.Key != "Run_1" || len(c.Changes) != 1 || c.Changes[0].Field != "Command" || c.Risky() {
+		t.Errorf("Run_1 change = %+v, want one non-risky Command change", c)
+	}
+	c := res.Changed[1]
+	if c.Key != "Run_2" || len(c.Changes) != 2 {
+		t.Fatalf("Run_2 change = %+v", c)
+	}
+	for _, fc := range c.Changes {
+		if fc.Risk == "" {
+			t.Errorf("%s change not flagged as risky", fc.Field)
+		}
+	}
+	if res.RiskyCount() != 1 {
+		t.Errorf("RiskyCount() = %d, want 1", res.RiskyCount())
+	}
+}
+
+func TestCompare_WalltimeIncreaseNotRisky(t *testing.T) {
+	a := []models.JobSpec{{JobName: "j", WalltimeHours: 2}}
+	b := []models.JobSpec{{JobName: "j", WalltimeHours: 4}}
+	res := Compare(a, b)
+	if len(res.Changed) != 1 || res.Changed[0].Risky() {
+		t.Errorf("Changed = %+v, want one non-risky change", res.Changed)
+	}
+}
+
+func TestCompare_DuplicateAndUnnamedJobs(t *testing.T) {
+	a := []models.JobSpec{{JobName: "dup"}, {JobName: "dup"}, {Directory: "Run_9"}}
+	b := []models.JobSpec{{JobName: "dup"}, {JobName: "dup", Slots: 2}, {Directory: "Run_9"}}
+	res := Compare(a, b)
+	if res.Unchanged != 2 || len(res.Changed) != 1 || res.Changed[0].Key != "dup#2" {
+		t.Errorf("Compare() = %+v, want dup#2 changed and 2 unchanged", res)
+	}
+}
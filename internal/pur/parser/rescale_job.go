@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// jobIDPattern matches a Rescale job ID (short alphanumeric).
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9]{4,16}$`)
+
+// ParseJobRef extracts a job ID from a bare ID or a platform job URL such as
+// https://platform.rescale.com/jobs/AbCdE/ or .../jobs/AbCdE/setup/.
+func ParseJobRef(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if jobIDPattern.MatchString(ref) {
+		return ref, nil
+	}
+	if u, err := url.Parse(ref); err == nil && u.Path != "" {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		for i := 0; i+1 < len(parts); i++ {
+			if parts[i] == "jobs" && jobIDPattern.MatchString(parts[i+1]) {
+				return parts[i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("not a job ID or job URL: %q", ref)
+}
+
+// rescaleJob is the subset of a v3 job response needed to rebuild a JobSpec.
+type rescaleJob struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	IsLowPriority bool   `json:"isLowPriority"`
+	ProjectID     string `json:"projectId"`
+	ClusterID     string `json:"clusterId"`
+	JobAnalyses   []struct {
+		Command  string `json:"command"`
+		Analysis struct {
+			Code        string `json:"code"`
+			Version     string `json:"version"`
+			VersionCode string `json:"versionCode"`
+		} `json:"analysis"`
+		Hardware struct {
+			CoreType     json.RawMessage `json:"coreType"` // {"code": ...} or "code"
+			CoresPerSlot int             `json:"coresPerSlot"`
+			Slots        int             `json:"slots"`
+			Walltime     int             `json:"walltime"` // hours
+		} `json:"hardware"`
+		InputFiles []struct {
+			ID         string `json:"id"`
+			Name       string `json:"name"`
+			Decompress bool   `json:"decompress"`
+		} `json:"inputFiles"`
+		EnvVars               map[string]string `json:"envVars"`
+		UseRescaleLicense     bool              `json:"useRescaleLicense"`
+		OnDemandLicenseSeller json.RawMessage   `json:"onDemandLicenseSeller"` // {"code": ...}, "code" or null
+	} `json:"jobanalyses"`
+	JobAutomations []struct {
+		Automation struct {
+			ID string `json:"id"`
+		} `json:"automation"`
+	} `json:"jobAutomations"`
+}
+
+// codeOf reads a field the API returns either as a string or as an object
+// with a "code" key.
+func codeOf(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(raw, &obj) == nil {
+		return obj.Code
+	}
+	return ""
+}
+
+// RescaleJobToJobSpec converts a v3 job response (api.Client.GetJobRaw) into
+// a JobSpec that reproduces it. Input files are referenced by ID through
+// ExtraInputFileIDs, so the spec needs no local directory. Returns warnings
+// for parts of the job a JobSpec cannot represent.
+func RescaleJobToJobSpec(raw json.RawMessage) (models.JobSpec, []string, error) {
+	var job rescaleJob
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return models.JobSpec{}, nil, fmt.Errorf("failed to parse job: %w", err)
+	}
+	if len(job.JobAnalyses) == 0 {
+		return models.JobSpec{}, nil, fmt.Errorf("job %s has no analyses", job.ID)
+	}
+
+	var warnings []string
+	if len(job.JobAnalyses) > 1 {
+		warnings = append(warnings, fmt.Sprintf("job has %d analyses; only the first is imported", len(job.JobAnalyses)))
+	}
+	ja := job.JobAnalyses[0]
+
+	spec := models.JobSpec{
+		JobName:               job.Name,
+		AnalysisCode:          ja.Analysis.Code,
+		AnalysisVersion:       ja.Analysis.Version,
+		Command:               ja.Command,
+		CoreType:              codeOf(ja.Hardware.CoreType),
+		CoresPerSlot:          ja.Hardware.CoresPerSlot,
+		WalltimeHours:         float64(ja.Hardware.Walltime),
+		Slots:                 ja.Hardware.Slots,
+		OnDemandLicenseSeller: codeOf(ja.OnDemandLicenseSeller),
+		SubmitMode:            "yes",
+		IsLowPriority:         job.IsLowPriority,
+		ProjectID:             job.ProjectID,
+		UseRescaleLicense:     ja.UseRescaleLicense,
+		ClusterID:             job.ClusterID,
+	}
+	if spec.AnalysisVersion == "" {
+		spec.AnalysisVersion = ja.Analysis.VersionCode
+	}
+	if spec.Slots == 0 {
+		spec.Slots = 1
+	}
+
+	// License settings travel as environment variables (see ParseLicenseJSON).
+	// The platform does not tell them apart from other variables, so all of
+	// them are imported as license settings.
+	if len(ja.EnvVars) > 0 {
+		data, err := json.Marshal(ja.EnvVars)
+		if err != nil {
+			return models.JobSpec{}, nil, fmt.Errorf("failed to encode environment variables: %w", err)
+		}
+		spec.LicenseSettings = string(data)
+	}
+
+	var fileIDs, compressed []string
+	for _, f := range ja.InputFiles {
+		fileIDs = append(fileIDs, f.ID)
+		if !f.Decompress {
+			compressed = append(compressed, f.Name)
+		}
+	}
+	spec.ExtraInputFileIDs = strings.Join(fileIDs, ",")
+	// NoDecompress applies to all of a job's inputs, so it can only be
+	// carried over when none of the original inputs were decompressed.
+	if len(compressed) > 0 && len(compressed) == len(fileIDs) {
+		spec.NoDecompress = true
+	} else if len(compressed) > 0 {
+		sort.Strings(compressed)
+		warnings = append(warnings, fmt.Sprintf("input files %s were not decompressed in the original job; imported jobs will decompress them",
+			strings.Join(compressed, ", ")))
+	}
+
+	for _, a := range job.JobAutomations {
+		if a.Automation.ID != "" {
+			spec.Automations = append(spec.Automations, a.Automation.ID)
+		}
+	}
+
+	return spec, warnings, nil
+}
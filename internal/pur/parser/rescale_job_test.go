@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJobRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"AbCdE", "AbCdE", false},
+		{"  AbCdE \n", "AbCdE", false},
+		{"https://platform.rescale.com/jobs/AbCdE/", "AbCdE", false},
+		{"https://eu.rescale.com/jobs/AbCdE/setup/", "AbCdE", false},
+		{"https://platform.rescale.com/api/v3/jobs/AbCdE/", "AbCdE", false},
+		{"https://platform.rescale.com/files/", "", true},
+		{"not a job", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseJobRef(tt.ref)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseJobRef(%q) = %q, %v; want %q, err=%v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRescaleJobToJobSpec(t *testing.T) {
+	raw := []byte(`{
+		"id": "AbCdE",
+		"name": "wing-study-3",
+		"isLowPriority": true,
+		"projectId": "proj1",
+		"jobanalyses": [{
+			"command": "simpleFoam -parallel",
+			"analysis": {"code": "openfoam", "version": "v2306", "versionCode": "2306"},
+			"hardware": {"coreType": {"code": "emerald"}, "coresPerSlot": 8, "slots": 2, "walltime": 12},
+			"inputFiles": [
+				{"id": "f1", "name": "case.tar.gz", "decompress": true},
+				{"id": "f2", "name": "mesh.tar.gz", "decompress": false}
+			],
+			"envVars": {"RLM_LICENSE": "5053@lic"},
+			"onDemandLicenseSeller": null
+		}],
+		"jobAutomations": [{"automation": {"id": "auto1"}}]
+	}`)
+
+	spec, warnings, err := RescaleJobToJobSpec(raw)
+	if err != nil {
+		t.Fatalf("RescaleJobToJobSpec() error: %v", err)
+	}
+
+	if spec.JobName != "wing-study-3" || spec.Command != "simpleFoam -parallel" {
+		t.Errorf("name/command = %q/%q", spec.JobName, spec.Command)
+	}
+	if spec.AnalysisCode != "openfoam" || spec.AnalysisVersion != "v2306" {
+		t.Errorf("analysis = %s %s", spec.AnalysisCode, spec.AnalysisVersion)
+	}
+	if spec.CoreType != "emerald" || spec.CoresPerSlot != 8 || spec.Slots != 2 || spec.WalltimeHours != 12 {
+		t.Errorf("hardware = %s x%d slots=%d walltime=%v", spec.CoreType, spec.CoresPerSlot, spec.Slots, spec.WalltimeHours)
+	}
+	if spec.ExtraInputFileIDs != "f1,f2" {
+		t.Errorf("ExtraInputFileIDs = %q", spec.ExtraInputFileIDs)
+	}
+	if spec.LicenseSettings != `{"RLM_LICENSE":"5053@lic"}` {
+		t.Errorf("LicenseSettings = %q", spec.LicenseSettings)
+	}
+	if !spec.IsLowPriority || spec.ProjectID != "proj1" || len(spec.Automations) != 1 || spec.Automations[0] != "auto1" {
+		t.Errorf("priority/project/automations = %v/%q/%v", spec.IsLowPriority, spec.ProjectID, spec.Automations)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "mesh.tar.gz") {
+		t.Errorf("warnings = %v, want one about mesh.tar.gz", warnings)
+	}
+	if spec.NoDecompress {
+		t.Error("NoDecompress = true for mixed inputs, want false")
+	}
+}
+
+func TestRescaleJobToJobSpec_NoDecompress(t *testing.T) {
+	raw := []byte(`{"id": "X1234", "jobanalyses": [{
+		"analysis": {"code": "openfoam", "versionCode": "2306"},
+		"hardware": {"coreType": "emerald", "coresPerSlot": 1},
+		"inputFiles": [
+			{"id": "f1", "name": "case.tar.gz", "decompress": false},
+			{"id": "f2", "name": "mesh.tar.gz", "decompress": false}
+		]
+	}]}`)
+	spec, warnings, err := RescaleJobToJobSpec(raw)
+	if err != nil {
+		t.Fatalf("RescaleJobToJobSpec() error: %v", err)
+	}
+	if !spec.NoDecompress {
+		t.Error("NoDecompress = false, want true when no input was decompressed")
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+}
+
+func TestRescaleJobToJobSpec_StringCodesAndNoAnalyses(t *testing.T) {
+	raw := []byte(`{"id": "X1234", "jobanalyses": [{
+		"analysis": {"code": "user_included", "versionCode": "0"},
+		"hardware": {"coreType": "onyx", "coresPerSlot": 1},
+		"onDemandLicenseSeller": {"code": "rescale"}
+	}]}`)
+	spec, _, err := RescaleJobToJobSpec(raw)
+	if err != nil {
+		t.Fatalf("RescaleJobToJobSpec() error: %v", err)
+	}
+	if spec.CoreType != "onyx" || spec.AnalysisVersion != "0" || spec.OnDemandLicenseSeller != "rescale" || spec.Slots != 1 {
+		t.Errorf("spec = %+v", spec)
+	}
+
+	if _, _, err := RescaleJobToJobSpec([]byte(`{"id": "X1234", "jobanalyses": []}`)); err == nil {
+		t.Error("expected error for job without analyses")
+	}
+}
@@ -473,6 +473,11 @@ func JobSpecToSGEMetadata(job models.JobSpec) *SGEMetadata {
 		slots = 1
 	}
 
+	envVars := make(map[string]string, len(job.EnvVars))
+	for k, v := range job.EnvVars {
+		envVars[k] = v
+	}
+
 	return &SGEMetadata{
 		Name:            job.JobName,
 		Command:         job.Command,
@@ -485,9 +490,10 @@ func JobSpecToSGEMetadata(job models.JobSpec) *SGEMetadata {
 		Tags:            job.Tags,
 		ProjectID:       job.ProjectID,
 		Automations:     job.Automations,
-		// Note: LicenseSettings JSON from CSV doesn't map directly to SGE fields
-		// UseLicense could be derived from LicenseSettings if needed
-		EnvVariables: make(map[string]string),
+		UseLicense:      job.UseRescaleLicense,
+		// Note: LicenseSettings JSON from CSV doesn't map directly to SGE
+		// fields; callers merge it into EnvVariables (config.JobEnvironment)
+		EnvVariables: envVars,
 	}
 }
 
@@ -506,6 +512,14 @@ func SGEMetadataToJobSpec(m *SGEMetadata) models.JobSpec {
 		slots = 1
 	}
 
+	var envVars map[string]string
+	if len(m.EnvVariables) > 0 {
+		envVars = make(map[string]string, len(m.EnvVariables))
+		for k, v := range m.EnvVariables {
+			envVars[k] = v
+		}
+	}
+
 	return models.JobSpec{
 		JobName:         m.Name,
 		Command:         m.Command,
@@ -520,5 +534,7 @@ func SGEMetadataToJobSpec(m *SGEMetadata) models.JobSpec {
 		Automations:     m.Automations,
 		// Note: InputFiles from script are stored in SGEMetadata.InputFiles
 		// and should be handled separately by the caller
+		EnvVars:           envVars,
+		UseRescaleLicense: m.UseLicense,
 	}
 }
@@ -0,0 +1,52 @@
+package pipeline
+
+// arrayProgress is the aggregate progress of the members of one array.
+type arrayProgress struct {
+	name      string
+	total     int
+	submitted int
+	failed    int
+}
+
+// arrayProgress returns the progress of each array of the run, in the order
+// the arrays appear.
+func (p *Pipeline) arrayProgress() []arrayProgress {
+	var arrays []arrayProgress
+	pos := make(map[string]int)
+	for i, spec := range p.jobs {
+		if spec.ArrayName == "" {
+			continue
+		}
+		k, ok := pos[spec.ArrayName]
+		if !ok {
+			k = len(arrays)
+			pos[spec.ArrayName] = k
+			arrays = append(arrays, arrayProgress{name: spec.ArrayName})
+		}
+		a := &arrays[k]
+		a.total++
+		st := p.stateMgr.GetState(i + 1)
+		switch {
+		case st == nil:
+		case st.SubmitStatus == "success":
+			a.submitted++
+		case st.TarStatus == "failed" || st.UploadStatus == "failed" || st.SubmitStatus == "failed":
+			a.failed++
+		}
+	}
+	return arrays
+}
+
+// logArrayProgress logs one aggregate line per array whose progress changed
+// since it was last logged; last is updated. A nil last logs every array.
+func (p *Pipeline) logArrayProgress(last map[string]arrayProgress) {
+	for _, a := range p.arrayProgress() {
+		if last != nil {
+			if last[a.name] == a {
+				continue
+			}
+			last[a.name] = a
+		}
+		p.logf("INFO", "pipeline", "", "Array %s: %d/%d submitted, %d failed", a.name, a.submitted, a.total, a.failed)
+	}
+}
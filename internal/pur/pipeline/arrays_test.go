@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+func TestArrayProgress(t *testing.T) {
+	stateMgr := state.NewManager(filepath.Join(t.TempDir(), "run.state"))
+	jobs := []models.JobSpec{
+		{JobName: "mesh"},
+		{JobName: "case_1", ArrayName: "case"},
+		{JobName: "case_2", ArrayName: "case"},
+		{JobName: "case_3", ArrayName: "case"},
+	}
+	for i, status := range []string{"success", "success", "failed", "pending"} {
+		st := stateMgr.InitializeState(i+1, jobs[i].JobName, "")
+		st.SubmitStatus = status
+		stateMgr.UpdateState(st)
+	}
+
+	p := &Pipeline{stateMgr: stateMgr, jobs: jobs}
+	got := p.arrayProgress()
+	want := arrayProgress{name: "case", total: 3, submitted: 1, failed: 1}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("arrayProgress() = %+v, want [%+v]", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+// resolveTarSourceDir returns the directory the tar stage archives for job:
+// the run directory, or TarSubpath within it. When Directory is a ZIP
+// archive, TarSubpath names the run folder inside it and the ZIP itself is
+// returned (see createArchives).
+func resolveTarSourceDir(job models.JobSpec) (string, error) {
+	if tar.IsZipSource(job.Directory) {
+		if _, err := tar.CleanZipSubtree(job.TarSubpath); err != nil {
+			return "", fmt.Errorf("ZIP source %s: set TarSubpath to the run folder inside it: %w", job.Directory, err)
+		}
+		return job.Directory, nil
+	}
+	if job.TarSubpath == "" {
+		return job.Directory, nil
+	}
+
+	tarSourceDir := filepath.Join(job.Directory, job.TarSubpath)
+	// Path traversal guard: prevent ../ escape outside run directory
+	absSource, errAbs := filepath.Abs(tarSourceDir)
+	absRunDir, errRun := filepath.Abs(job.Directory)
+	rel, errRel := filepath.Rel(absRunDir, absSource)
+	if errAbs != nil || errRun != nil || errRel != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("tar subpath '%s' escapes run directory", job.TarSubpath)
+	}
+	if _, err := os.Stat(tarSourceDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("tar subpath '%s' does not exist in %s", job.TarSubpath, job.Directory)
+	}
+	return tarSourceDir, nil
+}
+
+// JobContents lists the files in a job's input archive(s). When st shows the
+// tar stage succeeded and the archives are still on disk, they are read
+// directly (fromArchive is true). Otherwise the listing is previewed from the
+// run directory with the include/exclude/flatten settings in cfg, so missing
+// inputs can be spotted before anything is tarred or uploaded.
+func JobContents(cfg *config.Config, job models.JobSpec, st *models.JobState, multiPartMode bool) (entries []tar.Entry, fromArchive bool, err error) {
+	if st != nil && st.TarStatus == "success" {
+		if exists, _ := allTarsExist(st.TarPaths()); exists {
+			for _, path := range st.TarPaths() {
+				listed, err := tar.ListArchive(path)
+				if err != nil {
+					return nil, false, err
+				}
+				entries = append(entries, listed...)
+			}
+			return entries, true, nil
+		}
+	}
+
+	sourceDir, err := resolveTarSourceDir(job)
+	if err != nil {
+		return nil, false, err
+	}
+	if tar.IsZipSource(sourceDir) {
+		entries, err = tar.PreviewZipContents(sourceDir, job.TarSubpath, cfg.IncludePatterns, cfg.ExcludePatterns, cfg.FlattenTar)
+		return entries, false, err
+	}
+	entries, err = tar.PreviewContents(sourceDir, multiPartMode, cfg.IncludePatterns, cfg.ExcludePatterns, cfg.FlattenTar)
+	return entries, false, err
+}
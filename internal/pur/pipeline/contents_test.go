@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+func TestJobContents(t *testing.T) {
+	run := filepath.Join(t.TempDir(), "Run_1")
+	for _, name := range []string{"input.sim", "solver.log", "mesh/a.msh"} {
+		path := filepath.Join(run, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{ExcludePatterns: []string{"*.log"}}
+	job := models.JobSpec{Directory: run, JobName: "Run_1"}
+
+	// Before the tar stage: previewed with the exclude pattern applied
+	entries, fromArchive, err := JobContents(cfg, job, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromArchive || len(entries) != 2 {
+		t.Errorf("preview = %v (fromArchive %v), want 2 entries", entries, fromArchive)
+	}
+
+	// After the tar stage: the archive is read, even if the directory changed
+	archive := filepath.Join(t.TempDir(), "Run_1.tar.gz")
+	if err := tar.CreateTarGzWithOptions(run, archive, false, nil, nil, false, "gzip", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	st := &models.JobState{TarStatus: "success", TarPath: archive}
+	entries, fromArchive, err = JobContents(cfg, job, st, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromArchive || len(entries) != 3 {
+		t.Errorf("archive = %v (fromArchive %v), want 3 entries", entries, fromArchive)
+	}
+
+	// Archive deleted after upload: fall back to the preview
+	os.Remove(archive)
+	if _, fromArchive, _ = JobContents(cfg, job, st, false); fromArchive {
+		t.Error("expected preview when the archive is gone")
+	}
+}
+
+func TestJobContentsTarSubpath(t *testing.T) {
+	run := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(run, "inputs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(run, "inputs", "case.inp"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+
+	entries, _, err := JobContents(cfg, models.JobSpec{Directory: run, TarSubpath: "inputs"}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name != "inputs/case.inp" {
+		t.Errorf("entries = %v, want [inputs/case.inp]", entries)
+	}
+
+	if _, _, err := JobContents(cfg, models.JobSpec{Directory: run, TarSubpath: "../elsewhere"}, nil, false); err == nil {
+		t.Error("expected error for a subpath escaping the run directory")
+	}
+}
+
+func TestJobContentsZipSource(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "study.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"Run_1/input.sim", "Run_1/solver.log", "Run_2/input.sim"} {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zw.Close()
+	f.Close()
+	cfg := &config.Config{ExcludePatterns: []string{"*.log"}}
+
+	entries, fromArchive, err := JobContents(cfg, models.JobSpec{Directory: zipPath, TarSubpath: "Run_1"}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromArchive || len(entries) != 1 || entries[0].Name != "Run_1/input.sim" {
+		t.Errorf("entries = %v (fromArchive %v), want [Run_1/input.sim]", entries, fromArchive)
+	}
+
+	if _, _, err := JobContents(cfg, models.JobSpec{Directory: zipPath}, nil, false); err == nil {
+		t.Error("expected error for a ZIP source without TarSubpath")
+	}
+}
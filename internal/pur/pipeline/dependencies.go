@@ -0,0 +1,177 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/pur/validation"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// JobSubmitter abstracts the API call that submits a created job. This
+// allows tests to inject a fake instead of calling the real API.
+type JobSubmitter interface {
+	SubmitJob(ctx context.Context, jobID string) error
+}
+
+// resolveDependencies sets p.parents from the jobs' DependsOn columns, or
+// returns an error listing every problem found.
+func (p *Pipeline) resolveDependencies() error {
+	parents, problems := validation.ResolveDependencies(p.jobs)
+	if len(problems) > 0 {
+		indexes := make([]int, 0, len(problems))
+		for i := range problems {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		var lines []string
+		for _, i := range indexes {
+			for _, msg := range problems[i] {
+				lines = append(lines, fmt.Sprintf("row %d (%s): %s", i+1, p.jobs[i].JobName, msg))
+			}
+		}
+		return fmt.Errorf("invalid job dependencies:\n  %s", strings.Join(lines, "\n  "))
+	}
+	p.parents = parents
+	return nil
+}
+
+// holdForDependencies marks a created job as waiting for its parents; the
+// dependency stage submits it once they have completed.
+func (p *Pipeline) holdForDependencies(item *workItem) {
+	item.state.SubmitStatus = "waiting"
+	p.stateMgr.UpdateState(item.state)
+	p.reportStateChange(item.state, "submit", "waiting", item.state.JobID, "", 0.0)
+	p.logf("INFO", "job", item.state.JobName, "Created; waiting for %s to complete before submitting",
+		strings.Join(item.jobSpec.DependsOn, ", "))
+}
+
+// dependencyStage submits jobs held by holdForDependencies as soon as all
+// their parents have reached Completed on Rescale, and fails them when a
+// parent fails, is not submitted or ends any other way. It runs alongside
+// the workers and returns when they are done (workersDone is closed) and no
+// job is left waiting, or when ctx is cancelled. Jobs still waiting then
+// are picked up again by a resumed run.
+func (p *Pipeline) dependencyStage(ctx context.Context, workersDone <-chan struct{}) {
+	p.logf("INFO", "dependency", "", "Jobs with dependencies are submitted once the jobs they depend on complete")
+	ticker := time.NewTicker(p.outputPollInterval)
+	defer ticker.Stop()
+
+	finished := false
+	for {
+		// A pass can unblock or fail further jobs of a chain, so passes are
+		// repeated until nothing changes
+		for {
+			waiting, changed := p.checkDependencies(ctx, finished)
+			if (finished && waiting == 0) || ctx.Err() != nil {
+				return
+			}
+			if !changed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-workersDone:
+			workersDone = nil // Closed; stop selecting on it
+			finished = true
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDependencies makes one pass over the waiting jobs. finished means
+// the workers are done, so parents that were never created or submitted
+// will not be anymore. It returns how many jobs are still waiting and
+// whether any job was submitted or failed.
+func (p *Pipeline) checkDependencies(ctx context.Context, finished bool) (waiting int, changed bool) {
+	statuses := make(map[string]string) // Platform status by job ID, fetched once per pass
+	for i, spec := range p.jobs {
+		st := p.stateMgr.GetState(i + 1)
+		if st == nil || st.JobID == "" || st.SubmitStatus != "waiting" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return waiting, changed
+		}
+
+		ready, problem := p.parentsReady(ctx, i, finished, statuses)
+		item := &workItem{index: i + 1, jobSpec: spec, state: st}
+		switch {
+		case problem != "":
+			msg := "not submitted: " + problem
+			p.logf("ERROR", "dependency", st.JobName, "%s", msg)
+			st.SubmitStatus = "failed"
+			st.ErrorMessage = msg
+			p.stateMgr.UpdateState(st)
+			p.reportStateChange(st, "submit", "failed", st.JobID, msg, 0.0)
+			changed = true
+		case ready:
+			if p.submitJob(ctx, item) {
+				p.incrementCompleted()
+			}
+			changed = true
+		default:
+			waiting++
+		}
+	}
+	return waiting, changed
+}
+
+// parentsReady reports whether every parent of job i has completed, or
+// why the job can no longer be submitted.
+func (p *Pipeline) parentsReady(ctx context.Context, i int, finished bool, statuses map[string]string) (bool, string) {
+	ready := true
+	for _, parent := range p.parents[i] {
+		name := p.jobs[parent].JobName
+		st := p.stateMgr.GetState(parent + 1)
+		if st == nil {
+			if finished {
+				return false, fmt.Sprintf("dependency %s was not created", name)
+			}
+			ready = false
+			continue
+		}
+
+		switch {
+		case st.TarStatus == "failed" || st.UploadStatus == "failed" || st.SubmitStatus == "failed":
+			return false, fmt.Sprintf("dependency %s failed", name)
+		case st.SubmitStatus == "skipped":
+			return false, fmt.Sprintf("dependency %s was not submitted", name)
+		case st.SubmitStatus != "success":
+			if finished && st.SubmitStatus != "waiting" {
+				return false, fmt.Sprintf("dependency %s was not submitted", name)
+			}
+			ready = false
+			continue
+		}
+
+		status, ok := statuses[st.JobID]
+		if !ok {
+			var err error
+			if status, err = p.outputFetcher.JobStatus(ctx, st.JobID); err != nil {
+				p.logf("WARN", "dependency", p.jobs[i].JobName, "Failed to get status of job %s: %v", st.JobID, err)
+				return false, ""
+			}
+			statuses[st.JobID] = status
+		}
+		if status == "Completed" {
+			continue
+		}
+		if watch.TerminalStatuses[status] {
+			return false, fmt.Sprintf("dependency %s ended %s", name, status)
+		}
+		ready = false
+	}
+	return ready, ""
+}
+
+// hasDependencies reports whether any job of the run depends on another.
+func (p *Pipeline) hasDependencies() bool {
+	return len(p.parents) > 0
+}
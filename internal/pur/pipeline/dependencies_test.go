@@ -0,0 +1,119 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+// fakeSubmitter records submitted job IDs.
+type fakeSubmitter struct {
+	mu        sync.Mutex
+	submitted []string
+}
+
+func (s *fakeSubmitter) SubmitJob(ctx context.Context, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.submitted = append(s.submitted, jobID)
+	return nil
+}
+
+// newDependencyPipeline returns a pipeline over jobs whose states are all
+// created, with the given submit statuses.
+func newDependencyPipeline(t *testing.T, jobs []models.JobSpec, submitStatuses []string, statuses map[string]string) (*Pipeline, *fakeSubmitter) {
+	t.Helper()
+	stateMgr := state.NewManager(filepath.Join(t.TempDir(), "run.state"))
+	for i, spec := range jobs {
+		st := stateMgr.InitializeState(i+1, spec.JobName, spec.Directory)
+		st.JobID, st.SubmitStatus = "J"+spec.JobName, submitStatuses[i]
+		stateMgr.UpdateState(st)
+	}
+	submitter := &fakeSubmitter{}
+	p := &Pipeline{
+		cfg:                &config.Config{},
+		stateMgr:           stateMgr,
+		jobs:               jobs,
+		activeWorkers:      make(map[string]int),
+		stageStarts:        make(map[int]map[string]time.Time),
+		stageTimes:         make(map[int]map[string]time.Duration),
+		outputPollInterval: time.Millisecond,
+		outputFetcher:      &fakeOutputFetcher{statuses: statuses},
+		jobSubmitter:       submitter,
+	}
+	if err := p.resolveDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	return p, submitter
+}
+
+func TestDependencyStage(t *testing.T) {
+	jobs := []models.JobSpec{
+		{JobName: "mesh"},
+		{JobName: "solve", DependsOn: []string{"mesh"}},
+		{JobName: "post", DependsOn: []string{"solve"}},
+		{JobName: "other"},
+		{JobName: "report", DependsOn: []string{"other"}},
+		{JobName: "archive", DependsOn: []string{"report"}},
+	}
+	p, submitter := newDependencyPipeline(t, jobs,
+		[]string{"success", "waiting", "waiting", "success", "waiting", "waiting"},
+		map[string]string{"Jmesh": "Completed", "Jsolve": "Executing", "Jother": "Failed"})
+
+	done := make(chan struct{})
+	close(done)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	p.dependencyStage(ctx, done)
+
+	if len(submitter.submitted) != 1 || submitter.submitted[0] != "Jsolve" {
+		t.Errorf("submitted %v, want only Jsolve", submitter.submitted)
+	}
+	if st := p.stateMgr.GetState(3); st.SubmitStatus != "waiting" {
+		t.Errorf("post = %q, want it still waiting for solve", st.SubmitStatus)
+	}
+	if st := p.stateMgr.GetState(5); st.SubmitStatus != "failed" || !strings.Contains(st.ErrorMessage, "dependency other ended Failed") {
+		t.Errorf("report = %q (%s)", st.SubmitStatus, st.ErrorMessage)
+	}
+	// The failure carries down the chain
+	if st := p.stateMgr.GetState(6); st.SubmitStatus != "failed" || !strings.Contains(st.ErrorMessage, "dependency report failed") {
+		t.Errorf("archive = %q (%s)", st.SubmitStatus, st.ErrorMessage)
+	}
+}
+
+func TestDependencyStage_ParentNotSubmitted(t *testing.T) {
+	jobs := []models.JobSpec{
+		{JobName: "mesh"},
+		{JobName: "solve", DependsOn: []string{"mesh"}},
+	}
+	p, submitter := newDependencyPipeline(t, jobs, []string{"skipped", "waiting"}, nil)
+
+	done := make(chan struct{})
+	close(done)
+	p.dependencyStage(context.Background(), done)
+
+	if len(submitter.submitted) != 0 {
+		t.Errorf("submitted %v", submitter.submitted)
+	}
+	if st := p.stateMgr.GetState(2); st.SubmitStatus != "failed" || !strings.Contains(st.ErrorMessage, "mesh was not submitted") {
+		t.Errorf("solve = %q (%s)", st.SubmitStatus, st.ErrorMessage)
+	}
+}
+
+func TestResolveDependencies_RejectsCycles(t *testing.T) {
+	p := &Pipeline{jobs: []models.JobSpec{
+		{JobName: "a", DependsOn: []string{"b"}},
+		{JobName: "b", DependsOn: []string{"a"}},
+	}}
+	err := p.resolveDependencies()
+	if err == nil || !strings.Contains(err.Error(), "row 1 (a): DependsOn: dependency cycle a → b → a") {
+		t.Errorf("resolveDependencies() = %v", err)
+	}
+}
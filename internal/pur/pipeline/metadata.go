@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Where per-job metadata is written at job creation (job_metadata_target).
+const (
+	MetadataToDescription  = "description"
+	MetadataToCustomFields = "custom_fields"
+	MetadataToBoth         = "both"
+)
+
+// NormalizeMetadataTarget returns target as one of the MetadataTo*
+// constants, mapping empty and unknown values to MetadataToDescription.
+func NormalizeMetadataTarget(target string) string {
+	switch strings.ToLower(strings.TrimSpace(target)) {
+	case MetadataToCustomFields:
+		return MetadataToCustomFields
+	case MetadataToBoth:
+		return MetadataToBoth
+	default:
+		return MetadataToDescription
+	}
+}
+
+// MetadataDescription renders the job's metadata as the job description:
+// one "key=value" line per key, sorted by key.
+func MetadataDescription(spec models.JobSpec) string {
+	var b strings.Builder
+	for _, k := range spec.MetadataKeys() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(spec.Metadata[k])
+	}
+	return b.String()
+}
+
+// metadataTargets reports whether metadata goes to the description and to
+// custom fields under the configured job_metadata_target.
+func (p *Pipeline) metadataTargets() (description, customFields bool) {
+	target := ""
+	if p.cfg != nil {
+		target = p.cfg.JobMetadataTarget
+	}
+	switch NormalizeMetadataTarget(target) {
+	case MetadataToCustomFields:
+		return false, true
+	case MetadataToBoth:
+		return true, true
+	default:
+		return true, false
+	}
+}
@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestMetadataDescription(t *testing.T) {
+	spec := models.JobSpec{Metadata: map[string]string{"study": "wing-sweep", "Mach": "0.8", "AoA": "4"}}
+	if got, want := MetadataDescription(spec), "AoA=4\nMach=0.8\nstudy=wing-sweep"; got != want {
+		t.Errorf("MetadataDescription() = %q, want %q", got, want)
+	}
+	if got := MetadataDescription(models.JobSpec{}); got != "" {
+		t.Errorf("MetadataDescription(no metadata) = %q, want empty", got)
+	}
+}
+
+func TestMetadataTargets(t *testing.T) {
+	tests := []struct {
+		target             string
+		description, field bool
+	}{
+		{"", true, false},
+		{"description", true, false},
+		{"Custom_Fields", false, true},
+		{"both", true, true},
+		{"bogus", true, false},
+	}
+	for _, tt := range tests {
+		p := &Pipeline{cfg: &config.Config{JobMetadataTarget: tt.target}}
+		if d, f := p.metadataTargets(); d != tt.description || f != tt.field {
+			t.Errorf("metadataTargets(%q) = %v, %v; want %v, %v", tt.target, d, f, tt.description, tt.field)
+		}
+	}
+}
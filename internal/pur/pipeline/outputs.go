@@ -0,0 +1,264 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/download"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/util/filter"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// defaultOutputPollInterval is how often the output stage checks the status
+// of jobs it is waiting for.
+const defaultOutputPollInterval = constants.JobPollInterval
+
+// OutputFetcher abstracts the API calls of the output stage. This allows
+// tests to inject a fake instead of calling the real API.
+type OutputFetcher interface {
+	// JobStatus returns the job's current platform status.
+	JobStatus(ctx context.Context, jobID string) (string, error)
+	ListJobFiles(ctx context.Context, jobID string) ([]models.JobFile, error)
+	DownloadJobFile(ctx context.Context, file models.JobFile, localPath string) error
+}
+
+// apiOutputFetcher is the OutputFetcher backed by the API client.
+type apiOutputFetcher struct {
+	client      *api.Client
+	transferMgr *transfer.Manager
+}
+
+func (f *apiOutputFetcher) JobStatus(ctx context.Context, jobID string) (string, error) {
+	ctx, cancel := f.client.WithTimeout(ctx, config.APIStatus)
+	defer cancel()
+	statuses, err := f.client.GetJobStatuses(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	if latest, _, ok := watch.LatestStatus(statuses); ok {
+		return latest.Status, nil
+	}
+	if len(statuses) > 0 {
+		return statuses[0].Status, nil
+	}
+	return "", nil
+}
+
+func (f *apiOutputFetcher) ListJobFiles(ctx context.Context, jobID string) ([]models.JobFile, error) {
+	ctx, cancel := f.client.WithTimeout(ctx, config.APIListing)
+	defer cancel()
+	return f.client.ListJobFiles(ctx, jobID)
+}
+
+func (f *apiOutputFetcher) DownloadJobFile(ctx context.Context, file models.JobFile, localPath string) error {
+	handle := f.transferMgr.AllocateTransfer(file.DecryptedSize, 1)
+	defer handle.Complete()
+	return download.DownloadFile(ctx, download.DownloadParams{
+		FileInfo:       file.ToCloudFile(),
+		LocalPath:      localPath,
+		APIClient:      f.client,
+		TransferHandle: handle,
+		OutputWriter:   io.Discard,
+	})
+}
+
+// SetOutputFetcher overrides the default OutputFetcher (the API client).
+// Used in tests to inject a mock.
+func (p *Pipeline) SetOutputFetcher(fetcher OutputFetcher) {
+	p.outputFetcher = fetcher
+}
+
+// wantsOutputs reports whether the output stage should follow the job.
+func wantsOutputs(spec models.JobSpec) bool {
+	return len(spec.OutputPatterns) > 0 && spec.Directory != ""
+}
+
+// hasOutputStage reports whether any job of the run asks for its outputs.
+func (p *Pipeline) hasOutputStage() bool {
+	for _, spec := range p.jobs {
+		if wantsOutputs(spec) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingOutputs returns the submitted jobs whose outputs are still to be
+// downloaded.
+func (p *Pipeline) pendingOutputs() []*workItem {
+	var items []*workItem
+	for i, spec := range p.jobs {
+		if !wantsOutputs(spec) {
+			continue
+		}
+		st := p.stateMgr.GetState(i + 1)
+		if st == nil || st.JobID == "" || st.SubmitStatus != "success" {
+			continue
+		}
+		switch st.OutputStatus {
+		case "success", "failed", "skipped":
+			continue
+		}
+		items = append(items, &workItem{index: i + 1, jobSpec: spec, state: st})
+	}
+	return items
+}
+
+// outputStage follows submitted jobs whose specs list OutputPatterns and
+// downloads the matching output files once each job completes. It runs
+// alongside the workers and returns when they are done (workersDone is
+// closed) and no job is left to wait for, or when ctx is cancelled.
+func (p *Pipeline) outputStage(ctx context.Context, workersDone <-chan struct{}) {
+	// Downloads that failed in an earlier attempt of this run are retried
+	for i, spec := range p.jobs {
+		if st := p.stateMgr.GetState(i + 1); st != nil && wantsOutputs(spec) && st.OutputStatus == "failed" {
+			st.OutputStatus = "pending"
+			p.stateMgr.UpdateState(st)
+		}
+	}
+
+	p.logf("INFO", "output", "", "Outputs of jobs with output patterns will be downloaded into their directories as the jobs complete")
+	ticker := time.NewTicker(p.outputPollInterval)
+	defer ticker.Stop()
+
+	finished := false
+	for {
+		pending := p.pendingOutputs()
+		if finished && len(pending) == 0 {
+			return
+		}
+		for _, item := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			p.collectOutputs(ctx, item)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-workersDone:
+			workersDone = nil // Closed; stop selecting on it
+			finished = true
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectOutputs downloads a job's outputs if it has finished. Jobs that
+// are still running are left pending; a job that ended without completing
+// is skipped.
+func (p *Pipeline) collectOutputs(ctx context.Context, item *workItem) {
+	name, jobID := item.state.JobName, item.state.JobID
+
+	status, err := p.outputFetcher.JobStatus(ctx, jobID)
+	if err != nil {
+		p.logf("WARN", "output", name, "Failed to get status of job %s: %v", jobID, err)
+		return
+	}
+	if !watch.TerminalStatuses[status] {
+		return
+	}
+	if status != "Completed" {
+		item.state.OutputStatus = "skipped"
+		p.stateMgr.UpdateState(item.state)
+		p.reportStateChange(item.state, "output", "skipped", jobID, "", 0.0)
+		p.logf("WARN", "output", name, "Job %s ended %s; outputs not downloaded", jobID, status)
+		return
+	}
+
+	p.reportStateChange(item.state, "output", "in_progress", jobID, "", 0.0)
+	dir := outputDir(item.jobSpec)
+	downloaded, existing, err := p.downloadOutputs(ctx, jobID, item.jobSpec.OutputPatterns, dir)
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Still pending; a resumed run picks it up
+		}
+		msg := fmt.Sprintf("output download failed: %v", err)
+		p.logf("ERROR", "output", name, "%s", msg)
+		item.state.OutputStatus = "failed"
+		item.state.ErrorMessage = msg
+		p.stateMgr.UpdateState(item.state)
+		p.reportStateChange(item.state, "output", "failed", jobID, msg, 0.0)
+		return
+	}
+
+	item.state.OutputStatus = "success"
+	p.stateMgr.UpdateState(item.state)
+	p.reportStateChange(item.state, "output", "completed", jobID, "", 0.0)
+	if existing > 0 {
+		p.logf("INFO", "output", name, "Downloaded %d output file(s) to %s (%d already there, kept)", downloaded, dir, existing)
+	} else {
+		p.logf("INFO", "output", name, "Downloaded %d output file(s) to %s", downloaded, dir)
+	}
+}
+
+// downloadOutputs downloads the job's files whose names match patterns into
+// dir, keeping their relative paths. Files that already exist locally are
+// never overwritten, so the job's input files stay as they were and a
+// resumed run only fetches what is missing. It returns the number of files
+// downloaded and left in place.
+func (p *Pipeline) downloadOutputs(ctx context.Context, jobID string, patterns []string, dir string) (downloaded, existing int, err error) {
+	files, err := p.outputFetcher.ListJobFiles(ctx, jobID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list job files: %w", err)
+	}
+	files = filter.ApplyToJobFiles(files, filter.Config{Include: patterns})
+
+	for _, f := range files {
+		rel := f.RelativePath
+		if rel == "" {
+			rel = f.Name
+		}
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if r, err := filepath.Rel(dir, target); err != nil || r == "." || strings.HasPrefix(r, "..") {
+			return downloaded, existing, fmt.Errorf("output file %q escapes the job directory", rel)
+		}
+		if _, err := os.Lstat(target); err == nil {
+			existing++
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return downloaded, existing, err
+		}
+		if err := p.outputFetcher.DownloadJobFile(ctx, f, target); err != nil {
+			return downloaded, existing, fmt.Errorf("%s: %w", rel, err)
+		}
+		downloaded++
+	}
+	return downloaded, existing, nil
+}
+
+// outputDir is where a job's outputs are downloaded: its run directory, or
+// for a job read from a ZIP archive, a folder named after the job next to
+// the archive.
+func outputDir(spec models.JobSpec) string {
+	if info, err := os.Stat(spec.Directory); err == nil && !info.IsDir() {
+		return filepath.Join(filepath.Dir(spec.Directory), sanitizeJobName(spec.JobName))
+	}
+	return spec.Directory
+}
+
+// sanitizeJobName makes a job name safe to use as a single path element.
+func sanitizeJobName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		return "outputs"
+	}
+	return name
+}
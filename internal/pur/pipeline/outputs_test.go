@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+// fakeOutputFetcher serves fixed statuses and file lists and writes each
+// downloaded file's ID as its content.
+type fakeOutputFetcher struct {
+	statuses map[string]string
+	files    map[string][]models.JobFile
+}
+
+func (f *fakeOutputFetcher) JobStatus(ctx context.Context, jobID string) (string, error) {
+	return f.statuses[jobID], nil
+}
+
+func (f *fakeOutputFetcher) ListJobFiles(ctx context.Context, jobID string) ([]models.JobFile, error) {
+	return f.files[jobID], nil
+}
+
+func (f *fakeOutputFetcher) DownloadJobFile(ctx context.Context, file models.JobFile, localPath string) error {
+	return os.WriteFile(localPath, []byte(file.ID), 0644)
+}
+
+func TestOutputStage_DownloadsCompletedJobs(t *testing.T) {
+	root := t.TempDir()
+	dir1, dir2 := filepath.Join(root, "Run_1"), filepath.Join(root, "Run_2")
+	os.MkdirAll(dir1, 0755)
+	os.MkdirAll(dir2, 0755)
+	os.WriteFile(filepath.Join(dir1, "solver.out"), []byte("input"), 0644)
+
+	stateMgr := state.NewManager(filepath.Join(root, "run.state"))
+	jobs := []models.JobSpec{
+		{JobName: "job1", Directory: dir1, OutputPatterns: []string{"*.out"}},
+		{JobName: "job2", Directory: dir2, OutputPatterns: []string{"*.out"}},
+		{JobName: "job3", Directory: dir2}, // No patterns: not followed
+	}
+	for i, spec := range jobs {
+		st := stateMgr.InitializeState(i+1, spec.JobName, spec.Directory)
+		st.JobID, st.SubmitStatus = "J"+spec.JobName, "success"
+		stateMgr.UpdateState(st)
+	}
+
+	p := &Pipeline{
+		cfg:                &config.Config{},
+		stateMgr:           stateMgr,
+		jobs:               jobs,
+		stageStarts:        make(map[int]map[string]time.Time),
+		stageTimes:         make(map[int]map[string]time.Duration),
+		outputPollInterval: time.Millisecond,
+		outputFetcher: &fakeOutputFetcher{
+			statuses: map[string]string{"Jjob1": "Completed", "Jjob2": "Failed", "Jjob3": "Completed"},
+			files: map[string][]models.JobFile{"Jjob1": {
+				{ID: "f1", Name: "solver.out", RelativePath: "solver.out"},
+				{ID: "f2", Name: "restart.rst", RelativePath: "restart.rst"},
+				{ID: "f3", Name: "probe.out", RelativePath: "post/probe.out"},
+			}},
+		},
+	}
+	if !p.hasOutputStage() {
+		t.Fatal("hasOutputStage() = false")
+	}
+
+	done := make(chan struct{})
+	close(done)
+	p.outputStage(context.Background(), done)
+
+	if got, _ := os.ReadFile(filepath.Join(dir1, "post", "probe.out")); string(got) != "f3" {
+		t.Errorf("post/probe.out = %q, want the downloaded file", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir1, "solver.out")); string(got) != "input" {
+		t.Errorf("existing solver.out was overwritten: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir1, "restart.rst")); err == nil {
+		t.Error("restart.rst does not match the patterns but was downloaded")
+	}
+
+	for index, want := range map[int]string{1: "success", 2: "skipped", 3: ""} {
+		if got := stateMgr.GetState(index).OutputStatus; got != want {
+			t.Errorf("job%d OutputStatus = %q, want %q", index, got, want)
+		}
+	}
+}
+
+func TestOutputDir_ZipSourceUsesSiblingFolder(t *testing.T) {
+	root := t.TempDir()
+	zip := filepath.Join(root, "study.zip")
+	os.WriteFile(zip, []byte("PK"), 0644)
+
+	if got, want := outputDir(models.JobSpec{JobName: "case/1", Directory: zip}), filepath.Join(root, "case_1"); got != want {
+		t.Errorf("outputDir() = %s, want %s", got, want)
+	}
+	if got := outputDir(models.JobSpec{JobName: "x", Directory: root}); got != root {
+		t.Errorf("outputDir() = %s, want the run directory", got)
+	}
+}
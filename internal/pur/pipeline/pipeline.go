@@ -2,27 +2,43 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud"
+	cloudtransfer "github.com/rescale/rescale-int/internal/cloud/transfer"
 	"github.com/rescale/rescale-int/internal/cloud/upload"
 	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/logging"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/netusage"
 	"github.com/rescale/rescale-int/internal/pathutil"
+	"github.com/rescale/rescale-int/internal/pur/jobarray"
+	"github.com/rescale/rescale-int/internal/pur/runtimes"
 	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/pur/validation"
 	"github.com/rescale/rescale-int/internal/ratelimit"
 	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/tracing"
 	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/util/tags"
 	"github.com/rescale/rescale-int/internal/util/tar"
+	"github.com/rescale/rescale-int/internal/version"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AnalysisResolver abstracts the API call used by resolveAnalysisVersions.
@@ -50,6 +66,7 @@ type SyncUploadParams struct {
 	BatchLabel            string                 // Batch display label
 	ExtraProgressCallback func(progress float64) // Pipeline's own progress reporting
 	Tags                  []string               // Applied after upload (non-fatal on failure)
+	PauseInBlackout       bool                   // Hold between parts during blackout windows
 }
 
 // ProgressCallback is called when job progress updates
@@ -62,6 +79,13 @@ type LogCallback func(level string, message string, stage string, jobName string
 // uploadProgress is 0.0-1.0 and only used for upload stage, 0.0 for other stages
 type StateChangeCallback func(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64)
 
+// RunStartCallback is called once when Run starts working on the jobs
+type RunStartCallback func(runID string, totalJobs int)
+
+// TarProgressCallback is called while a job's input archives are written,
+// at most every constants.TarProgressInterval
+type TarProgressCallback func(jobName string, progress tar.Progress)
+
 // Pipeline orchestrates the parallel tar/upload/job workflow
 type Pipeline struct {
 	cfg              *config.Config
@@ -81,6 +105,24 @@ type Pipeline struct {
 	// Cleanup options
 	rmTarOnSuccess bool // Delete local tar file after successful upload
 
+	// Leave jobs the platform created with warnings unsubmitted
+	failOnWarning bool
+
+	// State indices of the jobs the feeder enqueues; nil means every job
+	// (see SetJobFilter)
+	onlyIndices map[int]bool
+
+	// Output stage: downloads the outputs of jobs whose specs list
+	// OutputPatterns once they complete (see outputStage)
+	outputFetcher      OutputFetcher
+	outputPollInterval time.Duration // Also how often the dependency stage polls
+
+	// Dependency stage: jobs with DependsOn are created, then submitted
+	// once their parents complete (see dependencyStage). parents maps a
+	// 0-based job index to the indexes of the jobs it depends on.
+	parents      map[int][]int
+	jobSubmitter JobSubmitter // Defaults to apiClient
+
 	// Resource and transfer management
 	resourceMgr *resources.Manager
 	transferMgr *transfer.Manager
@@ -100,6 +142,12 @@ type Pipeline struct {
 	closeUploadOnce sync.Once
 	closeJobOnce    sync.Once
 
+	// Items fed to tarQueue that have not yet passed the input settle check,
+	// including those waiting to be requeued. tarQueue is closed once the
+	// feeder has finished and this drops to zero (guarded by mu).
+	tarPending     int
+	feederFinished bool
+
 	// Concurrent version resolution
 	versionsResolved chan struct{}
 	resolvedVersions map[string]string // "analysisCode:displayVersion" -> versionCode
@@ -114,10 +162,20 @@ type Pipeline struct {
 	pipelineStart time.Time
 	firstTarOnce  sync.Once
 
+	// Network bytes moved during Run (see NetworkUsage)
+	networkUsage netusage.Usage
+
+	// Per-job stage timing for run reports (job index -> stage -> start/elapsed)
+	stageMu     sync.Mutex
+	stageStarts map[int]map[string]time.Time
+	stageTimes  map[int]map[string]time.Duration
+
 	// Callbacks (optional)
 	onProgress    ProgressCallback
 	onLog         LogCallback
 	onStateChange StateChangeCallback
+	onTarProgress TarProgressCallback
+	onRunStart    RunStartCallback
 
 	// When non-nil, uploadWorker and ResolveSharedFiles delegate uploads here
 	// instead of calling upload.UploadFile() directly.
@@ -125,12 +183,29 @@ type Pipeline struct {
 
 	batchID    string
 	batchLabel string
+
+	// Remote folders resolved from JobSpec.DestinationFolder, keyed by path.
+	// destFolderMu is held across resolution so concurrent upload workers
+	// don't create duplicate folders for the same path.
+	destFolderMu  sync.Mutex
+	destFolderIDs map[string]string
+	folderCache   *folder.FolderCache
+
+	// runID identifies this run in {run_id} default-tag placeholders and
+	// correlates its log lines and events.
+	// Derived from the state file name, matching run report and GUI run IDs.
+	runID string
+
+	// runLog receives every message logged during Run (nil if it couldn't
+	// be opened)
+	runLog *logging.RunLog
 }
 
 type workItem struct {
 	index   int
 	jobSpec models.JobSpec
 	state   *models.JobState
+	settled bool // Inputs passed the settle check; set when requeued after waiting
 }
 
 // findCommonParent finds the common parent directory of all job directories
@@ -171,10 +246,30 @@ func findCommonParent(jobs []models.JobSpec) string {
 	return common
 }
 
+// processingOrder returns job indices (0-based) in the order the feeder should
+// enqueue them: descending Priority, with CSV order preserved among equals.
+// State indices stay tied to CSV position so resume/state files are unaffected.
+func processingOrder(jobs []models.JobSpec) []int {
+	order := make([]int, len(jobs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return jobs[order[a]].Priority > jobs[order[b]].Priority
+	})
+	return order
+}
+
 // NewPipeline creates a new pipeline.
 // When existingState is non-nil, the pipeline shares the caller's state manager
 // instead of creating a duplicate. CLI callers pass nil.
 func NewPipeline(cfg *config.Config, apiClient *api.Client, jobs []models.JobSpec, stateFile string, multiPartMode bool, existingState *state.Manager, skipTarUpload bool, extraInputFiles string, decompressExtras bool) (*Pipeline, error) {
+	// Array specs run as their members, one job per index
+	jobs, err := jobarray.Expand(jobs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Normalize all job directories to absolute paths at ingress.
 	// This prevents CWD-dependent failures when paths were generated
 	// with a different working directory (especially GUI mode).
@@ -239,6 +334,18 @@ func NewPipeline(cfg *config.Config, apiClient *api.Client, jobs []models.JobSpe
 		jobQueue:      make(chan *workItem, cfg.JobWorkers*constants.DefaultQueueMultiplier),
 		activeWorkers: make(map[string]int),
 		totalJobs:     len(jobs),
+		stageStarts:   make(map[int]map[string]time.Time),
+		stageTimes:    make(map[int]map[string]time.Duration),
+		destFolderIDs: make(map[string]string),
+		folderCache:   folder.NewFolderCache(),
+
+		outputPollInterval: defaultOutputPollInterval,
+	}
+	p.outputFetcher = &apiOutputFetcher{client: apiClient, transferMgr: transferMgr}
+	p.jobSubmitter = apiClient
+
+	if err := p.resolveDependencies(); err != nil {
+		return nil, err
 	}
 
 	// Parse extraInputFiles into sharedFileIDs where possible (id: refs only at construction time;
@@ -247,9 +354,26 @@ func NewPipeline(cfg *config.Config, apiClient *api.Client, jobs []models.JobSpe
 		p.extraInputFilesRaw = extraInputFiles
 	}
 
+	if stateFile != "" {
+		p.runID = strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+	}
+
 	return p, nil
 }
 
+// RunID returns the ID the run's log lines and events are tagged with. It
+// is set by NewPipeline for runs with a state file and by Run otherwise.
+func (p *Pipeline) RunID() string {
+	return p.runID
+}
+
+// NetworkUsage returns the network bytes the process sent and received
+// during the last Run. Other traffic of the process in that time, such as a
+// GUI download running alongside, is included.
+func (p *Pipeline) NetworkUsage() netusage.Usage {
+	return p.networkUsage
+}
+
 // SetAnalysisResolver overrides the default AnalysisResolver (the API client).
 // Used in tests to inject a mock.
 func (p *Pipeline) SetAnalysisResolver(resolver AnalysisResolver) {
@@ -271,11 +395,40 @@ func (p *Pipeline) SetStateChangeCallback(callback StateChangeCallback) {
 	p.onStateChange = callback
 }
 
+// SetTarProgressCallback sets the tar progress callback function
+func (p *Pipeline) SetTarProgressCallback(callback TarProgressCallback) {
+	p.onTarProgress = callback
+}
+
+// SetRunStartCallback sets the run start callback function
+func (p *Pipeline) SetRunStartCallback(callback RunStartCallback) {
+	p.onRunStart = callback
+}
+
 // SetRmTarOnSuccess configures whether to delete local tar files after successful upload.
 func (p *Pipeline) SetRmTarOnSuccess(rm bool) {
 	p.rmTarOnSuccess = rm
 }
 
+// SetFailOnWarning configures whether a job the platform created with
+// warnings is left unsubmitted and marked failed.
+func (p *Pipeline) SetFailOnWarning(fail bool) {
+	p.failOnWarning = fail
+}
+
+// SetJobFilter restricts the run to the jobs with the given state indices
+// (1-based CSV positions), as when retrying the failed jobs of a run. The
+// other jobs are left as they are in the state file.
+func (p *Pipeline) SetJobFilter(indices []int) {
+	p.onlyIndices = make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		if idx >= 1 && idx <= len(p.jobs) {
+			p.onlyIndices[idx] = true
+		}
+	}
+	p.totalJobs = len(p.onlyIndices)
+}
+
 // SetSyncUploader sets the sync uploader for TransferService integration.
 // When set, uploads are routed through TransferService for queue visibility.
 func (p *Pipeline) SetSyncUploader(u SyncUploader) {
@@ -288,11 +441,12 @@ func (p *Pipeline) SetSyncUploader(u SyncUploader) {
 // avoid duplicate stdout output.
 func (p *Pipeline) logf(level, stage, jobName, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
+	p.runLog.Log(logging.RunLogRecord{Level: level, Stage: stage, JobName: jobName, Message: message})
 	if p.onLog != nil {
 		p.onLog(level, message, stage, jobName)
 	} else {
 		// Only log directly when no callback is set (CLI mode without Engine)
-		log.Printf("[%s] [%s] %s", level, stage, message)
+		log.Printf("[%s] [%s] %s%s", level, stage, message, logging.Correlation{RunID: p.runID}.Tag())
 	}
 }
 
@@ -312,7 +466,9 @@ func nextSkipStatus(current string) string {
 }
 
 // reportStateChange reports a state change, using callback if available
-func (p *Pipeline) reportStateChange(jobName, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
+func (p *Pipeline) reportStateChange(st *models.JobState, stage, newStatus, jobID, errorMessage string, uploadProgress float64) {
+	jobName := st.JobName
+	p.recordStageTiming(st.Index, stage, newStatus)
 	log.Printf("[DEBUG] reportStateChange called: job=%s, stage=%s, status=%s, jobID=%s, err=%s, progress=%.2f",
 		jobName, stage, newStatus, jobID, errorMessage, uploadProgress)
 	if p.onStateChange != nil {
@@ -322,6 +478,61 @@ func (p *Pipeline) reportStateChange(jobName, stage, newStatus, jobID, errorMess
 	}
 }
 
+// recordStageTiming tracks when each job enters and leaves a stage so run
+// reports can show per-stage durations. Repeated in_progress updates (upload
+// progress ticks) keep the original start time. Jobs are keyed by index since
+// names need not be unique.
+func (p *Pipeline) recordStageTiming(index int, stage, newStatus string) {
+	p.stageMu.Lock()
+	defer p.stageMu.Unlock()
+
+	switch newStatus {
+	case "in_progress":
+		starts := p.stageStarts[index]
+		if starts == nil {
+			starts = make(map[string]time.Time)
+			p.stageStarts[index] = starts
+		}
+		if _, ok := starts[stage]; !ok {
+			starts[stage] = time.Now()
+		}
+	case "completed", "failed":
+		start, ok := p.stageStarts[index][stage]
+		if !ok {
+			return
+		}
+		times := p.stageTimes[index]
+		if times == nil {
+			times = make(map[string]time.Duration)
+			p.stageTimes[index] = times
+		}
+		times[stage] = time.Since(start)
+		delete(p.stageStarts[index], stage)
+	}
+}
+
+// StageDurations returns a snapshot of per-job stage durations
+// (job index -> stage -> elapsed) for stages that have finished.
+func (p *Pipeline) StageDurations() map[int]map[string]time.Duration {
+	p.stageMu.Lock()
+	defer p.stageMu.Unlock()
+
+	out := make(map[int]map[string]time.Duration, len(p.stageTimes))
+	for job, stages := range p.stageTimes {
+		cp := make(map[string]time.Duration, len(stages))
+		for stage, d := range stages {
+			cp[stage] = d
+		}
+		out[job] = cp
+	}
+	return out
+}
+
+// StateManager returns the state manager backing this pipeline.
+func (p *Pipeline) StateManager() *state.Manager {
+	return p.stateMgr
+}
+
 // resolveAnalysisVersions resolves display names (e.g. "CPU") to versionCodes
 // (e.g. "0") for all jobs. This catches every entry path: GUI, CLI PUR (CSV),
 // legacy saved templates, JSON/SGE imports.
@@ -400,6 +611,12 @@ func (p *Pipeline) ResolveSharedFiles(ctx context.Context) error {
 	items := strings.Split(p.extraInputFilesRaw, ",")
 	seen := make(map[string]bool) // dedupe
 
+	// Shared files go with the jobs when they all upload to one folder
+	folderID, err := p.resolveDestinationFolder(ctx, p.sharedDestinationFolder())
+	if err != nil {
+		return fmt.Errorf("failed to prepare destination folder for shared files: %w", err)
+	}
+
 	for _, item := range items {
 		item = strings.TrimSpace(item)
 		if item == "" {
@@ -429,6 +646,7 @@ func (p *Pipeline) ResolveSharedFiles(ctx context.Context) error {
 			if p.syncUploader != nil {
 				cloudFile, err = p.syncUploader.UploadFileSync(ctx, SyncUploadParams{
 					LocalPath:   absPath,
+					FolderID:    folderID,
 					Name:        filepath.Base(absPath),
 					SourceLabel: "PUR",
 					BatchID:     p.batchID,
@@ -445,6 +663,7 @@ func (p *Pipeline) ResolveSharedFiles(ctx context.Context) error {
 				ratelimit.GlobalStore().BeginTransferActivity()
 				cloudFile, err = upload.UploadFile(ctx, upload.UploadParams{
 					LocalPath:      absPath,
+					FolderID:       folderID,
 					APIClient:      p.apiClient,
 					TransferHandle: transferHandle,
 					OutputWriter:   io.Discard,
@@ -464,8 +683,39 @@ func (p *Pipeline) ResolveSharedFiles(ctx context.Context) error {
 }
 
 // Run executes the pipeline
-func (p *Pipeline) Run(ctx context.Context) error {
+func (p *Pipeline) Run(ctx context.Context) (err error) {
+	if err := viewer.Check("run jobs"); err != nil {
+		return err
+	}
+
 	p.pipelineStart = time.Now()
+	if p.runID == "" {
+		p.runID = fmt.Sprintf("pur_%d", p.pipelineStart.Unix())
+	}
+
+	// The run gets its own log file, and everything started under ctx
+	// (uploads, API calls) is tagged with the run ID
+	if rl, err := logging.OpenRunLog(config.GetRunLogPath(p.runID), p.runID); err != nil {
+		log.Printf("Warning: %v", err)
+	} else {
+		p.runLog = rl
+		defer rl.Close()
+	}
+	ctx = logging.WithRunLog(logging.WithRunID(ctx, p.runID), p.runLog)
+
+	// Record the run's network usage for egress budgets, whatever the outcome
+	netStart := netusage.Current()
+	defer func() {
+		p.networkUsage = netusage.Current().Sub(netStart)
+		if err := netusage.RecordRun(config.GetNetworkUsagePath(), p.runID, p.networkUsage); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+
+	// Trace the run; the stages of each job are its children
+	ctx, span := tracing.Start(ctx, "pur.run",
+		attribute.String("pur.run_id", p.runID), attribute.Int("pur.jobs", p.totalJobs))
+	defer func() { tracing.End(span, err) }()
 
 	// Generate batch ID for grouping all uploads in this pipeline run
 	if p.syncUploader != nil {
@@ -473,12 +723,33 @@ func (p *Pipeline) Run(ctx context.Context) error {
 		p.batchLabel = fmt.Sprintf("PUR: %d jobs", p.totalJobs)
 	}
 
+	// Only one process may drive a run's state file at a time, which matters
+	// when the state directory is on a shared drive.
+	if p.stateMgr != nil {
+		if err := p.stateMgr.AcquireLock(); err != nil {
+			return fmt.Errorf("cannot start run: %w", err)
+		}
+		defer p.stateMgr.ReleaseLock()
+		if err := p.stateMgr.ReadOnly(); err != nil {
+			p.logf("WARN", "pipeline", "", "State location is read-only, progress will not be saved for resume: %v", err)
+		}
+		if n := p.stateMgr.Recovered(); n > 0 {
+			p.logf("WARN", "pipeline", "", "State file was cut short by an interrupted write; %d job row(s) dropped, those jobs start over", n)
+		}
+	}
+
 	p.logf("INFO", "pipeline", "", "Starting pipeline with %d jobs", p.totalJobs)
+	if p.onRunStart != nil {
+		p.onRunStart(p.runID, p.totalJobs)
+	}
 	p.logf("INFO", "pipeline", "", "Workers: tar=%d upload=%d job=%d", p.tarWorkers, p.uploadWorkers, p.jobWorkers)
 
 	// Resolve shared files synchronously (fast: parses IDs or uploads 1-2 files)
 	sharedStart := time.Now()
-	if err := p.ResolveSharedFiles(ctx); err != nil {
+	sharedCtx, sharedSpan := tracing.Start(ctx, "pur.shared_files")
+	err = p.ResolveSharedFiles(sharedCtx)
+	tracing.End(sharedSpan, err)
+	if err != nil {
 		return fmt.Errorf("failed to resolve shared files: %w", err)
 	}
 	p.logf("INFO", "pipeline", "", "Shared files resolved in %v", time.Since(sharedStart))
@@ -487,7 +758,9 @@ func (p *Pipeline) Run(ctx context.Context) error {
 	go func() {
 		defer close(p.versionsResolved)
 		vStart := time.Now()
-		p.resolveAnalysisVersions(ctx)
+		vCtx, vSpan := tracing.Start(ctx, "pur.resolve_versions")
+		p.resolveAnalysisVersions(vCtx)
+		vSpan.End()
 		p.logf("INFO", "pipeline", "", "Analysis versions resolved in %v", time.Since(vStart))
 	}()
 
@@ -514,18 +787,53 @@ func (p *Pipeline) Run(ctx context.Context) error {
 	stopProgress := make(chan struct{})
 	go p.progressReporter(stopProgress)
 
+	// Jobs with dependencies are submitted by their own stage once their
+	// parents complete; no job is submitted after it is done
+	workersDone := make(chan struct{})
+	dependenciesDone := make(chan struct{})
+	submitsDone := workersDone
+	if p.hasDependencies() {
+		go func() {
+			defer close(dependenciesDone)
+			p.dependencyStage(ctx, workersDone)
+		}()
+		submitsDone = dependenciesDone
+	} else {
+		close(dependenciesDone)
+	}
+
+	// Post-submit stage: wait for jobs that list output patterns to
+	// complete and download their outputs
+	outputsDone := make(chan struct{})
+	if p.hasOutputStage() {
+		go func() {
+			defer close(outputsDone)
+			p.outputStage(ctx, submitsDone)
+		}()
+	} else {
+		close(outputsDone)
+	}
+
 	// Feed work items to tar queue (context-aware to support cancellation)
 	go func() {
-		defer close(p.tarQueue)
+		defer p.finishFeeding()
 		defer close(p.feederDone)
-		for i, jobSpec := range p.jobs {
+		for _, i := range processingOrder(p.jobs) {
+			jobSpec := p.jobs[i]
 			index := i + 1
+			if p.onlyIndices != nil && !p.onlyIndices[index] {
+				continue
+			}
 			state := p.stateMgr.GetState(index)
 
 			// Initialize state if needed
 			if state == nil {
 				state = p.stateMgr.InitializeState(index, jobSpec.JobName, jobSpec.Directory)
-				p.stateMgr.Save()
+				p.stateMgr.UpdateState(state)
+			}
+			if state.ArrayName != jobSpec.ArrayName {
+				state.ArrayName = jobSpec.ArrayName
+				p.stateMgr.UpdateState(state)
 			}
 
 			item := &workItem{
@@ -534,13 +842,19 @@ func (p *Pipeline) Run(ctx context.Context) error {
 				state:   state,
 			}
 
+			// Pre-submission safety check (traversal, command injection) for
+			// jobs not yet created on the platform
+			if state.JobID == "" && !p.passesJobChecks(item) {
+				continue
+			}
+
 			// Submit-existing mode — skip tar/upload, go directly to job creation
 			if p.skipTarUpload && state.TarStatus != "success" {
 				item.state.TarStatus = "skipped"
 				item.state.UploadStatus = "skipped"
 				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "tar", "skipped", "", "", 0.0)
-				p.reportStateChange(item.state.JobName, "upload", "skipped", "", "", 0.0)
+				p.reportStateChange(item.state, "tar", "skipped", "", "", 0.0)
+				p.reportStateChange(item.state, "upload", "skipped", "", "", 0.0)
 				select {
 				case <-ctx.Done():
 					return
@@ -562,10 +876,10 @@ func (p *Pipeline) Run(ctx context.Context) error {
 				item.state.UploadStatus = nextSkipStatus(item.state.UploadStatus)
 				p.stateMgr.UpdateState(item.state)
 				if item.state.TarStatus == "skipped" {
-					p.reportStateChange(item.state.JobName, "tar", "skipped", "", "", 0.0)
+					p.reportStateChange(item.state, "tar", "skipped", "", "", 0.0)
 				}
 				if item.state.UploadStatus == "skipped" {
-					p.reportStateChange(item.state.JobName, "upload", "skipped", "", "", 0.0)
+					p.reportStateChange(item.state, "upload", "skipped", "", "", 0.0)
 				}
 				select {
 				case <-ctx.Done():
@@ -577,8 +891,11 @@ func (p *Pipeline) Run(ctx context.Context) error {
 
 			// Determine which queue to start in based on current state
 			if state.TarStatus == "success" && state.UploadStatus == "success" && state.JobID != "" {
-				// Already uploaded and job created, check if we need to submit
-				if state.SubmitStatus == "pending" && shouldSubmit(jobSpec.SubmitMode) {
+				// Already uploaded and job created, check if we need to submit.
+				// Jobs held back by --fail-on-warning are submitted once the
+				// run is resumed without it.
+				heldBack := state.SubmitStatus == "failed" && state.Warnings != ""
+				if (state.SubmitStatus == "pending" || heldBack) && shouldSubmit(jobSpec.SubmitMode) {
 					select {
 					case <-ctx.Done():
 						return
@@ -592,8 +909,9 @@ func (p *Pipeline) Run(ctx context.Context) error {
 					return
 				case p.jobQueue <- item:
 				}
-			} else if state.TarStatus == "success" {
-				// Already tarred, need to upload
+			} else if exists, _ := allTarsExist(state.TarPaths()); state.TarStatus == "success" && exists {
+				// Already tarred, need to upload. Tars evicted from the cache
+				// since take the tar path below and are rebuilt.
 				select {
 				case <-ctx.Done():
 					return
@@ -601,8 +919,10 @@ func (p *Pipeline) Run(ctx context.Context) error {
 				}
 			} else {
 				// Need to tar
+				p.holdTarItem()
 				select {
 				case <-ctx.Done():
+					p.releaseTarItem()
 					return
 				case p.tarQueue <- item:
 				}
@@ -610,16 +930,67 @@ func (p *Pipeline) Run(ctx context.Context) error {
 		}
 	}()
 
-	// Wait for all workers to complete
+	// Wait for all workers to complete, then for the dependency and output
+	// stages
 	wg.Wait()
+	close(workersDone)
+	<-dependenciesDone
+	<-outputsDone
 	close(stopProgress)
 
-	p.logf("INFO", "pipeline", "", "Pipeline completed: %d/%d jobs finished in %v",
-		p.completedJobs, p.totalJobs, time.Since(p.pipelineStart))
+	p.logf("INFO", "pipeline", "", "Pipeline completed: %d/%d jobs finished in %v (network: %s)",
+		p.completedJobs, p.totalJobs, time.Since(p.pipelineStart), netusage.Current().Sub(netStart))
+	p.logArrayProgress(nil)
 
 	return nil
 }
 
+// passesJobChecks screens a job spec before any work is done for it: the
+// required fields and value ranges (validation.ValidateJobFields), then the
+// safety checks (validation.ValidateJobSafety), each rejecting with its own
+// message. Safety warnings are logged; on errors the job is marked failed
+// and false is returned.
+func (p *Pipeline) passesJobChecks(item *workItem) bool {
+	if errs := validation.ValidateJobFields(item.jobSpec); len(errs) > 0 {
+		p.rejectJob(item, "invalid job spec", errs)
+		return false
+	}
+
+	errs, warnings := validation.ValidateJobSafety(item.jobSpec, p.validationMode())
+	for _, w := range warnings {
+		p.logf("WARN", "validate", item.state.JobName, "%s", w)
+	}
+	if len(errs) > 0 {
+		p.rejectJob(item, "safety check failed", errs)
+		return false
+	}
+	return true
+}
+
+// rejectJob marks a job that failed a pre-submission check as failed.
+func (p *Pipeline) rejectJob(item *workItem, reason string, errs []string) {
+	msg := reason + ": " + strings.Join(errs, "; ")
+	p.logf("ERROR", "validate", item.state.JobName, "REJECTED: %s", msg)
+	item.state.SubmitStatus = "failed"
+	item.state.ErrorMessage = msg
+	p.stateMgr.UpdateState(item.state)
+	p.reportStateChange(item.state, "create", "failed", "", item.state.ErrorMessage, 0.0)
+}
+
+// validationMode returns the configured job_validation_mode.
+func (p *Pipeline) validationMode() string {
+	if p.cfg != nil {
+		return p.cfg.JobValidationMode
+	}
+	return validation.SafetyPermissive
+}
+
+// validateSpec runs validation.ValidateJobSpecMode on spec with the
+// configured validation mode.
+func (p *Pipeline) validateSpec(spec models.JobSpec) (errs, warnings []string) {
+	return validation.ValidateJobSpecMode(spec, p.validationMode())
+}
+
 // tarWorker processes tar operations.
 func (p *Pipeline) tarWorker(ctx context.Context, wg *sync.WaitGroup, workerID int) {
 	defer wg.Done()
@@ -633,6 +1004,14 @@ func (p *Pipeline) tarWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 				goto shutdown
 			}
 
+			// A job whose inputs are still being written waits outside the
+			// worker and comes back through tarQueue
+			deferred := p.deferUntilSettled(ctx, item)
+			p.releaseTarItem()
+			if deferred {
+				continue
+			}
+
 			p.setActiveWorker("tar", 1)
 
 			// Belt-and-suspenders normalization for paths from legacy CSV/state files
@@ -646,10 +1025,10 @@ func (p *Pipeline) tarWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 
 			// Check if already tarred
 			if item.state.TarStatus == "success" {
-				exists, err := tar.ValidateTarExists(item.state.TarPath)
+				exists, err := allTarsExist(item.state.TarPaths())
 				if err != nil {
 					p.logf("WARN", "tar", item.state.JobName,
-						"Error checking existing tar file %s: %v (will recreate)", item.state.TarPath, err)
+						"Error checking existing tar file %s: %v (will recreate)", strings.Join(item.state.TarPaths(), ", "), err)
 				} else if exists {
 					p.setActiveWorker("tar", -1)
 					select {
@@ -662,42 +1041,24 @@ func (p *Pipeline) tarWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 			}
 
 			// Resolve tar source directory, applying TarSubpath if set
-			tarSourceDir := item.jobSpec.Directory
-			if item.jobSpec.TarSubpath != "" {
-				tarSourceDir = filepath.Join(item.jobSpec.Directory, item.jobSpec.TarSubpath)
-				// Path traversal guard: prevent ../ escape outside run directory
-				absSource, errAbs := filepath.Abs(tarSourceDir)
-				absRunDir, errRun := filepath.Abs(item.jobSpec.Directory)
-				rel, errRel := filepath.Rel(absRunDir, absSource)
-				if errAbs != nil || errRun != nil || errRel != nil || strings.HasPrefix(rel, "..") {
-					p.logf("ERROR", "tar", item.state.JobName,
-						"REJECTED: tar subpath '%s' escapes run directory", item.jobSpec.TarSubpath)
-					item.state.TarStatus = "failed"
-					item.state.SubmitStatus = "failed"
-					item.state.ErrorMessage = fmt.Sprintf("tar subpath '%s' escapes run directory", item.jobSpec.TarSubpath)
-					p.stateMgr.UpdateState(item.state)
-					p.reportStateChange(item.state.JobName, "tar", "failed", "", item.state.ErrorMessage, 0.0)
-					p.setActiveWorker("tar", -1)
-					continue
-				}
-				// Verify subpath exists
-				if _, errStat := os.Stat(tarSourceDir); os.IsNotExist(errStat) {
-					p.logf("ERROR", "tar", item.state.JobName,
-						"Tar subpath '%s' does not exist in %s", item.jobSpec.TarSubpath, item.jobSpec.Directory)
-					item.state.TarStatus = "failed"
-					item.state.SubmitStatus = "failed"
-					item.state.ErrorMessage = fmt.Sprintf("tar subpath '%s' does not exist in %s", item.jobSpec.TarSubpath, item.jobSpec.Directory)
-					p.stateMgr.UpdateState(item.state)
-					p.reportStateChange(item.state.JobName, "tar", "failed", "", item.state.ErrorMessage, 0.0)
-					p.setActiveWorker("tar", -1)
-					continue
-				}
+			tarSourceDir, err := resolveTarSourceDir(item.jobSpec)
+			if err != nil {
+				p.logf("ERROR", "tar", item.state.JobName, "%v", err)
+				item.state.TarStatus = "failed"
+				item.state.SubmitStatus = "failed"
+				item.state.ErrorMessage = err.Error()
+				p.stateMgr.UpdateState(item.state)
+				p.reportStateChange(item.state, "tar", "failed", "", item.state.ErrorMessage, 0.0)
+				p.setActiveWorker("tar", -1)
+				continue
 			}
 
-			tarPath := tar.GenerateTarPath(tarSourceDir, p.tempDir, p.cfg.TarCompression)
-			item.state.TarPath = tarPath
+			if err := p.waitForBlackout(ctx, item, "tar"); err != nil {
+				p.setActiveWorker("tar", -1)
+				goto shutdown
+			}
 
-			p.reportStateChange(item.state.JobName, "tar", "in_progress", "", "", 0.0)
+			p.reportStateChange(item.state, "tar", "in_progress", "", "", 0.0)
 
 			p.firstTarOnce.Do(func() {
 				p.logf("INFO", "tar", item.state.JobName,
@@ -705,40 +1066,28 @@ func (p *Pipeline) tarWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 					time.Since(p.pipelineStart))
 			})
 
-			p.logf("INFO", "tar", item.state.JobName, "Creating archive: %s -> %s", tarSourceDir, tarPath)
-
-			var err error
-			if len(p.cfg.IncludePatterns) > 0 || len(p.cfg.ExcludePatterns) > 0 || p.cfg.FlattenTar {
-				if len(p.cfg.IncludePatterns) > 0 {
-					p.logf("INFO", "tar", item.state.JobName, "Include patterns: %v", p.cfg.IncludePatterns)
-				}
-				if len(p.cfg.ExcludePatterns) > 0 {
-					p.logf("INFO", "tar", item.state.JobName, "Exclude patterns: %v", p.cfg.ExcludePatterns)
-				}
-				if p.cfg.FlattenTar {
-					p.logf("INFO", "tar", item.state.JobName, "Flatten mode enabled")
-				}
-				err = tar.CreateTarGzWithOptions(tarSourceDir, tarPath, p.multiPartMode,
-					p.cfg.IncludePatterns, p.cfg.ExcludePatterns, p.cfg.FlattenTar, p.cfg.TarCompression)
-			} else {
-				err = tar.CreateTarGz(tarSourceDir, tarPath, p.multiPartMode, p.cfg.TarCompression)
-			}
-
+			tarCtx, tarSpan := tracing.Start(ctx, "pur.tar", jobAttr(item))
+			err = p.createArchives(tarCtx, item, tarSourceDir)
+			tracing.End(tarSpan, err)
 			if err != nil {
 				p.logf("ERROR", "tar", item.state.JobName, "Failed: %v", err)
 				item.state.TarStatus = "failed"
 				item.state.SubmitStatus = "failed"
 				item.state.ErrorMessage = err.Error()
 				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "tar", "failed", "", err.Error(), 0.0)
+				p.reportStateChange(item.state, "tar", "failed", "", err.Error(), 0.0)
 				p.setActiveWorker("tar", -1)
 				continue
 			}
 
 			item.state.TarStatus = "success"
 			item.state.ErrorMessage = ""
+			if item.state.UploadStatus != "success" {
+				// Part file IDs from an earlier attempt belong to the old tars
+				item.state.FileID = ""
+			}
 			p.stateMgr.UpdateState(item.state)
-			p.reportStateChange(item.state.JobName, "tar", "completed", "", "", 0.0)
+			p.reportStateChange(item.state, "tar", "completed", "", "", 0.0)
 			p.logf("INFO", "tar", item.state.JobName, "Success")
 
 			p.setActiveWorker("tar", -1)
@@ -762,6 +1111,371 @@ shutdown:
 	p.mu.Unlock()
 }
 
+// holdTarItem counts an item about to be sent to tarQueue.
+func (p *Pipeline) holdTarItem() {
+	p.mu.Lock()
+	p.tarPending++
+	p.mu.Unlock()
+}
+
+// releaseTarItem uncounts an item taken off tarQueue (or not sent after all),
+// closing tarQueue if it was the last one after the feeder finished.
+func (p *Pipeline) releaseTarItem() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tarPending--
+	if p.feederFinished && p.tarPending == 0 {
+		close(p.tarQueue)
+	}
+}
+
+// finishFeeding records that the feeder sends nothing more, closing tarQueue
+// unless items are still pending.
+func (p *Pipeline) finishFeeding() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.feederFinished = true
+	if p.tarPending == 0 {
+		close(p.tarQueue)
+	}
+}
+
+// settleOptions returns the input settle check from settle_seconds,
+// settle_timeout_seconds and the tar include/exclude patterns.
+func (p *Pipeline) settleOptions() tar.SettleOptions {
+	return tar.SettleOptions{
+		StableFor:       time.Duration(p.cfg.SettleSeconds) * time.Second,
+		Timeout:         time.Duration(p.cfg.SettleTimeoutSeconds) * time.Second,
+		IncludePatterns: p.cfg.IncludePatterns,
+		ExcludePatterns: p.cfg.ExcludePatterns,
+	}
+}
+
+// deferUntilSettled checks once whether any file going into the job's archive
+// is still being written, so a pre-processor's output is not archived
+// truncated. If so, the job is reported in the "waiting" tar status and a
+// goroutine waits for the files to settle and requeues it, leaving the tar
+// worker free for other jobs. Reports whether the job was deferred. Errors
+// resolving or scanning the source are left to the tar step to report.
+func (p *Pipeline) deferUntilSettled(ctx context.Context, item *workItem) bool {
+	if item.settled || p.cfg.SettleSeconds <= 0 {
+		return false
+	}
+	dir, err := resolveTarSourceDir(item.jobSpec)
+	if err != nil {
+		return false
+	}
+	opts := p.settleOptions()
+	unsettled, err := tar.Unsettled(dir, opts)
+	if err != nil || len(unsettled) == 0 {
+		return false
+	}
+
+	p.logf("WARN", "tar", item.state.JobName,
+		"Waiting for files to settle (%d still being written, e.g. %s)", len(unsettled), unsettled[0])
+	p.reportStateChange(item.state, "tar", "waiting", "", "", 0.0)
+	p.holdTarItem()
+	go func() {
+		if err := tar.WaitForSettle(ctx, dir, opts); err != nil {
+			defer p.releaseTarItem()
+			if ctx.Err() != nil {
+				return
+			}
+			p.logf("ERROR", "tar", item.state.JobName, "Input files did not settle: %v", err)
+			item.state.TarStatus = "failed"
+			item.state.SubmitStatus = "failed"
+			item.state.ErrorMessage = err.Error()
+			p.stateMgr.UpdateState(item.state)
+			p.reportStateChange(item.state, "tar", "failed", "", err.Error(), 0.0)
+			return
+		}
+		// The held count passes to the requeued item
+		item.settled = true
+		select {
+		case <-ctx.Done():
+			p.releaseTarItem()
+		case p.tarQueue <- item:
+		}
+	}()
+	return true
+}
+
+// waitForBlackout holds the job while a blackout window (blackout_windows) is
+// active, reporting it in the "waiting" status of stage. Work already under
+// way is not interrupted. Returns an error only if ctx is cancelled.
+func (p *Pipeline) waitForBlackout(ctx context.Context, item *workItem, stage string) error {
+	waited := false
+	err := resources.WaitForBlackout(ctx, func(until time.Time) {
+		waited = true
+		p.logf("INFO", stage, item.state.JobName, "Paused for blackout window until %s", until.Format("15:04"))
+		p.reportStateChange(item.state, stage, "waiting", "", "", 0.0)
+	})
+	if err == nil && waited {
+		p.logf("INFO", stage, item.state.JobName, "Blackout window over, resuming")
+	}
+	return err
+}
+
+// createArchives archives tarSourceDir for the job and records the tar
+// path(s) in item.state.TarPath. With tar_split_mode set, the directory is
+// split into several tars (see tar.PlanSplit) whose entries keep the full
+// layout, so decompressing them all on the cluster reassembles it. Files
+// another process holds locked are handled per tar_locked_files, and text
+// inputs are checked per text_normalize.
+func (p *Pipeline) createArchives(ctx context.Context, item *workItem, tarSourceDir string) error {
+	locked := &tar.LockedFiles{
+		Policy:     p.cfg.TarLockedFiles,
+		RetryDelay: time.Duration(p.cfg.TarLockRetrySeconds) * time.Second,
+		Retries:    p.cfg.TarLockRetries,
+		Done:       ctx.Done(),
+	}
+	defer p.recordLockedFiles(item, locked)
+	text := p.textNormalizer(item)
+	defer p.recordNormalizedFiles(item, text)
+
+	if len(p.cfg.IncludePatterns) > 0 {
+		p.logf("INFO", "tar", item.state.JobName, "Include patterns: %v", p.cfg.IncludePatterns)
+	}
+	if len(p.cfg.ExcludePatterns) > 0 {
+		p.logf("INFO", "tar", item.state.JobName, "Exclude patterns: %v", p.cfg.ExcludePatterns)
+	}
+
+	if tar.IsZipSource(tarSourceDir) {
+		return p.createArchiveFromZip(item, tarSourceDir, text)
+	}
+
+	parts, err := p.planTarSplit(item, tarSourceDir)
+	if err != nil {
+		return err
+	}
+
+	progress := p.newTarProgress(item, tarSourceDir)
+	if err := p.writeArchives(ctx, item, tarSourceDir, parts, locked, text, progress); err != nil {
+		return err
+	}
+	progress.Finish()
+	return nil
+}
+
+// createArchiveFromZip writes the job's tar straight from the run folder
+// (TarSubpath) inside the ZIP archive zipPath, without extracting it. Split
+// and locked-file settings don't apply to ZIP sources.
+func (p *Pipeline) createArchiveFromZip(item *workItem, zipPath string, text *tar.TextNormalizer) error {
+	subtree := item.jobSpec.TarSubpath
+	tarPath := tar.GenerateTarPath(filepath.Join(zipPath, subtree), p.tempDir, p.cfg.TarCompression)
+	item.state.TarPath = models.JoinParts([]string{tarPath})
+	if tar.NormalizeSplitMode(p.cfg.TarSplitMode) != tar.SplitNone {
+		p.logf("WARN", "tar", item.state.JobName, "ZIP sources are not split, writing a single archive")
+	}
+	p.logf("INFO", "tar", item.state.JobName, "Creating archive from ZIP: %s (%s) -> %s", zipPath, subtree, tarPath)
+
+	progress, err := tar.NewZipProgressTracker(zipPath, subtree, p.cfg.IncludePatterns, p.cfg.ExcludePatterns,
+		constants.TarProgressInterval, p.tarProgressReporter(item.state.JobName))
+	if err != nil {
+		p.logf("WARN", "tar", item.state.JobName, "Cannot count files to archive, no progress will be shown: %v", err)
+		progress = nil
+	}
+	if err := tar.CreateTarGzFromZip(zipPath, subtree, tarPath, p.cfg.IncludePatterns, p.cfg.ExcludePatterns,
+		p.cfg.FlattenTar, p.cfg.TarCompression, text, progress); err != nil {
+		return err
+	}
+	progress.Finish()
+	return nil
+}
+
+// writeArchives writes the job's single tar, or one tar per part. Split
+// parts not yet started wait out blackout windows.
+func (p *Pipeline) writeArchives(ctx context.Context, item *workItem, tarSourceDir string, parts []tar.Part, locked *tar.LockedFiles, text *tar.TextNormalizer, progress *tar.ProgressTracker) error {
+	if len(parts) == 0 {
+		tarPath := tar.GenerateTarPath(tarSourceDir, p.tempDir, p.cfg.TarCompression)
+		item.state.TarPath = models.JoinParts([]string{tarPath})
+		p.logf("INFO", "tar", item.state.JobName, "Creating archive: %s -> %s", tarSourceDir, tarPath)
+
+		// The system tar cannot skip or retry locked files, or rewrite text files
+		if len(p.cfg.IncludePatterns) > 0 || len(p.cfg.ExcludePatterns) > 0 || p.cfg.FlattenTar ||
+			tar.NormalizeLockedPolicy(p.cfg.TarLockedFiles) != tar.LockedFail || text != nil {
+			if p.cfg.FlattenTar {
+				p.logf("INFO", "tar", item.state.JobName, "Flatten mode enabled")
+			}
+			return tar.CreateTarGzWithOptions(tarSourceDir, tarPath, p.multiPartMode,
+				p.cfg.IncludePatterns, p.cfg.ExcludePatterns, p.cfg.FlattenTar, p.cfg.TarCompression, locked, text, progress)
+		}
+		return tar.CreateTarGz(tarSourceDir, tarPath, p.multiPartMode, p.cfg.TarCompression, progress)
+	}
+
+	p.logf("INFO", "tar", item.state.JobName, "Splitting %s into %d archives (%s)",
+		tarSourceDir, len(parts), tar.NormalizeSplitMode(p.cfg.TarSplitMode))
+	tarPaths := make([]string, len(parts))
+	for i, part := range parts {
+		if i > 0 && !resources.BlackoutUntil(time.Now()).IsZero() {
+			if err := p.waitForBlackout(ctx, item, "tar"); err != nil {
+				return err
+			}
+			p.reportStateChange(item.state, "tar", "in_progress", "", "", 0.0)
+		}
+		tarPaths[i] = tar.GeneratePartTarPath(tarSourceDir, p.tempDir, p.cfg.TarCompression, i)
+		p.logf("INFO", "tar", item.state.JobName, "Creating archive %d/%d: %s (%s) -> %s",
+			i+1, len(parts), strings.Join(part.Members, ", "), cloud.FormatBytes(part.Size), tarPaths[i])
+		if err := tar.CreateTarPart(tarSourceDir, tarPaths[i], part.Members, p.multiPartMode,
+			p.cfg.IncludePatterns, p.cfg.ExcludePatterns, p.cfg.TarCompression, locked, text, progress); err != nil {
+			return fmt.Errorf("archive %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+	item.state.TarPath = models.JoinParts(tarPaths)
+	return nil
+}
+
+// newTarProgress returns a tracker that reports the job's archive progress
+// to the tar progress callback and, every constants.TarProgressLogInterval,
+// as a log line. Progress is best effort: if the files to archive cannot be
+// counted, the archive is written without it.
+func (p *Pipeline) newTarProgress(item *workItem, tarSourceDir string) *tar.ProgressTracker {
+	progress, err := tar.NewProgressTracker(tarSourceDir, p.cfg.IncludePatterns, p.cfg.ExcludePatterns,
+		constants.TarProgressInterval, p.tarProgressReporter(item.state.JobName))
+	if err != nil {
+		p.logf("WARN", "tar", item.state.JobName, "Cannot count files to archive, no progress will be shown: %v", err)
+		return nil
+	}
+	return progress
+}
+
+// tarProgressReporter returns the OnUpdate function of a job's tar progress
+// tracker: it passes updates to the tar progress callback and logs them.
+func (p *Pipeline) tarProgressReporter(jobName string) func(tar.Progress) {
+	var lastLog time.Time
+	return func(pr tar.Progress) {
+		if p.onTarProgress != nil {
+			p.onTarProgress(jobName, pr)
+		}
+		if !pr.Done && time.Since(lastLog) >= constants.TarProgressLogInterval && pr.Elapsed >= constants.TarProgressLogInterval {
+			lastLog = time.Now()
+			p.logf("INFO", "tar", jobName, "%s", FormatTarProgress(pr))
+		}
+	}
+}
+
+// FormatTarProgress describes archive progress for logs and progress lines,
+// e.g. "43% - 1200/3400 files, 130.2 GB of 300.0 GB (Run_1/mesh.cas)".
+func FormatTarProgress(pr tar.Progress) string {
+	msg := fmt.Sprintf("%.0f%% - %d/%d files, %s of %s", pr.Fraction()*100, pr.Files, pr.TotalFiles,
+		cloud.FormatBytes(pr.Bytes), cloud.FormatBytes(pr.TotalBytes))
+	if pr.CurrentFile != "" {
+		msg += " (" + pr.CurrentFile + ")"
+	}
+	return msg
+}
+
+// recordLockedFiles logs how locked files were handled for the job, records
+// the skipped ones in its state and releases any snapshots.
+func (p *Pipeline) recordLockedFiles(item *workItem, locked *tar.LockedFiles) {
+	if err := locked.Close(); err != nil {
+		p.logf("WARN", "tar", item.state.JobName, "%v", err)
+	}
+	if locked.SnapshotErr != nil {
+		p.logf("WARN", "tar", item.state.JobName, "Shadow copy unavailable, skipping locked files instead: %v", locked.SnapshotErr)
+	}
+	if len(locked.FromSnapshot) > 0 {
+		p.logf("INFO", "tar", item.state.JobName, "Read %d locked file(s) from a shadow copy: %s",
+			len(locked.FromSnapshot), strings.Join(locked.FromSnapshot, ", "))
+	}
+	if len(locked.Skipped) > 0 {
+		p.logf("WARN", "tar", item.state.JobName, "Skipped %d locked file(s): %s",
+			len(locked.Skipped), strings.Join(locked.Skipped, ", "))
+	}
+	item.state.SkippedFiles = models.JoinParts(locked.Skipped)
+}
+
+// textNormalizer returns the job's text input normalizer, or nil when
+// text_normalize is off or the job opts out (NoTextNormalize).
+func (p *Pipeline) textNormalizer(item *workItem) *tar.TextNormalizer {
+	if item.jobSpec.NoTextNormalize {
+		return nil
+	}
+	extensions := p.cfg.TextNormalizeExtensions
+	if len(extensions) == 0 {
+		extensions = strings.Split(constants.DefaultTextNormalizeExtensions, ";")
+	}
+	return tar.NewTextNormalizer(p.cfg.TextNormalize, extensions)
+}
+
+// recordNormalizedFiles logs the text inputs found with CRLF line endings
+// or a BOM and records them in the job's state.
+func (p *Pipeline) recordNormalizedFiles(item *workItem, text *tar.TextNormalizer) {
+	item.state.NormalizedFiles = ""
+	if text == nil || len(text.Changed) == 0 {
+		return
+	}
+	if text.Fixes() {
+		p.logf("INFO", "tar", item.state.JobName, "Archived %d text file(s) with LF line endings and no BOM: %s",
+			len(text.Changed), strings.Join(text.Changed, ", "))
+	} else {
+		p.logf("WARN", "tar", item.state.JobName, "%d text file(s) have CRLF line endings or a BOM (set text_normalize=fix to correct them): %s",
+			len(text.Changed), strings.Join(text.Changed, ", "))
+	}
+	item.state.NormalizedFiles = models.JoinParts(text.Changed)
+}
+
+// planTarSplit returns the parts to split the job's directory into, or nil
+// for a single tar. Splitting is skipped, with a warning, for jobs it would
+// break: flattened tars lose the layout the parts rely on, and parts that
+// are not decompressed cannot be reassembled.
+func (p *Pipeline) planTarSplit(item *workItem, tarSourceDir string) ([]tar.Part, error) {
+	mode := tar.NormalizeSplitMode(p.cfg.TarSplitMode)
+	if mode == tar.SplitNone {
+		return nil, nil
+	}
+	if p.cfg.FlattenTar {
+		p.logf("WARN", "tar", item.state.JobName, "Tar split disabled: not supported with flatten mode")
+		return nil, nil
+	}
+	if item.jobSpec.NoDecompress {
+		p.logf("WARN", "tar", item.state.JobName, "Tar split disabled: job inputs are not decompressed")
+		return nil, nil
+	}
+	parts, err := tar.PlanSplit(tarSourceDir, mode, p.cfg.TarSplitParts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan tar split: %w", err)
+	}
+	return parts, nil
+}
+
+// allTarsExist reports whether every tar in paths exists and is non-empty.
+func allTarsExist(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return false, nil
+	}
+	for _, path := range paths {
+		exists, err := tar.ValidateTarExists(path)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// jobTags returns the tags a created job gets: the job's own tags, the
+// workspace default tags from config with {version} and {run_id}
+// placeholders expanded, and the Interlink marker tag, which
+// api.Client.CreateJob applies itself.
+func (p *Pipeline) jobTags(spec models.JobSpec) []string {
+	defaults := tags.ExpandPlaceholders(p.cfg.DefaultTags, map[string]string{
+		"version": version.Version,
+		"run_id":  p.runID,
+	})
+	all := append(append([]string(nil), spec.Tags...), defaults...)
+	return tags.NormalizeTags(append(all, constants.DefaultAdminJobTag))
+}
+
+// recordSubmitted adds a submitted job to the platform's job history for
+// runtime predictions. Failures are logged, not returned: the job was
+// submitted.
+func (p *Pipeline) recordSubmitted(spec models.JobSpec, jobID string) {
+	path := config.GetJobHistoryPath(p.cfg.APIBaseURL)
+	if err := runtimes.Append(path, runtimes.NewEntry(spec, jobID)); err != nil {
+		p.logf("WARN", "job", spec.JobName, "Failed to record job history: %v", err)
+	}
+}
+
 // uploadWorker processes upload operations.
 func (p *Pipeline) uploadWorker(ctx context.Context, wg *sync.WaitGroup, workerID int) {
 	defer wg.Done()
@@ -788,8 +1502,17 @@ func (p *Pipeline) uploadWorker(ctx context.Context, wg *sync.WaitGroup, workerI
 				continue
 			}
 
-			p.logf("INFO", "upload", item.state.JobName, "Uploading: %s", item.state.TarPath)
-			p.reportStateChange(item.state.JobName, "upload", "in_progress", "", "", 0.0)
+			if err := p.waitForBlackout(ctx, item, "upload"); err != nil {
+				p.setActiveWorker("upload", -1)
+				goto shutdown
+			}
+
+			if tarPaths := item.state.TarPaths(); len(tarPaths) > 1 {
+				p.logf("INFO", "upload", item.state.JobName, "Uploading %d parts", len(tarPaths))
+			} else if len(tarPaths) == 1 {
+				p.logf("INFO", "upload", item.state.JobName, "Uploading: %s", tarPaths[0])
+			}
+			p.reportStateChange(item.state, "upload", "in_progress", "", "", 0.0)
 
 			// Per-upload proxy warmup for Basic proxy mode.
 			// Prevents proxy session expiry during long batch runs.
@@ -799,89 +1522,47 @@ func (p *Pipeline) uploadWorker(ctx context.Context, wg *sync.WaitGroup, workerI
 				}
 			}
 
-			var cloudFile *models.CloudFile
-			var err error
-			maxRetries := p.cfg.MaxRetries
-			if maxRetries < 1 {
-				maxRetries = 1
-			}
-
-			fileInfo, err := os.Stat(item.state.TarPath)
+			folderID, err := p.resolveDestinationFolder(ctx, item.jobSpec.DestinationFolder)
 			if err != nil {
-				p.logf("ERROR", "upload", item.state.JobName, "Failed to stat file: %v", err)
+				p.logf("ERROR", "upload", item.state.JobName, "Failed to prepare destination folder: %v", err)
 				item.state.UploadStatus = "failed"
 				item.state.SubmitStatus = "failed"
 				item.state.ErrorMessage = err.Error()
 				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "upload", "failed", "", err.Error(), 0.0)
+				p.reportStateChange(item.state, "upload", "failed", "", err.Error(), 0.0)
 				p.setActiveWorker("upload", -1)
 				continue
 			}
 
-			var transferHandle *transfer.Transfer
-
-			for attempt := 1; attempt <= maxRetries; attempt++ {
-				progressCallback := func(progress float64) {
-					p.reportStateChange(item.state.JobName, "upload", "in_progress", "", "", progress)
-				}
-
-				if p.syncUploader != nil {
-					cloudFile, err = p.syncUploader.UploadFileSync(ctx, SyncUploadParams{
-						LocalPath:             item.state.TarPath,
-						Name:                  filepath.Base(item.state.TarPath),
-						SourceLabel:           "PUR",
-						BatchID:               p.batchID,
-						BatchLabel:            p.batchLabel,
-						ExtraProgressCallback: progressCallback,
-					})
-				} else {
-					// CLI fallback: direct upload.
-					// Signal active transfer since CLI fallback bypasses RunBatch.
-					if transferHandle == nil {
-						transferHandle = p.transferMgr.AllocateTransfer(fileInfo.Size(), 1)
-					}
-					ratelimit.GlobalStore().BeginTransferActivity()
-					cloudFile, err = upload.UploadFile(ctx, upload.UploadParams{
-						LocalPath:        item.state.TarPath,
-						FolderID:         "",
-						APIClient:        p.apiClient,
-						ProgressCallback: progressCallback,
-						TransferHandle:   transferHandle,
-						OutputWriter:     io.Discard,
-					})
-					ratelimit.GlobalStore().EndTransferActivity()
-				}
+			tarPaths := item.state.TarPaths()
+			fileIDs := item.state.FileIDs()
+			if len(fileIDs) != len(tarPaths) {
+				fileIDs = make([]string, len(tarPaths))
+			}
 
+			uploadCtx, uploadSpan := tracing.Start(ctx, "pur.upload", jobAttr(item), attribute.Int("pur.parts", len(tarPaths)))
+			switch len(tarPaths) {
+			case 0:
+				err = fmt.Errorf("no tar file recorded for job")
+			case 1:
+				var cloudFile *models.CloudFile
+				cloudFile, err = p.uploadTar(uploadCtx, item, tarPaths[0], folderID, func(progress float64) {
+					p.reportStateChange(item.state, "upload", "in_progress", "", "", progress)
+				})
 				if err == nil {
-					break
-				}
-
-				errStr := err.Error()
-				isTimeout := strings.Contains(errStr, "timeout") ||
-					strings.Contains(errStr, "SocketTimeoutException") ||
-					strings.Contains(errStr, "connection reset") ||
-					strings.Contains(errStr, "EOF")
-
-				if isTimeout && attempt < maxRetries {
-					p.logf("WARN", "upload", item.state.JobName, "Detected proxy timeout, forcing fresh auth and retrying...")
-					// Warmup proxy on retry to re-establish session
-					if strings.ToLower(p.cfg.ProxyMode) == "basic" {
-						_ = inthttp.WarmupProxyConnection(ctx, p.cfg)
-					}
-					if strings.ToLower(p.cfg.ProxyMode) == "basic" || strings.ToLower(p.cfg.ProxyMode) == "ntlm" {
-						p.logf("INFO", "upload", item.state.JobName, "Waiting 2 seconds before retry...")
-						time.Sleep(2 * time.Second)
-					}
-					p.logf("INFO", "upload", item.state.JobName, "Retry attempt %d/%d", attempt+1, maxRetries)
-					continue
+					fileIDs[0] = cloudFile.ID
 				}
-
-				break
+			default:
+				err = p.uploadTarParts(uploadCtx, item, tarPaths, fileIDs, folderID)
 			}
+			tracing.End(uploadSpan, err)
+
+			// Keep the IDs of parts that did upload so a resume skips them
+			item.state.FileID = models.JoinParts(fileIDs)
 
 			if err != nil {
 				if strings.Contains(err.Error(), "timeout") {
-					p.logf("ERROR", "upload", item.state.JobName, "Failed after %d retries: %v", maxRetries, err)
+					p.logf("ERROR", "upload", item.state.JobName, "Failed after %d retries: %v", max(p.cfg.MaxRetries, 1), err)
 				} else {
 					p.logf("ERROR", "upload", item.state.JobName, "Failed: %v", err)
 				}
@@ -889,30 +1570,28 @@ func (p *Pipeline) uploadWorker(ctx context.Context, wg *sync.WaitGroup, workerI
 				item.state.SubmitStatus = "failed"
 				item.state.ErrorMessage = err.Error()
 				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "upload", "failed", "", err.Error(), 0.0)
+				p.reportStateChange(item.state, "upload", "failed", "", err.Error(), 0.0)
 				p.setActiveWorker("upload", -1)
-				if transferHandle != nil {
-					transferHandle.Complete()
-				}
 				continue
 			}
 
-			item.state.FileID = cloudFile.ID
 			item.state.UploadStatus = "success"
 			item.state.ErrorMessage = ""
 			p.stateMgr.UpdateState(item.state)
-			p.reportStateChange(item.state.JobName, "upload", "completed", "", "", 1.0)
-			p.logf("INFO", "upload", item.state.JobName, "Success: File ID %s", cloudFile.ID)
-
-			// Clean up tar file if requested
-			if p.rmTarOnSuccess && item.state.TarPath != "" {
-				if err := p.safeRemoveTar(item.state.TarPath, item.state.JobName); err != nil {
-					p.logf("WARN", "upload", item.state.JobName, "Tar cleanup skipped: %v", err)
-				}
+			p.reportStateChange(item.state, "upload", "completed", "", "", 1.0)
+			if len(fileIDs) == 1 {
+				p.logf("INFO", "upload", item.state.JobName, "Success: File ID %s", fileIDs[0])
+			} else {
+				p.logf("INFO", "upload", item.state.JobName, "Success: %d parts, File IDs %s", len(fileIDs), strings.Join(fileIDs, ", "))
 			}
 
-			if transferHandle != nil {
-				transferHandle.Complete()
+			// Clean up tar files if requested
+			if p.rmTarOnSuccess {
+				for _, tarPath := range tarPaths {
+					if err := p.safeRemoveTar(tarPath, item.state.JobName); err != nil {
+						p.logf("WARN", "upload", item.state.JobName, "Tar cleanup skipped: %v", err)
+					}
+				}
 			}
 
 			p.setActiveWorker("upload", -1)
@@ -936,6 +1615,207 @@ shutdown:
 	p.mu.Unlock()
 }
 
+// uploadTar uploads one tar file, retrying proxy timeouts up to MaxRetries
+// times. progress receives 0.0-1.0.
+func (p *Pipeline) uploadTar(ctx context.Context, item *workItem, tarPath, folderID string, progress func(float64)) (*models.CloudFile, error) {
+	fileInfo, err := os.Stat(tarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	maxRetries := p.cfg.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	var cloudFile *models.CloudFile
+	var transferHandle *transfer.Transfer
+	defer func() {
+		if transferHandle != nil {
+			transferHandle.Complete()
+		}
+	}()
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if p.syncUploader != nil {
+			cloudFile, err = p.syncUploader.UploadFileSync(ctx, SyncUploadParams{
+				LocalPath:             tarPath,
+				FolderID:              folderID,
+				Name:                  filepath.Base(tarPath),
+				SourceLabel:           "PUR",
+				BatchID:               p.batchID,
+				BatchLabel:            p.batchLabel,
+				ExtraProgressCallback: progress,
+				PauseInBlackout:       true,
+			})
+		} else {
+			// CLI fallback: direct upload.
+			// Signal active transfer since CLI fallback bypasses RunBatch.
+			if transferHandle == nil {
+				transferHandle = p.transferMgr.AllocateTransfer(fileInfo.Size(), 1)
+			}
+			ratelimit.GlobalStore().BeginTransferActivity()
+			cloudFile, err = upload.UploadFile(ctx, upload.UploadParams{
+				LocalPath:        tarPath,
+				FolderID:         folderID,
+				APIClient:        p.apiClient,
+				ProgressCallback: progress,
+				TransferHandle:   transferHandle,
+				OutputWriter:     io.Discard,
+				PauseInBlackout:  true,
+			})
+			ratelimit.GlobalStore().EndTransferActivity()
+		}
+
+		if err == nil {
+			break
+		}
+
+		// Storage rejected a part outright; another attempt would fail the same way
+		var partErr *cloudtransfer.PartError
+		if errors.As(err, &partErr) && partErr.Permanent() {
+			p.logf("ERROR", "upload", item.state.JobName, "Part %d of %d rejected by storage, not retrying", partErr.PartIndex+1, partErr.TotalParts)
+			break
+		}
+
+		errStr := err.Error()
+		isTimeout := strings.Contains(errStr, "timeout") ||
+			strings.Contains(errStr, "SocketTimeoutException") ||
+			strings.Contains(errStr, "connection reset") ||
+			strings.Contains(errStr, "EOF")
+
+		if isTimeout && attempt < maxRetries {
+			p.logf("WARN", "upload", item.state.JobName, "Detected proxy timeout, forcing fresh auth and retrying...")
+			// Warmup proxy on retry to re-establish session
+			if strings.ToLower(p.cfg.ProxyMode) == "basic" {
+				_ = inthttp.WarmupProxyConnection(ctx, p.cfg)
+			}
+			if mode := strings.ToLower(p.cfg.ProxyMode); mode == "basic" || mode == "ntlm" || mode == "negotiate" {
+				p.logf("INFO", "upload", item.state.JobName, "Waiting 2 seconds before retry...")
+				time.Sleep(2 * time.Second)
+			}
+			p.logf("INFO", "upload", item.state.JobName, "Retry attempt %d/%d", attempt+1, maxRetries)
+			continue
+		}
+
+		break
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudFile, nil
+}
+
+// uploadTarParts uploads the tars of a split run directory concurrently,
+// filling fileIDs (aligned with tarPaths). Parts that already have a file ID
+// from an earlier attempt are skipped. Job progress is weighted by part size.
+// Every part is attempted; the first error is returned.
+func (p *Pipeline) uploadTarParts(ctx context.Context, item *workItem, tarPaths, fileIDs []string, folderID string) error {
+	sizes := make([]int64, len(tarPaths))
+	done := make([]float64, len(tarPaths))
+	var total int64
+	for i, path := range tarPaths {
+		if info, err := os.Stat(path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+		if fileIDs[i] != "" {
+			done[i] = 1.0
+		}
+	}
+
+	var progressMu sync.Mutex
+	reportProgress := func(i int, progress float64) {
+		progressMu.Lock()
+		done[i] = progress
+		var sum float64
+		for j, d := range done {
+			if total > 0 {
+				sum += d * float64(sizes[j]) / float64(total)
+			} else {
+				sum += d / float64(len(done))
+			}
+		}
+		progressMu.Unlock()
+		p.reportStateChange(item.state, "upload", "in_progress", "", "", sum)
+	}
+
+	errs := make([]error, len(tarPaths))
+	sem := make(chan struct{}, constants.TarSplitUploadConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range tarPaths {
+		if fileIDs[i] != "" {
+			p.logf("INFO", "upload", item.state.JobName, "Part %d/%d already uploaded: File ID %s", i+1, len(tarPaths), fileIDs[i])
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.logf("INFO", "upload", item.state.JobName, "Uploading part %d/%d: %s", i+1, len(tarPaths), path)
+			cloudFile, err := p.uploadTar(ctx, item, path, folderID, func(progress float64) { reportProgress(i, progress) })
+			if err != nil {
+				errs[i] = fmt.Errorf("part %d/%d: %w", i+1, len(tarPaths), err)
+				return
+			}
+			fileIDs[i] = cloudFile.ID
+			reportProgress(i, 1.0)
+		}(i, path)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sharedDestinationFolder returns the DestinationFolder every job uses, or
+// "" when the jobs differ or none sets one.
+func (p *Pipeline) sharedDestinationFolder() string {
+	dest := ""
+	for i, job := range p.jobs {
+		d := strings.TrimSpace(job.DestinationFolder)
+		if i > 0 && d != dest {
+			return ""
+		}
+		dest = d
+	}
+	return dest
+}
+
+// resolveDestinationFolder returns the ID of the remote folder at path under
+// My Library, creating missing folders. An empty path returns "" so the upload
+// goes to the default location. Results are cached for the run.
+func (p *Pipeline) resolveDestinationFolder(ctx context.Context, path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil
+	}
+
+	p.destFolderMu.Lock()
+	defer p.destFolderMu.Unlock()
+
+	if id, ok := p.destFolderIDs[path]; ok {
+		return id, nil
+	}
+	if p.apiClient == nil {
+		return "", fmt.Errorf("cannot resolve destination folder %q: no API client", path)
+	}
+
+	id, err := folder.EnsureLibraryPath(ctx, p.apiClient, p.folderCache, path)
+	if err != nil {
+		return "", err
+	}
+	p.destFolderIDs[path] = id
+	p.logf("INFO", "upload", "", "Destination folder %s -> %s", path, id)
+	return id, nil
+}
+
 // safeRemoveTar safely deletes a tar file with multiple guardrails.
 func (p *Pipeline) safeRemoveTar(tarPath, jobName string) error {
 	// 1. Canonical path: resolve symlinks, get absolute path
@@ -975,7 +1855,7 @@ func (p *Pipeline) safeRemoveTar(tarPath, jobName string) error {
 		return fmt.Errorf("filename lacks FNV hash suffix (not created by Interlink): %s", base)
 	}
 
-	if err := os.Remove(tarPath); err != nil {
+	if err := securedelete.Remove(tarPath); err != nil {
 		return fmt.Errorf("failed to remove %s: %w", tarPath, err)
 	}
 	p.logf("INFO", "upload", jobName, "Removed tar file: %s", tarPath)
@@ -1005,6 +1885,7 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 
 			if item.state.JobID == "" {
 				// Wait for version resolution (concurrent with tar/upload)
+				waitStart := time.Now()
 				select {
 				case <-ctx.Done():
 					p.setActiveWorker("job", -1)
@@ -1012,6 +1893,11 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 				case <-p.versionsResolved:
 				}
 
+				// The span records the wait, the usual suspect when
+				// submission is slow to start
+				createCtx, createSpan := tracing.Start(ctx, "pur.create_job", jobAttr(item),
+					attribute.Int64("pur.versions_wait_ms", time.Since(waitStart).Milliseconds()))
+
 				// Apply resolved version
 				if p.resolvedVersions != nil {
 					key := item.jobSpec.AnalysisCode + ":" + item.jobSpec.AnalysisVersion
@@ -1023,7 +1909,7 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 				}
 
 				p.logf("INFO", "job", item.state.JobName, "Creating job: %s", item.jobSpec.JobName)
-				p.reportStateChange(item.state.JobName, "create", "in_progress", "", "", 0.0)
+				p.reportStateChange(item.state, "create", "in_progress", "", "", 0.0)
 
 				// When InputFiles are pre-specified (single job remoteFiles/localFiles mode),
 				// use them directly instead of the FileID from upload stage.
@@ -1031,48 +1917,68 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 				if item.jobSpec.Directory == "" && len(item.jobSpec.InputFiles) > 0 {
 					fileIDs = item.jobSpec.InputFiles
 				} else {
-					fileIDs = []string{item.state.FileID}
+					fileIDs = item.state.FileIDs()
 				}
 				jobReq, err := BuildJobRequest(item.jobSpec, fileIDs, p.sharedFileIDs, p.decompressExtras)
 				if err != nil {
 					p.logf("ERROR", "job", item.state.JobName, "Failed to build request: %v", err)
+					tracing.End(createSpan, err)
 					item.state.SubmitStatus = "failed"
 					item.state.ErrorMessage = err.Error()
 					p.stateMgr.UpdateState(item.state)
-					p.reportStateChange(item.state.JobName, "create", "failed", "", err.Error(), 0.0)
+					p.reportStateChange(item.state, "create", "failed", "", err.Error(), 0.0)
 					p.setActiveWorker("job", -1)
 					continue
 				}
 
-				jobResp, err := p.apiClient.CreateJob(ctx, *jobReq)
+				toDescription, toCustomFields := p.metadataTargets()
+				if toDescription {
+					jobReq.Description = MetadataDescription(item.jobSpec)
+				}
+
+				jobResp, err := p.apiClient.CreateJob(createCtx, *jobReq)
 				if err != nil {
 					p.logf("ERROR", "job", item.state.JobName, "Failed to create: %v", err)
+					tracing.End(createSpan, err)
 					item.state.SubmitStatus = "failed"
 					item.state.ErrorMessage = err.Error()
 					p.stateMgr.UpdateState(item.state)
-					p.reportStateChange(item.state.JobName, "create", "failed", "", err.Error(), 0.0)
+					p.reportStateChange(item.state, "create", "failed", "", err.Error(), 0.0)
 					p.setActiveWorker("job", -1)
 					continue
 				}
 
 				item.state.JobID = jobResp.ID
+				item.state.Warnings = strings.Join(jobResp.Warnings, models.WarningSeparator)
 				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "create", "completed", jobResp.ID, "", 0.0)
+				p.reportStateChange(item.state, "create", "completed", jobResp.ID, "", 0.0)
 				p.logf("INFO", "job", item.state.JobName, "Created: Job ID %s", jobResp.ID)
+				for _, w := range jobResp.Warnings {
+					p.logf("WARN", "job", item.state.JobName, "Platform warning: %s", w)
+				}
 
-				// Apply user tags. The job-creation body's "tags" field is ignored
+				// Apply the other tags. The job-creation body's "tags" field is ignored
 				// by the platform; tags must be POSTed one at a time to the
 				// per-job tags endpoint. Non-fatal: a tag failure does not fail
 				// the job.
-				for _, tag := range item.jobSpec.Tags {
-					if tag == "" {
-						continue
+				for _, tag := range p.jobTags(item.jobSpec) {
+					if tag == constants.DefaultAdminJobTag {
+						continue // Added by CreateJob
 					}
-					if err := p.apiClient.AddJobTag(ctx, jobResp.ID, tag); err != nil {
+					if err := p.apiClient.AddJobTag(createCtx, jobResp.ID, tag); err != nil {
 						p.logf("WARN", "job", item.state.JobName, "Failed to apply tag %q: %v", tag, err)
 					}
 				}
 
+				// Metadata as custom fields. Non-fatal like tags: the job is
+				// already created, and unknown field names are a workspace
+				// setup issue rather than a job failure.
+				if toCustomFields && len(item.jobSpec.Metadata) > 0 {
+					if err := p.apiClient.SetJobCustomFields(createCtx, jobResp.ID, item.jobSpec.Metadata); err != nil {
+						p.logf("WARN", "job", item.state.JobName, "Failed to set custom fields from metadata: %v", err)
+					}
+				}
+
 				// Org-scoped project assignment
 				orgCode := item.jobSpec.OrgCode
 				if orgCode == "" {
@@ -1081,7 +1987,7 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 				if orgCode != "" && item.jobSpec.ProjectID != "" {
 					maxAssignRetries := 3
 					for assignAttempt := 1; assignAttempt <= maxAssignRetries; assignAttempt++ {
-						err := p.apiClient.AssignProjectToJob(ctx, orgCode, item.state.JobID, item.jobSpec.ProjectID)
+						err := p.apiClient.AssignProjectToJob(createCtx, orgCode, item.state.JobID, item.jobSpec.ProjectID)
 						if err == nil {
 							p.logf("INFO", "job", item.state.JobName, "Project assignment successful (org=%s)", orgCode)
 							break
@@ -1093,27 +1999,22 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 					}
 					// Non-fatal: job continues even if assignment fails
 				}
+				createSpan.End()
 			}
 
 			if shouldSubmit(item.jobSpec.SubmitMode) && item.state.SubmitStatus != "success" {
-				p.logf("INFO", "job", item.state.JobName, "Submitting job %s", item.state.JobID)
-				p.reportStateChange(item.state.JobName, "submit", "in_progress", item.state.JobID, "", 0.0)
-
-				err := p.apiClient.SubmitJob(ctx, item.state.JobID)
-				if err != nil {
-					p.logf("ERROR", "job", item.state.JobName, "Failed to submit: %v", err)
-					item.state.SubmitStatus = "failed"
-					item.state.ErrorMessage = err.Error()
-					p.stateMgr.UpdateState(item.state)
-					p.reportStateChange(item.state.JobName, "submit", "failed", item.state.JobID, err.Error(), 0.0)
+				if len(p.parents[item.index-1]) > 0 {
+					// Submitted by the dependency stage once the parents complete
+					if item.state.SubmitStatus != "waiting" {
+						p.holdForDependencies(item)
+					}
+					p.setActiveWorker("job", -1)
+					continue
+				}
+				if !p.submitJob(ctx, item) {
 					p.setActiveWorker("job", -1)
 					continue
 				}
-
-				item.state.SubmitStatus = "success"
-				p.stateMgr.UpdateState(item.state)
-				p.reportStateChange(item.state.JobName, "submit", "completed", item.state.JobID, "", 0.0)
-				p.logf("INFO", "job", item.state.JobName, "Submitted successfully")
 			} else if item.state.SubmitStatus != "success" && item.state.SubmitStatus != "failed" {
 				item.state.SubmitStatus = "skipped"
 				p.stateMgr.UpdateState(item.state)
@@ -1125,21 +2026,65 @@ func (p *Pipeline) jobWorker(ctx context.Context, wg *sync.WaitGroup, workerID i
 	}
 }
 
+// jobAttr identifies the job a stage span belongs to.
+func jobAttr(item *workItem) attribute.KeyValue {
+	return attribute.String("pur.job", item.state.JobName)
+}
+
+// submitJob submits a created job and records the outcome. Unless
+// --fail-on-warning holds it back: the job then stays created on the
+// platform, and resuming without the flag submits it. It reports whether
+// the job was submitted.
+func (p *Pipeline) submitJob(ctx context.Context, item *workItem) bool {
+	if p.failOnWarning && item.state.Warnings != "" {
+		msg := "not submitted, the platform returned warnings: " + strings.Join(item.state.WarningList(), "; ")
+		p.logf("ERROR", "job", item.state.JobName, "%s", msg)
+		item.state.SubmitStatus = "failed"
+		item.state.ErrorMessage = msg
+		p.stateMgr.UpdateState(item.state)
+		p.reportStateChange(item.state, "submit", "failed", item.state.JobID, msg, 0.0)
+		return false
+	}
+
+	p.logf("INFO", "job", item.state.JobName, "Submitting job %s", item.state.JobID)
+	p.reportStateChange(item.state, "submit", "in_progress", item.state.JobID, "", 0.0)
+
+	submitCtx, span := tracing.Start(ctx, "pur.submit", jobAttr(item), attribute.String("rescale.job_id", item.state.JobID))
+	err := p.jobSubmitter.SubmitJob(submitCtx, item.state.JobID)
+	tracing.End(span, err)
+	if err != nil {
+		p.logf("ERROR", "job", item.state.JobName, "Failed to submit: %v", err)
+		item.state.SubmitStatus = "failed"
+		item.state.ErrorMessage = err.Error()
+		p.stateMgr.UpdateState(item.state)
+		p.reportStateChange(item.state, "submit", "failed", item.state.JobID, err.Error(), 0.0)
+		return false
+	}
+
+	item.state.SubmitStatus = "success"
+	if wantsOutputs(item.jobSpec) {
+		item.state.OutputStatus = "pending"
+	}
+	p.stateMgr.UpdateState(item.state)
+	p.reportStateChange(item.state, "submit", "completed", item.state.JobID, "", 0.0)
+	p.logf("INFO", "job", item.state.JobName, "Submitted successfully")
+	p.recordSubmitted(item.jobSpec, item.state.JobID)
+	return true
+}
+
 // BuildJobRequest builds a job request from job spec.
 // This is the single source of truth for JobSpec -> JobRequest conversion.
 // Used by both GUI (single job tab) and PUR pipeline.
 // fileIDs are the primary input files; ExtraInputFileIDs from spec are also included.
+// Both are decompressed on the cluster unless spec.NoDecompress is set.
 // sharedFileIDs are pipeline-level shared files (from --extra-input-files) attached to every job.
 // decompressExtras controls whether those shared files are decompressed on the cluster.
 func BuildJobRequest(spec models.JobSpec, fileIDs []string, sharedFileIDs []string, decompressExtras bool) (*models.JobRequest, error) {
-	// Parse license settings (optional - empty string is valid)
-	var licenseEnv map[string]string
-	if spec.LicenseSettings != "" {
-		var err error
-		licenseEnv, err = config.ParseLicenseJSON(spec.LicenseSettings)
-		if err != nil {
-			return nil, fmt.Errorf("invalid license settings: %w", err)
-		}
+	// License settings and EnvVars are both environment variables on the
+	// cluster (optional - empty is valid)
+	env, err := config.JobEnvironment(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment: %w", err)
 	}
 
 	// Build input files from provided file IDs
@@ -1161,7 +2106,7 @@ func BuildJobRequest(spec models.JobSpec, fileIDs []string, sharedFileIDs []stri
 			if id != "" {
 				inputFiles = append(inputFiles, models.InputFileRequest{
 					ID:         id,
-					Decompress: true,
+					Decompress: !spec.NoDecompress,
 				})
 			}
 		}
@@ -1204,15 +2149,17 @@ func BuildJobRequest(spec models.JobSpec, fileIDs []string, sharedFileIDs []stri
 					Walltime:     walltimeHoursToAPI(spec.WalltimeHours),
 				},
 				InputFiles:                 inputFiles,
-				EnvVars:                    licenseEnv,
-				UseRescaleLicense:          false,
+				EnvVars:                    env,
+				UseRescaleLicense:          spec.UseRescaleLicense,
 				OnDemandLicenseSeller:      nil,
 				UserDefinedLicenseSettings: nil,
 			},
 		},
-		IsLowPriority: spec.IsLowPriority,
+		// Scheduling tiers apply to on-demand hardware only
+		IsLowPriority: spec.IsLowPriority && spec.ClusterID == "",
 		Tags:          spec.Tags,
 		ProjectID:     spec.ProjectID,
+		ClusterID:     spec.ClusterID,
 	}
 
 	if spec.OnDemandLicenseSeller != "" {
@@ -1287,10 +2234,12 @@ func (p *Pipeline) incrementCompleted() {
 	p.mu.Unlock()
 }
 
-// progressReporter reports progress every 10 seconds
+// progressReporter reports progress every 10 seconds, with a line per array
+// whose members progressed
 func (p *Pipeline) progressReporter(stop chan struct{}) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
+	arrays := make(map[string]arrayProgress)
 
 	for {
 		select {
@@ -1304,6 +2253,7 @@ func (p *Pipeline) progressReporter(stop chan struct{}) {
 
 			p.logf("INFO", "pipeline", "", "Active workers: tar=%d upload=%d job=%d | Completed: %d/%d",
 				tarActive, uploadActive, jobActive, completed, p.totalJobs)
+			p.logArrayProgress(arrays)
 
 		case <-stop:
 			return
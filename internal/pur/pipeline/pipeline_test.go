@@ -2,12 +2,21 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/version"
 )
 
 // mockAnalysisResolver implements AnalysisResolver for testing.
@@ -305,6 +314,82 @@ func TestWalltimeHoursToAPI(t *testing.T) {
 	}
 }
 
+func TestProcessingOrder_PriorityThenCSVOrder(t *testing.T) {
+	jobs := []models.JobSpec{
+		{JobName: "a"},
+		{JobName: "b", Priority: 10},
+		{JobName: "c"},
+		{JobName: "d", Priority: 10},
+		{JobName: "e", Priority: -1},
+	}
+	got := processingOrder(jobs)
+	want := []int{1, 3, 0, 2, 4}
+	if len(got) != len(want) {
+		t.Fatalf("processingOrder() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("processingOrder() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSetJobFilter(t *testing.T) {
+	p := &Pipeline{jobs: make([]models.JobSpec, 4), totalJobs: 4}
+	p.SetJobFilter([]int{2, 4, 9, 0})
+	if p.totalJobs != 2 || !reflect.DeepEqual(p.onlyIndices, map[int]bool{2: true, 4: true}) {
+		t.Errorf("totalJobs = %d, onlyIndices = %v", p.totalJobs, p.onlyIndices)
+	}
+}
+
+func TestJobTags_MergesWorkspaceDefaults(t *testing.T) {
+	p := &Pipeline{
+		cfg:   &config.Config{DefaultTags: []string{"interlink-{version}", "run-{run_id}", "team-a"}},
+		runID: "run_42",
+	}
+	got := p.jobTags(models.JobSpec{Tags: []string{"team-a", "cfd"}})
+	want := []string{"team-a", "cfd", "interlink-" + version.Version, "run-run_42", constants.DefaultAdminJobTag}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("jobTags() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveDestinationFolder(t *testing.T) {
+	p := &Pipeline{destFolderIDs: map[string]string{"ProjectA/Study1": "folder-123"}}
+
+	if id, err := p.resolveDestinationFolder(context.Background(), "  "); err != nil || id != "" {
+		t.Errorf("empty path: got (%q, %v), want default folder", id, err)
+	}
+	if id, err := p.resolveDestinationFolder(context.Background(), "ProjectA/Study1"); err != nil || id != "folder-123" {
+		t.Errorf("cached path: got (%q, %v), want folder-123", id, err)
+	}
+	// Uncached path without an API client must fail rather than upload to the wrong place
+	if _, err := p.resolveDestinationFolder(context.Background(), "ProjectB"); err == nil {
+		t.Error("expected error resolving an uncached path without an API client")
+	}
+}
+
+func TestSharedDestinationFolder(t *testing.T) {
+	tests := []struct {
+		dests []string
+		want  string
+	}{
+		{[]string{"ProjectA", " ProjectA "}, "ProjectA"},
+		{[]string{"ProjectA", "ProjectB"}, ""},
+		{[]string{"ProjectA", ""}, ""},
+		{[]string{"", ""}, ""},
+	}
+	for _, tt := range tests {
+		p := &Pipeline{}
+		for _, d := range tt.dests {
+			p.jobs = append(p.jobs, models.JobSpec{DestinationFolder: d})
+		}
+		if got := p.sharedDestinationFolder(); got != tt.want {
+			t.Errorf("sharedDestinationFolder(%q) = %q, want %q", tt.dests, got, tt.want)
+		}
+	}
+}
+
 func TestBuildJobRequest_WalltimeIsHoursNotSeconds(t *testing.T) {
 	spec := models.JobSpec{
 		JobName:         "wt",
@@ -325,3 +410,210 @@ func TestBuildJobRequest_WalltimeIsHoursNotSeconds(t *testing.T) {
 		t.Errorf("walltime = %d, want 1 (hours); a value of 3600 would be the old seconds bug", got)
 	}
 }
+
+func TestBuildJobRequest_EnvironmentAndCluster(t *testing.T) {
+	spec := models.JobSpec{
+		JobName:           "env",
+		AnalysisCode:      "user_included",
+		Command:           "echo hi",
+		CoreType:          "emerald",
+		CoresPerSlot:      1,
+		Slots:             1,
+		WalltimeHours:     1.0,
+		LicenseSettings:   `{"LM_LICENSE_FILE":"27000@lic"}`,
+		EnvVars:           map[string]string{"OMP_NUM_THREADS": "4"},
+		UseRescaleLicense: true,
+		IsLowPriority:     true,
+		ClusterID:         "cluster123",
+	}
+	req, err := BuildJobRequest(spec, nil, nil, false)
+	if err != nil {
+		t.Fatalf("BuildJobRequest() error = %v", err)
+	}
+	analysis := req.JobAnalyses[0]
+	if len(analysis.EnvVars) != 2 || analysis.EnvVars["LM_LICENSE_FILE"] != "27000@lic" || analysis.EnvVars["OMP_NUM_THREADS"] != "4" {
+		t.Errorf("EnvVars = %v, want license settings and EnvVars merged", analysis.EnvVars)
+	}
+	if !analysis.UseRescaleLicense {
+		t.Error("UseRescaleLicense = false, want true")
+	}
+	if req.ClusterID != "cluster123" || req.IsLowPriority {
+		t.Errorf("ClusterID = %q, IsLowPriority = %v; want cluster123 and false on a reserved cluster", req.ClusterID, req.IsLowPriority)
+	}
+
+	spec.EnvVars = map[string]string{"LM_LICENSE_FILE": "1@other"}
+	if _, err := BuildJobRequest(spec, nil, nil, false); err == nil {
+		t.Error("BuildJobRequest() with conflicting license and env values succeeded, want error")
+	}
+}
+
+// fakeSyncUploader records uploads and returns "id-<name>", failing names in fail.
+type fakeSyncUploader struct {
+	mu       sync.Mutex
+	uploaded []string
+	fail     map[string]bool
+}
+
+func (f *fakeSyncUploader) UploadFileSync(ctx context.Context, params SyncUploadParams) (*models.CloudFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail[params.Name] {
+		return nil, fmt.Errorf("upload of %s failed", params.Name)
+	}
+	f.uploaded = append(f.uploaded, params.Name)
+	params.ExtraProgressCallback(1.0)
+	return &models.CloudFile{ID: "id-" + params.Name}, nil
+}
+
+func TestUploadTarParts_ResumesFailedParts(t *testing.T) {
+	dir := t.TempDir()
+	var tarPaths []string
+	for _, name := range []string{"p1.tar", "p2.tar", "p3.tar"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		tarPaths = append(tarPaths, path)
+	}
+
+	uploader := &fakeSyncUploader{fail: map[string]bool{"p2.tar": true}}
+	p := &Pipeline{
+		cfg:          &config.Config{},
+		syncUploader: uploader,
+		stageStarts:  make(map[int]map[string]time.Time),
+		stageTimes:   make(map[int]map[string]time.Duration),
+	}
+	item := &workItem{state: &models.JobState{JobName: "job"}}
+
+	fileIDs := make([]string, 3)
+	if err := p.uploadTarParts(context.Background(), item, tarPaths, fileIDs, ""); err == nil {
+		t.Fatal("expected an error for the failed part")
+	}
+	if !reflect.DeepEqual(fileIDs, []string{"id-p1.tar", "", "id-p3.tar"}) {
+		t.Fatalf("fileIDs after failure = %v", fileIDs)
+	}
+
+	// Resume: only the failed part is uploaded again
+	uploader.fail = nil
+	uploader.uploaded = nil
+	if err := p.uploadTarParts(context.Background(), item, tarPaths, fileIDs, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(uploader.uploaded, []string{"p2.tar"}) {
+		t.Errorf("resumed uploads = %v, want [p2.tar]", uploader.uploaded)
+	}
+	state := models.JobState{FileID: models.JoinParts(fileIDs)}
+	if got := state.FileIDs(); !reflect.DeepEqual(got, []string{"id-p1.tar", "id-p2.tar", "id-p3.tar"}) {
+		t.Errorf("FileIDs() = %v", got)
+	}
+}
+
+func TestWaitForBlackout_ReportsWaiting(t *testing.T) {
+	now := time.Now()
+	start := (now.Hour()*60 + now.Minute() + 23*60) % (24 * 60)
+	window := resources.BlackoutWindow{Start: start, End: (start + 120) % (24 * 60)}
+	if err := resources.SetBlackoutWindows(window.String()); err != nil {
+		t.Fatal(err)
+	}
+	defer resources.SetBlackoutWindows("")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stage, status string
+	p := &Pipeline{
+		cfg:         &config.Config{},
+		stageStarts: make(map[int]map[string]time.Time),
+		stageTimes:  make(map[int]map[string]time.Duration),
+		onStateChange: func(jobName, st, newStatus, jobID, errorMessage string, uploadProgress float64) {
+			stage, status = st, newStatus
+			cancel()
+		},
+	}
+	item := &workItem{state: &models.JobState{JobName: "job"}}
+
+	if err := p.waitForBlackout(ctx, item, "upload"); err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if stage != "upload" || status != "waiting" {
+		t.Errorf("reported %s/%s, want upload/waiting", stage, status)
+	}
+}
+
+func TestPassesJobChecks_SeparateMessages(t *testing.T) {
+	valid := models.JobSpec{
+		JobName: "run_1", AnalysisCode: "user_included", Command: "./run.sh",
+		CoreType: "emerald", CoresPerSlot: 4, Slots: 1, WalltimeHours: 1,
+	}
+	missing := valid
+	missing.Command = ""
+	unsafe := valid
+	unsafe.TarSubpath = "../other"
+
+	stateMgr := state.NewManager(filepath.Join(t.TempDir(), "run.state"))
+	p := &Pipeline{cfg: &config.Config{}, stateMgr: stateMgr}
+	for i, tc := range []struct {
+		spec   models.JobSpec
+		passes bool
+		reason string
+	}{
+		{valid, true, ""},
+		{missing, false, "invalid job spec: Command is required"},
+		{unsafe, false, "safety check failed: "},
+	} {
+		item := &workItem{jobSpec: tc.spec, state: stateMgr.InitializeState(i+1, tc.spec.JobName, "")}
+		if got := p.passesJobChecks(item); got != tc.passes {
+			t.Errorf("job %d: passesJobChecks() = %v, want %v", i+1, got, tc.passes)
+		}
+		if !strings.HasPrefix(item.state.ErrorMessage, tc.reason) || (tc.reason == "") != (item.state.ErrorMessage == "") {
+			t.Errorf("job %d: error = %q, want prefix %q", i+1, item.state.ErrorMessage, tc.reason)
+		}
+	}
+}
+
+// TestDeferUntilSettledRequeues verifies that a job whose inputs are still
+// being written is handed back through tarQueue once they settle, and that
+// tarQueue only closes after the requeued job is taken.
+func TestDeferUntilSettledRequeues(t *testing.T) {
+	dir := t.TempDir()
+	recent := time.Now().Add(-900 * time.Millisecond)
+	old := time.Now().Add(-time.Hour)
+	for name, mtime := range map[string]time.Time{"input.dat": recent, "mesh.cas": old} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Pipeline{
+		cfg:      &config.Config{SettleSeconds: 1, SettleTimeoutSeconds: 30},
+		tarQueue: make(chan *workItem, 1),
+		onLog:    func(level, message, stage, jobName string) {},
+	}
+	item := &workItem{index: 1, jobSpec: models.JobSpec{Directory: dir}, state: &models.JobState{Index: 1, JobName: "Run_1"}}
+
+	p.holdTarItem()
+	if !p.deferUntilSettled(context.Background(), item) {
+		t.Fatal("deferUntilSettled() = false for a directory with a recently written file")
+	}
+	p.releaseTarItem()
+	p.finishFeeding()
+
+	select {
+	case got, ok := <-p.tarQueue:
+		if !ok || got != item || !got.settled {
+			t.Fatalf("tarQueue gave %v (open %v), want the settled item", got, ok)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("job was not requeued after its inputs settled")
+	}
+	if p.deferUntilSettled(context.Background(), item) {
+		t.Error("deferUntilSettled() deferred an item that already settled")
+	}
+	p.releaseTarItem()
+	if _, ok := <-p.tarQueue; ok {
+		t.Error("tarQueue still open after the last pending item was taken")
+	}
+}
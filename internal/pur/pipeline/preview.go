@@ -0,0 +1,260 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+// Preview is the result of a dry run (see Pipeline.Preview): what the run
+// would archive, upload and send to the platform, job by job.
+type Preview struct {
+	RunID       string        `json:"runId"`
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Jobs        []JobPreview  `json:"jobs"`
+	SharedFiles []FilePreview `json:"sharedFiles,omitempty"` // --extra-input-files
+	TotalFiles  int           `json:"totalFiles"`
+	TotalBytes  int64         `json:"totalBytes"` // Uncompressed size of all archive inputs
+	Errors      int           `json:"errors"`     // Jobs that would fail before job creation
+}
+
+// JobPreview is one job of a Preview.
+type JobPreview struct {
+	Index     int    `json:"index"` // State index (1-based CSV position)
+	JobName   string `json:"jobName"`
+	Directory string `json:"directory,omitempty"`
+
+	// Archives the tar stage would write, with the uncompressed size of
+	// their contents (an upper bound for gzip archives)
+	Files    int           `json:"files"`
+	Bytes    int64         `json:"bytes"`
+	Archives []FilePreview `json:"archives,omitempty"`
+
+	// Remote folder the archives would be uploaded to ("" for the default
+	// upload location)
+	UploadFolder string `json:"uploadFolder,omitempty"`
+
+	// Body of the job creation request. File IDs not known before upload
+	// are shown as "<upload:NAME>".
+	Request      json.RawMessage   `json:"request,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	CustomFields map[string]string `json:"customFields,omitempty"`
+	Submit       bool              `json:"submit"`
+
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// FilePreview is a file the run would upload.
+type FilePreview struct {
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	Bytes       int64  `json:"bytes"`
+	UploadParts int    `json:"uploadParts"` // Multipart upload parts of constants.ChunkSize
+}
+
+// uploadPlaceholder stands in for the file ID of a file not yet uploaded.
+func uploadPlaceholder(path string) string {
+	return "<upload:" + filepath.Base(path) + ">"
+}
+
+// uploadParts returns how many multipart upload parts a file of size bytes
+// is sent in.
+func uploadParts(size int64) int {
+	if size <= 0 {
+		return 1
+	}
+	return int((size + constants.ChunkSize - 1) / constants.ChunkSize)
+}
+
+// Preview performs a dry run: it lists the files each job's archives would
+// hold, plans their upload and builds the exact job creation request, but
+// writes no archive, uploads nothing and calls no API endpoint that changes
+// anything. Analysis versions are still resolved when an API client is set,
+// as that only reads. The state file is not touched.
+func (p *Pipeline) Preview(ctx context.Context) *Preview {
+	if p.runID == "" {
+		p.runID = fmt.Sprintf("pur_%d", time.Now().Unix())
+	}
+	preview := &Preview{RunID: p.runID, GeneratedAt: time.Now()}
+
+	if p.apiClient != nil {
+		p.resolveAnalysisVersions(ctx)
+	}
+
+	// Shared files: pre-uploaded IDs are used as they are, local paths
+	// would be uploaded once for all jobs
+	var sharedIDs []string
+	for _, item := range strings.Split(p.extraInputFilesRaw, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case strings.HasPrefix(item, "id:"):
+			sharedIDs = append(sharedIDs, strings.TrimPrefix(item, "id:"))
+		default:
+			file := FilePreview{Name: filepath.Base(item), Path: item}
+			if info, err := os.Stat(item); err == nil {
+				file.Bytes = info.Size()
+			}
+			file.UploadParts = uploadParts(file.Bytes)
+			preview.SharedFiles = append(preview.SharedFiles, file)
+			sharedIDs = append(sharedIDs, uploadPlaceholder(item))
+		}
+	}
+
+	toDescription, toCustomFields := p.metadataTargets()
+	for i, spec := range p.jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		job := JobPreview{
+			Index:        i + 1,
+			JobName:      spec.JobName,
+			Directory:    spec.Directory,
+			UploadFolder: strings.TrimSpace(spec.DestinationFolder),
+			Tags:         p.jobTags(spec),
+			Submit:       shouldSubmit(spec.SubmitMode),
+		}
+
+		errs, warnings := p.validateSpec(spec)
+		job.Warnings = warnings
+		if len(errs) > 0 {
+			job.Error = "validation failed: " + strings.Join(errs, "; ")
+		}
+
+		var fileIDs []string
+		switch {
+		case job.Error != "":
+		case spec.Directory == "" && len(spec.InputFiles) > 0:
+			// Single job files mode: the inputs are uploaded or referenced as they are
+			fileIDs = spec.InputFiles
+		default:
+			archives, err := p.previewArchives(&job, i)
+			if err != nil {
+				job.Error = err.Error()
+			}
+			for _, a := range archives {
+				fileIDs = append(fileIDs, uploadPlaceholder(a.Path))
+			}
+			job.Archives = archives
+		}
+
+		if job.Error == "" {
+			if code, ok := p.resolvedVersions[spec.AnalysisCode+":"+spec.AnalysisVersion]; ok {
+				spec.AnalysisVersion = code
+			}
+			req, err := BuildJobRequest(spec, fileIDs, sharedIDs, p.decompressExtras)
+			if err == nil {
+				if toDescription {
+					req.Description = MetadataDescription(spec)
+				}
+				job.Request, err = marshalRequest(req)
+			}
+			if err != nil {
+				job.Error = err.Error()
+			}
+			if toCustomFields && len(spec.Metadata) > 0 {
+				job.CustomFields = spec.Metadata
+			}
+		}
+
+		if job.Error != "" {
+			preview.Errors++
+		}
+		preview.TotalFiles += job.Files
+		preview.TotalBytes += job.Bytes
+		preview.Jobs = append(preview.Jobs, job)
+	}
+	return preview
+}
+
+// marshalRequest encodes the job creation request as it is sent, keeping
+// the "<upload:NAME>" placeholders readable.
+func marshalRequest(req *models.JobRequest) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(req); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// previewArchives fills in job's file count and size and returns the
+// archives the tar stage would write for job i.
+func (p *Pipeline) previewArchives(job *JobPreview, i int) ([]FilePreview, error) {
+	spec := p.jobs[i]
+	if spec.Directory == "" {
+		return nil, fmt.Errorf("job has no directory or input files")
+	}
+	tarSourceDir, err := resolveTarSourceDir(spec)
+	if err != nil {
+		return nil, err
+	}
+	entries, _, err := JobContents(p.cfg, spec, nil, p.multiPartMode)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list inputs: %w", err)
+	}
+	for _, e := range entries {
+		job.Files++
+		job.Bytes += e.Size
+	}
+
+	var parts []tar.Part
+	if !tar.IsZipSource(tarSourceDir) {
+		item := &workItem{index: i + 1, jobSpec: spec, state: &models.JobState{JobName: spec.JobName}}
+		if parts, err = p.planTarSplit(item, tarSourceDir); err != nil {
+			return nil, err
+		}
+	}
+	if len(parts) == 0 {
+		source := tarSourceDir
+		if tar.IsZipSource(tarSourceDir) {
+			source = filepath.Join(tarSourceDir, spec.TarSubpath)
+		}
+		path := tar.GenerateTarPath(source, p.tempDir, p.cfg.TarCompression)
+		return []FilePreview{{Name: filepath.Base(path), Path: path, Bytes: job.Bytes, UploadParts: uploadParts(job.Bytes)}}, nil
+	}
+
+	archives := make([]FilePreview, len(parts))
+	for n, part := range parts {
+		path := tar.GeneratePartTarPath(tarSourceDir, p.tempDir, p.cfg.TarCompression, n)
+		archives[n] = FilePreview{Name: filepath.Base(path), Path: path, Bytes: part.Size, UploadParts: uploadParts(part.Size)}
+	}
+	return archives, nil
+}
+
+// PreviewPath returns the dry-run report path placed next to stateFile
+// (e.g. run_123.state -> run_123.preview.json).
+func PreviewPath(stateFile string) string {
+	return strings.TrimSuffix(stateFile, filepath.Ext(stateFile)) + ".preview.json"
+}
+
+// WriteJSON writes the preview as indented JSON.
+func (pr *Preview) WriteJSON(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pr); err != nil {
+		return fmt.Errorf("failed to marshal dry-run preview: %w", err)
+	}
+	data := buf.Bytes()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create preview directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write dry-run preview: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+func TestPreview(t *testing.T) {
+	root := t.TempDir()
+	run := filepath.Join(root, "Run_1")
+	if err := os.MkdirAll(run, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(run, "deck.inp"), []byte("*NODE\n"), 0644)
+	os.WriteFile(filepath.Join(run, "mesh.dat"), make([]byte, 1000), 0644)
+
+	cfg, _ := config.LoadConfigCSV("")
+	jobs := []models.JobSpec{
+		{
+			JobName: "Run_1", Directory: run, AnalysisCode: "user_included", AnalysisVersion: "0",
+			Command: "./run.sh", CoreType: "emerald", CoresPerSlot: 4, Slots: 1, WalltimeHours: 2,
+			SubmitMode: "create_and_submit",
+		},
+		{
+			JobName: "Missing", Directory: filepath.Join(root, "Run_2"), AnalysisCode: "user_included",
+			Command: "./run.sh", CoreType: "emerald", CoresPerSlot: 4, Slots: 1, WalltimeHours: 2,
+		},
+	}
+	stateFile := filepath.Join(root, "run.state")
+	p, err := NewPipeline(cfg, nil, jobs, stateFile, false, state.NewManager(stateFile), false, "id:shared-1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview := p.Preview(context.Background())
+	if len(preview.Jobs) != 2 || preview.Errors != 1 {
+		t.Fatalf("jobs = %d, errors = %d", len(preview.Jobs), preview.Errors)
+	}
+
+	job := preview.Jobs[0]
+	if job.Error != "" || job.Files != 2 || job.Bytes != 1006 || len(job.Archives) != 1 || !job.Submit {
+		t.Fatalf("job 1 = %+v", job)
+	}
+	archive := job.Archives[0]
+	if archive.UploadParts != 1 || archive.Bytes != 1006 {
+		t.Errorf("archive = %+v", archive)
+	}
+	req := string(job.Request)
+	for _, want := range []string{`"name": "Run_1"`, `"command": "./run.sh"`, `"<upload:` + archive.Name + `>"`, `"shared-1"`} {
+		if !strings.Contains(req, want) {
+			t.Errorf("request lacks %s:\n%s", want, req)
+		}
+	}
+
+	if preview.Jobs[1].Error == "" || preview.Jobs[1].Request != nil {
+		t.Errorf("job 2 = %+v", preview.Jobs[1])
+	}
+
+	// Nothing is written
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file written (stat error %v)", err)
+	}
+	if _, err := os.Stat(archive.Path); !os.IsNotExist(err) {
+		t.Errorf("archive written (stat error %v)", err)
+	}
+
+	if err := preview.WriteJSON(PreviewPath(stateFile)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "run.preview.json")); err != nil {
+		t.Error(err)
+	}
+}
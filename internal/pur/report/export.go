@@ -0,0 +1,284 @@
+package report
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jobs table export formats.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// TableRow is one row of an exported jobs table: a report entry plus what
+// the jobs table shows beyond the state file.
+type TableRow struct {
+	JobEntry
+	CreateStatus   string
+	PlatformStatus string    // Live job status on Rescale, e.g. Executing
+	SubStatus      string    // Queue/provisioning sub-status while waiting to execute
+	SubStatusSince time.Time // When the job entered SubStatus (zero if unknown)
+}
+
+// TableRows returns one row per job. CreateStatus is derived from whether the
+// job has an ID; platform statuses are left for the caller to fill in.
+func (r *Report) TableRows() []TableRow {
+	rows := make([]TableRow, 0, len(r.Jobs))
+	for _, j := range r.Jobs {
+		row := TableRow{JobEntry: j, CreateStatus: "pending"}
+		switch {
+		case j.JobID != "":
+			row.CreateStatus = "success"
+		case j.UploadStatus == "success" && j.SubmitStatus == "failed":
+			row.CreateStatus = "failed"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ExportFormat returns the format named by format, or inferred from path's
+// extension when format is empty.
+func ExportFormat(format, path string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if format != FormatXLSX {
+			format = FormatCSV
+		}
+	}
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX, "excel":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q (want csv or xlsx)", format)
+}
+
+// WriteTableFile writes rows to path in format ("csv" or "xlsx").
+func WriteTableFile(path, format string, rows []TableRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := WriteTable(f, format, rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteTable writes rows as CSV or as a single-sheet XLSX workbook.
+func WriteTable(w io.Writer, format string, rows []TableRow) error {
+	records := make([][]cell, 0, len(rows)+1)
+	header := make([]cell, len(tableColumns))
+	for i, c := range tableColumns {
+		header[i] = cell{text: c}
+	}
+	records = append(records, header)
+	for _, r := range rows {
+		records = append(records, tableRecord(r))
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, records)
+	case FormatXLSX:
+		return writeXLSX(w, records)
+	}
+	return fmt.Errorf("unsupported export format %q (want csv or xlsx)", format)
+}
+
+var tableColumns = []string{
+	"#", "Directory", "Job Name", "Tar", "Upload", "Create", "Submit", "Job ID",
+	"Platform Status", "Sub-status", "Sub-status Since", "Outcome",
+	"Tar (s)", "Upload (s)", "Create (s)", "Submit (s)", "Total (s)",
+	"Error", "Job URL",
+}
+
+// cell is a text or numeric spreadsheet cell. Numeric cells stay numbers in
+// XLSX so durations can be summed and sorted.
+type cell struct {
+	text  string
+	num   float64
+	isNum bool
+}
+
+func textCell(s string) cell { return cell{text: s} }
+
+func numCell(f float64) cell { return cell{num: f, isNum: true} }
+
+func (c cell) String() string {
+	if c.isNum {
+		return strconv.FormatFloat(c.num, 'f', -1, 64)
+	}
+	return c.text
+}
+
+func tableRecord(r TableRow) []cell {
+	since := ""
+	if !r.SubStatusSince.IsZero() {
+		since = r.SubStatusSince.UTC().Format(time.RFC3339)
+	}
+	rec := []cell{
+		numCell(float64(r.Index)),
+		textCell(r.Directory),
+		textCell(r.JobName),
+		textCell(r.TarStatus),
+		textCell(r.UploadStatus),
+		textCell(r.CreateStatus),
+		textCell(r.SubmitStatus),
+		textCell(r.JobID),
+		textCell(r.PlatformStatus),
+		textCell(r.SubStatus),
+		textCell(since),
+		textCell(r.Status),
+	}
+	var total time.Duration
+	for _, s := range Stages {
+		d, ok := r.StageDurations[s]
+		if !ok {
+			rec = append(rec, textCell(""))
+			continue
+		}
+		total += d
+		rec = append(rec, numCell(seconds(d)))
+	}
+	if total > 0 {
+		rec = append(rec, numCell(seconds(total)))
+	} else {
+		rec = append(rec, textCell(""))
+	}
+	return append(rec, textCell(r.Error), textCell(r.JobURL))
+}
+
+// seconds rounds d to tenths of a second.
+func seconds(d time.Duration) float64 {
+	return float64(d.Round(100*time.Millisecond)) / float64(time.Second)
+}
+
+func writeCSV(w io.Writer, records [][]cell) error {
+	cw := csv.NewWriter(w)
+	for _, rec := range records {
+		fields := make([]string, len(rec))
+		for i, c := range rec {
+			fields[i] = c.String()
+		}
+		if err := cw.Write(fields); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeXLSX writes a minimal Office Open XML workbook with one sheet, inline
+// strings and a bold, frozen header row.
+func writeXLSX(w io.Writer, records [][]cell) error {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetData>`)
+	for i, rec := range records {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		style := ""
+		if i == 0 {
+			style = ` s="1"`
+		}
+		for j, c := range rec {
+			ref := columnName(j) + strconv.Itoa(i+1)
+			if c.isNum {
+				fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, c.String())
+				continue
+			}
+			if c.text == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+			if err := xml.EscapeText(&b, []byte(c.text)); err != nil {
+				return fmt.Errorf("failed to write XLSX: %w", err)
+			}
+			b.WriteString(`</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	if _, err := io.WriteString(f, b.String()); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+// columnName returns the spreadsheet column letters for a 0-based index
+// (0 -> A, 26 -> AA).
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Jobs" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
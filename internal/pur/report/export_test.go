@@ -0,0 +1,134 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTableRows() []TableRow {
+	rows := Build(testStates(), Options{
+		PlatformURL: "https://platform.rescale.com",
+		StageDurations: map[int]map[string]time.Duration{
+			1: {"tar": 1500 * time.Millisecond, "upload": 10 * time.Second},
+		},
+	}).TableRows()
+	rows[0].PlatformStatus = "Queued"
+	rows[0].SubStatus = "Provisioning cluster"
+	rows[0].SubStatusSince = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return rows
+}
+
+func TestTableRows_CreateStatus(t *testing.T) {
+	rows := testTableRows()
+	for i, want := range []string{"success", "pending", "pending"} {
+		if rows[i].CreateStatus != want {
+			t.Errorf("rows[%d].CreateStatus = %q, want %q", i, rows[i].CreateStatus, want)
+		}
+	}
+}
+
+func TestWriteTable_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, FormatCSV, testTableRows()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want header + 3", len(records))
+	}
+	col := map[string]int{}
+	for i, h := range records[0] {
+		col[h] = i
+	}
+	first := records[1]
+	for name, want := range map[string]string{
+		"#":                "1",
+		"Job ID":           "abc",
+		"Platform Status":  "Queued",
+		"Sub-status":       "Provisioning cluster",
+		"Sub-status Since": "2026-01-01T12:00:00Z",
+		"Tar (s)":          "1.5",
+		"Upload (s)":       "10",
+		"Submit (s)":       "",
+		"Total (s)":        "11.5",
+		"Job URL":          "https://platform.rescale.com/jobs/abc/",
+	} {
+		if got := first[col[name]]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := records[2][col["Error"]]; got != "tar <boom>" {
+		t.Errorf("Error = %q", got)
+	}
+}
+
+func TestWriteTable_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, FormatXLSX, testTableRows()); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(data)
+	}
+	if sheet == "" {
+		t.Fatal("workbook has no sheet1.xml")
+	}
+	for _, want := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">#</t></is></c>`,
+		`<c r="A2"><v>1</v></c>`,
+		"tar &lt;boom&gt;",
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml missing %s", want)
+		}
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		format, path, want string
+		wantErr            bool
+	}{
+		{"", "jobs.xlsx", FormatXLSX, false},
+		{"", "jobs.CSV", FormatCSV, false},
+		{"", "", FormatCSV, false},
+		{"excel", "jobs.csv", FormatXLSX, false},
+		{"pdf", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ExportFormat(tt.format, tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ExportFormat(%q, %q) = %q, %v; want %q", tt.format, tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package report generates run summary artifacts (HTML and JSON) for PUR runs.
+// A report captures the final per-job state, per-stage durations and direct
+// links to each job in the Rescale web portal.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/netusage"
+)
+
+// Stage names recorded in StageDurations, in pipeline order.
+var Stages = []string{"tar", "upload", "create", "submit"}
+
+// JobEntry is one row of the report.
+type JobEntry struct {
+	Index           int                      `json:"index"`
+	JobName         string                   `json:"jobName"`
+	Directory       string                   `json:"directory"`
+	JobID           string                   `json:"jobId,omitempty"`
+	JobURL          string                   `json:"jobUrl,omitempty"`
+	TarStatus       string                   `json:"tarStatus"`
+	UploadStatus    string                   `json:"uploadStatus"`
+	SubmitStatus    string                   `json:"submitStatus"`
+	Status          string                   `json:"status"` // "succeeded", "failed" or "incomplete"
+	Error           string                   `json:"error,omitempty"`
+	SkippedFiles    []string                 `json:"skippedFiles,omitempty"`    // Locked files left out of the tar
+	NormalizedFiles []string                 `json:"normalizedFiles,omitempty"` // Text inputs with CRLF line endings or a BOM
+	Warnings        []string                 `json:"warnings,omitempty"`        // Returned by the platform on job creation
+	StageDurations  map[string]time.Duration `json:"stageDurationsNs,omitempty"`
+}
+
+// Totals summarizes job outcomes.
+type Totals struct {
+	Jobs       int `json:"jobs"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	Incomplete int `json:"incomplete"`
+}
+
+// Report is the full run summary.
+type Report struct {
+	RunID       string          `json:"runId"`
+	StateFile   string          `json:"stateFile,omitempty"`
+	PlatformURL string          `json:"platformUrl,omitempty"`
+	StartTime   time.Time       `json:"startTime"`
+	EndTime     time.Time       `json:"endTime"`
+	Duration    time.Duration   `json:"durationNs"`
+	Totals      Totals          `json:"totals"`
+	Network     *netusage.Usage `json:"network,omitempty"` // Bytes on the wire during the run
+	Jobs        []JobEntry      `json:"jobs"`
+}
+
+// Options carries the run-level inputs to Build.
+type Options struct {
+	RunID       string
+	StateFile   string
+	PlatformURL string // Web portal origin, e.g. https://platform.rescale.com
+	StartTime   time.Time
+	EndTime     time.Time
+	// StageDurations maps job index -> stage -> elapsed time (optional).
+	StageDurations map[int]map[string]time.Duration
+	// NetworkUsage is the data sent and received during the run (optional).
+	NetworkUsage netusage.Usage
+}
+
+// Build assembles a Report from final job states.
+func Build(states []*models.JobState, opts Options) *Report {
+	r := &Report{
+		RunID:       opts.RunID,
+		StateFile:   opts.StateFile,
+		PlatformURL: strings.TrimRight(opts.PlatformURL, "/"),
+		StartTime:   opts.StartTime,
+		EndTime:     opts.EndTime,
+	}
+	if !opts.StartTime.IsZero() && !opts.EndTime.IsZero() {
+		r.Duration = opts.EndTime.Sub(opts.StartTime)
+	}
+	if opts.NetworkUsage.Total() > 0 {
+		usage := opts.NetworkUsage
+		r.Network = &usage
+	}
+
+	for _, st := range states {
+		if st == nil {
+			continue
+		}
+		entry := JobEntry{
+			Index:        st.Index,
+			JobName:      st.JobName,
+			Directory:    st.Directory,
+			JobID:        st.JobID,
+			TarStatus:    st.TarStatus,
+			UploadStatus: st.UploadStatus,
+			SubmitStatus: st.SubmitStatus,
+			Status:       jobOutcome(st),
+			Error:        st.ErrorMessage,
+		}
+		entry.SkippedFiles = st.SkippedFileList()
+		entry.NormalizedFiles = st.NormalizedFileList()
+		entry.Warnings = st.WarningList()
+		if st.JobID != "" {
+			entry.JobURL = JobURL(r.PlatformURL, st.JobID)
+		}
+		if d, ok := opts.StageDurations[st.Index]; ok && len(d) > 0 {
+			entry.StageDurations = d
+		}
+
+		switch entry.Status {
+		case "succeeded":
+			r.Totals.Succeeded++
+		case "failed":
+			r.Totals.Failed++
+		default:
+			r.Totals.Incomplete++
+		}
+		r.Jobs = append(r.Jobs, entry)
+	}
+	r.Totals.Jobs = len(r.Jobs)
+	return r
+}
+
+// JobURL returns the web portal link for a job, or "" when the platform is unknown.
+func JobURL(platformURL, jobID string) string {
+	if platformURL == "" || jobID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/jobs/%s/", strings.TrimRight(platformURL, "/"), jobID)
+}
+
+// jobOutcome classifies a job state for the report.
+func jobOutcome(st *models.JobState) string {
+	if st.TarStatus == "failed" || st.UploadStatus == "failed" || st.SubmitStatus == "failed" {
+		return "failed"
+	}
+	switch st.SubmitStatus {
+	case "success", "skipped":
+		return "succeeded"
+	}
+	return "incomplete"
+}
+
+// DefaultPaths returns the HTML and JSON report paths placed next to stateFile
+// (e.g. run_123.state -> run_123.report.html / run_123.report.json).
+func DefaultPaths(stateFile string) (htmlPath, jsonPath string) {
+	base := strings.TrimSuffix(stateFile, filepath.Ext(stateFile))
+	return base + ".report.html", base + ".report.json"
+}
+
+// PathsFor derives both report paths from a user-supplied output path. A ".json"
+// path names the JSON artifact; anything else names the HTML artifact.
+func PathsFor(out string) (htmlPath, jsonPath string) {
+	ext := strings.ToLower(filepath.Ext(out))
+	base := strings.TrimSuffix(out, filepath.Ext(out))
+	switch ext {
+	case ".json":
+		return base + ".html", out
+	case ".html", ".htm":
+		return out, base + ".json"
+	}
+	return out + ".html", out + ".json"
+}
+
+// WriteFiles writes both the HTML and JSON artifacts.
+func (r *Report) WriteFiles(htmlPath, jsonPath string) error {
+	if err := r.WriteJSON(jsonPath); err != nil {
+		return err
+	}
+	return r.WriteHTML(htmlPath)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run report JSON: %w", err)
+	}
+	return nil
+}
+
+// ReadJSON reads a report written by WriteJSON.
+func ReadJSON(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse run report %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// WriteHTML renders the report as a self-contained HTML page.
+func (r *Report) WriteHTML(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create run report HTML: %w", err)
+	}
+	if err := htmlTemplate.Execute(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render run report HTML: %w", err)
+	}
+	return f.Close()
+}
+
+// Failures returns the entries whose outcome is "failed".
+func (r *Report) Failures() []JobEntry {
+	var out []JobEntry
+	for _, j := range r.Jobs {
+		if j.Status == "failed" {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"dur":    formatDuration,
+	"join":   strings.Join,
+	"stages": func() []string { return Stages },
+	"stage": func(j JobEntry, s string) string {
+		return formatDuration(j.StageDurations[s])
+	},
+	"ts": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format(time.RFC1123)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Rescale Interlink run report {{.RunID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+th { background: #f4f4f4; }
+.succeeded { color: #1a7f37; } .failed { color: #c62828; } .incomplete { color: #9a6700; }
+.totals span { margin-right: 1.5em; }
+</style>
+</head>
+<body>
+<h1>Run report: {{.RunID}}</h1>
+<p>Started {{ts .StartTime}} &middot; Finished {{ts .EndTime}} &middot; Duration {{dur .Duration}}{{with .Network}} &middot; Network {{.String}}{{end}}</p>
+<p class="totals"><span>Jobs: {{.Totals.Jobs}}</span><span class="succeeded">Succeeded: {{.Totals.Succeeded}}</span><span class="failed">Failed: {{.Totals.Failed}}</span><span class="incomplete">Incomplete: {{.Totals.Incomplete}}</span></p>
+<table>
+<thead><tr><th>#</th><th>Job</th><th>Status</th><th>Job ID</th>{{range stages}}<th>{{.}}</th>{{end}}<th>Error</th></tr></thead>
+<tbody>
+{{range .Jobs}}{{$j := .}}<tr>
+<td>{{.Index}}</td><td title="{{.Directory}}">{{.JobName}}</td><td class="{{.Status}}">{{.Status}}</td>
+<td>{{if .JobURL}}<a href="{{.JobURL}}">{{.JobID}}</a>{{else}}{{.JobID}}{{end}}</td>
+{{range stages}}<td>{{stage $j .}}</td>{{end}}
+<td>{{.Error}}{{with .SkippedFiles}}{{if $j.Error}}<br>{{end}}Skipped locked files: {{join . ", "}}{{end}}{{with .NormalizedFiles}}{{if or $j.Error $j.SkippedFiles}}<br>{{end}}Text files with CRLF or BOM: {{join . ", "}}{{end}}{{range $i, $w := .Warnings}}{{if or $i $j.Error $j.SkippedFiles $j.NormalizedFiles}}<br>{{end}}<span class="incomplete">&#9888; {{$w}}</span>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{with .Failures}}<h2>Failures</h2>
+<ul>{{range .}}<li><strong>{{.JobName}}</strong>: {{.Error}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
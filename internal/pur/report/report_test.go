@@ -0,0 +1,134 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/netusage"
+)
+
+func testStates() []*models.JobState {
+	return []*models.JobState{
+		{Index: 1, JobName: "Run_1", TarStatus: "success", UploadStatus: "success", JobID: "abc", SubmitStatus: "success"},
+		{Index: 2, JobName: "Run_2", TarStatus: "failed", UploadStatus: "pending", SubmitStatus: "failed", ErrorMessage: "tar <boom>"},
+		{Index: 3, JobName: "Run_3", TarStatus: "success", UploadStatus: "in_progress", SubmitStatus: "pending",
+			Warnings: "DEPRECATED_VERSION: version 1.0 is deprecated\nlicense <conflict>"},
+	}
+}
+
+func TestBuild_TotalsAndLinks(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := Build(testStates(), Options{
+		RunID:       "run_1",
+		PlatformURL: "https://platform.rescale.com/",
+		StartTime:   start,
+		EndTime:     start.Add(90 * time.Second),
+		StageDurations: map[int]map[string]time.Duration{
+			1: {"tar": 2 * time.Second, "upload": 10 * time.Second},
+		},
+	})
+
+	if r.Totals != (Totals{Jobs: 3, Succeeded: 1, Failed: 1, Incomplete: 1}) {
+		t.Errorf("Totals = %+v", r.Totals)
+	}
+	if r.Duration != 90*time.Second {
+		t.Errorf("Duration = %v, want 90s", r.Duration)
+	}
+	if got := r.Jobs[0].JobURL; got != "https://platform.rescale.com/jobs/abc/" {
+		t.Errorf("JobURL = %q", got)
+	}
+	if r.Jobs[1].JobURL != "" {
+		t.Errorf("job without ID should have no URL, got %q", r.Jobs[1].JobURL)
+	}
+	if r.Jobs[0].StageDurations["upload"] != 10*time.Second {
+		t.Errorf("stage durations not carried through: %v", r.Jobs[0].StageDurations)
+	}
+	if got := r.Jobs[2].Warnings; len(got) != 2 || got[1] != "license <conflict>" {
+		t.Errorf("Warnings = %q", got)
+	}
+	if f := r.Failures(); len(f) != 1 || f[0].JobName != "Run_2" {
+		t.Errorf("Failures() = %+v", f)
+	}
+}
+
+func TestBuild_StageDurationsByIndex(t *testing.T) {
+	states := []*models.JobState{
+		{Index: 1, JobName: "case"},
+		{Index: 2, JobName: "case"},
+	}
+	r := Build(states, Options{StageDurations: map[int]map[string]time.Duration{
+		1: {"tar": time.Second},
+		2: {"tar": 5 * time.Second},
+	}})
+	if r.Jobs[0].StageDurations["tar"] != time.Second || r.Jobs[1].StageDurations["tar"] != 5*time.Second {
+		t.Errorf("jobs sharing a name got durations %v and %v", r.Jobs[0].StageDurations, r.Jobs[1].StageDurations)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	htmlPath, jsonPath := DefaultPaths(filepath.Join(dir, "run_1.state"))
+	if filepath.Base(htmlPath) != "run_1.report.html" || filepath.Base(jsonPath) != "run_1.report.json" {
+		t.Fatalf("DefaultPaths = %s, %s", htmlPath, jsonPath)
+	}
+
+	r := Build(testStates(), Options{
+		RunID:        "run_1",
+		PlatformURL:  "https://platform.rescale.com",
+		NetworkUsage: netusage.Usage{Sent: 3 << 20, Received: 2048},
+	})
+	if err := r.WriteFiles(htmlPath, jsonPath); err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), `href="https://platform.rescale.com/jobs/abc/"`) {
+		t.Error("HTML report missing job link")
+	}
+	if strings.Contains(string(html), "tar <boom>") {
+		t.Error("HTML report did not escape error message")
+	}
+	if !strings.Contains(string(html), "license &lt;conflict&gt;") {
+		t.Error("HTML report missing escaped platform warning")
+	}
+	if !strings.Contains(string(html), "Network 3.0 MB sent, 2.0 KB received") {
+		t.Error("HTML report missing network usage")
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	if decoded.Totals.Jobs != 3 {
+		t.Errorf("decoded Totals.Jobs = %d, want 3", decoded.Totals.Jobs)
+	}
+	if decoded.Network == nil || decoded.Network.Sent != 3<<20 {
+		t.Errorf("decoded Network = %+v, want 3 MiB sent", decoded.Network)
+	}
+}
+
+func TestPathsFor(t *testing.T) {
+	cases := []struct{ in, html, json string }{
+		{"out/report.html", "out/report.html", "out/report.json"},
+		{"out/report.json", "out/report.html", "out/report.json"},
+		{"out/report", "out/report.html", "out/report.json"},
+	}
+	for _, c := range cases {
+		h, j := PathsFor(c.in)
+		if h != c.html || j != c.json {
+			t.Errorf("PathsFor(%q) = %q, %q; want %q, %q", c.in, h, j, c.html, c.json)
+		}
+	}
+}
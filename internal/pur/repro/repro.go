@@ -0,0 +1,332 @@
+// Package repro records what a PUR run was started with so it can be
+// reproduced later or attached to a support ticket: the Interlink build, FIPS
+// status, effective configuration (secrets redacted), job templates, scan
+// options and the software and hardware the jobs reference. The bundle is
+// written next to the run's state file as <base>.repro.json.
+package repro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	intfips "github.com/rescale/rescale-int/internal/fips"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+// maxTemplates caps the distinct job templates kept in a bundle, so a sweep
+// with per-job commands doesn't produce a bundle as large as the jobs CSV.
+const maxTemplates = 20
+
+// Redacted replaces secret values in the config snapshot.
+const Redacted = "[REDACTED]"
+
+// Build identifies the Interlink binary that started the run.
+type Build struct {
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	BuildTime   string `json:"buildTime"`
+	GoVersion   string `json:"goVersion"`
+	OS          string `json:"os"`
+	Arch        string `json:"arch"`
+	FIPSEnabled bool   `json:"fipsEnabled"`
+}
+
+// Setting is one effective configuration value, keyed as in config.csv.
+type Setting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ScanOptions are the inputs that decide which files each job archives and
+// uploads, after command-line overrides.
+type ScanOptions struct {
+	IncludePatterns   []string `json:"includePatterns,omitempty"`
+	ExcludePatterns   []string `json:"excludePatterns,omitempty"`
+	FlattenTar        bool     `json:"flattenTar"`
+	RunSubpath        string   `json:"runSubpath,omitempty"`
+	ValidationPattern string   `json:"validationPattern,omitempty"`
+	TarCompression    string   `json:"tarCompression,omitempty"`
+	TarSplitMode      string   `json:"tarSplitMode,omitempty"`
+	TarSplitParts     int      `json:"tarSplitParts,omitempty"`
+	MultiPart         bool     `json:"multiPart"`
+	ExtraInputFiles   string   `json:"extraInputFiles,omitempty"`
+	DecompressExtras  bool     `json:"decompressExtras"`
+}
+
+// Analysis is a software catalog entry referenced by the run's jobs.
+type Analysis struct {
+	Code    string `json:"code"`
+	Version string `json:"version,omitempty"`
+	Jobs    int    `json:"jobs"`
+}
+
+// Catalog lists the software and hardware catalog entries the jobs use.
+type Catalog struct {
+	Analyses  []Analysis `json:"analyses"`
+	CoreTypes []string   `json:"coreTypes"`
+}
+
+// Bundle is the reproducibility record for one run.
+type Bundle struct {
+	RunID       string           `json:"runId"`
+	StateFile   string           `json:"stateFile,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	Resumed     bool             `json:"resumed,omitempty"`
+	Build       Build            `json:"build"`
+	Config      []Setting        `json:"config"`
+	JobsSource  string           `json:"jobsSource,omitempty"`
+	JobCount    int              `json:"jobCount"`
+	Templates   []models.JobSpec `json:"templates"`
+	ScanOptions ScanOptions      `json:"scanOptions"`
+	Catalog     Catalog          `json:"catalog"`
+}
+
+// Options carries the run-level inputs to New that aren't in the config.
+type Options struct {
+	StateFile        string
+	JobsSource       string // Jobs CSV path, "stdin" or "inline JSON"; empty for GUI runs
+	Resumed          bool
+	MultiPart        bool
+	ExtraInputFiles  string
+	DecompressExtras bool
+}
+
+// New assembles a bundle from the effective config and the jobs about to run.
+func New(cfg *config.Config, jobs []models.JobSpec, opts Options) *Bundle {
+	b := &Bundle{
+		RunID:      RunID(opts.StateFile),
+		StateFile:  opts.StateFile,
+		CreatedAt:  time.Now(),
+		Resumed:    opts.Resumed,
+		Build:      CurrentBuild(),
+		JobsSource: opts.JobsSource,
+		JobCount:   len(jobs),
+		Templates:  templates(jobs),
+		Catalog:    catalog(jobs),
+		ScanOptions: ScanOptions{
+			MultiPart:        opts.MultiPart,
+			ExtraInputFiles:  opts.ExtraInputFiles,
+			DecompressExtras: opts.DecompressExtras,
+		},
+	}
+	if cfg != nil {
+		b.Config = settings(cfg)
+		b.ScanOptions.IncludePatterns = cfg.IncludePatterns
+		b.ScanOptions.ExcludePatterns = cfg.ExcludePatterns
+		b.ScanOptions.FlattenTar = cfg.FlattenTar
+		b.ScanOptions.RunSubpath = cfg.RunSubpath
+		b.ScanOptions.ValidationPattern = cfg.ValidationPattern
+		b.ScanOptions.TarCompression = cfg.TarCompression
+		b.ScanOptions.TarSplitMode = cfg.TarSplitMode
+		b.ScanOptions.TarSplitParts = cfg.TarSplitParts
+	}
+	return b
+}
+
+// CurrentBuild describes the running binary.
+func CurrentBuild() Build {
+	return Build{
+		Version:     version.Version,
+		Commit:      version.Commit(),
+		BuildTime:   version.BuildTime,
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		FIPSEnabled: intfips.Enabled,
+	}
+}
+
+// RunID derives the run ID from the state file name, as the run report does.
+func RunID(stateFile string) string {
+	return strings.TrimSuffix(filepath.Base(stateFile), filepath.Ext(stateFile))
+}
+
+// settings snapshots the saved config keys. Config.Records never includes the
+// API key, proxy password, webhook secret, chat webhook URLs or SMTP
+// password; their presence is recorded as Redacted so support can tell a
+// missing credential from a rejected one.
+func settings(cfg *config.Config) []Setting {
+	var out []Setting
+	for _, rec := range cfg.Records() {
+		out = append(out, Setting{Key: rec[0], Value: rec[1]})
+	}
+	for _, secret := range []struct{ key, value string }{
+		{"api_key", cfg.APIKey},
+		{"proxy_password", cfg.ProxyPassword},
+		{"webhook_secret", cfg.WebhookSecret},
+		{"slack_webhook_url", cfg.SlackWebhookURL},
+		{"teams_webhook_url", cfg.TeamsWebhookURL},
+		{"smtp_password", cfg.SMTPPassword},
+	} {
+		if secret.value != "" {
+			out = append(out, Setting{Key: secret.key, Value: Redacted})
+		}
+	}
+	return out
+}
+
+// templates returns the distinct job definitions, ignoring the per-job
+// directory and name, in first-seen order and capped at maxTemplates.
+func templates(jobs []models.JobSpec) []models.JobSpec {
+	var out []models.JobSpec
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		job.Directory = ""
+		job.JobName = ""
+		job.InputFiles = nil
+		key, err := json.Marshal(job)
+		if err != nil || seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+		out = append(out, job)
+		if len(out) == maxTemplates {
+			break
+		}
+	}
+	return out
+}
+
+func catalog(jobs []models.JobSpec) Catalog {
+	counts := make(map[Analysis]int)
+	coreTypes := make(map[string]bool)
+	for _, job := range jobs {
+		if job.AnalysisCode != "" {
+			counts[Analysis{Code: job.AnalysisCode, Version: job.AnalysisVersion}]++
+		}
+		if job.CoreType != "" {
+			coreTypes[job.CoreType] = true
+		}
+	}
+
+	c := Catalog{Analyses: []Analysis{}, CoreTypes: []string{}}
+	for a, n := range counts {
+		a.Jobs = n
+		c.Analyses = append(c.Analyses, a)
+	}
+	sort.Slice(c.Analyses, func(i, j int) bool {
+		if c.Analyses[i].Code != c.Analyses[j].Code {
+			return c.Analyses[i].Code < c.Analyses[j].Code
+		}
+		return c.Analyses[i].Version < c.Analyses[j].Version
+	})
+	for ct := range coreTypes {
+		c.CoreTypes = append(c.CoreTypes, ct)
+	}
+	sort.Strings(c.CoreTypes)
+	return c
+}
+
+// DefaultPath returns the bundle path for a state file: the state file's path
+// with its extension replaced by ".repro.json".
+func DefaultPath(stateFile string) string {
+	return strings.TrimSuffix(stateFile, filepath.Ext(stateFile)) + ".repro.json"
+}
+
+// WriteJSON writes the bundle as indented JSON.
+func (b *Bundle) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reproducibility bundle: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write reproducibility bundle: %w", err)
+	}
+	return nil
+}
+
+// ReadJSON reads a bundle written by WriteJSON.
+func ReadJSON(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reproducibility bundle: %w", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse reproducibility bundle %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// Print writes a human-readable summary of the bundle.
+func (b *Bundle) Print(w io.Writer) {
+	fmt.Fprintf(w, "Run:            %s\n", b.RunID)
+	if b.StateFile != "" {
+		fmt.Fprintf(w, "State file:     %s\n", b.StateFile)
+	}
+	started := b.CreatedAt.Local().Format(time.RFC3339)
+	if b.Resumed {
+		started += " (resumed)"
+	}
+	fmt.Fprintf(w, "Started:        %s\n", started)
+	fmt.Fprintf(w, "Interlink:      %s (commit %s, built %s)\n", b.Build.Version, b.Build.Commit, b.Build.BuildTime)
+	fmt.Fprintf(w, "Platform:       %s/%s, %s\n", b.Build.OS, b.Build.Arch, b.Build.GoVersion)
+	fmt.Fprintf(w, "FIPS 140-3:     %s\n", enabled(b.Build.FIPSEnabled))
+	if b.JobsSource != "" {
+		fmt.Fprintf(w, "Jobs:           %d from %s\n", b.JobCount, b.JobsSource)
+	} else {
+		fmt.Fprintf(w, "Jobs:           %d\n", b.JobCount)
+	}
+
+	s := b.ScanOptions
+	fmt.Fprintln(w, "\nScan options:")
+	fmt.Fprintf(w, "  Include:          %s\n", orNone(strings.Join(s.IncludePatterns, ";")))
+	fmt.Fprintf(w, "  Exclude:          %s\n", orNone(strings.Join(s.ExcludePatterns, ";")))
+	fmt.Fprintf(w, "  Run subpath:      %s\n", orNone(s.RunSubpath))
+	fmt.Fprintf(w, "  Validation:       %s\n", orNone(s.ValidationPattern))
+	fmt.Fprintf(w, "  Flatten tar:      %t\n", s.FlattenTar)
+	fmt.Fprintf(w, "  Compression:      %s\n", orNone(s.TarCompression))
+	if s.TarSplitMode != "" {
+		fmt.Fprintf(w, "  Tar split:        %s (%d parts)\n", s.TarSplitMode, s.TarSplitParts)
+	}
+	fmt.Fprintf(w, "  Multi-part:       %t\n", s.MultiPart)
+	if s.ExtraInputFiles != "" {
+		fmt.Fprintf(w, "  Extra inputs:     %s (decompress: %t)\n", s.ExtraInputFiles, s.DecompressExtras)
+	}
+
+	fmt.Fprintln(w, "\nCatalog:")
+	for _, a := range b.Catalog.Analyses {
+		fmt.Fprintf(w, "  %s %s (%d job(s))\n", a.Code, orNone(a.Version), a.Jobs)
+	}
+	fmt.Fprintf(w, "  Core types: %s\n", orNone(strings.Join(b.Catalog.CoreTypes, ", ")))
+
+	fmt.Fprintf(w, "\nTemplates (%d distinct):\n", len(b.Templates))
+	for i, t := range b.Templates {
+		fmt.Fprintf(w, "  %d. %s %s on %s, %d core(s)/slot, %g h: %s\n",
+			i+1, t.AnalysisCode, orNone(t.AnalysisVersion), orNone(t.CoreType), t.CoresPerSlot, t.WalltimeHours, t.Command)
+	}
+
+	fmt.Fprintln(w, "\nEffective config:")
+	for _, set := range b.Config {
+		if set.Value == "" {
+			continue
+		}
+		fmt.Fprintf(w, "  %-24s %s\n", set.Key, set.Value)
+	}
+}
+
+func enabled(v bool) string {
+	if v {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package repro
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func testJobs() []models.JobSpec {
+	job := models.JobSpec{
+		AnalysisCode:    "openfoam",
+		AnalysisVersion: "v2312",
+		Command:         "./Allrun",
+		CoreType:        "emerald",
+		CoresPerSlot:    4,
+		WalltimeHours:   2,
+	}
+	var jobs []models.JobSpec
+	for i, dir := range []string{"Run_1", "Run_2", "Run_3"} {
+		j := job
+		j.Directory = "/data/" + dir
+		j.JobName = dir
+		if i == 2 {
+			j.AnalysisVersion = "v2406"
+			j.CoreType = "onyx"
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+func TestNew(t *testing.T) {
+	cfg := &config.Config{
+		APIKey:          "sk-secret",
+		ProxyUser:       "alice",
+		IncludePatterns: []string{"*.dat"},
+		TarCompression:  "gzip",
+	}
+	b := New(cfg, testJobs(), Options{StateFile: "/state/run_42.state", JobsSource: "jobs.csv", MultiPart: true})
+
+	if b.RunID != "run_42" || b.JobCount != 3 {
+		t.Errorf("RunID = %q, JobCount = %d", b.RunID, b.JobCount)
+	}
+	if len(b.Templates) != 2 {
+		t.Fatalf("Templates = %d, want 2 (per-job directory and name ignored)", len(b.Templates))
+	}
+	if b.Templates[0].Directory != "" || b.Templates[0].JobName != "" {
+		t.Errorf("template kept per-job fields: %+v", b.Templates[0])
+	}
+	if len(b.Catalog.Analyses) != 2 || b.Catalog.Analyses[0] != (Analysis{"openfoam", "v2312", 2}) {
+		t.Errorf("Analyses = %+v", b.Catalog.Analyses)
+	}
+	if got := strings.Join(b.Catalog.CoreTypes, ","); got != "emerald,onyx" {
+		t.Errorf("CoreTypes = %q", got)
+	}
+	if !b.ScanOptions.MultiPart || b.ScanOptions.TarCompression != "gzip" || b.ScanOptions.IncludePatterns[0] != "*.dat" {
+		t.Errorf("ScanOptions = %+v", b.ScanOptions)
+	}
+
+	values := make(map[string]string)
+	for _, s := range b.Config {
+		values[s.Key] = s.Value
+	}
+	if values["api_key"] != Redacted {
+		t.Errorf("api_key = %q, want %q", values["api_key"], Redacted)
+	}
+	if _, ok := values["proxy_password"]; ok {
+		t.Error("unset proxy_password recorded")
+	}
+	if values["proxy_user"] != "alice" {
+		t.Errorf("proxy_user = %q", values["proxy_user"])
+	}
+}
+
+func TestWriteReadJSON(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "run_1.state")
+	path := DefaultPath(stateFile)
+	if filepath.Base(path) != "run_1.repro.json" {
+		t.Errorf("DefaultPath = %q", path)
+	}
+
+	b := New(&config.Config{APIKey: "sk-secret"}, testJobs(), Options{StateFile: stateFile})
+	if err := b.WriteJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("sk-secret")) {
+		t.Error("bundle contains the API key")
+	}
+
+	got, err := ReadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RunID != "run_1" || len(got.Templates) != 2 || got.Build.Version == "" {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	var out bytes.Buffer
+	got.Print(&out)
+	for _, want := range []string{"Run:            run_1", "openfoam v2406 (1 job(s))", "api_key"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Print output missing %q:\n%s", want, out.String())
+		}
+	}
+}
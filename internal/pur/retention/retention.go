@@ -0,0 +1,346 @@
+// Package retention carries out the end-of-run data retention actions a run
+// recipe asks for: deleting local tars, archiving the state file and run
+// logs, deleting the uploaded inputs on the platform after some days and
+// downloading the outputs once the jobs finish.
+//
+// A Policy lives next to the recipe's jobs file (see PolicyPath). When a run
+// ends, Plan turns it into Tasks, which are kept in a per-platform queue
+// file (see config.GetRetentionQueuePath) until a Worker carries them out.
+// Tasks snapshot what they need from the state file, so archiving the state
+// does not stop a later download or deletion. Every scheduled, completed,
+// retried, abandoned and cancelled task is recorded in an append-only JSON
+// Lines audit log (see config.GetRetentionAuditPath).
+package retention
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+)
+
+// Actions a task can carry out.
+const (
+	ActionDeleteLocalTars    = "delete_local_tars"
+	ActionArchive            = "archive"
+	ActionDeleteRemoteInputs = "delete_remote_inputs"
+	ActionDownloadOutputs    = "download_outputs"
+)
+
+// Policy is a run recipe's retention settings. The zero value does nothing.
+type Policy struct {
+	// Delete the local tars of jobs whose inputs were uploaded
+	DeleteLocalTars bool `json:"deleteLocalTars,omitempty"`
+
+	// Compress the state file, run report and reproducibility bundle into
+	// a .tar.gz in this folder and remove the originals. Skipped while the
+	// run has unfinished jobs, since resuming needs the state file.
+	ArchiveDir string `json:"archiveDir,omitempty"`
+
+	// Delete the run's uploaded input tars from the platform this many days
+	// after the run; 0 keeps them
+	DeleteRemoteInputsAfterDays int `json:"deleteRemoteInputsAfterDays,omitempty"`
+
+	// Download the outputs of the run's jobs here as they finish, laid out
+	// like `pur download-outputs`
+	DownloadOutputsTo string `json:"downloadOutputsTo,omitempty"`
+}
+
+// PolicyPath returns the retention policy file of a jobs file: its path with
+// the extension replaced by ".retention.json".
+func PolicyPath(jobsFile string) string {
+	return strings.TrimSuffix(jobsFile, filepath.Ext(jobsFile)) + ".retention.json"
+}
+
+// LoadPolicy reads a retention policy file. Unknown fields are rejected so a
+// misspelled action is not silently ignored.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retention policy: %w", err)
+	}
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse retention policy %s: %w", path, err)
+	}
+	if p.DeleteRemoteInputsAfterDays < 0 {
+		return nil, fmt.Errorf("retention policy %s: deleteRemoteInputsAfterDays must not be negative", path)
+	}
+	return &p, nil
+}
+
+// Empty reports whether p asks for no action.
+func (p *Policy) Empty() bool {
+	return p == nil || *p == Policy{}
+}
+
+// Run is a finished run as Plan needs it.
+type Run struct {
+	RunID     string
+	StateFile string   // Empty for a run without one
+	Files     []string // Run logs archived with the state file (report, bundle)
+	States    []*models.JobState
+	OutputDir map[string]string // Job ID -> output directory under DownloadOutputsTo
+	Finished  time.Time
+}
+
+// Job is a submitted job whose outputs a task still has to download.
+type Job struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	Dir  string `json:"dir"` // Relative to Task.Dest
+}
+
+// Task is one pending retention action of a run.
+type Task struct {
+	ID        string    `json:"id"`
+	RunID     string    `json:"runId"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"createdAt"`
+	DueAt     time.Time `json:"dueAt"`
+
+	StateFile string   `json:"stateFile,omitempty"` // archive: kept while the run is resumable
+	Paths     []string `json:"paths,omitempty"`     // Local tars, or files to archive
+	Dest      string   `json:"dest,omitempty"`      // Archive or download folder
+	FileIDs   []string `json:"fileIds,omitempty"`   // Remote inputs to delete
+	Jobs      []Job    `json:"jobs,omitempty"`      // Jobs left to download
+
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Plan returns the tasks p asks for after run. Tasks that would have nothing
+// to do (no tars, no uploaded inputs, no submitted jobs) are left out.
+func Plan(p *Policy, run Run) []Task {
+	if p.Empty() {
+		return nil
+	}
+	newTask := func(action string, due time.Time) Task {
+		return Task{ID: newTaskID(), RunID: run.RunID, Action: action, CreatedAt: run.Finished, DueAt: due}
+	}
+
+	var tars, fileIDs []string
+	var jobs []Job
+	for _, st := range run.States {
+		if st.UploadStatus == "success" {
+			for _, path := range st.TarPaths() {
+				if path != "" {
+					tars = append(tars, path)
+				}
+			}
+			for _, id := range st.FileIDs() {
+				if id != "" {
+					fileIDs = append(fileIDs, id)
+				}
+			}
+		}
+		if st.JobID != "" && st.SubmitStatus == "success" {
+			dir := run.OutputDir[st.JobID]
+			if dir == "" {
+				dir = st.JobName
+			}
+			jobs = append(jobs, Job{Name: st.JobName, ID: st.JobID, Dir: dir})
+		}
+	}
+
+	var tasks []Task
+	if p.DeleteLocalTars && len(tars) > 0 {
+		t := newTask(ActionDeleteLocalTars, run.Finished)
+		t.Paths = tars
+		tasks = append(tasks, t)
+	}
+	if p.DownloadOutputsTo != "" && len(jobs) > 0 {
+		t := newTask(ActionDownloadOutputs, run.Finished)
+		t.Dest = p.DownloadOutputsTo
+		t.Jobs = jobs
+		tasks = append(tasks, t)
+	}
+	if p.DeleteRemoteInputsAfterDays > 0 && len(fileIDs) > 0 {
+		t := newTask(ActionDeleteRemoteInputs, run.Finished.AddDate(0, 0, p.DeleteRemoteInputsAfterDays))
+		t.FileIDs = fileIDs
+		tasks = append(tasks, t)
+	}
+	if p.ArchiveDir != "" && run.StateFile != "" {
+		t := newTask(ActionArchive, run.Finished)
+		t.StateFile = run.StateFile
+		t.Paths = append([]string{run.StateFile}, run.Files...)
+		t.Dest = p.ArchiveDir
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+func newTaskID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Queue is the file of pending tasks. Each change rewrites the file whole;
+// a process that finishes a task re-reads the file first, so tasks added by
+// another process meanwhile are kept.
+type Queue struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewQueue returns the queue kept at path.
+func NewQueue(path string) *Queue {
+	return &Queue{path: path}
+}
+
+// Tasks returns the queued tasks, soonest due first. A missing file is an
+// empty queue.
+func (q *Queue) Tasks() ([]Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.load()
+}
+
+// Add queues tasks.
+func (q *Queue) Add(tasks ...Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return q.update(func(queued []Task) []Task { return append(queued, tasks...) })
+}
+
+// Remove drops the tasks with the given IDs, or every task of a run when
+// runID is not empty, and returns the removed tasks.
+func (q *Queue) Remove(runID string, ids ...string) ([]Task, error) {
+	drop := make(map[string]bool)
+	for _, id := range ids {
+		drop[id] = true
+	}
+	var removed []Task
+	err := q.update(func(queued []Task) []Task {
+		var kept []Task
+		for _, t := range queued {
+			if drop[t.ID] || (runID != "" && t.RunID == runID) {
+				removed = append(removed, t)
+				continue
+			}
+			kept = append(kept, t)
+		}
+		return kept
+	})
+	return removed, err
+}
+
+// put replaces the queued task with t's ID by t, if it is still queued.
+func (q *Queue) put(t Task) error {
+	return q.update(func(queued []Task) []Task {
+		for i := range queued {
+			if queued[i].ID == t.ID {
+				queued[i] = t
+			}
+		}
+		return queued
+	})
+}
+
+func (q *Queue) update(fn func([]Task) []Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	tasks, err := q.load()
+	if err != nil {
+		return err
+	}
+	return q.save(fn(tasks))
+}
+
+func (q *Queue) load() ([]Task, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retention queue: %w", err)
+	}
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse retention queue %s: %w", q.path, err)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DueAt.Before(tasks[j].DueAt) })
+	return tasks, nil
+}
+
+func (q *Queue) save(tasks []Task) error {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0700); err != nil {
+		return fmt.Errorf("failed to create retention queue directory: %w", err)
+	}
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write retention queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write retention queue: %w", err)
+	}
+	return nil
+}
+
+// Audit results.
+const (
+	ResultScheduled = "scheduled"
+	ResultDone      = "done"
+	ResultPartial   = "partial"   // Some of the work done; the rest is retried
+	ResultRetry     = "retry"     // Failed; tried again later
+	ResultSkipped   = "skipped"   // Nothing to do, or not safe to do
+	ResultAbandoned = "abandoned" // Given up on
+	ResultCancelled = "cancelled" // Removed from the queue by the user
+)
+
+// AuditEntry is one line of the audit log.
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	RunID  string    `json:"runId"`
+	TaskID string    `json:"taskId"`
+	Action string    `json:"action"`
+	Result string    `json:"result"`
+	Detail string    `json:"detail,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// AppendAudit adds e to the audit log at path, creating the file and its
+// directory if needed.
+func AppendAudit(path string, e AuditEntry) error {
+	return jsonl.Append(path, "retention audit log", e)
+}
+
+// ReadAudit returns the audit log at path, oldest first, optionally only
+// the entries of one run. A missing file has none; malformed lines are
+// skipped.
+func ReadAudit(path, runID string) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	err := jsonl.Read(path, "retention audit log", func(e AuditEntry) {
+		if runID == "" || e.RunID == runID {
+			entries = append(entries, e)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,285 @@
+package retention
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+// fakePlatform records deletions and finishes the jobs in finished.
+type fakePlatform struct {
+	deleted  []string
+	failIDs  map[string]bool
+	finished map[string]bool
+}
+
+func (p *fakePlatform) DeleteFile(ctx context.Context, fileID string) error {
+	if p.failIDs[fileID] {
+		return errors.New("HTTP 500")
+	}
+	p.deleted = append(p.deleted, fileID)
+	return nil
+}
+
+func (p *fakePlatform) DownloadOutputs(ctx context.Context, jobs []Job, dest string) ([]Job, error) {
+	var pending []Job
+	for _, j := range jobs {
+		if !p.finished[j.ID] {
+			pending = append(pending, j)
+		}
+	}
+	return pending, nil
+}
+
+// writeRun writes a finished two-job run: a state file, its report and one
+// tar per job, and returns the Run.
+func writeRun(t *testing.T, dir string, submitted bool) Run {
+	t.Helper()
+	stateFile := filepath.Join(dir, "wing.csv")
+	mgr := state.NewManager(stateFile)
+	for i, name := range []string{"Run_1", "Run_2"} {
+		tarPath := filepath.Join(dir, name+".tar.gz")
+		if err := os.WriteFile(tarPath, []byte("tar"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		st := mgr.InitializeState(i, name, filepath.Join(dir, name))
+		st.TarPath, st.TarStatus = tarPath, "success"
+		st.FileID, st.UploadStatus = "file"+name, "success"
+		st.JobID, st.SubmitStatus = "job"+name, "success"
+		if !submitted && i == 1 {
+			st.JobID, st.SubmitStatus = "", "failed"
+		}
+		if err := mgr.UpdateState(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mgr.Save(); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "wing.report.html")
+	if err := os.WriteFile(report, []byte("<html>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return Run{
+		RunID:     "wing",
+		StateFile: stateFile,
+		Files:     []string{report, filepath.Join(dir, "wing.repro.json")}, // No bundle written
+		States:    mgr.GetAllStates(),
+		Finished:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPlan(t *testing.T) {
+	run := writeRun(t, t.TempDir(), false)
+	tasks := Plan(&Policy{
+		DeleteLocalTars:             true,
+		ArchiveDir:                  "/archive",
+		DeleteRemoteInputsAfterDays: 30,
+		DownloadOutputsTo:           "/results",
+	}, run)
+
+	byAction := make(map[string]Task)
+	for _, task := range tasks {
+		byAction[task.Action] = task
+	}
+	if len(byAction) != 4 {
+		t.Fatalf("Plan() = %d tasks %v, want one of each action", len(tasks), tasks)
+	}
+	if got := byAction[ActionDeleteLocalTars].Paths; len(got) != 2 {
+		t.Errorf("tars to delete = %v, want both uploaded tars", got)
+	}
+	if got := byAction[ActionDownloadOutputs].Jobs; len(got) != 1 || got[0].ID != "jobRun_1" {
+		t.Errorf("jobs to download = %v, want only the submitted job", got)
+	}
+	remote := byAction[ActionDeleteRemoteInputs]
+	if want := run.Finished.AddDate(0, 0, 30); !remote.DueAt.Equal(want) || len(remote.FileIDs) != 2 {
+		t.Errorf("remote deletion = %v files due %v, want 2 due %v", remote.FileIDs, remote.DueAt, want)
+	}
+
+	if tasks := Plan(&Policy{}, run); len(tasks) != 0 {
+		t.Errorf("Plan() with an empty policy = %v, want none", tasks)
+	}
+}
+
+func TestWorker_RunDue(t *testing.T) {
+	dir := t.TempDir()
+	run := writeRun(t, dir, true)
+	archiveDir := filepath.Join(dir, "archive")
+	platform := &fakePlatform{finished: map[string]bool{"jobRun_1": true}}
+	auditPath := filepath.Join(dir, "audit.jsonl")
+	w := &Worker{Queue: NewQueue(filepath.Join(dir, "queue.json")), AuditLog: auditPath, Platform: platform}
+	now := run.Finished
+	w.now = func() time.Time { return now }
+
+	if err := w.Schedule(Plan(&Policy{
+		DeleteLocalTars:             true,
+		ArchiveDir:                  archiveDir,
+		DeleteRemoteInputsAfterDays: 7,
+		DownloadOutputsTo:           filepath.Join(dir, "results"),
+	}, run)); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := w.RunDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Done != 2 || sum.Waiting != 1 {
+		t.Errorf("first pass = %+v, want tars and archive done, download waiting", sum)
+	}
+	for _, p := range []string{"Run_1.tar.gz", "Run_2.tar.gz", "wing.csv", "wing.report.html"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after retention", p)
+		}
+	}
+	if got := archiveEntries(t, filepath.Join(archiveDir, "wing-20260301-120000.tar.gz")); len(got) != 2 || got[0] != "wing.csv" || got[1] != "wing.report.html" {
+		t.Errorf("archive holds %v, want the state file and report", got)
+	}
+
+	// Remote inputs wait for their day; the download for its second job
+	now = now.Add(8 * 24 * time.Hour)
+	platform.finished["jobRun_2"] = true
+	if sum, err = w.RunDue(context.Background()); err != nil || sum.Done != 2 {
+		t.Errorf("second pass = %+v, %v; want download and remote deletion done", sum, err)
+	}
+	sort.Strings(platform.deleted)
+	if len(platform.deleted) != 2 || platform.deleted[0] != "fileRun_1" {
+		t.Errorf("deleted remote files %v, want both inputs", platform.deleted)
+	}
+	if tasks, _ := w.Queue.Tasks(); len(tasks) != 0 {
+		t.Errorf("queue still holds %v", tasks)
+	}
+
+	entries, err := ReadAudit(auditPath, "wing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(map[string]int)
+	for _, e := range entries {
+		results[e.Result]++
+	}
+	if results[ResultScheduled] != 4 || results[ResultDone] != 4 || results[ResultPartial] != 1 {
+		t.Errorf("audit results = %v, want 4 scheduled, 1 partial download, 4 done", results)
+	}
+}
+
+func TestWorker_ArchiveSkipsUnfinishedRun(t *testing.T) {
+	dir := t.TempDir()
+	run := writeRun(t, dir, false)
+	w := &Worker{Queue: NewQueue(filepath.Join(dir, "queue.json"))}
+	if err := w.Schedule(Plan(&Policy{ArchiveDir: filepath.Join(dir, "archive")}, run)); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := w.RunDue(context.Background())
+	if err != nil || sum.Skipped != 1 {
+		t.Fatalf("RunDue() = %+v, %v; want the archive skipped", sum, err)
+	}
+	if _, err := os.Stat(run.StateFile); err != nil {
+		t.Errorf("state file of an unfinished run was removed: %v", err)
+	}
+}
+
+func TestWorker_ViewerModeLeavesTasksQueued(t *testing.T) {
+	t.Setenv(viewer.EnvVar, "")
+	viewer.SetEnabled(true)
+	defer viewer.SetEnabled(false)
+
+	dir := t.TempDir()
+	run := writeRun(t, dir, true)
+	w := &Worker{Queue: NewQueue(filepath.Join(dir, "queue.json"))}
+	if err := w.Schedule(Plan(&Policy{DeleteLocalTars: true, ArchiveDir: filepath.Join(dir, "archive")}, run)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.RunDue(context.Background()); !errors.Is(err, viewer.ErrReadOnly) {
+		t.Fatalf("RunDue() in viewer mode error = %v, want ErrReadOnly", err)
+	}
+	for _, p := range []string{"Run_1.tar.gz", "wing.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			t.Errorf("%s removed in viewer mode: %v", p, err)
+		}
+	}
+	if tasks, _ := w.Queue.Tasks(); len(tasks) != 2 {
+		t.Errorf("queue holds %d task(s), want both left queued", len(tasks))
+	}
+}
+
+func TestWorker_RetriesThenAbandons(t *testing.T) {
+	dir := t.TempDir()
+	platform := &fakePlatform{failIDs: map[string]bool{"f2": true}}
+	w := &Worker{Queue: NewQueue(filepath.Join(dir, "queue.json")), Platform: platform}
+	now := time.Now()
+	w.now = func() time.Time { return now }
+	task := Task{ID: "t1", RunID: "r", Action: ActionDeleteRemoteInputs, DueAt: now, FileIDs: []string{"f1", "f2"}}
+	if err := w.Schedule([]Task{task}); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		sum, err := w.RunDue(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks, _ := w.Queue.Tasks()
+		if attempt < MaxAttempts {
+			if sum.Retried != 1 || len(tasks) != 1 || len(tasks[0].FileIDs) != 1 {
+				t.Fatalf("attempt %d: %+v, queue %v; want a retry of f2 only", attempt, sum, tasks)
+			}
+			now = tasks[0].DueAt
+		} else if sum.Abandoned != 1 || len(tasks) != 0 {
+			t.Fatalf("attempt %d: %+v, queue %v; want the task abandoned", attempt, sum, tasks)
+		}
+	}
+	if len(platform.deleted) != 1 {
+		t.Errorf("deleted %v, want f1 once", platform.deleted)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := PolicyPath(filepath.Join(dir, "wing.csv"))
+	if filepath.Base(path) != "wing.retention.json" {
+		t.Errorf("PolicyPath() = %s, want wing.retention.json", path)
+	}
+	os.WriteFile(path, []byte(`{"deleteLocalTars": true, "deleteRemoteInputsAfterDays": 30}`), 0644)
+	p, err := LoadPolicy(path)
+	if err != nil || !p.DeleteLocalTars || p.DeleteRemoteInputsAfterDays != 30 {
+		t.Errorf("LoadPolicy() = %+v, %v", p, err)
+	}
+	os.WriteFile(path, []byte(`{"deleteLocalTar": true}`), 0644)
+	if _, err := LoadPolicy(path); err == nil {
+		t.Error("LoadPolicy() with a misspelled field succeeded, want error")
+	}
+}
+
+func archiveEntries(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	return names
+}
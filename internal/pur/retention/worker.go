@@ -0,0 +1,373 @@
+package retention
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+const (
+	// MaxAttempts is how many times a failing task is tried before it is
+	// abandoned.
+	MaxAttempts = 5
+
+	// RetryDelay is the wait before a failed task is tried again; it
+	// doubles with each attempt.
+	RetryDelay = 10 * time.Minute
+
+	// DownloadPollInterval is how often a download task checks for jobs
+	// that have finished since.
+	DownloadPollInterval = 15 * time.Minute
+
+	// DownloadWaitLimit is how long after the run a download task waits for
+	// its jobs before it is abandoned.
+	DownloadWaitLimit = 30 * 24 * time.Hour
+)
+
+// Platform carries out the tasks that need the Rescale platform. The CLI
+// implements it with the API client.
+type Platform interface {
+	// DeleteFile deletes an uploaded file.
+	DeleteFile(ctx context.Context, fileID string) error
+
+	// DownloadOutputs downloads the outputs of the jobs that have finished
+	// into their directories under dest and returns the jobs that have
+	// not, along with an error for any job that failed to download (which
+	// is also returned as pending).
+	DownloadOutputs(ctx context.Context, jobs []Job, dest string) (pending []Job, err error)
+}
+
+// Worker carries out the queued tasks that are due.
+type Worker struct {
+	Queue    *Queue
+	AuditLog string   // Audit log path; empty = no audit
+	Platform Platform // nil leaves platform tasks queued
+
+	now func() time.Time // Test seam
+}
+
+// Summary counts what RunDue did.
+type Summary struct {
+	Done      int
+	Waiting   int // Download tasks whose jobs have not all finished
+	Retried   int
+	Skipped   int
+	Abandoned int
+}
+
+// errSkip marks a task that must not be carried out; it is dropped.
+var errSkip = errors.New("skipped")
+
+// RunDue carries out the tasks that are due, in due order, and updates the
+// queue and audit log as each finishes. In viewer mode it returns an error
+// and leaves every task queued: local tars and state files are not removed,
+// and platform tasks would only be refused and retried.
+func (w *Worker) RunDue(ctx context.Context) (Summary, error) {
+	var sum Summary
+	if err := viewer.Check("run retention actions"); err != nil {
+		return sum, err
+	}
+	tasks, err := w.Queue.Tasks()
+	if err != nil {
+		return sum, err
+	}
+	now := w.clock()
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			return sum, ctx.Err()
+		}
+		if t.DueAt.After(now) {
+			break
+		}
+		if w.Platform == nil && (t.Action == ActionDeleteRemoteInputs || t.Action == ActionDownloadOutputs) {
+			continue
+		}
+		if err := w.runTask(ctx, t, &sum); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
+
+// Schedule queues tasks and records them in the audit log.
+func (w *Worker) Schedule(tasks []Task) error {
+	if err := w.Queue.Add(tasks...); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		w.audit(t, ResultScheduled, Describe(t), nil)
+	}
+	return nil
+}
+
+// Cancel removes the tasks with the given IDs, or every task of a run when
+// runID is not empty, and records them as cancelled.
+func (w *Worker) Cancel(runID string, ids ...string) ([]Task, error) {
+	removed, err := w.Queue.Remove(runID, ids...)
+	for _, t := range removed {
+		w.audit(t, ResultCancelled, Describe(t), nil)
+	}
+	return removed, err
+}
+
+// runTask carries out t and records the outcome. Only a failure to update
+// the queue is returned; a failing task is retried or abandoned instead.
+func (w *Worker) runTask(ctx context.Context, t Task, sum *Summary) error {
+	now := w.clock()
+	var detail string
+	var err error
+	progressed := true
+	switch t.Action {
+	case ActionDeleteLocalTars:
+		t.Paths, detail, err = deleteLocal(t.Paths)
+	case ActionArchive:
+		detail, err = archive(t, now)
+	case ActionDeleteRemoteInputs:
+		t.FileIDs, detail, err = w.deleteRemote(ctx, t.FileIDs)
+	case ActionDownloadOutputs:
+		before := len(t.Jobs)
+		t.Jobs, err = w.Platform.DownloadOutputs(ctx, t.Jobs, t.Dest)
+		progressed = len(t.Jobs) < before
+		detail = fmt.Sprintf("downloaded %d job(s), %d left", before-len(t.Jobs), len(t.Jobs))
+		if err == nil && len(t.Jobs) > 0 {
+			if now.Sub(t.CreatedAt) > DownloadWaitLimit {
+				sum.Abandoned++
+				w.audit(t, ResultAbandoned, fmt.Sprintf("%d job(s) still not finished after %s", len(t.Jobs), DownloadWaitLimit), nil)
+				_, err := w.Queue.Remove("", t.ID)
+				return err
+			}
+			sum.Waiting++
+			if progressed {
+				w.audit(t, ResultPartial, detail, nil)
+			}
+			t.DueAt = now.Add(DownloadPollInterval)
+			return w.Queue.put(t)
+		}
+	default:
+		err = fmt.Errorf("unknown action %q", t.Action)
+		t.Attempts = MaxAttempts
+	}
+
+	switch {
+	case err == nil:
+		sum.Done++
+		w.audit(t, ResultDone, detail, nil)
+		_, err := w.Queue.Remove("", t.ID)
+		return err
+	case errors.Is(err, errSkip):
+		sum.Skipped++
+		w.audit(t, ResultSkipped, detail, nil)
+		_, err := w.Queue.Remove("", t.ID)
+		return err
+	}
+
+	t.Attempts++
+	t.LastError = err.Error()
+	if t.Attempts >= MaxAttempts {
+		sum.Abandoned++
+		w.audit(t, ResultAbandoned, detail, err)
+		_, err := w.Queue.Remove("", t.ID)
+		return err
+	}
+	sum.Retried++
+	w.audit(t, ResultRetry, detail, err)
+	t.DueAt = now.Add(RetryDelay << (t.Attempts - 1))
+	return w.Queue.put(t)
+}
+
+func (w *Worker) audit(t Task, result, detail string, err error) {
+	if w.AuditLog == "" {
+		return
+	}
+	e := AuditEntry{Time: w.clock(), RunID: t.RunID, TaskID: t.ID, Action: t.Action, Result: result, Detail: detail}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	// The queue is authoritative; a lost audit line must not redo a task
+	_ = AppendAudit(w.AuditLog, e)
+}
+
+func (w *Worker) clock() time.Time {
+	if w.now != nil {
+		return w.now()
+	}
+	return time.Now()
+}
+
+// Describe summarizes what is left for a task to do.
+func Describe(t Task) string {
+	switch t.Action {
+	case ActionDeleteLocalTars:
+		return fmt.Sprintf("%d tar(s)", len(t.Paths))
+	case ActionArchive:
+		return fmt.Sprintf("%d file(s) to %s", len(t.Paths), t.Dest)
+	case ActionDeleteRemoteInputs:
+		return fmt.Sprintf("%d uploaded file(s)", len(t.FileIDs))
+	case ActionDownloadOutputs:
+		return fmt.Sprintf("%d job(s) to %s", len(t.Jobs), t.Dest)
+	}
+	return ""
+}
+
+// deleteLocal removes paths (securely with secure_delete on), treating
+// missing files as already removed, and returns the paths it could not
+// remove.
+func deleteLocal(paths []string) ([]string, string, error) {
+	var left []string
+	var firstErr error
+	deleted := 0
+	for _, p := range paths {
+		if err := securedelete.Remove(p); err != nil && !os.IsNotExist(err) {
+			left = append(left, p)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		deleted++
+	}
+	return left, fmt.Sprintf("deleted %d of %d tar(s)", deleted, len(paths)), firstErr
+}
+
+// deleteRemote deletes fileIDs from the platform and returns those it could
+// not delete.
+func (w *Worker) deleteRemote(ctx context.Context, fileIDs []string) ([]string, string, error) {
+	var left []string
+	var firstErr error
+	for _, id := range fileIDs {
+		if err := w.Platform.DeleteFile(ctx, id); err != nil {
+			left = append(left, id)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("file %s: %w", id, err)
+			}
+		}
+	}
+	return left, fmt.Sprintf("deleted %d of %d file(s)", len(fileIDs)-len(left), len(fileIDs)), firstErr
+}
+
+// archive packs the task's files that exist into <dest>/<run>-<time>.tar.gz
+// and removes them. A run another process is resuming, or with jobs that
+// did not finish, is skipped: its state file is still needed.
+func archive(t Task, now time.Time) (string, error) {
+	if state.Locked(t.StateFile) {
+		return "state file is in use by a resumed run", errSkip
+	}
+	mgr := state.NewManager(t.StateFile)
+	if err := mgr.Load(); err != nil {
+		return "", err
+	}
+	unfinished := 0
+	for _, st := range mgr.GetAllStates() {
+		if st.SubmitStatus != "success" && st.SubmitStatus != "skipped" {
+			unfinished++
+		}
+	}
+	if unfinished > 0 {
+		return fmt.Sprintf("%d job(s) not submitted; state kept for pur resume", unfinished), errSkip
+	}
+
+	var files []string
+	for _, p := range t.Paths {
+		if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+	if len(files) == 0 {
+		return "nothing left to archive", errSkip
+	}
+
+	if err := os.MkdirAll(t.Dest, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive folder: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.tar.gz", sanitize(t.RunID), now.Format("20060102-150405"))
+	dest := filepath.Join(t.Dest, name)
+	if err := writeTarGz(dest, files); err != nil {
+		return "", err
+	}
+	for _, p := range files {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("archived to %s but could not remove %s: %w", dest, p, err)
+		}
+	}
+	return fmt.Sprintf("archived %d file(s) to %s", len(files), dest), nil
+}
+
+// writeTarGz writes files, by base name, to a gzipped tar at dest. The
+// archive only appears at dest once complete.
+func writeTarGz(dest string, files []string) (err error) {
+	tmp := dest + ".partial"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, p := range files {
+		if err := addFile(tw, p); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", p, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+func addFile(tw *tar.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.Base(path)
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+// sanitize makes a run ID safe as a file name.
+func sanitize(s string) string {
+	if s == "" {
+		return "run"
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
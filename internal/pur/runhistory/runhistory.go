@@ -0,0 +1,222 @@
+// Package runhistory keeps a local log of started PUR runs keyed by a
+// content fingerprint, so a study submitted twice by accident is caught
+// before any compute is spent.
+//
+// Each platform has its own append-only JSON Lines file (see
+// config.GetRunHistoryPath) with one Entry per new run. Resumed runs are
+// not recorded.
+package runhistory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+)
+
+// Duplicate run policies: what a new run does when its fingerprint matches a
+// recent run.
+const (
+	// PolicyWarn reports the earlier run and runs anyway. This is the default.
+	PolicyWarn = "warn"
+
+	// PolicyBlock refuses to run unless forced.
+	PolicyBlock = "block"
+
+	// PolicyOff skips the check (runs are still recorded).
+	PolicyOff = "off"
+)
+
+// ErrDuplicateRun is returned by Check when PolicyBlock stops a run.
+var ErrDuplicateRun = errors.New("duplicate run")
+
+// NormalizePolicy maps a config value to one of the Policy constants.
+func NormalizePolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case PolicyBlock:
+		return PolicyBlock
+	case PolicyOff:
+		return PolicyOff
+	default:
+		return PolicyWarn
+	}
+}
+
+// Entry records one started run.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Fingerprint string    `json:"fingerprint"`
+	RunID       string    `json:"runId"`
+	StateFile   string    `json:"stateFile,omitempty"`
+	Jobs        int       `json:"jobs"`
+}
+
+// Fingerprint hashes the job specs together with a fingerprint of each job's
+// input directory (relative path, size and modification time of every
+// file). Job order does not matter; editing, adding or touching an input
+// file, or changing any job field, gives a different fingerprint.
+func Fingerprint(jobs []models.JobSpec) (string, error) {
+	parts := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		spec, err := json.Marshal(job)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode job %q: %w", job.JobName, err)
+		}
+		inputs, err := inputFingerprint(job.Directory)
+		if err != nil {
+			return "", fmt.Errorf("failed to fingerprint inputs of job %q: %w", job.JobName, err)
+		}
+		parts = append(parts, string(spec)+"\n"+inputs)
+	}
+	sort.Strings(parts)
+
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d\n%s", len(p), p)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// inputFingerprint hashes the file listing under dir. A missing directory
+// has an empty fingerprint rather than an error, so plans for directories
+// that do not exist yet still get a stable value.
+func inputFingerprint(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Append adds e to the log at path, creating the file and its directory if
+// needed.
+func Append(path string, e Entry) error {
+	return jsonl.Append(path, "run history", e)
+}
+
+// FindRecent returns the newest run in the log at path with the given
+// fingerprint started at or after since, or nil. A missing file has no runs;
+// malformed lines are skipped.
+func FindRecent(path, fingerprint string, since time.Time) (*Entry, error) {
+	var found *Entry
+	err := jsonl.Read(path, "run history", func(e Entry) {
+		if e.Fingerprint != fingerprint || e.Time.Before(since) || (found != nil && e.Time.Before(found.Time)) {
+			return
+		}
+		found = &e
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// StateFiles returns the distinct state files recorded in the log at path,
+// oldest run first. A missing file has none.
+func StateFiles(path string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	err := jsonl.Read(path, "run history", func(e Entry) {
+		if e.StateFile == "" || seen[e.StateFile] {
+			return
+		}
+		seen[e.StateFile] = true
+		files = append(files, e.StateFile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Status summarizes an earlier run from its state file, e.g.
+// "8/10 submitted, 2 failed". Runs without a readable state file report
+// "status unknown".
+func Status(e *Entry) string {
+	if e.StateFile == "" {
+		return "status unknown"
+	}
+	if _, err := os.Stat(e.StateFile); err != nil {
+		return "status unknown (state file missing)"
+	}
+	st := state.NewManager(e.StateFile)
+	if err := st.Load(); err != nil {
+		return "status unknown"
+	}
+
+	var submitted, failed int
+	for _, js := range st.GetAllStates() {
+		switch {
+		case js.SubmitStatus == "success":
+			submitted++
+		case js.SubmitStatus == "failed" || js.TarStatus == "failed" || js.UploadStatus == "failed":
+			failed++
+		}
+	}
+	total := e.Jobs
+	if total == 0 {
+		total = len(st.GetAllStates())
+	}
+	status := fmt.Sprintf("%d/%d submitted", submitted, total)
+	if failed > 0 {
+		status += fmt.Sprintf(", %d failed", failed)
+	}
+	return status
+}
+
+// Check looks for a recent run with the same fingerprint in the log at path.
+// With no match, or with PolicyOff, it returns "", nil. Otherwise the match
+// is described; PolicyBlock returns it wrapped in ErrDuplicateRun unless
+// force is set, and PolicyWarn (or a forced block) returns it as a warning.
+func Check(path, fingerprint, policy string, force bool, now time.Time, window time.Duration) (string, error) {
+	policy = NormalizePolicy(policy)
+	if policy == PolicyOff {
+		return "", nil
+	}
+	prev, err := FindRecent(path, fingerprint, now.Add(-window))
+	if err != nil || prev == nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("the same jobs and inputs were already run %s as %s (%s)",
+		prev.Time.Local().Format("2006-01-02 15:04"), prev.RunID, Status(prev))
+	if prev.StateFile != "" {
+		msg += fmt.Sprintf("; state file %s", prev.StateFile)
+	}
+	if policy == PolicyBlock && !force {
+		return "", fmt.Errorf("%w: %s (duplicate_run_policy=block)", ErrDuplicateRun, msg)
+	}
+	return "duplicate run: " + msg, nil
+}
@@ -0,0 +1,154 @@
+package runhistory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/state"
+)
+
+// writeRunDirs creates two run directories with one input file each.
+func writeRunDirs(t *testing.T) []models.JobSpec {
+	t.Helper()
+	root := t.TempDir()
+	var jobs []models.JobSpec
+	for _, name := range []string{"Run_1", "Run_2"} {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "input.dat"), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, models.JobSpec{Directory: dir, JobName: name, Command: "./run.sh", CoreType: "emerald"})
+	}
+	return jobs
+}
+
+func mustFingerprint(t *testing.T, jobs []models.JobSpec) string {
+	t.Helper()
+	fp, err := Fingerprint(jobs)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	return fp
+}
+
+func TestFingerprint(t *testing.T) {
+	jobs := writeRunDirs(t)
+	base := mustFingerprint(t, jobs)
+
+	if got := mustFingerprint(t, []models.JobSpec{jobs[1], jobs[0]}); got != base {
+		t.Error("fingerprint should not depend on job order")
+	}
+
+	changed := append([]models.JobSpec(nil), jobs...)
+	changed[0].CoreType = "onyx"
+	if mustFingerprint(t, changed) == base {
+		t.Error("fingerprint should change with a job field")
+	}
+
+	if err := os.WriteFile(filepath.Join(jobs[1].Directory, "extra.dat"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if mustFingerprint(t, jobs) == base {
+		t.Error("fingerprint should change when an input file is added")
+	}
+
+	// Directories that don't exist yet still fingerprint
+	missing := []models.JobSpec{{Directory: filepath.Join(t.TempDir(), "nope"), JobName: "x"}}
+	if a, b := mustFingerprint(t, missing), mustFingerprint(t, missing); a != b {
+		t.Error("fingerprint of a missing directory should be stable")
+	}
+}
+
+func TestAppendAndFindRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "platform.runs.jsonl")
+	now := time.Now().UTC()
+
+	for _, e := range []Entry{
+		{Time: now.Add(-30 * 24 * time.Hour), Fingerprint: "aaa", RunID: "old"},
+		{Time: now.Add(-2 * time.Hour), Fingerprint: "aaa", RunID: "earlier"},
+		{Time: now.Add(-time.Hour), Fingerprint: "aaa", RunID: "latest"},
+		{Time: now.Add(-time.Minute), Fingerprint: "bbb", RunID: "other"},
+	} {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Interrupted write leaves a partial line
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	f.WriteString(`{"time":"2026-`)
+	f.Close()
+
+	got, err := FindRecent(path, "aaa", now.Add(-14*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.RunID != "latest" {
+		t.Errorf("FindRecent = %+v, want latest", got)
+	}
+	if got, _ := FindRecent(path, "aaa", now.Add(-time.Minute)); got != nil {
+		t.Errorf("FindRecent outside window = %+v, want nil", got)
+	}
+	if got, err := FindRecent(filepath.Join(t.TempDir(), "none.jsonl"), "aaa", time.Time{}); got != nil || err != nil {
+		t.Errorf("missing file: %v, %v", got, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "platform.runs.jsonl")
+	now := time.Now()
+	stateFile := filepath.Join(t.TempDir(), "study.state")
+	st := state.NewManager(stateFile)
+	st.InitializeState(1, "Run_1", "/d/Run_1").SubmitStatus = "success"
+	st.InitializeState(2, "Run_2", "/d/Run_2").UploadStatus = "failed"
+	st.InitializeState(3, "Run_3", "/d/Run_3")
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(path, Entry{Time: now.Add(-time.Hour), Fingerprint: "fp", RunID: "study", StateFile: stateFile, Jobs: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	warning, err := Check(path, "fp", "", false, now, 14*24*time.Hour)
+	if err != nil {
+		t.Fatalf("warn policy: %v", err)
+	}
+	if !strings.Contains(warning, "study") || !strings.Contains(warning, "1/3 submitted, 1 failed") {
+		t.Errorf("warning = %q, want run ID and status", warning)
+	}
+
+	_, err = Check(path, "fp", "block", false, now, 14*24*time.Hour)
+	if !errors.Is(err, ErrDuplicateRun) {
+		t.Errorf("block policy: err = %v, want ErrDuplicateRun", err)
+	}
+
+	warning, err = Check(path, "fp", "block", true, now, 14*24*time.Hour)
+	if err != nil || warning == "" {
+		t.Errorf("forced block: warning = %q, err = %v; want a warning", warning, err)
+	}
+
+	for _, tc := range []struct {
+		name, fp, policy string
+	}{
+		{"off", "fp", "off"},
+		{"no match", "other", "block"},
+	} {
+		if warning, err := Check(path, tc.fp, tc.policy, false, now, 14*24*time.Hour); warning != "" || err != nil {
+			t.Errorf("%s: warning = %q, err = %v; want neither", tc.name, warning, err)
+		}
+	}
+}
+
+func TestStatusWithoutStateFile(t *testing.T) {
+	e := &Entry{RunID: "gone", StateFile: filepath.Join(t.TempDir(), "gone.state"), Jobs: 2}
+	if got := Status(e); !strings.Contains(got, "state file missing") {
+		t.Errorf("Status = %q, want state file missing", got)
+	}
+}
@@ -0,0 +1,389 @@
+// Package runtimes suggests walltime and core counts for new jobs from how
+// long earlier jobs of the same template actually ran.
+//
+// Each platform has its own append-only JSON Lines file (see
+// config.GetJobHistoryPath) with one Entry per job submitted by a PUR run.
+// Runtimes are not stored: they are read from each job's status history on
+// the platform when a prediction is made, so jobs still running at the time
+// of submission count once they complete.
+package runtimes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/util/jsonl"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+const (
+	// MaxSamples caps the completed jobs a prediction is based on; the most
+	// recently submitted matching jobs are used.
+	MaxSamples = 20
+
+	// Window is how far back submitted jobs are considered.
+	Window = 180 * 24 * time.Hour
+
+	// maxChecked caps the status requests one prediction makes, in case most
+	// recent jobs failed or are still running.
+	maxChecked = 3 * MaxSamples
+
+	// statusWorkers bounds concurrent status requests while collecting samples.
+	statusWorkers = 8
+
+	// walltimeMargin is the headroom added to the 90th percentile runtime
+	// when suggesting a walltime.
+	walltimeMargin = 1.25
+)
+
+// Confidence levels, by the number of completed jobs a prediction is based on.
+const (
+	ConfidenceLow    = "low"    // Fewer than 3 jobs
+	ConfidenceMedium = "medium" // 3-9 jobs
+	ConfidenceHigh   = "high"   // 10 or more jobs
+)
+
+// Entry records one submitted job and the template it was submitted from.
+type Entry struct {
+	Time          time.Time `json:"time"`
+	JobID         string    `json:"jobId"`
+	JobName       string    `json:"jobName,omitempty"`
+	Template      string    `json:"template"`
+	CoreType      string    `json:"coreType,omitempty"`
+	Cores         int       `json:"cores"` // Cores per slot times slots
+	WalltimeHours float64   `json:"walltimeHours,omitempty"`
+}
+
+// NewEntry describes job, submitted as jobID, for the job history.
+func NewEntry(job models.JobSpec, jobID string) Entry {
+	return Entry{
+		Time:          time.Now().UTC(),
+		JobID:         jobID,
+		JobName:       job.JobName,
+		Template:      TemplateKey(job),
+		CoreType:      job.CoreType,
+		Cores:         totalCores(job),
+		WalltimeHours: job.WalltimeHours,
+	}
+}
+
+// TemplateKey identifies the template a job was created from: its software
+// plus its tags and metadata keys. Metadata values and job names vary across
+// a sweep and are left out, as are hardware and walltime, which are what a
+// prediction suggests.
+func TemplateKey(job models.JobSpec) string {
+	tags := make([]string, 0, len(job.Tags))
+	for _, t := range job.Tags {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	sort.Strings(tags)
+	keys := make([]string, 0, len(job.Metadata))
+	for k := range job.Metadata {
+		keys = append(keys, strings.ToLower(k))
+	}
+	sort.Strings(keys)
+	return strings.Join([]string{
+		strings.ToLower(job.AnalysisCode),
+		strings.Join(tags, ","),
+		strings.Join(keys, ","),
+	}, "|")
+}
+
+func totalCores(job models.JobSpec) int {
+	slots := job.Slots
+	if slots < 1 {
+		slots = 1
+	}
+	return job.CoresPerSlot * slots
+}
+
+// Append adds e to the log at path, creating the file and its directory if
+// needed.
+func Append(path string, e Entry) error {
+	return jsonl.Append(path, "job history", e)
+}
+
+// Load returns the entries in the log at path for the given template
+// submitted at or after since, newest first. A job resubmitted by a resumed
+// run keeps its latest entry. A missing file has none; malformed lines are
+// skipped.
+func Load(path, template string, since time.Time) ([]Entry, error) {
+	byJob := make(map[string]Entry)
+	err := jsonl.Read(path, "job history", func(e Entry) {
+		if e.JobID != "" && e.Template == template && !e.Time.Before(since) {
+			byJob[e.JobID] = e
+		}
+	})
+	if err != nil || len(byJob) == 0 {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(byJob))
+	for _, e := range byJob {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	return entries, nil
+}
+
+// Runtime returns how long a job executed: from its first Executing status to
+// Completed. Jobs that have not completed, or failed or were stopped, have
+// no runtime.
+func Runtime(history []models.JobStatusEntry) (time.Duration, bool) {
+	var started, completed time.Time
+	for _, h := range history {
+		t, err := watch.ParseStatusDate(h.StatusDate)
+		if err != nil {
+			continue
+		}
+		switch h.Status {
+		case "Executing":
+			if started.IsZero() || t.Before(started) {
+				started = t
+			}
+		case "Completed":
+			completed = t
+		}
+	}
+	if started.IsZero() || completed.IsZero() || !completed.After(started) {
+		return 0, false
+	}
+	if latest, _, ok := watch.LatestStatus(history); ok && latest.Status != "Completed" {
+		return 0, false
+	}
+	return completed.Sub(started), true
+}
+
+// Sample is a completed job and how long it ran.
+type Sample struct {
+	Entry
+	Runtime time.Duration
+}
+
+// StatusClient reads job status histories; *api.Client satisfies it.
+type StatusClient interface {
+	GetJobStatuses(ctx context.Context, jobID string) ([]models.JobStatusEntry, error)
+}
+
+// Collect reads the status history of each entry, newest first, and returns
+// up to MaxSamples completed jobs. At most maxChecked jobs are read; jobs
+// whose status cannot be read are skipped.
+func Collect(ctx context.Context, client StatusClient, entries []Entry) []Sample {
+	var samples []Sample
+	// Check a batch at a time: most recent jobs have usually completed, so
+	// one batch is typically enough.
+	for start := 0; start < len(entries) && start < maxChecked && len(samples) < MaxSamples; start += MaxSamples {
+		end := start + MaxSamples
+		if end > len(entries) {
+			end = len(entries)
+		}
+		batch := make([]*Sample, end-start)
+		sem := make(chan struct{}, statusWorkers)
+		var wg sync.WaitGroup
+		for i, e := range entries[start:end] {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, e Entry) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				history, err := client.GetJobStatuses(ctx, e.JobID)
+				if err != nil {
+					return
+				}
+				if d, ok := Runtime(history); ok {
+					batch[i] = &Sample{Entry: e, Runtime: d}
+				}
+			}(i, e)
+		}
+		wg.Wait()
+		for _, s := range batch {
+			if s != nil && len(samples) < MaxSamples {
+				samples = append(samples, *s)
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return samples
+}
+
+// Range is a low/typical/high spread: the 10th, 50th and 90th percentiles.
+type Range struct {
+	Low     float64 `json:"low"`
+	Typical float64 `json:"typical"`
+	High    float64 `json:"high"`
+}
+
+// Prediction summarizes completed jobs of a template.
+type Prediction struct {
+	Samples    int    `json:"samples"`
+	Confidence string `json:"confidence"`
+
+	// Runtime in hours, and the walltime suggested to cover it.
+	RuntimeHours           Range   `json:"runtimeHours"`
+	SuggestedWalltimeHours float64 `json:"suggestedWalltimeHours"`
+
+	// Total cores the jobs ran on, the suggested count (the typical one) and
+	// the core type most of them used.
+	Cores          Range  `json:"cores"`
+	SuggestedCores int    `json:"suggestedCores"`
+	CoreType       string `json:"coreType,omitempty"`
+}
+
+// Predict summarizes samples, or returns nil if there are none.
+func Predict(samples []Sample) *Prediction {
+	if len(samples) == 0 {
+		return nil
+	}
+	hours := make([]float64, len(samples))
+	cores := make([]float64, 0, len(samples))
+	coreTypes := make(map[string]int)
+	for i, s := range samples {
+		hours[i] = s.Runtime.Hours()
+		if s.Cores > 0 {
+			cores = append(cores, float64(s.Cores))
+		}
+		if s.CoreType != "" {
+			coreTypes[s.CoreType]++
+		}
+	}
+
+	p := &Prediction{
+		Samples:      len(samples),
+		Confidence:   confidence(len(samples)),
+		RuntimeHours: spread(hours),
+	}
+	// Whole hours, as the platform and the template editor take them
+	p.SuggestedWalltimeHours = math.Max(1, math.Ceil(p.RuntimeHours.High*walltimeMargin))
+	if len(cores) > 0 {
+		p.Cores = spread(cores)
+		p.SuggestedCores = int(p.Cores.Typical)
+	}
+	for ct, n := range coreTypes {
+		if p.CoreType == "" || n > coreTypes[p.CoreType] || (n == coreTypes[p.CoreType] && ct < p.CoreType) {
+			p.CoreType = ct
+		}
+	}
+	return p
+}
+
+func confidence(n int) string {
+	switch {
+	case n >= 10:
+		return ConfidenceHigh
+	case n >= 3:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+func spread(values []float64) Range {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return Range{
+		Low:     percentile(sorted, 10),
+		Typical: percentile(sorted, 50),
+		High:    percentile(sorted, 90),
+	}
+}
+
+// percentile returns the p-th percentile (nearest rank) of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Predictor predicts for the templates of a batch of jobs, reading the
+// history and job statuses once per template. It is safe for concurrent use.
+type Predictor struct {
+	path   string
+	client StatusClient
+
+	mu    sync.Mutex
+	cache map[string]*Prediction
+}
+
+// NewPredictor predicts from the job history at path, reading job statuses
+// with client.
+func NewPredictor(path string, client StatusClient) *Predictor {
+	return &Predictor{path: path, client: client, cache: make(map[string]*Prediction)}
+}
+
+// Predict returns the prediction for job's template, or nil when no earlier
+// job of the template has completed.
+func (p *Predictor) Predict(ctx context.Context, job models.JobSpec) (*Prediction, error) {
+	key := TemplateKey(job)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pred, ok := p.cache[key]; ok {
+		return pred, nil
+	}
+	entries, err := Load(p.path, key, time.Now().Add(-Window))
+	if err != nil {
+		return nil, err
+	}
+	pred := Predict(Collect(ctx, p.client, entries))
+	p.cache[key] = pred
+	return pred, nil
+}
+
+// Warnings compares a job's requested walltime and cores with a prediction
+// for its template. A walltime below the 90th percentile runtime risks the
+// job being stopped before it finishes.
+func Warnings(job models.JobSpec, p *Prediction) []string {
+	if p == nil {
+		return nil
+	}
+	var warnings []string
+	if job.WalltimeHours > 0 && job.WalltimeHours < p.RuntimeHours.High {
+		warnings = append(warnings, fmt.Sprintf(
+			"walltime %gh is below the 90th percentile runtime of %d earlier job(s) from this template (%s, %s confidence); suggest %gh",
+			job.WalltimeHours, p.Samples, p.RuntimeSummary(), p.Confidence, p.SuggestedWalltimeHours))
+	}
+	if cores := totalCores(job); cores > 0 && p.SuggestedCores > 0 && p.Samples >= 3 &&
+		(float64(cores) < p.Cores.Low || float64(cores) > p.Cores.High) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d cores is outside the range earlier jobs from this template ran on (%s); runtime predictions may not apply",
+			cores, p.CoresSummary()))
+	}
+	return warnings
+}
+
+// RuntimeSummary describes the runtime spread, e.g. "typically 2.5h, 1.8-3.4h".
+func (p *Prediction) RuntimeSummary() string {
+	r := p.RuntimeHours
+	return fmt.Sprintf("typically %sh, %s-%sh", formatHours(r.Typical), formatHours(r.Low), formatHours(r.High))
+}
+
+// CoresSummary describes the core count spread, e.g. "typically 32, 16-64".
+func (p *Prediction) CoresSummary() string {
+	c := p.Cores
+	return fmt.Sprintf("typically %d, %d-%d", int(c.Typical), int(c.Low), int(c.High))
+}
+
+// String is a one-line suggestion for the plan output.
+func (p *Prediction) String() string {
+	s := fmt.Sprintf("runtime %s from %d completed job(s), %s confidence; suggested walltime %gh",
+		p.RuntimeSummary(), p.Samples, p.Confidence, p.SuggestedWalltimeHours)
+	if p.SuggestedCores > 0 {
+		s += fmt.Sprintf(", cores %s", p.CoresSummary())
+	}
+	return s
+}
+
+func formatHours(h float64) string {
+	return fmt.Sprintf("%.1f", h)
+}
@@ -0,0 +1,213 @@
+package runtimes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestTemplateKey(t *testing.T) {
+	a := models.JobSpec{
+		JobName:      "Run_1",
+		AnalysisCode: "openfoam",
+		Tags:         []string{"study-a", " CFD "},
+		Metadata:     map[string]string{"mesh": "fine", "re": "1000"},
+		CoresPerSlot: 32,
+	}
+	b := a
+	b.JobName = "Run_2"
+	b.Tags = []string{"cfd", "study-a"}
+	b.Metadata = map[string]string{"re": "5000", "mesh": "coarse"}
+	b.CoresPerSlot = 64
+	if TemplateKey(a) != TemplateKey(b) {
+		t.Errorf("jobs of one sweep got different keys: %q vs %q", TemplateKey(a), TemplateKey(b))
+	}
+
+	c := a
+	c.Tags = []string{"study-b"}
+	if TemplateKey(a) == TemplateKey(c) {
+		t.Error("different tags got the same key")
+	}
+	d := a
+	d.Metadata = map[string]string{"mesh": "fine"}
+	if TemplateKey(a) == TemplateKey(d) {
+		t.Error("different metadata keys got the same key")
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "platform.jobs.jsonl")
+	job := models.JobSpec{AnalysisCode: "openfoam", CoresPerSlot: 16, Slots: 2}
+	other := models.JobSpec{AnalysisCode: "lsdyna"}
+	now := time.Now().UTC()
+
+	for _, e := range []Entry{
+		{Time: now.Add(-200 * 24 * time.Hour), JobID: "old", Template: TemplateKey(job)},
+		{Time: now.Add(-2 * time.Hour), JobID: "j1", Template: TemplateKey(job)},
+		{Time: now.Add(-1 * time.Hour), JobID: "j2", Template: TemplateKey(job)},
+		{Time: now.Add(-1 * time.Hour), JobID: "x1", Template: TemplateKey(other)},
+		{Time: now, JobID: "j1", Template: TemplateKey(job)}, // Resubmitted by a resume
+	} {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Load(path, TemplateKey(job), now.Add(-Window))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.JobID)
+	}
+	if got := strings.Join(ids, ","); got != "j1,j2" {
+		t.Errorf("Load = %s, want j1,j2 (newest first, old and other templates dropped)", got)
+	}
+
+	if e := NewEntry(job, "j3"); e.Cores != 32 || e.Template != TemplateKey(job) {
+		t.Errorf("NewEntry = %+v", e)
+	}
+
+	if entries, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"), "", time.Time{}); err != nil || entries != nil {
+		t.Errorf("Load(missing) = %v, %v", entries, err)
+	}
+}
+
+func statuses(pairs ...string) []models.JobStatusEntry {
+	var h []models.JobStatusEntry
+	for i := 0; i < len(pairs); i += 2 {
+		h = append(h, models.JobStatusEntry{Status: pairs[i], StatusDate: pairs[i+1]})
+	}
+	return h
+}
+
+func TestRuntime(t *testing.T) {
+	completed := statuses(
+		"Completed", "2026-01-01T13:30:00Z",
+		"Executing", "2026-01-01T11:00:00Z",
+		"Queued", "2026-01-01T10:00:00Z",
+	)
+	if d, ok := Runtime(completed); !ok || d != 150*time.Minute {
+		t.Errorf("Runtime(completed) = %v, %t", d, ok)
+	}
+
+	running := statuses("Executing", "2026-01-01T11:00:00Z")
+	if _, ok := Runtime(running); ok {
+		t.Error("Runtime counted a running job")
+	}
+	failed := statuses(
+		"Executing", "2026-01-01T11:00:00Z",
+		"Completed", "2026-01-01T12:00:00Z",
+		"Failed", "2026-01-01T12:00:01Z",
+	)
+	if _, ok := Runtime(failed); ok {
+		t.Error("Runtime counted a failed job")
+	}
+}
+
+type fakeStatuses map[string][]models.JobStatusEntry
+
+func (f fakeStatuses) GetJobStatuses(ctx context.Context, jobID string) ([]models.JobStatusEntry, error) {
+	h, ok := f[jobID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return h, nil
+}
+
+func TestCollectAndPredict(t *testing.T) {
+	client := fakeStatuses{}
+	var entries []Entry
+	for i := 1; i <= 10; i++ {
+		id := fmt.Sprintf("job%d", i)
+		entries = append(entries, Entry{JobID: id, CoreType: "emerald", Cores: 32})
+		end := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+		client[id] = statuses(
+			"Executing", "2026-01-01T10:00:00Z",
+			"Completed", end.Format(time.RFC3339),
+		)
+	}
+	entries[0].Cores = 16
+	entries[9].CoreType = "onyx"
+	entries = append(entries, Entry{JobID: "unreadable"})
+
+	samples := Collect(context.Background(), client, entries)
+	if len(samples) != 10 {
+		t.Fatalf("Collect = %d samples, want 10", len(samples))
+	}
+
+	p := Predict(samples)
+	if p.Confidence != ConfidenceHigh {
+		t.Errorf("Confidence = %s", p.Confidence)
+	}
+	if p.RuntimeHours != (Range{Low: 1, Typical: 5, High: 9}) {
+		t.Errorf("RuntimeHours = %+v", p.RuntimeHours)
+	}
+	if p.SuggestedWalltimeHours != 12 { // ceil(9h * 1.25)
+		t.Errorf("SuggestedWalltimeHours = %g", p.SuggestedWalltimeHours)
+	}
+	if p.SuggestedCores != 32 || p.Cores.Low != 16 || p.CoreType != "emerald" {
+		t.Errorf("cores = %d %+v %s", p.SuggestedCores, p.Cores, p.CoreType)
+	}
+
+	if Predict(nil) != nil {
+		t.Error("Predict(nil) != nil")
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	p := &Prediction{
+		Samples:                5,
+		RuntimeHours:           Range{Low: 2, Typical: 3, High: 4},
+		SuggestedWalltimeHours: 5,
+		Cores:                  Range{Low: 16, Typical: 32, High: 32},
+		SuggestedCores:         32,
+	}
+
+	w := Warnings(models.JobSpec{WalltimeHours: 3, CoresPerSlot: 32}, p)
+	if len(w) != 1 || !strings.Contains(w[0], "suggest 5h") {
+		t.Errorf("short walltime: %v", w)
+	}
+	w = Warnings(models.JobSpec{WalltimeHours: 8, CoresPerSlot: 64}, p)
+	if len(w) != 1 || !strings.Contains(w[0], "64 cores") {
+		t.Errorf("unusual cores: %v", w)
+	}
+	if w := Warnings(models.JobSpec{WalltimeHours: 8, CoresPerSlot: 32}, p); len(w) != 0 {
+		t.Errorf("matching job: %v", w)
+	}
+	if w := Warnings(models.JobSpec{WalltimeHours: 1}, nil); w != nil {
+		t.Errorf("no prediction: %v", w)
+	}
+}
+
+func TestPredictorCachesPerTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	job := models.JobSpec{AnalysisCode: "openfoam", CoresPerSlot: 8}
+	if err := Append(path, NewEntry(job, "j1")); err != nil {
+		t.Fatal(err)
+	}
+	client := fakeStatuses{"j1": statuses(
+		"Executing", "2026-01-01T10:00:00Z",
+		"Completed", "2026-01-01T12:00:00Z",
+	)}
+
+	pr := NewPredictor(path, client)
+	p, err := pr.Predict(context.Background(), job)
+	if err != nil || p == nil || p.Samples != 1 || p.Confidence != ConfidenceLow {
+		t.Fatalf("Predict = %+v, %v", p, err)
+	}
+	delete(client, "j1")
+	if again, _ := pr.Predict(context.Background(), job); again != p {
+		t.Error("second prediction for the template was not cached")
+	}
+	if none, err := pr.Predict(context.Background(), models.JobSpec{AnalysisCode: "abaqus"}); none != nil || err != nil {
+		t.Errorf("Predict(no history) = %+v, %v", none, err)
+	}
+}
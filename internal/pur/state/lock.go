@@ -0,0 +1,153 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"syscall"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// LockOwner identifies the process holding a state file lock.
+type LockOwner struct {
+	Host     string    `json:"host"`
+	User     string    `json:"user"`
+	PID      int       `json:"pid"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// LockedError is returned when another live process holds the state lock.
+type LockedError struct {
+	Path  string // State file
+	Owner LockOwner
+}
+
+func (e *LockedError) Error() string {
+	if e.Owner.Host == "" {
+		return fmt.Sprintf("state file %s is locked by another process", e.Path)
+	}
+	return fmt.Sprintf("state file %s is in use by PID %d (%s@%s, since %s); wait for that run to finish or stop it",
+		e.Path, e.Owner.PID, e.Owner.User, e.Owner.Host, e.Owner.Acquired.Format(time.RFC3339))
+}
+
+// Locked reports whether a live run holds the lock on the state file at
+// stateFile, i.e. its lock file exists and was refreshed recently.
+func Locked(stateFile string) bool {
+	info, err := os.Stat(stateFile + ".lock")
+	return err == nil && time.Since(info.ModTime()) < constants.StateLockStaleAfter
+}
+
+// fileLock is an advisory lock implemented as a "<state>.lock" file created
+// with O_EXCL. Unlike flock/LockFileEx it works on SMB and NFS shares. The
+// holder refreshes the file's mtime periodically; a lock that has not been
+// refreshed for staleAfter belongs to a crashed process and is taken over, as
+// is at once a lock whose owner on this host is no longer running.
+type fileLock struct {
+	path string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// acquireFileLock creates the lock file at path or returns *LockedError.
+func acquireFileLock(path string, heartbeat, staleAfter time.Duration) (*fileLock, error) {
+	owner := currentLockOwner()
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, writeErr := f.Write(data)
+			closeErr := f.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("failed to write state lock: %w", errors.Join(writeErr, closeErr))
+			}
+			l := &fileLock{path: path, stop: make(chan struct{}), done: make(chan struct{})}
+			go l.refresh(heartbeat)
+			return l, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			continue // Released between our create and stat; try again
+		}
+		if owner := readLockOwner(path); time.Since(info.ModTime()) < staleAfter && !ownerGone(owner) {
+			return nil, &LockedError{Path: path, Owner: owner}
+		}
+		// Stale lock from a crashed run: take it over
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &LockedError{Path: path, Owner: readLockOwner(path)}
+}
+
+// refresh touches the lock file until release is called.
+func (l *fileLock) refresh(interval time.Duration) {
+	defer close(l.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			_ = os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// release stops the heartbeat and removes the lock file.
+func (l *fileLock) release() {
+	close(l.stop)
+	<-l.done
+	os.Remove(l.path)
+}
+
+func currentLockOwner() LockOwner {
+	owner := LockOwner{PID: os.Getpid(), Acquired: time.Now()}
+	owner.Host, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		owner.User = u.Username
+	}
+	return owner
+}
+
+// ownerGone reports whether owner is a process on this host that has exited.
+// Owners on other hosts cannot be checked and go stale instead.
+func ownerGone(owner LockOwner) bool {
+	if owner.PID <= 0 || owner.Host == "" {
+		return false
+	}
+	host, err := os.Hostname()
+	if err != nil || host != owner.Host {
+		return false
+	}
+	return !processAlive(owner.PID)
+}
+
+func readLockOwner(path string) LockOwner {
+	var owner LockOwner
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &owner)
+	}
+	return owner
+}
+
+// isReadOnlyErr reports whether err means the state location cannot be written,
+// e.g. a share mounted read-only or a directory the user has no write access to.
+func isReadOnlyErr(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
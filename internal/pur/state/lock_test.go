@@ -0,0 +1,536 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestAcquireLockExcludesSecondManager(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "shared", "run.state")
+
+	first := NewManager(stateFile)
+	if err := first.AcquireLock(); err != nil {
+		t.Fatalf("first AcquireLock() error = %v", err)
+	}
+
+	second := NewManager(stateFile)
+	err := second.AcquireLock()
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("second AcquireLock() error = %v, want *LockedError", err)
+	}
+	if locked.Owner.PID != os.Getpid() {
+		t.Errorf("lock owner PID = %d, want %d", locked.Owner.PID, os.Getpid())
+	}
+
+	first.ReleaseLock()
+	if err := second.AcquireLock(); err != nil {
+		t.Fatalf("AcquireLock() after release error = %v", err)
+	}
+	second.ReleaseLock()
+
+	if _, err := os.Stat(stateFile + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file still present after release: %v", err)
+	}
+}
+
+func TestAcquireLockTakesOverStaleLock(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	lockFile := stateFile + ".lock"
+	if err := os.WriteFile(lockFile, []byte(`{"host":"crashed","pid":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(stateFile)
+	if err := m.AcquireLock(); err != nil {
+		t.Fatalf("AcquireLock() over stale lock error = %v", err)
+	}
+	defer m.ReleaseLock()
+
+	if owner := readLockOwner(lockFile); owner.PID != os.Getpid() {
+		t.Errorf("lock owner PID = %d, want %d", owner.PID, os.Getpid())
+	}
+}
+
+func TestReadOnlyStateKeptInMemory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0755)
+
+	m := NewManager(filepath.Join(dir, "run.state"))
+	if err := m.AcquireLock(); err != nil {
+		t.Fatalf("AcquireLock() on read-only dir error = %v", err)
+	}
+	if m.ReadOnly() == nil {
+		t.Fatal("ReadOnly() = nil, want write error")
+	}
+
+	if err := m.UpdateState(&models.JobState{Index: 1, JobName: "job1"}); err != nil {
+		t.Fatalf("UpdateState() error = %v, want in-memory update", err)
+	}
+	if got := m.GetState(1); got == nil || got.JobName != "job1" {
+		t.Errorf("GetState(1) = %+v", got)
+	}
+}
+
+func TestSaveLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "run.state"))
+	m.InitializeState(1, "job1", "/data/job1")
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "run.state" {
+		t.Errorf("state dir entries = %v, want only run.state", entries)
+	}
+
+	reloaded := NewManager(filepath.Join(dir, "run.state"))
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetState(1); got == nil || got.Directory != "/data/job1" {
+		t.Errorf("reloaded state = %+v", got)
+	}
+}
+
+func TestSkippedFilesRoundTrip(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	st := m.InitializeState(1, "job1", "/data/job1")
+	st.SkippedFiles = "job1/solver.lock|job1/out.log"
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetState(1); got == nil || got.SkippedFiles != st.SkippedFiles {
+		t.Errorf("reloaded state = %+v", got)
+	}
+
+	// State files written before the SkippedFiles column still load
+	old := "Index,JobName,Directory,TarPath,TarStatus,FileID,UploadStatus,JobID,SubmitStatus,ExtraFileIDs,ErrorMessage,LastUpdated\n" +
+		"2,job2,/data/job2,,pending,,pending,,pending,,,\n"
+	if err := os.WriteFile(stateFile, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := NewManager(stateFile)
+	if err := legacy.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := legacy.GetState(2); got == nil || got.JobName != "job2" || got.SkippedFiles != "" {
+		t.Errorf("legacy state = %+v", got)
+	}
+}
+
+func TestWarningsRoundTrip(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	st := m.InitializeState(1, "job1", "/data/job1")
+	st.Warnings = strings.Join([]string{"Version 2021 is deprecated", "license_conflict: server busy"}, models.WarningSeparator)
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.GetState(1)
+	if got == nil || len(got.WarningList()) != 2 || got.WarningList()[1] != "license_conflict: server busy" {
+		t.Errorf("reloaded state = %+v", got)
+	}
+}
+
+func TestResetFailed(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	done := m.InitializeState(1, "done", "/data/done")
+	done.TarStatus, done.UploadStatus, done.JobID, done.SubmitStatus = "success", "success", "job-1", "success"
+	tarFailed := m.InitializeState(2, "tar", "/data/tar")
+	tarFailed.TarStatus, tarFailed.ErrorMessage = "failed", "permission denied"
+	uploadFailed := m.InitializeState(3, "upload", "/data/upload")
+	uploadFailed.TarStatus, uploadFailed.TarPath, uploadFailed.UploadStatus = "success", "/data/upload.tar.gz", "failed"
+	submitFailed := m.InitializeState(4, "submit", "/data/submit")
+	submitFailed.TarStatus, submitFailed.UploadStatus, submitFailed.FileID = "success", "success", "file-4"
+	submitFailed.JobID, submitFailed.SubmitStatus, submitFailed.ErrorMessage = "job-4", "failed", "quota exceeded"
+	m.InitializeState(5, "pending", "/data/pending")
+
+	reset, err := m.ResetFailed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reset) != 3 || reset[0] != 2 || reset[1] != 3 || reset[2] != 4 {
+		t.Fatalf("ResetFailed() = %v, want [2 3 4]", reset)
+	}
+
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []models.JobState{
+		{Index: 1, TarStatus: "success", UploadStatus: "success", JobID: "job-1", SubmitStatus: "success"},
+		{Index: 2, TarStatus: "pending", UploadStatus: "pending", SubmitStatus: "pending"},
+		{Index: 3, TarStatus: "success", TarPath: "/data/upload.tar.gz", UploadStatus: "pending", SubmitStatus: "pending"},
+		{Index: 4, TarStatus: "success", UploadStatus: "success", FileID: "file-4", JobID: "job-4", SubmitStatus: "pending"},
+	} {
+		got := reloaded.GetState(want.Index)
+		if got == nil || got.TarStatus != want.TarStatus || got.TarPath != want.TarPath || got.UploadStatus != want.UploadStatus ||
+			got.FileID != want.FileID || got.JobID != want.JobID || got.SubmitStatus != want.SubmitStatus || got.ErrorMessage != "" {
+			t.Errorf("job %d = %+v", want.Index, got)
+		}
+	}
+
+	// Nothing left to reset
+	if reset, err := reloaded.ResetFailed(); err != nil || reset != nil {
+		t.Errorf("second ResetFailed() = %v, %v", reset, err)
+	}
+}
+
+func TestUpdateStateBatchesRoutineChanges(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	m.saveInterval = time.Hour
+
+	st := m.InitializeState(1, "job1", "/data/job1")
+	st.TarStatus = "success"
+	if err := m.UpdateState(st); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Fatalf("routine change written at once (stat error %v)", err)
+	}
+
+	// A created job is written before UpdateState returns
+	st.JobID = "job-1"
+	if err := m.UpdateState(st); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetState(1); got == nil || got.JobID != "job-1" || got.TarStatus != "success" {
+		t.Fatalf("after critical change = %+v", got)
+	}
+
+	// Batched changes are written by ReleaseLock
+	st.OutputStatus = "pending"
+	m.UpdateState(st)
+	m.ReleaseLock()
+	reloaded = NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetState(1); got == nil || got.OutputStatus != "pending" {
+		t.Errorf("after ReleaseLock = %+v", got)
+	}
+}
+
+func TestUpdateStateTimerWritesBatch(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	m.saveInterval = 10 * time.Millisecond
+
+	for i := 1; i <= 3; i++ {
+		m.UpdateState(m.InitializeState(i, "job", "/data"))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		reloaded := NewManager(stateFile)
+		if err := reloaded.Load(); err == nil && len(reloaded.GetAllStates()) == 3 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batched changes were not written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoadRecoversTornWrite(t *testing.T) {
+	// Only CSV state files can be torn; SQLite rolls back an unfinished write
+	stateFile := filepath.Join(t.TempDir(), "run.csv")
+	m := NewManager(stateFile)
+	for i := 1; i <= 3; i++ {
+		m.InitializeState(i, "job", "/data")
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, torn := range map[string]string{
+		"short row":   string(data[:len(data)-20]),
+		"open quote":  string(data) + "4,\"job",
+		"clean break": string(data),
+	} {
+		if err := os.WriteFile(stateFile, []byte(torn), 0644); err != nil {
+			t.Fatal(err)
+		}
+		loaded := NewManager(stateFile)
+		if err := loaded.Load(); err != nil {
+			t.Fatalf("%s: Load() error = %v", name, err)
+		}
+		wantRows, wantRecovered := 2, 1
+		switch name {
+		case "open quote":
+			wantRows = 3
+		case "clean break":
+			wantRows, wantRecovered = 3, 0
+		}
+		if got := len(loaded.GetAllStates()); got != wantRows || loaded.Recovered() != wantRecovered {
+			t.Errorf("%s: %d rows, %d recovered; want %d, %d", name, got, loaded.Recovered(), wantRows, wantRecovered)
+		}
+	}
+}
+
+func TestLoadMigratesCSVState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	legacy := "Index,JobName,Directory,TarPath,TarStatus,FileID,UploadStatus,JobID,SubmitStatus,ExtraFileIDs,ErrorMessage,LastUpdated\n" +
+		"1,job1,/data/job1,/tmp/job1.tar.gz,success,file-1,success,,pending,,,2026-01-01T00:00:00Z\n" +
+		"2,job2,/data/job2,,pending,,pending,,pending,,,2026-01-01T00:00:00Z\n"
+	if err := os.WriteFile(stateFile, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(stateFile)
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if isSQLiteFile(stateFile) {
+		t.Fatal("Load() migrated the state file; only a write should")
+	}
+	st := m.GetState(1)
+	st.JobID = "job-1"
+	if err := m.UpdateState(st); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isSQLiteFile(stateFile) {
+		t.Fatal("state file not migrated to SQLite")
+	}
+	if backup, err := os.ReadFile(stateFile + BackupSuffix); err != nil || string(backup) != legacy {
+		t.Errorf("CSV backup = %q, %v", backup, err)
+	}
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	all := reloaded.GetAllStates()
+	if len(all) != 2 || all[0].JobID != "job-1" || all[0].FileID != "file-1" || all[1].JobName != "job2" {
+		t.Errorf("migrated states = %+v", all)
+	}
+}
+
+func TestCSVPathKeepsCSV(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.csv")
+	m := NewManager(stateFile)
+	m.InitializeState(1, "job1", "/data/job1")
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(stateFile)
+	if err != nil || !strings.HasPrefix(string(data), "Index,JobName,") {
+		t.Errorf("state file = %q, %v; want CSV", data, err)
+	}
+}
+
+func TestSaveWithoutLoadReplacesRows(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	first := NewManager(stateFile)
+	for i := 1; i <= 3; i++ {
+		first.InitializeState(i, "old", "/data/old")
+	}
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new run on the same path starts from scratch, as with CSV
+	second := NewManager(stateFile)
+	second.InitializeState(1, "new", "/data/new")
+	if err := second.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewManager(stateFile)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if all := reloaded.GetAllStates(); len(all) != 1 || all[0].JobName != "new" {
+		t.Errorf("states = %+v, want only the new run's job", all)
+	}
+}
+
+func TestReaderQueries(t *testing.T) {
+	for _, name := range []string{"run.state", "run.csv"} {
+		stateFile := filepath.Join(t.TempDir(), name)
+		m := NewManager(stateFile)
+		for i, jobName := range []string{"wing", "fuselage", "wing"} {
+			st := m.InitializeState(i+1, jobName, "/data")
+			st.JobID = fmt.Sprintf("job-%d", i+1)
+		}
+		if err := m.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := OpenReader(stateFile)
+		if err != nil {
+			t.Fatalf("%s: OpenReader() error = %v", name, err)
+		}
+		if n, err := r.Count(); err != nil || n != 3 {
+			t.Errorf("%s: Count() = %d, %v", name, n, err)
+		}
+		if st, err := r.ByJobID("job-2"); err != nil || st == nil || st.JobName != "fuselage" {
+			t.Errorf("%s: ByJobID() = %+v, %v", name, st, err)
+		}
+		if st, err := r.ByJobID("job-9"); err != nil || st != nil {
+			t.Errorf("%s: ByJobID(missing) = %+v, %v", name, st, err)
+		}
+		if states, err := r.ByJobName("wing"); err != nil || len(states) != 2 || states[1].Index != 3 {
+			t.Errorf("%s: ByJobName() = %+v, %v", name, states, err)
+		}
+		r.Close()
+	}
+}
+
+func TestReaderSeesConcurrentWrites(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	m := NewManager(stateFile)
+	st := m.InitializeState(1, "job1", "/data/job1")
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenReader(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	st.JobID = "job-1"
+	if err := m.UpdateState(st); err != nil {
+		t.Fatalf("UpdateState() with an open reader: %v", err)
+	}
+	m.InitializeState(2, "job2", "/data/job2")
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	all, err := r.All()
+	if err != nil || len(all) != 2 || all[0].JobID != "job-1" {
+		t.Errorf("All() = %+v, %v", all, err)
+	}
+}
+
+func TestSaveRejectsPathsSQLiteCannotOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"", filepath.Join(dir, "run?.state")} {
+		m := NewManager(name)
+		m.InitializeState(1, "job1", "/data/job1")
+		if err := m.writeSQLite(); err == nil {
+			t.Errorf("writeSQLite(%q) succeeded, want error", name)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files left behind: %v", entries)
+	}
+}
+
+func TestAcquireLockTakesOverDeadOwnersLock(t *testing.T) {
+	// A process that has exited leaves its PID behind
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	owner := fmt.Sprintf(`{"host":%q,"pid":%d}`, host, cmd.Process.Pid)
+	if err := os.WriteFile(stateFile+".lock", []byte(owner), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(stateFile)
+	if err := m.AcquireLock(); err != nil {
+		t.Fatalf("AcquireLock() over a dead owner's fresh lock error = %v", err)
+	}
+	m.ReleaseLock()
+}
+
+func TestLockedErrorNamesStateFileAndPID(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	first := NewManager(stateFile)
+	if err := first.AcquireLock(); err != nil {
+		t.Fatal(err)
+	}
+	defer first.ReleaseLock()
+
+	err := NewManager(stateFile).AcquireLock()
+	want := fmt.Sprintf("state file %s is in use by PID %d", stateFile, os.Getpid())
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("AcquireLock() error = %v, want %q", err, want)
+	}
+}
+
+func TestAcquireLockReloadsStateWrittenSinceLoad(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run.state")
+	setup := NewManager(stateFile)
+	setup.InitializeState(1, "job1", "/data/job1")
+	if err := setup.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two resumes load the same state; the first to get the lock submits the job
+	waiting := NewManager(stateFile)
+	if err := waiting.Load(); err != nil {
+		t.Fatal(err)
+	}
+	first := NewManager(stateFile)
+	if err := first.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.AcquireLock(); err != nil {
+		t.Fatal(err)
+	}
+	st := first.GetState(1)
+	st.JobID, st.SubmitStatus = "job-1", "success"
+	if err := first.UpdateState(st); err != nil {
+		t.Fatal(err)
+	}
+	first.ReleaseLock()
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(stateFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := waiting.AcquireLock(); err != nil {
+		t.Fatal(err)
+	}
+	defer waiting.ReleaseLock()
+	if got := waiting.GetState(1); got == nil || got.JobID != "job-1" || got.SubmitStatus != "success" {
+		t.Errorf("state after AcquireLock = %+v, want the first run's submitted job", got)
+	}
+}
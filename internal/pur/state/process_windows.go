@@ -0,0 +1,20 @@
+//go:build windows
+
+package state
+
+import "golang.org/x/sys/windows"
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but belongs to another user
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(handle)
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == 259 // STILL_ACTIVE
+}
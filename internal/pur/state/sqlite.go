@@ -0,0 +1,376 @@
+package state
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// sqliteMagic starts every SQLite database file.
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+// sqliteSchemaVersion is stored as the database's user_version.
+const sqliteSchemaVersion = 1
+
+// sqliteColumns are the columns of the jobs table, in stateHeader order.
+var sqliteColumns = []string{"idx", "job_name", "directory", "tar_path", "tar_status", "file_id",
+	"upload_status", "job_id", "submit_status", "extra_file_ids", "error_message", "last_updated",
+	"skipped_files", "warnings", "output_status", "array_name", "normalized_files"}
+
+// sqliteSchema creates the jobs table and its lookup indexes.
+var sqliteSchema = `CREATE TABLE IF NOT EXISTS jobs (
+	idx INTEGER PRIMARY KEY,
+	` + strings.Join(sqliteColumns[1:], " TEXT NOT NULL DEFAULT '',\n\t") + ` TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS jobs_job_name ON jobs(job_name);
+CREATE INDEX IF NOT EXISTS jobs_job_id ON jobs(job_id);
+PRAGMA user_version = ` + fmt.Sprint(sqliteSchemaVersion)
+
+// BackupSuffix is appended to a CSV state file's path for the copy kept when
+// it is migrated to SQLite.
+const BackupSuffix = ".csv.bak"
+
+// isSQLiteFile reports whether the file at path is an SQLite database.
+func isSQLiteFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(sqliteMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, sqliteMagic)
+}
+
+// openSQLite opens the state database at path. The rollback journal is used
+// rather than WAL, which does not work on network file systems, so state on
+// a shared drive keeps working; writes wait for readers rather than fail.
+func openSQLite(path string, readOnly bool) (*sql.DB, error) {
+	// The driver takes everything after the first '?' as options
+	if path == "" || strings.ContainsRune(path, '?') {
+		return nil, fmt.Errorf("invalid state file path %q", path)
+	}
+	dsn := path + "?_pragma=busy_timeout(5000)"
+	if readOnly {
+		dsn += "&_pragma=query_only(1)"
+	} else {
+		dsn += "&_pragma=journal_mode(DELETE)&_pragma=synchronous(FULL)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// sqliteErr marks errors that mean the database cannot be written as
+// fs.ErrPermission, so the manager switches to in-memory mode for them as it
+// does for a read-only CSV location.
+func sqliteErr(err error) error {
+	var se *sqlite.Error
+	if errors.As(err, &se) {
+		switch se.Code() & 0xff {
+		case sqlite3.SQLITE_READONLY, sqlite3.SQLITE_PERM, sqlite3.SQLITE_CANTOPEN:
+			return fmt.Errorf("%w: %v", fs.ErrPermission, err)
+		}
+	}
+	return err
+}
+
+// queryStates runs a SELECT of sqliteColumns from jobs with the given
+// clause and returns the states in index order.
+func queryStates(db *sql.DB, clause string, args ...any) ([]*models.JobState, error) {
+	query := "SELECT " + strings.Join(sqliteColumns, ", ") + " FROM jobs " + clause + " ORDER BY idx"
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+	defer rows.Close()
+
+	var states []*models.JobState
+	record := make([]string, len(sqliteColumns))
+	dest := make([]any, len(record))
+	for i := range record {
+		dest[i] = &record[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if st := parseRecord(record); st != nil {
+			states = append(states, st)
+		}
+	}
+	return states, rows.Err()
+}
+
+// loadSQLite loads state from the state database. Caller must hold the
+// write lock.
+func (m *Manager) loadSQLite() error {
+	db, err := openSQLite(m.filePath, true)
+	if err != nil {
+		return fmt.Errorf("failed to open state database: %w", err)
+	}
+	defer db.Close()
+
+	states, err := queryStates(db, "")
+	if err != nil {
+		return fmt.Errorf("failed to read state database: %w", err)
+	}
+	for _, st := range states {
+		m.states[st.Index] = st
+		m.saved[st.Index] = fieldsOf(st)
+		m.written[st.Index] = stateRecord(st)
+	}
+	m.synced = true
+	return nil
+}
+
+// writeSQLite writes the states that changed since the last write to the
+// state database in one transaction. The first write of a manager that did
+// not load the database replaces all rows, as a CSV write replaces the file.
+// A CSV file at the path is migrated instead (see replaceSQLite).
+func (m *Manager) writeSQLite() error {
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if m.migrate || (!m.synced && !isSQLiteFile(m.filePath) && fileExists(m.filePath)) {
+		return m.replaceSQLite()
+	}
+
+	db, err := openSQLite(m.filePath, false)
+	if err != nil {
+		return fmt.Errorf("failed to open state database: %w", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create state database: %w", sqliteErr(err))
+	}
+
+	changed, err := m.writeRows(db, !m.synced)
+	if err != nil {
+		return err
+	}
+	if !m.synced {
+		clear(m.written)
+	}
+	for idx, record := range changed {
+		m.written[idx] = record
+	}
+	m.synced = true
+	return nil
+}
+
+// writeRows upserts the states whose record differs from what was last
+// written, after deleting all rows when replace is set, and returns the
+// written records. Caller must hold the write lock.
+func (m *Manager) writeRows(db *sql.DB, replace bool) (map[int][]string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin state transaction: %w", sqliteErr(err))
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.Exec("DELETE FROM jobs"); err != nil {
+			return nil, fmt.Errorf("failed to clear state database: %w", sqliteErr(err))
+		}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(sqliteColumns)), ", ")
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO jobs (" + strings.Join(sqliteColumns, ", ") + ") VALUES (" + placeholders + ")")
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare state update: %w", sqliteErr(err))
+	}
+	defer stmt.Close()
+
+	changed := make(map[int][]string)
+	args := make([]any, len(sqliteColumns))
+	for _, idx := range m.sortedIndicesUnlocked() {
+		record := stateRecord(m.states[idx])
+		if !replace && slices.Equal(record, m.written[idx]) {
+			continue
+		}
+		args[0] = m.states[idx].Index
+		for i := 1; i < len(record); i++ {
+			args[i] = record[i]
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return nil, fmt.Errorf("failed to write state record: %w", sqliteErr(err))
+		}
+		changed[idx] = record
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit state: %w", sqliteErr(err))
+	}
+	return changed, nil
+}
+
+// replaceSQLite writes all states to a new database and renames it over the
+// state file. A CSV state file being replaced is first copied to
+// "<state>.csv.bak" (unless that exists), so the run can still be read by
+// older versions.
+func (m *Manager) replaceSQLite() error {
+	dir := filepath.Dir(m.filePath)
+	file, err := os.CreateTemp(dir, filepath.Base(m.filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	tempFile := file.Name()
+	file.Close()
+	// CreateTemp uses 0600; state on a shared drive must stay readable by the team
+	_ = os.Chmod(tempFile, 0644)
+
+	success := false
+	defer func() {
+		if !success {
+			os.Remove(tempFile)
+		}
+	}()
+
+	db, err := openSQLite(tempFile, false)
+	if err != nil {
+		return fmt.Errorf("failed to create state database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to create state database: %w", sqliteErr(err))
+	}
+	written, err := m.writeRows(db, true)
+	if closeErr := db.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close state database: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if fileExists(m.filePath) && !isSQLiteFile(m.filePath) && !fileExists(m.filePath+BackupSuffix) {
+		data, err := os.ReadFile(m.filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read CSV state for backup: %w", err)
+		}
+		if err := os.WriteFile(m.filePath+BackupSuffix, data, 0644); err != nil {
+			return fmt.Errorf("failed to back up CSV state: %w", err)
+		}
+	}
+
+	if err := os.Rename(tempFile, m.filePath); err != nil {
+		return fmt.Errorf("failed to rename state file: %w", err)
+	}
+	success = true
+
+	m.migrate = false
+	m.synced = true
+	m.written = written
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Reader queries a state file without loading it into a Manager, e.g. for
+// the GUI's run history while a run may be writing the file. An SQLite state
+// file is queried through its indexes; a CSV file is read into memory.
+type Reader struct {
+	db     *sql.DB
+	states []*models.JobState // CSV state files
+}
+
+// OpenReader opens the state file at path for reading.
+func OpenReader(path string) (*Reader, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	if isSQLiteFile(path) {
+		db, err := openSQLite(path, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open state database: %w", err)
+		}
+		return &Reader{db: db}, nil
+	}
+
+	m := NewManager(path)
+	m.mu.Lock()
+	err := m.loadCSV()
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{states: m.GetAllStates()}, nil
+}
+
+// Close closes the reader.
+func (r *Reader) Close() error {
+	if r.db != nil {
+		return r.db.Close()
+	}
+	return nil
+}
+
+// All returns all job states sorted by index.
+func (r *Reader) All() ([]*models.JobState, error) {
+	if r.db == nil {
+		return r.states, nil
+	}
+	return queryStates(r.db, "")
+}
+
+// Count returns the number of jobs.
+func (r *Reader) Count() (int, error) {
+	if r.db == nil {
+		return len(r.states), nil
+	}
+	var n int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM jobs").Scan(&n)
+	return n, sqliteErr(err)
+}
+
+// ByJobID returns the state of the job with the given platform job ID, or
+// nil if no job has it.
+func (r *Reader) ByJobID(jobID string) (*models.JobState, error) {
+	if jobID == "" {
+		return nil, nil
+	}
+	states, err := r.where("job_id = ?", jobID, func(st *models.JobState) bool { return st.JobID == jobID })
+	if err != nil || len(states) == 0 {
+		return nil, err
+	}
+	return states[0], nil
+}
+
+// ByJobName returns the states of the jobs with the given name.
+func (r *Reader) ByJobName(name string) ([]*models.JobState, error) {
+	return r.where("job_name = ?", name, func(st *models.JobState) bool { return st.JobName == name })
+}
+
+// where returns the states matching the SQL condition, or match for CSV
+// state files.
+func (r *Reader) where(cond string, arg any, match func(*models.JobState) bool) ([]*models.JobState, error) {
+	if r.db != nil {
+		return queryStates(r.db, "WHERE "+cond, arg)
+	}
+	var states []*models.JobState
+	for _, st := range r.states {
+		if match(st) {
+			states = append(states, st)
+		}
+	}
+	return states, nil
+}
@@ -2,13 +2,17 @@ package state
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/models"
 )
 
@@ -17,96 +21,425 @@ type Manager struct {
 	filePath string
 	states   map[int]*models.JobState // Index -> JobState
 	mu       sync.RWMutex
+
+	// Shared state support: lock is held while a run writes the file; readOnlyErr
+	// is set once the state location turns out not to be writable, after which
+	// state is kept in memory only.
+	lock        *fileLock
+	readOnlyErr error
+
+	// Batched persistence: UpdateState marks the state dirty and a timer
+	// writes it saveInterval later, except for critical transitions (see
+	// isCritical), which are written before UpdateState returns. saved holds
+	// the critical fields as last written for each job.
+	dirty        bool
+	saveTimer    *time.Timer
+	saveErr      error // From a timer save, returned by the next Save
+	saveInterval time.Duration
+	saved        map[int]savedFields
+
+	// Rows dropped by Load because a write was cut short
+	recovered int
+
+	// Modification time of the state file when Load read it, so AcquireLock
+	// can reload a file another run wrote in the meantime
+	loadedMod time.Time
+
+	// Storage format: an SQLite database (see sqlite.go), or CSV for paths
+	// ending in ".csv". A CSV state file at any other path is read as it is
+	// and migrated to SQLite by the next write. Only rows that changed since
+	// the last write (written) go to the database; synced is false until
+	// this manager has read or written the database, when a write replaces
+	// every row.
+	csvFormat bool
+	migrate   bool
+	synced    bool
+	written   map[int][]string
+}
+
+// savedFields are the fields of a job state whose change is written at once.
+type savedFields struct {
+	jobID        string
+	fileID       string
+	submitStatus string
 }
 
 // NewManager creates a new state manager
 func NewManager(filePath string) *Manager {
 	return &Manager{
-		filePath: filePath,
-		states:   make(map[int]*models.JobState),
+		filePath:     filePath,
+		states:       make(map[int]*models.JobState),
+		saveInterval: constants.StateSaveInterval,
+		saved:        make(map[int]savedFields),
+		csvFormat:    isCSVPath(filePath),
+		written:      make(map[int][]string),
+	}
+}
+
+// Path returns the state file path.
+func (m *Manager) Path() string {
+	return m.filePath
+}
+
+// stateHeader names the fields of a state record, in the order of the CSV
+// columns and of the SQLite columns (sqliteColumns).
+var stateHeader = []string{"Index", "JobName", "Directory", "TarPath", "TarStatus", "FileID",
+	"UploadStatus", "JobID", "SubmitStatus", "ExtraFileIDs", "ErrorMessage", "LastUpdated", "SkippedFiles", "Warnings", "OutputStatus", "ArrayName", "NormalizedFiles"}
+
+// isCSVPath reports whether path is kept as a CSV state file.
+func isCSVPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".csv")
+}
+
+// stateRecord returns the fields of state in stateHeader order.
+func stateRecord(state *models.JobState) []string {
+	return []string{
+		fmt.Sprintf("%d", state.Index),
+		state.JobName,
+		state.Directory,
+		state.TarPath,
+		state.TarStatus,
+		state.FileID,
+		state.UploadStatus,
+		state.JobID,
+		state.SubmitStatus,
+		state.ExtraFileIDs,
+		state.ErrorMessage,
+		state.LastUpdated.Format(time.RFC3339),
+		state.SkippedFiles,
+		state.Warnings,
+		state.OutputStatus,
+		state.ArrayName,
+		state.NormalizedFiles,
+	}
+}
+
+// parseRecord builds a job state from a record in stateHeader order. Records
+// from older state files may lack the trailing fields; records shorter than
+// 12 fields are not states and return nil.
+func parseRecord(record []string) *models.JobState {
+	if len(record) < 12 {
+		return nil
+	}
+
+	var index int
+	fmt.Sscanf(record[0], "%d", &index)
+
+	lastUpdated, _ := time.Parse(time.RFC3339, record[11])
+
+	state := &models.JobState{
+		Index:        index,
+		JobName:      record[1],
+		Directory:    record[2],
+		TarPath:      record[3],
+		TarStatus:    record[4],
+		FileID:       record[5],
+		UploadStatus: record[6],
+		JobID:        record[7],
+		SubmitStatus: record[8],
+		ExtraFileIDs: record[9],
+		ErrorMessage: record[10],
+		LastUpdated:  lastUpdated,
+	}
+	// SkippedFiles was added later; older state files lack it
+	if len(record) > 12 {
+		state.SkippedFiles = record[12]
+	}
+	if len(record) > 13 {
+		state.Warnings = record[13]
+	}
+	if len(record) > 14 {
+		state.OutputStatus = record[14]
 	}
+	if len(record) > 15 {
+		state.ArrayName = record[15]
+	}
+	if len(record) > 16 {
+		state.NormalizedFiles = record[16]
+	}
+	return state
 }
 
-// Load loads state from CSV file
+// Load loads state from the state file: an SQLite database, or a CSV file
+// (see Manager for when one is migrated).
 func (m *Manager) Load() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return m.loadUnlocked()
+}
 
-	if _, err := os.Stat(m.filePath); os.IsNotExist(err) {
+// loadUnlocked loads the state file. Caller must hold the write lock.
+func (m *Manager) loadUnlocked() error {
+	info, err := os.Stat(m.filePath)
+	if os.IsNotExist(err) {
 		return nil // No state file yet, that's OK
 	}
+	if err == nil {
+		m.loadedMod = info.ModTime()
+	}
 
+	if isSQLiteFile(m.filePath) {
+		return m.loadSQLite()
+	}
+	if err := m.loadCSV(); err != nil {
+		return err
+	}
+	m.migrate = !m.csvFormat
+	return nil
+}
+
+// loadCSV loads state from a CSV state file. Caller must hold the write lock.
+func (m *Manager) loadCSV() error {
 	file, err := os.Open(m.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open state file: %w", err)
 	}
 	defer file.Close()
 
+	// A crash while the file was being written (on file systems where the
+	// rename in writeCSV is not atomic, such as some network shares) can
+	// leave the last row cut short. Keep the rows before it; the job in the
+	// torn row starts over.
 	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to read state CSV: %w", err)
+	reader.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(records) == 0 {
+				return fmt.Errorf("failed to read state CSV: %w", err)
+			}
+			m.recovered++
+			break
+		}
+		records = append(records, record)
+	}
+	if m.recovered == 0 && len(records) > 1 && len(records[len(records)-1]) < len(records[0]) {
+		records = records[:len(records)-1]
+		m.recovered++
 	}
 
 	if len(records) < 2 {
 		return nil // Empty state file
 	}
 
-	// Expected header: Index,JobName,Directory,TarPath,TarStatus,FileID,UploadStatus,JobID,SubmitStatus,ExtraFileIDs,ErrorMessage,LastUpdated
-	for i := 1; i < len(records); i++ {
-		record := records[i]
-		if len(record) < 12 {
+	// Expected header: Index,JobName,Directory,TarPath,TarStatus,FileID,UploadStatus,JobID,SubmitStatus,ExtraFileIDs,ErrorMessage,LastUpdated[,SkippedFiles[,Warnings[,OutputStatus[,ArrayName[,NormalizedFiles]]]]]
+	for _, record := range records[1:] {
+		state := parseRecord(record)
+		if state == nil {
 			continue
 		}
+		m.states[state.Index] = state
+		m.saved[state.Index] = fieldsOf(state)
+	}
+
+	return nil
+}
+
+// Recovered returns how many rows Load dropped because the state file was
+// cut short mid-write; those jobs start over.
+func (m *Manager) Recovered() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.recovered
+}
 
-		var index int
-		fmt.Sscanf(record[0], "%d", &index)
-
-		lastUpdated, _ := time.Parse(time.RFC3339, record[11])
-
-		state := &models.JobState{
-			Index:        index,
-			JobName:      record[1],
-			Directory:    record[2],
-			TarPath:      record[3],
-			TarStatus:    record[4],
-			FileID:       record[5],
-			UploadStatus: record[6],
-			JobID:        record[7],
-			SubmitStatus: record[8],
-			ExtraFileIDs: record[9],
-			ErrorMessage: record[10],
-			LastUpdated:  lastUpdated,
+// Save writes the state file now, including changes UpdateState batched
+// (in one transaction, or one atomic file replace for CSV). It returns the
+// error of a failed batched write, if any.
+func (m *Manager) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.saveUnlocked(); err != nil {
+		return err
+	}
+	err := m.saveErr
+	m.saveErr = nil
+	return err
+}
+
+// AcquireLock takes the "<state>.lock" file so only one process writes this
+// run's state at a time. Returns *LockedError if another live run holds it.
+// If the state location is read-only the manager switches to in-memory mode
+// (see ReadOnly) and no error is returned. Calling it again is a no-op.
+//
+// State loaded before the lock is reloaded if another run has written the
+// file since, so the run continues from that run's progress rather than
+// overwriting it.
+func (m *Manager) AcquireLock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.filePath == "" || m.lock != nil || m.readOnlyErr != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0755); err != nil {
+		if isReadOnlyErr(err) {
+			m.readOnlyErr = err
+			return nil
 		}
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
 
-		m.states[index] = state
+	lock, err := acquireFileLock(m.filePath+".lock", constants.StateLockHeartbeat, constants.StateLockStaleAfter)
+	if err != nil {
+		if isReadOnlyErr(err) {
+			m.readOnlyErr = err
+			return nil
+		}
+		var locked *LockedError
+		if errors.As(err, &locked) {
+			locked.Path = m.filePath
+		}
+		return err
 	}
+	m.lock = lock
 
+	if info, err := os.Stat(m.filePath); err == nil && !m.loadedMod.IsZero() && !m.dirty && !info.ModTime().Equal(m.loadedMod) {
+		if err := m.reloadUnlocked(); err != nil {
+			m.lock.release()
+			m.lock = nil
+			return err
+		}
+	}
 	return nil
 }
 
-// Save saves state to CSV file (atomic write)
-func (m *Manager) Save() error {
+// reloadUnlocked replaces the loaded state with the state file's current
+// contents. Caller must hold the write lock.
+func (m *Manager) reloadUnlocked() error {
+	clear(m.states)
+	clear(m.saved)
+	clear(m.written)
+	m.recovered = 0
+	m.migrate = false
+	m.synced = false
+	if err := m.loadUnlocked(); err != nil {
+		return fmt.Errorf("failed to reload state: %w", err)
+	}
+	return nil
+}
+
+// ReleaseLock writes any batched changes and removes the lock taken by
+// AcquireLock, if any.
+func (m *Manager) ReleaseLock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dirty {
+		if err := m.saveUnlocked(); err != nil {
+			m.saveErr = err
+		}
+	}
+	if m.lock != nil {
+		m.lock.release()
+		m.lock = nil
+	}
+}
+
+// ReadOnly returns the write error that switched the manager to in-memory
+// mode, or nil while state is being persisted normally.
+func (m *Manager) ReadOnly() error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.saveUnlocked()
+	return m.readOnlyErr
 }
 
-// saveUnlocked saves state to CSV file without acquiring locks.
-// Caller must hold at least RLock on m.mu.
+// saveUnlocked saves state to the state file without acquiring locks.
+// Caller must hold the write lock on m.mu (it may set readOnlyErr).
 func (m *Manager) saveUnlocked() error {
+	if m.readOnlyErr != nil {
+		m.dirty = false
+		return nil // Read-only location: keep state in memory
+	}
+
+	err := m.writeFile()
+	if err != nil && isReadOnlyErr(err) {
+		m.readOnlyErr = err
+		m.dirty = false
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	m.dirty = false
+	for idx, st := range m.states {
+		m.saved[idx] = fieldsOf(st)
+	}
+	return nil
+}
+
+// scheduleSaveUnlocked marks the state dirty and starts the batch timer if
+// it is not running. Caller must hold the write lock on m.mu.
+func (m *Manager) scheduleSaveUnlocked() {
+	m.dirty = true
+	if m.saveTimer == nil {
+		m.saveTimer = time.AfterFunc(m.saveInterval, m.saveBatched)
+	}
+}
+
+// saveBatched writes the changes batched since the timer started.
+func (m *Manager) saveBatched() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.saveTimer = nil
+	if m.dirty {
+		if err := m.saveUnlocked(); err != nil {
+			m.saveErr = err
+		}
+	}
+}
+
+// isCritical reports whether state differs from what was last written in a
+// way that must reach the file before the caller goes on: losing a created
+// job, an uploaded file or a submit outcome in a crash would make a resume
+// create, upload or submit the job again.
+func (m *Manager) isCritical(state *models.JobState) bool {
+	saved := m.saved[state.Index]
+	switch {
+	case state.JobID != saved.jobID, state.FileID != saved.fileID:
+		return true
+	case state.SubmitStatus != saved.submitStatus:
+		return state.SubmitStatus == "success" || state.SubmitStatus == "failed"
+	}
+	return false
+}
+
+func fieldsOf(state *models.JobState) savedFields {
+	return savedFields{jobID: state.JobID, fileID: state.FileID, submitStatus: state.SubmitStatus}
+}
+
+// writeFile writes the states to the state file in its format.
+func (m *Manager) writeFile() error {
+	if m.csvFormat {
+		return m.writeCSV()
+	}
+	return m.writeSQLite()
+}
+
+// writeCSV writes all states to a temp file and renames it over the state file.
+func (m *Manager) writeCSV() error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(m.filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create state directory: %w", err)
 	}
 
-	// Write to temporary file first
-	tempFile := m.filePath + ".tmp"
-	file, err := os.Create(tempFile)
+	// Write to a uniquely named temporary file first so concurrent savers
+	// (e.g. a second machine resuming the run) never share a temp file
+	file, err := os.CreateTemp(dir, filepath.Base(m.filePath)+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("failed to create temp state file: %w", err)
 	}
+	tempFile := file.Name()
+	// CreateTemp uses 0600; state on a shared drive must stay readable by the team
+	_ = os.Chmod(tempFile, 0644)
 
 	// Use a flag to track successful completion for cleanup
 	success := false
@@ -120,37 +453,14 @@ func (m *Manager) saveUnlocked() error {
 	writer := csv.NewWriter(file)
 
 	// Write header
-	header := []string{"Index", "JobName", "Directory", "TarPath", "TarStatus", "FileID",
-		"UploadStatus", "JobID", "SubmitStatus", "ExtraFileIDs", "ErrorMessage", "LastUpdated"}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(stateHeader); err != nil {
 		return fmt.Errorf("failed to write state header: %w", err)
 	}
 
 	// Write data rows (sorted by index)
 	// Iterate over map keys to handle non-consecutive indices
-	indices := make([]int, 0, len(m.states))
-	for idx := range m.states {
-		indices = append(indices, idx)
-	}
-	sort.Ints(indices)
-
-	for _, idx := range indices {
-		state := m.states[idx]
-		record := []string{
-			fmt.Sprintf("%d", state.Index),
-			state.JobName,
-			state.Directory,
-			state.TarPath,
-			state.TarStatus,
-			state.FileID,
-			state.UploadStatus,
-			state.JobID,
-			state.SubmitStatus,
-			state.ExtraFileIDs,
-			state.ErrorMessage,
-			state.LastUpdated.Format(time.RFC3339),
-		}
-		if err := writer.Write(record); err != nil {
+	for _, idx := range m.sortedIndicesUnlocked() {
+		if err := writer.Write(stateRecord(m.states[idx])); err != nil {
 			return fmt.Errorf("failed to write state record: %w", err)
 		}
 	}
@@ -160,6 +470,11 @@ func (m *Manager) saveUnlocked() error {
 		return fmt.Errorf("failed to flush state writer: %w", err)
 	}
 
+	// Make the contents durable before the rename makes them the state
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp state file: %w", err)
+	}
+
 	if err := file.Close(); err != nil {
 		return fmt.Errorf("failed to close temp state file: %w", err)
 	}
@@ -180,7 +495,10 @@ func (m *Manager) GetState(index int) *models.JobState {
 	return m.states[index]
 }
 
-// UpdateState updates the state for a given job
+// UpdateState updates the state for a given job. Critical transitions
+// (see isCritical) are written to the state file before it returns; other
+// changes are batched and written within constants.StateSaveInterval, or
+// by Save or ReleaseLock.
 func (m *Manager) UpdateState(state *models.JobState) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -188,8 +506,11 @@ func (m *Manager) UpdateState(state *models.JobState) error {
 	state.LastUpdated = time.Now()
 	m.states[state.Index] = state
 
-	// Save immediately for persistence (while still holding lock to prevent race)
-	return m.saveUnlocked()
+	if m.isCritical(state) {
+		return m.saveUnlocked()
+	}
+	m.scheduleSaveUnlocked()
+	return nil
 }
 
 // InitializeState initializes state for a new job
@@ -216,18 +537,22 @@ func (m *Manager) GetAllStates() []*models.JobState {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Collect and sort indices for deterministic ordering
+	states := make([]*models.JobState, 0, len(m.states))
+	for _, idx := range m.sortedIndicesUnlocked() {
+		states = append(states, m.states[idx])
+	}
+	return states
+}
+
+// sortedIndicesUnlocked returns the job indices in ascending order. Caller
+// must hold m.mu.
+func (m *Manager) sortedIndicesUnlocked() []int {
 	indices := make([]int, 0, len(m.states))
 	for idx := range m.states {
 		indices = append(indices, idx)
 	}
 	sort.Ints(indices)
-
-	states := make([]*models.JobState, 0, len(m.states))
-	for _, idx := range indices {
-		states = append(states, m.states[idx])
-	}
-	return states
+	return indices
 }
 
 // CountByStatus counts jobs by their status
@@ -250,13 +575,68 @@ func (m *Manager) CountByStatus(statusField string, status string) int {
 			if state.SubmitStatus == status {
 				count++
 			}
+		case "output":
+			if state.OutputStatus == status {
+				count++
+			}
 		}
 	}
 	return count
 }
 
+// ResetFailed sets every job with a failed tar, upload or submit back to
+// pending from the stage that failed on, so the next pipeline run retries
+// it, and saves the state. Work the job finished before that stage (tars,
+// uploaded files, a created job) is kept, as are jobs that did not fail.
+// It returns the indices of the reset jobs in ascending order.
+func (m *Manager) ResetFailed() ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var reset []int
+	for idx, st := range m.states {
+		switch {
+		case st.TarStatus == "failed":
+			st.TarStatus = "pending"
+			st.UploadStatus = "pending"
+			st.SubmitStatus = "pending"
+		case st.UploadStatus == "failed":
+			st.UploadStatus = "pending"
+			st.SubmitStatus = "pending"
+		case st.SubmitStatus == "failed":
+			st.SubmitStatus = "pending"
+		default:
+			continue
+		}
+		st.UploadProgress = 0
+		st.ErrorMessage = ""
+		st.LastUpdated = time.Now()
+		reset = append(reset, idx)
+	}
+	sort.Ints(reset)
+
+	if len(reset) == 0 {
+		return nil, nil
+	}
+	return reset, m.saveUnlocked()
+}
+
+// FailedStage returns the stage st failed at, "tar", "upload" or "submit",
+// or "" if it has not failed.
+func FailedStage(st *models.JobState) string {
+	switch {
+	case st.TarStatus == "failed":
+		return "tar"
+	case st.UploadStatus == "failed":
+		return "upload"
+	case st.SubmitStatus == "failed":
+		return "submit"
+	}
+	return ""
+}
+
 // UpdateUploadProgress updates the upload progress for a job by index.
-// This is a transient update - progress is not persisted to CSV (only status is).
+// This is a transient update - progress is not persisted (only status is).
 func (m *Manager) UpdateUploadProgress(index int, progress float64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
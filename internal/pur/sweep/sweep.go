@@ -0,0 +1,230 @@
+// Package sweep expands a template job into one job per row of a parameter
+// table, substituting the row's variables into the job's name and command.
+// Unlike a directory scan, the jobs of a sweep share the template's input
+// directory and differ only in their parameters.
+package sweep
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// IndexVariable is always defined and holds a row's job index.
+const IndexVariable = "index"
+
+// MaxRows caps the number of jobs one sweep expands into.
+const MaxRows = 10000
+
+// placeholderRe matches a "{{name}}" placeholder; spaces inside the braces
+// are allowed.
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// Table is a parameter table: the variable names in column order and one
+// value per variable for each row.
+type Table struct {
+	Names []string
+	Rows  []map[string]string
+}
+
+// LoadTable reads a parameter table from a CSV file (a header row naming
+// the variables, then one row per job) or, for a .json file, from an array
+// of objects whose values are strings, numbers or booleans.
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameter table: %w", err)
+	}
+
+	var table *Table
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		table, err = parseJSON(data)
+	} else {
+		table, err = parseCSV(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameter table %s: %w", path, err)
+	}
+	if len(table.Rows) == 0 {
+		return nil, fmt.Errorf("parameter table %s has no rows", path)
+	}
+	if len(table.Rows) > MaxRows {
+		return nil, fmt.Errorf("parameter table %s has %d rows (at most %d)", path, len(table.Rows), MaxRows)
+	}
+	return table, nil
+}
+
+// parseCSV parses a CSV parameter table.
+func parseCSV(data []byte) (*Table, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(data), "\ufeff")))
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	names := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		names[i] = strings.TrimSpace(name)
+	}
+	if err := checkNames(names); err != nil {
+		return nil, err
+	}
+
+	table := &Table{Names: names}
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(names))
+		for i, name := range names {
+			row[name] = strings.TrimSpace(record[i])
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// parseJSON parses a JSON parameter table. Object keys have no order, so
+// the variable names are sorted.
+func parseJSON(data []byte) (*Table, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, fmt.Errorf("expected an array of objects: %w", err)
+	}
+
+	table := &Table{}
+	known := make(map[string]bool)
+	for i, obj := range objects {
+		row := make(map[string]string, len(obj))
+		for key, raw := range obj {
+			value, err := scalar(raw)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, %q: %w", i+1, key, err)
+			}
+			row[key] = value
+			if !known[key] {
+				known[key] = true
+				table.Names = append(table.Names, key)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	sort.Strings(table.Names)
+	if err := checkNames(table.Names); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// scalar returns the text of a JSON string, number or boolean.
+func scalar(raw json.RawMessage) (string, error) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("value must be a string, number or boolean")
+	}
+}
+
+// checkNames validates variable names.
+func checkNames(names []string) error {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		switch {
+		case name == IndexVariable:
+			return fmt.Errorf("%q is reserved for the job index", name)
+		case !placeholderRe.MatchString("{{" + name + "}}"):
+			return fmt.Errorf("invalid variable name %q", name)
+		case seen[name]:
+			return fmt.Errorf("variable %q is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Expand builds one job per table row from template, numbered from
+// startIndex. Each "{{name}}" placeholder in the template's JobName,
+// Command and ContinuationCommand is replaced by the row's value, and
+// "{{index}}" by the job index. A JobName without placeholders gets
+// "_<index>" in place of the template's "_1" suffix (or appended), as in a
+// directory scan, so the names stay unique. A placeholder naming no
+// variable, or two rows producing the same job name, is an error.
+func Expand(template models.JobSpec, table *Table, startIndex int) ([]models.JobSpec, error) {
+	jobs := make([]models.JobSpec, 0, len(table.Rows))
+	names := make(map[string]int)
+	for i, row := range table.Rows {
+		index := startIndex + i
+		vars := make(map[string]string, len(row)+1)
+		for k, v := range row {
+			vars[k] = v
+		}
+		vars[IndexVariable] = strconv.Itoa(index)
+
+		job := template
+		job.Tags = append([]string(nil), template.Tags...)
+		job.Automations = append([]string(nil), template.Automations...)
+		job.InputFiles = append([]string(nil), template.InputFiles...)
+		job.OutputPatterns = append([]string(nil), template.OutputPatterns...)
+		job.DependsOn = append([]string(nil), template.DependsOn...)
+
+		var err error
+		if placeholderRe.MatchString(template.JobName) {
+			job.JobName, err = substitute(template.JobName, vars)
+		} else {
+			job.JobName = fmt.Sprintf("%s_%d", strings.TrimSuffix(template.JobName, "_1"), index)
+		}
+		if err == nil {
+			job.Command, err = substitute(template.Command, vars)
+		}
+		if err == nil {
+			job.ContinuationCommand, err = substitute(template.ContinuationCommand, vars)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+
+		if prev, ok := names[job.JobName]; ok {
+			return nil, fmt.Errorf("rows %d and %d both produce job name %q", prev+1, i+1, job.JobName)
+		}
+		names[job.JobName] = i
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// substitute replaces the placeholders in s with their values in vars.
+func substitute(s string, vars map[string]string) (string, error) {
+	var missing string
+	out := placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := placeholderRe.FindStringSubmatch(m)[1]
+		value, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("no value for %q in the parameter table", missing)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package sweep
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func writeTable(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTable_CSV(t *testing.T) {
+	table, err := LoadTable(writeTable(t, "params.csv", "mach, aoa\n0.3, 2\n0.5,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(table.Names, ",") != "mach,aoa" || len(table.Rows) != 2 {
+		t.Fatalf("table = %+v", table)
+	}
+	if table.Rows[0]["mach"] != "0.3" || table.Rows[1]["aoa"] != "4" {
+		t.Errorf("rows = %v", table.Rows)
+	}
+}
+
+func TestLoadTable_JSON(t *testing.T) {
+	table, err := LoadTable(writeTable(t, "params.json", `[{"mach": 0.3, "mesh": "coarse"}, {"mach": 1e-1, "mesh": "fine", "viscous": true}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(table.Names, ",") != "mach,mesh,viscous" {
+		t.Errorf("names = %v", table.Names)
+	}
+	if table.Rows[0]["mach"] != "0.3" || table.Rows[1]["mach"] != "1e-1" || table.Rows[1]["viscous"] != "true" {
+		t.Errorf("rows = %v", table.Rows)
+	}
+}
+
+func TestLoadTable_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty.csv":    "mach\n",
+		"index.csv":    "index\n1\n",
+		"twice.csv":    "a,a\n1,2\n",
+		"space.csv":    "a b\n1\n",
+		"nested.json":  `[{"a": [1]}]`,
+		"object.json":  `{"a": 1}`,
+		"missing.json": `[]`,
+	} {
+		if _, err := LoadTable(writeTable(t, name, content)); err == nil {
+			t.Errorf("LoadTable(%s) succeeded", name)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	template := models.JobSpec{
+		JobName:   "wing_M{{mach}}_{{ aoa }}",
+		Command:   "./run.sh --mach {{mach}} --aoa {{aoa}} --case {{index}}",
+		Directory: "/data/wing",
+		Tags:      []string{"sweep"},
+	}
+	table := &Table{Names: []string{"mach", "aoa"}, Rows: []map[string]string{
+		{"mach": "0.3", "aoa": "2"},
+		{"mach": "0.5", "aoa": "4"},
+	}}
+
+	jobs, err := Expand(template, table, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs", len(jobs))
+	}
+	if jobs[1].JobName != "wing_M0.5_4" || jobs[1].Command != "./run.sh --mach 0.5 --aoa 4 --case 2" {
+		t.Errorf("job 2 = %q: %q", jobs[1].JobName, jobs[1].Command)
+	}
+	if jobs[0].Directory != "/data/wing" {
+		t.Errorf("directory = %q, want the template's", jobs[0].Directory)
+	}
+	jobs[0].Tags[0] = "changed"
+	if jobs[1].Tags[0] != "sweep" || template.Tags[0] != "sweep" {
+		t.Error("jobs share the template's tags")
+	}
+}
+
+func TestExpand_NameWithoutPlaceholders(t *testing.T) {
+	table := &Table{Names: []string{"n"}, Rows: []map[string]string{{"n": "1"}, {"n": "2"}}}
+	jobs, err := Expand(models.JobSpec{JobName: "case_1", Command: "run {{n}}"}, table, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobs[0].JobName != "case_10" || jobs[1].JobName != "case_11" {
+		t.Errorf("names = %q, %q", jobs[0].JobName, jobs[1].JobName)
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	table := &Table{Names: []string{"n"}, Rows: []map[string]string{{"n": "1"}, {"n": "1"}}}
+
+	_, err := Expand(models.JobSpec{JobName: "case", Command: "run {{m}}"}, table, 1)
+	if err == nil || !strings.Contains(err.Error(), `no value for "m"`) {
+		t.Errorf("unknown placeholder: err = %v", err)
+	}
+	_, err = Expand(models.JobSpec{JobName: "case_{{n}}"}, table, 1)
+	if err == nil || !strings.Contains(err.Error(), `rows 1 and 2 both produce job name "case_1"`) {
+		t.Errorf("duplicate names: err = %v", err)
+	}
+}
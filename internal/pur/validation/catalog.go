@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// CatalogSource is the API surface LoadCatalog needs. *api.Client satisfies it;
+// tests inject a fake.
+type CatalogSource interface {
+	GetCoreTypes(ctx context.Context, includeInactive bool) ([]models.CoreType, error)
+	GetAnalyses(ctx context.Context) ([]models.Analysis, error)
+	GetProjects(ctx context.Context, orgCode string) ([]models.Project, error)
+}
+
+// Catalog holds the platform reference data needed to validate many jobs:
+// core types, analyses with their versions, and the organization's projects.
+// It is fetched once per plan (one batch of requests regardless of job count)
+// and is read-only afterwards, so Check is safe for concurrent use.
+//
+// A section whose fetch failed is nil and its checks are skipped.
+type Catalog struct {
+	coreTypes *CoreTypeValidator
+	analyses  map[string]analysisVersions // lower-case analysis code -> versions
+	projects  map[string]bool             // project IDs
+}
+
+// analysisVersions maps a version display name or versionCode to the core
+// types allowed for it (empty = any).
+type analysisVersions map[string][]string
+
+// LoadCatalog fetches core types, analyses and (when orgCode is set) projects
+// concurrently. Fetch failures do not fail the load; they are returned as
+// warnings and the affected checks are skipped.
+func LoadCatalog(ctx context.Context, src CatalogSource, orgCode string) (*Catalog, []string) {
+	cat := &Catalog{}
+	var (
+		mu       sync.Mutex
+		warnings []string
+		wg       sync.WaitGroup
+	)
+	warn := func(format string, args ...interface{}) {
+		mu.Lock()
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		coreTypes, err := src.GetCoreTypes(ctx, true)
+		if err != nil {
+			warn("could not fetch core types, skipping core type checks: %v", err)
+			return
+		}
+		if len(coreTypes) == 0 {
+			// An empty list would reject every job's core type
+			warn("no core types returned, skipping core type checks")
+			return
+		}
+		cat.coreTypes = newCoreTypeValidatorFrom(coreTypes)
+	}()
+	go func() {
+		defer wg.Done()
+		analyses, err := src.GetAnalyses(ctx)
+		if err != nil {
+			warn("could not fetch analyses, skipping analysis checks: %v", err)
+			return
+		}
+		cat.analyses = indexAnalyses(analyses)
+	}()
+	if orgCode != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			projects, err := src.GetProjects(ctx, orgCode)
+			if err != nil {
+				warn("could not fetch projects, skipping project checks: %v", err)
+				return
+			}
+			cat.projects = make(map[string]bool, len(projects))
+			for _, p := range projects {
+				cat.projects[p.ID] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	return cat, warnings
+}
+
+// indexAnalyses builds the analysis lookup used by Check.
+func indexAnalyses(analyses []models.Analysis) map[string]analysisVersions {
+	index := make(map[string]analysisVersions, len(analyses))
+	for _, a := range analyses {
+		versions := make(analysisVersions)
+		for _, v := range a.Versions {
+			for _, key := range []string{v.Version, v.VersionCode} {
+				if key != "" {
+					versions[key] = v.AllowedCoreTypes
+				}
+			}
+		}
+		index[strings.ToLower(a.Code)] = versions
+	}
+	return index
+}
+
+// Check validates a job against the catalog: core type, analysis code,
+// analysis version and the version's allowed core types, and project ID.
+func (c *Catalog) Check(job models.JobSpec) []string {
+	var errs []string
+
+	coreTypeKnown := true
+	if c.coreTypes != nil && job.CoreType != "" {
+		if err := c.coreTypes.Validate(job.CoreType); err != nil {
+			errs = append(errs, err.Error())
+			coreTypeKnown = false
+		}
+	}
+
+	if c.analyses != nil && job.AnalysisCode != "" {
+		versions, ok := c.analyses[strings.ToLower(job.AnalysisCode)]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown analysis code %q", job.AnalysisCode))
+		} else if job.AnalysisVersion != "" {
+			allowed, ok := versions[job.AnalysisVersion]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("analysis %s has no version %q", job.AnalysisCode, job.AnalysisVersion))
+			} else if coreTypeKnown && len(allowed) > 0 && job.CoreType != "" && !containsFold(allowed, job.CoreType) {
+				errs = append(errs, fmt.Sprintf("core type %q is not available for %s %s", job.CoreType, job.AnalysisCode, job.AnalysisVersion))
+			}
+		}
+	}
+
+	if c.projects != nil && job.ProjectID != "" && !c.projects[job.ProjectID] {
+		errs = append(errs, fmt.Sprintf("project %q not found in organization", job.ProjectID))
+	}
+
+	return errs
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
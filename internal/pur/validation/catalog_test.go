@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+type fakeCatalogSource struct {
+	coreTypeCalls atomic.Int32
+	analysisCalls atomic.Int32
+	projectCalls  atomic.Int32
+	projectsErr   error
+	noCoreTypes   bool
+}
+
+func (f *fakeCatalogSource) GetCoreTypes(_ context.Context, _ bool) ([]models.CoreType, error) {
+	f.coreTypeCalls.Add(1)
+	if f.noCoreTypes {
+		return nil, nil
+	}
+	return []models.CoreType{{Code: "emerald"}, {Code: "onyx"}}, nil
+}
+
+func (f *fakeCatalogSource) GetAnalyses(_ context.Context) ([]models.Analysis, error) {
+	f.analysisCalls.Add(1)
+	var a models.Analysis
+	a.Code = "openfoam"
+	a.Versions = append(a.Versions, struct {
+		ID               string   `json:"id"`
+		Version          string   `json:"version,omitempty"`
+		VersionCode      string   `json:"versionCode,omitempty"`
+		AllowedCoreTypes []string `json:"allowedCoreTypes,omitempty"`
+	}{Version: "v2306", VersionCode: "2306", AllowedCoreTypes: []string{"emerald"}})
+	return []models.Analysis{a}, nil
+}
+
+func (f *fakeCatalogSource) GetProjects(_ context.Context, _ string) ([]models.Project, error) {
+	f.projectCalls.Add(1)
+	if f.projectsErr != nil {
+		return nil, f.projectsErr
+	}
+	return []models.Project{{ID: "proj1", Name: "Project 1"}}, nil
+}
+
+func TestCatalog_Check(t *testing.T) {
+	src := &fakeCatalogSource{}
+	cat, warnings := LoadCatalog(context.Background(), src, "ORG")
+	if len(warnings) != 0 {
+		t.Fatalf("LoadCatalog() warnings = %v", warnings)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*models.JobSpec)
+		wantErr string
+	}{
+		{"valid", func(j *models.JobSpec) {}, ""},
+		{"version by code", func(j *models.JobSpec) { j.AnalysisVersion = "2306" }, ""},
+		{"bad core type", func(j *models.JobSpec) { j.CoreType = "emerld" }, "invalid core type"},
+		{"unknown analysis", func(j *models.JobSpec) { j.AnalysisCode = "nope" }, "unknown analysis code"},
+		{"unknown version", func(j *models.JobSpec) { j.AnalysisVersion = "v1" }, "has no version"},
+		{"core type not allowed", func(j *models.JobSpec) { j.CoreType = "onyx" }, "not available for"},
+		{"unknown project", func(j *models.JobSpec) { j.ProjectID = "proj2" }, "project"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := models.JobSpec{
+				AnalysisCode:    "openfoam",
+				AnalysisVersion: "v2306",
+				CoreType:        "emerald",
+				ProjectID:       "proj1",
+			}
+			tt.modify(&job)
+			errs := cat.Check(job)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("Check() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("Check() = %v, want one error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+
+	// Checking many jobs reuses the catalog without further API calls
+	for i := 0; i < 100; i++ {
+		cat.Check(models.JobSpec{CoreType: "emerald", AnalysisCode: "openfoam"})
+	}
+	if src.coreTypeCalls.Load() != 1 || src.analysisCalls.Load() != 1 || src.projectCalls.Load() != 1 {
+		t.Errorf("API calls = %d/%d/%d, want 1/1/1",
+			src.coreTypeCalls.Load(), src.analysisCalls.Load(), src.projectCalls.Load())
+	}
+}
+
+func TestLoadCatalog_PartialFailure(t *testing.T) {
+	src := &fakeCatalogSource{projectsErr: errors.New("forbidden")}
+	cat, warnings := LoadCatalog(context.Background(), src, "ORG")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "projects") {
+		t.Fatalf("warnings = %v, want one projects warning", warnings)
+	}
+	// Project checks are skipped; the rest still run
+	if errs := cat.Check(models.JobSpec{CoreType: "emerald", ProjectID: "anything"}); len(errs) != 0 {
+		t.Errorf("Check() = %v, want no errors", errs)
+	}
+	if errs := cat.Check(models.JobSpec{CoreType: "bogus"}); len(errs) != 1 {
+		t.Errorf("Check() = %v, want core type error", errs)
+	}
+}
+
+func TestLoadCatalog_NoOrgSkipsProjects(t *testing.T) {
+	src := &fakeCatalogSource{}
+	LoadCatalog(context.Background(), src, "")
+	if src.projectCalls.Load() != 0 {
+		t.Errorf("GetProjects called %d times without an org code", src.projectCalls.Load())
+	}
+}
+
+func TestLoadCatalog_NoCoreTypesSkipsCheck(t *testing.T) {
+	src := &fakeCatalogSource{noCoreTypes: true}
+	cat, warnings := LoadCatalog(context.Background(), src, "")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "no core types") {
+		t.Fatalf("warnings = %v, want one core types warning", warnings)
+	}
+	if errs := cat.Check(models.JobSpec{CoreType: "emerald"}); len(errs) != 0 {
+		t.Errorf("Check() = %v, want no errors", errs)
+	}
+}
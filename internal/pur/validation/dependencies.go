@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// ResolveDependencies maps the DependsOn names of a run's jobs to the jobs
+// they refer to. A name matching no job but the ArrayName of expanded array
+// members refers to all of them. The result is keyed by 0-based job index
+// and holds the 0-based indexes of the job's parents. Problems are returned
+// per job (0-based): a name that matches no job or several, a job depending
+// on itself, and every job on a dependency cycle.
+func ResolveDependencies(jobs []models.JobSpec) (map[int][]int, map[int][]string) {
+	rows := make(map[string][]int)
+	arrays := make(map[string][]int)
+	for i, job := range jobs {
+		if job.JobName != "" {
+			rows[job.JobName] = append(rows[job.JobName], i)
+		}
+		if job.ArrayName != "" {
+			arrays[job.ArrayName] = append(arrays[job.ArrayName], i)
+		}
+	}
+
+	parents := make(map[int][]int)
+	problems := make(map[int][]string)
+	for i, job := range jobs {
+		seen := make(map[int]bool)
+		for _, name := range job.DependsOn {
+			match := rows[name]
+			if members := arrays[name]; len(match) == 0 && len(members) > 0 {
+				if job.ArrayName == name {
+					problems[i] = append(problems[i], "DependsOn: a job cannot depend on its own array")
+					continue
+				}
+				for _, m := range members {
+					if !seen[m] {
+						seen[m] = true
+						parents[i] = append(parents[i], m)
+					}
+				}
+				continue
+			}
+			switch {
+			case len(match) == 0:
+				problems[i] = append(problems[i], fmt.Sprintf("DependsOn: no job named %q in this run", name))
+			case len(match) > 1:
+				problems[i] = append(problems[i], fmt.Sprintf("DependsOn: job name %q is used by %d rows", name, len(match)))
+			case match[0] == i:
+				problems[i] = append(problems[i], "DependsOn: a job cannot depend on itself")
+			case !seen[match[0]]:
+				seen[match[0]] = true
+				parents[i] = append(parents[i], match[0])
+			}
+		}
+	}
+
+	for i, cycle := range findCycles(jobs, parents) {
+		problems[i] = append(problems[i], "DependsOn: dependency cycle "+cycle)
+	}
+	return parents, problems
+}
+
+// findCycles returns, for every job on a dependency cycle, the cycle as
+// "a → b → a" (a depends on b, which depends on a).
+func findCycles(jobs []models.JobSpec, parents map[int][]int) map[int]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	mark := make(map[int]int)
+	cycles := make(map[int]string)
+	var stack []int
+
+	var visit func(i int)
+	visit = func(i int) {
+		mark[i] = visiting
+		stack = append(stack, i)
+		for _, parent := range parents[i] {
+			switch mark[parent] {
+			case unvisited:
+				visit(parent)
+			case visiting:
+				// The stack from parent onwards is a cycle
+				start := len(stack) - 1
+				for stack[start] != parent {
+					start--
+				}
+				names := make([]string, 0, len(stack)-start+1)
+				for _, j := range stack[start:] {
+					names = append(names, jobs[j].JobName)
+				}
+				names = append(names, jobs[parent].JobName)
+				for _, j := range stack[start:] {
+					if _, ok := cycles[j]; !ok {
+						cycles[j] = strings.Join(names, " → ")
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		mark[i] = done
+	}
+	for i := range jobs {
+		if mark[i] == unvisited {
+			visit(i)
+		}
+	}
+	return cycles
+}
+
+// renameDependencies points DependsOn references to oldName at newName.
+func renameDependencies(jobs []models.JobSpec, oldName, newName string) {
+	for i := range jobs {
+		for k, name := range jobs[i].DependsOn {
+			if name == oldName {
+				jobs[i].DependsOn[k] = newName
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	jobs := namedJobs("mesh", "solve", "post")
+	jobs[1].DependsOn = []string{"mesh"}
+	jobs[2].DependsOn = []string{"solve", "mesh", "solve"}
+
+	parents, problems := ResolveDependencies(jobs)
+	if len(problems) != 0 {
+		t.Fatalf("problems = %v", problems)
+	}
+	if len(parents[0]) != 0 || len(parents[1]) != 1 || parents[1][0] != 0 {
+		t.Errorf("parents = %v", parents)
+	}
+	if len(parents[2]) != 2 || parents[2][0] != 1 || parents[2][1] != 0 {
+		t.Errorf("parents of post = %v, want [1 0]", parents[2])
+	}
+}
+
+func TestResolveDependencies_Problems(t *testing.T) {
+	jobs := namedJobs("a", "b", "c", "d", "d", "e")
+	jobs[0].DependsOn = []string{"b"}
+	jobs[1].DependsOn = []string{"c"}
+	jobs[2].DependsOn = []string{"a"}
+	jobs[3].DependsOn = []string{"missing"}
+	jobs[4].DependsOn = []string{"d"}
+	jobs[5].DependsOn = []string{"e"}
+
+	_, problems := ResolveDependencies(jobs)
+	for i, want := range map[int]string{
+		0: "dependency cycle a → b → c → a",
+		1: "dependency cycle a → b → c → a",
+		2: "dependency cycle a → b → c → a",
+		3: `no job named "missing"`,
+		4: `"d" is used by 2 rows`,
+		5: "cannot depend on itself",
+	} {
+		if len(problems[i]) != 1 || !strings.Contains(problems[i][0], want) {
+			t.Errorf("job %d problems = %v, want %q", i, problems[i], want)
+		}
+	}
+}
+
+func TestApplyNamePolicy_SuffixRenamesDependencies(t *testing.T) {
+	jobs := namedJobs("mesh", "solve")
+	jobs[1].DependsOn = []string{"mesh"}
+	collisions := []NameCollision{{Name: "mesh", Rows: []int{1}, ExistingJobIDs: []string{"AbCdE"}}}
+
+	if _, err := ApplyNamePolicy(jobs, collisions, NamePolicySuffix, NameSuffix("", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))); err != nil {
+		t.Fatal(err)
+	}
+	if jobs[0].JobName != "mesh_20260102-030405" || jobs[1].DependsOn[0] != jobs[0].JobName {
+		t.Errorf("jobs = %+v, want the dependency to follow the rename", jobs)
+	}
+}
+
+func TestResolveDependencies_Array(t *testing.T) {
+	jobs := namedJobs("case_1", "case_2", "post")
+	jobs[0].ArrayName, jobs[1].ArrayName = "case_{{index}}", "case_{{index}}"
+	jobs[1].DependsOn = []string{"case_{{index}}"}
+	jobs[2].DependsOn = []string{"case_{{index}}"}
+
+	parents, problems := ResolveDependencies(jobs)
+	if len(parents[2]) != 2 || parents[2][0] != 0 || parents[2][1] != 1 {
+		t.Errorf("parents of post = %v, want every member of the array", parents[2])
+	}
+	if len(problems) != 1 || !strings.Contains(problems[1][0], "its own array") {
+		t.Errorf("problems = %v", problems)
+	}
+}
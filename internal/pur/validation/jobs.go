@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// JobResult is the outcome of validating one job with ValidateJobs.
+type JobResult struct {
+	Errors   []string
+	Warnings []string
+}
+
+// JobCheck adds caller-specific checks to ValidateJobs, such as whether the
+// job's directory exists. It runs on a worker goroutine.
+type JobCheck func(job models.JobSpec) (errs, warnings []string)
+
+// ValidateJobs validates jobs in parallel with up to
+// constants.PlanValidationWorkers workers: ValidateJobSpecMode in mode,
+// catalog.Check when catalog is non-nil, then extra when non-nil. Results
+// are returned in job order. onDone, when non-nil, is called as each job
+// finishes with its index, its result and the number finished so far; it
+// may be called concurrently.
+//
+// Used by both pur plan and the GUI plan so the two validate the same way.
+func ValidateJobs(jobs []models.JobSpec, mode string, catalog *Catalog, extra JobCheck, onDone func(i int, res JobResult, done int)) []JobResult {
+	results := make([]JobResult, len(jobs))
+	var finished int64
+	var wg sync.WaitGroup
+	work := make(chan int)
+
+	workers := constants.PlanValidationWorkers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				job := jobs[i]
+				res := &results[i]
+				res.Errors, res.Warnings = ValidateJobSpecMode(job, mode)
+				if catalog != nil {
+					res.Errors = append(res.Errors, catalog.Check(job)...)
+				}
+				if extra != nil {
+					errs, warnings := extra(job)
+					res.Errors = append(res.Errors, errs...)
+					res.Warnings = append(res.Warnings, warnings...)
+				}
+				n := atomic.AddInt64(&finished, 1)
+				if onDone != nil {
+					onDone(i, *res, int(n))
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestValidateJobs(t *testing.T) {
+	cat, _ := LoadCatalog(context.Background(), &fakeCatalogSource{}, "")
+
+	var jobs []models.JobSpec
+	for i := 0; i < 50; i++ {
+		job := validJob()
+		job.JobName = fmt.Sprintf("job_%d", i)
+		job.AnalysisCode = "openfoam"
+		if i%10 == 3 {
+			job.CoreType = "emerld"
+		}
+		jobs = append(jobs, job)
+	}
+
+	var calls atomic.Int32
+	extra := func(job models.JobSpec) ([]string, []string) {
+		return nil, []string{"checked " + job.JobName}
+	}
+	results := ValidateJobs(jobs, SafetyPermissive, cat, extra, func(i int, res JobResult, done int) {
+		calls.Add(1)
+	})
+
+	if int(calls.Load()) != len(jobs) {
+		t.Errorf("onDone called %d times, want %d", calls.Load(), len(jobs))
+	}
+	for i, res := range results {
+		if len(res.Warnings) != 1 || res.Warnings[0] != "checked "+jobs[i].JobName {
+			t.Fatalf("results[%d].Warnings = %v, want result of job %s", i, res.Warnings, jobs[i].JobName)
+		}
+		if i%10 == 3 {
+			if len(res.Errors) != 1 || !strings.Contains(res.Errors[0], "invalid core type") {
+				t.Errorf("results[%d].Errors = %v, want one core type error", i, res.Errors)
+			}
+		} else if len(res.Errors) != 0 {
+			t.Errorf("results[%d].Errors = %v, want none", i, res.Errors)
+		}
+	}
+}
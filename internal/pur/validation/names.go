@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Job name uniqueness policies. Rescale accepts duplicate job names, which
+// makes archived outputs hard to tell apart; the policy decides what a run
+// does about names it would duplicate.
+const (
+	// NamePolicyWarn reports duplicate names and runs anyway. This is the default.
+	NamePolicyWarn = "warn"
+
+	// NamePolicySuffix renames duplicates by appending the run ID or a timestamp.
+	NamePolicySuffix = "suffix"
+
+	// NamePolicyBlock refuses to run while duplicate names exist.
+	NamePolicyBlock = "block"
+)
+
+// NormalizeNamePolicy maps a config value to one of the NamePolicy constants.
+func NormalizeNamePolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case NamePolicySuffix:
+		return NamePolicySuffix
+	case NamePolicyBlock:
+		return NamePolicyBlock
+	default:
+		return NamePolicyWarn
+	}
+}
+
+// NameCollision is a job name used more than once within a run (Rows, 1-based)
+// or already used by the user's recent jobs (ExistingJobIDs).
+type NameCollision struct {
+	Name           string
+	Rows           []int
+	ExistingJobIDs []string
+}
+
+func (c NameCollision) String() string {
+	var parts []string
+	if len(c.Rows) > 1 {
+		rows := make([]string, len(c.Rows))
+		for i, r := range c.Rows {
+			rows[i] = fmt.Sprint(r)
+		}
+		parts = append(parts, "rows "+strings.Join(rows, ", "))
+	}
+	if len(c.ExistingJobIDs) > 0 {
+		parts = append(parts, "existing jobs "+strings.Join(c.ExistingJobIDs, ", "))
+	}
+	return fmt.Sprintf("duplicate job name %q (%s)", c.Name, strings.Join(parts, "; "))
+}
+
+// RecentJobLister is the subset of the API client needed to look up the
+// user's recent job names (satisfied by *api.Client).
+type RecentJobLister interface {
+	ListJobsWithCutoff(ctx context.Context, cutoff time.Time) ([]models.JobResponse, error)
+}
+
+// RecentJobNames returns the IDs of the user's jobs created within window,
+// keyed by job name.
+func RecentJobNames(ctx context.Context, lister RecentJobLister, window time.Duration) (map[string][]string, error) {
+	jobs, err := lister.ListJobsWithCutoff(ctx, time.Now().Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent jobs: %w", err)
+	}
+	names := make(map[string][]string)
+	for _, j := range jobs {
+		if j.Name != "" {
+			names[j.Name] = append(names[j.Name], j.ID)
+		}
+	}
+	return names, nil
+}
+
+// FindNameCollisions returns the names in jobs that repeat within the run or
+// appear in existing (from RecentJobNames; may be nil). Collisions are in
+// order of first appearance; unnamed jobs are ignored.
+func FindNameCollisions(jobs []models.JobSpec, existing map[string][]string) []NameCollision {
+	rows := make(map[string][]int)
+	var order []string
+	for i, job := range jobs {
+		if job.JobName == "" {
+			continue
+		}
+		if _, ok := rows[job.JobName]; !ok {
+			order = append(order, job.JobName)
+		}
+		rows[job.JobName] = append(rows[job.JobName], i+1)
+	}
+
+	var collisions []NameCollision
+	for _, name := range order {
+		ids := existing[name]
+		if len(rows[name]) > 1 || len(ids) > 0 {
+			collisions = append(collisions, NameCollision{Name: name, Rows: rows[name], ExistingJobIDs: ids})
+		}
+	}
+	return collisions
+}
+
+// NameSuffix returns the suffix NamePolicySuffix appends: the run ID when
+// known, else a timestamp.
+func NameSuffix(runID string, now time.Time) string {
+	if runID != "" {
+		return runID
+	}
+	return now.Format("20060102-150405")
+}
+
+// ApplyNamePolicy acts on collisions found by FindNameCollisions. With
+// NamePolicyBlock it returns an error listing them; with NamePolicyWarn it
+// returns them as warnings. With NamePolicySuffix jobs are renamed in place
+// to name_<suffix> (plus _2, _3, ... when several rows need it) and the
+// renames are returned as warnings. Within a run the first row keeps its
+// name unless an existing job already uses it; DependsOn references to a
+// job that was the only one of its name follow its rename.
+func ApplyNamePolicy(jobs []models.JobSpec, collisions []NameCollision, policy, suffix string) ([]string, error) {
+	if len(collisions) == 0 {
+		return nil, nil
+	}
+
+	var messages []string
+	for _, c := range collisions {
+		messages = append(messages, c.String())
+	}
+
+	switch NormalizeNamePolicy(policy) {
+	case NamePolicyBlock:
+		return nil, fmt.Errorf("job names must be unique (job_name_policy=block):\n  %s", strings.Join(messages, "\n  "))
+	case NamePolicyWarn:
+		return messages, nil
+	}
+
+	taken := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		taken[job.JobName] = true
+	}
+
+	var renames []string
+	for _, c := range collisions {
+		rename := c.Rows
+		if len(c.ExistingJobIDs) == 0 {
+			rename = c.Rows[1:]
+		}
+		for k, row := range rename {
+			base := c.Name + "_" + suffix
+			if len(rename) > 1 {
+				base = fmt.Sprintf("%s_%d", base, k+1)
+			}
+			name := base
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s_%d", base, n)
+			}
+			taken[name] = true
+			jobs[row-1].JobName = name
+			if len(c.Rows) == 1 {
+				// The name was unique within the run, so dependencies on it
+				// follow the rename
+				renameDependencies(jobs, c.Name, name)
+			}
+			renames = append(renames, fmt.Sprintf("row %d renamed %q → %q (%s)", row, c.Name, name, c))
+		}
+	}
+	return renames, nil
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func namedJobs(names ...string) []models.JobSpec {
+	jobs := make([]models.JobSpec, len(names))
+	for i, n := range names {
+		jobs[i] = models.JobSpec{JobName: n}
+	}
+	return jobs
+}
+
+type fakeRecentJobs []models.JobResponse
+
+func (f fakeRecentJobs) ListJobsWithCutoff(ctx context.Context, cutoff time.Time) ([]models.JobResponse, error) {
+	return f, nil
+}
+
+func TestFindNameCollisions(t *testing.T) {
+	existing, err := RecentJobNames(context.Background(), fakeRecentJobs{
+		{ID: "AbCdE", Name: "run_3"},
+		{ID: "FgHiJ", Name: "other"},
+	}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FindNameCollisions(namedJobs("run_1", "run_2", "run_1", "run_3", ""), existing)
+	if len(got) != 2 {
+		t.Fatalf("got %d collisions, want 2: %v", len(got), got)
+	}
+	if got[0].Name != "run_1" || len(got[0].Rows) != 2 || got[0].Rows[1] != 3 {
+		t.Errorf("within-run collision = %+v", got[0])
+	}
+	if got[1].Name != "run_3" || len(got[1].ExistingJobIDs) != 1 || got[1].ExistingJobIDs[0] != "AbCdE" {
+		t.Errorf("existing-job collision = %+v", got[1])
+	}
+}
+
+func TestApplyNamePolicy(t *testing.T) {
+	existing := map[string][]string{"run_3": {"AbCdE"}}
+
+	t.Run("warn", func(t *testing.T) {
+		jobs := namedJobs("run_1", "run_1")
+		warnings, err := ApplyNamePolicy(jobs, FindNameCollisions(jobs, nil), NamePolicyWarn, "x")
+		if err != nil || len(warnings) != 1 || jobs[1].JobName != "run_1" {
+			t.Errorf("warn: warnings=%v err=%v jobs=%v", warnings, err, jobs)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		jobs := namedJobs("run_1", "run_3")
+		_, err := ApplyNamePolicy(jobs, FindNameCollisions(jobs, existing), "BLOCK", "x")
+		if err == nil || !strings.Contains(err.Error(), "run_3") {
+			t.Errorf("block: err = %v", err)
+		}
+	})
+
+	t.Run("suffix", func(t *testing.T) {
+		jobs := namedJobs("run_1", "run_1", "run_1", "run_3", "run_1_r7_2")
+		renames, err := ApplyNamePolicy(jobs, FindNameCollisions(jobs, existing), NamePolicySuffix, "r7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"run_1", "run_1_r7_1", "run_1_r7_2_2", "run_3_r7", "run_1_r7_2"}
+		for i, w := range want {
+			if jobs[i].JobName != w {
+				t.Errorf("job %d = %q, want %q", i+1, jobs[i].JobName, w)
+			}
+		}
+		if len(renames) != 3 {
+			t.Errorf("renames = %v", renames)
+		}
+	})
+}
+
+func TestNameSuffix(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := NameSuffix("", now); got != "20260304-050607" {
+		t.Errorf("NameSuffix(\"\") = %q", got)
+	}
+	if got := NameSuffix("study_a", now); got != "study_a" {
+		t.Errorf("NameSuffix(run) = %q", got)
+	}
+}
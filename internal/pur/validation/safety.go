@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// SafetyMode controls how suspicious job spec constructs are treated.
+const (
+	// SafetyPermissive reports suspicious constructs as warnings; only path
+	// traversal and NUL bytes block the job. This is the default.
+	SafetyPermissive = "permissive"
+
+	// SafetyStrict turns every suspicious construct into a validation error.
+	SafetyStrict = "strict"
+)
+
+// SafetyFinding is one suspicious construct in a job spec. Blocking findings
+// are errors in every mode; the rest are errors only in strict mode.
+type SafetyFinding struct {
+	Field    string
+	Message  string
+	Blocking bool
+}
+
+func (f SafetyFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Field, f.Message)
+}
+
+// NormalizeSafetyMode maps a config value to SafetyStrict or SafetyPermissive.
+func NormalizeSafetyMode(mode string) string {
+	if strings.EqualFold(strings.TrimSpace(mode), SafetyStrict) {
+		return SafetyStrict
+	}
+	return SafetyPermissive
+}
+
+// CheckJobSafety flags constructs in CSV-sourced values that are usually
+// mistakes or injection attempts: TarSubpath traversal, control characters in
+// names, and commands with unbalanced quotes, embedded line breaks or shell
+// command substitution.
+func CheckJobSafety(job models.JobSpec) []SafetyFinding {
+	var findings []SafetyFinding
+
+	if job.TarSubpath != "" {
+		if msg := subpathProblem(job.TarSubpath); msg != "" {
+			findings = append(findings, SafetyFinding{Field: "TarSubpath", Message: msg, Blocking: true})
+		}
+	}
+
+	names := []struct {
+		field string
+		value string
+	}{
+		{"JobName", job.JobName},
+		{"AnalysisCode", job.AnalysisCode},
+		{"AnalysisVersion", job.AnalysisVersion},
+		{"CoreType", job.CoreType},
+		{"ProjectID", job.ProjectID},
+		{"DestinationFolder", job.DestinationFolder},
+		{"TarSubpath", job.TarSubpath},
+	}
+	for _, tag := range job.Tags {
+		names = append(names, struct {
+			field string
+			value string
+		}{"Tags", tag})
+	}
+	for _, k := range job.MetadataKeys() {
+		names = append(names, struct {
+			field string
+			value string
+		}{models.MetadataPrefix + k, k + job.Metadata[k]})
+	}
+	for _, n := range names {
+		if strings.ContainsRune(n.value, 0) {
+			findings = append(findings, SafetyFinding{Field: n.field, Message: "contains a NUL byte", Blocking: true})
+		} else if hasControlChar(n.value, false) {
+			findings = append(findings, SafetyFinding{Field: n.field, Message: fmt.Sprintf("contains control characters: %q", n.value)})
+		}
+	}
+
+	findings = append(findings, commandFindings(job.Command)...)
+	return findings
+}
+
+// commandFindings checks a job command line.
+func commandFindings(cmd string) []SafetyFinding {
+	var findings []SafetyFinding
+	add := func(msg string, blocking bool) {
+		findings = append(findings, SafetyFinding{Field: "Command", Message: msg, Blocking: blocking})
+	}
+
+	if strings.ContainsRune(cmd, 0) {
+		add("contains a NUL byte", true)
+	}
+	if strings.ContainsAny(cmd, "\r\n") {
+		add("contains a line break; each line runs as a separate command", false)
+	}
+	if hasControlChar(cmd, true) {
+		add("contains control characters", false)
+	}
+	if q := unbalancedQuote(cmd); q != 0 {
+		add(fmt.Sprintf("has an unbalanced %c quote", q), false)
+	}
+	if strings.Contains(cmd, "`") || strings.Contains(cmd, "$(") {
+		add("uses shell command substitution (` or $( )", false)
+	}
+	return findings
+}
+
+// subpathProblem returns why a TarSubpath would leave the run directory, or "".
+func subpathProblem(subpath string) string {
+	if filepath.IsAbs(subpath) || strings.HasPrefix(subpath, "/") || strings.HasPrefix(subpath, `\`) ||
+		filepath.VolumeName(subpath) != "" {
+		return fmt.Sprintf("%q must be relative to the run directory", subpath)
+	}
+	clean := filepath.Clean(filepath.FromSlash(strings.ReplaceAll(subpath, `\`, "/")))
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Sprintf("%q escapes the run directory", subpath)
+	}
+	return ""
+}
+
+// hasControlChar reports control characters other than tab. allowLineBreaks
+// skips \r and \n, which commandFindings reports separately.
+func hasControlChar(s string, allowLineBreaks bool) bool {
+	for _, r := range s {
+		if r == '\t' || r == 0 || (allowLineBreaks && (r == '\n' || r == '\r')) {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// unbalancedQuote returns the quote character left open at the end of a
+// POSIX shell command line, or 0 if quoting is balanced.
+func unbalancedQuote(cmd string) rune {
+	var open rune
+	escaped := false
+	for _, r := range cmd {
+		switch {
+		case escaped:
+			escaped = false
+		case open == '\'':
+			if r == '\'' {
+				open = 0
+			}
+		case r == '\\':
+			escaped = true
+		case open == '"':
+			if r == '"' {
+				open = 0
+			}
+		case r == '\'' || r == '"':
+			open = r
+		}
+	}
+	return open
+}
+
+// ValidateJobSpecMode runs ValidateJobFields plus ValidateJobSafety.
+func ValidateJobSpecMode(job models.JobSpec, mode string) (errs []string, warnings []string) {
+	errs, warnings = ValidateJobSafety(job, mode)
+	return append(ValidateJobFields(job), errs...), warnings
+}
+
+// ValidateJobSafety runs the safety checks (CheckJobSafety). In strict mode
+// every finding is an error; in permissive mode non-blocking findings are
+// returned as warnings.
+func ValidateJobSafety(job models.JobSpec, mode string) (errs []string, warnings []string) {
+	strict := NormalizeSafetyMode(mode) == SafetyStrict
+	for _, f := range CheckJobSafety(job) {
+		if f.Blocking || strict {
+			errs = append(errs, f.String())
+		} else {
+			warnings = append(warnings, f.String())
+		}
+	}
+	return errs, warnings
+}
@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func validJob() models.JobSpec {
+	return models.JobSpec{
+		JobName:       "run_1",
+		Directory:     "/data/run_1",
+		AnalysisCode:  "user_included",
+		Command:       `./run.sh --name "case 1" 'x y'`,
+		CoreType:      "emerald",
+		CoresPerSlot:  4,
+		Slots:         1,
+		WalltimeHours: 1,
+	}
+}
+
+func TestCheckJobSafety_CleanJob(t *testing.T) {
+	job := validJob()
+	job.TarSubpath = "inputs/mesh"
+	job.Command = `echo "it's fine" && ./run.sh \"quoted\"`
+	if findings := CheckJobSafety(job); len(findings) != 0 {
+		t.Errorf("CheckJobSafety() = %v, want none", findings)
+	}
+}
+
+func TestCheckJobSafety(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*models.JobSpec)
+		field    string
+		blocking bool
+	}{
+		{"subpath traversal", func(j *models.JobSpec) { j.TarSubpath = "../other" }, "TarSubpath", true},
+		{"subpath nested traversal", func(j *models.JobSpec) { j.TarSubpath = "a/../../b" }, "TarSubpath", true},
+		{"subpath absolute", func(j *models.JobSpec) { j.TarSubpath = "/etc" }, "TarSubpath", true},
+		{"subpath backslash traversal", func(j *models.JobSpec) { j.TarSubpath = `..\other` }, "TarSubpath", true},
+		{"NUL in name", func(j *models.JobSpec) { j.JobName = "run\x00" }, "JobName", true},
+		{"control char in name", func(j *models.JobSpec) { j.JobName = "run\x1b[31m" }, "JobName", false},
+		{"control char in tag", func(j *models.JobSpec) { j.Tags = []string{"ok", "bad\x07"} }, "Tags", false},
+		{"unbalanced double quote", func(j *models.JobSpec) { j.Command = `./run.sh "case 1` }, "Command", false},
+		{"unbalanced single quote", func(j *models.JobSpec) { j.Command = `./run.sh 'case` }, "Command", false},
+		{"line break", func(j *models.JobSpec) { j.Command = "./run.sh\nrm -rf ~" }, "Command", false},
+		{"backtick substitution", func(j *models.JobSpec) { j.Command = "./run.sh `whoami`" }, "Command", false},
+		{"dollar substitution", func(j *models.JobSpec) { j.Command = "./run.sh $(curl x)" }, "Command", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := validJob()
+			tt.modify(&job)
+			findings := CheckJobSafety(job)
+			if len(findings) == 0 {
+				t.Fatal("CheckJobSafety() returned no findings")
+			}
+			f := findings[0]
+			if f.Field != tt.field || f.Blocking != tt.blocking {
+				t.Errorf("finding = %+v, want field %s blocking=%v", f, tt.field, tt.blocking)
+			}
+		})
+	}
+}
+
+func TestValidateJobSpecMode(t *testing.T) {
+	job := validJob()
+	job.Command = "./run.sh `whoami`"
+
+	errs, warnings := ValidateJobSpecMode(job, SafetyPermissive)
+	if len(errs) != 0 || len(warnings) != 1 {
+		t.Errorf("permissive: errs=%v warnings=%v, want 0 errors and 1 warning", errs, warnings)
+	}
+
+	errs, warnings = ValidateJobSpecMode(job, "STRICT")
+	if len(errs) != 1 || len(warnings) != 0 {
+		t.Errorf("strict: errs=%v warnings=%v, want 1 error and 0 warnings", errs, warnings)
+	}
+
+	job = validJob()
+	job.TarSubpath = "../escape"
+	errs = ValidateJobSpec(job)
+	if len(errs) != 1 || !strings.Contains(errs[0], "TarSubpath") {
+		t.Errorf("ValidateJobSpec() = %v, want TarSubpath error", errs)
+	}
+}
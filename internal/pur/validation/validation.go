@@ -6,7 +6,10 @@
 //
 // Features:
 //   - ValidateJobSpec: shared job validation for CLI and GUI
+//   - CheckJobSafety: traversal, control character and command injection checks
+//     (strict or permissive, see ValidateJobSpecMode)
 //   - CoreTypeValidator: API-based hardware validation with caching
+//   - Catalog: batched core type, analysis and project checks for many jobs
 //   - Suggestions for typos (e.g., "emerld" -> "emerald")
 //   - Thread-safe with concurrent access support
 package validation
@@ -19,13 +22,24 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
 )
 
 // ValidateJobSpec validates a job specification, returning a list of errors.
 // Shared validation used by both CLI (plan command) and GUI (ValidateJobSpec binding).
+// Blocking safety findings (see CheckJobSafety) are included; use
+// ValidateJobSpecMode for strict mode or to get the non-blocking warnings.
 func ValidateJobSpec(job models.JobSpec) []string {
+	errs, _ := ValidateJobSpecMode(job, SafetyPermissive)
+	return errs
+}
+
+// ValidateJobFields checks required fields and value ranges, without the
+// safety checks (see ValidateJobSafety).
+func ValidateJobFields(job models.JobSpec) []string {
 	var errors []string
 
 	if job.JobName == "" {
@@ -62,6 +76,16 @@ func ValidateJobSpec(job models.JobSpec) []string {
 		}
 	}
 
+	if job.DestinationFolder != "" {
+		if _, err := folder.SplitFolderPath(job.DestinationFolder); err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid destination folder: %v", err))
+		}
+	}
+
+	if _, err := config.JobEnvironment(job); err != nil {
+		errors = append(errors, fmt.Sprintf("Invalid environment: %v", err))
+	}
+
 	return errors
 }
 
@@ -84,6 +108,21 @@ func NewCoreTypeValidator(client *api.Client) *CoreTypeValidator {
 	}
 }
 
+// newCoreTypeValidatorFrom creates a validator preloaded with core types
+// fetched elsewhere (see LoadCatalog). It has no client and never refetches.
+func newCoreTypeValidatorFrom(coreTypes []models.CoreType) *CoreTypeValidator {
+	v := &CoreTypeValidator{
+		coreTypes:   coreTypes,
+		coreTypeMap: make(map[string]bool, len(coreTypes)),
+		cacheTTL:    constants.ValidationCacheTTL,
+		lastFetch:   time.Now(),
+	}
+	for _, ct := range coreTypes {
+		v.coreTypeMap[strings.ToLower(ct.Code)] = true
+	}
+	return v
+}
+
 // FetchCoreTypes fetches available core types from API
 func (v *CoreTypeValidator) FetchCoreTypes(ctx context.Context) error {
 	v.mu.Lock()
@@ -4,8 +4,10 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/rescale/rescale-int/internal/diagnostics"
 	"github.com/rescale/rescale-int/internal/events"
 	intfips "github.com/rescale/rescale-int/internal/fips"
+	"github.com/rescale/rescale-int/internal/pur/repro"
 	"github.com/rescale/rescale-int/internal/version"
 )
 
@@ -45,6 +47,12 @@ type ErrorReport struct {
 
 	// User note (GUI only — CLI has no modal)
 	UserNote string `json:"userNote,omitempty"`
+
+	// Most recent network test from Settings (GUI only, if one was run)
+	NetworkTest *diagnostics.NetworkTestResult `json:"networkTest,omitempty"`
+
+	// Reproducibility bundle of the most recent PUR run (GUI only, if one ran)
+	Repro *repro.Bundle `json:"repro,omitempty"`
 }
 
 // Builder assembles ErrorReports from classified errors and pre-snapshotted timelines.
@@ -0,0 +1,166 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// BlackoutWindow is a daily period during which tar and upload work pauses,
+// e.g. while NAS backups saturate the disks. Times are minutes after local
+// midnight; a window whose end is before its start runs past midnight.
+type BlackoutWindow struct {
+	Start int
+	End   int
+}
+
+func (w BlackoutWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// contains reports whether minute-of-day m falls in the window.
+func (w BlackoutWindow) contains(m int) bool {
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+// endAfter returns when the window containing now closes. Wall-clock times
+// are built with time.Date, so windows keep their local times across DST
+// changes.
+func (w BlackoutWindow) endAfter(now time.Time) time.Time {
+	y, mo, d := now.Date()
+	if w.Start > w.End && now.Hour()*60+now.Minute() >= w.Start {
+		d++ // Closes tomorrow
+	}
+	return time.Date(y, mo, d, w.End/60, w.End%60, 0, 0, now.Location())
+}
+
+// ParseBlackoutWindows parses the blackout_windows setting: HH:MM-HH:MM
+// ranges in local time separated by semicolons or commas, e.g.
+// "01:00-03:00;22:30-23:00". An empty spec means no windows.
+func ParseBlackoutWindows(spec string) ([]BlackoutWindow, error) {
+	var windows []BlackoutWindow
+	for _, field := range strings.FieldsFunc(spec, func(r rune) bool { return r == ';' || r == ',' }) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(strings.ReplaceAll(field, "–", "-"), "-")
+		if !ok {
+			return nil, fmt.Errorf("blackout window %q: expected HH:MM-HH:MM", field)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %q: %w", field, err)
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %q: %w", field, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("blackout window %q: start and end are the same", field)
+		}
+		windows = append(windows, BlackoutWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+// parseClock parses HH:MM (00:00-24:00) into minutes after midnight.
+func parseClock(s string) (int, error) {
+	var h, m int
+	s = strings.TrimSpace(s)
+	if n, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || n != 2 {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return (h*60 + m) % (24 * 60), nil
+}
+
+// blackoutSchedule holds the process-wide windows, like cpuBudget holds the
+// encryption budget.
+type blackoutSchedule struct {
+	mu      sync.Mutex
+	windows []BlackoutWindow
+}
+
+var transferBlackout = &blackoutSchedule{}
+
+// SetBlackoutWindows parses spec (see ParseBlackoutWindows) and applies it to
+// every tar and upload in the process. On error the current windows are kept.
+func SetBlackoutWindows(spec string) error {
+	windows, err := ParseBlackoutWindows(spec)
+	if err != nil {
+		return err
+	}
+	transferBlackout.mu.Lock()
+	transferBlackout.windows = windows
+	transferBlackout.mu.Unlock()
+	return nil
+}
+
+// BlackoutUntil returns when the blackout window containing now ends, or the
+// zero time if now is outside every window. Adjoining or overlapping windows
+// are treated as one.
+func BlackoutUntil(now time.Time) time.Time {
+	transferBlackout.mu.Lock()
+	windows := transferBlackout.windows
+	transferBlackout.mu.Unlock()
+	return blackoutUntil(windows, now)
+}
+
+func blackoutUntil(windows []BlackoutWindow, now time.Time) time.Time {
+	var until time.Time
+	at := now
+	// Each pass can only move into a window not yet visited
+	for range windows {
+		extended := false
+		m := at.Hour()*60 + at.Minute()
+		for _, w := range windows {
+			if w.contains(m) {
+				if end := w.endAfter(at); end.After(at) && end.After(until) {
+					until = end
+					extended = true
+				}
+			}
+		}
+		if !extended {
+			break
+		}
+		at = until
+	}
+	return until
+}
+
+// WaitForBlackout blocks while the current time is inside a blackout window.
+// onWait, if non-nil, is called once with the end of the window when waiting
+// starts. The schedule is re-checked every constants.BlackoutCheckInterval so
+// edited windows and clock changes take effect. Returns ctx.Err() if ctx is
+// cancelled first.
+func WaitForBlackout(ctx context.Context, onWait func(until time.Time)) error {
+	notified := false
+	for {
+		until := BlackoutUntil(time.Now())
+		if until.IsZero() {
+			return nil
+		}
+		if !notified && onWait != nil {
+			onWait(until)
+		}
+		notified = true
+
+		wait := min(time.Until(until), constants.BlackoutCheckInterval)
+		select {
+		case <-time.After(max(wait, time.Second)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
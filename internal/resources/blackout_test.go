@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseBlackoutWindows(t *testing.T) {
+	windows, err := ParseBlackoutWindows(" 01:00-03:00; 22:30–0:15 ,,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BlackoutWindow{{60, 180}, {22*60 + 30, 15}}
+	if len(windows) != len(want) || windows[0] != want[0] || windows[1] != want[1] {
+		t.Fatalf("windows = %v, want %v", windows, want)
+	}
+	if got := windows[1].String(); got != "22:30-00:15" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, spec := range []string{"01:00", "1-3", "01:00-01:00", "25:00-26:00", "01:60-02:00", "a:b-c:d"} {
+		if _, err := ParseBlackoutWindows(spec); err == nil {
+			t.Errorf("ParseBlackoutWindows(%q) succeeded, want error", spec)
+		}
+	}
+	if windows, err := ParseBlackoutWindows(""); err != nil || windows != nil {
+		t.Errorf("empty spec = %v, %v", windows, err)
+	}
+}
+
+func TestBlackoutUntil(t *testing.T) {
+	windows, err := ParseBlackoutWindows("01:00-03:00;03:00-03:30;22:00-00:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{day(10, 0, 59), time.Time{}},
+		{day(10, 1, 0), day(10, 3, 30)}, // adjoining windows merge
+		{day(10, 3, 29), day(10, 3, 30)},
+		{day(10, 3, 30), time.Time{}},
+		{day(10, 23, 0), day(11, 0, 30)}, // past midnight
+		{day(11, 0, 10), day(11, 0, 30)},
+	}
+	for _, tt := range tests {
+		if got := blackoutUntil(windows, tt.now); !got.Equal(tt.want) {
+			t.Errorf("blackoutUntil(%s) = %s, want %s", tt.now.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestBlackoutUntil_DST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	windows, _ := ParseBlackoutWindows("01:00-04:00")
+	// Clocks go forward at 02:00 on 2026-03-29: the window is two hours long
+	now := time.Date(2026, 3, 29, 1, 30, 0, 0, loc)
+	until := blackoutUntil(windows, now)
+	if until.Hour() != 4 || until.Sub(now) != 90*time.Minute {
+		t.Errorf("until = %s (%v after now), want 04:00 local, 1h30m later", until, until.Sub(now))
+	}
+}
+
+func TestWaitForBlackout(t *testing.T) {
+	defer SetBlackoutWindows("")
+
+	if err := SetBlackoutWindows("bad"); err == nil {
+		t.Fatal("SetBlackoutWindows accepted an invalid spec")
+	}
+	if err := WaitForBlackout(context.Background(), func(time.Time) { t.Error("waited outside a window") }); err != nil {
+		t.Fatal(err)
+	}
+
+	// A window covering now, regardless of the time the test runs
+	now := time.Now()
+	start := (now.Hour()*60 + now.Minute() + 23*60) % (24 * 60)
+	end := (start + 120) % (24 * 60)
+	spec := BlackoutWindow{Start: start, End: end}.String()
+	if err := SetBlackoutWindows(spec); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var until time.Time
+	done := make(chan error, 1)
+	go func() {
+		done <- WaitForBlackout(ctx, func(u time.Time) { until = u; cancel() })
+	}()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForBlackout did not return after cancel")
+	}
+	if until.Before(now) {
+		t.Errorf("until = %s, want after now", until)
+	}
+}
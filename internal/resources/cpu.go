@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/platform"
+)
+
+// cpuBudget limits how many encryption workers run at once, across every
+// transfer in the process. Streaming encryption is one goroutine per file,
+// so a batch of uploads would otherwise keep every core busy.
+type cpuBudget struct {
+	cores     int
+	onBattery func() bool
+
+	mu              sync.Mutex
+	percent         int
+	reduceOnBattery bool
+	inUse           int
+	freed           chan struct{} // closed and replaced on every release
+	battery         bool
+	batteryAt       time.Time
+}
+
+func newCPUBudget(cores int, onBattery func() bool) *cpuBudget {
+	return &cpuBudget{
+		cores:           cores,
+		onBattery:       onBattery,
+		percent:         constants.DefaultCPUBudgetPercent,
+		reduceOnBattery: true,
+		freed:           make(chan struct{}),
+	}
+}
+
+var encryptionBudget = newCPUBudget(runtime.NumCPU(), platform.OnBattery)
+
+// SetCPUBudget sets the share of logical CPUs (percent, clamped to
+// constants.MinCPUBudgetPercent..100) that encryption may use. With
+// reduceOnBattery, the share is cut to constants.OnBatteryCPUBudgetFactor
+// percent of that while the machine runs on battery.
+func SetCPUBudget(percent int, reduceOnBattery bool) {
+	encryptionBudget.set(percent, reduceOnBattery)
+}
+
+// EncryptionSlots returns how many encryption workers may currently run.
+func EncryptionSlots() int {
+	return encryptionBudget.slots()
+}
+
+// AcquireEncryption blocks until an encryption slot is free or ctx is done.
+// The returned release function must be called when the CPU-bound work is
+// finished; it is safe to call more than once.
+func AcquireEncryption(ctx context.Context) (release func(), err error) {
+	return encryptionBudget.acquire(ctx)
+}
+
+func (b *cpuBudget) set(percent int, reduceOnBattery bool) {
+	if percent <= 0 || percent > 100 {
+		percent = constants.DefaultCPUBudgetPercent
+	}
+	if percent < constants.MinCPUBudgetPercent {
+		percent = constants.MinCPUBudgetPercent
+	}
+
+	b.mu.Lock()
+	b.percent = percent
+	b.reduceOnBattery = reduceOnBattery
+	b.wakeLocked() // A larger budget may admit waiters
+	b.mu.Unlock()
+}
+
+func (b *cpuBudget) slots() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.slotsLocked()
+}
+
+func (b *cpuBudget) slotsLocked() int {
+	percent := b.percent
+	if b.reduceOnBattery && b.onBatteryLocked() {
+		percent = percent * constants.OnBatteryCPUBudgetFactor / 100
+	}
+	return max(1, b.cores*percent/100)
+}
+
+// onBatteryLocked returns the power source, checking it at most once per
+// constants.PowerSourceCheckInterval.
+func (b *cpuBudget) onBatteryLocked() bool {
+	if time.Since(b.batteryAt) >= constants.PowerSourceCheckInterval {
+		b.battery = b.onBattery()
+		b.batteryAt = time.Now()
+	}
+	return b.battery
+}
+
+func (b *cpuBudget) acquire(ctx context.Context) (func(), error) {
+	for {
+		b.mu.Lock()
+		if b.inUse < b.slotsLocked() {
+			b.inUse++
+			b.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(b.release) }, nil
+		}
+		freed := b.freed
+		b.mu.Unlock()
+
+		// Also re-check periodically: going back to mains power raises the limit
+		select {
+		case <-freed:
+		case <-time.After(constants.PowerSourceCheckInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (b *cpuBudget) release() {
+	b.mu.Lock()
+	b.inUse--
+	b.wakeLocked()
+	b.mu.Unlock()
+}
+
+func (b *cpuBudget) wakeLocked() {
+	close(b.freed)
+	b.freed = make(chan struct{})
+}
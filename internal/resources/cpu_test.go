@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCPUBudgetSlots(t *testing.T) {
+	battery := false
+	b := newCPUBudget(8, func() bool { return battery })
+
+	tests := []struct {
+		percent         int
+		reduceOnBattery bool
+		onBattery       bool
+		want            int
+	}{
+		{100, true, false, 8},
+		{50, true, false, 4},
+		{50, true, true, 2},
+		{50, false, true, 4},
+		{100, true, true, 4},
+		{1, false, false, 1},   // clamped to the 10% minimum, at least one slot
+		{0, false, false, 8},   // unset means the default (100%)
+		{150, false, false, 8}, // out of range means the default
+	}
+	for _, tt := range tests {
+		battery = tt.onBattery
+		b.batteryAt = time.Time{} // Force a power source check
+		b.set(tt.percent, tt.reduceOnBattery)
+		if got := b.slots(); got != tt.want {
+			t.Errorf("percent=%d reduce=%v battery=%v: slots = %d, want %d",
+				tt.percent, tt.reduceOnBattery, tt.onBattery, got, tt.want)
+		}
+	}
+}
+
+func TestCPUBudgetAcquire(t *testing.T) {
+	b := newCPUBudget(4, func() bool { return false })
+	b.set(50, false) // 2 slots
+
+	ctx := context.Background()
+	r1, err := b.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := b.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Third waiter blocks until a slot is released
+	got := make(chan func(), 1)
+	go func() {
+		r, err := b.acquire(ctx)
+		if err == nil {
+			got <- r
+		}
+	}()
+	select {
+	case <-got:
+		t.Fatal("acquired a third slot with a budget of two")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	r1()
+	r1() // Idempotent: must not free a second slot
+	var r3 func()
+	select {
+	case r3 = <-got:
+	case <-time.After(time.Second):
+		t.Fatal("waiter not admitted after release")
+	}
+
+	b.mu.Lock()
+	inUse := b.inUse
+	b.mu.Unlock()
+	if inUse != 2 {
+		t.Errorf("inUse = %d, want 2", inUse)
+	}
+
+	// A cancelled waiter gives up
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := b.acquire(cctx); err == nil {
+		t.Error("acquire with cancelled context succeeded while the budget was full")
+	}
+
+	r2()
+	r3()
+}
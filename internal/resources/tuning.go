@@ -0,0 +1,31 @@
+package resources
+
+import (
+	"runtime"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// DefaultPipelineWorkers returns the default number of tar, upload and job
+// workers for this machine. Tar creation is CPU-bound, and arm64 machines
+// (Apple silicon, Snapdragon X, Graviton) range from 4 to 16+ cores, with
+// about half of them efficiency cores on laptops, so arm64 uses half the
+// logical CPUs within constants.MinARMPipelineWorkers..MaxARMPipelineWorkers.
+// Other architectures keep constants.DefaultPipelineWorkers.
+func DefaultPipelineWorkers() int {
+	return pipelineWorkersFor(runtime.GOARCH, runtime.NumCPU())
+}
+
+func pipelineWorkersFor(arch string, cores int) int {
+	if arch != "arm64" {
+		return constants.DefaultPipelineWorkers
+	}
+	workers := cores / 2
+	if workers < constants.MinARMPipelineWorkers {
+		workers = constants.MinARMPipelineWorkers
+	}
+	if workers > constants.MaxARMPipelineWorkers {
+		workers = constants.MaxARMPipelineWorkers
+	}
+	return workers
+}
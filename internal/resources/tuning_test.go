@@ -0,0 +1,23 @@
+package resources
+
+import "testing"
+
+func TestPipelineWorkersFor(t *testing.T) {
+	tests := []struct {
+		arch  string
+		cores int
+		want  int
+	}{
+		{"amd64", 2, 4},
+		{"amd64", 64, 4},
+		{"arm64", 2, 2},  // floor
+		{"arm64", 8, 4},  // M1: 4P+4E
+		{"arm64", 12, 6}, // M3 Pro, Snapdragon X Elite
+		{"arm64", 64, 8}, // Graviton: capped
+	}
+	for _, tt := range tests {
+		if got := pipelineWorkersFor(tt.arch, tt.cores); got != tt.want {
+			t.Errorf("pipelineWorkersFor(%s, %d) = %d, want %d", tt.arch, tt.cores, got, tt.want)
+		}
+	}
+}
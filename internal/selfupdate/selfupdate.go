@@ -0,0 +1,300 @@
+// Package selfupdate replaces the running binary with a newer release from an
+// internal distribution server.
+//
+// The server publishes a JSON manifest (config update_url):
+//
+//	{
+//	  "version": "v4.10.0",
+//	  "artifacts": {
+//	    "rescale-int-linux-amd64":       {"url": "v4.10.0/rescale-int-linux-amd64", "sha256": "..."},
+//	    "rescale-int-gui-windows-amd64": {"url": "v4.10.0/rescale-int-gui.exe", "sha256": "..."}
+//	  }
+//	}
+//
+// Artifacts are keyed by executable name (without .exe), GOOS and GOARCH;
+// relative URLs resolve against the manifest URL. The manifest has a detached
+// signature at <update_url>.sig: an ASN.1 ECDSA P-256 signature over the
+// SHA-256 of the manifest file, as written by
+//
+//	openssl dgst -sha256 -sign release-key.pem -out latest.json.sig latest.json
+//
+// and checked against the public key in config update_public_key. Signing the
+// manifest binds the version, artifact key and checksum together: a binary is
+// only installed if the signature verifies, its SHA-256 matches the signed
+// manifest and the signed version is newer than the running one, so an old
+// signed release cannot be replayed to downgrade.
+package selfupdate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+var (
+	// ErrDisabled is returned when self-update is turned off by policy.
+	ErrDisabled = errors.New("self-update is disabled (self_update_disabled or RESCALE_DISABLE_UPDATE_CHECK)")
+
+	// ErrNotConfigured is returned when update_url or update_public_key is unset.
+	ErrNotConfigured = errors.New("self-update is not configured: set update_url and update_public_key")
+)
+
+// Manifest is the release manifest published at the update URL.
+type Manifest struct {
+	Version   string              `json:"version"`
+	Artifacts map[string]Artifact `json:"artifacts"`
+}
+
+// Artifact is one downloadable binary in a manifest.
+type Artifact struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Release is the artifact a Check found for this executable.
+type Release struct {
+	Version string
+	Current string
+	Key     string // Manifest artifact key
+	URL     string // Absolute download URL
+	SHA256  string
+	Newer   bool
+}
+
+// Updater checks for and installs releases of one executable.
+type Updater struct {
+	ManifestURL string
+	PublicKey   *ecdsa.PublicKey
+	Client      *http.Client
+	Executable  string // Path of the binary to replace
+	Current     string // Version of that binary
+}
+
+// Disabled reports whether policy turns self-update off.
+func Disabled(cfg *config.Config) bool {
+	v := os.Getenv("RESCALE_DISABLE_UPDATE_CHECK")
+	return (cfg != nil && cfg.SelfUpdateDisabled) || v == "1" || strings.EqualFold(v, "true")
+}
+
+// NewFromConfig returns an Updater for the running executable using the
+// configured manifest URL, public key and proxy settings.
+func NewFromConfig(cfg *config.Config) (*Updater, error) {
+	if Disabled(cfg) {
+		return nil, ErrDisabled
+	}
+	if cfg.UpdateURL == "" || cfg.UpdatePublicKey == "" {
+		return nil, ErrNotConfigured
+	}
+	if u, err := url.Parse(cfg.UpdateURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("update_url must be an https URL: %q", cfg.UpdateURL)
+	}
+
+	keyPEM, err := os.ReadFile(cfg.UpdatePublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read update public key: %w", err)
+	}
+	pub, err := ParsePublicKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same proxy settings as API traffic, without the proxy warmup request
+	clientCfg := *cfg
+	clientCfg.ProxyWarmup = false
+	client, err := inthttp.ConfigureHTTPClient(&clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate running executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	return &Updater{
+		ManifestURL: cfg.UpdateURL,
+		PublicKey:   pub,
+		Client:      client,
+		Executable:  exe,
+		Current:     version.Version,
+	}, nil
+}
+
+// ParsePublicKey parses a PEM "PUBLIC KEY" block holding an ECDSA P-256 key.
+// Other key types and curves are rejected so only FIPS 186-approved
+// signatures are accepted.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("update public key: no PEM PUBLIC KEY block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("update public key: %w", err)
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("update public key must be ECDSA P-256")
+	}
+	return pub, nil
+}
+
+// ArtifactKey returns the manifest key for an executable on a platform,
+// e.g. "rescale-int-linux-amd64" or "rescale-int-gui-windows-arm64".
+func ArtifactKey(exe, goos, goarch string) string {
+	name := strings.TrimSuffix(filepath.Base(exe), ".exe")
+	return fmt.Sprintf("%s-%s-%s", name, goos, goarch)
+}
+
+// Check fetches the manifest, verifies its signature and returns the release
+// for this executable. Release.Newer reports whether it is newer than the
+// running version.
+func (u *Updater) Check(ctx context.Context) (*Release, error) {
+	body, err := u.get(ctx, u.ManifestURL, constants.SelfUpdateMaxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch update manifest: %w", err)
+	}
+	sig, err := u.get(ctx, u.ManifestURL+".sig", constants.SelfUpdateMaxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch update manifest signature: %w", err)
+	}
+	digest := sha256.Sum256(body)
+	if !ecdsa.VerifyASN1(u.PublicKey, digest[:], sig) {
+		return nil, fmt.Errorf("update manifest signature verification failed: refusing to use it")
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse update manifest: %w", err)
+	}
+	if m.Version == "" {
+		return nil, fmt.Errorf("update manifest has no version")
+	}
+
+	key := ArtifactKey(u.Executable, runtime.GOOS, runtime.GOARCH)
+	art, ok := m.Artifacts[key]
+	if !ok || art.URL == "" {
+		return nil, fmt.Errorf("update manifest has no %s artifact", key)
+	}
+	if sum, err := hex.DecodeString(art.SHA256); err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("update manifest has no valid sha256 for %s", key)
+	}
+	base, err := url.Parse(u.ManifestURL)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(art.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact URL %q: %w", art.URL, err)
+	}
+	abs := base.ResolveReference(ref)
+	if abs.Scheme != base.Scheme {
+		return nil, fmt.Errorf("artifact URL %q must use %s", art.URL, base.Scheme)
+	}
+
+	return &Release{
+		Version: m.Version,
+		Current: u.Current,
+		Key:     key,
+		URL:     abs.String(),
+		SHA256:  strings.ToLower(art.SHA256),
+		Newer:   version.Compare(m.Version, u.Current) > 0,
+	}, nil
+}
+
+// Apply downloads rel, checks it against the SHA-256 from the signed
+// manifest, and atomically replaces the executable. The running process
+// keeps using the old binary until it restarts. Nothing is replaced if any
+// check fails, or if rel is not newer than the running version.
+func (u *Updater) Apply(ctx context.Context, rel *Release) error {
+	if version.Compare(rel.Version, u.Current) <= 0 {
+		return fmt.Errorf("refusing to install %s: not newer than the running %s", rel.Version, u.Current)
+	}
+	bin, err := u.get(ctx, rel.URL, constants.SelfUpdateMaxBinarySize)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", rel.Version, err)
+	}
+
+	digest := sha256.Sum256(bin)
+	if rel.SHA256 == "" || hex.EncodeToString(digest[:]) != rel.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s: signed manifest %s, downloaded %x; refusing to install", rel.Key, rel.SHA256, digest)
+	}
+
+	// Stage next to the executable so the swap is a same-volume rename
+	dir := filepath.Dir(u.Executable)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(u.Executable)+".new-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(u.Executable); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+
+	return replaceExecutable(tmpPath, u.Executable)
+}
+
+// get fetches url, failing on non-200 responses and bodies over limit bytes.
+func (u *Updater) get(ctx context.Context, rawURL string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "rescale-interlink/"+u.Current)
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", rawURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", rawURL, limit)
+	}
+	return data, nil
+}
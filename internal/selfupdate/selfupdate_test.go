@@ -0,0 +1,182 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// releaseServer serves a manifest for exe, signed by signer, plus the binary.
+func releaseServer(t *testing.T, exe string, signer *ecdsa.PrivateKey, bin []byte) *httptest.Server {
+	t.Helper()
+	digest := sha256.Sum256(bin)
+	manifest, err := json.Marshal(Manifest{
+		Version: "v99.0.0",
+		Artifacts: map[string]Artifact{
+			ArtifactKey(exe, runtime.GOOS, runtime.GOARCH): {URL: "v99.0.0/bin", SHA256: hex.EncodeToString(digest[:])},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestDigest := sha256.Sum256(manifest)
+	sig, err := ecdsa.SignASN1(rand.Reader, signer, manifestDigest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest.json", func(w http.ResponseWriter, r *http.Request) { w.Write(manifest) })
+	mux.HandleFunc("/latest.json.sig", func(w http.ResponseWriter, r *http.Request) { w.Write(sig) })
+	mux.HandleFunc("/v99.0.0/bin", func(w http.ResponseWriter, r *http.Request) { w.Write(bin) })
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestUpdater trusts key; the manifest is signed by signer.
+func newTestUpdater(t *testing.T, key, signer *ecdsa.PrivateKey, bin []byte) *Updater {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "rescale-int")
+	if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	srv := releaseServer(t, exe, signer, bin)
+	return &Updater{
+		ManifestURL: srv.URL + "/latest.json",
+		PublicKey:   &key.PublicKey,
+		Client:      srv.Client(),
+		Executable:  exe,
+		Current:     "v4.9.8",
+	}
+}
+
+func TestCheckAndApply(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	u := newTestUpdater(t, key, key, []byte("new binary"))
+
+	rel, err := u.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rel.Newer || rel.Version != "v99.0.0" {
+		t.Fatalf("release = %+v", rel)
+	}
+	if err := u.Apply(context.Background(), rel); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(u.Executable)
+	if string(got) != "new binary" {
+		t.Errorf("executable = %q after update", got)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(u.Executable))
+	if len(entries) != 1 && runtime.GOOS != "windows" {
+		t.Errorf("staging files left behind: %v", entries)
+	}
+}
+
+func TestCheckRejectsBadSignature(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	u := newTestUpdater(t, key, other, []byte("new binary"))
+
+	if _, err := u.Check(context.Background()); err == nil {
+		t.Fatal("expected manifest signature verification to fail")
+	}
+}
+
+func TestApplyRejectsDowngrade(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	u := newTestUpdater(t, key, key, []byte("new binary"))
+	u.Current = "v99.0.0"
+
+	rel, err := u.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Newer {
+		t.Errorf("release %s reported newer than %s", rel.Version, u.Current)
+	}
+	if err := u.Apply(context.Background(), rel); err == nil {
+		t.Fatal("expected a release that is not newer to be refused")
+	}
+	got, _ := os.ReadFile(u.Executable)
+	if string(got) != "old binary" {
+		t.Errorf("executable replaced by a release that is not newer: %q", got)
+	}
+}
+
+func TestApplyRejectsChecksumMismatch(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	u := newTestUpdater(t, key, key, []byte("new binary"))
+
+	rel, err := u.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel.SHA256 = hex.EncodeToString(make([]byte, 32))
+	if err := u.Apply(context.Background(), rel); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	encode := func(pub any) []byte {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if _, err := ParsePublicKey(encode(&p256.PublicKey)); err != nil {
+		t.Errorf("P-256 key rejected: %v", err)
+	}
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if _, err := ParsePublicKey(encode(&p384.PublicKey)); err == nil {
+		t.Error("P-384 key accepted")
+	}
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Error("garbage accepted")
+	}
+}
+
+func TestArtifactKey(t *testing.T) {
+	if got := ArtifactKey("/opt/rescale/rescale-int-gui.exe", "windows", "arm64"); got != "rescale-int-gui-windows-arm64" {
+		t.Errorf("ArtifactKey = %q", got)
+	}
+	if got := ArtifactKey("/usr/local/bin/rescale-int", "linux", "amd64"); got != "rescale-int-linux-amd64" {
+		t.Errorf("ArtifactKey = %q", got)
+	}
+}
+
+func TestNewFromConfigPolicy(t *testing.T) {
+	t.Setenv("RESCALE_DISABLE_UPDATE_CHECK", "")
+	if _, err := NewFromConfig(&config.Config{SelfUpdateDisabled: true}); err != ErrDisabled {
+		t.Errorf("disabled config: err = %v", err)
+	}
+	if _, err := NewFromConfig(&config.Config{}); err != ErrNotConfigured {
+		t.Errorf("empty config: err = %v", err)
+	}
+	if _, err := NewFromConfig(&config.Config{UpdateURL: "http://dist.example.com/latest.json", UpdatePublicKey: "key.pem"}); err == nil {
+		t.Error("plain http update_url accepted")
+	}
+	t.Setenv("RESCALE_DISABLE_UPDATE_CHECK", "1")
+	if _, err := NewFromConfig(&config.Config{UpdateURL: "https://dist.example.com/latest.json", UpdatePublicKey: "key.pem"}); err != ErrDisabled {
+		t.Errorf("env kill switch: err = %v", err)
+	}
+}
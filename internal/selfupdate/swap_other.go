@@ -0,0 +1,20 @@
+//go:build !windows
+
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+)
+
+// replaceExecutable renames staged over exe. The rename is atomic on the same
+// filesystem, and a running process keeps its open copy of the old binary.
+func replaceExecutable(staged, exe string) error {
+	if err := os.Rename(staged, exe); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
+
+// CleanupPrevious is a no-op: the old binary is unlinked by the rename.
+func CleanupPrevious() {}
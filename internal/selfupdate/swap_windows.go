@@ -0,0 +1,39 @@
+//go:build windows
+
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+)
+
+// oldSuffix marks the previous binary, which cannot be deleted while it runs.
+const oldSuffix = ".old"
+
+// replaceExecutable swaps staged in for exe. Windows cannot overwrite a
+// running executable but can rename it, so exe is moved aside first and
+// restored if the second rename fails.
+func replaceExecutable(staged, exe string) error {
+	old := exe + oldSuffix
+	_ = os.Remove(old) // Left over from a previous update
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", exe, err)
+	}
+	if err := os.Rename(staged, exe); err != nil {
+		if rerr := os.Rename(old, exe); rerr != nil {
+			return fmt.Errorf("failed to replace %s: %w (restoring the previous binary also failed: %v; it is at %s)", exe, err, rerr, old)
+		}
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
+
+// CleanupPrevious removes the binary left by the last update. Call it at
+// startup; it fails silently while another instance still runs the old copy.
+func CleanupPrevious() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	_ = os.Remove(exe + oldSuffix)
+}
@@ -0,0 +1,141 @@
+package sendto
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/core"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/services"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
+)
+
+// Processor uploads spooled requests. Run it in a goroutine for as long as
+// the GUI or `serve` is up.
+type Processor struct {
+	Spool Spool
+
+	// ResolveFolder returns the ID of the folder to upload into. Called once
+	// per batch so send_to_folder changes apply without a restart.
+	ResolveFolder func(ctx context.Context) (string, error)
+
+	// Start queues uploads (TransferService.StartTransfers).
+	Start func(ctx context.Context, requests []services.TransferRequest) error
+
+	Logger *logging.Logger
+}
+
+// NewProcessor returns a processor that uploads through engine's transfer
+// service into the folder path folderPath returns, under My Library.
+func NewProcessor(engine *core.Engine, folderPath func() string, logger *logging.Logger) *Processor {
+	cache := folder.NewFolderCache()
+	return &Processor{
+		Spool: Spool{Dir: config.SendToSpoolDirectory()},
+		ResolveFolder: func(ctx context.Context) (string, error) {
+			apiClient := engine.API()
+			if apiClient == nil {
+				return "", fmt.Errorf("not connected: configure an API key first")
+			}
+			roots, err := apiClient.GetRootFolders(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to get root folders: %w", err)
+			}
+			return folder.EnsureFolderPath(ctx, apiClient, cache, roots.MyLibrary, folderPath())
+		},
+		Start: func(ctx context.Context, requests []services.TransferRequest) error {
+			ts := engine.TransferService()
+			if ts == nil {
+				return fmt.Errorf("transfer service not available")
+			}
+			return ts.StartTransfers(ctx, requests)
+		},
+		Logger: logger,
+	}
+}
+
+// Run polls the spool every SendToPollInterval until ctx is cancelled.
+func (p *Processor) Run(ctx context.Context) {
+	defer p.Spool.StopHeartbeat()
+
+	ticker := time.NewTicker(constants.SendToPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := p.Spool.Heartbeat(); err != nil {
+			p.Logger.Warn().Err(err).Msg("Send to: failed to write heartbeat")
+		}
+		p.ProcessPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending queues uploads for every pending request as one batch. If
+// the folder cannot be resolved or the uploads cannot start, the requests
+// stay claimed and are retried after SendToClaimTimeout.
+func (p *Processor) ProcessPending(ctx context.Context) {
+	claimed, err := p.Spool.Claim()
+	if err != nil {
+		p.Logger.Warn().Err(err).Msg("Send to: failed to read spool")
+		return
+	}
+	if len(claimed) == 0 {
+		return
+	}
+
+	var requests []services.TransferRequest
+	batchID := fmt.Sprintf("sendto-%d", time.Now().UnixNano())
+	for _, c := range claimed {
+		for _, f := range c.Files {
+			info, err := os.Stat(f)
+			if err != nil || !info.Mode().IsRegular() {
+				p.Logger.Warn().Str("file", f).Msg("Send to: file no longer available, skipped")
+				continue
+			}
+			requests = append(requests, services.TransferRequest{
+				Type:        services.TransferTypeUpload,
+				Source:      f,
+				Name:        filepath.Base(f),
+				Size:        info.Size(),
+				SourceLabel: services.SourceLabelSendTo,
+				BatchID:     batchID,
+			})
+		}
+	}
+
+	if len(requests) > 0 {
+		folderID, err := p.ResolveFolder(ctx)
+		if err != nil {
+			p.Logger.Error().Err(err).Msg("Send to: failed to resolve upload folder")
+			return
+		}
+		label := fmt.Sprintf("Send to Rescale: %s", requests[0].Name)
+		if len(requests) > 1 {
+			label = fmt.Sprintf("Send to Rescale: %d files", len(requests))
+		}
+		for i := range requests {
+			requests[i].Dest = folderID
+			requests[i].BatchLabel = label
+		}
+		// Transfers outlive this poll
+		if err := p.Start(context.Background(), requests); err != nil {
+			p.Logger.Error().Err(err).Msg("Send to: failed to start uploads")
+			return
+		}
+		p.Logger.Info().Str("batch_id", batchID).Int("files", len(requests)).Msg("Send to: uploads queued")
+	}
+
+	for _, c := range claimed {
+		if err := p.Spool.Done(c); err != nil {
+			p.Logger.Warn().Err(err).Msg("Send to: failed to remove request")
+		}
+	}
+}
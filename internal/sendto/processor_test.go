@@ -0,0 +1,104 @@
+package sendto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/services"
+)
+
+func newTestProcessor(dir string, start func(context.Context, []services.TransferRequest) error) *Processor {
+	return &Processor{
+		Spool:         Spool{Dir: filepath.Join(dir, "spool")},
+		ResolveFolder: func(ctx context.Context) (string, error) { return "folder-1", nil },
+		Start:         start,
+		Logger:        logging.NewLoggerWithWriter(io.Discard),
+	}
+}
+
+func TestProcessPendingBatchesRequests(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.dat")
+	b := writeFile(t, dir, "b.dat")
+
+	var got []services.TransferRequest
+	p := newTestProcessor(dir, func(ctx context.Context, reqs []services.TransferRequest) error {
+		got = reqs
+		return nil
+	})
+	if err := p.Spool.Enqueue([]string{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Spool.Enqueue([]string{b}); err != nil {
+		t.Fatal(err)
+	}
+
+	p.ProcessPending(context.Background())
+
+	if len(got) != 2 {
+		t.Fatalf("started %d transfers, want 2", len(got))
+	}
+	for _, r := range got {
+		if r.Type != services.TransferTypeUpload || r.Dest != "folder-1" || r.SourceLabel != services.SourceLabelSendTo {
+			t.Errorf("unexpected request %+v", r)
+		}
+		if r.BatchID == "" || r.BatchID != got[0].BatchID {
+			t.Errorf("requests not in one batch: %q vs %q", r.BatchID, got[0].BatchID)
+		}
+		if r.BatchLabel != "Send to Rescale: 2 files" {
+			t.Errorf("BatchLabel = %q", r.BatchLabel)
+		}
+	}
+	if entries, _ := os.ReadDir(p.Spool.Dir); len(entries) != 0 {
+		t.Errorf("requests left in spool: %v", entries)
+	}
+}
+
+func TestProcessPendingSkipsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.dat")
+	b := writeFile(t, dir, "b.dat")
+
+	var got []services.TransferRequest
+	p := newTestProcessor(dir, func(ctx context.Context, reqs []services.TransferRequest) error {
+		got = reqs
+		return nil
+	})
+	if err := p.Spool.Enqueue([]string{a, b}); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(b)
+
+	p.ProcessPending(context.Background())
+
+	if len(got) != 1 || got[0].Source != a {
+		t.Fatalf("started %+v, want only %s", got, a)
+	}
+	if got[0].BatchLabel != "Send to Rescale: a.dat" {
+		t.Errorf("BatchLabel = %q", got[0].BatchLabel)
+	}
+}
+
+func TestProcessPendingKeepsRequestsOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.dat")
+
+	p := newTestProcessor(dir, func(ctx context.Context, reqs []services.TransferRequest) error {
+		return errors.New("transfer service not available")
+	})
+	if err := p.Spool.Enqueue([]string{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	p.ProcessPending(context.Background())
+
+	entries, _ := os.ReadDir(p.Spool.Dir)
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != claimedExt {
+		t.Errorf("spool after failed start = %v, want one claimed request", entries)
+	}
+}
@@ -0,0 +1,65 @@
+package sendto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// MenuLabel is the context-menu entry's text.
+const MenuLabel = "Send to Rescale Interlink"
+
+// ErrUnsupported is returned by Install on platforms without a supported
+// file manager integration.
+var ErrUnsupported = errors.New("the Send to Rescale Interlink menu is not supported on this platform")
+
+// GUIExecutable returns the GUI binary that belongs with exe: exe itself
+// when it is the GUI build, else a rescale-int-gui next to it. Returns ""
+// when there is none (CLI-only install).
+func GUIExecutable(exe string) string {
+	if strings.Contains(strings.ToLower(filepath.Base(exe)), "-gui") {
+		return exe
+	}
+	name := "rescale-int-gui"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	gui := filepath.Join(filepath.Dir(exe), name)
+	if info, err := os.Stat(gui); err == nil && !info.IsDir() {
+		return gui
+	}
+	return ""
+}
+
+// MenuExecutable returns the binary the context-menu entry runs: the GUI
+// build when there is one, since on Windows it starts without flashing a
+// console window, else exe.
+func MenuExecutable(exe string) string {
+	if gui := GUIExecutable(exe); gui != "" {
+		return gui
+	}
+	return exe
+}
+
+// LaunchGUI starts the GUI in the background so it picks up queued
+// requests.
+func LaunchGUI(exe string) error {
+	gui := GUIExecutable(exe)
+	if gui == "" {
+		return fmt.Errorf("no Interlink GUI found next to %s", exe)
+	}
+	cmd := exec.Command(gui, "--gui")
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", gui, err)
+	}
+	return cmd.Process.Release()
+}
+
+// shellQuote quotes s for /bin/sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,187 @@
+//go:build darwin
+
+package sendto
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+)
+
+// workflowPath is the Finder Quick Action. Users can bind a keyboard
+// shortcut to it in System Settings > Keyboard > Keyboard Shortcuts >
+// Services.
+func workflowPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "Services", MenuLabel+".workflow"), nil
+}
+
+// Install adds a Finder Quick Action that runs `send-to` with the selected
+// files.
+func Install(exe string) error {
+	path, err := workflowPath()
+	if err != nil {
+		return err
+	}
+	contents := filepath.Join(path, "Contents")
+	if err := os.MkdirAll(contents, 0755); err != nil {
+		return fmt.Errorf("failed to create Quick Action: %w", err)
+	}
+
+	command := html.EscapeString(fmt.Sprintf(`exec %s send-to "$@"`, shellQuote(MenuExecutable(exe))))
+	files := map[string]string{
+		"Info.plist":     fmt.Sprintf(infoPlist, html.EscapeString(MenuLabel)),
+		"document.wflow": fmt.Sprintf(documentWflow, command),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(contents, name), []byte(data), 0644); err != nil {
+			return fmt.Errorf("failed to write Quick Action: %w", err)
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the Finder Quick Action.
+func Uninstall() error {
+	path, err := workflowPath()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// Installed reports whether the Finder Quick Action is present.
+func Installed() bool {
+	path, err := workflowPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(path, "Contents", "document.wflow"))
+	return err == nil
+}
+
+// Supported reports whether Install works on this platform.
+func Supported() bool { return true }
+
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>NSServices</key>
+	<array>
+		<dict>
+			<key>NSMenuItem</key>
+			<dict>
+				<key>default</key>
+				<string>%s</string>
+			</dict>
+			<key>NSMessage</key>
+			<string>runWorkflowAsService</string>
+			<key>NSRequiredContext</key>
+			<dict>
+				<key>NSApplicationIdentifier</key>
+				<string>com.apple.finder</string>
+			</dict>
+			<key>NSSendFileTypes</key>
+			<array>
+				<string>public.item</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+// documentWflow is a single "Run Shell Script" action that receives the
+// selected files as arguments (inputMethod 1).
+const documentWflow = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>AMApplicationBuild</key>
+	<string>523</string>
+	<key>AMApplicationVersion</key>
+	<string>2.10</string>
+	<key>AMDocumentVersion</key>
+	<string>2</string>
+	<key>actions</key>
+	<array>
+		<dict>
+			<key>action</key>
+			<dict>
+				<key>AMAccepts</key>
+				<dict>
+					<key>Container</key>
+					<string>List</string>
+					<key>Optional</key>
+					<true/>
+					<key>Types</key>
+					<array>
+						<string>com.apple.cocoa.string</string>
+					</array>
+				</dict>
+				<key>AMActionVersion</key>
+				<string>2.0.3</string>
+				<key>AMApplication</key>
+				<array>
+					<string>Automator</string>
+				</array>
+				<key>AMProvides</key>
+				<dict>
+					<key>Container</key>
+					<string>List</string>
+					<key>Types</key>
+					<array>
+						<string>com.apple.cocoa.string</string>
+					</array>
+				</dict>
+				<key>ActionBundlePath</key>
+				<string>/System/Library/Automator/Run Shell Script.action</string>
+				<key>ActionName</key>
+				<string>Run Shell Script</string>
+				<key>ActionParameters</key>
+				<dict>
+					<key>COMMAND_STRING</key>
+					<string>%s</string>
+					<key>CheckedForUserDefaultShell</key>
+					<true/>
+					<key>inputMethod</key>
+					<integer>1</integer>
+					<key>shell</key>
+					<string>/bin/sh</string>
+					<key>source</key>
+					<string></string>
+				</dict>
+				<key>BundleIdentifier</key>
+				<string>com.apple.RunShellScript</string>
+				<key>CFBundleVersion</key>
+				<string>2.0.3</string>
+				<key>Class Name</key>
+				<string>RunShellScriptAction</string>
+			</dict>
+		</dict>
+	</array>
+	<key>connectors</key>
+	<dict/>
+	<key>workflowMetaData</key>
+	<dict>
+		<key>serviceApplicationBundleID</key>
+		<string>com.apple.finder</string>
+		<key>serviceApplicationPath</key>
+		<string>/System/Library/CoreServices/Finder.app</string>
+		<key>serviceInputTypeIdentifier</key>
+		<string>com.apple.Automator.fileSystemObject</string>
+		<key>serviceOutputTypeIdentifier</key>
+		<string>com.apple.Automator.nothing</string>
+		<key>serviceProcessesInput</key>
+		<integer>0</integer>
+		<key>workflowTypeIdentifier</key>
+		<string>com.apple.Automator.servicesMenu</string>
+	</dict>
+</dict>
+</plist>
+`
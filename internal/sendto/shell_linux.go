@@ -0,0 +1,96 @@
+//go:build linux
+
+package sendto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// menuFiles returns the GNOME Files (Nautilus) script and the KDE Dolphin
+// service menu.
+func menuFiles() (nautilus, dolphin string, err error) {
+	data, err := dataHome()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(data, "nautilus", "scripts", MenuLabel),
+		filepath.Join(data, "kio", "servicemenus", "rescale-interlink-send-to.desktop"), nil
+}
+
+// Install adds a Nautilus script (Scripts submenu) and a Dolphin service
+// menu entry that run `send-to` with the selected files.
+func Install(exe string) error {
+	nautilus, dolphin, err := menuFiles()
+	if err != nil {
+		return err
+	}
+	exe = MenuExecutable(exe)
+
+	script := fmt.Sprintf("#!/bin/sh\nexec %s send-to \"$@\"\n", shellQuote(exe))
+	// Desktop entry Exec quoting: double quotes, with \ " ` $ escaped
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`).Replace(exe)
+	desktop := fmt.Sprintf(`[Desktop Entry]
+Type=Service
+MimeType=application/octet-stream;all/allfiles;
+X-KDE-ServiceTypes=KonqPopupMenu/Plugin
+Actions=sendToRescale
+
+[Desktop Action sendToRescale]
+Name=%s
+Exec="%s" send-to %%F
+`, MenuLabel, quoted)
+
+	for path, data := range map[string]string{nautilus: script, dolphin: desktop} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		// Both must be executable to be offered
+		if err := os.WriteFile(path, []byte(data), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the Nautilus script and Dolphin service menu.
+func Uninstall() error {
+	nautilus, dolphin, err := menuFiles()
+	if err != nil {
+		return err
+	}
+	for _, path := range []string{nautilus, dolphin} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Installed reports whether the Nautilus script is present.
+func Installed() bool {
+	nautilus, _, err := menuFiles()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(nautilus)
+	return err == nil
+}
+
+// Supported reports whether Install works on this platform.
+func Supported() bool { return true }
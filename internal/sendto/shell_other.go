@@ -0,0 +1,15 @@
+//go:build !windows && !darwin && !linux
+
+package sendto
+
+// Install is not supported on this platform.
+func Install(exe string) error { return ErrUnsupported }
+
+// Uninstall is a no-op on this platform.
+func Uninstall() error { return nil }
+
+// Installed always reports false on this platform.
+func Installed() bool { return false }
+
+// Supported reports whether Install works on this platform.
+func Supported() bool { return false }
@@ -0,0 +1,65 @@
+//go:build windows
+
+package sendto
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// menuKey adds a verb for every file type, for the current user only, so
+// no elevation is needed.
+const menuKey = `Software\Classes\*\shell\RescaleInterlinkSendTo`
+
+// Install adds the Explorer context-menu entry. Explorer runs the command
+// once per selected file; the GUI batches requests that arrive together.
+func Install(exe string) error {
+	exe = MenuExecutable(exe)
+
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, menuKey, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to create context menu key: %w", err)
+	}
+	defer k.Close()
+	if err := k.SetStringValue("", MenuLabel); err != nil {
+		return fmt.Errorf("failed to set context menu label: %w", err)
+	}
+	if err := k.SetStringValue("Icon", exe); err != nil {
+		return fmt.Errorf("failed to set context menu icon: %w", err)
+	}
+
+	ck, _, err := registry.CreateKey(registry.CURRENT_USER, menuKey+`\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to create context menu command key: %w", err)
+	}
+	defer ck.Close()
+	if err := ck.SetStringValue("", fmt.Sprintf(`"%s" send-to "%%1"`, exe)); err != nil {
+		return fmt.Errorf("failed to set context menu command: %w", err)
+	}
+	return nil
+}
+
+// Uninstall removes the Explorer context-menu entry.
+func Uninstall() error {
+	for _, key := range []string{menuKey + `\command`, menuKey} {
+		if err := registry.DeleteKey(registry.CURRENT_USER, key); err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return fmt.Errorf("failed to remove context menu key: %w", err)
+		}
+	}
+	return nil
+}
+
+// Installed reports whether the context-menu entry is present.
+func Installed() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, menuKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	k.Close()
+	return true
+}
+
+// Supported reports whether Install works on this platform.
+func Supported() bool { return true }
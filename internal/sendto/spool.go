@@ -0,0 +1,188 @@
+// Package sendto implements "Send to Rescale Interlink": an OS context-menu
+// entry runs `rescale-int send-to FILE...`, which drops a request into a
+// spool directory. A running GUI or `rescale-int serve` picks requests up
+// and uploads the files into the configured folder (send_to_folder).
+//
+// The spool is plain files so it works the same on every platform and
+// needs no listening socket: requests are written atomically as *.json,
+// claimed by renaming them to *.claimed, and deleted once their uploads are
+// queued. Consumers touch a heartbeat file so `send-to` can tell whether
+// anything is running.
+package sendto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+const (
+	requestExt    = ".json"
+	claimedExt    = ".claimed"
+	heartbeatName = "consumer.alive"
+)
+
+// Request is one context-menu invocation.
+type Request struct {
+	Files     []string  `json:"files"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Claimed is a request taken from the spool by one consumer.
+type Claimed struct {
+	Request
+	path string
+}
+
+// Spool is the request directory shared by `send-to` and its consumers.
+type Spool struct {
+	Dir string
+}
+
+// Enqueue validates files (absolute paths to existing regular files) and
+// writes a request for them.
+func (s Spool) Enqueue(files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no files to send")
+	}
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			return fmt.Errorf("path must be absolute: %s", f)
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a folder; upload folders from the File Browser", f)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("not a regular file: %s", f)
+		}
+	}
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create send-to spool: %w", err)
+	}
+	data, err := json.Marshal(Request{Files: files, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	// Write under a name consumers ignore, then rename into place
+	tmp, err := os.CreateTemp(s.Dir, "request-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create send-to request: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write send-to request: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write send-to request: %w", err)
+	}
+	final := strings.TrimSuffix(tmp.Name(), ".tmp") + requestExt
+	if err := os.Rename(tmp.Name(), final); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to queue send-to request: %w", err)
+	}
+	return nil
+}
+
+// Claim takes every pending request, plus claimed ones abandoned for longer
+// than SendToClaimTimeout, oldest first. Unreadable requests are deleted.
+func (s Spool) Claim() ([]Claimed, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var claimed []Claimed
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(s.Dir, name)
+		switch filepath.Ext(name) {
+		case requestExt:
+		case claimedExt:
+			info, err := e.Info()
+			if err != nil || time.Since(info.ModTime()) < constants.SendToClaimTimeout {
+				continue
+			}
+		default:
+			continue
+		}
+
+		// The rename is the claim: only one consumer can win it. Reclaims
+		// get a new name so the rename is exclusive there too.
+		target := strings.TrimSuffix(path, filepath.Ext(path))
+		if filepath.Ext(name) == claimedExt {
+			target += fmt.Sprintf(".r%d", os.Getpid())
+		}
+		target += claimedExt
+		if err := os.Rename(path, target); err != nil {
+			continue
+		}
+		now := time.Now()
+		_ = os.Chtimes(target, now, now)
+
+		data, err := os.ReadFile(target)
+		var req Request
+		if err == nil {
+			err = json.Unmarshal(data, &req)
+		}
+		if err != nil {
+			os.Remove(target)
+			continue
+		}
+		claimed = append(claimed, Claimed{Request: req, path: target})
+	}
+
+	// ReadDir sorts by name; creation time is what matters
+	sort.SliceStable(claimed, func(i, j int) bool { return claimed[i].CreatedAt.Before(claimed[j].CreatedAt) })
+	return claimed, nil
+}
+
+// Done deletes a claimed request.
+func (s Spool) Done(c Claimed) error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Heartbeat records that a consumer is running.
+func (s Spool) Heartbeat() error {
+	path := filepath.Join(s.Dir, heartbeatName)
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0600)
+}
+
+// StopHeartbeat records that the consumer has stopped.
+func (s Spool) StopHeartbeat() {
+	os.Remove(filepath.Join(s.Dir, heartbeatName))
+}
+
+// ConsumerAlive reports whether a GUI or `serve` has checked the spool
+// within SendToConsumerStale.
+func (s Spool) ConsumerAlive() bool {
+	info, err := os.Stat(filepath.Join(s.Dir, heartbeatName))
+	return err == nil && time.Since(info.ModTime()) < constants.SendToConsumerStale
+}
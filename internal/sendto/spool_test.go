@@ -0,0 +1,152 @@
+package sendto
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+func writeFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEnqueueValidation(t *testing.T) {
+	dir := t.TempDir()
+	s := Spool{Dir: filepath.Join(dir, "spool")}
+	file := writeFile(t, dir, "a.dat")
+
+	cases := map[string][]string{
+		"empty":    nil,
+		"relative": {"a.dat"},
+		"missing":  {filepath.Join(dir, "missing.dat")},
+		"folder":   {dir},
+	}
+	for name, files := range cases {
+		if err := s.Enqueue(files); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if err := s.Enqueue([]string{file}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+}
+
+func TestClaimAndDone(t *testing.T) {
+	dir := t.TempDir()
+	s := Spool{Dir: filepath.Join(dir, "spool")}
+	a := writeFile(t, dir, "a.dat")
+	b := writeFile(t, dir, "b.dat")
+
+	if claimed, err := s.Claim(); err != nil || len(claimed) != 0 {
+		t.Fatalf("Claim() on missing spool = %v, %v", claimed, err)
+	}
+
+	if err := s.Enqueue([]string{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Enqueue([]string{b}); err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := s.Claim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("Claim() returned %d requests, want 2", len(claimed))
+	}
+	if claimed[0].Files[0] != a || claimed[1].Files[0] != b {
+		t.Errorf("Claim() order = %v, %v; want oldest first", claimed[0].Files, claimed[1].Files)
+	}
+
+	// Claimed requests are not handed out again before the timeout
+	if again, _ := s.Claim(); len(again) != 0 {
+		t.Errorf("second Claim() returned %d requests, want 0", len(again))
+	}
+
+	for _, c := range claimed {
+		if err := s.Done(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ := os.ReadDir(s.Dir)
+	if len(entries) != 0 {
+		t.Errorf("spool not empty after Done: %v", entries)
+	}
+}
+
+func TestClaimReclaimsAbandoned(t *testing.T) {
+	dir := t.TempDir()
+	s := Spool{Dir: filepath.Join(dir, "spool")}
+	a := writeFile(t, dir, "a.dat")
+	if err := s.Enqueue([]string{a}); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := s.Claim()
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim() = %v, %v", claimed, err)
+	}
+
+	old := time.Now().Add(-2 * constants.SendToClaimTimeout)
+	if err := os.Chtimes(claimed[0].path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.Claim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].Files[0] != a {
+		t.Fatalf("Claim() after timeout = %v, want the abandoned request", again)
+	}
+}
+
+func TestClaimDropsUnreadable(t *testing.T) {
+	s := Spool{Dir: t.TempDir()}
+	writeFile(t, s.Dir, "broken.json")
+
+	claimed, err := s.Claim()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 0 {
+		t.Errorf("Claim() returned %d requests, want 0", len(claimed))
+	}
+	if entries, _ := os.ReadDir(s.Dir); len(entries) != 0 {
+		t.Errorf("unreadable request not removed: %v", entries)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	s := Spool{Dir: filepath.Join(t.TempDir(), "spool")}
+	if s.ConsumerAlive() {
+		t.Fatal("ConsumerAlive() = true before any heartbeat")
+	}
+	if err := s.Heartbeat(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.ConsumerAlive() {
+		t.Error("ConsumerAlive() = false after Heartbeat")
+	}
+
+	old := time.Now().Add(-2 * constants.SendToConsumerStale)
+	if err := os.Chtimes(filepath.Join(s.Dir, heartbeatName), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if s.ConsumerAlive() {
+		t.Error("ConsumerAlive() = true for a stale heartbeat")
+	}
+
+	s.StopHeartbeat()
+	if s.ConsumerAlive() {
+		t.Error("ConsumerAlive() = true after StopHeartbeat")
+	}
+}
@@ -0,0 +1,594 @@
+// Package server implements `rescale-int serve`: a small REST API on a
+// loopback address so local tools (Python post-processing scripts, MATLAB,
+// shell pipelines) can start transfers and runs and follow their progress.
+//
+// Every request needs an "Authorization: Bearer <token>" header. Requests
+// that carry an Origin header are refused, so web pages open in a browser on
+// the same machine cannot drive the API.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/core"
+	"github.com/rescale/rescale-int/internal/events"
+	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/pur/jobarray"
+	"github.com/rescale/rescale-int/internal/pur/runhistory"
+	"github.com/rescale/rescale-int/internal/services"
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+// Server serves the REST API for one engine.
+type Server struct {
+	engine *core.Engine
+	token  string
+	logger *logging.Logger
+
+	mu        sync.Mutex
+	runCancel context.CancelFunc
+	lastRunID string // Most recent run started through the API
+}
+
+// New creates a server that accepts requests carrying token.
+func New(engine *core.Engine, token string, logger *logging.Logger) *Server {
+	return &Server{engine: engine, token: token, logger: logger}
+}
+
+// Handler returns the HTTP handler with authentication applied.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", s.handleStatus)
+	mux.HandleFunc("GET /v1/transfers", s.handleListTransfers)
+	mux.HandleFunc("DELETE /v1/transfers/{id}", s.handleCancelTransfer)
+	mux.HandleFunc("POST /v1/uploads", s.handleUpload)
+	mux.HandleFunc("POST /v1/downloads", s.handleDownload)
+	mux.HandleFunc("POST /v1/runs", s.handleStartRun)
+	mux.HandleFunc("GET /v1/runs/current", s.handleRunStatus)
+	mux.HandleFunc("DELETE /v1/runs/current", s.handleCancelRun)
+	mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return s.authenticate(mux)
+}
+
+// Serve listens on addr until ctx is cancelled. addr must be a loopback
+// address.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	if err := ValidateListenAddr(addr); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), constants.ServeShutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// ValidateListenAddr accepts host:port addresses on loopback interfaces only.
+func ValidateListenAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("listen address %q is not a loopback address; the API is for local tools only", addr)
+	}
+	return nil
+}
+
+// GenerateToken returns a random API token.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// WriteTokenFile writes token to path, readable by the current user only.
+func WriteTokenFile(path, token string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0600)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, "browser requests are not accepted")
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Status and transfers
+// ---------------------------------------------------------------------------
+
+type statusResponse struct {
+	Version   string        `json:"version"`
+	Run       runStatus     `json:"run"`
+	Transfers transferStats `json:"transfers"`
+}
+
+type transferStats struct {
+	Queued    int `json:"queued"`
+	Active    int `json:"active"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+
+	CancelIdle cancelIdleStats `json:"cancelIdle"`
+}
+
+// cancelIdleStats reports cancel-to-idle latency in milliseconds.
+type cancelIdleStats struct {
+	Count  int   `json:"count"`
+	Slow   int   `json:"slow"`
+	MaxMs  int64 `json:"maxMs"`
+	MeanMs int64 `json:"meanMs"`
+}
+
+type runStatus struct {
+	Active    bool       `json:"active"`
+	RunID     string     `json:"runId,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+	Failed    int        `json:"failed"`
+	Pending   int        `json:"pending"`
+}
+
+type transferTask struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	State       string     `json:"state"`
+	Name        string     `json:"name"`
+	Source      string     `json:"source"`
+	Dest        string     `json:"dest"`
+	Size        int64      `json:"size"`
+	BatchID     string     `json:"batchId,omitempty"`
+	Progress    float64    `json:"progress"`
+	Speed       float64    `json:"speed,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	st := s.engine.TransferService().GetStats()
+	writeJSON(w, http.StatusOK, statusResponse{
+		Version: version.Version,
+		Run:     s.runStatus(),
+		Transfers: transferStats{
+			Queued:    st.Queued + st.Initializing + st.Paused,
+			Active:    st.Active,
+			Completed: st.Completed,
+			Failed:    st.Failed,
+			Cancelled: st.Cancelled,
+			CancelIdle: cancelIdleStats{
+				Count:  st.CancelLatency.Count,
+				Slow:   st.CancelLatency.Slow,
+				MaxMs:  st.CancelLatency.Max.Milliseconds(),
+				MeanMs: st.CancelLatency.Mean().Milliseconds(),
+			},
+		},
+	})
+}
+
+func (s *Server) handleListTransfers(w http.ResponseWriter, r *http.Request) {
+	batchID := r.URL.Query().Get("batch_id")
+	tasks := []transferTask{}
+	for _, t := range s.engine.TransferService().GetTasks() {
+		if batchID != "" && t.BatchID != batchID {
+			continue
+		}
+		task := transferTask{
+			ID:       t.ID,
+			Type:     string(t.Type),
+			State:    string(t.State),
+			Name:     t.Name,
+			Source:   t.Source,
+			Dest:     t.Dest,
+			Size:     t.Size,
+			BatchID:  t.BatchID,
+			Progress: t.Progress,
+			Speed:    t.Speed,
+		}
+		if t.Error != nil {
+			task.Error = t.Error.Error()
+		}
+		if !t.CompletedAt.IsZero() {
+			completed := t.CompletedAt
+			task.CompletedAt = &completed
+		}
+		tasks = append(tasks, task)
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) handleCancelTransfer(w http.ResponseWriter, r *http.Request) {
+	if err := s.engine.TransferService().CancelTransfer(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ---------------------------------------------------------------------------
+// Uploads and downloads
+// ---------------------------------------------------------------------------
+
+type uploadRequest struct {
+	Paths    []string `json:"paths"`    // Absolute local file paths
+	FolderID string   `json:"folderId"` // Empty = My Library root
+	Tags     []string `json:"tags"`
+}
+
+type downloadRequest struct {
+	FileIDs []string `json:"fileIds"`
+	DestDir string   `json:"destDir"` // Absolute local directory
+}
+
+type batchResponse struct {
+	BatchID    string `json:"batchId"`
+	Files      int    `json:"files"`
+	Duplicates int    `json:"duplicates,omitempty"` // Skipped: already queued or transferring
+}
+
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	var req uploadRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Paths) == 0 {
+		writeError(w, http.StatusBadRequest, "paths is required")
+		return
+	}
+
+	batchID := newBatchID("upload")
+	requests := make([]services.TransferRequest, 0, len(req.Paths))
+	for _, p := range req.Paths {
+		if !filepath.IsAbs(p) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("path must be absolute: %s", p))
+			return
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !info.Mode().IsRegular() {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("not a regular file: %s", p))
+			return
+		}
+		requests = append(requests, services.TransferRequest{
+			Type:        services.TransferTypeUpload,
+			Source:      p,
+			Dest:        req.FolderID,
+			Name:        filepath.Base(p),
+			Size:        info.Size(),
+			SourceLabel: services.SourceLabelAPI,
+			BatchID:     batchID,
+			BatchLabel:  fmt.Sprintf("API upload: %d files", len(req.Paths)),
+			Tags:        req.Tags,
+		})
+	}
+
+	// Transfers outlive the request
+	dups := len(s.engine.TransferService().FindDuplicateTransfers(requests))
+	if err := s.engine.TransferService().StartTransfers(context.Background(), requests); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.logger.Info().Str("batch_id", batchID).Int("files", len(requests)-dups).Int("duplicates", dups).Msg("API upload started")
+	writeJSON(w, http.StatusAccepted, batchResponse{BatchID: batchID, Files: len(requests) - dups, Duplicates: dups})
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	var req downloadRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.FileIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "fileIds is required")
+		return
+	}
+	if !filepath.IsAbs(req.DestDir) {
+		writeError(w, http.StatusBadRequest, "destDir must be an absolute path")
+		return
+	}
+	if info, err := os.Stat(req.DestDir); err != nil || !info.IsDir() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("destDir is not a directory: %s", req.DestDir))
+		return
+	}
+
+	batchID := newBatchID("download")
+	requests := make([]services.TransferRequest, 0, len(req.FileIDs))
+	for _, id := range req.FileIDs {
+		info, err := s.engine.API().GetFileInfo(r.Context(), id)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("file %s: %v", id, err))
+			return
+		}
+		requests = append(requests, services.TransferRequest{
+			Type:        services.TransferTypeDownload,
+			Source:      id,
+			Dest:        filepath.Join(req.DestDir, filepath.Base(info.Name)),
+			Name:        info.Name,
+			Size:        info.DecryptedSize,
+			SourceLabel: services.SourceLabelAPI,
+			BatchID:     batchID,
+			BatchLabel:  fmt.Sprintf("API download: %d files", len(req.FileIDs)),
+			FileInfo:    info,
+		})
+	}
+
+	dups := len(s.engine.TransferService().FindDuplicateTransfers(requests))
+	if err := s.engine.TransferService().StartTransfers(context.Background(), requests); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.logger.Info().Str("batch_id", batchID).Int("files", len(requests)-dups).Int("duplicates", dups).Msg("API download started")
+	writeJSON(w, http.StatusAccepted, batchResponse{BatchID: batchID, Files: len(requests) - dups, Duplicates: dups})
+}
+
+func newBatchID(kind string) string {
+	return fmt.Sprintf("api_%s_%d", kind, time.Now().UnixNano())
+}
+
+// ---------------------------------------------------------------------------
+// Runs
+// ---------------------------------------------------------------------------
+
+type runRequest struct {
+	JobsFile         string `json:"jobsFile"`         // Absolute path to a jobs CSV or JSON file
+	ExtraInputFiles  string `json:"extraInputFiles"`  // Comma-separated paths and/or id:<fileId>
+	DecompressExtras bool   `json:"decompressExtras"` // Decompress extra files on the cluster
+}
+
+func (s *Server) handleStartRun(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !filepath.IsAbs(req.JobsFile) {
+		writeError(w, http.StatusBadRequest, "jobsFile must be an absolute path")
+		return
+	}
+	jobs, err := config.LoadJobs(req.JobsFile)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(jobs) == 0 {
+		writeError(w, http.StatusBadRequest, "jobs file has no jobs")
+		return
+	}
+
+	runID, err := s.startRun(r.Context(), jobs, core.RunOptions{
+		ExtraInputFiles:  req.ExtraInputFiles,
+		DecompressExtras: req.DecompressExtras,
+	})
+	if errors.Is(err, errRunActive) || errors.Is(err, runhistory.ErrDuplicateRun) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"runId": runID, "jobs": len(jobs)})
+}
+
+var errRunActive = errors.New("a run is already in progress")
+
+// startRun starts a PUR run in the background, as the GUI's bulk run does.
+func (s *Server) startRun(ctx context.Context, jobs []models.JobSpec, opts core.RunOptions) (string, error) {
+	if s.engine.IsRunActive() {
+		return "", errRunActive
+	}
+
+	runID := fmt.Sprintf("run_%d", time.Now().UnixNano())
+	stateDir := config.StateDirectory(s.engine.GetConfig())
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+	stateFile := filepath.Join(stateDir, runID+".state")
+
+	// Array specs run as their members, which get the state rows
+	jobs, err := jobarray.Expand(jobs)
+	if err != nil {
+		return "", err
+	}
+	fingerprint, err := s.engine.CheckDuplicateRun(jobs)
+	if err != nil {
+		return "", err
+	}
+	nameCtx, nameCancel := context.WithTimeout(ctx, constants.APIContextTimeout)
+	defer nameCancel()
+	if err := s.engine.ApplyJobNamePolicy(nameCtx, jobs, runID); err != nil {
+		return "", err
+	}
+	if err := s.engine.StartRun(runID, stateFile, len(jobs)); err != nil {
+		return "", fmt.Errorf("%w: %v", errRunActive, err)
+	}
+	s.engine.RecordRun(fingerprint, runID, stateFile, len(jobs))
+	if st := s.engine.GetState(); st != nil {
+		for i, job := range jobs {
+			st.InitializeState(i+1, job.JobName, job.Directory)
+		}
+		st.Save()
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.runCancel = cancel
+	s.lastRunID = runID
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		defer s.engine.EndRun()
+		if err := s.engine.RunFromSpecsWithOptions(runCtx, jobs, stateFile, opts); err != nil && runCtx.Err() == nil {
+			s.logger.Error().Err(err).Str("run_id", runID).Msg("API run failed")
+		}
+	}()
+	s.logger.Info().Str("run_id", runID).Int("jobs", len(jobs)).Msg("API run started")
+	return runID, nil
+}
+
+func (s *Server) runStatus() runStatus {
+	st := runStatus{}
+	if rc := s.engine.GetRunContext(); rc != nil {
+		st.Active = true
+		st.RunID = rc.RunID
+		started := rc.StartTime
+		st.StartedAt = &started
+	} else {
+		// Counts below belong to the last run until a new one starts
+		s.mu.Lock()
+		st.RunID = s.lastRunID
+		s.mu.Unlock()
+	}
+	st.Total, st.Completed, st.Failed, st.Pending = s.engine.GetRunStats()
+	return st
+}
+
+func (s *Server) handleRunStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.runStatus())
+}
+
+func (s *Server) handleCancelRun(w http.ResponseWriter, r *http.Request) {
+	if !s.engine.IsRunActive() {
+		writeError(w, http.StatusNotFound, "no run in progress")
+		return
+	}
+	s.mu.Lock()
+	if s.runCancel != nil {
+		s.runCancel()
+		s.runCancel = nil
+	}
+	s.mu.Unlock()
+	s.engine.Stop()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ---------------------------------------------------------------------------
+// Events (Server-Sent Events)
+// ---------------------------------------------------------------------------
+
+// handleEvents streams engine events as SSE. Each event's data line is the
+// same record `--events ndjson://...` writes.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	bus := s.engine.Events()
+	ch := bus.SubscribeAll()
+	defer bus.UnsubscribeAll(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(constants.ServeEventKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			line, err := events.MarshalNDJSON(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type(), line); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// JSON helpers
+// ---------------------------------------------------------------------------
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, constants.ServeMaxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/core"
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+const testToken = "test-token"
+
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+	engine, err := core.NewEngine(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(engine, testToken, logging.NewLogger("serve-test", nil)).Handler()
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	h := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"valid token", nil, http.StatusOK},
+		{"wrong token", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"no bearer prefix", map[string]string{"Authorization": testToken}, http.StatusUnauthorized},
+		{"browser origin", map[string]string{"Origin": "https://example.com"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, h, http.MethodGet, "/v1/status", "", tt.header)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	rec := do(t, newTestServer(t), http.MethodGet, "/v1/status", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var resp statusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Version == "" || resp.Run.Active {
+		t.Errorf("unexpected status %+v", resp)
+	}
+}
+
+func TestUploadValidation(t *testing.T) {
+	h := newTestServer(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty body", `{}`},
+		{"relative path", `{"paths":["input.dat"]}`},
+		{"missing file", `{"paths":[` + jsonString(filepath.Join(dir, "missing.dat")) + `]}`},
+		{"directory", `{"paths":[` + jsonString(dir) + `]}`},
+		{"unknown field", `{"paths":[],"folder":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, h, http.MethodPost, "/v1/uploads", tt.body, nil)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStartRunValidation(t *testing.T) {
+	h := newTestServer(t)
+	empty := filepath.Join(t.TempDir(), "jobs.csv")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{`{"jobsFile":"jobs.csv"}`, `{"jobsFile":` + jsonString(empty) + `}`} {
+		rec := do(t, h, http.MethodPost, "/v1/runs", body, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+
+	if rec := do(t, h, http.MethodDelete, "/v1/runs/current", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("cancel with no run: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestValidateListenAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:8642", false},
+		{"localhost:8642", false},
+		{"[::1]:8642", false},
+		{"0.0.0.0:8642", true},
+		{":8642", true},
+		{"192.168.1.10:8642", true},
+		{"127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateListenAddr(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateListenAddr(%q) err = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWriteTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "serve-token")
+	token, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 64 {
+		t.Errorf("token length = %d, want 64", len(token))
+	}
+	if err := WriteTokenFile(path, token); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(data)) != token {
+		t.Errorf("token file = %q", data)
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -338,6 +338,7 @@ func (m *MultiUserDaemon) startUserDaemon(profile UserProfile) error {
 		StateFile:     profile.StateFilePath,
 		Eligibility:   eligibility,
 		Filter:        filter,
+		Watchdog:      daemon.WatchdogConfigFromDaemonConf(daemonConf),
 	}
 
 	// Create the daemon
@@ -456,7 +457,8 @@ func (m *MultiUserDaemon) configChanged(entry *userDaemonEntry, profile UserProf
 		return true
 	}
 
-	// F2: detect changes to the per-user config.csv (APIBaseURL, proxy).
+	// F2: detect changes to the per-user config.csv (APIBaseURL, gateway
+	// settings, proxy).
 	// A missing or unreadable file returns an empty hash; if entry.configHash
 	// is also empty the compare is a no-op, so a missing file is not itself
 	// a false-positive restart trigger.
@@ -481,9 +483,11 @@ func hashUserConfig(cfg *config.Config) string {
 		return ""
 	}
 	h := sha256.New()
-	fmt.Fprintf(h, "%s|%s|%s|%s|%d|%s|%s|%t",
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%s|%d|%s|%s|%t",
 		cfg.APIBaseURL,
 		cfg.TenantURL,
+		cfg.APIBasePath,
+		cfg.APIHeaders,
 		cfg.ProxyMode,
 		cfg.ProxyHost,
 		cfg.ProxyPort,
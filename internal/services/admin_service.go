@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/viewer"
+)
+
+// AdminService lists and manages team members' Interlink jobs for
+// organization admins. Every call needs an API key with org admin
+// permissions; otherwise the API returns api.ErrAdminRequired.
+type AdminService struct {
+	apiClient *api.Client
+
+	mu sync.RWMutex
+}
+
+// NewAdminService creates an AdminService.
+func NewAdminService(apiClient *api.Client) *AdminService {
+	return &AdminService{apiClient: apiClient}
+}
+
+// SetAPIClient updates the API client (e.g., after credential change).
+func (s *AdminService) SetAPIClient(client *api.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiClient = client
+}
+
+func (s *AdminService) client() (*api.Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.apiClient == nil {
+		return nil, fmt.Errorf("API client not configured")
+	}
+	return s.apiClient, nil
+}
+
+// AdminJobFilter selects organization jobs for the admin view.
+type AdminJobFilter struct {
+	OrgCode string    // Empty = the API key owner's organization
+	Since   time.Time // Jobs created at or after this time
+	Tag     string    // Interlink marker tag; empty = constants.DefaultAdminJobTag
+	Owner   string    // Case-insensitive substring of the owner; empty = all
+	Status  string    // Case-insensitive job status; empty = all
+}
+
+// AdminJob is one team member's Interlink job.
+type AdminJob struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Owner     string   `json:"owner"`
+	Status    string   `json:"status"`
+	CreatedAt string   `json:"createdAt"`
+	Tags      []string `json:"tags"`
+}
+
+// AdminActionResult is the outcome of a bulk action on one job.
+type AdminActionResult struct {
+	JobID string `json:"jobId"`
+	Error string `json:"error,omitempty"`
+}
+
+// IsInterlinkJob reports whether a job's tags mark it as submitted via
+// Interlink: a tag equal to marker or starting with "marker-".
+func IsInterlinkJob(tags []string, marker string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, marker) || (len(t) > len(marker)+1 && strings.EqualFold(t[:len(marker)+1], marker+"-")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveOrgCode returns orgCode, or the organization of the API key owner
+// when orgCode is empty.
+func (s *AdminService) ResolveOrgCode(ctx context.Context, orgCode string) (string, error) {
+	if orgCode != "" {
+		return orgCode, nil
+	}
+	apiClient, err := s.client()
+	if err != nil {
+		return "", err
+	}
+	profile, err := apiClient.GetUserProfile(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user profile: %w", err)
+	}
+	if profile.Company.Code == "" {
+		return "", fmt.Errorf("API key is not part of an organization; set org_code")
+	}
+	return profile.Company.Code, nil
+}
+
+// ListInterlinkJobs lists recent organization jobs tagged as Interlink
+// jobs, newest first.
+func (s *AdminService) ListInterlinkJobs(ctx context.Context, filter AdminJobFilter) ([]AdminJob, error) {
+	apiClient, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	orgCode, err := s.ResolveOrgCode(ctx, filter.OrgCode)
+	if err != nil {
+		return nil, err
+	}
+	marker := filter.Tag
+	if marker == "" {
+		marker = constants.DefaultAdminJobTag
+	}
+
+	jobs, err := apiClient.ListOrganizationJobs(ctx, orgCode, filter.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cheap filters first: every remaining job costs a tags request
+	var candidates []models.OrgJob
+	for _, j := range jobs {
+		if filter.Owner != "" && !strings.Contains(strings.ToLower(j.Owner), strings.ToLower(filter.Owner)) {
+			continue
+		}
+		if filter.Status != "" && !strings.EqualFold(j.JobStatus.Status, filter.Status) {
+			continue
+		}
+		candidates = append(candidates, j)
+	}
+
+	tags := make([][]string, len(candidates))
+	errs := make([]error, len(candidates))
+	sem := make(chan struct{}, constants.AdminTagFetchWorkers)
+	var wg sync.WaitGroup
+	for i := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tags[i], errs[i] = apiClient.GetJobTags(ctx, candidates[i].ID)
+		}(i)
+	}
+	wg.Wait()
+
+	result := []AdminJob{}
+	for i, j := range candidates {
+		if errs[i] != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to get tags for job %s: %w", j.ID, errs[i])
+		}
+		if !IsInterlinkJob(tags[i], marker) {
+			continue
+		}
+		result = append(result, AdminJob{
+			ID:        j.ID,
+			Name:      j.Name,
+			Owner:     j.Owner,
+			Status:    j.JobStatus.Status,
+			CreatedAt: j.CreatedAt,
+			Tags:      tags[i],
+		})
+	}
+	return result, nil
+}
+
+// StopJobs stops each job, continuing past failures.
+func (s *AdminService) StopJobs(ctx context.Context, jobIDs []string) ([]AdminActionResult, error) {
+	if err := viewer.Check("stop jobs"); err != nil {
+		return nil, err
+	}
+
+	apiClient, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	return bulkJobAction(ctx, jobIDs, apiClient.StopJob), nil
+}
+
+// ArchiveJobs archives each job, continuing past failures.
+func (s *AdminService) ArchiveJobs(ctx context.Context, jobIDs []string) ([]AdminActionResult, error) {
+	if err := viewer.Check("archive jobs"); err != nil {
+		return nil, err
+	}
+
+	apiClient, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	return bulkJobAction(ctx, jobIDs, apiClient.ArchiveJob), nil
+}
+
+func bulkJobAction(ctx context.Context, jobIDs []string, action func(context.Context, string) error) []AdminActionResult {
+	results := make([]AdminActionResult, 0, len(jobIDs))
+	for _, id := range jobIDs {
+		res := AdminActionResult{JobID: id}
+		if err := ctx.Err(); err != nil {
+			res.Error = err.Error()
+		} else if err := action(ctx, id); err != nil {
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// StorageUsage returns per-member storage use, largest first.
+func (s *AdminService) StorageUsage(ctx context.Context, orgCode string) ([]models.MemberStorageUsage, error) {
+	apiClient, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	orgCode, err = s.ResolveOrgCode(ctx, orgCode)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := apiClient.GetOrganizationStorageUsage(ctx, orgCode)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].UsedBytes > usage[j].UsedBytes })
+	return usage, nil
+}
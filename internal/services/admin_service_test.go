@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+func newAdminTestServer(t *testing.T, orgJobsStatus int) (*AdminService, *[]string) {
+	t.Helper()
+	var archived []string
+	now := time.Now().UTC()
+	jobTags := map[string][]string{
+		"j1": {"interlink-v4.9.8", "run-pur_1"},
+		"j2": {"cfd"},
+		"j3": {"Interlink"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/users/me/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"email":"admin@acme.com","company":{"code":"acme"}}`))
+	})
+	mux.HandleFunc("/api/v2/organizations/acme/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		if orgJobsStatus != http.StatusOK {
+			w.WriteHeader(orgJobsStatus)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"next": nil,
+			"results": []map[string]any{
+				{"id": "j1", "name": "Wing_1", "owner": "alice@acme.com", "jobStatus": map[string]string{"content": "Executing"}, "dateInserted": now.Format(time.RFC3339)},
+				{"id": "j2", "name": "Other", "owner": "bob@acme.com", "jobStatus": map[string]string{"content": "Executing"}, "dateInserted": now.Format(time.RFC3339)},
+				{"id": "j3", "name": "Wing_2", "owner": "bob@acme.com", "jobStatus": map[string]string{"content": "Completed"}, "dateInserted": now.Format(time.RFC3339)},
+				{"id": "j4", "name": "Old", "owner": "alice@acme.com", "jobStatus": map[string]string{"content": "Completed"}, "dateInserted": now.AddDate(0, 0, -60).Format(time.RFC3339)},
+			},
+		})
+	})
+	mux.HandleFunc("/api/v3/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/") // api v3 jobs {id} {action}
+		id, action := parts[3], parts[4]
+		switch action {
+		case "tags":
+			var out []map[string]string
+			for _, tag := range jobTags[id] {
+				out = append(out, map[string]string{"name": tag})
+			}
+			json.NewEncoder(w).Encode(out)
+		case "archive":
+			if id == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			archived = append(archived, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := api.NewClientForTest(&config.Config{APIBaseURL: srv.URL, APIKey: "test"})
+	return NewAdminService(client), &archived
+}
+
+func TestAdminListInterlinkJobs(t *testing.T) {
+	s, _ := newAdminTestServer(t, http.StatusOK)
+	ctx := context.Background()
+	since := time.Now().AddDate(0, 0, -7)
+
+	jobs, err := s.ListInterlinkJobs(ctx, AdminJobFilter{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "j1" || jobs[1].ID != "j3" {
+		t.Errorf("jobs = %+v, want j1 and j3", jobs)
+	}
+
+	jobs, err = s.ListInterlinkJobs(ctx, AdminJobFilter{Since: since, Owner: "BOB", Status: "completed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "j3" {
+		t.Errorf("filtered jobs = %+v, want j3", jobs)
+	}
+}
+
+func TestAdminListRequiresAdmin(t *testing.T) {
+	s, _ := newAdminTestServer(t, http.StatusForbidden)
+	_, err := s.ListInterlinkJobs(context.Background(), AdminJobFilter{OrgCode: "acme"})
+	if !errors.Is(err, api.ErrAdminRequired) {
+		t.Errorf("err = %v, want ErrAdminRequired", err)
+	}
+}
+
+func TestAdminArchiveJobsContinuesPastFailures(t *testing.T) {
+	s, archived := newAdminTestServer(t, http.StatusOK)
+	results, err := s.ArchiveJobs(context.Background(), []string{"j1", "missing", "j3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Error != "" || results[1].Error == "" || results[2].Error != "" {
+		t.Errorf("results = %+v", results)
+	}
+	if strings.Join(*archived, ",") != "j1,j3" {
+		t.Errorf("archived = %v", *archived)
+	}
+}
+
+func TestIsInterlinkJob(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want bool
+	}{
+		{[]string{"interlink"}, true},
+		{[]string{"cfd", "interlink-v4.9.8"}, true},
+		{[]string{"INTERLINK-run"}, true},
+		{[]string{"interlinked"}, false},
+		{[]string{"interlink-"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsInterlinkJob(tt.tags, "interlink"); got != tt.want {
+			t.Errorf("IsInterlinkJob(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/rescale/rescale-int/internal/transfer/scan"
 	"github.com/rescale/rescale-int/internal/util/paths"
 	"github.com/rescale/rescale-int/internal/validation"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // FileService handles file and folder operations.
@@ -151,6 +152,10 @@ func (fs *FileService) ListLegacyFiles(ctx context.Context, cursor string, pageS
 }
 
 func (fs *FileService) CreateFolder(ctx context.Context, name string, parentID string) (string, error) {
+	if err := viewer.Check("create folder"); err != nil {
+		return "", err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -168,6 +173,10 @@ func (fs *FileService) CreateFolder(ctx context.Context, name string, parentID s
 }
 
 func (fs *FileService) DeleteFile(ctx context.Context, fileID string) error {
+	if err := viewer.Check("delete file"); err != nil {
+		return err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -184,6 +193,10 @@ func (fs *FileService) DeleteFile(ctx context.Context, fileID string) error {
 }
 
 func (fs *FileService) DeleteFolder(ctx context.Context, folderID string) error {
+	if err := viewer.Check("delete folder"); err != nil {
+		return err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -202,6 +215,10 @@ func (fs *FileService) DeleteFolder(ctx context.Context, folderID string) error
 // DeleteItems deletes multiple files and/or folders.
 // Returns the count of successfully deleted items.
 func (fs *FileService) DeleteItems(ctx context.Context, items []FileItem) (deleted int, failed int, err error) {
+	if err := viewer.Check("delete"); err != nil {
+		return 0, 0, err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -237,6 +254,10 @@ func (fs *FileService) DeleteItems(ctx context.Context, items []FileItem) (delet
 // This is the default delete path for user-initiated deletes. Permanent
 // deletion uses DeleteItems.
 func (fs *FileService) ArchiveItems(ctx context.Context, parentFolderID string, items []FileItem) (archived int, failed int, err error) {
+	if err := viewer.Check("archive"); err != nil {
+		return 0, 0, err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -270,6 +291,10 @@ func (fs *FileService) ArchiveItems(ctx context.Context, parentFolderID string,
 // PrepareUploadFolder creates the remote folder structure for a local folder.
 // Returns the mapping of local directories to remote folder IDs and the list of files to upload.
 func (fs *FileService) PrepareUploadFolder(ctx context.Context, localPath string, remoteFolderID string) (*UploadFolderResult, error) {
+	if err := viewer.Check("upload folder"); err != nil {
+		return nil, err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -359,6 +384,10 @@ func (fs *FileService) PrepareUploadFolder(ctx context.Context, localPath string
 // PrepareDownloadFolder scans a remote folder and prepares download specs.
 // Creates local directories and returns the list of files to download.
 func (fs *FileService) PrepareDownloadFolder(ctx context.Context, remoteFolderID string, localPath string, folderName string) ([]DownloadFileSpec, error) {
+	if err := viewer.Check("download folder"); err != nil {
+		return nil, err
+	}
+
 	fs.mu.RLock()
 	apiClient := fs.apiClient
 	fs.mu.RUnlock()
@@ -543,12 +572,20 @@ func (fs *FileService) ListTrashBinPage(ctx context.Context, cursor string, page
 // RecoverTrashItems restores a mix of trashed files and folders to their
 // original locations via a single bulk POST. The endpoint is all-or-nothing.
 func (fs *FileService) RecoverTrashItems(ctx context.Context, items []FileItem) (recovered int, failed int, err error) {
+	if err := viewer.Check("recover from trash"); err != nil {
+		return 0, 0, err
+	}
+
 	return fs.postTrashAction(ctx, "recover", items)
 }
 
 // PurgeTrashItems permanently deletes a mix of trashed files and folders via
 // a single bulk POST. This is irreversible. The endpoint is all-or-nothing.
 func (fs *FileService) PurgeTrashItems(ctx context.Context, items []FileItem) (deleted int, failed int, err error) {
+	if err := viewer.Check("delete from trash"); err != nil {
+		return 0, 0, err
+	}
+
 	return fs.postTrashAction(ctx, "delete", items)
 }
 
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/api"
+)
+
+// maxFolderWindowPageSize is the largest page_size the folder contents API accepts.
+const maxFolderWindowPageSize = 1000
+
+// ListFolderWindow returns one page (0-based) of a folder listing, sorted by
+// the server. Pages can be fetched in any order, so a browser only requests
+// the rows that are on screen instead of walking every cursor page of a
+// folder with 100k+ entries.
+func (fs *FileService) ListFolderWindow(ctx context.Context, folderID string, order FolderSort, page, pageSize int) (*FolderWindow, error) {
+	fs.mu.RLock()
+	apiClient := fs.apiClient
+	fs.mu.RUnlock()
+
+	if apiClient == nil {
+		return nil, fmt.Errorf("API client not configured")
+	}
+	if folderID == "" {
+		return nil, fmt.Errorf("folder ID is required")
+	}
+	if page < 0 {
+		page = 0
+	}
+	if pageSize <= 0 || pageSize > maxFolderWindowPageSize {
+		pageSize = maxFolderWindowPageSize
+	}
+
+	contents, err := apiClient.ListFolderContentsWindow(ctx, folderID, folderOrdering(order), page+1, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folder contents: %w", err)
+	}
+
+	return &FolderWindow{
+		FolderID: folderID,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    contents.Count,
+		Items:    orderedFolderItems(contents),
+	}, nil
+}
+
+// FindInFolder returns the index, in name order, of the first item whose name
+// starts at or after prefix (case-insensitive). It binary-searches pages, so
+// locating a name in a 100k-item folder takes about log2(pages)+1 requests.
+// The result may equal the folder's item count when every name sorts before prefix.
+func (fs *FileService) FindInFolder(ctx context.Context, folderID, prefix string, descending bool, pageSize int) (int, error) {
+	order := FolderSort{Field: "name", Descending: descending}
+
+	pages := map[int][]FileItem{}
+	fetch := func(page int) ([]FileItem, error) {
+		if items, ok := pages[page]; ok {
+			return items, nil
+		}
+		window, err := fs.ListFolderWindow(ctx, folderID, order, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		pageSize = window.PageSize
+		pages[page] = window.Items
+		return window.Items, nil
+	}
+
+	first, err := fs.ListFolderWindow(ctx, folderID, order, 0, pageSize)
+	if err != nil {
+		return 0, err
+	}
+	pageSize = first.PageSize
+	pages[0] = first.Items
+
+	pageCount := (first.Total + pageSize - 1) / pageSize
+	return findNameIndex(pageCount, pageSize, fetch, prefix, descending)
+}
+
+// findNameIndex binary-searches name-sorted pages for the first item that does
+// not sort before prefix. fetch returns the items of a 0-based page.
+func findNameIndex(pageCount, pageSize int, fetch func(page int) ([]FileItem, error), prefix string, descending bool) (int, error) {
+	if pageCount <= 0 {
+		return 0, nil
+	}
+
+	target := strings.ToLower(prefix)
+	before := func(name string) bool {
+		name = strings.ToLower(name)
+		if descending {
+			// Every name starting with prefix sorts below prefix+U+FFFF
+			return name > target+"\uffff"
+		}
+		return name < target
+	}
+
+	lo, hi := 0, pageCount-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		items, err := fetch(mid)
+		if err != nil {
+			return 0, err
+		}
+		if len(items) > 0 && before(items[len(items)-1].Name) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	items, err := fetch(lo)
+	if err != nil {
+		return 0, err
+	}
+	for i, item := range items {
+		if !before(item.Name) {
+			return lo*pageSize + i, nil
+		}
+	}
+	return lo*pageSize + len(items), nil
+}
+
+// folderOrdering maps a FolderSort to the API's ordering parameter.
+func folderOrdering(order FolderSort) string {
+	field := "name"
+	switch order.Field {
+	case "size":
+		field = "decryptedSize"
+	case "modTime":
+		field = "dateUploaded"
+	}
+	if order.Descending {
+		return "-" + field
+	}
+	return field
+}
+
+// orderedFolderItems converts an API page to FileItems in the order the
+// server returned them (the API client splits folders and files apart).
+func orderedFolderItems(contents *api.FolderContents) []FileItem {
+	type positioned struct {
+		pos  int
+		item FileItem
+	}
+	all := make([]positioned, 0, len(contents.Folders)+len(contents.Files))
+	for _, f := range contents.Folders {
+		all = append(all, positioned{f.Position, FileItem{
+			ID:       f.ID,
+			Name:     f.Name,
+			IsFolder: true,
+			ModTime:  f.DateUploaded,
+		}})
+	}
+	for _, f := range contents.Files {
+		all = append(all, positioned{f.Position, FileItem{
+			ID:      f.ID,
+			Name:    f.Name,
+			Size:    f.DecryptedSize,
+			ModTime: f.DateUploaded,
+		}})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].pos < all[j].pos })
+
+	items := make([]FileItem, len(all))
+	for i, p := range all {
+		items[i] = p.item
+	}
+	return items
+}
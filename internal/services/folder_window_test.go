@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/api"
+)
+
+// namePages splits names, sorted case-insensitively, into pages of pageSize.
+func namePages(names []string, pageSize int, descending bool) (int, func(page int) ([]FileItem, error), *int) {
+	sorted := append([]string(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if descending {
+			return strings.ToLower(sorted[i]) > strings.ToLower(sorted[j])
+		}
+		return strings.ToLower(sorted[i]) < strings.ToLower(sorted[j])
+	})
+	fetches := 0
+	fetch := func(page int) ([]FileItem, error) {
+		fetches++
+		start := page * pageSize
+		end := min(start+pageSize, len(sorted))
+		items := make([]FileItem, 0, end-start)
+		for _, name := range sorted[start:end] {
+			items = append(items, FileItem{ID: name, Name: name})
+		}
+		return items, nil
+	}
+	return (len(sorted) + pageSize - 1) / pageSize, fetch, &fetches
+}
+
+func TestFindNameIndex(t *testing.T) {
+	names := make([]string, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		names = append(names, fmt.Sprintf("run_%05d.dat", i))
+	}
+
+	pageCount, fetch, fetches := namePages(names, 100, false)
+	idx, err := findNameIndex(pageCount, 100, fetch, "RUN_04321", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 4321 {
+		t.Errorf("index = %d, want 4321", idx)
+	}
+	if *fetches > 9 {
+		t.Errorf("fetched %d pages, want a binary search (<= 9)", *fetches)
+	}
+
+	if idx, _ := findNameIndex(pageCount, 100, fetch, "zzz", false); idx != 10000 {
+		t.Errorf("index past end = %d, want 10000", idx)
+	}
+	if idx, _ := findNameIndex(pageCount, 100, fetch, "", false); idx != 0 {
+		t.Errorf("empty prefix index = %d, want 0", idx)
+	}
+}
+
+func TestFindNameIndexDescending(t *testing.T) {
+	names := []string{"alpha", "beta", "beta2", "betamax", "delta", "gamma"}
+	pageCount, fetch, _ := namePages(names, 2, true)
+
+	// Descending: gamma, delta, betamax, beta2, beta, alpha
+	idx, err := findNameIndex(pageCount, 2, fetch, "beta", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 2 {
+		t.Errorf("index = %d, want 2 (betamax)", idx)
+	}
+}
+
+func TestOrderedFolderItemsKeepsServerOrder(t *testing.T) {
+	contents := &api.FolderContents{
+		Folders: []api.FolderInfo{{ID: "d1", Name: "b-folder", Position: 1}},
+		Files: []api.FileInfo{
+			{ID: "f1", Name: "a.dat", Position: 0},
+			{ID: "f2", Name: "c.dat", Position: 2},
+		},
+	}
+	items := orderedFolderItems(contents)
+	var got []string
+	for _, item := range items {
+		got = append(got, item.ID)
+	}
+	if strings.Join(got, ",") != "f1,d1,f2" {
+		t.Errorf("order = %v, want server order f1,d1,f2", got)
+	}
+	if !items[1].IsFolder {
+		t.Error("folder entry lost IsFolder")
+	}
+}
+
+func TestFolderOrdering(t *testing.T) {
+	cases := map[FolderSort]string{
+		{Field: "name"}:                      "name",
+		{Field: "size", Descending: true}:    "-decryptedSize",
+		{Field: "modTime"}:                   "dateUploaded",
+		{Field: "unknown", Descending: true}: "-name",
+	}
+	for order, want := range cases {
+		if got := folderOrdering(order); got != want {
+			t.Errorf("folderOrdering(%+v) = %q, want %q", order, got, want)
+		}
+	}
+}
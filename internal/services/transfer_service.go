@@ -24,8 +24,12 @@ import (
 	"github.com/rescale/rescale-int/internal/ratelimit"
 	"github.com/rescale/rescale-int/internal/reporting"
 	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/tracing"
 	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/transfer/postprocess"
 	"github.com/rescale/rescale-int/internal/util/tags"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TransferService handles upload and download orchestration.
@@ -48,6 +52,13 @@ type TransferService struct {
 	// Credential manager (cached, shared across transfers)
 	credManager *credentials.Manager
 
+	// Post-download chain spec for requests that don't set their own
+	postDownload string
+
+	// registerMu makes the duplicate check and registration of
+	// StartTransfers atomic, so a double-clicked upload is queued once
+	registerMu sync.Mutex
+
 	mu sync.RWMutex
 }
 
@@ -56,6 +67,10 @@ type TransferServiceConfig struct {
 	// MaxConcurrent is the maximum number of concurrent transfers.
 	// Defaults to constants.DefaultMaxConcurrent (5).
 	MaxConcurrent int
+
+	// PostDownload is the default post-download chain spec (the
+	// post_download config setting).
+	PostDownload string
 }
 
 func NewTransferService(apiClient *api.Client, eventBus *events.EventBus, config TransferServiceConfig) *TransferService {
@@ -77,6 +92,8 @@ func NewTransferService(apiClient *api.Client, eventBus *events.EventBus, config
 		semaphore:   make(chan struct{}, config.MaxConcurrent),
 		resourceMgr: resourceMgr,
 		transferMgr: transferMgr,
+
+		postDownload: config.PostDownload,
 	}
 
 	// Set up retry executor
@@ -93,6 +110,25 @@ func (ts *TransferService) SetAPIClient(client *api.Client) {
 	ts.credManager = nil // Clear cached credential manager
 }
 
+// SetPostDownload updates the default post-download chain spec. Downloads
+// already running keep the chain they started with.
+func (ts *TransferService) SetPostDownload(spec string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.postDownload = spec
+}
+
+// postDownloadChain returns the chain to run after a download with the
+// given per-request spec.
+func (ts *TransferService) postDownloadChain(spec string) (postprocess.Chain, error) {
+	if spec == "" {
+		ts.mu.RLock()
+		spec = ts.postDownload
+		ts.mu.RUnlock()
+	}
+	return postprocess.Parse(spec)
+}
+
 // GetQueue returns the underlying transfer queue.
 // Used by GUI components that need direct queue access.
 func (ts *TransferService) GetQueue() *transfer.Queue {
@@ -122,6 +158,9 @@ func (ts *TransferService) StartTransfers(ctx context.Context, requests []Transf
 	if len(requests) == 0 {
 		return nil
 	}
+	if err := viewer.Check("transfer"); err != nil {
+		return err
+	}
 
 	ts.mu.RLock()
 	apiClient := ts.apiClient
@@ -141,8 +180,22 @@ func (ts *TransferService) StartTransfers(ctx context.Context, requests []Transf
 
 	// Pre-register ALL tasks synchronously before launching async workers.
 	// This ensures tasks are visible in the queue before StartTransfers() returns.
+	// Requests repeating a pending transfer are skipped unless AllowDuplicate.
+	ts.registerMu.Lock()
+	defer ts.registerMu.Unlock()
+	duplicates := make(map[int]bool)
+	for _, dup := range ts.findDuplicatesLocked(requests) {
+		if !requests[dup.Index].AllowDuplicate {
+			duplicates[dup.Index] = true
+		}
+	}
 	var uploadItems, downloadItems []preRegItem
-	for _, req := range requests {
+	for i, req := range requests {
+		if duplicates[i] {
+			ts.logger.Info().Str("type", string(req.Type)).Str("source", req.Source).Str("dest", req.Dest).
+				Msg("Skipping transfer already queued or running")
+			continue
+		}
 		if req.Type == TransferTypeUpload {
 			taskID := ts.registerUploadTask(req)
 			uploadItems = append(uploadItems, preRegItem{req: req, taskID: taskID})
@@ -163,6 +216,38 @@ func (ts *TransferService) StartTransfers(ctx context.Context, requests []Transf
 	return nil
 }
 
+// FindDuplicateTransfers returns the requests that repeat a transfer already
+// queued or running, or an earlier request in the list, so callers can ask
+// whether to skip them or set AllowDuplicate.
+func (ts *TransferService) FindDuplicateTransfers(requests []TransferRequest) []DuplicateTransfer {
+	ts.registerMu.Lock()
+	defer ts.registerMu.Unlock()
+	return ts.findDuplicatesLocked(requests)
+}
+
+// findDuplicatesLocked implements FindDuplicateTransfers. Caller must hold
+// registerMu.
+func (ts *TransferService) findDuplicatesLocked(requests []TransferRequest) []DuplicateTransfer {
+	type key struct {
+		typ          TransferType
+		source, dest string
+	}
+	var duplicates []DuplicateTransfer
+	seen := make(map[key]bool)
+	for i, req := range requests {
+		if task, ok := ts.queue.FindPending(transfer.TaskType(req.Type), req.Source, req.Dest); ok {
+			duplicates = append(duplicates, DuplicateTransfer{Index: i, TaskID: task.ID, State: TransferState(task.State)})
+			continue
+		}
+		k := key{req.Type, filepath.Clean(req.Source), filepath.Clean(req.Dest)}
+		if seen[k] {
+			duplicates = append(duplicates, DuplicateTransfer{Index: i})
+		}
+		seen[k] = true
+	}
+	return duplicates
+}
+
 // WarmCredentialCache pre-warms the credential cache.
 // Exported so callers (file_bindings.go) can invoke it synchronously
 // before scan/download to eliminate credential lock contention on first download.
@@ -268,6 +353,10 @@ func (ts *TransferService) StartStreamingDownloadBatch(
 	batchID, batchLabel, sourceLabel string,
 	cancelFn context.CancelFunc,
 ) error {
+	if err := viewer.Check("download"); err != nil {
+		return err
+	}
+
 	ts.mu.RLock()
 	apiClient := ts.apiClient
 	ts.mu.RUnlock()
@@ -363,6 +452,10 @@ func (ts *TransferService) StartStreamingUploadBatch(
 	batchID, batchLabel, sourceLabel string,
 	cancelFn context.CancelFunc,
 ) error {
+	if err := viewer.Check("upload"); err != nil {
+		return err
+	}
+
 	ts.mu.RLock()
 	apiClient := ts.apiClient
 	ts.mu.RUnlock()
@@ -511,6 +604,9 @@ func (ts *TransferService) registerUploadTask(req TransferRequest) string {
 	} else {
 		task = ts.queue.TrackTransferWithLabel(fileName, req.Size, transfer.TaskTypeUpload, req.Source, req.Dest, sourceLabel)
 	}
+	task.Tags = req.Tags
+	task.RemoteName = req.RemoteName
+	task.ReplaceFileIDs = req.ReplaceFileIDs
 	return task.ID
 }
 
@@ -524,10 +620,20 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 		fileName = filepath.Base(req.Source)
 	}
 
-	// Create derived context for cancel support
-	uploadCtx, uploadCancel := context.WithCancel(ctx)
+	// Deferred first so it runs last, once the slot is released: measures
+	// cancel-to-idle latency
+	defer ts.queue.MarkIdle(taskID)
+
+	// Create derived context for cancel support; the task ID correlates the
+	// upload's log lines with its queue events
+	uploadCtx, uploadCancel := context.WithCancel(logging.WithTransferID(ctx, taskID))
 	defer uploadCancel()
 
+	// The span covers the time queued; acquiring a slot is an event on it
+	uploadCtx, span := tracing.Start(uploadCtx, "transfer.upload",
+		attribute.String("rescale.task_id", taskID), attribute.String("file.name", fileName))
+	defer span.End()
+
 	// Set cancel fn early — enables CancelBatch to cancel even while queued
 	ts.queue.SetCancel(taskID, uploadCancel)
 
@@ -539,6 +645,12 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 		}
 	}()
 
+	// Hold while offline; the task stays queued until the network is back
+	if err := ts.queue.WaitForNetwork(uploadCtx); err != nil {
+		ts.queue.FailIfNotTerminal(taskID, err)
+		return
+	}
+
 	// Wait for semaphore slot
 	slotsBefore := atomic.LoadInt32(&ts.activeSlots)
 	log.Printf("[SLOT] UPLOAD %s: waiting (active=%d/%d)", fileName, slotsBefore, cap(ts.semaphore))
@@ -555,6 +667,7 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 
 	slotsNow := atomic.AddInt32(&ts.activeSlots, 1)
 	log.Printf("[SLOT] UPLOAD %s: ACQUIRED (active=%d/%d)", fileName, slotsNow, cap(ts.semaphore))
+	span.AddEvent("transfer slot acquired")
 
 	defer func() {
 		<-ts.semaphore
@@ -606,9 +719,10 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 
 	// Execute upload with progress callback
 	cloudFile, err := upload.UploadFile(uploadCtx, upload.UploadParams{
-		LocalPath: req.Source,
-		FolderID:  req.Dest,
-		APIClient: apiClient,
+		LocalPath:  req.Source,
+		FolderID:   req.Dest,
+		APIClient:  apiClient,
+		RemoteName: req.RemoteName,
 		ProgressCallback: func(progress float64) {
 			ts.queue.StartTransfer(taskID)
 			ts.queue.UpdateProgress(taskID, progress)
@@ -622,6 +736,10 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 			ts.queue.FailIfNotTerminal(taskID, err)
 			return
 		}
+		if delay, ok := ts.scheduleAutoRetry(taskID, err); ok {
+			ts.logger.Warn().Err(err).Str("path", req.Source).Dur("retry_in", delay).Msg("Upload failed, retry scheduled")
+			return
+		}
 		ts.queue.Fail(taskID, err)
 		ts.logger.Error().Err(err).Str("path", req.Source).Msg("Upload failed")
 		return
@@ -629,11 +747,58 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 
 	// Apply tags after successful upload (non-fatal)
 	ts.applyTags(ctx, apiClient, cloudFile.ID, req.Tags, fileName)
+	ts.deleteReplaced(ctx, apiClient, req.ReplaceFileIDs, fileName)
 
 	ts.queue.Complete(taskID)
 	ts.logger.Info().Str("path", req.Source).Msg("File uploaded")
 }
 
+// scheduleAutoRetry re-queues a failed upload with exponential delay when the
+// error is transient (network or server-side) and the task still has
+// automatic attempts left. A part that storage rejected outright
+// (a permanent cloud/transfer.PartError) fails the task at once. Returns the
+// scheduled delay and true on success; false means the caller should fail
+// the task.
+func (ts *TransferService) scheduleAutoRetry(taskID string, err error) (time.Duration, bool) {
+	switch inthttp.ClassifyError(err) {
+	case inthttp.ErrorTypeNetwork, inthttp.ErrorTypeRetryable:
+	default:
+		return 0, false
+	}
+
+	task, ok := ts.queue.GetTask(taskID)
+	if !ok {
+		return 0, false
+	}
+
+	// Failures while offline are retried regardless of the attempt limit;
+	// the queue holds the task and re-queues it when the network is back.
+	delay := autoRetryDelay(task.RetryAttempts)
+	if ts.queue.NetworkPaused() {
+		delay = constants.NetworkOfflineRetryDelay
+	} else if task.RetryAttempts >= constants.TransferAutoRetryMaxAttempts {
+		return 0, false
+	}
+	if !ts.queue.ScheduleRetry(taskID, err, delay) {
+		return 0, false
+	}
+	return delay, true
+}
+
+// autoRetryDelay returns the wait before automatic retry number attempt+1:
+// the initial delay doubled per previous attempt, capped at the maximum.
+// Deterministic (no jitter) so the Transfers tab countdown is exact.
+func autoRetryDelay(attempt int) time.Duration {
+	delay := constants.TransferAutoRetryInitialDelay
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= constants.TransferAutoRetryMaxDelay {
+			return constants.TransferAutoRetryMaxDelay
+		}
+	}
+	return delay
+}
+
 // UploadFileSync uploads a file synchronously with transfer queue visibility.
 // Blocks until the upload completes and returns the result.
 //
@@ -641,6 +806,10 @@ func (ts *TransferService) executeUploadTask(ctx context.Context, req TransferRe
 //   - If params.TransferHandle is provided: used for upload, NOT completed (caller owns)
 //   - If params.TransferHandle is nil: allocated internally and completed after upload
 func (ts *TransferService) UploadFileSync(ctx context.Context, req TransferRequest, params UploadFileSyncParams) (*models.CloudFile, error) {
+	if err := viewer.Check("upload"); err != nil {
+		return nil, err
+	}
+
 	ts.mu.RLock()
 	apiClient := ts.apiClient
 	ts.mu.RUnlock()
@@ -667,12 +836,25 @@ func (ts *TransferService) UploadFileSync(ctx context.Context, req TransferReque
 		task = ts.queue.TrackTransferWithLabel(fileName, req.Size, transfer.TaskTypeUpload, req.Source, req.Dest, sourceLabel)
 	}
 	taskID := task.ID
+	// Uploads for a PUR run carry its ID in ctx
+	if runID := logging.CorrelationFrom(ctx).RunID; runID != "" {
+		ts.queue.SetRunID(taskID, runID)
+	}
+
+	// Deferred first so it runs last, once the slot is released: measures
+	// cancel-to-idle latency
+	defer ts.queue.MarkIdle(taskID)
 
 	// Create derived context for cancel support
-	uploadCtx, uploadCancel := context.WithCancel(ctx)
+	uploadCtx, uploadCancel := context.WithCancel(logging.WithTransferID(ctx, taskID))
 	defer uploadCancel()
 	ts.queue.SetCancel(taskID, uploadCancel)
 
+	// The span covers the time queued; acquiring a slot is an event on it
+	uploadCtx, span := tracing.Start(uploadCtx, "transfer.upload",
+		attribute.String("rescale.task_id", taskID), attribute.String("file.name", fileName))
+	defer span.End()
+
 	// Acquire semaphore slot (unified concurrency with File Browser)
 	select {
 	case ts.semaphore <- struct{}{}:
@@ -681,6 +863,7 @@ func (ts *TransferService) UploadFileSync(ctx context.Context, req TransferReque
 		return nil, uploadCtx.Err()
 	}
 	atomic.AddInt32(&ts.activeSlots, 1)
+	span.AddEvent("transfer slot acquired")
 
 	// Signal active transfer for sleep inhibition + coordinator keepalive.
 	// UploadFileSync bypasses RunBatch/RunBatchFromChannel, so must signal directly.
@@ -732,6 +915,7 @@ func (ts *TransferService) UploadFileSync(ctx context.Context, req TransferReque
 		APIClient:        apiClient,
 		ProgressCallback: progressCallback,
 		TransferHandle:   transferHandle,
+		PauseInBlackout:  params.PauseInBlackout,
 	})
 
 	if err != nil {
@@ -750,6 +934,19 @@ func (ts *TransferService) UploadFileSync(ctx context.Context, req TransferReque
 	return cloudFile, nil
 }
 
+// deleteReplaced deletes the files an upload overwrote. Failures are logged
+// as warnings; the new file is already in place.
+func (ts *TransferService) deleteReplaced(ctx context.Context, apiClient *api.Client, fileIDs []string, fileName string) {
+	for _, id := range fileIDs {
+		if err := apiClient.DeleteFile(ctx, id); err != nil {
+			ts.logger.Warn().Err(err).
+				Str("file", fileName).
+				Str("replaced_file_id", id).
+				Msg("Failed to delete the file being replaced (non-fatal)")
+		}
+	}
+}
+
 // applyTags applies tags to a file after upload. Failures are logged as warnings.
 func (ts *TransferService) applyTags(ctx context.Context, apiClient *api.Client, fileID string, rawTags []string, fileName string) {
 	normalized := tags.NormalizeTags(rawTags)
@@ -792,6 +989,7 @@ func (ts *TransferService) registerDownloadTask(req TransferRequest) string {
 	} else {
 		task = ts.queue.TrackTransferWithLabel(fileName, req.Size, transfer.TaskTypeDownload, req.Source, req.Dest, sourceLabel)
 	}
+	task.PostProcess = req.PostProcess
 	return task.ID
 }
 
@@ -805,10 +1003,20 @@ func (ts *TransferService) executeDownloadTask(ctx context.Context, req Transfer
 		fileName = req.Source
 	}
 
-	// Create derived context for cancel support
-	dlCtx, dlCancel := context.WithCancel(ctx)
+	// Deferred first so it runs last, once the slot is released: measures
+	// cancel-to-idle latency
+	defer ts.queue.MarkIdle(taskID)
+
+	// Create derived context for cancel support; the task ID correlates the
+	// download's log lines with its queue events
+	dlCtx, dlCancel := context.WithCancel(logging.WithTransferID(ctx, taskID))
 	defer dlCancel()
 
+	// The span covers the time queued; acquiring a slot is an event on it
+	dlCtx, span := tracing.Start(dlCtx, "transfer.download",
+		attribute.String("rescale.task_id", taskID), attribute.String("file.name", fileName))
+	defer span.End()
+
 	// Set cancel fn early — enables CancelBatch to cancel even while queued
 	ts.queue.SetCancel(taskID, dlCancel)
 
@@ -820,6 +1028,12 @@ func (ts *TransferService) executeDownloadTask(ctx context.Context, req Transfer
 		}
 	}()
 
+	// Hold while offline; the task stays queued until the network is back
+	if err := ts.queue.WaitForNetwork(dlCtx); err != nil {
+		ts.queue.FailIfNotTerminal(taskID, err)
+		return
+	}
+
 	// Wait for semaphore slot
 	slotsBefore := atomic.LoadInt32(&ts.activeSlots)
 	log.Printf("[SLOT] DOWNLOAD %s: waiting (active=%d/%d)", fileName, slotsBefore, cap(ts.semaphore))
@@ -836,6 +1050,7 @@ func (ts *TransferService) executeDownloadTask(ctx context.Context, req Transfer
 
 	slotsNow := atomic.AddInt32(&ts.activeSlots, 1)
 	log.Printf("[SLOT] DOWNLOAD %s: ACQUIRED (active=%d/%d)", fileName, slotsNow, cap(ts.semaphore))
+	span.AddEvent("transfer slot acquired")
 	log.Printf("[TIMING] DOWNLOAD %s: semaphore acquired, starting credential check", fileName)
 
 	defer func() {
@@ -911,17 +1126,63 @@ func (ts *TransferService) executeDownloadTask(ctx context.Context, req Transfer
 			ts.queue.FailIfNotTerminal(taskID, err)
 			return
 		}
+		// Connections dropped by a network change: re-queue instead of failing
+		if inthttp.IsConnectionLost(err) || ts.queue.NetworkPaused() {
+			if delay, ok := ts.scheduleAutoRetry(taskID, err); ok {
+				ts.logger.Warn().Err(err).Str("file_id", req.Source).Dur("retry_in", delay).Msg("Download interrupted by network change, retry scheduled")
+				return
+			}
+		}
 		ts.queue.Fail(taskID, err)
 		ts.logger.Error().Err(err).Str("file_id", req.Source).Str("name", fileName).Msg("Download failed")
-	} else {
-		ts.queue.Complete(taskID)
-		ts.logger.Info().Str("file_id", req.Source).Str("local_path", req.Dest).Msg("File downloaded")
+		return
+	}
+
+	if err := ts.runPostDownload(dlCtx, req, taskID, localPath); err != nil {
+		if errors.Is(err, context.Canceled) {
+			ts.queue.FailIfNotTerminal(taskID, err)
+			return
+		}
+		ts.queue.Fail(taskID, err)
+		ts.logger.Error().Err(err).Str("file_id", req.Source).Str("local_path", localPath).Msg("Post-download processing failed")
+		return
+	}
+	ts.queue.Complete(taskID)
+	ts.logger.Info().Str("file_id", req.Source).Str("local_path", req.Dest).Msg("File downloaded")
+}
+
+// runPostDownload runs the request's post-download chain on the file at
+// localPath, recording the status of each step on the task. The task stays
+// active until the chain finishes; a failing step fails the task, and a
+// retry downloads the file again.
+func (ts *TransferService) runPostDownload(ctx context.Context, req TransferRequest, taskID, localPath string) error {
+	chain, err := ts.postDownloadChain(req.PostProcess)
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	ts.queue.SetSteps(taskID, chain.Pending())
+	f := &postprocess.File{Path: localPath, FileID: req.Source}
+	err = chain.Run(ctx, f, func(steps []postprocess.Status) {
+		ts.queue.SetSteps(taskID, steps)
+	})
+	if err != nil {
+		return fmt.Errorf("post-download %w", err)
 	}
+	ts.logger.Info().Str("file_id", req.Source).Str("path", f.Path).Str("chain", chain.String()).Msg("Post-download processing complete")
+	return nil
 }
 
 // ExecuteRetry implements transfer.RetryExecutor.
 // Called by the queue when a user requests retry on a failed task.
 func (ts *TransferService) ExecuteRetry(task *transfer.TransferTask) {
+	if err := viewer.Check("retry"); err != nil {
+		ts.queue.Fail(task.ID, err)
+		return
+	}
+
 	ts.mu.RLock()
 	apiClient := ts.apiClient
 	ts.mu.RUnlock()
@@ -945,6 +1206,10 @@ func (ts *TransferService) ExecuteRetry(task *transfer.TransferTask) {
 			Dest:   task.Dest,
 			Name:   task.Name,
 			Size:   task.Size,
+			Tags:   task.Tags,
+
+			RemoteName:     task.RemoteName,
+			ReplaceFileIDs: task.ReplaceFileIDs,
 		}
 		ts.executeUploadRetry(ctx, req, task.ID, apiClient)
 	} else {
@@ -954,6 +1219,8 @@ func (ts *TransferService) ExecuteRetry(task *transfer.TransferTask) {
 			Dest:   task.Dest,
 			Name:   task.Name,
 			Size:   task.Size,
+
+			PostProcess: task.PostProcess,
 		}
 		ts.executeDownloadRetry(ctx, req, task.ID, apiClient)
 	}
@@ -1031,16 +1298,22 @@ func (ts *TransferService) RetryTransfer(taskID string) (string, error) {
 	return ts.queue.Retry(taskID)
 }
 
+// GiveUpTransfer abandons a pending automatic retry, leaving the task failed.
+func (ts *TransferService) GiveUpTransfer(taskID string) error {
+	return ts.queue.GiveUp(taskID)
+}
+
 func (ts *TransferService) GetStats() TransferStats {
 	qStats := ts.queue.GetStats()
 	return TransferStats{
-		Queued:       qStats.Queued,
-		Initializing: qStats.Initializing,
-		Active:       qStats.Active,
-		Paused:       qStats.Paused,
-		Completed:    qStats.Completed,
-		Failed:       qStats.Failed,
-		Cancelled:    qStats.Cancelled,
+		Queued:        qStats.Queued,
+		Initializing:  qStats.Initializing,
+		Active:        qStats.Active,
+		Paused:        qStats.Paused,
+		Completed:     qStats.Completed,
+		Failed:        qStats.Failed,
+		Cancelled:     qStats.Cancelled,
+		CancelLatency: ts.queue.GetCancelLatency(),
 	}
 }
 
@@ -1051,22 +1324,25 @@ func (ts *TransferService) GetTasks() []TransferTask {
 	for i := range qTasks {
 		qt := &qTasks[i]
 		tasks[i] = TransferTask{
-			ID:          qt.ID,
-			Type:        TransferType(qt.Type),
-			State:       TransferState(qt.State),
-			Name:        qt.Name,
-			Source:      qt.Source,
-			Dest:        qt.Dest,
-			Size:        qt.Size,
-			SourceLabel: qt.SourceLabel,
-			BatchID:     qt.BatchID,
-			BatchLabel:  qt.BatchLabel,
-			Progress:    qt.Progress,
-			Speed:       qt.Speed,
-			Error:       qt.Error,
-			CreatedAt:   qt.CreatedAt,
-			StartedAt:   qt.StartedAt,
-			CompletedAt: qt.CompletedAt,
+			ID:            qt.ID,
+			Type:          TransferType(qt.Type),
+			State:         TransferState(qt.State),
+			Name:          qt.Name,
+			Source:        qt.Source,
+			Dest:          qt.Dest,
+			Size:          qt.Size,
+			SourceLabel:   qt.SourceLabel,
+			BatchID:       qt.BatchID,
+			BatchLabel:    qt.BatchLabel,
+			Progress:      qt.Progress,
+			Speed:         qt.Speed,
+			Error:         qt.Error,
+			Steps:         qt.Steps,
+			RetryAttempts: qt.RetryAttempts,
+			NextRetryAt:   qt.NextRetryAt,
+			CreatedAt:     qt.CreatedAt,
+			StartedAt:     qt.StartedAt,
+			CompletedAt:   qt.CompletedAt,
 		}
 	}
 	return tasks
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/events"
 	"github.com/rescale/rescale-int/internal/transfer"
 )
@@ -104,6 +105,70 @@ func TestCancelAllEmpty(t *testing.T) {
 // TestStreamingDownloadBatchAdaptiveConcurrency verifies that
 // StartStreamingDownloadBatch uses RunBatchFromChannel with adaptive concurrency
 // from the ResourceManager, not hardcoded workers.
+func TestAutoRetryDelay(t *testing.T) {
+	initial := constants.TransferAutoRetryInitialDelay
+	if got := autoRetryDelay(0); got != initial {
+		t.Errorf("autoRetryDelay(0) = %v, want %v", got, initial)
+	}
+	if got := autoRetryDelay(2); got != 4*initial {
+		t.Errorf("autoRetryDelay(2) = %v, want %v", got, 4*initial)
+	}
+	if got := autoRetryDelay(50); got != constants.TransferAutoRetryMaxDelay {
+		t.Errorf("autoRetryDelay(50) = %v, want cap %v", got, constants.TransferAutoRetryMaxDelay)
+	}
+}
+
+func TestScheduleAutoRetry(t *testing.T) {
+	ts := NewTransferService(nil, events.NewEventBus(100), TransferServiceConfig{})
+	q := ts.GetQueue()
+
+	transient := q.TrackTransfer("flaky.dat", 100, transfer.TaskTypeUpload, "/path", "folder")
+	q.Activate(transient.ID)
+	if _, ok := ts.scheduleAutoRetry(transient.ID, fmt.Errorf("upload part 3: connection reset by peer")); !ok {
+		t.Error("network error should schedule an automatic retry")
+	}
+	if task, _ := q.GetTask(transient.ID); task.State != transfer.TaskRetryWaiting {
+		t.Errorf("State = %s, want retry_waiting", task.State)
+	}
+	q.GiveUp(transient.ID)
+
+	fatal := q.TrackTransfer("missing.dat", 100, transfer.TaskTypeUpload, "/path", "folder")
+	q.Activate(fatal.ID)
+	if _, ok := ts.scheduleAutoRetry(fatal.ID, fmt.Errorf("404 not found")); ok {
+		t.Error("fatal error should not be retried automatically")
+	}
+
+	exhausted := q.TrackTransfer("exhausted.dat", 100, transfer.TaskTypeUpload, "/path", "folder")
+	exhausted.RetryAttempts = constants.TransferAutoRetryMaxAttempts
+	q.Activate(exhausted.ID)
+	if _, ok := ts.scheduleAutoRetry(exhausted.ID, fmt.Errorf("503 service unavailable")); ok {
+		t.Error("task with no attempts left should not be retried")
+	}
+}
+
+func TestFindDuplicateTransfers(t *testing.T) {
+	ts := NewTransferService(nil, events.NewEventBus(100), TransferServiceConfig{})
+	q := ts.GetQueue()
+	pending := q.TrackTransfer("model.inp", 100, transfer.TaskTypeUpload, "/data/model.inp", "folder")
+	done := q.TrackTransfer("old.dat", 100, transfer.TaskTypeUpload, "/data/old.dat", "folder")
+	q.Complete(done.ID)
+
+	dups := ts.FindDuplicateTransfers([]TransferRequest{
+		{Type: TransferTypeUpload, Source: "/data/model.inp", Dest: "folder"},       // pending
+		{Type: TransferTypeUpload, Source: "/data/model.inp", Dest: "other-folder"}, // other destination
+		{Type: TransferTypeUpload, Source: "/data/old.dat", Dest: "folder"},         // already completed
+		{Type: TransferTypeDownload, Source: "XyZ", Dest: "/out/a.dat"},
+		{Type: TransferTypeDownload, Source: "XyZ", Dest: "/out/./a.dat"}, // repeats the request above
+	})
+	want := []DuplicateTransfer{
+		{Index: 0, TaskID: pending.ID, State: TransferStateQueued},
+		{Index: 4},
+	}
+	if fmt.Sprint(dups) != fmt.Sprint(want) {
+		t.Errorf("FindDuplicateTransfers() = %+v, want %+v", dups, want)
+	}
+}
+
 func TestStreamingDownloadBatchAdaptiveConcurrency(t *testing.T) {
 	eventBus := events.NewEventBus(100)
 	ts := NewTransferService(nil, eventBus, TransferServiceConfig{
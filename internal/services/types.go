@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/transfer"
+	"github.com/rescale/rescale-int/internal/transfer/postprocess"
 )
 
 // TransferType identifies whether a transfer is an upload or download.
@@ -21,13 +23,14 @@ const (
 type TransferState string
 
 const (
-	TransferStateQueued       TransferState = "queued"       // Waiting for execution slot
-	TransferStateInitializing TransferState = "initializing" // Acquired slot, setting up
-	TransferStateActive       TransferState = "active"       // Actively transferring bytes
-	TransferStatePaused       TransferState = "paused"       // Paused by user
-	TransferStateCompleted    TransferState = "completed"    // Successfully completed
-	TransferStateFailed       TransferState = "failed"       // Failed with error
-	TransferStateCancelled    TransferState = "cancelled"    // Cancelled by user
+	TransferStateQueued       TransferState = "queued"        // Waiting for execution slot
+	TransferStateInitializing TransferState = "initializing"  // Acquired slot, setting up
+	TransferStateActive       TransferState = "active"        // Actively transferring bytes
+	TransferStatePaused       TransferState = "paused"        // Paused by user
+	TransferStateCompleted    TransferState = "completed"     // Successfully completed
+	TransferStateFailed       TransferState = "failed"        // Failed with error
+	TransferStateCancelled    TransferState = "cancelled"     // Cancelled by user
+	TransferStateRetryWaiting TransferState = "retry_waiting" // Transient failure, automatic retry scheduled
 )
 
 // Source label constants for transfer origin tracking.
@@ -40,6 +43,12 @@ const (
 	// Transfers tab; filtered by IPC handlers when returning
 	// DaemonTransferSnapshot.
 	SourceLabelDaemon = "Daemon"
+	// SourceLabelAPI identifies transfers started through the local REST
+	// server (rescale-int serve).
+	SourceLabelAPI = "API"
+	// SourceLabelSendTo identifies uploads queued from the OS context menu
+	// ("Send to Rescale Interlink").
+	SourceLabelSendTo = "SendTo"
 )
 
 // TransferRequest specifies a single transfer to be executed.
@@ -79,10 +88,40 @@ type TransferRequest struct {
 	// Tags to apply after successful upload. Tagging failure is non-fatal (logged as warning).
 	Tags []string
 
+	// RemoteName is the name to register an upload under when a duplicate-name
+	// policy renamed it (empty = base name of Source).
+	RemoteName string
+
+	// ReplaceFileIDs are existing files an upload overwrites. They are deleted
+	// only after the upload succeeds; failure to delete is non-fatal.
+	ReplaceFileIDs []string
+
+	// PostProcess is the chain of steps run on a download once it completes
+	// (see package postprocess). Empty uses the configured post_download
+	// default; "none" skips it.
+	PostProcess string
+
 	// FileInfo is optional pre-fetched file metadata for downloads.
 	// When set, DownloadFile() skips the GetFileInfo() API call.
 	// Nil means download will fetch metadata via API (safe degradation).
 	FileInfo *models.CloudFile
+
+	// AllowDuplicate queues the transfer even if the same file is already
+	// queued or transferring between the same source and destination.
+	// StartTransfers skips such duplicates otherwise.
+	AllowDuplicate bool
+}
+
+// DuplicateTransfer is a request that repeats a transfer already queued or
+// running: the same type, source and destination.
+type DuplicateTransfer struct {
+	// Index is the request's position in the checked requests
+	Index int
+
+	// TaskID and State identify the pending transfer it repeats; TaskID is
+	// empty when it repeats an earlier request of the same call
+	TaskID string
+	State  TransferState
 }
 
 // TransferTask represents an active or completed transfer.
@@ -128,6 +167,16 @@ type TransferTask struct {
 	// Error if the transfer failed
 	Error error
 
+	// Steps is the status of each post-download processing step, once the
+	// chain has started
+	Steps []postprocess.Status
+
+	// RetryAttempts is the number of automatic retries scheduled so far
+	RetryAttempts int
+
+	// NextRetryAt is when the pending automatic retry fires (retry_waiting only)
+	NextRetryAt time.Time
+
 	// CreatedAt is when the transfer was queued
 	CreatedAt time.Time
 
@@ -143,6 +192,10 @@ type UploadFileSyncParams struct {
 	// ExtraProgressCallback is an additional callback for the caller's own tracking
 	// (e.g., pipeline's reportStateChange). Called in addition to queue progress.
 	ExtraProgressCallback func(progress float64)
+
+	// PauseInBlackout holds the upload between parts during blackout windows
+	// (see upload.UploadParams.PauseInBlackout).
+	PauseInBlackout bool
 }
 
 // IsTerminal returns true if the transfer is in a terminal state.
@@ -166,6 +219,9 @@ type TransferStats struct {
 	Completed    int
 	Failed       int
 	Cancelled    int
+
+	// CancelLatency is how long cancelled in-flight transfers took to stop
+	CancelLatency transfer.CancelLatency
 }
 
 // Total returns the total number of tracked transfers.
@@ -232,6 +288,32 @@ type FolderContents struct {
 	NextCursor string
 }
 
+// FolderSort selects the server-side ordering of a windowed folder listing.
+type FolderSort struct {
+	// Field is "name", "size" or "modTime"
+	Field string
+
+	// Descending reverses the order
+	Descending bool
+}
+
+// FolderWindow is one page of a folder listing fetched by page number,
+// in server order. Used to browse folders too large to list in full.
+type FolderWindow struct {
+	// FolderID is the ID of the folder being listed
+	FolderID string
+
+	// Page is the 0-based page number and PageSize its size
+	Page     int
+	PageSize int
+
+	// Total is the number of items in the whole folder
+	Total int
+
+	// Items is this page's files and folders, in the requested order
+	Items []FileItem
+}
+
 // UploadFolderResult contains the result of a folder structure creation.
 type UploadFolderResult struct {
 	// LocalToRemoteMapping maps local directory paths to created remote folder IDs
@@ -0,0 +1,526 @@
+// Package templaterepo imports job templates and run recipes from a shared
+// team repository, e.g. one the CAE methods team curates. The repository is
+// a folder (typically on a network share) or a git repository:
+//
+//	templates/<name>.json  job templates, as saved by the GUI template library
+//	recipes/<name>.csv     run recipes: jobs CSV/JSON files and PUR templates
+//	recipes/<name>.json    for `pur make-dirs-csv --template`
+//
+// Imported files are copied into the local template and recipe directories
+// and recorded in a ledger with the content version they came from, so a
+// later check can tell which have changed upstream. Pinned imports keep
+// their version until unpinned; a git repository can be pinned as a whole
+// with "<url>#<tag or branch>".
+package templaterepo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// Kinds of repository items, named after the folder they live in.
+const (
+	KindTemplate = "template"
+	KindRecipe   = "recipe"
+)
+
+// kindDirs maps each kind to its folder in the repository and the file
+// extensions it holds.
+var kindDirs = []struct {
+	kind string
+	dir  string
+	exts []string
+}{
+	{KindTemplate, "templates", []string{".json"}},
+	{KindRecipe, "recipes", []string{".csv", ".json"}},
+}
+
+// Source is a parsed template_repo setting.
+type Source struct {
+	Location string // Folder path or git URL
+	Ref      string // Git tag or branch the repository is pinned to
+	Git      bool
+}
+
+// ParseSource parses a template_repo setting. URLs (and scp-style
+// "git@host:path" addresses) are git repositories and may end in
+// "#<tag or branch>"; anything else is a folder.
+func ParseSource(s string) (Source, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Source{}, fmt.Errorf("no template repository configured (set template_repo)")
+	}
+	src := Source{Location: s, Git: true}
+	if i := strings.LastIndex(s, "#"); i >= 0 {
+		src.Location, src.Ref = s[:i], s[i+1:]
+	}
+	isURL := strings.Contains(s, "://") || strings.HasPrefix(s, "git@")
+	if !isURL && !strings.HasSuffix(src.Location, ".git") {
+		return Source{Location: s}, nil // A folder, which may contain '#'
+	}
+	if src.Ref == "" && src.Location != s {
+		return Source{}, fmt.Errorf("template repository %q has an empty #ref", s)
+	}
+	return src, nil
+}
+
+// String returns the source in template_repo form.
+func (s Source) String() string {
+	if s.Ref != "" {
+		return s.Location + "#" + s.Ref
+	}
+	return s.Location
+}
+
+// Item is a template or recipe offered by the repository.
+type Item struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"` // File name without extension
+	File        string `json:"file"` // Slash-separated, relative to the repository root
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Repo is a repository's contents as of Open.
+type Repo struct {
+	Source   Source
+	Dir      string // The folder, or the local clone of a git repository
+	Revision string // Git commit; empty for folders
+
+	// FetchErr is set when a git repository could not be updated and its
+	// last fetched revision is used instead, e.g. while offline.
+	FetchErr error
+}
+
+// Open makes the repository's current contents available. A folder is read
+// in place; a git repository is cloned into cacheDir, or its clone there is
+// updated to the latest revision of the pinned ref (the default branch when
+// none is pinned). Requires git on PATH for git repositories.
+func Open(ctx context.Context, setting, cacheDir string) (*Repo, error) {
+	src, err := ParseSource(setting)
+	if err != nil {
+		return nil, err
+	}
+	if !src.Git {
+		info, err := os.Stat(src.Location)
+		if err != nil {
+			return nil, fmt.Errorf("template repository not reachable: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("template repository %s is not a folder", src.Location)
+		}
+		return &Repo{Source: src, Dir: src.Location}, nil
+	}
+
+	sum := sha256.Sum256([]byte(src.String()))
+	repo := &Repo{Source: src, Dir: filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))}
+	if _, err := os.Stat(filepath.Join(repo.Dir, ".git")); err != nil {
+		if err := cloneRepo(ctx, src, repo.Dir); err != nil {
+			return nil, err
+		}
+	} else if err := fetchRepo(ctx, src, repo.Dir); err != nil {
+		repo.FetchErr = err
+	}
+	out, err := git(ctx, repo.Dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	repo.Revision = strings.TrimSpace(out)
+	return repo, nil
+}
+
+func cloneRepo(ctx context.Context, src Source, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create template repository cache: %w", err)
+	}
+	args := []string{"clone", "--depth", "1", "--quiet"}
+	if src.Ref != "" {
+		args = append(args, "--branch", src.Ref)
+	}
+	if _, err := git(ctx, "", append(args, "--", src.Location, dir)...); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+func fetchRepo(ctx context.Context, src Source, dir string) error {
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := git(ctx, dir, "fetch", "--depth", "1", "--quiet", "origin", ref); err != nil {
+		return err
+	}
+	_, err := git(ctx, dir, "reset", "--hard", "--quiet", "FETCH_HEAD")
+	return err
+}
+
+// git runs a git command in dir and returns its output. Credential prompts
+// are disabled so an inaccessible repository fails instead of hanging.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required for git template repositories: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return string(out), nil
+}
+
+// Items lists the repository's templates and recipes, sorted by kind and name.
+func (r *Repo) Items() ([]Item, error) {
+	var items []Item
+	found := false
+	for _, kd := range kindDirs {
+		entries, err := os.ReadDir(filepath.Join(r.Dir, kd.dir))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template repository: %w", err)
+		}
+		found = true
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !slices.Contains(kd.exts, ext) {
+				continue
+			}
+			item := Item{Kind: kd.kind, Name: strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())), File: kd.dir + "/" + e.Name()}
+			data, err := r.Read(item)
+			if err != nil {
+				return nil, err
+			}
+			item.Version = Version(data)
+			if kd.kind == KindTemplate {
+				item.Description = templateDescription(data)
+			}
+			items = append(items, item)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("template repository %s has no templates/ or recipes/ folder", r.Source)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind == KindTemplate
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// Read returns the content of item.
+func (r *Repo) Read(item Item) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(r.Dir, filepath.FromSlash(item.File)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from the template repository: %w", item.File, err)
+	}
+	return data, nil
+}
+
+// ErrNotFound is returned by Find when no item matches.
+var ErrNotFound = errors.New("not in the template repository")
+
+// Find returns the item named by ref: its file ("recipes/wing.csv"), or its
+// name when only one item has it.
+func Find(items []Item, ref string) (Item, error) {
+	ref = strings.TrimSuffix(filepath.ToSlash(ref), "/")
+	var matches []Item
+	for _, item := range items {
+		if item.File == ref {
+			return item, nil
+		}
+		if item.Name == ref || item.Kind+"/"+item.Name == ref {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Item{}, fmt.Errorf("%s is %w", ref, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	files := make([]string, len(matches))
+	for i, m := range matches {
+		files[i] = m.File
+	}
+	return Item{}, fmt.Errorf("%s is ambiguous; use one of %s", ref, strings.Join(files, ", "))
+}
+
+// Version identifies content: the first 12 hex digits of its SHA-256.
+func Version(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// templateDescription summarizes a job template: its "description" field,
+// or its software and core type.
+func templateDescription(data []byte) string {
+	var t struct {
+		Description  string `json:"description"`
+		AnalysisCode string `json:"analysisCode"`
+		CoreType     string `json:"coreType"`
+	}
+	if json.Unmarshal(data, &t) != nil {
+		return ""
+	}
+	if t.Description != "" {
+		return t.Description
+	}
+	return strings.TrimSpace(strings.Join(nonEmpty(t.AnalysisCode, t.CoreType), " on "))
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Import records an item imported from the repository.
+type Import struct {
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	File       string    `json:"file"`
+	Source     string    `json:"source"`
+	Version    string    `json:"version"`
+	Revision   string    `json:"revision,omitempty"`
+	Pinned     bool      `json:"pinned,omitempty"`
+	ImportedAt time.Time `json:"importedAt"`
+}
+
+// Library is where imported items are saved: the local template and recipe
+// directories and the ledger of imports.
+type Library struct {
+	TemplateDir string
+	RecipeDir   string
+	LedgerPath  string
+}
+
+// DefaultLibrary returns the library of the user's template library and
+// recipe directory.
+func DefaultLibrary() Library {
+	return Library{
+		TemplateDir: config.TemplateDirectory(),
+		RecipeDir:   config.RecipeDirectory(),
+		LedgerPath:  config.TemplateImportsPath(),
+	}
+}
+
+// LocalPath returns where item is saved in the library.
+func (l Library) LocalPath(kind, file string) string {
+	dir := l.TemplateDir
+	if kind == KindRecipe {
+		dir = l.RecipeDir
+	}
+	return filepath.Join(dir, path.Base(file))
+}
+
+// Imports returns the recorded imports, sorted by file.
+func (l Library) Imports() ([]Import, error) {
+	data, err := os.ReadFile(l.LedgerPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template imports: %w", err)
+	}
+	var imports []Import
+	if err := json.Unmarshal(data, &imports); err != nil {
+		return nil, fmt.Errorf("invalid template imports %s: %w", l.LedgerPath, err)
+	}
+	return imports, nil
+}
+
+func (l Library) saveImports(imports []Import) error {
+	sort.Slice(imports, func(i, j int) bool { return imports[i].File < imports[j].File })
+	data, err := json.MarshalIndent(imports, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(l.LedgerPath, data)
+}
+
+// ErrLocalFile is returned when importing over a local file that was not
+// imported from the repository, or that was edited since its import.
+var ErrLocalFile = errors.New("a local file of that name would be overwritten")
+
+// Import copies item into the library and records it. An existing local file
+// that was not imported, or was edited since, is only replaced with force.
+// pin sets whether the import is pinned; an existing pin is kept otherwise.
+func (l Library) Import(repo *Repo, item Item, pin, force bool) (Import, error) {
+	data, err := repo.Read(item)
+	if err != nil {
+		return Import{}, err
+	}
+	imports, err := l.Imports()
+	if err != nil {
+		return Import{}, err
+	}
+	i := indexOf(imports, item.File)
+	dest := l.LocalPath(item.Kind, item.File)
+	if !force {
+		if local, err := os.ReadFile(dest); err == nil {
+			if i < 0 || Version(local) != imports[i].Version {
+				return Import{}, fmt.Errorf("%w: %s (use force to replace it)", ErrLocalFile, dest)
+			}
+		}
+	}
+
+	if err := writeFileAtomic(dest, data); err != nil {
+		return Import{}, fmt.Errorf("failed to save %s: %w", dest, err)
+	}
+	imp := Import{
+		Kind:       item.Kind,
+		Name:       item.Name,
+		File:       item.File,
+		Source:     repo.Source.String(),
+		Version:    Version(data),
+		Revision:   repo.Revision,
+		Pinned:     pin,
+		ImportedAt: time.Now().UTC(),
+	}
+	if i >= 0 {
+		imp.Pinned = pin || imports[i].Pinned
+		imports[i] = imp
+	} else {
+		imports = append(imports, imp)
+	}
+	return imp, l.saveImports(imports)
+}
+
+// SetPinned pins or unpins the import of file.
+func (l Library) SetPinned(file string, pinned bool) error {
+	imports, err := l.Imports()
+	if err != nil {
+		return err
+	}
+	i := indexOf(imports, file)
+	if i < 0 {
+		return fmt.Errorf("%s has not been imported", file)
+	}
+	imports[i].Pinned = pinned
+	return l.saveImports(imports)
+}
+
+func indexOf(imports []Import, file string) int {
+	for i, imp := range imports {
+		if imp.File == file {
+			return i
+		}
+	}
+	return -1
+}
+
+// ImportedItems returns the recorded imports as items, e.g. to resolve a
+// name with Find without opening the repository.
+func ImportedItems(imports []Import) []Item {
+	items := make([]Item, len(imports))
+	for i, imp := range imports {
+		items[i] = Item{Kind: imp.Kind, Name: imp.Name, File: imp.File, Version: imp.Version}
+	}
+	return items
+}
+
+// Status values of an item against its import.
+const (
+	StatusNotImported = "not-imported"
+	StatusCurrent     = "current"
+	StatusUpdate      = "update-available"
+	StatusPinned      = "pinned"   // Pinned while the repository has a newer version
+	StatusModified    = "modified" // Local copy edited since import (or deleted)
+	StatusRemoved     = "removed"  // Imported, no longer in the repository
+)
+
+// Check is an item's state in the library.
+type Check struct {
+	Item   Item    `json:"item"`
+	Import *Import `json:"import,omitempty"`
+	Status string  `json:"status"`
+}
+
+// Check compares the repository's items with the library: every item, plus
+// imports the repository no longer has.
+func (l Library) Check(items []Item) ([]Check, error) {
+	imports, err := l.Imports()
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]Check, 0, len(items))
+	seen := make(map[string]bool)
+	for _, item := range items {
+		c := Check{Item: item, Status: StatusNotImported}
+		if i := indexOf(imports, item.File); i >= 0 {
+			imp := imports[i]
+			c.Import = &imp
+			c.Status = l.importStatus(imp, item.Version)
+			seen[item.File] = true
+		}
+		checks = append(checks, c)
+	}
+	for _, imp := range imports {
+		if !seen[imp.File] {
+			checks = append(checks, Check{Item: Item{Kind: imp.Kind, Name: imp.Name, File: imp.File}, Import: &imp, Status: StatusRemoved})
+		}
+	}
+	return checks, nil
+}
+
+func (l Library) importStatus(imp Import, upstream string) string {
+	local, err := os.ReadFile(l.LocalPath(imp.Kind, imp.File))
+	switch {
+	case err != nil || Version(local) != imp.Version:
+		return StatusModified
+	case imp.Version == upstream:
+		return StatusCurrent
+	case imp.Pinned:
+		return StatusPinned
+	}
+	return StatusUpdate
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it
+// over path.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package templaterepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Source
+		wantErr bool
+	}{
+		{`\\fileserver\cae\templates`, Source{Location: `\\fileserver\cae\templates`}, false},
+		{"/mnt/share/run#1", Source{Location: "/mnt/share/run#1"}, false},
+		{"https://git.example.com/cae/templates.git", Source{Location: "https://git.example.com/cae/templates.git", Git: true}, false},
+		{"git@git.example.com:cae/templates.git#v2.1", Source{Location: "git@git.example.com:cae/templates.git", Ref: "v2.1", Git: true}, false},
+		{"/srv/git/templates.git#main", Source{Location: "/srv/git/templates.git", Ref: "main", Git: true}, false},
+		{"https://git.example.com/cae/templates#", Source{}, true},
+		{"  ", Source{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSource(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSource(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSource(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// writeRepo creates a folder repository with the given files.
+func writeRepo(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testLibrary(t *testing.T) Library {
+	dir := t.TempDir()
+	return Library{
+		TemplateDir: filepath.Join(dir, "templates"),
+		RecipeDir:   filepath.Join(dir, "recipes"),
+		LedgerPath:  filepath.Join(dir, "template-imports.json"),
+	}
+}
+
+func statusOf(t *testing.T, lib Library, repo *Repo, file string) string {
+	t.Helper()
+	items, err := repo.Items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks, err := lib.Check(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range checks {
+		if c.Item.File == file {
+			return c.Status
+		}
+	}
+	return ""
+}
+
+func TestImportLifecycle(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepo(t, repoDir, map[string]string{
+		"templates/wing.json": `{"analysisCode":"openfoam","coreType":"emerald"}`,
+		"templates/notes.txt": "not a template",
+		"recipes/sweep.csv":   "jobName,directory\n",
+	})
+	repo, err := Open(context.Background(), repoDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := repo.Items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].File != "templates/wing.json" || items[0].Description != "openfoam on emerald" || items[1].Kind != KindRecipe {
+		t.Fatalf("Items() = %+v", items)
+	}
+
+	lib := testLibrary(t)
+	wing, _ := Find(items, "wing")
+	if _, err := lib.Import(repo, wing, false, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(lib.TemplateDir, "wing.json")); err != nil {
+		t.Errorf("imported template not saved: %v", err)
+	}
+	if got := statusOf(t, lib, repo, "templates/wing.json"); got != StatusCurrent {
+		t.Errorf("status after import = %s, want %s", got, StatusCurrent)
+	}
+	if got := statusOf(t, lib, repo, "recipes/sweep.csv"); got != StatusNotImported {
+		t.Errorf("recipe status = %s, want %s", got, StatusNotImported)
+	}
+
+	// Upstream changes
+	writeRepo(t, repoDir, map[string]string{"templates/wing.json": `{"analysisCode":"openfoam","coreType":"onyx"}`})
+	if got := statusOf(t, lib, repo, "templates/wing.json"); got != StatusUpdate {
+		t.Errorf("status after upstream change = %s, want %s", got, StatusUpdate)
+	}
+	if err := lib.SetPinned("templates/wing.json", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusOf(t, lib, repo, "templates/wing.json"); got != StatusPinned {
+		t.Errorf("status when pinned = %s, want %s", got, StatusPinned)
+	}
+
+	// Re-importing keeps the pin and takes the new version
+	imp, err := lib.Import(repo, wing, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imp.Pinned || statusOf(t, lib, repo, "templates/wing.json") != StatusCurrent {
+		t.Errorf("re-import = %+v, want pinned and current", imp)
+	}
+
+	// Local edits are not overwritten without force
+	if err := os.WriteFile(filepath.Join(lib.TemplateDir, "wing.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusOf(t, lib, repo, "templates/wing.json"); got != StatusModified {
+		t.Errorf("status after local edit = %s, want %s", got, StatusModified)
+	}
+	if _, err := lib.Import(repo, wing, false, false); !errors.Is(err, ErrLocalFile) {
+		t.Errorf("Import() over local edit error = %v, want ErrLocalFile", err)
+	}
+	if _, err := lib.Import(repo, wing, false, true); err != nil {
+		t.Errorf("Import(force) error = %v", err)
+	}
+
+	// Removed upstream
+	os.Remove(filepath.Join(repoDir, "templates", "wing.json"))
+	if got := statusOf(t, lib, repo, "templates/wing.json"); got != StatusRemoved {
+		t.Errorf("status after upstream removal = %s, want %s", got, StatusRemoved)
+	}
+}
+
+func TestImportRefusesOwnTemplate(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepo(t, repoDir, map[string]string{"templates/wing.json": `{}`})
+	repo, err := Open(context.Background(), repoDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib := testLibrary(t)
+	writeRepo(t, lib.TemplateDir, map[string]string{"wing.json": `{"jobName":"mine"}`})
+
+	items, _ := repo.Items()
+	if _, err := lib.Import(repo, items[0], false, false); !errors.Is(err, ErrLocalFile) {
+		t.Errorf("Import() over own template error = %v, want ErrLocalFile", err)
+	}
+}
+
+func TestFindAmbiguousName(t *testing.T) {
+	items := []Item{
+		{Kind: KindTemplate, Name: "wing", File: "templates/wing.json"},
+		{Kind: KindRecipe, Name: "wing", File: "recipes/wing.csv"},
+	}
+	if _, err := Find(items, "wing"); err == nil {
+		t.Error("Find(ambiguous) succeeded")
+	}
+	if item, err := Find(items, "recipe/wing"); err != nil || item.File != "recipes/wing.csv" {
+		t.Errorf("Find(kind/name) = %+v, %v", item, err)
+	}
+	if item, err := Find(items, "templates/wing.json"); err != nil || item.Kind != KindTemplate {
+		t.Errorf("Find(file) = %+v, %v", item, err)
+	}
+}
+
+func TestOpenGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	upstream := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = upstream
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "--quiet")
+	writeRepo(t, upstream, map[string]string{"templates/wing.json": `{"description":"Wing CFD"}`})
+	run("add", ".")
+	run("commit", "--quiet", "-m", "first")
+
+	cacheDir := t.TempDir()
+	repo, err := Open(context.Background(), "file:///"+strings.TrimPrefix(filepath.ToSlash(upstream), "/"), cacheDir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	items, err := repo.Items()
+	if err != nil || len(items) != 1 || items[0].Description != "Wing CFD" || repo.Revision == "" {
+		t.Fatalf("Items() = %+v, %v (revision %q)", items, err, repo.Revision)
+	}
+
+	writeRepo(t, upstream, map[string]string{"templates/wing.json": `{"description":"Wing CFD v2"}`})
+	run("commit", "--quiet", "-am", "second")
+	updated, err := Open(context.Background(), "file:///"+strings.TrimPrefix(filepath.ToSlash(upstream), "/"), cacheDir)
+	if err != nil || updated.FetchErr != nil {
+		t.Fatalf("Open() after upstream commit error = %v, fetch %v", err, updated.FetchErr)
+	}
+	items, _ = updated.Items()
+	if updated.Revision == repo.Revision || items[0].Description != "Wing CFD v2" {
+		t.Errorf("repository not updated: revision %s, items %+v", updated.Revision, items)
+	}
+}
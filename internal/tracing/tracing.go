@@ -0,0 +1,155 @@
+// Package tracing exports OpenTelemetry traces of job pipelines, transfers
+// and API calls to an OTLP collector, for diagnosing where time goes (slow
+// job submissions, long upload initialization) without ad-hoc timing logs.
+//
+// Tracing is off until Init is called with an endpoint (config otel_endpoint)
+// or the standard OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// environment variables are set. Until then Start returns no-op spans, so
+// instrumented code costs next to nothing.
+//
+// Exporter options the config does not cover, such as authentication headers
+// (OTEL_EXPORTER_OTLP_HEADERS) and sampling (OTEL_TRACES_SAMPLER), are read
+// from the standard OTEL_* environment variables.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+// instrumentationName names the tracer all spans come from.
+const instrumentationName = "github.com/rescale/rescale-int"
+
+// ShutdownFunc flushes buffered spans and stops the exporter.
+type ShutdownFunc func(context.Context) error
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+)
+
+// Enabled reports whether endpoint, or the OTEL_* environment, configures an
+// OTLP trace exporter.
+func Enabled(endpoint string) bool {
+	return strings.TrimSpace(endpoint) != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// ValidateEndpoint checks an otel_endpoint value: empty, or an http(s) URL
+// of an OTLP/HTTP collector such as http://localhost:4318.
+func ValidateEndpoint(endpoint string) error {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return nil
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return fmt.Errorf("otel_endpoint %q must be an http:// or https:// URL of an OTLP/HTTP collector", endpoint)
+	}
+	return nil
+}
+
+// Init starts exporting spans over OTLP/HTTP to endpoint, or to the collector
+// named by the OTEL_* environment when endpoint is empty. component ("cli",
+// "gui") is recorded on every span's resource. When tracing is not
+// configured Init does nothing and returns a no-op ShutdownFunc.
+//
+// Calling Init again replaces the exporter, e.g. after otel_endpoint changes
+// in the GUI; an empty endpoint then turns tracing off.
+func Init(ctx context.Context, endpoint, component string) (ShutdownFunc, error) {
+	if err := ValidateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Replace any earlier provider; its spans are flushed first
+	if provider != nil {
+		_ = provider.Shutdown(ctx)
+		provider = nil
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	}
+	if !Enabled(endpoint) {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "rescale-interlink"),
+		attribute.String("service.version", version.Version),
+		attribute.String("rescale.component", component),
+	))
+	if err != nil {
+		res = resource.Default()
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	provider = tp
+	otel.SetTracerProvider(tp)
+	otel.SetErrorHandler(onceErrorHandler())
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if provider != tp {
+			return nil // Replaced by a later Init
+		}
+		provider = nil
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return tp.Shutdown(ctx)
+	}, nil
+}
+
+// onceErrorHandler logs the first export error only: an unreachable
+// collector would otherwise log on every batch.
+func onceErrorHandler() otel.ErrorHandler {
+	var once sync.Once
+	return otel.ErrorHandlerFunc(func(err error) {
+		once.Do(func() {
+			log.Printf("Warning: tracing: %v (further tracing errors are not logged)", err)
+		})
+	})
+}
+
+// Start starts a span named name, a child of the span in ctx if any, and
+// returns a context carrying it. End the span with End.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, as the span's status and ends it. Ending a span
+// twice is harmless, so a deferred End can back up an earlier one.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
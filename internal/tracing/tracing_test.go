@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "http://localhost:4318", " https://otel.example.com/ "} {
+		if err := ValidateEndpoint(endpoint); err != nil {
+			t.Errorf("ValidateEndpoint(%q) error = %v", endpoint, err)
+		}
+	}
+	for _, endpoint := range []string{"localhost:4318", "grpc://localhost:4317"} {
+		if err := ValidateEndpoint(endpoint); err == nil {
+			t.Errorf("ValidateEndpoint(%q) succeeded, want an error", endpoint)
+		}
+	}
+}
+
+// TestInitNotConfigured verifies spans are no-ops when neither the config
+// nor the environment names a collector.
+func TestInitNotConfigured(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown, err := Init(context.Background(), "", "test")
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer shutdown(context.Background())
+
+	_, span := Start(context.Background(), "noop")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("span is recording with tracing not configured")
+	}
+}
+
+// TestInitExportsSpans verifies ended spans reach the OTLP/HTTP collector by
+// the time the ShutdownFunc returns.
+func TestInitExportsSpans(t *testing.T) {
+	var exports atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			exports.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	shutdown, err := Init(context.Background(), collector.URL, "test")
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	ctx, parent := Start(context.Background(), "pur.run")
+	_, child := Start(ctx, "pur.submit")
+	if !child.IsRecording() || child.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Error("child span is not recording in the parent's trace")
+	}
+	End(child, errors.New("submit failed"))
+	End(parent, nil)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+	if exports.Load() == 0 {
+		t.Error("no spans were exported to the collector")
+	}
+
+	// Spans after shutdown are no-ops again
+	if _, span := Start(context.Background(), "after"); span.IsRecording() {
+		t.Error("span is recording after shutdown")
+	}
+}
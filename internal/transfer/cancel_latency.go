@@ -0,0 +1,70 @@
+package transfer
+
+import (
+	"log"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// CancelLatency summarizes cancel-to-idle latency: the time from cancelling
+// an in-flight transfer until its worker has stopped all I/O and released
+// its slot. Only tasks that had a running worker are measured.
+type CancelLatency struct {
+	Count int           // cancels measured
+	Slow  int           // cancels slower than constants.CancelIdleTarget
+	Max   time.Duration // slowest cancel
+	Total time.Duration // sum of all measured cancels
+}
+
+// Mean returns the average cancel-to-idle latency (0 when none measured).
+func (c CancelLatency) Mean() time.Duration {
+	if c.Count == 0 {
+		return 0
+	}
+	return c.Total / time.Duration(c.Count)
+}
+
+// noteCancelLocked records when a task with a running worker was cancelled.
+// Caller must hold q.mu.
+func (q *Queue) noteCancelLocked(taskID string, now time.Time) {
+	if _, ok := q.cancelRequested[taskID]; !ok {
+		q.cancelRequested[taskID] = now
+	}
+}
+
+// MarkIdle is called by a transfer worker when it returns, after releasing
+// its slot. If the task had been cancelled, the time since the cancel is
+// added to the queue's cancel latency; cancels slower than
+// CancelIdleTarget are logged.
+func (q *Queue) MarkIdle(taskID string) {
+	q.mu.Lock()
+	requested, ok := q.cancelRequested[taskID]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+	delete(q.cancelRequested, taskID)
+	latency := time.Since(requested)
+	q.cancelLatency.Count++
+	q.cancelLatency.Total += latency
+	if latency > q.cancelLatency.Max {
+		q.cancelLatency.Max = latency
+	}
+	slow := latency > constants.CancelIdleTarget
+	if slow {
+		q.cancelLatency.Slow++
+	}
+	q.mu.Unlock()
+
+	if slow {
+		log.Printf("[CANCEL] %s: idle %v after cancel (target %v)", taskID, latency.Round(time.Millisecond), constants.CancelIdleTarget)
+	}
+}
+
+// GetCancelLatency returns cancel-to-idle latency for this queue's lifetime.
+func (q *Queue) GetCancelLatency() CancelLatency {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.cancelLatency
+}
@@ -0,0 +1,294 @@
+// Package dirsync compares a local directory tree with a remote Rescale
+// folder tree and plans the transfers that bring them in line
+// (`rescale-int files sync`).
+//
+// Files are matched by their slash-separated path relative to the two roots.
+// A pair is unchanged when the sizes match and the local file has not been
+// modified since the remote copy was uploaded; with Options.Checksum the
+// local SHA-512 is compared with the one registered for the remote file
+// instead. Downloads stamp the local file with the remote upload time, so a
+// file fetched by one sync is unchanged in the next.
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/localfs"
+	"github.com/rescale/rescale-int/internal/localfs/journal"
+	"github.com/rescale/rescale-int/internal/transfer/scan"
+)
+
+// Direction selects which side may be changed.
+type Direction string
+
+const (
+	Both Direction = "both" // Newer copy wins; nothing is deleted
+	Up   Direction = "up"   // Make the remote folder match the local directory
+	Down Direction = "down" // Make the local directory match the remote folder
+)
+
+// TempSuffix marks a download in progress. Such files are left out of the
+// local scan so an interrupted sync doesn't upload them.
+const TempSuffix = ".rescale-sync"
+
+// Action is what a sync does with one path.
+type Action string
+
+const (
+	Upload       Action = "upload"
+	Download     Action = "download"
+	DeleteLocal  Action = "delete-local"
+	DeleteRemote Action = "delete-remote"
+)
+
+// LocalFile is a regular file under the local root.
+type LocalFile struct {
+	RelPath string // Slash-separated, relative to the local root
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// RemoteFile is a file under the remote root folder. When a folder holds
+// several files of the same name, the newest is used and the others are
+// listed in OlderIDs so an upload can replace them all.
+type RemoteFile struct {
+	RelPath  string // Slash-separated, relative to the remote root
+	ID       string
+	Size     int64
+	Uploaded time.Time
+	SHA512   string // Registered plaintext hash; "" if the platform has none
+	OlderIDs []string
+	Task     scan.RemoteFileTask
+}
+
+// Op is one planned change.
+type Op struct {
+	Action  Action
+	RelPath string
+	Local   *LocalFile  // nil for downloads of new files and remote deletions
+	Remote  *RemoteFile // nil for uploads of new files and local deletions
+	Reason  string      // "new", "changed", "local newer", "remote newer" or "deleted"
+}
+
+// Size is the number of bytes the op transfers (0 for deletions).
+func (op Op) Size() int64 {
+	switch op.Action {
+	case Upload:
+		return op.Local.Size
+	case Download:
+		return op.Remote.Size
+	}
+	return 0
+}
+
+// Options controls Compare.
+type Options struct {
+	Direction Direction
+	// Delete removes files that exist only on the destination side. It
+	// needs a one-way Direction: in both directions a file missing on one
+	// side can't be told apart from a new file on the other.
+	Delete bool
+	// Checksum compares local SHA-512 hashes with the remote ones instead of
+	// trusting size and modification time. Hash computes them.
+	Checksum bool
+	Hash     func(path string) (string, error)
+}
+
+// Plan is the outcome of Compare, ordered by path.
+type Plan struct {
+	Ops       []Op
+	Unchanged int
+}
+
+// Count returns the number of ops with action a and their total size.
+func (p *Plan) Count(a Action) (n int, bytes int64) {
+	for _, op := range p.Ops {
+		if op.Action == a {
+			n++
+			bytes += op.Size()
+		}
+	}
+	return n, bytes
+}
+
+// Compare plans the ops that bring local and remote in line under opts.
+func Compare(local []LocalFile, remote []RemoteFile, opts Options) (*Plan, error) {
+	switch opts.Direction {
+	case Both, Up, Down:
+	default:
+		return nil, fmt.Errorf("invalid sync direction %q", opts.Direction)
+	}
+	if opts.Delete && opts.Direction == Both {
+		return nil, fmt.Errorf("--delete needs a one-way sync (--upload-only or --download-only)")
+	}
+
+	remoteByPath := make(map[string]*RemoteFile, len(remote))
+	for i := range remote {
+		remoteByPath[remote[i].RelPath] = &remote[i]
+	}
+
+	plan := &Plan{}
+	seen := make(map[string]bool, len(local))
+	for i := range local {
+		l := &local[i]
+		seen[l.RelPath] = true
+		r := remoteByPath[l.RelPath]
+		if r == nil {
+			switch {
+			case opts.Direction != Down:
+				plan.Ops = append(plan.Ops, Op{Action: Upload, RelPath: l.RelPath, Local: l, Reason: "new"})
+			case opts.Delete:
+				plan.Ops = append(plan.Ops, Op{Action: DeleteLocal, RelPath: l.RelPath, Local: l, Reason: "deleted"})
+			}
+			continue
+		}
+
+		same, err := unchanged(l, r, opts)
+		if err != nil {
+			return nil, err
+		}
+		if same {
+			plan.Unchanged++
+			continue
+		}
+		op := Op{RelPath: l.RelPath, Local: l, Remote: r, Reason: "changed"}
+		switch opts.Direction {
+		case Up:
+			op.Action = Upload
+		case Down:
+			op.Action = Download
+		default:
+			if l.ModTime.After(r.Uploaded) {
+				op.Action, op.Reason = Upload, "local newer"
+			} else {
+				op.Action, op.Reason = Download, "remote newer"
+			}
+		}
+		plan.Ops = append(plan.Ops, op)
+	}
+
+	for i := range remote {
+		r := &remote[i]
+		if seen[r.RelPath] {
+			continue
+		}
+		switch {
+		case opts.Direction != Up:
+			plan.Ops = append(plan.Ops, Op{Action: Download, RelPath: r.RelPath, Remote: r, Reason: "new"})
+		case opts.Delete:
+			plan.Ops = append(plan.Ops, Op{Action: DeleteRemote, RelPath: r.RelPath, Remote: r, Reason: "deleted"})
+		}
+	}
+
+	sort.SliceStable(plan.Ops, func(i, j int) bool { return plan.Ops[i].RelPath < plan.Ops[j].RelPath })
+	return plan, nil
+}
+
+// unchanged reports whether l and r hold the same content.
+func unchanged(l *LocalFile, r *RemoteFile, opts Options) (bool, error) {
+	if l.Size != r.Size {
+		return false, nil
+	}
+	if opts.Checksum && r.SHA512 != "" && opts.Hash != nil {
+		sum, err := opts.Hash(l.Path)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash %s: %w", l.RelPath, err)
+		}
+		return strings.EqualFold(sum, r.SHA512), nil
+	}
+	return !l.ModTime.After(r.Uploaded), nil
+}
+
+// ScanLocal lists the regular files under root. Hidden files and
+// directories are left out unless includeHidden is set, as are downloads a
+// previous sync left unfinished. With a change journal j, a registered root
+// is scanned incrementally; the returned stats are nil without one.
+func ScanLocal(ctx context.Context, root string, includeHidden bool, j *journal.Store) ([]LocalFile, *journal.ScanStats, error) {
+	opts := localfs.WalkOptions{
+		IncludeHidden:  includeHidden,
+		SkipHiddenDirs: true,
+		FollowSymlinks: true,
+	}
+	var entries []localfs.FileEntry
+	var stats *journal.ScanStats
+	if j != nil {
+		var err error
+		if entries, stats, err = j.Scan(ctx, root, opts); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		result, err := localfs.WalkCollect(root, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = result.Files
+	}
+
+	files := make([]LocalFile, 0, len(entries))
+	for _, f := range entries {
+		if strings.HasSuffix(f.Name, TempSuffix) {
+			continue
+		}
+		rel, err := filepath.Rel(root, f.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, LocalFile{
+			RelPath: filepath.ToSlash(rel),
+			Path:    f.Path,
+			Size:    f.Size,
+			ModTime: f.ModTime,
+		})
+	}
+	return files, stats, nil
+}
+
+// ScanRemote lists the files under folderID.
+func ScanRemote(ctx context.Context, apiClient *api.Client, folderID string) ([]RemoteFile, error) {
+	_, tasks, err := scan.ScanRemoteFolderRecursive(ctx, apiClient, folderID, "")
+	if err != nil {
+		return nil, err
+	}
+	return remoteFiles(tasks), nil
+}
+
+// remoteFiles converts scanned tasks, keeping the newest file of each path.
+func remoteFiles(tasks []scan.RemoteFileTask) []RemoteFile {
+	byPath := make(map[string]int)
+	var files []RemoteFile
+	for _, t := range tasks {
+		f := RemoteFile{
+			RelPath:  filepath.ToSlash(t.RelativePath),
+			ID:       t.FileID,
+			Size:     t.Size,
+			Uploaded: t.DateUploaded,
+			Task:     t,
+		}
+		for _, c := range t.Checksums {
+			if strings.EqualFold(c.HashFunction, "sha512") {
+				f.SHA512 = c.FileHash
+			}
+		}
+
+		i, dup := byPath[f.RelPath]
+		if !dup {
+			byPath[f.RelPath] = len(files)
+			files = append(files, f)
+			continue
+		}
+		if f.Uploaded.After(files[i].Uploaded) {
+			f.OlderIDs = append(files[i].OlderIDs, files[i].ID)
+			files[i] = f
+		} else {
+			files[i].OlderIDs = append(files[i].OlderIDs, f.ID)
+		}
+	}
+	return files
+}
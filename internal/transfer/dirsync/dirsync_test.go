@@ -0,0 +1,149 @@
+package dirsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/localfs/journal"
+	"github.com/rescale/rescale-int/internal/models"
+	"github.com/rescale/rescale-int/internal/transfer/scan"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func fixture() ([]LocalFile, []RemoteFile) {
+	local := []LocalFile{
+		{RelPath: "same.dat", Size: 10, ModTime: t0.Add(-time.Hour)},
+		{RelPath: "edited.dat", Size: 12, ModTime: t0.Add(time.Hour)},
+		{RelPath: "stale.dat", Size: 5, ModTime: t0.Add(-time.Hour)},
+		{RelPath: "sub/local-only.dat", Size: 3, ModTime: t0},
+	}
+	remote := []RemoteFile{
+		{RelPath: "same.dat", ID: "r1", Size: 10, Uploaded: t0},
+		{RelPath: "edited.dat", ID: "r2", Size: 10, Uploaded: t0},
+		{RelPath: "stale.dat", ID: "r3", Size: 7, Uploaded: t0},
+		{RelPath: "sub/remote-only.dat", ID: "r4", Size: 4, Uploaded: t0},
+	}
+	return local, remote
+}
+
+func describe(p *Plan) string {
+	var parts []string
+	for _, op := range p.Ops {
+		parts = append(parts, string(op.Action)+":"+op.RelPath)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{Direction: Both},
+			"upload:edited.dat download:stale.dat upload:sub/local-only.dat download:sub/remote-only.dat"},
+		{Options{Direction: Up},
+			"upload:edited.dat upload:stale.dat upload:sub/local-only.dat"},
+		{Options{Direction: Up, Delete: true},
+			"upload:edited.dat upload:stale.dat upload:sub/local-only.dat delete-remote:sub/remote-only.dat"},
+		{Options{Direction: Down},
+			"download:edited.dat download:stale.dat download:sub/remote-only.dat"},
+		{Options{Direction: Down, Delete: true},
+			"download:edited.dat download:stale.dat delete-local:sub/local-only.dat download:sub/remote-only.dat"},
+	}
+	for _, tt := range tests {
+		local, remote := fixture()
+		plan, err := Compare(local, remote, tt.opts)
+		if err != nil {
+			t.Fatalf("Compare(%+v) error = %v", tt.opts, err)
+		}
+		if got := describe(plan); got != tt.want {
+			t.Errorf("Compare(%+v)\n got  %s\n want %s", tt.opts, got, tt.want)
+		}
+		if plan.Unchanged != 1 {
+			t.Errorf("Compare(%+v) Unchanged = %d, want 1", tt.opts, plan.Unchanged)
+		}
+	}
+
+	local, remote := fixture()
+	if _, err := Compare(local, remote, Options{Direction: Both, Delete: true}); err == nil {
+		t.Error("Compare accepted --delete in both directions")
+	}
+	plan, _ := Compare(local, remote, Options{Direction: Up})
+	if n, bytes := plan.Count(Upload); n != 3 || bytes != 20 {
+		t.Errorf("Count(Upload) = %d, %d, want 3, 20", n, bytes)
+	}
+}
+
+func TestCompareChecksum(t *testing.T) {
+	local := []LocalFile{
+		{RelPath: "touched.dat", Path: "touched", Size: 10, ModTime: t0.Add(time.Hour)},
+		{RelPath: "edited.dat", Path: "edited", Size: 10, ModTime: t0.Add(-time.Hour)},
+	}
+	remote := []RemoteFile{
+		{RelPath: "touched.dat", Size: 10, Uploaded: t0, SHA512: "AAAA"},
+		{RelPath: "edited.dat", Size: 10, Uploaded: t0, SHA512: "bbbb"},
+	}
+	hashes := map[string]string{"touched": "aaaa", "edited": "cccc"}
+	plan, err := Compare(local, remote, Options{
+		Direction: Both,
+		Checksum:  true,
+		Hash:      func(path string) (string, error) { return hashes[path], nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(plan); got != "download:edited.dat" || plan.Unchanged != 1 {
+		t.Errorf("Compare = %s (%d unchanged), want download:edited.dat (1 unchanged)", got, plan.Unchanged)
+	}
+}
+
+func TestRemoteFilesKeepsNewestDuplicate(t *testing.T) {
+	files := remoteFiles([]scan.RemoteFileTask{
+		{FileID: "old", RelativePath: filepath.Join("a", "x.dat"), DateUploaded: t0},
+		{FileID: "new", RelativePath: filepath.Join("a", "x.dat"), DateUploaded: t0.Add(time.Hour),
+			Checksums: []models.FileChecksum{{HashFunction: "sha512", FileHash: "abc"}}},
+		{FileID: "older", RelativePath: filepath.Join("a", "x.dat"), DateUploaded: t0.Add(-time.Hour)},
+	})
+	if len(files) != 1 {
+		t.Fatalf("remoteFiles = %+v", files)
+	}
+	f := files[0]
+	if f.RelPath != "a/x.dat" || f.ID != "new" || f.SHA512 != "abc" || strings.Join(f.OlderIDs, ",") != "old,older" {
+		t.Errorf("remoteFiles = %+v", f)
+	}
+}
+
+func TestScanLocal(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	os.WriteFile(filepath.Join(root, "a.dat"), []byte("abc"), 0644)
+	os.WriteFile(filepath.Join(root, "sub", "b.dat"), []byte("b"), 0644)
+	os.WriteFile(filepath.Join(root, "sub", "c.dat"+TempSuffix), []byte("partial"), 0644)
+	os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref"), 0644)
+
+	// A registered root gives the same list through the journal snapshot,
+	// on the first scan and the next.
+	j := journal.NewStore(t.TempDir())
+	if err := j.Register(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*journal.Store{nil, j, j} {
+		files, _, err := ScanLocal(context.Background(), root, false, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rels []string
+		for _, f := range files {
+			rels = append(rels, f.RelPath)
+		}
+		if got := strings.Join(rels, ","); got != "a.dat,sub/b.dat" {
+			t.Errorf("ScanLocal = %s, want a.dat,sub/b.dat", got)
+		}
+	}
+}
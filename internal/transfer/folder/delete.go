@@ -0,0 +1,272 @@
+package folder
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/ratelimit"
+)
+
+// Defaults for DeleteRecursive.
+const (
+	DefaultDeleteWorkers = 4
+	MaxDeleteWorkers     = 16
+)
+
+// DeleteClient is the part of the API client DeleteRecursive uses.
+type DeleteClient interface {
+	ListFolderContentsAll(ctx context.Context, folderID string) (*api.FolderContents, error)
+	DeleteFile(ctx context.Context, fileID string) error
+	DeleteFolder(ctx context.Context, folderID string) error
+}
+
+// DeleteProgress is a point-in-time snapshot of a recursive delete.
+type DeleteProgress struct {
+	Listing bool // Still enumerating the tree; Total is not final yet
+	Total   int  // Files and folders to delete, including the root
+	Deleted int
+	Failed  int
+}
+
+// DeleteOptions configures DeleteRecursive.
+type DeleteOptions struct {
+	Workers       int     // Concurrent delete requests (default DefaultDeleteWorkers)
+	RatePerSecond float64 // Deletes per second; 0 = only the API client's own limits
+
+	// OnProgress is called after each listed folder and each deletion. It
+	// is called from several goroutines, one call at a time.
+	OnProgress func(DeleteProgress)
+}
+
+// DeleteFailure is an item DeleteRecursive could not delete.
+type DeleteFailure struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"` // Starts with the deleted folder's name
+	IsFolder bool   `json:"isFolder"`
+	Error    string `json:"error"`
+}
+
+// DeleteResult reports the outcome of DeleteRecursive.
+type DeleteResult struct {
+	Total     int             `json:"total"`
+	Deleted   int             `json:"deleted"`
+	Failures  []DeleteFailure `json:"failures,omitempty"`
+	Cancelled bool            `json:"cancelled,omitempty"`
+}
+
+// deleteItem is a file or folder of the tree being deleted.
+type deleteItem struct {
+	id       string
+	parentID string
+	path     string
+	isFolder bool
+	depth    int
+}
+
+// DeleteRecursive permanently deletes folderID and everything in it, one
+// item at a time so that progress can be reported and the operation can be
+// cancelled between requests. The tree is listed first; files are then
+// deleted, and folders after them from the deepest level up. A folder that
+// still holds an item that failed is left in place and reported too.
+//
+// When ctx is cancelled, no further requests are made and the result
+// covers what was deleted so far, with Cancelled set.
+func DeleteRecursive(ctx context.Context, client DeleteClient, folderID, name string, opts DeleteOptions) (*DeleteResult, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultDeleteWorkers
+	}
+	if workers > MaxDeleteWorkers {
+		workers = MaxDeleteWorkers
+	}
+	var limiter *ratelimit.RateLimiter
+	if opts.RatePerSecond > 0 {
+		limiter = ratelimit.NewRateLimiter(opts.RatePerSecond, max(opts.RatePerSecond, 1))
+	}
+
+	d := &recursiveDelete{
+		client:      client,
+		onProgress:  opts.OnProgress,
+		parents:     make(map[string]string),
+		failedUnder: make(map[string]bool),
+	}
+
+	// List the tree breadth-first; folders are recorded with their depth
+	d.progress.Listing = true
+	folders := []deleteItem{{id: folderID, path: name, isFolder: true}}
+	var files []deleteItem
+	unlisted := make(map[string]bool)
+	for i := 0; i < len(folders); i++ {
+		if ctx.Err() != nil {
+			return d.finish(true), ctx.Err()
+		}
+		parent := folders[i]
+		contents, err := client.ListFolderContentsAll(ctx, parent.id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return d.finish(true), ctx.Err()
+			}
+			if i == 0 {
+				return nil, fmt.Errorf("failed to list folder: %w", err)
+			}
+			// Deleting the folder would remove content that was never listed
+			d.fail(parent, fmt.Errorf("failed to list folder: %w", err))
+			unlisted[parent.id] = true
+			continue
+		}
+		for _, f := range contents.Folders {
+			folders = append(folders, deleteItem{id: f.ID, parentID: parent.id, path: path.Join(parent.path, f.Name), isFolder: true, depth: parent.depth + 1})
+			d.parents[f.ID] = parent.id
+		}
+		for _, f := range contents.Files {
+			files = append(files, deleteItem{id: f.ID, parentID: parent.id, path: path.Join(parent.path, f.Name)})
+		}
+		d.setTotal(len(folders) + len(files))
+	}
+	d.mu.Lock()
+	d.progress.Listing = false
+	d.mu.Unlock()
+	d.setTotal(len(folders) + len(files))
+
+	// Files first, then folders level by level from the deepest
+	if !d.deleteAll(ctx, files, workers, limiter) {
+		return d.finish(true), ctx.Err()
+	}
+	maxDepth := 0
+	for _, f := range folders {
+		maxDepth = max(maxDepth, f.depth)
+	}
+	for depth := maxDepth; depth >= 0; depth-- {
+		var level []deleteItem
+		for _, f := range folders {
+			if f.depth == depth && !unlisted[f.id] {
+				level = append(level, f)
+			}
+		}
+		if !d.deleteAll(ctx, level, workers, limiter) {
+			return d.finish(true), ctx.Err()
+		}
+	}
+	return d.finish(false), nil
+}
+
+// recursiveDelete holds the shared state of one DeleteRecursive call.
+type recursiveDelete struct {
+	client     DeleteClient
+	onProgress func(DeleteProgress)
+
+	parents map[string]string // Folder ID -> parent folder ID; written while listing only
+
+	mu          sync.Mutex
+	progress    DeleteProgress
+	failures    []DeleteFailure
+	failedUnder map[string]bool // IDs of folders holding an item that failed
+}
+
+// deleteAll deletes items with up to workers concurrent requests. It
+// reports false if ctx was cancelled before all were attempted.
+func (d *recursiveDelete) deleteAll(ctx context.Context, items []deleteItem, workers int, limiter *ratelimit.RateLimiter) bool {
+	work := make(chan deleteItem)
+	var wg sync.WaitGroup
+	for i := 0; i < min(workers, len(items)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				d.deleteOne(ctx, item, limiter)
+			}
+		}()
+	}
+
+	completed := true
+	for _, item := range items {
+		if ctx.Err() != nil {
+			completed = false
+			break
+		}
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+	return completed && ctx.Err() == nil
+}
+
+// deleteOne deletes a single item and records the outcome.
+func (d *recursiveDelete) deleteOne(ctx context.Context, item deleteItem, limiter *ratelimit.RateLimiter) {
+	if item.isFolder {
+		d.mu.Lock()
+		blocked := d.failedUnder[item.id]
+		d.mu.Unlock()
+		if blocked {
+			d.fail(item, fmt.Errorf("not deleted: it still holds items that could not be deleted"))
+			return
+		}
+	}
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+	}
+
+	var err error
+	if item.isFolder {
+		err = d.client.DeleteFolder(ctx, item.id)
+	} else {
+		err = d.client.DeleteFile(ctx, item.id)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Cancelled mid-request; not a failure of the item
+		}
+		d.fail(item, err)
+		return
+	}
+
+	d.mu.Lock()
+	d.progress.Deleted++
+	d.report()
+	d.mu.Unlock()
+}
+
+// fail records that item could not be deleted, which keeps every folder
+// above it in place.
+func (d *recursiveDelete) fail(item deleteItem, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures = append(d.failures, DeleteFailure{ID: item.id, Path: item.path, IsFolder: item.isFolder, Error: err.Error()})
+	d.progress.Failed++
+	for id := item.parentID; id != ""; id = d.parents[id] {
+		d.failedUnder[id] = true
+	}
+	d.report()
+}
+
+// setTotal updates the item count and reports it.
+func (d *recursiveDelete) setTotal(total int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.progress.Total = total
+	d.report()
+}
+
+// report passes the current progress to the callback. d.mu must be held.
+func (d *recursiveDelete) report() {
+	if d.onProgress != nil {
+		d.onProgress(d.progress)
+	}
+}
+
+// finish builds the result.
+func (d *recursiveDelete) finish(cancelled bool) *DeleteResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &DeleteResult{
+		Total:     d.progress.Total,
+		Deleted:   d.progress.Deleted,
+		Failures:  append([]DeleteFailure(nil), d.failures...),
+		Cancelled: cancelled,
+	}
+}
@@ -0,0 +1,127 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/api"
+)
+
+// fakeDeleteClient serves a fixed folder tree and records deletions.
+type fakeDeleteClient struct {
+	tree    map[string]*api.FolderContents
+	failIDs map[string]bool
+
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (c *fakeDeleteClient) ListFolderContentsAll(ctx context.Context, folderID string) (*api.FolderContents, error) {
+	if contents, ok := c.tree[folderID]; ok {
+		return contents, nil
+	}
+	return &api.FolderContents{}, nil
+}
+
+func (c *fakeDeleteClient) DeleteFile(ctx context.Context, fileID string) error {
+	return c.delete(fileID)
+}
+
+func (c *fakeDeleteClient) DeleteFolder(ctx context.Context, folderID string) error {
+	return c.delete(folderID)
+}
+
+func (c *fakeDeleteClient) delete(id string) error {
+	if c.failIDs[id] {
+		return errors.New("permission denied")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleted = append(c.deleted, id)
+	return nil
+}
+
+func newFakeTree() *fakeDeleteClient {
+	return &fakeDeleteClient{
+		tree: map[string]*api.FolderContents{
+			"root": {
+				Folders: []api.FolderInfo{{ID: "sub", Name: "sub"}, {ID: "sub2", Name: "sub2"}},
+				Files:   []api.FileInfo{{ID: "a", Name: "a.txt"}},
+			},
+			"sub":  {Files: []api.FileInfo{{ID: "b", Name: "b.txt"}, {ID: "c", Name: "c.txt"}}},
+			"sub2": {Files: []api.FileInfo{{ID: "d", Name: "d.txt"}}},
+		},
+		failIDs: map[string]bool{},
+	}
+}
+
+func TestDeleteRecursive(t *testing.T) {
+	client := newFakeTree()
+	var last DeleteProgress
+	result, err := DeleteRecursive(context.Background(), client, "root", "data", DeleteOptions{
+		Workers:    2,
+		OnProgress: func(p DeleteProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 7 || result.Deleted != 7 || len(result.Failures) != 0 {
+		t.Errorf("result = %+v, want all 7 items deleted", result)
+	}
+	if last.Listing || last.Deleted != 7 {
+		t.Errorf("last progress = %+v", last)
+	}
+	if client.deleted[len(client.deleted)-1] != "root" {
+		t.Errorf("deletion order %v, want the root folder last", client.deleted)
+	}
+}
+
+func TestDeleteRecursive_FailureKeepsParents(t *testing.T) {
+	client := newFakeTree()
+	client.failIDs["c"] = true
+
+	result, err := DeleteRecursive(context.Background(), client, "root", "data", DeleteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a, b, d and sub2 go; c fails and keeps sub and the root
+	if result.Deleted != 4 || len(result.Failures) != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+	if f := result.Failures[0]; f.ID != "c" || f.Path != "data/sub/c.txt" || f.IsFolder {
+		t.Errorf("first failure = %+v", f)
+	}
+	for _, id := range client.deleted {
+		if id == "sub" || id == "root" {
+			t.Errorf("folder %s was deleted although it still holds a file", id)
+		}
+	}
+}
+
+func TestDeleteRecursive_Cancel(t *testing.T) {
+	client := newFakeTree()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result, err := DeleteRecursive(ctx, client, "root", "data", DeleteOptions{
+		Workers: 1,
+		OnProgress: func(p DeleteProgress) {
+			if p.Deleted == 1 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) || !result.Cancelled {
+		t.Fatalf("DeleteRecursive() = %+v, %v, want a cancelled result", result, err)
+	}
+	if result.Deleted >= result.Total {
+		t.Errorf("deleted %d of %d despite the cancel", result.Deleted, result.Total)
+	}
+	for _, id := range client.deleted {
+		if id == "root" {
+			t.Error("root folder deleted despite the cancel")
+		}
+	}
+}
@@ -126,6 +126,75 @@ func CheckFolderExists(ctx context.Context, apiClient *api.Client, cache *Folder
 	return "", false, nil
 }
 
+// SplitFolderPath splits a slash-separated remote folder path such as
+// "ProjectA/Study 1" into folder names. Backslashes are accepted as
+// separators and empty segments are ignored. "." and ".." segments are
+// rejected since remote folders have no relative navigation.
+func SplitFolderPath(path string) ([]string, error) {
+	var names []string
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "." || part == ".." {
+			return nil, fmt.Errorf("invalid folder path %q: relative segment %q", path, part)
+		}
+		names = append(names, part)
+	}
+	return names, nil
+}
+
+// EnsureFolderPath walks path (see SplitFolderPath) down from parentID,
+// reusing existing folders and creating missing ones, and returns the ID of
+// the deepest folder. An empty path returns parentID unchanged.
+func EnsureFolderPath(ctx context.Context, apiClient *api.Client, cache *FolderCache, parentID, path string) (string, error) {
+	names, err := SplitFolderPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	currentID := parentID
+	for _, name := range names {
+		existingID, exists, err := CheckFolderExists(ctx, apiClient, cache, currentID, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to check if folder %s exists: %w", name, err)
+		}
+		if exists {
+			currentID = existingID
+			continue
+		}
+
+		folderID, err := apiClient.CreateFolder(ctx, name, currentID)
+		if err != nil {
+			// Another writer may have created it since the cached listing
+			cache.Invalidate(currentID)
+			if existingID, exists, checkErr := CheckFolderExists(ctx, apiClient, cache, currentID, name); checkErr == nil && exists {
+				currentID = existingID
+				continue
+			}
+			return "", fmt.Errorf("failed to create folder %s: %w", name, err)
+		}
+		// Parent listing is now stale
+		cache.Invalidate(currentID)
+		currentID = folderID
+	}
+	return currentID, nil
+}
+
+// EnsureLibraryPath is EnsureFolderPath under My Library. An empty path
+// returns "" so uploads go to the default location.
+func EnsureLibraryPath(ctx context.Context, apiClient *api.Client, cache *FolderCache, path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", nil
+	}
+	roots, err := apiClient.GetRootFolders(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get root folders: %w", err)
+	}
+	return EnsureFolderPath(ctx, apiClient, cache, roots.MyLibrary, path)
+}
+
 // processFolderParams groups the shared parameters for per-folder processing.
 type processFolderParams struct {
 	ctx                context.Context
@@ -101,3 +101,28 @@ func TestWalkStreamDirectoryOrdering(t *testing.T) {
 		seen[d] = true
 	}
 }
+
+func TestSplitFolderPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "", want: nil},
+		{path: "ProjectA", want: []string{"ProjectA"}},
+		{path: "/ProjectA//Study 1/", want: []string{"ProjectA", "Study 1"}},
+		{path: `ProjectA\Study1`, want: []string{"ProjectA", "Study1"}},
+		{path: "ProjectA/../Other", wantErr: true},
+		{path: "./ProjectA", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := SplitFolderPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SplitFolderPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("SplitFolderPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
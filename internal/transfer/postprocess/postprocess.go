@@ -0,0 +1,325 @@
+// Package postprocess runs a chain of steps on a file once it has been
+// downloaded: unpack it, write its checksum, rename it by pattern or hand
+// it to a user script.
+//
+// A chain is written as a spec of steps separated by semicolons, each a name
+// with an optional argument after a colon:
+//
+//	untar;checksum;rename:*.log={stem}-{date}{ext};script:/opt/bin/index.sh --quick
+//
+// The spec comes from the post_download config setting, or from a single
+// download request, which may also say "none" to skip the configured chain.
+package postprocess
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/util/tar"
+)
+
+// None is the spec that turns post-processing off for a request.
+const None = "none"
+
+// Step states.
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateSkipped = "skipped" // Did not apply to the file, or an earlier step failed
+	StateFailed  = "failed"
+)
+
+// Status is the state of one step of a chain for one file.
+type Status struct {
+	Step   string `json:"step"` // The step as written in the spec
+	State  string `json:"state"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// File is the downloaded file a chain works on. Steps that move the file
+// update Path.
+type File struct {
+	Path   string
+	FileID string
+}
+
+// Step is one processing step. Run returns a short description of what it
+// did, or a *SkipError when the step does not apply to the file.
+type Step interface {
+	String() string
+	Run(ctx context.Context, f *File) (string, error)
+}
+
+// SkipError reports that a step does not apply to a file. It does not stop
+// the chain.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string { return e.Reason }
+
+// Chain is a sequence of steps, run in order until one fails.
+type Chain []Step
+
+// Parse reads a chain spec. An empty spec or None yields an empty chain.
+func Parse(spec string) (Chain, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.EqualFold(spec, None) {
+		return nil, nil
+	}
+	var chain Chain
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(part, ":")
+		step, err := parseStep(strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(arg))
+		if err != nil {
+			return nil, fmt.Errorf("invalid post-download step %q: %w", part, err)
+		}
+		chain = append(chain, step)
+	}
+	return chain, nil
+}
+
+func parseStep(name, arg string) (Step, error) {
+	switch name {
+	case "untar":
+		if arg != "" && arg != "delete" {
+			return nil, fmt.Errorf("unknown option %q (want untar or untar:delete)", arg)
+		}
+		return &untarStep{deleteArchive: arg == "delete"}, nil
+	case "checksum":
+		switch strings.ToLower(arg) {
+		case "", "sha256":
+			return &checksumStep{algorithm: "sha256", newHash: sha256.New}, nil
+		case "sha512":
+			return &checksumStep{algorithm: "sha512", newHash: sha512.New}, nil
+		}
+		return nil, fmt.Errorf("unknown algorithm %q (want sha256 or sha512)", arg)
+	case "rename":
+		pattern, template, ok := strings.Cut(arg, "=")
+		if !ok || pattern == "" || template == "" {
+			return nil, errors.New("want rename:<glob>=<new name>")
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", pattern, err)
+		}
+		if strings.ContainsAny(template, `/\`) {
+			return nil, errors.New("the new name must not contain a path separator")
+		}
+		return &renameStep{pattern: pattern, template: template}, nil
+	case "script":
+		args := strings.Fields(arg)
+		if len(args) == 0 {
+			return nil, errors.New("want script:<command>")
+		}
+		return &scriptStep{args: args}, nil
+	}
+	return nil, fmt.Errorf("unknown step %q (want untar, checksum, rename or script)", name)
+}
+
+// String returns the spec of the chain.
+func (c Chain) String() string {
+	parts := make([]string, len(c))
+	for i, step := range c {
+		parts[i] = step.String()
+	}
+	return strings.Join(parts, ";")
+}
+
+// Pending returns the status of each step before the chain runs.
+func (c Chain) Pending() []Status {
+	statuses := make([]Status, len(c))
+	for i, step := range c {
+		statuses[i] = Status{Step: step.String(), State: StatePending}
+	}
+	return statuses
+}
+
+// Run runs the steps on f in order. report, if not nil, receives a copy of
+// the statuses each time one changes. The first failing step stops the
+// chain; its error is returned and the steps after it are skipped.
+func (c Chain) Run(ctx context.Context, f *File, report func([]Status)) error {
+	statuses := c.Pending()
+	update := func(i int, state, detail string) {
+		statuses[i].State, statuses[i].Detail = state, detail
+		if report != nil {
+			report(append([]Status(nil), statuses...))
+		}
+	}
+
+	for i, step := range c {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		update(i, StateRunning, "")
+		detail, err := step.Run(ctx, f)
+		var skip *SkipError
+		switch {
+		case errors.As(err, &skip):
+			update(i, StateSkipped, skip.Reason)
+		case err != nil:
+			for j := i + 1; j < len(c); j++ {
+				statuses[j].State, statuses[j].Detail = StateSkipped, "not run"
+			}
+			update(i, StateFailed, err.Error())
+			return fmt.Errorf("%s: %w", step, err)
+		default:
+			update(i, StateDone, detail)
+		}
+	}
+	return nil
+}
+
+// untarStep unpacks a tar archive next to it.
+type untarStep struct {
+	deleteArchive bool
+}
+
+func (s *untarStep) String() string {
+	if s.deleteArchive {
+		return "untar:delete"
+	}
+	return "untar"
+}
+
+func (s *untarStep) Run(ctx context.Context, f *File) (string, error) {
+	if !tar.IsArchiveName(f.Path) {
+		return "", &SkipError{Reason: "not a tar archive"}
+	}
+	n, err := tar.Extract(f.Path, filepath.Dir(f.Path))
+	if err != nil {
+		return "", err
+	}
+	if s.deleteArchive {
+		if err := os.Remove(f.Path); err != nil {
+			return "", fmt.Errorf("extracted %d files but failed to delete the archive: %w", n, err)
+		}
+	}
+	return fmt.Sprintf("extracted %d files", n), nil
+}
+
+// checksumStep writes the file's checksum to <file>.<algorithm>, in the
+// format sha256sum and sha512sum read.
+type checksumStep struct {
+	algorithm string
+	newHash   func() hash.Hash
+}
+
+func (s *checksumStep) String() string {
+	if s.algorithm == "sha256" {
+		return "checksum"
+	}
+	return "checksum:" + s.algorithm
+}
+
+func (s *checksumStep) Run(ctx context.Context, f *File) (string, error) {
+	if _, err := os.Stat(f.Path); err != nil {
+		return "", &SkipError{Reason: "file no longer exists"}
+	}
+	in, err := os.Open(f.Path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	h := s.newHash()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", f.Path, err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(f.Path))
+	if err := os.WriteFile(f.Path+"."+s.algorithm, []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("failed to write checksum file: %w", err)
+	}
+	return s.algorithm + " " + sum[:12] + "…", nil
+}
+
+// renameStep renames the file when its name matches a pattern. The new name
+// may use {name}, {stem}, {ext} (with its dot), {date} (YYYY-MM-DD) and
+// {fileid}.
+type renameStep struct {
+	pattern  string
+	template string
+}
+
+func (s *renameStep) String() string { return "rename:" + s.pattern + "=" + s.template }
+
+func (s *renameStep) Run(ctx context.Context, f *File) (string, error) {
+	name := filepath.Base(f.Path)
+	if ok, _ := filepath.Match(s.pattern, name); !ok {
+		return "", &SkipError{Reason: "name does not match " + s.pattern}
+	}
+	if _, err := os.Stat(f.Path); err != nil {
+		return "", &SkipError{Reason: "file no longer exists"}
+	}
+	ext := filepath.Ext(name)
+	newName := strings.NewReplacer(
+		"{name}", name,
+		"{stem}", strings.TrimSuffix(name, ext),
+		"{ext}", ext,
+		"{date}", time.Now().Format("2006-01-02"),
+		"{fileid}", f.FileID,
+	).Replace(s.template)
+	if newName == name {
+		return "", &SkipError{Reason: "name unchanged"}
+	}
+	target := filepath.Join(filepath.Dir(f.Path), newName)
+	if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("%s already exists", newName)
+	}
+	if err := os.Rename(f.Path, target); err != nil {
+		return "", err
+	}
+	f.Path = target
+	return "renamed to " + newName, nil
+}
+
+// scriptStep runs a user command with the file's path as its last
+// argument. The command is run directly, not through a shell, from the
+// file's directory; RESCALE_FILE_PATH and RESCALE_FILE_ID are set in its
+// environment.
+type scriptStep struct {
+	args []string
+}
+
+func (s *scriptStep) String() string { return "script:" + strings.Join(s.args, " ") }
+
+func (s *scriptStep) Run(ctx context.Context, f *File) (string, error) {
+	args := append(append([]string(nil), s.args[1:]...), f.Path)
+	cmd := exec.CommandContext(ctx, s.args[0], args...)
+	cmd.Dir = filepath.Dir(f.Path)
+	cmd.Env = append(os.Environ(), "RESCALE_FILE_PATH="+f.Path, "RESCALE_FILE_ID="+f.FileID)
+	out, err := cmd.CombinedOutput()
+	last := lastLine(string(out))
+	if err != nil {
+		if last != "" {
+			return "", fmt.Errorf("%w: %s", err, last)
+		}
+		return "", err
+	}
+	return last, nil
+}
+
+// lastLine returns the last non-empty line of out, shortened for display.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if len(last) > 200 {
+		last = last[:200] + "…"
+	}
+	return last
+}
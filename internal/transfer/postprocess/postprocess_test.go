@@ -0,0 +1,130 @@
+package postprocess
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	chain, err := Parse(" untar:delete ; checksum:sha512;rename:*.log={stem}.txt; script:echo -n ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := chain.String(), "untar:delete;checksum:sha512;rename:*.log={stem}.txt;script:echo -n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for _, spec := range []string{"", "none", "NONE"} {
+		if chain, err := Parse(spec); err != nil || len(chain) != 0 {
+			t.Errorf("Parse(%q) = %v, %v, want an empty chain", spec, chain, err)
+		}
+	}
+	for _, spec := range []string{"unzip", "untar:keep", "checksum:md5", "rename:*.log", "rename:[=x", "rename:*=a/b", "script:"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func writeTar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "results.tar")
+	writeTar(t, archive, map[string]string{"out/a.dat": "a", "b.dat": "b"})
+
+	chain, err := Parse("untar;checksum;rename:*.tar={stem}-{fileid}{ext};rename:*.zip=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &File{Path: archive, FileID: "XyZ"}
+	var reports [][]Status
+	if err := chain.Run(context.Background(), f, func(s []Status) { reports = append(reports, s) }); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"out/a.dat", "b.dat", "results.tar.sha256", "results-XyZ.tar"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if f.Path != filepath.Join(dir, "results-XyZ.tar") {
+		t.Errorf("Path = %s, want the renamed archive", f.Path)
+	}
+	sum, _ := os.ReadFile(filepath.Join(dir, "results.tar.sha256"))
+	if !strings.HasSuffix(string(sum), "  results.tar\n") {
+		t.Errorf("checksum file = %q", sum)
+	}
+
+	final := reports[len(reports)-1]
+	wantStates := []string{StateDone, StateDone, StateDone, StateSkipped}
+	for i, s := range final {
+		if s.State != wantStates[i] {
+			t.Errorf("step %s: state %s (%s), want %s", s.Step, s.State, s.Detail, wantStates[i])
+		}
+	}
+	if final[0].Detail != "extracted 2 files" {
+		t.Errorf("untar detail = %q", final[0].Detail)
+	}
+	// Each step reports running, then its outcome
+	if len(reports) != 2*len(chain) {
+		t.Errorf("got %d reports, want %d", len(reports), 2*len(chain))
+	}
+}
+
+func TestRun_StopsAtFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX command")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	os.WriteFile(path, []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "data-2.txt"), nil, 0644)
+
+	chain, _ := Parse("rename:*.txt={stem}-2{ext};script:false;checksum")
+	var final []Status
+	err := chain.Run(context.Background(), &File{Path: path}, func(s []Status) { final = s })
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Run() = %v, want the rename to fail", err)
+	}
+	if final[0].State != StateFailed || final[1].State != StateSkipped || final[2].State != StateSkipped {
+		t.Errorf("statuses = %+v", final)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("failed rename moved the file")
+	}
+}
+
+func TestUntar_RejectsEscapingEntries(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.tar.gz")
+	writeTar(t, archive, map[string]string{"../escaped.txt": "x"})
+
+	chain, _ := Parse("untar")
+	if err := chain.Run(context.Background(), &File{Path: archive}, nil); err == nil {
+		t.Fatal("Run() succeeded, want an error")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escaped.txt")); err == nil {
+		t.Error("entry was written outside the destination")
+	}
+}
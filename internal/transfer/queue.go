@@ -5,10 +5,13 @@ package transfer
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/rescale/rescale-int/internal/events"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/transfer/postprocess"
 )
 
 // RetryExecutor is implemented by components that can retry failed transfers.
@@ -56,9 +59,22 @@ type Queue struct {
 	// Cancel functions for active tasks
 	cancelFuncs map[string]context.CancelFunc
 
+	// Cancel-to-idle tracking: when each in-flight task was cancelled, until
+	// its worker calls MarkIdle
+	cancelRequested map[string]time.Time
+	cancelLatency   CancelLatency
+
+	// Pending automatic retries (tasks in TaskRetryWaiting)
+	retryTimers map[string]*time.Timer
+
 	// Retry executor (set by GUI to handle retry requests)
 	retryExecutor RetryExecutor
 
+	// Network pause: while the default route is gone, new transfers wait in
+	// WaitForNetwork and automatic retries are held instead of re-queued.
+	networkPaused  bool
+	networkResumed chan struct{} // closed by ResumeForNetwork
+
 	// Event publishing
 	eventBus *events.EventBus
 
@@ -92,6 +108,8 @@ func NewQueue(eventBus *events.EventBus) *Queue {
 		tasks:                 make([]*TransferTask, 0),
 		tasksByID:             make(map[string]*TransferTask),
 		cancelFuncs:           make(map[string]context.CancelFunc),
+		cancelRequested:       make(map[string]time.Time),
+		retryTimers:           make(map[string]*time.Timer),
 		batchCancelFuncs:      make(map[string]context.CancelFunc),
 		batchScanInProgress:   make(map[string]bool),
 		preRegisteredBatches:  make(map[string]*BatchStats),
@@ -259,6 +277,16 @@ func (q *Queue) UpdateSize(taskID string, size int64) {
 	}
 }
 
+// SetRunID tags a task with the PUR run it belongs to, for correlating its
+// events with the run's logs.
+func (q *Queue) SetRunID(taskID, runID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if task, ok := q.tasksByID[taskID]; ok && task != nil {
+		task.RunID = runID
+	}
+}
+
 // UpdateProgress updates a task's progress.
 // Progress should be 0.0 to 1.0.
 // Speed is calculated automatically using smoothed EMA.
@@ -353,6 +381,21 @@ func (q *Queue) Complete(taskID string) {
 	}
 }
 
+// SetSteps records the post-download processing status of a task and
+// publishes a progress event so the Transfers tab shows it.
+func (q *Queue) SetSteps(taskID string, steps []postprocess.Status) {
+	q.mu.Lock()
+	task, exists := q.tasksByID[taskID]
+	if exists && task != nil {
+		task.Steps = steps
+	}
+	q.mu.Unlock()
+
+	if exists && task != nil {
+		q.publishTransferEvent(events.EventTransferProgress, task)
+	}
+}
+
 // Fail marks a task as failed with an error.
 func (q *Queue) Fail(taskID string, err error) {
 	q.mu.Lock()
@@ -379,14 +422,19 @@ func (q *Queue) Cancel(taskID string) error {
 		q.mu.Unlock()
 		return errors.New("task not found")
 	}
-	if task.State != TaskActive && task.State != TaskInitializing && task.State != TaskQueued {
+	if task.State != TaskActive && task.State != TaskInitializing && task.State != TaskQueued && task.State != TaskRetryWaiting {
 		q.mu.Unlock()
 		return errors.New("task is not cancellable")
 	}
 	cancelFn := q.cancelFuncs[taskID]
+	if cancelFn != nil {
+		q.noteCancelLocked(taskID, time.Now())
+	}
 	task.State = TaskCancelled
+	task.NextRetryAt = time.Time{}
 	task.CompletedAt = time.Now()
 	delete(q.cancelFuncs, taskID)
+	q.stopRetryTimerLocked(taskID)
 	q.mu.Unlock()
 
 	if cancelFn != nil {
@@ -398,15 +446,17 @@ func (q *Queue) Cancel(taskID string) error {
 
 // CancelAll cancels all active, initializing, and queued tasks.
 func (q *Queue) CancelAll() {
+	now := time.Now()
 	q.mu.Lock()
 	tasksToCancel := make([]*TransferTask, 0)
 	cancelFns := make([]context.CancelFunc, 0)
 
 	for _, task := range q.tasks {
-		if task.State == TaskActive || task.State == TaskInitializing || task.State == TaskQueued {
+		if task.State == TaskActive || task.State == TaskInitializing || task.State == TaskQueued || task.State == TaskRetryWaiting {
 			tasksToCancel = append(tasksToCancel, task)
 			if fn := q.cancelFuncs[task.ID]; fn != nil {
 				cancelFns = append(cancelFns, fn)
+				q.noteCancelLocked(task.ID, now)
 			}
 		}
 	}
@@ -423,10 +473,12 @@ func (q *Queue) CancelAll() {
 	for _, task := range tasksToCancel {
 		if !task.IsTerminal() {
 			task.State = TaskCancelled
+			task.NextRetryAt = time.Time{}
 			task.CompletedAt = time.Now()
 			actuallyCancelled = append(actuallyCancelled, task)
 		}
 		delete(q.cancelFuncs, task.ID)
+		q.stopRetryTimerLocked(task.ID)
 	}
 	q.mu.Unlock()
 
@@ -456,27 +508,207 @@ func (q *Queue) Retry(taskID string) (string, error) {
 		return "", errors.New("no retry executor configured")
 	}
 
-	// Reset the existing task instead of creating a new one,
-	// keeping a single entry in the queue instead of duplicates.
+	// A manual retry of a task that already gave up starts a fresh
+	// automatic-retry budget; "retry now" on a waiting task keeps its count.
+	q.mu.Lock()
+	q.stopRetryTimerLocked(taskID)
+	q.mu.Unlock()
 	originalTask.mu.Lock()
-	originalTask.State = TaskQueued
-	originalTask.Progress = 0.0
-	originalTask.Speed = 0.0
-	originalTask.Error = nil
-	originalTask.StartedAt = time.Time{}
-	originalTask.CompletedAt = time.Time{}
-	originalTask.lastBytes = 0
-	originalTask.lastUpdateTime = time.Time{}
-	originalTask.lastBatchBytes = 0
-	// Note: Keep ID, Type, Name, Source, Dest, Size, CreatedAt unchanged
+	if originalTask.State != TaskRetryWaiting {
+		originalTask.RetryAttempts = 0
+	}
 	originalTask.mu.Unlock()
 
-	q.publishTransferEvent(events.EventTransferQueued, originalTask)
+	q.requeue(originalTask, executor)
+	return taskID, nil
+}
+
+// requeue resets a task to TaskQueued and hands it to the retry executor.
+// Reuses the same task entry, keeping a single entry in the queue instead of
+// duplicates.
+func (q *Queue) requeue(task *TransferTask, executor RetryExecutor) {
+	task.mu.Lock()
+	task.State = TaskQueued
+	task.Progress = 0.0
+	task.Speed = 0.0
+	task.Error = nil
+	task.NextRetryAt = time.Time{}
+	task.StartedAt = time.Time{}
+	task.CompletedAt = time.Time{}
+	task.lastBytes = 0
+	task.lastUpdateTime = time.Time{}
+	task.lastBatchBytes = 0
+	task.Steps = nil
+	// Note: Keep ID, Type, Name, Source, Dest, Size, CreatedAt, RetryAttempts unchanged
+	task.mu.Unlock()
+
+	q.mu.Lock()
+	delete(q.cancelRequested, task.ID)
+	q.mu.Unlock()
+
+	q.publishTransferEvent(events.EventTransferQueued, task)
 
 	// Execute retry via executor (in goroutine to not block)
-	go executor.ExecuteRetry(originalTask)
+	go executor.ExecuteRetry(task)
+}
 
-	return taskID, nil
+// ScheduleRetry moves a failed in-flight task to TaskRetryWaiting and
+// re-queues it automatically after delay. err is kept on the task so the
+// Transfers tab can show why it is waiting. Returns false if the task is
+// unknown, already terminal, or no retry executor is configured; the caller
+// should then Fail the task as usual.
+func (q *Queue) ScheduleRetry(taskID string, err error, delay time.Duration) bool {
+	q.mu.Lock()
+	task, exists := q.tasksByID[taskID]
+	if !exists || task == nil || task.IsTerminal() || task.GetState() == TaskRetryWaiting || q.retryExecutor == nil {
+		q.mu.Unlock()
+		return false
+	}
+	task.mu.Lock()
+	task.State = TaskRetryWaiting
+	task.Error = err
+	task.Speed = 0.0
+	task.RetryAttempts++
+	task.NextRetryAt = time.Now().Add(delay)
+	task.mu.Unlock()
+	delete(q.cancelFuncs, taskID)
+	q.stopRetryTimerLocked(taskID)
+	q.retryTimers[taskID] = time.AfterFunc(delay, func() { q.fireRetry(taskID) })
+	q.mu.Unlock()
+
+	q.publishTransferEvent(events.EventTransferRetryScheduled, task)
+	return true
+}
+
+// fireRetry runs when a scheduled retry's delay elapses.
+func (q *Queue) fireRetry(taskID string) {
+	q.mu.Lock()
+	task, exists := q.tasksByID[taskID]
+	executor := q.retryExecutor
+	delete(q.retryTimers, taskID)
+	q.mu.Unlock()
+
+	// The task may have been retried manually, given up, or cancelled
+	// between the timer firing and acquiring the lock.
+	if !exists || task == nil || task.GetState() != TaskRetryWaiting || executor == nil {
+		return
+	}
+	// Offline: leave the task waiting; ResumeForNetwork re-queues it.
+	if q.NetworkPaused() {
+		return
+	}
+	q.requeue(task, executor)
+}
+
+// PauseForNetwork holds the queue while the machine has no network route.
+// Transfers not yet started block in WaitForNetwork and automatic retries
+// stay in TaskRetryWaiting instead of failing again. Idempotent.
+func (q *Queue) PauseForNetwork() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.networkPaused {
+		return
+	}
+	q.networkPaused = true
+	q.networkResumed = make(chan struct{})
+}
+
+// ResumeForNetwork releases a network pause. Tasks waiting for an automatic
+// retry are re-queued immediately if they failed because the connection was
+// lost or their retry came due while offline; other retries keep their
+// schedule. Returns the number re-queued.
+func (q *Queue) ResumeForNetwork() int {
+	q.mu.Lock()
+	if !q.networkPaused {
+		q.mu.Unlock()
+		return 0
+	}
+	q.networkPaused = false
+	close(q.networkResumed)
+	q.networkResumed = nil
+
+	executor := q.retryExecutor
+	var ready []*TransferTask
+	now := time.Now()
+	for _, task := range q.tasks {
+		if task.GetState() != TaskRetryWaiting {
+			continue
+		}
+		task.mu.RLock()
+		held := inthttp.IsConnectionLost(task.Error) || !task.NextRetryAt.After(now)
+		task.mu.RUnlock()
+		if held {
+			q.stopRetryTimerLocked(task.ID)
+			ready = append(ready, task)
+		}
+	}
+	q.mu.Unlock()
+
+	if executor == nil {
+		return 0
+	}
+	for _, task := range ready {
+		q.requeue(task, executor)
+	}
+	return len(ready)
+}
+
+// NetworkPaused reports whether the queue is held for a network outage.
+func (q *Queue) NetworkPaused() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.networkPaused
+}
+
+// WaitForNetwork blocks while the queue is paused for a network outage.
+// Returns ctx.Err() if ctx is cancelled first.
+func (q *Queue) WaitForNetwork(ctx context.Context) error {
+	q.mu.RLock()
+	resumed := q.networkResumed
+	q.mu.RUnlock()
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GiveUp abandons a scheduled automatic retry, leaving the task failed with
+// the error that triggered the retry.
+func (q *Queue) GiveUp(taskID string) error {
+	q.mu.Lock()
+	task, exists := q.tasksByID[taskID]
+	if !exists || task == nil {
+		q.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if task.GetState() != TaskRetryWaiting {
+		q.mu.Unlock()
+		return errors.New("task has no pending retry")
+	}
+	q.stopRetryTimerLocked(taskID)
+	task.mu.Lock()
+	task.State = TaskFailed
+	task.NextRetryAt = time.Time{}
+	task.CompletedAt = time.Now()
+	task.mu.Unlock()
+	q.mu.Unlock()
+
+	q.publishTransferEvent(events.EventTransferFailed, task)
+	return nil
+}
+
+// stopRetryTimerLocked stops and forgets a pending retry timer.
+// Caller must hold q.mu.
+func (q *Queue) stopRetryTimerLocked(taskID string) {
+	if timer, ok := q.retryTimers[taskID]; ok {
+		timer.Stop()
+		delete(q.retryTimers, taskID)
+	}
 }
 
 // ClearCompleted removes all completed/failed/cancelled tasks from the queue.
@@ -516,7 +748,7 @@ func (q *Queue) GetStats() QueueStats {
 	stats := QueueStats{}
 	for _, task := range q.tasks {
 		switch task.GetState() {
-		case TaskQueued:
+		case TaskQueued, TaskRetryWaiting:
 			stats.Queued++
 		case TaskInitializing:
 			stats.Initializing++
@@ -559,6 +791,36 @@ func (q *Queue) GetTask(taskID string) (TransferTask, bool) {
 	return task.Clone(), true
 }
 
+// FindPending returns a copy of a transfer of the given type, source and
+// destination that is queued, running, paused or waiting to retry, so the
+// same file is not transferred twice at once. Paths are compared cleaned.
+func (q *Queue) FindPending(taskType TaskType, source, dest string) (TransferTask, bool) {
+	source, dest = cleanTransferPath(source), cleanTransferPath(dest)
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	for _, task := range q.tasks {
+		if task.Type != taskType || cleanTransferPath(task.Source) != source || cleanTransferPath(task.Dest) != dest {
+			continue
+		}
+		switch task.GetState() {
+		case TaskCompleted, TaskFailed, TaskCancelled:
+			continue
+		}
+		return task.Clone(), true
+	}
+	return TransferTask{}, false
+}
+
+// cleanTransferPath cleans a local path; file and folder IDs are unchanged.
+func cleanTransferPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	return filepath.Clean(p)
+}
+
 // publishTransferEvent publishes a transfer event to the event bus.
 // Suppresses progress events for batched tasks to reduce event flood;
 // terminal events (completed, failed, cancelled) are always published.
@@ -575,8 +837,10 @@ func (q *Queue) publishTransferEvent(eventType events.EventType, task *TransferT
 
 	event := &events.TransferEvent{
 		BaseEvent: events.BaseEvent{
-			EventType: eventType,
-			Time:      time.Now(),
+			EventType:  eventType,
+			Time:       time.Now(),
+			RunID:      task.RunID,
+			TransferID: task.ID,
 		},
 		TaskID:   task.ID,
 		TaskType: string(task.Type),
@@ -647,7 +911,7 @@ func (q *Queue) GetAllBatchStats() []BatchStats {
 
 		state := task.GetState()
 		switch state {
-		case TaskQueued:
+		case TaskQueued, TaskRetryWaiting:
 			bs.Queued++
 		case TaskInitializing:
 			bs.Active++ // initializing tasks have a semaphore slot and are doing real work
@@ -785,8 +1049,8 @@ func (q *Queue) GetBatchTasks(batchID string, offset, limit int, stateFilter str
 					continue
 				}
 			} else if stateFilter == "queued" {
-				// Only tasks waiting for a slot
-				if state != TaskQueued {
+				// Only tasks waiting for a slot or a scheduled retry
+				if state != TaskQueued && state != TaskRetryWaiting {
 					continue
 				}
 			} else if string(state) != stateFilter {
@@ -851,6 +1115,7 @@ func (q *Queue) CancelBatch(batchID string) error {
 	}
 	q.mu.Unlock()
 
+	now := time.Now()
 	q.mu.Lock()
 	var tasksToCancel []*TransferTask
 	var cancelFns []context.CancelFunc
@@ -866,6 +1131,7 @@ func (q *Queue) CancelBatch(batchID string) error {
 		tasksToCancel = append(tasksToCancel, task)
 		if fn := q.cancelFuncs[task.ID]; fn != nil {
 			cancelFns = append(cancelFns, fn)
+			q.noteCancelLocked(task.ID, now)
 		}
 	}
 	q.mu.Unlock()
@@ -881,10 +1147,12 @@ func (q *Queue) CancelBatch(batchID string) error {
 	for _, task := range tasksToCancel {
 		if !task.IsTerminal() {
 			task.State = TaskCancelled
+			task.NextRetryAt = time.Time{}
 			task.CompletedAt = time.Now()
 			actuallyCancelled = append(actuallyCancelled, task)
 		}
 		delete(q.cancelFuncs, task.ID)
+		q.stopRetryTimerLocked(task.ID)
 	}
 	q.mu.Unlock()
 
@@ -995,22 +1263,57 @@ func (q *Queue) CleanupBatch(batchID string) {
 
 // RetryFailedInBatch retries all failed tasks in a batch.
 func (q *Queue) RetryFailedInBatch(batchID string) error {
+	q.retryFailedWhere(func(task *TransferTask) bool { return task.BatchID == batchID })
+	return nil
+}
+
+// RetryAllFailed retries every failed task, in batches or not, leaving
+// completed and cancelled tasks alone. Returns the number re-queued.
+func (q *Queue) RetryAllFailed() int {
+	return q.retryFailedWhere(func(*TransferTask) bool { return true })
+}
+
+// retryFailedWhere re-queues the failed tasks match selects and returns how
+// many were re-queued.
+func (q *Queue) retryFailedWhere(match func(*TransferTask) bool) int {
 	q.mu.RLock()
 	var failedTaskIDs []string
 	for _, task := range q.tasks {
-		if task.BatchID == batchID && task.GetState() == TaskFailed {
+		if match(task) && task.GetState() == TaskFailed {
 			failedTaskIDs = append(failedTaskIDs, task.ID)
 		}
 	}
 	q.mu.RUnlock()
 
+	retried := 0
 	for _, taskID := range failedTaskIDs {
 		if _, err := q.Retry(taskID); err != nil {
-			// Log but continue — don't fail the whole batch retry for one task
+			// Continue — don't fail the whole retry for one task
 			continue
 		}
+		retried++
 	}
-	return nil
+	return retried
+}
+
+// GetFailedTasks returns up to limit failed tasks across all batches, oldest
+// first, and the total number of failed tasks.
+func (q *Queue) GetFailedTasks(limit int) ([]TransferTask, int) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	result := []TransferTask{}
+	total := 0
+	for _, task := range q.tasks {
+		if task.GetState() != TaskFailed {
+			continue
+		}
+		total++
+		if len(result) < limit {
+			result = append(result, task.Clone())
+		}
+	}
+	return result, total
 }
 
 // ensureBatchTicker starts the batch progress ticker if not already running.
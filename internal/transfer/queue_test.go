@@ -404,6 +404,60 @@ func TestQueueCancelAll(t *testing.T) {
 	}
 }
 
+func TestQueueCancelLatency(t *testing.T) {
+	queue := NewQueue(nil)
+
+	task := queue.TrackTransfer("test.dat", 1000, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+	queue.SetCancel(task.ID, func() {})
+
+	if err := queue.Cancel(task.ID); err != nil {
+		t.Fatalf("Cancel returned error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	queue.MarkIdle(task.ID)
+
+	lat := queue.GetCancelLatency()
+	if lat.Count != 1 {
+		t.Fatalf("Expected 1 measured cancel, got %d", lat.Count)
+	}
+	if lat.Max < 5*time.Millisecond {
+		t.Errorf("Expected max latency >= 5ms, got %v", lat.Max)
+	}
+	if lat.Slow != 0 {
+		t.Errorf("Expected 0 slow cancels, got %d", lat.Slow)
+	}
+	if lat.Mean() != lat.Max {
+		t.Errorf("Expected mean %v to equal max for a single cancel, got %v", lat.Max, lat.Mean())
+	}
+
+	// A second MarkIdle for the same task is not counted again
+	queue.MarkIdle(task.ID)
+	if got := queue.GetCancelLatency().Count; got != 1 {
+		t.Errorf("Expected 1 measured cancel after repeat MarkIdle, got %d", got)
+	}
+}
+
+func TestQueueCancelLatencyIgnoresIdleTasks(t *testing.T) {
+	queue := NewQueue(nil)
+
+	// Worker finished without a cancel
+	done := queue.TrackTransfer("done.dat", 100, TaskTypeUpload, "/p1", "f")
+	queue.Activate(done.ID)
+	queue.SetCancel(done.ID, func() {})
+	queue.Complete(done.ID)
+	queue.MarkIdle(done.ID)
+
+	// Cancelled before a worker picked it up: nothing to wait for
+	queued := queue.TrackTransfer("queued.dat", 100, TaskTypeUpload, "/p2", "f")
+	queue.CancelAll()
+	queue.MarkIdle(queued.ID)
+
+	if got := queue.GetCancelLatency().Count; got != 0 {
+		t.Errorf("Expected 0 measured cancels, got %d", got)
+	}
+}
+
 func TestQueueGetTasks(t *testing.T) {
 	queue := NewQueue(nil)
 
@@ -566,6 +620,223 @@ func TestQueueRetryNonFailed(t *testing.T) {
 	}
 }
 
+func TestQueueScheduleRetry(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	task := queue.TrackTransfer("flaky.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+
+	if !queue.ScheduleRetry(task.ID, errors.New("connection reset"), 20*time.Millisecond) {
+		t.Fatal("ScheduleRetry returned false for an in-flight task")
+	}
+
+	waiting, _ := queue.GetTask(task.ID)
+	if waiting.State != TaskRetryWaiting {
+		t.Errorf("State = %s, want %s", waiting.State, TaskRetryWaiting)
+	}
+	if waiting.RetryAttempts != 1 || waiting.NextRetryAt.IsZero() || waiting.Error == nil {
+		t.Errorf("unexpected retry bookkeeping: attempts=%d next=%v err=%v", waiting.RetryAttempts, waiting.NextRetryAt, waiting.Error)
+	}
+	if stats := queue.GetStats(); stats.Queued != 1 || stats.Failed != 0 {
+		t.Errorf("retry-waiting task should count as queued, got %+v", stats)
+	}
+
+	if !executor.waitForExecutions(1, 5*time.Second) {
+		t.Fatal("Timed out waiting for scheduled retry")
+	}
+	requeued, _ := queue.GetTask(task.ID)
+	if requeued.State != TaskQueued || requeued.Error != nil || !requeued.NextRetryAt.IsZero() {
+		t.Errorf("after firing: state=%s err=%v next=%v", requeued.State, requeued.Error, requeued.NextRetryAt)
+	}
+	if requeued.RetryAttempts != 1 {
+		t.Errorf("RetryAttempts = %d, want 1 (kept across automatic re-queue)", requeued.RetryAttempts)
+	}
+}
+
+func TestQueueNetworkPauseHoldsRetries(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	task := queue.TrackTransfer("roaming.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+
+	queue.PauseForNetwork()
+	if !queue.NetworkPaused() {
+		t.Fatal("NetworkPaused() = false after PauseForNetwork")
+	}
+	queue.ScheduleRetry(task.ID, errors.New("network is unreachable"), time.Millisecond)
+
+	// The timer fires but the task is held while offline
+	if executor.waitForExecutions(1, 50*time.Millisecond) {
+		t.Fatal("retry ran while the network was paused")
+	}
+	if got, _ := queue.GetTask(task.ID); got.State != TaskRetryWaiting {
+		t.Errorf("State = %s, want %s while paused", got.State, TaskRetryWaiting)
+	}
+
+	// New transfers block until resume
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- queue.WaitForNetwork(context.Background()) }()
+	select {
+	case <-waitErr:
+		t.Fatal("WaitForNetwork returned while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if n := queue.ResumeForNetwork(); n != 1 {
+		t.Errorf("ResumeForNetwork() = %d, want 1", n)
+	}
+	if !executor.waitForExecutions(1, 5*time.Second) {
+		t.Fatal("held retry not re-queued on resume")
+	}
+	select {
+	case err := <-waitErr:
+		if err != nil {
+			t.Errorf("WaitForNetwork() = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForNetwork did not return after resume")
+	}
+	if queue.ResumeForNetwork() != 0 {
+		t.Error("second ResumeForNetwork should be a no-op")
+	}
+}
+
+func TestQueueResumeForNetworkKeepsOtherRetries(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	dropped := queue.TrackTransfer("dropped.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(dropped.ID)
+	throttled := queue.TrackTransfer("throttled.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(throttled.ID)
+
+	queue.PauseForNetwork()
+	queue.ScheduleRetry(dropped.ID, errors.New("read: connection reset by peer"), time.Hour)
+	queue.ScheduleRetry(throttled.ID, errors.New("503 service unavailable"), time.Hour)
+
+	if n := queue.ResumeForNetwork(); n != 1 {
+		t.Errorf("ResumeForNetwork() = %d, want 1", n)
+	}
+	if !executor.waitForExecutions(1, 5*time.Second) {
+		t.Fatal("connection-lost retry not re-queued on resume")
+	}
+	if got, _ := queue.GetTask(throttled.ID); got.State != TaskRetryWaiting {
+		t.Errorf("throttled State = %s, want %s until its retry is due", got.State, TaskRetryWaiting)
+	}
+}
+
+func TestQueueWaitForNetworkCancelled(t *testing.T) {
+	queue := NewQueue(nil)
+	if err := queue.WaitForNetwork(context.Background()); err != nil {
+		t.Fatalf("WaitForNetwork() = %v when not paused", err)
+	}
+
+	queue.PauseForNetwork()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := queue.WaitForNetwork(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForNetwork() = %v, want context.Canceled", err)
+	}
+}
+
+func TestQueueScheduleRetry_TerminalTask(t *testing.T) {
+	queue := NewQueue(nil)
+	queue.SetRetryExecutor(newMockRetryExecutor())
+
+	task := queue.TrackTransfer("done.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Complete(task.ID)
+
+	if queue.ScheduleRetry(task.ID, errors.New("late"), time.Millisecond) {
+		t.Error("ScheduleRetry should refuse a terminal task")
+	}
+}
+
+func TestQueueRetryNowCancelsTimer(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	task := queue.TrackTransfer("flaky.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+	queue.ScheduleRetry(task.ID, errors.New("503"), time.Hour)
+
+	if _, err := queue.Retry(task.ID); err != nil {
+		t.Fatalf("Retry on waiting task: %v", err)
+	}
+	if !executor.waitForExecutions(1, 5*time.Second) {
+		t.Fatal("Timed out waiting for retry execution")
+	}
+	got, _ := queue.GetTask(task.ID)
+	if got.RetryAttempts != 1 {
+		t.Errorf("RetryAttempts = %d, want 1 (retry now keeps the count)", got.RetryAttempts)
+	}
+
+	queue.mu.RLock()
+	pending := len(queue.retryTimers)
+	queue.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("expected pending timer to be stopped, %d remain", pending)
+	}
+}
+
+func TestQueueGiveUp(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	task := queue.TrackTransfer("flaky.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+	queue.ScheduleRetry(task.ID, errors.New("timeout"), 20*time.Millisecond)
+
+	if err := queue.GiveUp(task.ID); err != nil {
+		t.Fatalf("GiveUp: %v", err)
+	}
+	got, _ := queue.GetTask(task.ID)
+	if got.State != TaskFailed || got.Error == nil {
+		t.Errorf("after GiveUp: state=%s err=%v, want failed with original error", got.State, got.Error)
+	}
+	if executor.waitForExecutions(1, 100*time.Millisecond) {
+		t.Error("retry fired after GiveUp")
+	}
+	if err := queue.GiveUp(task.ID); err == nil {
+		t.Error("GiveUp on a failed task should return an error")
+	}
+
+	// A manual retry after giving up starts a fresh automatic budget.
+	if _, err := queue.Retry(task.ID); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	executor.waitForExecutions(1, 5*time.Second)
+	if got, _ := queue.GetTask(task.ID); got.RetryAttempts != 0 {
+		t.Errorf("RetryAttempts = %d after manual retry, want 0", got.RetryAttempts)
+	}
+}
+
+func TestQueueCancelRetryWaiting(t *testing.T) {
+	queue := NewQueue(nil)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	task := queue.TrackTransfer("flaky.dat", 100, TaskTypeUpload, "/path", "folder")
+	queue.Activate(task.ID)
+	queue.ScheduleRetry(task.ID, errors.New("timeout"), 20*time.Millisecond)
+
+	if err := queue.Cancel(task.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if got, _ := queue.GetTask(task.ID); got.State != TaskCancelled {
+		t.Errorf("State = %s, want cancelled", got.State)
+	}
+	if executor.waitForExecutions(1, 100*time.Millisecond) {
+		t.Error("retry fired after Cancel")
+	}
+}
+
 func TestQueueEvents(t *testing.T) {
 	eventBus := events.NewEventBus(100)
 	defer eventBus.Close()
@@ -1051,6 +1322,43 @@ func TestRetryFailedInBatch(t *testing.T) {
 	_ = t3 // Queued task should not be retried
 }
 
+func TestRetryAllFailed(t *testing.T) {
+	eb := events.NewEventBus(100)
+	queue := NewQueue(eb)
+	executor := newMockRetryExecutor()
+	queue.SetRetryExecutor(executor)
+
+	// Failed tasks in two batches and one ungrouped, plus a completed task
+	t1 := queue.TrackTransferWithBatch("a.txt", 100, TaskTypeUpload, "/a", "f", "FileBrowser", "batch1", "Folder")
+	t2 := queue.TrackTransferWithBatch("b.txt", 200, TaskTypeUpload, "/b", "f", "FileBrowser", "batch2", "Folder")
+	t3 := queue.TrackTransferWithBatch("c.txt", 300, TaskTypeUpload, "/c", "f", "FileBrowser", "", "")
+	t4 := queue.TrackTransferWithBatch("d.txt", 400, TaskTypeUpload, "/d", "f", "FileBrowser", "batch1", "Folder")
+	for _, task := range []*TransferTask{t1, t2, t3} {
+		queue.Activate(task.ID)
+		queue.Fail(task.ID, errors.New("part 2 of 4 failed (permanent): 400 Bad Request"))
+	}
+	queue.Activate(t4.ID)
+	queue.Complete(t4.ID)
+
+	failed, total := queue.GetFailedTasks(2)
+	if total != 3 || len(failed) != 2 {
+		t.Fatalf("GetFailedTasks(2) = %d tasks, total %d; want 2, 3", len(failed), total)
+	}
+
+	if n := queue.RetryAllFailed(); n != 3 {
+		t.Errorf("RetryAllFailed() = %d, want 3", n)
+	}
+	if !executor.waitForExecutions(3, 2*time.Second) {
+		t.Fatal("Timed out waiting for retry execution")
+	}
+	if _, total := queue.GetFailedTasks(10); total != 0 {
+		t.Errorf("%d tasks still failed after RetryAllFailed", total)
+	}
+	if task, _ := queue.GetTask(t4.ID); task.State != TaskCompleted {
+		t.Errorf("Completed task should remain completed, got %s", task.State)
+	}
+}
+
 func TestBatchProgressSuppressesIndividual(t *testing.T) {
 	eb := events.NewEventBus(100)
 	queue := NewQueue(eb)
@@ -1426,3 +1734,27 @@ func TestClearCompletedRemovesPlaceholderBatchSkip(t *testing.T) {
 		}
 	}
 }
+
+func TestFindPending(t *testing.T) {
+	queue := NewQueue(nil)
+	task := queue.TrackTransfer("a.dat", 10, TaskTypeUpload, "data/./a.dat", "folder1")
+
+	if got, ok := queue.FindPending(TaskTypeUpload, "data/a.dat", "folder1"); !ok || got.ID != task.ID {
+		t.Fatalf("FindPending(same file) = %v, %v, want task %s", got.ID, ok, task.ID)
+	}
+	if _, ok := queue.FindPending(TaskTypeUpload, "data/a.dat", "folder2"); ok {
+		t.Error("FindPending matched a different destination")
+	}
+	if _, ok := queue.FindPending(TaskTypeDownload, "data/a.dat", "folder1"); ok {
+		t.Error("FindPending matched a different direction")
+	}
+
+	queue.Activate(task.ID)
+	if _, ok := queue.FindPending(TaskTypeUpload, "data/a.dat", "folder1"); !ok {
+		t.Error("FindPending missed a running transfer")
+	}
+	queue.Complete(task.ID)
+	if _, ok := queue.FindPending(TaskTypeUpload, "data/a.dat", "folder1"); ok {
+		t.Error("FindPending matched a completed transfer")
+	}
+}
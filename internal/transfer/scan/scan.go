@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
 	"github.com/rescale/rescale-int/internal/constants"
@@ -23,9 +24,12 @@ type RemoteFolderInfo struct {
 // RemoteFileTask represents a file to download
 type RemoteFileTask struct {
 	FileID       string
+	FolderID     string // Folder holding the file
 	Name         string
 	RelativePath string
 	Size         int64
+	DateUploaded time.Time
+	Checksums    []models.FileChecksum
 	CloudFile    *models.CloudFile
 }
 
@@ -86,9 +90,12 @@ func ScanRemoteFolderRecursive(
 		fileRelPath := filepath.Join(relativePath, file.Name)
 		files = append(files, RemoteFileTask{
 			FileID:       file.ID,
+			FolderID:     folderID,
 			Name:         file.Name,
 			RelativePath: fileRelPath,
 			Size:         file.DecryptedSize,
+			DateUploaded: file.DateUploaded,
+			Checksums:    file.FileChecksums,
 			CloudFile:    file.ToCloudFile(),
 		})
 	}
@@ -149,9 +156,12 @@ func scanRemoteFolderRecursiveImpl(
 		fileRelPath := filepath.Join(relativePath, file.Name)
 		files = append(files, RemoteFileTask{
 			FileID:       file.ID,
+			FolderID:     folderID,
 			Name:         file.Name,
 			RelativePath: fileRelPath,
 			Size:         file.DecryptedSize,
+			DateUploaded: file.DateUploaded,
+			Checksums:    file.FileChecksums,
 			CloudFile:    file.ToCloudFile(),
 		})
 	}
@@ -358,9 +368,12 @@ func ScanRemoteFolderStreaming(
 								fileRelPath := filepath.Join(work.relativePath, file.Name)
 								task := RemoteFileTask{
 									FileID:       file.ID,
+									FolderID:     work.folderID,
 									Name:         file.Name,
 									RelativePath: fileRelPath,
 									Size:         file.DecryptedSize,
+									DateUploaded: file.DateUploaded,
+									Checksums:    file.FileChecksums,
 									CloudFile:    file.ToCloudFile(),
 								}
 
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/rescale/rescale-int/internal/transfer/postprocess"
 )
 
 // TaskType indicates whether a task is an upload or download.
@@ -20,13 +22,14 @@ const (
 type TaskState string
 
 const (
-	TaskQueued       TaskState = "queued"       // Waiting in queue for semaphore slot
-	TaskInitializing TaskState = "initializing" // Acquired slot, initializing upload/download session
-	TaskActive       TaskState = "active"       // Actually transferring bytes
-	TaskPaused       TaskState = "paused"       // Paused by user
-	TaskCompleted    TaskState = "completed"    // Successfully completed
-	TaskFailed       TaskState = "failed"       // Failed with error
-	TaskCancelled    TaskState = "cancelled"    // Cancelled by user
+	TaskQueued       TaskState = "queued"        // Waiting in queue for semaphore slot
+	TaskInitializing TaskState = "initializing"  // Acquired slot, initializing upload/download session
+	TaskActive       TaskState = "active"        // Actually transferring bytes
+	TaskPaused       TaskState = "paused"        // Paused by user
+	TaskCompleted    TaskState = "completed"     // Successfully completed
+	TaskFailed       TaskState = "failed"        // Failed with error
+	TaskCancelled    TaskState = "cancelled"     // Cancelled by user
+	TaskRetryWaiting TaskState = "retry_waiting" // Failed transiently, automatic retry scheduled
 )
 
 // TransferTask represents a single upload or download task in the queue.
@@ -36,13 +39,23 @@ type TransferTask struct {
 	Type TaskType // Upload or download
 
 	// Source and destination
-	Name        string // Display name (filename)
-	Source      string // Local path (upload) or remote file ID (download)
-	Dest        string // Remote folder ID (upload) or local path (download)
-	Size        int64  // File size in bytes
-	SourceLabel string // Origin context ("PUR", "SingleJob", "FileBrowser")
-	BatchID     string // Groups related transfers for bulk display
-	BatchLabel  string // Display name for the batch (folder name, etc.)
+	Name        string   // Display name (filename)
+	Source      string   // Local path (upload) or remote file ID (download)
+	Dest        string   // Remote folder ID (upload) or local path (download)
+	Size        int64    // File size in bytes
+	SourceLabel string   // Origin context ("PUR", "SingleJob", "FileBrowser")
+	BatchID     string   // Groups related transfers for bulk display
+	BatchLabel  string   // Display name for the batch (folder name, etc.)
+	RunID       string   // PUR run the transfer belongs to ("" = none)
+	Tags        []string // Tags applied after upload; carried so retries re-apply them
+
+	// Upload duplicate-name outcome, carried so retries keep it
+	RemoteName     string   // Name to register the file under ("" = base name of Source)
+	ReplaceFileIDs []string // Existing files deleted once the upload succeeds
+
+	// Post-download processing, carried so retries re-run it
+	PostProcess string               // Chain spec for this download ("" = configured default, "none" = off)
+	Steps       []postprocess.Status // Per-step status once the chain has started
 
 	// State tracking
 	State    TaskState // Current state
@@ -50,6 +63,10 @@ type TransferTask struct {
 	Speed    float64   // bytes/sec (smoothed with EMA)
 	Error    error     // Error if failed
 
+	// Automatic retry tracking
+	RetryAttempts int       // Automatic retries scheduled since the last manual start
+	NextRetryAt   time.Time // When the scheduled retry fires (TaskRetryWaiting only)
+
 	// Speed calculation internals (for EMA smoothing)
 	lastBytes      int64     // Bytes transferred at last update
 	lastUpdateTime time.Time // Time of last update
@@ -63,9 +80,9 @@ type TransferTask struct {
 	CompletedAt time.Time // When task completed/failed/cancelled
 
 	// Internal
-	mu     sync.RWMutex        // Protects all fields
-	ctx    context.Context     // For cancellation
-	cancel context.CancelFunc  // Cancel function
+	mu     sync.RWMutex       // Protects all fields
+	ctx    context.Context    // For cancellation
+	cancel context.CancelFunc // Cancel function
 }
 
 // NewTransferTask creates a new transfer task with the given parameters.
@@ -228,22 +245,25 @@ func (t *TransferTask) Clone() TransferTask {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 	return TransferTask{
-		ID:          t.ID,
-		Type:        t.Type,
-		Name:        t.Name,
-		Source:      t.Source,
-		Dest:        t.Dest,
-		Size:        t.Size,
-		SourceLabel: t.SourceLabel,
-		BatchID:     t.BatchID,
-		BatchLabel:  t.BatchLabel,
-		State:       t.State,
-		Progress:    t.Progress,
-		Speed:       t.Speed,
-		Error:       t.Error,
-		CreatedAt:   t.CreatedAt,
-		StartedAt:   t.StartedAt,
-		CompletedAt: t.CompletedAt,
+		ID:            t.ID,
+		Type:          t.Type,
+		Name:          t.Name,
+		Source:        t.Source,
+		Dest:          t.Dest,
+		Size:          t.Size,
+		SourceLabel:   t.SourceLabel,
+		BatchID:       t.BatchID,
+		BatchLabel:    t.BatchLabel,
+		RunID:         t.RunID,
+		State:         t.State,
+		Progress:      t.Progress,
+		Speed:         t.Speed,
+		Error:         t.Error,
+		RetryAttempts: t.RetryAttempts,
+		NextRetryAt:   t.NextRetryAt,
+		CreatedAt:     t.CreatedAt,
+		StartedAt:     t.StartedAt,
+		CompletedAt:   t.CompletedAt,
 	}
 }
 
@@ -254,10 +274,11 @@ func (t *TransferTask) IsTerminal() bool {
 	return state == TaskCompleted || state == TaskFailed || state == TaskCancelled
 }
 
-// CanRetry returns true if the task can be retried (failed or cancelled).
+// CanRetry returns true if the task can be retried (failed, cancelled, or
+// waiting for an automatic retry).
 func (t *TransferTask) CanRetry() bool {
 	state := t.GetState()
-	return state == TaskFailed || state == TaskCancelled
+	return state == TaskFailed || state == TaskCancelled || state == TaskRetryWaiting
 }
 
 // ID generation
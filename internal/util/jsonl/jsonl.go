@@ -0,0 +1,73 @@
+// Package jsonl appends to and reads the append-only JSON Lines logs
+// Interlink keeps under its config directory: upload, run and job histories,
+// network usage and audit logs. Each line holds one JSON record.
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// maxLine is the longest line Read accepts.
+const maxLine = 1024 * 1024
+
+// appendMu serializes appends from concurrent goroutines in this process, so
+// lines of the same log never interleave.
+var appendMu sync.Mutex
+
+// Append adds v as one line to the log at path, creating the file and its
+// directory if needed. name describes the log in errors, e.g. "run history".
+func Append(path, name string, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", name, err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return f.Close()
+}
+
+// Read calls fn with each record in the log at path, in file order. A
+// missing file has no records; lines that do not decode as T (e.g. an
+// interrupted write) are skipped. name describes the log in errors.
+func Read[T any](path, name string, fn func(T)) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLine)
+	for scanner.Scan() {
+		var v T
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			continue
+		}
+		fn(v)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
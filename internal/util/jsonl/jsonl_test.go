@@ -0,0 +1,47 @@
+package jsonl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type record struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "test.jsonl")
+	for _, r := range []record{{"a", 1}, {"b", 2}} {
+		if err := Append(path, "test log", r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An interrupted write leaves a partial line behind
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"id\":\"c\",\"si\n")
+	f.Close()
+	if err := Append(path, "test log", record{"d", 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []record
+	if err := Read(path, "test log", func(r record) { got = append(got, r) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].ID != "a" || got[1].Size != 2 || got[2].ID != "d" {
+		t.Errorf("Read() = %+v, want a, b and d", got)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	called := false
+	err := Read(filepath.Join(t.TempDir(), "missing.jsonl"), "test log", func(record) { called = true })
+	if err != nil || called {
+		t.Errorf("Read() of a missing file = %v (called %v), want no records", err, called)
+	}
+}
@@ -18,6 +18,7 @@ type ScanResult struct {
 	JobName     string // Generated job name with optional project suffix
 	ProjectName string // Source project name (multi-part only)
 	DirNumber   int    // Extracted or sequential directory number
+	ZipFolder   string // ScanZip only: run folder inside the ZIP at Directory
 }
 
 // ScanOpts controls how ScanDirectories scans for run directories.
@@ -46,11 +47,7 @@ func ScanDirectories(opts ScanOpts) ([]ScanResult, error) {
 		return nil, fmt.Errorf("scan pattern is required")
 	}
 
-	type dirEntry struct {
-		path        string
-		projectName string
-	}
-	var dirEntries []dirEntry
+	var dirEntries []runEntry
 
 	isMultiPart := len(opts.PartDirs) > 0
 
@@ -67,7 +64,7 @@ func ScanDirectories(opts ScanOpts) ([]ScanResult, error) {
 		// Validate each run directory
 		for _, run := range allRuns {
 			if ValidateRunDirectory(run.RunPath, opts.ValidationPattern) {
-				dirEntries = append(dirEntries, dirEntry{
+				dirEntries = append(dirEntries, runEntry{
 					path:        run.RunPath,
 					projectName: run.ProjectName,
 				})
@@ -117,7 +114,7 @@ func ScanDirectories(opts ScanOpts) ([]ScanResult, error) {
 				continue
 			}
 
-			dirEntries = append(dirEntries, dirEntry{
+			dirEntries = append(dirEntries, runEntry{
 				path:        match,
 				projectName: "",
 			})
@@ -128,15 +125,28 @@ func ScanDirectories(opts ScanOpts) ([]ScanResult, error) {
 		}
 	}
 
+	return nameRuns(dirEntries, opts, isMultiPart), nil
+}
+
+// runEntry is a run directory found by a scan, before naming.
+type runEntry struct {
+	path        string
+	projectName string
+}
+
+// nameRuns sorts entries by path and gives each a job name built from
+// opts.BaseJobName and the number in its directory name (or its position
+// counted from opts.StartIndex), with the project suffix in multi-part mode.
+func nameRuns(entries []runEntry, opts ScanOpts, isMultiPart bool) []ScanResult {
 	// Sort by path for deterministic output
-	sort.Slice(dirEntries, func(i, j int) bool {
-		return dirEntries[i].path < dirEntries[j].path
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
 	})
 
 	// Generate results with job names
 	numRe := regexp.MustCompile(`\d+`)
 	var results []ScanResult
-	for i, entry := range dirEntries {
+	for i, entry := range entries {
 		dirNum := i + opts.StartIndex
 
 		// Try to extract directory number from name
@@ -163,5 +173,5 @@ func ScanDirectories(opts ScanOpts) ([]ScanResult, error) {
 		})
 	}
 
-	return results, nil
+	return results
 }
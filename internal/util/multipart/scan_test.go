@@ -1,6 +1,7 @@
 package multipart
 
 import (
+	"archive/zip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -202,3 +203,53 @@ func TestScanDirectories_NoMatches(t *testing.T) {
 		t.Fatal("expected error for no matches")
 	}
 }
+
+func TestScanZip(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "study.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{
+		"study/Run_2/output.avg.fnc",
+		"study/Run_10/output.avg.fnc",
+		"study/Run_3/input.sim",
+		"study/.Run_4/output.avg.fnc",
+		"__MACOSX/study/Run_2/._output.avg.fnc",
+	} {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zw.Close()
+	f.Close()
+
+	results, err := ScanZip(zipPath, ScanOpts{
+		RunSubpath:        "study",
+		Pattern:           "Run_*",
+		ValidationPattern: "*.avg.fnc",
+		BaseJobName:       "Job",
+		StartIndex:        1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if results[0].ZipFolder != "study/Run_10" || results[0].JobName != "Job_10" ||
+		results[1].ZipFolder != "study/Run_2" || results[1].JobName != "Job_2" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if !filepath.IsAbs(results[0].Directory) || filepath.Base(results[0].Directory) != "study.zip" {
+		t.Errorf("Directory = %q, want the absolute ZIP path", results[0].Directory)
+	}
+
+	if _, err := ScanZip(zipPath, ScanOpts{RunSubpath: "missing", Pattern: "Run_*", BaseJobName: "Job"}); err == nil {
+		t.Error("expected error for a missing run subpath")
+	}
+	if _, err := ScanZip(zipPath, ScanOpts{Pattern: "Case_*", BaseJobName: "Job"}); err == nil {
+		t.Error("expected error when no folder matches")
+	}
+}
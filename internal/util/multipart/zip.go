@@ -0,0 +1,89 @@
+package multipart
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ScanZip is ScanDirectories for a study delivered as a ZIP archive: it
+// finds the run folders matching opts.Pattern (below opts.RunSubpath) inside
+// the archive at zipPath without extracting it. opts.PartDirs and
+// opts.SingleDir are ignored. Each result's Directory is the absolute ZIP
+// path and ZipFolder the run folder inside it, which is how a job reads its
+// inputs from a ZIP (Directory set to the ZIP, TarSubpath to the folder).
+func ScanZip(zipPath string, opts ScanOpts) ([]ScanResult, error) {
+	if opts.Pattern == "" {
+		return nil, fmt.Errorf("scan pattern is required")
+	}
+	absZip, err := filepath.Abs(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", zipPath, err)
+	}
+	zr, err := zip.OpenReader(absZip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ZIP archive: %w", err)
+	}
+	defer zr.Close()
+
+	scanRoot := "."
+	if opts.RunSubpath != "" {
+		scanRoot = path.Clean(strings.Trim(filepath.ToSlash(opts.RunSubpath), "/"))
+		if info, err := fs.Stat(zr, scanRoot); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("subpath '%s' not found in %s", opts.RunSubpath, zipPath)
+		}
+	}
+
+	matches, err := fs.Glob(zr, path.Join(scanRoot, opts.Pattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", zipPath, err)
+	}
+
+	var entries []runEntry
+	for _, match := range matches {
+		base := path.Base(match)
+		// Skip hidden folders and the resource forks macOS adds to ZIPs
+		if strings.HasPrefix(base, ".") || base == "__MACOSX" {
+			continue
+		}
+		if info, err := fs.Stat(zr, match); err != nil || !info.IsDir() {
+			continue
+		}
+		if !validateZipRun(zr, match, opts.ValidationPattern) {
+			continue
+		}
+		entries = append(entries, runEntry{path: match})
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no folders in %s matched pattern: %s (with validation: %s)", zipPath, opts.Pattern, opts.ValidationPattern)
+	}
+
+	results := nameRuns(entries, opts, false)
+	for i := range results {
+		results[i].ZipFolder = results[i].Directory
+		results[i].Directory = absZip
+	}
+	return results, nil
+}
+
+// validateZipRun is ValidateRunDirectory for a folder inside a ZIP archive.
+func validateZipRun(fsys fs.FS, dir, validationPattern string) bool {
+	if validationPattern == "" {
+		return true
+	}
+	found := false
+	fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if matched, _ := path.Match(validationPattern, path.Base(p)); matched {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,104 @@
+// Package securedelete removes Interlink-created temporary files (staging
+// tars, .encrypted transfer copies, compat staging archives). In secure mode
+// a file's contents are overwritten and flushed before it is unlinked, so the
+// data is not left behind in freed blocks that undelete tools can recover.
+package securedelete
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/rescale/rescale-int/internal/logging"
+)
+
+// Limitations explains when overwriting cannot guarantee erasure. Shown by
+// `config test` and in the GUI next to the setting.
+const Limitations = "Overwrite-before-delete is effective on traditional hard disks with in-place " +
+	"filesystems (ext4, NTFS, HFS+). SSDs and NVMe drives remap writes (wear leveling), and " +
+	"copy-on-write or snapshotting filesystems (APFS, Btrfs, ZFS, Windows Volume Shadow Copy) " +
+	"and network shares keep old blocks, so the original data may survive. On such storage, " +
+	"rely on full-disk encryption (BitLocker, FileVault, LUKS) to protect deleted temp files."
+
+// overwriteChunk is the write size used when overwriting file contents.
+const overwriteChunk = 1024 * 1024
+
+var enabled atomic.Bool
+
+// SetEnabled turns secure mode on or off process-wide. Called from config
+// load (CLI) and from the GUI when the setting changes.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether secure mode is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Remove deletes the named file like os.Remove. In secure mode a regular
+// file is first overwritten with zeros and synced; if that fails (e.g. the
+// file is locked) a warning is logged and the file is still removed.
+func Remove(path string) error {
+	if enabled.Load() {
+		if err := overwrite(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logging.Printf(context.Background(), "[WARN] secure delete could not overwrite %s: %v", path, err)
+		}
+	}
+	return os.Remove(path)
+}
+
+// RemoveAll deletes path and everything beneath it like os.RemoveAll,
+// overwriting each regular file first in secure mode.
+func RemoveAll(path string) error {
+	if enabled.Load() {
+		_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() {
+				if owErr := overwrite(p); owErr != nil {
+					logging.Printf(context.Background(), "[WARN] secure delete could not overwrite %s: %v", p, owErr)
+				}
+			}
+			return nil
+		})
+	}
+	return os.RemoveAll(path)
+}
+
+// overwrite replaces a regular file's contents with zeros in place and
+// flushes them to the device. Symlinks and special files are left alone.
+func overwrite(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() || info.Size() == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	zeros := make([]byte, overwriteChunk)
+	remaining := info.Size()
+	for remaining > 0 {
+		n := int64(len(zeros))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err := f.Write(zeros[:n]); err != nil {
+			f.Close()
+			return err
+		}
+		remaining -= n
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
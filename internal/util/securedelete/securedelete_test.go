@@ -0,0 +1,72 @@
+package securedelete
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverwriteZeroesContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staging.tar.gz")
+	data := bytes.Repeat([]byte("export-controlled"), 200000) // > one overwrite chunk
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := overwrite(path); err != nil {
+		t.Fatalf("overwrite() error = %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(data) {
+		t.Fatalf("size changed: %d, want %d", len(got), len(data))
+	}
+	if !bytes.Equal(got, make([]byte, len(data))) {
+		t.Error("contents not fully zeroed")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	for _, secure := range []bool{false, true} {
+		SetEnabled(secure)
+		path := filepath.Join(t.TempDir(), "file.encrypted")
+		if err := os.WriteFile(path, []byte("ciphertext"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Remove(path); err != nil {
+			t.Errorf("Remove(secure=%v) error = %v", secure, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Remove(secure=%v) left file behind", secure)
+		}
+		if err := Remove(path); !os.IsNotExist(err) {
+			t.Errorf("Remove(missing, secure=%v) = %v, want not-exist", secure, err)
+		}
+	}
+	SetEnabled(false)
+}
+
+func TestRemoveAllSecure(t *testing.T) {
+	SetEnabled(true)
+	defer SetEnabled(false)
+
+	dir := filepath.Join(t.TempDir(), "stage")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"run.sh", "sub/input.zip"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("RemoveAll() left directory behind")
+	}
+}
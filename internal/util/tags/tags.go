@@ -29,3 +29,56 @@ func ParseCommaSeparated(input string) []string {
 	parts := strings.Split(input, ",")
 	return NormalizeTags(parts)
 }
+
+// ParseSemicolonSeparated splits a semicolon-separated string into normalized
+// tags. Used for default_tags, whose tags may themselves contain commas.
+func ParseSemicolonSeparated(input string) []string {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+	return NormalizeTags(strings.Split(input, ";"))
+}
+
+// JoinSemicolonSeparated is the inverse of ParseSemicolonSeparated.
+func JoinSemicolonSeparated(tags []string) string {
+	return strings.Join(tags, ";")
+}
+
+// ExpandPlaceholders substitutes {name} placeholders in each tag with the
+// matching value from vars (e.g. "run-{run_id}" -> "run-pur_123"). Tags that
+// reference a placeholder with no value are dropped rather than applied
+// half-expanded. The result is normalized.
+func ExpandPlaceholders(raw []string, vars map[string]string) []string {
+	var out []string
+	for _, tag := range raw {
+		expanded, ok := expandTag(tag, vars)
+		if ok {
+			out = append(out, expanded)
+		}
+	}
+	return NormalizeTags(out)
+}
+
+func expandTag(tag string, vars map[string]string) (string, bool) {
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(tag, '{')
+		if open < 0 {
+			b.WriteString(tag)
+			return b.String(), true
+		}
+		end := strings.IndexByte(tag[open:], '}')
+		if end < 0 {
+			b.WriteString(tag)
+			return b.String(), true
+		}
+		name := tag[open+1 : open+end]
+		value := vars[name]
+		if value == "" {
+			return "", false
+		}
+		b.WriteString(tag[:open])
+		b.WriteString(value)
+		tag = tag[open+end+1:]
+	}
+}
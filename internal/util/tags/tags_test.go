@@ -52,3 +52,50 @@ func TestParseCommaSeparated(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSemicolonSeparated(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"multiple", " foo ; bar;", []string{"foo", "bar"}},
+		{"comma kept in tag", "team,cfd;run-{run_id}", []string{"team,cfd", "run-{run_id}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSemicolonSeparated(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSemicolonSeparated(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			if back := ParseSemicolonSeparated(JoinSemicolonSeparated(got)); !reflect.DeepEqual(back, tt.want) {
+				t.Errorf("round trip of %v = %v", got, back)
+			}
+		})
+	}
+}
+
+func TestExpandPlaceholders(t *testing.T) {
+	vars := map[string]string{"version": "v4.9.8", "run_id": "pur_42"}
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"no placeholders", []string{"team-a"}, []string{"team-a"}},
+		{"expand", []string{"interlink-{version}", "run-{run_id}"}, []string{"interlink-v4.9.8", "run-pur_42"}},
+		{"multiple in one tag", []string{"{run_id}@{version}"}, []string{"pur_42@v4.9.8"}},
+		{"unknown placeholder dropped", []string{"x-{missing}", "keep"}, []string{"keep"}},
+		{"unterminated brace kept literally", []string{"odd{tag"}, []string{"odd{tag"}},
+		{"dedup after expansion", []string{"{version}", "v4.9.8"}, []string{"v4.9.8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandPlaceholders(tt.input, vars)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandPlaceholders(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
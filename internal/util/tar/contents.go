@@ -0,0 +1,80 @@
+package tar
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Entry is one file in a job's input archive.
+type Entry struct {
+	Name string // Entry name inside the archive, slash-separated
+	Size int64
+}
+
+// PreviewContents lists the files an archive of sourceDir would contain,
+// without creating it. It applies the same filtering and naming as
+// CreateTarGzWithOptions; with no patterns and flatten off, that is also
+// what CreateTarGz produces. Directories are not listed.
+func PreviewContents(sourceDir string, useAbsolutePaths bool, includePatterns, excludePatterns []string, flatten bool) ([]Entry, error) {
+	info, err := os.Stat(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("source directory does not exist: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("source path is not a directory: %s", sourceDir)
+	}
+
+	var entries []Entry
+	err = walkTree(sourceDir, sourceDir, useAbsolutePaths, includePatterns, excludePatterns, flatten, make(map[string]string),
+		func(tarPath, _ string, fileInfo os.FileInfo) error {
+			if fileInfo.Mode().IsRegular() {
+				entries = append(entries, Entry{Name: filepath.ToSlash(tarPath), Size: fileInfo.Size()})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListArchive lists the regular files in an existing archive, gzip-compressed
+// or not.
+func ListArchive(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var entries []Entry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			entries = append(entries, Entry{Name: filepath.ToSlash(hdr.Name), Size: hdr.Size})
+		}
+	}
+}
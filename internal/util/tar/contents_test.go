@@ -0,0 +1,67 @@
+package tar
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func entryNames(entries []Entry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPreviewContents_MatchesArchive(t *testing.T) {
+	run := makeRunDir(t)
+
+	for _, compression := range []string{"gzip", "none"} {
+		archive := filepath.Join(t.TempDir(), "run.tar")
+		if err := CreateTarGzWithOptions(run, archive, false, nil, []string{"*.x"}, false, compression, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		preview, err := PreviewContents(run, false, nil, []string{"*.x"}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed, err := ListArchive(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"Run_1/bc/inlet.dat", "Run_1/input.sim", "Run_1/mesh/a.msh", "Run_1/mesh/b.msh", "Run_1/results/.keep"}
+		if got := entryNames(preview); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: preview = %v, want %v", compression, got, want)
+		}
+		if got := entryNames(listed); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: archive = %v, want %v", compression, got, want)
+		}
+	}
+}
+
+func TestPreviewContents_FlattenAndSizes(t *testing.T) {
+	run := makeRunDir(t)
+
+	entries, err := PreviewContents(run, false, []string{"*.msh"}, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := make(map[string]int64)
+	for _, e := range entries {
+		sizes[e.Name] = e.Size
+	}
+	if want := map[string]int64{"a.msh": 600, "b.msh": 300}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("entries = %v, want %v", sizes, want)
+	}
+}
+
+func TestPreviewContents_MissingDir(t *testing.T) {
+	if _, err := PreviewContents(filepath.Join(t.TempDir(), "nope"), false, nil, nil, false); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
@@ -0,0 +1,96 @@
+package tar
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsArchiveName reports whether name looks like an archive Extract reads:
+// .tar, .tar.gz or .tgz.
+func IsArchiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract unpacks the archive at path, gzip-compressed or not, into destDir
+// and returns the number of files written. Entries that would land outside
+// destDir are rejected; links and special files are skipped. Modification
+// times are kept.
+func Extract(path, destDir string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	root, err := filepath.Abs(destDir)
+	if err != nil {
+		return 0, err
+	}
+	files := 0
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if target != root && !strings.HasPrefix(target, root+string(filepath.Separator)) {
+			return files, fmt.Errorf("archive entry %q escapes the destination directory", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return files, err
+			}
+			if err := extractFile(tr, target, hdr.FileInfo().Mode().Perm()|0600); err != nil {
+				return files, fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+			os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+			files++
+		}
+	}
+}
+
+// extractFile writes the current entry of tr to target.
+func extractFile(tr *tar.Reader, target string, perm os.FileMode) error {
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, tr); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,185 @@
+package tar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// Policies for files another process holds locked while a run directory is
+// archived (tar_locked_files). Only Windows enforces such locks; elsewhere
+// no file is ever reported as locked.
+const (
+	// LockedFail fails the archive on the first locked file. This is the default.
+	LockedFail = "fail"
+
+	// LockedSkip leaves locked files out and records them.
+	LockedSkip = "skip"
+
+	// LockedRetry waits and tries again, failing if the file stays locked.
+	LockedRetry = "retry"
+
+	// LockedSnapshot reads locked files from a Volume Shadow Copy of their
+	// drive. Without the rights to create one, locked files are skipped.
+	LockedSnapshot = "snapshot"
+)
+
+// ErrFileLocked reports a file that another process holds locked.
+var ErrFileLocked = errors.New("file is locked by another process")
+
+// NormalizeLockedPolicy returns policy as one of the Locked* constants,
+// mapping empty and unknown values to LockedFail.
+func NormalizeLockedPolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case LockedSkip:
+		return LockedSkip
+	case LockedRetry:
+		return LockedRetry
+	case LockedSnapshot:
+		return LockedSnapshot
+	default:
+		return LockedFail
+	}
+}
+
+// LockedFiles configures locked-file handling for one job's archives and
+// records the outcome. A nil *LockedFiles fails on the first locked file.
+// Call Close when the job's archives are done to release any snapshots.
+type LockedFiles struct {
+	Policy     string
+	RetryDelay time.Duration   // Wait between attempts under LockedRetry
+	Retries    int             // Attempts after the first under LockedRetry
+	Done       <-chan struct{} // Closed to abandon retry waits
+
+	Skipped      []string // Tar entry names of files left out
+	FromSnapshot []string // Tar entry names of files read from a snapshot
+	SnapshotErr  error    // Why no snapshot could be used, when files were skipped instead
+
+	snapshots map[string]*shadowCopy // By volume
+}
+
+func (l *LockedFiles) policy() string {
+	if l == nil {
+		return LockedFail
+	}
+	return NormalizeLockedPolicy(l.Policy)
+}
+
+// openForArchive opens a regular file for archiving and reads its first
+// bytes, so a lock is found before the tar header is written. It returns
+// the open file (nil when the file is skipped) and a reader for its
+// contents.
+func (l *LockedFiles) openForArchive(filePath, entryName string, size int64) (*os.File, io.Reader, error) {
+	f, r, err := probeOpener(filePath, size)
+	if err == nil || !isLocked(err) {
+		return f, r, err
+	}
+
+	switch l.policy() {
+	case LockedSkip:
+		l.Skipped = append(l.Skipped, entryName)
+		return nil, nil, nil
+
+	case LockedRetry:
+		for attempt := 1; attempt <= l.Retries; attempt++ {
+			select {
+			case <-time.After(l.RetryDelay):
+			case <-l.Done:
+				return nil, nil, fmt.Errorf("%s: %w (cancelled while waiting)", filePath, ErrFileLocked)
+			}
+			f, r, err = probeOpener(filePath, size)
+			if err == nil || !isLocked(err) {
+				return f, r, err
+			}
+		}
+		return nil, nil, fmt.Errorf("%s: %w after %d retries", filePath, ErrFileLocked, l.Retries)
+
+	case LockedSnapshot:
+		shadowPath, serr := l.snapshotPath(filePath)
+		if serr == nil {
+			if f, r, serr = probeOpener(shadowPath, size); serr == nil {
+				l.FromSnapshot = append(l.FromSnapshot, entryName)
+				return f, r, nil
+			}
+		}
+		if l.SnapshotErr == nil {
+			l.SnapshotErr = serr
+		}
+		l.Skipped = append(l.Skipped, entryName)
+		return nil, nil, nil
+
+	default:
+		return nil, nil, fmt.Errorf("%s: %w", filePath, ErrFileLocked)
+	}
+}
+
+// snapshotPath returns where filePath can be read in a shadow copy of its
+// volume, creating the copy on first use.
+func (l *LockedFiles) snapshotPath(filePath string) (string, error) {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+	volume := filepath.VolumeName(abs)
+	if volume == "" || strings.HasPrefix(volume, `\\`) {
+		return "", fmt.Errorf("snapshots need a local drive, not %q", abs)
+	}
+
+	if l.snapshots == nil {
+		l.snapshots = make(map[string]*shadowCopy)
+	}
+	sc, ok := l.snapshots[volume]
+	if !ok {
+		if sc, err = createShadowCopy(volume + `\`); err != nil {
+			return "", err
+		}
+		l.snapshots[volume] = sc
+	}
+	return sc.device + abs[len(volume):], nil
+}
+
+// Close releases the snapshots created for this job.
+func (l *LockedFiles) Close() error {
+	if l == nil {
+		return nil
+	}
+	var errs []error
+	for volume, sc := range l.snapshots {
+		if err := sc.release(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete shadow copy of %s: %w", volume, err))
+		}
+	}
+	l.snapshots = nil
+	return errors.Join(errs...)
+}
+
+// probeOpener is the injection point for probeOpen; tests swap in a fake
+// that reports locks, which only Windows produces.
+var probeOpener = probeOpen
+
+func isLocked(err error) bool {
+	return errors.Is(err, ErrFileLocked) || isLockedError(err)
+}
+
+// probeOpen opens path and reads up to TarLockProbeBytes of it. The returned
+// reader yields the whole file.
+func probeOpen(path string, size int64) (*os.File, io.Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	probe := make([]byte, min(size, constants.TarLockProbeBytes))
+	n, err := io.ReadFull(f, probe)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, io.MultiReader(bytes.NewReader(probe[:n]), f), nil
+}
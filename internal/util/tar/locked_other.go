@@ -0,0 +1,24 @@
+//go:build !windows
+
+package tar
+
+import "errors"
+
+// isLockedError reports whether err is a Windows sharing or lock violation.
+// Other platforms only have advisory locks, which do not block reads.
+func isLockedError(err error) bool {
+	return false
+}
+
+// shadowCopy is a Volume Shadow Copy; they exist only on Windows.
+type shadowCopy struct {
+	device string
+}
+
+func createShadowCopy(volume string) (*shadowCopy, error) {
+	return nil, errors.New("snapshots are only supported on Windows")
+}
+
+func (s *shadowCopy) release() error {
+	return nil
+}
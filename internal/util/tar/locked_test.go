@@ -0,0 +1,139 @@
+package tar
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// lockFiles makes probeOpener report the named base names as locked for the
+// first `times` attempts each (forever when times < 0).
+func lockFiles(t *testing.T, times int, names ...string) {
+	t.Helper()
+	attempts := make(map[string]int)
+	locked := make(map[string]bool)
+	for _, n := range names {
+		locked[n] = true
+	}
+	orig := probeOpener
+	probeOpener = func(path string, size int64) (*os.File, io.Reader, error) {
+		base := filepath.Base(path)
+		if locked[base] && (times < 0 || attempts[base] < times) {
+			attempts[base]++
+			return nil, nil, ErrFileLocked
+		}
+		return orig(path, size)
+	}
+	t.Cleanup(func() { probeOpener = orig })
+}
+
+func TestNormalizeLockedPolicy(t *testing.T) {
+	for in, want := range map[string]string{
+		"":          LockedFail,
+		"fail":      LockedFail,
+		" Skip ":    LockedSkip,
+		"RETRY":     LockedRetry,
+		"snapshot":  LockedSnapshot,
+		"something": LockedFail,
+	} {
+		if got := NormalizeLockedPolicy(in); got != want {
+			t.Errorf("NormalizeLockedPolicy(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLockedFiles_Fail(t *testing.T) {
+	run := makeRunDir(t)
+	lockFiles(t, -1, "b.msh")
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", nil, nil, nil)
+	if !errors.Is(err, ErrFileLocked) {
+		t.Fatalf("err = %v, want ErrFileLocked", err)
+	}
+}
+
+func TestLockedFiles_Skip(t *testing.T) {
+	run := makeRunDir(t)
+	lockFiles(t, -1, "b.msh", "inlet.dat")
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	locked := &LockedFiles{Policy: LockedSkip}
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", locked, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(locked.Skipped)
+	if want := []string{"Run_1/bc/inlet.dat", "Run_1/mesh/b.msh"}; !reflect.DeepEqual(locked.Skipped, want) {
+		t.Errorf("Skipped = %v, want %v", locked.Skipped, want)
+	}
+	listed, err := ListArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Run_1/bc/nested/wall.x", "Run_1/input.sim", "Run_1/mesh/a.msh", "Run_1/results/.keep"}
+	if got := entryNames(listed); !reflect.DeepEqual(got, want) {
+		t.Errorf("archive = %v, want %v", got, want)
+	}
+}
+
+func TestLockedFiles_Retry(t *testing.T) {
+	run := makeRunDir(t)
+	archive := filepath.Join(t.TempDir(), "run.tar")
+
+	// Released on the second retry: archived in full
+	lockFiles(t, 2, "a.msh")
+	locked := &LockedFiles{Policy: LockedRetry, RetryDelay: time.Millisecond, Retries: 3}
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", locked, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	listed, err := ListArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range listed {
+		if e.Name == "Run_1/mesh/a.msh" && e.Size != 600 {
+			t.Errorf("a.msh size = %d, want 600", e.Size)
+		}
+	}
+
+	// Never released: fails once the retries run out
+	lockFiles(t, -1, "a.msh")
+	locked = &LockedFiles{Policy: LockedRetry, RetryDelay: time.Millisecond, Retries: 2}
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", locked, nil, nil); !errors.Is(err, ErrFileLocked) {
+		t.Errorf("err = %v, want ErrFileLocked", err)
+	}
+
+	// Cancelled while waiting
+	done := make(chan struct{})
+	close(done)
+	locked = &LockedFiles{Policy: LockedRetry, RetryDelay: time.Hour, Retries: 1, Done: done}
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", locked, nil, nil); !errors.Is(err, ErrFileLocked) {
+		t.Errorf("err = %v, want ErrFileLocked", err)
+	}
+}
+
+func TestLockedFiles_SnapshotFallsBackToSkip(t *testing.T) {
+	if filepath.VolumeName(os.TempDir()) != "" {
+		t.Skip("shadow copies are attempted on Windows")
+	}
+	run := makeRunDir(t)
+	lockFiles(t, -1, "input.sim")
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	locked := &LockedFiles{Policy: LockedSnapshot}
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", locked, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(locked.Skipped, []string{"Run_1/input.sim"}) || locked.SnapshotErr == nil {
+		t.Errorf("Skipped = %v, SnapshotErr = %v; want input.sim skipped with an error", locked.Skipped, locked.SnapshotErr)
+	}
+	if err := locked.Close(); err != nil {
+		t.Error(err)
+	}
+}
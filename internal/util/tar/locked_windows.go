@@ -0,0 +1,70 @@
+//go:build windows
+
+package tar
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// isLockedError reports whether err is a sharing violation (another process
+// opened the file without read sharing, e.g. Excel) or a lock violation (a
+// byte-range lock, e.g. a solver's restart file).
+func isLockedError(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION)
+}
+
+// shadowCopy is a client-accessible Volume Shadow Copy of one volume.
+// Files are read through its device path.
+type shadowCopy struct {
+	id     string
+	device string // e.g. \\?\GLOBALROOT\Device\HarddiskVolumeShadowCopy3
+}
+
+// createShadowCopy snapshots volume (e.g. `C:\`) through WMI. This needs
+// administrator rights.
+func createShadowCopy(volume string) (*shadowCopy, error) {
+	script := fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+$r = Invoke-CimMethod -ClassName Win32_ShadowCopy -MethodName Create -Arguments @{Volume='%s'; Context='ClientAccessible'}
+if ($r.ReturnValue -ne 0) { throw "Win32_ShadowCopy.Create returned $($r.ReturnValue)" }
+$s = Get-CimInstance Win32_ShadowCopy | Where-Object { $_.ID -eq $r.ShadowID }
+Write-Output $s.ID
+Write-Output $s.DeviceObject`, strings.ReplaceAll(volume, "'", "''"))
+
+	out, err := runPowerShell(script)
+	if err != nil {
+		if strings.Contains(out, "returned 1") || strings.Contains(strings.ToLower(out), "access") {
+			return nil, fmt.Errorf("creating a shadow copy of %s requires administrator rights: %s", volume, out)
+		}
+		return nil, fmt.Errorf("failed to create shadow copy of %s: %w: %s", volume, err, out)
+	}
+	lines := strings.Fields(out)
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `\\?\GLOBALROOT\`) {
+		return nil, fmt.Errorf("unexpected shadow copy output: %q", out)
+	}
+	return &shadowCopy{id: lines[0], device: lines[1]}, nil
+}
+
+// release deletes the shadow copy.
+func (s *shadowCopy) release() error {
+	script := fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+Get-CimInstance Win32_ShadowCopy | Where-Object { $_.ID -eq '%s' } | Remove-CimInstance`, s.id)
+	if out, err := runPowerShell(script); err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+	return nil
+}
+
+func runPowerShell(script string) (string, error) {
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
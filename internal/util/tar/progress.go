@@ -0,0 +1,174 @@
+package tar
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Progress is a snapshot of how far a job's archives have got.
+type Progress struct {
+	Files       int    // Files archived (or skipped) so far
+	TotalFiles  int    // Files to archive
+	Bytes       int64  // Source bytes archived so far
+	TotalBytes  int64  // Source bytes to archive
+	CurrentFile string // Tar entry name of the file being archived
+	Elapsed     time.Duration
+	Done        bool // Set on the last update, after the archives are written
+}
+
+// Fraction returns the share of bytes archived, 0.0-1.0. Directories with
+// only empty files are measured by file count instead.
+func (p Progress) Fraction() float64 {
+	var f float64
+	switch {
+	case p.TotalBytes > 0:
+		f = float64(p.Bytes) / float64(p.TotalBytes)
+	case p.TotalFiles > 0:
+		f = float64(p.Files) / float64(p.TotalFiles)
+	}
+	return min(f, 1)
+}
+
+// Rate returns the average archiving rate in source bytes per second.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Bytes) / p.Elapsed.Seconds()
+}
+
+// ETA estimates the time left at the average rate (0 if unknown).
+func (p Progress) ETA() time.Duration {
+	rate := p.Rate()
+	if rate <= 0 || p.Bytes >= p.TotalBytes {
+		return 0
+	}
+	return time.Duration(float64(p.TotalBytes-p.Bytes) / rate * float64(time.Second))
+}
+
+// ProgressTracker counts the files and bytes written to one job's archives
+// and passes a Progress to OnUpdate at most once per Interval, plus a final
+// one from Finish. A nil *ProgressTracker reports nothing. It is not safe
+// for concurrent use; a job's archives are written one after another.
+type ProgressTracker struct {
+	Interval time.Duration
+	OnUpdate func(Progress)
+
+	progress    Progress
+	start, last time.Time
+
+	// Size of CurrentFile, counted when the next one starts, for archivers
+	// that only report file names (see nextFile)
+	pending    int64
+	hasPending bool
+}
+
+// NewProgressTracker returns a tracker for archiving sourceDir with the
+// given patterns, counting the files and bytes to archive up front.
+func NewProgressTracker(sourceDir string, includePatterns, excludePatterns []string, interval time.Duration, onUpdate func(Progress)) (*ProgressTracker, error) {
+	t := &ProgressTracker{Interval: interval, OnUpdate: onUpdate}
+	err := walkTree(sourceDir, sourceDir, false, includePatterns, excludePatterns, false, nil,
+		func(_, _ string, fileInfo os.FileInfo) error {
+			if fileInfo.Mode().IsRegular() {
+				t.progress.TotalFiles++
+				t.progress.TotalBytes += fileInfo.Size()
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	t.start = time.Now()
+	return t, nil
+}
+
+// startFile records that entryName is being archived.
+func (t *ProgressTracker) startFile(entryName string) {
+	if t == nil {
+		return
+	}
+	t.progress.CurrentFile = entryName
+	t.report(false)
+}
+
+// finishFile counts the current file as archived (or skipped).
+func (t *ProgressTracker) finishFile() {
+	if t == nil {
+		return
+	}
+	t.progress.Files++
+	t.report(false)
+}
+
+// addBytes counts n source bytes as archived.
+func (t *ProgressTracker) addBytes(n int64) {
+	if t == nil {
+		return
+	}
+	t.progress.Bytes += n
+	t.report(false)
+}
+
+// nextFile is for archivers that only report each file's name as they
+// start it (the system tar): it counts the previous file as archived and
+// starts entryName, of the given size.
+func (t *ProgressTracker) nextFile(entryName string, size int64) {
+	if t == nil {
+		return
+	}
+	t.settlePending()
+	t.pending, t.hasPending = size, true
+	t.startFile(entryName)
+}
+
+func (t *ProgressTracker) settlePending() {
+	if t.hasPending {
+		t.progress.Bytes += t.pending
+		t.progress.Files++
+		t.hasPending = false
+	}
+}
+
+// reader wraps r so the bytes read from it are counted.
+func (t *ProgressTracker) reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &countingReader{r: r, t: t}
+}
+
+// Finish counts any file still pending and sends the final update.
+func (t *ProgressTracker) Finish() {
+	if t == nil {
+		return
+	}
+	t.settlePending()
+	t.progress.CurrentFile = ""
+	t.progress.Done = true
+	t.report(true)
+}
+
+func (t *ProgressTracker) report(force bool) {
+	if t.OnUpdate == nil {
+		return
+	}
+	now := time.Now()
+	if !force && now.Sub(t.last) < t.Interval {
+		return
+	}
+	t.last = now
+	t.progress.Elapsed = now.Sub(t.start)
+	t.OnUpdate(t.progress)
+}
+
+type countingReader struct {
+	r io.Reader
+	t *ProgressTracker
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.t.addBytes(int64(n))
+	return n, err
+}
@@ -0,0 +1,86 @@
+package tar
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// recordProgress returns a tracker for run that keeps every update.
+func recordProgress(t *testing.T, run string, exclude []string) (*ProgressTracker, *[]Progress) {
+	t.Helper()
+	var updates []Progress
+	progress, err := NewProgressTracker(run, nil, exclude, 0, func(p Progress) { updates = append(updates, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return progress, &updates
+}
+
+func checkFinalProgress(t *testing.T, updates []Progress, files int, bytes int64) {
+	t.Helper()
+	if len(updates) == 0 {
+		t.Fatal("no progress updates")
+	}
+	last := updates[len(updates)-1]
+	if !last.Done || last.Files != files || last.Bytes != bytes || last.Fraction() != 1 {
+		t.Errorf("final progress = %+v, want done with %d files, %d bytes", last, files, bytes)
+	}
+	var sawFile bool
+	for _, u := range updates {
+		if u.CurrentFile == "Run_1/mesh/a.msh" {
+			sawFile = true
+		}
+		if u.Bytes > u.TotalBytes || u.Files > u.TotalFiles {
+			t.Errorf("progress %+v exceeds its totals", u)
+		}
+	}
+	if !sawFile {
+		t.Error("no update named Run_1/mesh/a.msh as the current file")
+	}
+}
+
+func TestNewProgressTracker_Totals(t *testing.T) {
+	run := makeRunDir(t)
+	progress, _ := recordProgress(t, run, []string{"*.x"})
+	if progress.progress.TotalFiles != 5 || progress.progress.TotalBytes != 1200 {
+		t.Errorf("totals = %d files, %d bytes, want 5 files, 1200 bytes",
+			progress.progress.TotalFiles, progress.progress.TotalBytes)
+	}
+}
+
+func TestProgress_ArchiveWriter(t *testing.T) {
+	run := makeRunDir(t)
+	progress, updates := recordProgress(t, run, nil)
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", nil, nil, progress); err != nil {
+		t.Fatal(err)
+	}
+	progress.Finish()
+	checkFinalProgress(t, *updates, 6, 1250)
+}
+
+func TestProgress_SystemTar(t *testing.T) {
+	if _, err := exec.LookPath("tar"); err != nil {
+		t.Skip("no system tar")
+	}
+	run := makeRunDir(t)
+	progress, updates := recordProgress(t, run, nil)
+
+	archive := filepath.Join(t.TempDir(), "run.tar.gz")
+	if err := CreateTarGz(run, archive, false, "gzip", progress); err != nil {
+		t.Fatal(err)
+	}
+	progress.Finish()
+	checkFinalProgress(t, *updates, 6, 1250)
+
+	// The verbose listing must not end up in the archive
+	entries, err := ListArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Errorf("archive has %d files, want 6", len(entries))
+	}
+}
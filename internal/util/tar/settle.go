@@ -0,0 +1,212 @@
+package tar
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// settlePollInterval is how often a directory is rescanned while waiting for
+// files to settle. Variable so tests can shorten it.
+var settlePollInterval = 2 * time.Second
+
+// SettleOptions controls WaitForSettle.
+type SettleOptions struct {
+	// StableFor is how long every file's size and mtime must stay unchanged
+	// (and no file may be open for writing, where detectable) before the
+	// directory is considered settled.
+	StableFor time.Duration
+
+	// Timeout bounds the total wait. Zero means wait until ctx is cancelled.
+	Timeout time.Duration
+
+	// IncludePatterns and ExcludePatterns select the files that are checked,
+	// as for CreateTarGzWithOptions; files the archive leaves out may keep
+	// changing.
+	IncludePatterns []string
+	ExcludePatterns []string
+
+	// OnWaiting is called once, the first time the directory is found not to
+	// be settled, with the paths that are still changing.
+	OnWaiting func(unsettled []string)
+}
+
+// fileSnapshot is the size/mtime pair used to detect ongoing writes.
+type fileSnapshot struct {
+	size    int64
+	modTime time.Time
+}
+
+// Unsettled scans dir once and returns the files that would go into its
+// archive and are still being written: modified within opts.StableFor or, where
+// detectable, held open for writing. An empty result means WaitForSettle would
+// return at once.
+func Unsettled(dir string, opts SettleOptions) ([]string, error) {
+	if opts.StableFor <= 0 {
+		return nil, nil
+	}
+	cur, err := snapshotDir(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unsettledFiles(dir, nil, cur, opts.StableFor), nil
+}
+
+// WaitForSettle blocks until no file under dir that its archive would contain
+// has changed for opts.StableFor, guarding against archiving inputs that a
+// pre-processor is still writing. A directory whose newest file is already
+// older than StableFor returns after a single scan. Returns an error naming
+// the still-changing files if opts.Timeout elapses first.
+func WaitForSettle(ctx context.Context, dir string, opts SettleOptions) error {
+	if opts.StableFor <= 0 {
+		return nil
+	}
+
+	var deadline time.Time
+	if opts.Timeout > 0 {
+		deadline = time.Now().Add(opts.Timeout)
+	}
+
+	var prev map[string]fileSnapshot
+	notified := false
+	for {
+		cur, err := snapshotDir(dir, opts)
+		if err != nil {
+			return err
+		}
+		unsettled := unsettledFiles(dir, prev, cur, opts.StableFor)
+		if len(unsettled) == 0 {
+			return nil
+		}
+
+		if !notified && opts.OnWaiting != nil {
+			opts.OnWaiting(unsettled)
+		}
+		notified = true
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("files still being written after %v: %s", opts.Timeout, summarizePaths(unsettled))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(settlePollInterval):
+		}
+		prev = cur
+	}
+}
+
+// unsettledFiles returns the files of cur modified within stableFor, changed
+// in size since prev (covers coarse mtime resolution), or held open for
+// writing by another process. Open files are only looked for once size and
+// mtime look stable, since that check walks every process's descriptors.
+func unsettledFiles(dir string, prev, cur map[string]fileSnapshot, stableFor time.Duration) []string {
+	unsettled := recentlyModified(cur, time.Now().Add(-stableFor))
+	if prev != nil {
+		unsettled = mergePaths(unsettled, changedFiles(prev, cur))
+	}
+	if len(unsettled) > 0 {
+		return unsettled
+	}
+	for _, path := range openForWrite(dir) {
+		if _, ok := cur[path]; ok {
+			unsettled = append(unsettled, path)
+		}
+	}
+	return mergePaths(unsettled, nil)
+}
+
+// snapshotDir records size and mtime for every regular file under dir that
+// passes opts' include/exclude patterns. Paths are absolute, to match the
+// descriptor targets openForWrite reports.
+func snapshotDir(dir string, opts SettleOptions) (map[string]fileSnapshot, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	snap := make(map[string]fileSnapshot)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can vanish mid-walk while a writer renames temporaries
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		// dir itself is a ZIP source; patterns apply to its members
+		if path != dir && !shouldIncludeFile(d.Name(), opts.IncludePatterns, opts.ExcludePatterns) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		snap[path] = fileSnapshot{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return snap, nil
+}
+
+// recentlyModified returns the files modified after cutoff.
+func recentlyModified(snap map[string]fileSnapshot, cutoff time.Time) []string {
+	var out []string
+	for path, s := range snap {
+		if s.modTime.After(cutoff) {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// changedFiles returns files whose size differs between two scans or that
+// appeared since the previous scan.
+func changedFiles(prev, cur map[string]fileSnapshot) []string {
+	var out []string
+	for path, c := range cur {
+		if p, ok := prev[path]; !ok || p.size != c.size {
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// mergePaths returns the sorted union of a and b.
+func mergePaths(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var out []string
+	for _, list := range [][]string{a, b} {
+		for _, p := range list {
+			if !seen[p] {
+				seen[p] = true
+				out = append(out, p)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// summarizePaths formats up to five paths for an error message.
+func summarizePaths(paths []string) string {
+	const max = 5
+	if len(paths) <= max {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s (and %d more)", strings.Join(paths[:max], ", "), len(paths)-max)
+}
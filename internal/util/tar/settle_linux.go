@@ -0,0 +1,70 @@
+//go:build linux
+
+package tar
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// openForWrite returns files under dir that another process currently holds
+// open for writing, found by walking /proc/<pid>/fd. Processes we cannot
+// inspect (other users, races with exiting processes) are skipped.
+func openForWrite(dir string) []string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := absDir + string(os.PathSeparator)
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	self := os.Getpid()
+
+	var out []string
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, prefix) {
+				continue
+			}
+			if fdWritable(proc.Name(), fd.Name()) {
+				out = append(out, target)
+			}
+		}
+	}
+	return out
+}
+
+// fdWritable reports whether the descriptor was opened with O_WRONLY or O_RDWR,
+// based on the octal flags line in /proc/<pid>/fdinfo/<fd>.
+func fdWritable(pid, fd string) bool {
+	data, err := os.ReadFile(filepath.Join("/proc", pid, "fdinfo", fd))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "flags:") {
+			continue
+		}
+		flags, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "flags:")), 8, 64)
+		if err != nil {
+			return false
+		}
+		return flags&(uint64(os.O_WRONLY)|uint64(os.O_RDWR)) != 0
+	}
+	return false
+}
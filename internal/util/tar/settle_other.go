@@ -0,0 +1,9 @@
+//go:build !linux
+
+package tar
+
+// openForWrite is not detectable on this platform; quiescence relies on
+// size/mtime stability alone.
+func openForWrite(dir string) []string {
+	return nil
+}
@@ -0,0 +1,124 @@
+package tar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForSettle_OldFilesReturnImmediately(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.dat")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	err := WaitForSettle(context.Background(), dir, SettleOptions{
+		StableFor: time.Minute,
+		Timeout:   time.Second,
+		OnWaiting: func([]string) { called = true },
+	})
+	if err != nil {
+		t.Fatalf("WaitForSettle() error = %v", err)
+	}
+	if called {
+		t.Error("OnWaiting called for an already-settled directory")
+	}
+}
+
+func TestWaitForSettle_WaitsForRecentWrites(t *testing.T) {
+	orig := settlePollInterval
+	settlePollInterval = 10 * time.Millisecond
+	defer func() { settlePollInterval = orig }()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "input.dat"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var waiting []string
+	start := time.Now()
+	err := WaitForSettle(context.Background(), dir, SettleOptions{
+		StableFor: 100 * time.Millisecond,
+		Timeout:   5 * time.Second,
+		OnWaiting: func(u []string) { waiting = u },
+	})
+	if err != nil {
+		t.Fatalf("WaitForSettle() error = %v", err)
+	}
+	if len(waiting) != 1 || filepath.Base(waiting[0]) != "input.dat" {
+		t.Errorf("OnWaiting got %v, want [input.dat]", waiting)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("returned after %v, expected to wait for the stability window", elapsed)
+	}
+}
+
+func TestWaitForSettle_Timeout(t *testing.T) {
+	orig := settlePollInterval
+	settlePollInterval = 10 * time.Millisecond
+	defer func() { settlePollInterval = orig }()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "growing.dat")
+	// A file modified "in the future" never leaves the stability window.
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WaitForSettle(context.Background(), dir, SettleOptions{
+		StableFor: time.Second,
+		Timeout:   50 * time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), "growing.dat") {
+		t.Fatalf("WaitForSettle() error = %v, want timeout naming growing.dat", err)
+	}
+}
+
+func TestWaitForSettle_DisabledWhenZero(t *testing.T) {
+	if err := WaitForSettle(context.Background(), "/does/not/exist", SettleOptions{}); err != nil {
+		t.Errorf("WaitForSettle() with zero StableFor error = %v", err)
+	}
+}
+
+func TestUnsettled_IgnoresFilesLeftOutOfArchive(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"input.dat", "solver.log"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if name == "input.dat" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	opts := SettleOptions{StableFor: time.Minute}
+	unsettled, err := Unsettled(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsettled) != 1 || filepath.Base(unsettled[0]) != "solver.log" {
+		t.Errorf("Unsettled() = %v, want [solver.log]", unsettled)
+	}
+
+	opts.ExcludePatterns = []string{"*.log"}
+	if unsettled, err = Unsettled(dir, opts); err != nil || len(unsettled) != 0 {
+		t.Errorf("Unsettled() with *.log excluded = %v, %v; want none", unsettled, err)
+	}
+}
@@ -0,0 +1,211 @@
+package tar
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+)
+
+// Split modes for archiving one run directory as several tars.
+const (
+	// SplitNone archives the directory as a single tar. This is the default.
+	SplitNone = "none"
+
+	// SplitSubdirs archives each top-level subdirectory separately; loose
+	// top-level files share one more tar.
+	SplitSubdirs = "subdirs"
+
+	// SplitSize spreads the top-level entries over N tars of similar size.
+	SplitSize = "size"
+)
+
+// NormalizeSplitMode returns mode as one of the Split* constants, mapping
+// empty and unknown values to SplitNone.
+func NormalizeSplitMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case SplitSubdirs:
+		return SplitSubdirs
+	case SplitSize:
+		return SplitSize
+	default:
+		return SplitNone
+	}
+}
+
+// Part is one tar of a split run directory.
+type Part struct {
+	Members []string // Top-level entry names, relative to the run directory
+	Size    int64    // Total size of the members' regular files
+}
+
+// PlanSplit divides the top-level entries of sourceDir into parts according
+// to mode. parts is the number of tars for SplitSize. A nil result means the
+// directory should be archived as a single tar: the mode is SplitNone or
+// there is nothing to split (fewer than two parts would result).
+func PlanSplit(sourceDir, mode string, parts int) ([]Part, error) {
+	mode = NormalizeSplitMode(mode)
+	if mode == SplitNone {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourceDir, err)
+	}
+
+	var plan []Part
+	switch mode {
+	case SplitSubdirs:
+		var loose Part
+		for _, e := range entries {
+			size, err := treeSize(filepath.Join(sourceDir, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if e.IsDir() {
+				plan = append(plan, Part{Members: []string{e.Name()}, Size: size})
+			} else {
+				loose.Members = append(loose.Members, e.Name())
+				loose.Size += size
+			}
+		}
+		if len(loose.Members) > 0 {
+			plan = append(plan, loose)
+		}
+
+	case SplitSize:
+		if parts < 2 {
+			return nil, nil
+		}
+		type sized struct {
+			name string
+			size int64
+		}
+		items := make([]sized, 0, len(entries))
+		for _, e := range entries {
+			size, err := treeSize(filepath.Join(sourceDir, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, sized{e.Name(), size})
+		}
+		// Largest first into the currently smallest part
+		sort.SliceStable(items, func(i, j int) bool { return items[i].size > items[j].size })
+		plan = make([]Part, min(parts, len(items)))
+		for _, it := range items {
+			smallest := 0
+			for i := range plan {
+				if plan[i].Size < plan[smallest].Size {
+					smallest = i
+				}
+			}
+			plan[smallest].Members = append(plan[smallest].Members, it.name)
+			plan[smallest].Size += it.size
+		}
+		for i := range plan {
+			sort.Strings(plan[i].Members)
+		}
+	}
+
+	if len(plan) < 2 {
+		return nil, nil
+	}
+	return plan, nil
+}
+
+// treeSize returns the total size of the regular files at or below path.
+func treeSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to size %s: %w", path, err)
+	}
+	return total, nil
+}
+
+// CreateTarPart archives the given top-level members of sourceDir. Entry
+// names are the same as in a full archive of sourceDir (see
+// CreateTarGzWithOptions), so extracting every part of a split directory in
+// one place reproduces its layout.
+func CreateTarPart(sourceDir, outputPath string, members []string, useAbsolutePaths bool, includePatterns, excludePatterns []string, compression string, locked *LockedFiles, text *TextNormalizer, progress *ProgressTracker) error {
+	info, err := os.Stat(sourceDir)
+	if err != nil {
+		return fmt.Errorf("source directory does not exist: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("source path is not a directory: %s", sourceDir)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create tar file: %w", err)
+	}
+	defer outFile.Close()
+
+	var tarWriter *tar.Writer
+	if compression == "none" {
+		tarWriter = tar.NewWriter(outFile)
+	} else {
+		gzWriter := gzip.NewWriter(outFile)
+		defer gzWriter.Close()
+		tarWriter = tar.NewWriter(gzWriter)
+	}
+	defer tarWriter.Close()
+
+	for _, member := range members {
+		root := filepath.Join(sourceDir, member)
+		if err := addTree(tarWriter, sourceDir, root, useAbsolutePaths, includePatterns, excludePatterns, false, nil, locked, text, progress); err != nil {
+			securedelete.Remove(outputPath) // Clean up partial file
+			return fmt.Errorf("failed to create tar: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GeneratePartTarPath generates the path for part (0-based) of a split
+// directory's archives. Like GenerateTarPath, the name ends in an FNV hash
+// suffix; the hash also covers the part number.
+// Example: "Testing_Run_6_part02_a1b2c3d4.tar.gz"
+func GeneratePartTarPath(directory, basePath, compression string, part int) string {
+	absDir, err := filepath.Abs(directory)
+	if err != nil {
+		absDir = filepath.Clean(directory)
+	}
+
+	ext := ".tar.gz"
+	if compression == "none" {
+		ext = ".tar"
+	}
+	name := strings.TrimSuffix(filepath.Base(GenerateTarPath(absDir, basePath, compression)), ext)
+	prefix := name[:strings.LastIndex(name, "_")] // Drop the directory's own hash
+
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%s#%d", absDir, part)))
+
+	return filepath.Join(basePath, fmt.Sprintf("%s_part%02d_%08x%s", prefix, part+1, h.Sum32(), ext))
+}
@@ -0,0 +1,164 @@
+package tar
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/pathutil"
+)
+
+// makeRunDir creates Run_1 with three subdirectories of different sizes and
+// one loose file.
+func makeRunDir(t *testing.T) string {
+	t.Helper()
+	run := filepath.Join(t.TempDir(), "Run_1")
+	files := map[string]int{
+		"mesh/a.msh":       600,
+		"mesh/b.msh":       300,
+		"bc/inlet.dat":     200,
+		"results/.keep":    0,
+		"input.sim":        100,
+		"bc/nested/wall.x": 50,
+	}
+	for name, size := range files {
+		path := filepath.Join(run, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return run
+}
+
+func TestPlanSplit_Subdirs(t *testing.T) {
+	run := makeRunDir(t)
+
+	parts, err := PlanSplit(run, "subdirs", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, p := range parts {
+		got = append(got, p.Members)
+	}
+	want := [][]string{{"bc"}, {"mesh"}, {"results"}, {"input.sim"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parts = %v, want %v", got, want)
+	}
+	if parts[0].Size != 250 || parts[1].Size != 900 {
+		t.Errorf("sizes = %d, %d, want 250, 900", parts[0].Size, parts[1].Size)
+	}
+}
+
+func TestPlanSplit_Size(t *testing.T) {
+	run := makeRunDir(t)
+
+	parts, err := PlanSplit(run, "size", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("len(parts) = %d, want 2", len(parts))
+	}
+	// mesh (900) alone; bc (250), input.sim (100) and results (0) together
+	if !reflect.DeepEqual(parts[0].Members, []string{"mesh"}) ||
+		!reflect.DeepEqual(parts[1].Members, []string{"bc", "input.sim", "results"}) {
+		t.Errorf("parts = %+v", parts)
+	}
+}
+
+func TestPlanSplit_NothingToSplit(t *testing.T) {
+	run := filepath.Join(t.TempDir(), "Run_1")
+	if err := os.MkdirAll(filepath.Join(run, "only"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		mode  string
+		parts int
+	}{{"none", 4}, {"", 4}, {"bogus", 4}, {"subdirs", 0}, {"size", 4}, {"size", 1}} {
+		parts, err := PlanSplit(run, tt.mode, tt.parts)
+		if err != nil || parts != nil {
+			t.Errorf("PlanSplit(%q, %d) = %v, %v; want nil", tt.mode, tt.parts, parts, err)
+		}
+	}
+}
+
+func TestCreateTarPart_ReassemblesLayout(t *testing.T) {
+	run := makeRunDir(t)
+	out := t.TempDir()
+
+	parts, err := PlanSplit(run, "subdirs", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for i, part := range parts {
+		path := GeneratePartTarPath(run, out, "none", i)
+		if err := CreateTarPart(run, path, part.Members, false, nil, []string{"*.x"}, "none", nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tarEntries(t, path)...)
+	}
+	sort.Strings(names)
+
+	want := []string{
+		"Run_1/bc", "Run_1/bc/inlet.dat", "Run_1/bc/nested",
+		"Run_1/input.sim",
+		"Run_1/mesh", "Run_1/mesh/a.msh", "Run_1/mesh/b.msh",
+		"Run_1/results", "Run_1/results/.keep",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("entries = %v, want %v", names, want)
+	}
+}
+
+func TestGeneratePartTarPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Study.v2", "Run_6")
+	a := GeneratePartTarPath(dir, "/tmp", "gzip", 0)
+	b := GeneratePartTarPath(dir, "/tmp", "gzip", 1)
+
+	if a == b {
+		t.Fatalf("parts share a path: %s", a)
+	}
+	if !strings.HasPrefix(filepath.Base(a), "Study.v2_Run_6_part01_") || !strings.HasSuffix(a, ".tar.gz") {
+		t.Errorf("unexpected name %s", a)
+	}
+	if none := GeneratePartTarPath(dir, "/tmp", "none", 0); strings.TrimSuffix(none, ".tar") != strings.TrimSuffix(a, ".tar.gz") {
+		t.Errorf("uncompressed part %s does not match %s", none, a)
+	}
+	if !pathutil.HasFNVSuffix(a) {
+		t.Errorf("%s lacks the FNV suffix safeRemoveTar requires", a)
+	}
+}
+
+func tarEntries(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var names []string
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, filepath.ToSlash(hdr.Name))
+	}
+}
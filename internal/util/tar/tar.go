@@ -2,6 +2,8 @@ package tar
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"compress/gzip"
 	"fmt"
 	"hash/fnv"
@@ -9,12 +11,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/util/securedelete"
 )
 
 // CreateTarGz creates a tar archive of a directory using system tar command
 // This matches the Python PUR behavior of using subprocess tar
 // Supports both compressed (gzip) and uncompressed archives via the compression parameter
-func CreateTarGz(sourceDir, outputPath string, useAbsolutePaths bool, compression string) error {
+// progress, if non-nil, is updated from tar's verbose listing, one file at a time
+func CreateTarGz(sourceDir, outputPath string, useAbsolutePaths bool, compression string, progress *ProgressTracker) error {
 	// Validate source directory exists
 	info, err := os.Stat(sourceDir)
 	if err != nil {
@@ -40,8 +46,12 @@ func CreateTarGz(sourceDir, outputPath string, useAbsolutePaths bool, compressio
 	} else {
 		tarFlags = "-czf" // Create with gzip compression (default)
 	}
+	if progress != nil {
+		tarFlags = "-cv" + tarFlags[2:] // List entries as they are archived
+	}
 
 	var args []string
+	var entryRoot string // Directory the listed entry names are relative to
 	if useAbsolutePaths {
 		// For multi-part mode: use absolute paths
 		args = []string{tarFlags, outputPath, "-P", sourceDir}
@@ -50,11 +60,17 @@ func CreateTarGz(sourceDir, outputPath string, useAbsolutePaths bool, compressio
 		parent := filepath.Dir(sourceDir)
 		dirname := filepath.Base(sourceDir)
 		args = []string{tarFlags, outputPath, "-C", parent, dirname}
+		entryRoot = parent
 	}
 
 	// Execute tar command
 	cmd := exec.Command("tar", args...)
-	output, err := cmd.CombinedOutput()
+	var output []byte
+	if progress != nil {
+		output, err = runWithListing(cmd, entryRoot, progress)
+	} else {
+		output, err = cmd.CombinedOutput()
+	}
 	if err != nil {
 		return fmt.Errorf("tar command failed: %w: %s", err, string(output))
 	}
@@ -67,10 +83,68 @@ func CreateTarGz(sourceDir, outputPath string, useAbsolutePaths bool, compressio
 	return nil
 }
 
+// runWithListing runs a verbose tar command, passing each regular file it
+// lists to progress, and returns the rest of its output (error messages).
+// GNU tar lists entries on stdout, bsdtar (macOS, Windows) on stderr.
+func runWithListing(cmd *exec.Cmd, entryRoot string, progress *ProgressTracker) ([]byte, error) {
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var other bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, info, ok := listedEntry(entryRoot, line); ok {
+				if info.Mode().IsRegular() {
+					progress.nextFile(name, info.Size())
+				}
+				continue
+			}
+			other.WriteString(line)
+			other.WriteByte('\n')
+		}
+		io.Copy(io.Discard, pr) // Drain after an overlong line so tar never blocks
+	}()
+
+	err := cmd.Wait()
+	pw.Close()
+	<-done
+	return other.Bytes(), err
+}
+
+// listedEntry resolves a line of verbose tar output to the entry it names,
+// relative to entryRoot (or absolute if entryRoot is empty). bsdtar prefixes
+// entries with "a ".
+func listedEntry(entryRoot, line string) (string, os.FileInfo, bool) {
+	for _, name := range []string{line, strings.TrimPrefix(line, "a ")} {
+		if name == "" {
+			continue
+		}
+		path := name
+		if entryRoot != "" {
+			path = filepath.Join(entryRoot, name)
+		}
+		if info, err := os.Lstat(path); err == nil {
+			return filepath.ToSlash(strings.TrimSuffix(name, "/")), info, true
+		}
+	}
+	return "", nil, false
+}
+
 // CreateTarGzWithOptions creates a tar archive with filtering and flattening options
 // This uses Go's archive/tar package for fine-grained control
 // Supports both compressed (gzip) and uncompressed archives via the compression parameter
-func CreateTarGzWithOptions(sourceDir, outputPath string, useAbsolutePaths bool, includePatterns, excludePatterns []string, flatten bool, compression string) error {
+// locked decides what happens to files another process holds locked (nil = fail)
+// text, if non-nil, checks or corrects line endings and BOMs of text files
+// progress, if non-nil, is updated as files are written
+func CreateTarGzWithOptions(sourceDir, outputPath string, useAbsolutePaths bool, includePatterns, excludePatterns []string, flatten bool, compression string, locked *LockedFiles, text *TextNormalizer, progress *ProgressTracker) error {
 	// Validate source directory exists
 	info, err := os.Stat(sourceDir)
 	if err != nil {
@@ -109,10 +183,89 @@ func CreateTarGzWithOptions(sourceDir, outputPath string, useAbsolutePaths bool,
 	// Track filenames in flatten mode to detect duplicates
 	fileNames := make(map[string]string) // filename -> original_path
 
-	// Walk the source directory
+	err = addTree(tarWriter, sourceDir, sourceDir, useAbsolutePaths, includePatterns, excludePatterns, flatten, fileNames, locked, text, progress)
+	if err != nil {
+		securedelete.Remove(outputPath) // Clean up partial file
+		return fmt.Errorf("failed to create tar: %w", err)
+	}
+
+	return nil
+}
+
+// addTree writes root and everything below it to tarWriter. Entry names are
+// relative to the parent of sourceDir (so they start with its base name),
+// absolute in useAbsolutePaths mode, or bare file names in flatten mode.
+// sourceDir itself is not written. Locked files are handled per locked, text
+// files are checked per text, and files and bytes written are counted in
+// progress.
+func addTree(tarWriter *tar.Writer, sourceDir, root string, useAbsolutePaths bool, includePatterns, excludePatterns []string, flatten bool, fileNames map[string]string, locked *LockedFiles, text *TextNormalizer, progress *ProgressTracker) error {
+	return walkTree(sourceDir, root, useAbsolutePaths, includePatterns, excludePatterns, flatten, fileNames,
+		func(tarPath, filePath string, fileInfo os.FileInfo) error {
+			// Open regular files before writing the header, so a locked
+			// file can still be skipped
+			var contents io.Reader
+			size := fileInfo.Size()
+			if fileInfo.Mode().IsRegular() {
+				progress.startFile(filepath.ToSlash(tarPath))
+				file, r, err := locked.openForArchive(filePath, filepath.ToSlash(tarPath), fileInfo.Size())
+				if err != nil {
+					return fmt.Errorf("failed to open file: %w", err)
+				}
+				if file == nil {
+					// Skipped: locked. Count it so progress still reaches the total.
+					progress.addBytes(fileInfo.Size())
+					progress.finishFile()
+					return nil
+				}
+				defer file.Close()
+				contents = progress.reader(r)
+				// A snapshot copy may differ in size from the live file
+				if st, err := file.Stat(); err == nil {
+					size = st.Size()
+				}
+				if contents, size, err = text.normalize(filepath.ToSlash(tarPath), contents, size); err != nil {
+					return fmt.Errorf("failed to read %s: %w", filePath, err)
+				}
+			}
+
+			// Create tar header
+			header, err := tar.FileInfoHeader(fileInfo, "")
+			if err != nil {
+				return fmt.Errorf("failed to create tar header: %w", err)
+			}
+
+			// Set the header name
+			header.Name = tarPath
+			if contents != nil {
+				header.Size = size
+			}
+
+			// Write header
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return fmt.Errorf("failed to write tar header: %w", err)
+			}
+
+			// Write file contents if it's a regular file
+			if contents != nil {
+				if _, err := io.Copy(tarWriter, contents); err != nil {
+					if isLocked(err) {
+						err = fmt.Errorf("%s: %w while archiving", filePath, ErrFileLocked)
+					}
+					return fmt.Errorf("failed to write file contents: %w", err)
+				}
+				progress.finishFile()
+			}
+
+			return nil
+		})
+}
+
+// walkTree calls fn with the tar entry name of root and everything below it
+// that addTree would archive, applying the same filtering and naming rules.
+func walkTree(sourceDir, root string, useAbsolutePaths bool, includePatterns, excludePatterns []string, flatten bool, fileNames map[string]string, fn func(tarPath, filePath string, fileInfo os.FileInfo) error) error {
 	dirName := filepath.Base(sourceDir)
 
-	err = filepath.Walk(sourceDir, func(filePath string, fileInfo os.FileInfo, err error) error {
+	return filepath.Walk(root, func(filePath string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -159,42 +312,8 @@ func CreateTarGzWithOptions(sourceDir, outputPath string, useAbsolutePaths bool,
 			tarPath = filepath.Join(dirName, relPath)
 		}
 
-		// Create tar header
-		header, err := tar.FileInfoHeader(fileInfo, "")
-		if err != nil {
-			return fmt.Errorf("failed to create tar header: %w", err)
-		}
-
-		// Set the header name
-		header.Name = tarPath
-
-		// Write header
-		if err := tarWriter.WriteHeader(header); err != nil {
-			return fmt.Errorf("failed to write tar header: %w", err)
-		}
-
-		// Write file contents if it's a regular file
-		if fileInfo.Mode().IsRegular() {
-			file, err := os.Open(filePath)
-			if err != nil {
-				return fmt.Errorf("failed to open file: %w", err)
-			}
-			defer file.Close()
-
-			if _, err := io.Copy(tarWriter, file); err != nil {
-				return fmt.Errorf("failed to write file contents: %w", err)
-			}
-		}
-
-		return nil
+		return fn(tarPath, filePath, fileInfo)
 	})
-
-	if err != nil {
-		os.Remove(outputPath) // Clean up partial file
-		return fmt.Errorf("failed to create tar: %w", err)
-	}
-
-	return nil
 }
 
 // shouldIncludeFile determines if a file should be included based on patterns
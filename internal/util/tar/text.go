@@ -0,0 +1,140 @@
+package tar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// Modes for checking the line endings and byte order mark of text inputs
+// as they are archived (text_normalize). Windows CRLF line endings and
+// UTF-8 BOMs break some solvers' input deck parsers.
+const (
+	// TextNormalizeOff archives every file as it is. This is the default.
+	TextNormalizeOff = "off"
+
+	// TextNormalizeCheck reports text files with CRLF line endings or a BOM
+	// but archives them as they are.
+	TextNormalizeCheck = "check"
+
+	// TextNormalizeFix archives such files with LF line endings and without
+	// the BOM. The files on disk are not changed.
+	TextNormalizeFix = "fix"
+)
+
+// utf8BOM is the UTF-8 encoding of U+FEFF.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// NormalizeTextMode returns mode as one of the TextNormalize* constants,
+// mapping empty and unknown values to TextNormalizeOff.
+func NormalizeTextMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case TextNormalizeCheck:
+		return TextNormalizeCheck
+	case TextNormalizeFix:
+		return TextNormalizeFix
+	default:
+		return TextNormalizeOff
+	}
+}
+
+// TextNormalizer configures text input normalization for one job's archives
+// and records the files it found. Only regular files whose extension is in
+// Extensions are checked, and only if they look like text (no NUL bytes)
+// and are at most constants.MaxTextNormalizeBytes. A nil *TextNormalizer
+// archives every file as it is.
+type TextNormalizer struct {
+	Mode       string
+	Extensions []string // With the dot, e.g. ".inp"; matched case-insensitively
+
+	Changed []string // Tar entry names of files with CRLF line endings or a BOM, with what was found
+}
+
+// NewTextNormalizer returns a normalizer for mode and extensions (entries
+// with or without the leading dot), or nil if mode is off or no extension
+// is given.
+func NewTextNormalizer(mode string, extensions []string) *TextNormalizer {
+	mode = NormalizeTextMode(mode)
+	if mode == TextNormalizeOff {
+		return nil
+	}
+	n := &TextNormalizer{Mode: mode}
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		n.Extensions = append(n.Extensions, ext)
+	}
+	if len(n.Extensions) == 0 {
+		return nil
+	}
+	return n
+}
+
+// Fixes reports whether files are corrected rather than only reported.
+func (n *TextNormalizer) Fixes() bool {
+	return n != nil && n.Mode == TextNormalizeFix
+}
+
+// applies reports whether the file entryName of size bytes is checked.
+func (n *TextNormalizer) applies(entryName string, size int64) bool {
+	if n == nil || size > constants.MaxTextNormalizeBytes {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(entryName))
+	for _, e := range n.Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns the contents to archive for entryName and their size,
+// given the file's contents r and size. Checked files are read into memory;
+// others are passed through.
+func (n *TextNormalizer) normalize(entryName string, r io.Reader, size int64) (io.Reader, int64, error) {
+	if !n.applies(entryName, size) {
+		return r, size, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	fixed, found := NormalizeText(data)
+	if found != "" {
+		n.Changed = append(n.Changed, fmt.Sprintf("%s (%s)", entryName, found))
+		if n.Fixes() {
+			data = fixed
+		}
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// NormalizeText returns data without a leading UTF-8 BOM and with CRLF line
+// endings turned into LF, and what was found ("CRLF", "BOM" or "BOM, CRLF";
+// "" if data was already clean, in which case it is returned unchanged).
+// Data containing NUL bytes is taken to be binary and never changed.
+func NormalizeText(data []byte) ([]byte, string) {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return data, ""
+	}
+	var found []string
+	if bytes.HasPrefix(data, utf8BOM) {
+		data = data[len(utf8BOM):]
+		found = append(found, "BOM")
+	}
+	if bytes.Contains(data, []byte("\r\n")) {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		found = append(found, "CRLF")
+	}
+	return data, strings.Join(found, ", ")
+}
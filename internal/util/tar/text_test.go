@@ -0,0 +1,140 @@
+package tar
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tarFiles returns the contents of the regular files in the uncompressed
+// tar at path, by entry name.
+func tarFiles(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[filepath.ToSlash(hdr.Name)] = string(data)
+		}
+	}
+}
+
+func makeTextRun(t *testing.T) string {
+	t.Helper()
+	run := filepath.Join(t.TempDir(), "Run_1")
+	files := map[string]string{
+		"deck.inp":  "\xEF\xBB\xBF*NODE\r\n1, 0.0\r\n",
+		"clean.inp": "*NODE\n1, 0.0\n",
+		"run.SH":    "#!/bin/sh\r\nsolve\r\n",
+		"data.bin":  "a\r\nb",
+		"mesh.inp":  "bin\x00ary\r\n",
+	}
+	if err := os.MkdirAll(run, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(run, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return run
+}
+
+func TestNormalizeText(t *testing.T) {
+	for in, want := range map[string][2]string{
+		"a\nb\n":               {"a\nb\n", ""},
+		"a\r\nb\r\n":           {"a\nb\n", "CRLF"},
+		"\xEF\xBB\xBFa\n":      {"a\n", "BOM"},
+		"\xEF\xBB\xBFa\r\nb":   {"a\nb", "BOM, CRLF"},
+		"a\rb":                 {"a\rb", ""},
+		"\x00\xEF\xBB\xBF\r\n": {"\x00\xEF\xBB\xBF\r\n", ""},
+	} {
+		got, found := NormalizeText([]byte(in))
+		if string(got) != want[0] || found != want[1] {
+			t.Errorf("NormalizeText(%q) = %q, %q; want %q, %q", in, got, found, want[0], want[1])
+		}
+	}
+}
+
+func TestNewTextNormalizer(t *testing.T) {
+	if n := NewTextNormalizer("off", []string{".inp"}); n != nil {
+		t.Errorf("off: got %+v", n)
+	}
+	if n := NewTextNormalizer("fix", []string{" ", ""}); n != nil {
+		t.Errorf("no extensions: got %+v", n)
+	}
+	n := NewTextNormalizer(" Check ", []string{"INP", ".sh"})
+	if n == nil || n.Mode != TextNormalizeCheck || !reflect.DeepEqual(n.Extensions, []string{".inp", ".sh"}) {
+		t.Errorf("got %+v", n)
+	}
+}
+
+func TestTextNormalizer_Fix(t *testing.T) {
+	run := makeTextRun(t)
+	text := NewTextNormalizer(TextNormalizeFix, []string{".inp", ".sh"})
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	if err := CreateTarGzWithOptions(run, archive, false, nil, nil, false, "none", nil, text, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	files := tarFiles(t, archive)
+	for name, want := range map[string]string{
+		"Run_1/deck.inp":  "*NODE\n1, 0.0\n",
+		"Run_1/clean.inp": "*NODE\n1, 0.0\n",
+		"Run_1/run.SH":    "#!/bin/sh\nsolve\n",
+		"Run_1/data.bin":  "a\r\nb",
+		"Run_1/mesh.inp":  "bin\x00ary\r\n",
+	} {
+		if files[name] != want {
+			t.Errorf("%s = %q, want %q", name, files[name], want)
+		}
+	}
+	want := []string{"Run_1/deck.inp (BOM, CRLF)", "Run_1/run.SH (CRLF)"}
+	if !reflect.DeepEqual(text.Changed, want) {
+		t.Errorf("Changed = %v, want %v", text.Changed, want)
+	}
+
+	// The files on disk are left alone
+	if data, _ := os.ReadFile(filepath.Join(run, "run.SH")); string(data) != "#!/bin/sh\r\nsolve\r\n" {
+		t.Errorf("run.SH on disk = %q", data)
+	}
+}
+
+func TestTextNormalizer_Check(t *testing.T) {
+	run := makeTextRun(t)
+	text := NewTextNormalizer(TextNormalizeCheck, []string{".inp"})
+
+	archive := filepath.Join(t.TempDir(), "run.tar")
+	if err := CreateTarPart(run, archive, []string{"deck.inp", "run.SH"}, false, nil, nil, "none", nil, text, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	files := tarFiles(t, archive)
+	if files["Run_1/deck.inp"] != "\xEF\xBB\xBF*NODE\r\n1, 0.0\r\n" {
+		t.Errorf("deck.inp = %q, want it unchanged", files["Run_1/deck.inp"])
+	}
+	if !reflect.DeepEqual(text.Changed, []string{"Run_1/deck.inp (BOM, CRLF)"}) {
+		t.Errorf("Changed = %v", text.Changed)
+	}
+}
@@ -0,0 +1,297 @@
+package tar
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+)
+
+// IsZipSource reports whether a job directory is really a ZIP archive, in
+// which case its run folders are read from inside the archive instead of
+// from disk (see CreateTarGzFromZip).
+func IsZipSource(dir string) bool {
+	if !strings.EqualFold(filepath.Ext(dir), ".zip") {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// CleanZipSubtree normalizes the name of a folder inside a ZIP archive
+// ("Run_1", "study/Run_1/") and rejects names that would leave the archive.
+func CleanZipSubtree(subtree string) (string, error) {
+	s := strings.Trim(zipSlash(subtree), "/")
+	if s == "" {
+		return "", fmt.Errorf("no folder inside the ZIP archive given")
+	}
+	if s = path.Clean(s); s == "." || s == ".." || strings.HasPrefix(s, "../") {
+		return "", fmt.Errorf("folder '%s' is outside the ZIP archive", subtree)
+	}
+	return s, nil
+}
+
+// zipSlash converts the backslashes some Windows tools write into ZIP entry
+// names to slashes.
+func zipSlash(name string) string {
+	return strings.ReplaceAll(name, `\`, "/")
+}
+
+// walkZip calls fn, in name order, for every entry of zr below subtree that
+// an archive of it would contain, with the tar entry name: relative to the
+// parent of subtree (so starting with its base name), or the bare file name
+// in flatten mode. Entries whose names would escape the archive and
+// symlinks are skipped.
+func walkZip(zr *zip.Reader, subtree string, includePatterns, excludePatterns []string, flatten bool, fn func(tarPath string, f *zip.File) error) error {
+	files := make([]*zip.File, len(zr.File))
+	copy(files, zr.File)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	dirName := path.Base(subtree)
+	fileNames := make(map[string]string) // Flatten mode: file name -> entry
+	found := false
+	for _, f := range files {
+		name := strings.TrimSuffix(zipSlash(f.Name), "/")
+		rel, ok := strings.CutPrefix(name, subtree+"/")
+		if !ok {
+			if name == subtree {
+				found = true
+			}
+			continue
+		}
+		found = true
+		if strings.HasPrefix(name, "/") || path.Clean(name) != name {
+			continue // Zip-slip guard: "../" or absolute entry names
+		}
+
+		mode := f.Mode()
+		isDir := mode.IsDir()
+		if !isDir && (!mode.IsRegular() || strings.HasSuffix(zipSlash(f.Name), "/")) {
+			continue // Symlinks, and folders only named with a backslash (implied by their files)
+		}
+		if !isDir && !shouldIncludeFile(path.Base(name), includePatterns, excludePatterns) {
+			continue
+		}
+
+		var tarPath string
+		if flatten {
+			if isDir {
+				continue
+			}
+			tarPath = path.Base(name)
+			if existing, exists := fileNames[tarPath]; exists {
+				return fmt.Errorf("duplicate filename '%s' found in '%s' and '%s'", tarPath, existing, name)
+			}
+			fileNames[tarPath] = name
+		} else {
+			tarPath = path.Join(dirName, rel)
+		}
+
+		if err := fn(tarPath, f); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("folder '%s' not found in ZIP archive", subtree)
+	}
+	return nil
+}
+
+// PreviewZipContents lists the files CreateTarGzFromZip would archive for
+// subtree of the ZIP at zipPath, without writing anything.
+func PreviewZipContents(zipPath, subtree string, includePatterns, excludePatterns []string, flatten bool) ([]Entry, error) {
+	subtree, err := CleanZipSubtree(subtree)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ZIP archive: %w", err)
+	}
+	defer zr.Close()
+
+	var entries []Entry
+	err = walkZip(&zr.Reader, subtree, includePatterns, excludePatterns, flatten, func(tarPath string, f *zip.File) error {
+		if f.Mode().IsRegular() {
+			entries = append(entries, Entry{Name: tarPath, Size: int64(f.UncompressedSize64)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NewZipProgressTracker is NewProgressTracker for archiving subtree of the
+// ZIP at zipPath with CreateTarGzFromZip.
+func NewZipProgressTracker(zipPath, subtree string, includePatterns, excludePatterns []string, interval time.Duration, onUpdate func(Progress)) (*ProgressTracker, error) {
+	entries, err := PreviewZipContents(zipPath, subtree, includePatterns, excludePatterns, false)
+	if err != nil {
+		return nil, err
+	}
+	t := &ProgressTracker{Interval: interval, OnUpdate: onUpdate}
+	for _, e := range entries {
+		t.progress.TotalFiles++
+		t.progress.TotalBytes += e.Size
+	}
+	t.start = time.Now()
+	return t, nil
+}
+
+// CreateTarGzFromZip archives the folder subtree of the ZIP at zipPath
+// ("Run_1", "study/Run_1") straight into a tar at outputPath, decompressing
+// one file at a time, so a run stored in a ZIP is never extracted to disk.
+// Entry names, filtering, flattening and text normalization follow
+// CreateTarGzWithOptions.
+func CreateTarGzFromZip(zipPath, subtree, outputPath string, includePatterns, excludePatterns []string, flatten bool, compression string, text *TextNormalizer, progress *ProgressTracker) error {
+	subtree, err := CleanZipSubtree(subtree)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open ZIP archive: %w", err)
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create tar file: %w", err)
+	}
+
+	err = writeZipTar(outFile, &zr.Reader, subtree, includePatterns, excludePatterns, flatten, compression, text, progress)
+	if cerr := outFile.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("failed to close tar file: %w", cerr)
+	}
+	if err != nil {
+		securedelete.Remove(outputPath) // Clean up partial file
+		return fmt.Errorf("failed to create tar from ZIP: %w", err)
+	}
+	return nil
+}
+
+// writeZipTar writes the tar (gzip-compressed unless compression is "none")
+// of subtree of zr to w.
+func writeZipTar(w io.Writer, zr *zip.Reader, subtree string, includePatterns, excludePatterns []string, flatten bool, compression string, text *TextNormalizer, progress *ProgressTracker) error {
+	var gzWriter *gzip.Writer
+	if compression != "none" {
+		gzWriter = gzip.NewWriter(w)
+		w = gzWriter
+	}
+	tarWriter := tar.NewWriter(w)
+
+	err := walkZip(zr, subtree, includePatterns, excludePatterns, flatten, func(tarPath string, f *zip.File) error {
+		header, err := tar.FileInfoHeader(f.FileInfo(), "")
+		if err != nil {
+			return fmt.Errorf("failed to create tar header: %w", err)
+		}
+		header.Name = tarPath
+		if header.Typeflag == tar.TypeDir {
+			header.Name += "/"
+			return tarWriter.WriteHeader(header)
+		}
+
+		progress.startFile(tarPath)
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s in ZIP archive: %w", f.Name, err)
+		}
+		defer rc.Close()
+		contents, size, err := text.normalize(tarPath, progress.reader(rc), header.Size)
+		if err != nil {
+			return fmt.Errorf("failed to read %s in ZIP archive: %w", f.Name, err)
+		}
+		header.Size = size
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header: %w", err)
+		}
+		if _, err := io.Copy(tarWriter, contents); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		progress.finishFile()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish tar: %w", err)
+	}
+	if gzWriter != nil {
+		if err := gzWriter.Close(); err != nil {
+			return fmt.Errorf("failed to finish gzip stream: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExtractZip extracts the folder subtree of the ZIP at zipPath into destDir,
+// as destDir/<base name of subtree>, and returns that directory. Only that
+// folder is written, so picking a few runs out of a large study costs only
+// their size on disk. Modification times are kept.
+func ExtractZip(zipPath, subtree, destDir string) (string, error) {
+	subtree, err := CleanZipSubtree(subtree)
+	if err != nil {
+		return "", err
+	}
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open ZIP archive: %w", err)
+	}
+	defer zr.Close()
+
+	root := filepath.Join(destDir, path.Base(subtree))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", root, err)
+	}
+	err = walkZip(&zr.Reader, subtree, nil, nil, false, func(tarPath string, f *zip.File) error {
+		target := filepath.Join(destDir, filepath.FromSlash(tarPath))
+		if f.FileInfo().IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := extractZipFile(f, target); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+		}
+		modTime := f.FileInfo().ModTime()
+		return os.Chtimes(target, modTime, modTime)
+	})
+	if err != nil {
+		return "", err
+	}
+	return root, nil
+}
+
+// extractZipFile writes the contents of f to target.
+func extractZipFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,138 @@
+package tar
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// makeStudyZip writes a ZIP with two runs below "study", a loose file and an
+// entry trying to escape the archive.
+func makeStudyZip(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "study.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"study/Run_1/input.sim":   "sim 1",
+		"study/Run_1/mesh/a.msh":  "mesh",
+		"study/Run_1/solver.log":  "log",
+		"study/Run_1/../evil.sh":  "escape",
+		"study/Run_2/input.sim":   "sim 2",
+		"study/README.txt":        "readme",
+		"study/Run_1/results/":    "",
+		"study/Run_10/input.sim":  "sim 10",
+		"study/Run_2/mesh/b.msh":  "mesh",
+		"study/Run_2/mesh/c.msh":  "mesh",
+		"study/Run_10/mesh/d.msh": "mesh",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return path
+}
+
+func TestCreateTarGzFromZip(t *testing.T) {
+	zipPath := makeStudyZip(t)
+	if !IsZipSource(zipPath) {
+		t.Fatal("IsZipSource = false for a ZIP file")
+	}
+
+	for _, compression := range []string{"gzip", "none"} {
+		archive := filepath.Join(t.TempDir(), "run.tar")
+		if err := CreateTarGzFromZip(zipPath, "study/Run_1/", archive, nil, []string{"*.log"}, false, compression, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		listed, err := ListArchive(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		preview, err := PreviewZipContents(zipPath, "study/Run_1", nil, []string{"*.log"}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"Run_1/input.sim", "Run_1/mesh/a.msh"}
+		if got := entryNames(listed); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: archive = %v, want %v", compression, got, want)
+		}
+		if got := entryNames(preview); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: preview = %v, want %v", compression, got, want)
+		}
+	}
+}
+
+func TestCreateTarGzFromZip_Errors(t *testing.T) {
+	zipPath := makeStudyZip(t)
+	out := filepath.Join(t.TempDir(), "run.tar.gz")
+
+	if err := CreateTarGzFromZip(zipPath, "study/Run_3", out, nil, nil, false, "gzip", nil, nil); err == nil {
+		t.Error("expected error for a missing folder")
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("partial archive left behind")
+	}
+	if err := CreateTarGzFromZip(zipPath, "../outside", out, nil, nil, false, "gzip", nil, nil); err == nil {
+		t.Error("expected error for a folder outside the archive")
+	}
+	if err := CreateTarGzFromZip(zipPath, "study", out, nil, nil, true, "gzip", nil, nil); err == nil {
+		t.Error("expected duplicate file name error in flatten mode")
+	}
+}
+
+func TestZipProgressTracker(t *testing.T) {
+	zipPath := makeStudyZip(t)
+	var last Progress
+	progress, err := NewZipProgressTracker(zipPath, "study/Run_2", nil, nil, 0, func(p Progress) { last = p })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateTarGzFromZip(zipPath, "study/Run_2", filepath.Join(t.TempDir(), "run.tar.gz"), nil, nil, false, "gzip", nil, progress); err != nil {
+		t.Fatal(err)
+	}
+	progress.Finish()
+	if !last.Done || last.Files != 3 || last.TotalFiles != 3 || last.Bytes != last.TotalBytes || last.TotalBytes != 13 {
+		t.Errorf("final progress = %+v, want 3/3 files and 13/13 bytes", last)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	zipPath := makeStudyZip(t)
+	dest := t.TempDir()
+
+	dir, err := ExtractZip(zipPath, "study/Run_1", dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join(dest, "Run_1") {
+		t.Errorf("dir = %s, want %s", dir, filepath.Join(dest, "Run_1"))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "mesh", "a.msh"))
+	if err != nil || string(data) != "mesh" {
+		t.Errorf("a.msh = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "results")); err != nil || !info.IsDir() {
+		t.Errorf("empty results folder not extracted: %v", err)
+	}
+
+	// Only the selected run is written, and nothing escapes dest
+	entries, _ := os.ReadDir(dest)
+	if len(entries) != 1 {
+		t.Errorf("dest has %d entries, want only Run_1", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(dest, "evil.sh")); !os.IsNotExist(err) {
+		t.Error("entry escaping the run folder was extracted")
+	}
+}
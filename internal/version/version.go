@@ -2,9 +2,87 @@
 // This is a separate package to avoid import cycles between cli and service packages.
 package version
 
+import (
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
 // Version is the build version string, set by ldflags during build.
 // Format: vX.Y.Z or vX.Y.Z-dev for development builds.
 var Version = "v4.9.8"
 
 // BuildTime is the build timestamp, set by ldflags during build.
 var BuildTime = "unknown"
+
+// Compare compares two semver-like version strings.
+// Returns >0 if a > b, <0 if a < b, 0 if equal.
+// Strips leading "v" prefix and "-dev"/"-beta"/etc. suffixes before comparison.
+func Compare(a, b string) int {
+	parseVersion := func(v string) []int {
+		v = strings.TrimPrefix(v, "v")
+		// Strip pre-release suffix (e.g., "-dev", "-beta.1")
+		if idx := strings.IndexByte(v, '-'); idx != -1 {
+			v = v[:idx]
+		}
+		parts := strings.Split(v, ".")
+		nums := make([]int, len(parts))
+		for i, p := range parts {
+			n, _ := strconv.Atoi(p)
+			nums[i] = n
+		}
+		return nums
+	}
+
+	aParts := parseVersion(a)
+	bParts := parseVersion(b)
+
+	// Pad shorter slice with zeros
+	maxLen := len(aParts)
+	if len(bParts) > maxLen {
+		maxLen = len(bParts)
+	}
+	for len(aParts) < maxLen {
+		aParts = append(aParts, 0)
+	}
+	for len(bParts) < maxLen {
+		bParts = append(bParts, 0)
+	}
+
+	for i := 0; i < maxLen; i++ {
+		if aParts[i] > bParts[i] {
+			return 1
+		}
+		if aParts[i] < bParts[i] {
+			return -1
+		}
+	}
+	return 0
+}
+
+// Commit returns the VCS revision the binary was built from, with a "-dirty"
+// suffix for builds from a modified tree, or "unknown" when the build carries
+// no VCS information (e.g. go run or -buildvcs=false).
+func Commit() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	var rev string
+	var modified bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if rev == "" {
+		return "unknown"
+	}
+	if modified {
+		rev += "-dirty"
+	}
+	return rev
+}
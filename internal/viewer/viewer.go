@@ -0,0 +1,56 @@
+// Package viewer implements read-only "viewer" mode for shared monitoring
+// stations. In viewer mode runs can be watched and files browsed, but
+// nothing is uploaded, downloaded, deleted or submitted.
+//
+// The mode is enforced where the work happens, not only in the GUI: the API
+// client refuses every request that is not a GET or HEAD, the transfer
+// entry points refuse to move data, and the service layer refuses mutating
+// operations, so no CLI command or GUI binding can get around it.
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// EnvVar forces viewer mode on when set to a true value, whatever the
+// config says. Useful for locking down a monitoring station's shortcut.
+const EnvVar = "RESCALE_VIEWER_MODE"
+
+// ErrReadOnly is wrapped by every error returned for an operation blocked
+// in viewer mode.
+var ErrReadOnly = errors.New("not allowed in viewer (read-only) mode")
+
+var enabled atomic.Bool
+
+// SetEnabled turns viewer mode on or off process-wide. Called from config
+// load (CLI) and from the GUI when the setting changes. RESCALE_VIEWER_MODE
+// keeps it on regardless of on.
+func SetEnabled(on bool) {
+	enabled.Store(on || envForced())
+}
+
+// Enabled reports whether viewer mode is on.
+func Enabled() bool {
+	return enabled.Load() || envForced()
+}
+
+// Check returns an error wrapping ErrReadOnly, naming op, when viewer mode
+// is on; otherwise nil.
+func Check(op string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", op, ErrReadOnly)
+}
+
+func envForced() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvVar))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
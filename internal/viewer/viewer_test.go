@@ -0,0 +1,38 @@
+package viewer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	SetEnabled(false)
+	if err := Check("upload"); err != nil {
+		t.Errorf("Check() with viewer mode off = %v, want nil", err)
+	}
+
+	SetEnabled(true)
+	defer SetEnabled(false)
+	err := Check("upload")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Check() with viewer mode on = %v, want ErrReadOnly", err)
+	}
+	if got, want := err.Error(), "upload: not allowed in viewer (read-only) mode"; got != want {
+		t.Errorf("Check() error = %q, want %q", got, want)
+	}
+}
+
+func TestEnvForcesViewerMode(t *testing.T) {
+	t.Setenv(EnvVar, "true")
+	SetEnabled(false)
+	if !Enabled() {
+		t.Error("Enabled() = false with RESCALE_VIEWER_MODE=true, want true")
+	}
+
+	t.Setenv(EnvVar, "")
+	SetEnabled(false)
+	if Enabled() {
+		t.Error("Enabled() = true after RESCALE_VIEWER_MODE was cleared, want false")
+	}
+}
@@ -0,0 +1,159 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/services"
+)
+
+// AdminJobFilterDTO selects jobs for the Team Admin tab.
+type AdminJobFilterDTO struct {
+	SinceDays int    `json:"sinceDays"` // 0 = constants.DefaultAdminSinceDays
+	Owner     string `json:"owner"`
+	Status    string `json:"status"`
+}
+
+// AdminJobsResultDTO is the JSON-safe result of ListTeamJobs.
+type AdminJobsResultDTO struct {
+	Jobs          []services.AdminJob `json:"jobs"`
+	AdminRequired bool                `json:"adminRequired"` // API key lacks org admin permissions
+	Error         string              `json:"error,omitempty"`
+}
+
+// AdminActionResultsDTO is the JSON-safe result of a bulk job action.
+type AdminActionResultsDTO struct {
+	Results []services.AdminActionResult `json:"results"`
+	Error   string                       `json:"error,omitempty"`
+}
+
+// MemberStorageDTO is one team member's storage use.
+type MemberStorageDTO struct {
+	Email     string `json:"email"`
+	FullName  string `json:"fullName"`
+	UsedBytes int64  `json:"usedBytes"`
+	FileCount int    `json:"fileCount"`
+}
+
+// TeamStorageResultDTO is the JSON-safe result of GetTeamStorageUsage.
+type TeamStorageResultDTO struct {
+	Members       []MemberStorageDTO `json:"members"`
+	AdminRequired bool               `json:"adminRequired"`
+	Error         string             `json:"error,omitempty"`
+}
+
+func (a *App) adminService() (*services.AdminService, error) {
+	if a.config == nil {
+		return nil, ErrNoConfig
+	}
+	if a.engine == nil || a.engine.API() == nil {
+		return nil, ErrNoAPIClient
+	}
+	return services.NewAdminService(a.engine.API()), nil
+}
+
+// ListTeamJobs lists recent Interlink jobs across the organization.
+func (a *App) ListTeamJobs(filter AdminJobFilterDTO) AdminJobsResultDTO {
+	result := AdminJobsResultDTO{Jobs: []services.AdminJob{}}
+	svc, err := a.adminService()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	days := filter.SinceDays
+	if days <= 0 {
+		days = constants.DefaultAdminSinceDays
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.AdminRequestTimeout)
+	defer cancel()
+
+	jobs, err := svc.ListInterlinkJobs(ctx, services.AdminJobFilter{
+		OrgCode: a.config.OrgCode,
+		Since:   time.Now().AddDate(0, 0, -days),
+		Tag:     a.config.AdminJobTag,
+		Owner:   filter.Owner,
+		Status:  filter.Status,
+	})
+	if err != nil {
+		result.AdminRequired = errors.Is(err, api.ErrAdminRequired)
+		result.Error = err.Error()
+		a.logWarn("admin", fmt.Sprintf("Failed to list team jobs: %v", err))
+		return result
+	}
+	result.Jobs = jobs
+	return result
+}
+
+// StopTeamJobs stops the given jobs, continuing past failures.
+func (a *App) StopTeamJobs(jobIDs []string) AdminActionResultsDTO {
+	return a.teamJobAction("Stopped", jobIDs, (*services.AdminService).StopJobs)
+}
+
+// ArchiveTeamJobs archives the given jobs, continuing past failures.
+func (a *App) ArchiveTeamJobs(jobIDs []string) AdminActionResultsDTO {
+	return a.teamJobAction("Archived", jobIDs, (*services.AdminService).ArchiveJobs)
+}
+
+func (a *App) teamJobAction(done string, jobIDs []string,
+	action func(*services.AdminService, context.Context, []string) ([]services.AdminActionResult, error)) AdminActionResultsDTO {
+	result := AdminActionResultsDTO{Results: []services.AdminActionResult{}}
+	svc, err := a.adminService()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.AdminRequestTimeout)
+	defer cancel()
+
+	results, err := action(svc, ctx, jobIDs)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			a.logWarn("admin", fmt.Sprintf("Job %s: %s", r.JobID, r.Error))
+		}
+	}
+	a.logInfo("admin", fmt.Sprintf("%s %d of %d team job(s)", done, len(results)-failed, len(results)))
+	result.Results = results
+	return result
+}
+
+// GetTeamStorageUsage returns per-member storage use, largest first.
+func (a *App) GetTeamStorageUsage() TeamStorageResultDTO {
+	result := TeamStorageResultDTO{Members: []MemberStorageDTO{}}
+	svc, err := a.adminService()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.AdminRequestTimeout)
+	defer cancel()
+
+	usage, err := svc.StorageUsage(ctx, a.config.OrgCode)
+	if err != nil {
+		result.AdminRequired = errors.Is(err, api.ErrAdminRequired)
+		result.Error = err.Error()
+		return result
+	}
+	for _, u := range usage {
+		result.Members = append(result.Members, MemberStorageDTO{
+			Email:     u.Email,
+			FullName:  u.FullName,
+			UsedBytes: u.UsedBytes,
+			FileCount: u.FileCount,
+		})
+	}
+	return result
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/wailsapp/wails/v2"
@@ -24,13 +25,21 @@ import (
 	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/core"
+	"github.com/rescale/rescale-int/internal/diagnostics"
 	"github.com/rescale/rescale-int/internal/events"
 	"github.com/rescale/rescale-int/internal/ipc"
 	"github.com/rescale/rescale-int/internal/logging"
+	"github.com/rescale/rescale-int/internal/netusage"
+	"github.com/rescale/rescale-int/internal/notify"
 	"github.com/rescale/rescale-int/internal/ratelimit"
 	"github.com/rescale/rescale-int/internal/ratelimit/coordinator"
 	"github.com/rescale/rescale-int/internal/reporting"
+	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/sendto"
 	"github.com/rescale/rescale-int/internal/service"
+	"github.com/rescale/rescale-int/internal/tracing"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // Assets holds the embedded frontend files, passed in from main package.
@@ -62,11 +71,25 @@ type App struct {
 
 	reporter *reporting.Reporter
 
+	// Most recent Settings network test, attached to error reports for support.
+	networkTestMu   sync.Mutex
+	lastNetworkTest *diagnostics.NetworkTestResult
+
 	// State helper shared with Tray and CLI; owns the canonical (installation,
 	// per-user) state model plus the 10s transient-pending timeout.
 	stateMu    sync.Mutex
 	stateComp  *service.Computer
 	priorState service.State
+
+	// Flushes the OTLP trace exporter (otel_endpoint); see startTracing
+	tracingShutdown tracing.ShutdownFunc
+
+	// Logs the session's network traffic for the footer usage widget
+	usageRecorder *netusage.Recorder
+
+	// POSTs run events to the configured webhooks; see startNotifier
+	notifierMu sync.Mutex
+	notifier   *notify.Notifier
 }
 
 // ensureStateComputer lazily constructs the shared service.Computer. Called
@@ -90,6 +113,12 @@ func NewApp() *App {
 // Messages are truncated to 1000 chars to prevent unbounded log growth.
 const maxLogMessageLen = 1000
 
+// apiContext returns a context bounded by the configured timeout for an API
+// call of the given endpoint class (api_timeout_* settings).
+func (a *App) apiContext(class config.APIEndpointClass) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), a.config.APITimeout(class))
+}
+
 func (a *App) log(level string, stage string, message string) {
 	// Truncate very long messages to prevent memory issues
 	if len(message) > maxLogMessageLen {
@@ -173,12 +202,29 @@ func (a *App) startup(ctx context.Context) {
 		// Set EventBus for timing infrastructure so timing logs appear in Activity tab
 		cloud.SetEventBus(a.engine.Events())
 
+		// Pause/resume transfers across network changes (Wi-Fi → VPN, offline)
+		a.engine.WatchNetwork(ctx)
+
+		// Report blackout windows (blackout_windows) for the PUR run countdown
+		a.engine.WatchBlackout(ctx)
+
+		// Trim caches left over from earlier sessions to the configured budget
+		go a.engine.EnforceCacheLimits()
+
+		// Upload files sent from the OS context menu ("Send to Rescale Interlink")
+		go sendto.NewProcessor(a.engine, func() string {
+			if a.config == nil {
+				return ""
+			}
+			return a.config.SendToFolder
+		}, wailsLogger).Run(ctx)
+
 		// Wire cross-process rate limit coordinator (lazy — only spawns when GetLimiter is called)
 		ratelimit.GlobalStore().SetCoordinatorEnsurer(coordinator.EnsureCoordinatorClient)
 
 		// Route backend log.Printf to GUI Activity Logs via TeeWriter.
 		// This intercepts all stdlib log output and publishes to EventBus.
-		tee := logging.NewTeeWriter(os.Stderr, a.engine.Events())
+		tee := logging.NewTeeWriter(logging.StdlibWriter(os.Stderr), a.engine.Events())
 		log.SetOutput(tee)
 
 		// Wire rate limit visibility notifications for GUI Activity Logs
@@ -200,8 +246,20 @@ func (a *App) startup(ctx context.Context) {
 	// Initialize detailed logging from config
 	if a.config != nil {
 		cloud.SetDetailedLogging(a.config.DetailedLogging)
+		securedelete.SetEnabled(a.config.SecureDelete)
+		viewer.SetEnabled(a.config.ViewerMode)
+		resources.SetCPUBudget(a.config.CPUBudgetPercent, a.config.CPUReduceOnBattery)
+		if err := resources.SetBlackoutWindows(a.config.BlackoutWindows); err != nil {
+			wailsLogger.Warn().Err(err).Msg("Ignoring invalid blackout_windows")
+		}
+		a.startTracing()
+		a.startNotifier()
 	}
 
+	// Record network usage for the footer widget and 'history usage'
+	a.usageRecorder = netusage.NewRecorder(config.GetNetworkUsagePath())
+	go a.usageRecorder.Run(ctx, usageRecordInterval)
+
 	// Plan 2 path migrations (idempotent; current-user scope in GUI).
 	config.RunStartupMigrations(wailsLogger, config.ScopeCurrentUser, nil)
 
@@ -241,6 +299,78 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.engine != nil {
 		a.engine.Stop()
 	}
+
+	if a.usageRecorder != nil {
+		if err := a.usageRecorder.Flush(); err != nil {
+			wailsLogger.Warn().Err(err).Msg("Network usage not recorded")
+		}
+	}
+
+	// Deliver the last run events to webhooks
+	a.stopNotifier()
+
+	// Flush spans of the last operations
+	if a.tracingShutdown != nil {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = a.tracingShutdown(flushCtx)
+	}
+}
+
+// startTracing (re)starts exporting traces to the configured otel_endpoint,
+// or stops it when the endpoint is cleared.
+func (a *App) startTracing() {
+	shutdown, err := tracing.Init(context.Background(), a.config.OTelEndpoint, "gui")
+	if err != nil {
+		wailsLogger.Warn().Err(err).Msg("Tracing disabled")
+		return
+	}
+	a.tracingShutdown = shutdown
+	if tracing.Enabled(a.config.OTelEndpoint) {
+		wailsLogger.Info().Msg("Exporting traces over OTLP")
+	}
+}
+
+// startNotifier (re)starts delivering engine events to the configured
+// webhooks and Slack/Teams integrations, or stops it when none are set. The previous notifier finishes
+// its queued deliveries first.
+func (a *App) startNotifier() {
+	var n *notify.Notifier
+	if a.engine != nil {
+		var err error
+		if n, err = notify.FromConfig(a.config); err != nil {
+			wailsLogger.Warn().Err(err).Msg("Webhooks, chat and email notifications disabled")
+		}
+	}
+	if n != nil {
+		n.Attach(a.engine.Events())
+		wailsLogger.Info().
+			Int("webhooks", len(a.config.WebhookURLs)).
+			Bool("slack", a.config.SlackWebhookURL != "").
+			Bool("teams", a.config.TeamsWebhookURL != "").
+			Int("email_recipients", len(a.config.EmailTo)).
+			Msg("Sending run events to webhooks, chat and email")
+	}
+
+	a.notifierMu.Lock()
+	old := a.notifier
+	a.notifier = n
+	a.notifierMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+}
+
+// stopNotifier stops the webhook notifier, if any, after its queued
+// deliveries.
+func (a *App) stopNotifier() {
+	a.notifierMu.Lock()
+	n := a.notifier
+	a.notifier = nil
+	a.notifierMu.Unlock()
+	if n != nil {
+		n.Close()
+	}
 }
 
 // Run launches the Wails GUI application.
@@ -284,6 +414,14 @@ func Run(args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// JSON log output (log_format) from here on
+	if format, err := logging.ParseFormat(cfg.LogFormat); err != nil {
+		wailsLogger.Warn().Err(err).Msg("Ignoring log_format")
+	} else if format == logging.FormatJSON {
+		logging.SetFormat(format)
+		wailsLogger = logging.NewLogger("wails", nil)
+	}
+
 	// Create engine
 	engine, err := core.NewEngine(cfg)
 	if err != nil {
@@ -0,0 +1,24 @@
+package wailsapp
+
+import (
+	"time"
+
+	"github.com/rescale/rescale-int/internal/resources"
+)
+
+// BlackoutStatusDTO reports whether tar and upload work is paused for a
+// blackout window (blackout_windows).
+type BlackoutStatusDTO struct {
+	Active bool   `json:"active"`
+	Until  string `json:"until"` // RFC3339 end of the window; empty when not active
+}
+
+// GetBlackoutStatus returns the current blackout state, for views opened
+// after the blackout_changed event was sent.
+func (a *App) GetBlackoutStatus() BlackoutStatusDTO {
+	until := resources.BlackoutUntil(time.Now())
+	if until.IsZero() {
+		return BlackoutStatusDTO{}
+	}
+	return BlackoutStatusDTO{Active: true, Until: until.Format(time.RFC3339)}
+}
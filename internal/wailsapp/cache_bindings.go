@@ -0,0 +1,93 @@
+package wailsapp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/cache"
+	"github.com/rescale/rescale-int/internal/cloud"
+)
+
+// CacheCategoryDTO is the disk footprint of one cache category.
+type CacheCategoryDTO struct {
+	Category   string `json:"category"` // tars, resume
+	Entries    int    `json:"entries"`
+	Bytes      int64  `json:"bytes"`
+	InUseBytes int64  `json:"inUseBytes"` // Held by a running run or transfer
+	Limit      int64  `json:"limit"`      // 0 = no per-category limit
+	Oldest     string `json:"oldest,omitempty"`
+}
+
+// CacheStatsDTO is the disk footprint of Interlink's caches.
+type CacheStatsDTO struct {
+	Categories []CacheCategoryDTO `json:"categories"`
+	Bytes      int64              `json:"bytes"`
+	Limit      int64              `json:"limit"` // 0 = unlimited
+	Error      string             `json:"error,omitempty"`
+}
+
+// CacheClearResultDTO reports what ClearCache removed.
+type CacheClearResultDTO struct {
+	Removed int    `json:"removed"`
+	Freed   int64  `json:"freed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetCacheStats reports how much disk each cache category uses.
+func (a *App) GetCacheStats() CacheStatsDTO {
+	result := CacheStatsDTO{Categories: []CacheCategoryDTO{}}
+	if a.config == nil {
+		result.Error = "configuration not loaded"
+		return result
+	}
+	mgr, err := cache.New(a.config)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	stats, err := mgr.Stats()
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Bytes = stats.Bytes
+	result.Limit = stats.Limit
+	for _, c := range stats.Categories {
+		dto := CacheCategoryDTO{
+			Category:   c.Category,
+			Entries:    c.Entries,
+			Bytes:      c.Bytes,
+			InUseBytes: c.InUseBytes,
+			Limit:      c.Limit,
+		}
+		if !c.Oldest.IsZero() {
+			dto.Oldest = c.Oldest.Format(time.RFC3339)
+		}
+		result.Categories = append(result.Categories, dto)
+	}
+	return result
+}
+
+// ClearCache removes cached files in the given categories (all if empty)
+// and drops the in-memory software/hardware catalogs. Files held by a
+// running run or transfer are kept.
+func (a *App) ClearCache(categories []string) CacheClearResultDTO {
+	a.ClearCatalogCache()
+	if a.config == nil {
+		return CacheClearResultDTO{Error: "configuration not loaded"}
+	}
+	mgr, err := cache.New(a.config)
+	if err != nil {
+		return CacheClearResultDTO{Error: err.Error()}
+	}
+
+	res := mgr.Clear(categories...)
+	result := CacheClearResultDTO{Removed: res.Removed, Freed: res.Freed}
+	if len(res.Errors) > 0 {
+		result.Error = errors.Join(res.Errors...).Error()
+		a.logWarn("cache", fmt.Sprintf("Cache clear: %s", result.Error))
+	}
+	a.logInfo("cache", fmt.Sprintf("Cleared %d cached file(s), freed %s", res.Removed, cloud.FormatBytes(res.Freed)))
+	return result
+}
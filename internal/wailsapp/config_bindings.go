@@ -12,10 +12,22 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cache"
 	"github.com/rescale/rescale-int/internal/cli"
 	"github.com/rescale/rescale-int/internal/cloud"
 	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
 	intfips "github.com/rescale/rescale-int/internal/fips"
+	inthttp "github.com/rescale/rescale-int/internal/http"
+	"github.com/rescale/rescale-int/internal/notify"
+	"github.com/rescale/rescale-int/internal/resources"
+	"github.com/rescale/rescale-int/internal/templaterepo"
+	"github.com/rescale/rescale-int/internal/tracing"
+	"github.com/rescale/rescale-int/internal/transfer/postprocess"
+	"github.com/rescale/rescale-int/internal/util/securedelete"
+	"github.com/rescale/rescale-int/internal/util/tags"
+	"github.com/rescale/rescale-int/internal/util/tar"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // AppInfoDTO contains application version, FIPS, and platform information.
@@ -67,27 +79,90 @@ func (a *App) GetAppInfo() AppInfoDTO {
 
 // ConfigDTO is the JSON-safe configuration structure.
 type ConfigDTO struct {
-	APIBaseURL        string `json:"apiBaseUrl"`
-	TenantURL         string `json:"tenantUrl"`
-	APIKey            string `json:"apiKey"`
-	ProxyMode         string `json:"proxyMode"`
-	ProxyHost         string `json:"proxyHost"`
-	ProxyPort         int    `json:"proxyPort"`
-	ProxyUser         string `json:"proxyUser"`
-	ProxyPassword     string `json:"proxyPassword"`
-	NoProxy           string `json:"noProxy"`
-	ProxyWarmup       bool   `json:"proxyWarmup"`
-	TarWorkers        int    `json:"tarWorkers"`
-	UploadWorkers     int    `json:"uploadWorkers"`
-	JobWorkers        int    `json:"jobWorkers"`
-	ExcludePatterns   string `json:"excludePatterns"`
-	IncludePatterns   string `json:"includePatterns"`
-	FlattenTar        bool   `json:"flattenTar"`
-	TarCompression    string `json:"tarCompression"`
-	ValidationPattern string `json:"validationPattern"`
-	RunSubpath        string `json:"runSubpath"`
-	MaxRetries        int    `json:"maxRetries"`
-	DetailedLogging   bool   `json:"detailedLogging"`
+	APIBaseURL           string `json:"apiBaseUrl"`
+	TenantURL            string `json:"tenantUrl"`
+	APIKey               string `json:"apiKey"`
+	ProxyMode            string `json:"proxyMode"`
+	ProxyHost            string `json:"proxyHost"`
+	ProxyPort            int    `json:"proxyPort"`
+	ProxyUser            string `json:"proxyUser"`
+	ProxyPassword        string `json:"proxyPassword"`
+	NoProxy              string `json:"noProxy"`
+	ProxyWarmup          bool   `json:"proxyWarmup"`
+	TarWorkers           int    `json:"tarWorkers"`
+	UploadWorkers        int    `json:"uploadWorkers"`
+	JobWorkers           int    `json:"jobWorkers"`
+	ExcludePatterns      string `json:"excludePatterns"`
+	IncludePatterns      string `json:"includePatterns"`
+	FlattenTar           bool   `json:"flattenTar"`
+	TarCompression       string `json:"tarCompression"`
+	TarSplitMode         string `json:"tarSplitMode"`  // none, subdirs, size
+	TarSplitParts        int    `json:"tarSplitParts"` // tars per job in size mode
+	ValidationPattern    string `json:"validationPattern"`
+	RunSubpath           string `json:"runSubpath"`
+	MaxRetries           int    `json:"maxRetries"`
+	PartRetries          int    `json:"partRetries"`          // Attempt budget per upload part
+	LargeUploadConfirmGB int    `json:"largeUploadConfirmGb"` // 0 disables the confirmation
+	SettleSeconds        int    `json:"settleSeconds"`
+	SettleTimeoutSeconds int    `json:"settleTimeoutSeconds"`
+	TarLockedFiles       string `json:"tarLockedFiles"`      // fail, skip, retry, snapshot
+	TarLockRetrySeconds  int    `json:"tarLockRetrySeconds"` // wait between retries
+	TarLockRetries       int    `json:"tarLockRetries"`
+	TextNormalize        string `json:"textNormalize"`           // off, check, fix
+	TextNormalizeExts    string `json:"textNormalizeExtensions"` // Semicolon-separated; empty = default list
+	DefaultTags          string `json:"defaultTags"`             // Semicolon-separated; supports {version} and {run_id}
+	DetailedLogging      bool   `json:"detailedLogging"`
+	StateDir             string `json:"stateDir"`         // Empty = ~/.rescale-int/states
+	DownloadDir          string `json:"downloadDir"`      // Empty = local browser's current folder
+	DownloadOrganize     string `json:"downloadOrganize"` // none, job, date, date-job
+	SecureDelete         bool   `json:"secureDelete"`
+	JobValidationMode    string `json:"jobValidationMode"`  // permissive, strict
+	JobNamePolicy        string `json:"jobNamePolicy"`      // warn, suffix, block
+	DuplicateRunPolicy   string `json:"duplicateRunPolicy"` // warn, block, off
+	JobMetadataTarget    string `json:"jobMetadataTarget"`  // description, custom_fields, both
+	SendToFolder         string `json:"sendToFolder"`       // Folder path under My Library for context-menu uploads
+	UploadReadbackVerify bool   `json:"uploadReadbackVerify"`
+	CPUBudgetPercent     int    `json:"cpuBudgetPercent"` // 10-100
+	CPUReduceOnBattery   bool   `json:"cpuReduceOnBattery"`
+	UpdateURL            string `json:"updateUrl"`       // Self-update manifest (HTTPS)
+	UpdatePublicKey      string `json:"updatePublicKey"` // PEM file with the release signing key
+	SelfUpdateDisabled   bool   `json:"selfUpdateDisabled"`
+	AdminMode            bool   `json:"adminMode"`       // Show the Team Admin tab
+	AdminJobTag          string `json:"adminJobTag"`     // Tag marking Interlink jobs; empty = "interlink"
+	ViewerMode           bool   `json:"viewerMode"`      // Read-only: no uploads, downloads, deletes or runs
+	CacheMaxMB           int    `json:"cacheMaxMb"`      // Total disk cache budget; 0 = unlimited
+	CacheLimits          string `json:"cacheLimits"`     // Per-category caps, e.g. "tars=20480,resume=512"
+	Workspace            string `json:"workspace"`       // Workspace ID; empty = the API key's default
+	BlackoutWindows      string `json:"blackoutWindows"` // Daily pause windows, e.g. "01:00-03:00"
+	PostDownload         string `json:"postDownload"`    // Steps run on downloads, e.g. "untar;checksum"
+	TemplateRepo         string `json:"templateRepo"`    // Team template repository: folder or git URL[#ref]
+	OTelEndpoint         string `json:"otelEndpoint"`    // OTLP/HTTP trace collector; empty = tracing off
+	WebhookURLs          string `json:"webhookUrls"`     // Semicolon-separated; empty = no webhooks
+	WebhookEvents        string `json:"webhookEvents"`   // Semicolon-separated state_change, complete, error; empty = all
+	WebhookSecret        string `json:"webhookSecret"`   // HMAC signing key; empty = unsigned
+
+	// Slack/Teams chat notifications
+	SlackWebhookURL          string `json:"slackWebhookUrl"`          // Incoming webhook; empty = no Slack messages
+	TeamsWebhookURL          string `json:"teamsWebhookUrl"`          // Incoming webhook; empty = no Teams messages
+	ChatEvents               string `json:"chatEvents"`               // Semicolon-separated run_started, run_completed, job_failed; empty = all
+	ChatTemplateRunStarted   string `json:"chatTemplateRunStarted"`   // text/template; empty = default text
+	ChatTemplateRunCompleted string `json:"chatTemplateRunCompleted"` // text/template; empty = default text
+	ChatTemplateJobFailed    string `json:"chatTemplateJobFailed"`    // text/template; empty = default text
+
+	// Run summary email
+	SMTPHost     string `json:"smtpHost"`     // Empty = no email
+	SMTPPort     int    `json:"smtpPort"`     // 0 = 587 (STARTTLS); 465 = implicit TLS
+	SMTPUsername string `json:"smtpUsername"` // Empty = no login
+	SMTPPassword string `json:"smtpPassword"`
+	EmailFrom    string `json:"emailFrom"` // Empty = smtpUsername
+	EmailTo      string `json:"emailTo"`   // Semicolon-separated recipients
+}
+
+// IsViewerMode reports whether the app is in read-only viewer mode, from
+// viewer_mode or RESCALE_VIEWER_MODE. The UI disables mutating controls
+// when it is; the service layer refuses them either way.
+func (a *App) IsViewerMode() bool {
+	return viewer.Enabled()
 }
 
 // GetConfig returns the current configuration.
@@ -101,27 +176,81 @@ func (a *App) GetConfig() ConfigDTO {
 		compression = "gzip"
 	}
 	return ConfigDTO{
-		APIBaseURL:        a.config.APIBaseURL,
-		TenantURL:         a.config.TenantURL,
-		APIKey:            a.config.APIKey,
-		ProxyMode:         a.config.ProxyMode,
-		ProxyHost:         a.config.ProxyHost,
-		ProxyPort:         a.config.ProxyPort,
-		ProxyUser:         a.config.ProxyUser,
-		ProxyPassword:     a.config.ProxyPassword,
-		NoProxy:           a.config.NoProxy,
-		ProxyWarmup:       a.config.ProxyWarmup,
-		TarWorkers:        a.config.TarWorkers,
-		UploadWorkers:     a.config.UploadWorkers,
-		JobWorkers:        a.config.JobWorkers,
-		ExcludePatterns:   strings.Join(a.config.ExcludePatterns, ","),
-		IncludePatterns:   strings.Join(a.config.IncludePatterns, ","),
-		FlattenTar:        a.config.FlattenTar,
-		TarCompression:    compression,
-		ValidationPattern: a.config.ValidationPattern,
-		RunSubpath:        a.config.RunSubpath,
-		MaxRetries:        a.config.MaxRetries,
-		DetailedLogging:   a.config.DetailedLogging,
+		APIBaseURL:           a.config.APIBaseURL,
+		TenantURL:            a.config.TenantURL,
+		APIKey:               a.config.APIKey,
+		ProxyMode:            a.config.ProxyMode,
+		ProxyHost:            a.config.ProxyHost,
+		ProxyPort:            a.config.ProxyPort,
+		ProxyUser:            a.config.ProxyUser,
+		ProxyPassword:        a.config.ProxyPassword,
+		NoProxy:              a.config.NoProxy,
+		ProxyWarmup:          a.config.ProxyWarmup,
+		TarWorkers:           a.config.TarWorkers,
+		UploadWorkers:        a.config.UploadWorkers,
+		JobWorkers:           a.config.JobWorkers,
+		ExcludePatterns:      strings.Join(a.config.ExcludePatterns, ","),
+		IncludePatterns:      strings.Join(a.config.IncludePatterns, ","),
+		FlattenTar:           a.config.FlattenTar,
+		TarCompression:       compression,
+		TarSplitMode:         tar.NormalizeSplitMode(a.config.TarSplitMode),
+		TarSplitParts:        a.config.TarSplitParts,
+		ValidationPattern:    a.config.ValidationPattern,
+		RunSubpath:           a.config.RunSubpath,
+		MaxRetries:           a.config.MaxRetries,
+		PartRetries:          inthttp.PartRetries(a.config),
+		LargeUploadConfirmGB: a.config.LargeUploadConfirmGB,
+		SettleSeconds:        a.config.SettleSeconds,
+		SettleTimeoutSeconds: a.config.SettleTimeoutSeconds,
+		TarLockedFiles:       a.config.TarLockedFiles,
+		TarLockRetrySeconds:  a.config.TarLockRetrySeconds,
+		TarLockRetries:       a.config.TarLockRetries,
+		TextNormalize:        a.config.TextNormalize,
+		TextNormalizeExts:    strings.Join(a.config.TextNormalizeExtensions, ";"),
+		DefaultTags:          tags.JoinSemicolonSeparated(a.config.DefaultTags),
+		DetailedLogging:      a.config.DetailedLogging,
+		StateDir:             a.config.StateDir,
+		DownloadDir:          a.config.DownloadDir,
+		DownloadOrganize:     a.config.DownloadOrganize,
+		SecureDelete:         a.config.SecureDelete,
+		JobValidationMode:    a.config.JobValidationMode,
+		JobNamePolicy:        a.config.JobNamePolicy,
+		DuplicateRunPolicy:   a.config.DuplicateRunPolicy,
+		JobMetadataTarget:    a.config.JobMetadataTarget,
+		SendToFolder:         a.config.SendToFolder,
+		UploadReadbackVerify: a.config.UploadReadbackVerify,
+		CPUBudgetPercent:     a.config.CPUBudgetPercent,
+		CPUReduceOnBattery:   a.config.CPUReduceOnBattery,
+		UpdateURL:            a.config.UpdateURL,
+		UpdatePublicKey:      a.config.UpdatePublicKey,
+		SelfUpdateDisabled:   a.config.SelfUpdateDisabled,
+		AdminMode:            a.config.AdminMode,
+		AdminJobTag:          a.config.AdminJobTag,
+		ViewerMode:           a.config.ViewerMode,
+		CacheMaxMB:           a.config.CacheMaxMB,
+		CacheLimits:          a.config.CacheLimits,
+		Workspace:            a.config.Workspace,
+		BlackoutWindows:      a.config.BlackoutWindows,
+		PostDownload:         a.config.PostDownload,
+		TemplateRepo:         a.config.TemplateRepo,
+		OTelEndpoint:         a.config.OTelEndpoint,
+		WebhookURLs:          strings.Join(a.config.WebhookURLs, ";"),
+		WebhookEvents:        strings.Join(a.config.WebhookEvents, ";"),
+		WebhookSecret:        a.config.WebhookSecret,
+
+		SlackWebhookURL:          a.config.SlackWebhookURL,
+		TeamsWebhookURL:          a.config.TeamsWebhookURL,
+		ChatEvents:               strings.Join(a.config.ChatEvents, ";"),
+		ChatTemplateRunStarted:   a.config.ChatTemplateRunStarted,
+		ChatTemplateRunCompleted: a.config.ChatTemplateRunCompleted,
+		ChatTemplateJobFailed:    a.config.ChatTemplateJobFailed,
+
+		SMTPHost:     a.config.SMTPHost,
+		SMTPPort:     a.config.SMTPPort,
+		SMTPUsername: a.config.SMTPUsername,
+		SMTPPassword: a.config.SMTPPassword,
+		EmailFrom:    a.config.EmailFrom,
+		EmailTo:      strings.Join(a.config.EmailTo, ";"),
 	}
 }
 
@@ -132,6 +261,71 @@ func (a *App) UpdateConfig(cfg ConfigDTO) error {
 		wailsLogger.Warn().Msg("UpdateConfig: config is nil, returning")
 		return nil
 	}
+	if _, err := cache.ParseLimits(cfg.CacheMaxMB, cfg.CacheLimits); err != nil {
+		return err
+	}
+	if _, err := resources.ParseBlackoutWindows(cfg.BlackoutWindows); err != nil {
+		return err
+	}
+	if _, err := postprocess.Parse(cfg.PostDownload); err != nil {
+		return err
+	}
+	if repo := strings.TrimSpace(cfg.TemplateRepo); repo != "" {
+		if _, err := templaterepo.ParseSource(repo); err != nil {
+			return err
+		}
+	}
+	if err := tracing.ValidateEndpoint(cfg.OTelEndpoint); err != nil {
+		return err
+	}
+	webhookURLs := splitSemicolons(cfg.WebhookURLs)
+	for _, u := range webhookURLs {
+		if err := notify.ValidateURL(u); err != nil {
+			return err
+		}
+	}
+	webhookEvents := splitSemicolons(cfg.WebhookEvents)
+	if _, err := notify.ParseEvents(webhookEvents); err != nil {
+		return err
+	}
+	slackURL := strings.TrimSpace(cfg.SlackWebhookURL)
+	teamsURL := strings.TrimSpace(cfg.TeamsWebhookURL)
+	for _, u := range []string{slackURL, teamsURL} {
+		if u == "" {
+			continue
+		}
+		if err := notify.ValidateURL(u); err != nil {
+			return err
+		}
+	}
+	chatEvents := splitSemicolons(cfg.ChatEvents)
+	if _, err := notify.ParseChatEvents(chatEvents); err != nil {
+		return err
+	}
+	for event, text := range map[string]string{
+		notify.ChatRunStarted:   cfg.ChatTemplateRunStarted,
+		notify.ChatRunCompleted: cfg.ChatTemplateRunCompleted,
+		notify.ChatJobFailed:    cfg.ChatTemplateJobFailed,
+	} {
+		if _, err := notify.ParseChatTemplate(event, text); err != nil {
+			return err
+		}
+	}
+	emailConfig := config.Config{
+		SMTPHost:     strings.TrimSpace(cfg.SMTPHost),
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUsername: strings.TrimSpace(cfg.SMTPUsername),
+		SMTPPassword: cfg.SMTPPassword,
+		EmailFrom:    strings.TrimSpace(cfg.EmailFrom),
+		EmailTo:      splitSemicolons(cfg.EmailTo),
+	}
+	if err := notify.ValidateEmail(&emailConfig); err != nil {
+		return err
+	}
+	// A shared station must not be unlocked from the GUI it locks
+	if a.config.ViewerMode && !cfg.ViewerMode {
+		return fmt.Errorf("viewer mode can only be turned off by editing viewer_mode in the config file")
+	}
 	if err := config.ValidateProxyModeForBuild(cfg.ProxyMode); err != nil {
 		wailsLogger.Warn().Err(err).Str("proxy_mode", cfg.ProxyMode).Msg("UpdateConfig: unsupported proxy mode")
 		return err
@@ -146,7 +340,8 @@ func (a *App) UpdateConfig(cfg ConfigDTO) error {
 		a.config.ProxyPort != cfg.ProxyPort ||
 		a.config.ProxyUser != cfg.ProxyUser ||
 		a.config.ProxyPassword != cfg.ProxyPassword ||
-		a.config.NoProxy != cfg.NoProxy
+		a.config.NoProxy != cfg.NoProxy ||
+		a.config.Workspace != cfg.Workspace
 
 	a.config.APIBaseURL = cfg.APIBaseURL
 	a.config.TenantURL = cfg.TenantURL
@@ -173,10 +368,95 @@ func (a *App) UpdateConfig(cfg ConfigDTO) error {
 	}
 	a.config.FlattenTar = cfg.FlattenTar
 	a.config.TarCompression = cfg.TarCompression
+	a.config.TarSplitMode = cfg.TarSplitMode
+	if cfg.TarSplitParts >= 2 && cfg.TarSplitParts <= constants.MaxTarSplitParts {
+		a.config.TarSplitParts = cfg.TarSplitParts
+	}
 	a.config.ValidationPattern = cfg.ValidationPattern
 	a.config.RunSubpath = cfg.RunSubpath
 	a.config.MaxRetries = cfg.MaxRetries
+	if cfg.PartRetries >= 1 {
+		a.config.PartRetries = cfg.PartRetries
+	}
+	if cfg.LargeUploadConfirmGB >= 0 {
+		a.config.LargeUploadConfirmGB = cfg.LargeUploadConfirmGB
+	}
+	a.config.SettleSeconds = cfg.SettleSeconds
+	a.config.SettleTimeoutSeconds = cfg.SettleTimeoutSeconds
+	a.config.TarLockedFiles = cfg.TarLockedFiles
+	if cfg.TarLockRetrySeconds >= 1 {
+		a.config.TarLockRetrySeconds = cfg.TarLockRetrySeconds
+	}
+	if cfg.TarLockRetries >= 0 {
+		a.config.TarLockRetries = cfg.TarLockRetries
+	}
+	a.config.TextNormalize = cfg.TextNormalize
+	a.config.TextNormalizeExtensions = nil
+	for _, ext := range strings.Split(cfg.TextNormalizeExts, ";") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			a.config.TextNormalizeExtensions = append(a.config.TextNormalizeExtensions, ext)
+		}
+	}
+	a.config.DefaultTags = tags.ParseSemicolonSeparated(cfg.DefaultTags)
 	a.config.DetailedLogging = cfg.DetailedLogging
+	a.config.StateDir = strings.TrimSpace(cfg.StateDir)
+	a.config.DownloadDir = strings.TrimSpace(cfg.DownloadDir)
+	a.config.DownloadOrganize = cfg.DownloadOrganize
+	a.config.SecureDelete = cfg.SecureDelete
+	a.config.JobValidationMode = cfg.JobValidationMode
+	a.config.JobNamePolicy = cfg.JobNamePolicy
+	a.config.DuplicateRunPolicy = cfg.DuplicateRunPolicy
+	a.config.JobMetadataTarget = cfg.JobMetadataTarget
+	a.config.SendToFolder = strings.TrimSpace(cfg.SendToFolder)
+	a.config.UploadReadbackVerify = cfg.UploadReadbackVerify
+	a.config.CPUBudgetPercent = cfg.CPUBudgetPercent
+	a.config.CPUReduceOnBattery = cfg.CPUReduceOnBattery
+	a.config.UpdateURL = strings.TrimSpace(cfg.UpdateURL)
+	a.config.UpdatePublicKey = strings.TrimSpace(cfg.UpdatePublicKey)
+	a.config.SelfUpdateDisabled = cfg.SelfUpdateDisabled
+	a.config.AdminMode = cfg.AdminMode
+	a.config.AdminJobTag = strings.TrimSpace(cfg.AdminJobTag)
+	a.config.ViewerMode = cfg.ViewerMode
+	if cfg.CacheMaxMB >= 0 {
+		a.config.CacheMaxMB = cfg.CacheMaxMB
+	}
+	a.config.CacheLimits = strings.TrimSpace(cfg.CacheLimits)
+	a.config.BlackoutWindows = strings.TrimSpace(cfg.BlackoutWindows)
+	a.config.PostDownload = strings.TrimSpace(cfg.PostDownload)
+	a.config.TemplateRepo = strings.TrimSpace(cfg.TemplateRepo)
+	otelChanged := a.config.OTelEndpoint != strings.TrimSpace(cfg.OTelEndpoint)
+	a.config.OTelEndpoint = strings.TrimSpace(cfg.OTelEndpoint)
+	webhooksChanged := strings.Join(a.config.WebhookURLs, ";") != strings.Join(webhookURLs, ";") ||
+		strings.Join(a.config.WebhookEvents, ";") != strings.Join(webhookEvents, ";") ||
+		a.config.WebhookSecret != cfg.WebhookSecret ||
+		a.config.SlackWebhookURL != slackURL ||
+		a.config.TeamsWebhookURL != teamsURL ||
+		strings.Join(a.config.ChatEvents, ";") != strings.Join(chatEvents, ";") ||
+		a.config.ChatTemplateRunStarted != cfg.ChatTemplateRunStarted ||
+		a.config.ChatTemplateRunCompleted != cfg.ChatTemplateRunCompleted ||
+		a.config.ChatTemplateJobFailed != cfg.ChatTemplateJobFailed ||
+		a.config.SMTPHost != emailConfig.SMTPHost ||
+		a.config.SMTPPort != emailConfig.SMTPPort ||
+		a.config.SMTPUsername != emailConfig.SMTPUsername ||
+		a.config.SMTPPassword != emailConfig.SMTPPassword ||
+		a.config.EmailFrom != emailConfig.EmailFrom ||
+		strings.Join(a.config.EmailTo, ";") != strings.Join(emailConfig.EmailTo, ";")
+	a.config.WebhookURLs = webhookURLs
+	a.config.WebhookEvents = webhookEvents
+	a.config.WebhookSecret = cfg.WebhookSecret
+	a.config.SlackWebhookURL = slackURL
+	a.config.TeamsWebhookURL = teamsURL
+	a.config.ChatEvents = chatEvents
+	a.config.ChatTemplateRunStarted = cfg.ChatTemplateRunStarted
+	a.config.ChatTemplateRunCompleted = cfg.ChatTemplateRunCompleted
+	a.config.ChatTemplateJobFailed = cfg.ChatTemplateJobFailed
+	a.config.SMTPHost = emailConfig.SMTPHost
+	a.config.SMTPPort = emailConfig.SMTPPort
+	a.config.SMTPUsername = emailConfig.SMTPUsername
+	a.config.SMTPPassword = emailConfig.SMTPPassword
+	a.config.EmailFrom = emailConfig.EmailFrom
+	a.config.EmailTo = emailConfig.EmailTo
+	a.config.Workspace = strings.TrimSpace(cfg.Workspace)
 
 	// tenant_url is a legacy alias — keep in sync (both directions)
 	if a.config.TenantURL == "" && a.config.APIBaseURL != "" {
@@ -186,8 +466,24 @@ func (a *App) UpdateConfig(cfg ConfigDTO) error {
 		a.config.APIBaseURL = a.config.TenantURL
 	}
 
-	// Update timing system when DetailedLogging changes
+	// Apply process-wide toggles (timing logs, secure temp-file deletion,
+	// viewer mode, encryption CPU budget, blackout windows)
 	cloud.SetDetailedLogging(cfg.DetailedLogging)
+	securedelete.SetEnabled(cfg.SecureDelete)
+	viewer.SetEnabled(cfg.ViewerMode)
+	resources.SetCPUBudget(cfg.CPUBudgetPercent, cfg.CPUReduceOnBattery)
+	_ = resources.SetBlackoutWindows(a.config.BlackoutWindows) // Validated above
+	if otelChanged {
+		a.startTracing()
+	}
+	if webhooksChanged {
+		go a.startNotifier() // Waits for the old notifier's deliveries
+	}
+	if a.engine != nil {
+		if ts := a.engine.TransferService(); ts != nil {
+			ts.SetPostDownload(a.config.PostDownload)
+		}
+	}
 
 	// Update engine's API client when API-related settings change.
 	// Without this, typing a new API key and clicking "Test Connection" would fail
@@ -333,6 +629,8 @@ func (a *App) TestConnection() ConnectionResultDTO {
 	// Copy config values we need - avoid race conditions with concurrent config updates
 	configCopy := &config.Config{
 		APIBaseURL:    a.config.APIBaseURL,
+		APIBasePath:   a.config.APIBasePath,
+		APIHeaders:    a.config.APIHeaders,
 		APIKey:        a.config.APIKey,
 		ProxyMode:     a.config.ProxyMode,
 		ProxyHost:     a.config.ProxyHost,
@@ -340,6 +638,7 @@ func (a *App) TestConnection() ConnectionResultDTO {
 		ProxyUser:     a.config.ProxyUser,
 		ProxyPassword: a.config.ProxyPassword,
 		NoProxy:       a.config.NoProxy,
+		Workspace:     a.config.Workspace,
 		ProxyWarmup:   false, // CRITICAL: Disable proxy warmup for connection test to avoid blocking
 	}
 
@@ -637,3 +936,15 @@ func (a *App) SaveFile(title string) (result string, err error) {
 // =============================================================================
 // DEPRECATED - Auto-download config is now unified in daemon.conf.
 // Use GetDaemonConfig() and SaveDaemonConfig() from daemon_bindings.go instead.
+
+// splitSemicolons splits a semicolon-separated list from the Setup tab,
+// dropping empty items.
+func splitSemicolons(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
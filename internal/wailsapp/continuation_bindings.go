@@ -0,0 +1,123 @@
+package wailsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/pur/continuation"
+	"github.com/rescale/rescale-int/internal/pur/parser"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// ContinuationFileDTO is an output file of the job being continued.
+type ContinuationFileDTO struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	RelativePath string `json:"relativePath"` // Relative to the job's working directory
+	Size         int64  `json:"size"`
+}
+
+// ContinuationSourceDTO describes a finished job to continue: its settings,
+// status and output files to pick restart files from.
+type ContinuationSourceDTO struct {
+	JobID         string                `json:"jobId"`
+	Status        string                `json:"status"`
+	Continuable   bool                  `json:"continuable"` // Finished (completed, failed or stopped)
+	Job           JobSpecDTO            `json:"job"`         // Original settings; extraInputFileIds holds its inputs
+	SuggestedName string                `json:"suggestedName"`
+	Files         []ContinuationFileDTO `json:"files"`
+	Warnings      []string              `json:"warnings"`
+}
+
+// ContinuationRequestDTO selects how to continue a ContinuationSourceDTO.
+type ContinuationRequestDTO struct {
+	Job            JobSpecDTO `json:"job"` // ContinuationSourceDTO.Job
+	RestartFileIDs []string   `json:"restartFileIds"`
+	Name           string     `json:"name"`    // Empty = suggested name
+	Command        string     `json:"command"` // Empty = original command
+	KeepInputs     bool       `json:"keepInputs"`
+}
+
+// GetContinuationSource loads a job (ID or platform URL) to continue from
+// its restart files.
+func (a *App) GetContinuationSource(jobRef string) (ContinuationSourceDTO, error) {
+	if a.engine == nil || a.engine.API() == nil {
+		return ContinuationSourceDTO{}, ErrNoAPIClient
+	}
+	jobID, err := parser.ParseJobRef(jobRef)
+	if err != nil {
+		return ContinuationSourceDTO{}, err
+	}
+	apiClient := a.engine.API()
+	ctx, cancel := context.WithTimeout(context.Background(), constants.GUIOperationTimeout)
+	defer cancel()
+
+	history, err := apiClient.GetJobStatuses(ctx, jobID)
+	if err != nil {
+		return ContinuationSourceDTO{}, fmt.Errorf("failed to get status of job %s: %w", jobID, err)
+	}
+	latest, _, _ := watch.LatestStatus(history)
+
+	raw, err := apiClient.GetJobRaw(ctx, jobID)
+	if err != nil {
+		return ContinuationSourceDTO{}, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	spec, warnings, err := parser.RescaleJobToJobSpec(raw)
+	if err != nil {
+		return ContinuationSourceDTO{}, err
+	}
+	if tags, err := apiClient.GetJobTags(ctx, jobID); err == nil {
+		spec.Tags = tags
+	} else {
+		warnings = append(warnings, fmt.Sprintf("could not fetch tags: %v", err))
+	}
+
+	result := ContinuationSourceDTO{
+		JobID:         jobID,
+		Status:        latest.Status,
+		Continuable:   continuation.Continuable(latest.Status),
+		SuggestedName: continuation.NextName(spec.JobName),
+		Files:         []ContinuationFileDTO{},
+		Warnings:      warnings,
+	}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	result.Job = jobSpecToDTO(spec)
+	normalizeJobSpecDTO(&result.Job)
+
+	// Output files only exist once the job has finished
+	if result.Continuable {
+		files, err := apiClient.ListJobFiles(ctx, jobID)
+		if err != nil {
+			return ContinuationSourceDTO{}, fmt.Errorf("failed to list output files of job %s: %w", jobID, err)
+		}
+		for _, f := range files {
+			result.Files = append(result.Files, ContinuationFileDTO{
+				ID:           f.ID,
+				Name:         f.Name,
+				RelativePath: continuation.RelativePath(f),
+				Size:         f.DecryptedSize,
+			})
+		}
+	}
+	return result, nil
+}
+
+// BuildContinuationJob returns the job spec continuing req.Job from the
+// selected restart files, for StartSingleJob in "remoteFiles" mode with
+// inputFiles as the remote file IDs.
+func (a *App) BuildContinuationJob(req ContinuationRequestDTO) (JobSpecDTO, error) {
+	spec, err := continuation.BuildSpec(dtoToJobSpec(req.Job), req.RestartFileIDs, continuation.Options{
+		Name:       req.Name,
+		Command:    req.Command,
+		KeepInputs: req.KeepInputs,
+	})
+	if err != nil {
+		return JobSpecDTO{}, err
+	}
+	result := jobSpecToDTO(spec)
+	normalizeJobSpecDTO(&result)
+	return result, nil
+}
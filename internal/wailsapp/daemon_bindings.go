@@ -19,6 +19,7 @@ import (
 	"github.com/rescale/rescale-int/internal/pathutil"
 	"github.com/rescale/rescale-int/internal/service"
 	"github.com/rescale/rescale-int/internal/version"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // DaemonStatusDTO represents the daemon status for the frontend.
@@ -167,6 +168,10 @@ func (a *App) GetDaemonStatus() DaemonStatusDTO {
 // StartDaemon starts the daemon process in background mode with IPC enabled.
 // This spawns a new process that survives the GUI closing.
 func (a *App) StartDaemon() error {
+	if err := viewer.Check("start auto-download daemon"); err != nil {
+		return err
+	}
+
 	// Check if already running
 	if pid := daemon.IsDaemonRunning(); pid != 0 {
 		return fmt.Errorf("daemon is already running (PID %d)", pid)
@@ -24,6 +24,7 @@ import (
 	"github.com/rescale/rescale-int/internal/pathutil"
 	"github.com/rescale/rescale-int/internal/service"
 	"github.com/rescale/rescale-int/internal/version"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // Windows process creation flag to hide console window.
@@ -176,6 +177,10 @@ func (a *App) GetDaemonStatus() DaemonStatusDTO {
 // Uses subprocess mode by default instead of Windows Service (which requires admin).
 // Blocks subprocess spawn if Windows Service is already running.
 func (a *App) StartDaemon() error {
+	if err := viewer.Check("start auto-download daemon"); err != nil {
+		return err
+	}
+
 	// Persist config + token before handing off to a different-identity process.
 	if err := a.ensureAllConfigPersisted(); err != nil {
 		return fmt.Errorf("cannot start daemon: %w", err)
@@ -0,0 +1,171 @@
+package wailsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/diagnostics"
+)
+
+// NetworkTestResultDTO is the JSON-safe version of diagnostics.NetworkTestResult.
+type NetworkTestResultDTO struct {
+	StartedAt   string                   `json:"startedAt"`
+	DurationMs  int64                    `json:"durationMs"`
+	API         NetworkLatencyDTO        `json:"api"`
+	DNS         []NetworkDNSDTO          `json:"dns"`
+	Proxy       NetworkProxyDTO          `json:"proxy"`
+	Storage     []NetworkStorageProbeDTO `json:"storage"`
+	StorageType string                   `json:"storageType,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// NetworkLatencyDTO holds API round-trip percentiles in milliseconds.
+type NetworkLatencyDTO struct {
+	Host    string  `json:"host"`
+	Samples int     `json:"samples"`
+	Failed  int     `json:"failed"`
+	MinMs   float64 `json:"minMs"`
+	P50Ms   float64 `json:"p50Ms"`
+	P90Ms   float64 `json:"p90Ms"`
+	P99Ms   float64 `json:"p99Ms"`
+	MaxMs   float64 `json:"maxMs"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// NetworkDNSDTO holds the resolution time for one host.
+type NetworkDNSDTO struct {
+	Host       string   `json:"host"`
+	DurationMs float64  `json:"durationMs"`
+	Addresses  []string `json:"addresses"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// NetworkProxyDTO describes the proxy path to the API.
+type NetworkProxyDTO struct {
+	Mode string   `json:"mode"`
+	URL  string   `json:"url,omitempty"`
+	Hops int      `json:"hops"`
+	Via  []string `json:"via"`
+}
+
+// NetworkStorageProbeDTO holds PUT/GET throughput for one probe size.
+type NetworkStorageProbeDTO struct {
+	Label        string  `json:"label"`
+	Bytes        int64   `json:"bytes"`
+	UploadMs     float64 `json:"uploadMs"`
+	DownloadMs   float64 `json:"downloadMs"`
+	UploadMBps   float64 `json:"uploadMBps"`
+	DownloadMBps float64 `json:"downloadMBps"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// networkTestRunning prevents concurrent RunNetworkTest calls.
+var networkTestRunning sync.Mutex
+
+// RunNetworkTest measures API latency, DNS resolution, proxy hops and storage
+// throughput using the current configuration. The result is kept and attached
+// to error reports built afterwards so support sees the user's network profile.
+func (a *App) RunNetworkTest() NetworkTestResultDTO {
+	if !networkTestRunning.TryLock() {
+		return NetworkTestResultDTO{Error: "Network test already in progress"}
+	}
+	defer networkTestRunning.Unlock()
+
+	if a.config == nil {
+		return NetworkTestResultDTO{Error: "No configuration loaded"}
+	}
+	if a.engine == nil || a.engine.API() == nil {
+		return NetworkTestResultDTO{Error: "Not connected - test the connection first"}
+	}
+
+	a.logInfo("network-test", "Running network test...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.NetworkTestTimeout)
+	defer cancel()
+
+	result := diagnostics.NewNetworkTester(a.config, a.engine.API()).Run(ctx)
+
+	a.networkTestMu.Lock()
+	a.lastNetworkTest = result
+	a.networkTestMu.Unlock()
+
+	a.logInfo("network-test", fmt.Sprintf("Network test finished in %dms: API p50 %.0fms, %d proxy hop(s)",
+		result.DurationMs, result.API.P50Ms, result.Proxy.Hops))
+
+	return networkTestResultToDTO(result)
+}
+
+// GetLastNetworkTest returns the most recent network test result, if any.
+func (a *App) GetLastNetworkTest() *NetworkTestResultDTO {
+	result := a.getLastNetworkTest()
+	if result == nil {
+		return nil
+	}
+	dto := networkTestResultToDTO(result)
+	return &dto
+}
+
+// getLastNetworkTest returns the stored result for error reports.
+func (a *App) getLastNetworkTest() *diagnostics.NetworkTestResult {
+	a.networkTestMu.Lock()
+	defer a.networkTestMu.Unlock()
+	return a.lastNetworkTest
+}
+
+// networkTestResultToDTO converts a diagnostics result to its DTO.
+func networkTestResultToDTO(r *diagnostics.NetworkTestResult) NetworkTestResultDTO {
+	dto := NetworkTestResultDTO{
+		StartedAt:  r.StartedAt.Format(time.RFC3339),
+		DurationMs: r.DurationMs,
+		API: NetworkLatencyDTO{
+			Host:    r.API.Host,
+			Samples: r.API.Samples,
+			Failed:  r.API.Failed,
+			MinMs:   r.API.MinMs,
+			P50Ms:   r.API.P50Ms,
+			P90Ms:   r.API.P90Ms,
+			P99Ms:   r.API.P99Ms,
+			MaxMs:   r.API.MaxMs,
+			Error:   r.API.Error,
+		},
+		DNS: make([]NetworkDNSDTO, 0, len(r.DNS)),
+		Proxy: NetworkProxyDTO{
+			Mode: r.Proxy.Mode,
+			URL:  r.Proxy.URL,
+			Hops: r.Proxy.Hops,
+			Via:  r.Proxy.Via,
+		},
+		Storage:     make([]NetworkStorageProbeDTO, 0, len(r.Storage)),
+		StorageType: r.StorageType,
+	}
+	if dto.Proxy.Via == nil {
+		dto.Proxy.Via = []string{}
+	}
+	for _, d := range r.DNS {
+		addrs := d.Addresses
+		if addrs == nil {
+			addrs = []string{}
+		}
+		dto.DNS = append(dto.DNS, NetworkDNSDTO{
+			Host:       d.Host,
+			DurationMs: d.DurationMs,
+			Addresses:  addrs,
+			Error:      d.Error,
+		})
+	}
+	for _, s := range r.Storage {
+		dto.Storage = append(dto.Storage, NetworkStorageProbeDTO{
+			Label:        s.Label,
+			Bytes:        s.Bytes,
+			UploadMs:     s.UploadMs,
+			DownloadMs:   s.DownloadMs,
+			UploadMBps:   s.UploadMBps,
+			DownloadMBps: s.DownloadMBps,
+			Error:        s.Error,
+		})
+	}
+	return dto
+}
@@ -15,4 +15,7 @@ var (
 
 	// ErrNoAPIClient is returned when API client is not configured.
 	ErrNoAPIClient = errors.New("API client not configured")
+
+	// ErrNoConfig is returned when the application config is not loaded.
+	ErrNoConfig = errors.New("config not loaded")
 )
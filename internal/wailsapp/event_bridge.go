@@ -123,10 +123,12 @@ func (eb *EventBridge) forwardEvent(event events.Event) {
 
 	case *events.TransferEvent:
 		// Only throttle PROGRESS events — never throttle terminal states
-		// (failed, completed, cancelled). These must always be delivered to UI.
+		// (failed, completed, cancelled) or retry scheduling. These must
+		// always be delivered to UI.
 		isTerminalState := e.Type() == events.EventTransferFailed ||
 			e.Type() == events.EventTransferCompleted ||
-			e.Type() == events.EventTransferCancelled
+			e.Type() == events.EventTransferCancelled ||
+			e.Type() == events.EventTransferRetryScheduled
 		if !isTerminalState && eb.shouldThrottle(e.TaskID) {
 			return
 		}
@@ -148,6 +150,14 @@ func (eb *EventBridge) forwardEvent(event events.Event) {
 		// Forward credential changes so file browser can invalidate cache
 		runtime.EventsEmit(eb.ctx, "interlink:config_changed", configChangedEventToDTO(e))
 
+	case *events.NetworkChangedEvent:
+		// Infrequent; drives the Transfers tab "network paused" banner
+		runtime.EventsEmit(eb.ctx, "interlink:network_changed", networkChangedEventToDTO(e))
+
+	case *events.BlackoutChangedEvent:
+		// Starts and ends of blackout windows; drive the PUR run countdown
+		runtime.EventsEmit(eb.ctx, "interlink:blackout_changed", blackoutChangedEventToDTO(e))
+
 	case *events.ReportableErrorEvent:
 		// Reportable error events for safe error reporting — NOT throttled
 		runtime.EventsEmit(eb.ctx, "interlink:reportable_error", reportableErrorEventToDTO(e))
@@ -181,6 +191,11 @@ type ProgressEventDTO struct {
 	Message      string  `json:"message"`
 	RateBytes    float64 `json:"rateBytes"`
 	ETAMs        int64   `json:"etaMs"`
+
+	// Tar stage only
+	FilesCurrent int    `json:"filesCurrent,omitempty"`
+	FilesTotal   int    `json:"filesTotal,omitempty"`
+	CurrentFile  string `json:"currentFile,omitempty"`
 }
 
 func progressEventToDTO(e *events.ProgressEvent) ProgressEventDTO {
@@ -194,26 +209,33 @@ func progressEventToDTO(e *events.ProgressEvent) ProgressEventDTO {
 		Message:      e.Message,
 		RateBytes:    e.Rate,
 		ETAMs:        e.ETA.Milliseconds(),
+		FilesCurrent: e.FilesCurrent,
+		FilesTotal:   e.FilesTotal,
+		CurrentFile:  e.CurrentFile,
 	}
 }
 
 // LogEventDTO is the JSON-safe version of events.LogEvent.
 type LogEventDTO struct {
-	Timestamp string `json:"timestamp"`
-	Level     string `json:"level"`
-	Message   string `json:"message"`
-	Stage     string `json:"stage"`
-	JobName   string `json:"jobName"`
-	Error     string `json:"error,omitempty"`
+	Timestamp  string `json:"timestamp"`
+	Level      string `json:"level"`
+	Message    string `json:"message"`
+	Stage      string `json:"stage"`
+	JobName    string `json:"jobName"`
+	Error      string `json:"error,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+	TransferID string `json:"transferId,omitempty"`
 }
 
 func logEventToDTO(e *events.LogEvent) LogEventDTO {
 	dto := LogEventDTO{
-		Timestamp: e.Timestamp().Format(time.RFC3339Nano),
-		Level:     e.Level.String(),
-		Message:   e.Message,
-		Stage:     e.Stage,
-		JobName:   e.JobName,
+		Timestamp:  e.Timestamp().Format(time.RFC3339Nano),
+		Level:      e.Level.String(),
+		Message:    e.Message,
+		Stage:      e.Stage,
+		JobName:    e.JobName,
+		RunID:      e.RunID,
+		TransferID: e.TransferID,
 	}
 	if e.Error != nil {
 		dto.Error = e.Error.Error()
@@ -231,10 +253,15 @@ type StateChangeEventDTO struct {
 	JobID          string  `json:"jobId,omitempty"`
 	ErrorMessage   string  `json:"errorMessage,omitempty"`
 	UploadProgress float64 `json:"uploadProgress"`
+
+	// Queue/provisioning sub-status for the status stage (empty otherwise)
+	SubStatus       string `json:"subStatus,omitempty"`
+	SubStatusReason string `json:"subStatusReason,omitempty"`
+	SubStatusSince  string `json:"subStatusSince,omitempty"` // RFC3339
 }
 
 func stateChangeEventToDTO(e *events.StateChangeEvent) StateChangeEventDTO {
-	return StateChangeEventDTO{
+	dto := StateChangeEventDTO{
 		Timestamp:      e.Timestamp().Format(time.RFC3339Nano),
 		JobName:        e.JobName,
 		OldStatus:      e.OldStatus,
@@ -243,7 +270,14 @@ func stateChangeEventToDTO(e *events.StateChangeEvent) StateChangeEventDTO {
 		JobID:          e.JobID,
 		ErrorMessage:   e.ErrorMessage,
 		UploadProgress: e.UploadProgress,
+
+		SubStatus:       e.SubStatus,
+		SubStatusReason: e.SubStatusReason,
 	}
+	if !e.SubStatusSince.IsZero() {
+		dto.SubStatusSince = e.SubStatusSince.Format(time.RFC3339)
+	}
+	return dto
 }
 
 // ErrorEventDTO is the JSON-safe version of events.ErrorEvent.
@@ -269,20 +303,26 @@ func errorEventToDTO(e *events.ErrorEvent) ErrorEventDTO {
 
 // CompleteEventDTO is the JSON-safe version of events.CompleteEvent.
 type CompleteEventDTO struct {
-	Timestamp   string `json:"timestamp"`
-	TotalJobs   int    `json:"totalJobs"`
-	SuccessJobs int    `json:"successJobs"`
-	FailedJobs  int    `json:"failedJobs"`
-	DurationMs  int64  `json:"durationMs"`
+	Timestamp     string `json:"timestamp"`
+	TotalJobs     int    `json:"totalJobs"`
+	SuccessJobs   int    `json:"successJobs"`
+	FailedJobs    int    `json:"failedJobs"`
+	DurationMs    int64  `json:"durationMs"`
+	ReportPath    string `json:"reportPath,omitempty"`
+	BytesSent     int64  `json:"bytesSent"`
+	BytesReceived int64  `json:"bytesReceived"`
 }
 
 func completeEventToDTO(e *events.CompleteEvent) CompleteEventDTO {
 	return CompleteEventDTO{
-		Timestamp:   e.Timestamp().Format(time.RFC3339Nano),
-		TotalJobs:   e.TotalJobs,
-		SuccessJobs: e.SuccessJobs,
-		FailedJobs:  e.FailedJobs,
-		DurationMs:  e.Duration.Milliseconds(),
+		Timestamp:     e.Timestamp().Format(time.RFC3339Nano),
+		TotalJobs:     e.TotalJobs,
+		SuccessJobs:   e.SuccessJobs,
+		FailedJobs:    e.FailedJobs,
+		DurationMs:    e.Duration.Milliseconds(),
+		ReportPath:    e.ReportPath,
+		BytesSent:     e.BytesSent,
+		BytesReceived: e.BytesReceived,
 	}
 }
 
@@ -296,6 +336,7 @@ type TransferEventDTO struct {
 	Progress  float64 `json:"progress"` // 0.0 to 1.0
 	Speed     float64 `json:"speed"`    // bytes/sec
 	Error     string  `json:"error,omitempty"`
+	RunID     string  `json:"runId,omitempty"` // PUR run the transfer belongs to
 }
 
 func transferEventToDTO(e *events.TransferEvent) TransferEventDTO {
@@ -307,6 +348,7 @@ func transferEventToDTO(e *events.TransferEvent) TransferEventDTO {
 		Size:      e.Size,
 		Progress:  e.Progress,
 		Speed:     e.Speed,
+		RunID:     e.RunID,
 	}
 	if e.Error != nil {
 		dto.Error = e.Error.Error()
@@ -430,6 +472,41 @@ func configChangedEventToDTO(e *events.ConfigChangedEvent) ConfigChangedEventDTO
 	}
 }
 
+// NetworkChangedEventDTO is the JSON-safe version of events.NetworkChangedEvent.
+type NetworkChangedEventDTO struct {
+	Timestamp string `json:"timestamp"`
+	OldRoute  string `json:"oldRoute"`
+	NewRoute  string `json:"newRoute"`
+	Online    bool   `json:"online"`
+}
+
+func networkChangedEventToDTO(e *events.NetworkChangedEvent) NetworkChangedEventDTO {
+	return NetworkChangedEventDTO{
+		Timestamp: e.Timestamp().Format(time.RFC3339Nano),
+		OldRoute:  e.OldRoute,
+		NewRoute:  e.NewRoute,
+		Online:    e.Online,
+	}
+}
+
+// BlackoutChangedEventDTO is the JSON-safe version of events.BlackoutChangedEvent.
+type BlackoutChangedEventDTO struct {
+	Timestamp string `json:"timestamp"`
+	Active    bool   `json:"active"`
+	Until     string `json:"until"` // RFC3339; empty when not active
+}
+
+func blackoutChangedEventToDTO(e *events.BlackoutChangedEvent) BlackoutChangedEventDTO {
+	dto := BlackoutChangedEventDTO{
+		Timestamp: e.Timestamp().Format(time.RFC3339Nano),
+		Active:    e.Active,
+	}
+	if e.Active {
+		dto.Until = e.Until.Format(time.RFC3339)
+	}
+	return dto
+}
+
 // ReportableErrorEventDTO is the JSON-safe version of events.ReportableErrorEvent.
 type ReportableErrorEventDTO struct {
 	Timestamp    string                          `json:"timestamp"`
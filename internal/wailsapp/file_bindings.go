@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/cloud/upload"
+	"github.com/rescale/rescale-int/internal/config"
 	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/events"
 	inthttp "github.com/rescale/rescale-int/internal/http"
@@ -24,6 +26,7 @@ import (
 	"github.com/rescale/rescale-int/internal/transfer/folder"
 	"github.com/rescale/rescale-int/internal/transfer/scan"
 	"github.com/rescale/rescale-int/internal/validation"
+	"github.com/rescale/rescale-int/internal/viewer"
 )
 
 // translateAPIError converts common API errors to user-friendly messages.
@@ -82,6 +85,17 @@ type FolderContentsDTO struct {
 	Warning    string        `json:"warning,omitempty"`    // Timeout or error message
 }
 
+// FolderWindowDTO is one server-sorted page of a remote folder, fetched by
+// page number for the virtualized remote browser.
+type FolderWindowDTO struct {
+	FolderID string        `json:"folderId"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
+	Total    int           `json:"total"`
+	Items    []FileItemDTO `json:"items"`
+	Warning  string        `json:"warning,omitempty"`
+}
+
 // DeleteResultDTO contains the result of a delete operation.
 type DeleteResultDTO struct {
 	Deleted int    `json:"deleted"`
@@ -251,6 +265,82 @@ func (a *App) GetHomeDirectory() string {
 	return home
 }
 
+// LocalRootDTO is a drive, mount, or user-defined shortcut for the local browser.
+type LocalRootDTO struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Kind string `json:"kind"` // "home", "drive", "mount", "custom"
+}
+
+// GetLocalRoots returns the local browser sidebar entries: home, drives and
+// mounts, followed by user-defined roots from config.
+func (a *App) GetLocalRoots() []LocalRootDTO {
+	var custom []string
+	if a.config != nil {
+		custom = a.config.LocalRoots
+	}
+	roots := localfs.ListRoots(custom)
+	result := make([]LocalRootDTO, len(roots))
+	for i, r := range roots {
+		result[i] = LocalRootDTO{Name: r.Name, Path: r.Path, Kind: r.Kind}
+	}
+	return result
+}
+
+// AddLocalRoot adds a user-defined local browser root and persists it.
+func (a *App) AddLocalRoot(path string) error {
+	if a.config == nil {
+		return ErrNoConfig
+	}
+	if err := a.ValidateLocalDirectory(path); err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	for _, existing := range a.config.LocalRoots {
+		if filepath.Clean(existing) == abs {
+			return nil
+		}
+	}
+	a.config.LocalRoots = append(a.config.LocalRoots, abs)
+	return a.saveLocalRoots()
+}
+
+// RemoveLocalRoot removes a user-defined local browser root and persists the change.
+func (a *App) RemoveLocalRoot(path string) error {
+	if a.config == nil {
+		return ErrNoConfig
+	}
+	clean := filepath.Clean(path)
+	kept := a.config.LocalRoots[:0]
+	for _, existing := range a.config.LocalRoots {
+		if filepath.Clean(existing) != clean {
+			kept = append(kept, existing)
+		}
+	}
+	a.config.LocalRoots = kept
+	return a.saveLocalRoots()
+}
+
+// saveLocalRoots writes the roots to config.csv so they survive restarts.
+// Only local_roots changes on disk; other unsaved settings edits stay unsaved.
+func (a *App) saveLocalRoots() error {
+	configPath := config.GetDefaultConfigPath()
+	saved, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		a.logError("config", fmt.Sprintf("Failed to save local roots: %v", err))
+		return err
+	}
+	saved.LocalRoots = append([]string(nil), a.config.LocalRoots...)
+	if err := config.SaveConfigCSV(saved, configPath); err != nil {
+		a.logError("config", fmt.Sprintf("Failed to save local roots: %v", err))
+		return err
+	}
+	return nil
+}
+
 // ListRemoteFolder returns the contents of a remote folder (first page only).
 // Deprecated: Use ListRemoteFolderPage for paginated access.
 func (a *App) ListRemoteFolder(folderID string) FolderContentsDTO {
@@ -282,6 +372,51 @@ func (a *App) ListRemoteFolderPage(folderID string, cursor string, pageSize int)
 	return folderContentsToDTO(contents)
 }
 
+// ListRemoteFolderWindow returns one page (0-based) of a remote folder sorted
+// server-side by sortField ("name", "size" or "modTime"). The browser only
+// requests the pages around the visible rows, so huge folders stay usable.
+func (a *App) ListRemoteFolderWindow(folderID string, sortField string, sortDesc bool, page int, pageSize int) FolderWindowDTO {
+	empty := FolderWindowDTO{FolderID: folderID, Page: page, PageSize: pageSize, Items: []FileItemDTO{}}
+	if a.engine == nil {
+		return empty
+	}
+
+	fs := a.engine.FileService()
+	if fs == nil {
+		return empty
+	}
+
+	ctx := context.Background()
+	window, err := fs.ListFolderWindow(ctx, folderID, services.FolderSort{Field: sortField, Descending: sortDesc}, page, pageSize)
+	if err != nil {
+		empty.Warning = translateAPIError(err)
+		return empty
+	}
+
+	return FolderWindowDTO{
+		FolderID: window.FolderID,
+		Page:     window.Page,
+		PageSize: window.PageSize,
+		Total:    window.Total,
+		Items:    fileItemsToDTO(window.Items),
+	}
+}
+
+// FindRemoteFolderItem returns the index, in name order, of the first item in
+// the folder whose name starts at or after prefix, for jump-to-name.
+func (a *App) FindRemoteFolderItem(folderID string, prefix string, sortDesc bool, pageSize int) (int, error) {
+	if a.engine == nil {
+		return 0, ErrNoEngine
+	}
+
+	fs := a.engine.FileService()
+	if fs == nil {
+		return 0, ErrNoFileService
+	}
+
+	return fs.FindInFolder(context.Background(), folderID, prefix, sortDesc, pageSize)
+}
+
 // ListRemoteLegacy returns a flat list of all files (legacy mode).
 // Pass pageSize=0 for API default.
 func (a *App) ListRemoteLegacy(cursor string, pageSize int) FolderContentsDTO {
@@ -398,7 +533,7 @@ func (a *App) ValidateRemoteFolder(folderID string) error {
 	if folderID == "" {
 		return fmt.Errorf("no destination folder specified")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := a.apiContext(config.APIStatus)
 	defer cancel()
 	_, err := apiClient.ListFolderContentsPage(ctx, folderID, "", 1)
 	if err != nil {
@@ -419,6 +554,21 @@ func (a *App) ValidateLocalDirectory(path string) error {
 	return nil
 }
 
+// ResolveDownloadDestination returns the configured default download folder
+// for a download labelled label (a job or remote folder name), creating it if
+// needed. Returns "" when no download root is configured, in which case the
+// local browser's current folder is used.
+func (a *App) ResolveDownloadDestination(label string) (string, error) {
+	dir := config.DownloadDirectory(a.config, label, time.Now())
+	if dir == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download folder %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
 // CreateRemoteFolder creates a new folder.
 func (a *App) CreateRemoteFolder(name string, parentID string) (string, error) {
 	if a.engine == nil {
@@ -508,9 +658,20 @@ func folderContentsToDTO(contents *services.FolderContents) FolderContentsDTO {
 		return FolderContentsDTO{Items: []FileItemDTO{}}
 	}
 
-	items := make([]FileItemDTO, len(contents.Items))
-	for i, item := range contents.Items {
-		items[i] = FileItemDTO{
+	return FolderContentsDTO{
+		FolderID:   contents.FolderID,
+		FolderPath: contents.FolderPath,
+		Items:      fileItemsToDTO(contents.Items),
+		HasMore:    contents.HasMore,
+		NextCursor: contents.NextCursor,
+	}
+}
+
+// fileItemsToDTO converts service file items to their DTOs.
+func fileItemsToDTO(items []services.FileItem) []FileItemDTO {
+	dtos := make([]FileItemDTO, len(items))
+	for i, item := range items {
+		dtos[i] = FileItemDTO{
 			ID:        item.ID,
 			Name:      item.Name,
 			IsFolder:  item.IsFolder,
@@ -521,14 +682,7 @@ func folderContentsToDTO(contents *services.FolderContents) FolderContentsDTO {
 			SymlinkID: item.SymlinkID,
 		}
 	}
-
-	return FolderContentsDTO{
-		FolderID:   contents.FolderID,
-		FolderPath: contents.FolderPath,
-		Items:      items,
-		HasMore:    contents.HasMore,
-		NextCursor: contents.NextCursor,
-	}
+	return dtos
 }
 
 // FolderDownloadResultDTO is the JSON-safe version of cli.DownloadResult.
@@ -547,6 +701,10 @@ type FolderDownloadResultDTO struct {
 // folderName: the display name for the folder (used as the local folder name).
 // conflictMode: "merge" (skip existing files), "overwrite" (delete existing folder first), or "" (default, same as merge).
 func (a *App) StartFolderDownload(folderID string, folderName string, destPath string, conflictMode string) FolderDownloadResultDTO {
+	if err := viewer.Check("folder download"); err != nil {
+		return FolderDownloadResultDTO{Error: err.Error()}
+	}
+
 	displayName := folderName
 	if displayName == "" {
 		displayName = folderID
@@ -833,6 +991,37 @@ type FolderExistsCheckDTO struct {
 	Error    string `json:"error,omitempty"`    // Error message if check failed
 }
 
+// UploadNameDTO is the duplicate-name outcome for one file about to be
+// uploaded (see upload.DuplicatePolicy).
+type UploadNameDTO struct {
+	Name           string   `json:"name"`                     // Name to upload the file as
+	Exists         bool     `json:"exists"`                   // A file of the original name is already in the folder
+	Skip           bool     `json:"skip"`                     // Don't upload (policy "skip")
+	ReplaceFileIDs []string `json:"replaceFileIDs,omitempty"` // Existing files to delete after upload (policy "overwrite")
+}
+
+// UploadNamesPlanDTO resolves a batch of file names against a destination folder.
+type UploadNamesPlanDTO struct {
+	Files []UploadNameDTO `json:"files"`
+	Error string          `json:"error,omitempty"`
+}
+
+// LargeUploadDTO summarises a batch of local paths for the large-upload
+// confirmation. Needed is false when the batch is under the configured
+// threshold (or the check is off) and the upload can start straight away.
+type LargeUploadDTO struct {
+	Needed          bool    `json:"needed"`
+	Files           int     `json:"files"`
+	TotalBytes      int64   `json:"totalBytes"`
+	ThresholdBytes  int64   `json:"thresholdBytes"`
+	BytesPerSec     float64 `json:"bytesPerSec"`     // Measured from upload history; 0 when unknown
+	EstimateSeconds float64 `json:"estimateSeconds"` // 0 when unknown
+	QuotaKnown      bool    `json:"quotaKnown"`      // False for unlimited workspaces or platforms without quotas
+	QuotaRemaining  int64   `json:"quotaRemaining"`
+	ExceedsQuota    bool    `json:"exceedsQuota"`
+	Error           string  `json:"error,omitempty"`
+}
+
 // LocalFolderExistsCheckDTO returns info about whether a local folder exists.
 type LocalFolderExistsCheckDTO struct {
 	Exists bool   `json:"exists"`
@@ -921,11 +1110,81 @@ func (a *App) CheckFoldersExistForUpload(folderNames []string, parentFolderID st
 	return results
 }
 
+// PlanUploadNames applies a duplicate-name policy (skip, overwrite, rename,
+// version or allow) to file names about to be uploaded into folderID. The
+// folder is listed once for the whole batch. With "allow" nothing changes, so
+// the result just reports which names already exist.
+func (a *App) PlanUploadNames(fileNames []string, folderID string, policy string) UploadNamesPlanDTO {
+	p, err := upload.ParseDuplicatePolicy(policy)
+	if err != nil {
+		return UploadNamesPlanDTO{Error: err.Error()}
+	}
+	if a.engine == nil {
+		return UploadNamesPlanDTO{Error: ErrNoEngine.Error()}
+	}
+	apiClient := a.engine.API()
+	if apiClient == nil {
+		return UploadNamesPlanDTO{Error: "API client not configured"}
+	}
+
+	existing, err := upload.ListFolderNames(context.Background(), apiClient, folderID)
+	if err != nil {
+		return UploadNamesPlanDTO{Error: "Failed to check for existing files: " + err.Error()}
+	}
+	plan := UploadNamesPlanDTO{Files: make([]UploadNameDTO, len(fileNames))}
+	for i, name := range fileNames {
+		t := existing.Resolve(name, p)
+		plan.Files[i] = UploadNameDTO{Name: t.Name, Exists: t.Exists, Skip: t.Skip, ReplaceFileIDs: t.ReplaceIDs}
+	}
+	return plan
+}
+
+// CheckLargeUpload sizes the local files and folders about to be uploaded
+// and, when they exceed the large_upload_confirm_gb setting, returns the
+// total size, the estimated duration at the measured upload throughput and
+// the workspace's remaining storage quota for a confirmation dialog.
+func (a *App) CheckLargeUpload(paths []string) LargeUploadDTO {
+	if a.engine == nil {
+		return LargeUploadDTO{Error: ErrNoEngine.Error()}
+	}
+	apiClient := a.engine.API()
+	if apiClient == nil {
+		return LargeUploadDTO{Error: "API client not configured"}
+	}
+	if upload.LargeUploadThreshold(apiClient.GetConfig()) == 0 {
+		return LargeUploadDTO{}
+	}
+
+	total, files, err := upload.LocalSize(paths)
+	if err != nil {
+		return LargeUploadDTO{Error: err.Error()}
+	}
+	l := upload.CheckLargeUpload(context.Background(), apiClient, total, files)
+	if l == nil {
+		return LargeUploadDTO{Files: files, TotalBytes: total}
+	}
+	return LargeUploadDTO{
+		Needed:          true,
+		Files:           l.Files,
+		TotalBytes:      l.TotalBytes,
+		ThresholdBytes:  l.ThresholdBytes,
+		BytesPerSec:     l.BytesPerSec,
+		EstimateSeconds: l.Estimate.Seconds(),
+		QuotaKnown:      l.QuotaKnown,
+		QuotaRemaining:  l.QuotaRemaining,
+		ExceedsQuota:    l.ExceedsQuota(),
+	}
+}
+
 // StartFolderUpload uploads a local folder recursively to the Rescale platform.
 // Creates remote folder structure (merge mode: reuses existing folders), scans local
 // files, and queues them to TransferService. Returns immediately — scan and uploads
 // proceed in background.
 func (a *App) StartFolderUpload(localPath string, destFolderID string, uploadTags []string) FolderUploadResultDTO {
+	if err := viewer.Check("folder upload"); err != nil {
+		return FolderUploadResultDTO{Error: err.Error()}
+	}
+
 	displayName := filepath.Base(localPath)
 	a.logInfo("folder-upload", fmt.Sprintf("Starting folder upload: %s", displayName))
 
@@ -1040,7 +1299,7 @@ func (a *App) StartFolderUpload(localPath string, destFolderID string, uploadTag
 
 	// Validate destination folder exists before starting heavy work
 	if parentID != "" {
-		valCtx, valCancel := context.WithTimeout(ctx, 30*time.Second)
+		valCtx, valCancel := context.WithTimeout(ctx, a.config.APITimeout(config.APIStatus))
 		if _, err := apiClient.ListFolderContentsPage(valCtx, parentID, "", 1); err != nil {
 			valCancel()
 			deferredError = fmt.Sprintf("Destination folder not found or inaccessible: %s", err.Error())
@@ -0,0 +1,43 @@
+package wailsapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// TestAddLocalRoot_SavesOnlyRoots verifies adding a sidebar root persists the
+// root without writing other unsaved settings edits to config.csv.
+func TestAddLocalRoot_SavesOnlyRoots(t *testing.T) {
+	setIsolatedUserConfigEnv(t)
+	configPath := config.GetDefaultConfigPath()
+
+	saved, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved.APIBaseURL = "https://eu.rescale.com"
+	if err := config.SaveConfigCSV(saved, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := *saved
+	edited.APIBaseURL = "https://unsaved.example.com"
+	app := &App{config: &edited}
+	root := t.TempDir()
+	if err := app.AddLocalRoot(root); err != nil {
+		t.Fatalf("AddLocalRoot() error = %v", err)
+	}
+
+	reloaded, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.LocalRoots, []string{root}) {
+		t.Errorf("LocalRoots = %v, want [%s]", reloaded.LocalRoots, root)
+	}
+	if reloaded.APIBaseURL != "https://eu.rescale.com" {
+		t.Errorf("APIBaseURL = %q, unsaved edit was written", reloaded.APIBaseURL)
+	}
+}
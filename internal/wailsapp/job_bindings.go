@@ -12,17 +12,25 @@ import (
 	"time"
 
 	"github.com/rescale/rescale-int/internal/config"
-	"github.com/rescale/rescale-int/internal/constants"
 	"github.com/rescale/rescale-int/internal/core"
 	"github.com/rescale/rescale-int/internal/events"
+	"github.com/rescale/rescale-int/internal/hardware"
 	inthttp "github.com/rescale/rescale-int/internal/http"
 	"github.com/rescale/rescale-int/internal/models"
 	"github.com/rescale/rescale-int/internal/pur/filescan"
+	"github.com/rescale/rescale-int/internal/pur/jobarray"
+	"github.com/rescale/rescale-int/internal/pur/jobdiff"
 	"github.com/rescale/rescale-int/internal/pur/parser"
 	"github.com/rescale/rescale-int/internal/pur/pattern"
+	"github.com/rescale/rescale-int/internal/pur/pipeline"
+	"github.com/rescale/rescale-int/internal/pur/report"
+	"github.com/rescale/rescale-int/internal/pur/runtimes"
+	"github.com/rescale/rescale-int/internal/pur/state"
 	"github.com/rescale/rescale-int/internal/pur/validation"
 	"github.com/rescale/rescale-int/internal/reporting"
 	"github.com/rescale/rescale-int/internal/services"
+	"github.com/rescale/rescale-int/internal/transfer/folder"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // emitScanProgress publishes a scan progress event for software/hardware catalog scanning.
@@ -70,6 +78,28 @@ type JobSpecDTO struct {
 	InputFiles []string `json:"inputFiles,omitempty"`
 
 	TarSubpath string `json:"tarSubpath,omitempty"`
+
+	Priority int `json:"priority,omitempty"` // Higher runs first within a run
+
+	DestinationFolder string `json:"destinationFolder,omitempty"` // Remote folder path under My Library
+
+	Metadata map[string]string `json:"metadata,omitempty"` // Written to the job description and/or custom fields
+
+	ContinuationCommand string `json:"continuationCommand,omitempty"` // Command when continuing from restart files
+
+	OutputPatterns []string `json:"outputPatterns,omitempty"` // Output files downloaded into Directory on completion
+
+	DependsOn []string `json:"dependsOn,omitempty"` // Jobs of the run that must complete before this one is submitted
+
+	Array string `json:"array,omitempty"` // Index range (e.g. "1-100"); the spec runs as one job per index
+
+	NoTextNormalize bool `json:"noTextNormalize,omitempty"` // Archive text inputs as they are, whatever text_normalize says
+
+	EnvVars map[string]string `json:"envVars,omitempty"` // Environment variables set on the cluster, besides licenseSettings
+
+	UseRescaleLicense bool `json:"useRescaleLicense,omitempty"` // Run on a Rescale-provided license
+
+	ClusterID string `json:"clusterId,omitempty"` // Run on this reserved or persistent cluster
 }
 
 // SecondaryPatternDTO represents a secondary file pattern for file-based scanning.
@@ -93,6 +123,8 @@ type ScanOptionsDTO struct {
 
 	TarSubpath string `json:"tarSubpath,omitempty"`
 
+	OutputPatterns []string `json:"outputPatterns,omitempty"` // Overrides the template's output patterns
+
 	IteratePatterns bool `json:"iteratePatterns"`
 }
 
@@ -115,14 +147,24 @@ type CoreTypeDTO struct {
 	DisplayOrder int    `json:"displayOrder"`
 	IsActive     bool   `json:"isActive"`
 	Cores        []int  `json:"cores"`
+
+	// Hardware details and the classes the core type picker filters on;
+	// empty when the platform does not report them (see package hardware)
+	MemoryPerCoreGB   float64 `json:"memoryPerCoreGb,omitempty"`
+	Processor         string  `json:"processor,omitempty"`
+	Interconnect      string  `json:"interconnect,omitempty"`
+	InterconnectClass string  `json:"interconnectClass,omitempty"`
+	Architecture      string  `json:"architecture,omitempty"`
+	Price             float64 `json:"price,omitempty"`
+	PriceBand         string  `json:"priceBand,omitempty"`
 }
 
 // AnalysisCodeDTO represents a software analysis code.
 type AnalysisCodeDTO struct {
-	Code        string              `json:"code"`
-	Name        string              `json:"name"`
-	Description string              `json:"description"`
-	VendorName  string              `json:"vendorName"`
+	Code        string               `json:"code"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	VendorName  string               `json:"vendorName"`
 	Versions    []AnalysisVersionDTO `json:"versions"`
 }
 
@@ -173,18 +215,39 @@ type RunStatusDTO struct {
 
 // JobRowDTO represents a job row for the jobs table.
 type JobRowDTO struct {
-	Index          int     `json:"index"`
-	Directory      string  `json:"directory"`
-	JobName        string  `json:"jobName"`
-	TarStatus      string  `json:"tarStatus"`
-	UploadStatus   string  `json:"uploadStatus"`
-	UploadProgress float64 `json:"uploadProgress"`
-	CreateStatus   string  `json:"createStatus"`
-	SubmitStatus   string  `json:"submitStatus"`
-	Status         string  `json:"status"`
-	JobID          string  `json:"jobId"`
-	Progress       float64 `json:"progress"`
-	Error          string  `json:"error"`
+	Index          int      `json:"index"`
+	Directory      string   `json:"directory"`
+	JobName        string   `json:"jobName"`
+	TarStatus      string   `json:"tarStatus"`
+	UploadStatus   string   `json:"uploadStatus"`
+	UploadProgress float64  `json:"uploadProgress"`
+	CreateStatus   string   `json:"createStatus"`
+	SubmitStatus   string   `json:"submitStatus"`
+	Status         string   `json:"status"`
+	JobID          string   `json:"jobId"`
+	Progress       float64  `json:"progress"`
+	Error          string   `json:"error"`
+	Warnings       []string `json:"warnings,omitempty"`     // Returned by the platform when the job was created
+	OutputStatus   string   `json:"outputStatus,omitempty"` // Download of outputs on completion
+	ArrayName      string   `json:"arrayName,omitempty"`    // Array the job is a member of
+}
+
+// JobExportRowDTO is one row of the Jobs table as the GUI shows it, for
+// ExportJobsTable.
+type JobExportRowDTO struct {
+	Index          int    `json:"index"` // 0-based row index
+	Directory      string `json:"directory"`
+	JobName        string `json:"jobName"`
+	TarStatus      string `json:"tarStatus"`
+	UploadStatus   string `json:"uploadStatus"`
+	CreateStatus   string `json:"createStatus"`
+	SubmitStatus   string `json:"submitStatus"`
+	Status         string `json:"status"`
+	JobID          string `json:"jobId"`
+	Error          string `json:"error"`
+	PlatformStatus string `json:"platformStatus,omitempty"`
+	SubStatus      string `json:"subStatus,omitempty"`
+	SubStatusSince string `json:"subStatusSince,omitempty"` // RFC3339
 }
 
 // SingleJobInputDTO represents input for single job submission.
@@ -226,6 +289,7 @@ func (a *App) ScanDirectory(opts ScanOptionsDTO, template JobSpecDTO) ScanResult
 		PartDirs:          []string{opts.RootDir},
 		StartIndex:        1, // Prevent job names starting at _0
 		TarSubpath:        opts.TarSubpath,
+		OutputPatterns:    opts.OutputPatterns,
 		IteratePatterns:   opts.IteratePatterns,
 	}
 
@@ -293,6 +357,9 @@ func (a *App) scanFilesMode(opts ScanOptionsDTO, template JobSpecDTO) ScanResult
 		} else {
 			job.JobName = fmt.Sprintf("Job_%d", i+1)
 		}
+		if len(opts.OutputPatterns) > 0 {
+			job.OutputPatterns = opts.OutputPatterns
+		}
 
 		jobs = append(jobs, job)
 	}
@@ -325,7 +392,7 @@ func (a *App) GetCoreTypes() CoreTypesResultDTO {
 	// Emit scan start event
 	a.emitScanProgress("hardware", 0, 0, false, false, "")
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.PaginatedAPITimeout)
+	ctx, cancel := a.apiContext(config.APICatalog)
 	defer cancel()
 
 	coreTypes, err := a.engine.API().GetCoreTypes(ctx, true)
@@ -343,6 +410,14 @@ func (a *App) GetCoreTypes() CoreTypesResultDTO {
 			DisplayOrder: ct.DisplayOrder,
 			IsActive:     ct.IsActive,
 			Cores:        ct.Cores,
+
+			MemoryPerCoreGB:   hardware.MemoryPerCoreGB(ct),
+			Processor:         ct.Processor,
+			Interconnect:      ct.Interconnect,
+			InterconnectClass: hardware.Interconnect(ct),
+			Architecture:      hardware.Architecture(ct),
+			Price:             ct.Price,
+			PriceBand:         hardware.PriceBand(ct),
 		}
 	}
 
@@ -356,6 +431,17 @@ func (a *App) GetCoreTypes() CoreTypesResultDTO {
 	return CoreTypesResultDTO{CoreTypes: dtos}
 }
 
+// GetCoreTypeFavorites returns the core types the user has starred.
+func (a *App) GetCoreTypeFavorites() ([]string, error) {
+	return hardware.LoadFavorites(config.GetHardwareFavoritesPath())
+}
+
+// SetCoreTypeFavorite stars or unstars a core type and returns the starred
+// core types after the change.
+func (a *App) SetCoreTypeFavorite(code string, favorite bool) ([]string, error) {
+	return hardware.SetFavorite(config.GetHardwareFavoritesPath(), code, favorite)
+}
+
 // GetAnalysisCodes returns available software analysis codes.
 func (a *App) GetAnalysisCodes(search string) AnalysisCodesResultDTO {
 	if a.engine == nil {
@@ -378,7 +464,7 @@ func (a *App) GetAnalysisCodes(search string) AnalysisCodesResultDTO {
 	// Emit scan start event
 	a.emitScanProgress("software", 0, 0, false, false, "")
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.PaginatedAPITimeout)
+	ctx, cancel := a.apiContext(config.APICatalog)
 	defer cancel()
 
 	analyses, err := a.engine.GetAnalyses(ctx)
@@ -446,7 +532,7 @@ func (a *App) GetAutomations() AutomationsResultDTO {
 		return AutomationsResultDTO{Error: "engine not initialized"}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.PaginatedAPITimeout)
+	ctx, cancel := a.apiContext(config.APICatalog)
 	defer cancel()
 
 	automations, err := a.engine.API().ListAutomations(ctx)
@@ -487,17 +573,39 @@ func (a *App) StartBulkRunWithOptions(jobs []JobSpecDTO, opts PURRunOptionsDTO)
 	}
 
 	runID := fmt.Sprintf("run_%d", time.Now().UnixNano())
-	stateFile := generateStateFilePath(runID)
+	stateFile := a.generateStateFilePath(runID)
 
 	jobSpecs := make([]models.JobSpec, len(jobs))
 	for i, job := range jobs {
 		jobSpecs[i] = dtoToJobSpec(job)
 	}
 
-	if err := a.engine.StartRun(runID, stateFile, len(jobs)); err != nil {
+	// Array specs run as their members, which get the state rows
+	jobSpecs, err := jobarray.Expand(jobSpecs)
+	if err != nil {
+		return "", err
+	}
+
+	// A recent run of the same jobs and inputs (duplicate_run_policy) is
+	// checked before names are changed
+	fingerprint, err := a.engine.CheckDuplicateRun(jobSpecs)
+	if err != nil {
 		return "", err
 	}
 
+	// Duplicate job names (job_name_policy) are resolved before the state
+	// rows, which are keyed by name, are created
+	nameCtx, nameCancel := a.apiContext(config.APIListing)
+	defer nameCancel()
+	if err := a.engine.ApplyJobNamePolicy(nameCtx, jobSpecs, runID); err != nil {
+		return "", err
+	}
+
+	if err := a.engine.StartRun(runID, stateFile, len(jobSpecs)); err != nil {
+		return "", err
+	}
+	a.engine.RecordRun(fingerprint, runID, stateFile, len(jobSpecs))
+
 	// Pre-populate state
 	if st := a.engine.GetState(); st != nil {
 		for i, job := range jobSpecs {
@@ -542,7 +650,7 @@ func (a *App) StartBulkRun(jobs []JobSpecDTO) (string, error) {
 
 	// Generate run ID and state file
 	runID := fmt.Sprintf("run_%d", time.Now().UnixNano())
-	stateFile := generateStateFilePath(runID)
+	stateFile := a.generateStateFilePath(runID)
 
 	// Convert DTOs to job specs
 	jobSpecs := make([]models.JobSpec, len(jobs))
@@ -550,10 +658,32 @@ func (a *App) StartBulkRun(jobs []JobSpecDTO) (string, error) {
 		jobSpecs[i] = dtoToJobSpec(job)
 	}
 
-	if err := a.engine.StartRun(runID, stateFile, len(jobs)); err != nil {
+	// Array specs run as their members, which get the state rows
+	jobSpecs, err := jobarray.Expand(jobSpecs)
+	if err != nil {
+		return "", err
+	}
+
+	// A recent run of the same jobs and inputs (duplicate_run_policy) is
+	// checked before names are changed
+	fingerprint, err := a.engine.CheckDuplicateRun(jobSpecs)
+	if err != nil {
 		return "", err
 	}
 
+	// Duplicate job names (job_name_policy) are resolved before the state
+	// rows, which are keyed by name, are created
+	nameCtx, nameCancel := a.apiContext(config.APIListing)
+	defer nameCancel()
+	if err := a.engine.ApplyJobNamePolicy(nameCtx, jobSpecs, runID); err != nil {
+		return "", err
+	}
+
+	if err := a.engine.StartRun(runID, stateFile, len(jobSpecs)); err != nil {
+		return "", err
+	}
+	a.engine.RecordRun(fingerprint, runID, stateFile, len(jobSpecs))
+
 	// Pre-populate all jobs as "pending" so the GUI sees them immediately
 	// (before the pipeline goroutine starts processing).
 	if st := a.engine.GetState(); st != nil {
@@ -615,7 +745,7 @@ func (a *App) StartSingleJob(input SingleJobInputDTO) (string, error) {
 	}
 
 	runID := fmt.Sprintf("single_%d", time.Now().UnixNano())
-	stateFile := generateStateFilePath(runID)
+	stateFile := a.generateStateFilePath(runID)
 
 	// Convert DTO to job spec
 	jobSpec := dtoToJobSpec(input.Job)
@@ -686,10 +816,27 @@ func (a *App) StartSingleJob(input SingleJobInputDTO) (string, error) {
 				return
 			}
 
-			if apiClient := a.engine.API(); apiClient != nil {
+			apiClient := a.engine.API()
+			if apiClient != nil {
 				inthttp.WarmupProxyIfNeeded(ctx, apiClient.GetConfig())
 			}
 
+			// Input files go to the job's destination folder like PUR tars
+			destFolderID := ""
+			if strings.TrimSpace(jobSpec.DestinationFolder) != "" {
+				if apiClient == nil {
+					a.failSingleJob(jobSpec.JobName, "Cannot prepare destination folder: not connected")
+					return
+				}
+				var folderErr error
+				destFolderID, folderErr = folder.EnsureLibraryPath(ctx, apiClient, folder.NewFolderCache(), jobSpec.DestinationFolder)
+				if folderErr != nil {
+					wailsLogger.Error().Err(folderErr).Str("folder", jobSpec.DestinationFolder).Msg("Destination folder failed")
+					a.failSingleJob(jobSpec.JobName, fmt.Sprintf("Failed to prepare destination folder: %v", folderErr))
+					return
+				}
+			}
+
 			jobBatchID := fmt.Sprintf("job_%d", time.Now().UnixNano())
 			jobBatchLabel := fmt.Sprintf("Job: %s", jobSpec.JobName)
 
@@ -711,6 +858,7 @@ func (a *App) StartSingleJob(input SingleJobInputDTO) (string, error) {
 				cloudFile, uploadErr := ts.UploadFileSync(ctx, services.TransferRequest{
 					Type:        services.TransferTypeUpload,
 					Source:      filePath,
+					Dest:        destFolderID,
 					Name:        filepath.Base(filePath),
 					SourceLabel: services.SourceLabelSingleJob,
 					BatchID:     jobBatchID,
@@ -937,6 +1085,9 @@ func (a *App) GetJobRows() []JobRowDTO {
 			JobID:          state.JobID,
 			Progress:       0, // Transient - provided via events
 			Error:          state.ErrorMessage,
+			Warnings:       state.WarningList(),
+			OutputStatus:   state.OutputStatus,
+			ArrayName:      state.ArrayName,
 		}
 	}
 	return rows
@@ -960,18 +1111,14 @@ func (a *App) ResetRun() {
 // RunHistoryEntryDTO represents a historical run entry.
 type RunHistoryEntryDTO struct {
 	RunID    string `json:"runId"`
-	RunType  string `json:"runType"`  // "pur" or "single", derived from ID prefix
+	RunType  string `json:"runType"` // "pur" or "single", derived from ID prefix
 	ModTime  string `json:"modTime"`
 	JobCount int    `json:"jobCount"`
 }
 
 // GetRunHistory lists historical run state files, sorted by modification time (newest first).
 func (a *App) GetRunHistory() []RunHistoryEntryDTO {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return []RunHistoryEntryDTO{}
-	}
-	stateDir := filepath.Join(homeDir, ".rescale-int", "states")
+	stateDir := config.StateDirectory(a.config)
 
 	entries, err := os.ReadDir(stateDir)
 	if err != nil {
@@ -997,15 +1144,11 @@ func (a *App) GetRunHistory() []RunHistoryEntryDTO {
 			runType = "single"
 		}
 
-		// Count job rows by counting non-header lines
-		filePath := filepath.Join(stateDir, entry.Name())
+		// Count job rows; a run may be writing the file, so query it read-only
 		jobCount := 0
-		data, err := os.ReadFile(filePath)
-		if err == nil {
-			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
-			if len(lines) > 1 {
-				jobCount = len(lines) - 1 // Subtract header row
-			}
+		if r, err := state.OpenReader(filepath.Join(stateDir, entry.Name())); err == nil {
+			jobCount, _ = r.Count()
+			r.Close()
 		}
 		// Tolerate malformed files: skip if we can't parse, don't fail the list
 
@@ -1031,6 +1174,138 @@ func (a *App) GetRunHistory() []RunHistoryEntryDTO {
 	return results
 }
 
+// StopJob stops a running or queued job on the platform and returns the
+// job's status after the request. The engine publishes the new status so
+// the run monitor updates without waiting for the next poll.
+func (a *App) StopJob(jobID string) (string, error) {
+	if a.engine == nil {
+		return "", ErrNoEngine
+	}
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return "", fmt.Errorf("job ID is required")
+	}
+	ctx, cancel := a.apiContext(config.APIMutation)
+	defer cancel()
+	status, err := a.engine.StopJob(ctx, jobID)
+	if err != nil {
+		return "", fmt.Errorf("failed to stop job %s: %w", jobID, err)
+	}
+	return status, nil
+}
+
+// GetRunReportPath returns the HTML report path for a run, or "" if the run
+// has no report yet.
+func (a *App) GetRunReportPath(runID string) (string, error) {
+	clean := filepath.Base(runID)
+	if clean != runID || strings.Contains(runID, "..") {
+		return "", fmt.Errorf("invalid run ID: %s", runID)
+	}
+	htmlPath, _ := report.DefaultPaths(a.generateStateFilePath(clean))
+	if _, err := os.Stat(htmlPath); err != nil {
+		return "", nil
+	}
+	return htmlPath, nil
+}
+
+// OpenRunReport opens a run's HTML summary report in the default browser.
+func (a *App) OpenRunReport(runID string) error {
+	if a.ctx == nil {
+		return fmt.Errorf(appNotReadyError)
+	}
+	htmlPath, err := a.GetRunReportPath(runID)
+	if err != nil {
+		return err
+	}
+	if htmlPath == "" {
+		return fmt.Errorf("no report found for run: %s", runID)
+	}
+	runtime.BrowserOpenURL(a.ctx, fileURL(htmlPath))
+	return nil
+}
+
+// ExportJobsTable asks where to save the given Jobs table rows and writes
+// them as CSV or XLSX (format "csv" or "xlsx"), adding stage durations from
+// the current run. Returns the saved path, or "" if the user cancelled.
+func (a *App) ExportJobsTable(rows []JobExportRowDTO, format string) (path string, err error) {
+	format, err = report.ExportFormat(format, "")
+	if err != nil {
+		return "", err
+	}
+	if !dialogMu.TryLock() {
+		return "", fmt.Errorf(dialogBusyMessage)
+	}
+	defer dialogMu.Unlock()
+	defer recoverDialogPanic("ExportJobsTable", &err)
+	if a.ctx == nil {
+		wailsLogger.Error().Str("binding", "ExportJobsTable").Msg("dialog binding invoked before context ready")
+		return "", fmt.Errorf(appNotReadyError)
+	}
+
+	filter := runtime.FileFilter{DisplayName: "CSV Files (*.csv)", Pattern: "*.csv"}
+	if format == report.FormatXLSX {
+		filter = runtime.FileFilter{DisplayName: "Excel Workbooks (*.xlsx)", Pattern: "*.xlsx"}
+	}
+	path, err = portalAwareSaveFile(a.ctx, "ExportJobsTable", runtime.SaveDialogOptions{
+		DefaultFilename: fmt.Sprintf("interlink-jobs-%s.%s", time.Now().Format("2006-01-02T15-04-05"), format),
+		Title:           "Export Jobs Table",
+		Filters:         []runtime.FileFilter{filter, {DisplayName: "All Files (*.*)", Pattern: "*.*"}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("save dialog: %w", err)
+	}
+	if path == "" {
+		return "", nil // User cancelled
+	}
+
+	if err := report.WriteTableFile(path, format, a.jobExportRows(rows)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// jobExportRows converts GUI rows to report rows, with 1-based indexes to
+// match the state file and run report.
+func (a *App) jobExportRows(rows []JobExportRowDTO) []report.TableRow {
+	var durations map[int]map[string]time.Duration
+	platformURL := ""
+	if a.engine != nil {
+		durations = a.engine.StageDurations()
+		if cfg := a.engine.GetConfig(); cfg != nil {
+			platformURL = cfg.APIBaseURL
+		}
+	}
+
+	out := make([]report.TableRow, 0, len(rows))
+	for _, r := range rows {
+		row := report.TableRow{
+			JobEntry: report.JobEntry{
+				Index:          r.Index + 1,
+				JobName:        r.JobName,
+				Directory:      r.Directory,
+				Status:         r.Status,
+				TarStatus:      r.TarStatus,
+				UploadStatus:   r.UploadStatus,
+				SubmitStatus:   r.SubmitStatus,
+				JobID:          r.JobID,
+				Error:          r.Error,
+				StageDurations: durations[r.Index+1],
+			},
+			CreateStatus:   r.CreateStatus,
+			PlatformStatus: r.PlatformStatus,
+			SubStatus:      r.SubStatus,
+		}
+		if r.JobID != "" {
+			row.JobURL = report.JobURL(platformURL, r.JobID)
+		}
+		if t, err := time.Parse(time.RFC3339, r.SubStatusSince); err == nil {
+			row.SubStatusSince = t
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
 // GetHistoricalJobRows loads job rows from a historical state file.
 func (a *App) GetHistoricalJobRows(runID string) ([]JobRowDTO, error) {
 	// Path traversal sanitization (C8)
@@ -1039,57 +1314,156 @@ func (a *App) GetHistoricalJobRows(runID string) ([]JobRowDTO, error) {
 		return nil, fmt.Errorf("invalid run ID: %s", runID)
 	}
 
-	stateFile := generateStateFilePath(clean)
+	stateFile := a.generateStateFilePath(clean)
 
-	data, err := os.ReadFile(stateFile)
+	r, err := state.OpenReader(stateFile)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("state file not found for run: %s", runID)
 		}
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
-	if len(lines) <= 1 {
-		return []JobRowDTO{}, nil // Header only or empty
+	defer r.Close()
+	states, err := r.All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
-	// Parse CSV-like state file: header + data rows
-	// State file format: index,jobName,directory,tarStatus,uploadStatus,uploadProgress,submitStatus,jobId,errorMessage
-	var rows []JobRowDTO
-	for i, line := range lines[1:] { // Skip header
-		fields := strings.Split(line, ",")
-		if len(fields) < 9 {
-			continue // Skip malformed rows
-		}
-
-		uploadProgress := 0.0
-		if len(fields) > 5 {
-			fmt.Sscanf(fields[5], "%f", &uploadProgress)
-		}
-
+	rows := make([]JobRowDTO, 0, len(states))
+	for _, st := range states {
 		rows = append(rows, JobRowDTO{
-			Index:          i,
-			JobName:        fields[1],
-			Directory:      fields[2],
-			TarStatus:      fields[3],
-			UploadStatus:   fields[4],
-			UploadProgress: uploadProgress,
+			Index:          st.Index,
+			JobName:        st.JobName,
+			Directory:      st.Directory,
+			TarStatus:      st.TarStatus,
+			UploadStatus:   st.UploadStatus,
+			UploadProgress: st.UploadProgress,
 			CreateStatus:   "",
-			SubmitStatus:   fields[6],
-			Status:         fields[6], // Use submit status as overall
-			JobID:          fields[7],
+			SubmitStatus:   st.SubmitStatus,
+			Status:         st.SubmitStatus, // Use submit status as overall
+			JobID:          st.JobID,
 			Progress:       0,
-			Error:          fields[8],
+			Error:          st.ErrorMessage,
+			Warnings:       st.WarningList(),
+			OutputStatus:   st.OutputStatus,
+			ArrayName:      st.ArrayName,
 		})
 	}
 
 	return rows, nil
 }
 
-// ValidateJobSpec validates a job specification.
+// ValidateJobSpec validates a job specification, including the safety checks
+// in the configured job_validation_mode.
 func (a *App) ValidateJobSpec(job JobSpecDTO) []string {
-	return validation.ValidateJobSpec(dtoToJobSpec(job))
+	mode := validation.SafetyPermissive
+	if a.config != nil {
+		mode = a.config.JobValidationMode
+	}
+	errs, _ := validation.ValidateJobSpecMode(dtoToJobSpec(job), mode)
+	return errs
+}
+
+// TarEntryDTO is one file in a job's input tar.
+type TarEntryDTO struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// TarContentsDTO lists a job's input tar for the review table.
+type TarContentsDTO struct {
+	Entries     []TarEntryDTO `json:"entries"`
+	TotalSize   int64         `json:"totalSize"`
+	FromArchive bool          `json:"fromArchive"` // false = previewed from the directory
+}
+
+// GetJobTarContents lists the files in a job's input tar. Jobs whose tar
+// stage has succeeded in the current run list the archive itself; others are
+// previewed from the directory with the configured include/exclude/flatten
+// settings.
+func (a *App) GetJobTarContents(job JobSpecDTO) (TarContentsDTO, error) {
+	cfg := a.config
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	var jobState *models.JobState
+	if a.engine != nil {
+		if st := a.engine.GetState(); st != nil {
+			for _, s := range st.GetAllStates() {
+				if s.Directory == job.Directory && s.JobName == job.JobName {
+					jobState = s
+					break
+				}
+			}
+		}
+	}
+
+	entries, fromArchive, err := pipeline.JobContents(cfg, dtoToJobSpec(job), jobState, false)
+	if err != nil {
+		return TarContentsDTO{}, err
+	}
+	result := TarContentsDTO{Entries: make([]TarEntryDTO, len(entries)), FromArchive: fromArchive}
+	for i, e := range entries {
+		result.Entries[i] = TarEntryDTO{Name: e.Name, Size: e.Size}
+		result.TotalSize += e.Size
+	}
+	return result, nil
+}
+
+// RuntimePredictionDTO suggests walltime and cores for a template from how
+// long earlier jobs of the same template ran. Hours and cores are 10th,
+// 50th and 90th percentiles.
+type RuntimePredictionDTO struct {
+	Available              bool    `json:"available"` // false = no completed jobs of this template
+	Samples                int     `json:"samples"`
+	Confidence             string  `json:"confidence"` // low, medium or high
+	RuntimeLowHours        float64 `json:"runtimeLowHours"`
+	RuntimeTypicalHours    float64 `json:"runtimeTypicalHours"`
+	RuntimeHighHours       float64 `json:"runtimeHighHours"`
+	SuggestedWalltimeHours float64 `json:"suggestedWalltimeHours"`
+	CoresLow               int     `json:"coresLow"`
+	CoresHigh              int     `json:"coresHigh"`
+	SuggestedCores         int     `json:"suggestedCores"`
+	CoreType               string  `json:"coreType"`
+	Error                  string  `json:"error,omitempty"`
+}
+
+// PredictJobRuntime predicts runtime, walltime and cores for a template from
+// earlier PUR jobs with the same software, tags and metadata keys. The
+// template's own walltime and cores don't affect the prediction, so the
+// editor compares them itself as they change.
+func (a *App) PredictJobRuntime(job JobSpecDTO) RuntimePredictionDTO {
+	var result RuntimePredictionDTO
+	if a.engine == nil || a.engine.API() == nil || a.config == nil {
+		result.Error = "engine not initialized"
+		return result
+	}
+
+	ctx, cancel := a.apiContext(config.APIListing)
+	defer cancel()
+
+	predictor := runtimes.NewPredictor(config.GetJobHistoryPath(a.config.APIBaseURL), a.engine.API())
+	p, err := predictor.Predict(ctx, dtoToJobSpec(job))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if p == nil {
+		return result
+	}
+	result.Available = true
+	result.Samples = p.Samples
+	result.Confidence = p.Confidence
+	result.RuntimeLowHours = p.RuntimeHours.Low
+	result.RuntimeTypicalHours = p.RuntimeHours.Typical
+	result.RuntimeHighHours = p.RuntimeHours.High
+	result.SuggestedWalltimeHours = p.SuggestedWalltimeHours
+	result.CoresLow = int(p.Cores.Low)
+	result.CoresHigh = int(p.Cores.High)
+	result.SuggestedCores = p.SuggestedCores
+	result.CoreType = p.CoreType
+	return result
 }
 
 // CommandPreviewDTO shows how a command varies for a directory.
@@ -1166,6 +1540,17 @@ func jobSpecToDTO(j models.JobSpec) JobSpecDTO {
 		Automations:           j.Automations,
 		InputFiles:            j.InputFiles,
 		TarSubpath:            j.TarSubpath,
+		Priority:              j.Priority,
+		DestinationFolder:     j.DestinationFolder,
+		Metadata:              j.Metadata,
+		ContinuationCommand:   j.ContinuationCommand,
+		OutputPatterns:        j.OutputPatterns,
+		DependsOn:             j.DependsOn,
+		Array:                 j.Array,
+		NoTextNormalize:       j.NoTextNormalize,
+		EnvVars:               j.EnvVars,
+		UseRescaleLicense:     j.UseRescaleLicense,
+		ClusterID:             j.ClusterID,
 	}
 }
 
@@ -1193,16 +1578,24 @@ func dtoToJobSpec(j JobSpecDTO) models.JobSpec {
 		Automations:           j.Automations,
 		InputFiles:            j.InputFiles,
 		TarSubpath:            j.TarSubpath,
+		Priority:              j.Priority,
+		DestinationFolder:     j.DestinationFolder,
+		Metadata:              j.Metadata,
+		ContinuationCommand:   j.ContinuationCommand,
+		OutputPatterns:        j.OutputPatterns,
+		DependsOn:             j.DependsOn,
+		Array:                 j.Array,
+		NoTextNormalize:       j.NoTextNormalize,
+		EnvVars:               j.EnvVars,
+		UseRescaleLicense:     j.UseRescaleLicense,
+		ClusterID:             j.ClusterID,
 	}
 }
 
-// generateStateFilePath creates a unique state file path.
-func generateStateFilePath(runID string) string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "."
-	}
-	stateDir := filepath.Join(homeDir, ".rescale-int", "states")
+// generateStateFilePath creates a unique state file path under the
+// configured state directory.
+func (a *App) generateStateFilePath(runID string) string {
+	stateDir := config.StateDirectory(a.config)
 	os.MkdirAll(stateDir, 0755)
 	return filepath.Join(stateDir, fmt.Sprintf("%s.state", runID))
 }
@@ -1225,6 +1618,66 @@ func (a *App) LoadJobsFromCSV(path string) ([]JobSpecDTO, error) {
 	return dtos, nil
 }
 
+// JobFieldChangeDTO is one differing field of a job in DiffJobFiles.
+type JobFieldChangeDTO struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Risk  string `json:"risk"` // non-empty for risky changes (core type, walltime reduction)
+}
+
+// JobChangeDTO lists the field differences of one job.
+type JobChangeDTO struct {
+	Key     string              `json:"key"`
+	Risky   bool                `json:"risky"`
+	Changes []JobFieldChangeDTO `json:"changes"`
+}
+
+// JobDiffDTO is the result of comparing two jobs files.
+type JobDiffDTO struct {
+	CountA     int            `json:"countA"`
+	CountB     int            `json:"countB"`
+	Added      []string       `json:"added"`
+	Removed    []string       `json:"removed"`
+	Changed    []JobChangeDTO `json:"changed"`
+	Unchanged  int            `json:"unchanged"`
+	RiskyCount int            `json:"riskyCount"`
+}
+
+// DiffJobFiles compares two jobs files (CSV or JSON) field by field.
+func (a *App) DiffJobFiles(pathA, pathB string) (JobDiffDTO, error) {
+	if pathA == "" || pathB == "" {
+		return JobDiffDTO{}, fmt.Errorf("two file paths are required")
+	}
+	jobsA, err := config.LoadJobs(pathA)
+	if err != nil {
+		return JobDiffDTO{}, fmt.Errorf("failed to load %s: %w", filepath.Base(pathA), err)
+	}
+	jobsB, err := config.LoadJobs(pathB)
+	if err != nil {
+		return JobDiffDTO{}, fmt.Errorf("failed to load %s: %w", filepath.Base(pathB), err)
+	}
+
+	res := jobdiff.Compare(jobsA, jobsB)
+	dto := JobDiffDTO{
+		CountA:     len(jobsA),
+		CountB:     len(jobsB),
+		Added:      res.Added,
+		Removed:    res.Removed,
+		Changed:    make([]JobChangeDTO, len(res.Changed)),
+		Unchanged:  res.Unchanged,
+		RiskyCount: res.RiskyCount(),
+	}
+	for i, c := range res.Changed {
+		changes := make([]JobFieldChangeDTO, len(c.Changes))
+		for j, fc := range c.Changes {
+			changes[j] = JobFieldChangeDTO{Field: fc.Field, Old: fc.Old, New: fc.New, Risk: fc.Risk}
+		}
+		dto.Changed[i] = JobChangeDTO{Key: c.Key, Risky: c.Risky(), Changes: changes}
+	}
+	return dto, nil
+}
+
 // SaveJobsToCSV saves job specifications to a CSV file.
 func (a *App) SaveJobsToCSV(path string, jobs []JobSpecDTO) error {
 	if path == "" {
@@ -1317,6 +1770,43 @@ func (a *App) LoadJobFromSGE(path string) (JobSpecDTO, error) {
 	return jobSpecToDTO(spec), nil
 }
 
+// JobImportResultDTO is a job spec rebuilt from an existing Rescale job.
+type JobImportResultDTO struct {
+	Job      JobSpecDTO `json:"job"`
+	Warnings []string   `json:"warnings"`
+}
+
+// ImportJobFromRescale rebuilds a job spec from an existing job, given its ID
+// or platform URL, for use as base job settings.
+func (a *App) ImportJobFromRescale(ref string) (JobImportResultDTO, error) {
+	if a.engine == nil || a.engine.API() == nil {
+		return JobImportResultDTO{}, fmt.Errorf("engine not initialized")
+	}
+	jobID, err := parser.ParseJobRef(ref)
+	if err != nil {
+		return JobImportResultDTO{}, err
+	}
+
+	ctx, cancel := a.apiContext(config.APIStatus)
+	defer cancel()
+
+	raw, err := a.engine.API().GetJobRaw(ctx, jobID)
+	if err != nil {
+		return JobImportResultDTO{}, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	spec, warnings, err := parser.RescaleJobToJobSpec(raw)
+	if err != nil {
+		return JobImportResultDTO{}, err
+	}
+	if tags, err := a.engine.API().GetJobTags(ctx, jobID); err == nil {
+		spec.Tags = tags
+	} else {
+		warnings = append(warnings, fmt.Sprintf("could not fetch tags: %v", err))
+	}
+
+	return JobImportResultDTO{Job: jobSpecToDTO(spec), Warnings: warnings}, nil
+}
+
 // SaveJobToSGE saves a job specification as an SGE script file.
 func (a *App) SaveJobToSGE(path string, job JobSpecDTO) error {
 	if path == "" {
@@ -1387,11 +1877,10 @@ type TemplateInfoDTO struct {
 
 // getTemplatesDir returns the path to the templates directory, creating it if needed.
 func getTemplatesDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
+	templatesDir := config.TemplateDirectory()
+	if templatesDir == "" {
+		return "", fmt.Errorf("cannot determine home directory")
 	}
-	templatesDir := filepath.Join(homeDir, ".config", "rescale", "templates")
 	if err := os.MkdirAll(templatesDir, 0755); err != nil {
 		return "", err
 	}
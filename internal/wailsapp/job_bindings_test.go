@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/pur/state"
 )
 
 // TestScanDirectoryNoEngine verifies ScanDirectory returns error when engine is nil.
@@ -450,6 +453,30 @@ func TestGetHistoricalJobRows_MissingFile(t *testing.T) {
 	}
 }
 
+// TestGetHistoricalJobRows_StateStore verifies history reads runs saved by
+// the state manager.
+func TestGetHistoricalJobRows_StateStore(t *testing.T) {
+	app := &App{config: &config.Config{StateDir: t.TempDir()}}
+	m := state.NewManager(app.generateStateFilePath("run1"))
+	st := m.InitializeState(1, "wing", "/data/wing")
+	st.TarStatus, st.UploadStatus, st.SubmitStatus, st.JobID = "success", "success", "success", "job-1"
+	m.InitializeState(2, "tail", "/data/tail").ErrorMessage = "upload failed"
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := app.GetHistoricalJobRows("run1")
+	if err != nil {
+		t.Fatalf("GetHistoricalJobRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].JobID != "job-1" || rows[0].Status != "success" || rows[1].Error != "upload failed" {
+		t.Errorf("rows = %+v", rows)
+	}
+	if history := app.GetRunHistory(); len(history) != 1 || history[0].JobCount != 2 {
+		t.Errorf("GetRunHistory() = %+v, want run1 with 2 jobs", history)
+	}
+}
+
 func TestGetRunHistory_EmptyDir(t *testing.T) {
 	app := &App{}
 	// This should return empty slice, not panic, even if states dir doesn't exist
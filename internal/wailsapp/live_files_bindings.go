@@ -0,0 +1,218 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/rescale/rescale-int/internal/api"
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/viewer"
+	"github.com/rescale/rescale-int/internal/watch"
+)
+
+// LiveFileDTO is a file or directory in a running job's work directory.
+type LiveFileDTO struct {
+	Path         string `json:"path"` // Relative to the work directory
+	Name         string `json:"name"`
+	IsDirectory  bool   `json:"isDirectory"`
+	Size         int64  `json:"size"`
+	DateModified string `json:"dateModified"`
+}
+
+// LiveFilesDTO lists one directory of a running job for the Live Files
+// panel. Running is false when the job has no active cluster (queued or
+// finished); Supported is false when the platform offers no in-job access.
+type LiveFilesDTO struct {
+	JobID         string        `json:"jobId"`
+	Status        string        `json:"status"`
+	Running       bool          `json:"running"`
+	Supported     bool          `json:"supported"`
+	Path          string        `json:"path"` // Directory listed ("" = work directory)
+	Entries       []LiveFileDTO `json:"entries"`
+	MaxUploadSize int64         `json:"maxUploadSize"`
+}
+
+// liveRun returns the API client and active run of a job, or "" as the run
+// when the job has no running cluster.
+func (a *App) liveRun(ctx context.Context, jobID string) (*api.Client, string, error) {
+	if a.engine == nil || a.engine.API() == nil {
+		return nil, "", ErrNoAPIClient
+	}
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, "", fmt.Errorf("job ID is required")
+	}
+	apiClient := a.engine.API()
+	runID, err := apiClient.ActiveRunID(ctx, jobID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get runs of job %s: %w", jobID, err)
+	}
+	return apiClient, runID, nil
+}
+
+// GetLiveFiles lists a directory ("" for the work directory) of a running
+// job. A job without a running cluster, or on a platform without in-job
+// access, is reported in the result rather than as an error.
+func (a *App) GetLiveFiles(jobID, dir string) (LiveFilesDTO, error) {
+	result := LiveFilesDTO{JobID: strings.TrimSpace(jobID), Entries: []LiveFileDTO{}, MaxUploadSize: constants.MaxLiveUploadSize}
+	dir, err := api.CleanLivePath(dir)
+	if err != nil {
+		return result, err
+	}
+	result.Path = dir
+
+	ctx, cancel := a.apiContext(config.APIListing)
+	defer cancel()
+	apiClient, runID, err := a.liveRun(ctx, jobID)
+	if err != nil {
+		return result, err
+	}
+	if statuses, err := apiClient.GetJobStatuses(ctx, result.JobID); err == nil {
+		if latest, _, ok := watch.LatestStatus(statuses); ok {
+			result.Status = latest.Status
+		}
+	}
+	if runID == "" {
+		return result, nil
+	}
+	result.Running = true
+
+	entries, err := apiClient.ListLiveFiles(ctx, result.JobID, runID, dir)
+	if errors.Is(err, api.ErrLiveFilesUnsupported) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Supported = true
+	for _, e := range entries {
+		result.Entries = append(result.Entries, LiveFileDTO{
+			Path:         e.Path,
+			Name:         e.Name,
+			IsDirectory:  e.IsDirectory,
+			Size:         e.Size,
+			DateModified: e.DateModified,
+		})
+	}
+	sort.Slice(result.Entries, func(i, j int) bool {
+		if result.Entries[i].IsDirectory != result.Entries[j].IsDirectory {
+			return result.Entries[i].IsDirectory
+		}
+		return result.Entries[i].Name < result.Entries[j].Name
+	})
+	return result, nil
+}
+
+// DownloadLiveFile asks where to save a file of a running job's work
+// directory and downloads its current contents there. Returns the saved
+// path, or "" if the user cancelled.
+func (a *App) DownloadLiveFile(jobID, remotePath string) (savedPath string, err error) {
+	if err := viewer.Check("save live file"); err != nil {
+		return "", err
+	}
+	remotePath, err = api.CleanLivePath(remotePath)
+	if err != nil {
+		return "", err
+	}
+	if remotePath == "" {
+		return "", fmt.Errorf("file path is required")
+	}
+
+	if !dialogMu.TryLock() {
+		return "", fmt.Errorf(dialogBusyMessage)
+	}
+	defer dialogMu.Unlock()
+	defer recoverDialogPanic("DownloadLiveFile", &err)
+	if a.ctx == nil {
+		wailsLogger.Error().Str("binding", "DownloadLiveFile").Msg("dialog binding invoked before context ready")
+		return "", fmt.Errorf(appNotReadyError)
+	}
+
+	savedPath, err = portalAwareSaveFile(a.ctx, "DownloadLiveFile", runtime.SaveDialogOptions{
+		DefaultFilename: path.Base(remotePath),
+		Title:           "Save Live File",
+	})
+	if err != nil {
+		return "", fmt.Errorf("save dialog: %w", err)
+	}
+	if savedPath == "" {
+		return "", nil // User cancelled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.GUIOperationTimeout)
+	defer cancel()
+	apiClient, runID, err := a.liveRun(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	if runID == "" {
+		return "", fmt.Errorf("job %s is no longer running", jobID)
+	}
+
+	// Download next to the destination so a failed copy leaves no partial file
+	tmp := savedPath + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	_, err = apiClient.DownloadLiveFile(ctx, strings.TrimSpace(jobID), runID, remotePath, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, savedPath)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return savedPath, nil
+}
+
+// UploadLiveFile uploads a small local file into a running job's work
+// directory at remotePath, or under the file's own name in the directory
+// remotePath names when it is "" or ends in "/". An existing file is
+// replaced only with overwrite.
+func (a *App) UploadLiveFile(jobID, localPath, remotePath string, overwrite bool) (LiveFileDTO, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return LiveFileDTO{}, fmt.Errorf("cannot read %s: %w", localPath, err)
+	}
+	if info.IsDir() {
+		return LiveFileDTO{}, fmt.Errorf("%s is a directory; only single files can be uploaded", localPath)
+	}
+	if info.Size() > constants.MaxLiveUploadSize {
+		return LiveFileDTO{}, fmt.Errorf("%s is larger than the %d MB limit for live uploads", info.Name(), constants.MaxLiveUploadSize/(1024*1024))
+	}
+	if remotePath == "" || strings.HasSuffix(remotePath, "/") {
+		remotePath += info.Name()
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return LiveFileDTO{}, fmt.Errorf("cannot read %s: %w", localPath, err)
+	}
+
+	ctx, cancel := a.apiContext(config.APIMutation)
+	defer cancel()
+	apiClient, runID, err := a.liveRun(ctx, jobID)
+	if err != nil {
+		return LiveFileDTO{}, err
+	}
+	if runID == "" {
+		return LiveFileDTO{}, fmt.Errorf("job %s is no longer running", jobID)
+	}
+	entry, err := apiClient.UploadLiveFile(ctx, strings.TrimSpace(jobID), runID, remotePath, data, overwrite)
+	if err != nil {
+		return LiveFileDTO{}, err
+	}
+	a.logInfo("live-files", fmt.Sprintf("Uploaded %s to %s of job %s", info.Name(), entry.Path, jobID))
+	return LiveFileDTO{Path: entry.Path, Name: entry.Name, Size: entry.Size, DateModified: entry.DateModified}, nil
+}
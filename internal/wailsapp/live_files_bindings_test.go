@@ -0,0 +1,49 @@
+package wailsapp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// TestGetLiveFilesNoAPIClient verifies the Live Files panel gets an error,
+// not a panic, before the engine is ready.
+func TestGetLiveFilesNoAPIClient(t *testing.T) {
+	app := &App{config: &config.Config{}} // engine is nil
+
+	_, err := app.GetLiveFiles("XxYyZz", "")
+	if !errors.Is(err, ErrNoAPIClient) {
+		t.Fatalf("expected ErrNoAPIClient, got: %v", err)
+	}
+}
+
+// TestGetLiveFilesRejectsEscapingPath verifies a directory outside the work
+// directory is rejected before any API call.
+func TestGetLiveFilesRejectsEscapingPath(t *testing.T) {
+	app := &App{config: &config.Config{}}
+
+	_, err := app.GetLiveFiles("XxYyZz", "../other")
+	if err == nil || !strings.Contains(err.Error(), "work directory") {
+		t.Fatalf("expected path error, got: %v", err)
+	}
+}
+
+// TestUploadLiveFileTooLarge verifies files over the live upload limit are
+// rejected before any API call.
+func TestUploadLiveFileTooLarge(t *testing.T) {
+	app := &App{config: &config.Config{}}
+	path := filepath.Join(t.TempDir(), "big.dat")
+	if err := os.WriteFile(path, make([]byte, constants.MaxLiveUploadSize+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := app.UploadLiveFile("XxYyZz", path, "", false)
+	if err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Fatalf("expected size limit error, got: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package wailsapp
+
+import (
+	"time"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/netusage"
+)
+
+// usageRecordInterval is how often the session's network traffic is added
+// to the usage log.
+const usageRecordInterval = time.Minute
+
+// NetworkUsageDTO is the data sent and received on this machine, for the
+// footer usage widget. Today and month totals include CLI runs.
+type NetworkUsageDTO struct {
+	TodaySent       int64  `json:"todaySent"`
+	TodayReceived   int64  `json:"todayReceived"`
+	MonthSent       int64  `json:"monthSent"`
+	MonthReceived   int64  `json:"monthReceived"`
+	SessionSent     int64  `json:"sessionSent"`
+	SessionReceived int64  `json:"sessionReceived"`
+	Error           string `json:"error,omitempty"`
+}
+
+// GetNetworkUsage returns today's, this month's and this session's network
+// usage. The session's traffic is recorded first so the totals are current.
+func (a *App) GetNetworkUsage() NetworkUsageDTO {
+	session := netusage.Current()
+	result := NetworkUsageDTO{SessionSent: session.Sent, SessionReceived: session.Received}
+
+	if a.usageRecorder != nil {
+		if err := a.usageRecorder.Flush(); err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	now := time.Now()
+	entries, err := netusage.Load(config.GetNetworkUsagePath(), netusage.StartOfMonth(now))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	today := netusage.Sum(entries, netusage.StartOfDay(now))
+	month := netusage.Sum(entries, netusage.StartOfMonth(now))
+	result.TodaySent, result.TodayReceived = today.Sent, today.Received
+	result.MonthSent, result.MonthReceived = month.Sent, month.Received
+	return result
+}
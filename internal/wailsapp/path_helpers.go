@@ -2,6 +2,9 @@ package wailsapp
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
 
 	"github.com/rescale/rescale-int/internal/validation"
 )
@@ -15,3 +18,15 @@ func resolveSafeDownloadPath(relativePath, baseDir string) (string, error) {
 	}
 	return localPath, nil
 }
+
+// fileURL returns the file:// URL of an absolute local path. Windows drive
+// paths get the extra leading slash (file:///C:/...) and special characters
+// such as spaces are escaped.
+func fileURL(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	u := url.URL{Scheme: "file", Path: p}
+	return u.String()
+}
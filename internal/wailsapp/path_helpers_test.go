@@ -40,3 +40,16 @@ func TestResolveSafeDownloadPath_SimpleFilename(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, result)
 	}
 }
+
+func TestFileURL(t *testing.T) {
+	tests := map[string]string{
+		"/home/me/run report.html":        "file:///home/me/run%20report.html",
+		"C:/Users/me/pur_1_report.html":   "file:///C:/Users/me/pur_1_report.html",
+		"C:/Users/me/runs #2/report.html": "file:///C:/Users/me/runs%20%232/report.html",
+	}
+	for path, want := range tests {
+		if got := fileURL(path); got != want {
+			t.Errorf("fileURL(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
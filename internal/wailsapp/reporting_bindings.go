@@ -60,6 +60,10 @@ func (a *App) BuildErrorReport(requestJSON string) (string, error) {
 	report.WorkspaceName = req.WorkspaceName
 	report.WorkspaceID = req.WorkspaceID
 	report.PlatformURL = req.PlatformURL
+	report.NetworkTest = a.getLastNetworkTest()
+	if a.engine != nil {
+		report.Repro = a.engine.LastReproBundle()
+	}
 
 	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
@@ -0,0 +1,83 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/selfupdate"
+	"github.com/rescale/rescale-int/internal/version"
+)
+
+// SelfUpdateDTO is the JSON-safe result of a self-update check or install.
+type SelfUpdateDTO struct {
+	Enabled        bool   `json:"enabled"` // Configured and not disabled by policy
+	HasUpdate      bool   `json:"hasUpdate"`
+	CurrentVersion string `json:"currentVersion"`
+	LatestVersion  string `json:"latestVersion,omitempty"`
+	Installed      bool   `json:"installed"` // Swapped in; takes effect on restart
+	Error          string `json:"error,omitempty"`
+}
+
+// CheckSelfUpdate checks the configured distribution server for a newer
+// release of the running binary. Unlike CheckForUpdates (GitHub, badge
+// only), a release found here can be installed with InstallSelfUpdate.
+func (a *App) CheckSelfUpdate() SelfUpdateDTO {
+	result, _, _ := a.selfUpdateCheck()
+	return result
+}
+
+// InstallSelfUpdate downloads the newer release, verifies its signature and
+// replaces the running binary. The GUI keeps running the old binary until
+// it is restarted.
+func (a *App) InstallSelfUpdate() SelfUpdateDTO {
+	result, u, rel := a.selfUpdateCheck()
+	if result.Error != "" || !result.HasUpdate {
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.SelfUpdateTimeout)
+	defer cancel()
+
+	a.logInfo("update", fmt.Sprintf("Installing %s...", rel.Version))
+	if err := u.Apply(ctx, rel); err != nil {
+		a.logError("update", fmt.Sprintf("Self-update failed: %v", err))
+		result.Error = err.Error()
+		return result
+	}
+	a.logInfo("update", fmt.Sprintf("Installed %s (signature verified); restart to use it", rel.Version))
+	result.Installed = true
+	return result
+}
+
+func (a *App) selfUpdateCheck() (SelfUpdateDTO, *selfupdate.Updater, *selfupdate.Release) {
+	result := SelfUpdateDTO{CurrentVersion: version.Version}
+	if a.config == nil {
+		result.Error = "configuration not loaded"
+		return result, nil, nil
+	}
+
+	u, err := selfupdate.NewFromConfig(a.config)
+	if errors.Is(err, selfupdate.ErrDisabled) || errors.Is(err, selfupdate.ErrNotConfigured) {
+		return result, nil, nil
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil, nil
+	}
+	result.Enabled = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIContextTimeout)
+	defer cancel()
+
+	rel, err := u.Check(ctx)
+	if err != nil {
+		a.logWarn("update", fmt.Sprintf("Self-update check failed: %v", err))
+		result.Error = err.Error()
+		return result, nil, nil
+	}
+	result.HasUpdate = rel.Newer
+	result.LatestVersion = rel.Version
+	return result, u, rel
+}
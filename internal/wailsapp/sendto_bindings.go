@@ -0,0 +1,37 @@
+package wailsapp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rescale/rescale-int/internal/sendto"
+)
+
+// SendToMenuDTO reports the "Send to Rescale Interlink" context-menu entry.
+type SendToMenuDTO struct {
+	Supported bool   `json:"supported"`
+	Installed bool   `json:"installed"`
+	Label     string `json:"label"`
+}
+
+// GetSendToMenu reports whether the context-menu entry is installed.
+func (a *App) GetSendToMenu() SendToMenuDTO {
+	return SendToMenuDTO{
+		Supported: sendto.Supported(),
+		Installed: sendto.Installed(),
+		Label:     sendto.MenuLabel,
+	}
+}
+
+// SetSendToMenu adds or removes the context-menu entry for the current user.
+// Files sent through it are uploaded into send_to_folder while the GUI runs.
+func (a *App) SetSendToMenu(enabled bool) error {
+	if !enabled {
+		return sendto.Uninstall()
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	return sendto.Install(exe)
+}
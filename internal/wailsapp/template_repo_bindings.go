@@ -0,0 +1,167 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/constants"
+	"github.com/rescale/rescale-int/internal/templaterepo"
+)
+
+// TemplateRepoItemDTO is a template or recipe in the team template
+// repository, with the state of its import.
+type TemplateRepoItemDTO struct {
+	Kind             string `json:"kind"` // "template" or "recipe"
+	Name             string `json:"name"`
+	File             string `json:"file"`
+	Version          string `json:"version"`
+	InstalledVersion string `json:"installedVersion"` // Empty when not imported
+	Description      string `json:"description"`
+	Status           string `json:"status"` // templaterepo.Status* value
+	Pinned           bool   `json:"pinned"`
+	LocalPath        string `json:"localPath"`
+}
+
+// TemplateRepoDTO lists the team template repository (template_repo).
+type TemplateRepoDTO struct {
+	Configured bool                  `json:"configured"`
+	Source     string                `json:"source"`
+	Revision   string                `json:"revision"`
+	Warning    string                `json:"warning"` // Set when the cached copy could not be updated
+	Items      []TemplateRepoItemDTO `json:"items"`
+	Updates    int                   `json:"updates"` // Imports with a newer version upstream
+}
+
+// openTemplateRepo opens the configured template repository.
+func (a *App) openTemplateRepo(ctx context.Context) (*templaterepo.Repo, []templaterepo.Item, error) {
+	if a.config == nil || strings.TrimSpace(a.config.TemplateRepo) == "" {
+		return nil, nil, errors.New("no template repository configured; set one in Setup")
+	}
+	repo, err := templaterepo.Open(ctx, a.config.TemplateRepo, config.GetTemplateRepoCacheDir())
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := repo.Items()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, items, nil
+}
+
+// GetTemplateRepo lists the templates and recipes in the team template
+// repository and the status of those imported.
+func (a *App) GetTemplateRepo() (TemplateRepoDTO, error) {
+	if a.config == nil || strings.TrimSpace(a.config.TemplateRepo) == "" {
+		return TemplateRepoDTO{Items: []TemplateRepoItemDTO{}}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.GUIOperationTimeout)
+	defer cancel()
+
+	repo, items, err := a.openTemplateRepo(ctx)
+	if err != nil {
+		return TemplateRepoDTO{}, err
+	}
+	lib := templaterepo.DefaultLibrary()
+	checks, err := lib.Check(items)
+	if err != nil {
+		return TemplateRepoDTO{}, err
+	}
+
+	dto := TemplateRepoDTO{
+		Configured: true,
+		Source:     repo.Source.String(),
+		Revision:   repo.Revision,
+		Items:      make([]TemplateRepoItemDTO, 0, len(checks)),
+	}
+	if repo.FetchErr != nil {
+		dto.Warning = fmt.Sprintf("Could not update the template repository; showing revision %.12s: %v", repo.Revision, repo.FetchErr)
+	}
+	for _, c := range checks {
+		item := TemplateRepoItemDTO{
+			Kind:        c.Item.Kind,
+			Name:        c.Item.Name,
+			File:        c.Item.File,
+			Version:     c.Item.Version,
+			Description: c.Item.Description,
+			Status:      c.Status,
+		}
+		if c.Import != nil {
+			item.InstalledVersion = c.Import.Version
+			item.Pinned = c.Import.Pinned
+			item.LocalPath = lib.LocalPath(c.Item.Kind, c.Item.File)
+		}
+		if c.Status == templaterepo.StatusUpdate {
+			dto.Updates++
+		}
+		dto.Items = append(dto.Items, item)
+	}
+	return dto, nil
+}
+
+// ImportRepoTemplate imports a template or recipe from the team template
+// repository by its file path. A local file that was not imported, or was
+// edited since, is only replaced when force is set.
+func (a *App) ImportRepoTemplate(file string, pin, force bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.GUIOperationTimeout)
+	defer cancel()
+
+	repo, items, err := a.openTemplateRepo(ctx)
+	if err != nil {
+		return err
+	}
+	item, err := templaterepo.Find(items, file)
+	if err != nil {
+		return err
+	}
+	lib := templaterepo.DefaultLibrary()
+	imp, err := lib.Import(repo, item, pin, force)
+	if errors.Is(err, templaterepo.ErrLocalFile) {
+		// The panel asks before retrying with force
+		return fmt.Errorf("%w: %s", templaterepo.ErrLocalFile, lib.LocalPath(item.Kind, item.File))
+	}
+	if err != nil {
+		return err
+	}
+	a.logInfo("Templates", fmt.Sprintf("Imported %s %s version %s from the template repository", item.Kind, item.Name, imp.Version))
+	return nil
+}
+
+// UpdateRepoTemplates updates the imports that changed upstream, skipping
+// pinned and locally edited ones, and returns how many were updated.
+func (a *App) UpdateRepoTemplates() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.GUIOperationTimeout)
+	defer cancel()
+
+	repo, items, err := a.openTemplateRepo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	lib := templaterepo.DefaultLibrary()
+	checks, err := lib.Check(items)
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for _, c := range checks {
+		if c.Status != templaterepo.StatusUpdate {
+			continue
+		}
+		if _, err := lib.Import(repo, c.Item, false, false); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	if updated > 0 {
+		a.logInfo("Templates", fmt.Sprintf("Updated %d template(s) from the template repository", updated))
+	}
+	return updated, nil
+}
+
+// SetRepoTemplatePinned pins or unpins an imported template or recipe.
+func (a *App) SetRepoTemplatePinned(file string, pinned bool) error {
+	return templaterepo.DefaultLibrary().SetPinned(file, pinned)
+}
@@ -0,0 +1,68 @@
+package wailsapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rescale/rescale-int/internal/config"
+	"github.com/rescale/rescale-int/internal/templaterepo"
+)
+
+// TestGetTemplateRepoNotConfigured verifies the Team Repository panel gets an
+// empty listing, not an error, when template_repo is unset.
+func TestGetTemplateRepoNotConfigured(t *testing.T) {
+	app := &App{config: &config.Config{}}
+
+	dto, err := app.GetTemplateRepo()
+	if err != nil {
+		t.Fatalf("GetTemplateRepo() error = %v", err)
+	}
+	if dto.Configured || dto.Items == nil {
+		t.Errorf("GetTemplateRepo() = %+v, want unconfigured with empty items", dto)
+	}
+	if err := app.ImportRepoTemplate("templates/wing.json", false, false); err == nil {
+		t.Error("ImportRepoTemplate() without a repository succeeded")
+	}
+}
+
+// TestImportAndUpdateRepoTemplates verifies a template imported from a folder
+// repository is reported current, then updated after it changes upstream.
+func TestImportAndUpdateRepoTemplates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	repoDir := t.TempDir()
+	template := filepath.Join(repoDir, "templates", "wing.json")
+	if err := os.MkdirAll(filepath.Dir(template), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(template, []byte(`{"description":"Wing CFD"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := &App{config: &config.Config{TemplateRepo: repoDir}}
+
+	if err := app.ImportRepoTemplate("templates/wing.json", false, false); err != nil {
+		t.Fatalf("ImportRepoTemplate() error = %v", err)
+	}
+	dto, err := app.GetTemplateRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dto.Items) != 1 || dto.Items[0].Status != templaterepo.StatusCurrent || dto.Items[0].Description != "Wing CFD" {
+		t.Fatalf("GetTemplateRepo() items = %+v", dto.Items)
+	}
+
+	if err := os.WriteFile(template, []byte(`{"description":"Wing CFD v2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if dto, _ = app.GetTemplateRepo(); dto.Updates != 1 {
+		t.Errorf("Updates = %d after upstream change, want 1", dto.Updates)
+	}
+	if n, err := app.UpdateRepoTemplates(); err != nil || n != 1 {
+		t.Errorf("UpdateRepoTemplates() = %d, %v, want 1", n, err)
+	}
+	data, err := os.ReadFile(filepath.Join(config.TemplateDirectory(), "wing.json"))
+	if err != nil || string(data) != `{"description":"Wing CFD v2"}` {
+		t.Errorf("template after update = %q, %v", data, err)
+	}
+}
@@ -20,26 +20,53 @@ type TransferRequestDTO struct {
 	BatchID     string   `json:"batchID,omitempty"`
 	BatchLabel  string   `json:"batchLabel,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	// Set from PlanUploadNames when a duplicate-name policy applies
+	RemoteName     string   `json:"remoteName,omitempty"`     // Upload under this name
+	ReplaceFileIDs []string `json:"replaceFileIDs,omitempty"` // Delete these files after upload
+
+	// Post-download chain for a download ("" = configured default, "none" = off)
+	PostProcess string `json:"postProcess,omitempty"`
+
+	// Queue even if the same transfer is already queued or running
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
+}
+
+// DuplicateTransferDTO is a request that repeats a queued or running transfer.
+type DuplicateTransferDTO struct {
+	Index  int    `json:"index"`  // Position in the checked requests
+	TaskID string `json:"taskId"` // The pending transfer; empty when repeating an earlier request
+	State  string `json:"state"`  // State of the pending transfer
 }
 
 // TransferTaskDTO is the JSON-safe version of services.TransferTask.
 type TransferTaskDTO struct {
-	ID          string  `json:"id"`
-	Type        string  `json:"type"`                  // "upload" or "download"
-	State       string  `json:"state"`                 // queued, initializing, active, completed, failed, cancelled
-	Name        string  `json:"name"`                  // Display name
-	Source      string  `json:"source"`                // Source path or ID
-	Dest        string  `json:"dest"`                  // Destination path or ID
-	Size        int64   `json:"size"`                  // Total size in bytes
-	SourceLabel string  `json:"sourceLabel,omitempty"` // "PUR", "SingleJob", "FileBrowser"
-	BatchID     string  `json:"batchID,omitempty"`
-	BatchLabel  string  `json:"batchLabel,omitempty"`
-	Progress    float64 `json:"progress"`              // 0.0 to 1.0
-	Speed       float64 `json:"speed"`                 // bytes/sec
-	Error       string  `json:"error,omitempty"`
-	CreatedAt   string  `json:"createdAt"`
-	StartedAt   string  `json:"startedAt,omitempty"`
-	CompletedAt string  `json:"completedAt,omitempty"`
+	ID            string            `json:"id"`
+	Type          string            `json:"type"`                  // "upload" or "download"
+	State         string            `json:"state"`                 // queued, initializing, active, completed, failed, cancelled
+	Name          string            `json:"name"`                  // Display name
+	Source        string            `json:"source"`                // Source path or ID
+	Dest          string            `json:"dest"`                  // Destination path or ID
+	Size          int64             `json:"size"`                  // Total size in bytes
+	SourceLabel   string            `json:"sourceLabel,omitempty"` // "PUR", "SingleJob", "FileBrowser"
+	BatchID       string            `json:"batchID,omitempty"`
+	BatchLabel    string            `json:"batchLabel,omitempty"`
+	Progress      float64           `json:"progress"` // 0.0 to 1.0
+	Speed         float64           `json:"speed"`    // bytes/sec
+	Error         string            `json:"error,omitempty"`
+	Steps         []TransferStepDTO `json:"steps,omitempty"`         // Post-download processing, once started
+	RetryAttempts int               `json:"retryAttempts,omitempty"` // Automatic retries scheduled so far
+	NextRetryAt   string            `json:"nextRetryAt,omitempty"`   // RFC3339; set while state is retry_waiting
+	CreatedAt     string            `json:"createdAt"`
+	StartedAt     string            `json:"startedAt,omitempty"`
+	CompletedAt   string            `json:"completedAt,omitempty"`
+}
+
+// TransferStepDTO is the status of one post-download processing step.
+type TransferStepDTO struct {
+	Step   string `json:"step"`  // The step as configured, e.g. "untar"
+	State  string `json:"state"` // pending, running, done, skipped, failed
+	Detail string `json:"detail,omitempty"`
 }
 
 // TransferStatsDTO is the JSON-safe version of services.TransferStats.
@@ -66,7 +93,34 @@ func (a *App) StartTransfers(requests []TransferRequestDTO) error {
 		return ErrNoTransferService
 	}
 
-	// Convert DTOs to service requests
+	reqs := toTransferRequests(requests)
+
+	ctx := context.Background()
+	return ts.StartTransfers(ctx, reqs)
+}
+
+// FindDuplicateTransfers returns the requests that repeat a transfer already
+// queued or running (or an earlier request), so the File Browser can ask
+// whether to skip them or transfer them again with allowDuplicate.
+// Returns empty slice instead of nil to prevent frontend null errors.
+func (a *App) FindDuplicateTransfers(requests []TransferRequestDTO) []DuplicateTransferDTO {
+	result := []DuplicateTransferDTO{}
+	if a.engine == nil {
+		return result
+	}
+	ts := a.engine.TransferService()
+	if ts == nil {
+		return result
+	}
+
+	for _, d := range ts.FindDuplicateTransfers(toTransferRequests(requests)) {
+		result = append(result, DuplicateTransferDTO{Index: d.Index, TaskID: d.TaskID, State: string(d.State)})
+	}
+	return result
+}
+
+// toTransferRequests converts DTOs to service requests.
+func toTransferRequests(requests []TransferRequestDTO) []services.TransferRequest {
 	reqs := make([]services.TransferRequest, len(requests))
 	for i, r := range requests {
 		reqs[i] = services.TransferRequest{
@@ -79,11 +133,16 @@ func (a *App) StartTransfers(requests []TransferRequestDTO) error {
 			BatchID:     r.BatchID,
 			BatchLabel:  r.BatchLabel,
 			Tags:        r.Tags,
+
+			RemoteName:     r.RemoteName,
+			ReplaceFileIDs: r.ReplaceFileIDs,
+
+			PostProcess: r.PostProcess,
+
+			AllowDuplicate: r.AllowDuplicate,
 		}
 	}
-
-	ctx := context.Background()
-	return ts.StartTransfers(ctx, reqs)
+	return reqs
 }
 
 func (a *App) CancelTransfer(taskID string) error {
@@ -127,6 +186,20 @@ func (a *App) RetryTransfer(taskID string) (string, error) {
 	return ts.RetryTransfer(taskID)
 }
 
+// GiveUpTransfer abandons a pending automatic retry, leaving the task failed.
+func (a *App) GiveUpTransfer(taskID string) error {
+	if a.engine == nil {
+		return ErrNoEngine
+	}
+
+	ts := a.engine.TransferService()
+	if ts == nil {
+		return ErrNoTransferService
+	}
+
+	return ts.GiveUpTransfer(taskID)
+}
+
 func (a *App) GetTransferStats() TransferStatsDTO {
 	if a.engine == nil {
 		return TransferStatsDTO{}
@@ -317,50 +390,107 @@ func (a *App) RetryFailedInBatch(batchID string) error {
 	return ts.GetQueue().RetryFailedInBatch(batchID)
 }
 
+// failedSummaryLimit caps the failed tasks sent for the Transfers tab
+// failure summary; the total is always reported.
+const failedSummaryLimit = 50
+
+// FailedTransfersDTO lists failed transfers across all batches for the
+// failure summary.
+type FailedTransfersDTO struct {
+	Total int               `json:"total"`
+	Tasks []TransferTaskDTO `json:"tasks"` // Oldest first, at most failedSummaryLimit
+}
+
+// GetFailedTransfers returns the failed transfers for the Transfers tab
+// failure summary.
+func (a *App) GetFailedTransfers() FailedTransfersDTO {
+	result := FailedTransfersDTO{Tasks: []TransferTaskDTO{}}
+	if a.engine == nil {
+		return result
+	}
+
+	ts := a.engine.TransferService()
+	if ts == nil {
+		return result
+	}
+
+	tasks, total := ts.GetQueue().GetFailedTasks(failedSummaryLimit)
+	result.Total = total
+	for i := range tasks {
+		result.Tasks = append(result.Tasks, transferTaskToDTO(serviceTaskFromQueueTask(&tasks[i])))
+	}
+	return result
+}
+
+// RetryFailedTransfers retries every failed transfer, leaving completed and
+// cancelled ones alone. Returns the number re-queued.
+func (a *App) RetryFailedTransfers() (int, error) {
+	if a.engine == nil {
+		return 0, ErrNoEngine
+	}
+
+	ts := a.engine.TransferService()
+	if ts == nil {
+		return 0, ErrNoTransferService
+	}
+
+	return ts.GetQueue().RetryAllFailed(), nil
+}
+
 // serviceTaskFromQueueTask converts a transfer.TransferTask to services.TransferTask.
 // Takes pointer to avoid copying sync.RWMutex embedded in TransferTask.
 func serviceTaskFromQueueTask(qt *transfer.TransferTask) services.TransferTask {
 	return services.TransferTask{
-		ID:          qt.ID,
-		Type:        services.TransferType(qt.Type),
-		State:       services.TransferState(qt.State),
-		Name:        qt.Name,
-		Source:      qt.Source,
-		Dest:        qt.Dest,
-		Size:        qt.Size,
-		SourceLabel: qt.SourceLabel,
-		BatchID:     qt.BatchID,
-		BatchLabel:  qt.BatchLabel,
-		Progress:    qt.Progress,
-		Speed:       qt.Speed,
-		Error:       qt.Error,
-		CreatedAt:   qt.CreatedAt,
-		StartedAt:   qt.StartedAt,
-		CompletedAt: qt.CompletedAt,
+		ID:            qt.ID,
+		Type:          services.TransferType(qt.Type),
+		State:         services.TransferState(qt.State),
+		Name:          qt.Name,
+		Source:        qt.Source,
+		Dest:          qt.Dest,
+		Size:          qt.Size,
+		SourceLabel:   qt.SourceLabel,
+		BatchID:       qt.BatchID,
+		BatchLabel:    qt.BatchLabel,
+		Progress:      qt.Progress,
+		Speed:         qt.Speed,
+		Error:         qt.Error,
+		Steps:         qt.Steps,
+		RetryAttempts: qt.RetryAttempts,
+		NextRetryAt:   qt.NextRetryAt,
+		CreatedAt:     qt.CreatedAt,
+		StartedAt:     qt.StartedAt,
+		CompletedAt:   qt.CompletedAt,
 	}
 }
 
 // transferTaskToDTO converts a services.TransferTask to a DTO.
 func transferTaskToDTO(t services.TransferTask) TransferTaskDTO {
 	dto := TransferTaskDTO{
-		ID:          t.ID,
-		Type:        string(t.Type),
-		State:       string(t.State),
-		Name:        t.Name,
-		Source:      t.Source,
-		Dest:        t.Dest,
-		Size:        t.Size,
-		SourceLabel: t.SourceLabel,
-		BatchID:     t.BatchID,
-		BatchLabel:  t.BatchLabel,
-		Progress:    t.Progress,
-		Speed:       t.Speed,
-		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		ID:            t.ID,
+		Type:          string(t.Type),
+		State:         string(t.State),
+		Name:          t.Name,
+		Source:        t.Source,
+		Dest:          t.Dest,
+		Size:          t.Size,
+		SourceLabel:   t.SourceLabel,
+		BatchID:       t.BatchID,
+		BatchLabel:    t.BatchLabel,
+		Progress:      t.Progress,
+		Speed:         t.Speed,
+		RetryAttempts: t.RetryAttempts,
+		CreatedAt:     t.CreatedAt.Format(time.RFC3339),
 	}
 
 	if t.Error != nil {
 		dto.Error = t.Error.Error()
 	}
+	for _, s := range t.Steps {
+		dto.Steps = append(dto.Steps, TransferStepDTO{Step: s.Step, State: s.State, Detail: s.Detail})
+	}
+	if !t.NextRetryAt.IsZero() {
+		dto.NextRetryAt = t.NextRetryAt.Format(time.RFC3339)
+	}
 	if !t.StartedAt.IsZero() {
 		dto.StartedAt = t.StartedAt.Format(time.RFC3339)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -245,47 +244,7 @@ func envDisabled() bool {
 	return v == "1" || strings.EqualFold(v, "true")
 }
 
-// compareVersions compares two semver-like version strings.
-// Returns >0 if a > b, <0 if a < b, 0 if equal.
-// Strips leading "v" prefix and "-dev"/"-beta"/etc. suffixes before comparison.
+// compareVersions compares two semver-like version strings (see version.Compare).
 func compareVersions(a, b string) int {
-	parseVersion := func(v string) []int {
-		v = strings.TrimPrefix(v, "v")
-		// Strip pre-release suffix (e.g., "-dev", "-beta.1")
-		if idx := strings.IndexByte(v, '-'); idx != -1 {
-			v = v[:idx]
-		}
-		parts := strings.Split(v, ".")
-		nums := make([]int, len(parts))
-		for i, p := range parts {
-			n, _ := strconv.Atoi(p)
-			nums[i] = n
-		}
-		return nums
-	}
-
-	aParts := parseVersion(a)
-	bParts := parseVersion(b)
-
-	// Pad shorter slice with zeros
-	maxLen := len(aParts)
-	if len(bParts) > maxLen {
-		maxLen = len(bParts)
-	}
-	for len(aParts) < maxLen {
-		aParts = append(aParts, 0)
-	}
-	for len(bParts) < maxLen {
-		bParts = append(bParts, 0)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		if aParts[i] > bParts[i] {
-			return 1
-		}
-		if aParts[i] < bParts[i] {
-			return -1
-		}
-	}
-	return 0
+	return version.Compare(a, b)
 }
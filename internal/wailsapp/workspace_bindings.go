@@ -0,0 +1,79 @@
+package wailsapp
+
+import (
+	"fmt"
+
+	"github.com/rescale/rescale-int/internal/config"
+)
+
+// WorkspaceDTO is one workspace the user belongs to.
+type WorkspaceDTO struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"` // File browsing, uploads and jobs currently use it
+}
+
+// ListWorkspaces lists the user's workspaces for the header selector.
+func (a *App) ListWorkspaces() ([]WorkspaceDTO, error) {
+	if a.engine == nil || a.engine.API() == nil {
+		return nil, ErrNoAPIClient
+	}
+	apiClient := a.engine.API()
+
+	ctx, cancel := a.apiContext(config.APIListing)
+	defer cancel()
+
+	workspaces, err := apiClient.ListWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := apiClient.Workspace()
+	if active == "" {
+		// The API key's default workspace
+		if profile, err := apiClient.GetUserProfile(ctx); err == nil {
+			active = profile.Workspace.ID
+		}
+	}
+
+	result := make([]WorkspaceDTO, len(workspaces))
+	for i, w := range workspaces {
+		result[i] = WorkspaceDTO{ID: w.ID, Name: w.Name, Active: w.ID == active}
+	}
+	return result, nil
+}
+
+// SetWorkspace scopes file browsing, uploads and job submission to the
+// workspace with the given ID ("" for the API key's default) and saves the
+// choice to config.csv. Only the workspace setting is written, so unsaved
+// Setup changes stay unsaved.
+func (a *App) SetWorkspace(id string) error {
+	if a.config == nil {
+		return ErrNoConfig
+	}
+	if a.engine == nil {
+		return ErrNoAPIClient
+	}
+
+	a.config.Workspace = id
+	// Synchronous, unlike UpdateConfig: the caller reloads the file browser
+	// right after and must not list the previous workspace's folders
+	if err := a.engine.UpdateConfig(a.config); err != nil {
+		return fmt.Errorf("failed to switch workspace: %w", err)
+	}
+	a.ClearCatalogCache()
+	a.logInfo("config", fmt.Sprintf("Switched to workspace %q", id))
+
+	configPath := config.GetDefaultConfigPath()
+	saved, err := config.LoadConfigCSV(configPath)
+	if err != nil {
+		// Nothing saved yet: the workspace is saved with the rest of the config
+		return nil
+	}
+	saved.Workspace = id
+	if err := config.SaveConfigCSV(saved, configPath); err != nil {
+		a.logError("config", fmt.Sprintf("Failed to save workspace: %v", err))
+		return fmt.Errorf("switched workspace, but failed to save it: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/constants"
+)
+
+// ErrStreamUnsupported is returned by an EventStream when the platform has no
+// job event channel. The monitor then falls back to polling.
+var ErrStreamUnsupported = errors.New("job event stream not supported")
+
+// minStreamGap spaces out long-polls that return empty immediately, so a
+// server that ignores the wait parameter cannot turn the stream into a busy loop.
+const minStreamGap = time.Second
+
+// StatusEvent is one job status transition delivered by an EventStream.
+type StatusEvent struct {
+	JobID  string
+	Status string
+}
+
+// EventStream blocks until the platform reports status transitions after
+// cursor (or its wait window elapses) and returns them with the next cursor.
+type EventStream func(ctx context.Context, cursor string) ([]StatusEvent, string, error)
+
+// JobSource returns the IDs of the jobs to follow. It is re-read on every
+// sweep and whenever events arrive, so newly-submitted jobs are picked up.
+type JobSource func() []string
+
+// MonitorConfig controls the monitor backends.
+type MonitorConfig struct {
+	PollInterval    time.Duration // Polling fallback interval
+	ResyncInterval  time.Duration // Full sweep interval while streaming
+	MaxStreamErrors int           // Consecutive stream failures before falling back
+}
+
+func (c *MonitorConfig) applyDefaults() {
+	if c.PollInterval <= 0 {
+		c.PollInterval = constants.JobPollInterval
+	}
+	if c.ResyncInterval <= 0 {
+		c.ResyncInterval = constants.JobEventResyncInterval
+	}
+	if c.MaxStreamErrors <= 0 {
+		c.MaxStreamErrors = constants.MaxConsecutiveJobEventErrors
+	}
+}
+
+// Monitor follows the status of many jobs. It prefers the platform's job event
+// stream, which reports transitions within seconds and costs one request per
+// wait window regardless of job count, and falls back to polling each
+// non-terminal job when the stream is unavailable or keeps failing.
+type Monitor struct {
+	cfg      MonitorConfig
+	jobs     JobSource
+	statusFn StatusFunc
+	stream   EventStream
+	cb       *Callbacks
+
+	mu        sync.Mutex
+	statuses  map[string]string // last known status per job ("" = not yet seen)
+	streaming bool
+}
+
+// NewMonitor creates a monitor. stream may be nil to poll only.
+func NewMonitor(cfg MonitorConfig, jobs JobSource, statusFn StatusFunc, stream EventStream, cb *Callbacks) *Monitor {
+	cfg.applyDefaults()
+	return &Monitor{
+		cfg:      cfg,
+		jobs:     jobs,
+		statusFn: statusFn,
+		stream:   stream,
+		cb:       cb,
+		statuses: make(map[string]string),
+	}
+}
+
+// Streaming reports whether the monitor is currently on the event stream.
+func (m *Monitor) Streaming() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.streaming
+}
+
+// Run monitors until ctx is cancelled. Status changes are reported through
+// Callbacks.OnStatusChange and OnTerminal; a switch to polling is reported
+// through Callbacks.OnFallback.
+func (m *Monitor) Run(ctx context.Context) error {
+	// Baseline so later transitions have an "old" status to compare against
+	m.sweep(ctx, false)
+
+	if m.stream != nil {
+		err := m.runStream(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.cb.fallback(err)
+	}
+	return m.runPoll(ctx)
+}
+
+// runStream consumes the event stream. It returns when the stream is
+// unsupported, fails MaxStreamErrors times in a row, or ctx is cancelled.
+func (m *Monitor) runStream(ctx context.Context) error {
+	m.setStreaming(true)
+	defer m.setStreaming(false)
+
+	cursor := ""
+	lastSweep := time.Now()
+	consecutiveErrors := 0
+
+	for {
+		started := time.Now()
+		events, next, err := m.stream(ctx, cursor)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if errors.Is(err, ErrStreamUnsupported) {
+				return err
+			}
+			consecutiveErrors++
+			m.cb.streamError(err)
+			if consecutiveErrors >= m.cfg.MaxStreamErrors {
+				return fmt.Errorf("job event stream failed %d times in a row: %w", consecutiveErrors, err)
+			}
+			if !sleepCtx(ctx, time.Duration(consecutiveErrors)*time.Second) {
+				return ctx.Err()
+			}
+			continue
+		}
+		consecutiveErrors = 0
+		cursor = next
+
+		tracked := m.refreshJobs()
+		for _, ev := range events {
+			if tracked[ev.JobID] {
+				m.apply(ev.JobID, ev.Status)
+			}
+		}
+
+		if time.Since(lastSweep) >= m.cfg.ResyncInterval {
+			m.sweep(ctx, false)
+			lastSweep = time.Now()
+		} else {
+			// Jobs submitted since the last sweep have no baseline yet
+			m.sweep(ctx, true)
+		}
+
+		if len(events) == 0 && time.Since(started) < minStreamGap {
+			if !sleepCtx(ctx, minStreamGap) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// runPoll checks every non-terminal job each PollInterval.
+func (m *Monitor) runPoll(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.sweep(ctx, false)
+		}
+	}
+}
+
+// sweep fetches the status of every non-terminal job (or, with onlyNew, only
+// jobs never seen before). Terminal jobs are never polled again.
+func (m *Monitor) sweep(ctx context.Context, onlyNew bool) {
+	m.refreshJobs()
+
+	m.mu.Lock()
+	var pending []string
+	for jobID, status := range m.statuses {
+		if TerminalStatuses[status] || (onlyNew && status != "") {
+			continue
+		}
+		pending = append(pending, jobID)
+	}
+	m.mu.Unlock()
+
+	for _, jobID := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		status, err := checkStatus(ctx, jobID, m.statusFn)
+		if err != nil {
+			m.cb.onError(jobID, err)
+			continue
+		}
+		m.apply(jobID, status)
+	}
+}
+
+// refreshJobs syncs the tracked set with the job source and returns it.
+func (m *Monitor) refreshJobs() map[string]bool {
+	ids := m.jobs()
+	tracked := make(map[string]bool, len(ids))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		tracked[id] = true
+		if _, ok := m.statuses[id]; !ok {
+			m.statuses[id] = ""
+		}
+	}
+	for id := range m.statuses {
+		if !tracked[id] {
+			delete(m.statuses, id)
+		}
+	}
+	return tracked
+}
+
+// apply records a job's status and fires callbacks if it changed.
+func (m *Monitor) apply(jobID, status string) {
+	if status == "" {
+		return
+	}
+	m.mu.Lock()
+	old, ok := m.statuses[jobID]
+	changed := ok && old != status
+	if changed {
+		m.statuses[jobID] = status
+	}
+	m.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	m.cb.statusChange(jobID, old, status)
+	if TerminalStatuses[status] {
+		m.cb.terminal(jobID, status)
+	}
+}
+
+func (m *Monitor) setStreaming(v bool) {
+	m.mu.Lock()
+	m.streaming = v
+	m.mu.Unlock()
+}
+
+// sleepCtx waits for d, returning false if ctx is cancelled first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,187 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// changeRecorder collects status changes reported through Callbacks.
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes []string
+	ch      chan struct{}
+}
+
+func newChangeRecorder() *changeRecorder {
+	return &changeRecorder{ch: make(chan struct{}, 100)}
+}
+
+func (r *changeRecorder) callbacks() *Callbacks {
+	return &Callbacks{
+		OnStatusChange: func(jobID, _, newStatus string) {
+			r.mu.Lock()
+			r.changes = append(r.changes, jobID+"="+newStatus)
+			r.mu.Unlock()
+			r.ch <- struct{}{}
+		},
+	}
+}
+
+func (r *changeRecorder) waitFor(t *testing.T, n int) []string {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for status change %d of %d", i+1, n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.changes...)
+}
+
+func staticJobs(ids ...string) JobSource {
+	return func() []string { return ids }
+}
+
+func TestMonitor_StreamDeliversEventsWithoutPolling(t *testing.T) {
+	var statusCalls atomic.Int32
+	statusFn := func(_ context.Context, _ string) (string, error) {
+		statusCalls.Add(1)
+		return "Queued", nil
+	}
+
+	var streamCalls atomic.Int32
+	stream := func(ctx context.Context, cursor string) ([]StatusEvent, string, error) {
+		if streamCalls.Add(1) == 1 {
+			return []StatusEvent{{JobID: "j1", Status: "Executing"}, {JobID: "other", Status: "Completed"}}, "c1", nil
+		}
+		<-ctx.Done()
+		return nil, cursor, ctx.Err()
+	}
+
+	rec := newChangeRecorder()
+	m := NewMonitor(MonitorConfig{PollInterval: time.Hour}, staticJobs("j1", "j2"), statusFn, stream, rec.callbacks())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	// Baseline: j1 and j2 Queued, then the streamed transition for j1
+	changes := rec.waitFor(t, 3)
+	if changes[2] != "j1=Executing" {
+		t.Errorf("changes = %v, want j1=Executing last", changes)
+	}
+	if !m.Streaming() {
+		t.Error("Streaming() = false while on the event stream")
+	}
+	if got := statusCalls.Load(); got != 2 {
+		t.Errorf("status calls = %d, want 2 (baseline only)", got)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestMonitor_FallsBackToPollingWhenUnsupported(t *testing.T) {
+	stream := func(_ context.Context, _ string) ([]StatusEvent, string, error) {
+		return nil, "", ErrStreamUnsupported
+	}
+
+	var fellBack atomic.Bool
+	rec := newChangeRecorder()
+	cb := rec.callbacks()
+	cb.OnFallback = func(reason error) {
+		if errors.Is(reason, ErrStreamUnsupported) {
+			fellBack.Store(true)
+		}
+	}
+
+	m := NewMonitor(MonitorConfig{PollInterval: 10 * time.Millisecond}, staticJobs("j1"),
+		mockStatusSequence("Queued", "Executing", "Completed"), stream, cb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+
+	changes := rec.waitFor(t, 3)
+	want := []string{"j1=Queued", "j1=Executing", "j1=Completed"}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v, want %v", changes, want)
+		}
+	}
+	if !fellBack.Load() {
+		t.Error("OnFallback not called with ErrStreamUnsupported")
+	}
+}
+
+func TestMonitor_FallsBackAfterRepeatedStreamErrors(t *testing.T) {
+	var streamCalls atomic.Int32
+	stream := func(_ context.Context, _ string) ([]StatusEvent, string, error) {
+		streamCalls.Add(1)
+		return nil, "", errors.New("gateway timeout")
+	}
+
+	fallback := make(chan error, 1)
+	var streamErrors, jobErrors atomic.Int32
+	cb := &Callbacks{
+		OnFallback:    func(reason error) { fallback <- reason },
+		OnStreamError: func(error) { streamErrors.Add(1) },
+		OnError:       func(string, error) { jobErrors.Add(1) },
+	}
+	m := NewMonitor(MonitorConfig{PollInterval: time.Hour, MaxStreamErrors: 2}, staticJobs("j1"),
+		mockStatusSequence("Queued"), stream, cb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+
+	select {
+	case <-fallback:
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor did not fall back to polling")
+	}
+	if got := streamCalls.Load(); got != 2 {
+		t.Errorf("stream calls = %d, want 2", got)
+	}
+	if streamErrors.Load() != 2 || jobErrors.Load() != 0 {
+		t.Errorf("stream errors = %d, job errors = %d; want 2 stream errors only", streamErrors.Load(), jobErrors.Load())
+	}
+}
+
+func TestMonitor_SweepSkipsTerminalJobs(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	statusFn := func(_ context.Context, jobID string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[jobID]++
+		if jobID == "done" {
+			return "Completed", nil
+		}
+		return "Executing", nil
+	}
+
+	m := NewMonitor(MonitorConfig{}, staticJobs("done", "running"), statusFn, nil, nil)
+	ctx := context.Background()
+	m.sweep(ctx, false)
+	m.sweep(ctx, false)
+	m.sweep(ctx, false)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["done"] != 1 {
+		t.Errorf("terminal job polled %d times, want 1", calls["done"])
+	}
+	if calls["running"] != 3 {
+		t.Errorf("running job polled %d times, want 3", calls["running"])
+	}
+}
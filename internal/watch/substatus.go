@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+// Sub-statuses shown while a submitted job is waiting to execute.
+const (
+	SubStatusQueued       = "Queued"
+	SubStatusValidating   = "Validating"
+	SubStatusProvisioning = "Provisioning cluster"
+	SubStatusStarting     = "Starting"
+)
+
+// preExecutionSubStatus maps the platform statuses a job passes through
+// between submission and Executing to what the user is waiting on.
+var preExecutionSubStatus = map[string]string{
+	"Pending":           SubStatusQueued,
+	"Waiting for Queue": SubStatusQueued,
+	"Queued":            SubStatusValidating,
+	"Validated":         SubStatusProvisioning,
+	"Started":           SubStatusStarting,
+}
+
+// SubStatus is where a submitted job is in the queue/provisioning sequence
+// and since when.
+type SubStatus struct {
+	Label  string    // One of the SubStatus* constants
+	Reason string    // Platform status reason, if any
+	Since  time.Time // When the job entered this sub-state (zero if unknown)
+}
+
+// SubStatusLabel returns the sub-status for a platform status, or "" if the
+// status is not one a job passes through between submission and Executing.
+func SubStatusLabel(status string) string {
+	return preExecutionSubStatus[status]
+}
+
+// PreExecutionSubStatus derives the sub-status from a job's status history
+// (GET /jobs/{id}/statuses/). ok is false once the latest status is no longer
+// a pre-execution one.
+func PreExecutionSubStatus(history []models.JobStatusEntry) (sub SubStatus, ok bool) {
+	latest, latestAt, ok := LatestStatus(history)
+	if !ok {
+		return SubStatus{}, false
+	}
+	label, ok := preExecutionSubStatus[latest.Status]
+	if !ok {
+		return SubStatus{}, false
+	}
+	return SubStatus{Label: label, Reason: latest.StatusReason, Since: latestAt}, true
+}
+
+// LatestStatus returns the most recent entry of a job's status history and
+// its date. The platform does not guarantee the order of the history, so
+// entries are compared by date; entries with unparseable dates are ignored.
+func LatestStatus(history []models.JobStatusEntry) (latest models.JobStatusEntry, at time.Time, ok bool) {
+	for _, h := range history {
+		t, err := ParseStatusDate(h.StatusDate)
+		if err != nil {
+			continue
+		}
+		if !ok || t.After(at) {
+			latest, at, ok = h, t, true
+		}
+	}
+	return latest, at, ok
+}
+
+// ParseStatusDate parses a job status history date, which the platform
+// returns in RFC 3339 or with a fixed microsecond fraction.
+func ParseStatusDate(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse("2006-01-02T15:04:05.000000Z", s)
+	}
+	return t, err
+}
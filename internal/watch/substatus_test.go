@@ -0,0 +1,67 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rescale/rescale-int/internal/models"
+)
+
+func TestPreExecutionSubStatus_LatestEntryWins(t *testing.T) {
+	// History is not guaranteed to be ordered
+	history := []models.JobStatusEntry{
+		{Status: "Validated", StatusDate: "2026-03-01T10:02:00Z", StatusReason: "Waiting for hardware"},
+		{Status: "Pending", StatusDate: "2026-03-01T10:00:00Z"},
+		{Status: "Queued", StatusDate: "2026-03-01T10:01:00.500000Z"},
+	}
+
+	sub, ok := PreExecutionSubStatus(history)
+	if !ok {
+		t.Fatal("expected a pre-execution sub-status")
+	}
+	if sub.Label != SubStatusProvisioning {
+		t.Errorf("Label = %q, want %q", sub.Label, SubStatusProvisioning)
+	}
+	if sub.Reason != "Waiting for hardware" {
+		t.Errorf("Reason = %q, want %q", sub.Reason, "Waiting for hardware")
+	}
+	if want := time.Date(2026, 3, 1, 10, 2, 0, 0, time.UTC); !sub.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", sub.Since, want)
+	}
+}
+
+func TestPreExecutionSubStatus_Executing(t *testing.T) {
+	history := []models.JobStatusEntry{
+		{Status: "Started", StatusDate: "2026-03-01T10:03:00Z"},
+		{Status: "Executing", StatusDate: "2026-03-01T10:05:00Z"},
+	}
+	if sub, ok := PreExecutionSubStatus(history); ok {
+		t.Errorf("expected no sub-status once executing, got %+v", sub)
+	}
+}
+
+func TestPreExecutionSubStatus_NoParsableDates(t *testing.T) {
+	history := []models.JobStatusEntry{{Status: "Queued", StatusDate: "yesterday"}}
+	if _, ok := PreExecutionSubStatus(history); ok {
+		t.Error("expected no sub-status without a parsable date")
+	}
+	if _, ok := PreExecutionSubStatus(nil); ok {
+		t.Error("expected no sub-status for empty history")
+	}
+}
+
+func TestSubStatusLabel(t *testing.T) {
+	tests := map[string]string{
+		"Pending":   SubStatusQueued,
+		"Queued":    SubStatusValidating,
+		"Validated": SubStatusProvisioning,
+		"Started":   SubStatusStarting,
+		"Executing": "",
+		"Completed": "",
+	}
+	for status, want := range tests {
+		if got := SubStatusLabel(status); got != want {
+			t.Errorf("SubStatusLabel(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -46,6 +46,8 @@ type Callbacks struct {
 	OnDownloadPass func(jobID string, err error)
 	OnTerminal     func(jobID, finalStatus string)
 	OnError        func(jobID string, err error)
+	OnStreamError  func(err error)    // Monitor's event stream request failed; it retries
+	OnFallback     func(reason error) // Monitor switched from the event stream to polling
 }
 
 func (cb *Callbacks) statusChange(jobID, old, new string) {
@@ -72,6 +74,18 @@ func (cb *Callbacks) onError(jobID string, err error) {
 	}
 }
 
+func (cb *Callbacks) streamError(err error) {
+	if cb != nil && cb.OnStreamError != nil {
+		cb.OnStreamError(err)
+	}
+}
+
+func (cb *Callbacks) fallback(reason error) {
+	if cb != nil && cb.OnFallback != nil {
+		cb.OnFallback(reason)
+	}
+}
+
 // StatusFunc fetches the current status string for a job.
 type StatusFunc func(ctx context.Context, jobID string) (string, error)
 
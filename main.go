@@ -8,10 +8,12 @@
 // - CLI subcommands/flags → CLI mode
 //
 // Build with: wails build (for all platforms)
+//
+// Building with `-tags nogui` compiles the GUI out (see gui_nogui.go): no
+// Wails, WebKit or GTK dependencies, for hosts that can't install them.
 package main
 
 import (
-	"embed"
 	"fmt"
 	"log"
 	"os"
@@ -30,37 +32,14 @@ import (
 	"github.com/rescale/rescale-int/internal/config"
 	intfips "github.com/rescale/rescale-int/internal/fips"
 	"github.com/rescale/rescale-int/internal/mesa"
+	"github.com/rescale/rescale-int/internal/selfupdate"
 	"github.com/rescale/rescale-int/internal/version"
-	"github.com/rescale/rescale-int/internal/wailsapp"
 )
 
-//go:embed all:frontend/dist
-var assets embed.FS
-
 func init() {
-	// Linux-only environment mitigations. Must be set before GTK / WebKit
-	// initialization so the underlying libraries pick them up on first use.
-	if runtime.GOOS == "linux" {
-		if os.Getenv("GTK_IM_MODULE") == "" {
-			os.Setenv("GTK_IM_MODULE", "none")
-		}
-		// Disable GVFS remote-backend enumeration in the file chooser. Interlink
-		// does not use GVFS remote paths; on some VDIs (notably RHEL 9) a broken
-		// GVFS / D-Bus setup can crash the chooser during widget construction.
-		// Opt out with RESCALE_ENABLE_GVFS=1 if a user relies on GVFS.
-		if os.Getenv("RESCALE_ENABLE_GVFS") != "1" && os.Getenv("GIO_USE_VFS") == "" {
-			os.Setenv("GIO_USE_VFS", "local")
-		}
-		// Force WebKitGTK off the DMA-BUF rendering path, which is known-broken
-		// on RHEL 9 and contributes to GPU-related modal-dialog instability.
-		// Opt out with RESCALE_GPU_ACCEL=1 if a user wants accelerated rendering.
-		if os.Getenv("RESCALE_GPU_ACCEL") != "1" && os.Getenv("WEBKIT_DISABLE_DMABUF_RENDERER") == "" {
-			os.Setenv("WEBKIT_DISABLE_DMABUF_RENDERER", "1")
-		}
-	}
-
-	// Shared FIPS 140-3 compliance check (common to GUI and CLI binaries)
-	intfips.Init("wails")
+	// Shared FIPS 140-3 compliance check (common to GUI and CLI binaries).
+	// A GUI launch has no terminal to read stderr on, so it gets a dialog.
+	intfips.Init(fipsMode, !isCLIMode())
 
 	// Warn if NTLM proxy is configured in FIPS mode —
 	// NTLM uses non-FIPS algorithms (MD4/MD5) which may violate compliance for FRM platforms
@@ -81,6 +60,9 @@ func main() {
 	cli.Version = version.Version
 	cli.BuildTime = version.BuildTime
 
+	// Remove the binary a previous self-update moved aside (Windows)
+	selfupdate.CleanupPrevious()
+
 	// Check for diagnostic modes before GUI/CLI
 	if slices.Contains(os.Args, "--mesa-doctor") {
 		fmt.Printf("Rescale Interlink %s\n\n", version.Version)
@@ -112,8 +94,7 @@ func main() {
 	}
 
 	// GUI mode - launch Wails GUI
-	wailsapp.Assets = assets
-	if err := wailsapp.Run(os.Args); err != nil {
+	if err := runGUI(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -126,9 +107,10 @@ func main() {
 // - CLI subcommands are present (jobs, files, folders, etc.)
 // - CLI flags are present (--help, --version, -h, -v)
 // - No display available (DISPLAY/WAYLAND_DISPLAY not set on Linux)
+// - The GUI is compiled out (-tags nogui) and --gui is not given
 //
 // GUI mode when:
-// - --gui flag is present (force GUI mode)
+// - --gui flag is present (force GUI mode; fails in a nogui build)
 // - No arguments and display is available
 func isCLIMode() bool {
 	// Explicit flags
@@ -138,6 +120,9 @@ func isCLIMode() bool {
 	if slices.Contains(os.Args, "--gui") {
 		return false
 	}
+	if !guiAvailable {
+		return true
+	}
 
 	// CLI subcommands and flags that indicate CLI mode
 	cliPatterns := []string{